@@ -31,28 +31,71 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/docs"
+	"github.com/banua-coder/pico-api-go/internal/cli"
 	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/grpcserver"
 	"github.com/banua-coder/pico-api-go/internal/handler"
 	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/banua-coder/pico-api-go/internal/service"
 	"github.com/banua-coder/pico-api-go/pkg/cache"
-	"github.com/banua-coder/pico-api-go/pkg/database"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/epi"
+	"github.com/banua-coder/pico-api-go/pkg/mailer"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+	"github.com/banua-coder/pico-api-go/pkg/telegram"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/banua-coder/pico-api-go/pkg/webhook"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	cfg := config.Load()
 
-	db, err := database.NewMySQLConnection(&cfg.Database)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	// Maintenance subcommands (e.g. `pico-api backup --out file.sql.gz`) run and exit
+	if cli.IsCommand(os.Args[1:]) {
+		if err := cli.Run(cfg, os.Args[1:]); err != nil {
+			log.Fatalf("%s failed: %v", os.Args[1], err)
+		}
+		return
+	}
+
+	if cfg.Tracing.Endpoint != "" {
+		exporter := tracing.NewOTLPExporter(cfg.Tracing.Endpoint, cfg.Tracing.ServiceName, cfg.Tracing.Headers)
+		tracing.SetRecorder(tracing.NewRecorder(exporter, tracing.RecorderOptions{
+			BatchSize:     cfg.Tracing.BatchSize,
+			FlushInterval: cfg.Tracing.FlushInterval,
+		}))
+		log.Printf("OTLP tracing enabled: exporting to %s", cfg.Tracing.Endpoint)
+	}
+
+	clk := clock.System
+	if !cfg.Demo.ClockStart.IsZero() {
+		clk = clock.Offset(cfg.Demo.ClockStart)
+		log.Printf("Demo mode: clock starts at %s", cfg.Demo.ClockStart.Format(time.RFC3339))
+	}
+
+	// Fail fast on what would otherwise only break once requests arrive
+	db, diagnostics := cli.Diagnose(cfg)
+	log.Println(service.FormatDiagnostics(diagnostics))
+	if !diagnostics.OK {
+		if db != nil {
+			_ = db.Close()
+		}
+		log.Fatalf("Startup diagnostics found %d fatal problems", diagnostics.Fatal)
 	}
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -61,6 +104,9 @@ func main() {
 	}()
 
 	log.Println("Database connected successfully")
+	db.SetSlowQueryThreshold(cfg.Monitoring.SlowQueryThreshold)
+
+	service.NewIndexAdvisor(repository.NewIndexRepository(db)).WarnMissing(context.Background())
 
 	nationalCaseRepo := repository.NewNationalCaseRepository(db)
 	provinceRepo := repository.NewProvinceRepository(db)
@@ -69,33 +115,57 @@ func main() {
 	// Initialize cache — use Redis-backed dual-layer if REDIS_ADDR is set, otherwise in-memory only
 	var c *cache.Cache
 	var cacheInvalidator service.CacheInvalidator
+	statusChecks := []service.ComponentCheck{
+		{Name: "database", Check: func(context.Context) error { return db.HealthCheck() }},
+	}
 
 	redisAddr := os.Getenv("REDIS_ADDR")
 	if redisAddr != "" {
-		rac, err := cache.NewRedisAwareCache(time.Hour, cache.RedisOptions{
+		rac, err := cache.NewRedisAwareCache(cfg.Cache.DefaultTTL, cache.RedisOptions{
 			Addr:     redisAddr,
 			Password: os.Getenv("REDIS_PASSWORD"),
 			DB:       0,
 		})
 		if err != nil {
 			log.Printf("Redis unavailable (%v), falling back to in-memory cache only", err)
-			c = cache.New(time.Hour)
+			c = cache.New(cfg.Cache.DefaultTTL)
 			cacheInvalidator = c
 		} else {
 			log.Printf("Redis connected: %s (dual-layer cache active)", redisAddr)
 			c = rac.Unwrap()
 			cacheInvalidator = rac
+			statusChecks = append(statusChecks, service.ComponentCheck{Name: "cache", Check: rac.Ping})
 		}
 	} else {
-		c = cache.New(time.Hour)
+		c = cache.New(cfg.Cache.DefaultTTL)
 		cacheInvalidator = c
 	}
-	c.StartCleanup(5 * time.Minute)
+	c.StartCleanup(cfg.Cache.CleanupInterval)
+	// Data changes clear the responses served from memory along with the
+	// query cache, or they would outlive it until their max age
+	responseCache := middleware.NewCachedResponses(cfg.ResponseCache)
+	cacheInvalidator = service.CacheInvalidators{cacheInvalidator, responseCache}
+	cacheTTLs := service.CacheTTLs{
+		Latest:     cfg.Cache.LatestTTL,
+		Historical: cfg.Cache.HistoricalTTL,
+		Default:    cfg.Cache.DefaultTTL,
+	}
 
-	covidService := service.NewCachedCovidService(
-		service.NewCovidService(nationalCaseRepo, provinceRepo, provinceCaseRepo),
-		c,
-	)
+	testRepo := repository.NewTestRepository(db)
+	var covidService service.CovidService = service.NewCovidServiceWithTests(nationalCaseRepo, provinceRepo, provinceCaseRepo, testRepo)
+	covidService.(service.ClockSetter).SetClock(clk)
+	var cacheWarmer service.CacheWarmer
+	var warmCache service.WarmCache
+	if cfg.Cache.Enabled {
+		covidService = service.NewCachedCovidServiceWithTTLs(covidService, c, cacheTTLs)
+		cacheWarmer, _ = covidService.(service.CacheWarmer)
+		warmCache, _ = covidService.(service.WarmCache)
+	} else {
+		log.Println("Query cache disabled (CACHE_ENABLED=false)")
+	}
+	if cfg.Tracing.Endpoint != "" {
+		covidService = service.NewTracedCovidService(covidService)
+	}
 
 	// New repositories and services for migrated Lumen endpoints
 	regencyRepo := repository.NewRegencyRepository(db)
@@ -103,10 +173,10 @@ func main() {
 	hospitalRepo := repository.NewHospitalRepository(db)
 	taskForceRepo := repository.NewTaskForceRepository(db)
 
-	regencyService := service.NewCachedRegencyService(
-		service.NewRegencyService(regencyRepo, regencyCaseRepo),
-		c,
-	)
+	var regencyService service.RegencyServiceInterface = service.NewRegencyService(regencyRepo, regencyCaseRepo)
+	if cfg.Cache.Enabled {
+		regencyService = service.NewCachedRegencyServiceWithTTLs(regencyService, c, cacheTTLs)
+	}
 	hospitalService := service.NewHospitalService(hospitalRepo)
 	taskForceService := service.NewTaskForceService(taskForceRepo)
 
@@ -116,6 +186,18 @@ func main() {
 	provinceStatsRepo := repository.NewProvinceStatsRepository(db)
 	provinceStatsService := service.NewProvinceStatsService(provinceStatsRepo)
 
+	var tableStatsMonitor *service.TableStatsMonitor
+	if cfg.Monitoring.TableStatsEnabled {
+		tableStatsMonitor = service.NewTableStatsMonitor(
+			repository.NewTableStatsRepository(db),
+			cfg.Monitoring.TableGrowthWarnPercent,
+			cfg.Monitoring.DiskQuotaBytes,
+		)
+		tableStatsMonitor.SetClock(clk)
+		tableStatsMonitor.Start(cfg.Monitoring.TableStatsInterval)
+		defer tableStatsMonitor.Stop()
+	}
+
 	// Override Swagger host/basePath from environment variables if set
 	if host := os.Getenv("SWAGGER_HOST"); host != "" {
 		docs.SwaggerInfo.Host = host
@@ -129,25 +211,276 @@ func main() {
 
 	enableSwagger := true
 	svc := handler.Services{
-		CovidService:     covidService,
-		RegencyService:   regencyService,
-		CacheInvalidator: cacheInvalidator,
-		HospitalService:  hospitalService,
-		TaskForceService:    taskForceService,
+		CovidService:         covidService,
+		RegencyService:       regencyService,
+		CacheInvalidator:     cacheInvalidator,
+		HospitalService:      hospitalService,
+		TaskForceService:     taskForceService,
 		VaccinationService:   vaccinationService,
 		ProvinceStatsService: provinceStatsService,
+		Crawler:              cfg.Crawler,
+		Validation:           cfg.Validation,
+		RateLimit:            cfg.RateLimit,
+	}
+	if tableStatsMonitor != nil {
+		svc.TableStats = tableStatsMonitor
 	}
+	backupStore, err := storage.New(&cfg.Storage, cfg.Backup.Dir, "backups")
+	if err != nil {
+		log.Fatalf("Failed to set up backup storage: %v", err)
+	}
+	svc.BackupService = service.NewBackupService(db, backupStore)
+	exportStore, err := storage.New(&cfg.Storage, cfg.Export.Dir, "exports")
+	if err != nil {
+		log.Fatalf("Failed to set up export storage: %v", err)
+	}
+	exportService := service.NewExportService(covidService, handler.RenderExport, exportStore, cfg.Export.QueueSize, cfg.Export.Retention)
+	exportService.SetClock(clk)
+	exportService.Start()
+	defer exportService.Stop()
+	svc.ExportService = exportService
+	syncLogRepo := repository.NewSyncLogRepository(db)
+	svc.SyncService = service.NewSyncService(syncLogRepo, cacheInvalidator)
+	freshness := service.NewFreshnessService(syncLogRepo)
+	freshness.SetClock(clk)
+	if err := freshness.Load(context.Background()); err != nil {
+		log.Printf("Data source lookup failed: %v", err)
+	}
+	svc.Freshness = freshness
+	svc.CaseRevisionService = service.NewCaseRevisionService(nationalCaseRepo, repository.NewCaseRevisionRepository(db))
+	svc.IntegrityService = service.NewIntegrityService(repository.NewIntegrityRepository(db), cacheInvalidator)
+	svc.SoftDeleteService = service.NewSoftDeleteService(repository.NewSoftDeleteRepository(db), cacheInvalidator)
+	rtEstimation, err := service.NewRtEstimationService(repository.NewRtEstimateRepository(db), epi.Config{
+		SerialIntervalMean: cfg.Rt.SerialIntervalMean,
+		SerialIntervalSD:   cfg.Rt.SerialIntervalSD,
+		WindowDays:         cfg.Rt.WindowDays,
+	}, cacheInvalidator)
+	if err != nil {
+		log.Fatalf("Failed to set up Rt estimation: %v", err)
+	}
+	rtEstimation.SetClock(clk)
+	if cfg.Rt.EstimationInterval > 0 {
+		rtEstimation.Start(cfg.Rt.EstimationInterval)
+		defer rtEstimation.Stop()
+	}
+	svc.RtEstimationService = rtEstimation
+	dataQuality := service.NewDataQualityService(nationalCaseRepo, provinceCaseRepo, service.OutlierRule{
+		WindowDays:   cfg.DataQuality.OutlierWindowDays,
+		Threshold:    cfg.DataQuality.OutlierThreshold,
+		MinDeviation: cfg.DataQuality.OutlierMinDeviation,
+	})
+	dataQuality.SetClock(clk)
+	dataQuality.Start(cfg.DataQuality.ScanInterval)
+	defer dataQuality.Stop()
+	svc.DataQuality = dataQuality
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookService := service.NewWebhookService(webhookRepo, webhook.NewDefaultSender())
+	webhookService.SetClock(clk)
+	webhookService.Start(cfg.Webhooks.RetryInterval)
+	defer webhookService.Stop()
+	svc.WebhookService = webhookService
+
+	// New case data goes to webhook subscribers, the open /stream/cases
+	// connections and the /ws dashboard hub, and sync runs update the data
+	// source reported in list responses and are scanned for anomalies
+	caseStream := service.NewCaseStream()
+	caseStream.SetClock(clk)
+	svc.CaseStream = caseStream
+	dashboardHub := service.NewDashboardHub(covidService, service.DashboardHubLimits{
+		MaxConnections:          cfg.WebSocket.MaxConnections,
+		MaxConnectionsPerClient: cfg.WebSocket.MaxConnectionsPerClient,
+	})
+	svc.DashboardHub = dashboardHub
+	casePublisher := service.Publishers{caseStream, dashboardHub, freshness, dataQuality, webhookService}
+
+	casePoller := service.NewCaseWebhookPoller(nationalCaseRepo, provinceCaseRepo, webhookRepo, casePublisher)
+	casePoller.SetClock(clk)
+	casePoller.Start(cfg.Webhooks.PollInterval)
+	defer casePoller.Stop()
+	var alertMessenger service.AlertMessenger
+	if cfg.Alerts.TelegramBotToken != "" {
+		alertMessenger = telegram.NewClient(cfg.Alerts.TelegramBotToken)
+	}
+	alertService := service.NewAlertService(repository.NewAlertRepository(db), webhookService, alertMessenger)
+	alertService.SetClock(clk)
+	svc.AlertService = alertService
+	if cfg.Mail.SMTPHost != "" {
+		smtpMailer := mailer.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+		subscriptionService := service.NewSubscriptionService(repository.NewSubscriptionRepository(db),
+			provinceCaseRepo, smtpMailer, cfg.Subscriptions.PublicBaseURL)
+		subscriptionService.SetClock(clk)
+		subscriptionService.Start(cfg.Subscriptions.UpdateInterval)
+		defer subscriptionService.Stop()
+		svc.SubscriptionService = subscriptionService
+	}
+	shareService := service.NewShareService(repository.NewShareLinkRepository(db),
+		cfg.Subscriptions.PublicBaseURL, cfg.Share.DashboardURL)
+	shareService.SetClock(clk)
+	svc.ShareService = shareService
+	svc.SummaryService = service.NewSummaryService(covidService)
+	svc.RecapService = service.NewRecapService(covidService, vaccinationService)
+	svc.ReportService = service.NewReportService(covidService)
+	testingService := service.NewTestingService(testRepo, covidService)
+	testingService.SetClock(clk)
+	svc.TestingService = testingService
+	svc.PopulationService = service.NewPopulationService(repository.NewPopulationRepository(db),
+		covidService, vaccinationService, cfg.Population.Source)
+	svc.StatusService = service.NewStatusService(covidService, models.StatusThresholds{
+		RtCaution:         cfg.Status.RtCaution,
+		RtCritical:        cfg.Status.RtCritical,
+		IncidenceCaution:  cfg.Status.IncidenceCaution,
+		IncidenceCritical: cfg.Status.IncidenceCritical,
+	})
+	statusPage := service.NewAPIStatusService(repository.NewAPIStatusRepository(db), statusChecks, cfg.StatusPage.Retention)
+	statusPage.SetClock(clk)
+	statusPage.Start(cfg.StatusPage.CheckInterval)
+	defer statusPage.Stop()
+	svc.APIStatusService = statusPage
+	announcements := service.NewAnnouncementService(repository.NewAnnouncementRepository(db))
+	announcements.SetClock(clk)
+	announcements.Start(cfg.Announcements.RefreshInterval)
+	defer announcements.Stop()
+	svc.AnnouncementService = announcements
+	caseNotes := service.NewCaseNoteService(repository.NewCaseNoteRepository(db))
+	caseNotes.SetClock(clk)
+	svc.CaseNoteService = caseNotes
+	apiKeys := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), loadAPIKeys(cfg.Auth))
+	apiKeys.SetClock(clk)
+	if cfg.Auth.Enabled {
+		apiKeys.Start(cfg.Auth.RefreshInterval)
+		defer apiKeys.Stop()
+		log.Println("API key authentication enabled")
+	}
+	ingestion := service.NewIngestionService(repository.NewIngestionRepository(db), cacheInvalidator, cacheWarmer, casePublisher, alertService)
+	ingestion.Start()
+	defer ingestion.Stop()
+	svc.IngestionService = ingestion
+	tenants, err := tenant.NewRegistry(cfg.Tenants.Definitions, cfg.Tenants.Default)
+	if err != nil {
+		log.Fatalf("Invalid tenant configuration: %v", err)
+	}
+	latencyRecorder := middleware.NewLatencyRecorder()
+	svc.Latency = latencyRecorder
+	bandwidthRecorder := middleware.NewBandwidthRecorder()
+	svc.Bandwidth = bandwidthRecorder
+	abuseDetector := middleware.NewAbuseDetector(cfg.Abuse)
+	abuseDetector.SetClock(clk)
+	// Every middleware keyed on the client resolves it behind the same
+	// trusted proxies
+	clients := middleware.NewClientResolver(cfg.RateLimit.TrustedProxies)
+	abuseDetector.SetClientResolver(clients)
+	abuseDetector.StartCleanup(time.Minute)
+	svc.AbuseGuard = abuseDetector
+	rateLimits := middleware.NewRateLimits(cfg.RateLimit)
+	rateLimits.SetClock(clk)
+	svc.RateLimits = rateLimits
+	reloader := config.NewReloader(cfg, config.Reload)
+	reloader.OnChange("RateLimit", func(next *config.Config) { rateLimits.Update(next.RateLimit) })
+	svc.ConfigReloader = reloader
+	reaggregation := service.NewReaggregationService(
+		service.AggregationTask{Name: "freshness", Run: freshness.Load, Preview: freshness.Preview},
+		service.AggregationTask{Name: "data_quality", Run: dataQuality.Scan, Preview: dataQuality.Preview},
+		service.CacheRefreshTask(cacheInvalidator, cacheWarmer),
+	)
+	reaggregation.SetClock(clk)
+	svc.Reaggregation = reaggregation
+	readiness := service.NewReadinessService(db.PingContext, repository.NewSchemaRepository(db), warmCache, service.ReadinessPolicy{
+		DatabaseGracePeriod: cfg.Readiness.DatabaseGracePeriod,
+		RequireCacheWarm:    cfg.Readiness.RequireCacheWarm,
+		RequireMigrations:   cfg.Readiness.RequireMigrations,
+	})
+	readiness.SetClock(clk)
+	svc.Readiness = readiness
+	svc.Admin = cfg.Admin
 	router := handler.SetupRoutes(svc, db, enableSwagger)
 
 	router.Use(middleware.Recovery)
+	router.Use(middleware.Tracing)
+	router.Use(middleware.Tenant(tenants))
 	router.Use(middleware.Logging)
-	router.Use(middleware.RateLimit(cfg.RateLimit))
+	router.Use(middleware.Metrics(latencyRecorder))
+	router.Use(middleware.SlowRequestLog(cfg.Monitoring.SlowRequestThreshold))
+	router.Use(middleware.RequestTimeout(cfg.Server.RequestTimeout))
+	router.Use(middleware.APIKeyAuth(cfg.Auth, apiKeys))
+	router.Use(middleware.Bandwidth(bandwidthRecorder))
+	router.Use(middleware.CrawlerSnapshots(cfg.Crawler))
+	router.Use(middleware.RequestDedup(cfg.Dedup, clients))
+	router.Use(rateLimits.Middleware)
 	router.Use(middleware.CORS)
+	router.Use(responseCache.Middleware)
 
-	address := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("Server starting on %s", address)
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		Handler: middleware.AbuseDetection(abuseDetector)(router),
+	}
+	// Shutdown waits for the open event streams and does not track hijacked
+	// WebSocket connections; closing the stream and the hub ends both
+	server.RegisterOnShutdown(caseStream.Close)
+	server.RegisterOnShutdown(dashboardHub.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 2)
+	go func() {
+		log.Printf("Server starting on %s", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+	// The gRPC server serves the same case data, cache included, behind the
+	// same API keys and rate limits
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.GRPC.Port))
+		if err != nil {
+			log.Fatalf("gRPC server failed to start: %v", err)
+		}
+		grpcServer = grpcserver.NewServer(covidService, grpcserver.Access{Auth: cfg.Auth, APIKeys: apiKeys, RateLimits: rateLimits})
+		go func() {
+			log.Printf("gRPC server starting on %s", lis.Addr())
+			serveErr <- grpcServer.Serve(lis)
+		}()
+	}
 
-	if err := http.ListenAndServe(address, router); err != nil {
+	select {
+	case err := <-serveErr:
 		log.Fatalf("Server failed to start: %v", err)
+	case <-ctx.Done():
+	}
+
+	// The deferred Stop calls run once in-flight requests have finished
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %v", err)
+	}
+	if grpcServer != nil {
+		// Streams still open when the timeout passes are cut off
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
+}
+
+// loadAPIKeys parses the API_KEYS definitions. INGEST_API_KEY keeps working
+// for ingestion as a write scoped key named "ingest".
+func loadAPIKeys(cfg config.AuthConfig) []models.APIKey {
+	keys := make([]models.APIKey, 0, len(cfg.Keys)+1)
+	for _, def := range cfg.Keys {
+		key, err := models.ParseAPIKey(def)
+		if err != nil {
+			log.Fatalf("Invalid API_KEYS: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if ingestKey := os.Getenv("INGEST_API_KEY"); ingestKey != "" {
+		keys = append(keys, models.APIKey{Name: "ingest", KeyHash: models.HashAPIKey(ingestKey), Scopes: []string{models.ScopeWrite}})
 	}
+	return keys
 }
@@ -0,0 +1,361 @@
+// Package cli implements the maintenance subcommands of the pico-api binary,
+// such as database backup, restore, data ingestion, integrity checks, Rt
+// estimation, the startup diagnostics and the smoke test of a deployment.
+package cli
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/internal/smoke"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+	"github.com/banua-coder/pico-api-go/pkg/epi"
+	"github.com/banua-coder/pico-api-go/pkg/telegram"
+	"github.com/banua-coder/pico-api-go/pkg/webhook"
+)
+
+// IsCommand reports whether args start with a known maintenance subcommand.
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "backup", "restore", "ingest", "integrity", "estimate-rt", "check", "smoke":
+		return true
+	}
+	return false
+}
+
+// Run executes the subcommand named by args[0].
+func Run(cfg *config.Config, args []string) error {
+	switch args[0] {
+	case "backup":
+		return runBackup(cfg, args[1:])
+	case "restore":
+		return runRestore(cfg, args[1:])
+	case "ingest":
+		return runIngest(cfg, args[1:])
+	case "integrity":
+		return runIntegrity(cfg, args[1:])
+	case "estimate-rt":
+		return runEstimateRt(cfg, args[1:])
+	case "check":
+		return runCheck(cfg, args[1:])
+	case "smoke":
+		return runSmoke(cfg, args[1:])
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func runBackup(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	out := fs.String("out", "backup-"+time.Now().UTC().Format("20060102-150405")+".sql.gz", "output file (gzip-compressed SQL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := database.NewConnection(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer closeDB(db)
+
+	size, err := service.WriteBackupFile(context.Background(), db, *out)
+	if err != nil {
+		return err
+	}
+	log.Printf("Backup written to %s (%d bytes)", *out, size)
+	return nil
+}
+
+func runRestore(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	in := fs.String("in", "", "backup file to restore (.sql or .sql.gz)")
+	confirm := fs.String("confirm", "", "name of the target database, required to confirm the restore")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *in == "" {
+		return fmt.Errorf("restore requires --in <file>")
+	}
+	if *confirm == "" || *confirm != cfg.Database.DBName {
+		return fmt.Errorf("restore overwrites database %q; re-run with --confirm %s to proceed", cfg.Database.DBName, cfg.Database.DBName)
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("Error closing backup file: %v", err)
+		}
+	}()
+
+	var r io.Reader = f
+	if strings.HasSuffix(*in, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip backup: %w", err)
+		}
+		defer func() {
+			if err := gz.Close(); err != nil {
+				log.Printf("Error closing gzip reader: %v", err)
+			}
+		}()
+		r = gz
+	}
+
+	db, err := database.NewConnection(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer closeDB(db)
+
+	executed, err := db.Restore(context.Background(), r)
+	if err != nil {
+		return err
+	}
+	log.Printf("Restore of %s completed (%d statements)", *in, executed)
+	return nil
+}
+
+func runIngest(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	in := fs.String("in", "", "JSON file with \"national\" and \"province\" case rows")
+	source := fs.String("source", "", "name of the upstream source, overrides the file's \"source\"")
+	dryRun := fs.Bool("dry-run", false, "report the rows that would change without committing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *in == "" {
+		return fmt.Errorf("ingest requires --in <file>")
+	}
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return fmt.Errorf("failed to read ingestion file: %w", err)
+	}
+	var batch models.IngestionBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return fmt.Errorf("failed to parse ingestion file: %w", err)
+	}
+	if *source != "" {
+		batch.Source = *source
+	}
+
+	db, err := database.NewConnection(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer closeDB(db)
+
+	// The API server's cache expires on its own TTL; nothing to invalidate from here
+	webhooks := service.NewWebhookService(repository.NewWebhookRepository(db), webhook.NewDefaultSender())
+	var messenger service.AlertMessenger
+	if cfg.Alerts.TelegramBotToken != "" {
+		messenger = telegram.NewClient(cfg.Alerts.TelegramBotToken)
+	}
+	alerts := service.NewAlertService(repository.NewAlertRepository(db), webhooks, messenger)
+	svc := service.NewIngestionService(repository.NewIngestionRepository(db), nil, nil, webhooks, alerts)
+	summary, err := svc.Ingest(context.Background(), batch, *dryRun)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		log.Printf("Dry run: %d rows would be inserted, %d updated, %d unchanged, %d conflicts",
+			summary.Inserted, summary.Updated, summary.Unchanged, len(summary.Conflicts))
+	} else {
+		log.Printf("Sync run %d: %d inserted, %d updated, %d unchanged, %d conflicts",
+			summary.RunID, summary.Inserted, summary.Updated, summary.Unchanged, len(summary.Conflicts))
+	}
+	for _, c := range summary.Conflicts {
+		log.Printf("  conflict %s %s: %s", c.Table, c.Key, c.Reason)
+	}
+	return nil
+}
+
+func runIntegrity(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("integrity", flag.ContinueOnError)
+	quarantine := fs.Bool("quarantine", false, "move orphaned province case rows to province_cases_quarantine")
+	dryRun := fs.Bool("dry-run", false, "with --quarantine, report the rows that would move without committing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := database.NewConnection(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer closeDB(db)
+
+	svc := service.NewIntegrityService(repository.NewIntegrityRepository(db), nil)
+	report, err := svc.GetOrphanReport(context.Background())
+	if err != nil {
+		return err
+	}
+	log.Printf("Found %d orphaned province case rows (%d missing national day, %d missing province)",
+		len(report.Rows), report.MissingDay, report.MissingProvince)
+	for _, o := range report.Rows {
+		log.Printf("  province_cases id=%d day=%d province_id=%s: %s", o.ID, o.Day, o.ProvinceID, strings.Join(o.Reasons, ", "))
+	}
+
+	if !*quarantine || len(report.Rows) == 0 {
+		return nil
+	}
+	summary, err := svc.QuarantineOrphans(context.Background(), *dryRun)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		log.Printf("Dry run: %d rows would be quarantined", summary.Deleted)
+		return nil
+	}
+	log.Printf("Quarantined %d rows", summary.Deleted)
+	return nil
+}
+
+func runEstimateRt(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("estimate-rt", flag.ContinueOnError)
+	overwrite := fs.Bool("overwrite", false, "replace the Rt of rows that already have one")
+	dryRun := fs.Bool("dry-run", false, "report the rows that would change without committing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := database.NewConnection(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer closeDB(db)
+
+	svc, err := service.NewRtEstimationService(repository.NewRtEstimateRepository(db), epi.Config{
+		SerialIntervalMean: cfg.Rt.SerialIntervalMean,
+		SerialIntervalSD:   cfg.Rt.SerialIntervalSD,
+		WindowDays:         cfg.Rt.WindowDays,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	summary, err := svc.Estimate(context.Background(), *overwrite, *dryRun)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		log.Printf("Dry run: Rt would be written to %d rows", summary.Updated)
+		return nil
+	}
+	log.Printf("Wrote Rt to %d rows", summary.Updated)
+	return nil
+}
+
+// Diagnose runs the startup diagnostics. The database is only connected to
+// when the configuration has no fatal problem; the connection is returned,
+// nil when it failed, for the caller to use and close.
+func Diagnose(cfg *config.Config) (*database.DB, models.DiagnosticsReport) {
+	env := service.StartupEnvironment{
+		ConfigChecks:      cfg.Validate(),
+		RequireMigrations: cfg.Readiness.RequireMigrations,
+		// Bucket stores spool uploads in the temp directory
+		Dirs:         []service.WritableDir{{Name: "TMPDIR", Path: os.TempDir(), Required: true}},
+		MaxClockSkew: cfg.Startup.MaxClockSkew,
+	}
+	if cfg.Storage.IsLocal() {
+		env.Dirs = append([]service.WritableDir{
+			{Name: "EXPORT_DIR", Path: cfg.Export.Dir, Required: true},
+			{Name: "BACKUP_DIR", Path: cfg.Backup.Dir},
+		}, env.Dirs...)
+	}
+
+	var db *database.DB
+	configOK := true
+	for _, c := range env.ConfigChecks {
+		configOK = configOK && c.Status != models.DiagnosticFatal
+	}
+	if configOK {
+		var err error
+		if db, err = database.NewConnection(&cfg.Database); err != nil {
+			env.ConnectErr = err
+		} else {
+			env.Schema = repository.NewSchemaRepository(db)
+			env.ServerTime = db.ServerTime
+		}
+	} else {
+		env.ConnectErr = errors.New("not attempted because of the fatal configuration problems")
+	}
+	return db, service.DiagnoseStartup(context.Background(), env)
+}
+
+func runCheck(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, report := Diagnose(cfg)
+	if db != nil {
+		defer closeDB(db)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(service.FormatDiagnostics(report))
+	}
+	if !report.OK {
+		return fmt.Errorf("%d fatal problems", report.Fatal)
+	}
+	return nil
+}
+
+func runSmoke(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("smoke", flag.ContinueOnError)
+	baseURL := fs.String("base-url", "http://"+net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port)), "deployment to test, e.g. https://pico-api.banuacoder.com")
+	apiKey := fs.String("api-key", "", "API key sent as X-API-Key, e.g. for a higher rate limit")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout of each request")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report := smoke.Run(context.Background(), smoke.Options{BaseURL: *baseURL, APIKey: *apiKey, Timeout: *timeout})
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(smoke.Format(report))
+	}
+	if !report.OK {
+		return fmt.Errorf("%d of %d checks failed", report.Failed, len(report.Results))
+	}
+	return nil
+}
+
+func closeDB(db *database.DB) {
+	if err := db.Close(); err != nil {
+		log.Printf("Error closing database connection: %v", err)
+	}
+}
@@ -1,21 +1,57 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database  DatabaseConfig
-	Server    ServerConfig
-	RateLimit RateLimitConfig
+	Database      DatabaseConfig
+	Server        ServerConfig
+	GRPC          GRPCConfig
+	RateLimit     RateLimitConfig
+	Monitoring    MonitoringConfig
+	Backup        BackupConfig
+	Webhooks      WebhookConfig
+	Alerts        AlertConfig
+	Mail          MailConfig
+	Subscriptions SubscriptionConfig
+	Share         ShareConfig
+	Cache         CacheConfig
+	Tenants       TenantConfig
+	Status        StatusConfig
+	Tracing       TracingConfig
+	Crawler       CrawlerConfig
+	Dedup         DedupConfig
+	Validation    ValidationConfig
+	Abuse         AbuseConfig
+	StatusPage    StatusPageConfig
+	Readiness     ReadinessConfig
+	Announcements AnnouncementConfig
+	Auth          AuthConfig
+	ResponseCache ResponseCacheConfig
+	WebSocket     WebSocketConfig
+	Export        ExportConfig
+	Storage       StorageConfig
+	Population    PopulationConfig
+	Rt            RtConfig
+	DataQuality   DataQualityConfig
+	Admin         AdminConfig
+	Demo          DemoConfig
+	Startup       StartupConfig
 }
 
 type DatabaseConfig struct {
+	// Driver is mysql (the default) or postgres
+	Driver          string
 	Host            string
 	Port            int
 	Username        string
@@ -25,11 +61,31 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	// Replicas are the "host[:port]" addresses of read replicas sharing the
+	// primary's credentials and database name. Read-only queries are spread
+	// over them; writes and transactions stay on the primary.
+	Replicas []string
+	// ReplicaRetryInterval is how long a replica that failed is skipped
+	ReplicaRetryInterval time.Duration
+	// SSLMode is the sslmode of PostgreSQL connections
+	SSLMode string
 }
 
 type ServerConfig struct {
 	Port int
 	Host string
+	// RequestTimeout cancels a request's database queries once exceeded; zero disables it
+	RequestTimeout time.Duration
+	// ShutdownTimeout is how long in-flight requests may finish after a
+	// SIGINT or SIGTERM before the server exits
+	ShutdownTimeout time.Duration
+}
+
+// GRPCConfig controls the gRPC server of the case data, which listens on
+// the server host beside the HTTP server
+type GRPCConfig struct {
+	Enabled bool
+	Port    int
 }
 
 type RateLimitConfig struct {
@@ -37,35 +93,516 @@ type RateLimitConfig struct {
 	RequestsPerMinute int
 	BurstSize         int
 	WindowSize        time.Duration
+	// ExemptPathPrefixes are request paths served without rate limiting
+	ExemptPathPrefixes []string
+	// SoftLimitRequests is how many requests per client may wait for a free
+	// slot instead of getting a 429; zero disables the soft limit
+	SoftLimitRequests int
+	// SoftLimitMaxWait is the longest such a request is delayed
+	SoftLimitMaxWait time.Duration
+	// RouteLimits are "path[?query]:requests_per_minute" limits replacing
+	// the global one for matching requests; "*" matches one path segment
+	RouteLimits []string
+	// Tiers are "name:requests_per_minute" limits that API keys refer to
+	Tiers []string
+	// TrustedProxies are the CIDRs of proxies whose X-Forwarded-For is
+	// honored; when empty the forwarding headers are always trusted
+	TrustedProxies []string
+	// CachedRequestCost is the fraction of a request that a response served
+	// from the response cache counts against the limit: 0 does not count it,
+	// 1 counts it in full
+	CachedRequestCost float64
+	// MaxConcurrentRequests caps the requests of one client, counted like
+	// the limit, in flight at the same time; zero disables the cap
+	MaxConcurrentRequests int
+}
+
+type MonitoringConfig struct {
+	TableStatsEnabled      bool
+	TableStatsInterval     time.Duration
+	TableGrowthWarnPercent float64
+	DiskQuotaBytes         int64
+	SlowRequestThreshold   time.Duration
+	SlowQueryThreshold     time.Duration
+}
+
+type BackupConfig struct {
+	Dir string
+}
+
+type WebhookConfig struct {
+	RetryInterval time.Duration
+	// PollInterval is how often new case rows are looked for to notify subscribers
+	PollInterval time.Duration
+}
+
+type AlertConfig struct {
+	TelegramBotToken string
+}
+
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+type SubscriptionConfig struct {
+	PublicBaseURL  string
+	UpdateInterval time.Duration
+}
+
+// CacheConfig controls the in-memory query cache. LatestTTL applies to the
+// most recent day of data (/national/latest, /provinces), HistoricalTTL to
+// fixed date ranges and DefaultTTL to other lists.
+type CacheConfig struct {
+	Enabled         bool
+	DefaultTTL      time.Duration
+	LatestTTL       time.Duration
+	HistoricalTTL   time.Duration
+	CleanupInterval time.Duration
+}
+
+type ShareConfig struct {
+	DashboardURL string
+}
+
+type TenantConfig struct {
+	// Definitions are extra "slug:province_id:name" tenants next to the built-in sulteng
+	Definitions []string
+	// Default is the slug of the tenant serving requests that select none
+	Default string
+}
+
+// StatusConfig holds the thresholds of the province status overview. Rt
+// thresholds apply to the 7-day average Rt, incidence thresholds to the new
+// positive cases of the last 7 days.
+type StatusConfig struct {
+	RtCaution         float64
+	RtCritical        float64
+	IncidenceCaution  float64
+	IncidenceCritical float64
+}
+
+// TracingConfig configures the OTLP span exporter. Tracing is disabled while
+// Endpoint is empty.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector base URL; spans go to Endpoint + "/v1/traces"
+	Endpoint    string
+	ServiceName string
+	// Headers are "key=value" pairs sent with every export, e.g. an API key
+	Headers       []string
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// DedupConfig controls the micro-cache that answers identical GET requests
+// from the same client within a short window. It is disabled while Window is
+// zero.
+type DedupConfig struct {
+	// Window is how long a response is reused, capped at 5 seconds
+	Window time.Duration
+}
+
+// ResponseCacheConfig controls the Cache-Control and Expires headers of
+// successful GET responses and the optional in-memory cache of them
+type ResponseCacheConfig struct {
+	// Policies are "path[?query]:max_age" definitions, e.g.
+	// /api/v1/national/latest:5m, where "*" matches any one path segment or
+	// any non-empty parameter value and a path ending in "$" does not match
+	// the paths below it. The most specific matching policy applies; other
+	// routes get no headers.
+	Policies []string
+	// ServeEnabled answers repeated requests for the same URL from memory
+	// until their policy's max age passes
+	ServeEnabled bool
+}
+
+// WebSocketConfig limits the connections to the realtime dashboard WebSocket;
+// zero disables a limit
+type WebSocketConfig struct {
+	MaxConnections          int
+	MaxConnectionsPerClient int
+}
+
+// RtConfig configures the estimation of missing Rt values from daily cases
+type RtConfig struct {
+	// SerialIntervalMean and SerialIntervalSD describe the serial interval
+	// in days
+	SerialIntervalMean float64
+	SerialIntervalSD   float64
+	// WindowDays is the number of days each estimate is smoothed over
+	WindowDays int
+	// EstimationInterval is how often missing Rt values are estimated; zero
+	// leaves estimation to the admin endpoint
+	EstimationInterval time.Duration
+}
+
+// DataQualityConfig controls the scans flagging anomalies in the case data
+type DataQualityConfig struct {
+	// ScanInterval is how often the data is scanned besides after each
+	// ingestion; zero scans only at startup and after ingestions
+	ScanInterval time.Duration
+	// OutlierWindowDays is how many days before a daily count it is
+	// compared to
+	OutlierWindowDays int
+	// OutlierThreshold is how many robust standard deviations from the
+	// median of the window make a daily count an outlier
+	OutlierThreshold float64
+	// OutlierMinDeviation is the smallest distance from the median flagged
+	OutlierMinDeviation int64
+}
+
+// AdminConfig controls the admin endpoints under /admin and /api/v1/admin,
+// which take the ADMIN_KEY in X-Admin-Key or an admin scoped API key
+type AdminConfig struct {
+	Enabled bool
+}
+
+// DemoConfig controls the demo mode, which runs the API on a clock starting
+// at ClockStart so historic data shows up as current: "latest" figures, date
+// range presets, rate limit windows and schedules all follow that clock
+type DemoConfig struct {
+	// ClockStart is the time the clock shows at startup; zero keeps the
+	// system clock
+	ClockStart time.Time
+}
+
+// PopulationConfig selects the population figures of per capita statistics
+type PopulationConfig struct {
+	// Source is the province_populations source to read, e.g. bps-2020
+	Source string
+}
+
+// ExportConfig controls the queued case exports
+type ExportConfig struct {
+	// Dir is where export files are written until they expire
+	Dir string
+	// QueueSize caps the exports waiting to run
+	QueueSize int
+	// Retention is how long a finished export stays downloadable
+	Retention time.Duration
+}
+
+// StorageConfig selects where exports and backups are stored
+type StorageConfig struct {
+	// Driver is local (the default), keeping files in EXPORT_DIR and
+	// BACKUP_DIR, s3 or gcs
+	Driver string
+	Bucket string
+	// Prefix is prepended to the keys of the bucket, e.g. pico/
+	Prefix string
+	// Endpoint is the URL of an S3 compatible service; empty uses AWS S3
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// IsLocal reports whether files are kept in EXPORT_DIR and BACKUP_DIR rather
+// than a bucket
+func (c StorageConfig) IsLocal() bool {
+	driver := strings.ToLower(strings.TrimSpace(c.Driver))
+	return driver == "" || driver == "local"
+}
+
+// ValidationConfig controls the checks on request parameters
+type ValidationConfig struct {
+	// MaxDateRangeDays caps the days between start_date and end_date; zero
+	// allows any range
+	MaxDateRangeDays int
+}
+
+// AbuseConfig controls the automatic temporary bans of clients that keep
+// getting 404 and 429 responses, as scrapers walking IDs do
+type AbuseConfig struct {
+	Enabled bool
+	// StrikeLimit is the number of 404 and 429 responses within StrikeWindow
+	// that gets a client banned
+	StrikeLimit  int
+	StrikeWindow time.Duration
+	BanDuration  time.Duration
+}
+
+// StatusPageConfig controls the component checks behind /api/v1/system-status
+type StatusPageConfig struct {
+	// CheckInterval is how often components are checked for the uptime figures
+	CheckInterval time.Duration
+	// Retention is how long check results are kept
+	Retention time.Duration
+}
+
+// ReadinessConfig decides which failing checks of /readyz make the API
+// unready rather than degraded
+type ReadinessConfig struct {
+	// DatabaseGracePeriod is how long failing database pings are tolerated
+	DatabaseGracePeriod time.Duration
+	// RequireCacheWarm keeps the API unready until the query cache was
+	// warmed after startup
+	RequireCacheWarm bool
+	// RequireMigrations keeps the API unready while migrations are missing
+	RequireMigrations bool
+}
+
+// StartupConfig controls the diagnostics run before the API serves
+type StartupConfig struct {
+	// MaxClockSkew is how far the system clock may be off the database
+	// server's before a warning is reported
+	MaxClockSkew time.Duration
+}
+
+// AnnouncementConfig controls the announcements shown in meta.notices
+type AnnouncementConfig struct {
+	// RefreshInterval is how often announcements are reloaded, so changes
+	// made through another instance show up
+	RefreshInterval time.Duration
+}
+
+// AuthConfig controls the optional X-API-Key authentication of /api/v1 and
+// /admin requests
+type AuthConfig struct {
+	Enabled bool
+	// Keys are "name:key:scopes[:requests_per_minute|tier]" definitions accepted
+	// alongside the keys stored in the api_keys table
+	Keys []string
+	// ExemptPathPrefixes are request paths served without a key
+	ExemptPathPrefixes []string
+	// RefreshInterval is how often stored keys are reloaded
+	RefreshInterval time.Duration
+}
+
+// CrawlerConfig controls /robots.txt and how requests from crawlers are served
+type CrawlerConfig struct {
+	// RobotsDisallow are the path prefixes robots.txt asks crawlers to skip
+	RobotsDisallow []string
+	// RobotsCrawlDelay is the Crawl-delay in seconds; zero leaves it out
+	RobotsCrawlDelay int
+	// SnapshotsEnabled serves crawlers cached copies of successful responses
+	SnapshotsEnabled bool
+	SnapshotTTL      time.Duration
+	// UserAgents are extra User-Agent substrings that identify a crawler
+	UserAgents []string
 }
 
 func Load() *Config {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables or defaults")
 	}
+	return fromEnv()
+}
+
+// Reload rereads the configuration with the values of the .env file taking
+// precedence over the environment, so edits to the file apply. Variables
+// removed from the file keep their previous value.
+func Reload() (*Config, error) {
+	if err := godotenv.Overload(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+	return fromEnv(), nil
+}
 
+func fromEnv() *Config {
+	driver := getEnv("DB_DRIVER", "mysql")
 	return &Config{
 		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "127.0.0.1"), // Changed default to 127.0.0.1
-			Port:            getEnvAsInt("DB_PORT", 3306),
-			Username:        getEnv("DB_USERNAME", ""),
-			Password:        getEnv("DB_PASSWORD", ""),
-			DBName:          getEnv("DB_NAME", ""),
-			MaxOpenConns:    getEnvAsInt("MYSQL_MAX_OPEN_CONNS", 5),
-			MaxIdleConns:    getEnvAsInt("MYSQL_MAX_IDLE_CONNS", 2),
-			ConnMaxLifetime: getEnvAsDuration("MYSQL_CONN_MAX_LIFETIME", 30*time.Second),
-			ConnMaxIdleTime: getEnvAsDuration("MYSQL_CONN_MAX_IDLE_TIME", 15*time.Second),
+			Driver:               driver,
+			Host:                 getEnv("DB_HOST", "127.0.0.1"), // Changed default to 127.0.0.1
+			Port:                 getEnvAsInt("DB_PORT", defaultDatabasePort(driver)),
+			Username:             getEnv("DB_USERNAME", ""),
+			Password:             getEnv("DB_PASSWORD", ""),
+			DBName:               getEnv("DB_NAME", ""),
+			MaxOpenConns:         getEnvAsInt("MYSQL_MAX_OPEN_CONNS", 5),
+			MaxIdleConns:         getEnvAsInt("MYSQL_MAX_IDLE_CONNS", 2),
+			ConnMaxLifetime:      getEnvAsDuration("MYSQL_CONN_MAX_LIFETIME", 30*time.Second),
+			ConnMaxIdleTime:      getEnvAsDuration("MYSQL_CONN_MAX_IDLE_TIME", 15*time.Second),
+			Replicas:             getEnvAsList("DB_READ_REPLICAS", nil),
+			ReplicaRetryInterval: getEnvAsDuration("DB_REPLICA_RETRY_INTERVAL", 30*time.Second),
+			SSLMode:              getEnv("DB_SSL_MODE", "require"),
 		},
 		Server: ServerConfig{
-			Port: getEnvAsInt("SERVER_PORT", 8080),
-			Host: getEnv("SERVER_HOST", "localhost"),
+			Port:            getEnvAsInt("SERVER_PORT", 8080),
+			Host:            getEnv("SERVER_HOST", "localhost"),
+			RequestTimeout:  getEnvAsDuration("REQUEST_TIMEOUT", 30*time.Second),
+			ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		},
+		GRPC: GRPCConfig{
+			Enabled: getEnvAsBool("GRPC_ENABLED", false),
+			Port:    getEnvAsInt("GRPC_PORT", 9090),
 		},
 		RateLimit: RateLimitConfig{
-			Enabled:           getEnvAsBool("RATE_LIMIT_ENABLED", true),
-			RequestsPerMinute: getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 100),
-			BurstSize:         getEnvAsInt("RATE_LIMIT_BURST_SIZE", 20),
-			WindowSize:        getEnvAsDuration("RATE_LIMIT_WINDOW_SIZE", 1*time.Minute),
+			Enabled:               getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			RequestsPerMinute:     getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 100),
+			BurstSize:             getEnvAsInt("RATE_LIMIT_BURST_SIZE", 20),
+			WindowSize:            getEnvAsDuration("RATE_LIMIT_WINDOW_SIZE", 1*time.Minute),
+			ExemptPathPrefixes:    getEnvAsList("RATE_LIMIT_EXEMPT_PATHS", []string{"/api/v1/embed/", "/healthz", "/readyz"}),
+			SoftLimitRequests:     getEnvAsInt("RATE_LIMIT_SOFT_LIMIT_REQUESTS", 0),
+			SoftLimitMaxWait:      getEnvAsDuration("RATE_LIMIT_SOFT_LIMIT_MAX_WAIT", 2*time.Second),
+			RouteLimits:           getEnvAsList("RATE_LIMIT_ROUTES", nil),
+			Tiers:                 getEnvAsList("RATE_LIMIT_TIERS", nil),
+			TrustedProxies:        getEnvAsList("RATE_LIMIT_TRUSTED_PROXIES", nil),
+			CachedRequestCost:     getEnvAsFloat("RATE_LIMIT_CACHED_REQUEST_COST", 0),
+			MaxConcurrentRequests: getEnvAsInt("RATE_LIMIT_MAX_CONCURRENT_REQUESTS", 0),
+		},
+		Monitoring: MonitoringConfig{
+			TableStatsEnabled:      getEnvAsBool("TABLE_STATS_ENABLED", true),
+			TableStatsInterval:     getEnvAsDuration("TABLE_STATS_INTERVAL", 1*time.Hour),
+			TableGrowthWarnPercent: getEnvAsFloat("TABLE_STATS_GROWTH_WARN_PERCENT", 50),
+			DiskQuotaBytes:         int64(getEnvAsInt("DISK_QUOTA_MB", 0)) * 1024 * 1024,
+			SlowRequestThreshold:   getEnvAsDuration("SLOW_REQUEST_THRESHOLD", 1*time.Second),
+			SlowQueryThreshold:     getEnvAsDuration("SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		},
+		Backup: BackupConfig{
+			Dir: getEnv("BACKUP_DIR", "backups"),
+		},
+		Webhooks: WebhookConfig{
+			RetryInterval: getEnvAsDuration("WEBHOOK_RETRY_INTERVAL", 1*time.Minute),
+			PollInterval:  getEnvAsDuration("WEBHOOK_POLL_INTERVAL", 1*time.Minute),
+		},
+		Alerts: AlertConfig{
+			TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
+		},
+		Mail: MailConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", ""),
+		},
+		Subscriptions: SubscriptionConfig{
+			PublicBaseURL:  getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+			UpdateInterval: getEnvAsDuration("SUBSCRIPTION_UPDATE_INTERVAL", 15*time.Minute),
+		},
+		Cache: CacheConfig{
+			Enabled:         getEnvAsBool("CACHE_ENABLED", true),
+			DefaultTTL:      getEnvAsDuration("CACHE_TTL_DEFAULT", time.Hour),
+			LatestTTL:       getEnvAsDuration("CACHE_TTL_LATEST", 15*time.Minute),
+			HistoricalTTL:   getEnvAsDuration("CACHE_TTL_HISTORICAL", 24*time.Hour),
+			CleanupInterval: getEnvAsDuration("CACHE_CLEANUP_INTERVAL", 5*time.Minute),
+		},
+		Share: ShareConfig{
+			DashboardURL: getEnv("SHARE_DASHBOARD_URL", ""),
+		},
+		Tenants: TenantConfig{
+			Definitions: getEnvAsList("TENANTS", nil),
+			Default:     getEnv("DEFAULT_TENANT", ""),
+		},
+		Status: StatusConfig{
+			RtCaution:         getEnvAsFloat("STATUS_RT_CAUTION", 1.0),
+			RtCritical:        getEnvAsFloat("STATUS_RT_CRITICAL", 1.2),
+			IncidenceCaution:  getEnvAsFloat("STATUS_INCIDENCE_CAUTION", 50),
+			IncidenceCritical: getEnvAsFloat("STATUS_INCIDENCE_CRITICAL", 200),
+		},
+		Tracing: TracingConfig{
+			Endpoint:      getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			ServiceName:   getEnv("OTEL_SERVICE_NAME", "pico-api-go"),
+			Headers:       getEnvAsList("OTEL_EXPORTER_OTLP_HEADERS", nil),
+			BatchSize:     getEnvAsInt("TRACING_BATCH_SIZE", 256),
+			FlushInterval: getEnvAsDuration("TRACING_FLUSH_INTERVAL", 5*time.Second),
+		},
+		Crawler: CrawlerConfig{
+			RobotsDisallow:   getEnvAsList("ROBOTS_DISALLOW", []string{"/api/", "/admin/", "/swagger/"}),
+			RobotsCrawlDelay: getEnvAsInt("ROBOTS_CRAWL_DELAY", 10),
+			SnapshotsEnabled: getEnvAsBool("CRAWLER_SNAPSHOTS_ENABLED", true),
+			SnapshotTTL:      getEnvAsDuration("CRAWLER_SNAPSHOT_TTL", 6*time.Hour),
+			UserAgents:       getEnvAsList("CRAWLER_USER_AGENTS", nil),
+		},
+		Dedup: DedupConfig{
+			Window: getEnvAsDuration("REQUEST_DEDUP_WINDOW", 0),
+		},
+		Abuse: AbuseConfig{
+			Enabled:      getEnvAsBool("ABUSE_DETECTION_ENABLED", true),
+			StrikeLimit:  getEnvAsInt("ABUSE_STRIKE_LIMIT", 60),
+			StrikeWindow: getEnvAsDuration("ABUSE_STRIKE_WINDOW", 5*time.Minute),
+			BanDuration:  getEnvAsDuration("ABUSE_BAN_DURATION", time.Hour),
+		},
+		Validation: ValidationConfig{
+			MaxDateRangeDays: getEnvAsInt("MAX_DATE_RANGE_DAYS", 0),
+		},
+		StatusPage: StatusPageConfig{
+			CheckInterval: getEnvAsDuration("STATUS_PAGE_CHECK_INTERVAL", time.Minute),
+			Retention:     getEnvAsDuration("STATUS_PAGE_RETENTION", 30*24*time.Hour),
+		},
+		Readiness: ReadinessConfig{
+			DatabaseGracePeriod: getEnvAsDuration("READINESS_DB_GRACE_PERIOD", 30*time.Second),
+			RequireCacheWarm:    getEnvAsBool("READINESS_REQUIRE_CACHE_WARM", true),
+			RequireMigrations:   getEnvAsBool("READINESS_REQUIRE_MIGRATIONS", true),
+		},
+		Announcements: AnnouncementConfig{
+			RefreshInterval: getEnvAsDuration("ANNOUNCEMENT_REFRESH_INTERVAL", time.Minute),
+		},
+		Auth: AuthConfig{
+			Enabled: getEnvAsBool("AUTH_ENABLED", false),
+			Keys:    getEnvAsList("API_KEYS", nil),
+			ExemptPathPrefixes: getEnvAsList("AUTH_EXEMPT_PATHS", []string{
+				"/api/v1/health", "/api/v1/.well-known/", "/api/v1/embed/", "/api/v1/og/",
+				"/api/v1/system-status", "/api/v1/subscriptions",
+			}),
+			RefreshInterval: getEnvAsDuration("API_KEY_REFRESH_INTERVAL", 5*time.Minute),
+		},
+		ResponseCache: ResponseCacheConfig{
+			Policies: getEnvAsList("RESPONSE_CACHE_POLICIES", []string{
+				// Open-ended lists include the latest day, so only lists
+				// ending at a given date are kept for long
+				"/api/v1/national/latest:5m", "/api/v1/provinces/latest:5m",
+				"/api/v1/national$:5m", "/api/v1/national$?end_date=*:1h",
+				"/api/v1/provinces/cases$:5m", "/api/v1/provinces/cases$?end_date=*:1h",
+				"/api/v1/provinces/*/cases$:5m", "/api/v1/provinces/*/cases$?end_date=*:1h",
+			}),
+			ServeEnabled: getEnvAsBool("RESPONSE_CACHE_SERVE_ENABLED", false),
 		},
+		WebSocket: WebSocketConfig{
+			MaxConnections:          getEnvAsInt("WS_MAX_CONNECTIONS", 1000),
+			MaxConnectionsPerClient: getEnvAsInt("WS_MAX_CONNECTIONS_PER_CLIENT", 5),
+		},
+		Export: ExportConfig{
+			Dir:       getEnv("EXPORT_DIR", "exports"),
+			QueueSize: getEnvAsInt("EXPORT_QUEUE_SIZE", 20),
+			Retention: getEnvAsDuration("EXPORT_RETENTION", 24*time.Hour),
+		},
+		Storage: StorageConfig{
+			Driver:          getEnv("STORAGE_DRIVER", "local"),
+			Bucket:          getEnv("STORAGE_BUCKET", ""),
+			Prefix:          getEnv("STORAGE_PREFIX", ""),
+			Endpoint:        getEnv("STORAGE_ENDPOINT", ""),
+			Region:          getEnv("STORAGE_REGION", ""),
+			AccessKeyID:     getEnv("STORAGE_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("STORAGE_SECRET_ACCESS_KEY", ""),
+		},
+		Population: PopulationConfig{
+			Source: getEnv("POPULATION_SOURCE", "bps-2020"),
+		},
+		Rt: RtConfig{
+			SerialIntervalMean: getEnvAsFloat("RT_SERIAL_INTERVAL_MEAN", 4.7),
+			SerialIntervalSD:   getEnvAsFloat("RT_SERIAL_INTERVAL_SD", 2.9),
+			WindowDays:         getEnvAsInt("RT_WINDOW_DAYS", 7),
+			EstimationInterval: getEnvAsDuration("RT_ESTIMATION_INTERVAL", 0),
+		},
+		DataQuality: DataQualityConfig{
+			ScanInterval:        getEnvAsDuration("DATA_QUALITY_SCAN_INTERVAL", time.Hour),
+			OutlierWindowDays:   getEnvAsInt("DATA_QUALITY_OUTLIER_WINDOW_DAYS", 28),
+			OutlierThreshold:    getEnvAsFloat("DATA_QUALITY_OUTLIER_THRESHOLD", 10),
+			OutlierMinDeviation: int64(getEnvAsInt("DATA_QUALITY_OUTLIER_MIN_DEVIATION", 50)),
+		},
+		Admin: AdminConfig{
+			Enabled: getEnvAsBool("ADMIN_API_ENABLED", false),
+		},
+		Demo: DemoConfig{
+			ClockStart: getEnvAsTime("DEMO_CLOCK_START", time.Time{}),
+		},
+		Startup: StartupConfig{
+			MaxClockSkew: getEnvAsDuration("STARTUP_MAX_CLOCK_SKEW", time.Minute),
+		},
+	}
+}
+
+// defaultDatabasePort is the port the driver's engine listens on by default
+func defaultDatabasePort(driver string) int {
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql", "pgx":
+		return 5432
+	default:
+		return 3306
 	}
 }
 
@@ -94,6 +631,15 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -102,3 +648,34 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsTime parses an RFC 3339 timestamp or a YYYY-MM-DD date, which is
+// midnight UTC
+func getEnvAsTime(key string, defaultValue time.Time) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t
+	}
+	return defaultValue
+}
+
+// getEnvAsList splits a comma separated value, dropping empty items
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -16,10 +16,13 @@ func unsetEnvVars(keys ...string) {
 }
 
 func TestLoad_Defaults(t *testing.T) {
-	unsetEnvVars("DB_HOST", "DB_PORT", "DB_USERNAME", "DB_PASSWORD", "DB_NAME",
-		"SERVER_PORT", "SERVER_HOST", "RATE_LIMIT_ENABLED", "RATE_LIMIT_REQUESTS_PER_MINUTE",
-		"RATE_LIMIT_BURST_SIZE", "RATE_LIMIT_WINDOW_SIZE",
-		"MYSQL_MAX_OPEN_CONNS", "MYSQL_MAX_IDLE_CONNS", "MYSQL_CONN_MAX_LIFETIME", "MYSQL_CONN_MAX_IDLE_TIME")
+	unsetEnvVars("DB_DRIVER", "DB_HOST", "DB_PORT", "DB_USERNAME", "DB_PASSWORD", "DB_NAME",
+		"SERVER_PORT", "SERVER_HOST", "REQUEST_TIMEOUT", "SHUTDOWN_TIMEOUT", "RATE_LIMIT_ENABLED", "RATE_LIMIT_REQUESTS_PER_MINUTE",
+		"RATE_LIMIT_BURST_SIZE", "RATE_LIMIT_WINDOW_SIZE", "RATE_LIMIT_EXEMPT_PATHS",
+		"CACHE_ENABLED", "TENANTS", "DEFAULT_TENANT", "CACHE_TTL_DEFAULT", "CACHE_TTL_LATEST", "CACHE_TTL_HISTORICAL",
+		"MYSQL_MAX_OPEN_CONNS", "MYSQL_MAX_IDLE_CONNS", "MYSQL_CONN_MAX_LIFETIME", "MYSQL_CONN_MAX_IDLE_TIME",
+		"STATUS_RT_CAUTION", "STATUS_RT_CRITICAL", "STATUS_INCIDENCE_CAUTION", "STATUS_INCIDENCE_CRITICAL",
+		"WS_MAX_CONNECTIONS", "WS_MAX_CONNECTIONS_PER_CLIENT", "GRPC_ENABLED", "GRPC_PORT")
 
 	cfg := Load()
 
@@ -31,10 +34,64 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.Equal(t, 15*time.Second, cfg.Database.ConnMaxIdleTime)
 	assert.Equal(t, 8080, cfg.Server.Port)
 	assert.Equal(t, "localhost", cfg.Server.Host)
+	assert.Equal(t, 30*time.Second, cfg.Server.RequestTimeout)
+	assert.Equal(t, 15*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, GRPCConfig{Port: 9090}, cfg.GRPC)
 	assert.True(t, cfg.RateLimit.Enabled)
 	assert.Equal(t, 100, cfg.RateLimit.RequestsPerMinute)
 	assert.Equal(t, 20, cfg.RateLimit.BurstSize)
 	assert.Equal(t, 1*time.Minute, cfg.RateLimit.WindowSize)
+	assert.Equal(t, []string{"/api/v1/embed/", "/healthz", "/readyz"}, cfg.RateLimit.ExemptPathPrefixes)
+	assert.Zero(t, cfg.RateLimit.SoftLimitRequests)
+	assert.Equal(t, 2*time.Second, cfg.RateLimit.SoftLimitMaxWait)
+	assert.Empty(t, cfg.RateLimit.RouteLimits)
+	assert.Empty(t, cfg.RateLimit.Tiers)
+	assert.Empty(t, cfg.RateLimit.TrustedProxies)
+	assert.Zero(t, cfg.RateLimit.CachedRequestCost)
+	assert.Zero(t, cfg.RateLimit.MaxConcurrentRequests)
+	assert.True(t, cfg.Cache.Enabled)
+	assert.Equal(t, 15*time.Minute, cfg.Cache.LatestTTL)
+	assert.Equal(t, 24*time.Hour, cfg.Cache.HistoricalTTL)
+	assert.Equal(t, time.Hour, cfg.Cache.DefaultTTL)
+	assert.Empty(t, cfg.Tenants.Definitions)
+	assert.Empty(t, cfg.Tenants.Default)
+	assert.Equal(t, StatusConfig{RtCaution: 1.0, RtCritical: 1.2, IncidenceCaution: 50, IncidenceCritical: 200}, cfg.Status)
+	assert.Equal(t, TracingConfig{ServiceName: "pico-api-go", BatchSize: 256, FlushInterval: 5 * time.Second}, cfg.Tracing)
+	assert.Equal(t, CrawlerConfig{
+		RobotsDisallow:   []string{"/api/", "/admin/", "/swagger/"},
+		RobotsCrawlDelay: 10,
+		SnapshotsEnabled: true,
+		SnapshotTTL:      6 * time.Hour,
+	}, cfg.Crawler)
+	assert.Equal(t, DedupConfig{}, cfg.Dedup)
+	assert.Zero(t, cfg.Validation.MaxDateRangeDays)
+	assert.Equal(t, AbuseConfig{Enabled: true, StrikeLimit: 60, StrikeWindow: 5 * time.Minute, BanDuration: time.Hour}, cfg.Abuse)
+	assert.Equal(t, StatusPageConfig{CheckInterval: time.Minute, Retention: 30 * 24 * time.Hour}, cfg.StatusPage)
+	assert.Equal(t, ReadinessConfig{DatabaseGracePeriod: 30 * time.Second, RequireCacheWarm: true, RequireMigrations: true}, cfg.Readiness)
+	assert.Equal(t, AnnouncementConfig{RefreshInterval: time.Minute}, cfg.Announcements)
+	assert.Equal(t, AuthConfig{
+		ExemptPathPrefixes: []string{
+			"/api/v1/health", "/api/v1/.well-known/", "/api/v1/embed/", "/api/v1/og/",
+			"/api/v1/system-status", "/api/v1/subscriptions",
+		},
+		RefreshInterval: 5 * time.Minute,
+	}, cfg.Auth)
+	assert.Equal(t, ResponseCacheConfig{
+		Policies: []string{
+			"/api/v1/national/latest:5m", "/api/v1/provinces/latest:5m",
+			"/api/v1/national$:5m", "/api/v1/national$?end_date=*:1h",
+			"/api/v1/provinces/cases$:5m", "/api/v1/provinces/cases$?end_date=*:1h",
+			"/api/v1/provinces/*/cases$:5m", "/api/v1/provinces/*/cases$?end_date=*:1h",
+		},
+	}, cfg.ResponseCache)
+	assert.Equal(t, WebSocketConfig{MaxConnections: 1000, MaxConnectionsPerClient: 5}, cfg.WebSocket)
+	assert.Equal(t, ExportConfig{Dir: "exports", QueueSize: 20, Retention: 24 * time.Hour}, cfg.Export)
+	assert.Equal(t, PopulationConfig{Source: "bps-2020"}, cfg.Population)
+	assert.Equal(t, RtConfig{SerialIntervalMean: 4.7, SerialIntervalSD: 2.9, WindowDays: 7}, cfg.Rt)
+	assert.Equal(t, DataQualityConfig{ScanInterval: time.Hour, OutlierWindowDays: 28, OutlierThreshold: 10, OutlierMinDeviation: 50}, cfg.DataQuality)
+	assert.False(t, cfg.Admin.Enabled)
+	assert.True(t, cfg.Demo.ClockStart.IsZero())
+	assert.Equal(t, StartupConfig{MaxClockSkew: time.Minute}, cfg.Startup)
 }
 
 func TestLoad_FromEnv(t *testing.T) {
@@ -43,12 +100,16 @@ func TestLoad_FromEnv(t *testing.T) {
 	require.NoError(t, os.Setenv("DB_USERNAME", "admin"))
 	require.NoError(t, os.Setenv("DB_PASSWORD", "secret"))
 	require.NoError(t, os.Setenv("DB_NAME", "pico_db"))
+	require.NoError(t, os.Setenv("DB_READ_REPLICAS", "replica-1, replica-2:3307"))
 	require.NoError(t, os.Setenv("SERVER_PORT", "9090"))
+	require.NoError(t, os.Setenv("REQUEST_TIMEOUT", "5s"))
+	require.NoError(t, os.Setenv("TENANTS", "gorontalo:75:Gorontalo, sulbar:76:Sulawesi Barat"))
+	require.NoError(t, os.Setenv("DEFAULT_TENANT", "gorontalo"))
 	require.NoError(t, os.Setenv("RATE_LIMIT_ENABLED", "false"))
 	require.NoError(t, os.Setenv("RATE_LIMIT_REQUESTS_PER_MINUTE", "200"))
 	t.Cleanup(func() {
-		unsetEnvVars("DB_HOST", "DB_PORT", "DB_USERNAME", "DB_PASSWORD", "DB_NAME",
-			"SERVER_PORT", "RATE_LIMIT_ENABLED", "RATE_LIMIT_REQUESTS_PER_MINUTE")
+		unsetEnvVars("DB_HOST", "DB_PORT", "DB_USERNAME", "DB_PASSWORD", "DB_NAME", "DB_READ_REPLICAS",
+			"SERVER_PORT", "REQUEST_TIMEOUT", "TENANTS", "DEFAULT_TENANT", "RATE_LIMIT_ENABLED", "RATE_LIMIT_REQUESTS_PER_MINUTE")
 	})
 
 	cfg := Load()
@@ -58,11 +119,27 @@ func TestLoad_FromEnv(t *testing.T) {
 	assert.Equal(t, "admin", cfg.Database.Username)
 	assert.Equal(t, "secret", cfg.Database.Password)
 	assert.Equal(t, "pico_db", cfg.Database.DBName)
+	assert.Equal(t, []string{"replica-1", "replica-2:3307"}, cfg.Database.Replicas)
 	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, 5*time.Second, cfg.Server.RequestTimeout)
+	assert.Equal(t, []string{"gorontalo:75:Gorontalo", "sulbar:76:Sulawesi Barat"}, cfg.Tenants.Definitions)
+	assert.Equal(t, "gorontalo", cfg.Tenants.Default)
 	assert.False(t, cfg.RateLimit.Enabled)
 	assert.Equal(t, 200, cfg.RateLimit.RequestsPerMinute)
 }
 
+func TestLoad_PostgresDriver(t *testing.T) {
+	unsetEnvVars("DB_PORT", "DB_SSL_MODE")
+	require.NoError(t, os.Setenv("DB_DRIVER", "postgres"))
+	t.Cleanup(func() { unsetEnvVars("DB_DRIVER") })
+
+	cfg := Load()
+
+	assert.Equal(t, "postgres", cfg.Database.Driver)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, "require", cfg.Database.SSLMode)
+}
+
 func TestGetEnv_Default(t *testing.T) {
 	unsetEnvVars("TEST_KEY_FORGE")
 	assert.Equal(t, "default_val", getEnv("TEST_KEY_FORGE", "default_val"))
@@ -124,3 +201,22 @@ func TestGetEnvAsBool_Invalid(t *testing.T) {
 	t.Cleanup(func() { unsetEnvVars("TEST_BOOL_FORGE") })
 	assert.True(t, getEnvAsBool("TEST_BOOL_FORGE", true))
 }
+
+func TestGetEnvAsList(t *testing.T) {
+	t.Setenv("TEST_LIST_FORGE", " /a/, ,/b/ ")
+	assert.Equal(t, []string{"/a/", "/b/"}, getEnvAsList("TEST_LIST_FORGE", nil))
+
+	unsetEnvVars("TEST_LIST_FORGE")
+	assert.Equal(t, []string{"/c/"}, getEnvAsList("TEST_LIST_FORGE", []string{"/c/"}))
+}
+
+func TestGetEnvAsTime(t *testing.T) {
+	t.Setenv("TEST_TIME_FORGE", "2021-07-15T08:00:00+08:00")
+	assert.Equal(t, time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), getEnvAsTime("TEST_TIME_FORGE", time.Time{}).UTC())
+
+	t.Setenv("TEST_TIME_FORGE", "2021-07-15")
+	assert.Equal(t, time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), getEnvAsTime("TEST_TIME_FORGE", time.Time{}))
+
+	t.Setenv("TEST_TIME_FORGE", "15/07/2021")
+	assert.True(t, getEnvAsTime("TEST_TIME_FORGE", time.Time{}).IsZero())
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// Reloader rereads the configuration on demand and passes the sections that
+// can change while serving to the hooks registered for them
+type Reloader struct {
+	load func() (*Config, error)
+
+	mu      sync.Mutex
+	current *Config
+	hooks   map[string]func(*Config)
+}
+
+// NewReloader creates a Reloader starting from current that rereads the
+// configuration with load, usually Reload
+func NewReloader(current *Config, load func() (*Config, error)) *Reloader {
+	return &Reloader{load: load, current: current, hooks: make(map[string]func(*Config))}
+}
+
+// OnChange registers apply to be called with the new configuration when the
+// section, a field name of Config such as RateLimit, changes
+func (r *Reloader) OnChange(section string, apply func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks[section] = apply
+}
+
+// Reload rereads the configuration and applies the changed sections that
+// have a hook. The other changed sections are reported as requiring a
+// restart and keep their running values.
+func (r *Reloader) Reload() (*models.ConfigReload, error) {
+	next, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := &models.ConfigReload{Changed: []string{}, Applied: []string{}, RestartRequired: []string{}}
+	current, updated := reflect.ValueOf(r.current).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < current.NumField(); i++ {
+		if reflect.DeepEqual(current.Field(i).Interface(), updated.Field(i).Interface()) {
+			continue
+		}
+		section := current.Type().Field(i).Name
+		result.Changed = append(result.Changed, section)
+		apply, ok := r.hooks[section]
+		if !ok {
+			result.RestartRequired = append(result.RestartRequired, section)
+			continue
+		}
+		apply(next)
+		current.Field(i).Set(updated.Field(i))
+		result.Applied = append(result.Applied, section)
+	}
+	return result, nil
+}
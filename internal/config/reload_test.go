@@ -0,0 +1,49 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	current := &Config{
+		RateLimit:  RateLimitConfig{Enabled: true, RequestsPerMinute: 100},
+		Server:     ServerConfig{Port: 8080},
+		Validation: ValidationConfig{MaxDateRangeDays: 365},
+	}
+	next := &Config{
+		RateLimit:  RateLimitConfig{Enabled: true, RequestsPerMinute: 50},
+		Server:     ServerConfig{Port: 9090},
+		Validation: ValidationConfig{MaxDateRangeDays: 365},
+	}
+	reloader := NewReloader(current, func() (*Config, error) { return next, nil })
+	var applied *Config
+	reloader.OnChange("RateLimit", func(cfg *Config) { applied = cfg })
+
+	result, err := reloader.Reload()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Server", "RateLimit"}, result.Changed)
+	assert.Equal(t, []string{"RateLimit"}, result.Applied)
+	assert.Equal(t, []string{"Server"}, result.RestartRequired)
+	assert.Same(t, next, applied)
+	// Only the applied section takes the new values
+	assert.Equal(t, 50, current.RateLimit.RequestsPerMinute)
+	assert.Equal(t, 8080, current.Server.Port)
+
+	result, err = reloader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Server"}, result.Changed)
+	assert.Empty(t, result.Applied)
+}
+
+func TestReloader_Reload_Error(t *testing.T) {
+	reloader := NewReloader(&Config{}, func() (*Config, error) { return nil, errors.New("bad .env") })
+
+	_, err := reloader.Reload()
+
+	assert.EqualError(t, err, "bad .env")
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// postgresSSLModes are the sslmode values libpq accepts
+var postgresSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate checks the settings that would otherwise only fail once a request
+// or background job needs them. Each problem is a check named after its
+// environment variable: fatal when the API cannot work with the value,
+// a warning when it runs with a feature off or degraded.
+func (c *Config) Validate() []models.DiagnosticCheck {
+	var checks []models.DiagnosticCheck
+	fatal := func(name, format string, args ...interface{}) {
+		checks = append(checks, models.DiagnosticCheck{Name: name, Status: models.DiagnosticFatal, Message: fmt.Sprintf(format, args...)})
+	}
+	warn := func(name, format string, args ...interface{}) {
+		checks = append(checks, models.DiagnosticCheck{Name: name, Status: models.DiagnosticWarning, Message: fmt.Sprintf(format, args...)})
+	}
+
+	db := c.Database
+	postgres := false
+	switch strings.ToLower(strings.TrimSpace(db.Driver)) {
+	case "", "mysql":
+	case "postgres", "postgresql", "pgx":
+		postgres = true
+	default:
+		fatal("DB_DRIVER", "unsupported database driver %q, use mysql or postgres", db.Driver)
+	}
+	if db.Host == "" {
+		fatal("DB_HOST", "the database host is required")
+	}
+	if !validPort(db.Port) {
+		fatal("DB_PORT", "%d is not a port", db.Port)
+	}
+	if db.DBName == "" {
+		fatal("DB_NAME", "the database name is required")
+	}
+	if db.Username == "" {
+		warn("DB_USERNAME", "no database user set, connecting without one")
+	}
+	if postgres && !contains(postgresSSLModes, db.SSLMode) {
+		fatal("DB_SSL_MODE", "unknown sslmode %q, use one of %s", db.SSLMode, strings.Join(postgresSSLModes, ", "))
+	}
+	if db.MaxOpenConns <= 0 {
+		fatal("MYSQL_MAX_OPEN_CONNS", "must be positive, got %d", db.MaxOpenConns)
+	} else if db.MaxIdleConns > db.MaxOpenConns {
+		warn("MYSQL_MAX_IDLE_CONNS", "%d idle connections exceed the %d open ones, only %d are kept", db.MaxIdleConns, db.MaxOpenConns, db.MaxOpenConns)
+	}
+
+	if !validPort(c.Server.Port) {
+		fatal("SERVER_PORT", "%d is not a port", c.Server.Port)
+	}
+	if c.Server.RequestTimeout < 0 {
+		fatal("REQUEST_TIMEOUT", "must not be negative, got %s", c.Server.RequestTimeout)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		fatal("SHUTDOWN_TIMEOUT", "must be positive, got %s", c.Server.ShutdownTimeout)
+	}
+
+	if c.GRPC.Enabled {
+		if !validPort(c.GRPC.Port) {
+			fatal("GRPC_PORT", "%d is not a port", c.GRPC.Port)
+		} else if c.GRPC.Port == c.Server.Port {
+			fatal("GRPC_PORT", "%d is already the HTTP server's SERVER_PORT", c.GRPC.Port)
+		}
+	}
+
+	if c.RateLimit.Enabled {
+		if c.RateLimit.RequestsPerMinute <= 0 {
+			fatal("RATE_LIMIT_REQUESTS_PER_MINUTE", "must be positive while rate limiting is enabled, got %d", c.RateLimit.RequestsPerMinute)
+		}
+		if c.RateLimit.CachedRequestCost < 0 || c.RateLimit.CachedRequestCost > 1 {
+			fatal("RATE_LIMIT_CACHED_REQUEST_COST", "must be between 0 and 1, got %g", c.RateLimit.CachedRequestCost)
+		}
+	}
+
+	if c.Cache.Enabled {
+		for _, ttl := range []struct {
+			name  string
+			value time.Duration
+		}{
+			{"CACHE_TTL_DEFAULT", c.Cache.DefaultTTL},
+			{"CACHE_TTL_LATEST", c.Cache.LatestTTL},
+			{"CACHE_TTL_HISTORICAL", c.Cache.HistoricalTTL},
+		} {
+			if ttl.value <= 0 {
+				fatal(ttl.name, "must be positive while caching is enabled, got %s", ttl.value)
+			}
+		}
+	}
+
+	if u, err := url.Parse(c.Subscriptions.PublicBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		fatal("PUBLIC_BASE_URL", "%q is not an absolute URL; links in emails and share links are built from it", c.Subscriptions.PublicBaseURL)
+	}
+	if c.Mail.SMTPHost != "" {
+		if !validPort(c.Mail.SMTPPort) {
+			fatal("SMTP_PORT", "%d is not a port", c.Mail.SMTPPort)
+		}
+		if c.Mail.From == "" {
+			fatal("MAIL_FROM", "required when SMTP_HOST is set")
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(c.Storage.Driver)) {
+	case "", "local":
+		if c.Export.Dir == "" {
+			fatal("EXPORT_DIR", "the export directory is required")
+		}
+	case "s3", "gcs":
+		if c.Storage.Bucket == "" {
+			fatal("STORAGE_BUCKET", "required by the %s storage driver", c.Storage.Driver)
+		}
+		if c.Storage.AccessKeyID == "" || c.Storage.SecretAccessKey == "" {
+			fatal("STORAGE_ACCESS_KEY_ID", "the %s storage driver needs STORAGE_ACCESS_KEY_ID and STORAGE_SECRET_ACCESS_KEY", c.Storage.Driver)
+		}
+		if c.Storage.Endpoint != "" {
+			if u, err := url.Parse(c.Storage.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				fatal("STORAGE_ENDPOINT", "%q is not an absolute URL", c.Storage.Endpoint)
+			}
+		}
+	default:
+		fatal("STORAGE_DRIVER", "unsupported storage driver %q, use local, s3 or gcs", c.Storage.Driver)
+	}
+	if c.Export.QueueSize <= 0 {
+		fatal("EXPORT_QUEUE_SIZE", "must be positive, got %d", c.Export.QueueSize)
+	}
+	if c.Export.Retention <= 0 {
+		fatal("EXPORT_RETENTION", "must be positive, got %s", c.Export.Retention)
+	}
+
+	if c.Rt.WindowDays <= 0 {
+		fatal("RT_WINDOW_DAYS", "must be positive, got %d", c.Rt.WindowDays)
+	}
+	if c.Rt.SerialIntervalMean <= 0 || c.Rt.SerialIntervalSD <= 0 {
+		fatal("RT_SERIAL_INTERVAL_MEAN", "the serial interval mean and RT_SERIAL_INTERVAL_SD must be positive, got %g and %g", c.Rt.SerialIntervalMean, c.Rt.SerialIntervalSD)
+	}
+	if c.Status.RtCaution > c.Status.RtCritical {
+		warn("STATUS_RT_CAUTION", "%g is above STATUS_RT_CRITICAL %g, no province is classed as caution", c.Status.RtCaution, c.Status.RtCritical)
+	}
+	if c.Status.IncidenceCaution > c.Status.IncidenceCritical {
+		warn("STATUS_INCIDENCE_CAUTION", "%g is above STATUS_INCIDENCE_CRITICAL %g, no province is classed as caution", c.Status.IncidenceCaution, c.Status.IncidenceCritical)
+	}
+
+	if c.Admin.Enabled && os.Getenv("ADMIN_KEY") == "" {
+		warn("ADMIN_KEY", "the admin API is enabled without ADMIN_KEY; only admin scoped API keys can use it")
+	}
+	return checks
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func validationStatuses(checks []models.DiagnosticCheck) map[string]string {
+	statuses := make(map[string]string, len(checks))
+	for _, c := range checks {
+		statuses[c.Name] = c.Status
+	}
+	return statuses
+}
+
+func TestValidate_Defaults(t *testing.T) {
+	unsetEnvVars("DB_DRIVER", "DB_HOST", "DB_PORT", "DB_USERNAME", "DB_NAME", "SERVER_PORT",
+		"RATE_LIMIT_ENABLED", "RATE_LIMIT_REQUESTS_PER_MINUTE", "CACHE_ENABLED", "EXPORT_DIR", "ADMIN_API_ENABLED", "STORAGE_DRIVER")
+	t.Setenv("DB_USERNAME", "pico")
+	t.Setenv("DB_NAME", "pico_db")
+
+	assert.Empty(t, fromEnv().Validate())
+}
+
+func TestValidate_Problems(t *testing.T) {
+	cfg := fromEnv()
+	cfg.Database.Driver = "sqlite"
+	cfg.Database.DBName = ""
+	cfg.Database.Username = ""
+	cfg.Database.MaxIdleConns = cfg.Database.MaxOpenConns + 1
+	cfg.Server.Port = 70000
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.RequestsPerMinute = 0
+	cfg.Cache.Enabled = true
+	cfg.Cache.LatestTTL = 0
+	cfg.Subscriptions.PublicBaseURL = "localhost:8080"
+	cfg.Mail.SMTPHost = "smtp.example.com"
+	cfg.Mail.From = ""
+	cfg.Export.Retention = -time.Hour
+	cfg.Status.RtCaution, cfg.Status.RtCritical = 1.5, 1.2
+
+	assert.Equal(t, map[string]string{
+		"DB_DRIVER":                      models.DiagnosticFatal,
+		"DB_NAME":                        models.DiagnosticFatal,
+		"DB_USERNAME":                    models.DiagnosticWarning,
+		"MYSQL_MAX_IDLE_CONNS":           models.DiagnosticWarning,
+		"SERVER_PORT":                    models.DiagnosticFatal,
+		"RATE_LIMIT_REQUESTS_PER_MINUTE": models.DiagnosticFatal,
+		"CACHE_TTL_LATEST":               models.DiagnosticFatal,
+		"PUBLIC_BASE_URL":                models.DiagnosticFatal,
+		"MAIL_FROM":                      models.DiagnosticFatal,
+		"EXPORT_RETENTION":               models.DiagnosticFatal,
+		"STATUS_RT_CAUTION":              models.DiagnosticWarning,
+	}, validationStatuses(cfg.Validate()))
+}
+
+func TestValidate_PostgresSSLMode(t *testing.T) {
+	cfg := fromEnv()
+	cfg.Database.DBName = "pico_db"
+	cfg.Database.Username = "pico"
+	cfg.Database.Driver = "postgres"
+	cfg.Database.SSLMode = "verify-full"
+	assert.Empty(t, cfg.Validate())
+
+	cfg.Database.SSLMode = "on"
+	assert.Equal(t, map[string]string{"DB_SSL_MODE": models.DiagnosticFatal}, validationStatuses(cfg.Validate()))
+}
+
+func TestValidate_Storage(t *testing.T) {
+	cfg := fromEnv()
+	cfg.Database.DBName = "pico_db"
+	cfg.Database.Username = "pico"
+	cfg.Storage = StorageConfig{Driver: "gcs", Bucket: "pico", AccessKeyID: "GOOG1", SecretAccessKey: "secret"}
+	// Files go to the bucket, so no export directory is needed
+	cfg.Export.Dir = ""
+	assert.Empty(t, cfg.Validate())
+
+	cfg.Storage = StorageConfig{Driver: "s3", Endpoint: "minio:9000"}
+	assert.Equal(t, map[string]string{
+		"STORAGE_BUCKET":        models.DiagnosticFatal,
+		"STORAGE_ACCESS_KEY_ID": models.DiagnosticFatal,
+		"STORAGE_ENDPOINT":      models.DiagnosticFatal,
+	}, validationStatuses(cfg.Validate()))
+
+	cfg.Storage = StorageConfig{Driver: "ftp"}
+	assert.Equal(t, map[string]string{"STORAGE_DRIVER": models.DiagnosticFatal}, validationStatuses(cfg.Validate()))
+}
+
+func TestValidate_GRPC(t *testing.T) {
+	cfg := fromEnv()
+	cfg.Database.DBName = "pico_db"
+	cfg.Database.Username = "pico"
+	cfg.GRPC = GRPCConfig{Enabled: true, Port: 9090}
+	assert.Empty(t, cfg.Validate())
+
+	cfg.GRPC.Port = cfg.Server.Port
+	assert.Equal(t, map[string]string{"GRPC_PORT": models.DiagnosticFatal}, validationStatuses(cfg.Validate()))
+}
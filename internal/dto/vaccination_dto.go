@@ -27,11 +27,20 @@ type GroupData struct {
 	Coverage   CoverageData `json:"coverage"`
 }
 
+// BoosterData holds booster dose counts and coverage. Boosters are only
+// recorded in total, not per group.
+type BoosterData struct {
+	Daily      int64   `json:"daily"`
+	Cumulative int64   `json:"cumulative"`
+	Coverage   float64 `json:"coverage"`
+}
+
 // VaccinationTotals holds total daily and cumulative dose data.
 type VaccinationTotals struct {
 	Daily      DoseData     `json:"daily"`
 	Cumulative DoseData     `json:"cumulative"`
 	Coverage   CoverageData `json:"coverage"`
+	Booster    BoosterData  `json:"booster"`
 }
 
 // VaccinationResponse is the API response for national vaccination data.
@@ -87,6 +96,11 @@ func TransformNationalVaccine(v models.NationalVaccine) VaccinationResponse {
 				Dose1: calcCoverage(totalCum.Dose1, v.TotalVaccinationTarget),
 				Dose2: calcCoverage(totalCum.Dose2, v.TotalVaccinationTarget),
 			},
+			Booster: BoosterData{
+				Daily:      v.BoosterVaccinationReceived,
+				Cumulative: v.CumulativeBoosterVaccinationReceived,
+				Coverage:   calcCoverage(v.CumulativeBoosterVaccinationReceived, v.TotalVaccinationTarget),
+			},
 		},
 		Groups: map[string]GroupData{
 			"health_worker": buildGroup(v.HealthWorkerVaccinationTarget,
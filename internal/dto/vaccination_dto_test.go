@@ -15,6 +15,8 @@ func sampleVaccine() models.NationalVaccine {
 		SecondVaccinationReceived:                       1535,
 		CumulativeFirstVaccinationReceived:              81885,
 		CumulativeSecondVaccinationReceived:             61974,
+		BoosterVaccinationReceived:                      120,
+		CumulativeBoosterVaccinationReceived:            22405,
 		HealthWorkerVaccinationTarget:                   24698,
 		HealthWorkerFirstVaccinationReceived:            1,
 		HealthWorkerSecondVaccinationReceived:           36,
@@ -47,6 +49,13 @@ func TestTransformNationalVaccine(t *testing.T) {
 		t.Errorf("Total.Coverage.Dose2 = %f, want 2.77", r.Total.Coverage.Dose2)
 	}
 
+	if r.Total.Booster.Daily != 120 {
+		t.Errorf("Total.Booster.Daily = %d, want 120", r.Total.Booster.Daily)
+	}
+	if r.Total.Booster.Coverage != 1.0 {
+		t.Errorf("Total.Booster.Coverage = %f, want 1.0", r.Total.Booster.Coverage)
+	}
+
 	// All 5 groups
 	for _, g := range []string{"health_worker", "elderly", "public_officer", "public", "teenager"} {
 		if _, ok := r.Groups[g]; !ok {
@@ -0,0 +1,99 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyMetadata is the metadata key carrying the client's API key, the
+// X-API-Key header of HTTP
+var apiKeyMetadata = strings.ToLower(middleware.APIKeyHeader)
+
+// Access is what lets a call in: with Auth enabled, an x-api-key with the
+// read scope checked against APIKeys, and the RateLimits the HTTP API counts
+// against. A nil APIKeys or RateLimits skips that check.
+type Access struct {
+	Auth       config.AuthConfig
+	APIKeys    middleware.APIKeyAuthenticator
+	RateLimits *middleware.RateLimits
+}
+
+// authorize authenticates and counts a call, returning its context with the
+// API key that authenticated it
+func (a Access) authorize(ctx context.Context) (context.Context, error) {
+	if a.Auth.Enabled && a.APIKeys != nil {
+		values := metadata.ValueFromIncomingContext(ctx, apiKeyMetadata)
+		if len(values) == 0 || values[0] == "" {
+			return ctx, status.Error(codes.Unauthenticated, "missing API key")
+		}
+		key, ok := a.APIKeys.Authenticate(values[0])
+		if !ok {
+			return ctx, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		// Every call reads case data
+		if !key.HasScope(models.ScopeRead) {
+			return ctx, status.Errorf(codes.PermissionDenied, "API key lacks the %s scope", models.ScopeRead)
+		}
+		ctx = middleware.WithAPIKey(ctx, key)
+	}
+
+	if a.RateLimits != nil {
+		if allowed, resetTime := a.RateLimits.Allow(ctx, peerIP(ctx)); !allowed {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", fmt.Sprintf("%d", int(math.Ceil(resetTime.Seconds())))))
+			return ctx, status.Error(codes.ResourceExhausted, "rate limit exceeded, too many requests")
+		}
+	}
+	return ctx, nil
+}
+
+func (a Access) unary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a Access) stream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream is a ServerStream with the context of its authorized call
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// peerIP returns the address the call came from, which the gRPC server sees
+// directly as it is not meant to sit behind HTTP proxies
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
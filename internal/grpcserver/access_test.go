@@ -0,0 +1,112 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	covidv1 "github.com/banua-coder/pico-api-go/proto/covid/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type stubAPIKeys map[string]models.APIKey
+
+func (s stubAPIKeys) Authenticate(key string) (*models.APIKey, bool) {
+	k, ok := s[key]
+	if !ok {
+		return nil, false
+	}
+	return &k, true
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestAccess_APIKeys(t *testing.T) {
+	mockService := new(mockCovidService)
+	mockService.On("GetProvinces").Return([]models.Province{{ID: "72", Name: "Sulawesi Tengah"}}, nil)
+	mockService.On("EachNationalCase", mock.Anything).Return([][]models.NationalCase{{{Day: 1}}}, nil)
+	client := dialWithAccess(t, mockService, Access{
+		Auth: config.AuthConfig{Enabled: true},
+		APIKeys: stubAPIKeys{
+			"reader": {KeyHash: "reader", Scopes: []string{models.ScopeRead}},
+			"nobody": {KeyHash: "nobody"},
+		},
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"missing key", context.Background(), codes.Unauthenticated},
+		{"invalid key", withAPIKey("bogus"), codes.Unauthenticated},
+		{"key without the read scope", withAPIKey("nobody"), codes.PermissionDenied},
+		{"key with the read scope", withAPIKey("reader"), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ListProvinces(tt.ctx, &covidv1.ListProvincesRequest{})
+			assert.Equal(t, tt.want, status.Code(err))
+
+			stream, err := client.StreamNationalCases(tt.ctx, &covidv1.StreamNationalCasesRequest{})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
+
+func TestAccess_RateLimit(t *testing.T) {
+	mockService := new(mockCovidService)
+	mockService.On("GetProvinces").Return([]models.Province{}, nil)
+	rateLimits := middleware.NewRateLimits(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 2, WindowSize: time.Minute})
+	t.Cleanup(func() { rateLimits.Update(config.RateLimitConfig{}) })
+	client := dialWithAccess(t, mockService, Access{RateLimits: rateLimits})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.ListProvinces(context.Background(), &covidv1.ListProvincesRequest{})
+		require.NoError(t, err)
+	}
+	var header metadata.MD
+	_, err := client.ListProvinces(context.Background(), &covidv1.ListProvincesRequest{}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.NotEmpty(t, header.Get("retry-after"))
+	mockService.AssertNumberOfCalls(t, "GetProvinces", 2)
+}
+
+func TestAccess_RateLimitPerKey(t *testing.T) {
+	mockService := new(mockCovidService)
+	mockService.On("GetProvinces").Return([]models.Province{}, nil)
+	rateLimits := middleware.NewRateLimits(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1, WindowSize: time.Minute})
+	t.Cleanup(func() { rateLimits.Update(config.RateLimitConfig{}) })
+	client := dialWithAccess(t, mockService, Access{
+		Auth: config.AuthConfig{Enabled: true},
+		APIKeys: stubAPIKeys{
+			"a": {KeyHash: "a", Scopes: []string{models.ScopeRead}},
+			"b": {KeyHash: "b", Scopes: []string{models.ScopeRead}, RequestsPerMinute: 5},
+		},
+		RateLimits: rateLimits,
+	})
+
+	_, err := client.ListProvinces(withAPIKey("a"), &covidv1.ListProvincesRequest{})
+	require.NoError(t, err)
+	_, err = client.ListProvinces(withAPIKey("a"), &covidv1.ListProvincesRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Keys are counted apart, each against its own limit
+	for i := 0; i < 3; i++ {
+		_, err = client.ListProvinces(withAPIKey("b"), &covidv1.ListProvincesRequest{})
+		require.NoError(t, err)
+	}
+}
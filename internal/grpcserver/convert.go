@@ -0,0 +1,72 @@
+package grpcserver
+
+import (
+	"github.com/banua-coder/pico-api-go/internal/models"
+	covidv1 "github.com/banua-coder/pico-api-go/proto/covid/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func nationalCaseToProto(c *models.NationalCase) *covidv1.NationalCase {
+	return &covidv1.NationalCase{
+		Id:                  c.ID,
+		Day:                 c.Day,
+		Date:                timestamppb.New(c.Date),
+		Positive:            c.Positive,
+		Recovered:           c.Recovered,
+		Deceased:            c.Deceased,
+		CumulativePositive:  c.CumulativePositive,
+		CumulativeRecovered: c.CumulativeRecovered,
+		CumulativeDeceased:  c.CumulativeDeceased,
+		Rt:                  c.Rt,
+		RtUpper:             c.RtUpper,
+		RtLower:             c.RtLower,
+		Tests:               testsToProto(c.Tests),
+	}
+}
+
+func provinceCaseToProto(c *models.ProvinceCaseWithDate) *covidv1.ProvinceCase {
+	pc := &covidv1.ProvinceCase{
+		Id:                                       c.ID,
+		Day:                                      c.Day,
+		Date:                                     timestamppb.New(c.Date),
+		Province:                                 &covidv1.Province{Id: c.ProvinceID},
+		Positive:                                 c.Positive,
+		Recovered:                                c.Recovered,
+		Deceased:                                 c.Deceased,
+		PersonUnderObservation:                   c.PersonUnderObservation,
+		FinishedPersonUnderObservation:           c.FinishedPersonUnderObservation,
+		PersonUnderSupervision:                   c.PersonUnderSupervision,
+		FinishedPersonUnderSupervision:           c.FinishedPersonUnderSupervision,
+		CumulativePositive:                       c.CumulativePositive,
+		CumulativeRecovered:                      c.CumulativeRecovered,
+		CumulativeDeceased:                       c.CumulativeDeceased,
+		CumulativePersonUnderObservation:         c.CumulativePersonUnderObservation,
+		CumulativeFinishedPersonUnderObservation: c.CumulativeFinishedPersonUnderObservation,
+		CumulativePersonUnderSupervision:         c.CumulativePersonUnderSupervision,
+		CumulativeFinishedPersonUnderSupervision: c.CumulativeFinishedPersonUnderSupervision,
+		Rt:                                       c.Rt,
+		RtUpper:                                  c.RtUpper,
+		RtLower:                                  c.RtLower,
+		Tests:                                    testsToProto(c.Tests),
+	}
+	if c.Province != nil {
+		pc.Province = provinceToProto(c.Province)
+	}
+	return pc
+}
+
+func provinceToProto(p *models.Province) *covidv1.Province {
+	return &covidv1.Province{Id: p.ID, Name: p.Name}
+}
+
+func testsToProto(t *models.DailyTests) *covidv1.DailyTests {
+	if t == nil {
+		return nil
+	}
+	return &covidv1.DailyTests{
+		Pcr:               t.PCR,
+		Antigen:           t.Antigen,
+		CumulativePcr:     t.CumulativePCR,
+		CumulativeAntigen: t.CumulativeAntigen,
+	}
+}
@@ -0,0 +1,232 @@
+// Package grpcserver serves the case data of the CovidService over gRPC as
+// the covid.v1.CovidService of proto/covid/v1/covid.proto, for internal
+// services that would rather not go through HTTP and JSON.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	covidv1 "github.com/banua-coder/pico-api-go/proto/covid/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer returns a gRPC server with covidService registered, letting calls
+// in as access allows. Panics in a call fail it with INTERNAL, as the HTTP
+// Recovery middleware does.
+func NewServer(covidService service.CovidService, access Access, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(recoverUnary, access.unary),
+		grpc.ChainStreamInterceptor(recoverStream, access.stream),
+	)
+	s := grpc.NewServer(opts...)
+	covidv1.RegisterCovidServiceServer(s, NewCovidServer(covidService))
+	return s
+}
+
+// CovidServer implements covid.v1.CovidService with the same CovidService
+// the HTTP handlers use, so both see the same cache and data
+type CovidServer struct {
+	covidv1.UnimplementedCovidServiceServer
+	covidService service.CovidService
+}
+
+func NewCovidServer(covidService service.CovidService) *CovidServer {
+	return &CovidServer{covidService: covidService}
+}
+
+func (s *CovidServer) ListNationalCases(ctx context.Context, req *covidv1.ListNationalCasesRequest) (*covidv1.ListNationalCasesResponse, error) {
+	opts, err := s.queryOptions(ctx, req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	opts.Page = page(req.GetLimit(), req.GetOffset())
+
+	cases, total, err := s.covidService.ListNationalCases(ctx, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &covidv1.ListNationalCasesResponse{Cases: make([]*covidv1.NationalCase, len(cases)), Total: int32(total)}
+	for i := range cases {
+		resp.Cases[i] = nationalCaseToProto(&cases[i])
+	}
+	return resp, nil
+}
+
+func (s *CovidServer) StreamNationalCases(req *covidv1.StreamNationalCasesRequest, stream grpc.ServerStreamingServer[covidv1.NationalCase]) error {
+	ctx := stream.Context()
+	opts, err := s.queryOptions(ctx, req.GetQuery())
+	if err != nil {
+		return err
+	}
+
+	err = s.covidService.EachNationalCase(ctx, opts, func(cases []models.NationalCase) error {
+		for i := range cases {
+			if err := stream.Send(nationalCaseToProto(&cases[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return streamError(err)
+}
+
+func (s *CovidServer) GetLatestNationalCase(ctx context.Context, _ *covidv1.GetLatestNationalCaseRequest) (*covidv1.NationalCase, error) {
+	nationalCase, err := s.covidService.GetLatestNationalCase(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if nationalCase == nil {
+		return nil, status.Error(codes.NotFound, "no national case")
+	}
+	return nationalCaseToProto(nationalCase), nil
+}
+
+func (s *CovidServer) GetNationalCaseByDay(ctx context.Context, req *covidv1.GetNationalCaseByDayRequest) (*covidv1.NationalCase, error) {
+	nationalCase, err := s.covidService.GetNationalCaseByDay(ctx, req.GetDay())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if nationalCase == nil {
+		return nil, status.Errorf(codes.NotFound, "no national case for day %d", req.GetDay())
+	}
+	return nationalCaseToProto(nationalCase), nil
+}
+
+func (s *CovidServer) ListProvinces(ctx context.Context, _ *covidv1.ListProvincesRequest) (*covidv1.ListProvincesResponse, error) {
+	provinces, err := s.covidService.GetProvinces(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &covidv1.ListProvincesResponse{Provinces: make([]*covidv1.Province, len(provinces))}
+	for i := range provinces {
+		resp.Provinces[i] = provinceToProto(&provinces[i])
+	}
+	return resp, nil
+}
+
+func (s *CovidServer) GetProvince(ctx context.Context, req *covidv1.GetProvinceRequest) (*covidv1.Province, error) {
+	province, err := s.covidService.GetProvinceByID(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if province == nil {
+		return nil, status.Errorf(codes.NotFound, "no province %q", req.GetId())
+	}
+	return provinceToProto(province), nil
+}
+
+func (s *CovidServer) ListProvinceCases(ctx context.Context, req *covidv1.ListProvinceCasesRequest) (*covidv1.ListProvinceCasesResponse, error) {
+	opts, err := s.queryOptions(ctx, req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	opts.ProvinceID = req.GetProvinceId()
+	opts.Page = page(req.GetLimit(), req.GetOffset())
+
+	cases, total, err := s.covidService.ListProvinceCases(ctx, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &covidv1.ListProvinceCasesResponse{Cases: make([]*covidv1.ProvinceCase, len(cases)), Total: int32(total)}
+	for i := range cases {
+		resp.Cases[i] = provinceCaseToProto(&cases[i])
+	}
+	return resp, nil
+}
+
+func (s *CovidServer) StreamProvinceCases(req *covidv1.StreamProvinceCasesRequest, stream grpc.ServerStreamingServer[covidv1.ProvinceCase]) error {
+	ctx := stream.Context()
+	opts, err := s.queryOptions(ctx, req.GetQuery())
+	if err != nil {
+		return err
+	}
+	opts.ProvinceID = req.GetProvinceId()
+
+	err = s.covidService.EachProvinceCase(ctx, opts, func(cases []models.ProvinceCaseWithDate) error {
+		for i := range cases {
+			if err := stream.Send(provinceCaseToProto(&cases[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return streamError(err)
+}
+
+// queryOptions turns a CaseQuery into the options of the case lists, failing
+// with INVALID_ARGUMENT where the HTTP handlers write a 400
+func (s *CovidServer) queryOptions(ctx context.Context, q *covidv1.CaseQuery) (service.QueryOptions, error) {
+	startDate, endDate := q.GetStartDate(), q.GetEndDate()
+	if preset := q.GetRange(); preset != "" {
+		if startDate != "" || endDate != "" {
+			return service.QueryOptions{}, status.Error(codes.InvalidArgument, "range cannot be combined with start_date or end_date")
+		}
+		var err error
+		startDate, endDate, err = s.covidService.ResolveDateRange(ctx, preset)
+		if errors.Is(err, service.ErrInvalidDateRangePreset) {
+			return service.QueryOptions{}, status.Errorf(codes.InvalidArgument, "invalid range %q, use one of %s", preset, strings.Join(service.DateRangePresets, ", "))
+		}
+		if err != nil {
+			return service.QueryOptions{}, status.Error(codes.Internal, err.Error())
+		}
+	}
+	dates, err := service.ParseDateRange(startDate, endDate)
+	if err != nil {
+		return service.QueryOptions{}, status.Error(codes.InvalidArgument, "invalid date format, use YYYY-MM-DD")
+	}
+	sort, err := utils.ParseSort(q.GetSort(), utils.IsValidSortField)
+	if err != nil {
+		return service.QueryOptions{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return service.QueryOptions{Range: dates, Sort: sort}, nil
+}
+
+// page applies the limits of the HTTP case lists to a requested page
+func page(limit, offset int32) service.Page {
+	l, o := utils.ValidatePaginationParams(int(limit), int(offset))
+	return service.Page{Limit: l, Offset: o}
+}
+
+// streamError keeps the status of a failed Send and of a cancelled or timed
+// out call, and reports other errors as INTERNAL
+func streamError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic recovered in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic recovered in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
@@ -0,0 +1,368 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	covidv1 "github.com/banua-coder/pico-api-go/proto/covid/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// mockCovidService mocks the methods the server calls; the embedded interface
+// is nil, so any other call panics
+type mockCovidService struct {
+	mock.Mock
+	service.CovidService
+}
+
+func (m *mockCovidService) ListNationalCases(ctx context.Context, opts service.QueryOptions) ([]models.NationalCase, int, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+}
+
+func (m *mockCovidService) EachNationalCase(ctx context.Context, opts service.QueryOptions, fn func([]models.NationalCase) error) error {
+	args := m.Called(opts)
+	for _, chunk := range args.Get(0).([][]models.NationalCase) {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *mockCovidService) GetLatestNationalCase(ctx context.Context) (*models.NationalCase, error) {
+	args := m.Called()
+	return args.Get(0).(*models.NationalCase), args.Error(1)
+}
+
+func (m *mockCovidService) GetNationalCaseByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
+	args := m.Called(day)
+	return args.Get(0).(*models.NationalCase), args.Error(1)
+}
+
+func (m *mockCovidService) GetProvinces(ctx context.Context) ([]models.Province, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Province), args.Error(1)
+}
+
+func (m *mockCovidService) GetProvinceByID(ctx context.Context, id string) (*models.Province, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Province), args.Error(1)
+}
+
+func (m *mockCovidService) ListProvinceCases(ctx context.Context, opts service.QueryOptions) ([]models.ProvinceCaseWithDate, int, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+}
+
+func (m *mockCovidService) EachProvinceCase(ctx context.Context, opts service.QueryOptions, fn func([]models.ProvinceCaseWithDate) error) error {
+	args := m.Called(opts)
+	for _, chunk := range args.Get(0).([][]models.ProvinceCaseWithDate) {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *mockCovidService) ResolveDateRange(ctx context.Context, preset string) (string, string, error) {
+	args := m.Called(preset)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+// dial serves covidService on an in-memory listener and returns a client of it
+func dial(t *testing.T, covidService service.CovidService) covidv1.CovidServiceClient {
+	t.Helper()
+	return dialWithAccess(t, covidService, Access{})
+}
+
+// dialWithAccess is dial for a server letting calls in as access allows
+func dialWithAccess(t *testing.T, covidService service.CovidService, access Access) covidv1.CovidServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(covidService, access)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return covidv1.NewCovidServiceClient(conn)
+}
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestCovidServer_ListNationalCases(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	rt := 1.2
+	mockService.On("ListNationalCases", service.QueryOptions{
+		Range: service.DateRange{Start: date("2020-03-01"), End: date("2020-03-31")},
+		Sort:  utils.SortParams{Field: "positive", Order: "desc"},
+		Page:  service.Page{Limit: 2, Offset: 4},
+	}).Return([]models.NationalCase{
+		{ID: 1, Day: 10, Date: date("2020-03-10"), Positive: 27, CumulativePositive: 100, Rt: &rt,
+			Tests: &models.DailyTests{PCR: 300, CumulativePCR: 900}},
+		{ID: 2, Day: 11, Date: date("2020-03-11"), Positive: 20},
+	}, 31, nil)
+
+	resp, err := client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{
+		Query:  &covidv1.CaseQuery{StartDate: "2020-03-01", EndDate: "2020-03-31", Sort: "positive:desc"},
+		Limit:  2,
+		Offset: 4,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(31), resp.GetTotal())
+	require.Len(t, resp.GetCases(), 2)
+	first := resp.GetCases()[0]
+	assert.Equal(t, int64(10), first.GetDay())
+	assert.Equal(t, date("2020-03-10"), first.GetDate().AsTime())
+	assert.Equal(t, int64(27), first.GetPositive())
+	assert.Equal(t, int64(100), first.GetCumulativePositive())
+	require.NotNil(t, first.Rt)
+	assert.Equal(t, 1.2, first.GetRt())
+	assert.Nil(t, first.RtUpper)
+	assert.Equal(t, int64(300), first.GetTests().GetPcr())
+	assert.Nil(t, resp.GetCases()[1].GetTests())
+	mockService.AssertExpectations(t)
+}
+
+// Pages default to and are capped at the limits of /national
+func TestCovidServer_ListNationalCases_DefaultPage(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("ListNationalCases", service.QueryOptions{Page: service.Page{Limit: utils.DefaultPageLimit}}).
+		Return([]models.NationalCase{}, 0, nil)
+	mockService.On("ListNationalCases", service.QueryOptions{Page: service.Page{Limit: utils.MaxPageLimit}}).
+		Return([]models.NationalCase{}, 0, nil)
+
+	_, err := client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{})
+	require.NoError(t, err)
+	_, err = client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{Limit: utils.MaxPageLimit + 1, Offset: -1})
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidServer_ListNationalCases_Range(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("ResolveDateRange", "last30d").Return("2021-01-01", "2021-01-30", nil)
+	mockService.On("ListNationalCases", service.QueryOptions{
+		Range: service.DateRange{Start: date("2021-01-01"), End: date("2021-01-30")},
+		Page:  service.Page{Limit: utils.DefaultPageLimit},
+	}).Return([]models.NationalCase{}, 0, nil)
+
+	_, err := client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{
+		Query: &covidv1.CaseQuery{Range: "last30d"},
+	})
+
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidServer_ListNationalCases_InvalidQuery(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("ResolveDateRange", "lastyear").Return("", "", service.ErrInvalidDateRangePreset)
+
+	tests := []struct {
+		name  string
+		query *covidv1.CaseQuery
+	}{
+		{"malformed date", &covidv1.CaseQuery{StartDate: "2020/03/01", EndDate: "2020-03-31"}},
+		{"unknown range", &covidv1.CaseQuery{Range: "lastyear"}},
+		{"range with dates", &covidv1.CaseQuery{Range: "last30d", StartDate: "2020-03-01"}},
+		{"unknown sort field", &covidv1.CaseQuery{Sort: "name"}},
+		{"unknown sort order", &covidv1.CaseQuery{Sort: "date:up"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{Query: tt.query})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+	mockService.AssertNotCalled(t, "ListNationalCases", mock.Anything)
+}
+
+func TestCovidServer_ListNationalCases_ServiceError(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("ListNationalCases", mock.Anything).Return([]models.NationalCase(nil), 0, errors.New("database error"))
+
+	_, err := client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "database error", status.Convert(err).Message())
+}
+
+func TestCovidServer_StreamNationalCases(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("EachNationalCase", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([][]models.NationalCase{
+			{{Day: 1, Positive: 2}, {Day: 2, Positive: 0}},
+			{{Day: 3, Positive: 5}},
+		}, nil)
+
+	stream, err := client.StreamNationalCases(context.Background(), &covidv1.StreamNationalCasesRequest{
+		Query: &covidv1.CaseQuery{Sort: "date"},
+	})
+	require.NoError(t, err)
+
+	var days []int64
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		days = append(days, c.GetDay())
+	}
+	assert.Equal(t, []int64{1, 2, 3}, days)
+	mockService.AssertExpectations(t)
+}
+
+// Cases sent before a failure reach the client before its status
+func TestCovidServer_StreamNationalCases_Error(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("EachNationalCase", service.QueryOptions{}).
+		Return([][]models.NationalCase{{{Day: 1}}}, errors.New("connection lost"))
+
+	stream, err := client.StreamNationalCases(context.Background(), &covidv1.StreamNationalCasesRequest{})
+	require.NoError(t, err)
+
+	c, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), c.GetDay())
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestCovidServer_GetNationalCase(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("GetLatestNationalCase").Return(&models.NationalCase{Day: 400}, nil)
+	mockService.On("GetNationalCaseByDay", int64(10)).Return(&models.NationalCase{Day: 10}, nil)
+	mockService.On("GetNationalCaseByDay", int64(999)).Return((*models.NationalCase)(nil), nil)
+
+	latest, err := client.GetLatestNationalCase(context.Background(), &covidv1.GetLatestNationalCaseRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), latest.GetDay())
+
+	byDay, err := client.GetNationalCaseByDay(context.Background(), &covidv1.GetNationalCaseByDayRequest{Day: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), byDay.GetDay())
+
+	_, err = client.GetNationalCaseByDay(context.Background(), &covidv1.GetNationalCaseByDayRequest{Day: 999})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCovidServer_Provinces(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("GetProvinces").Return([]models.Province{{ID: "72", Name: "Sulawesi Tengah"}, {ID: "73", Name: "Sulawesi Selatan"}}, nil)
+	mockService.On("GetProvinceByID", "72").Return(&models.Province{ID: "72", Name: "Sulawesi Tengah"}, nil)
+	mockService.On("GetProvinceByID", "99").Return((*models.Province)(nil), nil)
+
+	list, err := client.ListProvinces(context.Background(), &covidv1.ListProvincesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetProvinces(), 2)
+	assert.Equal(t, "73", list.GetProvinces()[1].GetId())
+
+	province, err := client.GetProvince(context.Background(), &covidv1.GetProvinceRequest{Id: "72"})
+	require.NoError(t, err)
+	assert.Equal(t, "Sulawesi Tengah", province.GetName())
+
+	_, err = client.GetProvince(context.Background(), &covidv1.GetProvinceRequest{Id: "99"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCovidServer_ListProvinceCases(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	withProvince := models.ProvinceCaseWithDate{Date: date("2020-04-01")}
+	withProvince.ProvinceID = "72"
+	withProvince.Positive = 3
+	withProvince.PersonUnderObservation = 120
+	withProvince.Province = &models.Province{ID: "72", Name: "Sulawesi Tengah"}
+	withoutProvince := models.ProvinceCaseWithDate{Date: date("2020-03-31")}
+	withoutProvince.ProvinceID = "72"
+	mockService.On("ListProvinceCases", service.QueryOptions{
+		ProvinceID: "72",
+		Page:       service.Page{Limit: 10},
+	}).Return([]models.ProvinceCaseWithDate{withProvince, withoutProvince}, 2, nil)
+
+	resp, err := client.ListProvinceCases(context.Background(), &covidv1.ListProvinceCasesRequest{ProvinceId: "72", Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), resp.GetTotal())
+	require.Len(t, resp.GetCases(), 2)
+	first := resp.GetCases()[0]
+	assert.Equal(t, date("2020-04-01"), first.GetDate().AsTime())
+	assert.Equal(t, int64(3), first.GetPositive())
+	assert.Equal(t, int64(120), first.GetPersonUnderObservation())
+	assert.Equal(t, "Sulawesi Tengah", first.GetProvince().GetName())
+	assert.Equal(t, "72", resp.GetCases()[1].GetProvince().GetId())
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidServer_StreamProvinceCases(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	c := models.ProvinceCaseWithDate{}
+	c.ProvinceID = "72"
+	c.Day = 7
+	mockService.On("EachProvinceCase", service.QueryOptions{ProvinceID: "72"}).
+		Return([][]models.ProvinceCaseWithDate{{c}}, nil)
+
+	stream, err := client.StreamProvinceCases(context.Background(), &covidv1.StreamProvinceCasesRequest{ProvinceId: "72"})
+	require.NoError(t, err)
+
+	got, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.GetDay())
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+	mockService.AssertExpectations(t)
+}
+
+// A panicking call fails with INTERNAL and leaves the server up
+func TestCovidServer_RecoversPanics(t *testing.T) {
+	mockService := new(mockCovidService)
+	client := dial(t, mockService)
+	mockService.On("GetProvinces").Return([]models.Province{}, nil)
+
+	// ResolveDateRange is not mocked, so the call panics
+	_, err := client.ListNationalCases(context.Background(), &covidv1.ListNationalCasesRequest{
+		Query: &covidv1.CaseQuery{Range: "last7d"},
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = client.ListProvinces(context.Background(), &covidv1.ListProvincesRequest{})
+	assert.NoError(t, err)
+}
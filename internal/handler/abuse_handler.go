@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AbuseHandler shows and lifts the temporary bans applied by abuse detection.
+type AbuseHandler struct {
+	guard service.AbuseGuard
+}
+
+// NewAbuseHandler creates a new AbuseHandler.
+func NewAbuseHandler(guard service.AbuseGuard) *AbuseHandler {
+	return &AbuseHandler{guard: guard}
+}
+
+// ListBans godoc
+//
+//	@Summary		List banned clients
+//	@Description	Returns the clients temporarily banned for collecting too many 404 and 429 responses, latest first.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.ClientBan}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/bans [get]
+func (h *AbuseHandler) ListBans(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	writeSuccessResponse(w, h.guard.ActiveBans())
+}
+
+// Unban godoc
+//
+//	@Summary		Lift a ban
+//	@Description	Lifts the client's ban and resets its strike count.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			ip			path		string	true	"Client IP"
+//	@Success		200			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/bans/{ip} [delete]
+func (h *AbuseHandler) Unban(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	ip := mux.Vars(r)["ip"]
+	if !h.guard.Unban(ip) {
+		writeErrorResponse(w, http.StatusNotFound, "Client is not banned")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, Response{Status: "success", Message: "Client " + ip + " unbanned"})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAbuseGuard struct {
+	bans     []models.ClientBan
+	unbanned []string
+}
+
+func (s *stubAbuseGuard) ActiveBans() []models.ClientBan {
+	return s.bans
+}
+
+func (s *stubAbuseGuard) Unban(clientIP string) bool {
+	for _, ban := range s.bans {
+		if ban.ClientIP == clientIP {
+			s.unbanned = append(s.unbanned, clientIP)
+			return true
+		}
+	}
+	return false
+}
+
+func TestAbuseHandler_ListBans(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	bannedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := NewAbuseHandler(&stubAbuseGuard{bans: []models.ClientBan{
+		{ClientIP: "203.0.113.7", Strikes: 60, BannedAt: bannedAt, ExpiresAt: bannedAt.Add(time.Hour)},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/bans", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	h.ListBans(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"client_ip":"203.0.113.7"`)
+	assert.Contains(t, w.Body.String(), `"expires_at":"2026-01-02T04:04:05Z"`)
+}
+
+func TestAbuseHandler_Unban(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	guard := &stubAbuseGuard{bans: []models.ClientBan{{ClientIP: "203.0.113.7"}}}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/bans/{ip}", NewAbuseHandler(guard).Unban).Methods("DELETE")
+
+	for ip, want := range map[string]int{"203.0.113.7": http.StatusOK, "198.51.100.1": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/bans/"+ip, nil)
+		req.Header.Set("X-Admin-Key", "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, ip)
+	}
+	assert.Equal(t, []string{"203.0.113.7"}, guard.unbanned)
+}
+
+func TestAbuseHandler_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	h := NewAbuseHandler(&stubAbuseGuard{})
+
+	w := httptest.NewRecorder()
+	h.ListBans(w, httptest.NewRequest(http.MethodGet, "/admin/bans", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
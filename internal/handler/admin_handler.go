@@ -4,7 +4,10 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
 )
 
 // AdminHandler handles admin endpoints.
@@ -20,19 +23,24 @@ func NewAdminHandler(invalidator service.CacheInvalidator) *AdminHandler {
 // ClearCache godoc
 //
 //	@Summary		Clear all in-memory cache
-//	@Description	Clears all cached data. Requires X-Admin-Key header matching ADMIN_KEY env var.
+//	@Description	Clears all cached data. Requires X-Admin-Key header matching ADMIN_KEY env var. With dry_run=true the number of entries that would be removed is reported instead.
 //	@Tags			admin
 //	@Produce		json
 //	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			dry_run		query		boolean	false	"Report what would be cleared without clearing"
 //	@Success		200			{object}	map[string]string
 //	@Failure		401			{object}	map[string]string
 //	@Router			/admin/cache/clear [post]
 func (h *AdminHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
-	adminKey := os.Getenv("ADMIN_KEY")
-	if adminKey == "" || r.Header.Get("X-Admin-Key") != adminKey {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"error":"unauthorized"}`)) //nolint:errcheck
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	if isDryRun(r) {
+		summary := models.ChangeSummary{DryRun: true}
+		if sizer, ok := h.invalidator.(service.CacheSizer); ok {
+			summary.Deleted = sizer.Len()
+		}
+		writeDryRunResponse(w, summary)
 		return
 	}
 	h.invalidator.Clear()
@@ -40,3 +48,36 @@ func (h *AdminHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"message":"cache cleared"}`)) //nolint:errcheck
 }
+
+// authorizeAdmin checks the X-Admin-Key header against the ADMIN_KEY env var and
+// writes a 401 response when it does not match. Requests authenticated with an
+// admin scoped API key are accepted as well.
+func authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if key, ok := middleware.APIKeyFromContext(r.Context()); ok && key.HasScope(models.ScopeAdmin) {
+		return true
+	}
+	adminKey := os.Getenv("ADMIN_KEY")
+	if adminKey == "" || r.Header.Get("X-Admin-Key") != adminKey {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unauthorized"}`)) //nolint:errcheck
+		return false
+	}
+	return true
+}
+
+// requireAdmin only passes on requests accepted by authorizeAdmin
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeAdmin(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isDryRun reports whether a mutating request asked for ?dry_run=true. Dry runs
+// must compute the same change summary as a real run but commit nothing.
+func isDryRun(r *http.Request) bool {
+	return utils.ParseBoolQueryParam(r, "dry_run")
+}
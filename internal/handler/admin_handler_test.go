@@ -6,6 +6,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -90,3 +94,50 @@ func TestAdminHandler_ClearCache_EmptyAdminKeyEnv(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	invalidator.AssertNotCalled(t, "Clear")
 }
+
+type sizedCacheInvalidator struct {
+	MockCacheInvalidator
+	entries int
+}
+
+func (s *sizedCacheInvalidator) Len() int {
+	return s.entries
+}
+
+func TestAdminHandler_ClearCache_DryRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "test-secret-key")
+
+	invalidator := &sizedCacheInvalidator{entries: 7}
+	h := NewAdminHandler(invalidator)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/clear?dry_run=true", nil)
+	req.Header.Set("X-Admin-Key", "test-secret-key")
+	w := httptest.NewRecorder()
+
+	h.ClearCache(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	assert.Contains(t, w.Body.String(), `"deleted":7`)
+	invalidator.AssertNotCalled(t, "Clear")
+}
+
+func TestAdminHandler_ClearCache_AdminAPIKey(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "test-secret-key")
+
+	invalidator := new(MockCacheInvalidator)
+	invalidator.On("Clear").Once()
+	keys := service.NewAPIKeyService(nil, []models.APIKey{
+		{Name: "ops", KeyHash: models.HashAPIKey("ops-key"), Scopes: []string{models.ScopeAdmin}},
+	})
+	handler := middleware.APIKeyAuth(config.AuthConfig{Enabled: true}, keys)(http.HandlerFunc(NewAdminHandler(invalidator).ClearCache))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/clear", nil)
+	req.Header.Set("X-API-Key", "ops-key")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	invalidator.AssertExpectations(t)
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// AlertHandler handles the alert rule admin endpoints.
+type AlertHandler struct {
+	service service.AlertServiceInterface
+}
+
+// NewAlertHandler creates a new AlertHandler.
+func NewAlertHandler(service service.AlertServiceInterface) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+// alertRuleRequest is the body of create and update requests. Active defaults to true.
+type alertRuleRequest struct {
+	Name      string  `json:"name"`
+	Metric    string  `json:"metric"`
+	Scope     string  `json:"scope"`
+	Operator  string  `json:"operator"`
+	Threshold float64 `json:"threshold"`
+	Channel   string  `json:"channel"`
+	Target    string  `json:"target"`
+	Active    *bool   `json:"active"`
+}
+
+func (req alertRuleRequest) rule() models.AlertRule {
+	rule := models.AlertRule{
+		Name:      req.Name,
+		Metric:    req.Metric,
+		Scope:     req.Scope,
+		Operator:  req.Operator,
+		Threshold: req.Threshold,
+		Channel:   req.Channel,
+		Target:    req.Target,
+		Active:    true,
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	return rule
+}
+
+// ListRules godoc
+//
+//	@Summary		List alert rules
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.AlertRule}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/alert-rules [get]
+func (h *AlertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	rules, err := h.service.ListRules(r.Context())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, rules)
+}
+
+// GetRule godoc
+//
+//	@Summary		Get an alert rule
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Rule ID"
+//	@Success		200			{object}	Response{data=models.AlertRule}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/alert-rules/{id} [get]
+func (h *AlertHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseRuleID(w, r)
+	if !ok {
+		return
+	}
+	rule, err := h.service.GetRule(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if rule == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Alert rule not found")
+		return
+	}
+	writeSuccessResponse(w, rule)
+}
+
+// CreateRule godoc
+//
+//	@Summary		Create an alert rule
+//	@Description	Creates a rule such as daily_positive in province 72 > 100 notifying a Telegram chat. Metrics: daily_positive, daily_recovered, daily_deceased, cumulative_positive, cumulative_recovered, cumulative_deceased, rt. Scope is "national" or a province ID. Operators: > >= < <= == !=. Channels: log, webhook, telegram (target is the chat ID).
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string				true	"Admin key"
+//	@Param			rule		body		alertRuleRequest	true	"Alert rule"
+//	@Success		201			{object}	Response{data=models.AlertRule}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/alert-rules [post]
+func (h *AlertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	req, ok := decodeAlertRule(w, r)
+	if !ok {
+		return
+	}
+	rule, err := h.service.CreateRule(r.Context(), req.rule())
+	if err != nil {
+		writeAlertRuleError(w, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, Response{Status: "success", Data: rule})
+}
+
+// UpdateRule godoc
+//
+//	@Summary		Replace an alert rule
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string				true	"Admin key"
+//	@Param			id			path		integer				true	"Rule ID"
+//	@Param			rule		body		alertRuleRequest	true	"Alert rule"
+//	@Success		200			{object}	Response{data=models.AlertRule}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/alert-rules/{id} [put]
+func (h *AlertHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseRuleID(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeAlertRule(w, r)
+	if !ok {
+		return
+	}
+	rule, err := h.service.UpdateRule(r.Context(), id, req.rule())
+	if err != nil {
+		writeAlertRuleError(w, err)
+		return
+	}
+	if rule == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Alert rule not found")
+		return
+	}
+	writeSuccessResponse(w, rule)
+}
+
+// DeleteRule godoc
+//
+//	@Summary		Delete an alert rule and its evaluation history
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Rule ID"
+//	@Success		200			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/alert-rules/{id} [delete]
+func (h *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseRuleID(w, r)
+	if !ok {
+		return
+	}
+	found, err := h.service.DeleteRule(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeErrorResponse(w, http.StatusNotFound, "Alert rule not found")
+		return
+	}
+	writeSuccessResponse(w, map[string]int64{"deleted": id})
+}
+
+// ListEvaluations godoc
+//
+//	@Summary		List evaluations of an alert rule
+//	@Description	Returns the latest evaluations, newest first, with the observed value and whether the rule triggered and notified.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Rule ID"
+//	@Param			limit		query		integer	false	"Maximum evaluations to return (default: 50)"
+//	@Success		200			{object}	Response{data=[]models.AlertEvaluation}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/alert-rules/{id}/evaluations [get]
+func (h *AlertHandler) ListEvaluations(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseRuleID(w, r)
+	if !ok {
+		return
+	}
+	limit := utils.ParseIntQueryParam(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	evaluations, err := h.service.ListEvaluations(r.Context(), id, limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if evaluations == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Alert rule not found")
+		return
+	}
+	writeSuccessResponse(w, evaluations)
+}
+
+func parseRuleID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid alert rule ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeAlertRule(w http.ResponseWriter, r *http.Request) (alertRuleRequest, bool) {
+	var req alertRuleRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid alert rule body: "+err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+func writeAlertRuleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidAlertRule) {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAlertService struct {
+	mock.Mock
+}
+
+func (m *MockAlertService) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	args := m.Called()
+	return args.Get(0).([]models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertService) GetRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertService) CreateRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error) {
+	args := m.Called(rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertService) UpdateRule(ctx context.Context, id int64, rule models.AlertRule) (*models.AlertRule, error) {
+	args := m.Called(id, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertService) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAlertService) ListEvaluations(ctx context.Context, ruleID int64, limit int) ([]models.AlertEvaluation, error) {
+	args := m.Called(ruleID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AlertEvaluation), args.Error(1)
+}
+
+func serveAlertAdmin(svc *MockAlertService, method, path, body string) *httptest.ResponseRecorder {
+	router := SetupRoutes(Services{AlertService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+const sultengSpikeBody = `{"name":"Sulteng spike","metric":"daily_positive","scope":"72","operator":">","threshold":100,"channel":"telegram","target":"-100"}`
+
+func TestAlertHandler_CreateRule(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	rule := models.AlertRule{Name: "Sulteng spike", Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100,
+		Channel: models.AlertChannelTelegram, Target: "-100", Active: true}
+	created := rule
+	created.ID = 1
+	svc := new(MockAlertService)
+	svc.On("CreateRule", rule).Return(&created, nil)
+
+	w := serveAlertAdmin(svc, http.MethodPost, "/admin/alert-rules", sultengSpikeBody)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":1`)
+	svc.AssertExpectations(t)
+}
+
+func TestAlertHandler_CreateRule_Invalid(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAlertService)
+	svc.On("CreateRule", mock.Anything).Return(nil, fmt.Errorf("%w: unknown metric \"beds\"", service.ErrInvalidAlertRule))
+
+	w := serveAlertAdmin(svc, http.MethodPost, "/admin/alert-rules", `{"name":"x","metric":"beds"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown metric")
+}
+
+func TestAlertHandler_CreateRule_UnknownField(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAlertService)
+
+	w := serveAlertAdmin(svc, http.MethodPost, "/admin/alert-rules", `{"name":"x","limit":5}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "CreateRule", mock.Anything)
+}
+
+func TestAlertHandler_CreateRule_RequiresAdminKey(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "other")
+	svc := new(MockAlertService)
+
+	w := serveAlertAdmin(svc, http.MethodPost, "/admin/alert-rules", sultengSpikeBody)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	svc.AssertNotCalled(t, "CreateRule", mock.Anything)
+}
+
+func TestAlertHandler_UpdateRule_KeepsInactive(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAlertService)
+	svc.On("UpdateRule", int64(1), mock.MatchedBy(func(r models.AlertRule) bool { return !r.Active })).
+		Return(&models.AlertRule{ID: 1}, nil)
+
+	w := serveAlertAdmin(svc, http.MethodPut, "/admin/alert-rules/1",
+		`{"name":"x","metric":"rt","scope":"national","operator":">","threshold":1,"channel":"log","active":false}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestAlertHandler_UpdateRule_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAlertService)
+	svc.On("UpdateRule", int64(9), mock.Anything).Return(nil, nil)
+
+	w := serveAlertAdmin(svc, http.MethodPut, "/admin/alert-rules/9", sultengSpikeBody)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAlertHandler_DeleteRule(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAlertService)
+	svc.On("DeleteRule", int64(1)).Return(true, nil)
+	svc.On("DeleteRule", int64(9)).Return(false, nil)
+
+	assert.Equal(t, http.StatusOK, serveAlertAdmin(svc, http.MethodDelete, "/admin/alert-rules/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveAlertAdmin(svc, http.MethodDelete, "/admin/alert-rules/9", "").Code)
+}
+
+func TestAlertHandler_ListEvaluations(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	day, value := int64(410), 120.0
+	svc := new(MockAlertService)
+	svc.On("ListEvaluations", int64(1), 10).Return([]models.AlertEvaluation{
+		{ID: 2, RuleID: 1, Day: &day, Value: &value, Triggered: true, Notified: true},
+	}, nil)
+	svc.On("ListEvaluations", int64(9), 50).Return(nil, nil)
+
+	w := serveAlertAdmin(svc, http.MethodGet, "/admin/alert-rules/1/evaluations?limit=10", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"notified":true`)
+
+	w = serveAlertAdmin(svc, http.MethodGet, "/admin/alert-rules/9/evaluations", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
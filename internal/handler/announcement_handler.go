@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AnnouncementHandler serves the admin endpoints of the announcements shown
+// in meta.notices
+type AnnouncementHandler struct {
+	service service.AnnouncementServiceInterface
+}
+
+// NewAnnouncementHandler creates a new AnnouncementHandler.
+func NewAnnouncementHandler(service service.AnnouncementServiceInterface) *AnnouncementHandler {
+	return &AnnouncementHandler{service: service}
+}
+
+// announcementRequest is the body of create and update requests
+type announcementRequest struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// StartsAt defaults to now on create and to the current start on update
+	StartsAt *time.Time `json:"starts_at"`
+	// EndsAt is optional; without it the announcement shows until deleted
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+func (req announcementRequest) announcement() models.Announcement {
+	a := models.Announcement{Severity: req.Severity, Message: req.Message, EndsAt: req.EndsAt}
+	if req.StartsAt != nil {
+		a.StartsAt = req.StartsAt.UTC()
+	}
+	if a.EndsAt != nil {
+		end := a.EndsAt.UTC()
+		a.EndsAt = &end
+	}
+	return a
+}
+
+// ListAnnouncements godoc
+//
+//	@Summary		List announcements
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.Announcement}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/announcements [get]
+func (h *AnnouncementHandler) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	announcements, err := h.service.ListAnnouncements(r.Context())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, announcements)
+}
+
+// CreateAnnouncement godoc
+//
+//	@Summary		Create an announcement
+//	@Description	Adds a message shown in meta.notices of every response from starts_at (default now) until ends_at, or until it is deleted. Severity is info, warning or critical; the message is at most 500 characters.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key		header		string				true	"Admin key"
+//	@Param			announcement	body		announcementRequest	true	"Announcement"
+//	@Success		201				{object}	Response{data=models.Announcement}
+//	@Failure		400				{object}	Response
+//	@Failure		401				{object}	map[string]string
+//	@Router			/admin/announcements [post]
+func (h *AnnouncementHandler) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	req, ok := decodeAnnouncement(w, r)
+	if !ok {
+		return
+	}
+	announcement, err := h.service.CreateAnnouncement(r.Context(), req.announcement())
+	if err != nil {
+		writeAnnouncementError(w, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, Response{Status: "success", Data: announcement})
+}
+
+// UpdateAnnouncement godoc
+//
+//	@Summary		Update an announcement
+//	@Description	Replaces the announcement's severity, message and time window. Set ends_at to now to take it down while keeping it listed.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key		header		string				true	"Admin key"
+//	@Param			id				path		integer				true	"Announcement ID"
+//	@Param			announcement	body		announcementRequest	true	"Announcement"
+//	@Success		200				{object}	Response{data=models.Announcement}
+//	@Failure		400				{object}	Response
+//	@Failure		401				{object}	map[string]string
+//	@Failure		404				{object}	Response
+//	@Router			/admin/announcements/{id} [put]
+func (h *AnnouncementHandler) UpdateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseAnnouncementID(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeAnnouncement(w, r)
+	if !ok {
+		return
+	}
+	announcement, err := h.service.UpdateAnnouncement(r.Context(), id, req.announcement())
+	if err != nil {
+		writeAnnouncementError(w, err)
+		return
+	}
+	if announcement == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Announcement not found")
+		return
+	}
+	writeSuccessResponse(w, announcement)
+}
+
+// DeleteAnnouncement godoc
+//
+//	@Summary		Delete an announcement
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Announcement ID"
+//	@Success		200			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/announcements/{id} [delete]
+func (h *AnnouncementHandler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseAnnouncementID(w, r)
+	if !ok {
+		return
+	}
+	found, err := h.service.DeleteAnnouncement(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeErrorResponse(w, http.StatusNotFound, "Announcement not found")
+		return
+	}
+	writeSuccessResponse(w, map[string]int64{"deleted": id})
+}
+
+func parseAnnouncementID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid announcement ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeAnnouncement(w http.ResponseWriter, r *http.Request) (announcementRequest, bool) {
+	var req announcementRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid announcement body: "+err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+func writeAnnouncementError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidAnnouncement) {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAnnouncementService struct {
+	mock.Mock
+}
+
+func (m *MockAnnouncementService) ActiveNotices() []models.Notice {
+	args := m.Called()
+	return args.Get(0).([]models.Notice)
+}
+
+func (m *MockAnnouncementService) ListAnnouncements(ctx context.Context) ([]models.Announcement, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementService) CreateAnnouncement(ctx context.Context, announcement models.Announcement) (*models.Announcement, error) {
+	args := m.Called(announcement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementService) UpdateAnnouncement(ctx context.Context, id int64, announcement models.Announcement) (*models.Announcement, error) {
+	args := m.Called(id, announcement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementService) DeleteAnnouncement(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func serveAnnouncements(t *testing.T, svc *MockAnnouncementService, method, path, body string) *httptest.ResponseRecorder {
+	router := SetupRoutes(Services{AnnouncementService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	t.Cleanup(func() { noticeBoard = nil })
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAnnouncements_NoticesInResponseMeta(t *testing.T) {
+	svc := new(MockAnnouncementService)
+	svc.On("ActiveNotices").Return([]models.Notice{
+		{ID: 1, Severity: models.AnnouncementSeverityWarning, Message: "Data delayed today due to an upstream outage"},
+	})
+
+	w := serveAnnouncements(t, svc, "GET", "/api/v1/tenant", "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Meta)
+	require.Len(t, response.Meta.Notices, 1)
+	assert.Equal(t, "Data delayed today due to an upstream outage", response.Meta.Notices[0].Message)
+}
+
+func TestAnnouncements_EmptyNoticesAndDisabled(t *testing.T) {
+	svc := new(MockAnnouncementService)
+	svc.On("ActiveNotices").Return([]models.Notice{})
+
+	w := serveAnnouncements(t, svc, "GET", "/api/v1/tenant", "")
+	assert.Contains(t, w.Body.String(), `"meta":{"notices":[]}`)
+
+	w = httptest.NewRecorder()
+	SetupRoutes(Services{}, nil, false).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/tenant", nil))
+	assert.NotContains(t, w.Body.String(), `"meta"`)
+}
+
+func TestAnnouncementHandler_CreateAnnouncement(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAnnouncementService)
+	svc.On("ActiveNotices").Return([]models.Notice{})
+	end := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	svc.On("CreateAnnouncement", models.Announcement{Severity: "warning", Message: "Data delayed today", EndsAt: &end}).
+		Return(&models.Announcement{ID: 4, Severity: "warning", Message: "Data delayed today", EndsAt: &end}, nil)
+
+	w := serveAnnouncements(t, svc, "POST", "/admin/announcements", `{"severity":"warning","message":"Data delayed today","ends_at":"2026-03-02T07:00:00+07:00"}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":4`)
+	svc.AssertExpectations(t)
+}
+
+func TestAnnouncementHandler_CreateAnnouncement_UnknownField(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAnnouncementService)
+	svc.On("ActiveNotices").Return([]models.Notice{})
+
+	w := serveAnnouncements(t, svc, "POST", "/admin/announcements", `{"severity":"info","message":"Hi","title":"x"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "CreateAnnouncement", mock.Anything)
+}
+
+func TestAnnouncementHandler_UpdateAnnouncement_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAnnouncementService)
+	svc.On("ActiveNotices").Return([]models.Notice{})
+	svc.On("UpdateAnnouncement", int64(9), mock.Anything).Return(nil, nil)
+
+	w := serveAnnouncements(t, svc, "PUT", "/admin/announcements/9", `{"severity":"info","message":"Hi"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAnnouncementHandler_DeleteAnnouncement(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAnnouncementService)
+	svc.On("ActiveNotices").Return([]models.Notice{})
+	svc.On("DeleteAnnouncement", int64(4)).Return(true, nil)
+
+	w := serveAnnouncements(t, svc, "DELETE", "/admin/announcements/4", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// APIStatusHandler serves the public status page and its incident admin endpoints.
+type APIStatusHandler struct {
+	service service.APIStatusServiceInterface
+}
+
+// NewAPIStatusHandler creates a new APIStatusHandler.
+func NewAPIStatusHandler(service service.APIStatusServiceInterface) *APIStatusHandler {
+	return &APIStatusHandler{service: service}
+}
+
+// incidentRequest is the body of create and update requests
+type incidentRequest struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	// Resolved resolves the incident, or reopens it when false; only used by updates
+	Resolved bool `json:"resolved"`
+}
+
+func (req incidentRequest) incident() models.Incident {
+	return models.Incident{Title: req.Title, Message: req.Message, Severity: req.Severity}
+}
+
+// GetStatus godoc
+//
+//	@Summary		API status page
+//	@Description	Returns the live state of the API's components, current incident notices and the uptime of each component over the last 24 hours, 7 days and 30 days. Status is operational, degraded or down. Resolved incidents are listed for 7 days.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Response{data=models.APIStatus}
+//	@Router			/system-status [get]
+func (h *APIStatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.service.GetStatus(r.Context())
+	if err != nil {
+		log.Printf("Error building status page: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load status")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	writeSuccessResponse(w, status)
+}
+
+// ListIncidents godoc
+//
+//	@Summary		List status page incidents
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.Incident}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/incidents [get]
+func (h *APIStatusHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	incidents, err := h.service.ListIncidents(r.Context())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, incidents)
+}
+
+// CreateIncident godoc
+//
+//	@Summary		Post an incident notice
+//	@Description	Adds a notice to the status page. Severity is minor, major or maintenance; open minor and major incidents mark the API as degraded.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string			true	"Admin key"
+//	@Param			incident	body		incidentRequest	true	"Incident"
+//	@Success		201			{object}	Response{data=models.Incident}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/incidents [post]
+func (h *APIStatusHandler) CreateIncident(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	req, ok := decodeIncident(w, r)
+	if !ok {
+		return
+	}
+	incident, err := h.service.CreateIncident(r.Context(), req.incident())
+	if err != nil {
+		writeIncidentError(w, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, Response{Status: "success", Data: incident})
+}
+
+// UpdateIncident godoc
+//
+//	@Summary		Update or resolve an incident notice
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string			true	"Admin key"
+//	@Param			id			path		integer			true	"Incident ID"
+//	@Param			incident	body		incidentRequest	true	"Incident"
+//	@Success		200			{object}	Response{data=models.Incident}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/incidents/{id} [put]
+func (h *APIStatusHandler) UpdateIncident(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseIncidentID(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeIncident(w, r)
+	if !ok {
+		return
+	}
+	incident, err := h.service.UpdateIncident(r.Context(), id, req.incident(), req.Resolved)
+	if err != nil {
+		writeIncidentError(w, err)
+		return
+	}
+	if incident == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+	writeSuccessResponse(w, incident)
+}
+
+// DeleteIncident godoc
+//
+//	@Summary		Delete an incident notice
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Incident ID"
+//	@Success		200			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/incidents/{id} [delete]
+func (h *APIStatusHandler) DeleteIncident(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseIncidentID(w, r)
+	if !ok {
+		return
+	}
+	found, err := h.service.DeleteIncident(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeErrorResponse(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+	writeSuccessResponse(w, map[string]int64{"deleted": id})
+}
+
+func parseIncidentID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid incident ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeIncident(w http.ResponseWriter, r *http.Request) (incidentRequest, bool) {
+	var req incidentRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid incident body: "+err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+func writeIncidentError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidIncident) {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIStatusService struct {
+	mock.Mock
+}
+
+func (m *MockAPIStatusService) GetStatus(ctx context.Context) (*models.APIStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIStatus), args.Error(1)
+}
+
+func (m *MockAPIStatusService) ListIncidents(ctx context.Context) ([]models.Incident, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Incident), args.Error(1)
+}
+
+func (m *MockAPIStatusService) CreateIncident(ctx context.Context, incident models.Incident) (*models.Incident, error) {
+	args := m.Called(incident)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Incident), args.Error(1)
+}
+
+func (m *MockAPIStatusService) UpdateIncident(ctx context.Context, id int64, incident models.Incident, resolved bool) (*models.Incident, error) {
+	args := m.Called(id, incident, resolved)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Incident), args.Error(1)
+}
+
+func (m *MockAPIStatusService) DeleteIncident(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func serveAPIStatus(svc *MockAPIStatusService, method, path, body string) *httptest.ResponseRecorder {
+	router := SetupRoutes(Services{APIStatusService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIStatusHandler_GetStatus(t *testing.T) {
+	svc := new(MockAPIStatusService)
+	svc.On("GetStatus").Return(&models.APIStatus{
+		Status:     models.APIStatusDegraded,
+		Components: []models.ComponentStatus{{Name: "database", Status: models.APIStatusDown}},
+		Incidents:  []models.Incident{},
+		Uptime:     []models.ComponentUptime{},
+		CheckedAt:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}, nil)
+
+	w := serveAPIStatus(svc, "GET", "/api/v1/system-status", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), `"status":"degraded"`)
+	assert.Contains(t, w.Body.String(), `"name":"database","status":"down"`)
+}
+
+func TestAPIStatusHandler_CreateIncident(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAPIStatusService)
+	incident := models.Incident{Title: "Delayed data", Message: "Ministry feed is late", Severity: models.IncidentSeverityMinor}
+	created := incident
+	created.ID = 4
+	svc.On("CreateIncident", incident).Return(&created, nil)
+
+	w := serveAPIStatus(svc, "POST", "/admin/incidents", `{"title":"Delayed data","message":"Ministry feed is late","severity":"minor"}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":4`)
+}
+
+func TestAPIStatusHandler_CreateIncident_Invalid(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAPIStatusService)
+	svc.On("CreateIncident", mock.Anything).Return(nil, fmt.Errorf("%w: severity must be minor, major or maintenance", service.ErrInvalidIncident))
+
+	w := serveAPIStatus(svc, "POST", "/admin/incidents", `{"title":"Outage","severity":"critical"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAPIStatusHandler_CreateIncident_UnknownField(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAPIStatusService)
+
+	w := serveAPIStatus(svc, "POST", "/admin/incidents", `{"title":"Outage","severity":"major","level":3}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "CreateIncident", mock.Anything)
+}
+
+func TestAPIStatusHandler_UpdateIncident_Resolve(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAPIStatusService)
+	resolvedAt := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	svc.On("UpdateIncident", int64(4), models.Incident{Title: "Delayed data", Severity: models.IncidentSeverityMinor}, true).
+		Return(&models.Incident{ID: 4, Title: "Delayed data", Severity: models.IncidentSeverityMinor, ResolvedAt: &resolvedAt}, nil)
+
+	w := serveAPIStatus(svc, "PUT", "/admin/incidents/4", `{"title":"Delayed data","severity":"minor","resolved":true}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"resolved_at":"2026-03-01T13:00:00Z"`)
+}
+
+func TestAPIStatusHandler_UpdateIncident_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAPIStatusService)
+	svc.On("UpdateIncident", int64(9), mock.Anything, false).Return(nil, nil)
+
+	w := serveAPIStatus(svc, "PUT", "/admin/incidents/9", `{"title":"Delayed data","severity":"minor"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAPIStatusHandler_DeleteIncident(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockAPIStatusService)
+	svc.On("DeleteIncident", int64(4)).Return(true, nil)
+	svc.On("DeleteIncident", int64(5)).Return(false, nil)
+
+	assert.Equal(t, http.StatusOK, serveAPIStatus(svc, "DELETE", "/admin/incidents/4", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveAPIStatus(svc, "DELETE", "/admin/incidents/5", "").Code)
+}
+
+func TestAPIStatusHandler_RequiresAdminKey(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "other")
+	svc := new(MockAPIStatusService)
+
+	w := serveAPIStatus(svc, "GET", "/admin/incidents", "")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// BackupHandler handles admin-triggered database backups.
+type BackupHandler struct {
+	service service.BackupServiceInterface
+}
+
+// NewBackupHandler creates a new BackupHandler.
+func NewBackupHandler(service service.BackupServiceInterface) *BackupHandler {
+	return &BackupHandler{service: service}
+}
+
+// StartBackup godoc
+//
+//	@Summary		Start a database backup
+//	@Description	Starts an asynchronous gzipped SQL backup. Requires X-Admin-Key header matching ADMIN_KEY env var.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		202			{object}	Response{data=models.BackupJob}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/backups [post]
+func (h *BackupHandler) StartBackup(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	job := h.service.StartBackup()
+	writeJSONResponse(w, http.StatusAccepted, Response{
+		Status: "success",
+		Data:   job,
+	})
+}
+
+// ListBackups godoc
+//
+//	@Summary		List database backups
+//	@Description	Lists backup jobs started since the server booted, newest first.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.BackupJob}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/backups [get]
+func (h *BackupHandler) ListBackups(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	writeSuccessResponse(w, h.service.ListJobs())
+}
+
+// GetBackup godoc
+//
+//	@Summary		Get a database backup job
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		string	true	"Backup job ID"
+//	@Success		200			{object}	Response{data=models.BackupJob}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/backups/{id} [get]
+func (h *BackupHandler) GetBackup(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	job := h.service.GetJob(id)
+	if job == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Backup job "+id+" not found")
+		return
+	}
+	writeSuccessResponse(w, job)
+}
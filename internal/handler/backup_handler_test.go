@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBackupService struct{ mock.Mock }
+
+func (m *MockBackupService) StartBackup() models.BackupJob {
+	return m.Called().Get(0).(models.BackupJob)
+}
+
+func (m *MockBackupService) GetJob(id string) *models.BackupJob {
+	if r := m.Called(id).Get(0); r != nil {
+		return r.(*models.BackupJob)
+	}
+	return nil
+}
+
+func (m *MockBackupService) ListJobs() []models.BackupJob {
+	return m.Called().Get(0).([]models.BackupJob)
+}
+
+func TestBackupHandler_StartBackup(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockBackupService)
+	svc.On("StartBackup").Return(models.BackupJob{ID: "job-1", Status: models.BackupStatusRunning})
+
+	h := NewBackupHandler(svc)
+	req := httptest.NewRequest(http.MethodPost, "/admin/backups", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	h.StartBackup(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), "job-1")
+	svc.AssertExpectations(t)
+}
+
+func TestBackupHandler_StartBackup_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockBackupService)
+
+	h := NewBackupHandler(svc)
+	req := httptest.NewRequest(http.MethodPost, "/admin/backups", nil)
+	w := httptest.NewRecorder()
+	h.StartBackup(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	svc.AssertNotCalled(t, "StartBackup")
+}
+
+func TestBackupHandler_GetBackup_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockBackupService)
+	svc.On("GetJob", "missing").Return(nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/backups/{id}", NewBackupHandler(svc).GetBackup)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/backups/missing", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	svc.AssertExpectations(t)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// BandwidthHandler exposes the response bytes counted by the bandwidth middleware.
+type BandwidthHandler struct {
+	reporter service.BandwidthReporter
+}
+
+// NewBandwidthHandler creates a new BandwidthHandler.
+func NewBandwidthHandler(reporter service.BandwidthReporter) *BandwidthHandler {
+	return &BandwidthHandler{reporter: reporter}
+}
+
+// GetBandwidth godoc
+//
+//	@Summary		Bandwidth per route and API key
+//	@Description	Returns the response body bytes served since startup per route and per API key, most bytes first, with each key's top routes. Requests without a key are reported as the anonymous client and requests with all=true as a separate route, so the consumers worth moving to CSV or exports stand out.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=models.BandwidthReport}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/bandwidth [get]
+func (h *BandwidthHandler) GetBandwidth(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	writeSuccessResponse(w, h.reporter.BandwidthSummary())
+}
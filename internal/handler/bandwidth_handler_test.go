@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubBandwidthReporter struct {
+	report models.BandwidthReport
+}
+
+func (s stubBandwidthReporter) BandwidthSummary() models.BandwidthReport {
+	return s.report
+}
+
+func TestBandwidthHandler_GetBandwidth(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	router := SetupRoutes(Services{Bandwidth: stubBandwidthReporter{report: models.BandwidthReport{
+		Requests:   4,
+		TotalBytes: 12000,
+		Clients:    []models.ClientBandwidth{{Client: "dashboard", APIKeyID: 3, Requests: 4, Bytes: 12000}},
+	}}, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/bandwidth", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_bytes":12000`)
+	assert.Contains(t, w.Body.String(), `"client":"dashboard"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/bandwidth", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// CaseNoteHandler serves the admin endpoints of the analyst notes returned
+// with ?include=notes
+type CaseNoteHandler struct {
+	service service.CaseNoteServiceInterface
+}
+
+// NewCaseNoteHandler creates a new CaseNoteHandler.
+func NewCaseNoteHandler(service service.CaseNoteServiceInterface) *CaseNoteHandler {
+	return &CaseNoteHandler{service: service}
+}
+
+// caseNoteRequest is the body of create and update requests
+type caseNoteRequest struct {
+	// ProvinceID is empty for a note on the national data
+	ProvinceID string          `json:"province_id"`
+	Date       string          `json:"date" example:"2020-07-01"`
+	Text       models.NoteText `json:"text"`
+}
+
+func (req caseNoteRequest) note() (models.CaseNote, error) {
+	n := models.CaseNote{ProvinceID: req.ProvinceID, Text: req.Text}
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return n, errors.New("date must be YYYY-MM-DD")
+		}
+		n.Date = date
+	}
+	return n, nil
+}
+
+// ListNotes godoc
+//
+//	@Summary		List analyst notes
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			province_id	query		string	false	"national or a province ID; all notes when omitted"
+//	@Param			start_date	query		string	false	"Start date (YYYY-MM-DD)"
+//	@Param			end_date	query		string	false	"End date (YYYY-MM-DD)"
+//	@Success		200			{object}	Response{data=[]models.CaseNote}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/notes [get]
+func (h *CaseNoteHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	filter := models.CaseNoteFilter{Scope: r.URL.Query().Get("province_id")}
+	for param, date := range map[string]*time.Time{"start_date": &filter.StartDate, "end_date": &filter.EndDate} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid "+param+", use YYYY-MM-DD")
+			return
+		}
+		*date = parsed
+	}
+	notes, err := h.service.ListNotes(r.Context(), filter)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, notes)
+}
+
+// CreateNote godoc
+//
+//	@Summary		Create an analyst note
+//	@Description	Attaches commentary in Indonesian (text.id) and/or English (text.en), at most 500 characters each, to the national data of a date, or to a province's with province_id. Case endpoints return it with ?include=notes.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string			true	"Admin key"
+//	@Param			note		body		caseNoteRequest	true	"Note"
+//	@Success		201			{object}	Response{data=models.CaseNote}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/notes [post]
+func (h *CaseNoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	note, ok := decodeCaseNote(w, r)
+	if !ok {
+		return
+	}
+	created, err := h.service.CreateNote(r.Context(), note)
+	if err != nil {
+		writeCaseNoteError(w, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, Response{Status: "success", Data: created})
+}
+
+// UpdateNote godoc
+//
+//	@Summary		Update an analyst note
+//	@Description	Replaces the note's province, date and text.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string			true	"Admin key"
+//	@Param			id			path		integer			true	"Note ID"
+//	@Param			note		body		caseNoteRequest	true	"Note"
+//	@Success		200			{object}	Response{data=models.CaseNote}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/notes/{id} [put]
+func (h *CaseNoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseCaseNoteID(w, r)
+	if !ok {
+		return
+	}
+	note, ok := decodeCaseNote(w, r)
+	if !ok {
+		return
+	}
+	updated, err := h.service.UpdateNote(r.Context(), id, note)
+	if err != nil {
+		writeCaseNoteError(w, err)
+		return
+	}
+	if updated == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Note not found")
+		return
+	}
+	writeSuccessResponse(w, updated)
+}
+
+// DeleteNote godoc
+//
+//	@Summary		Delete an analyst note
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Note ID"
+//	@Success		200			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/notes/{id} [delete]
+func (h *CaseNoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, ok := parseCaseNoteID(w, r)
+	if !ok {
+		return
+	}
+	found, err := h.service.DeleteNote(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeErrorResponse(w, http.StatusNotFound, "Note not found")
+		return
+	}
+	writeSuccessResponse(w, map[string]int64{"deleted": id})
+}
+
+func parseCaseNoteID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeCaseNote(w http.ResponseWriter, r *http.Request) (models.CaseNote, bool) {
+	var req caseNoteRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note body: "+err.Error())
+		return models.CaseNote{}, false
+	}
+	note, err := req.note()
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note body: "+err.Error())
+		return models.CaseNote{}, false
+	}
+	return note, true
+}
+
+func writeCaseNoteError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidCaseNote) {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCaseNoteService struct {
+	mock.Mock
+}
+
+func (m *MockCaseNoteService) ListNotes(ctx context.Context, filter models.CaseNoteFilter) ([]models.CaseNote, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.CaseNote), args.Error(1)
+}
+
+func (m *MockCaseNoteService) CreateNote(ctx context.Context, note models.CaseNote) (*models.CaseNote, error) {
+	args := m.Called(note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CaseNote), args.Error(1)
+}
+
+func (m *MockCaseNoteService) UpdateNote(ctx context.Context, id int64, note models.CaseNote) (*models.CaseNote, error) {
+	args := m.Called(id, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CaseNote), args.Error(1)
+}
+
+func (m *MockCaseNoteService) DeleteNote(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCaseNoteService) AnnotateNationalCases(ctx context.Context, cases []models.NationalCase) ([]models.NationalCase, error) {
+	args := m.Called(cases)
+	return args.Get(0).([]models.NationalCase), args.Error(1)
+}
+
+func (m *MockCaseNoteService) AnnotateProvinceCases(ctx context.Context, cases []models.ProvinceCaseWithDate) ([]models.ProvinceCaseWithDate, error) {
+	args := m.Called(cases)
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+}
+
+func serveCaseNotes(svc *MockCaseNoteService, method, path, body string) *httptest.ResponseRecorder {
+	router := SetupRoutes(Services{CaseNoteService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCaseNoteHandler_CreateNote(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockCaseNoteService)
+	date := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	note := models.CaseNote{ProvinceID: "72", Date: date, Text: models.NoteText{ID: "Laporan tertunda", EN: "Backlog reported this day"}}
+	created := note
+	created.ID = 3
+	svc.On("CreateNote", note).Return(&created, nil)
+
+	w := serveCaseNotes(svc, http.MethodPost, "/admin/notes",
+		`{"province_id":"72","date":"2020-07-01","text":{"id":"Laporan tertunda","en":"Backlog reported this day"}}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":3`)
+	svc.AssertExpectations(t)
+}
+
+func TestCaseNoteHandler_CreateNote_Invalid(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockCaseNoteService)
+	svc.On("CreateNote", mock.Anything).Return(nil, service.ErrInvalidCaseNote)
+
+	for _, body := range []string{
+		`{"date":"01/07/2020","text":{"en":"Backlog"}}`,
+		`{"date":"2020-07-01","text":{"en":"Backlog"},"lang":"en"}`,
+		`{"date":"2020-07-01"}`,
+	} {
+		w := serveCaseNotes(svc, http.MethodPost, "/admin/notes", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	svc.AssertNumberOfCalls(t, "CreateNote", 1)
+}
+
+func TestCaseNoteHandler_UpdateNote_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockCaseNoteService)
+	svc.On("UpdateNote", int64(9), mock.Anything).Return(nil, nil)
+
+	w := serveCaseNotes(svc, http.MethodPut, "/admin/notes/9", `{"date":"2020-07-01","text":{"en":"Backlog"}}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCaseNoteHandler_ListNotes(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockCaseNoteService)
+	svc.On("ListNotes", models.CaseNoteFilter{
+		Scope:     models.NoteScopeNational,
+		StartDate: time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+	}).Return([]models.CaseNote{}, nil)
+
+	w := serveCaseNotes(svc, http.MethodGet, "/admin/notes?province_id=national&start_date=2020-07-01", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestCaseNoteHandler_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockCaseNoteService)
+	router := SetupRoutes(Services{CaseNoteService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/notes/1", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	svc.AssertNotCalled(t, "DeleteNote", mock.Anything)
+}
+
+func TestCovidHandler_GetNationalCases_Notes(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	notes := new(MockCaseNoteService)
+	handler.notes = notes
+	cases := []models.NationalCase{{Day: 7, Positive: 70}}
+	annotated := []models.NationalCase{cases[0]}
+	annotated[0].Notes = []models.CaseNote{{ID: 1, Text: models.NoteText{EN: "Backlog reported this day"}}}
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(cases, 1, nil)
+	notes.On("AnnotateNationalCases", cases).Return(annotated, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national", nil))
+	assert.NotContains(t, rr.Body.String(), `"notes":`, "notes are only added on request")
+
+	rr = httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?include=notes", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"notes":[{"id":1,`)
+	assert.Contains(t, rr.Body.String(), `"text":{"en":"Backlog reported this day"}`)
+	notes.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// CaseRevisionHandler serves the corrections of published case figures.
+type CaseRevisionHandler struct {
+	service service.CaseRevisionServiceInterface
+}
+
+// NewCaseRevisionHandler creates a new CaseRevisionHandler.
+func NewCaseRevisionHandler(service service.CaseRevisionServiceInterface) *CaseRevisionHandler {
+	return &CaseRevisionHandler{service: service}
+}
+
+// GetNationalRevisions godoc
+//
+//	@Summary		Revisions of a national case
+//	@Description	Lists every write to the national case of a day, oldest first: its first publication (insert) and each later correction (update), with the changed fields, when they changed and the source of the run. Revisions of rolled back runs have rolled_back_at, from when their changes were undone.
+//	@Tags			national
+//	@Produce		json
+//	@Param			day	path		int	true	"Day number"
+//	@Success		200	{object}	Response{data=models.CaseRevisionHistory}
+//	@Failure		400	{object}	Response
+//	@Failure		404	{object}	Response
+//	@Failure		500	{object}	Response
+//	@Router			/national/{day}/revisions [get]
+func (h *CaseRevisionHandler) GetNationalRevisions(w http.ResponseWriter, r *http.Request) {
+	day, err := strconv.ParseInt(mux.Vars(r)["day"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid day parameter")
+		return
+	}
+
+	history, err := h.service.GetNationalRevisions(r.Context(), day)
+	if errors.Is(err, service.ErrCaseNotFound) {
+		writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Data untuk hari ke-%d tidak ditemukan", day))
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading case revisions: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load case revisions")
+		return
+	}
+	writeSuccessResponse(w, history)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCaseRevisionService struct {
+	mock.Mock
+}
+
+func (m *MockCaseRevisionService) GetNationalRevisions(ctx context.Context, day int64) (*models.CaseRevisionHistory, error) {
+	args := m.Called(day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CaseRevisionHistory), args.Error(1)
+}
+
+func TestCaseRevisionHandler_GetNationalRevisions(t *testing.T) {
+	svc := new(MockCaseRevisionService)
+	runID := int64(97)
+	svc.On("GetNationalRevisions", int64(500)).Return(&models.CaseRevisionHistory{
+		Day:  500,
+		Date: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+		Revisions: []models.CaseRevision{{
+			ID: 812, SyncRunID: &runID, Source: "rt-estimation", Action: "update",
+			Changes:   []models.FieldChange{{Field: "rt", After: 1.12}},
+			ChangedAt: time.Date(2021, 8, 2, 6, 0, 0, 0, time.UTC),
+		}},
+	}, nil)
+	router := SetupRoutes(Services{CaseRevisionService: svc}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/national/500/revisions", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"sync_run_id":97,"source":"rt-estimation","action":"update"`)
+	assert.Contains(t, rr.Body.String(), `"changes":[{"field":"rt","before":null,"after":1.12}]`)
+	assert.NotContains(t, rr.Body.String(), "rolled_back_at")
+	svc.AssertExpectations(t)
+}
+
+func TestCaseRevisionHandler_GetNationalRevisions_Errors(t *testing.T) {
+	svc := new(MockCaseRevisionService)
+	svc.On("GetNationalRevisions", int64(9999)).Return(nil, service.ErrCaseNotFound)
+	svc.On("GetNationalRevisions", int64(1)).Return(nil, errors.New("db down"))
+	router := SetupRoutes(Services{CaseRevisionService: svc}, nil, false)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/v1/national/abc/revisions", http.StatusBadRequest},
+		{"/api/v1/national/9999/revisions", http.StatusNotFound},
+		{"/api/v1/national/1/revisions", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", tt.path, nil))
+		assert.Equal(t, tt.status, rr.Code, tt.path)
+	}
+}
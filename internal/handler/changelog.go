@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Types of API changes
+const (
+	changeAdded      = "added"
+	changeChanged    = "changed"
+	changeDeprecated = "deprecated"
+	changeRemoved    = "removed"
+)
+
+// unreleasedVersion is the version of the changes merged since apiVersion
+const unreleasedVersion = "2.10.0"
+
+// APIChange is an entry of the public API changelog: an endpoint, or one of
+// its parameters, added, changed, deprecated or removed in a version
+type APIChange struct {
+	Version string `json:"version" example:"2.10.0"`
+	Type    string `json:"type" example:"added"`
+	Method  string `json:"method" example:"GET"`
+	// Path is the route template, e.g. /api/v1/provinces/{provinceId}/cases
+	Path string `json:"path"`
+	// Parameter is set when the change is about a query parameter
+	Parameter   string `json:"parameter,omitempty"`
+	Description string `json:"description"`
+	// Replacement is what to use instead of a deprecated or removed endpoint
+	// or parameter
+	Replacement string `json:"replacement,omitempty"`
+	// Sunset is the planned removal date (YYYY-MM-DD) of a deprecation
+	Sunset string `json:"sunset,omitempty"`
+}
+
+// APIChangelog is the changelog of the public API, newest version first
+type APIChangelog struct {
+	CurrentVersion string      `json:"current_version"`
+	Changes        []APIChange `json:"changes"`
+}
+
+// apiChanges records every change of the public API. Add an entry with the
+// route when adding, changing, deprecating or removing an endpoint or
+// parameter; a test checks that the paths exist. Deprecated entries also make
+// matching requests carry Deprecation, Sunset and Link headers.
+var apiChanges = []APIChange{
+	{Version: "2.9.0", Type: changeChanged, Method: "GET", Path: "/api/v1/vaccination/national", Description: "Restructured response with the doses grouped per round and the vaccination coverage percentage"},
+	{Version: "2.9.0", Type: changeChanged, Method: "GET", Path: "/api/v1/vaccination/province", Description: "Restructured response with the doses grouped per round and the vaccination coverage percentage"},
+	{Version: "2.9.0", Type: changeChanged, Method: "GET", Path: "/api/v1/stats/gender", Description: "Restructured response grouping the counts per gender"},
+
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/changes", Description: "This changelog of the public API"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/.well-known/api-descriptor", Description: "Machine-readable API capabilities and limits"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/tenant", Description: "Tenant serving the request, selected by X-Tenant or subdomain"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/healthz", Description: "Liveness probe"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/readyz", Description: "Readiness probe checking the database, cache warm-up and migrations"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "POST", Path: "/api/v1/national", Description: "Submit national cases with an API key"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "POST", Path: "/api/v1/provinces/{provinceId}/cases", Description: "Submit province cases with an API key; an existing day answers 409"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national/compare-periods", Description: "Compare the national totals of two periods"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national/tests", Description: "National test counts and positivity rates"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national/vaccinations", Description: "National first, second and booster doses with case-style pagination and sorting"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national/{day}/revisions", Description: "Correction history of a national case"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/latest", Description: "Latest case of selected provinces in one request"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/vaccinations", Description: "First, second and booster doses of a province"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/per-capita", Description: "Cases, deaths and dose coverage of a province per 100k people"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/metrics", Description: "Latest, minimum, maximum and average of every metric for a province"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/calendar", Description: "A metric for every calendar day of a year"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/districts", Description: "Districts of a province"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/districts/{districtId}/cases", Description: "Cases of a district"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/metrics", Description: "Statistics accepted by rankings, province sorting and province metrics"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/rankings", Description: "Provinces ranked by a metric of their latest case"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/summary", Description: "National and province totals with moving averages"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/recap/{year}", Description: "Yearly recap"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/status", Description: "Status classification of each province"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/data-quality/issues", Description: "Negative daily counts, cumulative regressions and outliers in the case data"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/system-status", Description: "API status page with incidents and uptime"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/reports/daily", Description: "Daily PDF situational report"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/export/xlsx", Description: "Excel workbook of the case data"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "POST", Path: "/api/v1/exports", Description: "Queue an export job"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/exports/{id}", Description: "Progress of an export job"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/exports/{id}/download", Description: "File of a completed export job"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/embed/summary.html", Description: "Embeddable HTML summary card"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/og/daily.png", Description: "Open Graph preview image of a day"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "POST", Path: "/api/v1/share", Description: "Store a filtered view under a short code"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/s/{code}", Description: "Open a share link"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "POST", Path: "/api/v1/subscriptions", Description: "Subscribe to daily email updates with double opt-in"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "POST", Path: "/api/v1/webhooks", Description: "Register a webhook for new case data"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/stream/cases", Description: "Server-Sent Events stream of new case data"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/ws", Description: "WebSocket dashboard updates"},
+
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "format", Description: "format=csv, or Accept: text/csv, returns CSV"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "range", Description: "Range presets such as last30d instead of start_date/end_date"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "interval", Description: "Weekly or monthly aggregation"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "smoothing", Description: "Moving averages of the daily counts"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "shape", Description: "Nested, flat or legacy response shape"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "legacy", Description: "Pre-2.0 Indonesian field names, same as shape=legacy"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "fields", Description: "Sparse fieldsets: only the listed JSON fields of each row"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/cases", Parameter: "fields", Description: "Sparse fieldsets: only the listed JSON fields of each row"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "include", Description: "include=timestamps adds created_at/updated_at, include=notes the analyst notes"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "date", Description: "Single-day lookup"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/national", Parameter: "as_of", Description: "The data as it was at a past time"},
+	{Version: unreleasedVersion, Type: changeChanged, Method: "GET", Path: "/api/v1/national", Parameter: "sort", Description: "Also sorts by Rt and the cumulative and ODP/PDP fields"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/cases", Parameter: "cursor", Description: "Keyset pagination"},
+	{Version: unreleasedVersion, Type: changeAdded, Method: "GET", Path: "/api/v1/provinces/{provinceId}/cases", Parameter: "has_rt", Description: "Only cases with, or without, an Rt estimate"},
+	{Version: unreleasedVersion, Type: changeChanged, Method: "GET", Path: "/api/v1/national", Description: "Responses carry meta.freshness and meta.notices, and flagged rows a quality block"},
+	{Version: unreleasedVersion, Type: changeChanged, Method: "GET", Path: "/api/v1/national", Parameter: "all", Description: "JSON is streamed, with the row count in the X-Result-Count trailer"},
+	{Version: unreleasedVersion, Type: changeChanged, Method: "GET", Path: "/api/v1/provinces/{provinceId}/cases", Parameter: "all", Description: "JSON is streamed, with the row count in the X-Result-Count trailer"},
+}
+
+// GetChanges godoc
+//
+//	@Summary		API changelog
+//	@Description	Endpoints and parameters added, changed, deprecated or removed per API version, newest first, so clients can find out what changed since the version they were built against
+//	@Tags			health
+//	@Produce		json
+//	@Param			since	query		string	false	"Only changes of versions after this one, e.g. 2.9.0"
+//	@Param			type	query		string	false	"added, changed, deprecated or removed"
+//	@Param			path	query		string	false	"Only changes of this route, e.g. /api/v1/national"
+//	@Success		200		{object}	Response{data=APIChangelog}
+//	@Failure		400		{object}	Response
+//	@Router			/changes [get]
+func GetChanges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var since []int
+	if v := query.Get("since"); v != "" {
+		parsed, err := parseVersion(v)
+		if err != nil {
+			writeValidationError(w, &ValidationError{Code: ErrCodeInvalidFilter, Field: "since", Message: err.Error()})
+			return
+		}
+		since = parsed
+	}
+	changeType := query.Get("type")
+	switch changeType {
+	case "", changeAdded, changeChanged, changeDeprecated, changeRemoved:
+	default:
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "type",
+			Message: fmt.Sprintf("Invalid type %q. Use %s, %s, %s or %s", changeType, changeAdded, changeChanged, changeDeprecated, changeRemoved),
+		})
+		return
+	}
+
+	writeSuccessResponse(w, APIChangelog{
+		CurrentVersion: apiVersion,
+		Changes:        filterChanges(apiChanges, since, changeType, query.Get("path")),
+	})
+}
+
+// filterChanges returns the matching changes, newest version first and in
+// recorded order within a version
+func filterChanges(changes []APIChange, since []int, changeType, path string) []APIChange {
+	matched := []APIChange{}
+	for _, c := range changes {
+		if since != nil && compareVersions(mustParseVersion(c.Version), since) <= 0 {
+			continue
+		}
+		if (changeType != "" && c.Type != changeType) || (path != "" && c.Path != path) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return compareVersions(mustParseVersion(matched[i].Version), mustParseVersion(matched[j].Version)) > 0
+	})
+	return matched
+}
+
+// parseVersion parses a MAJOR.MINOR.PATCH version, with an optional v prefix
+func parseVersion(v string) ([]int, error) {
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid version %q, use MAJOR.MINOR.PATCH", v)
+	}
+	version := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q, use MAJOR.MINOR.PATCH", v)
+		}
+		version[i] = n
+	}
+	return version, nil
+}
+
+// mustParseVersion parses the versions of apiChanges, which a test keeps valid
+func mustParseVersion(v string) []int {
+	version, err := parseVersion(v)
+	if err != nil {
+		panic(err)
+	}
+	return version
+}
+
+func compareVersions(a, b []int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
+
+// deprecationHeaders marks responses of deprecated endpoints, and of requests
+// using a deprecated parameter, with the Deprecation header, plus Sunset and a
+// successor-version Link when the changelog has them
+func deprecationHeaders(changes []APIChange) mux.MiddlewareFunc {
+	deprecated := map[string][]APIChange{}
+	for _, c := range changes {
+		if c.Type == changeDeprecated {
+			key := c.Method + " " + c.Path
+			deprecated[key] = append(deprecated[key], c)
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil || len(deprecated) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			path, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, c := range deprecated[r.Method+" "+path] {
+				if c.Parameter != "" && !r.URL.Query().Has(c.Parameter) {
+					continue
+				}
+				w.Header().Set("Deprecation", "true")
+				if sunset, err := time.Parse("2006-01-02", c.Sunset); err == nil {
+					w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+				}
+				if c.Replacement != "" {
+					w.Header().Add("Link", "<"+c.Replacement+`>; rel="successor-version"`)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
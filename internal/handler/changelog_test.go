@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getChanges(t *testing.T, router http.Handler, target string) (int, APIChangelog) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+	var response struct {
+		Data APIChangelog `json:"data"`
+	}
+	if rr.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	}
+	return rr.Code, response.Data
+}
+
+func TestGetChanges(t *testing.T) {
+	router := SetupRoutes(Services{}, nil, false)
+
+	t.Run("all changes newest first", func(t *testing.T) {
+		code, changelog := getChanges(t, router, "/api/v1/changes")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, apiVersion, changelog.CurrentVersion)
+		require.Len(t, changelog.Changes, len(apiChanges))
+		assert.Equal(t, unreleasedVersion, changelog.Changes[0].Version)
+		assert.Equal(t, "2.9.0", changelog.Changes[len(changelog.Changes)-1].Version)
+	})
+
+	t.Run("since a version", func(t *testing.T) {
+		code, changelog := getChanges(t, router, "/api/v1/changes?since=v2.9.0")
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, changelog.Changes)
+		for _, c := range changelog.Changes {
+			assert.Equal(t, unreleasedVersion, c.Version)
+		}
+	})
+
+	t.Run("by type and path", func(t *testing.T) {
+		code, changelog := getChanges(t, router, "/api/v1/changes?type=changed&path=/api/v1/national")
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, changelog.Changes)
+		for _, c := range changelog.Changes {
+			assert.Equal(t, changeChanged, c.Type)
+			assert.Equal(t, "/api/v1/national", c.Path)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		code, changelog := getChanges(t, router, "/api/v1/changes?since=99.0.0")
+		require.Equal(t, http.StatusOK, code)
+		assert.NotNil(t, changelog.Changes)
+		assert.Empty(t, changelog.Changes)
+	})
+
+	for _, target := range []string{"/api/v1/changes?since=2.9", "/api/v1/changes?since=latest", "/api/v1/changes?type=fixed"} {
+		code, _ := getChanges(t, router, target)
+		assert.Equal(t, http.StatusBadRequest, code, target)
+	}
+}
+
+// Every changelog entry of an endpoint that still exists must name a route
+// the router serves, so a renamed route cannot leave the changelog stale
+func TestAPIChanges_MatchRoutes(t *testing.T) {
+	router := SetupRoutes(Services{
+		CovidService:         new(MockCovidService),
+		RegencyService:       new(MockRegencyService),
+		VaccinationService:   service.NewVaccinationService(nil),
+		ProvinceStatsService: new(MockProvinceStatsService),
+		CaseRevisionService:  new(MockCaseRevisionService),
+		WebhookService:       new(MockWebhookService),
+		IngestionService:     new(MockIngestionService),
+		SubscriptionService:  new(MockSubscriptionService),
+		ShareService:         new(MockShareService),
+		SummaryService:       new(MockSummaryService),
+		RecapService:         new(MockRecapService),
+		ReportService:        new(MockReportService),
+		StatusService:        new(MockStatusService),
+		APIStatusService:     new(MockAPIStatusService),
+		TestingService:       new(MockTestingService),
+		PopulationService:    new(MockPopulationService),
+		CaseStream:           service.NewCaseStream(),
+		DashboardHub:         service.NewDashboardHub(nil, service.DashboardHubLimits{}),
+		ExportService:        new(MockExportService),
+		DataQuality:          new(MockDataQualityReporter),
+		Readiness:            new(MockReadinessChecker),
+	}, nil, false)
+
+	routes := map[string]bool{}
+	require.NoError(t, router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			routes[method+" "+path] = true
+		}
+		return nil
+	}))
+
+	for _, c := range apiChanges {
+		_, err := parseVersion(c.Version)
+		assert.NoError(t, err, "%s %s", c.Method, c.Path)
+		assert.Contains(t, []string{changeAdded, changeChanged, changeDeprecated, changeRemoved}, c.Type)
+		assert.NotEmpty(t, c.Description, "%s %s", c.Method, c.Path)
+		if c.Type == changeRemoved && c.Parameter == "" {
+			continue
+		}
+		assert.True(t, routes[c.Method+" "+c.Path], "changelog names unknown route %s %s", c.Method, c.Path)
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	changes := []APIChange{
+		{Version: "2.10.0", Type: changeDeprecated, Method: "GET", Path: "/old", Description: "Old endpoint", Replacement: "/api/v1/new", Sunset: "2027-01-31"},
+		{Version: "2.10.0", Type: changeDeprecated, Method: "GET", Path: "/items/{id}", Parameter: "legacy", Description: "Legacy field names"},
+	}
+	router := mux.NewRouter()
+	router.Use(deprecationHeaders(changes))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/old", ok).Methods("GET")
+	router.HandleFunc("/items/{id}", ok).Methods("GET")
+
+	serve := func(target string) http.Header {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Header()
+	}
+
+	header := serve("/old")
+	assert.Equal(t, "true", header.Get("Deprecation"))
+	assert.Equal(t, "Sun, 31 Jan 2027 00:00:00 GMT", header.Get("Sunset"))
+	assert.Equal(t, `</api/v1/new>; rel="successor-version"`, header.Get("Link"))
+
+	header = serve("/items/7?legacy=true")
+	assert.Equal(t, "true", header.Get("Deprecation"))
+	assert.Empty(t, header.Get("Sunset"))
+	assert.Empty(t, header.Get("Link"))
+
+	assert.Empty(t, serve("/items/7").Get("Deprecation"))
+}
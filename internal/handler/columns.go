@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// nationalColumns returns the national case columns the response to r needs,
+// nil for every column. It writes a 400 for an unknown shape or field and
+// reports whether the request may go on.
+func nationalColumns(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	if wantsCSV(r) {
+		return nil, true
+	}
+	view, ok := caseViewOf(w, r, models.NationalCaseResponse{})
+	if !ok {
+		return nil, false
+	}
+	return caseColumns(view, func(c models.NationalCase) interface{} {
+		return c.TransformToResponse()
+	}), true
+}
+
+// provinceColumns is nationalColumns for province cases
+func provinceColumns(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	if wantsCSV(r) {
+		return nil, true
+	}
+	view, ok := caseViewOf(w, r, models.ProvinceCaseResponse{})
+	if !ok {
+		return nil, false
+	}
+	return caseColumns(view, func(c models.ProvinceCaseWithDate) interface{} {
+		return c.TransformToResponse()
+	}), true
+}
+
+// maxCachedColumnSets bounds the column sets caseColumns keeps; ?fields=
+// lists are chosen by clients, so the cache starts over when it is full
+const maxCachedColumnSets = 1024
+
+// columnSetKey identifies the rows and view a column set was found for
+type columnSetKey struct {
+	row    reflect.Type
+	shape  string
+	fields string
+}
+
+var columnSets = struct {
+	sync.Mutex
+	m map[columnSetKey][]string
+}{m: make(map[columnSetKey][]string)}
+
+// caseColumns returns the db columns of case rows of type M that change the
+// rows written in the view, so queries for a ?fields= selection read only
+// those. respond converts a row to its response and must be the same for
+// every call with rows of type M. The columns are found once per row type,
+// shape and field selection by findCaseColumns. It returns nil, for every
+// column, when the view keeps every field or selects the quality flags, which
+// are found from the whole row.
+func caseColumns[M any](view caseView, respond func(M) interface{}) []string {
+	if view.fields.IsZero() {
+		return nil
+	}
+
+	key := columnSetKey{row: reflect.TypeOf((*M)(nil)).Elem(), shape: view.shape, fields: view.fields.String()}
+	columnSets.Lock()
+	columns, ok := columnSets.m[key]
+	columnSets.Unlock()
+	if ok {
+		return columns
+	}
+
+	// Callers share the cached slice, so appending to it must copy
+	columns = slices.Clip(findCaseColumns(view, respond))
+	columnSets.Lock()
+	if len(columnSets.m) >= maxCachedColumnSets {
+		columnSets.m = make(map[columnSetKey][]string)
+	}
+	columnSets.m[key] = columns
+	columnSets.Unlock()
+	return columns
+}
+
+// findCaseColumns changes each column of a sample row in turn and compares
+// the written rows, which keeps the columns of caseColumns right for every
+// shape and derived field
+func findCaseColumns[M any](view caseView, respond func(M) interface{}) []string {
+	var sample M
+	row := reflect.ValueOf(&sample).Elem()
+	fields := columnFields(row.Type())
+	for i, f := range fields {
+		setSampleValue(row.FieldByIndex(f.index), i)
+	}
+	if tests := row.FieldByName("Tests"); tests.IsValid() {
+		tests.Set(reflect.ValueOf(&models.DailyTests{PCR: 1000, Antigen: 500, CumulativePCR: 90000, CumulativeAntigen: 40000}))
+	}
+	write := func(row M) []byte {
+		data, _ := json.Marshal(view.render(respond(row)))
+		return data
+	}
+	written := write(sample)
+
+	if quality := row.FieldByName("Quality"); quality.IsValid() {
+		flagged := sample
+		reflect.ValueOf(&flagged).Elem().FieldByName("Quality").Set(reflect.ValueOf(&models.RecordQuality{
+			Flagged: true,
+			Issues:  []models.QualityFlag{{Type: "sample", Field: "positive", Message: "sample"}},
+		}))
+		if !bytes.Equal(write(flagged), written) {
+			return nil
+		}
+	}
+
+	var columns []string
+	for _, f := range fields {
+		changed := sample
+		if !changeSampleValue(reflect.ValueOf(&changed).Elem().FieldByIndex(f.index)) || !bytes.Equal(write(changed), written) {
+			columns = append(columns, f.column)
+		}
+	}
+	return columns
+}
+
+// columnField is a field of a row read from a db column
+type columnField struct {
+	column string
+	index  []int
+}
+
+// columnFields lists the fields of struct type t with a db tag, including
+// those of embedded structs
+func columnFields(t reflect.Type) []columnField {
+	var fields []columnField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			for _, f := range columnFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if name, _, _ := strings.Cut(sf.Tag.Get("db"), ","); name != "" && name != "-" {
+			fields = append(fields, columnField{column: name, index: []int{i}})
+		}
+	}
+	return fields
+}
+
+// sampleTime is the first time of the sample rows
+var sampleTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// setSampleValue sets the field of column i of a sample row to a value that
+// differs from the other columns, so derived fields such as active cases are
+// not zero
+func setSampleValue(v reflect.Value, i int) {
+	switch v.Kind() {
+	case reflect.Int64:
+		v.SetInt(int64(100 + 37*i))
+	case reflect.String:
+		v.SetString("s" + strconv.Itoa(i))
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		setSampleValue(v.Elem(), i)
+	case reflect.Float64:
+		v.SetFloat(1.5 + float64(i))
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(sampleTime.AddDate(0, 0, i)))
+		}
+	}
+}
+
+// changeSampleValue changes the field of a sample row, reporting false for
+// fields of a kind it cannot change
+func changeSampleValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int64:
+		v.SetInt(v.Int() + 1000)
+	case reflect.String:
+		v.SetString(v.String() + "x")
+	case reflect.Ptr:
+		if v.IsNil() {
+			return false
+		}
+		// The copy shares the pointer with the sample
+		changed := reflect.New(v.Type().Elem())
+		changed.Elem().Set(v.Elem())
+		v.Set(changed)
+		return changeSampleValue(v.Elem())
+	case reflect.Float64:
+		v.SetFloat(v.Float() + 1000)
+	case reflect.Struct:
+		if v.Type() != reflect.TypeOf(time.Time{}) {
+			return false
+		}
+		v.Set(reflect.ValueOf(v.Interface().(time.Time).AddDate(1, 0, 0)))
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNationalColumns(t *testing.T) {
+	tests := []struct {
+		target  string
+		columns []string
+	}{
+		{"/api/v1/national", nil},
+		{"/api/v1/national?shape=flat", nil},
+		{"/api/v1/national?fields=daily.active", []string{"positive", "recovered", "deceased"}},
+		{"/api/v1/national?fields=statistics.percentages.recovered", []string{"cumulative_positive", "cumulative_recovered"}},
+		{"/api/v1/national?fields=statistics.testing.positivity_rate", []string{"positive"}},
+		{"/api/v1/national?fields=statistics.reproduction_rate", []string{"rt", "rt_upper", "rt_lower"}},
+		{"/api/v1/national?fields=date,rt_upper_bound&shape=flat", []string{"date", "rt_upper"}},
+		{"/api/v1/national?fields=created_at&include=timestamps", []string{"created_at"}},
+		// Quality flags are found from the whole row
+		{"/api/v1/national?fields=day,quality", nil},
+		// CSV keeps every column
+		{"/api/v1/national?fields=day&format=csv", nil},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		columns, ok := nationalColumns(rr, httptest.NewRequest("GET", tt.target, nil))
+		require.True(t, ok, tt.target)
+		assert.Equal(t, tt.columns, columns, tt.target)
+	}
+
+	rr := httptest.NewRecorder()
+	_, ok := nationalColumns(rr, httptest.NewRequest("GET", "/api/v1/national?fields=kasus", nil))
+	assert.False(t, ok)
+	assert.Equal(t, 400, rr.Code)
+}
+
+func TestProvinceColumns(t *testing.T) {
+	tests := []struct {
+		target  string
+		columns []string
+	}{
+		{"/api/v1/provinces/72/cases?fields=daily.odp.active", []string{"person_under_observation", "finished_person_under_observation"}},
+		{"/api/v1/provinces/72/cases?fields=cumulative.pdp", []string{"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision"}},
+		{"/api/v1/provinces/72/cases?fields=province,day", []string{"day"}},
+		{"/api/v1/provinces/72/cases?fields=date", []string{"date"}},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		columns, ok := provinceColumns(rr, httptest.NewRequest("GET", tt.target, nil))
+		require.True(t, ok, tt.target)
+		assert.Equal(t, tt.columns, columns, tt.target)
+	}
+}
+
+// The columns are found once per row type, shape and field selection
+func TestNationalColumns_Cached(t *testing.T) {
+	first, ok := nationalColumns(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/national?fields=date,rt_upper_bound&shape=flat", nil))
+	require.True(t, ok)
+	key := columnSetKey{row: reflect.TypeOf(models.NationalCase{}), shape: shapeFlat, fields: "date,rt_upper_bound"}
+	columnSets.Lock()
+	cached, found := columnSets.m[key]
+	columnSets.Unlock()
+	require.True(t, found)
+	assert.Equal(t, first, cached)
+
+	again, ok := nationalColumns(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/national?fields=rt_upper_bound,date&shape=flat", nil))
+	require.True(t, ok)
+	assert.Equal(t, []string{"date", "rt_upper"}, again)
+	assert.Equal(t, len(again), cap(again))
+}
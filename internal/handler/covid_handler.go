@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/banua-coder/pico-api-go/internal/metrics"
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/banua-coder/pico-api-go/internal/service"
 	"github.com/banua-coder/pico-api-go/pkg/database"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
@@ -16,6 +21,13 @@ import (
 type CovidHandler struct {
 	covidService service.CovidService
 	db           *database.DB
+	tableStats   service.TableStatsProvider
+	// population adds per capita statistics to province lists when set
+	population service.PopulationServiceInterface
+	// quality adds the quality block of flagged rows when set
+	quality service.DataQualityReporter
+	// notes adds the analyst notes of the rows' dates when set
+	notes service.CaseNoteServiceInterface
 }
 
 func NewCovidHandler(covidService service.CovidService, db *database.DB) *CovidHandler {
@@ -32,16 +44,30 @@ func NewCovidHandler(covidService service.CovidService, db *database.DB) *CovidH
 // @Tags national
 // @Accept json
 // @Produce json
+// @Produce text/csv
 // @Param limit query integer false "Records per page (default: 50, max: 1000)"
 // @Param offset query integer false "Records to skip (default: 0)"
 // @Param page query integer false "Page number (1-based, alternative to offset)"
 // @Param all query boolean false "Return all data without pagination"
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
-// @Param sort query string false "Sort by field:order (e.g., date:desc, positive:asc). Default: date:asc"
+// @Param range query string false "Date range preset ending on the latest data date: last7d, last14d, last30d, last90d, ytd or all. Cannot be combined with start_date/end_date"
+// @Param date query string false "Return only the record of this day (YYYY-MM-DD), 404 when there is none. Cannot be combined with start_date, end_date, range or interval"
+// @Param as_of query string false "Return the data as it was published at this RFC 3339 time (e.g. 2021-08-01T00:00:00Z), without later corrections. Daily interval and sort=date only"
+// @Param interval query string false "daily (default), weekly (ISO weeks) or monthly. Weekly and monthly return every period unpaginated with summed daily counts, the period's highest cumulative counts and its average Rt"
+// @Param smoothing query integer false "Adds statistics.moving_average, the average daily positive, recovered and deceased over this many days (1-90) ending on each date. Days without data are left out of the average. Daily interval only"
+// @Param sort query string false "Sort by field:order (e.g., date:desc, positive:asc, rt:desc). Fields: date, day, positive, recovered, deceased, active, cumulative_positive, cumulative_recovered, cumulative_deceased, rt. Default: date:asc"
+// @Param format query string false "Response format: json (default) or csv. Accept: text/csv also selects CSV"
+// @Param shape query string false "JSON shape: nested (default), flat, with prefixed keys such as daily_positive and rt_value, or legacy"
+// @Param fields query string false "Comma-separated JSON fields to keep, as dot paths of the requested shape such as day,daily.positive; an object keeps all of it. Default: every field"
+// @Param include query string false "Comma-separated: timestamps adds the created_at and updated_at of each record, notes the analyst notes of its date"
+// @Param legacy query bool false "Serialize the pre-2.0 schema with Indonesian keys (kasus, sembuh, meninggal); same as shape=legacy"
 // @Success 200 {object} Response{data=models.PaginatedResponse{data=[]models.NationalCaseResponse}} "Paginated response"
 // @Success 200 {object} Response{data=[]models.NationalCaseResponse} "All data response when all=true"
+// @Success 200 {object} Response{data=[]models.AggregatedCaseResponse} "Aggregated response when interval is weekly or monthly"
+// @Success 200 {object} Response{data=models.NationalCaseResponse} "Single record when date is set"
 // @Failure 400 {object} Response
+// @Failure 404 {object} Response "No record on date"
 // @Failure 429 {object} Response "Rate limit exceeded"
 // @Failure 500 {object} Response
 // @Header 200 {string} X-RateLimit-Limit "Request limit per window"
@@ -50,71 +76,89 @@ func NewCovidHandler(covidService service.CovidService, db *database.DB) *CovidH
 // @Header 429 {string} Retry-After "Seconds to wait before retrying"
 // @Router /national [get]
 func (h *CovidHandler) GetNationalCases(w http.ResponseWriter, r *http.Request) {
+	date, ok := singleDate(w, r)
+	if !ok {
+		return
+	}
+	if !date.IsZero() {
+		h.getNationalCaseOnDate(w, r, date)
+		return
+	}
+
 	// Parse query parameters
 	limit := utils.ParseIntQueryParam(r, "limit", 50)
 	offset := utils.ParseIntQueryParam(r, "offset", 0)
 	all := utils.ParseBoolQueryParam(r, "all")
 	startDate := r.URL.Query().Get("start_date")
 	endDate := r.URL.Query().Get("end_date")
+	if !h.resolveRangePreset(w, r, &startDate, &endDate) {
+		return
+	}
+	dates, ok := parseDateRange(w, startDate, endDate)
+	if !ok {
+		return
+	}
 
 	// Parse sort parameters (default: date ascending)
 	sortParams := utils.ParseSortParam(r, "date")
-
-	// Validate pagination params
-	limit, offset = utils.ValidatePaginationParams(limit, offset)
-
-	if all {
-		// Return all data without pagination
-		if startDate != "" && endDate != "" {
-			cases, err := h.covidService.GetNationalCasesByDateRangeSorted(startDate, endDate, sortParams)
-			if err != nil {
-				writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-				return
-			}
-			responseData := models.TransformSliceToResponse(cases)
-			writeSuccessResponse(w, responseData)
-			return
-		}
-
-		cases, err := h.covidService.GetNationalCasesSorted(sortParams)
-		if err != nil {
-			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		responseData := models.TransformSliceToResponse(cases)
-		writeSuccessResponse(w, responseData)
+	asOf, ok := asOfTime(w, r, sortParams)
+	if !ok {
 		return
 	}
 
-	// Return paginated data
-	if startDate != "" && endDate != "" {
-		cases, total, err := h.covidService.GetNationalCasesByDateRangePaginatedSorted(startDate, endDate, limit, offset, sortParams)
+	q, ok := aggregateQuery(w, r, dates, sortParams)
+	if !ok {
+		return
+	}
+	smoothing, ok := smoothingDays(w, r, q)
+	if !ok {
+		return
+	}
+	if q != nil {
+		cases, err := h.covidService.GetNationalCasesAggregated(r.Context(), *q)
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		responseData := models.TransformSliceToResponse(cases)
-		pagination := models.CalculatePaginationMeta(limit, offset, total)
-		paginatedResponse := models.PaginatedResponse{
-			Data:       responseData,
-			Pagination: pagination,
-		}
-		writeSuccessResponse(w, paginatedResponse)
+		responseData := models.TransformAggregatedCaseSliceToResponse(cases)
+		writeCaseList(w, r, fmt.Sprintf("national_cases_%s.csv", q.Interval), models.AggregatedCaseCSVHeader, responseData, nil)
 		return
 	}
 
-	cases, total, err := h.covidService.GetNationalCasesPaginatedSorted(limit, offset, sortParams)
+	// Validate pagination params
+	limit, offset = utils.ValidatePaginationParams(limit, offset)
+
+	columns, ok := nationalColumns(w, r)
+	if !ok {
+		return
+	}
+	opts := service.QueryOptions{Range: dates, Sort: sortParams, AsOf: asOf, Columns: columns}
+	if all && streamsAll(r, asOf, smoothing) {
+		streamCaseList(w, r, func(emit func([]models.NationalCaseResponse) error) error {
+			return h.covidService.EachNationalCase(r.Context(), opts, func(cases []models.NationalCase) error {
+				return emit(models.TransformSliceToResponse(h.annotateNational(r, cases)))
+			})
+		})
+		return
+	}
+	if !all {
+		opts.Page = service.Page{Limit: limit, Offset: offset}
+	}
+	cases, total, err := h.covidService.ListNationalCases(r.Context(), opts)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	responseData := models.TransformSliceToResponse(cases)
-	pagination := models.CalculatePaginationMeta(limit, offset, total)
-	paginatedResponse := models.PaginatedResponse{
-		Data:       responseData,
-		Pagination: pagination,
+	if cases, ok = h.addNationalMovingAverages(w, r, cases, smoothing); !ok {
+		return
+	}
+	responseData := models.TransformSliceToResponse(h.annotateNational(r, cases))
+	if all {
+		writeCaseList(w, r, "national_cases.csv", models.NationalCaseCSVHeader, responseData, nil)
+		return
 	}
-	writeSuccessResponse(w, paginatedResponse)
+	pagination := models.CalculatePaginationMeta(limit, offset, total)
+	writeCaseList(w, r, "national_cases.csv", models.NationalCaseCSVHeader, responseData, &pagination)
 }
 
 // GetLatestNationalCase godoc
@@ -124,12 +168,22 @@ func (h *CovidHandler) GetNationalCases(w http.ResponseWriter, r *http.Request)
 // @Tags national
 // @Accept json
 // @Produce json
+// @Param shape query string false "JSON shape: nested (default), flat, with prefixed keys such as daily_positive and rt_value, or legacy"
+// @Param fields query string false "Comma-separated JSON fields to keep, as dot paths of the requested shape such as day,daily.positive; an object keeps all of it. Default: every field"
+// @Param include query string false "Comma-separated: timestamps adds the created_at and updated_at of each record, notes the analyst notes of its date"
+// @Param legacy query bool false "Serialize the pre-2.0 schema with Indonesian keys (kasus, sembuh, meninggal); same as shape=legacy"
 // @Success 200 {object} Response{data=models.NationalCaseResponse}
+// @Failure 400 {object} Response
 // @Failure 404 {object} Response
 // @Failure 500 {object} Response
 // @Router /national/latest [get]
 func (h *CovidHandler) GetLatestNationalCase(w http.ResponseWriter, r *http.Request) {
-	nationalCase, err := h.covidService.GetLatestNationalCase()
+	view, ok := caseViewOf(w, r, models.NationalCaseResponse{})
+	if !ok {
+		return
+	}
+
+	nationalCase, err := h.covidService.GetLatestNationalCase(r.Context())
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -141,20 +195,70 @@ func (h *CovidHandler) GetLatestNationalCase(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Transform to new response structure
-	responseData := nationalCase.TransformToResponse()
-	writeSuccessResponse(w, responseData)
+	responseData := models.TransformSliceToResponse(h.annotateNational(r, []models.NationalCase{*nationalCase}))
+	stripTimestamps(r, responseData)
+	writeSuccessResponse(w, view.render(responseData[0]))
+}
+
+// CompareNationalPeriods godoc
+//
+// @Summary Compare two periods of national cases
+// @Description Totals the new national cases of two periods and the percentage change from period_a to period_b. A period is a month (2021-06), a day (2021-06-15) or a range (2021-06-01..2021-06-15). A change is null when the period_a value is zero.
+// @Tags national
+// @Accept json
+// @Produce json
+// @Param period_a query string true "First period, e.g. 2021-06"
+// @Param period_b query string true "Second period, e.g. 2021-07"
+// @Success 200 {object} Response{data=models.PeriodComparison}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /national/compare-periods [get]
+func (h *CovidHandler) CompareNationalPeriods(w http.ResponseWriter, r *http.Request) {
+	var periods [2]models.Period
+	for i, param := range []string{"period_a", "period_b"} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%s is required", param))
+			return
+		}
+		period, err := models.ParsePeriod(value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s: %v", param, err))
+			return
+		}
+		periods[i] = period
+	}
+
+	var totals [2]models.PeriodTotals
+	for i, period := range periods {
+		dates, ok := parseDateRange(w, period.StartDate, period.EndDate)
+		if !ok {
+			return
+		}
+		periodTotals, err := h.covidService.GetNationalTotals(r.Context(), dates)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		periodTotals.Period = period
+		totals[i] = periodTotals
+	}
+
+	writeSuccessResponse(w, models.ComparePeriods(totals[0], totals[1]))
 }
 
 // GetProvinces godoc
 //
 // @Summary Get provinces with COVID-19 data
-// @Description Retrieve all provinces with their latest COVID-19 case data by default. Use exclude_latest_case=true for basic province list only.
+// @Description Retrieve all provinces with their latest COVID-19 case data by default. Use exclude_latest_case=true for basic province list only. sort orders provinces by any metric of their latest case listed by /metrics.
 // @Tags provinces
 // @Accept json
 // @Produce json
 // @Param exclude_latest_case query boolean false "Exclude latest case data (default: false)"
+// @Param sort query string false "Sort by metric:order (e.g., case_fatality_rate:desc). Default: province ID"
 // @Success 200 {object} Response{data=[]models.ProvinceWithLatestCase} "Provinces with latest case data"
 // @Success 200 {object} Response{data=[]models.Province} "Basic province list when exclude_latest_case=true"
+// @Failure 400 {object} Response
 // @Failure 500 {object} Response
 // @Router /provinces [get]
 func (h *CovidHandler) GetProvinces(w http.ResponseWriter, r *http.Request) {
@@ -162,7 +266,7 @@ func (h *CovidHandler) GetProvinces(w http.ResponseWriter, r *http.Request) {
 	excludeLatestCase := r.URL.Query().Get("exclude_latest_case") == "true"
 
 	if excludeLatestCase {
-		provinces, err := h.covidService.GetProvinces()
+		provinces, err := h.covidService.GetProvinces(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -172,14 +276,90 @@ func (h *CovidHandler) GetProvinces(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Default behavior: include latest case data for COVID-19 context
-	provincesWithCases, err := h.covidService.GetProvincesWithLatestCase()
+	provincesWithCases, err := h.covidService.GetProvincesWithLatestCase(r.Context())
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if sortParam := r.URL.Query().Get("sort"); sortParam != "" {
+		provincesWithCases, err = sortProvincesByMetric(metrics.Default, provincesWithCases, sortParam)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if provincesWithCases, err = h.addPerCapita(r, provincesWithCases); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 	writeSuccessResponse(w, provincesWithCases)
 }
 
+// maxLatestProvinceIDs limits the provinces of one /provinces/latest request
+const maxLatestProvinceIDs = 50
+
+// GetLatestProvinceCasesByIDs godoc
+//
+// @Summary Get the latest case of selected provinces
+// @Description Retrieve the latest COVID-19 case data of the requested provinces in one query, in the order the IDs are given. Unknown IDs are left out.
+// @Tags provinces
+// @Accept json
+// @Produce json
+// @Param ids query string true "Comma separated province IDs (e.g., '72,31,73'), at most 50"
+// @Success 200 {object} Response{data=[]models.ProvinceWithLatestCase}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /provinces/latest [get]
+func (h *CovidHandler) GetLatestProvinceCasesByIDs(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		writeErrorResponse(w, http.StatusBadRequest, "ids is required, e.g. ids=72,31,73")
+		return
+	}
+	if len(ids) > maxLatestProvinceIDs {
+		writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("At most %d province IDs can be requested", maxLatestProvinceIDs))
+		return
+	}
+
+	provinces, err := h.covidService.GetProvincesWithLatestCaseByIDs(r.Context(), ids)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	byID := make(map[string]models.ProvinceWithLatestCase, len(provinces))
+	for _, p := range provinces {
+		byID[p.ID] = p
+	}
+	ordered := make([]models.ProvinceWithLatestCase, 0, len(provinces))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			ordered = append(ordered, p)
+		}
+	}
+	if ordered, err = h.addPerCapita(r, ordered); err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, ordered)
+}
+
+// addPerCapita adds the per capita statistics to provinces when a population
+// service is configured
+func (h *CovidHandler) addPerCapita(r *http.Request, provinces []models.ProvinceWithLatestCase) ([]models.ProvinceWithLatestCase, error) {
+	if h.population == nil {
+		return provinces, nil
+	}
+	return h.population.AddPerCapita(r.Context(), provinces)
+}
+
 // GetProvinceCases godoc
 //
 // @Summary Get province COVID-19 cases
@@ -187,6 +367,7 @@ func (h *CovidHandler) GetProvinces(w http.ResponseWriter, r *http.Request) {
 // @Tags province-cases
 // @Accept json
 // @Produce json
+// @Produce text/csv
 // @Param provinceId path string false "Province ID (e.g., '31' for Jakarta)"
 // @Param limit query integer false "Records per page (default: 50, max: 1000)"
 // @Param offset query integer false "Records to skip (default: 0)"
@@ -194,10 +375,25 @@ func (h *CovidHandler) GetProvinces(w http.ResponseWriter, r *http.Request) {
 // @Param all query boolean false "Return all data without pagination"
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
-// @Param sort query string false "Sort by field:order (e.g., date:desc, positive:asc). Default: date:asc"
+// @Param range query string false "Date range preset ending on the latest data date: last7d, last14d, last30d, last90d, ytd or all. Cannot be combined with start_date/end_date"
+// @Param date query string false "Return only the record of this day (YYYY-MM-DD), 404 when there is none. Cannot be combined with start_date, end_date, range or interval"
+// @Param as_of query string false "Return the data as it was published at this RFC 3339 time (e.g. 2021-08-01T00:00:00Z), without later corrections. Daily interval and sort=date only"
+// @Param interval query string false "daily (default), weekly (ISO weeks) or monthly. Weekly and monthly return every period of every province unpaginated with summed daily counts, the period's highest cumulative counts and its average Rt"
+// @Param smoothing query integer false "Adds statistics.moving_average, the average daily positive, recovered and deceased over this many days (1-90) ending on each date. Days without data are left out of the average. Daily interval only"
+// @Param sort query string false "Sort by field:order (e.g., date:desc, positive:asc, rt:desc). Fields: date, day, positive, recovered, deceased, active, cumulative_positive, cumulative_recovered, cumulative_deceased, rt, odp, odp_finished, pdp, pdp_finished, cumulative_odp, cumulative_odp_finished, cumulative_pdp, cumulative_pdp_finished, province_name. Default: date:asc"
+// @Param cursor query string false "Cursor pagination: empty for the first page, then pagination.next_cursor of the previous page. Only sort=date is supported"
+// @Param format query string false "Response format: json (default) or csv. Accept: text/csv also selects CSV"
+// @Param shape query string false "JSON shape: nested (default), flat, with prefixed keys such as daily_positive and rt_value, or legacy"
+// @Param fields query string false "Comma-separated JSON fields to keep, as dot paths of the requested shape such as day,daily.positive; an object keeps all of it. Default: every field"
+// @Param include query string false "Comma-separated: timestamps adds the created_at and updated_at of each record, notes the analyst notes of its date"
+// @Param legacy query bool false "Serialize the pre-2.0 schema with Indonesian keys (kasus, sembuh, meninggal); same as shape=legacy"
+// @Param has_rt query bool false "Only return rows that have an Rt value, skipping the early days without one. Daily interval only"
 // @Success 200 {object} Response{data=models.PaginatedResponse{data=[]models.ProvinceCaseResponse}} "Paginated response"
 // @Success 200 {object} Response{data=[]models.ProvinceCaseResponse} "All data response when all=true"
+// @Success 200 {object} Response{data=[]models.AggregatedCaseResponse} "Aggregated response when interval is weekly or monthly"
+// @Success 200 {object} Response{data=models.ProvinceCaseResponse} "Single record when date is set, province routes only"
 // @Failure 400 {object} Response
+// @Failure 404 {object} Response "No record on date"
 // @Failure 500 {object} Response
 // @Router /provinces/cases [get]
 // @Router /provinces/{provinceId}/cases [get]
@@ -205,6 +401,15 @@ func (h *CovidHandler) GetProvinceCases(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	provinceID := vars["provinceId"]
 
+	date, ok := singleDate(w, r)
+	if !ok {
+		return
+	}
+	if !date.IsZero() {
+		h.getProvinceCaseOnDate(w, r, provinceID, date)
+		return
+	}
+
 	// Parse query parameters
 	limit := utils.ParseIntQueryParam(r, "limit", 50)
 	offset := utils.ParseIntQueryParam(r, "offset", 0)
@@ -212,9 +417,20 @@ func (h *CovidHandler) GetProvinceCases(w http.ResponseWriter, r *http.Request)
 	all := utils.ParseBoolQueryParam(r, "all")
 	startDate := r.URL.Query().Get("start_date")
 	endDate := r.URL.Query().Get("end_date")
+	if !h.resolveRangePreset(w, r, &startDate, &endDate) {
+		return
+	}
+	dates, ok := parseDateRange(w, startDate, endDate)
+	if !ok {
+		return
+	}
 
 	// Parse sort parameters (default: date ascending)
 	sortParams := utils.ParseSortParam(r, "date")
+	asOf, ok := asOfTime(w, r, sortParams)
+	if !ok {
+		return
+	}
 
 	// Convert page to offset if page is specified (page-based pagination)
 	if page > 0 {
@@ -224,116 +440,414 @@ func (h *CovidHandler) GetProvinceCases(w http.ResponseWriter, r *http.Request)
 	// Validate pagination params
 	limit, offset = utils.ValidatePaginationParams(limit, offset)
 
-	if provinceID == "" {
-		// Handle all provinces cases
-		if all {
-			// Return all data without pagination
-			if startDate != "" && endDate != "" {
-				cases, err := h.covidService.GetAllProvinceCasesByDateRangeSorted(startDate, endDate, sortParams)
-				if err != nil {
-					writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-					return
-				}
-				responseData := models.TransformProvinceCaseSliceToResponse(cases)
-				writeSuccessResponse(w, responseData)
-				return
-			}
-
-			cases, err := h.covidService.GetAllProvinceCasesSorted(sortParams)
-			if err != nil {
-				writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-				return
-			}
-			responseData := models.TransformProvinceCaseSliceToResponse(cases)
-			writeSuccessResponse(w, responseData)
-			return
-		}
-
-		// Return paginated data
-		if startDate != "" && endDate != "" {
-			cases, total, err := h.covidService.GetAllProvinceCasesByDateRangePaginatedSorted(startDate, endDate, limit, offset, sortParams)
-			if err != nil {
-				writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-				return
-			}
-			responseData := models.TransformProvinceCaseSliceToResponse(cases)
-			pagination := models.CalculatePaginationMeta(limit, offset, total)
-			paginatedResponse := models.PaginatedResponse{
-				Data:       responseData,
-				Pagination: pagination,
-			}
-			writeSuccessResponse(w, paginatedResponse)
-			return
-		}
+	filename := "province_cases.csv"
+	if provinceID != "" {
+		filename = fmt.Sprintf("province_%s_cases.csv", provinceID)
+	}
 
-		cases, total, err := h.covidService.GetAllProvinceCasesPaginatedSorted(limit, offset, sortParams)
+	q, ok := aggregateQuery(w, r, dates, sortParams)
+	if !ok {
+		return
+	}
+	smoothing, ok := smoothingDays(w, r, q)
+	if !ok {
+		return
+	}
+	hasRt := utils.ParseBoolQueryParam(r, "has_rt")
+	if hasRt && q != nil {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "has_rt",
+			Message: "has_rt only applies to daily cases",
+		})
+		return
+	}
+	if q != nil {
+		q.ProvinceID = provinceID
+		cases, err := h.covidService.GetProvinceCasesAggregated(r.Context(), *q)
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		responseData := models.TransformProvinceCaseSliceToResponse(cases)
-		pagination := models.CalculatePaginationMeta(limit, offset, total)
-		paginatedResponse := models.PaginatedResponse{
-			Data:       responseData,
-			Pagination: pagination,
-		}
-		writeSuccessResponse(w, paginatedResponse)
+		responseData := models.TransformAggregatedCaseSliceToResponse(cases)
+		filename = strings.TrimSuffix(filename, ".csv") + "_" + q.Interval + ".csv"
+		writeCaseList(w, r, filename, models.AggregatedCaseCSVHeader, responseData, nil)
+		return
+	}
+
+	// Cursor mode, selected by the cursor parameter even when it is empty
+	if r.URL.Query().Has("cursor") && !all {
+		h.getProvinceCasesAfterCursor(w, r, provinceID, dates, limit, sortParams, smoothing, filename)
 		return
 	}
 
-	// Handle specific province cases
+	columns, ok := provinceColumns(w, r)
+	if !ok {
+		return
+	}
+	opts := service.QueryOptions{
+		ProvinceID: provinceID,
+		Range:      dates,
+		HasRt:      hasRt,
+		Sort:       sortParams,
+		AsOf:       asOf,
+		Columns:    columns,
+	}
+	if all && streamsAll(r, asOf, smoothing) {
+		streamCaseList(w, r, func(emit func([]models.ProvinceCaseResponse) error) error {
+			return h.covidService.EachProvinceCase(r.Context(), opts, func(cases []models.ProvinceCaseWithDate) error {
+				return emit(models.TransformProvinceCaseSliceToResponse(h.annotateProvince(r, cases)))
+			})
+		})
+		return
+	}
+	if !all {
+		opts.Page = service.Page{Limit: limit, Offset: offset}
+	}
+	cases, total, err := h.covidService.ListProvinceCases(r.Context(), opts)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if cases, ok = h.addProvinceMovingAverages(w, r, cases, smoothing); !ok {
+		return
+	}
+	responseData := models.TransformProvinceCaseSliceToResponse(h.annotateProvince(r, cases))
 	if all {
-		// Return all data without pagination
-		if startDate != "" && endDate != "" {
-			cases, err := h.covidService.GetProvinceCasesByDateRangeSorted(provinceID, startDate, endDate, sortParams)
-			if err != nil {
-				writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-				return
-			}
-			responseData := models.TransformProvinceCaseSliceToResponse(cases)
-			writeSuccessResponse(w, responseData)
-			return
+		writeCaseList(w, r, filename, models.ProvinceCaseCSVHeader, responseData, nil)
+		return
+	}
+	pagination := models.CalculatePaginationMeta(limit, offset, total)
+	writeCaseList(w, r, filename, models.ProvinceCaseCSVHeader, responseData, &pagination)
+}
+
+// singleDate reads the ?date= parameter, zero when it is not set. It writes a
+// 400 for invalid dates or dates combined with a range or interval, and
+// reports whether the request may go on.
+func singleDate(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	query := r.URL.Query()
+	value := query.Get("date")
+	if value == "" {
+		return time.Time{}, true
+	}
+	for _, param := range []string{"start_date", "end_date", "range", "interval", "as_of"} {
+		if query.Get(param) != "" {
+			writeValidationError(w, &ValidationError{
+				Code:    ErrCodeInvalidFilter,
+				Field:   "date",
+				Message: fmt.Sprintf("date cannot be combined with %s", param),
+			})
+			return time.Time{}, false
 		}
+	}
 
-		cases, err := h.covidService.GetProvinceCasesSorted(provinceID, sortParams)
-		if err != nil {
-			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
+	dates, ok := parseDateRange(w, value, value)
+	if !ok {
+		return time.Time{}, false
+	}
+	return dates.Start, true
+}
+
+// asOfTime reads the ?as_of= parameter, zero when it is not set. Past data is
+// rebuilt from its revisions one daily list at a time, so it writes a 400 for
+// invalid times, other orders than by date and the parameters that need
+// another query, and reports whether the request may go on.
+func asOfTime(w http.ResponseWriter, r *http.Request, sortParams utils.SortParams) (time.Time, bool) {
+	query := r.URL.Query()
+	value := query.Get("as_of")
+	if value == "" {
+		return time.Time{}, true
+	}
+	asOf, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidDateFormat,
+			Field:   "as_of",
+			Message: "Invalid as_of. Use an RFC 3339 time such as 2021-08-01T00:00:00Z",
+		})
+		return time.Time{}, false
+	}
+	if interval := query.Get("interval"); interval != "" && interval != models.IntervalDaily {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "as_of",
+			Message: "as_of only applies to daily cases",
+		})
+		return time.Time{}, false
+	}
+	for _, param := range []string{"smoothing", "cursor"} {
+		if query.Has(param) {
+			writeValidationError(w, &ValidationError{
+				Code:    ErrCodeInvalidFilter,
+				Field:   "as_of",
+				Message: fmt.Sprintf("as_of cannot be combined with %s", param),
+			})
+			return time.Time{}, false
 		}
-		responseData := models.TransformProvinceCaseSliceToResponse(cases)
-		writeSuccessResponse(w, responseData)
+	}
+	if sortParams.Field != "date" {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "as_of",
+			Message: "as_of only supports sorting by date",
+		})
+		return time.Time{}, false
+	}
+	return asOf.UTC(), true
+}
+
+// getNationalCaseOnDate writes the national record of a single ?date=.
+func (h *CovidHandler) getNationalCaseOnDate(w http.ResponseWriter, r *http.Request, date time.Time) {
+	view, ok := caseViewOf(w, r, models.NationalCaseResponse{})
+	if !ok {
+		return
+	}
+
+	nationalCase, err := h.covidService.GetNationalCaseByDate(r.Context(), date)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if nationalCase == nil {
+		writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Data untuk tanggal %s tidak ditemukan", date.Format("2006-01-02")))
 		return
 	}
 
-	// Return paginated data
-	if startDate != "" && endDate != "" {
-		cases, total, err := h.covidService.GetProvinceCasesByDateRangePaginatedSorted(provinceID, startDate, endDate, limit, offset, sortParams)
+	responseData := models.TransformSliceToResponse(h.annotateNational(r, []models.NationalCase{*nationalCase}))
+	stripTimestamps(r, responseData)
+	writeSuccessResponse(w, view.render(responseData[0]))
+}
+
+// getProvinceCaseOnDate writes the record of one province on a single ?date=.
+// The all-provinces route has no single record, so it needs a province.
+func (h *CovidHandler) getProvinceCaseOnDate(w http.ResponseWriter, r *http.Request, provinceID string, date time.Time) {
+	if provinceID == "" {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "date",
+			Message: "date requires a province, use start_date and end_date for all provinces",
+		})
+		return
+	}
+	view, ok := caseViewOf(w, r, models.ProvinceCaseResponse{})
+	if !ok {
+		return
+	}
+
+	provinceCase, err := h.covidService.GetProvinceCaseByDate(r.Context(), provinceID, date)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if provinceCase == nil {
+		writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Data untuk tanggal %s tidak ditemukan", date.Format("2006-01-02")))
+		return
+	}
+
+	responseData := models.TransformProvinceCaseSliceToResponse(h.annotateProvince(r, []models.ProvinceCaseWithDate{*provinceCase}))
+	stripTimestamps(r, responseData)
+	writeSuccessResponse(w, view.render(responseData[0]))
+}
+
+// resolveRangePreset replaces the dates with those of the ?range= preset, if
+// any. It writes a 400 for unknown presets or presets combined with explicit
+// dates, and reports whether the request may go on.
+func (h *CovidHandler) resolveRangePreset(w http.ResponseWriter, r *http.Request, startDate, endDate *string) bool {
+	preset := r.URL.Query().Get("range")
+	if preset == "" {
+		return true
+	}
+	if *startDate != "" || *endDate != "" {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidRange,
+			Field:   "range",
+			Message: "range cannot be combined with start_date or end_date",
+		})
+		return false
+	}
+
+	start, end, err := h.covidService.ResolveDateRange(r.Context(), preset)
+	if errors.Is(err, service.ErrInvalidDateRangePreset) {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidRange,
+			Field:   "range",
+			Message: fmt.Sprintf("Invalid range %q. Use one of %s", preset, strings.Join(service.DateRangePresets, ", ")),
+		})
+		return false
+	}
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	*startDate, *endDate = start, end
+	return true
+}
+
+// aggregateQuery reads the ?interval= parameter. It returns nil for daily
+// cases and the query of weekly or monthly totals otherwise. It writes a 400
+// for unknown intervals or totals sorted by anything but date, and reports
+// whether the request may go on.
+func aggregateQuery(w http.ResponseWriter, r *http.Request, dates service.DateRange, sortParams utils.SortParams) (*repository.CaseAggregateQuery, bool) {
+	interval := r.URL.Query().Get("interval")
+	if interval == "" || interval == models.IntervalDaily {
+		return nil, true
+	}
+	if !models.IsValidCaseInterval(interval) {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidInterval,
+			Field:   "interval",
+			Message: fmt.Sprintf("Invalid interval %q. Use one of %s", interval, strings.Join(models.CaseIntervals, ", ")),
+		})
+		return nil, false
+	}
+	if sortParams.Field != "date" {
+		writeErrorResponse(w, http.StatusBadRequest, "Weekly and monthly intervals only support sorting by date")
+		return nil, false
+	}
+
+	q := &repository.CaseAggregateQuery{Interval: interval, Desc: sortParams.Order == "desc"}
+	if dates.IsSet() {
+		q.StartDate, q.EndDate = dates.Start, dates.End
+	}
+	return q, true
+}
+
+// maxSmoothingDays limits the window of the ?smoothing= moving averages
+const maxSmoothingDays = 90
+
+// smoothingDays reads the ?smoothing= parameter, the number of days of the
+// moving averages to add, or 0 when absent. It writes a 400 for values out of
+// range or smoothing of weekly and monthly totals, and reports whether the
+// request may go on.
+func smoothingDays(w http.ResponseWriter, r *http.Request, q *repository.CaseAggregateQuery) (int, bool) {
+	raw := r.URL.Query().Get("smoothing")
+	if raw == "" {
+		return 0, true
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 1 || days > maxSmoothingDays {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidSmoothing,
+			Field:   "smoothing",
+			Message: fmt.Sprintf("smoothing must be a number of days between 1 and %d", maxSmoothingDays),
+		})
+		return 0, false
+	}
+	if q != nil {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidSmoothing,
+			Field:   "smoothing",
+			Message: "smoothing only applies to daily cases",
+		})
+		return 0, false
+	}
+	return days, true
+}
+
+// addNationalMovingAverages adds the moving averages to the cases when
+// smoothing is requested. It writes a 500 on failure and reports whether the
+// request may go on.
+func (h *CovidHandler) addNationalMovingAverages(w http.ResponseWriter, r *http.Request, cases []models.NationalCase, smoothing int) ([]models.NationalCase, bool) {
+	if smoothing == 0 {
+		return cases, true
+	}
+	smoothed, err := h.covidService.AddNationalMovingAverages(r.Context(), cases, smoothing)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return smoothed, true
+}
+
+// addProvinceMovingAverages is addNationalMovingAverages for province cases
+func (h *CovidHandler) addProvinceMovingAverages(w http.ResponseWriter, r *http.Request, cases []models.ProvinceCaseWithDate, smoothing int) ([]models.ProvinceCaseWithDate, bool) {
+	if smoothing == 0 {
+		return cases, true
+	}
+	smoothed, err := h.covidService.AddProvinceMovingAverages(r.Context(), cases, smoothing)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return smoothed, true
+}
+
+// annotateNational adds the quality block of flagged rows when a data
+// quality reporter is configured, and the analyst notes of the rows' dates
+// with ?include=notes
+func (h *CovidHandler) annotateNational(r *http.Request, cases []models.NationalCase) []models.NationalCase {
+	if h.quality != nil {
+		cases = h.quality.AnnotateNationalCases(cases)
+	}
+	if h.notes != nil && wantsInclude(r, includeNotes) {
+		annotated, err := h.notes.AnnotateNationalCases(r.Context(), cases)
 		if err != nil {
-			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
+			log.Printf("Error adding case notes: %v", err)
+			return cases
 		}
-		responseData := models.TransformProvinceCaseSliceToResponse(cases)
-		pagination := models.CalculatePaginationMeta(limit, offset, total)
-		paginatedResponse := models.PaginatedResponse{
-			Data:       responseData,
-			Pagination: pagination,
+		cases = annotated
+	}
+	return cases
+}
+
+// annotateProvince is annotateNational for province cases
+func (h *CovidHandler) annotateProvince(r *http.Request, cases []models.ProvinceCaseWithDate) []models.ProvinceCaseWithDate {
+	if h.quality != nil {
+		cases = h.quality.AnnotateProvinceCases(cases)
+	}
+	if h.notes != nil && wantsInclude(r, includeNotes) {
+		annotated, err := h.notes.AnnotateProvinceCases(r.Context(), cases)
+		if err != nil {
+			log.Printf("Error adding case notes: %v", err)
+			return cases
 		}
-		writeSuccessResponse(w, paginatedResponse)
+		cases = annotated
+	}
+	return cases
+}
+
+// getProvinceCasesAfterCursor serves a page of province cases using keyset
+// pagination. An empty cursor returns the first page.
+func (h *CovidHandler) getProvinceCasesAfterCursor(w http.ResponseWriter, r *http.Request, provinceID string, dates service.DateRange, limit int, sortParams utils.SortParams, smoothing int, filename string) {
+	if sortParams.Field != "date" {
+		writeErrorResponse(w, http.StatusBadRequest, "Cursor pagination only supports sorting by date")
 		return
 	}
+	columns, ok := provinceColumns(w, r)
+	if !ok {
+		return
+	}
+	q := repository.ProvinceCaseCursorQuery{
+		ProvinceID: provinceID,
+		Desc:       sortParams.Order == "desc",
+		Limit:      limit,
+		HasRt:      utils.ParseBoolQueryParam(r, "has_rt"),
+		Columns:    columns,
+	}
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		after, err := models.DecodeCaseCursor(token)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		q.After = &after
+	}
+	if dates.IsSet() {
+		q.StartDate, q.EndDate = dates.Start, dates.End
+	}
 
-	cases, total, err := h.covidService.GetProvinceCasesPaginatedSorted(provinceID, limit, offset, sortParams)
+	cases, next, err := h.covidService.GetProvinceCasesAfterCursor(r.Context(), q)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	responseData := models.TransformProvinceCaseSliceToResponse(cases)
-	pagination := models.CalculatePaginationMeta(limit, offset, total)
-	paginatedResponse := models.PaginatedResponse{
-		Data:       responseData,
-		Pagination: pagination,
+	cases, ok = h.addProvinceMovingAverages(w, r, cases, smoothing)
+	if !ok {
+		return
 	}
-	writeSuccessResponse(w, paginatedResponse)
+	responseData := models.TransformProvinceCaseSliceToResponse(h.annotateProvince(r, cases))
+	pagination := models.CursorPaginationMeta(limit, q.After != nil, next)
+	writeCaseList(w, r, filename, models.ProvinceCaseCSVHeader, responseData, &pagination)
 }
 
 // HealthCheck godoc
@@ -350,7 +864,7 @@ func (h *CovidHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	health := map[string]interface{}{
 		"status":    "healthy",
 		"service":   "COVID-19 API",
-		"version": "2.9.0",
+		"version": apiVersion,
 		"timestamp": time.Now().UTC().Format(time.RFC3339),
 	}
 
@@ -382,6 +896,12 @@ func (h *CovidHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 	health["database"] = dbHealth
 
+	if h.tableStats != nil {
+		if snapshot := h.tableStats.Snapshot(); snapshot != nil {
+			health["tables"] = snapshot
+		}
+	}
+
 	// Set appropriate HTTP status code based on health status
 	statusCode := http.StatusOK
 	if health["status"] == "degraded" || health["status"] == "unhealthy" {
@@ -404,6 +924,7 @@ func (h *CovidHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 // @Tags national
 // @Produce json
 // @Param day path int true "Day number"
+// @Param include query string false "Comma-separated: timestamps adds the created_at and updated_at of the record, notes the analyst notes of its date"
 // @Success 200 {object} Response
 // @Failure 404 {object} Response
 // @Router /national/{day} [get]
@@ -415,7 +936,7 @@ func (h *CovidHandler) GetNationalCaseByDay(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	nationalCase, err := h.covidService.GetNationalCaseByDay(day)
+	nationalCase, err := h.covidService.GetNationalCaseByDay(r.Context(), day)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -424,7 +945,10 @@ func (h *CovidHandler) GetNationalCaseByDay(w http.ResponseWriter, r *http.Reque
 		writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Data untuk hari ke-%d tidak ditemukan", day))
 		return
 	}
-	writeSuccessResponse(w, nationalCase)
+	// Copy before clearing, the service may hand out a cached case
+	data := h.annotateNational(r, []models.NationalCase{*nationalCase})
+	stripTimestamps(r, data)
+	writeSuccessResponse(w, data[0])
 }
 
 // GetProvinceByID godoc
@@ -439,7 +963,7 @@ func (h *CovidHandler) GetProvinceByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	code := vars["code"]
 
-	province, err := h.covidService.GetProvinceByID(code)
+	province, err := h.covidService.GetProvinceByID(r.Context(), code)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -459,7 +983,7 @@ func (h *CovidHandler) GetAPIIndex(w http.ResponseWriter, r *http.Request) {
 	endpoints := map[string]interface{}{
 		"api": map[string]interface{}{
 			"title":       "Sulawesi Tengah COVID-19 Data API",
-			"version": "2.9.0",
+			"version": apiVersion,
 			"description": "A comprehensive REST API for COVID-19 data in Sulawesi Tengah (Central Sulawesi)",
 		},
 		"documentation": map[string]interface{}{
@@ -475,17 +999,47 @@ func (h *CovidHandler) GetAPIIndex(w http.ResponseWriter, r *http.Request) {
 				"method":      "GET",
 				"description": "Check API health status and database connectivity",
 			},
+			"descriptor": map[string]interface{}{
+				"url":         "/api/v1/.well-known/api-descriptor",
+				"method":      "GET",
+				"description": "Machine-readable capabilities for SDKs: version, formats, limits, rate limit and metrics",
+			},
+			"changes": map[string]interface{}{
+				"url":         "/api/v1/changes",
+				"method":      "GET",
+				"description": "Changelog of endpoints and parameters per API version, filterable by since, type and path",
+			},
+			"tenant": map[string]interface{}{
+				"url":         "/api/v1/tenant",
+				"method":      "GET",
+				"description": "Tenant serving the request, selected by the X-Tenant header or subdomain",
+			},
 			"national": map[string]interface{}{
 				"list": map[string]string{
 					"url":         "/api/v1/national",
 					"method":      "GET",
 					"description": "Get national COVID-19 cases (with optional date range)",
 				},
+				"compare_periods": map[string]string{
+					"url":         "/api/v1/national/compare-periods?period_a=2021-06&period_b=2021-07",
+					"method":      "GET",
+					"description": "Totals and percentage changes of national cases between two periods",
+				},
 				"latest": map[string]string{
 					"url":         "/api/v1/national/latest",
 					"method":      "GET",
 					"description": "Get latest national COVID-19 case data",
 				},
+				"tests": map[string]string{
+					"url":         "/api/v1/national/tests",
+					"method":      "GET",
+					"description": "Daily PCR and antigen tests with positivity rates (with optional date range)",
+				},
+				"revisions": map[string]string{
+					"url":         "/api/v1/national/{day}/revisions",
+					"method":      "GET",
+					"description": "Publication and later corrections of a day's national case, with the changed fields",
+				},
 			},
 			"provinces": map[string]interface{}{
 				"list": map[string]string{
@@ -493,6 +1047,11 @@ func (h *CovidHandler) GetAPIIndex(w http.ResponseWriter, r *http.Request) {
 					"method":      "GET",
 					"description": "Get provinces with latest case data (default)",
 				},
+				"latest": map[string]string{
+					"url":         "/api/v1/provinces/latest?ids=72,31,73",
+					"method":      "GET",
+					"description": "Latest case data of selected provinces in the requested order",
+				},
 				"cases": map[string]interface{}{
 					"all": map[string]string{
 						"url":         "/api/v1/provinces/cases",
@@ -506,6 +1065,18 @@ func (h *CovidHandler) GetAPIIndex(w http.ResponseWriter, r *http.Request) {
 					},
 				},
 			},
+			"export": map[string]interface{}{
+				"xlsx": map[string]string{
+					"url":         "/api/v1/export/xlsx?scope=province&province_id=72",
+					"method":      "GET",
+					"description": "Excel workbook of national or province cases with Daily, Cumulative and Statistics sheets",
+				},
+				"jobs": map[string]string{
+					"url":         "/api/v1/exports",
+					"method":      "POST",
+					"description": "Queue a CSV or Excel export and poll /api/v1/exports/{id} for its progress and download_url",
+				},
+			},
 			"regencies": map[string]interface{}{
 				"list": map[string]string{
 					"url":         "/api/v1/regencies",
@@ -523,6 +1094,143 @@ func (h *CovidHandler) GetAPIIndex(w http.ResponseWriter, r *http.Request) {
 					"description": "Get COVID-19 cases for a specific regency",
 				},
 			},
+			"districts": map[string]interface{}{
+				"by_province": map[string]string{
+					"url":         "/api/v1/provinces/{provinceId}/districts",
+					"method":      "GET",
+					"description": "Get districts (kabupaten/kota) of a province (e.g., /api/v1/provinces/72/districts)",
+				},
+				"cases": map[string]string{
+					"url":         "/api/v1/districts/{districtId}/cases",
+					"method":      "GET",
+					"description": "Get COVID-19 cases for a district (e.g., /api/v1/districts/7271/cases for Palu)",
+				},
+			},
+			"submission": map[string]interface{}{
+				"national": map[string]string{
+					"url":         "/api/v1/national",
+					"method":      "POST",
+					"description": "Upsert national case rows (requires X-API-Key)",
+				},
+				"province": map[string]string{
+					"url":         "/api/v1/provinces/{provinceId}/cases",
+					"method":      "POST",
+					"description": "Upsert case rows of a province (requires X-API-Key)",
+				},
+			},
+			"subscriptions": map[string]interface{}{
+				"subscribe": map[string]string{
+					"url":         "/api/v1/subscriptions",
+					"method":      "POST",
+					"description": "Subscribe an email to daily Sulawesi Tengah updates (double opt-in)",
+				},
+			},
+			"webhooks": map[string]interface{}{
+				"register": map[string]string{
+					"url":         "/api/v1/webhooks",
+					"method":      "POST",
+					"description": "Register a callback URL notified of new national and province case rows (requires a write-scoped X-API-Key)",
+				},
+			},
+			"stream": map[string]interface{}{
+				"cases": map[string]string{
+					"url":         "/api/v1/stream/cases",
+					"method":      "GET",
+					"description": "Server-Sent Events stream pushing an event whenever new daily data arrives (optional province_id filter)",
+				},
+				"dashboard": map[string]string{
+					"url":         "/api/v1/ws",
+					"method":      "GET",
+					"description": "WebSocket sending the latest national and Sulawesi Tengah numbers on connect and whenever new daily data arrives",
+				},
+			},
+			"embed": map[string]interface{}{
+				"summary": map[string]string{
+					"url":         "/api/v1/embed/summary.html",
+					"method":      "GET",
+					"description": "Self-contained HTML card with the latest Sulawesi Tengah figures for iframes",
+				},
+			},
+			"summary": map[string]string{
+				"url":         "/api/v1/summary",
+				"method":      "GET",
+				"description": "Latest national and province totals with 7-day moving averages of new cases",
+			},
+			"status": map[string]string{
+				"url":         "/api/v1/status",
+				"method":      "GET",
+				"description": "Controlled, caution or critical status of every province from its rolling Rt and weekly incidence",
+			},
+			"system_status": map[string]string{
+				"url":         "/api/v1/system-status",
+				"method":      "GET",
+				"description": "Status page of the API with component health, incident notices and 24h/7d/30d uptime",
+			},
+			"recap": map[string]string{
+				"url":         "/api/v1/recap/{year}",
+				"method":      "GET",
+				"description": "Yearly recap with totals, worst day, best month, average Rt, waves and vaccination progress (optional: ?province_id=72)",
+			},
+			"reports": map[string]interface{}{
+				"daily": map[string]string{
+					"url":         "/api/v1/reports/daily?date={YYYY-MM-DD}",
+					"method":      "GET",
+					"description": "One-page PDF situational report of Sulawesi Tengah with key numbers, weekly trend and Rt",
+				},
+			},
+			"metrics": map[string]interface{}{
+				"list": map[string]string{
+					"url":         "/api/v1/metrics",
+					"method":      "GET",
+					"description": "Statistics accepted by rankings, province sorting and province metrics",
+				},
+				"rankings": map[string]string{
+					"url":         "/api/v1/rankings?metric={metric}&order=desc&limit=10",
+					"method":      "GET",
+					"description": "Provinces ranked by a metric of their latest case",
+				},
+				"by_province": map[string]string{
+					"url":         "/api/v1/provinces/{provinceId}/metrics?start_date={YYYY-MM-DD}&end_date={YYYY-MM-DD}",
+					"method":      "GET",
+					"description": "Latest, minimum, maximum and average of every metric for a province",
+				},
+				"calendar": map[string]string{
+					"url":         "/api/v1/provinces/{provinceId}/calendar?year={YYYY}&metric=daily_positive",
+					"method":      "GET",
+					"description": "A metric for every calendar day of a year, null on days without data",
+				},
+				"per_capita": map[string]string{
+					"url":         "/api/v1/provinces/{provinceId}/per-capita",
+					"method":      "GET",
+					"description": "Cases and deaths per 100k people and vaccine dose coverage of a province",
+				},
+			},
+			"data_quality": map[string]interface{}{
+				"issues": map[string]string{
+					"url":         "/api/v1/data-quality/issues?province_id={provinceId|national}&type={type}",
+					"method":      "GET",
+					"description": "Negative daily counts, cumulative regressions and outliers found in the case data",
+				},
+			},
+			"og": map[string]interface{}{
+				"daily": map[string]string{
+					"url":         "/api/v1/og/daily.png?date={YYYY-MM-DD}",
+					"method":      "GET",
+					"description": "1200x630 PNG preview image with a day's Sulawesi Tengah headline numbers",
+				},
+			},
+			"share": map[string]interface{}{
+				"create": map[string]string{
+					"url":         "/api/v1/share",
+					"method":      "POST",
+					"description": "Store a filtered view under a short code",
+				},
+				"open": map[string]string{
+					"url":         "/s/{code}",
+					"method":      "GET",
+					"description": "Redirect to the dashboard view of a share link (expand=true returns the filters)",
+				},
+			},
 			"hospitals": map[string]interface{}{
 				"list": map[string]string{
 					"url":         "/api/v1/hospitals",
@@ -558,6 +1266,16 @@ func (h *CovidHandler) GetAPIIndex(w http.ResponseWriter, r *http.Request) {
 					"method":      "GET",
 					"description": "Get vaccination locations in Sulawesi Tengah",
 				},
+				"national_doses": map[string]string{
+					"url":         "/api/v1/national/vaccinations?start_date={YYYY-MM-DD}&end_date={YYYY-MM-DD}&sort=date:desc",
+					"method":      "GET",
+					"description": "National first, second and booster doses with case-style pagination and sorting",
+				},
+				"province_doses": map[string]string{
+					"url":         "/api/v1/provinces/{provinceId}/vaccinations",
+					"method":      "GET",
+					"description": "First, second and booster doses of a province with case-style pagination and sorting",
+				},
 			},
 			"stats": map[string]interface{}{
 				"gender": map[string]string{
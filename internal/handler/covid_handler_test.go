@@ -1,35 +1,71 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/internal/service"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// testDateRange parses a date range for expectations
+func testDateRange(start, end string) service.DateRange {
+	dates, err := service.ParseDateRange(start, end)
+	if err != nil {
+		panic(err)
+	}
+	return dates
+}
+
 type MockCovidService struct {
 	mock.Mock
 }
 
-func (m *MockCovidService) GetNationalCases() ([]models.NationalCase, error) {
-	args := m.Called()
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+func (m *MockCovidService) ListNationalCases(ctx context.Context, opts service.QueryOptions) ([]models.NationalCase, int, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
 }
 
-func (m *MockCovidService) GetNationalCasesByDateRange(startDate, endDate string) ([]models.NationalCase, error) {
-	args := m.Called(startDate, endDate)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+func (m *MockCovidService) ListProvinceCases(ctx context.Context, opts service.QueryOptions) ([]models.ProvinceCaseWithDate, int, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+}
+
+func (m *MockCovidService) EachNationalCase(ctx context.Context, opts service.QueryOptions, fn func([]models.NationalCase) error) error {
+	args := m.Called(opts)
+	for _, chunk := range args.Get(0).([][]models.NationalCase) {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
-func (m *MockCovidService) GetLatestNationalCase() (*models.NationalCase, error) {
+func (m *MockCovidService) EachProvinceCase(ctx context.Context, opts service.QueryOptions, fn func([]models.ProvinceCaseWithDate) error) error {
+	args := m.Called(opts)
+	for _, chunk := range args.Get(0).([][]models.ProvinceCaseWithDate) {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockCovidService) GetLatestNationalCase(ctx context.Context) (*models.NationalCase, error) {
 	args := m.Called()
 	result := args.Get(0)
 	if result == nil {
@@ -38,7 +74,43 @@ func (m *MockCovidService) GetLatestNationalCase() (*models.NationalCase, error)
 	return result.(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockCovidService) GetNationalCaseByDay(day int64) (*models.NationalCase, error) {
+func (m *MockCovidService) ResolveDateRange(ctx context.Context, preset string) (string, string, error) {
+	args := m.Called(preset)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockCovidService) GetNationalCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
+}
+
+func (m *MockCovidService) GetNationalTotals(ctx context.Context, dates service.DateRange) (models.PeriodTotals, error) {
+	args := m.Called(dates)
+	return args.Get(0).(models.PeriodTotals), args.Error(1)
+}
+
+func (m *MockCovidService) GetProvinceCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
+}
+
+func (m *MockCovidService) AddNationalMovingAverages(ctx context.Context, cases []models.NationalCase, days int) ([]models.NationalCase, error) {
+	args := m.Called(cases, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NationalCase), args.Error(1)
+}
+
+func (m *MockCovidService) AddProvinceMovingAverages(ctx context.Context, cases []models.ProvinceCaseWithDate, days int) ([]models.ProvinceCaseWithDate, error) {
+	args := m.Called(cases, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+}
+
+func (m *MockCovidService) GetNationalCaseByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
 	args := m.Called(day)
 	result := args.Get(0)
 	if result == nil {
@@ -47,7 +119,16 @@ func (m *MockCovidService) GetNationalCaseByDay(day int64) (*models.NationalCase
 	return result.(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockCovidService) GetProvinceByID(id string) (*models.Province, error) {
+func (m *MockCovidService) GetNationalCaseByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	args := m.Called(date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.NationalCase), args.Error(1)
+}
+
+func (m *MockCovidService) GetProvinceByID(ctx context.Context, id string) (*models.Province, error) {
 	args := m.Called(id)
 	result := args.Get(0)
 	if result == nil {
@@ -56,178 +137,520 @@ func (m *MockCovidService) GetProvinceByID(id string) (*models.Province, error)
 	return result.(*models.Province), args.Error(1)
 }
 
-func (m *MockCovidService) GetProvinces() ([]models.Province, error) {
+func (m *MockCovidService) GetProvinces(ctx context.Context) ([]models.Province, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Province), args.Error(1)
 }
 
-func (m *MockCovidService) GetProvincesWithLatestCase() ([]models.ProvinceWithLatestCase, error) {
+func (m *MockCovidService) GetProvincesWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
 	args := m.Called()
 	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
 }
 
-func (m *MockCovidService) GetProvinceCases(provinceID string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockCovidService) GetProvinceCasesAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	args := m.Called(q)
+	next, _ := args.Get(1).(*models.CaseCursor)
+	return args.Get(0).([]models.ProvinceCaseWithDate), next, args.Error(2)
 }
 
-func (m *MockCovidService) GetProvinceCasesByDateRange(provinceID, startDate, endDate string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, startDate, endDate)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockCovidService) GetProvinceCaseByDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	args := m.Called(provinceID, date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.ProvinceCaseWithDate), args.Error(1)
 }
 
-func (m *MockCovidService) GetAllProvinceCases() ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called()
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockCovidService) GetProvincesWithLatestCaseByIDs(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
 }
 
-func (m *MockCovidService) GetAllProvinceCasesByDateRange(startDate, endDate string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(startDate, endDate)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
+func TestCovidHandler_GetNationalCases(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
 
-// Paginated methods
-func (m *MockCovidService) GetProvinceCasesPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
+	expectedCases := []models.NationalCase{
+		{ID: 1, Positive: 100, Recovered: 80, Deceased: 5},
+	}
 
-func (m *MockCovidService) GetProvinceCasesByDateRangePaginated(provinceID, startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, startDate, endDate, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(expectedCases, len(expectedCases), nil)
 
-func (m *MockCovidService) GetAllProvinceCasesPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
+	req, err := http.NewRequest("GET", "/api/v1/national", nil)
+	assert.NoError(t, err)
 
-func (m *MockCovidService) GetAllProvinceCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(startDate, endDate, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, req)
 
-// Sorted methods
-func (m *MockCovidService) GetNationalCasesSorted(sortParams utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(sortParams)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
+	assert.Equal(t, http.StatusOK, rr.Code)
 
-func (m *MockCovidService) GetNationalCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(startDate, endDate, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+	var response Response
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", response.Status)
+	assert.NotNil(t, response.Data)
+
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetNationalCasesPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func TestCovidHandler_GetNationalCases_WithDateRange(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	expectedCases := []models.NationalCase{
+		{ID: 1, Positive: 100, Date: time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	mockService.On("ListNationalCases", service.QueryOptions{Range: testDateRange("2020-03-01", "2020-03-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(expectedCases, len(expectedCases), nil)
+
+	req, err := http.NewRequest("GET", "/api/v1/national?start_date=2020-03-01&end_date=2020-03-31", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response Response
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", response.Status)
+
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetNationalCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func TestCovidHandler_GetNationalCases_WithRangePreset(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	mockService.On("ResolveDateRange", "last7d").Return("2023-06-24", "2023-06-30", nil)
+	mockService.On("ListNationalCases", service.QueryOptions{Range: testDateRange("2023-06-24", "2023-06-30"), Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return([]models.NationalCase{}, 0, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/national?range=last7d", nil)
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetNationalCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(startDate, endDate, limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func TestCovidHandler_GetNationalCases_WeeklyInterval(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	rt := 1.1
+	mockService.On("GetNationalCasesAggregated", repository.CaseAggregateQuery{
+		Interval:  models.IntervalWeekly,
+		StartDate: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC),
+		Desc:      true,
+	}).Return([]models.AggregatedCase{{Period: "2021-W06", Days: 7, Positive: 600, Recovered: 550, AverageRt: &rt}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/national?interval=weekly&start_date=2021-02-01&end_date=2021-02-14&sort=date:desc&limit=1", nil)
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"period":"2021-W06"`)
+	assert.Contains(t, rr.Body.String(), `"average_rt":1.1`)
+	assert.NotContains(t, rr.Body.String(), `"pagination"`)
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetNationalCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func TestCovidHandler_GetProvinceCases_MonthlyIntervalCSV(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	mockService.On("GetProvinceCasesAggregated", repository.CaseAggregateQuery{Interval: models.IntervalMonthly, ProvinceID: "72"}).
+		Return([]models.AggregatedCase{{Period: "2021-06", ProvinceID: "72", Days: 30}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/provinces/72/cases?interval=monthly&format=csv", nil)
+	req = mux.SetURLVars(req, map[string]string{"provinceId": "72"})
+	rr := httptest.NewRecorder()
+	handler.GetProvinceCases(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "province_72_cases_monthly.csv")
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "period,start_date,end_date"))
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetProvinceCasesSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func TestCovidHandler_GetNationalCases_InvalidInterval(t *testing.T) {
+	handler := NewCovidHandler(new(MockCovidService), nil)
+
+	for target, code := range map[string]string{
+		"/api/v1/national?interval=yearly":                    ErrCodeInvalidInterval,
+		"/api/v1/national?interval=weekly&sort=positive:desc": "",
+	} {
+		rr := httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, code, response.Code, target)
+	}
 }
 
-func (m *MockCovidService) GetProvinceCasesPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func TestCovidHandler_GetNationalCases_Smoothing(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	date := time.Date(2021, 2, 7, 0, 0, 0, 0, time.UTC)
+	cases := []models.NationalCase{{Day: 7, Date: date, Positive: 70}}
+	smoothed := []models.NationalCase{{Day: 7, Date: date, Positive: 70,
+		MovingAverage: &models.MovingAverage{Days: 7, Positive: 40, Recovered: 20, Deceased: 1.5}}}
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(cases, 1, nil)
+	mockService.On("AddNationalMovingAverages", cases, 7).Return(smoothed, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?smoothing=7", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"moving_average":{"days":7,"positive":40,"recovered":20,"deceased":1.5}`)
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetProvinceCasesByDateRangeSorted(provinceID, startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, startDate, endDate, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func TestCovidHandler_GetProvinceCases_Smoothing(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	cases := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{Day: 7, ProvinceID: "72"}, Date: time.Date(2021, 2, 7, 0, 0, 0, 0, time.UTC)}}
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return(cases, len(cases), nil)
+	mockService.On("AddProvinceMovingAverages", cases, 3).Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/api/v1/provinces/72/cases?all=true&smoothing=3", nil)
+	req = mux.SetURLVars(req, map[string]string{"provinceId": "72"})
+	rr := httptest.NewRecorder()
+	handler.GetProvinceCases(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetProvinceCasesByDateRangePaginatedSorted(provinceID, startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func TestCovidHandler_GetNationalCases_InvalidSmoothing(t *testing.T) {
+	handler := NewCovidHandler(new(MockCovidService), nil)
+
+	for _, target := range []string{
+		"/api/v1/national?smoothing=0",
+		"/api/v1/national?smoothing=91",
+		"/api/v1/national?smoothing=week",
+		"/api/v1/national?smoothing=7&interval=weekly",
+	} {
+		rr := httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, ErrCodeInvalidSmoothing, response.Code, target)
+	}
 }
 
-func (m *MockCovidService) GetAllProvinceCasesSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func TestCovidHandler_GetNationalCases_FlatShape(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	rt := 1.2
+	cases := []models.NationalCase{{Day: 7, Date: time.Date(2021, 2, 7, 0, 0, 0, 0, time.UTC), Positive: 70, Recovered: 20, CumulativePositive: 700, Rt: &rt}}
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(cases, 1, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?shape=flat", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	if assert.Len(t, response.Data.Data, 1) {
+		row := response.Data.Data[0]
+		assert.Equal(t, 70.0, row["daily_positive"])
+		assert.Equal(t, 50.0, row["daily_active"])
+		assert.Equal(t, 700.0, row["cumulative_active"])
+		assert.Equal(t, 1.2, row["rt_value"])
+		assert.NotContains(t, row, "daily")
+		assert.NotContains(t, row, "statistics")
+	}
+	mockService.AssertExpectations(t)
 }
 
-func (m *MockCovidService) GetAllProvinceCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func TestCovidHandler_GetNationalCases_Fields(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	rt := 1.2
+	cases := []models.NationalCase{{Day: 7, Date: time.Date(2021, 2, 7, 0, 0, 0, 0, time.UTC), Positive: 70, CumulativePositive: 700, Rt: &rt}}
+	// Only the columns of the selected fields are read
+	tests := map[string]struct {
+		columns []string
+		row     string
+	}{
+		"/api/v1/national?fields=day,daily.positive,statistics.reproduction_rate.value": {[]string{"day", "positive", "rt"}, `{"day":7,"daily":{"positive":70},"statistics":{"reproduction_rate":{"value":1.2}}}`},
+		"/api/v1/national?fields=cumulative_positive,day&shape=flat":                    {[]string{"day", "cumulative_positive"}, `{"day":7,"cumulative_positive":700}`},
+		"/api/v1/national?fields=kasus&legacy=true":                                     {[]string{"positive"}, `{"kasus":70}`},
+	}
+	for _, tt := range tests {
+		mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}, Columns: tt.columns}).Return(cases, 1, nil)
+	}
+
+	for target, tt := range tests {
+		row := tt.row
+		rr := httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", target, nil))
+		require.Equal(t, http.StatusOK, rr.Code, target)
+		var response struct {
+			Data struct {
+				Data       []json.RawMessage     `json:"data"`
+				Pagination models.PaginationMeta `json:"pagination"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response), target)
+		require.Len(t, response.Data.Data, 1, target)
+		assert.Equal(t, row, string(response.Data.Data[0]), target)
+		assert.Equal(t, 1, response.Data.Pagination.Total, target)
+	}
+
+	// The fields are those of the requested shape
+	for _, target := range []string{"/api/v1/national?fields=positive", "/api/v1/national?fields=daily.positive&shape=flat"} {
+		rr := httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), `"code":"INVALID_FIELD","field":"fields"`, target)
+	}
 }
 
-func (m *MockCovidService) GetAllProvinceCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(startDate, endDate, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func TestCovidHandler_GetLatestNationalCase_Fields(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("GetLatestNationalCase").Return(&models.NationalCase{Day: 9, Positive: 12}, nil)
+
+	rr := httptest.NewRecorder()
+	NewCovidHandler(mockService, nil).GetLatestNationalCase(rr, httptest.NewRequest("GET", "/api/v1/national/latest?fields=day,daily.positive", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"success","data":{"day":9,"daily":{"positive":12}}}`, rr.Body.String())
+
+	// Unknown fields are rejected before the case is read
+	rr = httptest.NewRecorder()
+	NewCovidHandler(new(MockCovidService), nil).GetLatestNationalCase(rr, httptest.NewRequest("GET", "/api/v1/national/latest?fields=daily.rt", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
-func (m *MockCovidService) GetAllProvinceCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func TestCovidHandler_GetLatestNationalCase_Legacy(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	mockService.On("GetLatestNationalCase").Return(&models.NationalCase{Day: 9, Positive: 12, Recovered: 3, Deceased: 1}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetLatestNationalCase(rr, httptest.NewRequest("GET", "/api/v1/national/latest?legacy=true", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"hari":9`)
+	assert.Contains(t, rr.Body.String(), `"kasus":12,"sembuh":3,"meninggal":1`)
+	assert.NotContains(t, rr.Body.String(), `"daily"`)
+	mockService.AssertExpectations(t)
 }
 
-func TestCovidHandler_GetNationalCases(t *testing.T) {
+func TestCovidHandler_GetNationalCases_Timestamps(t *testing.T) {
 	mockService := new(MockCovidService)
 	handler := NewCovidHandler(mockService, nil)
+	stored := time.Date(2021, 2, 8, 1, 30, 0, 0, time.UTC)
+	cases := []models.NationalCase{{Day: 7, Positive: 70, RecordTimestamps: models.RecordTimestamps{CreatedAt: &stored, UpdatedAt: &stored}}}
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(cases, 1, nil)
 
-	expectedCases := []models.NationalCase{
-		{ID: 1, Positive: 100, Recovered: 80, Deceased: 5},
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "created_at")
+
+	for _, target := range []string{"/api/v1/national?include=timestamps", "/api/v1/national?include=timestamps&shape=flat"} {
+		rr = httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusOK, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), `"created_at":"2021-02-08T01:30:00Z","updated_at":"2021-02-08T01:30:00Z"`, target)
 	}
+	mockService.AssertExpectations(t)
+}
 
-	mockService.On("GetNationalCasesPaginatedSorted", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, len(expectedCases), nil)
+func TestCovidHandler_GetNationalCaseByDay_Timestamps(t *testing.T) {
+	svc := new(MockCovidService)
+	stored := time.Date(2021, 2, 8, 1, 30, 0, 0, time.UTC)
+	nationalCase := &models.NationalCase{ID: 1, Positive: 100, RecordTimestamps: models.RecordTimestamps{CreatedAt: &stored, UpdatedAt: &stored}}
+	svc.On("GetNationalCaseByDay", int64(1)).Return(nationalCase, nil)
 
-	req, err := http.NewRequest("GET", "/api/v1/national", nil)
-	assert.NoError(t, err)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/national/{day}", NewCovidHandler(svc, nil).GetNationalCaseByDay)
 
 	rr := httptest.NewRecorder()
-	handler.GetNationalCases(rr, req)
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/national/1", nil))
+	assert.NotContains(t, rr.Body.String(), "updated_at")
+	// The service's case is left untouched
+	assert.Equal(t, &stored, nationalCase.UpdatedAt)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/national/1?include=timestamps", nil))
+	assert.Contains(t, rr.Body.String(), `"updated_at":"2021-02-08T01:30:00Z"`)
+}
 
-	assert.Equal(t, http.StatusOK, rr.Code)
+func TestCovidHandler_GetLatestNationalCase_InvalidShape(t *testing.T) {
+	handler := NewCovidHandler(new(MockCovidService), nil)
+
+	for _, target := range []string{
+		"/api/v1/national/latest?shape=deep",
+		"/api/v1/national/latest?legacy=true&shape=flat",
+	} {
+		rr := httptest.NewRecorder()
+		handler.GetLatestNationalCase(rr, httptest.NewRequest("GET", target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, ErrCodeInvalidShape, response.Code, target)
+	}
+}
 
+func TestCovidHandler_GetNationalCases_SingleDate(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetNationalCaseByDate", date).Return(&models.NationalCase{ID: 1, Day: 500, Date: date, Positive: 1200}, nil)
+	mockService.On("GetNationalCaseByDate", date.AddDate(0, 0, 1)).Return(nil, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?date=2021-07-15", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
 	var response Response
-	err = json.Unmarshal(rr.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "success", response.Status)
-	assert.NotNil(t, response.Data)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	data, ok := response.Data.(map[string]interface{})
+	assert.True(t, ok, "a single record, not a list")
+	assert.Equal(t, float64(500), data["day"])
+
+	rr = httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?date=2021-07-16", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "2021-07-16")
 
 	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "ListNationalCases", mock.Anything)
 }
 
-func TestCovidHandler_GetNationalCases_WithDateRange(t *testing.T) {
+func TestCovidHandler_GetProvinceCases_SingleDate(t *testing.T) {
 	mockService := new(MockCovidService)
 	handler := NewCovidHandler(mockService, nil)
 
-	expectedCases := []models.NationalCase{
-		{ID: 1, Positive: 100, Date: time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)},
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	provinceCase := &models.ProvinceCaseWithDate{
+		ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "72", Positive: 80},
+		Date:         date,
 	}
+	mockService.On("GetProvinceCaseByDate", "72", date).Return(provinceCase, nil)
 
-	mockService.On("GetNationalCasesByDateRangePaginatedSorted", "2020-03-01", "2020-03-31", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, len(expectedCases), nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", handler.GetProvinceCases)
+	router.HandleFunc("/api/v1/provinces/cases", handler.GetProvinceCases)
 
-	req, err := http.NewRequest("GET", "/api/v1/national?start_date=2020-03-01&end_date=2020-03-31", nil)
-	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/cases?date=2021-07-15", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	_, ok := response.Data.(map[string]interface{})
+	assert.True(t, ok, "a single record, not a list")
+
+	// The all-provinces route has no single record for a date
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/cases?date=2021-07-15", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, ErrCodeInvalidFilter, response.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_GetNationalCases_InvalidSingleDate(t *testing.T) {
+	handler := NewCovidHandler(new(MockCovidService), nil)
+
+	tests := []struct {
+		target string
+		code   string
+	}{
+		{"/api/v1/national?date=15-07-2021", ErrCodeInvalidDateFormat},
+		{"/api/v1/national?date=2021-07-15&start_date=2021-07-01", ErrCodeInvalidFilter},
+		{"/api/v1/national?date=2021-07-15&range=last7d", ErrCodeInvalidFilter},
+		{"/api/v1/national?date=2021-07-15&interval=weekly", ErrCodeInvalidFilter},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", tt.target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, tt.target)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, tt.code, response.Code, tt.target)
+	}
+}
+
+func TestCovidHandler_GetProvinceCases_AsOf(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	asOf := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("ListProvinceCases", service.QueryOptions{
+		ProvinceID: "72",
+		Sort:       utils.SortParams{Field: "date", Order: "desc"},
+		Page:       service.Page{Limit: 50},
+		AsOf:       asOf,
+	}).Return([]models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "72"}}}, 1, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", handler.GetProvinceCases)
 
 	rr := httptest.NewRecorder()
-	handler.GetNationalCases(rr, req)
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/cases?as_of=2021-08-01T07:00:00%2B07:00&sort=date:desc", nil))
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
 
-	var response Response
-	err = json.Unmarshal(rr.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "success", response.Status)
+func TestCovidHandler_GetNationalCases_InvalidAsOf(t *testing.T) {
+	handler := NewCovidHandler(new(MockCovidService), nil)
+
+	tests := []struct {
+		target string
+		code   string
+	}{
+		{"/api/v1/national?as_of=2021-08-01", ErrCodeInvalidDateFormat},
+		{"/api/v1/national?as_of=2021-08-01T00:00:00Z&sort=positive:desc", ErrCodeInvalidFilter},
+		{"/api/v1/national?as_of=2021-08-01T00:00:00Z&interval=weekly", ErrCodeInvalidFilter},
+		{"/api/v1/national?as_of=2021-08-01T00:00:00Z&smoothing=7", ErrCodeInvalidFilter},
+		{"/api/v1/national?as_of=2021-08-01T00:00:00Z&date=2021-07-15", ErrCodeInvalidFilter},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		handler.GetNationalCases(rr, httptest.NewRequest("GET", tt.target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, tt.target)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, tt.code, response.Code, tt.target)
+	}
+}
+
+func TestCovidHandler_GetProvinceCases_InvalidRangePreset(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
 
+	mockService.On("ResolveDateRange", "yesterday").Return("", "", fmt.Errorf("%w %q", service.ErrInvalidDateRangePreset, "yesterday"))
+
+	for _, target := range []string{
+		"/api/v1/provinces/cases?range=yesterday",
+		"/api/v1/provinces/cases?range=last7d&start_date=2023-01-01&end_date=2023-01-31",
+	} {
+		rr := httptest.NewRecorder()
+		handler.GetProvinceCases(rr, httptest.NewRequest("GET", target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, ErrCodeInvalidRange, response.Code)
+	}
 	mockService.AssertExpectations(t)
 }
 
@@ -235,7 +658,7 @@ func TestCovidHandler_GetNationalCases_ServiceError(t *testing.T) {
 	mockService := new(MockCovidService)
 	handler := NewCovidHandler(mockService, nil)
 
-	mockService.On("GetNationalCasesPaginatedSorted", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return([]models.NationalCase{}, 0, errors.New("database error"))
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return([]models.NationalCase{}, 0, errors.New("database error"))
 
 	req, err := http.NewRequest("GET", "/api/v1/national", nil)
 	assert.NoError(t, err)
@@ -352,7 +775,7 @@ func TestCovidHandler_GetProvinceCases_AllProvinces_Paginated(t *testing.T) {
 	}
 	expectedTotal := 100
 
-	mockService.On("GetAllProvinceCasesPaginatedSorted", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, expectedTotal, nil)
+	mockService.On("ListProvinceCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(expectedCases, expectedTotal, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/cases", nil)
 	assert.NoError(t, err)
@@ -394,7 +817,7 @@ func TestCovidHandler_GetProvinceCases_SpecificProvince_Paginated(t *testing.T)
 	}
 	expectedTotal := 50
 
-	mockService.On("GetProvinceCasesPaginatedSorted", "11", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, expectedTotal, nil)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "11", Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(expectedCases, expectedTotal, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/11/cases", nil)
 	assert.NoError(t, err)
@@ -429,7 +852,7 @@ func TestCovidHandler_GetProvinceCases_AllData(t *testing.T) {
 		{ProvinceCase: models.ProvinceCase{ID: 2, ProvinceID: "31", Positive: 100}},
 	}
 
-	mockService.On("GetAllProvinceCasesSorted", utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, nil)
+	mockService.On("EachProvinceCase", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return([][]models.ProvinceCaseWithDate{expectedCases[:1], expectedCases[1:]}, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/cases?all=true", nil)
 	assert.NoError(t, err)
@@ -461,7 +884,7 @@ func TestCovidHandler_GetProvinceCases_CustomPagination(t *testing.T) {
 	}
 	expectedTotal := 200
 
-	mockService.On("GetAllProvinceCasesPaginatedSorted", 100, 50, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, expectedTotal, nil)
+	mockService.On("ListProvinceCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 100, Offset: 50}}).Return(expectedCases, expectedTotal, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/cases?limit=100&offset=50", nil)
 	assert.NoError(t, err)
@@ -501,7 +924,7 @@ func TestCovidHandler_GetProvinceCases_DateRange_Paginated(t *testing.T) {
 	}
 	expectedTotal := 30
 
-	mockService.On("GetAllProvinceCasesByDateRangePaginatedSorted", "2024-01-01", "2024-01-31", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, expectedTotal, nil)
+	mockService.On("ListProvinceCases", service.QueryOptions{Range: testDateRange("2024-01-01", "2024-01-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(expectedCases, expectedTotal, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/cases?start_date=2024-01-01&end_date=2024-01-31", nil)
 	assert.NoError(t, err)
@@ -532,7 +955,7 @@ func TestCovidHandler_GetProvinceCases_DateRange_AllData(t *testing.T) {
 		{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11", Positive: 50}},
 	}
 
-	mockService.On("GetAllProvinceCasesByDateRangeSorted", "2024-01-01", "2024-01-31", utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, nil)
+	mockService.On("EachProvinceCase", service.QueryOptions{Range: testDateRange("2024-01-01", "2024-01-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return([][]models.ProvinceCaseWithDate{expectedCases}, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/cases?start_date=2024-01-01&end_date=2024-01-31&all=true", nil)
 	assert.NoError(t, err)
@@ -563,7 +986,7 @@ func TestCovidHandler_GetProvinceCases_SpecificProvince_AllData(t *testing.T) {
 		{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "31", Positive: 200}},
 	}
 
-	mockService.On("GetProvinceCasesSorted", "31", utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, nil)
+	mockService.On("EachProvinceCase", service.QueryOptions{ProvinceID: "31", Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return([][]models.ProvinceCaseWithDate{expectedCases}, nil)
 
 	req, err := http.NewRequest("GET", "/api/v1/provinces/31/cases?all=true", nil)
 	assert.NoError(t, err)
@@ -684,6 +1107,36 @@ func TestCovidHandler_HealthCheck(t *testing.T) {
 	assert.Equal(t, "unavailable", dbData["status"])
 }
 
+type stubTableStatsProvider struct {
+	snapshot *models.TableStatsSnapshot
+}
+
+func (s stubTableStatsProvider) Snapshot() *models.TableStatsSnapshot {
+	return s.snapshot
+}
+
+func TestCovidHandler_HealthCheck_WithTableStats(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	handler.tableStats = stubTableStatsProvider{snapshot: &models.TableStatsSnapshot{
+		TotalRows: 42,
+		Tables:    []models.TableStats{{Name: "national_cases", Rows: 42}},
+	}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	rr := httptest.NewRecorder()
+	handler.HealthCheck(rr, req)
+
+	var response Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+	data, ok := response.Data.(map[string]interface{})
+	assert.True(t, ok)
+	tables, ok := data["tables"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, float64(42), tables["total_rows"])
+}
+
 func TestCovidHandler_GetNationalCaseByDay_Success(t *testing.T) {
 	svc := new(MockCovidService)
 	expected := &models.NationalCase{ID: 1, Positive: 100}
@@ -795,3 +1248,216 @@ func TestCovidHandler_GetProvinceByID_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	svc.AssertExpectations(t)
 }
+
+func TestCovidHandler_CompareNationalPeriods(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("GetNationalTotals", testDateRange("2021-06-01", "2021-06-30")).Return(models.PeriodTotals{Days: 2, Positive: 200, AverageDailyPositive: 100}, nil)
+	mockService.On("GetNationalTotals", testDateRange("2021-07-01", "2021-07-31")).Return(models.PeriodTotals{Days: 1, Positive: 300, AverageDailyPositive: 300}, nil)
+	handler := NewCovidHandler(mockService, nil)
+
+	rr := httptest.NewRecorder()
+	handler.CompareNationalPeriods(rr, httptest.NewRequest("GET", "/api/v1/national/compare-periods?period_a=2021-06&period_b=2021-07", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data models.PeriodComparison `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "2021-06-30", response.Data.PeriodA.EndDate)
+	assert.Equal(t, int64(200), response.Data.PeriodA.Positive)
+	assert.Equal(t, int64(300), response.Data.PeriodB.Positive)
+	assert.InDelta(t, 50.0, *response.Data.PercentChange.Positive, 1e-9)
+	assert.InDelta(t, 200.0, *response.Data.PercentChange.AverageDailyPositive, 1e-9)
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_CompareNationalPeriods_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(*MockCovidService)
+		wantStatus int
+	}{
+		{name: "missing period_b", query: "?period_a=2021-06", setup: func(*MockCovidService) {}, wantStatus: http.StatusBadRequest},
+		{name: "invalid period_a", query: "?period_a=june&period_b=2021-07", setup: func(*MockCovidService) {}, wantStatus: http.StatusBadRequest},
+		{
+			name:  "service error",
+			query: "?period_a=2021-06&period_b=2021-07",
+			setup: func(m *MockCovidService) {
+				m.On("GetNationalTotals", testDateRange("2021-06-01", "2021-06-30")).Return(models.PeriodTotals{}, errors.New("db down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCovidService)
+			tt.setup(mockService)
+			rr := httptest.NewRecorder()
+			NewCovidHandler(mockService, nil).CompareNationalPeriods(rr, httptest.NewRequest("GET", "/api/v1/national/compare-periods"+tt.query, nil))
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestCovidHandler_GetLatestProvinceCasesByIDs(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	// The repository orders by name; the response follows the requested order
+	mockService.On("GetProvincesWithLatestCaseByIDs", []string{"72", "31", "98"}).Return([]models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "31", Name: "DKI Jakarta"}, LatestCase: &models.ProvinceCaseResponse{Day: 101}},
+		{Province: models.Province{ID: "72", Name: "Sulawesi Tengah"}, LatestCase: &models.ProvinceCaseResponse{Day: 100}},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetLatestProvinceCasesByIDs(rr, httptest.NewRequest("GET", "/api/v1/provinces/latest?ids=72,%2031,72,98", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []models.ProvinceWithLatestCase `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "72", response.Data[0].ID)
+	assert.Equal(t, "31", response.Data[1].ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_GetLatestProvinceCasesByIDs_InvalidIDs(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	distinct := make([]string, maxLatestProvinceIDs+1)
+	for i := range distinct {
+		distinct[i] = strconv.Itoa(10 + i)
+	}
+	tooMany := strings.Join(distinct, ",")
+	for _, ids := range []string{"", ",,", tooMany} {
+		rr := httptest.NewRecorder()
+		handler.GetLatestProvinceCasesByIDs(rr, httptest.NewRequest("GET", "/api/v1/provinces/latest?ids="+ids, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, ids)
+	}
+	mockService.AssertNotCalled(t, "GetProvincesWithLatestCaseByIDs", mock.Anything)
+}
+
+func TestCovidHandler_GetProvinceCases_Cursor(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", handler.GetProvinceCases)
+
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	next := &models.CaseCursor{Date: date, ID: 1234}
+	mockService.On("GetProvinceCasesAfterCursor", repository.ProvinceCaseCursorQuery{ProvinceID: "72", Limit: 2}).
+		Return([]models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{ID: 1233, ProvinceID: "72"}, Date: date},
+			{ProvinceCase: models.ProvinceCase{ID: 1234, ProvinceID: "72"}, Date: date},
+		}, next, nil)
+	mockService.On("GetProvinceCasesAfterCursor", repository.ProvinceCaseCursorQuery{ProvinceID: "72", After: next, Desc: true, Limit: 2}).
+		Return([]models.ProvinceCaseWithDate{}, nil, nil)
+
+	var response struct {
+		Data models.PaginatedResponse `json:"data"`
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/cases?cursor=&limit=2", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.PaginationMeta{Limit: 2, HasNext: true, NextCursor: next.Encode()}, response.Data.Pagination)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/cases?limit=2&sort=date:desc&cursor="+next.Encode(), nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response.Data = models.PaginatedResponse{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.False(t, response.Data.Pagination.HasNext)
+	assert.True(t, response.Data.Pagination.HasPrev)
+	assert.Empty(t, response.Data.Pagination.NextCursor)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_GetProvinceCases_HasRt(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/cases", handler.GetProvinceCases)
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", handler.GetProvinceCases)
+
+	rt := 1.2
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	cases := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 7, ProvinceID: "72", Rt: &rt}, Date: date}}
+	mockService.On("ListProvinceCases", service.QueryOptions{
+		ProvinceID: "72",
+		HasRt:      true,
+		Sort:       utils.SortParams{Field: "date", Order: "asc"},
+		Page:       service.Page{Limit: 10, Offset: 20},
+	}).Return(cases, 120, nil)
+	mockService.On("EachProvinceCase", service.QueryOptions{
+		Range: testDateRange("2021-06-01", "2021-06-30"),
+		HasRt: true,
+		Sort:  utils.SortParams{Field: "rt", Order: "desc"},
+	}).Return([][]models.ProvinceCaseWithDate{cases}, nil)
+	mockService.On("GetProvinceCasesAfterCursor", repository.ProvinceCaseCursorQuery{Limit: 50, HasRt: true}).
+		Return(cases, nil, nil)
+
+	var paginated struct {
+		Data models.PaginatedResponse `json:"data"`
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/cases?has_rt=true&limit=10&page=3", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &paginated))
+	assert.Equal(t, 120, paginated.Data.Pagination.Total)
+
+	var all struct {
+		Data []models.ProvinceCaseResponse `json:"data"`
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/cases?has_rt=true&all=true&start_date=2021-06-01&end_date=2021-06-30&sort=rt:desc", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &all))
+	assert.Len(t, all.Data, 1)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/cases?has_rt=true&cursor=", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/cases?has_rt=true&interval=weekly", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), ErrCodeInvalidFilter)
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetProvinceCasesAggregated", mock.Anything)
+}
+
+func TestCovidHandler_GetProvinceCases_InvalidDate(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", handler.GetProvinceCases)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/cases?has_rt=true&start_date=2021-13-01&end_date=2021-06-30", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), ErrCodeInvalidDateFormat)
+	mockService.AssertNotCalled(t, "ListProvinceCases", mock.Anything)
+}
+
+func TestCovidHandler_GetProvinceCases_CursorInvalidParams(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/cases", handler.GetProvinceCases)
+
+	for _, query := range []string{"cursor=garbage", "cursor=&sort=positive:desc", "cursor=&start_date=2021-13-01&end_date=2021-12-31"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/cases?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetProvinceCasesAfterCursor", mock.Anything)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWantsCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		accept string
+		want   bool
+	}{
+		{"default json", "/national", "", false},
+		{"format query", "/national?format=csv", "", true},
+		{"format query uppercase", "/national?format=CSV", "", true},
+		{"accept header", "/national", "text/csv", true},
+		{"accept header with params", "/national", "text/csv; charset=utf-8", true},
+		{"json preferred in accept", "/national", "application/json, text/csv", false},
+		{"csv preferred in accept", "/national", "text/csv, application/json", true},
+		{"format json overrides accept", "/national?format=json", "text/csv", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			assert.Equal(t, tt.want, wantsCSV(req))
+		})
+	}
+}
+
+func TestCovidHandler_GetNationalCases_CSV(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	rt := 1.25
+	expectedCases := []models.NationalCase{
+		{ID: 1, Day: 1, Date: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC), Positive: 2, CumulativePositive: 2, Rt: &rt},
+		{ID: 2, Day: 2, Date: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC), Positive: 0, CumulativePositive: 2},
+	}
+
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(expectedCases, 10, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/national?format=csv", nil)
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "national_cases.csv")
+	assert.Equal(t, "10", rr.Header().Get("X-Total-Count"))
+	assert.Equal(t, "50", rr.Header().Get("X-Pagination-Limit"))
+
+	records, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, models.NationalCaseCSVHeader, records[0])
+	assert.Equal(t, "2020-03-02", records[1][1])
+	assert.Equal(t, "1.25", records[1][10])
+	assert.Equal(t, "", records[2][10])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_GetProvinceCases_CSVAcceptHeader(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	expectedCases := []models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{ID: 1, Day: 1, ProvinceID: "72", Positive: 3, Province: &models.Province{ID: "72", Name: "Sulawesi Tengah"}}, Date: time.Date(2020, 3, 26, 0, 0, 0, 0, time.UTC)},
+	}
+
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return(expectedCases, len(expectedCases), nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/provinces/72/cases?all=true", nil)
+	req.Header.Set("Accept", "text/csv")
+	req = mux.SetURLVars(req, map[string]string{"provinceId": "72"})
+	rr := httptest.NewRecorder()
+	handler.GetProvinceCases(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "province_72_cases.csv")
+	assert.Empty(t, rr.Header().Get("X-Total-Count"))
+
+	records, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, models.ProvinceCaseCSVHeader, records[0])
+	assert.Equal(t, []string{"1", "2020-03-26", "72", "Sulawesi Tengah", "3"}, records[1][:5])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestWriteCSV_FlushesLargeResults(t *testing.T) {
+	rows := make([]models.NationalCaseResponse, csvFlushRows*2+1)
+	rr := httptest.NewRecorder()
+
+	writeCSV(rr, "national_cases.csv", models.NationalCaseCSVHeader, rows)
+
+	assert.True(t, rr.Flushed)
+	records, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, len(rows)+1)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// DataQualityHandler serves the anomalies flagged in the case data.
+type DataQualityHandler struct {
+	service service.DataQualityReporter
+}
+
+// NewDataQualityHandler creates a new DataQualityHandler.
+func NewDataQualityHandler(service service.DataQualityReporter) *DataQualityHandler {
+	return &DataQualityHandler{service: service}
+}
+
+// qualityIssueTypes are the accepted ?type= values
+var qualityIssueTypes = []string{
+	models.QualityIssueNegativeDaily,
+	models.QualityIssueCumulativeRegression,
+	models.QualityIssueOutlier,
+}
+
+// GetIssues godoc
+//
+//	@Summary		List data quality issues
+//	@Description	Lists the anomalies found by the latest scan of the case data, most recent first: negative daily counts (negative_daily), cumulative counts below those of the previous date (cumulative_regression) and daily counts far from the median of the days before (outlier). Flagged rows also carry these issues in their quality block.
+//	@Tags			data-quality
+//	@Produce		json
+//	@Param			province_id	query		string	false	"Province ID, or national for the national rows"
+//	@Param			type		query		string	false	"negative_daily, cumulative_regression or outlier"
+//	@Param			start_date	query		string	false	"Start date (YYYY-MM-DD)"
+//	@Param			end_date	query		string	false	"End date (YYYY-MM-DD)"
+//	@Param			limit		query		integer	false	"Issues per page (default: 50, max: 1000)"
+//	@Param			offset		query		integer	false	"Issues to skip (default: 0)"
+//	@Success		200			{object}	Response{data=models.DataQualityReport}
+//	@Failure		400			{object}	Response
+//	@Router			/data-quality/issues [get]
+func (h *DataQualityHandler) GetIssues(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	dates, ok := parseDateRange(w, query.Get("start_date"), query.Get("end_date"))
+	if !ok {
+		return
+	}
+	issueType := query.Get("type")
+	if issueType != "" && !slices.Contains(qualityIssueTypes, issueType) {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "type",
+			Message: fmt.Sprintf("Invalid type %q. Use %s, %s or %s", issueType, qualityIssueTypes[0], qualityIssueTypes[1], qualityIssueTypes[2]),
+		})
+		return
+	}
+
+	limit, offset := utils.ValidatePaginationParams(utils.ParseIntQueryParam(r, "limit", 50), utils.ParseIntQueryParam(r, "offset", 0))
+	writeSuccessResponse(w, h.service.Issues(models.QualityIssueFilter{
+		ProvinceID: query.Get("province_id"),
+		Type:       issueType,
+		StartDate:  dates.Start,
+		EndDate:    dates.End,
+		Limit:      limit,
+		Offset:     offset,
+	}))
+}
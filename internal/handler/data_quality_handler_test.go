@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDataQualityReporter struct {
+	mock.Mock
+}
+
+func (m *MockDataQualityReporter) Issues(filter models.QualityIssueFilter) models.DataQualityReport {
+	return m.Called(filter).Get(0).(models.DataQualityReport)
+}
+
+func (m *MockDataQualityReporter) AnnotateNationalCases(cases []models.NationalCase) []models.NationalCase {
+	return m.Called(cases).Get(0).([]models.NationalCase)
+}
+
+func (m *MockDataQualityReporter) AnnotateProvinceCases(cases []models.ProvinceCaseWithDate) []models.ProvinceCaseWithDate {
+	return m.Called(cases).Get(0).([]models.ProvinceCaseWithDate)
+}
+
+func TestDataQualityHandler_GetIssues(t *testing.T) {
+	reporter := new(MockDataQualityReporter)
+	checkedAt := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	reporter.On("Issues", models.QualityIssueFilter{
+		ProvinceID: "72",
+		Type:       models.QualityIssueNegativeDaily,
+		StartDate:  time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2021, 7, 31, 0, 0, 0, 0, time.UTC),
+		Limit:      10,
+	}).Return(models.DataQualityReport{
+		CheckedAt: &checkedAt,
+		Issues: []models.QualityIssue{{ProvinceID: "72", Day: 500, Date: time.Date(2021, 7, 20, 0, 0, 0, 0, time.UTC), QualityFlag: models.QualityFlag{
+			Type: models.QualityIssueNegativeDaily, Field: "recovered", Value: -4, Message: "recovered is negative",
+		}}},
+		Pagination: models.CalculatePaginationMeta(10, 0, 1),
+	})
+	router := SetupRoutes(Services{DataQuality: reporter}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/data-quality/issues?province_id=72&type=negative_daily&start_date=2021-07-01&end_date=2021-07-31&limit=10", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"checked_at":"2021-08-01T12:00:00Z"`)
+	assert.Contains(t, rr.Body.String(), `"type":"negative_daily","field":"recovered","value":-4`)
+	assert.Contains(t, rr.Body.String(), `"total":1`)
+	reporter.AssertExpectations(t)
+}
+
+func TestDataQualityHandler_GetIssues_InvalidType(t *testing.T) {
+	router := SetupRoutes(Services{DataQuality: new(MockDataQualityReporter)}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/data-quality/issues?type=typo", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), ErrCodeInvalidFilter)
+}
+
+func TestCovidHandler_GetNationalCases_Quality(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	reporter := new(MockDataQualityReporter)
+	handler.quality = reporter
+	cases := []models.NationalCase{{Day: 7, Positive: 70}, {Day: 8, Recovered: -2}}
+	flagged := []models.NationalCase{cases[0], cases[1]}
+	flagged[1].Quality = &models.RecordQuality{Flagged: true, Issues: []models.QualityFlag{
+		{Type: models.QualityIssueNegativeDaily, Field: "recovered", Value: -2, Message: "recovered is negative"},
+	}}
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).Return(cases, 2, nil)
+	reporter.On("AnnotateNationalCases", cases).Return(flagged)
+
+	rr := httptest.NewRecorder()
+	handler.GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, strings.Count(rr.Body.String(), `"quality":`))
+	assert.Contains(t, rr.Body.String(), `"quality":{"flagged":true,"issues":[{"type":"negative_daily"`)
+	reporter.AssertExpectations(t)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/metrics"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// apiVersion is reported by the index, the health check and the API
+// descriptor; keep it in sync with @version in cmd/main.go
+const apiVersion = "2.9.0"
+
+// DescriptorHandler serves the machine-readable API descriptor
+type DescriptorHandler struct {
+	rateLimit  config.RateLimitConfig
+	validation config.ValidationConfig
+	registry   *metrics.Registry
+}
+
+// NewDescriptorHandler creates a new DescriptorHandler
+func NewDescriptorHandler(rateLimit config.RateLimitConfig, validation config.ValidationConfig, registry *metrics.Registry) *DescriptorHandler {
+	return &DescriptorHandler{rateLimit: rateLimit, validation: validation, registry: registry}
+}
+
+// APIDescriptor describes the capabilities of the API so that generated SDKs
+// and apps can configure themselves
+type APIDescriptor struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Formats are the response formats of the case lists, selected with
+	// ?format= or the Accept header
+	Formats       []string            `json:"formats"`
+	Limits        DescriptorLimits    `json:"limits"`
+	RateLimit     DescriptorRateLimit `json:"rate_limit"`
+	Intervals     []string            `json:"intervals"`
+	RangePresets  []string            `json:"range_presets"`
+	Metrics       []MetricInfo        `json:"metrics"`
+	Documentation map[string]string   `json:"documentation"`
+}
+
+// DescriptorLimits are the bounds of request parameters
+type DescriptorLimits struct {
+	DefaultPageSize int `json:"default_page_size"`
+	MaxPageSize     int `json:"max_page_size"`
+	// MaxDateRangeDays is 0 when date ranges are unlimited
+	MaxDateRangeDays     int `json:"max_date_range_days"`
+	MaxSmoothingDays     int `json:"max_smoothing_days"`
+	MaxRankingLimit      int `json:"max_ranking_limit"`
+	MaxLatestProvinceIDs int `json:"max_latest_province_ids"`
+}
+
+// DescriptorRateLimit is the global rate limit per client. The X-RateLimit-*
+// response headers report the remaining requests.
+type DescriptorRateLimit struct {
+	Enabled           bool `json:"enabled"`
+	RequestsPerWindow int  `json:"requests_per_window"`
+	BurstSize         int  `json:"burst_size"`
+	WindowSeconds     int  `json:"window_seconds"`
+	// MaxConcurrentRequests is 0 when in-flight requests are not capped
+	MaxConcurrentRequests int `json:"max_concurrent_requests"`
+}
+
+// GetDescriptor godoc
+//
+//	@Summary		API descriptor
+//	@Description	Machine-readable capabilities of the API: version, response formats, parameter limits, the rate limit, aggregation intervals, range presets and available metrics
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Response{data=APIDescriptor}
+//	@Router			/.well-known/api-descriptor [get]
+func (h *DescriptorHandler) GetDescriptor(w http.ResponseWriter, r *http.Request) {
+	all := h.registry.All()
+	infos := make([]MetricInfo, len(all))
+	for i, m := range all {
+		infos[i] = MetricInfo{Name: m.Name(), Description: m.Description(), Dependencies: m.Dependencies()}
+	}
+
+	writeSuccessResponse(w, APIDescriptor{
+		Name:    "Sulawesi Tengah COVID-19 Data API",
+		Version: apiVersion,
+		Formats: []string{"json", "csv"},
+		Limits: DescriptorLimits{
+			DefaultPageSize:      utils.DefaultPageLimit,
+			MaxPageSize:          utils.MaxPageLimit,
+			MaxDateRangeDays:     h.validation.MaxDateRangeDays,
+			MaxSmoothingDays:     maxSmoothingDays,
+			MaxRankingLimit:      maxRankingLimit,
+			MaxLatestProvinceIDs: maxLatestProvinceIDs,
+		},
+		RateLimit: DescriptorRateLimit{
+			Enabled:               h.rateLimit.Enabled,
+			RequestsPerWindow:     h.rateLimit.RequestsPerMinute,
+			BurstSize:             h.rateLimit.BurstSize,
+			WindowSeconds:         int(h.rateLimit.WindowSize.Seconds()),
+			MaxConcurrentRequests: h.rateLimit.MaxConcurrentRequests,
+		},
+		Intervals:    models.CaseIntervals,
+		RangePresets: service.DateRangePresets,
+		Metrics:      infos,
+		Documentation: map[string]string{
+			"swagger_ui":   "/swagger/index.html",
+			"openapi_yaml": "/docs/swagger.yaml",
+			"openapi_json": "/docs/swagger.json",
+			"changelog":    "/api/v1/changes",
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptorHandler_GetDescriptor(t *testing.T) {
+	router := SetupRoutes(Services{
+		CovidService: new(MockCovidService),
+		RateLimit:    config.RateLimitConfig{Enabled: true, RequestsPerMinute: 100, BurstSize: 20, WindowSize: time.Minute},
+		Validation:   config.ValidationConfig{MaxDateRangeDays: 366},
+	}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/.well-known/api-descriptor", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data APIDescriptor `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	descriptor := response.Data
+	assert.Equal(t, apiVersion, descriptor.Version)
+	assert.Equal(t, []string{"json", "csv"}, descriptor.Formats)
+	assert.Equal(t, DescriptorLimits{
+		DefaultPageSize:      50,
+		MaxPageSize:          1000,
+		MaxDateRangeDays:     366,
+		MaxSmoothingDays:     maxSmoothingDays,
+		MaxRankingLimit:      maxRankingLimit,
+		MaxLatestProvinceIDs: maxLatestProvinceIDs,
+	}, descriptor.Limits)
+	assert.Equal(t, DescriptorRateLimit{Enabled: true, RequestsPerWindow: 100, BurstSize: 20, WindowSeconds: 60}, descriptor.RateLimit)
+	assert.Contains(t, descriptor.Intervals, "weekly")
+	assert.Contains(t, descriptor.RangePresets, "last30d")
+	assert.NotEmpty(t, descriptor.Metrics)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+const (
+	// embedRefreshSeconds is how often an embedded card reloads itself
+	embedRefreshSeconds = 900
+
+	// embedCacheControl lets browsers and CDNs keep the card for ten minutes
+	// and serve a stale copy for an hour while it is refreshed
+	embedCacheControl = "public, max-age=600, s-maxage=600, stale-while-revalidate=3600"
+)
+
+var embedSummaryTemplate = template.Must(template.New("summary").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>COVID-19 {{.Province}}</title>
+<style>
+body{margin:0;font-family:-apple-system,"Segoe UI",Roboto,Helvetica,Arial,sans-serif;color:#1f2933;background:#fff}
+.card{box-sizing:border-box;max-width:360px;padding:12px 16px;border:1px solid #d9e2ec;border-radius:8px}
+h1{margin:0;font-size:15px}
+.date{margin:2px 0 10px;font-size:12px;color:#627d98}
+.grid{display:grid;grid-template-columns:1fr 1fr;gap:8px}
+.label{font-size:11px;color:#627d98;text-transform:uppercase}
+.value{font-size:18px;font-weight:600}
+.delta{font-size:11px;color:#829ab1}
+.positive{color:#c62828}.recovered{color:#2e7d32}.deceased{color:#424242}.active{color:#ef6c00}
+.footer{margin-top:10px;font-size:10px;color:#829ab1}
+</style>
+</head>
+<body>
+<div class="card">
+<h1>COVID-19 {{.Province}}</h1>
+<p class="date">Hari ke-{{.Day}} &middot; {{.Date}}</p>
+<div class="grid">
+{{range .Stats}}<div><div class="label">{{.Label}}</div><div class="value {{.Class}}">{{.Value}}</div>{{if .Delta}}<div class="delta">{{.Delta}} hari ini</div>{{end}}</div>
+{{end}}</div>
+{{if .Rt}}<p class="footer">Rt {{.Rt}} &middot; Sumber: PICO API</p>{{else}}<p class="footer">Sumber: PICO API</p>{{end}}
+</div>
+</body>
+</html>
+`))
+
+type embedStat struct {
+	Label string
+	Class string
+	Value string
+	Delta string
+}
+
+type embedSummaryData struct {
+	Province string
+	Refresh  int
+	Day      int64
+	Date     string
+	Stats    []embedStat
+	Rt       string
+}
+
+// EmbedHandler renders small self-contained HTML widgets for iframing on
+// other sites.
+type EmbedHandler struct {
+	covidService service.CovidService
+}
+
+// NewEmbedHandler creates a new EmbedHandler.
+func NewEmbedHandler(covidService service.CovidService) *EmbedHandler {
+	return &EmbedHandler{covidService: covidService}
+}
+
+// GetSummaryCard godoc
+//
+//	@Summary		Embeddable summary card
+//	@Description	Renders the latest figures of the tenant's province (Sulawesi Tengah by default) as a self-contained HTML card for iframes. The card reloads itself every 15 minutes, is cacheable for 10 minutes and is not rate limited.
+//	@Tags			embed
+//	@Produce		html
+//	@Success		200	{string}	string	"HTML card"
+//	@Success		304	{string}	string	"Not modified"
+//	@Failure		404	{string}	string	"No data"
+//	@Failure		500	{string}	string	"Error"
+//	@Router			/embed/summary.html [get]
+func (h *EmbedHandler) GetSummaryCard(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	cases, _, err := h.covidService.ListProvinceCases(r.Context(), service.QueryOptions{
+		ProvinceID: t.ProvinceID,
+		Sort:       utils.SortParams{Field: "date", Order: "desc"},
+		Page:       service.Page{Limit: 1},
+	})
+	if err != nil {
+		log.Printf("Error loading embed summary: %v", err)
+		http.Error(w, "Data sementara tidak tersedia", http.StatusInternalServerError)
+		return
+	}
+	if len(cases) == 0 {
+		http.Error(w, "Data belum tersedia", http.StatusNotFound)
+		return
+	}
+	latest := cases[0]
+
+	// The card only changes with a new or corrected day of data
+	etag := fmt.Sprintf(`"summary-%s-%d-%d-%d-%d"`, t.ProvinceID, latest.Day, latest.CumulativePositive, latest.CumulativeRecovered, latest.CumulativeDeceased)
+	w.Header().Set("Cache-Control", embedCacheControl)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := embedSummaryTemplate.Execute(&buf, newEmbedSummaryData(t.Name, latest)); err != nil {
+		log.Printf("Error rendering embed summary: %v", err)
+		http.Error(w, "Data sementara tidak tersedia", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors *")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("Error writing embed summary: %v", err)
+	}
+}
+
+func newEmbedSummaryData(province string, c models.ProvinceCaseWithDate) embedSummaryData {
+	resp := c.TransformToResponse()
+	data := embedSummaryData{
+		Province: province,
+		Refresh:  embedRefreshSeconds,
+		Day:      c.Day,
+		Date:     formatIndonesianDate(resp.Date),
+		Stats: []embedStat{
+			{Label: "Positif", Class: "positive", Value: formatThousands(resp.Cumulative.Positive), Delta: signedThousands(resp.Daily.Positive)},
+			{Label: "Sembuh", Class: "recovered", Value: formatThousands(resp.Cumulative.Recovered), Delta: signedThousands(resp.Daily.Recovered)},
+			{Label: "Meninggal", Class: "deceased", Value: formatThousands(resp.Cumulative.Deceased), Delta: signedThousands(resp.Daily.Deceased)},
+			{Label: "Dirawat", Class: "active", Value: formatThousands(resp.Cumulative.Active)},
+		},
+	}
+	if rate := resp.Statistics.ReproductionRate; rate != nil && rate.Value != nil {
+		data.Rt = strconv.FormatFloat(*rate.Value, 'f', 2, 64)
+	}
+	return data
+}
+
+var indonesianMonths = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+func formatIndonesianDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+// formatThousands formats n with dots between thousands, e.g. 12.345
+func formatThousands(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var b strings.Builder
+	for i, ch := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(ch)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// signedThousands formats a daily change as +12, or empty when there was none
+func signedThousands(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	if n > 0 {
+		return "+" + formatThousands(n)
+	}
+	return formatThousands(n)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var embedLatestSort = utils.SortParams{Field: "date", Order: "desc"}
+
+func TestEmbedHandler_GetSummaryCard(t *testing.T) {
+	mockService := new(MockCovidService)
+	rt := 0.87
+	latest := models.ProvinceCaseWithDate{
+		ProvinceCase: models.ProvinceCase{
+			Day: 512, ProvinceID: "72", Positive: 14, Recovered: 20,
+			CumulativePositive: 12345, CumulativeRecovered: 11000, CumulativeDeceased: 321, Rt: &rt,
+		},
+		Date: time.Date(2021, 8, 17, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: embedLatestSort, Page: service.Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{latest}, 400, nil)
+	handler := NewEmbedHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	handler.GetSummaryCard(rr, httptest.NewRequest("GET", "/api/v1/embed/summary.html", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, embedCacheControl, rr.Header().Get("Cache-Control"))
+	assert.Contains(t, rr.Header().Get("Content-Security-Policy"), "frame-ancestors *")
+	body := rr.Body.String()
+	assert.Contains(t, body, "17 Agustus 2021")
+	assert.Contains(t, body, "12.345")
+	assert.Contains(t, body, "&#43;14 hari ini")
+	assert.Contains(t, body, "1.024") // active: 12345 - 11000 - 321
+	assert.Contains(t, body, "Rt 0.87")
+	assert.Contains(t, body, `http-equiv="refresh" content="900"`)
+
+	// A client holding the current version gets 304
+	req := httptest.NewRequest("GET", "/api/v1/embed/summary.html", nil)
+	req.Header.Set("If-None-Match", rr.Header().Get("ETag"))
+	rr = httptest.NewRecorder()
+	handler.GetSummaryCard(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestEmbedHandler_GetSummaryCard_NoData(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: embedLatestSort, Page: service.Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{}, 0, nil)
+
+	rr := httptest.NewRecorder()
+	NewEmbedHandler(mockService).GetSummaryCard(rr, httptest.NewRequest("GET", "/api/v1/embed/summary.html", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestEmbedHandler_GetSummaryCard_Error(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: embedLatestSort, Page: service.Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db down"))
+
+	rr := httptest.NewRecorder()
+	NewEmbedHandler(mockService).GetSummaryCard(rr, httptest.NewRequest("GET", "/api/v1/embed/summary.html", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "db down")
+}
+
+func TestFormatThousands(t *testing.T) {
+	assert.Equal(t, "0", formatThousands(0))
+	assert.Equal(t, "999", formatThousands(999))
+	assert.Equal(t, "1.000", formatThousands(1000))
+	assert.Equal(t, "1.234.567", formatThousands(1234567))
+	assert.Equal(t, "-12.345", formatThousands(-12345))
+	assert.Equal(t, "", signedThousands(0))
+	assert.Equal(t, "-3", signedThousands(-3))
+}
+
+func TestEmbedHandler_GetSummaryCard_Tenant(t *testing.T) {
+	mockService := new(MockCovidService)
+	latest := ogTestCase()
+	latest.ProvinceID = "75"
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "75", Sort: embedLatestSort, Page: service.Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{latest}, 1, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/embed/summary.html", nil)
+	req = req.WithContext(tenant.NewContext(req.Context(), tenant.Tenant{Slug: "gorontalo", ProvinceID: "75", Name: "Gorontalo"}))
+	rr := httptest.NewRecorder()
+	NewEmbedHandler(mockService).GetSummaryCard(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "COVID-19 Gorontalo")
+	assert.Contains(t, rr.Header().Get("ETag"), "summary-75-")
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/xlsx"
+)
+
+// Export scopes of ExportXLSX
+const (
+	exportScopeNational = models.ExportScopeNational
+	exportScopeProvince = models.ExportScopeProvince
+)
+
+// ExportXLSX godoc
+//
+// @Summary Export cases as an Excel workbook
+// @Description Stream national or province cases as an .xlsx workbook with Daily, Cumulative and Statistics sheets, one row per day (and province). The scope defaults to province when province_id is given and to national otherwise.
+// @Tags export
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param scope query string false "national or province"
+// @Param province_id query string false "Province ID (e.g., '72'); all provinces when omitted with scope=province"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {file} file "Excel workbook"
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /export/xlsx [get]
+func (h *CovidHandler) ExportXLSX(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	provinceID := query.Get("province_id")
+	startDate := query.Get("start_date")
+	endDate := query.Get("end_date")
+	dateRange, ok := parseDateRange(w, startDate, endDate)
+	if !ok {
+		return
+	}
+
+	scope, ok := exportScope(w, query.Get("scope"), provinceID)
+	if !ok {
+		return
+	}
+
+	switch scope {
+	case exportScopeNational:
+		cases, _, err := h.covidService.ListNationalCases(r.Context(), service.QueryOptions{Range: dateRange})
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeWorkbook(w, "national_cases.xlsx", nationalCaseSheets(models.TransformSliceToResponse(cases)))
+
+	case exportScopeProvince:
+		cases, _, err := h.covidService.ListProvinceCases(r.Context(), service.QueryOptions{ProvinceID: provinceID, Range: dateRange})
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		filename := "province_cases.xlsx"
+		if provinceID != "" {
+			filename = fmt.Sprintf("province_%s_cases.xlsx", provinceID)
+		}
+		writeWorkbook(w, filename, provinceCaseSheets(models.TransformProvinceCaseSliceToResponse(cases)))
+	}
+}
+
+// exportScope resolves the scope of an export, which defaults to province
+// when a province is given and to national otherwise. It writes a 400 for
+// unknown scopes or a province in a national export, and reports whether the
+// request may go on.
+func exportScope(w http.ResponseWriter, scope, provinceID string) (string, bool) {
+	if scope == "" {
+		scope = exportScopeNational
+		if provinceID != "" {
+			scope = exportScopeProvince
+		}
+	}
+
+	switch scope {
+	case exportScopeNational:
+		if provinceID != "" {
+			writeValidationError(w, &ValidationError{
+				Code:    ErrCodeInvalidScope,
+				Field:   "province_id",
+				Message: "province_id requires scope=province",
+			})
+			return "", false
+		}
+	case exportScopeProvince:
+	default:
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidScope,
+			Field:   "scope",
+			Message: fmt.Sprintf("Invalid scope %q. Use national or province", scope),
+		})
+		return "", false
+	}
+	return scope, true
+}
+
+// workbookSheet is one sheet of an exported workbook
+type workbookSheet struct {
+	name   string
+	header []string
+	rows   [][]xlsx.Cell
+}
+
+// writeWorkbook streams the sheets as an .xlsx attachment. Errors after the
+// headers are sent can only be logged.
+func writeWorkbook(w http.ResponseWriter, filename string, sheets []workbookSheet) {
+	w.Header().Set("Content-Type", xlsx.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSheets(w, sheets); err != nil {
+		log.Printf("Error writing XLSX response: %v", err)
+	}
+}
+
+// writeSheets writes the sheets as an .xlsx workbook
+func writeSheets(w io.Writer, sheets []workbookSheet) error {
+	xw := xlsx.NewWriter(w)
+	for _, sheet := range sheets {
+		if err := xw.WriteSheet(sheet.name, sheet.header, sheet.rows); err != nil {
+			return fmt.Errorf("failed to write sheet %s: %w", sheet.name, err)
+		}
+	}
+	return xw.Close()
+}
+
+// RenderExport writes the cases of an export job as CSV, with the columns of
+// the CSV case lists, or as the workbook of ExportXLSX
+func RenderExport(w io.Writer, req service.ExportRequest, national []models.NationalCase, province []models.ProvinceCaseWithDate) error {
+	if req.Format == models.ExportFormatXLSX {
+		if req.Scope == models.ExportScopeNational {
+			return writeSheets(w, nationalCaseSheets(models.TransformSliceToResponse(national)))
+		}
+		return writeSheets(w, provinceCaseSheets(models.TransformProvinceCaseSliceToResponse(province)))
+	}
+
+	cw := csv.NewWriter(w)
+	if req.Scope == models.ExportScopeNational {
+		writeCSVRows(cw, models.NationalCaseCSVHeader, models.TransformSliceToResponse(national))
+	} else {
+		writeCSVRows(cw, models.ProvinceCaseCSVHeader, models.TransformProvinceCaseSliceToResponse(province))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSVRows writes the rows under a header line. Write errors are kept by
+// the csv.Writer until Flush.
+func writeCSVRows[T csvRecord](cw *csv.Writer, header []string, rows []T) {
+	_ = cw.Write(header)
+	for _, row := range rows {
+		_ = cw.Write(row.CSVRow())
+	}
+}
+
+// nationalCaseSheets lays the cases out as Daily, Cumulative and Statistics sheets
+func nationalCaseSheets(cases []models.NationalCaseResponse) []workbookSheet {
+	daily := workbookSheet{name: "Daily", header: []string{"day", "date", "positive", "recovered", "deceased", "active"}}
+	cumulative := workbookSheet{name: "Cumulative", header: daily.header}
+	statistics := workbookSheet{name: "Statistics", header: statisticsHeader([]string{"day", "date"})}
+	for _, c := range cases {
+		key := []xlsx.Cell{c.Day, c.Date}
+		daily.rows = append(daily.rows, append(key, c.Daily.Positive, c.Daily.Recovered, c.Daily.Deceased, c.Daily.Active))
+		cumulative.rows = append(cumulative.rows, append(key, c.Cumulative.Positive, c.Cumulative.Recovered, c.Cumulative.Deceased, c.Cumulative.Active))
+		statistics.rows = append(statistics.rows, append(key, statisticsCells(c.Statistics.Percentages, c.Statistics.ReproductionRate)...))
+	}
+	return []workbookSheet{daily, cumulative, statistics}
+}
+
+// provinceCaseSheets lays the cases out as Daily, Cumulative and Statistics
+// sheets, with the ODP/PDP counts next to the case counts
+func provinceCaseSheets(cases []models.ProvinceCaseResponse) []workbookSheet {
+	keyHeader := []string{"day", "date", "province_id", "province_name"}
+	daily := workbookSheet{name: "Daily", header: append(append([]string{}, keyHeader...),
+		"positive", "recovered", "deceased", "active", "odp_active", "odp_finished", "pdp_active", "pdp_finished")}
+	cumulative := workbookSheet{name: "Cumulative", header: append(append([]string{}, keyHeader...),
+		"positive", "recovered", "deceased", "active", "odp_active", "odp_finished", "odp_total", "pdp_active", "pdp_finished", "pdp_total")}
+	statistics := workbookSheet{name: "Statistics", header: statisticsHeader(keyHeader)}
+	for _, c := range cases {
+		var provinceID, provinceName xlsx.Cell
+		if c.Province != nil {
+			provinceID, provinceName = c.Province.ID, c.Province.Name
+		}
+		key := []xlsx.Cell{c.Day, c.Date, provinceID, provinceName}
+		d, cu := c.Daily, c.Cumulative
+		daily.rows = append(daily.rows, append(key, d.Positive, d.Recovered, d.Deceased, d.Active,
+			d.ODP.Active, d.ODP.Finished, d.PDP.Active, d.PDP.Finished))
+		cumulative.rows = append(cumulative.rows, append(key, cu.Positive, cu.Recovered, cu.Deceased, cu.Active,
+			cu.ODP.Active, cu.ODP.Finished, cu.ODP.Total, cu.PDP.Active, cu.PDP.Finished, cu.PDP.Total))
+		statistics.rows = append(statistics.rows, append(key, statisticsCells(c.Statistics.Percentages, c.Statistics.ReproductionRate)...))
+	}
+	return []workbookSheet{daily, cumulative, statistics}
+}
+
+func statisticsHeader(keyHeader []string) []string {
+	return append(append([]string{}, keyHeader...),
+		"active_percent", "recovered_percent", "deceased_percent", "rt", "rt_upper", "rt_lower")
+}
+
+// statisticsCells returns the percentages and Rt columns, the Rt cells
+// empty when unknown
+func statisticsCells(p models.CasePercentages, rate *models.ReproductionRate) []xlsx.Cell {
+	cells := []xlsx.Cell{p.Active, p.Recovered, p.Deceased}
+	if rate == nil {
+		return append(cells, nil, nil, nil)
+	}
+	return append(cells, rate.Value, rate.UpperBound, rate.LowerBound)
+}
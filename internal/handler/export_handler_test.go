@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/xlsx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// workbookParts unzips an exported workbook into its parts by name
+func workbookParts(t *testing.T, body []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestCovidHandler_ExportXLSX_National(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	rt := 1.1
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	mockService.On("ListNationalCases", service.QueryOptions{Range: testDateRange("2021-06-01", "2021-06-30")}).Return([]models.NationalCase{
+		{Day: 450, Date: date, Positive: 120, Recovered: 100, Deceased: 5, CumulativePositive: 1000, CumulativeRecovered: 800, CumulativeDeceased: 50, Rt: &rt},
+	}, 0, nil)
+
+	rr := httptest.NewRecorder()
+	handler.ExportXLSX(rr, httptest.NewRequest("GET", "/api/v1/export/xlsx?scope=national&start_date=2021-06-01&end_date=2021-06-30", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, xlsx.ContentType, rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), `filename="national_cases.xlsx"`)
+	parts := workbookParts(t, rr.Body.Bytes())
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Daily"`)
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Cumulative"`)
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Statistics"`)
+	assert.Contains(t, parts["xl/worksheets/sheet1.xml"], `<c r="C2" s="0"><v>120</v></c>`)
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], `<c r="C2" s="0"><v>1000</v></c>`)
+	assert.Contains(t, parts["xl/worksheets/sheet3.xml"], `<c r="F2" s="0"><v>1.1</v></c>`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_ExportXLSX_Province(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72"}).Return([]models.ProvinceCaseWithDate{
+		{
+			ProvinceCase: models.ProvinceCase{Day: 450, ProvinceID: "72", Positive: 30, PersonUnderObservation: 4, Province: &models.Province{ID: "72", Name: "Sulawesi Tengah"}},
+			Date:         date,
+		},
+	}, 0, nil)
+	mockService.On("ListProvinceCases", service.QueryOptions{}).Return([]models.ProvinceCaseWithDate{}, 0, nil)
+
+	// The scope follows province_id when omitted
+	rr := httptest.NewRecorder()
+	handler.ExportXLSX(rr, httptest.NewRequest("GET", "/api/v1/export/xlsx?province_id=72", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), `filename="province_72_cases.xlsx"`)
+	daily := workbookParts(t, rr.Body.Bytes())["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, daily, `<t>Sulawesi Tengah</t>`)
+	assert.Contains(t, daily, `<c r="E2" s="0"><v>30</v></c>`)
+	assert.Contains(t, daily, `<c r="I2" s="0"><v>4</v></c>`)
+
+	rr = httptest.NewRecorder()
+	handler.ExportXLSX(rr, httptest.NewRequest("GET", "/api/v1/export/xlsx?scope=province", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), `filename="province_cases.xlsx"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCovidHandler_ExportXLSX_Errors(t *testing.T) {
+	mockService := new(MockCovidService)
+	handler := NewCovidHandler(mockService, nil)
+	mockService.On("ListNationalCases", service.QueryOptions{}).Return([]models.NationalCase(nil), 0, errors.New("db error"))
+
+	for _, query := range []string{"scope=regency", "scope=national&province_id=72"} {
+		rr := httptest.NewRecorder()
+		handler.ExportXLSX(rr, httptest.NewRequest("GET", "/api/v1/export/xlsx?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		assert.Contains(t, rr.Body.String(), ErrCodeInvalidScope, query)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ExportXLSX(rr, httptest.NewRequest("GET", "/api/v1/export/xlsx", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockService.AssertNotCalled(t, "GetProvinceCases", mock.Anything)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+	"github.com/banua-coder/pico-api-go/pkg/xlsx"
+	"github.com/gorilla/mux"
+)
+
+// maxExportBodyBytes bounds the create export request body
+const maxExportBodyBytes = 4 << 10
+
+// exportContentTypes are the download content types by export format
+var exportContentTypes = map[string]string{
+	models.ExportFormatCSV:  "text/csv; charset=utf-8",
+	models.ExportFormatXLSX: xlsx.ContentType,
+}
+
+// ExportJobHandler handles the queued export endpoints.
+type ExportJobHandler struct {
+	service service.ExportServiceInterface
+}
+
+// NewExportJobHandler creates a new ExportJobHandler.
+func NewExportJobHandler(service service.ExportServiceInterface) *ExportJobHandler {
+	return &ExportJobHandler{service: service}
+}
+
+// exportJobRequest is the body of CreateExport
+type exportJobRequest struct {
+	Scope      string `json:"scope" example:"province"`
+	ProvinceID string `json:"province_id,omitempty" example:"72"`
+	Format     string `json:"format" example:"xlsx"`
+	StartDate  string `json:"start_date,omitempty" example:"2021-01-01"`
+	EndDate    string `json:"end_date,omitempty" example:"2021-12-31"`
+}
+
+// CreateExport godoc
+//
+//	@Summary		Queue a case export
+//	@Description	Queues an export of national or province cases as CSV or an Excel workbook (the default) instead of holding the request open while it is built. Poll the returned job for its progress and download_url. The scope defaults to province when province_id is given and to national otherwise.
+//	@Tags			export
+//	@Accept			json
+//	@Produce		json
+//	@Param			export	body		exportJobRequest	true	"Cases and format to export"
+//	@Success		202		{object}	Response{data=models.ExportJob}
+//	@Failure		400		{object}	Response
+//	@Failure		503		{object}	Response	"Export queue full"
+//	@Router			/exports [post]
+func (h *ExportJobHandler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	var body exportJobRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExportBodyBytes)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	dates, ok := parseDateRange(w, body.StartDate, body.EndDate)
+	if !ok {
+		return
+	}
+	scope, ok := exportScope(w, body.Scope, body.ProvinceID)
+	if !ok {
+		return
+	}
+	if body.Format == "" {
+		body.Format = models.ExportFormatXLSX
+	}
+	if _, known := exportContentTypes[body.Format]; !known {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "format",
+			Message: fmt.Sprintf("Invalid format %q. Use csv or xlsx", body.Format),
+		})
+		return
+	}
+
+	job, err := h.service.StartExport(service.ExportRequest{
+		Scope:      scope,
+		ProvinceID: body.ProvinceID,
+		Format:     body.Format,
+		Range:      dates,
+	})
+	if errors.Is(err, service.ErrExportQueueFull) {
+		w.Header().Set("Retry-After", "60")
+		writeErrorResponse(w, http.StatusServiceUnavailable, "Too many exports are queued, try again later")
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Location", "/api/v1/exports/"+job.ID)
+	writeJSONResponse(w, http.StatusAccepted, Response{Status: "success", Data: job})
+}
+
+// GetExport godoc
+//
+//	@Summary		Get a case export job
+//	@Description	Reports the status of an export, the rows processed out of the total with the estimated finish, and the download_url once completed. Jobs expire a while after they finish.
+//	@Tags			export
+//	@Produce		json
+//	@Param			id	path		string	true	"Export job ID"
+//	@Success		200	{object}	Response{data=models.ExportJob}
+//	@Failure		404	{object}	Response
+//	@Router			/exports/{id} [get]
+func (h *ExportJobHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	job := h.service.GetJob(id)
+	if job == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Export job "+id+" not found")
+		return
+	}
+	writeSuccessResponse(w, job)
+}
+
+// DownloadExport godoc
+//
+//	@Summary		Download a completed case export
+//	@Description	CSV exports are stored pre-compressed, and served brotli or gzip encoded when Accept-Encoding allows.
+//	@Tags			export
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			id				path		string	true	"Export job ID"
+//	@Param			Accept-Encoding	header		string	false	"Encodings accepted, e.g. br, gzip"
+//	@Success		200				{file}		file	"Exported cases"
+//	@Failure		404				{object}	Response
+//	@Failure		409				{object}	Response	"Export not completed"
+//	@Failure		500				{object}	Response
+//	@Router			/exports/{id}/download [get]
+func (h *ExportJobHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	job := h.service.GetJob(id)
+	if job == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Export job "+id+" not found")
+		return
+	}
+	if job.Status != models.ExportStatusCompleted {
+		writeErrorResponse(w, http.StatusConflict, fmt.Sprintf("Export job %s is %s", id, job.Status))
+		return
+	}
+	filename := "national_cases." + job.Format
+	if job.Scope == models.ExportScopeProvince {
+		filename = "province_cases." + job.Format
+		if job.ProvinceID != "" {
+			filename = fmt.Sprintf("province_%s_cases.%s", job.ProvinceID, job.Format)
+		}
+	}
+	blob, encoding, err := h.service.OpenExport(r.Context(), id, r.Header.Get("Accept-Encoding"))
+	if errors.Is(err, storage.ErrNotFound) {
+		writeErrorResponse(w, http.StatusNotFound, "Export file of job "+id+" not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error opening export %s: %v", id, err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to open export file")
+		return
+	}
+	defer func() { _ = blob.Close() }()
+
+	w.Header().Set("Content-Type", exportContentTypes[job.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Vary", "Accept-Encoding")
+	if encoding != "" {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+	// Local files can serve range requests; bucket objects are streamed
+	if content, ok := blob.ReadCloser.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, filename, blob.ModTime, content)
+		return
+	}
+	if blob.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+	}
+	if _, err := io.Copy(w, blob); err != nil {
+		log.Printf("Error sending export %s: %v", id, err)
+	}
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExportService struct{ mock.Mock }
+
+func (m *MockExportService) StartExport(req service.ExportRequest) (models.ExportJob, error) {
+	args := m.Called(req)
+	return args.Get(0).(models.ExportJob), args.Error(1)
+}
+
+func (m *MockExportService) GetJob(id string) *models.ExportJob {
+	if r := m.Called(id).Get(0); r != nil {
+		return r.(*models.ExportJob)
+	}
+	return nil
+}
+
+func (m *MockExportService) OpenExport(ctx context.Context, id, acceptEncoding string) (*storage.Blob, string, error) {
+	args := m.Called(id, acceptEncoding)
+	if r := args.Get(0); r != nil {
+		return r.(*storage.Blob), args.String(1), args.Error(2)
+	}
+	return nil, args.String(1), args.Error(2)
+}
+
+func exportJobRouter(svc service.ExportServiceInterface) *mux.Router {
+	h := NewExportJobHandler(svc)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/exports", h.CreateExport).Methods("POST")
+	router.HandleFunc("/api/v1/exports/{id}", h.GetExport).Methods("GET")
+	router.HandleFunc("/api/v1/exports/{id}/download", h.DownloadExport).Methods("GET")
+	return router
+}
+
+func TestExportJobHandler_CreateExport(t *testing.T) {
+	svc := new(MockExportService)
+	svc.On("StartExport", service.ExportRequest{
+		Scope:      models.ExportScopeProvince,
+		ProvinceID: "72",
+		Format:     models.ExportFormatXLSX,
+		Range:      testDateRange("2021-01-01", "2021-12-31"),
+	}).Return(models.ExportJob{ID: "abc", Status: models.ExportStatusQueued}, nil)
+
+	rr := httptest.NewRecorder()
+	body := `{"province_id": "72", "start_date": "2021-01-01", "end_date": "2021-12-31"}`
+	exportJobRouter(svc).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/exports", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/api/v1/exports/abc", rr.Header().Get("Location"))
+	assert.Contains(t, rr.Body.String(), `"status":"queued"`)
+	svc.AssertExpectations(t)
+}
+
+func TestExportJobHandler_CreateExport_Invalid(t *testing.T) {
+	tests := []struct {
+		body string
+		code string
+	}{
+		{`{"format": "pdf"}`, ErrCodeInvalidFilter},
+		{`{"scope": "regency"}`, ErrCodeInvalidScope},
+		{`{"scope": "national", "province_id": "72"}`, ErrCodeInvalidScope},
+		{`{"start_date": "01-01-2021"}`, ErrCodeInvalidDateFormat},
+	}
+	for _, tt := range tests {
+		svc := new(MockExportService)
+		rr := httptest.NewRecorder()
+		exportJobRouter(svc).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/exports", strings.NewReader(tt.body)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, tt.body)
+		var response Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, tt.code, response.Code, tt.body)
+		svc.AssertNotCalled(t, "StartExport", mock.Anything)
+	}
+}
+
+func TestExportJobHandler_CreateExport_QueueFull(t *testing.T) {
+	svc := new(MockExportService)
+	svc.On("StartExport", mock.Anything).Return(models.ExportJob{}, service.ErrExportQueueFull)
+
+	rr := httptest.NewRecorder()
+	exportJobRouter(svc).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/exports", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+}
+
+func TestExportJobHandler_GetExport(t *testing.T) {
+	eta := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	svc := new(MockExportService)
+	svc.On("GetJob", "abc").Return(&models.ExportJob{ID: "abc", Status: models.ExportStatusRunning, RowsProcessed: 1000, TotalRows: 3000, ETA: &eta})
+	svc.On("GetJob", "missing").Return(nil)
+	router := exportJobRouter(svc)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/exports/abc", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"rows_processed":1000,"total_rows":3000,"eta":"2021-08-01T12:00:00Z"`)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/exports/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestExportJobHandler_DownloadExport(t *testing.T) {
+	store := storage.NewLocal(t.TempDir())
+	_, err := store.Put(context.Background(), "export-abc.csv", strings.NewReader("day,date\n"))
+	require.NoError(t, err)
+	blob, err := store.Open(context.Background(), "export-abc.csv")
+	require.NoError(t, err)
+	svc := new(MockExportService)
+	svc.On("GetJob", "abc").Return(&models.ExportJob{ID: "abc", Status: models.ExportStatusCompleted, Scope: models.ExportScopeProvince, ProvinceID: "72", Format: models.ExportFormatCSV, File: "export-abc.csv"})
+	svc.On("OpenExport", "abc", "").Return(blob, "", nil)
+	svc.On("GetJob", "busy").Return(&models.ExportJob{ID: "busy", Status: models.ExportStatusRunning})
+	router := exportJobRouter(svc)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/exports/abc/download", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "province_72_cases.csv")
+	assert.Equal(t, "bytes", rr.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "day,date\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/exports/busy/download", nil))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+// Files of a bucket store are streamed from the download of the object
+func TestExportJobHandler_DownloadExport_Streamed(t *testing.T) {
+	svc := new(MockExportService)
+	svc.On("GetJob", "abc").Return(&models.ExportJob{ID: "abc", Status: models.ExportStatusCompleted, Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	svc.On("OpenExport", "abc", "").Return(&storage.Blob{ReadCloser: io.NopCloser(strings.NewReader("day,date\n")), Size: 9}, "", nil)
+	svc.On("GetJob", "gone").Return(&models.ExportJob{ID: "gone", Status: models.ExportStatusCompleted, Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	svc.On("OpenExport", "gone", "").Return(nil, "", storage.ErrNotFound)
+	router := exportJobRouter(svc)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/exports/abc/download", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "9", rr.Header().Get("Content-Length"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "national_cases.csv")
+	assert.Equal(t, "day,date\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/exports/gone/download", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// Pre-compressed variants are served with their Content-Encoding
+func TestExportJobHandler_DownloadExport_Encoded(t *testing.T) {
+	svc := new(MockExportService)
+	svc.On("GetJob", "abc").Return(&models.ExportJob{ID: "abc", Status: models.ExportStatusCompleted, Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	svc.On("OpenExport", "abc", "gzip, br").Return(&storage.Blob{ReadCloser: io.NopCloser(strings.NewReader("compressed")), Size: 10}, storage.EncodingBrotli, nil)
+	router := exportJobRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/exports/abc/download", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "br", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "10", rr.Header().Get("Content-Length"))
+	assert.Equal(t, "compressed", rr.Body.String())
+}
+
+func TestRenderExport(t *testing.T) {
+	national := []models.NationalCase{{ID: 1, Day: 1, Date: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Positive: 10}}
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderExport(&buf, service.ExportRequest{Scope: models.ExportScopeNational, Format: models.ExportFormatCSV}, national, nil))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, models.NationalCaseCSVHeader, records[0])
+	assert.Len(t, records, 2)
+
+	// An empty province export still has the province columns
+	buf.Reset()
+	require.NoError(t, RenderExport(&buf, service.ExportRequest{Scope: models.ExportScopeProvince, Format: models.ExportFormatCSV}, nil, nil))
+	assert.Equal(t, strings.Join(models.ProvinceCaseCSVHeader, ",")+"\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, RenderExport(&buf, service.ExportRequest{Scope: models.ExportScopeNational, Format: models.ExportFormatXLSX}, national, nil))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("PK")), "an xlsx workbook is a zip archive")
+}
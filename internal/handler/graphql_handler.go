@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed graphql_schema.graphql
+var graphqlSchema string
+
+// Bounds of GraphQL requests, so one request cannot fan out into an
+// unbounded number of queries
+const (
+	graphqlMaxBodyBytes   = 64 << 10
+	graphqlMaxQueryLength = 16 << 10
+	graphqlMaxDepth       = 4
+)
+
+// GraphQLHandler serves /graphql, where clients query national cases,
+// provinces and province cases for only the fields they need
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates a GraphQLHandler resolving queries with the
+// same CovidService as the REST endpoints
+func NewGraphQLHandler(covidService service.CovidService) *GraphQLHandler {
+	schema := graphql.MustParseSchema(graphqlSchema, &graphqlResolver{covidService: covidService},
+		graphql.UseFieldResolvers(),
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxQueryLength(graphqlMaxQueryLength),
+	)
+	return &GraphQLHandler{schema: schema}
+}
+
+// graphqlRequest is a GraphQL request as sent over HTTP
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query godoc
+// @Summary Query cases with GraphQL
+// @Description Runs a GraphQL query against national cases, provinces and province cases, returning only the requested fields. POST a JSON body with query, operationName and variables, or GET with ?query=. The response is a GraphQL response with data and errors rather than the usual envelope. Queries may nest up to 4 levels.
+// @Tags national
+// @Accept json
+// @Produce json
+// @Param request body graphqlRequest false "GraphQL request"
+// @Param query query string false "GraphQL query, for GET requests"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} Response
+// @Router /graphql [post]
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req graphqlRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, "Invalid variables: "+err.Error())
+				return
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphqlMaxBodyBytes)).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid GraphQL request: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "The query is required")
+		return
+	}
+
+	response := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding GraphQL response: %v", err)
+	}
+}
+
+// graphqlResolver resolves the Query type
+type graphqlResolver struct {
+	covidService service.CovidService
+}
+
+// caseListArgs are the arguments of the case lists
+type caseListArgs struct {
+	StartDate *string
+	EndDate   *string
+	Range     *string
+	Sort      *string
+	Limit     int32
+	Offset    int32
+}
+
+type provinceCaseListArgs struct {
+	ProvinceID *graphql.ID
+	caseListArgs
+}
+
+func (r *graphqlResolver) NationalCases(ctx context.Context, args caseListArgs) (*nationalCasePage, error) {
+	opts, err := r.queryOptions(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	opts.Columns = selectedColumns(ctx, graphqlNationalColumns)
+
+	cases, total, err := r.covidService.ListNationalCases(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	page := &nationalCasePage{
+		Total:  int32(total),
+		Limit:  int32(opts.Page.Limit),
+		Offset: int32(opts.Page.Offset),
+		Items:  make([]*graphqlNationalCase, len(cases)),
+	}
+	for i := range cases {
+		page.Items[i] = newGraphQLNationalCase(&cases[i])
+	}
+	return page, nil
+}
+
+func (r *graphqlResolver) LatestNationalCase(ctx context.Context) (*graphqlNationalCase, error) {
+	nationalCase, err := r.covidService.GetLatestNationalCase(ctx)
+	if err != nil || nationalCase == nil {
+		return nil, err
+	}
+	return newGraphQLNationalCase(nationalCase), nil
+}
+
+func (r *graphqlResolver) NationalCase(ctx context.Context, args struct{ Day int32 }) (*graphqlNationalCase, error) {
+	nationalCase, err := r.covidService.GetNationalCaseByDay(ctx, int64(args.Day))
+	if err != nil || nationalCase == nil {
+		return nil, err
+	}
+	return newGraphQLNationalCase(nationalCase), nil
+}
+
+func (r *graphqlResolver) Provinces(ctx context.Context) ([]*graphqlProvince, error) {
+	provinces, err := r.covidService.GetProvinces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*graphqlProvince, len(provinces))
+	for i := range provinces {
+		result[i] = newGraphQLProvince(&provinces[i])
+	}
+	return result, nil
+}
+
+func (r *graphqlResolver) Province(ctx context.Context, args struct{ ID graphql.ID }) (*graphqlProvince, error) {
+	province, err := r.covidService.GetProvinceByID(ctx, string(args.ID))
+	if err != nil || province == nil {
+		return nil, err
+	}
+	return newGraphQLProvince(province), nil
+}
+
+func (r *graphqlResolver) ProvinceCases(ctx context.Context, args provinceCaseListArgs) (*provinceCasePage, error) {
+	opts, err := r.queryOptions(ctx, args.caseListArgs)
+	if err != nil {
+		return nil, err
+	}
+	if args.ProvinceID != nil {
+		opts.ProvinceID = string(*args.ProvinceID)
+	}
+	opts.Columns = selectedColumns(ctx, graphqlProvinceColumns)
+
+	cases, total, err := r.covidService.ListProvinceCases(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	page := &provinceCasePage{
+		Total:  int32(total),
+		Limit:  int32(opts.Page.Limit),
+		Offset: int32(opts.Page.Offset),
+		Items:  make([]*graphqlProvinceCase, len(cases)),
+	}
+	for i := range cases {
+		page.Items[i] = newGraphQLProvinceCase(&cases[i])
+	}
+	return page, nil
+}
+
+// queryOptions turns the list arguments into the options of the case lists,
+// with the checks of the REST parameters
+func (r *graphqlResolver) queryOptions(ctx context.Context, args caseListArgs) (service.QueryOptions, error) {
+	startDate, endDate := deref(args.StartDate), deref(args.EndDate)
+	if preset := deref(args.Range); preset != "" {
+		if startDate != "" || endDate != "" {
+			return service.QueryOptions{}, errors.New("range cannot be combined with startDate or endDate")
+		}
+		var err error
+		startDate, endDate, err = r.covidService.ResolveDateRange(ctx, preset)
+		if errors.Is(err, service.ErrInvalidDateRangePreset) {
+			return service.QueryOptions{}, fmt.Errorf("invalid range %q, use one of %s", preset, strings.Join(service.DateRangePresets, ", "))
+		}
+		if err != nil {
+			return service.QueryOptions{}, err
+		}
+	}
+	dates, err := service.ParseDateRange(startDate, endDate)
+	if err != nil {
+		return service.QueryOptions{}, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+	sort, err := utils.ParseSort(deref(args.Sort), utils.IsValidSortField)
+	if err != nil {
+		return service.QueryOptions{}, err
+	}
+	limit, offset := utils.ValidatePaginationParams(int(args.Limit), int(args.Offset))
+	return service.QueryOptions{Range: dates, Sort: sort, Page: service.Page{Limit: limit, Offset: offset}}, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// graphqlNationalColumns maps the fields of NationalCase to the columns they
+// are read from. Fields without columns come from the key columns or other
+// tables.
+var graphqlNationalColumns = map[string][]string{
+	"dailyPositive":       {"positive"},
+	"dailyRecovered":      {"recovered"},
+	"dailyDeceased":       {"deceased"},
+	"dailyActive":         {"positive", "recovered", "deceased"},
+	"cumulativePositive":  {"cumulative_positive"},
+	"cumulativeRecovered": {"cumulative_recovered"},
+	"cumulativeDeceased":  {"cumulative_deceased"},
+	"cumulativeActive":    {"cumulative_positive", "cumulative_recovered", "cumulative_deceased"},
+	"rt":                  {"rt"},
+	"rtUpper":             {"rt_upper"},
+	"rtLower":             {"rt_lower"},
+}
+
+// graphqlProvinceColumns is graphqlNationalColumns for ProvinceCase
+var graphqlProvinceColumns = map[string][]string{
+	"dailyPositive":         {"positive"},
+	"dailyRecovered":        {"recovered"},
+	"dailyDeceased":         {"deceased"},
+	"dailyActive":           {"positive", "recovered", "deceased"},
+	"dailyOdpActive":        {"person_under_observation", "finished_person_under_observation"},
+	"dailyOdpFinished":      {"finished_person_under_observation"},
+	"dailyPdpActive":        {"person_under_supervision", "finished_person_under_supervision"},
+	"dailyPdpFinished":      {"finished_person_under_supervision"},
+	"cumulativePositive":    {"cumulative_positive"},
+	"cumulativeRecovered":   {"cumulative_recovered"},
+	"cumulativeDeceased":    {"cumulative_deceased"},
+	"cumulativeActive":      {"cumulative_positive", "cumulative_recovered", "cumulative_deceased"},
+	"cumulativeOdpActive":   {"cumulative_person_under_observation", "cumulative_finished_person_under_observation"},
+	"cumulativeOdpFinished": {"cumulative_finished_person_under_observation"},
+	"cumulativeOdpTotal":    {"cumulative_person_under_observation"},
+	"cumulativePdpActive":   {"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision"},
+	"cumulativePdpFinished": {"cumulative_finished_person_under_supervision"},
+	"cumulativePdpTotal":    {"cumulative_person_under_supervision"},
+	"rt":                    {"rt"},
+	"rtUpper":               {"rt_upper"},
+	"rtLower":               {"rt_lower"},
+}
+
+// selectedColumns returns the columns of the item fields selected in a case
+// list query, so only those are read besides the key columns
+func selectedColumns(ctx context.Context, columnsOf map[string][]string) []string {
+	var columns []string
+	seen := map[string]bool{}
+	for _, path := range graphql.SelectedFieldNames(ctx) {
+		field, ok := strings.CutPrefix(path, "items.")
+		if !ok {
+			continue
+		}
+		for _, column := range columnsOf[field] {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		// Naming a key column reads the key columns only
+		return []string{"day"}
+	}
+	return columns
+}
+
+type nationalCasePage struct {
+	Total  int32
+	Limit  int32
+	Offset int32
+	Items  []*graphqlNationalCase
+}
+
+type provinceCasePage struct {
+	Total  int32
+	Limit  int32
+	Offset int32
+	Items  []*graphqlProvinceCase
+}
+
+type graphqlProvince struct {
+	ID   graphql.ID
+	Name string
+}
+
+func newGraphQLProvince(p *models.Province) *graphqlProvince {
+	return &graphqlProvince{ID: graphql.ID(p.ID), Name: p.Name}
+}
+
+type graphqlDailyTests struct {
+	Pcr               int32
+	Antigen           int32
+	CumulativePcr     int32
+	CumulativeAntigen int32
+}
+
+func newGraphQLDailyTests(t *models.DailyTests) *graphqlDailyTests {
+	if t == nil {
+		return nil
+	}
+	return &graphqlDailyTests{
+		Pcr:               int32(t.PCR),
+		Antigen:           int32(t.Antigen),
+		CumulativePcr:     int32(t.CumulativePCR),
+		CumulativeAntigen: int32(t.CumulativeAntigen),
+	}
+}
+
+// graphqlCaseCounts are the fields national and province cases share
+type graphqlCaseCounts struct {
+	Day                 int32
+	Date                string
+	DailyPositive       int32
+	DailyRecovered      int32
+	DailyDeceased       int32
+	DailyActive         int32
+	CumulativePositive  int32
+	CumulativeRecovered int32
+	CumulativeDeceased  int32
+	CumulativeActive    int32
+	Rt                  *float64
+	RtUpper             *float64
+	RtLower             *float64
+	Tests               *graphqlDailyTests
+}
+
+type graphqlNationalCase struct {
+	graphqlCaseCounts
+}
+
+func newGraphQLNationalCase(c *models.NationalCase) *graphqlNationalCase {
+	return &graphqlNationalCase{graphqlCaseCounts{
+		Day:                 int32(c.Day),
+		Date:                c.Date.Format("2006-01-02"),
+		DailyPositive:       int32(c.Positive),
+		DailyRecovered:      int32(c.Recovered),
+		DailyDeceased:       int32(c.Deceased),
+		DailyActive:         int32(c.Positive - c.Recovered - c.Deceased),
+		CumulativePositive:  int32(c.CumulativePositive),
+		CumulativeRecovered: int32(c.CumulativeRecovered),
+		CumulativeDeceased:  int32(c.CumulativeDeceased),
+		CumulativeActive:    int32(c.CumulativePositive - c.CumulativeRecovered - c.CumulativeDeceased),
+		Rt:                  c.Rt,
+		RtUpper:             c.RtUpper,
+		RtLower:             c.RtLower,
+		Tests:               newGraphQLDailyTests(c.Tests),
+	}}
+}
+
+type graphqlProvinceCase struct {
+	graphqlCaseCounts
+	Province              *graphqlProvince
+	DailyOdpActive        int32
+	DailyOdpFinished      int32
+	DailyPdpActive        int32
+	DailyPdpFinished      int32
+	CumulativeOdpActive   int32
+	CumulativeOdpFinished int32
+	CumulativeOdpTotal    int32
+	CumulativePdpActive   int32
+	CumulativePdpFinished int32
+	CumulativePdpTotal    int32
+}
+
+func newGraphQLProvinceCase(c *models.ProvinceCaseWithDate) *graphqlProvinceCase {
+	province := &graphqlProvince{ID: graphql.ID(c.ProvinceID)}
+	if c.Province != nil {
+		province = newGraphQLProvince(c.Province)
+	}
+	return &graphqlProvinceCase{
+		graphqlCaseCounts: graphqlCaseCounts{
+			Day:                 int32(c.Day),
+			Date:                c.Date.Format("2006-01-02"),
+			DailyPositive:       int32(c.Positive),
+			DailyRecovered:      int32(c.Recovered),
+			DailyDeceased:       int32(c.Deceased),
+			DailyActive:         int32(c.Positive - c.Recovered - c.Deceased),
+			CumulativePositive:  int32(c.CumulativePositive),
+			CumulativeRecovered: int32(c.CumulativeRecovered),
+			CumulativeDeceased:  int32(c.CumulativeDeceased),
+			CumulativeActive:    int32(c.CumulativePositive - c.CumulativeRecovered - c.CumulativeDeceased),
+			Rt:                  c.Rt,
+			RtUpper:             c.RtUpper,
+			RtLower:             c.RtLower,
+			Tests:               newGraphQLDailyTests(c.Tests),
+		},
+		Province:              province,
+		DailyOdpActive:        int32(c.PersonUnderObservation - c.FinishedPersonUnderObservation),
+		DailyOdpFinished:      int32(c.FinishedPersonUnderObservation),
+		DailyPdpActive:        int32(c.PersonUnderSupervision - c.FinishedPersonUnderSupervision),
+		DailyPdpFinished:      int32(c.FinishedPersonUnderSupervision),
+		CumulativeOdpActive:   int32(c.CumulativePersonUnderObservation - c.CumulativeFinishedPersonUnderObservation),
+		CumulativeOdpFinished: int32(c.CumulativeFinishedPersonUnderObservation),
+		CumulativeOdpTotal:    int32(c.CumulativePersonUnderObservation),
+		CumulativePdpActive:   int32(c.CumulativePersonUnderSupervision - c.CumulativeFinishedPersonUnderSupervision),
+		CumulativePdpFinished: int32(c.CumulativeFinishedPersonUnderSupervision),
+		CumulativePdpTotal:    int32(c.CumulativePersonUnderSupervision),
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type graphqlTestResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, h *GraphQLHandler, query string, variables map[string]interface{}) graphqlTestResponse {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(string(body)))
+	rr := httptest.NewRecorder()
+	h.Query(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var resp graphqlTestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestGraphQLHandler_NationalCases(t *testing.T) {
+	mockService := new(MockCovidService)
+	rt := 1.1
+	cases := []models.NationalCase{
+		{Day: 2, Date: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC), Positive: 10, Recovered: 3, Deceased: 1, Rt: &rt},
+	}
+	mockService.On("ListNationalCases", mock.MatchedBy(func(opts service.QueryOptions) bool {
+		return opts.Range == testDateRange("2020-03-01", "2020-03-31") &&
+			opts.Sort == utils.SortParams{Field: "positive", Order: "desc"} &&
+			opts.Page == service.Page{Limit: 10, Offset: 5} &&
+			assert.ObjectsAreEqual([]string{"positive", "recovered", "deceased", "rt"}, opts.Columns)
+	})).Return(cases, 21, nil)
+
+	h := NewGraphQLHandler(mockService)
+	resp := postGraphQL(t, h, `query($start: String, $end: String) {
+		nationalCases(startDate: $start, endDate: $end, sort: "positive:desc", limit: 10, offset: 5) {
+			total limit offset items { day date dailyPositive dailyActive rt }
+		}
+	}`, map[string]interface{}{"start": "2020-03-01", "end": "2020-03-31"})
+
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"total": 21, "limit": 10, "offset": 5, "items": [
+		{"day": 2, "date": "2020-03-03", "dailyPositive": 10, "dailyActive": 6, "rt": 1.1}
+	]}`, string(resp.Data["nationalCases"]))
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_KeyFieldsOnly(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", mock.MatchedBy(func(opts service.QueryOptions) bool {
+		return opts.ProvinceID == "72" && assert.ObjectsAreEqual([]string{"day"}, opts.Columns)
+	})).Return([]models.ProvinceCaseWithDate{}, 0, nil)
+
+	h := NewGraphQLHandler(mockService)
+	resp := postGraphQL(t, h, `{ provinceCases(provinceId: "72") { total items { day date } } }`, nil)
+
+	require.Empty(t, resp.Errors)
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_ProvinceCases(t *testing.T) {
+	mockService := new(MockCovidService)
+	cases := []models.ProvinceCaseWithDate{{
+		ProvinceCase: models.ProvinceCase{
+			Day: 1, ProvinceID: "72",
+			PersonUnderObservation: 5, FinishedPersonUnderObservation: 2,
+			CumulativePersonUnderObservation: 9, CumulativeFinishedPersonUnderObservation: 4,
+			Province: &models.Province{ID: "72", Name: "Sulawesi Tengah"},
+		},
+		Date: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+	}}
+	mockService.On("ResolveDateRange", "last30d").Return("2020-02-01", "2020-03-02", nil)
+	mockService.On("ListProvinceCases", mock.MatchedBy(func(opts service.QueryOptions) bool {
+		return opts.Range == testDateRange("2020-02-01", "2020-03-02") &&
+			assert.ObjectsAreEqual([]string{"person_under_observation", "finished_person_under_observation",
+				"cumulative_person_under_observation", "cumulative_finished_person_under_observation"}, opts.Columns)
+	})).Return(cases, 1, nil)
+
+	h := NewGraphQLHandler(mockService)
+	resp := postGraphQL(t, h, `{ provinceCases(range: "last30d") {
+		items { province { id name } dailyOdpActive cumulativeOdpActive cumulativeOdpTotal }
+	} }`, nil)
+
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"items": [{"province": {"id": "72", "name": "Sulawesi Tengah"},
+		"dailyOdpActive": 3, "cumulativeOdpActive": 5, "cumulativeOdpTotal": 9}]}`, string(resp.Data["provinceCases"]))
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_Provinces(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("GetProvinces").Return([]models.Province{{ID: "72", Name: "Sulawesi Tengah"}}, nil)
+	mockService.On("GetProvinceByID", "99").Return(nil, nil)
+
+	h := NewGraphQLHandler(mockService)
+	resp := postGraphQL(t, h, `{ provinces { id name } province(id: "99") { name } }`, nil)
+
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `[{"id": "72", "name": "Sulawesi Tengah"}]`, string(resp.Data["provinces"]))
+	assert.JSONEq(t, `null`, string(resp.Data["province"]))
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"invalid sort field", `{ nationalCases(sort: "bogus") { total } }`, `invalid sort field "bogus"`},
+		{"invalid date", `{ nationalCases(startDate: "2020-13-01", endDate: "2020-12-31") { total } }`, "invalid date format"},
+		{"range with dates", `{ nationalCases(range: "last30d", startDate: "2020-01-01") { total } }`, "range cannot be combined"},
+		{"unknown field", `{ nationalCases { items { bogus } } }`, "bogus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCovidService)
+			h := NewGraphQLHandler(mockService)
+			resp := postGraphQL(t, h, tt.query, nil)
+
+			require.NotEmpty(t, resp.Errors)
+			assert.Contains(t, resp.Errors[0].Message, tt.want)
+			mockService.AssertNotCalled(t, "ListNationalCases", mock.Anything)
+		})
+	}
+}
+
+func TestGraphQLHandler_Get(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("GetNationalCaseByDay", int64(3)).Return(&models.NationalCase{Day: 3, Positive: 7}, nil)
+
+	h := NewGraphQLHandler(mockService)
+	query := url.Values{"query": {`query($day: Int!) { nationalCase(day: $day) { day dailyPositive } }`}, "variables": {`{"day": 3}`}}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/graphql?"+query.Encode(), nil)
+	rr := httptest.NewRecorder()
+	h.Query(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data": {"nationalCase": {"day": 3, "dailyPositive": 7}}}`, rr.Body.String())
+}
+
+func TestGraphQLHandler_BadRequest(t *testing.T) {
+	h := NewGraphQLHandler(new(MockCovidService))
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed JSON", `{"query":`},
+		{"missing query", `{"variables": {}}`},
+		{"body too large", `{"query": "` + strings.Repeat(" ", graphqlMaxBodyBytes) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h.Query(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
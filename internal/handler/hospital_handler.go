@@ -31,7 +31,7 @@ func (h *HospitalHandler) GetHospitals(w http.ResponseWriter, r *http.Request) {
 	p := parsePaginationParams(r)
 
 	if p.LoadAll {
-		hospitals, err := h.service.GetHospitals()
+		hospitals, err := h.service.GetHospitals(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -40,7 +40,7 @@ func (h *HospitalHandler) GetHospitals(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hospitals, total, err := h.service.GetHospitalsPaginated(p.PerPage, p.Offset)
+	hospitals, total, err := h.service.GetHospitalsPaginated(r.Context(), p.PerPage, p.Offset)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -61,7 +61,7 @@ func (h *HospitalHandler) GetHospitalByCode(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	code := vars["code"]
 
-	hospital, err := h.service.GetHospitalByCode(code)
+	hospital, err := h.service.GetHospitalByCode(r.Context(), code)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -14,16 +15,16 @@ import (
 
 type MockHospitalService struct{ mock.Mock }
 
-func (m *MockHospitalService) GetHospitalsPaginated(limit, offset int) ([]models.Hospital, int, error) {
+func (m *MockHospitalService) GetHospitalsPaginated(ctx context.Context, limit, offset int) ([]models.Hospital, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.Hospital), args.Int(1), args.Error(2)
 }
 
-func (m *MockHospitalService) GetHospitals() ([]models.Hospital, error) {
+func (m *MockHospitalService) GetHospitals(ctx context.Context) ([]models.Hospital, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Hospital), args.Error(1)
 }
-func (m *MockHospitalService) GetHospitalByCode(code string) (*models.Hospital, error) {
+func (m *MockHospitalService) GetHospitalByCode(ctx context.Context, code string) (*models.Hospital, error) {
 	args := m.Called(code)
 	if r := args.Get(0); r != nil {
 		return r.(*models.Hospital), args.Error(1)
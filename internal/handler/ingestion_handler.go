@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// maxIngestionBodyBytes bounds a submitted batch; a full year of daily rows for
+// every province fits comfortably
+const maxIngestionBodyBytes = 8 << 20
+
+// defaultIngestionSource is recorded in sync_log when the request does not name a source
+const defaultIngestionSource = "api"
+
+// IngestionHandler handles authenticated case data submission.
+type IngestionHandler struct {
+	service service.IngestionServiceInterface
+}
+
+// NewIngestionHandler creates a new IngestionHandler.
+func NewIngestionHandler(service service.IngestionServiceInterface) *IngestionHandler {
+	return &IngestionHandler{service: service}
+}
+
+// SubmitNationalCases godoc
+//
+//	@Summary		Submit national case data
+//	@Description	Upserts one national case row or an array of rows, keyed by day, as a tracked sync run. Rows identical to the stored data are left alone. Requires X-API-Key header matching INGEST_API_KEY env var.
+//	@Tags			ingestion
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-API-Key	header		string				true	"Ingestion API key"
+//	@Param			source		query		string				false	"Source recorded for the sync run (default: api)"
+//	@Param			dry_run		query		boolean				false	"Report the rows that would change without committing"
+//	@Param			cases		body		[]models.NationalCase	true	"National case rows"
+//	@Success		200			{object}	Response{data=models.SyncSummary}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		409			{object}	Response{data=service.DuplicateCaseError}
+//	@Router			/national [post]
+func (h *IngestionHandler) SubmitNationalCases(w http.ResponseWriter, r *http.Request) {
+	if !authorizeIngestion(w, r) {
+		return
+	}
+	rows, err := decodeCaseRows[models.NationalCase](w, r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.ingest(w, r, models.IngestionBatch{Source: ingestionSource(r), National: rows})
+}
+
+// SubmitProvinceCases godoc
+//
+//	@Summary		Submit province case data
+//	@Description	Upserts one province case row or an array of rows for the province, keyed by day, as a tracked sync run. province_id may be omitted from the rows; when given it must match the path. Requires X-API-Key header matching INGEST_API_KEY env var.
+//	@Tags			ingestion
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-API-Key	header		string				true	"Ingestion API key"
+//	@Param			provinceId	path		string				true	"Province ID"
+//	@Param			source		query		string				false	"Source recorded for the sync run (default: api)"
+//	@Param			dry_run		query		boolean				false	"Report the rows that would change without committing"
+//	@Param			cases		body		[]models.ProvinceCase	true	"Province case rows"
+//	@Success		200			{object}	Response{data=models.SyncSummary}
+//	@Failure		400			{object}	Response
+//	@Failure		401			{object}	map[string]string
+//	@Failure		409			{object}	Response{data=service.DuplicateCaseError}
+//	@Router			/provinces/{provinceId}/cases [post]
+func (h *IngestionHandler) SubmitProvinceCases(w http.ResponseWriter, r *http.Request) {
+	if !authorizeIngestion(w, r) {
+		return
+	}
+	provinceID := mux.Vars(r)["provinceId"]
+	rows, err := decodeCaseRows[models.ProvinceCase](w, r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for i := range rows {
+		if rows[i].ProvinceID == "" {
+			rows[i].ProvinceID = provinceID
+		} else if rows[i].ProvinceID != provinceID {
+			writeErrorResponse(w, http.StatusBadRequest,
+				fmt.Sprintf("row %d has province_id %q but was submitted for province %q", i, rows[i].ProvinceID, provinceID))
+			return
+		}
+	}
+	h.ingest(w, r, models.IngestionBatch{Source: ingestionSource(r), Province: rows})
+}
+
+func (h *IngestionHandler) ingest(w http.ResponseWriter, r *http.Request, batch models.IngestionBatch) {
+	dryRun := isDryRun(r)
+	summary, err := h.service.Ingest(r.Context(), batch, dryRun)
+	if err != nil {
+		var dup *service.DuplicateCaseError
+		switch {
+		case errors.Is(err, service.ErrInvalidIngestionBatch):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &dup):
+			// Nothing of the batch was written; the client can compare its row
+			// with the stored one and resubmit
+			writeJSONResponse(w, http.StatusConflict, Response{
+				Status: "error",
+				Error:  dup.Error(),
+				Data:   dup,
+			})
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	if dryRun {
+		writeDryRunResponse(w, summary)
+		return
+	}
+	writeSuccessResponse(w, summary)
+}
+
+// decodeCaseRows reads a single row object or an array of rows from the body
+func decodeCaseRows[T any](w http.ResponseWriter, r *http.Request) ([]T, error) {
+	var raw json.RawMessage
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIngestionBodyBytes))
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	var rows []T
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := strictUnmarshal(raw, &rows); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+	} else {
+		var row T
+		if err := strictUnmarshal(raw, &row); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("request body has no rows")
+	}
+	return rows, nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func ingestionSource(r *http.Request) string {
+	if source := r.URL.Query().Get("source"); source != "" {
+		return source
+	}
+	return defaultIngestionSource
+}
+
+// authorizeIngestion checks the X-API-Key header against the INGEST_API_KEY env
+// var and writes a 401 response when it does not match. Submission is disabled
+// while INGEST_API_KEY is unset, unless the request was authenticated with a
+// write scoped API key.
+func authorizeIngestion(w http.ResponseWriter, r *http.Request) bool {
+	if key, ok := middleware.APIKeyFromContext(r.Context()); ok && key.HasScope(models.ScopeWrite) {
+		return true
+	}
+	apiKey := os.Getenv("INGEST_API_KEY")
+	if apiKey == "" || r.Header.Get("X-API-Key") != apiKey {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unauthorized"}`)) //nolint:errcheck
+		return false
+	}
+	return true
+}
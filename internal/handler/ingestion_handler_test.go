@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIngestionService struct {
+	mock.Mock
+}
+
+func (m *MockIngestionService) Ingest(ctx context.Context, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error) {
+	args := m.Called(batch, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SyncSummary), args.Error(1)
+}
+
+func serveIngestion(svc *MockIngestionService, method, path, key, body string) *httptest.ResponseRecorder {
+	router := SetupRoutes(Services{IngestionService: svc}, nil, false)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIngestionHandler_SubmitNationalCases(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	svc.On("Ingest", mock.MatchedBy(func(b models.IngestionBatch) bool {
+		return b.Source == "api" && len(b.National) == 2 && b.National[1].Day == 2
+	}), false).Return(&models.SyncSummary{RunID: 4, Inserted: 1, Updated: 1}, nil)
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/national", "writer",
+		`[{"day":1,"date":"2020-03-02T00:00:00Z","positive":2,"cumulative_positive":2},
+		  {"day":2,"date":"2020-03-03T00:00:00Z","positive":1,"cumulative_positive":3}]`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"run_id":4`)
+	svc.AssertExpectations(t)
+}
+
+func TestIngestionHandler_SubmitNationalCases_DryRun(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	svc.On("Ingest", mock.Anything, true).Return(&models.SyncSummary{DryRun: true, Inserted: 1}, nil)
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/national?dry_run=true", "writer",
+		`{"day":1,"date":"2020-03-02T00:00:00Z"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "dry run: no changes committed")
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	svc.AssertExpectations(t)
+}
+
+func TestIngestionHandler_SubmitNationalCases_SingleObject(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	svc.On("Ingest", mock.MatchedBy(func(b models.IngestionBatch) bool {
+		return b.Source == "dinkes" && len(b.National) == 1
+	}), false).Return(&models.SyncSummary{RunID: 5}, nil)
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/national?source=dinkes", "writer",
+		`{"day":1,"date":"2020-03-02T00:00:00Z"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestIngestionHandler_RequiresAPIKey(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+
+	assert.Equal(t, http.StatusUnauthorized, serveIngestion(svc, http.MethodPost, "/api/v1/national", "", `{}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveIngestion(svc, http.MethodPost, "/api/v1/national", "wrong", `{}`).Code)
+	svc.AssertNotCalled(t, "Ingest", mock.Anything, mock.Anything)
+}
+
+func TestIngestionHandler_DisabledWithoutAPIKey(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "")
+	svc := new(MockIngestionService)
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/national", "anything", `{}`)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestIngestionHandler_RejectsMalformedBody(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+
+	for _, body := range []string{`not json`, `[]`, `{"day":1,"hospitalised":3}`} {
+		w := serveIngestion(svc, http.MethodPost, "/api/v1/national", "writer", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	svc.AssertNotCalled(t, "Ingest", mock.Anything, mock.Anything)
+}
+
+func TestIngestionHandler_ValidationError(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	svc.On("Ingest", mock.Anything, false).Return(nil, fmt.Errorf("%w: national[0]: date is required", service.ErrInvalidIngestionBatch))
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/national", "writer", `{"day":1}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "date is required")
+}
+
+func TestIngestionHandler_StorageError(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	svc.On("Ingest", mock.Anything, false).Return(nil, errors.New("db down"))
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/national", "writer", `{"day":1}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestIngestionHandler_SubmitProvinceCases_FillsProvinceID(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	svc.On("Ingest", mock.MatchedBy(func(b models.IngestionBatch) bool {
+		return len(b.Province) == 2 && b.Province[0].ProvinceID == "72" && b.Province[1].ProvinceID == "72"
+	}), false).Return(&models.SyncSummary{RunID: 6, Inserted: 2}, nil)
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/provinces/72/cases", "writer",
+		`[{"day":1,"positive":3,"cumulative_positive":3},{"day":2,"province_id":"72"}]`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestIngestionHandler_SubmitProvinceCases_MismatchedProvince(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/provinces/72/cases", "writer", `{"day":1,"province_id":"73"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "Ingest", mock.Anything, mock.Anything)
+}
+
+func TestIngestionHandler_SubmitProvinceCases_Duplicate(t *testing.T) {
+	t.Setenv("INGEST_API_KEY", "writer")
+	svc := new(MockIngestionService)
+	dup := &service.DuplicateCaseError{Table: "province_cases", Key: "province_id=72,day=8", ID: 300,
+		Existing: map[string]interface{}{"day": 8, "province_id": "72", "positive": 7}}
+	svc.On("Ingest", mock.Anything, false).Return(nil, fmt.Errorf("failed to ingest batch from api: %w", dup))
+
+	w := serveIngestion(svc, http.MethodPost, "/api/v1/provinces/72/cases", "writer", `{"day":8,"positive":9}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"error":"province_cases row province_id=72,day=8 already exists (id 300)"`)
+	assert.Contains(t, w.Body.String(), `"existing":{"day":8,"positive":7,"province_id":"72"}`)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// IntegrityHandler handles referential integrity admin endpoints.
+type IntegrityHandler struct {
+	service service.IntegrityServiceInterface
+}
+
+// NewIntegrityHandler creates a new IntegrityHandler.
+func NewIntegrityHandler(service service.IntegrityServiceInterface) *IntegrityHandler {
+	return &IntegrityHandler{service: service}
+}
+
+// GetOrphanReport godoc
+//
+//	@Summary		Report orphaned province case rows
+//	@Description	Lists province_cases rows whose day has no national_cases record or whose province_id has no province. These rows are silently dropped by the province case endpoints.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=models.OrphanReport}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/integrity/orphans [get]
+func (h *IntegrityHandler) GetOrphanReport(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	report, err := h.service.GetOrphanReport(r.Context())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, report)
+}
+
+// QuarantineOrphans godoc
+//
+//	@Summary		Quarantine orphaned province case rows
+//	@Description	Moves orphaned province_cases rows into province_cases_quarantine. Supports dry_run=true.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would move without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/integrity/orphans/quarantine [post]
+func (h *IntegrityHandler) QuarantineOrphans(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	dryRun := isDryRun(r)
+	summary, err := h.service.QuarantineOrphans(r.Context(), dryRun)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if dryRun {
+		writeDryRunResponse(w, *summary)
+		return
+	}
+	writeSuccessResponse(w, summary)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIntegrityService struct{ mock.Mock }
+
+func (m *MockIntegrityService) GetOrphanReport(ctx context.Context) (*models.OrphanReport, error) {
+	args := m.Called()
+	if r := args.Get(0); r != nil {
+		return r.(*models.OrphanReport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIntegrityService) QuarantineOrphans(ctx context.Context, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(dryRun)
+	if r := args.Get(0); r != nil {
+		return r.(*models.ChangeSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestIntegrityHandler_GetOrphanReport(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockIntegrityService)
+	svc.On("GetOrphanReport").Return(models.NewOrphanReport([]models.OrphanProvinceCase{
+		{ID: 4, Day: 900, ProvinceID: "72", Reasons: []string{models.OrphanReasonMissingDay}},
+	}), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/integrity/orphans", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	NewIntegrityHandler(svc).GetOrphanReport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"missing_day":1`)
+	assert.Contains(t, w.Body.String(), models.OrphanReasonMissingDay)
+}
+
+func TestIntegrityHandler_GetOrphanReport_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockIntegrityService)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/integrity/orphans", nil)
+	w := httptest.NewRecorder()
+	NewIntegrityHandler(svc).GetOrphanReport(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	svc.AssertNotCalled(t, "GetOrphanReport")
+}
+
+func TestIntegrityHandler_QuarantineOrphans_DryRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockIntegrityService)
+	svc.On("QuarantineOrphans", true).Return(&models.ChangeSummary{DryRun: true, Deleted: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/integrity/orphans/quarantine?dry_run=true", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	NewIntegrityHandler(svc).QuarantineOrphans(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"deleted":3`)
+	assert.Contains(t, w.Body.String(), "dry run")
+}
+
+func TestIntegrityHandler_QuarantineOrphans_Error(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockIntegrityService)
+	svc.On("QuarantineOrphans", false).Return(nil, errors.New("boom"))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/integrity/orphans/quarantine", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	NewIntegrityHandler(svc).QuarantineOrphans(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
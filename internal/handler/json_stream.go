@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// streamsAll reports whether an ?all=true case list can be streamed rather
+// than loaded whole: only JSON is, and only without moving averages, which
+// need the days before each row, or as_of, which rebuilds the rows from their
+// revisions. CSV is written row by row already.
+func streamsAll(r *http.Request, asOf time.Time, smoothing int) bool {
+	return !wantsCSV(r) && asOf.IsZero() && smoothing == 0
+}
+
+// streamCaseList writes the JSON response writeCaseList writes without
+// pagination, for lists too long to hold in memory. each calls emit with
+// consecutive chunks of rows, and every chunk is encoded and flushed before
+// the next one is read. The meta follows the data, since the freshness is
+// only known once every row was seen, and the number of rows is sent in the
+// X-Result-Count trailer.
+//
+// An error before the first chunk is answered with a 500. Once rows were
+// sent the status cannot change, so the body is left unterminated for the
+// client to fail on.
+func streamCaseList[T any](w http.ResponseWriter, r *http.Request, each func(emit func([]T) error) error) {
+	var sample T
+	view, ok := caseViewOf(w, r, sample)
+	if !ok {
+		return
+	}
+
+	s := &jsonArrayStream{w: w}
+	err := each(func(rows []T) error {
+		if len(rows) == 0 {
+			return nil
+		}
+		// The freshness needs the timestamps that may be stripped next
+		s.describe(listMeta(rows))
+		stripTimestamps(r, rows)
+		return s.write(reshapeRows(rows, view))
+	})
+	if err != nil {
+		if !s.started {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		log.Printf("Error streaming JSON response: %v", err)
+		return
+	}
+	if s.rows == 0 {
+		s.describe(listMeta([]T{}))
+	}
+	if err := s.close(); err != nil {
+		log.Printf("Error streaming JSON response: %v", err)
+	}
+}
+
+// jsonArrayStream writes a success response whose data array is written a
+// chunk at a time
+type jsonArrayStream struct {
+	w       http.ResponseWriter
+	started bool
+	rows    int
+	meta    *ResponseMeta
+	buf     bytes.Buffer
+}
+
+// describe merges the freshness of a chunk into the meta, keeping the latest
+// update seen
+func (s *jsonArrayStream) describe(meta *ResponseMeta) {
+	switch {
+	case meta == nil:
+	case s.meta == nil:
+		s.meta = meta
+	case meta.LastUpdated != nil && (s.meta.LastUpdated == nil || meta.LastUpdated.After(*s.meta.LastUpdated)):
+		s.meta.LastUpdated = meta.LastUpdated
+	}
+}
+
+// start writes the headers and opens the data array
+func (s *jsonArrayStream) start() {
+	s.started = true
+	s.w.Header().Set("Content-Type", "application/json")
+	s.w.Header().Set("Trailer", resultCountHeader)
+	s.w.WriteHeader(http.StatusOK)
+	s.buf.WriteString(`{"status":"success","data":[`)
+}
+
+// write appends the items to the data array and flushes them to the client
+func (s *jsonArrayStream) write(items []interface{}) error {
+	if !s.started {
+		s.start()
+	}
+	for _, item := range items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if s.rows > 0 {
+			s.buf.WriteByte(',')
+		}
+		s.buf.Write(encoded)
+		s.rows++
+	}
+	if _, err := s.w.Write(s.buf.Bytes()); err != nil {
+		return err
+	}
+	s.buf.Reset()
+	if err := http.NewResponseController(s.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// close ends the data array, adds the meta and sets the row count trailer
+func (s *jsonArrayStream) close() error {
+	if !s.started {
+		s.start()
+	}
+	s.buf.WriteByte(']')
+	if meta := withNotices(s.meta); meta != nil {
+		encoded, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		s.buf.WriteString(`,"meta":`)
+		s.buf.Write(encoded)
+	}
+	s.buf.WriteString("}\n")
+	if _, err := s.w.Write(s.buf.Bytes()); err != nil {
+		return err
+	}
+	s.w.Header().Set(resultCountHeader, strconv.Itoa(s.rows))
+	return nil
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allByDate = service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}}
+
+func TestCovidHandler_GetNationalCases_StreamsAll(t *testing.T) {
+	freshness = service.NewFreshnessService(nil)
+	t.Cleanup(func() { freshness = nil })
+	earlier := time.Date(2021, 7, 13, 1, 0, 0, 0, time.UTC)
+	latest := time.Date(2021, 7, 14, 1, 0, 0, 0, time.UTC)
+	mockService := new(MockCovidService)
+	mockService.On("EachNationalCase", allByDate).Return([][]models.NationalCase{
+		{{ID: 1, Day: 1, Positive: 2, RecordTimestamps: models.RecordTimestamps{UpdatedAt: &latest}}},
+		{{ID: 2, Day: 2, Positive: 3, RecordTimestamps: models.RecordTimestamps{UpdatedAt: &earlier}}, {ID: 3, Day: 3, Positive: 5}},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	NewCovidHandler(mockService, nil).GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?all=true", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "3", rr.Result().Trailer.Get(resultCountHeader))
+	var response struct {
+		Status string                        `json:"status"`
+		Data   []models.NationalCaseResponse `json:"data"`
+		Meta   *ResponseMeta                 `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "success", response.Status)
+	require.Len(t, response.Data, 3)
+	assert.Equal(t, int64(5), response.Data[2].Daily.Positive)
+	// Timestamps are stripped, but the freshness is the latest of every chunk
+	assert.NotContains(t, rr.Body.String(), `"updated_at"`)
+	require.NotNil(t, response.Meta)
+	assert.Equal(t, &latest, response.Meta.LastUpdated)
+	assert.NotNil(t, response.Meta.Notices)
+	mockService.AssertExpectations(t)
+}
+
+// A streamed list reads the same as the list written at once
+func TestCovidHandler_GetNationalCases_StreamsAllShapes(t *testing.T) {
+	rt := 1.1
+	cases := []models.NationalCase{{ID: 1, Day: 1, Positive: 2, Rt: &rt}, {ID: 2, Day: 2, Positive: 3}}
+	// Selected fields read only their columns
+	columns := map[string][]string{
+		"&fields=day,daily.positive":  {"day", "positive"},
+		"&shape=flat&fields=rt_value": {"rt"},
+	}
+	for _, query := range []string{"", "&shape=flat", "&legacy=true", "&include=timestamps", "&fields=day,daily.positive", "&shape=flat&fields=rt_value"} {
+		t.Run(query, func(t *testing.T) {
+			target := "/api/v1/national?all=true" + query
+			opts := allByDate
+			opts.Columns = columns[query]
+			mockService := new(MockCovidService)
+			mockService.On("EachNationalCase", opts).Return([][]models.NationalCase{cases[:1], cases[1:]}, nil)
+
+			streamed := httptest.NewRecorder()
+			NewCovidHandler(mockService, nil).GetNationalCases(streamed, httptest.NewRequest("GET", target, nil))
+			whole := httptest.NewRecorder()
+			writeCaseList(whole, httptest.NewRequest("GET", target, nil), "national_cases.csv", models.NationalCaseCSVHeader, models.TransformSliceToResponse(cases), nil)
+
+			require.Equal(t, http.StatusOK, streamed.Code)
+			assert.JSONEq(t, whole.Body.String(), streamed.Body.String())
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	NewCovidHandler(new(MockCovidService), nil).GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?all=true&shape=round", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCovidHandler_GetNationalCases_StreamsNoRows(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("EachNationalCase", allByDate).Return([][]models.NationalCase{}, nil)
+
+	rr := httptest.NewRecorder()
+	NewCovidHandler(mockService, nil).GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?all=true", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"success","data":[]}`, rr.Body.String())
+	assert.Equal(t, "0", rr.Result().Trailer.Get(resultCountHeader))
+}
+
+func TestCovidHandler_GetNationalCases_StreamErrors(t *testing.T) {
+	t.Run("before the first chunk", func(t *testing.T) {
+		mockService := new(MockCovidService)
+		mockService.On("EachNationalCase", allByDate).Return([][]models.NationalCase{}, errors.New("database error"))
+
+		rr := httptest.NewRecorder()
+		NewCovidHandler(mockService, nil).GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?all=true", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Contains(t, rr.Body.String(), "database error")
+	})
+
+	t.Run("after rows were sent", func(t *testing.T) {
+		mockService := new(MockCovidService)
+		mockService.On("EachNationalCase", allByDate).Return([][]models.NationalCase{{{ID: 1, Day: 1}}}, errors.New("database error"))
+
+		rr := httptest.NewRecorder()
+		NewCovidHandler(mockService, nil).GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national?all=true", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		// The body is left unterminated so the client cannot take it as complete
+		assert.False(t, json.Valid(rr.Body.Bytes()))
+	})
+}
+
+// Lists that need every row at once are not streamed
+func TestCovidHandler_GetNationalCases_AllWithoutStreaming(t *testing.T) {
+	for target, asOf := range map[string]time.Time{
+		"/api/v1/national?all=true&format=csv":                 {},
+		"/api/v1/national?all=true&as_of=2021-07-01T00:00:00Z": time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		t.Run(target, func(t *testing.T) {
+			mockService := new(MockCovidService)
+			opts := allByDate
+			opts.AsOf = asOf
+			mockService.On("ListNationalCases", opts).Return([]models.NationalCase{{ID: 1, Day: 1}}, 1, nil)
+
+			rr := httptest.NewRecorder()
+			NewCovidHandler(mockService, nil).GetNationalCases(rr, httptest.NewRequest("GET", target, nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockService.AssertExpectations(t)
+			mockService.AssertNotCalled(t, "EachNationalCase", opts)
+		})
+	}
+}
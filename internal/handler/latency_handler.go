@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// LatencyHandler exposes the per-route latency percentiles collected by the metrics middleware.
+type LatencyHandler struct {
+	reporter service.LatencyReporter
+}
+
+// NewLatencyHandler creates a new LatencyHandler.
+func NewLatencyHandler(reporter service.LatencyReporter) *LatencyHandler {
+	return &LatencyHandler{reporter: reporter}
+}
+
+// GetLatency godoc
+//
+//	@Summary		Per-route latency percentiles
+//	@Description	Returns request count, p50, p95, p99, max and mean latency in milliseconds per route since startup, slowest p99 first. Requests with all=true are reported as a separate route.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.RouteLatency}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/latency [get]
+func (h *LatencyHandler) GetLatency(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	writeSuccessResponse(w, h.reporter.LatencySummary())
+}
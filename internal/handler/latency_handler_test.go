@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubLatencyReporter struct {
+	summary []models.RouteLatency
+}
+
+func (s stubLatencyReporter) LatencySummary() []models.RouteLatency {
+	return s.summary
+}
+
+func TestLatencyHandler_GetLatency(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	h := NewLatencyHandler(stubLatencyReporter{summary: []models.RouteLatency{
+		{Route: "GET /api/v1/national?all=true", Count: 10, P50Ms: 120, P95Ms: 900, P99Ms: 1800},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/latency", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	h.GetLatency(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"p99_ms":1800`)
+}
+
+func TestLatencyHandler_GetLatency_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	h := NewLatencyHandler(stubLatencyReporter{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/latency", nil)
+	w := httptest.NewRecorder()
+	h.GetLatency(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// MaintenanceHandler handles the maintenance endpoints of the /api/v1/admin
+// subrouter, which only serves requests passing requireAdmin.
+type MaintenanceHandler struct {
+	reloader      service.ConfigReloader
+	reaggregation service.ReaggregationServiceInterface
+	rateLimits    service.RateLimitReporter
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler.
+func NewMaintenanceHandler(reloader service.ConfigReloader, reaggregation service.ReaggregationServiceInterface, rateLimits service.RateLimitReporter) *MaintenanceHandler {
+	return &MaintenanceHandler{reloader: reloader, reaggregation: reaggregation, rateLimits: rateLimits}
+}
+
+// ReloadConfig godoc
+//
+//	@Summary		Reload the configuration
+//	@Description	Rereads the environment and the .env file. Changed sections that can change while serving, such as RateLimit, are applied at once; the others are listed under restart_required and keep their running values.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=models.ConfigReload}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		500			{object}	Response
+//	@Router			/admin/config/reload [post]
+func (h *MaintenanceHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	result, err := h.reloader.Reload()
+	if err != nil {
+		log.Printf("Error reloading configuration: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to reload configuration")
+		return
+	}
+	if len(result.Changed) > 0 {
+		log.Printf("Configuration reloaded: applied %v, restart required for %v", result.Applied, result.RestartRequired)
+	}
+	writeSuccessResponse(w, result)
+}
+
+// Reaggregate godoc
+//
+//	@Summary		Recompute derived data
+//	@Description	Recomputes the data derived from the case tables, for when they were changed outside the API: the data source of list responses, the data quality issues and the query cache, which is cleared and warmed again. Every step runs even when an earlier one fails; the response is a 500 listing the failed steps then. Supports dry_run=true.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			dry_run		query		boolean	false	"Report the data quality issues and data source that would change without applying them"
+//	@Success		200			{object}	Response{data=models.ReaggregationResult}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		500			{object}	Response{data=models.ReaggregationResult}
+//	@Router			/admin/reaggregate [post]
+func (h *MaintenanceHandler) Reaggregate(w http.ResponseWriter, r *http.Request) {
+	if isDryRun(r) {
+		summary, err := h.reaggregation.Preview(r.Context())
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeDryRunResponse(w, *summary)
+		return
+	}
+	result := h.reaggregation.Reaggregate(r.Context())
+	if result.Failed() {
+		writeJSONResponse(w, http.StatusInternalServerError, Response{
+			Status: "error",
+			Error:  "Re-aggregation failed",
+			Data:   result,
+		})
+		return
+	}
+	writeSuccessResponse(w, result)
+}
+
+// GetRateLimits godoc
+//
+//	@Summary		Rate limiter state
+//	@Description	Lists the global, route and API key limits with the clients that made requests in their current window, most requests first, and the requests each client has in flight when concurrent requests are capped.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=models.RateLimitSnapshot}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/rate-limits [get]
+func (h *MaintenanceHandler) GetRateLimits(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponse(w, h.rateLimits.Snapshot())
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConfigReloader struct {
+	mock.Mock
+}
+
+func (m *MockConfigReloader) Reload() (*models.ConfigReload, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ConfigReload), args.Error(1)
+}
+
+type MockReaggregationService struct {
+	mock.Mock
+}
+
+func (m *MockReaggregationService) Reaggregate(ctx context.Context) models.ReaggregationResult {
+	return m.Called().Get(0).(models.ReaggregationResult)
+}
+
+func (m *MockReaggregationService) Preview(ctx context.Context) (*models.ChangeSummary, error) {
+	args := m.Called()
+	summary, _ := args.Get(0).(*models.ChangeSummary)
+	return summary, args.Error(1)
+}
+
+type MockRateLimitReporter struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitReporter) Snapshot() models.RateLimitSnapshot {
+	return m.Called().Get(0).(models.RateLimitSnapshot)
+}
+
+func serveAdmin(router http.Handler, method, target, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if key != "" {
+		req.Header.Set("X-Admin-Key", key)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestMaintenanceRoutes_Disabled(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	router := SetupRoutes(Services{
+		CacheInvalidator: new(MockCacheInvalidator),
+		ConfigReloader:   new(MockConfigReloader),
+		Reaggregation:    new(MockReaggregationService),
+		RateLimits:       new(MockRateLimitReporter),
+		Bandwidth:        stubBandwidthReporter{},
+	}, nil, false)
+
+	assert.Equal(t, http.StatusNotFound, serveAdmin(router, "POST", "/api/v1/admin/cache/flush", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, serveAdmin(router, "GET", "/api/v1/admin/rate-limits", "secret").Code)
+	// The /admin endpoints and the reports under /api/v1/admin are off as well
+	assert.Equal(t, http.StatusNotFound, serveAdmin(router, "POST", "/admin/cache/clear", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, serveAdmin(router, "GET", "/admin/outbound", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, serveAdmin(router, "GET", "/api/v1/admin/bandwidth", "secret").Code)
+}
+
+func TestMaintenanceRoutes_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	reloader := new(MockConfigReloader)
+	router := SetupRoutes(Services{ConfigReloader: reloader, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(router, "POST", "/api/v1/admin/config/reload", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(router, "POST", "/api/v1/admin/config/reload", "wrong").Code)
+	reloader.AssertNotCalled(t, "Reload")
+}
+
+func TestMaintenanceHandler_Endpoints(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	invalidator := new(MockCacheInvalidator)
+	invalidator.On("Clear").Once()
+	reloader := new(MockConfigReloader)
+	reloader.On("Reload").Return(&models.ConfigReload{
+		Changed: []string{"RateLimit", "Server"}, Applied: []string{"RateLimit"}, RestartRequired: []string{"Server"},
+	}, nil)
+	reaggregation := new(MockReaggregationService)
+	reaggregation.On("Reaggregate").Return(models.ReaggregationResult{Steps: []models.ReaggregationStep{{Name: "cache", DurationMs: 12}}})
+	rateLimits := new(MockRateLimitReporter)
+	rateLimits.On("Snapshot").Return(models.RateLimitSnapshot{Enabled: true, Limits: []models.RateLimitState{{
+		Scope: "global", RequestsPerMinute: 100, WindowSeconds: 60,
+		Clients: []models.RateLimitClient{{Client: "10.0.0.1", Requests: 40, Remaining: 60}},
+	}}})
+	router := SetupRoutes(Services{
+		CacheInvalidator: invalidator,
+		ConfigReloader:   reloader,
+		Reaggregation:    reaggregation,
+		RateLimits:       rateLimits,
+		Admin:            config.AdminConfig{Enabled: true},
+	}, nil, false)
+
+	rr := serveAdmin(router, "POST", "/api/v1/admin/cache/flush", "secret")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "cache cleared")
+
+	rr = serveAdmin(router, "POST", "/api/v1/admin/config/reload", "secret")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"applied":["RateLimit"],"restart_required":["Server"]`)
+
+	rr = serveAdmin(router, "POST", "/api/v1/admin/reaggregate", "secret")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"steps":[{"name":"cache","duration_ms":12}]`)
+
+	rr = serveAdmin(router, "GET", "/api/v1/admin/rate-limits", "secret")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"clients":[{"client":"10.0.0.1","requests":40,"remaining":60}]`)
+
+	invalidator.AssertExpectations(t)
+}
+
+func TestMaintenanceHandler_Failures(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	reloader := new(MockConfigReloader)
+	reloader.On("Reload").Return(nil, errors.New("failed to read .env"))
+	reaggregation := new(MockReaggregationService)
+	reaggregation.On("Reaggregate").Return(models.ReaggregationResult{Steps: []models.ReaggregationStep{{Name: "data_quality", Error: "db down"}}})
+	router := SetupRoutes(Services{ConfigReloader: reloader, Reaggregation: reaggregation, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	assert.Equal(t, http.StatusInternalServerError, serveAdmin(router, "POST", "/api/v1/admin/config/reload", "secret").Code)
+	rr := serveAdmin(router, "POST", "/api/v1/admin/reaggregate", "secret")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"error":"db down"`)
+}
+
+func TestMaintenanceRoutes_ReaggregateDryRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	reaggregation := new(MockReaggregationService)
+	summary := &models.ChangeSummary{DryRun: true}
+	summary.Add(models.RowChange{Table: "data_quality_issues", Key: "72/310/negative_daily/positive", Action: models.ChangeActionInsert})
+	reaggregation.On("Preview").Return(summary, nil)
+	router := SetupRoutes(Services{ConfigReloader: new(MockConfigReloader), Reaggregation: reaggregation, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	rr := serveAdmin(router, "POST", "/api/v1/admin/reaggregate?dry_run=true", "secret")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"message":"dry run: no changes committed"`)
+	assert.Contains(t, rr.Body.String(), `"dry_run":true,"inserted":1`)
+	reaggregation.AssertNotCalled(t, "Reaggregate")
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/metrics"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultRankingLimit = 10
+	maxRankingLimit     = 100
+	// defaultCalendarMetric is the metric of calendars without ?metric
+	defaultCalendarMetric = "daily_positive"
+	// metricsPageSize is the number of cases read per query when aggregating
+	// province metrics
+	metricsPageSize = 1000
+)
+
+// MetricHandler serves the statistics of a metrics registry
+type MetricHandler struct {
+	covidService service.CovidService
+	registry     *metrics.Registry
+}
+
+// NewMetricHandler creates a new MetricHandler
+func NewMetricHandler(covidService service.CovidService, registry *metrics.Registry) *MetricHandler {
+	return &MetricHandler{covidService: covidService, registry: registry}
+}
+
+// MetricInfo describes a registered metric
+type MetricInfo struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Dependencies []string `json:"dependencies"`
+}
+
+// RankingEntry is a province's position in a metric ranking
+type RankingEntry struct {
+	Rank     int              `json:"rank"`
+	Province *models.Province `json:"province"`
+	Value    float64          `json:"value"`
+	Day      int64            `json:"day"`
+	Date     time.Time        `json:"date"`
+}
+
+// ProvinceMetrics aggregates every metric over a province's cases
+type ProvinceMetrics struct {
+	ProvinceID string                     `json:"province_id"`
+	StartDate  string                     `json:"start_date,omitempty"`
+	EndDate    string                     `json:"end_date,omitempty"`
+	Days       int                        `json:"days"`
+	Metrics    map[string]metrics.Summary `json:"metrics"`
+}
+
+// ListMetrics godoc
+//
+//	@Summary		List metrics
+//	@Description	Lists the statistics accepted by the rankings, province sorting and province metrics endpoints
+//	@Tags			metrics
+//	@Produce		json
+//	@Success		200	{object}	Response{data=[]MetricInfo}
+//	@Router			/metrics [get]
+func (h *MetricHandler) ListMetrics(w http.ResponseWriter, r *http.Request) {
+	all := h.registry.All()
+	infos := make([]MetricInfo, len(all))
+	for i, m := range all {
+		infos[i] = MetricInfo{Name: m.Name(), Description: m.Description(), Dependencies: m.Dependencies()}
+	}
+	writeSuccessResponse(w, infos)
+}
+
+// GetRankings godoc
+//
+//	@Summary		Rank provinces by a metric
+//	@Description	Ranks provinces by a metric of their latest case. Provinces the metric is undefined for, e.g. a rate without confirmed cases, are left out.
+//	@Tags			metrics
+//	@Produce		json
+//	@Param			metric	query		string	true	"Metric name, see /metrics"
+//	@Param			order	query		string	false	"desc (default) or asc"
+//	@Param			limit	query		integer	false	"Number of provinces (default: 10, max: 100)"
+//	@Success		200		{object}	Response{data=[]RankingEntry}
+//	@Failure		400		{object}	Response
+//	@Failure		500		{object}	Response
+//	@Router			/rankings [get]
+func (h *MetricHandler) GetRankings(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("metric")
+	if _, ok := h.registry.Get(name); !ok {
+		writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown metric %q", name))
+		return
+	}
+	order := "desc"
+	if strings.ToLower(r.URL.Query().Get("order")) == "asc" {
+		order = "asc"
+	}
+	limit := utils.ParseIntQueryParam(r, "limit", defaultRankingLimit)
+	if limit <= 0 || limit > maxRankingLimit {
+		limit = defaultRankingLimit
+	}
+
+	provinces, err := h.covidService.GetProvincesWithLatestCase(r.Context())
+	if err != nil {
+		log.Printf("Error loading provinces for ranking: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load province data")
+		return
+	}
+
+	withCase := make([]models.ProvinceWithLatestCase, 0, len(provinces))
+	inputs := make([]metrics.Values, 0, len(provinces))
+	for _, p := range provinces {
+		if p.LatestCase != nil {
+			withCase = append(withCase, p)
+			inputs = append(inputs, metrics.FromProvinceCase(*p.LatestCase))
+		}
+	}
+	ranked, err := h.registry.Rank(name, order, inputs)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	entries := make([]RankingEntry, len(ranked))
+	for i, rk := range ranked {
+		p := withCase[rk.Index]
+		province := p.Province
+		entries[i] = RankingEntry{
+			Rank:     i + 1,
+			Province: &province,
+			Value:    rk.Value,
+			Day:      p.LatestCase.Day,
+			Date:     p.LatestCase.Date,
+		}
+	}
+	writeSuccessResponse(w, entries)
+}
+
+// GetProvinceMetrics godoc
+//
+//	@Summary		Aggregate metrics of a province
+//	@Description	Returns the latest, minimum, maximum and average of every metric over a province's cases, optionally within a date range
+//	@Tags			metrics
+//	@Produce		json
+//	@Param			provinceId	path		string	true	"Province ID (e.g., '72')"
+//	@Param			start_date	query		string	false	"Start date (YYYY-MM-DD)"
+//	@Param			end_date	query		string	false	"End date (YYYY-MM-DD)"
+//	@Success		200			{object}	Response{data=ProvinceMetrics}
+//	@Failure		400			{object}	Response
+//	@Failure		404			{object}	Response
+//	@Failure		500			{object}	Response
+//	@Router			/provinces/{provinceId}/metrics [get]
+func (h *MetricHandler) GetProvinceMetrics(w http.ResponseWriter, r *http.Request) {
+	provinceID := mux.Vars(r)["provinceId"]
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+	if (startDate == "") != (endDate == "") {
+		writeErrorResponse(w, http.StatusBadRequest, "start_date and end_date must be given together")
+		return
+	}
+
+	dates, ok := parseDateRange(w, startDate, endDate)
+	if !ok {
+		return
+	}
+	// Fold the cases in page by page so long ranges are not held in memory
+	acc := h.registry.NewAccumulator()
+	for offset := 0; ; offset += metricsPageSize {
+		cases, total, err := h.covidService.ListProvinceCases(r.Context(), service.QueryOptions{
+			ProvinceID: provinceID,
+			Range:      dates,
+			Sort:       utils.SortParams{Field: "date", Order: "asc"},
+			Page:       service.Page{Limit: metricsPageSize, Offset: offset},
+		})
+		if err != nil {
+			log.Printf("Error loading province cases for metrics: %v", err)
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to load case data")
+			return
+		}
+		for i := range cases {
+			acc.Add(metrics.FromProvinceCase(cases[i].TransformToResponseWithoutProvince()))
+		}
+		if len(cases) < metricsPageSize || offset+len(cases) >= total {
+			break
+		}
+	}
+	if acc.Rows() == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "No case data for this province")
+		return
+	}
+
+	writeSuccessResponse(w, ProvinceMetrics{
+		ProvinceID: provinceID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Days:       acc.Rows(),
+		Metrics:    acc.Summaries(),
+	})
+}
+
+// CalendarDay is the value of a metric on a calendar day. Value is nil when
+// there is no case data for the day or the metric is undefined on it.
+type CalendarDay struct {
+	Date  string   `json:"date"`
+	Value *float64 `json:"value"`
+}
+
+// ProvinceCalendar holds a metric for every day of a year, for calendar
+// heatmaps. Min and Max span the non-null values and are nil without any.
+type ProvinceCalendar struct {
+	ProvinceID string        `json:"province_id"`
+	Year       int           `json:"year"`
+	Metric     string        `json:"metric"`
+	Min        *float64      `json:"min"`
+	Max        *float64      `json:"max"`
+	Days       []CalendarDay `json:"days"`
+}
+
+// GetProvinceCalendar godoc
+//
+//	@Summary		Calendar heatmap of a province
+//	@Description	Returns a metric for every calendar day of a year, with null values for days without data, for calendar-heatmap widgets
+//	@Tags			metrics
+//	@Produce		json
+//	@Param			provinceId	path		string	true	"Province ID (e.g., '72')"
+//	@Param			year		query		integer	true	"Year (e.g., 2021)"
+//	@Param			metric		query		string	false	"Metric name, see /metrics (default: daily_positive)"
+//	@Success		200			{object}	Response{data=ProvinceCalendar}
+//	@Failure		400			{object}	Response
+//	@Failure		500			{object}	Response
+//	@Router			/provinces/{provinceId}/calendar [get]
+func (h *MetricHandler) GetProvinceCalendar(w http.ResponseWriter, r *http.Request) {
+	provinceID := mux.Vars(r)["provinceId"]
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil || year < firstDataYear || year > time.Now().Year() {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid or missing year")
+		return
+	}
+	name := r.URL.Query().Get("metric")
+	if name == "" {
+		name = defaultCalendarMetric
+	}
+	if _, ok := h.registry.Get(name); !ok {
+		writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown metric %q", name))
+		return
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	cases, _, err := h.covidService.ListProvinceCases(r.Context(), service.QueryOptions{
+		ProvinceID: provinceID,
+		Range:      service.DateRange{Start: start, End: end},
+		Sort:       utils.SortParams{Field: "date", Order: "asc"},
+	})
+	if err != nil {
+		log.Printf("Error loading province cases for calendar: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load case data")
+		return
+	}
+
+	values := make(map[string]float64, len(cases))
+	for i := range cases {
+		if v, ok := h.registry.Evaluate(metrics.FromProvinceCase(cases[i].TransformToResponseWithoutProvince()))[name]; ok {
+			values[cases[i].Date.Format("2006-01-02")] = v
+		}
+	}
+
+	calendar := ProvinceCalendar{ProvinceID: provinceID, Year: year, Metric: name}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		day := CalendarDay{Date: d.Format("2006-01-02")}
+		if v, ok := values[day.Date]; ok {
+			day.Value = &v
+			if calendar.Min == nil || v < *calendar.Min {
+				calendar.Min = &v
+			}
+			if calendar.Max == nil || v > *calendar.Max {
+				calendar.Max = &v
+			}
+		}
+		calendar.Days = append(calendar.Days, day)
+	}
+	writeSuccessResponse(w, calendar)
+}
+
+// sortProvincesByMetric orders provinces by a metric of their latest case
+// according to a "metric:order" sort parameter. Provinces the metric is
+// undefined for are placed last.
+func sortProvincesByMetric(registry *metrics.Registry, provinces []models.ProvinceWithLatestCase, sortParam string) ([]models.ProvinceWithLatestCase, error) {
+	name, order, _ := strings.Cut(sortParam, ":")
+	name = strings.TrimSpace(name)
+	if strings.ToLower(strings.TrimSpace(order)) != "desc" {
+		order = "asc"
+	}
+
+	inputs := make([]metrics.Values, len(provinces))
+	for i, p := range provinces {
+		if p.LatestCase != nil {
+			inputs[i] = metrics.FromProvinceCase(*p.LatestCase)
+		}
+	}
+	ranked, err := registry.Rank(name, order, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]models.ProvinceWithLatestCase, 0, len(provinces))
+	placed := make([]bool, len(provinces))
+	for _, rk := range ranked {
+		sorted = append(sorted, provinces[rk.Index])
+		placed[rk.Index] = true
+	}
+	for i, p := range provinces {
+		if !placed[i] {
+			sorted = append(sorted, p)
+		}
+	}
+	return sorted, nil
+}
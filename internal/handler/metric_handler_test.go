@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/metrics"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metricTestProvinces() []models.ProvinceWithLatestCase {
+	latest := func(positive, deceased int64) *models.ProvinceCaseResponse {
+		return &models.ProvinceCaseResponse{
+			Day:        400,
+			Date:       time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+			Cumulative: models.ProvinceCumulativeCases{Positive: positive, Deceased: deceased},
+		}
+	}
+	return []models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "11", Name: "Aceh"}, LatestCase: latest(1000, 40)},
+		{Province: models.Province{ID: "31", Name: "DKI Jakarta"}, LatestCase: latest(5000, 50)},
+		{Province: models.Province{ID: "72", Name: "Sulawesi Tengah"}, LatestCase: latest(2000, 100)},
+		{Province: models.Province{ID: "99", Name: "Empty"}},
+	}
+}
+
+func TestMetricHandler_ListMetrics(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NewMetricHandler(new(MockCovidService), metrics.Default).ListMetrics(rr, httptest.NewRequest("GET", "/api/v1/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []MetricInfo `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, len(metrics.Default.Names()))
+	assert.Contains(t, response.Data, MetricInfo{
+		Name:         "case_fatality_rate",
+		Description:  "Deaths as a percentage of confirmed cases",
+		Dependencies: []string{"deceased", "positive"},
+	})
+}
+
+func TestMetricHandler_GetRankings(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("GetProvincesWithLatestCase").Return(metricTestProvinces(), nil)
+
+	rr := httptest.NewRecorder()
+	NewMetricHandler(mockService, metrics.Default).GetRankings(rr, httptest.NewRequest("GET", "/api/v1/rankings?metric=case_fatality_rate&limit=2", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []RankingEntry `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, 1, response.Data[0].Rank)
+	assert.Equal(t, "72", response.Data[0].Province.ID)
+	assert.InDelta(t, 5.0, response.Data[0].Value, 1e-9)
+	assert.Equal(t, "11", response.Data[1].Province.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestMetricHandler_GetRankings_Errors(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NewMetricHandler(new(MockCovidService), metrics.Default).GetRankings(rr, httptest.NewRequest("GET", "/api/v1/rankings?metric=nope", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService := new(MockCovidService)
+	mockService.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase(nil), errors.New("db down"))
+	rr = httptest.NewRecorder()
+	NewMetricHandler(mockService, metrics.Default).GetRankings(rr, httptest.NewRequest("GET", "/api/v1/rankings?metric=positive", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestMetricHandler_GetProvinceMetrics(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Range: testDateRange("2021-01-01", "2021-01-02"), Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: metricsPageSize}}).
+		Return([]models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{Day: 1, ProvinceID: "72", CumulativePositive: 100, CumulativeDeceased: 2}},
+			{ProvinceCase: models.ProvinceCase{Day: 2, ProvinceID: "72", CumulativePositive: 200, CumulativeDeceased: 8}},
+		}, 0, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/provinces/72/metrics?start_date=2021-01-01&end_date=2021-01-02", nil)
+	req = mux.SetURLVars(req, map[string]string{"provinceId": "72"})
+	rr := httptest.NewRecorder()
+	NewMetricHandler(mockService, metrics.Default).GetProvinceMetrics(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data ProvinceMetrics `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Data.Days)
+	cfr := response.Data.Metrics["case_fatality_rate"]
+	assert.InDelta(t, 4.0, *cfr.Latest, 1e-9)
+	assert.InDelta(t, 3.0, *cfr.Average, 1e-9)
+	mockService.AssertExpectations(t)
+}
+
+func TestMetricHandler_GetProvinceMetrics_Paged(t *testing.T) {
+	opts := service.QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: metricsPageSize}}
+	firstPage := make([]models.ProvinceCaseWithDate, metricsPageSize)
+	for i := range firstPage {
+		firstPage[i].CumulativePositive = 100
+	}
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", opts).Return(firstPage, metricsPageSize+1, nil)
+	opts.Page.Offset = metricsPageSize
+	mockService.On("ListProvinceCases", opts).Return([]models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{CumulativePositive: 300}},
+	}, metricsPageSize+1, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/provinces/72/metrics", nil), map[string]string{"provinceId": "72"})
+	rr := httptest.NewRecorder()
+	NewMetricHandler(mockService, metrics.Default).GetProvinceMetrics(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data ProvinceMetrics `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, metricsPageSize+1, response.Data.Days)
+	positive := response.Data.Metrics["positive"]
+	assert.InDelta(t, 300.0, *positive.Latest, 1e-9)
+	assert.InDelta(t, 300.0, *positive.Max, 1e-9)
+	mockService.AssertExpectations(t)
+}
+
+func TestMetricHandler_GetProvinceMetrics_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(*MockCovidService)
+		wantStatus int
+	}{
+		{name: "only start date", query: "?start_date=2021-01-01", setup: func(*MockCovidService) {}, wantStatus: http.StatusBadRequest},
+		{name: "invalid date", query: "?start_date=2021-01-01&end_date=01-02-2021", setup: func(*MockCovidService) {}, wantStatus: http.StatusBadRequest},
+		{
+			name: "no data",
+			setup: func(m *MockCovidService) {
+				m.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: metricsPageSize}}).Return([]models.ProvinceCaseWithDate{}, 0, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			setup: func(m *MockCovidService) {
+				m.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: metricsPageSize}}).Return([]models.ProvinceCaseWithDate(nil), 0, errors.New("db down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCovidService)
+			tt.setup(mockService)
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/provinces/72/metrics"+tt.query, nil), map[string]string{"provinceId": "72"})
+			rr := httptest.NewRecorder()
+			NewMetricHandler(mockService, metrics.Default).GetProvinceMetrics(rr, req)
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestCovidHandler_GetProvinces_SortByMetric(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("GetProvincesWithLatestCase").Return(metricTestProvinces(), nil)
+	handler := NewCovidHandler(mockService, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetProvinces(rr, httptest.NewRequest("GET", "/api/v1/provinces?sort=case_fatality_rate:asc", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []models.ProvinceWithLatestCase `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	var ids []string
+	for _, p := range response.Data {
+		ids = append(ids, p.ID)
+	}
+	assert.Equal(t, []string{"31", "11", "72", "99"}, ids, "provinces without the metric come last")
+
+	rr = httptest.NewRecorder()
+	handler.GetProvinces(rr, httptest.NewRequest("GET", "/api/v1/provinces?sort=unknown:asc", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMetricHandler_GetProvinceCalendar(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Range: testDateRange("2020-01-01", "2020-12-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{Day: 1, ProvinceID: "72", Positive: 4}, Date: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{ProvinceCase: models.ProvinceCase{Day: 3, ProvinceID: "72", Positive: 9}, Date: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC)},
+		}, 0, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/calendar", NewMetricHandler(mockService, metrics.Default).GetProvinceCalendar)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/calendar?year=2020", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data ProvinceCalendar `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	calendar := response.Data
+	assert.Equal(t, "daily_positive", calendar.Metric)
+	require.Len(t, calendar.Days, 366, "2020 is a leap year")
+	assert.Equal(t, "2020-01-01", calendar.Days[0].Date)
+	assert.Equal(t, "2020-12-31", calendar.Days[365].Date)
+	// March 1st is day 61 of 2020
+	require.NotNil(t, calendar.Days[60].Value)
+	assert.Equal(t, 4.0, *calendar.Days[60].Value)
+	assert.Nil(t, calendar.Days[61].Value, "missing dates are null")
+	assert.Equal(t, 9.0, *calendar.Days[62].Value)
+	assert.Equal(t, 4.0, *calendar.Min)
+	assert.Equal(t, 9.0, *calendar.Max)
+	assert.Contains(t, rr.Body.String(), `"value":null`)
+}
+
+func TestMetricHandler_GetProvinceCalendar_InvalidParams(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/calendar", NewMetricHandler(new(MockCovidService), metrics.Default).GetProvinceCalendar)
+	for _, url := range []string{
+		"/api/v1/provinces/72/calendar",
+		"/api/v1/provinces/72/calendar?year=1999",
+		"/api/v1/provinces/72/calendar?year=2021&metric=nope",
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+}
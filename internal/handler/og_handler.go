@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/ogimage"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// ogHistoricalCacheControl is used for images of a past date, which only
+// change when the data is corrected
+const ogHistoricalCacheControl = "public, max-age=86400, s-maxage=86400"
+
+// OGHandler renders Open Graph preview images for the companion website and
+// bot posts.
+type OGHandler struct {
+	covidService service.CovidService
+}
+
+// NewOGHandler creates a new OGHandler.
+func NewOGHandler(covidService service.CovidService) *OGHandler {
+	return &OGHandler{covidService: covidService}
+}
+
+// GetDailyImage godoc
+//
+//	@Summary		Daily Open Graph image
+//	@Description	Renders a 1200x630 PNG with the headline numbers of the tenant's province (Sulawesi Tengah by default) for a day, for use as og:image. Without date the latest day is used.
+//	@Tags			og
+//	@Produce		png
+//	@Param			date	query		string	false	"Date (YYYY-MM-DD)"
+//	@Success		200		{file}		binary	"PNG image"
+//	@Success		304		{string}	string	"Not modified"
+//	@Failure		400		{object}	Response
+//	@Failure		404		{object}	Response
+//	@Failure		500		{object}	Response
+//	@Router			/og/daily.png [get]
+func (h *OGHandler) GetDailyImage(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	date := r.URL.Query().Get("date")
+	cacheControl := embedCacheControl
+
+	opts := service.QueryOptions{
+		ProvinceID: t.ProvinceID,
+		Sort:       utils.SortParams{Field: "date", Order: "desc"},
+		Page:       service.Page{Limit: 1},
+	}
+	if date != "" {
+		day, ok := parseDateRange(w, date, date)
+		if !ok {
+			return
+		}
+		cacheControl = ogHistoricalCacheControl
+		opts.Range = day
+		opts.Sort = utils.SortParams{Field: "date", Order: "asc"}
+		opts.Page = service.Page{}
+	}
+	cases, _, err := h.covidService.ListProvinceCases(r.Context(), opts)
+	if err != nil {
+		log.Printf("Error loading OG image data: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load case data")
+		return
+	}
+	if len(cases) == 0 {
+		writeErrorResponse(w, http.StatusNotFound, "No case data for this date")
+		return
+	}
+	day := cases[0]
+
+	etag := fmt.Sprintf(`"og-%s-%d-%d-%d-%d"`, t.ProvinceID, day.Day, day.CumulativePositive, day.CumulativeRecovered, day.CumulativeDeceased)
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := ogimage.Render(&buf, newDailyOGCard(t.Name, day)); err != nil {
+		log.Printf("Error rendering OG image: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to render image")
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("Error writing OG image: %v", err)
+	}
+}
+
+func newDailyOGCard(province string, c models.ProvinceCaseWithDate) ogimage.Card {
+	data := newEmbedSummaryData(province, c)
+	colors := map[string]color.Color{
+		"positive":  color.RGBA{0xc6, 0x28, 0x28, 0xff},
+		"recovered": color.RGBA{0x2e, 0x7d, 0x32, 0xff},
+		"deceased":  color.RGBA{0x42, 0x42, 0x42, 0xff},
+		"active":    color.RGBA{0xef, 0x6c, 0x00, 0xff},
+	}
+
+	card := ogimage.Card{
+		Title:    "COVID-19 " + data.Province,
+		Subtitle: fmt.Sprintf("Hari ke-%d · %s", data.Day, data.Date),
+		Footer:   "Sumber: PICO API",
+	}
+	if data.Rt != "" {
+		card.Footer = "Rt " + data.Rt + " · Sumber: PICO API"
+	}
+	for _, s := range data.Stats {
+		stat := ogimage.Stat{Label: s.Label, Value: s.Value, Color: colors[s.Class]}
+		if s.Delta != "" {
+			stat.Delta = s.Delta + " hari ini"
+		}
+		card.Stats = append(card.Stats, stat)
+	}
+	return card
+}
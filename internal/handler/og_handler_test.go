@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/ogimage"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ogTestCase() models.ProvinceCaseWithDate {
+	return models.ProvinceCaseWithDate{
+		ProvinceCase: models.ProvinceCase{
+			Day: 512, ProvinceID: "72", Positive: 14,
+			CumulativePositive: 12345, CumulativeRecovered: 11000, CumulativeDeceased: 321,
+		},
+		Date: time.Date(2021, 8, 17, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestOGHandler_GetDailyImage_Latest(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: embedLatestSort, Page: service.Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{ogTestCase()}, 400, nil)
+	handler := NewOGHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	handler.GetDailyImage(rr, httptest.NewRequest("GET", "/api/v1/og/daily.png", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, embedCacheControl, rr.Header().Get("Cache-Control"))
+	img, err := png.Decode(bytes.NewReader(rr.Body.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, ogimage.Width, img.Bounds().Dx())
+	assert.Equal(t, ogimage.Height, img.Bounds().Dy())
+
+	req := httptest.NewRequest("GET", "/api/v1/og/daily.png", nil)
+	req.Header.Set("If-None-Match", rr.Header().Get("ETag"))
+	rr = httptest.NewRecorder()
+	handler.GetDailyImage(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.Bytes())
+}
+
+func TestOGHandler_GetDailyImage_Date(t *testing.T) {
+	mockService := new(MockCovidService)
+	mockService.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Range: testDateRange("2021-08-17", "2021-08-17"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.ProvinceCaseWithDate{ogTestCase()}, 0, nil)
+
+	rr := httptest.NewRecorder()
+	NewOGHandler(mockService).GetDailyImage(rr, httptest.NewRequest("GET", "/api/v1/og/daily.png?date=2021-08-17", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, ogHistoricalCacheControl, rr.Header().Get("Cache-Control"))
+	mockService.AssertExpectations(t)
+}
+
+func TestOGHandler_GetDailyImage_Errors(t *testing.T) {
+	dateSort := utils.SortParams{Field: "date", Order: "asc"}
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(*MockCovidService)
+		wantStatus int
+	}{
+		{name: "invalid date", query: "?date=17-08-2021", setup: func(*MockCovidService) {}, wantStatus: http.StatusBadRequest},
+		{
+			name:  "no data",
+			query: "?date=2019-01-01",
+			setup: func(m *MockCovidService) {
+				m.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Range: testDateRange("2019-01-01", "2019-01-01"), Sort: dateSort}).Return([]models.ProvinceCaseWithDate{}, 0, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			setup: func(m *MockCovidService) {
+				m.On("ListProvinceCases", service.QueryOptions{ProvinceID: "72", Sort: embedLatestSort, Page: service.Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCovidService)
+			tt.setup(mockService)
+
+			rr := httptest.NewRecorder()
+			NewOGHandler(mockService).GetDailyImage(rr, httptest.NewRequest("GET", "/api/v1/og/daily.png"+tt.query, nil))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestNewDailyOGCard(t *testing.T) {
+	card := newDailyOGCard("Sulawesi Tengah", ogTestCase())
+
+	assert.Equal(t, "COVID-19 Sulawesi Tengah", card.Title)
+	assert.Equal(t, "Hari ke-512 · 17 Agustus 2021", card.Subtitle)
+	require.Len(t, card.Stats, 4)
+	assert.Equal(t, "12.345", card.Stats[0].Value)
+	assert.Equal(t, "+14 hari ini", card.Stats[0].Delta)
+	assert.NotNil(t, card.Stats[0].Color)
+	assert.Empty(t, card.Stats[1].Delta)
+}
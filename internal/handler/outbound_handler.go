@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/pkg/httpclient"
+)
+
+// GetOutboundStats godoc
+//
+//	@Summary		Outbound HTTP client metrics
+//	@Description	Returns request, retry and failure counters, average attempt latency and circuit breaker state for each outbound HTTP client since startup.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]httpclient.Stats}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/outbound [get]
+func GetOutboundStats(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	writeSuccessResponse(w, httpclient.AllStats())
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOutboundStats(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	httpclient.New("handler-test-upstream", httpclient.DefaultOptions())
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/outbound", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	GetOutboundStats(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"handler-test-upstream"`)
+	assert.Contains(t, w.Body.String(), `"circuit_state":"closed"`)
+}
+
+func TestGetOutboundStats_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/outbound", nil)
+	w := httptest.NewRecorder()
+	GetOutboundStats(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
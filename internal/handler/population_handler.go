@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// PopulationHandler serves the per capita statistics of provinces.
+type PopulationHandler struct {
+	service service.PopulationServiceInterface
+}
+
+// NewPopulationHandler creates a new PopulationHandler.
+func NewPopulationHandler(service service.PopulationServiceInterface) *PopulationHandler {
+	return &PopulationHandler{service: service}
+}
+
+// GetProvincePerCapita godoc
+//
+//	@Summary		Per capita statistics of a province
+//	@Description	Relates the province's latest cumulative cases and vaccine doses to its population: positive cases and deaths per 100,000 people and the first, second and booster dose coverage in percent. The population source is set by the server. Values are null when their data is missing.
+//	@Tags			provinces
+//	@Produce		json
+//	@Param			provinceId	path		string	true	"Province ID (e.g., '72')"
+//	@Success		200			{object}	Response{data=models.ProvincePerCapita}
+//	@Failure		404			{object}	Response
+//	@Failure		500			{object}	Response
+//	@Router			/provinces/{provinceId}/per-capita [get]
+func (h *PopulationHandler) GetProvincePerCapita(w http.ResponseWriter, r *http.Request) {
+	provinceID := mux.Vars(r)["provinceId"]
+	perCapita, err := h.service.GetProvincePerCapita(r.Context(), provinceID)
+	if errors.Is(err, service.ErrPopulationNotFound) {
+		writeErrorResponse(w, http.StatusNotFound, "No population data for province "+provinceID)
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading per capita statistics: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load per capita statistics")
+		return
+	}
+	writeSuccessResponse(w, perCapita)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPopulationService struct {
+	mock.Mock
+}
+
+func (m *MockPopulationService) GetProvincePerCapita(ctx context.Context, provinceID string) (*models.ProvincePerCapita, error) {
+	args := m.Called(provinceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProvincePerCapita), args.Error(1)
+}
+
+func (m *MockPopulationService) AddPerCapita(ctx context.Context, provinces []models.ProvinceWithLatestCase) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called(provinces)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
+}
+
+func TestPopulationHandler_GetProvincePerCapita(t *testing.T) {
+	mockService := new(MockPopulationService)
+	incidence := 2000.0
+	mockService.On("GetProvincePerCapita", "72").Return(&models.ProvincePerCapita{
+		Province:  models.Province{ID: "72", Name: "Sulawesi Tengah"},
+		PerCapita: models.PerCapita{Population: 3000000, PopulationSource: "bps-2020", PopulationYear: 2020, IncidencePer100k: &incidence},
+	}, nil)
+	router := SetupRoutes(Services{PopulationService: mockService}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/per-capita", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"population":3000000`)
+	assert.Contains(t, rr.Body.String(), `"incidence_per_100k":2000`)
+	assert.Contains(t, rr.Body.String(), `"first_dose_coverage":null`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPopulationHandler_GetProvincePerCapita_Errors(t *testing.T) {
+	mockService := new(MockPopulationService)
+	mockService.On("GetProvincePerCapita", "99").Return(nil, service.ErrPopulationNotFound)
+	mockService.On("GetProvincePerCapita", "72").Return(nil, errors.New("db down"))
+	router := SetupRoutes(Services{PopulationService: mockService}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/99/per-capita", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/provinces/72/per-capita", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "db down")
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// ProbeHandler serves the liveness and readiness probes of orchestrators
+// such as Kubernetes
+type ProbeHandler struct {
+	readiness service.ReadinessChecker
+}
+
+// NewProbeHandler creates a new ProbeHandler. readiness may be nil when only
+// the liveness probe is served.
+func NewProbeHandler(readiness service.ReadinessChecker) *ProbeHandler {
+	return &ProbeHandler{readiness: readiness}
+}
+
+// Liveness godoc
+//
+//	@Summary		Liveness probe
+//	@Description	Reports that the process is up and serving HTTP. It never touches the database or the cache, so a failing dependency does not get the process restarted.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Response{data=map[string]string}
+//	@Router			/healthz [get]
+func (h *ProbeHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, Response{
+		Status: "success",
+		Data:   map[string]string{"status": "alive", "version": apiVersion},
+	})
+}
+
+// Readiness godoc
+//
+//	@Summary		Readiness probe
+//	@Description	Checks that the database answers, the cache was warmed and the migrations were applied. Checks failing within their tolerance are degraded and keep the API ready; any failing check makes it unready with a 503.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Response{data=models.Readiness}
+//	@Failure		503	{object}	Response{data=models.Readiness}
+//	@Router			/readyz [get]
+func (h *ProbeHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	readiness := h.readiness.Ready(r.Context())
+	if !readiness.Ready {
+		writeJSONResponse(w, http.StatusServiceUnavailable, Response{
+			Status: "error",
+			Error:  "Service is not ready",
+			Data:   readiness,
+		})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, Response{Status: "success", Data: readiness})
+}
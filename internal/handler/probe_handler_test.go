@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReadinessChecker struct {
+	mock.Mock
+}
+
+func (m *MockReadinessChecker) Ready(ctx context.Context) models.Readiness {
+	return m.Called().Get(0).(models.Readiness)
+}
+
+func TestProbeHandler_Liveness(t *testing.T) {
+	router := SetupRoutes(Services{}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"alive"`)
+}
+
+func TestProbeHandler_Readiness(t *testing.T) {
+	readiness := new(MockReadinessChecker)
+	readiness.On("Ready").Return(models.Readiness{Ready: true, Checks: []models.ReadinessCheck{
+		{Name: "database", Status: models.ReadinessDegraded, Error: "connection refused"},
+	}}).Once()
+	readiness.On("Ready").Return(models.Readiness{Ready: false, Checks: []models.ReadinessCheck{
+		{Name: "database", Status: models.ReadinessFailing, Error: "connection refused"},
+	}}).Once()
+	router := SetupRoutes(Services{Readiness: readiness}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"ready":true`)
+	assert.Contains(t, rr.Body.String(), `"status":"degraded"`)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"ready":false`)
+	assert.Contains(t, rr.Body.String(), `"error":"Service is not ready"`)
+	readiness.AssertExpectations(t)
+}
+
+func TestProbeHandler_ReadinessNotConfigured(t *testing.T) {
+	router := SetupRoutes(Services{}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -24,7 +24,7 @@ func NewProvinceStatsHandler(service service.ProvinceStatsServiceInterface) *Pro
 // @Failure 500 {object} Response
 // @Router /stats/gender [get]
 func (h *ProvinceStatsHandler) GetGenderCases(w http.ResponseWriter, r *http.Request) {
-	data, err := h.service.GetGenderCases()
+	data, err := h.service.GetGenderCases(r.Context())
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -41,7 +41,7 @@ func (h *ProvinceStatsHandler) GetGenderCases(w http.ResponseWriter, r *http.Req
 // @Failure 500 {object} Response
 // @Router /stats/gender/latest [get]
 func (h *ProvinceStatsHandler) GetLatestGenderCase(w http.ResponseWriter, r *http.Request) {
-	data, err := h.service.GetLatestGenderCase()
+	data, err := h.service.GetLatestGenderCase(r.Context())
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -60,7 +60,7 @@ func (h *ProvinceStatsHandler) GetLatestGenderCase(w http.ResponseWriter, r *htt
 // @Success 200 {object} Response
 // @Router /stats/tests [get]
 func (h *ProvinceStatsHandler) GetTests(w http.ResponseWriter, r *http.Request) {
-	data, err := h.service.GetTests()
+	data, err := h.service.GetTests(r.Context())
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -75,7 +75,7 @@ func (h *ProvinceStatsHandler) GetTests(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} Response
 // @Router /stats/test-types [get]
 func (h *ProvinceStatsHandler) GetTestTypes(w http.ResponseWriter, r *http.Request) {
-	data, err := h.service.GetTestTypes()
+	data, err := h.service.GetTestTypes(r.Context())
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
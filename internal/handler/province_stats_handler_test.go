@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -13,22 +14,22 @@ import (
 
 type MockProvinceStatsService struct{ mock.Mock }
 
-func (m *MockProvinceStatsService) GetGenderCases() ([]models.ProvinceGenderCase, error) {
+func (m *MockProvinceStatsService) GetGenderCases(ctx context.Context) ([]models.ProvinceGenderCase, error) {
 	args := m.Called()
 	return args.Get(0).([]models.ProvinceGenderCase), args.Error(1)
 }
-func (m *MockProvinceStatsService) GetLatestGenderCase() (*models.ProvinceGenderCase, error) {
+func (m *MockProvinceStatsService) GetLatestGenderCase(ctx context.Context) (*models.ProvinceGenderCase, error) {
 	args := m.Called()
 	if r := args.Get(0); r != nil {
 		return r.(*models.ProvinceGenderCase), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
-func (m *MockProvinceStatsService) GetTests() ([]models.ProvinceTest, error) {
+func (m *MockProvinceStatsService) GetTests(ctx context.Context) ([]models.ProvinceTest, error) {
 	args := m.Called()
 	return args.Get(0).([]models.ProvinceTest), args.Error(1)
 }
-func (m *MockProvinceStatsService) GetTestTypes() ([]models.TestType, error) {
+func (m *MockProvinceStatsService) GetTestTypes(ctx context.Context) ([]models.TestType, error) {
 	args := m.Called()
 	return args.Get(0).([]models.TestType), args.Error(1)
 }
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// firstDataYear is the first year with case data
+const firstDataYear = 2020
+
+// RecapHandler serves the yearly recap of the annual retrospective page.
+type RecapHandler struct {
+	service service.RecapServiceInterface
+}
+
+// NewRecapHandler creates a new RecapHandler.
+func NewRecapHandler(service service.RecapServiceInterface) *RecapHandler {
+	return &RecapHandler{service: service}
+}
+
+// GetRecap godoc
+//
+//	@Summary		Yearly recap
+//	@Description	Summarizes a year nationally or of a province: new and cumulative cases, the day with the most new positive cases, the month with the fewest, the average Rt, the waves of the 7-day average of new positive cases and the vaccination progress
+//	@Tags			recap
+//	@Produce		json
+//	@Param			year		path		integer	true	"Year (e.g., 2021)"
+//	@Param			province_id	query		string	false	"Province ID (e.g., '72'); national when omitted"
+//	@Success		200			{object}	Response{data=models.Recap}
+//	@Failure		400			{object}	Response
+//	@Failure		404			{object}	Response
+//	@Failure		500			{object}	Response
+//	@Router			/recap/{year} [get]
+func (h *RecapHandler) GetRecap(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(mux.Vars(r)["year"])
+	if err != nil || year < firstDataYear || year > time.Now().Year() {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid year")
+		return
+	}
+	provinceID := r.URL.Query().Get("province_id")
+	if provinceID != "" {
+		if id, err := strconv.Atoi(provinceID); err != nil || id < 10 || id > 99 {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid province ID")
+			return
+		}
+	}
+
+	recap, err := h.service.GetRecap(r.Context(), year, provinceID)
+	if errors.Is(err, service.ErrRecapNoData) {
+		writeErrorResponse(w, http.StatusNotFound, "No case data for this year")
+		return
+	}
+	if err != nil {
+		log.Printf("Error building recap: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to build recap")
+		return
+	}
+	writeSuccessResponse(w, recap)
+}
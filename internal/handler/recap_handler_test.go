@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecapService struct {
+	mock.Mock
+}
+
+func (m *MockRecapService) GetRecap(ctx context.Context, year int, provinceID string) (*models.Recap, error) {
+	args := m.Called(year, provinceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Recap), args.Error(1)
+}
+
+func serveRecap(svc service.RecapServiceInterface, url string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/recap/{year}", NewRecapHandler(svc).GetRecap)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+	return rr
+}
+
+func TestRecapHandler_GetRecap(t *testing.T) {
+	mockService := new(MockRecapService)
+	mockService.On("GetRecap", 2021, "72").Return(&models.Recap{
+		Year: 2021, Scope: models.RecapScopeProvince, ProvinceID: "72", WaveCount: 2,
+	}, nil)
+
+	rr := serveRecap(mockService, "/api/v1/recap/2021?province_id=72")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data models.Recap `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "province", response.Data.Scope)
+	assert.Equal(t, 2, response.Data.WaveCount)
+}
+
+func TestRecapHandler_GetRecap_InvalidParams(t *testing.T) {
+	mockService := new(MockRecapService)
+	for _, url := range []string{
+		"/api/v1/recap/abc",
+		"/api/v1/recap/2019",
+		"/api/v1/recap/9999",
+		"/api/v1/recap/2021?province_id=7",
+		"/api/v1/recap/2021?province_id=xx",
+	} {
+		rr := serveRecap(mockService, url)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+	mockService.AssertNotCalled(t, "GetRecap", mock.Anything, mock.Anything)
+}
+
+func TestRecapHandler_GetRecap_Errors(t *testing.T) {
+	mockService := new(MockRecapService)
+	mockService.On("GetRecap", 2020, "").Return(nil, service.ErrRecapNoData)
+	mockService.On("GetRecap", 2021, "").Return(nil, errors.New("db down"))
+
+	assert.Equal(t, http.StatusNotFound, serveRecap(mockService, "/api/v1/recap/2020").Code)
+	rr := serveRecap(mockService, "/api/v1/recap/2021")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "db down")
+}
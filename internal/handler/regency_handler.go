@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/service"
 	"github.com/gorilla/mux"
 )
@@ -33,7 +34,7 @@ func (h *RegencyHandler) GetRegencies(w http.ResponseWriter, r *http.Request) {
 	p := parsePaginationParams(r)
 
 	if p.LoadAll {
-		regencies, err := h.service.GetRegencies()
+		regencies, err := h.service.GetRegencies(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -42,7 +43,7 @@ func (h *RegencyHandler) GetRegencies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	regencies, total, err := h.service.GetRegenciesPaginated(p.PerPage, p.Offset)
+	regencies, total, err := h.service.GetRegenciesPaginated(r.Context(), p.PerPage, p.Offset)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -67,7 +68,7 @@ func (h *RegencyHandler) GetRegencyByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	regency, err := h.service.GetRegencyByID(id)
+	regency, err := h.service.GetRegencyByID(r.Context(), id)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -79,6 +80,49 @@ func (h *RegencyHandler) GetRegencyByID(w http.ResponseWriter, r *http.Request)
 	writeSuccessResponse(w, regency)
 }
 
+// GetProvinceDistricts godoc
+// @Summary Get districts (kabupaten/kota) of a province
+// @Description Returns every regency/city of the province, e.g. Palu, Sigi and Donggala for Sulawesi Tengah (72)
+// @Tags regencies
+// @Produce json
+// @Param provinceId path int true "Province ID (e.g., 72 for Sulawesi Tengah)"
+// @Success 200 {object} Response{data=[]models.Regency}
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /provinces/{provinceId}/districts [get]
+func (h *RegencyHandler) GetProvinceDistricts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	provinceID, err := strconv.Atoi(vars["provinceId"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid province ID")
+		return
+	}
+
+	regencies, err := h.service.GetRegenciesByProvince(r.Context(), provinceID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if regencies == nil {
+		regencies = []models.Regency{}
+	}
+	writeSuccessResponse(w, regencies)
+}
+
+// GetDistrictCases godoc
+// @Summary Get daily cases for a district (kabupaten/kota)
+// @Description Returns all daily COVID-19 case data for a regency/city. Same data as /regencies/{code}/cases.
+// @Tags regencies
+// @Produce json
+// @Param districtId path int true "District (regency) ID, e.g. 7271 for Palu"
+// @Success 200 {object} Response{data=[]models.RegencyCase}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /districts/{districtId}/cases [get]
+func (h *RegencyHandler) GetDistrictCases(w http.ResponseWriter, r *http.Request) {
+	h.writeRegencyCases(w, r, mux.Vars(r)["districtId"])
+}
+
 // GetRegencyCases godoc
 // @Summary Get daily cases for a regency
 // @Description Returns all daily COVID-19 case data for a specific regency
@@ -89,20 +133,23 @@ func (h *RegencyHandler) GetRegencyByID(w http.ResponseWriter, r *http.Request)
 // @Failure 404 {object} Response
 // @Router /regencies/{code}/cases [get]
 func (h *RegencyHandler) GetRegencyCases(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	id, err := strconv.Atoi(vars["code"])
+	h.writeRegencyCases(w, r, mux.Vars(r)["code"])
+}
+
+func (h *RegencyHandler) writeRegencyCases(w http.ResponseWriter, r *http.Request, code string) {
+	id, err := strconv.Atoi(code)
 	if err != nil {
 		writeErrorResponse(w, http.StatusBadRequest, "Invalid regency code")
 		return
 	}
 
-	cases, err := h.service.GetRegencyCases(id)
+	cases, err := h.service.GetRegencyCases(r.Context(), id)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	if cases == nil {
-		writeErrorResponse(w, http.StatusNotFound, "Tidak ditemukan data untuk kabupaten/kota dengan kode "+vars["code"])
+		writeErrorResponse(w, http.StatusNotFound, "Tidak ditemukan data untuk kabupaten/kota dengan kode "+code)
 		return
 	}
 	writeSuccessResponse(w, cases)
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -14,27 +15,34 @@ import (
 
 type MockRegencyService struct{ mock.Mock }
 
-func (m *MockRegencyService) GetRegenciesPaginated(limit, offset int) ([]models.Regency, int, error) {
+func (m *MockRegencyService) GetRegenciesPaginated(ctx context.Context, limit, offset int) ([]models.Regency, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.Regency), args.Int(1), args.Error(2)
 }
 
-func (m *MockRegencyService) GetRegencies() ([]models.Regency, error) {
+func (m *MockRegencyService) GetRegencies(ctx context.Context) ([]models.Regency, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Regency), args.Error(1)
 }
-func (m *MockRegencyService) GetRegencyByID(id int) (*models.Regency, error) {
+func (m *MockRegencyService) GetRegenciesByProvince(ctx context.Context, provinceID int) ([]models.Regency, error) {
+	args := m.Called(provinceID)
+	if r := args.Get(0); r != nil {
+		return r.([]models.Regency), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *MockRegencyService) GetRegencyByID(ctx context.Context, id int) (*models.Regency, error) {
 	args := m.Called(id)
 	if r := args.Get(0); r != nil {
 		return r.(*models.Regency), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
-func (m *MockRegencyService) GetRegencyCases(id int) ([]models.RegencyCase, error) {
+func (m *MockRegencyService) GetRegencyCases(ctx context.Context, id int) ([]models.RegencyCase, error) {
 	args := m.Called(id)
 	return args.Get(0).([]models.RegencyCase), args.Error(1)
 }
-func (m *MockRegencyService) GetLatestRegencyCases() ([]models.RegencyCase, error) {
+func (m *MockRegencyService) GetLatestRegencyCases(ctx context.Context) ([]models.RegencyCase, error) {
 	args := m.Called()
 	return args.Get(0).([]models.RegencyCase), args.Error(1)
 }
@@ -168,3 +176,82 @@ func TestGetRegencyCases_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 	svc.AssertExpectations(t)
 }
+
+func TestGetProvinceDistricts_Success(t *testing.T) {
+	svc := new(MockRegencyService)
+	svc.On("GetRegenciesByProvince", 72).Return([]models.Regency{
+		{ID: 7271, ProvinceID: 72, Name: "Kota Palu"},
+		{ID: 7210, ProvinceID: 72, Name: "Kabupaten Sigi"},
+	}, nil)
+
+	h := NewRegencyHandler(svc)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/provinces/72/districts", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/districts", h.GetProvinceDistricts)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Kota Palu")
+	svc.AssertExpectations(t)
+}
+
+func TestGetProvinceDistricts_NoneReturnsEmptyList(t *testing.T) {
+	svc := new(MockRegencyService)
+	svc.On("GetRegenciesByProvince", 11).Return(nil, nil)
+
+	h := NewRegencyHandler(svc)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/provinces/11/districts", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/districts", h.GetProvinceDistricts)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[]`)
+}
+
+func TestGetProvinceDistricts_InvalidID(t *testing.T) {
+	h := NewRegencyHandler(new(MockRegencyService))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/provinces/abc/districts", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/provinces/{provinceId}/districts", h.GetProvinceDistricts)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetDistrictCases_Success(t *testing.T) {
+	svc := new(MockRegencyService)
+	svc.On("GetRegencyCases", 7271).Return([]models.RegencyCase{{}}, nil)
+
+	h := NewRegencyHandler(svc)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/districts/7271/cases", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/districts/{districtId}/cases", h.GetDistrictCases)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestGetDistrictCases_NotFound(t *testing.T) {
+	svc := new(MockRegencyService)
+	svc.On("GetRegencyCases", 9999).Return([]models.RegencyCase(nil), nil)
+
+	h := NewRegencyHandler(svc)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/districts/9999/cases", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/districts/{districtId}/cases", h.GetDistrictCases)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/pdf"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+)
+
+// ReportHandler serves the printable situational reports for health offices.
+type ReportHandler struct {
+	service service.ReportServiceInterface
+}
+
+// NewReportHandler creates a new ReportHandler.
+func NewReportHandler(service service.ReportServiceInterface) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// GetDailyReport godoc
+//
+//	@Summary		Daily situational report
+//	@Description	Renders a one-page PDF report of the tenant's province (Sulawesi Tengah by default) for a day: new and cumulative cases, Rt, the new positive cases of the week against the week before and a chart of the last 14 days. Without date the latest day is used.
+//	@Tags			reports
+//	@Produce		application/pdf
+//	@Param			date	query		string	false	"Date (YYYY-MM-DD)"
+//	@Success		200		{file}		binary	"PDF report"
+//	@Failure		400		{object}	Response
+//	@Failure		404		{object}	Response
+//	@Failure		500		{object}	Response
+//	@Router			/reports/daily [get]
+func (h *ReportHandler) GetDailyReport(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	date := r.URL.Query().Get("date")
+	day, ok := parseDateRange(w, date, date)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetDailyReport(r.Context(), t.ProvinceID, day.Start)
+	if errors.Is(err, service.ErrReportNoData) {
+		writeErrorResponse(w, http.StatusNotFound, "No case data for this date")
+		return
+	}
+	if err != nil {
+		log.Printf("Error building daily report: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to build report")
+		return
+	}
+	if report.ProvinceName == "" {
+		report.ProvinceName = t.Name
+	}
+
+	var buf bytes.Buffer
+	if err := renderDailyReport(&buf, report); err != nil {
+		log.Printf("Error rendering daily report: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to render report")
+		return
+	}
+	filename := fmt.Sprintf("daily_report_%s_%s.pdf", report.ProvinceID, report.Date.Format("2006-01-02"))
+	w.Header().Set("Content-Type", pdf.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("Error writing daily report: %v", err)
+	}
+}
+
+// Layout of the daily report, in points
+const (
+	reportMargin      = 48.0
+	reportTileGap     = 12.0
+	reportTileHeight  = 78.0
+	reportChartHeight = 180.0
+)
+
+var (
+	reportHeader = pdf.Color{R: 0x10, G: 0x2a, B: 0x43}
+	reportMuted  = pdf.Color{R: 0x62, G: 0x7d, B: 0x98}
+	reportTile   = pdf.Color{R: 0xf0, G: 0xf4, B: 0xf8}
+	reportWhite  = pdf.Color{R: 0xff, G: 0xff, B: 0xff}
+	reportColors = map[string]pdf.Color{
+		"positive":  {R: 0xc6, G: 0x28, B: 0x28},
+		"recovered": {R: 0x2e, G: 0x7d, B: 0x32},
+		"deceased":  {R: 0x42, G: 0x42, B: 0x42},
+		"active":    {R: 0xef, G: 0x6c, B: 0x00},
+	}
+)
+
+// renderDailyReport draws the report on one A4 page
+func renderDailyReport(w io.Writer, report *models.DailyReport) error {
+	doc := pdf.New(fmt.Sprintf("Laporan Situasi COVID-19 %s %s", report.ProvinceName, report.Date.Format("2006-01-02")))
+	page := doc.AddPage()
+	width := pdf.PageWidth - 2*reportMargin
+
+	page.Rect(0, 0, pdf.PageWidth, 110, reportHeader)
+	page.Text(reportMargin, 52, pdf.Bold, 22, reportWhite, "Laporan Situasi COVID-19")
+	page.Text(reportMargin, 80, pdf.Regular, 13, reportWhite,
+		fmt.Sprintf("%s · Hari ke-%d · %s", report.ProvinceName, report.Day, formatIndonesianDate(report.Date)))
+
+	// Key numbers: cumulative totals with the change of the day
+	y := 140.0
+	page.Text(reportMargin, y, pdf.Bold, 13, reportHeader, "Angka Utama")
+	y += 14
+	tiles := []struct {
+		label, class string
+		total, today int64
+	}{
+		{"Positif", "positive", report.Cumulative.Positive, report.NewCases.Positive},
+		{"Sembuh", "recovered", report.Cumulative.Recovered, report.NewCases.Recovered},
+		{"Meninggal", "deceased", report.Cumulative.Deceased, report.NewCases.Deceased},
+		{"Dirawat", "active", report.Cumulative.Active, report.NewCases.Active},
+	}
+	tileWidth := (width - 3*reportTileGap) / float64(len(tiles))
+	for i, tile := range tiles {
+		x := reportMargin + float64(i)*(tileWidth+reportTileGap)
+		page.Rect(x, y, tileWidth, reportTileHeight, reportTile)
+		page.Rect(x, y, 4, reportTileHeight, reportColors[tile.class])
+		page.Text(x+14, y+22, pdf.Regular, 10, reportMuted, tile.label)
+		page.Text(x+14, y+48, pdf.Bold, 18, reportColors[tile.class], formatThousands(tile.total))
+		if today := signedThousands(tile.today); today != "" {
+			page.Text(x+14, y+66, pdf.Regular, 9, reportMuted, today+" hari ini")
+		}
+	}
+	y += reportTileHeight + 36
+
+	// Rt and the weekly trend
+	page.Text(reportMargin, y, pdf.Bold, 13, reportHeader, "Tren")
+	y += 22
+	page.Text(reportMargin, y, pdf.Regular, 11, pdf.Black, "Angka reproduksi efektif (Rt): "+formatReportRt(report.Rt))
+	y += 18
+	page.Text(reportMargin, y, pdf.Regular, 11, pdf.Black, fmt.Sprintf("Kasus positif baru 7 hari terakhir: %s %s",
+		formatThousands(report.WeekPositive), formatWeekChange(report)))
+	y += 36
+
+	// New positive cases of the trend days as bars
+	page.Text(reportMargin, y, pdf.Bold, 13, reportHeader, fmt.Sprintf("Kasus Positif Baru %d Hari Terakhir", len(report.Trend)))
+	y += 16
+	drawReportChart(page, reportMargin, y, width, reportChartHeight, report.Trend)
+
+	page.Text(reportMargin, pdf.PageHeight-reportMargin, pdf.Regular, 9, reportMuted, "Sumber: PICO API")
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// drawReportChart draws a bar per day with the day of the month under it,
+// scaled to the highest day
+func drawReportChart(page *pdf.Page, x, y, width, height float64, days []models.ReportDay) {
+	baseline := y + height
+	page.Line(x, baseline, x+width, baseline, 0.5, reportMuted)
+	if len(days) == 0 {
+		return
+	}
+	var highest int64
+	for _, d := range days {
+		if d.Positive > highest {
+			highest = d.Positive
+		}
+	}
+	page.Text(x, y-2, pdf.Regular, 8, reportMuted, "maks. "+formatThousands(highest))
+
+	slot := width / float64(len(days))
+	for i, d := range days {
+		barX := x + float64(i)*slot + slot*0.15
+		if highest > 0 && d.Positive > 0 {
+			barHeight := float64(d.Positive) / float64(highest) * (height - 12)
+			page.Rect(barX, baseline-barHeight, slot*0.7, barHeight, reportColors["positive"])
+		}
+		page.Text(barX, baseline+12, pdf.Regular, 8, reportMuted, strconv.Itoa(d.Date.Day()))
+	}
+}
+
+// formatReportRt formats Rt with its bounds when known
+func formatReportRt(rate *models.ReproductionRate) string {
+	if rate == nil || rate.Value == nil {
+		return "belum tersedia"
+	}
+	s := strconv.FormatFloat(*rate.Value, 'f', 2, 64)
+	if rate.LowerBound != nil && rate.UpperBound != nil {
+		s += fmt.Sprintf(" (%s – %s)", strconv.FormatFloat(*rate.LowerBound, 'f', 2, 64), strconv.FormatFloat(*rate.UpperBound, 'f', 2, 64))
+	}
+	return s
+}
+
+// formatWeekChange compares the week with the week before, e.g.
+// "(naik 50.0% dari 70 pada 7 hari sebelumnya)"
+func formatWeekChange(report *models.DailyReport) string {
+	if report.WeekChangePercent == nil {
+		return ""
+	}
+	change := *report.WeekChangePercent
+	direction := "naik"
+	switch {
+	case change < 0:
+		direction, change = "turun", -change
+	case change == 0:
+		return "(sama dengan 7 hari sebelumnya)"
+	}
+	return fmt.Sprintf("(%s %s%% dari %s pada 7 hari sebelumnya)", direction,
+		strconv.FormatFloat(change, 'f', 1, 64), formatThousands(report.PreviousWeekPositive))
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) GetDailyReport(ctx context.Context, provinceID string, date time.Time) (*models.DailyReport, error) {
+	args := m.Called(provinceID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DailyReport), args.Error(1)
+}
+
+func reportTestReport() *models.DailyReport {
+	rt, lower, upper := 1.08, 0.95, 1.2
+	change := -12.5
+	date := time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)
+	return &models.DailyReport{
+		ProvinceID: "72", Day: 500, Date: date,
+		NewCases:             models.CaseTotals{Positive: 15, Recovered: 4, Deceased: 1, Active: 10},
+		Cumulative:           models.CaseTotals{Positive: 12345, Recovered: 11000, Deceased: 321, Active: 1024},
+		Rt:                   &models.ReproductionRate{Value: &rt, LowerBound: &lower, UpperBound: &upper},
+		WeekPositive:         70,
+		PreviousWeekPositive: 80,
+		WeekChangePercent:    &change,
+		Trend:                []models.ReportDay{{Date: date.AddDate(0, 0, -1), Positive: 20}, {Date: date, Positive: 15}},
+	}
+}
+
+// reportText inflates the page content of a rendered report
+func reportText(t *testing.T, body []byte) string {
+	t.Helper()
+	start := bytes.Index(body, []byte("stream\n"))
+	require.GreaterOrEqual(t, start, 0)
+	zr, err := zlib.NewReader(bytes.NewReader(body[start+len("stream\n"):]))
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestReportHandler_GetDailyReport(t *testing.T) {
+	mockService := new(MockReportService)
+	mockService.On("GetDailyReport", "72", time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)).Return(reportTestReport(), nil)
+
+	rr := httptest.NewRecorder()
+	NewReportHandler(mockService).GetDailyReport(rr, httptest.NewRequest("GET", "/api/v1/reports/daily?date=2021-07-14", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, pdf.ContentType, rr.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="daily_report_72_2021-07-14.pdf"`, rr.Header().Get("Content-Disposition"))
+	assert.True(t, bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")))
+
+	// The province name falls back to the tenant's
+	text := reportText(t, rr.Body.Bytes())
+	assert.Contains(t, text, `(Sulawesi Tengah \267 Hari ke-500 \267 14 Juli 2021)`)
+	assert.Contains(t, text, "(12.345)")
+	assert.Contains(t, text, "(+15 hari ini)")
+	assert.Contains(t, text, `(Angka reproduksi efektif \(Rt\): 1.08 \(0.95 \226 1.20\))`)
+	assert.Contains(t, text, `(Kasus positif baru 7 hari terakhir: 70 \(turun 12.5% dari 80 pada 7 hari sebelumnya\))`)
+	assert.Contains(t, text, "(Kasus Positif Baru 2 Hari Terakhir)")
+	mockService.AssertExpectations(t)
+}
+
+func TestReportHandler_GetDailyReport_Errors(t *testing.T) {
+	mockService := new(MockReportService)
+	mockService.On("GetDailyReport", "72", time.Time{}).Return(nil, service.ErrReportNoData)
+	mockService.On("GetDailyReport", "72", time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)).Return(nil, errors.New("db down"))
+	handler := NewReportHandler(mockService)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/api/v1/reports/daily?date=14-07-2021", http.StatusBadRequest},
+		{"/api/v1/reports/daily", http.StatusNotFound},
+		{"/api/v1/reports/daily?date=2021-07-14", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		handler.GetDailyReport(rr, httptest.NewRequest("GET", tt.target, nil))
+		assert.Equal(t, tt.status, rr.Code, tt.target)
+	}
+}
+
+func TestFormatWeekChange(t *testing.T) {
+	report := &models.DailyReport{PreviousWeekPositive: 1200}
+	assert.Empty(t, formatWeekChange(report))
+	change := 25.0
+	report.WeekChangePercent = &change
+	assert.Equal(t, "(naik 25.0% dari 1.200 pada 7 hari sebelumnya)", formatWeekChange(report))
+	change = 0
+	assert.Equal(t, "(sama dengan 7 hari sebelumnya)", formatWeekChange(report))
+}
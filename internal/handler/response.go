@@ -5,7 +5,11 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"reflect"
 	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
 )
 
 type Response struct {
@@ -13,8 +17,30 @@ type Response struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Code and Field identify the invalid parameter of a 400 response
+	Code  string `json:"code,omitempty"`
+	Field string `json:"field,omitempty"`
+	// Meta is present on every response when announcements are enabled and
+	// on list responses when freshness is reported
+	Meta *ResponseMeta `json:"meta,omitempty"`
+}
+
+// ResponseMeta carries information about the API rather than the requested data
+type ResponseMeta struct {
+	// Notices are the active announcements, e.g. about delayed data
+	Notices []models.Notice `json:"notices"`
+	// DataFreshness is set on list responses
+	*models.DataFreshness
 }
 
+// noticeBoard supplies ResponseMeta.Notices. It is set by SetupRoutes and nil
+// when announcements are disabled.
+var noticeBoard service.NoticeBoard
+
+// freshness supplies ResponseMeta.DataFreshness. It is set by SetupRoutes and
+// nil when not configured.
+var freshness service.FreshnessReporter
+
 // PaginationMeta holds pagination metadata
 type PaginationMeta struct {
 	Page       int  `json:"page"`
@@ -85,7 +111,43 @@ func buildPaginationMeta(p PaginationParams, total int) PaginationMeta {
 	}
 }
 
+// listMeta returns the freshness meta of a list response, unwrapping
+// paginated data; it is nil for other data or without a freshness reporter
+func listMeta(data interface{}) *ResponseMeta {
+	if freshness == nil {
+		return nil
+	}
+	switch paginated := data.(type) {
+	case models.PaginatedResponse:
+		data = paginated.Data
+	case PaginatedResponse:
+		data = paginated.Data
+	}
+	if reflect.ValueOf(data).Kind() != reflect.Slice {
+		return nil
+	}
+	described := freshness.Describe(data)
+	return &ResponseMeta{DataFreshness: &described}
+}
+
+// withNotices adds the active announcements to meta, creating it when
+// announcements are enabled
+func withNotices(meta *ResponseMeta) *ResponseMeta {
+	if noticeBoard != nil {
+		if meta == nil {
+			meta = &ResponseMeta{}
+		}
+		meta.Notices = noticeBoard.ActiveNotices()
+	}
+	// Notices are always a list when there is a meta
+	if meta != nil && meta.Notices == nil {
+		meta.Notices = []models.Notice{}
+	}
+	return meta
+}
+
 func writeJSONResponse(w http.ResponseWriter, statusCode int, response Response) {
+	response.Meta = withNotices(response.Meta)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -93,20 +155,51 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, response Response)
 	}
 }
 
+// resultCountHeader reports how many items a list response returned, for clients
+// and for the slow request log
+const resultCountHeader = "X-Result-Count"
+
+func setResultCount(w http.ResponseWriter, data interface{}) {
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice {
+		w.Header().Set(resultCountHeader, strconv.Itoa(v.Len()))
+	}
+}
+
 func writeSuccessResponse(w http.ResponseWriter, data interface{}) {
+	writeSuccessResponseWithMeta(w, data, listMeta(data))
+}
+
+// writeSuccessResponseWithMeta is writeSuccessResponse with a meta described
+// beforehand, e.g. before the rows were stripped of their timestamps
+func writeSuccessResponseWithMeta(w http.ResponseWriter, data interface{}, meta *ResponseMeta) {
+	setResultCount(w, data)
 	writeJSONResponse(w, http.StatusOK, Response{
 		Status: "success",
 		Data:   data,
+		Meta:   meta,
 	})
 }
 
 func writePaginatedResponse(w http.ResponseWriter, data interface{}, meta PaginationMeta) {
+	setResultCount(w, data)
 	writeJSONResponse(w, http.StatusOK, Response{
 		Status: "success",
 		Data: PaginatedResponse{
 			Data:       data,
 			Pagination: meta,
 		},
+		Meta: listMeta(data),
+	})
+}
+
+// writeDryRunResponse reports the changes a mutating request would have made
+// without committing them. summary is a models.ChangeSummary, or the
+// models.SyncSummary of an ingestion.
+func writeDryRunResponse(w http.ResponseWriter, summary interface{}) {
+	writeJSONResponse(w, http.StatusOK, Response{
+		Status:  "success",
+		Message: "dry run: no changes committed",
+		Data:    summary,
 	})
 }
 
@@ -5,8 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWriteJSONResponse(t *testing.T) {
@@ -100,6 +105,41 @@ func TestWritePaginatedResponse(t *testing.T) {
 	assert.NotNil(t, response.Data)
 }
 
+func TestWriteResponses_Freshness(t *testing.T) {
+	freshness = service.NewFreshnessService(nil)
+	t.Cleanup(func() { freshness = nil })
+
+	// Lists get the freshness meta, other data does not
+	rr := httptest.NewRecorder()
+	writePaginatedResponse(rr, []string{"item1"}, PaginationMeta{Page: 1, PerPage: 10, Total: 1, TotalPages: 1})
+	assert.Contains(t, rr.Body.String(), `"meta":{"notices":[],"last_updated":null,"data_source":"","generated_at":`)
+	rr = httptest.NewRecorder()
+	writeSuccessResponse(rr, map[string]string{"key": "value"})
+	assert.NotContains(t, rr.Body.String(), `"meta"`)
+}
+
+func TestCovidHandler_GetNationalCases_Freshness(t *testing.T) {
+	freshness = service.NewFreshnessService(nil)
+	t.Cleanup(func() { freshness = nil })
+	mockService := new(MockCovidService)
+	updated := time.Date(2021, 7, 14, 1, 0, 0, 0, time.UTC)
+	mockService.On("ListNationalCases", service.QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: service.Page{Limit: 50}}).
+		Return([]models.NationalCase{{ID: 1, RecordTimestamps: models.RecordTimestamps{UpdatedAt: &updated}}}, 1, nil)
+
+	rr := httptest.NewRecorder()
+	NewCovidHandler(mockService, nil).GetNationalCases(rr, httptest.NewRequest("GET", "/api/v1/national", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.NotNil(t, response.Meta)
+	require.NotNil(t, response.Meta.DataFreshness)
+	// The rows lose their timestamps without ?include=timestamps, the meta keeps the latest
+	assert.NotContains(t, rr.Body.String(), `"updated_at"`)
+	assert.Equal(t, updated, *response.Meta.LastUpdated)
+	assert.False(t, response.Meta.GeneratedAt.IsZero())
+}
+
 func TestParsePaginationParams_Defaults(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
 	p := parsePaginationParams(req)
@@ -174,3 +214,13 @@ func TestBuildPaginationMeta_Empty(t *testing.T) {
 	assert.Equal(t, 1, meta.TotalPages)
 	assert.False(t, meta.HasNext)
 }
+
+func TestWriteSuccessResponse_SetsResultCount(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeSuccessResponse(w, []string{"a", "b", "c"})
+	assert.Equal(t, "3", w.Header().Get(resultCountHeader))
+
+	w = httptest.NewRecorder()
+	writeSuccessResponse(w, map[string]string{"a": "b"})
+	assert.Empty(t, w.Header().Get(resultCountHeader))
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+)
+
+// robotsCacheControl lets crawlers keep robots.txt for a day
+const robotsCacheControl = "public, max-age=86400"
+
+// RobotsHandler serves /robots.txt
+type RobotsHandler struct {
+	body string
+}
+
+// NewRobotsHandler builds robots.txt from the crawler configuration
+func NewRobotsHandler(cfg config.CrawlerConfig) *RobotsHandler {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(cfg.RobotsDisallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range cfg.RobotsDisallow {
+		fmt.Fprintf(&b, "Disallow: %s\n", path)
+	}
+	if cfg.RobotsCrawlDelay > 0 {
+		fmt.Fprintf(&b, "Crawl-delay: %d\n", cfg.RobotsCrawlDelay)
+	}
+	return &RobotsHandler{body: b.String()}
+}
+
+// ServeRobots writes robots.txt
+func (h *RobotsHandler) ServeRobots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", robotsCacheControl)
+	_, _ = w.Write([]byte(h.body))
+}
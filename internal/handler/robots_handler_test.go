@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRobotsHandler_ServeRobots(t *testing.T) {
+	router := SetupRoutes(Services{Crawler: config.CrawlerConfig{
+		RobotsDisallow:   []string{"/api/", "/admin/"},
+		RobotsCrawlDelay: 10,
+	}}, nil, false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "User-agent: *\nDisallow: /api/\nDisallow: /admin/\nCrawl-delay: 10\n", w.Body.String())
+}
+
+func TestRobotsHandler_AllowAll(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewRobotsHandler(config.CrawlerConfig{}).ServeRobots(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+
+	assert.Equal(t, "User-agent: *\nDisallow:\n", w.Body.String())
+}
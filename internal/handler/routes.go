@@ -2,13 +2,45 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/metrics"
+	"github.com/banua-coder/pico-api-go/internal/middleware"
 	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/internal/site"
 	"github.com/banua-coder/pico-api-go/pkg/database"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// subscribeRateLimit limits subscription requests per client on top of the
+// global rate limit, since each accepted request sends an email
+var subscribeRateLimit = config.RateLimitConfig{
+	Enabled:           true,
+	RequestsPerMinute: 5,
+	BurstSize:         5,
+	WindowSize:        time.Hour,
+}
+
+// shareRateLimit limits share link creation per client on top of the global
+// rate limit, since each new filter set stores a row
+var shareRateLimit = config.RateLimitConfig{
+	Enabled:           true,
+	RequestsPerMinute: 30,
+	BurstSize:         10,
+	WindowSize:        time.Minute,
+}
+
+// exportRateLimit limits export creation per client on top of the global
+// rate limit, since each export reads every matching case
+var exportRateLimit = config.RateLimitConfig{
+	Enabled:           true,
+	RequestsPerMinute: 10,
+	BurstSize:         5,
+	WindowSize:        time.Minute,
+}
+
 // Services holds all service dependencies for route setup
 type Services struct {
 	CovidService         service.CovidService
@@ -18,28 +50,202 @@ type Services struct {
 	VaccinationService   *service.VaccinationService
 	ProvinceStatsService service.ProvinceStatsServiceInterface
 	CacheInvalidator     service.CacheInvalidator
+	TableStats           service.TableStatsProvider
+	BackupService        service.BackupServiceInterface
+	SyncService          service.SyncServiceInterface
+	IntegrityService     service.IntegrityServiceInterface
+	SoftDeleteService    service.SoftDeleteServiceInterface
+	RtEstimationService  service.RtEstimationServiceInterface
+	CaseRevisionService  service.CaseRevisionServiceInterface
+	Latency              service.LatencyReporter
+	Bandwidth            service.BandwidthReporter
+	AbuseGuard           service.AbuseGuard
+	WebhookService       service.WebhookServiceInterface
+	AlertService         service.AlertServiceInterface
+	IngestionService     service.IngestionServiceInterface
+	SubscriptionService  service.SubscriptionServiceInterface
+	ShareService         service.ShareServiceInterface
+	SummaryService       service.SummaryServiceInterface
+	RecapService         service.RecapServiceInterface
+	ReportService        service.ReportServiceInterface
+	StatusService        service.StatusServiceInterface
+	APIStatusService     service.APIStatusServiceInterface
+	AnnouncementService  service.AnnouncementServiceInterface
+	CaseNoteService      service.CaseNoteServiceInterface
+	TestingService       service.TestingServiceInterface
+	PopulationService    service.PopulationServiceInterface
+	CaseStream           service.CaseStreamInterface
+	DashboardHub         service.DashboardHubInterface
+	ExportService        service.ExportServiceInterface
+	Freshness            service.FreshnessReporter
+	DataQuality          service.DataQualityReporter
+	ConfigReloader       service.ConfigReloader
+	Reaggregation        service.ReaggregationServiceInterface
+	RateLimits           service.RateLimitReporter
+	Readiness            service.ReadinessChecker
+	// Admin enables the admin endpoints under /admin and /api/v1/admin
+	Admin config.AdminConfig
+	// Crawler configures /robots.txt
+	Crawler config.CrawlerConfig
+	// Validation configures the checks on request parameters
+	Validation config.ValidationConfig
+	// RateLimit is the global rate limit reported by the API descriptor
+	RateLimit config.RateLimitConfig
 }
 
 func SetupRoutes(svc Services, db *database.DB, enableSwagger bool) *mux.Router {
 	router := mux.NewRouter()
 
+	// Active announcements go into the meta of every JSON response
+	noticeBoard = svc.AnnouncementService
+	// and the freshness of the returned rows into that of list responses
+	freshness = svc.Freshness
+
 	covidHandler := NewCovidHandler(svc.CovidService, db)
+	covidHandler.tableStats = svc.TableStats
+	covidHandler.population = svc.PopulationService
+	covidHandler.quality = svc.DataQuality
+	covidHandler.notes = svc.CaseNoteService
+
+	// Handlers and route limits tell clients apart as the global rate limit
+	// does, following X-Forwarded-For only from trusted proxies
+	clients := middleware.NewClientResolver(svc.RateLimit.TrustedProxies)
+	routeLimit := func(cfg config.RateLimitConfig) func(http.Handler) http.Handler {
+		cfg.TrustedProxies = svc.RateLimit.TrustedProxies
+		return middleware.RateLimit(cfg)
+	}
+
+	// Deprecated endpoints and parameters of the changelog announce themselves
+	router.Use(deprecationHeaders(apiChanges))
 
 	api := router.PathPrefix("/api/v1").Subrouter()
+	api.Use(dateRangeValidation(svc.Validation.MaxDateRangeDays))
 
 	// API index endpoint
 	api.HandleFunc("", covidHandler.GetAPIIndex).Methods("GET", "OPTIONS")
 	api.HandleFunc("/", covidHandler.GetAPIIndex).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tenant", GetTenant).Methods("GET", "OPTIONS")
+	api.HandleFunc("/changes", GetChanges).Methods("GET", "OPTIONS")
+	descriptorHandler := NewDescriptorHandler(svc.RateLimit, svc.Validation, metrics.Default)
+	api.HandleFunc("/.well-known/api-descriptor", descriptorHandler.GetDescriptor).Methods("GET", "OPTIONS")
 
 	// Main endpoints
 	api.HandleFunc("/health", covidHandler.HealthCheck).Methods("GET", "OPTIONS")
 	api.HandleFunc("/national", covidHandler.GetNationalCases).Methods("GET", "OPTIONS")
 	api.HandleFunc("/national/latest", covidHandler.GetLatestNationalCase).Methods("GET", "OPTIONS")
+	api.HandleFunc("/national/compare-periods", covidHandler.CompareNationalPeriods).Methods("GET", "OPTIONS")
+	if svc.VaccinationService != nil {
+		// Registered before /national/{day}, which would match it otherwise
+		api.HandleFunc("/national/vaccinations", NewVaccinationHandler(svc.VaccinationService).ListNationalVaccinations).Methods("GET", "OPTIONS")
+	}
+	if svc.TestingService != nil {
+		api.HandleFunc("/national/tests", NewTestingHandler(svc.TestingService).GetNationalTests).Methods("GET", "OPTIONS")
+	}
 	api.HandleFunc("/national/{day}", covidHandler.GetNationalCaseByDay).Methods("GET", "OPTIONS")
+	if svc.CaseRevisionService != nil {
+		api.HandleFunc("/national/{day}/revisions", NewCaseRevisionHandler(svc.CaseRevisionService).GetNationalRevisions).Methods("GET", "OPTIONS")
+	}
 	api.HandleFunc("/provinces", covidHandler.GetProvinces).Methods("GET", "OPTIONS")
 	api.HandleFunc("/provinces/cases", covidHandler.GetProvinceCases).Methods("GET", "OPTIONS")
+	api.HandleFunc("/provinces/latest", covidHandler.GetLatestProvinceCasesByIDs).Methods("GET", "OPTIONS")
 	api.HandleFunc("/provinces/{provinceId}/cases", covidHandler.GetProvinceCases).Methods("GET", "OPTIONS")
+	if svc.PopulationService != nil {
+		api.HandleFunc("/provinces/{provinceId}/per-capita", NewPopulationHandler(svc.PopulationService).GetProvincePerCapita).Methods("GET", "OPTIONS")
+	}
 	api.HandleFunc("/provinces/{code}", covidHandler.GetProvinceByID).Methods("GET", "OPTIONS")
+	api.HandleFunc("/export/xlsx", covidHandler.ExportXLSX).Methods("GET", "OPTIONS")
+
+	// Anomalies flagged in the case data
+	if svc.DataQuality != nil {
+		api.HandleFunc("/data-quality/issues", NewDataQualityHandler(svc.DataQuality).GetIssues).Methods("GET", "OPTIONS")
+	}
+
+	// Aggregated summary for dashboards
+	if svc.SummaryService != nil {
+		summaryHandler := NewSummaryHandler(svc.SummaryService)
+		api.HandleFunc("/summary", summaryHandler.GetSummary).Methods("GET", "OPTIONS")
+	}
+
+	// Yearly recap for the annual retrospective page
+	if svc.RecapService != nil {
+		recapHandler := NewRecapHandler(svc.RecapService)
+		api.HandleFunc("/recap/{year}", recapHandler.GetRecap).Methods("GET", "OPTIONS")
+	}
+
+	// Printable situational reports for health offices
+	if svc.ReportService != nil {
+		reportHandler := NewReportHandler(svc.ReportService)
+		api.HandleFunc("/reports/daily", reportHandler.GetDailyReport).Methods("GET", "OPTIONS")
+	}
+
+	// Traffic-light status of every province
+	if svc.StatusService != nil {
+		statusHandler := NewStatusHandler(svc.StatusService)
+		api.HandleFunc("/status", statusHandler.GetStatuses).Methods("GET", "OPTIONS")
+	}
+	if svc.CaseStream != nil {
+		api.HandleFunc("/stream/cases", NewStreamHandler(svc.CaseStream).StreamCases).Methods("GET", "OPTIONS")
+	}
+	if svc.DashboardHub != nil {
+		api.HandleFunc("/ws", NewWebSocketHandler(svc.DashboardHub, clients).Dashboard).Methods("GET")
+	}
+
+	// Status page of the API itself; /status is taken by the province statuses
+	if svc.APIStatusService != nil {
+		apiStatusHandler := NewAPIStatusHandler(svc.APIStatusService)
+		api.HandleFunc("/system-status", apiStatusHandler.GetStatus).Methods("GET", "OPTIONS")
+	}
+
+	// Derived statistics from the metrics registry
+	metricHandler := NewMetricHandler(svc.CovidService, metrics.Default)
+	api.HandleFunc("/metrics", metricHandler.ListMetrics).Methods("GET", "OPTIONS")
+	api.HandleFunc("/rankings", metricHandler.GetRankings).Methods("GET", "OPTIONS")
+	api.HandleFunc("/provinces/{provinceId}/metrics", metricHandler.GetProvinceMetrics).Methods("GET", "OPTIONS")
+	api.HandleFunc("/provinces/{provinceId}/calendar", metricHandler.GetProvinceCalendar).Methods("GET", "OPTIONS")
+
+	// GraphQL over the case data, for clients that need only some fields
+	graphqlHandler := NewGraphQLHandler(svc.CovidService)
+	api.HandleFunc("/graphql", graphqlHandler.Query).Methods("GET", "POST", "OPTIONS")
+
+	// Embeddable widgets, exempt from rate limiting through RATE_LIMIT_EXEMPT_PATHS
+	embedHandler := NewEmbedHandler(svc.CovidService)
+	api.HandleFunc("/embed/summary.html", embedHandler.GetSummaryCard).Methods("GET", "OPTIONS")
+
+	// Open Graph preview images
+	ogHandler := NewOGHandler(svc.CovidService)
+	api.HandleFunc("/og/daily.png", ogHandler.GetDailyImage).Methods("GET", "OPTIONS")
+
+	// Data submission endpoints
+	if svc.IngestionService != nil {
+		ingestionHandler := NewIngestionHandler(svc.IngestionService)
+		api.HandleFunc("/national", ingestionHandler.SubmitNationalCases).Methods("POST")
+		api.HandleFunc("/provinces/{provinceId}/cases", ingestionHandler.SubmitProvinceCases).Methods("POST")
+	}
+
+	// Email subscription endpoints
+	if svc.SubscriptionService != nil {
+		subscriptionHandler := NewSubscriptionHandler(svc.SubscriptionService)
+		subscribe := routeLimit(subscribeRateLimit)(http.HandlerFunc(subscriptionHandler.Subscribe))
+		api.Handle("/subscriptions", subscribe).Methods("POST", "OPTIONS")
+		api.HandleFunc("/subscriptions/confirm", subscriptionHandler.ConfirmSubscription).Methods("GET", "OPTIONS")
+		api.HandleFunc("/subscriptions/unsubscribe", subscriptionHandler.Unsubscribe).Methods("GET", "OPTIONS")
+	}
+
+	// Share link endpoints
+	if svc.ExportService != nil {
+		exportJobHandler := NewExportJobHandler(svc.ExportService)
+		createExport := routeLimit(exportRateLimit)(http.HandlerFunc(exportJobHandler.CreateExport))
+		api.Handle("/exports", createExport).Methods("POST", "OPTIONS")
+		api.HandleFunc("/exports/{id}", exportJobHandler.GetExport).Methods("GET", "OPTIONS")
+		api.HandleFunc("/exports/{id}/download", exportJobHandler.DownloadExport).Methods("GET", "OPTIONS")
+	}
+
+	if svc.ShareService != nil {
+		shareHandler := NewShareHandler(svc.ShareService)
+		createShareLink := routeLimit(shareRateLimit)(http.HandlerFunc(shareHandler.CreateShareLink))
+		api.Handle("/share", createShareLink).Methods("POST", "OPTIONS")
+		router.HandleFunc("/s/{code}", shareHandler.ExpandShareLink).Methods("GET", "OPTIONS")
+	}
 
 	// Regency endpoints
 	if svc.RegencyService != nil {
@@ -47,6 +253,8 @@ func SetupRoutes(svc Services, db *database.DB, enableSwagger bool) *mux.Router
 		api.HandleFunc("/regencies", regencyHandler.GetRegencies).Methods("GET", "OPTIONS")
 		api.HandleFunc("/regencies/{code}", regencyHandler.GetRegencyByID).Methods("GET", "OPTIONS")
 		api.HandleFunc("/regencies/{code}/cases", regencyHandler.GetRegencyCases).Methods("GET", "OPTIONS")
+		api.HandleFunc("/provinces/{provinceId}/districts", regencyHandler.GetProvinceDistricts).Methods("GET", "OPTIONS")
+		api.HandleFunc("/districts/{districtId}/cases", regencyHandler.GetDistrictCases).Methods("GET", "OPTIONS")
 	}
 
 	// Hospital endpoints
@@ -68,6 +276,7 @@ func SetupRoutes(svc Services, db *database.DB, enableSwagger bool) *mux.Router
 		api.HandleFunc("/vaccination/national", vaccinationHandler.GetNationalVaccinations).Methods("GET", "OPTIONS")
 		api.HandleFunc("/vaccination/province", vaccinationHandler.GetProvinceVaccinations).Methods("GET", "OPTIONS")
 		api.HandleFunc("/vaccination/locations", vaccinationHandler.GetVaccineLocations).Methods("GET", "OPTIONS")
+		api.HandleFunc("/provinces/{provinceId}/vaccinations", vaccinationHandler.ListProvinceVaccinations).Methods("GET", "OPTIONS")
 	}
 
 	// Province stats endpoints (gender cases, tests)
@@ -79,12 +288,24 @@ func SetupRoutes(svc Services, db *database.DB, enableSwagger bool) *mux.Router
 		api.HandleFunc("/stats/test-types", statsHandler.GetTestTypes).Methods("GET", "OPTIONS")
 	}
 
-	// Admin endpoints
-	if svc.CacheInvalidator != nil {
-		adminHandler := NewAdminHandler(svc.CacheInvalidator)
-		router.HandleFunc("/admin/cache/clear", adminHandler.ClearCache).Methods("POST", "OPTIONS")
+	if svc.WebhookService != nil {
+		api.HandleFunc("/webhooks", NewWebhookHandler(svc.WebhookService).CreateSubscription).Methods("POST", "OPTIONS")
+	}
+
+	// Admin endpoints, off unless enabled in the configuration
+	if svc.Admin.Enabled {
+		setupAdminRoutes(router, api, svc)
+	}
+
+	// Orchestrator probes live outside /api/v1, clear of API keys
+	probeHandler := NewProbeHandler(svc.Readiness)
+	router.HandleFunc("/healthz", probeHandler.Liveness).Methods("GET", "HEAD")
+	if svc.Readiness != nil {
+		router.HandleFunc("/readyz", probeHandler.Readiness).Methods("GET", "HEAD")
 	}
 
+	router.HandleFunc("/robots.txt", NewRobotsHandler(svc.Crawler).ServeRobots).Methods("GET", "HEAD")
+
 	// Conditionally add Swagger documentation based on environment
 	if enableSwagger {
 		router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
@@ -92,10 +313,119 @@ func SetupRoutes(svc Services, db *database.DB, enableSwagger bool) *mux.Router
 			http.Redirect(w, r, "/swagger/index.html", http.StatusFound)
 		}).Methods("GET")
 	} else {
-		router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			http.Redirect(w, r, "/api/v1", http.StatusFound)
-		}).Methods("GET")
+		// Production builds serve the embedded landing page instead
+		siteHandler := site.Handler()
+		router.Handle("/", siteHandler).Methods("GET", "HEAD")
+		router.PathPrefix("/assets/").Handler(siteHandler).Methods("GET", "HEAD")
 	}
 
 	return router
 }
+
+// setupAdminRoutes adds the admin endpoints under /admin and /api/v1/admin.
+// The /admin handlers check the admin key themselves; the maintenance
+// endpoints go through requireAdmin.
+func setupAdminRoutes(router, api *mux.Router, svc Services) {
+	if svc.CacheInvalidator != nil {
+		adminHandler := NewAdminHandler(svc.CacheInvalidator)
+		router.HandleFunc("/admin/cache/clear", adminHandler.ClearCache).Methods("POST", "OPTIONS")
+	}
+	if svc.BackupService != nil {
+		backupHandler := NewBackupHandler(svc.BackupService)
+		router.HandleFunc("/admin/backups", backupHandler.StartBackup).Methods("POST", "OPTIONS")
+		router.HandleFunc("/admin/backups", backupHandler.ListBackups).Methods("GET")
+		router.HandleFunc("/admin/backups/{id}", backupHandler.GetBackup).Methods("GET", "OPTIONS")
+	}
+	if svc.SyncService != nil {
+		syncHandler := NewSyncHandler(svc.SyncService)
+		router.HandleFunc("/admin/sync-runs", syncHandler.ListSyncRuns).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/sync-runs/{id:[0-9]+}", syncHandler.GetSyncRun).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/sync-runs/{id}/rollback", syncHandler.RollbackSyncRun).Methods("POST", "OPTIONS")
+	}
+	if svc.Latency != nil {
+		latencyHandler := NewLatencyHandler(svc.Latency)
+		api.HandleFunc("/admin/latency", latencyHandler.GetLatency).Methods("GET", "OPTIONS")
+	}
+	if svc.Bandwidth != nil {
+		bandwidthHandler := NewBandwidthHandler(svc.Bandwidth)
+		api.HandleFunc("/admin/bandwidth", bandwidthHandler.GetBandwidth).Methods("GET", "OPTIONS")
+	}
+	if svc.AbuseGuard != nil {
+		abuseHandler := NewAbuseHandler(svc.AbuseGuard)
+		router.HandleFunc("/admin/bans", abuseHandler.ListBans).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/bans/{ip}", abuseHandler.Unban).Methods("DELETE", "OPTIONS")
+	}
+	if svc.IntegrityService != nil {
+		integrityHandler := NewIntegrityHandler(svc.IntegrityService)
+		router.HandleFunc("/admin/integrity/orphans", integrityHandler.GetOrphanReport).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/integrity/orphans/quarantine", integrityHandler.QuarantineOrphans).Methods("POST", "OPTIONS")
+	}
+	if svc.SoftDeleteService != nil {
+		softDeleteHandler := NewSoftDeleteHandler(svc.SoftDeleteService)
+		router.HandleFunc("/admin/national/{day:[0-9]+}", softDeleteHandler.DeleteNationalCase).Methods("DELETE", "OPTIONS")
+		router.HandleFunc("/admin/national/{day:[0-9]+}/restore", softDeleteHandler.RestoreNationalCase).Methods("POST", "OPTIONS")
+		router.HandleFunc("/admin/provinces/{provinceId}/cases/{day:[0-9]+}", softDeleteHandler.DeleteProvinceCase).Methods("DELETE", "OPTIONS")
+		router.HandleFunc("/admin/provinces/{provinceId}/cases/{day:[0-9]+}/restore", softDeleteHandler.RestoreProvinceCase).Methods("POST", "OPTIONS")
+		router.HandleFunc("/admin/provinces/{provinceId}", softDeleteHandler.DeleteProvince).Methods("DELETE", "OPTIONS")
+		router.HandleFunc("/admin/provinces/{provinceId}/restore", softDeleteHandler.RestoreProvince).Methods("POST", "OPTIONS")
+	}
+	if svc.RtEstimationService != nil {
+		rtHandler := NewRtEstimationHandler(svc.RtEstimationService)
+		router.HandleFunc("/admin/rt/estimate", rtHandler.EstimateRt).Methods("POST", "OPTIONS")
+	}
+	if svc.WebhookService != nil {
+		webhookHandler := NewWebhookHandler(svc.WebhookService)
+		router.HandleFunc("/admin/webhooks", webhookHandler.ListSubscriptions).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/webhooks/{id:[0-9]+}/deliveries", webhookHandler.ListDeliveries).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/webhooks/deliveries/{id:[0-9]+}", webhookHandler.GetDelivery).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/webhooks/deliveries/{id:[0-9]+}/redeliver", webhookHandler.Redeliver).Methods("POST", "OPTIONS")
+	}
+	if svc.AlertService != nil {
+		alertHandler := NewAlertHandler(svc.AlertService)
+		router.HandleFunc("/admin/alert-rules", alertHandler.ListRules).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/alert-rules", alertHandler.CreateRule).Methods("POST")
+		router.HandleFunc("/admin/alert-rules/{id:[0-9]+}", alertHandler.GetRule).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/alert-rules/{id:[0-9]+}", alertHandler.UpdateRule).Methods("PUT")
+		router.HandleFunc("/admin/alert-rules/{id:[0-9]+}", alertHandler.DeleteRule).Methods("DELETE")
+		router.HandleFunc("/admin/alert-rules/{id:[0-9]+}/evaluations", alertHandler.ListEvaluations).Methods("GET", "OPTIONS")
+	}
+	if svc.AnnouncementService != nil {
+		announcementHandler := NewAnnouncementHandler(svc.AnnouncementService)
+		router.HandleFunc("/admin/announcements", announcementHandler.ListAnnouncements).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/announcements", announcementHandler.CreateAnnouncement).Methods("POST")
+		router.HandleFunc("/admin/announcements/{id:[0-9]+}", announcementHandler.UpdateAnnouncement).Methods("PUT")
+		router.HandleFunc("/admin/announcements/{id:[0-9]+}", announcementHandler.DeleteAnnouncement).Methods("DELETE")
+	}
+	if svc.CaseNoteService != nil {
+		caseNoteHandler := NewCaseNoteHandler(svc.CaseNoteService)
+		router.HandleFunc("/admin/notes", caseNoteHandler.ListNotes).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/notes", caseNoteHandler.CreateNote).Methods("POST")
+		router.HandleFunc("/admin/notes/{id:[0-9]+}", caseNoteHandler.UpdateNote).Methods("PUT")
+		router.HandleFunc("/admin/notes/{id:[0-9]+}", caseNoteHandler.DeleteNote).Methods("DELETE")
+	}
+	if svc.APIStatusService != nil {
+		apiStatusHandler := NewAPIStatusHandler(svc.APIStatusService)
+		router.HandleFunc("/admin/incidents", apiStatusHandler.ListIncidents).Methods("GET", "OPTIONS")
+		router.HandleFunc("/admin/incidents", apiStatusHandler.CreateIncident).Methods("POST")
+		router.HandleFunc("/admin/incidents/{id:[0-9]+}", apiStatusHandler.UpdateIncident).Methods("PUT")
+		router.HandleFunc("/admin/incidents/{id:[0-9]+}", apiStatusHandler.DeleteIncident).Methods("DELETE")
+	}
+	router.HandleFunc("/admin/outbound", GetOutboundStats).Methods("GET", "OPTIONS")
+
+	// Maintenance endpoints under /api/v1/admin
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(requireAdmin)
+	maintenanceHandler := NewMaintenanceHandler(svc.ConfigReloader, svc.Reaggregation, svc.RateLimits)
+	if svc.CacheInvalidator != nil {
+		admin.HandleFunc("/cache/flush", NewAdminHandler(svc.CacheInvalidator).ClearCache).Methods("POST", "OPTIONS")
+	}
+	if svc.ConfigReloader != nil {
+		admin.HandleFunc("/config/reload", maintenanceHandler.ReloadConfig).Methods("POST", "OPTIONS")
+	}
+	if svc.Reaggregation != nil {
+		admin.HandleFunc("/reaggregate", maintenanceHandler.Reaggregate).Methods("POST", "OPTIONS")
+	}
+	if svc.RateLimits != nil {
+		admin.HandleFunc("/rate-limits", maintenanceHandler.GetRateLimits).Methods("GET", "OPTIONS")
+	}
+}
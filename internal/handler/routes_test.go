@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupRoutes_RootServesSiteWithoutSwagger(t *testing.T) {
+	router := SetupRoutes(Services{}, nil, false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>PICO API</title>")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/style.css", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSetupRoutes_RootRedirectsToSwagger(t *testing.T) {
+	router := SetupRoutes(Services{}, nil, true)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/swagger/index.html", w.Header().Get("Location"))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// RtEstimationHandler handles the admin endpoint estimating Rt.
+type RtEstimationHandler struct {
+	service service.RtEstimationServiceInterface
+}
+
+// NewRtEstimationHandler creates a new RtEstimationHandler.
+func NewRtEstimationHandler(service service.RtEstimationServiceInterface) *RtEstimationHandler {
+	return &RtEstimationHandler{service: service}
+}
+
+// EstimateRt godoc
+//
+//	@Summary		Estimate missing Rt values
+//	@Description	Estimates Rt with its 95% credible interval from the daily positive cases (Cori method) and writes it to the rt, rt_lower and rt_upper of national and province rows without one. The write is a sync run that can be rolled back. Supports dry_run=true.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			overwrite	query		boolean	false	"Replace the Rt of rows that already have one"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		500			{object}	Response
+//	@Router			/admin/rt/estimate [post]
+func (h *RtEstimationHandler) EstimateRt(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	dryRun := isDryRun(r)
+	summary, err := h.service.Estimate(r.Context(), utils.ParseBoolQueryParam(r, "overwrite"), dryRun)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if dryRun {
+		writeDryRunResponse(w, *summary)
+		return
+	}
+	writeSuccessResponse(w, summary)
+}
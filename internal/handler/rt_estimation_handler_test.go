@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRtEstimationService struct{ mock.Mock }
+
+func (m *MockRtEstimationService) Estimate(ctx context.Context, overwrite, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(overwrite, dryRun)
+	if r := args.Get(0); r != nil {
+		return r.(*models.ChangeSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestRtEstimationHandler_EstimateRt(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockRtEstimationService)
+	svc.On("Estimate", true, false).Return(&models.ChangeSummary{Updated: 12}, nil)
+	router := SetupRoutes(Services{RtEstimationService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/rt/estimate?overwrite=true", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"updated":12`)
+	svc.AssertExpectations(t)
+}
+
+func TestRtEstimationHandler_EstimateRt_DryRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockRtEstimationService)
+	svc.On("Estimate", false, true).Return(&models.ChangeSummary{DryRun: true, Updated: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/rt/estimate?dry_run=true", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	NewRtEstimationHandler(svc).EstimateRt(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	svc.AssertExpectations(t)
+}
+
+func TestRtEstimationHandler_EstimateRt_Errors(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockRtEstimationService)
+	svc.On("Estimate", false, false).Return(nil, errors.New("db down"))
+
+	w := httptest.NewRecorder()
+	NewRtEstimationHandler(svc).EstimateRt(w, httptest.NewRequest(http.MethodPost, "/admin/rt/estimate", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/rt/estimate", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w = httptest.NewRecorder()
+	NewRtEstimationHandler(svc).EstimateRt(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
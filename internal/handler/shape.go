@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// Response shapes of the case data selected with ?shape=. The legacy shape,
+// selected with ?legacy=true, is the pre-2.0 schema with Indonesian keys.
+const (
+	shapeNested = "nested"
+	shapeFlat   = "flat"
+	shapeLegacy = "legacy"
+)
+
+// responseShape returns the shape the client asked for. It writes a 400 for
+// an unknown shape and reports whether the request may go on.
+func responseShape(w http.ResponseWriter, r *http.Request) (string, bool) {
+	shape := r.URL.Query().Get("shape")
+	if utils.ParseBoolQueryParam(r, "legacy") {
+		if shape != "" && shape != shapeLegacy {
+			writeValidationError(w, &ValidationError{
+				Code:    ErrCodeInvalidShape,
+				Field:   "shape",
+				Message: "legacy=true cannot be combined with another shape",
+			})
+			return "", false
+		}
+		return shapeLegacy, true
+	}
+	switch shape {
+	case "", shapeNested:
+		return shapeNested, true
+	case shapeFlat, shapeLegacy:
+		return shape, true
+	default:
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidShape,
+			Field:   "shape",
+			Message: fmt.Sprintf("Invalid shape %q. Use %s, %s or %s", shape, shapeNested, shapeFlat, shapeLegacy),
+		})
+		return "", false
+	}
+}
+
+// Values of ?include=: the record timestamps created_at/updated_at, and the
+// analyst notes of the rows' dates
+const (
+	includeTimestamps = "timestamps"
+	includeNotes      = "notes"
+)
+
+// wantsInclude reports whether ?include= lists value
+func wantsInclude(r *http.Request, value string) bool {
+	for _, include := range utils.ParseStringArrayQueryParam(r, "include") {
+		if include == value {
+			return true
+		}
+	}
+	return false
+}
+
+// wantsTimestamps reports whether ?include= lists the record timestamps
+func wantsTimestamps(r *http.Request) bool {
+	return wantsInclude(r, includeTimestamps)
+}
+
+// stripTimestamps clears the record timestamps of the rows in place unless
+// the client asked for them
+func stripTimestamps[T any](r *http.Request, rows []T) {
+	if wantsTimestamps(r) {
+		return
+	}
+	for i := range rows {
+		if row, ok := any(&rows[i]).(interface{ ClearTimestamps() }); ok {
+			row.ClearTimestamps()
+		}
+	}
+}
+
+// caseView is how case rows are written: converted to a shape, then cut down
+// to the ?fields= the client selected
+type caseView struct {
+	shape  string
+	fields models.FieldSet
+}
+
+// caseViewOf reads the shape and the ?fields= of rows like sample, which are
+// checked against the fields of the shape. It writes a 400 for an unknown
+// shape or field and reports whether the request may go on.
+func caseViewOf(w http.ResponseWriter, r *http.Request, sample interface{}) (caseView, bool) {
+	shape, ok := responseShape(w, r)
+	if !ok {
+		return caseView{}, false
+	}
+	fields, err := models.ParseFieldSet(r.URL.Query().Get("fields"), reflect.TypeOf(reshape(sample, shape)))
+	if err != nil {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidField,
+			Field:   "fields",
+			Message: err.Error(),
+		})
+		return caseView{}, false
+	}
+	return caseView{shape: shape, fields: fields}, true
+}
+
+// isDefault reports whether rows are written as they are
+func (v caseView) isDefault() bool {
+	return v.shape == shapeNested && v.fields.IsZero()
+}
+
+// render converts a row to the view
+func (v caseView) render(row interface{}) interface{} {
+	return v.fields.Select(reshape(row, v.shape))
+}
+
+// reshapeRows renders rows in the view
+func reshapeRows[T any](rows []T, view caseView) []interface{} {
+	reshaped := make([]interface{}, len(rows))
+	for i, row := range rows {
+		reshaped[i] = view.render(row)
+	}
+	return reshaped
+}
+
+func reshape(row interface{}, shape string) interface{} {
+	switch c := row.(type) {
+	case models.NationalCaseResponse:
+		switch shape {
+		case shapeFlat:
+			return c.Flat()
+		case shapeLegacy:
+			return c.Legacy()
+		}
+	case models.ProvinceCaseResponse:
+		switch shape {
+		case shapeFlat:
+			return c.Flat()
+		case shapeLegacy:
+			return c.Legacy()
+		}
+	case models.AggregatedCaseResponse:
+		if shape == shapeFlat {
+			return c.Flat()
+		}
+	}
+	return row
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// maxShareBodyBytes bounds the create share link request body
+const maxShareBodyBytes = 4 << 10
+
+// ShareHandler handles the short link endpoints for shareable filtered views.
+type ShareHandler struct {
+	service service.ShareServiceInterface
+}
+
+// NewShareHandler creates a new ShareHandler.
+func NewShareHandler(service service.ShareServiceInterface) *ShareHandler {
+	return &ShareHandler{service: service}
+}
+
+// shareLinkResponse is a share link with the API request returning its cases
+type shareLinkResponse struct {
+	*models.ShareLink
+	CasesPath string `json:"cases_path"`
+}
+
+func newShareLinkResponse(link *models.ShareLink) shareLinkResponse {
+	return shareLinkResponse{ShareLink: link, CasesPath: link.Filters.CasesPath()}
+}
+
+// CreateShareLink godoc
+//
+//	@Summary		Create a share link
+//	@Description	Stores a filtered view (province, date range, metrics) under a short code. Sharing the same filters again returns the same link. Rate limited per client.
+//	@Tags			share
+//	@Accept			json
+//	@Produce		json
+//	@Param			filters	body		models.ShareFilters	true	"Filters of the view"
+//	@Success		201		{object}	Response{data=shareLinkResponse}
+//	@Failure		400		{object}	Response
+//	@Failure		429		{object}	Response
+//	@Router			/share [post]
+func (h *ShareHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	var filters models.ShareFilters
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxShareBodyBytes)).Decode(&filters); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	link, err := h.service.CreateLink(r.Context(), filters)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidShareFilters) {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create share link")
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, Response{Status: "success", Data: newShareLinkResponse(link)})
+}
+
+// ExpandShareLink godoc
+//
+//	@Summary		Open a share link
+//	@Description	Redirects to the dashboard showing the shared view. Returns the stored filters as JSON instead when expand=true or when no dashboard is configured.
+//	@Tags			share
+//	@Produce		json
+//	@Param			code	path		string	true	"Share code"
+//	@Param			expand	query		boolean	false	"Return the filters instead of redirecting"
+//	@Success		200		{object}	Response{data=shareLinkResponse}
+//	@Success		302		{string}	string	"Redirect to the dashboard"
+//	@Failure		404		{object}	Response
+//	@Router			/s/{code} [get]
+func (h *ShareHandler) ExpandShareLink(w http.ResponseWriter, r *http.Request) {
+	link, err := h.service.GetLink(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load share link")
+		return
+	}
+	if link == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Share link not found")
+		return
+	}
+	if target := h.service.DashboardURL(link); target != "" && !utils.ParseBoolQueryParam(r, "expand") {
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+	writeSuccessResponse(w, newShareLinkResponse(link))
+}
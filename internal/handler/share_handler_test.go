@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockShareService struct {
+	mock.Mock
+}
+
+func (m *MockShareService) CreateLink(ctx context.Context, filters models.ShareFilters) (*models.ShareLink, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShareLink), args.Error(1)
+}
+
+func (m *MockShareService) GetLink(ctx context.Context, code string) (*models.ShareLink, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShareLink), args.Error(1)
+}
+
+func (m *MockShareService) DashboardURL(link *models.ShareLink) string {
+	return m.Called(link).String(0)
+}
+
+func TestShareHandler_CreateShareLink(t *testing.T) {
+	svc := new(MockShareService)
+	filters := models.ShareFilters{ProvinceID: "72", StartDate: "2021-06-01", EndDate: "2021-08-31", Metrics: []string{"rt"}}
+	svc.On("CreateLink", filters).Return(&models.ShareLink{Code: "abc1234", Filters: filters, URL: "http://localhost:8080/s/abc1234"}, nil)
+	router := SetupRoutes(Services{ShareService: svc}, nil, false)
+
+	w := serveSubscriptions(router, http.MethodPost, "/api/v1/share",
+		`{"province_id":"72","start_date":"2021-06-01","end_date":"2021-08-31","metrics":["rt"]}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"abc1234"`)
+	assert.Contains(t, w.Body.String(), `"url":"http://localhost:8080/s/abc1234"`)
+	assert.Contains(t, w.Body.String(), `"cases_path":"/api/v1/provinces/72/cases?end_date=2021-08-31\u0026start_date=2021-06-01"`)
+	svc.AssertExpectations(t)
+}
+
+func TestShareHandler_CreateShareLink_Invalid(t *testing.T) {
+	svc := new(MockShareService)
+	svc.On("CreateLink", mock.Anything).Return(nil, fmt.Errorf("%w: unknown metric", service.ErrInvalidShareFilters))
+	router := SetupRoutes(Services{ShareService: svc}, nil, false)
+
+	w := serveSubscriptions(router, http.MethodPost, "/api/v1/share", `{"metrics":["vaccinated"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown metric")
+
+	w = serveSubscriptions(router, http.MethodPost, "/api/v1/share", `not json`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestShareHandler_ExpandShareLink_Redirect(t *testing.T) {
+	svc := new(MockShareService)
+	link := &models.ShareLink{Code: "abc1234", Filters: models.ShareFilters{ProvinceID: "72"}}
+	svc.On("GetLink", "abc1234").Return(link, nil)
+	svc.On("DashboardURL", link).Return("https://dashboard.example.com/?province=72")
+	router := SetupRoutes(Services{ShareService: svc}, nil, false)
+
+	w := serveSubscriptions(router, http.MethodGet, "/s/abc1234", "")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://dashboard.example.com/?province=72", w.Header().Get("Location"))
+
+	w = serveSubscriptions(router, http.MethodGet, "/s/abc1234?expand=true", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"province_id":"72"`)
+}
+
+func TestShareHandler_ExpandShareLink_WithoutDashboard(t *testing.T) {
+	svc := new(MockShareService)
+	link := &models.ShareLink{Code: "abc1234"}
+	svc.On("GetLink", "abc1234").Return(link, nil)
+	svc.On("DashboardURL", link).Return("")
+	router := SetupRoutes(Services{ShareService: svc}, nil, false)
+
+	w := serveSubscriptions(router, http.MethodGet, "/s/abc1234", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"cases_path":"/api/v1/national"`)
+}
+
+func TestShareHandler_ExpandShareLink_NotFound(t *testing.T) {
+	svc := new(MockShareService)
+	svc.On("GetLink", "missing").Return(nil, nil)
+	svc.On("GetLink", "broken").Return(nil, errors.New("db down"))
+	router := SetupRoutes(Services{ShareService: svc}, nil, false)
+
+	assert.Equal(t, http.StatusNotFound, serveSubscriptions(router, http.MethodGet, "/s/missing", "").Code)
+	assert.Equal(t, http.StatusInternalServerError, serveSubscriptions(router, http.MethodGet, "/s/broken", "").Code)
+}
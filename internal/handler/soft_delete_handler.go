@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// SoftDeleteHandler handles the admin endpoints hiding erroneous records
+// from the API and restoring them.
+type SoftDeleteHandler struct {
+	service service.SoftDeleteServiceInterface
+}
+
+// NewSoftDeleteHandler creates a new SoftDeleteHandler.
+func NewSoftDeleteHandler(service service.SoftDeleteServiceInterface) *SoftDeleteHandler {
+	return &SoftDeleteHandler{service: service}
+}
+
+// DeleteNationalCase godoc
+//
+//	@Summary		Soft-delete the national case of a day
+//	@Description	Hides the case from every endpoint without removing the row, so province cases referencing the day and its revision history stay intact. Recorded as a sync run that can be rolled back.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			day			path		integer	true	"Day number"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/national/{day} [delete]
+func (h *SoftDeleteHandler) DeleteNationalCase(w http.ResponseWriter, r *http.Request) {
+	h.handleDay(w, r, func(day int64, dryRun bool) (*models.ChangeSummary, error) {
+		return h.service.DeleteNationalCase(r.Context(), day, dryRun)
+	})
+}
+
+// RestoreNationalCase godoc
+//
+//	@Summary		Restore a soft-deleted national case
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			day			path		integer	true	"Day number"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/national/{day}/restore [post]
+func (h *SoftDeleteHandler) RestoreNationalCase(w http.ResponseWriter, r *http.Request) {
+	h.handleDay(w, r, func(day int64, dryRun bool) (*models.ChangeSummary, error) {
+		return h.service.RestoreNationalCase(r.Context(), day, dryRun)
+	})
+}
+
+// DeleteProvinceCase godoc
+//
+//	@Summary		Soft-delete the case of a province on a day
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			provinceId	path		string	true	"Province ID"
+//	@Param			day			path		integer	true	"Day number"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/provinces/{provinceId}/cases/{day} [delete]
+func (h *SoftDeleteHandler) DeleteProvinceCase(w http.ResponseWriter, r *http.Request) {
+	h.handleDay(w, r, func(day int64, dryRun bool) (*models.ChangeSummary, error) {
+		return h.service.DeleteProvinceCase(r.Context(), mux.Vars(r)["provinceId"], day, dryRun)
+	})
+}
+
+// RestoreProvinceCase godoc
+//
+//	@Summary		Restore a soft-deleted province case
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			provinceId	path		string	true	"Province ID"
+//	@Param			day			path		integer	true	"Day number"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/provinces/{provinceId}/cases/{day}/restore [post]
+func (h *SoftDeleteHandler) RestoreProvinceCase(w http.ResponseWriter, r *http.Request) {
+	h.handleDay(w, r, func(day int64, dryRun bool) (*models.ChangeSummary, error) {
+		return h.service.RestoreProvinceCase(r.Context(), mux.Vars(r)["provinceId"], day, dryRun)
+	})
+}
+
+// DeleteProvince godoc
+//
+//	@Summary		Soft-delete a province
+//	@Description	Hides the province and all of its cases from every endpoint. Province deletions have no revisions and are undone with the restore endpoint.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			provinceId	path		string	true	"Province ID"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/provinces/{provinceId} [delete]
+func (h *SoftDeleteHandler) DeleteProvince(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	summary, err := h.service.DeleteProvince(r.Context(), mux.Vars(r)["provinceId"], isDryRun(r))
+	writeSoftDeleteResult(w, summary, err)
+}
+
+// RestoreProvince godoc
+//
+//	@Summary		Restore a soft-deleted province with its cases
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			provinceId	path		string	true	"Province ID"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/provinces/{provinceId}/restore [post]
+func (h *SoftDeleteHandler) RestoreProvince(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	summary, err := h.service.RestoreProvince(r.Context(), mux.Vars(r)["provinceId"], isDryRun(r))
+	writeSoftDeleteResult(w, summary, err)
+}
+
+// handleDay authorizes the request and applies fn to the day of the path
+// and the dry_run flag
+func (h *SoftDeleteHandler) handleDay(w http.ResponseWriter, r *http.Request, fn func(day int64, dryRun bool) (*models.ChangeSummary, error)) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	day, err := strconv.ParseInt(mux.Vars(r)["day"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid day")
+		return
+	}
+	summary, err := fn(day, isDryRun(r))
+	writeSoftDeleteResult(w, summary, err)
+}
+
+func writeSoftDeleteResult(w http.ResponseWriter, summary *models.ChangeSummary, err error) {
+	switch {
+	case errors.Is(err, service.ErrSoftDeleteNoMatch):
+		writeErrorResponse(w, http.StatusNotFound, "Record not found or already in the requested state")
+	case err != nil:
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+	case summary.DryRun:
+		writeDryRunResponse(w, *summary)
+	default:
+		writeSuccessResponse(w, summary)
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSoftDeleteService struct{ mock.Mock }
+
+func (m *MockSoftDeleteService) DeleteNationalCase(ctx context.Context, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(day, dryRun))
+}
+
+func (m *MockSoftDeleteService) RestoreNationalCase(ctx context.Context, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(day, dryRun))
+}
+
+func (m *MockSoftDeleteService) DeleteProvinceCase(ctx context.Context, provinceID string, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(provinceID, day, dryRun))
+}
+
+func (m *MockSoftDeleteService) RestoreProvinceCase(ctx context.Context, provinceID string, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(provinceID, day, dryRun))
+}
+
+func (m *MockSoftDeleteService) DeleteProvince(ctx context.Context, id string, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(id, dryRun))
+}
+
+func (m *MockSoftDeleteService) RestoreProvince(ctx context.Context, id string, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(id, dryRun))
+}
+
+func (m *MockSoftDeleteService) summary(args mock.Arguments) (*models.ChangeSummary, error) {
+	if r := args.Get(0); r != nil {
+		return r.(*models.ChangeSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestSoftDeleteHandler_Routes(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSoftDeleteService)
+	deleted := &models.ChangeSummary{Deleted: 1}
+	restored := &models.ChangeSummary{Restored: 1}
+	svc.On("DeleteNationalCase", int64(8), false).Return(deleted, nil)
+	svc.On("RestoreNationalCase", int64(8), false).Return(restored, nil)
+	svc.On("DeleteProvinceCase", "72", int64(8), false).Return(deleted, nil)
+	svc.On("RestoreProvinceCase", "72", int64(8), false).Return(restored, nil)
+	svc.On("DeleteProvince", "72", false).Return(deleted, nil)
+	svc.On("RestoreProvince", "72", false).Return(restored, nil)
+	router := SetupRoutes(Services{SoftDeleteService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	for _, tc := range []struct {
+		method, path, want string
+	}{
+		{http.MethodDelete, "/admin/national/8", `"deleted":1`},
+		{http.MethodPost, "/admin/national/8/restore", `"restored":1`},
+		{http.MethodDelete, "/admin/provinces/72/cases/8", `"deleted":1`},
+		{http.MethodPost, "/admin/provinces/72/cases/8/restore", `"restored":1`},
+		{http.MethodDelete, "/admin/provinces/72", `"deleted":1`},
+		{http.MethodPost, "/admin/provinces/72/restore", `"restored":1`},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-Admin-Key", "secret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, tc.path)
+		assert.Contains(t, rr.Body.String(), tc.want, tc.path)
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestSoftDeleteHandler_DeleteNationalCase_NoMatch(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSoftDeleteService)
+	svc.On("DeleteNationalCase", int64(8), false).Return(nil, service.ErrSoftDeleteNoMatch)
+	router := SetupRoutes(Services{SoftDeleteService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/national/8", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSoftDeleteHandler_DryRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSoftDeleteService)
+	preview := &models.ChangeSummary{DryRun: true, Deleted: 1}
+	svc.On("DeleteProvinceCase", "72", int64(8), true).Return(preview, nil)
+	svc.On("DeleteProvince", "72", true).Return(preview, nil)
+	router := SetupRoutes(Services{SoftDeleteService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	for _, path := range []string{"/admin/provinces/72/cases/8?dry_run=true", "/admin/provinces/72?dry_run=true"} {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set("X-Admin-Key", "secret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, path)
+		assert.Contains(t, rr.Body.String(), "dry run: no changes committed", path)
+		assert.Contains(t, rr.Body.String(), `"dry_run":true`, path)
+	}
+	svc.AssertExpectations(t)
+	svc.AssertNotCalled(t, "DeleteProvince", "72", false)
+}
+
+func TestSoftDeleteHandler_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSoftDeleteService)
+	router := SetupRoutes(Services{SoftDeleteService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/provinces/72", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	svc.AssertNotCalled(t, "DeleteProvince", mock.Anything, mock.Anything)
+}
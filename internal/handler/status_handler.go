@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// StatusHandler serves the traffic-light status of every province.
+type StatusHandler struct {
+	service service.StatusServiceInterface
+}
+
+// NewStatusHandler creates a new StatusHandler.
+func NewStatusHandler(service service.StatusServiceInterface) *StatusHandler {
+	return &StatusHandler{service: service}
+}
+
+// GetStatuses godoc
+//
+//	@Summary		Province status overview
+//	@Description	Classifies every province as controlled, caution or critical from its rolling 7-day Rt and weekly incidence of new positive cases. The response includes the thresholds and the methodology.
+//	@Tags			status
+//	@Produce		json
+//	@Success		200	{object}	Response{data=models.StatusOverview}
+//	@Failure		500	{object}	Response
+//	@Router			/status [get]
+func (h *StatusHandler) GetStatuses(w http.ResponseWriter, r *http.Request) {
+	overview, err := h.service.GetStatuses(r.Context())
+	if err != nil {
+		log.Printf("Error building status overview: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to build status overview")
+		return
+	}
+	writeSuccessResponse(w, overview)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatusService struct {
+	mock.Mock
+}
+
+func (m *MockStatusService) GetStatuses(ctx context.Context) (*models.StatusOverview, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatusOverview), args.Error(1)
+}
+
+func TestStatusHandler_GetStatuses(t *testing.T) {
+	mockService := new(MockStatusService)
+	mockService.On("GetStatuses").Return(&models.StatusOverview{
+		Methodology: models.StatusMethodology,
+		Provinces:   []models.ProvinceStatus{{Province: models.Province{ID: "72"}, Status: models.StatusCaution}},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	NewStatusHandler(mockService).GetStatuses(rr, httptest.NewRequest("GET", "/api/v1/status", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data models.StatusOverview `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Data.Methodology)
+	assert.Equal(t, "caution", response.Data.Provinces[0].Status)
+}
+
+func TestStatusHandler_GetStatuses_Error(t *testing.T) {
+	mockService := new(MockStatusService)
+	mockService.On("GetStatuses").Return(nil, errors.New("db down"))
+
+	rr := httptest.NewRecorder()
+	NewStatusHandler(mockService).GetStatuses(rr, httptest.NewRequest("GET", "/api/v1/status", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "db down")
+}
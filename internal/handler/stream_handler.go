@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// streamHeartbeat is the interval of the comment lines that keep idle streams
+// from being closed by proxies
+const streamHeartbeat = 30 * time.Second
+
+// streamRetry is the reconnection delay sent to EventSource clients
+const streamRetry = 5 * time.Second
+
+// StreamHandler serves the Server-Sent Events streams
+type StreamHandler struct {
+	stream    service.CaseStreamInterface
+	heartbeat time.Duration
+}
+
+// NewStreamHandler creates a new StreamHandler.
+func NewStreamHandler(stream service.CaseStreamInterface) *StreamHandler {
+	return &StreamHandler{stream: stream, heartbeat: streamHeartbeat}
+}
+
+// StreamCases godoc
+//
+//	@Summary		Stream new case data
+//	@Description	Holds a Server-Sent Events connection that pushes an event whenever new daily data arrives: sync.completed right after an ingestion run, and national_case.created and province_case.created with the new rows once they are picked up. Each event's data is the same JSON envelope webhooks receive. Events are not replayed, so a reconnecting client should refetch the latest endpoints.
+//	@Tags			stream
+//	@Produce		text/event-stream
+//	@Param			province_id	query		string	false	"Comma-separated two-digit province IDs; only changes of these provinces are sent"
+//	@Success		200			{string}	string	"Event stream"
+//	@Failure		400			{object}	Response
+//	@Router			/stream/cases [get]
+func (h *StreamHandler) StreamCases(w http.ResponseWriter, r *http.Request) {
+	var filter models.WebhookFilter
+	if ids := r.URL.Query().Get("province_id"); ids != "" {
+		filter.ProvinceIDs = strings.Split(ids, ",")
+	}
+	if err := filter.Validate(); err != nil {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidFilter,
+			Field:   "province_id",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	events, cancel := h.stream.Subscribe(filter)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Keep reverse proxies such as nginx from buffering the events
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		log.Printf("Error starting case stream: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Error encoding case stream event %s: %v", event.ID, err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readStreamBlock reads lines up to the blank line ending an event
+func readStreamBlock(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestStreamHandler_StreamCases(t *testing.T) {
+	stream := service.NewCaseStream()
+	handler := NewStreamHandler(stream)
+	handler.heartbeat = 50 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(handler.StreamCases))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/stream/cases?province_id=72", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	body := bufio.NewReader(resp.Body)
+	assert.Equal(t, []string{"retry: 5000"}, readStreamBlock(t, body))
+
+	require.NoError(t, stream.Publish(context.Background(), models.WebhookEventProvinceCaseCreated, models.ProvinceCaseCreatedData{
+		Cases: []models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{ID: 7, Day: 500, ProvinceID: "73"}},
+			{ProvinceCase: models.ProvinceCase{ID: 8, Day: 500, ProvinceID: "72"}},
+		},
+	}))
+
+	var event []string
+	for event == nil || event[0] == ": ping" {
+		event = readStreamBlock(t, body)
+	}
+	require.Len(t, event, 3)
+	assert.Equal(t, "id: 1", event[0])
+	assert.Equal(t, "event: province_case.created", event[1])
+	assert.Contains(t, event[2], `"event":"province_case.created"`)
+	assert.Contains(t, event[2], `"province_id":"72"`)
+	assert.NotContains(t, event[2], `"province_id":"73"`)
+
+	assert.Equal(t, []string{": ping"}, readStreamBlock(t, body))
+}
+
+func TestStreamHandler_StreamCases_InvalidProvince(t *testing.T) {
+	handler := NewStreamHandler(service.NewCaseStream())
+
+	w := httptest.NewRecorder()
+	handler.StreamCases(w, httptest.NewRequest(http.MethodGet, "/api/v1/stream/cases?province_id=7", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeInvalidFilter)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// maxSubscriptionBodyBytes bounds the subscribe request body
+const maxSubscriptionBodyBytes = 4 << 10
+
+// SubscriptionHandler handles the public email subscription endpoints.
+type SubscriptionHandler struct {
+	service service.SubscriptionServiceInterface
+}
+
+// NewSubscriptionHandler creates a new SubscriptionHandler.
+func NewSubscriptionHandler(service service.SubscriptionServiceInterface) *SubscriptionHandler {
+	return &SubscriptionHandler{service: service}
+}
+
+type subscribeRequest struct {
+	Email string `json:"email"`
+}
+
+// Subscribe godoc
+//
+//	@Summary		Subscribe to daily Sulawesi Tengah updates
+//	@Description	Emails a confirmation link to the address. Updates are only sent once the link is followed. The response is the same whether or not the address was already subscribed. Rate limited per client.
+//	@Tags			subscriptions
+//	@Accept			json
+//	@Produce		json
+//	@Param			subscription	body		subscribeRequest	true	"Email address"
+//	@Success		202				{object}	Response
+//	@Failure		400				{object}	Response
+//	@Failure		429				{object}	Response
+//	@Router			/subscriptions [post]
+func (h *SubscriptionHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req subscribeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubscriptionBodyBytes)).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.service.Subscribe(r.Context(), req.Email); err != nil {
+		if errors.Is(err, service.ErrInvalidEmail) {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid email address")
+			return
+		}
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to process subscription")
+		return
+	}
+	writeJSONResponse(w, http.StatusAccepted, Response{
+		Status:  "success",
+		Message: "Check your inbox for a confirmation link",
+	})
+}
+
+// ConfirmSubscription godoc
+//
+//	@Summary		Confirm an email subscription
+//	@Description	Target of the link in the confirmation email.
+//	@Tags			subscriptions
+//	@Produce		json
+//	@Param			token	query		string	true	"Confirmation token"
+//	@Success		200		{object}	Response
+//	@Failure		404		{object}	Response
+//	@Router			/subscriptions/confirm [get]
+func (h *SubscriptionHandler) ConfirmSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.service.Confirm(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to confirm subscription")
+		return
+	}
+	if sub == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Confirmation link is invalid or expired")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, Response{Status: "success", Message: "Subscription confirmed"})
+}
+
+// Unsubscribe godoc
+//
+//	@Summary		Unsubscribe from email updates
+//	@Description	Target of the unsubscribe link in every update email.
+//	@Tags			subscriptions
+//	@Produce		json
+//	@Param			token	query		string	true	"Unsubscribe token"
+//	@Success		200		{object}	Response
+//	@Failure		404		{object}	Response
+//	@Router			/subscriptions/unsubscribe [get]
+func (h *SubscriptionHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.service.Unsubscribe(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to unsubscribe")
+		return
+	}
+	if sub == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Unsubscribe link is invalid")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, Response{Status: "success", Message: "You have been unsubscribed"})
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSubscriptionService struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionService) Subscribe(ctx context.Context, email string) error {
+	return m.Called(email).Error(0)
+}
+
+func (m *MockSubscriptionService) Confirm(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailSubscription), args.Error(1)
+}
+
+func (m *MockSubscriptionService) Unsubscribe(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailSubscription), args.Error(1)
+}
+
+func serveSubscriptions(router *mux.Router, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = "203.0.113.7:4000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubscriptionHandler_Subscribe(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	svc.On("Subscribe", "warga@example.com").Return(nil)
+	router := SetupRoutes(Services{SubscriptionService: svc}, nil, false)
+
+	w := serveSubscriptions(router, http.MethodPost, "/api/v1/subscriptions", `{"email":"warga@example.com"}`)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), "confirmation link")
+	svc.AssertExpectations(t)
+}
+
+func TestSubscriptionHandler_Subscribe_InvalidEmail(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	svc.On("Subscribe", "nope").Return(service.ErrInvalidEmail)
+	router := SetupRoutes(Services{SubscriptionService: svc}, nil, false)
+
+	assert.Equal(t, http.StatusBadRequest, serveSubscriptions(router, http.MethodPost, "/api/v1/subscriptions", `{"email":"nope"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serveSubscriptions(router, http.MethodPost, "/api/v1/subscriptions", `email=nope`).Code)
+}
+
+func TestSubscriptionHandler_Subscribe_MailFailureHidesDetails(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	svc.On("Subscribe", "warga@example.com").Return(errors.New("dial tcp smtp.internal:587: connection refused"))
+	router := SetupRoutes(Services{SubscriptionService: svc}, nil, false)
+
+	w := serveSubscriptions(router, http.MethodPost, "/api/v1/subscriptions", `{"email":"warga@example.com"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "smtp.internal")
+}
+
+func TestSubscriptionHandler_Subscribe_RateLimited(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	svc.On("Subscribe", mock.Anything).Return(nil)
+	router := SetupRoutes(Services{SubscriptionService: svc}, nil, false)
+
+	for i := 0; i < subscribeRateLimit.RequestsPerMinute; i++ {
+		w := serveSubscriptions(router, http.MethodPost, "/api/v1/subscriptions", `{"email":"warga@example.com"}`)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	}
+	w := serveSubscriptions(router, http.MethodPost, "/api/v1/subscriptions", `{"email":"warga@example.com"}`)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	svc.AssertNumberOfCalls(t, "Subscribe", subscribeRateLimit.RequestsPerMinute)
+}
+
+func TestSubscriptionHandler_Confirm(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	svc.On("Confirm", "tok").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionConfirmed}, nil)
+	svc.On("Confirm", "bad").Return(nil, nil)
+	router := SetupRoutes(Services{SubscriptionService: svc}, nil, false)
+
+	assert.Equal(t, http.StatusOK, serveSubscriptions(router, http.MethodGet, "/api/v1/subscriptions/confirm?token=tok", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveSubscriptions(router, http.MethodGet, "/api/v1/subscriptions/confirm?token=bad", "").Code)
+}
+
+func TestSubscriptionHandler_Unsubscribe(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	svc.On("Unsubscribe", "tok").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionUnsubscribed}, nil)
+	svc.On("Unsubscribe", "").Return(nil, nil)
+	router := SetupRoutes(Services{SubscriptionService: svc}, nil, false)
+
+	assert.Equal(t, http.StatusOK, serveSubscriptions(router, http.MethodGet, "/api/v1/subscriptions/unsubscribe?token=tok", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveSubscriptions(router, http.MethodGet, "/api/v1/subscriptions/unsubscribe", "").Code)
+}
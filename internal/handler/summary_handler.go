@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// SummaryHandler serves the aggregated summary for dashboards.
+type SummaryHandler struct {
+	service service.SummaryServiceInterface
+}
+
+// NewSummaryHandler creates a new SummaryHandler.
+func NewSummaryHandler(service service.SummaryServiceInterface) *SummaryHandler {
+	return &SummaryHandler{service: service}
+}
+
+// GetSummary godoc
+//
+//	@Summary		Aggregated summary
+//	@Description	Returns the latest national totals, the latest totals of every province, 7-day moving averages of new cases and the date of the last update in one response
+//	@Tags			summary
+//	@Produce		json
+//	@Success		200	{object}	Response{data=models.Summary}
+//	@Failure		500	{object}	Response
+//	@Router			/summary [get]
+func (h *SummaryHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.service.GetSummary(r.Context())
+	if err != nil {
+		log.Printf("Error building summary: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to build summary")
+		return
+	}
+	writeSuccessResponse(w, summary)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSummaryService struct {
+	mock.Mock
+}
+
+func (m *MockSummaryService) GetSummary(ctx context.Context) (*models.Summary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Summary), args.Error(1)
+}
+
+func TestSummaryHandler_GetSummary(t *testing.T) {
+	mockService := new(MockSummaryService)
+	mockService.On("GetSummary").Return(&models.Summary{
+		National:  &models.NationalSummary{Day: 10, Totals: models.CaseTotals{Positive: 1000}},
+		Provinces: []models.ProvinceSummary{{Province: models.Province{ID: "72"}}},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	NewSummaryHandler(mockService).GetSummary(rr, httptest.NewRequest("GET", "/api/v1/summary", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data models.Summary `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, int64(1000), response.Data.National.Totals.Positive)
+	assert.Len(t, response.Data.Provinces, 1)
+}
+
+func TestSummaryHandler_GetSummary_Error(t *testing.T) {
+	mockService := new(MockSummaryService)
+	mockService.On("GetSummary").Return(nil, errors.New("db down"))
+
+	rr := httptest.NewRecorder()
+	NewSummaryHandler(mockService).GetSummary(rr, httptest.NewRequest("GET", "/api/v1/summary", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "db down")
+}
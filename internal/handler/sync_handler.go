@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// SyncHandler handles ingestion run history and rollback admin endpoints.
+type SyncHandler struct {
+	service service.SyncServiceInterface
+}
+
+// NewSyncHandler creates a new SyncHandler.
+func NewSyncHandler(service service.SyncServiceInterface) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+// ListSyncRuns godoc
+//
+//	@Summary		List ingestion runs
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			limit		query		integer	false	"Maximum runs to return (default: 20)"
+//	@Success		200			{object}	Response{data=[]models.SyncRun}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/sync-runs [get]
+func (h *SyncHandler) ListSyncRuns(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	limit := utils.ParseIntQueryParam(r, "limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	runs, err := h.service.ListRuns(r.Context(), limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, runs)
+}
+
+// GetSyncRun godoc
+//
+//	@Summary		Get an ingestion run with its row revisions
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Sync run ID"
+//	@Success		200			{object}	Response{data=service.SyncRunDetail}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/sync-runs/{id} [get]
+func (h *SyncHandler) GetSyncRun(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid sync run ID")
+		return
+	}
+	run, err := h.service.GetRun(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if run == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Sync run not found")
+		return
+	}
+	writeSuccessResponse(w, run)
+}
+
+// RollbackSyncRun godoc
+//
+//	@Summary		Roll back an ingestion run
+//	@Description	Atomically reverts every row the run inserted or updated. Use "latest" as the ID to roll back the most recent run. Supports dry_run=true.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		string	true	"Sync run ID or 'latest'"
+//	@Param			dry_run		query		boolean	false	"Report the rows that would change without committing"
+//	@Success		200			{object}	Response{data=models.ChangeSummary}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Failure		409			{object}	Response
+//	@Router			/admin/sync-runs/{id}/rollback [post]
+func (h *SyncHandler) RollbackSyncRun(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	dryRun := isDryRun(r)
+
+	var summary *models.ChangeSummary
+	var err error
+	if idParam := mux.Vars(r)["id"]; idParam == "latest" {
+		summary, err = h.service.RollbackLatestRun(r.Context(), dryRun)
+	} else {
+		id, parseErr := strconv.ParseInt(idParam, 10, 64)
+		if parseErr != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid sync run ID")
+			return
+		}
+		summary, err = h.service.RollbackRun(r.Context(), id, dryRun)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrSyncRunNotFound):
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, service.ErrSyncRunNotRollbackable), errors.Is(err, service.ErrSyncRunConflict):
+		writeErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if dryRun {
+		writeDryRunResponse(w, *summary)
+		return
+	}
+	writeSuccessResponse(w, summary)
+}
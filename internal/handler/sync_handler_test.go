@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSyncService struct{ mock.Mock }
+
+func (m *MockSyncService) ListRuns(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]models.SyncRun), args.Error(1)
+}
+
+func (m *MockSyncService) GetRun(ctx context.Context, id int64) (*service.SyncRunDetail, error) {
+	args := m.Called(id)
+	if r := args.Get(0); r != nil {
+		return r.(*service.SyncRunDetail), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSyncService) RollbackRun(ctx context.Context, id int64, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(id, dryRun)
+	if r := args.Get(0); r != nil {
+		return r.(*models.ChangeSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSyncService) RollbackLatestRun(ctx context.Context, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(dryRun)
+	if r := args.Get(0); r != nil {
+		return r.(*models.ChangeSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func serveSyncRollback(svc *MockSyncService, path string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/sync-runs/{id}/rollback", NewSyncHandler(svc).RollbackSyncRun)
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSyncHandler_RollbackSyncRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSyncService)
+	svc.On("RollbackRun", int64(7), false).Return(&models.ChangeSummary{Deleted: 3}, nil)
+
+	w := serveSyncRollback(svc, "/admin/sync-runs/7/rollback")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"deleted":3`)
+	svc.AssertExpectations(t)
+}
+
+func TestSyncHandler_RollbackSyncRun_LatestDryRun(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSyncService)
+	svc.On("RollbackLatestRun", true).Return(&models.ChangeSummary{DryRun: true, Updated: 1}, nil)
+
+	w := serveSyncRollback(svc, "/admin/sync-runs/latest/rollback?dry_run=true")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "dry run")
+	svc.AssertExpectations(t)
+}
+
+func TestSyncHandler_RollbackSyncRun_Conflict(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSyncService)
+	svc.On("RollbackRun", int64(7), false).Return(nil, fmt.Errorf("wrapped: %w", service.ErrSyncRunConflict))
+
+	w := serveSyncRollback(svc, "/admin/sync-runs/7/rollback")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSyncHandler_RollbackSyncRun_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSyncService)
+	svc.On("RollbackRun", int64(7), false).Return(nil, fmt.Errorf("wrapped: %w", service.ErrSyncRunNotFound))
+
+	w := serveSyncRollback(svc, "/admin/sync-runs/7/rollback")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSyncHandler_RollbackSyncRun_InvalidID(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockSyncService)
+
+	w := serveSyncRollback(svc, "/admin/sync-runs/abc/rollback")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
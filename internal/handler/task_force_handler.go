@@ -30,7 +30,7 @@ func (h *TaskForceHandler) GetTaskForces(w http.ResponseWriter, r *http.Request)
 	p := parsePaginationParams(r)
 
 	if p.LoadAll {
-		taskForces, err := h.service.GetTaskForces()
+		taskForces, err := h.service.GetTaskForces(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -39,7 +39,7 @@ func (h *TaskForceHandler) GetTaskForces(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	taskForces, total, err := h.service.GetTaskForcesPaginated(p.PerPage, p.Offset)
+	taskForces, total, err := h.service.GetTaskForcesPaginated(r.Context(), p.PerPage, p.Offset)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
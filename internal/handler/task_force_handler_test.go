@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -13,12 +14,12 @@ import (
 
 type MockTaskForceService struct{ mock.Mock }
 
-func (m *MockTaskForceService) GetTaskForcesPaginated(limit, offset int) ([]models.TaskForceByRegency, int, error) {
+func (m *MockTaskForceService) GetTaskForcesPaginated(ctx context.Context, limit, offset int) ([]models.TaskForceByRegency, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.TaskForceByRegency), args.Int(1), args.Error(2)
 }
 
-func (m *MockTaskForceService) GetTaskForces() ([]models.TaskForceByRegency, error) {
+func (m *MockTaskForceService) GetTaskForces(ctx context.Context) ([]models.TaskForceByRegency, error) {
 	args := m.Called()
 	return args.Get(0).([]models.TaskForceByRegency), args.Error(1)
 }
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+)
+
+// GetTenant godoc
+//
+//	@Summary		Current tenant
+//	@Description	Returns the tenant serving the request, selected by the X-Tenant header or subdomain. Province-focused endpoints such as regencies, hospitals and the embed card use its province.
+//	@Tags			tenants
+//	@Produce		json
+//	@Param			X-Tenant	header		string	false	"Tenant slug, e.g. sulteng"
+//	@Success		200			{object}	Response{data=tenant.Tenant}
+//	@Failure		404			{object}	Response
+//	@Router			/tenant [get]
+func GetTenant(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponse(w, tenant.FromContext(r.Context()))
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTenant(t *testing.T) {
+	gorontalo := tenant.Tenant{Slug: "gorontalo", ProvinceID: "75", Name: "Gorontalo"}
+	req := httptest.NewRequest("GET", "/api/v1/tenant", nil)
+	req = req.WithContext(tenant.NewContext(req.Context(), gorontalo))
+
+	rr := httptest.NewRecorder()
+	GetTenant(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body struct {
+		Data tenant.Tenant `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, gorontalo, body.Data)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+)
+
+// TestingHandler serves the national test counts.
+type TestingHandler struct {
+	service service.TestingServiceInterface
+}
+
+// NewTestingHandler creates a new TestingHandler.
+func NewTestingHandler(service service.TestingServiceInterface) *TestingHandler {
+	return &TestingHandler{service: service}
+}
+
+// GetNationalTests godoc
+//
+//	@Summary		National test counts
+//	@Description	Returns the daily and cumulative PCR and antigen tests with the positivity rate, the share of tests that came back positive, optionally within a date range. Positivity rates are null on days without tests or case data.
+//	@Tags			national
+//	@Produce		json
+//	@Param			start_date	query		string	false	"Start date (YYYY-MM-DD)"
+//	@Param			end_date	query		string	false	"End date (YYYY-MM-DD)"
+//	@Success		200			{object}	Response{data=[]models.TestingDay}
+//	@Failure		400			{object}	Response
+//	@Failure		500			{object}	Response
+//	@Router			/national/tests [get]
+func (h *TestingHandler) GetNationalTests(w http.ResponseWriter, r *http.Request) {
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+	if (startDate == "") != (endDate == "") {
+		writeErrorResponse(w, http.StatusBadRequest, "start_date and end_date must be given together")
+		return
+	}
+	dates, ok := parseDateRange(w, startDate, endDate)
+	if !ok {
+		return
+	}
+
+	days, err := h.service.GetNationalTests(r.Context(), dates)
+	if err != nil {
+		log.Printf("Error loading national tests: %v", err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to load test data")
+		return
+	}
+	writeSuccessResponse(w, days)
+}
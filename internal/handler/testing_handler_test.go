@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTestingService struct {
+	mock.Mock
+}
+
+func (m *MockTestingService) GetNationalTests(ctx context.Context, dates service.DateRange) ([]models.TestingDay, error) {
+	args := m.Called(dates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TestingDay), args.Error(1)
+}
+
+func TestTestingHandler_GetNationalTests(t *testing.T) {
+	mockService := new(MockTestingService)
+	rate := 10.0
+	mockService.On("GetNationalTests", testDateRange("2021-07-01", "2021-07-31")).Return([]models.TestingDay{{
+		Date: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		TestingStatistics: models.TestingStatistics{
+			Daily:          models.TestCounts{PCR: 800, Antigen: 200, Total: 1000},
+			PositivityRate: &rate,
+		},
+	}}, nil)
+	router := SetupRoutes(Services{TestingService: mockService}, nil, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/national/tests?start_date=2021-07-01&end_date=2021-07-31", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"daily":{"pcr":800,"antigen":200,"total":1000}`)
+	assert.Contains(t, rr.Body.String(), `"positivity_rate":10`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTestingHandler_GetNationalTests_InvalidDates(t *testing.T) {
+	h := NewTestingHandler(new(MockTestingService))
+
+	for _, query := range []string{"?start_date=2021-07-01", "?start_date=2021-07-01&end_date=31-07-2021"} {
+		rr := httptest.NewRecorder()
+		h.GetNationalTests(rr, httptest.NewRequest("GET", "/api/v1/national/tests"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestTestingHandler_GetNationalTests_Error(t *testing.T) {
+	mockService := new(MockTestingService)
+	mockService.On("GetNationalTests", service.DateRange{}).Return(nil, errors.New("db down"))
+
+	rr := httptest.NewRecorder()
+	NewTestingHandler(mockService).GetNationalTests(rr, httptest.NewRequest("GET", "/api/v1/national/tests", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/dto"
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
 )
 
 type VaccinationHandler struct {
@@ -30,7 +36,7 @@ func (h *VaccinationHandler) GetNationalVaccinations(w http.ResponseWriter, r *h
 	p := parsePaginationParams(r)
 
 	if p.LoadAll {
-		data, err := h.service.GetNationalVaccinations()
+		data, err := h.service.GetNationalVaccinations(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -39,7 +45,7 @@ func (h *VaccinationHandler) GetNationalVaccinations(w http.ResponseWriter, r *h
 		return
 	}
 
-	data, total, err := h.service.GetNationalVaccinationsPaginated(p.PerPage, p.Offset)
+	data, total, err := h.service.GetNationalVaccinationsPaginated(r.Context(), p.PerPage, p.Offset)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -61,7 +67,7 @@ func (h *VaccinationHandler) GetProvinceVaccinations(w http.ResponseWriter, r *h
 	p := parsePaginationParams(r)
 
 	if p.LoadAll {
-		data, err := h.service.GetProvinceVaccinations()
+		data, err := h.service.GetProvinceVaccinations(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -70,7 +76,7 @@ func (h *VaccinationHandler) GetProvinceVaccinations(w http.ResponseWriter, r *h
 		return
 	}
 
-	data, total, err := h.service.GetProvinceVaccinationsPaginated(p.PerPage, p.Offset)
+	data, total, err := h.service.GetProvinceVaccinationsPaginated(r.Context(), p.PerPage, p.Offset)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -92,7 +98,7 @@ func (h *VaccinationHandler) GetVaccineLocations(w http.ResponseWriter, r *http.
 	p := parsePaginationParams(r)
 
 	if p.LoadAll {
-		data, err := h.service.GetVaccineLocations()
+		data, err := h.service.GetVaccineLocations(r.Context())
 		if err != nil {
 			writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -101,7 +107,7 @@ func (h *VaccinationHandler) GetVaccineLocations(w http.ResponseWriter, r *http.
 		return
 	}
 
-	data, total, err := h.service.GetVaccineLocationsPaginated(p.PerPage, p.Offset)
+	data, total, err := h.service.GetVaccineLocationsPaginated(r.Context(), p.PerPage, p.Offset)
 	if err != nil {
 		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -109,6 +115,118 @@ func (h *VaccinationHandler) GetVaccineLocations(w http.ResponseWriter, r *http.
 	writePaginatedResponse(w, data, buildPaginationMeta(p, total))
 }
 
+// ListNationalVaccinations godoc
+//
+// @Summary Get national vaccination data
+// @Description Retrieve national first, second and booster dose data with optional date range filtering, sorting, and pagination, like the case endpoints
+// @Tags vaccination
+// @Produce json
+// @Param limit query integer false "Records per page (default: 50, max: 1000)"
+// @Param offset query integer false "Records to skip (default: 0)"
+// @Param page query integer false "Page number (1-based, alternative to offset)"
+// @Param all query boolean false "Return all data without pagination"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param sort query string false "Sort by field:order, fields: date, day, first_dose, second_dose, booster_dose and their cumulative_ variants. Default: date:asc"
+// @Success 200 {object} Response{data=models.PaginatedResponse{data=[]dto.VaccinationResponse}} "Paginated response"
+// @Success 200 {object} Response{data=[]dto.VaccinationResponse} "All data response when all=true"
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /national/vaccinations [get]
+func (h *VaccinationHandler) ListNationalVaccinations(w http.ResponseWriter, r *http.Request) {
+	q, all, err := parseVaccinationQuery(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, total, err := h.service.QueryNationalVaccinations(r.Context(), q)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeVaccinationList(w, transformNationalSlice(data), all, q, total)
+}
+
+// ListProvinceVaccinations godoc
+//
+// @Summary Get province vaccination data
+// @Description Retrieve first, second and booster dose data of a province with optional date range filtering, sorting, and pagination, like the case endpoints
+// @Tags vaccination
+// @Produce json
+// @Param provinceId path string true "Province ID (e.g., '72' for Sulawesi Tengah)"
+// @Param limit query integer false "Records per page (default: 50, max: 1000)"
+// @Param offset query integer false "Records to skip (default: 0)"
+// @Param page query integer false "Page number (1-based, alternative to offset)"
+// @Param all query boolean false "Return all data without pagination"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param sort query string false "Sort by field:order, fields: date, day, first_dose, second_dose, booster_dose and their cumulative_ variants. Default: date:asc"
+// @Success 200 {object} Response{data=models.PaginatedResponse{data=[]dto.ProvinceVaccinationResponse}} "Paginated response"
+// @Success 200 {object} Response{data=[]dto.ProvinceVaccinationResponse} "All data response when all=true"
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /provinces/{provinceId}/vaccinations [get]
+func (h *VaccinationHandler) ListProvinceVaccinations(w http.ResponseWriter, r *http.Request) {
+	provinceID, err := strconv.Atoi(mux.Vars(r)["provinceId"])
+	if err != nil || provinceID < 10 || provinceID > 99 {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid province ID")
+		return
+	}
+	q, all, err := parseVaccinationQuery(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, total, err := h.service.QueryProvinceVaccinations(r.Context(), provinceID, q)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeVaccinationList(w, transformProvinceSlice(data), all, q, total)
+}
+
+// parseVaccinationQuery reads the pagination, date range and sort parameters
+// the case endpoints use
+func parseVaccinationQuery(r *http.Request) (repository.VaccinationQuery, bool, error) {
+	var q repository.VaccinationQuery
+	for _, d := range []struct {
+		param string
+		dest  *time.Time
+	}{{"start_date", &q.StartDate}, {"end_date", &q.EndDate}} {
+		value := r.URL.Query().Get(d.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return q, false, fmt.Errorf("invalid %s, use YYYY-MM-DD", d.param)
+		}
+		*d.dest = parsed
+	}
+	q.Sort = utils.ParseSortParamFor(r, "date", repository.IsValidVaccinationSortField)
+
+	if utils.ParseBoolQueryParam(r, "all") {
+		return q, true, nil
+	}
+	limit := utils.ParseIntQueryParam(r, "limit", 50)
+	offset := utils.ParseIntQueryParam(r, "offset", 0)
+	if page := utils.ParseIntQueryParam(r, "page", 0); page > 0 {
+		offset = (page - 1) * limit
+	}
+	q.Limit, q.Offset = utils.ValidatePaginationParams(limit, offset)
+	return q, false, nil
+}
+
+func writeVaccinationList[T any](w http.ResponseWriter, rows []T, all bool, q repository.VaccinationQuery, total int) {
+	if all {
+		writeSuccessResponse(w, rows)
+		return
+	}
+	writeSuccessResponse(w, models.PaginatedResponse{Data: rows, Pagination: models.CalculatePaginationMeta(q.Limit, q.Offset, total)})
+}
+
 func transformNationalSlice(data []models.NationalVaccine) []dto.VaccinationResponse {
 	result := make([]dto.VaccinationResponse, len(data))
 	for i, v := range data {
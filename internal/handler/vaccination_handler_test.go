@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -8,40 +9,57 @@ import (
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 type MockVaccinationService struct{ mock.Mock }
 
-func (m *MockVaccinationService) GetNationalVaccinationsPaginated(limit, offset int) ([]models.NationalVaccine, int, error) {
+func (m *MockVaccinationService) GetNationalVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.NationalVaccine, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.NationalVaccine), args.Int(1), args.Error(2)
 }
 
-func (m *MockVaccinationService) GetProvinceVaccinationsPaginated(limit, offset int) ([]models.ProvinceVaccine, int, error) {
+func (m *MockVaccinationService) GetProvinceVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.ProvinceVaccine, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.ProvinceVaccine), args.Int(1), args.Error(2)
 }
 
-func (m *MockVaccinationService) GetVaccineLocationsPaginated(limit, offset int) ([]models.VaccineLocation, int, error) {
+func (m *MockVaccinationService) GetVaccineLocationsPaginated(ctx context.Context, limit, offset int) ([]models.VaccineLocation, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.VaccineLocation), args.Int(1), args.Error(2)
 }
 
-func (m *MockVaccinationService) GetNationalVaccinations() ([]models.NationalVaccine, error) {
+func (m *MockVaccinationService) GetNationalVaccinations(ctx context.Context) ([]models.NationalVaccine, error) {
 	args := m.Called()
 	return args.Get(0).([]models.NationalVaccine), args.Error(1)
 }
-func (m *MockVaccinationService) GetProvinceVaccinations() ([]models.ProvinceVaccine, error) {
+func (m *MockVaccinationService) GetProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error) {
 	args := m.Called()
 	return args.Get(0).([]models.ProvinceVaccine), args.Error(1)
 }
-func (m *MockVaccinationService) GetVaccineLocations() ([]models.VaccineLocation, error) {
+func (m *MockVaccinationService) GetVaccineLocations(ctx context.Context) ([]models.VaccineLocation, error) {
 	args := m.Called()
 	return args.Get(0).([]models.VaccineLocation), args.Error(1)
 }
 
+func (m *MockVaccinationService) QueryNationalVaccinations(ctx context.Context, q repository.VaccinationQuery) ([]models.NationalVaccine, int, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.NationalVaccine), args.Int(1), args.Error(2)
+}
+func (m *MockVaccinationService) GetLatestProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ProvinceVaccine), args.Error(1)
+}
+
+func (m *MockVaccinationService) QueryProvinceVaccinations(ctx context.Context, provinceID int, q repository.VaccinationQuery) ([]models.ProvinceVaccine, int, error) {
+	args := m.Called(provinceID, q)
+	return args.Get(0).([]models.ProvinceVaccine), args.Int(1), args.Error(2)
+}
+
 func sampleNationalVaccine() models.NationalVaccine {
 	return models.NationalVaccine{ID: 1, Day: 1, Date: time.Now()}
 }
@@ -201,3 +219,72 @@ func TestGetVaccineLocations_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	svc.AssertExpectations(t)
 }
+
+func TestListNationalVaccinations_Paginated(t *testing.T) {
+	svc := new(MockVaccinationService)
+	q := repository.VaccinationQuery{
+		StartDate: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC),
+		Sort:      utils.SortParams{Field: "booster_dose", Order: "desc"},
+		Limit:     10,
+		Offset:    10,
+	}
+	svc.On("QueryNationalVaccinations", q).Return([]models.NationalVaccine{sampleNationalVaccine()}, 25, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/national/vaccinations?start_date=2021-06-01&end_date=2021-06-30&sort=booster_dose:desc&limit=10&page=2", nil)
+	w := httptest.NewRecorder()
+	NewVaccinationHandler(svc).ListNationalVaccinations(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":25`)
+	assert.Contains(t, w.Body.String(), `"booster"`)
+	svc.AssertExpectations(t)
+}
+
+func TestListNationalVaccinations_All(t *testing.T) {
+	svc := new(MockVaccinationService)
+	// Case fields cannot sort vaccinations and fall back to date
+	q := repository.VaccinationQuery{Sort: utils.SortParams{Field: "date", Order: "desc"}}
+	svc.On("QueryNationalVaccinations", q).Return([]models.NationalVaccine{sampleNationalVaccine()}, 1, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/national/vaccinations?all=true&sort=positive:desc", nil)
+	w := httptest.NewRecorder()
+	NewVaccinationHandler(svc).ListNationalVaccinations(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"pagination"`)
+	svc.AssertExpectations(t)
+}
+
+func TestListNationalVaccinations_InvalidDate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/national/vaccinations?start_date=June", nil)
+	w := httptest.NewRecorder()
+	NewVaccinationHandler(new(MockVaccinationService)).ListNationalVaccinations(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListProvinceVaccinations(t *testing.T) {
+	svc := new(MockVaccinationService)
+	q := repository.VaccinationQuery{Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 50}
+	svc.On("QueryProvinceVaccinations", 31, q).Return([]models.ProvinceVaccine{{NationalVaccine: sampleNationalVaccine(), ProvinceID: 31}}, 1, nil)
+	svc.On("QueryProvinceVaccinations", 72, q).Return([]models.ProvinceVaccine(nil), 0, errors.New("db error"))
+
+	tests := []struct {
+		provinceID string
+		wantStatus int
+	}{
+		{"31", http.StatusOK},
+		{"72", http.StatusInternalServerError},
+		{"jakarta", http.StatusBadRequest},
+		{"123", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/provinces/"+tt.provinceID+"/vaccinations", nil)
+		req = mux.SetURLVars(req, map[string]string{"provinceId": tt.provinceID})
+		w := httptest.NewRecorder()
+		NewVaccinationHandler(svc).ListProvinceVaccinations(w, req)
+		assert.Equal(t, tt.wantStatus, w.Code, tt.provinceID)
+	}
+	svc.AssertExpectations(t)
+}
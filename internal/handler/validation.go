@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// Machine-readable codes of 400 responses to invalid request parameters
+const (
+	ErrCodeInvalidDateFormat = "INVALID_DATE_FORMAT"
+	ErrCodeStartAfterEnd     = "START_AFTER_END"
+	ErrCodeRangeTooLarge     = "RANGE_TOO_LARGE"
+	ErrCodeInvalidRange      = "INVALID_RANGE"
+	ErrCodeInvalidInterval   = "INVALID_INTERVAL"
+	ErrCodeInvalidSmoothing  = "INVALID_SMOOTHING"
+	ErrCodeInvalidShape      = "INVALID_SHAPE"
+	ErrCodeInvalidField      = "INVALID_FIELD"
+	ErrCodeInvalidFilter     = "INVALID_FILTER"
+	ErrCodeInvalidScope      = "INVALID_SCOPE"
+)
+
+// ValidationError describes a request parameter that failed validation
+type ValidationError struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// validateDateRange checks the start_date and end_date parameters: each must
+// be YYYY-MM-DD, start_date may not be after end_date and, when maxDays is
+// positive, the range may span at most maxDays days. Either date may be
+// given alone; endpoints decide whether that is allowed.
+func validateDateRange(startDate, endDate string, maxDays int) *ValidationError {
+	dates := make(map[string]time.Time, 2)
+	for _, d := range []struct{ field, value string }{{"start_date", startDate}, {"end_date", endDate}} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return &ValidationError{
+				Code:    ErrCodeInvalidDateFormat,
+				Field:   d.field,
+				Message: fmt.Sprintf("Invalid %s %q. Use YYYY-MM-DD", d.field, d.value),
+			}
+		}
+		dates[d.field] = parsed
+	}
+
+	start, hasStart := dates["start_date"]
+	end, hasEnd := dates["end_date"]
+	if !hasStart || !hasEnd {
+		return nil
+	}
+	if start.After(end) {
+		return &ValidationError{
+			Code:    ErrCodeStartAfterEnd,
+			Field:   "start_date",
+			Message: "start_date must not be after end_date",
+		}
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; maxDays > 0 && days > maxDays {
+		return &ValidationError{
+			Code:    ErrCodeRangeTooLarge,
+			Field:   "end_date",
+			Message: fmt.Sprintf("Date range spans %d days, the maximum is %d", days, maxDays),
+		}
+	}
+	return nil
+}
+
+// dateRangeValidation rejects requests with an invalid start_date/end_date
+// pair before they reach a handler, so bad dates are a 400 instead of a
+// failed query
+func dateRangeValidation(maxDays int) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if err := validateDateRange(query.Get("start_date"), query.Get("end_date"), maxDays); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseDateRange parses the start_date/end_date pair once for the service
+// layer. It writes a 400 and reports false when a date is malformed.
+func parseDateRange(w http.ResponseWriter, startDate, endDate string) (service.DateRange, bool) {
+	dates, err := service.ParseDateRange(startDate, endDate)
+	if err != nil {
+		writeValidationError(w, &ValidationError{
+			Code:    ErrCodeInvalidDateFormat,
+			Message: "Invalid date format. Use YYYY-MM-DD",
+		})
+		return service.DateRange{}, false
+	}
+	return dates, true
+}
+
+func writeValidationError(w http.ResponseWriter, err *ValidationError) {
+	writeJSONResponse(w, http.StatusBadRequest, Response{
+		Status: "error",
+		Error:  err.Message,
+		Code:   err.Code,
+		Field:  err.Field,
+	})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDateRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		maxDays   int
+		wantCode  string
+		wantField string
+	}{
+		{name: "no dates", start: "", end: ""},
+		{name: "valid range", start: "2021-01-01", end: "2021-01-31"},
+		{name: "single day", start: "2021-01-01", end: "2021-01-01", maxDays: 1},
+		{name: "start only", start: "2021-01-01", end: ""},
+		{name: "bad start", start: "01-01-2021", end: "2021-01-31", wantCode: ErrCodeInvalidDateFormat, wantField: "start_date"},
+		{name: "bad end", start: "2021-01-01", end: "2021-02-30", wantCode: ErrCodeInvalidDateFormat, wantField: "end_date"},
+		{name: "start after end", start: "2021-02-01", end: "2021-01-01", wantCode: ErrCodeStartAfterEnd, wantField: "start_date"},
+		{name: "within max", start: "2021-01-01", end: "2021-01-31", maxDays: 31},
+		{name: "over max", start: "2021-01-01", end: "2021-02-01", maxDays: 31, wantCode: ErrCodeRangeTooLarge, wantField: "end_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDateRange(tt.start, tt.end, tt.maxDays)
+			if tt.wantCode == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.wantCode, err.Code)
+			assert.Equal(t, tt.wantField, err.Field)
+		})
+	}
+}
+
+func TestSetupRoutes_RejectsInvalidDateRange(t *testing.T) {
+	router := SetupRoutes(Services{Validation: config.ValidationConfig{MaxDateRangeDays: 30}}, nil, false)
+
+	for target, code := range map[string]string{
+		"/api/v1/national?start_date=2021-13-01&end_date=2021-12-31":           ErrCodeInvalidDateFormat,
+		"/api/v1/provinces/72/cases?start_date=2021-03-01&end_date=2021-02-01": ErrCodeStartAfterEnd,
+		"/api/v1/national?start_date=2021-01-01&end_date=2021-12-31&all=true":  ErrCodeRangeTooLarge,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+
+		var response Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, code, response.Code, target)
+		assert.NotEmpty(t, response.Field)
+	}
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// WebhookHandler handles webhook registration and the delivery dashboard admin endpoints.
+type WebhookHandler struct {
+	service service.WebhookServiceInterface
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(service service.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// webhookSubscriptionRequest is the body of a registration request
+type webhookSubscriptionRequest struct {
+	URL    string               `json:"url"`
+	Events []string             `json:"events"`
+	Filter models.WebhookFilter `json:"filter"`
+}
+
+// webhookRegistration is a created subscription including its signing secret,
+// which is only ever returned here
+type webhookRegistration struct {
+	models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// CreateSubscription godoc
+//
+//	@Summary		Register a webhook
+//	@Description	Registers a callback URL notified with signed POST requests of the given events, e.g. national_case.created and province_case.created when new case rows appear. The signing secret is only returned in this response. Requires an API key with the write scope or the admin key.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-API-Key		header		string						false	"API key with the write scope"
+//	@Param			X-Admin-Key		header		string						false	"Admin key"
+//	@Param			subscription	body		webhookSubscriptionRequest	true	"Callback URL, events and optional filter"
+//	@Success		201				{object}	Response{data=webhookRegistration}
+//	@Failure		400				{object}	Response
+//	@Failure		401				{object}	map[string]string
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	if !authorizeWebhookRegistration(w, r) {
+		return
+	}
+	var req webhookSubscriptionRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid webhook subscription body: "+err.Error())
+		return
+	}
+	sub, err := h.service.CreateSubscription(r.Context(), models.WebhookSubscription{
+		URL:    req.URL,
+		Events: req.Events,
+		Filter: req.Filter,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWebhookSubscription) {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, Response{
+		Status: "success",
+		Data:   webhookRegistration{WebhookSubscription: *sub, Secret: sub.Secret},
+	})
+}
+
+// authorizeWebhookRegistration accepts an API key with the write scope and
+// otherwise falls back to the admin checks
+func authorizeWebhookRegistration(w http.ResponseWriter, r *http.Request) bool {
+	if key, ok := middleware.APIKeyFromContext(r.Context()); ok && key.HasScope(models.ScopeWrite) {
+		return true
+	}
+	return authorizeAdmin(w, r)
+}
+
+// ListSubscriptions godoc
+//
+//	@Summary		List webhook subscriptions
+//	@Description	Returns every subscription with its delivered, retrying and failed delivery counts.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Success		200			{object}	Response{data=[]models.WebhookSubscriptionStats}
+//	@Failure		401			{object}	map[string]string
+//	@Router			/admin/webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	subs, err := h.service.ListSubscriptions(r.Context())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeSuccessResponse(w, subs)
+}
+
+// ListDeliveries godoc
+//
+//	@Summary		List deliveries of a webhook subscription
+//	@Description	Returns the latest deliveries, newest first, with response status, attempts and next scheduled retry.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Subscription ID"
+//	@Param			limit		query		integer	false	"Maximum deliveries to return (default: 50)"
+//	@Success		200			{object}	Response{data=[]models.WebhookDelivery}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+	limit := utils.ParseIntQueryParam(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	deliveries, err := h.service.ListDeliveries(r.Context(), id, limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if deliveries == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Webhook subscription not found")
+		return
+	}
+	writeSuccessResponse(w, deliveries)
+}
+
+// GetDelivery godoc
+//
+//	@Summary		Get a webhook delivery with its payload
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Delivery ID"
+//	@Success		200			{object}	Response{data=models.WebhookDelivery}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/webhooks/deliveries/{id} [get]
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+	delivery, err := h.service.GetDelivery(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if delivery == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Webhook delivery not found")
+		return
+	}
+	writeSuccessResponse(w, delivery)
+}
+
+// Redeliver godoc
+//
+//	@Summary		Redeliver a webhook delivery
+//	@Description	Immediately re-sends the delivery with its original delivery ID and payload and returns the new outcome. Subscribers that already accepted the delivery ID will treat it as a replay.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Key	header		string	true	"Admin key"
+//	@Param			id			path		integer	true	"Delivery ID"
+//	@Success		200			{object}	Response{data=models.WebhookDelivery}
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	Response
+//	@Router			/admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+	delivery, err := h.service.Redeliver(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if delivery == nil {
+		writeErrorResponse(w, http.StatusNotFound, "Webhook delivery not found")
+		return
+	}
+	writeSuccessResponse(w, delivery)
+}
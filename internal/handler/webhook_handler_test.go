@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	args := m.Called(sub)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscriptionStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscriptionStats), args.Error(1)
+}
+
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(subscriptionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func serveWebhookAdmin(svc *MockWebhookService, method, path string) *httptest.ResponseRecorder {
+	router := SetupRoutes(Services{WebhookService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandler_ListSubscriptions(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("ListSubscriptions").Return([]models.WebhookSubscriptionStats{
+		{WebhookSubscription: models.WebhookSubscription{ID: 1, URL: "https://a.example/hook", Secret: "s1"}, Failed: 2},
+	}, nil)
+
+	w := serveWebhookAdmin(svc, http.MethodGet, "/admin/webhooks")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"failed":2`)
+	assert.NotContains(t, w.Body.String(), "s1")
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	status := 502
+	svc := new(MockWebhookService)
+	svc.On("ListDeliveries", int64(1), 10).Return([]models.WebhookDelivery{
+		{ID: 2, DeliveryID: "d2", Status: models.WebhookDeliveryRetrying, Attempts: 1, ResponseStatus: &status},
+	}, nil)
+
+	w := serveWebhookAdmin(svc, http.MethodGet, "/admin/webhooks/1/deliveries?limit=10")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"response_status":502`)
+}
+
+func TestWebhookHandler_ListDeliveries_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("ListDeliveries", int64(9), 50).Return(nil, nil)
+
+	w := serveWebhookAdmin(svc, http.MethodGet, "/admin/webhooks/9/deliveries")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandler_GetDelivery(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("GetDelivery", int64(3)).Return(&models.WebhookDelivery{ID: 3, Payload: []byte(`{"id":"d3"}`)}, nil)
+
+	w := serveWebhookAdmin(svc, http.MethodGet, "/admin/webhooks/deliveries/3")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{"id":"d3"}`)
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("Redeliver", int64(3)).Return(&models.WebhookDelivery{ID: 3, Status: models.WebhookDeliveryDelivered, Attempts: 2}, nil)
+
+	w := serveWebhookAdmin(svc, http.MethodPost, "/admin/webhooks/deliveries/3/redeliver")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"delivered"`)
+}
+
+func TestWebhookHandler_Redeliver_NotFound(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("Redeliver", int64(3)).Return(nil, nil)
+
+	w := serveWebhookAdmin(svc, http.MethodPost, "/admin/webhooks/deliveries/3/redeliver")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandler_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	h := NewWebhookHandler(new(MockWebhookService))
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/admin/webhooks/deliveries/3/redeliver", nil), map[string]string{"id": "3"})
+	w := httptest.NewRecorder()
+	h.Redeliver(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestWebhookHandler_CreateSubscription(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("CreateSubscription", models.WebhookSubscription{
+		URL:    "https://a.example/hook",
+		Events: []string{models.WebhookEventProvinceCaseCreated},
+		Filter: models.WebhookFilter{ProvinceIDs: []string{"72"}},
+	}).Return(&models.WebhookSubscription{
+		ID: 3, URL: "https://a.example/hook", Secret: "s3", Active: true,
+		Events: []string{models.WebhookEventProvinceCaseCreated}, Filter: models.WebhookFilter{ProvinceIDs: []string{"72"}},
+	}, nil)
+
+	router := SetupRoutes(Services{WebhookService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks",
+		strings.NewReader(`{"url":"https://a.example/hook","events":["province_case.created"],"filter":{"province_ids":["72"]}}`))
+	req.Header.Set("X-Admin-Key", "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":3`)
+	assert.Contains(t, w.Body.String(), `"secret":"s3"`)
+	svc.AssertExpectations(t)
+}
+
+func TestWebhookHandler_CreateSubscription_WriteAPIKey(t *testing.T) {
+	svc := new(MockWebhookService)
+	svc.On("CreateSubscription", mock.Anything).Return(&models.WebhookSubscription{ID: 4, Secret: "s4"}, nil)
+	keys := service.NewAPIKeyService(nil, []models.APIKey{
+		{Name: "partner", KeyHash: models.HashAPIKey("partner-key"), Scopes: []string{models.ScopeRead, models.ScopeWrite}},
+	})
+	handler := middleware.APIKeyAuth(config.AuthConfig{Enabled: true}, keys)(http.HandlerFunc(NewWebhookHandler(svc).CreateSubscription))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", strings.NewReader(`{"url":"https://a.example/hook","events":["*"]}`))
+	req.Header.Set("X-API-Key", "partner-key")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestWebhookHandler_CreateSubscription_Unauthorized(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+
+	router := SetupRoutes(Services{WebhookService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", strings.NewReader(`{"url":"https://a.example/hook","events":["*"]}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	svc.AssertNotCalled(t, "CreateSubscription", mock.Anything)
+}
+
+func TestWebhookHandler_CreateSubscription_Invalid(t *testing.T) {
+	t.Setenv("ADMIN_KEY", "secret")
+	svc := new(MockWebhookService)
+	svc.On("CreateSubscription", mock.Anything).Return(nil, service.ErrInvalidWebhookSubscription)
+
+	router := SetupRoutes(Services{WebhookService: svc, Admin: config.AdminConfig{Enabled: true}}, nil, false)
+	for _, body := range []string{`{"url":"ftp://a.example","events":["*"]}`, `{"url":"https://a.example","secret":"mine"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", strings.NewReader(body))
+		req.Header.Set("X-Admin-Key", "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	svc.AssertNumberOfCalls(t, "CreateSubscription", 1)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"golang.org/x/net/websocket"
+)
+
+// webSocketPingInterval is how often an open WebSocket is pinged; a client
+// that stops reading is dropped once a ping cannot be written in time
+const webSocketPingInterval = 30 * time.Second
+
+// webSocketWriteTimeout bounds every write to a WebSocket
+const webSocketWriteTimeout = 10 * time.Second
+
+// webSocketMaxMessageBytes caps the size of a message a client may send
+const webSocketMaxMessageBytes = 512
+
+// webSocketMessage is a JSON message on /ws. The server sends "update" with
+// the dashboard numbers and "pong" in reply to a client's "ping".
+type webSocketMessage struct {
+	Type string                  `json:"type"`
+	Data *models.DashboardUpdate `json:"data,omitempty"`
+}
+
+// WebSocketHandler serves the realtime dashboard WebSocket
+type WebSocketHandler struct {
+	hub          service.DashboardHubInterface
+	clients      *middleware.ClientResolver
+	pingInterval time.Duration
+}
+
+// NewWebSocketHandler creates a new WebSocketHandler counting connections per
+// client as clients tells them apart.
+func NewWebSocketHandler(hub service.DashboardHubInterface, clients *middleware.ClientResolver) *WebSocketHandler {
+	return &WebSocketHandler{hub: hub, clients: clients, pingInterval: webSocketPingInterval}
+}
+
+// Dashboard godoc
+//
+//	@Summary		Realtime dashboard updates
+//	@Description	Upgrades to a WebSocket that sends {"type":"update","data":{...}} with the latest national and Sulawesi Tengah numbers on connect and again whenever new daily data arrives. A client may send {"type":"ping"} and gets {"type":"pong"} back; the server also pings every 30 seconds. Connections are limited per client IP.
+//	@Tags			stream
+//	@Success		101	{object}	models.DashboardUpdate	"Switching Protocols"
+//	@Failure		426	{object}	Response
+//	@Failure		429	{object}	Response
+//	@Failure		503	{object}	Response
+//	@Router			/ws [get]
+func (h *WebSocketHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		writeErrorResponse(w, http.StatusUpgradeRequired, "Expected a WebSocket upgrade")
+		return
+	}
+
+	updates, cancel, err := h.hub.Subscribe(h.clients.ClientIP(r))
+	switch {
+	case errors.Is(err, service.ErrDashboardClientLimit):
+		writeErrorResponse(w, http.StatusTooManyRequests, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer cancel()
+
+	initial, err := h.hub.Latest(r.Context())
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// No Handshake: dashboards on any origin may connect, like CORS allows
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serve(ws, initial, updates)
+	}}
+	server.ServeHTTP(hijackableWriter{w}, r)
+}
+
+// serve sends the updates to ws until the client leaves, stops answering or
+// the subscription ends
+func (h *WebSocketHandler) serve(ws *websocket.Conn, initial models.DashboardUpdate, updates <-chan models.DashboardUpdate) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = webSocketMaxMessageBytes
+
+	// The reader only answers pings; the writer below owns every write
+	pings := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var raw string
+			if err := websocket.Message.Receive(ws, &raw); err != nil {
+				return
+			}
+			var msg webSocketMessage
+			if json.Unmarshal([]byte(raw), &msg) == nil && msg.Type == "ping" {
+				select {
+				case pings <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	send := func(msg webSocketMessage) error {
+		if err := ws.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout)); err != nil {
+			return err
+		}
+		return websocket.JSON.Send(ws, msg)
+	}
+	if err := send(webSocketMessage{Type: "update", Data: &initial}); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-done:
+			return
+		case update, ok := <-updates:
+			if !ok {
+				// Dropped for falling behind or shutting down; the client reconnects
+				return
+			}
+			err = send(webSocketMessage{Type: "update", Data: &update})
+		case <-pings:
+			err = send(webSocketMessage{Type: "pong"})
+		case <-ticker.C:
+			if err = ws.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout)); err == nil {
+				ws.PayloadType = websocket.PingFrame
+				_, err = ws.Write(nil)
+			}
+		}
+		if err != nil {
+			log.Printf("Closing dashboard WebSocket of %s: %v", ws.Request().RemoteAddr, err)
+			return
+		}
+	}
+}
+
+// hijackableWriter lets the WebSocket server take over a connection through
+// the response writer wrappers of the middleware
+type hijackableWriter struct {
+	http.ResponseWriter
+}
+
+// Hijack hijacks the underlying connection
+func (hw hijackableWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(hw.ResponseWriter).Hijack()
+}
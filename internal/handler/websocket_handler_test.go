@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/middleware"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocketHandler_Dashboard(t *testing.T) {
+	covid := new(MockCovidService)
+	covid.On("GetLatestNationalCase").Return(&models.NationalCase{ID: 1, Day: 500, Positive: 120}, nil)
+	covid.On("GetProvincesWithLatestCaseByIDs", []string{"72"}).
+		Return([]models.ProvinceWithLatestCase{{Province: models.Province{ID: "72", Name: "Sulawesi Tengah"}}}, nil)
+	hub := service.NewDashboardHub(covid, service.DashboardHubLimits{})
+	handler := NewWebSocketHandler(hub, middleware.NewClientResolver(nil))
+	// The connection is hijacked through the middleware's response writers
+	server := httptest.NewServer(middleware.Logging(http.HandlerFunc(handler.Dashboard)))
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/ws", "", server.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var msg webSocketMessage
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, "update", msg.Type)
+	require.NotNil(t, msg.Data)
+	assert.Equal(t, int64(500), msg.Data.National.Day)
+	assert.Equal(t, "Sulawesi Tengah", msg.Data.Province.Name)
+
+	require.NoError(t, websocket.JSON.Send(ws, map[string]string{"type": "ping"}))
+	msg = webSocketMessage{}
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, webSocketMessage{Type: "pong"}, msg)
+
+	require.NoError(t, hub.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 1}))
+	msg = webSocketMessage{}
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, "update", msg.Type)
+
+	// Closing the hub on shutdown closes the connection
+	hub.Close()
+	assert.Error(t, websocket.JSON.Receive(ws, &msg))
+}
+
+func TestWebSocketHandler_Dashboard_Errors(t *testing.T) {
+	hub := service.NewDashboardHub(new(MockCovidService), service.DashboardHubLimits{MaxConnectionsPerClient: 1})
+	_, cancel, err := hub.Subscribe("192.0.2.1")
+	require.NoError(t, err)
+	defer cancel()
+	handler := NewWebSocketHandler(hub, middleware.NewClientResolver(nil))
+
+	upgrade := func(req *http.Request) *http.Request {
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		return req
+	}
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"plain request", httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil), http.StatusUpgradeRequired},
+		{"client limit", upgrade(httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil)), http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.Dashboard(rr, tt.req)
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+
+	hub.Close()
+	rr := httptest.NewRecorder()
+	handler.Dashboard(rr, upgrade(httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil)))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
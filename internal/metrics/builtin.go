@@ -0,0 +1,52 @@
+package metrics
+
+// Default is the registry used by the API. Packages add statistics to it with
+// Default.MustRegister, typically from an init function.
+var Default = NewRegistry()
+
+func init() {
+	for _, m := range builtin() {
+		Default.MustRegister(m)
+	}
+}
+
+func builtin() []Metric {
+	return []Metric{
+		input("positive", "Cumulative confirmed cases"),
+		input("recovered", "Cumulative recoveries"),
+		input("deceased", "Cumulative deaths"),
+		input("active", "Cases that have neither recovered nor died"),
+		input("daily_positive", "New confirmed cases on the day"),
+		input("rt", "Effective reproduction number"),
+		New("case_fatality_rate", "Deaths as a percentage of confirmed cases",
+			[]string{"deceased", "positive"}, percentOf("deceased", "positive")),
+		New("recovery_rate", "Recoveries as a percentage of confirmed cases",
+			[]string{"recovered", "positive"}, percentOf("recovered", "positive")),
+		New("active_rate", "Active cases as a percentage of confirmed cases",
+			[]string{"active", "positive"}, percentOf("active", "positive")),
+		New("growth_rate", "Day over day growth of confirmed cases in percent",
+			[]string{"daily_positive", "positive"}, func(v Values) (float64, bool) {
+				previous := v["positive"] - v["daily_positive"]
+				if previous <= 0 {
+					return 0, false
+				}
+				return v["daily_positive"] / previous * 100, true
+			}),
+	}
+}
+
+// input exposes a raw input as a metric so it can be ranked and sorted by
+func input(name, description string) Metric {
+	return New(name, description, []string{name}, func(v Values) (float64, bool) {
+		return v[name], true
+	})
+}
+
+func percentOf(part, whole string) func(Values) (float64, bool) {
+	return func(v Values) (float64, bool) {
+		if v[whole] == 0 {
+			return 0, false
+		}
+		return v[part] / v[whole] * 100, true
+	}
+}
@@ -0,0 +1,286 @@
+// Package metrics defines the derived statistics served by the rankings,
+// province sorting and aggregate endpoints. A new statistic is added by
+// registering a Metric; every endpoint that accepts a metric name picks it up.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// Inputs are the raw values read from a case row. Metrics may depend on these
+// and on previously registered metrics.
+var Inputs = []string{
+	"positive", "recovered", "deceased", "active",
+	"daily_positive", "daily_recovered", "daily_deceased", "rt",
+}
+
+// Values maps input and metric names to their values for one case row
+type Values map[string]float64
+
+// Metric is a statistic computed from a case row
+type Metric interface {
+	Name() string
+	Description() string
+	// Dependencies lists the inputs and metrics Compute reads
+	Dependencies() []string
+	// Compute returns false when the value is undefined, e.g. a rate with a
+	// zero denominator
+	Compute(v Values) (float64, bool)
+}
+
+type funcMetric struct {
+	name, description string
+	deps              []string
+	compute           func(Values) (float64, bool)
+}
+
+func (m funcMetric) Name() string                     { return m.name }
+func (m funcMetric) Description() string              { return m.description }
+func (m funcMetric) Dependencies() []string           { return m.deps }
+func (m funcMetric) Compute(v Values) (float64, bool) { return m.compute(v) }
+
+// New creates a Metric from a compute function
+func New(name, description string, deps []string, compute func(Values) (float64, bool)) Metric {
+	return funcMetric{name: name, description: description, deps: deps, compute: compute}
+}
+
+// Registry holds metrics in registration order, which is also the order they
+// are computed in
+type Registry struct {
+	mu      sync.RWMutex
+	metrics []Metric
+	byName  map[string]Metric
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]Metric)}
+}
+
+// Register adds m. Its dependencies must be inputs or already registered
+// metrics, which rules out cycles.
+func (r *Registry) Register(m Metric) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byName[m.Name()]; exists {
+		return fmt.Errorf("metric %q is already registered", m.Name())
+	}
+	for _, dep := range m.Dependencies() {
+		if _, ok := r.byName[dep]; !ok && !isInput(dep) {
+			return fmt.Errorf("metric %q depends on unknown metric %q", m.Name(), dep)
+		}
+	}
+	r.metrics = append(r.metrics, m)
+	r.byName[m.Name()] = m
+	return nil
+}
+
+// MustRegister is like Register but panics on error
+func (r *Registry) MustRegister(m Metric) {
+	if err := r.Register(m); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns the metric with the name
+func (r *Registry) Get(name string) (Metric, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.byName[name]
+	return m, ok
+}
+
+// All returns the registered metrics in registration order
+func (r *Registry) All() []Metric {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Metric(nil), r.metrics...)
+}
+
+// Names returns the registered metric names in registration order
+func (r *Registry) Names() []string {
+	all := r.All()
+	names := make([]string, len(all))
+	for i, m := range all {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+// Evaluate computes every registered metric from the inputs. Metrics that are
+// undefined, or depend on one that is, are left out of the result.
+func (r *Registry) Evaluate(in Values) Values {
+	work := make(Values, len(in))
+	for k, v := range in {
+		work[k] = v
+	}
+	out := make(Values)
+	for _, m := range r.All() {
+		if !hasAll(work, m.Dependencies()) {
+			continue
+		}
+		if v, ok := m.Compute(work); ok && !math.IsNaN(v) && !math.IsInf(v, 0) {
+			work[m.Name()] = v
+			out[m.Name()] = v
+		}
+	}
+	return out
+}
+
+// Summary aggregates a metric over a series of case rows
+type Summary struct {
+	Latest  *float64 `json:"latest"`
+	Min     *float64 `json:"min"`
+	Max     *float64 `json:"max"`
+	Average *float64 `json:"average"`
+	// Count is the number of rows the metric was defined for
+	Count int `json:"count"`
+}
+
+// Aggregate summarizes every registered metric over series, which must be in
+// chronological order
+func (r *Registry) Aggregate(series []Values) map[string]Summary {
+	acc := r.NewAccumulator()
+	for _, in := range series {
+		acc.Add(in)
+	}
+	return acc.Summaries()
+}
+
+// Accumulator summarizes every registered metric over case rows as they are
+// added, so a series can be aggregated page by page instead of being held in
+// memory. It is safe for concurrent use. Latest is the value of the last row
+// added, so rows must be added in chronological order for it to be the latest.
+type Accumulator struct {
+	registry *Registry
+
+	mu        sync.Mutex
+	rows      int
+	summaries map[string]Summary
+	sums      map[string]float64
+}
+
+// NewAccumulator creates an empty Accumulator of the registered metrics
+func (r *Registry) NewAccumulator() *Accumulator {
+	names := r.Names()
+	summaries := make(map[string]Summary, len(names))
+	for _, name := range names {
+		summaries[name] = Summary{}
+	}
+	return &Accumulator{registry: r, summaries: summaries, sums: make(map[string]float64)}
+}
+
+// Add folds the metrics of a case row into the summaries
+func (a *Accumulator) Add(in Values) {
+	// Evaluate outside the lock so concurrent callers only serialize the fold
+	computed := a.registry.Evaluate(in)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rows++
+	for name, v := range computed {
+		s := a.summaries[name]
+		s.Latest = &v
+		if s.Min == nil || v < *s.Min {
+			s.Min = &v
+		}
+		if s.Max == nil || v > *s.Max {
+			s.Max = &v
+		}
+		s.Count++
+		a.sums[name] += v
+		a.summaries[name] = s
+	}
+}
+
+// Rows returns the number of rows added
+func (a *Accumulator) Rows() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rows
+}
+
+// Summaries returns the summary of every registered metric over the rows
+// added so far
+func (a *Accumulator) Summaries() map[string]Summary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make(map[string]Summary, len(a.summaries))
+	for name, s := range a.summaries {
+		if s.Count > 0 {
+			avg := a.sums[name] / float64(s.Count)
+			s.Average = &avg
+		}
+		out[name] = s
+	}
+	return out
+}
+
+// Ranked is the position of an item in the slice passed to Rank and its value
+// of the ranked metric
+type Ranked struct {
+	Index int
+	Value float64
+}
+
+// Rank orders items by the metric, descending unless order is "asc". Items the
+// metric is undefined for are dropped.
+func (r *Registry) Rank(name, order string, items []Values) ([]Ranked, error) {
+	if _, ok := r.Get(name); !ok {
+		return nil, fmt.Errorf("unknown metric %q", name)
+	}
+	ranked := make([]Ranked, 0, len(items))
+	for i, in := range items {
+		if v, ok := r.Evaluate(in)[name]; ok {
+			ranked = append(ranked, Ranked{Index: i, Value: v})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if order == "asc" {
+			return ranked[i].Value < ranked[j].Value
+		}
+		return ranked[i].Value > ranked[j].Value
+	})
+	return ranked, nil
+}
+
+// FromProvinceCase reads the inputs of a province case row
+func FromProvinceCase(c models.ProvinceCaseResponse) Values {
+	v := Values{
+		"positive":        float64(c.Cumulative.Positive),
+		"recovered":       float64(c.Cumulative.Recovered),
+		"deceased":        float64(c.Cumulative.Deceased),
+		"active":          float64(c.Cumulative.Active),
+		"daily_positive":  float64(c.Daily.Positive),
+		"daily_recovered": float64(c.Daily.Recovered),
+		"daily_deceased":  float64(c.Daily.Deceased),
+	}
+	if rt := c.Statistics.ReproductionRate; rt != nil && rt.Value != nil {
+		v["rt"] = *rt.Value
+	}
+	return v
+}
+
+func isInput(name string) bool {
+	for _, in := range Inputs {
+		if in == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAll(v Values, names []string) bool {
+	for _, n := range names {
+		if _, ok := v[n]; !ok {
+			return false
+		}
+	}
+	return true
+}
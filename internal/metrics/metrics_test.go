@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(New("doubled", "", []string{"positive"}, func(v Values) (float64, bool) {
+		return v["positive"] * 2, true
+	})))
+
+	assert.Error(t, r.Register(New("doubled", "", nil, nil)), "duplicate name")
+	assert.Error(t, r.Register(New("per_capita", "", []string{"population"}, nil)), "unknown dependency")
+	require.NoError(t, r.Register(New("quadrupled", "", []string{"doubled"}, func(v Values) (float64, bool) {
+		return v["doubled"] * 2, true
+	})), "metrics may depend on registered metrics")
+	assert.Equal(t, []string{"doubled", "quadrupled"}, r.Names())
+}
+
+func TestRegistry_Evaluate(t *testing.T) {
+	got := Default.Evaluate(Values{"positive": 200, "recovered": 150, "deceased": 10, "active": 40, "daily_positive": 20})
+
+	assert.Equal(t, 200.0, got["positive"])
+	assert.InDelta(t, 5.0, got["case_fatality_rate"], 1e-9)
+	assert.InDelta(t, 75.0, got["recovery_rate"], 1e-9)
+	assert.InDelta(t, 20.0, got["active_rate"], 1e-9)
+	assert.InDelta(t, 20.0/180*100, got["growth_rate"], 1e-9)
+	assert.NotContains(t, got, "rt", "rt is missing from the inputs")
+	assert.NotContains(t, got, "daily_recovered", "inputs that are not metrics are not returned")
+}
+
+func TestRegistry_Evaluate_Undefined(t *testing.T) {
+	got := Default.Evaluate(Values{"positive": 0, "recovered": 0, "deceased": 0, "active": 0, "daily_positive": 0})
+
+	assert.NotContains(t, got, "case_fatality_rate")
+	assert.NotContains(t, got, "growth_rate")
+	assert.Contains(t, got, "positive")
+}
+
+func TestRegistry_Rank(t *testing.T) {
+	items := []Values{
+		{"positive": 100, "deceased": 5},
+		{"positive": 0, "deceased": 0},
+		{"positive": 100, "deceased": 10},
+	}
+
+	ranked, err := Default.Rank("case_fatality_rate", "desc", items)
+	require.NoError(t, err)
+	assert.Equal(t, []Ranked{{Index: 2, Value: 10}, {Index: 0, Value: 5}}, ranked)
+
+	ranked, err = Default.Rank("case_fatality_rate", "asc", items)
+	require.NoError(t, err)
+	assert.Equal(t, 0, ranked[0].Index)
+
+	_, err = Default.Rank("unknown", "desc", items)
+	assert.Error(t, err)
+}
+
+func TestRegistry_Aggregate(t *testing.T) {
+	got := Default.Aggregate([]Values{
+		{"positive": 100, "deceased": 2},
+		{"positive": 200, "deceased": 8},
+		{"positive": 0, "deceased": 0},
+	})
+
+	cfr := got["case_fatality_rate"]
+	assert.Equal(t, 2, cfr.Count)
+	assert.InDelta(t, 4.0, *cfr.Latest, 1e-9)
+	assert.InDelta(t, 2.0, *cfr.Min, 1e-9)
+	assert.InDelta(t, 4.0, *cfr.Max, 1e-9)
+	assert.InDelta(t, 3.0, *cfr.Average, 1e-9)
+
+	rt := got["rt"]
+	assert.Equal(t, 0, rt.Count)
+	assert.Nil(t, rt.Latest)
+}
+
+func TestAccumulator_Concurrent(t *testing.T) {
+	acc := Default.NewAccumulator()
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acc.Add(Values{"positive": float64(i)})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, acc.Rows())
+	positive := acc.Summaries()["positive"]
+	assert.Equal(t, 100, positive.Count)
+	assert.InDelta(t, 1.0, *positive.Min, 1e-9)
+	assert.InDelta(t, 100.0, *positive.Max, 1e-9)
+	assert.InDelta(t, 50.5, *positive.Average, 1e-9)
+}
+
+func TestFromProvinceCase(t *testing.T) {
+	rt := 1.2
+	v := FromProvinceCase(models.ProvinceCaseResponse{
+		Daily:      models.ProvinceDailyCases{Positive: 3},
+		Cumulative: models.ProvinceCumulativeCases{Positive: 30, Recovered: 20, Deceased: 1, Active: 9},
+		Statistics: models.ProvinceCaseStatistics{ReproductionRate: &models.ReproductionRate{Value: &rt}},
+	})
+
+	assert.Equal(t, Values{
+		"positive": 30, "recovered": 20, "deceased": 1, "active": 9,
+		"daily_positive": 3, "daily_recovered": 0, "daily_deceased": 0, "rt": 1.2,
+	}, v)
+}
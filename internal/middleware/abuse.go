@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// abuseStrikes counts a client's 404 and 429 responses in the current window
+type abuseStrikes struct {
+	count       int
+	windowStart time.Time
+}
+
+// AbuseDetector bans clients for a while once they collect too many 404 and
+// 429 responses, the pattern of scrapers walking IDs or ignoring the rate
+// limit. It is separate from RateLimiter: a ban rejects every request from
+// the client, whatever its path.
+type AbuseDetector struct {
+	mutex   sync.Mutex
+	config  config.AbuseConfig
+	strikes map[string]*abuseStrikes
+	bans    map[string]models.ClientBan
+	clock   clock.Clock
+	clients *ClientResolver
+}
+
+// NewAbuseDetector creates an AbuseDetector with no strikes or bans
+func NewAbuseDetector(cfg config.AbuseConfig) *AbuseDetector {
+	return &AbuseDetector{
+		config:  cfg,
+		strikes: make(map[string]*abuseStrikes),
+		bans:    make(map[string]models.ClientBan),
+		clock:   clock.System,
+		clients: NewClientResolver(nil),
+	}
+}
+
+// SetClock sets the clock of the strike windows, the bans and the cleanup
+// schedule
+func (d *AbuseDetector) SetClock(c clock.Clock) {
+	d.clock = c
+}
+
+// SetClientResolver sets how clients are told apart. Without trusted proxies,
+// the default, clients are the addresses of their connections.
+func (d *AbuseDetector) SetClientResolver(clients *ClientResolver) {
+	d.clients = clients
+}
+
+// StartCleanup forgets expired bans and stale strike counts every interval
+func (d *AbuseDetector) StartCleanup(interval time.Duration) {
+	go func() {
+		ticker := d.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C() {
+			d.cleanup()
+		}
+	}()
+}
+
+func (d *AbuseDetector) cleanup() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.clock.Now()
+	for ip, ban := range d.bans {
+		if !now.Before(ban.ExpiresAt) {
+			delete(d.bans, ip)
+		}
+	}
+	for ip, s := range d.strikes {
+		if now.Sub(s.windowStart) > d.config.StrikeWindow {
+			delete(d.strikes, ip)
+		}
+	}
+}
+
+// banned returns the client's ban while it is in force
+func (d *AbuseDetector) banned(clientIP string) (models.ClientBan, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	ban, ok := d.bans[clientIP]
+	if !ok {
+		return ban, false
+	}
+	if !d.clock.Now().Before(ban.ExpiresAt) {
+		delete(d.bans, clientIP)
+		return ban, false
+	}
+	return ban, true
+}
+
+// strike counts a 404 or 429 response and bans the client when it reaches
+// the limit within the window
+func (d *AbuseDetector) strike(clientIP string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.clock.Now()
+	s, ok := d.strikes[clientIP]
+	if !ok || now.Sub(s.windowStart) > d.config.StrikeWindow {
+		s = &abuseStrikes{windowStart: now}
+		d.strikes[clientIP] = s
+	}
+	s.count++
+	if s.count < d.config.StrikeLimit {
+		return
+	}
+
+	delete(d.strikes, clientIP)
+	d.bans[clientIP] = models.ClientBan{
+		ClientIP:  clientIP,
+		Strikes:   s.count,
+		BannedAt:  now,
+		ExpiresAt: now.Add(d.config.BanDuration),
+	}
+	log.Printf("Banned client %s for %s after %d 404/429 responses", clientIP, d.config.BanDuration, s.count)
+}
+
+// ActiveBans lists the bans in force, latest first
+func (d *AbuseDetector) ActiveBans() []models.ClientBan {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.clock.Now()
+	bans := make([]models.ClientBan, 0, len(d.bans))
+	for _, ban := range d.bans {
+		if now.Before(ban.ExpiresAt) {
+			bans = append(bans, ban)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		if !bans[i].BannedAt.Equal(bans[j].BannedAt) {
+			return bans[i].BannedAt.After(bans[j].BannedAt)
+		}
+		return bans[i].ClientIP < bans[j].ClientIP
+	})
+	return bans
+}
+
+// Unban lifts the client's ban and clears its strikes, reporting whether it
+// was banned
+func (d *AbuseDetector) Unban(clientIP string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	_, ok := d.bans[clientIP]
+	delete(d.bans, clientIP)
+	delete(d.strikes, clientIP)
+	return ok
+}
+
+// AbuseDetection rejects requests from banned clients with 403 and counts the
+// 404 and 429 responses of everyone else. It wraps the whole router rather
+// than being added with router.Use, since requests to unknown paths never
+// reach route middleware.
+func AbuseDetection(detector *AbuseDetector) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !detector.config.Enabled || detector.config.StrikeLimit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// A forged X-Forwarded-For must neither dodge a ban nor get
+			// someone else banned
+			ip := detector.clients.StrictClientIP(r)
+			if ban, ok := detector.banned(ip); ok {
+				retryAfter := int(ban.ExpiresAt.Sub(detector.clock.Now()).Seconds()) + 1
+				w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
+				writeRateLimitError(w, http.StatusForbidden, "Temporarily banned for too many failed requests.")
+				return
+			}
+
+			wrapped := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+			if wrapped.status == http.StatusNotFound || wrapped.status == http.StatusTooManyRequests {
+				detector.strike(ip)
+			}
+		})
+	}
+}
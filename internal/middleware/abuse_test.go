@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAbuseDetector(clk clock.Clock) *AbuseDetector {
+	d := NewAbuseDetector(config.AbuseConfig{Enabled: true, StrikeLimit: 3, StrikeWindow: time.Minute, BanDuration: time.Hour})
+	d.SetClock(clk)
+	return d
+}
+
+func abuseRequest(h http.Handler, path, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func abuseTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+func TestAbuseDetection_BansAfterStrikeLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewMock(now)
+	d := newTestAbuseDetector(clk)
+	h := AbuseDetection(d)(abuseTestHandler())
+
+	abuseRequest(h, "/missing", "10.0.0.1:1234")
+	abuseRequest(h, "/limited", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "10.0.0.1:1234").Code)
+	abuseRequest(h, "/missing", "10.0.0.1:1234")
+
+	w := abuseRequest(h, "/ok", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "3601", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "10.0.0.2:1234").Code, "other clients are not affected")
+
+	bans := d.ActiveBans()
+	if assert.Len(t, bans, 1) {
+		assert.Equal(t, "10.0.0.1", bans[0].ClientIP)
+		assert.Equal(t, 3, bans[0].Strikes)
+		assert.Equal(t, now.Add(time.Hour), bans[0].ExpiresAt)
+	}
+
+	clk.Advance(time.Hour)
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "10.0.0.1:1234").Code, "bans expire")
+	assert.Empty(t, d.ActiveBans())
+}
+
+func TestAbuseDetection_StrikesExpireWithWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewMock(now)
+	d := newTestAbuseDetector(clk)
+	h := AbuseDetection(d)(abuseTestHandler())
+
+	abuseRequest(h, "/missing", "10.0.0.1:1234")
+	abuseRequest(h, "/missing", "10.0.0.1:1234")
+	clk.Advance(2 * time.Minute)
+	abuseRequest(h, "/missing", "10.0.0.1:1234")
+
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "10.0.0.1:1234").Code)
+	assert.Empty(t, d.ActiveBans())
+}
+
+func TestAbuseDetector_Unban(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewMock(now)
+	d := newTestAbuseDetector(clk)
+	h := AbuseDetection(d)(abuseTestHandler())
+	for i := 0; i < 3; i++ {
+		abuseRequest(h, "/missing", "10.0.0.1:1234")
+	}
+
+	assert.True(t, d.Unban("10.0.0.1"))
+	assert.False(t, d.Unban("10.0.0.1"))
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "10.0.0.1:1234").Code)
+}
+
+func TestAbuseDetection_IgnoresForgedForwardedFor(t *testing.T) {
+	d := newTestAbuseDetector(clock.NewMock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+	h := AbuseDetection(d)(abuseTestHandler())
+	forged := func(path, forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.9:1234"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.Header.Set("X-Real-IP", forwarded)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Strikes sent in a victim's name count against the sender
+	for i := 0; i < 3; i++ {
+		forged("/missing", "198.51.100.1")
+	}
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "198.51.100.1:1234").Code)
+	assert.Equal(t, http.StatusForbidden, forged("/ok", "198.51.100.2"))
+}
+
+func TestAbuseDetection_TrustedProxies(t *testing.T) {
+	d := newTestAbuseDetector(clock.NewMock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+	d.SetClientResolver(NewClientResolver([]string{"10.0.0.0/8"}))
+	h := AbuseDetection(d)(abuseTestHandler())
+	viaProxy := func(path, forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.5:1234"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		viaProxy("/missing", "198.51.100.1")
+	}
+	assert.Equal(t, http.StatusForbidden, viaProxy("/ok", "198.51.100.1"))
+	// Other clients behind the same proxy are not banned
+	assert.Equal(t, http.StatusOK, viaProxy("/ok", "198.51.100.2"))
+}
+
+func TestAbuseDetection_Disabled(t *testing.T) {
+	d := NewAbuseDetector(config.AbuseConfig{StrikeLimit: 1, StrikeWindow: time.Minute, BanDuration: time.Hour})
+	h := AbuseDetection(d)(abuseTestHandler())
+
+	abuseRequest(h, "/missing", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, abuseRequest(h, "/ok", "10.0.0.1:1234").Code)
+	assert.Empty(t, d.ActiveBans())
+}
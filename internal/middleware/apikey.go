@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// APIKeyHeader carries the client's API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator looks up the key sent in the X-API-Key header
+type APIKeyAuthenticator interface {
+	Authenticate(key string) (*models.APIKey, bool)
+}
+
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the key that authenticated the request, if any
+func APIKeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+	k, ok := ctx.Value(apiKeyContextKey{}).(*models.APIKey)
+	return k, ok
+}
+
+// WithAPIKey returns ctx carrying the key that authenticated a call, for
+// calls that are not checked by APIKeyAuth such as those over gRPC
+func WithAPIKey(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// APIKeyAuth requires a valid X-API-Key with the scope a request needs on
+// /api/v1 and /admin paths that are not exempt: admin for admin endpoints,
+// read for GET and HEAD and write otherwise. Admin requests without a key fall
+// through to the X-Admin-Key check of the handlers. RateLimit counts requests
+// with a key per key.
+func APIKeyAuth(cfg config.AuthConfig, keys APIKeyAuthenticator) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			if r.Method == http.MethodOptions || !requiresAPIKey(cfg, path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Get(APIKeyHeader)
+			if header == "" {
+				if isAdminPath(path) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				writeAuthError(w, http.StatusUnauthorized, "Missing API key")
+				return
+			}
+			key, ok := keys.Authenticate(header)
+			if !ok {
+				writeAuthError(w, http.StatusUnauthorized, "Invalid API key")
+				return
+			}
+			scope := requiredScope(r)
+			if !key.HasScope(scope) {
+				writeAuthError(w, http.StatusForbidden, fmt.Sprintf("API key lacks the %s scope", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithAPIKey(r.Context(), key)))
+		})
+	}
+}
+
+// requiresAPIKey reports whether path is an API or admin path that is not exempt
+func requiresAPIKey(cfg config.AuthConfig, path string) bool {
+	if !strings.HasPrefix(path, "/api/v1") && !isAdminPath(path) {
+		return false
+	}
+	for _, prefix := range cfg.ExemptPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAdminPath(path string) bool {
+	return strings.HasPrefix(path, "/admin/") || strings.HasPrefix(path, "/api/v1/admin/")
+}
+
+func requiredScope(r *http.Request) string {
+	switch {
+	case isAdminPath(r.URL.Path):
+		return models.ScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return models.ScopeRead
+	default:
+		return models.ScopeWrite
+	}
+}
+
+func writeAuthError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(ErrorResponse{Status: "error", Error: message}); err != nil {
+		log.Printf("Error encoding auth JSON response: %v", err)
+	}
+}
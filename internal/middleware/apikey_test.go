@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAPIKeys map[string]models.APIKey
+
+func (s stubAPIKeys) Authenticate(key string) (*models.APIKey, bool) {
+	k, ok := s[key]
+	if !ok {
+		return nil, false
+	}
+	return &k, true
+}
+
+var testAPIKeys = stubAPIKeys{
+	"reader": {Name: "reader", KeyHash: "r", Scopes: []string{models.ScopeRead}},
+	"writer": {Name: "writer", KeyHash: "w", Scopes: []string{models.ScopeWrite}},
+	"admin":  {Name: "admin", KeyHash: "a", Scopes: []string{models.ScopeAdmin}},
+}
+
+func newAPIKeyTestHandler(cfg config.AuthConfig) http.Handler {
+	return APIKeyAuth(cfg, testAPIKeys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if k, ok := APIKeyFromContext(r.Context()); ok {
+			w.Header().Set("X-Key-Name", k.Name)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestAPIKeyAuth_Disabled(t *testing.T) {
+	handler := newAPIKeyTestHandler(config.AuthConfig{})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/national", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAPIKeyAuth_Scopes(t *testing.T) {
+	handler := newAPIKeyTestHandler(config.AuthConfig{Enabled: true, ExemptPathPrefixes: []string{"/api/v1/health"}})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{"missing key", "GET", "/api/v1/national", "", http.StatusUnauthorized},
+		{"unknown key", "GET", "/api/v1/national", "guess", http.StatusUnauthorized},
+		{"read", "GET", "/api/v1/national", "reader", http.StatusOK},
+		{"read cannot write", "POST", "/api/v1/national", "reader", http.StatusForbidden},
+		{"write", "POST", "/api/v1/national", "writer", http.StatusOK},
+		{"write cannot administer", "GET", "/admin/backups", "writer", http.StatusForbidden},
+		{"admin", "DELETE", "/admin/bans/10.0.0.1", "admin", http.StatusOK},
+		{"admin key header checked by handler", "GET", "/admin/backups", "", http.StatusOK},
+		{"exempt path", "GET", "/api/v1/health", "", http.StatusOK},
+		{"outside the API", "GET", "/robots.txt", "", http.StatusOK},
+		{"preflight", "OPTIONS", "/api/v1/national", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			if tt.status == http.StatusOK && tt.key != "" {
+				assert.Equal(t, tt.key, rr.Header().Get("X-Key-Name"))
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// clientTopRoutes is how many routes each client of a bandwidth report lists
+const clientTopRoutes = 5
+
+// byteCounter sums the response sizes of a route or client
+type byteCounter struct {
+	requests int64
+	bytes    int64
+	maxBytes int64
+}
+
+func (c *byteCounter) add(n int64) {
+	c.requests++
+	c.bytes += n
+	if n > c.maxBytes {
+		c.maxBytes = n
+	}
+}
+
+func (c *byteCounter) mean() float64 {
+	if c.requests == 0 {
+		return 0
+	}
+	return float64(c.bytes) / float64(c.requests)
+}
+
+// bandwidthClient identifies an API key, or the requests without one
+type bandwidthClient struct {
+	name string
+	id   int64
+}
+
+// BandwidthRecorder sums the response bytes served per route and per client
+// since startup
+type BandwidthRecorder struct {
+	mutex   sync.Mutex
+	since   time.Time
+	clients map[bandwidthClient]map[string]*byteCounter
+}
+
+// NewBandwidthRecorder creates an empty BandwidthRecorder
+func NewBandwidthRecorder() *BandwidthRecorder {
+	return &BandwidthRecorder{
+		since:   time.Now().UTC(),
+		clients: make(map[bandwidthClient]map[string]*byteCounter),
+	}
+}
+
+// Observe records a response of n body bytes for the route and API key,
+// nil for requests without one
+func (br *BandwidthRecorder) Observe(route string, key *models.APIKey, n int64) {
+	client := bandwidthClient{name: models.AnonymousClient}
+	if key != nil {
+		client = bandwidthClient{name: key.Name, id: key.ID}
+	}
+
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	routes, ok := br.clients[client]
+	if !ok {
+		routes = make(map[string]*byteCounter)
+		br.clients[client] = routes
+	}
+	c, ok := routes[route]
+	if !ok {
+		c = &byteCounter{}
+		routes[route] = c
+	}
+	c.add(n)
+}
+
+// BandwidthSummary returns the bytes served per route and per client, most
+// bytes first
+func (br *BandwidthRecorder) BandwidthSummary() models.BandwidthReport {
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	report := models.BandwidthReport{
+		Since:   br.since,
+		Routes:  []models.RouteBandwidth{},
+		Clients: make([]models.ClientBandwidth, 0, len(br.clients)),
+	}
+	routeTotals := make(map[string]*byteCounter)
+	for client, routes := range br.clients {
+		var total byteCounter
+		usage := models.ClientBandwidth{Client: client.name, APIKeyID: client.id}
+		for route, c := range routes {
+			total.requests += c.requests
+			total.bytes += c.bytes
+			usage.TopRoutes = append(usage.TopRoutes, routeBandwidth(route, c))
+
+			rt, ok := routeTotals[route]
+			if !ok {
+				rt = &byteCounter{}
+				routeTotals[route] = rt
+			}
+			rt.requests += c.requests
+			rt.bytes += c.bytes
+			if c.maxBytes > rt.maxBytes {
+				rt.maxBytes = c.maxBytes
+			}
+		}
+		sortRouteBandwidth(usage.TopRoutes)
+		if len(usage.TopRoutes) > clientTopRoutes {
+			usage.TopRoutes = usage.TopRoutes[:clientTopRoutes]
+		}
+		usage.Requests, usage.Bytes, usage.MeanBytes = total.requests, total.bytes, total.mean()
+		report.Clients = append(report.Clients, usage)
+		report.Requests += total.requests
+		report.TotalBytes += total.bytes
+	}
+	for route, c := range routeTotals {
+		report.Routes = append(report.Routes, routeBandwidth(route, c))
+	}
+
+	sortRouteBandwidth(report.Routes)
+	sort.Slice(report.Clients, func(i, j int) bool {
+		if report.Clients[i].Bytes != report.Clients[j].Bytes {
+			return report.Clients[i].Bytes > report.Clients[j].Bytes
+		}
+		return report.Clients[i].Client < report.Clients[j].Client
+	})
+	return report
+}
+
+func routeBandwidth(route string, c *byteCounter) models.RouteBandwidth {
+	return models.RouteBandwidth{Route: route, Requests: c.requests, Bytes: c.bytes, MeanBytes: c.mean(), MaxBytes: c.maxBytes}
+}
+
+func sortRouteBandwidth(routes []models.RouteBandwidth) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Bytes != routes[j].Bytes {
+			return routes[i].Bytes > routes[j].Bytes
+		}
+		return routes[i].Route < routes[j].Route
+	})
+}
+
+// Bandwidth records the body bytes of every response under its route, labelled
+// like Metrics, and under the API key that authenticated the request. It must
+// run inside APIKeyAuth to see the key.
+func Bandwidth(recorder *BandwidthRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrapped := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+			key, _ := APIKeyFromContext(r.Context())
+			recorder.Observe(routeLabel(r), key, int64(wrapped.size))
+		})
+	}
+}
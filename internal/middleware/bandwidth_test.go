@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidth_RecordsBytesPerRouteAndKey(t *testing.T) {
+	recorder := NewBandwidthRecorder()
+	dashboard := &models.APIKey{ID: 3, Name: "dashboard"}
+	router := mux.NewRouter()
+	// Stands in for APIKeyAuth, which runs before Bandwidth
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(APIKeyHeader) != "" {
+				r = r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, dashboard))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Use(Bandwidth(recorder))
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", func(w http.ResponseWriter, r *http.Request) {
+		size := 100
+		if r.URL.Query().Get("all") == "true" {
+			size = 5000
+		}
+		_, _ = w.Write([]byte(strings.Repeat("x", size)))
+	}).Methods("GET")
+
+	serve := func(target string, withKey bool) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if withKey {
+			req.Header.Set(APIKeyHeader, "s3cret")
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	serve("/api/v1/provinces/72/cases?all=true", true)
+	serve("/api/v1/provinces/11/cases", true)
+	serve("/api/v1/provinces/72/cases", false)
+
+	report := recorder.BandwidthSummary()
+	assert.Equal(t, int64(3), report.Requests)
+	assert.Equal(t, int64(5200), report.TotalBytes)
+
+	require.Len(t, report.Routes, 2)
+	assert.Equal(t, models.RouteBandwidth{Route: "GET /api/v1/provinces/{provinceId}/cases?all=true", Requests: 1, Bytes: 5000, MeanBytes: 5000, MaxBytes: 5000}, report.Routes[0])
+	assert.Equal(t, models.RouteBandwidth{Route: "GET /api/v1/provinces/{provinceId}/cases", Requests: 2, Bytes: 200, MeanBytes: 100, MaxBytes: 100}, report.Routes[1])
+
+	require.Len(t, report.Clients, 2)
+	assert.Equal(t, "dashboard", report.Clients[0].Client)
+	assert.Equal(t, int64(3), report.Clients[0].APIKeyID)
+	assert.Equal(t, int64(5100), report.Clients[0].Bytes)
+	assert.Equal(t, 2550.0, report.Clients[0].MeanBytes)
+	require.Len(t, report.Clients[0].TopRoutes, 2)
+	assert.Equal(t, "GET /api/v1/provinces/{provinceId}/cases?all=true", report.Clients[0].TopRoutes[0].Route)
+	assert.Equal(t, models.AnonymousClient, report.Clients[1].Client)
+	assert.Equal(t, int64(100), report.Clients[1].Bytes)
+}
+
+func TestBandwidthRecorder_LimitsTopRoutes(t *testing.T) {
+	recorder := NewBandwidthRecorder()
+	for i := 1; i <= clientTopRoutes+2; i++ {
+		recorder.Observe("GET /r"+strings.Repeat("x", i), nil, int64(i))
+	}
+
+	report := recorder.BandwidthSummary()
+	require.Len(t, report.Clients, 1)
+	assert.Len(t, report.Clients[0].TopRoutes, clientTopRoutes)
+	assert.Equal(t, int64(clientTopRoutes+2), report.Clients[0].TopRoutes[0].Bytes)
+	assert.Len(t, report.Routes, clientTopRoutes+2)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientResolver finds the client of a request behind the trusted proxies
+// of RATE_LIMIT_TRUSTED_PROXIES
+type ClientResolver struct {
+	proxies []*net.IPNet
+}
+
+// NewClientResolver creates a ClientResolver trusting the proxies at cidrs,
+// which may also be single addresses. Invalid entries are logged and ignored.
+func NewClientResolver(cidrs []string) *ClientResolver {
+	c := &ClientResolver{}
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Printf("Ignoring trusted proxy %q: %v", cidr, err)
+			continue
+		}
+		c.proxies = append(c.proxies, network)
+	}
+	return c
+}
+
+// ClientIP returns the address of the client. With trusted proxies
+// configured, X-Forwarded-For is only honored on connections from them, and
+// the client is the last address in it that is not a trusted proxy. Without
+// any, the forwarding headers are trusted as sent.
+func (c *ClientResolver) ClientIP(r *http.Request) string {
+	if len(c.proxies) == 0 {
+		return clientIP(r)
+	}
+	return c.forwardedFor(r)
+}
+
+// StrictClientIP is ClientIP for decisions that a forged header must not
+// sway, such as bans: without trusted proxies it is the address of the
+// connection, whatever the forwarding headers say
+func (c *ClientResolver) StrictClientIP(r *http.Request) string {
+	if len(c.proxies) == 0 {
+		return remoteIP(r)
+	}
+	return c.forwardedFor(r)
+}
+
+// forwardedFor walks X-Forwarded-For back from the connection through the
+// trusted proxies
+func (c *ClientResolver) forwardedFor(r *http.Request) string {
+	remote := remoteIP(r)
+	if !c.trusted(remote) {
+		return remote
+	}
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		if !c.trusted(hop) {
+			return hop
+		}
+		remote = hop
+	}
+	return remote
+}
+
+func (c *ClientResolver) trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range c.proxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the address of the connection
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
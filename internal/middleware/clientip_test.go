@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientResolver_TrustedProxies(t *testing.T) {
+	clients := NewClientResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		expectedIP string
+	}{
+		{"untrusted connection ignores the header", "203.0.113.9:1234", "198.51.100.1", "203.0.113.9"},
+		{"trusted proxy", "10.1.2.3:1234", "198.51.100.1", "198.51.100.1"},
+		{"spoofed hops before the real client", "10.1.2.3:1234", "1.2.3.4, 198.51.100.1, 192.168.1.1", "198.51.100.1"},
+		{"trusted proxy without header", "10.1.2.3:1234", "", "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			assert.Equal(t, tt.expectedIP, clients.ClientIP(req))
+			assert.Equal(t, tt.expectedIP, clients.StrictClientIP(req))
+		})
+	}
+}
+
+func TestClientResolver_NoTrustedProxies(t *testing.T) {
+	clients := NewClientResolver(nil)
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "203.0.113.9:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	assert.Equal(t, "198.51.100.1", clients.ClientIP(req))
+	assert.Equal(t, "203.0.113.9", clients.StrictClientIP(req))
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"sync"
+)
+
+// ConcurrencyLimitHeader reports the concurrent request cap on the 429
+// responses of clients that reached it
+const ConcurrencyLimitHeader = "X-Concurrency-Limit"
+
+// concurrencyLimiter caps the requests each client has in flight, so one
+// consumer with parallel crawlers cannot hold every database connection
+type concurrencyLimiter struct {
+	max int
+
+	mu       sync.Mutex
+	inflight map[string]int
+}
+
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	return &concurrencyLimiter{max: max, inflight: make(map[string]int)}
+}
+
+// acquire takes a slot of client and reports whether one was free. Each
+// successful acquire must be followed by a release.
+func (c *concurrencyLimiter) acquire(client string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inflight[client] >= c.max {
+		return false
+	}
+	c.inflight[client]++
+	return true
+}
+
+// release frees a slot taken by acquire
+func (c *concurrencyLimiter) release(client string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inflight[client] <= 1 {
+		delete(c.inflight, client)
+		return
+	}
+	c.inflight[client]--
+}
+
+// snapshot returns the requests each client has in flight
+func (c *concurrencyLimiter) snapshot() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inflight := make(map[string]int, len(c.inflight))
+	for client, n := range c.inflight {
+		inflight[client] = n
+	}
+	return inflight
+}
@@ -19,7 +19,7 @@ func TestCORS_SetsHeaders(t *testing.T) {
 
 	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
 	assert.Equal(t, "GET, POST, PUT, DELETE, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
-	assert.Equal(t, "Content-Type, Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "Content-Type, Authorization, X-Tenant, X-API-Key", w.Header().Get("Access-Control-Allow-Headers"))
 	assert.Equal(t, "86400", w.Header().Get("Access-Control-Max-Age"))
 	assert.Equal(t, http.StatusOK, w.Code)
 }
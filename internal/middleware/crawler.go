@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/pkg/cache"
+)
+
+// CrawlerSnapshotHeader tells whether a crawler was served a snapshot (HIT) or
+// a fresh response that was stored as one (MISS)
+const CrawlerSnapshotHeader = "X-Crawler-Snapshot"
+
+// maxSnapshotBytes keeps whole-table responses such as ?all=true out of the
+// snapshot cache
+const maxSnapshotBytes = 1 << 20
+
+// perRequestHeaders are set by other middleware for each request and are not
+// part of a snapshot
+var perRequestHeaders = []string{
+	CrawlerSnapshotHeader, DedupHeader, ResponseCacheHeader, "X-Trace-Id",
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+	RateLimitDelayHeader,
+}
+
+// crawlerTokens are User-Agent substrings of well-known crawlers. "bot" alone
+// covers Googlebot, bingbot, AhrefsBot, SemrushBot, PetalBot and most others.
+var crawlerTokens = []string{
+	"bot", "crawl", "spider", "slurp", "archiver", "facebookexternalhit", "bingpreview", "headlesschrome",
+}
+
+// IsCrawler reports whether userAgent looks like a crawler, matching the
+// built-in tokens and extra case-insensitively
+func IsCrawler(userAgent string, extra []string) bool {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return false
+	}
+	for _, tokens := range [][]string{crawlerTokens, extra} {
+		for _, token := range tokens {
+			if token != "" && strings.Contains(ua, strings.ToLower(token)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// crawlerSnapshot is a stored successful response
+type crawlerSnapshot struct {
+	header http.Header
+	body   []byte
+}
+
+// snapshotWriter passes a response through while keeping a copy of it
+type snapshotWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (sw *snapshotWriter) WriteHeader(code int) {
+	sw.status = code
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *snapshotWriter) Write(b []byte) (int, error) {
+	if !sw.overflow {
+		if sw.body.Len()+len(b) > maxSnapshotBytes {
+			sw.overflow = true
+			sw.body.Reset()
+		} else {
+			sw.body.Write(b)
+		}
+	}
+	return sw.ResponseWriter.Write(b)
+}
+
+func (sw *snapshotWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// CrawlerSnapshots serves GET requests from crawlers out of a snapshot cache,
+// so indexing bots neither use up the rate limit nor hold database
+// connections. A crawler's first request for a URL runs normally and its 200
+// response is kept for cfg.SnapshotTTL; requests from other clients are never
+// affected. It must run before RateLimit for hits to skip the limit.
+func CrawlerSnapshots(cfg config.CrawlerConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !cfg.SnapshotsEnabled {
+			return next
+		}
+		snapshots := cache.New(cfg.SnapshotTTL)
+		snapshots.StartCleanup(cfg.SnapshotTTL)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || isStream(r) || !IsCrawler(r.UserAgent(), cfg.UserAgents) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Tenants share paths, so the host and X-Tenant header are part of the key
+			key := r.Host + "|" + r.Header.Get("X-Tenant") + "|" + r.URL.RequestURI()
+			if v, ok := snapshots.Get(key); ok {
+				snap := v.(crawlerSnapshot)
+				for name, values := range snap.header {
+					w.Header()[name] = values
+				}
+				w.Header().Set(CrawlerSnapshotHeader, "HIT")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(snap.body)
+				return
+			}
+
+			w.Header().Set(CrawlerSnapshotHeader, "MISS")
+			sw := &snapshotWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+			if sw.status != http.StatusOK || sw.overflow {
+				return
+			}
+			header := w.Header().Clone()
+			for _, name := range perRequestHeaders {
+				header.Del(name)
+			}
+			snapshots.Set(key, crawlerSnapshot{header: header, body: bytes.Clone(sw.body.Bytes())}, cfg.SnapshotTTL)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const googlebotUA = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+func TestIsCrawler(t *testing.T) {
+	assert.True(t, IsCrawler(googlebotUA, nil))
+	assert.True(t, IsCrawler("Mozilla/5.0 (compatible; AhrefsBot/7.0)", nil))
+	assert.True(t, IsCrawler("facebookexternalhit/1.1", nil))
+	assert.True(t, IsCrawler("MyScraper/1.0", []string{"myscraper"}))
+	assert.False(t, IsCrawler("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0", nil))
+	assert.False(t, IsCrawler("curl/8.4.0", nil))
+	assert.False(t, IsCrawler("", []string{""}))
+}
+
+func newCrawlerTestHandler(calls *int, status int) http.Handler {
+	cfg := config.CrawlerConfig{SnapshotsEnabled: true, SnapshotTTL: time.Minute}
+	return CrawlerSnapshots(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+}
+
+func crawl(h http.Handler, target, userAgent string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("User-Agent", userAgent)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestCrawlerSnapshots_ServesCrawlersFromSnapshot(t *testing.T) {
+	calls := 0
+	h := newCrawlerTestHandler(&calls, http.StatusOK)
+
+	first := crawl(h, "/api/v1/provinces", googlebotUA)
+	assert.Equal(t, "MISS", first.Header().Get(CrawlerSnapshotHeader))
+
+	second := crawl(h, "/api/v1/provinces", googlebotUA)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get(CrawlerSnapshotHeader))
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Empty(t, second.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, `{"status":"success"}`, second.Body.String())
+	assert.Equal(t, 1, calls)
+
+	crawl(h, "/api/v1/provinces?page=2", googlebotUA)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCrawlerSnapshots_IgnoresOtherClients(t *testing.T) {
+	calls := 0
+	h := newCrawlerTestHandler(&calls, http.StatusOK)
+
+	crawl(h, "/api/v1/provinces", googlebotUA)
+	w := crawl(h, "/api/v1/provinces", "Mozilla/5.0 Chrome/120.0")
+
+	assert.Empty(t, w.Header().Get(CrawlerSnapshotHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestCrawlerSnapshots_SkipsErrorsAndLargeBodies(t *testing.T) {
+	calls := 0
+	h := newCrawlerTestHandler(&calls, http.StatusInternalServerError)
+	crawl(h, "/api/v1/provinces", googlebotUA)
+	crawl(h, "/api/v1/provinces", googlebotUA)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	large := CrawlerSnapshots(config.CrawlerConfig{SnapshotsEnabled: true, SnapshotTTL: time.Minute})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			_, _ = w.Write([]byte(strings.Repeat("x", maxSnapshotBytes+1)))
+		}))
+	crawl(large, "/api/v1/provinces/72/cases?all=true", googlebotUA)
+	w := crawl(large, "/api/v1/provinces/72/cases?all=true", googlebotUA)
+	assert.Equal(t, "MISS", w.Header().Get(CrawlerSnapshotHeader))
+	assert.Equal(t, maxSnapshotBytes+1, w.Body.Len())
+	assert.Equal(t, 2, calls)
+}
+
+func TestCrawlerSnapshots_Disabled(t *testing.T) {
+	calls := 0
+	h := CrawlerSnapshots(config.CrawlerConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+
+	crawl(h, "/api/v1/provinces", googlebotUA)
+	w := crawl(h, "/api/v1/provinces", googlebotUA)
+
+	assert.Empty(t, w.Header().Get(CrawlerSnapshotHeader))
+	assert.Equal(t, 2, calls)
+}
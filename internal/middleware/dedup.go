@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/pkg/cache"
+)
+
+// DedupHeader is set to HIT on responses reused from an identical request
+const DedupHeader = "X-Dedup"
+
+// maxDedupWindow keeps the micro-cache from serving noticeably stale data
+const maxDedupWindow = 5 * time.Second
+
+// dedupCall is a request being served for the first client to ask for it.
+// snap is set before done is closed when the response can be shared.
+type dedupCall struct {
+	done chan struct{}
+	snap *crawlerSnapshot
+}
+
+// RequestDedup answers identical GET requests from the same client within
+// cfg.Window with one response, so dashboard auto-refresh storms and
+// double-fired frontend requests do not each hit the database. Requests that
+// arrive while the first is still running wait for it. Only 200 responses are
+// reused, and event streams are never shared. Clients are told apart by
+// clients. It must run before RateLimit for reused responses to skip the limit.
+func RequestDedup(cfg config.DedupConfig, clients *ClientResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		window := cfg.Window
+		if window <= 0 {
+			return next
+		}
+		if window > maxDedupWindow {
+			window = maxDedupWindow
+		}
+		recent := cache.New(window)
+		recent.StartCleanup(time.Minute)
+
+		var mu sync.Mutex
+		inflight := make(map[string]*dedupCall)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || isStream(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Accept is part of the key because it selects CSV over JSON
+			key := clients.ClientIP(r) + "|" + r.Host + "|" + r.Header.Get("X-Tenant") + "|" +
+				r.Header.Get("Accept") + "|" + r.URL.RequestURI()
+			if v, ok := recent.Get(key); ok {
+				writeDedupSnapshot(w, v.(*crawlerSnapshot))
+				return
+			}
+
+			mu.Lock()
+			if call, ok := inflight[key]; ok {
+				mu.Unlock()
+				select {
+				case <-call.done:
+					if call.snap != nil {
+						writeDedupSnapshot(w, call.snap)
+						return
+					}
+				case <-r.Context().Done():
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			call := &dedupCall{done: make(chan struct{})}
+			inflight[key] = call
+			mu.Unlock()
+
+			defer func() {
+				mu.Lock()
+				delete(inflight, key)
+				mu.Unlock()
+				close(call.done)
+			}()
+
+			sw := &snapshotWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+			if sw.status != http.StatusOK || sw.overflow {
+				return
+			}
+			header := w.Header().Clone()
+			for _, name := range perRequestHeaders {
+				header.Del(name)
+			}
+			call.snap = &crawlerSnapshot{header: header, body: bytes.Clone(sw.body.Bytes())}
+			recent.Set(key, call.snap, window)
+		})
+	}
+}
+
+func writeDedupSnapshot(w http.ResponseWriter, snap *crawlerSnapshot) {
+	for name, values := range snap.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(DedupHeader, "HIT")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(snap.body)
+}
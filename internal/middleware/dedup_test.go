@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDedupTestHandler(calls *atomic.Int32, status int, release <-chan struct{}) http.Handler {
+	return RequestDedup(config.DedupConfig{Window: time.Second}, NewClientResolver(nil))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+}
+
+func dedupRequest(h http.Handler, method, target, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestDedup_ReusesResponseWithinWindow(t *testing.T) {
+	var calls atomic.Int32
+	h := newDedupTestHandler(&calls, http.StatusOK, nil)
+
+	first := dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+	assert.Empty(t, first.Header().Get(DedupHeader))
+
+	second := dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:5678")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get(DedupHeader))
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Empty(t, second.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, `{"status":"success"}`, second.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRequestDedup_KeyedByClientAndURL(t *testing.T) {
+	var calls atomic.Int32
+	h := newDedupTestHandler(&calls, http.StatusOK, nil)
+
+	dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+	assert.Empty(t, dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.2:1234").Header().Get(DedupHeader))
+	assert.Empty(t, dedupRequest(h, http.MethodGet, "/api/v1/national/latest?page=2", "10.0.0.1:1234").Header().Get(DedupHeader))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRequestDedup_TrustedProxies(t *testing.T) {
+	var calls atomic.Int32
+	h := RequestDedup(config.DedupConfig{Window: time.Second}, NewClientResolver([]string{"10.0.0.0/8"}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	forwarded := func(remoteAddr, forwardedFor string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/national/latest", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Header().Get(DedupHeader)
+	}
+
+	// A direct client cannot pass for another one with the header
+	forwarded("203.0.113.9:1234", "198.51.100.1")
+	assert.Equal(t, "HIT", forwarded("203.0.113.9:1234", "198.51.100.2"))
+	// Clients behind a trusted proxy are told apart
+	forwarded("10.0.0.5:1234", "198.51.100.1")
+	assert.Empty(t, forwarded("10.0.0.5:1234", "198.51.100.2"))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRequestDedup_SkipsNonGetAndErrors(t *testing.T) {
+	var calls atomic.Int32
+	h := newDedupTestHandler(&calls, http.StatusOK, nil)
+	dedupRequest(h, http.MethodPost, "/api/v1/subscriptions", "10.0.0.1:1234")
+	dedupRequest(h, http.MethodPost, "/api/v1/subscriptions", "10.0.0.1:1234")
+	assert.Equal(t, int32(2), calls.Load())
+
+	var failed atomic.Int32
+	h = newDedupTestHandler(&failed, http.StatusInternalServerError, nil)
+	dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+	w := dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, int32(2), failed.Load())
+}
+
+func TestRequestDedup_CollapsesConcurrentRequests(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h := newDedupTestHandler(&calls, http.StatusOK, release)
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 5)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+		}(i)
+	}
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, w := range responses {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"status":"success"}`, w.Body.String())
+	}
+}
+
+func TestRequestDedup_DisabledWithoutWindow(t *testing.T) {
+	calls := 0
+	h := RequestDedup(config.DedupConfig{}, NewClientResolver(nil))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+	w := dedupRequest(h, http.MethodGet, "/api/v1/national/latest", "10.0.0.1:1234")
+	assert.Empty(t, w.Header().Get(DedupHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestRequestDedup_SkipsStreams(t *testing.T) {
+	var calls atomic.Int32
+	h := newDedupTestHandler(&calls, http.StatusOK, nil)
+
+	dedupRequest(h, http.MethodGet, "/api/v1/stream/cases", "10.0.0.1:1234")
+	w := dedupRequest(h, http.MethodGet, "/api/v1/stream/cases", "10.0.0.1:1234")
+
+	assert.Empty(t, w.Header().Get(DedupHeader))
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
 )
 
 type responseWriter struct {
@@ -23,6 +25,12 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return size, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush an event stream
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -34,14 +42,20 @@ func Logging(next http.Handler) http.Handler {
 
 		next.ServeHTTP(wrapped, r)
 
+		traceID := "-"
+		if t, ok := tracing.FromContext(r.Context()); ok {
+			traceID = t.TraceID
+		}
+
 		log.Printf(
-			"%s %s %d %d %v %s",
+			"%s %s %d %d %v %s trace=%s",
 			r.Method,
 			r.URL.Path,
 			wrapped.status,
 			wrapped.size,
 			time.Since(start),
 			r.UserAgent(),
+			traceID,
 		)
 	})
 }
@@ -50,3 +50,15 @@ func TestResponseWriter_Write(t *testing.T) {
 	assert.Equal(t, 4, n)
 	assert.Equal(t, 4, rw.size)
 }
+
+func TestLogging_Flushes(t *testing.T) {
+	handler := Logging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		assert.NoError(t, http.NewResponseController(w).Flush())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/stream/cases", nil))
+
+	assert.True(t, w.Flushed)
+}
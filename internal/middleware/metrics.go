@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// latencyBucketsMs are the upper bounds of the latency histogram buckets in
+// milliseconds. Observations above the last bound fall into an overflow bucket.
+var latencyBucketsMs = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+// latencyHistogram is a fixed-bucket histogram of request durations
+type latencyHistogram struct {
+	counts []int64
+	count  int64
+	sumMs  float64
+	maxMs  float64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{counts: make([]int64, len(latencyBucketsMs)+1)}
+}
+
+func (h *latencyHistogram) observe(ms float64) {
+	i := sort.SearchFloat64s(latencyBucketsMs, ms)
+	h.counts[i]++
+	h.count++
+	h.sumMs += ms
+	if ms > h.maxMs {
+		h.maxMs = ms
+	}
+}
+
+// percentile estimates the q-th quantile (0..1) by linear interpolation inside
+// the bucket holding it, capped at the largest observed value.
+func (h *latencyHistogram) percentile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+	rank := q * float64(h.count)
+	var seen int64
+	for i, c := range h.counts {
+		if c == 0 || float64(seen+c) < rank {
+			seen += c
+			continue
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = latencyBucketsMs[i-1]
+		}
+		upper := h.maxMs
+		if i < len(latencyBucketsMs) && latencyBucketsMs[i] < upper {
+			upper = latencyBucketsMs[i]
+		}
+		estimate := lower + (upper-lower)*(rank-float64(seen))/float64(c)
+		if estimate > h.maxMs {
+			return h.maxMs
+		}
+		return estimate
+	}
+	return h.maxMs
+}
+
+// LatencyRecorder keeps a latency histogram per route
+type LatencyRecorder struct {
+	mutex  sync.Mutex
+	routes map[string]*latencyHistogram
+}
+
+// NewLatencyRecorder creates an empty LatencyRecorder
+func NewLatencyRecorder() *LatencyRecorder {
+	return &LatencyRecorder{routes: make(map[string]*latencyHistogram)}
+}
+
+// Observe records a request duration for the given route
+func (lr *LatencyRecorder) Observe(route string, d time.Duration) {
+	lr.mutex.Lock()
+	defer lr.mutex.Unlock()
+
+	h, ok := lr.routes[route]
+	if !ok {
+		h = newLatencyHistogram()
+		lr.routes[route] = h
+	}
+	h.observe(float64(d) / float64(time.Millisecond))
+}
+
+// LatencySummary returns p50/p95/p99 per route, slowest p99 first
+func (lr *LatencyRecorder) LatencySummary() []models.RouteLatency {
+	lr.mutex.Lock()
+	defer lr.mutex.Unlock()
+
+	summary := make([]models.RouteLatency, 0, len(lr.routes))
+	for route, h := range lr.routes {
+		summary = append(summary, models.RouteLatency{
+			Route:  route,
+			Count:  h.count,
+			P50Ms:  h.percentile(0.50),
+			P95Ms:  h.percentile(0.95),
+			P99Ms:  h.percentile(0.99),
+			MaxMs:  h.maxMs,
+			MeanMs: h.sumMs / float64(h.count),
+		})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].P99Ms != summary[j].P99Ms {
+			return summary[i].P99Ms > summary[j].P99Ms
+		}
+		return summary[i].Route < summary[j].Route
+	})
+	return summary
+}
+
+// Metrics records the latency of every request under its route template, e.g.
+// "GET /api/v1/provinces/{provinceId}/cases". Requests with all=true are tracked
+// separately because they return whole tables and dominate the tail.
+func Metrics(recorder *LatencyRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			recorder.Observe(routeLabel(r), time.Since(start))
+		})
+	}
+}
+
+// routeLabel names the matched route without path parameters so values such as
+// province IDs do not create one histogram each
+func routeLabel(r *http.Request) string {
+	path := "unmatched"
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			path = tpl
+		}
+	}
+	label := r.Method + " " + path
+	if r.URL.Query().Get("all") == "true" {
+		label += "?all=true"
+	}
+	return label
+}
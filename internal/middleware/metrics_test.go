@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyHistogram_Percentiles(t *testing.T) {
+	h := newLatencyHistogram()
+	for i := 0; i < 98; i++ {
+		h.observe(3)
+	}
+	h.observe(800)
+	h.observe(4000)
+
+	assert.Equal(t, int64(100), h.count)
+	p50 := h.percentile(0.50)
+	assert.True(t, p50 > 2 && p50 <= 5, "p50 = %v", p50)
+	assert.True(t, h.percentile(0.99) > 500, "p99 should reflect the slow tail")
+	assert.Equal(t, 4000.0, h.percentile(1))
+	assert.Equal(t, 4000.0, h.maxMs)
+}
+
+func TestLatencyHistogram_Overflow(t *testing.T) {
+	h := newLatencyHistogram()
+	h.observe(45000)
+
+	p99 := h.percentile(0.99)
+	assert.True(t, p99 > 30000 && p99 <= 45000, "p99 = %v", p99)
+}
+
+func TestLatencyHistogram_Empty(t *testing.T) {
+	assert.Equal(t, 0.0, newLatencyHistogram().percentile(0.5))
+}
+
+func TestMetrics_RecordsRouteTemplate(t *testing.T) {
+	recorder := NewLatencyRecorder()
+	router := mux.NewRouter()
+	router.Use(Metrics(recorder))
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+
+	for _, path := range []string{
+		"/api/v1/provinces/72/cases",
+		"/api/v1/provinces/11/cases",
+		"/api/v1/provinces/72/cases?all=true",
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	summary := recorder.LatencySummary()
+	counts := make(map[string]int64)
+	for _, s := range summary {
+		counts[s.Route] = s.Count
+	}
+	assert.Equal(t, int64(2), counts["GET /api/v1/provinces/{provinceId}/cases"])
+	assert.Equal(t, int64(1), counts["GET /api/v1/provinces/{provinceId}/cases?all=true"])
+}
+
+func TestLatencyRecorder_SortsSlowestFirst(t *testing.T) {
+	recorder := NewLatencyRecorder()
+	recorder.Observe("GET /fast", time.Millisecond)
+	recorder.Observe("GET /slow", 2*time.Second)
+
+	summary := recorder.LatencySummary()
+
+	assert.Equal(t, "GET /slow", summary[0].Route)
+	assert.Equal(t, "GET /fast", summary[1].Route)
+}
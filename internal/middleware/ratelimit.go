@@ -1,15 +1,20 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
 )
 
 // ErrorResponse represents an error response structure
@@ -31,11 +36,20 @@ func writeRateLimitError(w http.ResponseWriter, statusCode int, message string)
 	}
 }
 
+// RateLimitDelayHeader reports, in milliseconds, how long a request over the
+// limit was held in the soft limit band before being served
+const RateLimitDelayHeader = "X-RateLimit-Delay"
+
 // ClientRecord tracks request history for a client
 type ClientRecord struct {
 	requests    []time.Time
 	mutex       sync.RWMutex
 	lastCleanup time.Time
+	// queued counts the client's requests waiting in the soft limit band
+	queued int
+	// cachedCost is the CachedRequestCost of response cache hits not yet
+	// counted as a whole request
+	cachedCost float64
 }
 
 // RateLimiter implements a sliding window rate limiter
@@ -43,21 +57,28 @@ type RateLimiter struct {
 	clients       map[string]*ClientRecord
 	mutex         sync.RWMutex
 	config        config.RateLimitConfig
-	cleanupTicker *time.Ticker
+	clock         clock.Clock
+	cleanupTicker clock.Ticker
 	stopChan      chan struct{}
 }
 
 // NewRateLimiter creates a new rate limiter instance
 func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	return newRateLimiter(cfg, clock.System)
+}
+
+// newRateLimiter creates a rate limiter whose window follows clk
+func newRateLimiter(cfg config.RateLimitConfig, clk clock.Clock) *RateLimiter {
 	rl := &RateLimiter{
 		clients:  make(map[string]*ClientRecord),
 		config:   cfg,
+		clock:    clk,
 		stopChan: make(chan struct{}),
 	}
 
 	// Start background cleanup every 5 minutes
 	if cfg.Enabled {
-		rl.cleanupTicker = time.NewTicker(5 * time.Minute)
+		rl.cleanupTicker = clk.NewTicker(5 * time.Minute)
 		go rl.cleanup()
 	}
 
@@ -76,7 +97,7 @@ func (rl *RateLimiter) Stop() {
 func (rl *RateLimiter) cleanup() {
 	for {
 		select {
-		case <-rl.cleanupTicker.C:
+		case <-rl.cleanupTicker.C():
 			rl.cleanOldClients()
 		case <-rl.stopChan:
 			return
@@ -89,7 +110,7 @@ func (rl *RateLimiter) cleanOldClients() {
 	rl.mutex.Lock()
 	defer rl.mutex.Unlock()
 
-	cutoff := time.Now().Add(-rl.config.WindowSize * 2) // Keep records for 2x window size
+	cutoff := rl.clock.Now().Add(-rl.config.WindowSize * 2) // Keep records for 2x window size
 
 	for clientIP, record := range rl.clients {
 		record.mutex.RLock()
@@ -103,8 +124,10 @@ func (rl *RateLimiter) cleanOldClients() {
 	}
 }
 
-// getClientIP extracts client IP from request
-func (rl *RateLimiter) getClientIP(r *http.Request) string {
+// clientIP returns the address of the client behind any load balancer or
+// proxy, trusting the forwarding headers as sent. ClientResolver falls back
+// to it when no trusted proxies are configured.
+func clientIP(r *http.Request) string {
 	// Check X-Forwarded-For header first (for load balancers/proxies)
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
 		// Take the first IP from the comma-separated list
@@ -137,7 +160,7 @@ func (rl *RateLimiter) isAllowed(clientIP string) (bool, int, time.Duration) {
 	if !exists {
 		client = &ClientRecord{
 			requests:    make([]time.Time, 0),
-			lastCleanup: time.Now(),
+			lastCleanup: rl.clock.Now(),
 		}
 		rl.clients[clientIP] = client
 	}
@@ -146,7 +169,7 @@ func (rl *RateLimiter) isAllowed(clientIP string) (bool, int, time.Duration) {
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
-	now := time.Now()
+	now := rl.clock.Now()
 	windowStart := now.Add(-rl.config.WindowSize)
 
 	// Remove old requests outside the window
@@ -179,7 +202,127 @@ func (rl *RateLimiter) isAllowed(clientIP string) (bool, int, time.Duration) {
 	return true, remaining, 0
 }
 
-// RateLimit returns a middleware that implements rate limiting
+// refundCached uncounts a request that was served from the response cache,
+// except for its CachedRequestCost, which builds up until it makes a whole
+// request. It returns the requests remaining in the window.
+func (rl *RateLimiter) refundCached(clientIP string) int {
+	rl.mutex.RLock()
+	client, exists := rl.clients[clientIP]
+	rl.mutex.RUnlock()
+	if !exists {
+		return rl.config.RequestsPerMinute
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.cachedCost += rl.config.CachedRequestCost
+	if client.cachedCost >= 1 {
+		client.cachedCost--
+	} else if len(client.requests) > 0 {
+		client.requests = client.requests[:len(client.requests)-1]
+	}
+	return rl.config.RequestsPerMinute - len(client.requests)
+}
+
+// cacheRefundWriter refunds the rate limit of a request once its response
+// turns out to come from the response cache, which costs the server next to
+// nothing
+type cacheRefundWriter struct {
+	http.ResponseWriter
+	refund      func() int
+	wroteHeader bool
+}
+
+func (cw *cacheRefundWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if cw.Header().Get(ResponseCacheHeader) == "HIT" {
+			cw.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", cw.refund()))
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cacheRefundWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *cacheRefundWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// enqueue takes one of the client's soft limit slots, reporting false when
+// they are all in use
+func (rl *RateLimiter) enqueue(clientIP string) bool {
+	rl.mutex.RLock()
+	client, exists := rl.clients[clientIP]
+	rl.mutex.RUnlock()
+	if !exists {
+		return false
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if client.queued >= rl.config.SoftLimitRequests {
+		return false
+	}
+	client.queued++
+	return true
+}
+
+// dequeue releases a slot taken by enqueue
+func (rl *RateLimiter) dequeue(clientIP string) {
+	rl.mutex.RLock()
+	client, exists := rl.clients[clientIP]
+	rl.mutex.RUnlock()
+	if !exists {
+		return
+	}
+
+	client.mutex.Lock()
+	client.queued--
+	client.mutex.Unlock()
+}
+
+// waitForSlot holds a request that is over the limit until a slot frees up,
+// as long as that happens within SoftLimitMaxWait and the client has fewer
+// than SoftLimitRequests requests waiting already. It returns the result of
+// the last check and how long the request waited.
+func (rl *RateLimiter) waitForSlot(r *http.Request, clientIP string, resetTime time.Duration) (bool, int, time.Duration, time.Duration) {
+	start := rl.clock.Now()
+	deadline := start.Add(rl.config.SoftLimitMaxWait)
+	if rl.config.SoftLimitRequests <= 0 || resetTime > rl.config.SoftLimitMaxWait || !rl.enqueue(clientIP) {
+		return false, 0, resetTime, 0
+	}
+	defer rl.dequeue(clientIP)
+
+	for {
+		// Other waiting requests may take the slot first, so check again
+		// after at least a millisecond
+		select {
+		case <-rl.clock.After(max(resetTime, time.Millisecond)):
+		case <-r.Context().Done():
+			return false, 0, resetTime, rl.clock.Now().Sub(start)
+		}
+
+		allowed, remaining, reset := rl.isAllowed(clientIP)
+		now := rl.clock.Now()
+		if allowed || now.Add(reset).After(deadline) {
+			return allowed, remaining, reset, now.Sub(start)
+		}
+		resetTime = reset
+	}
+}
+
+// RateLimit returns a middleware that implements rate limiting. Requests are
+// counted against the limit of the most specific matching route, then the
+// limit or tier of their API key, then the global limit. Responses served by
+// a ResponseCache running after it only count for cfg.CachedRequestCost.
+// With cfg.MaxConcurrentRequests, clients also get a 429 while that many of
+// their requests are in flight; such requests do not count against the limit.
 func RateLimit(cfg config.RateLimitConfig) func(http.Handler) http.Handler {
 	if !cfg.Enabled {
 		// Return a no-op middleware if rate limiting is disabled
@@ -187,27 +330,199 @@ func RateLimit(cfg config.RateLimitConfig) func(http.Handler) http.Handler {
 			return next
 		}
 	}
+	return NewRateLimits(cfg).Middleware
+}
+
+// RateLimits is the rate limiting of RateLimit with a configuration that can
+// be replaced while serving and client state that can be inspected
+type RateLimits struct {
+	mu          sync.RWMutex
+	cfg         config.RateLimitConfig
+	clock       clock.Clock
+	policy      *rateLimitPolicy
+	concurrency *concurrencyLimiter
+}
+
+// NewRateLimits creates the rate limiting of cfg
+func NewRateLimits(cfg config.RateLimitConfig) *RateLimits {
+	l := &RateLimits{clock: clock.System}
+	l.Update(cfg)
+	return l
+}
 
-	limiter := NewRateLimiter(cfg)
+// SetClock sets the clock the windows are measured on. Like Update, it
+// starts clients over.
+func (l *RateLimits) SetClock(c clock.Clock) {
+	l.mu.Lock()
+	l.clock = c
+	cfg := l.cfg
+	l.mu.Unlock()
+	l.Update(cfg)
+}
+
+// Update replaces the configuration. Clients start over with no requests
+// counted under the new limits.
+func (l *RateLimits) Update(cfg config.RateLimitConfig) {
+	l.mu.RLock()
+	clk := l.clock
+	l.mu.RUnlock()
 
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			clientIP := limiter.getClientIP(r)
-			allowed, remaining, resetTime := limiter.isAllowed(clientIP)
+	var policy *rateLimitPolicy
+	var concurrency *concurrencyLimiter
+	if cfg.Enabled {
+		policy = newRateLimitPolicy(cfg, clk)
+		if cfg.MaxConcurrentRequests > 0 {
+			concurrency = newConcurrencyLimiter(cfg.MaxConcurrentRequests)
+		}
+	}
 
-			// Set rate limiting headers
-			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", cfg.RequestsPerMinute))
-			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+	l.mu.Lock()
+	previous := l.policy
+	l.cfg, l.policy, l.concurrency = cfg, policy, concurrency
+	l.mu.Unlock()
+	if previous != nil {
+		previous.stop()
+	}
+}
 
-			if !allowed {
-				w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(resetTime).Unix()))
-				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(resetTime.Seconds())))
+// Middleware limits the requests passing through it
+func (l *RateLimits) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.mu.RLock()
+		cfg, policy, concurrency := l.cfg, l.policy, l.concurrency
+		l.mu.RUnlock()
+		if !cfg.Enabled || isRateLimitExempt(cfg, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-				writeRateLimitError(w, http.StatusTooManyRequests, "Rate limit exceeded. Too many requests.")
+		limiter, clientIP := policy.limiterFor(r)
+		// Streams stay open for as long as the client listens, so they
+		// do not hold a slot
+		if concurrency != nil && !isStream(r) {
+			if !concurrency.acquire(clientIP) {
+				w.Header().Set(ConcurrencyLimitHeader, fmt.Sprintf("%d", cfg.MaxConcurrentRequests))
+				w.Header().Set("Retry-After", "1")
+				writeRateLimitError(w, http.StatusTooManyRequests, "Too many concurrent requests. Wait for a response before sending more.")
 				return
 			}
+			defer concurrency.release(clientIP)
+		}
 
-			next.ServeHTTP(w, r)
+		allowed, remaining, resetTime := limiter.isAllowed(clientIP)
+		if !allowed {
+			var waited time.Duration
+			allowed, remaining, resetTime, waited = limiter.waitForSlot(r, clientIP, resetTime)
+			if waited > 0 {
+				w.Header().Set(RateLimitDelayHeader, fmt.Sprintf("%d", waited.Milliseconds()))
+			}
+		}
+
+		// Set rate limiting headers
+		w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.config.RequestsPerMinute))
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+
+		if !allowed {
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", limiter.clock.Now().Add(resetTime).Unix()))
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(resetTime.Seconds())))
+
+			writeRateLimitError(w, http.StatusTooManyRequests, "Rate limit exceeded. Too many requests.")
+			return
+		}
+
+		if limiter.config.CachedRequestCost < 1 {
+			w = &cacheRefundWriter{ResponseWriter: w, refund: func() int {
+				return limiter.refundCached(clientIP)
+			}}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Allow counts a call that does not come over HTTP, such as a gRPC call,
+// against the limit or tier of the API key in ctx or else the global limit.
+// Calls with a key share its budget with the key's HTTP requests; others are
+// counted per client. When the call is not allowed, it also returns how long
+// until the window has room again.
+func (l *RateLimits) Allow(ctx context.Context, client string) (bool, time.Duration) {
+	l.mu.RLock()
+	cfg, policy := l.cfg, l.policy
+	l.mu.RUnlock()
+	if !cfg.Enabled {
+		return true, 0
+	}
+
+	limiter, client := policy.limiterForCall(ctx, client)
+	allowed, _, resetTime := limiter.isAllowed(client)
+	return allowed, resetTime
+}
+
+// Snapshot returns every limit with the clients it currently counts
+func (l *RateLimits) Snapshot() models.RateLimitSnapshot {
+	l.mu.RLock()
+	cfg, policy, concurrency := l.cfg, l.policy, l.concurrency
+	l.mu.RUnlock()
+
+	snapshot := models.RateLimitSnapshot{Enabled: cfg.Enabled, Limits: []models.RateLimitState{}}
+	if policy == nil {
+		return snapshot
+	}
+	if concurrency != nil {
+		snapshot.MaxConcurrentRequests = cfg.MaxConcurrentRequests
+		snapshot.InFlight = concurrency.snapshot()
+	}
+	snapshot.Limits = policy.snapshot()
+	return snapshot
+}
+
+// snapshot returns the clients with requests in the current window, most
+// requests first
+func (rl *RateLimiter) snapshot(scope string) models.RateLimitState {
+	state := models.RateLimitState{
+		Scope:             scope,
+		RequestsPerMinute: rl.config.RequestsPerMinute,
+		WindowSeconds:     int(rl.config.WindowSize.Seconds()),
+		Clients:           []models.RateLimitClient{},
+	}
+	windowStart := rl.clock.Now().Add(-rl.config.WindowSize)
+
+	rl.mutex.RLock()
+	defer rl.mutex.RUnlock()
+	for clientIP, client := range rl.clients {
+		client.mutex.RLock()
+		requests := 0
+		for _, reqTime := range client.requests {
+			if reqTime.After(windowStart) {
+				requests++
+			}
+		}
+		queued := client.queued
+		client.mutex.RUnlock()
+		if requests == 0 && queued == 0 {
+			continue
+		}
+		state.Clients = append(state.Clients, models.RateLimitClient{
+			Client:    clientIP,
+			Requests:  requests,
+			Remaining: max(rl.config.RequestsPerMinute-requests, 0),
+			Queued:    queued,
 		})
 	}
+	sort.Slice(state.Clients, func(i, j int) bool {
+		if state.Clients[i].Requests != state.Clients[j].Requests {
+			return state.Clients[i].Requests > state.Clients[j].Requests
+		}
+		return state.Clients[i].Client < state.Clients[j].Client
+	})
+	return state
+}
+
+// isRateLimitExempt reports whether path starts with one of the exempt prefixes
+func isRateLimitExempt(cfg config.RateLimitConfig, path string) bool {
+	for _, prefix := range cfg.ExemptPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
 }
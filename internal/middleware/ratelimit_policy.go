@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// routeLimit is a limit for the requests matching a route pattern
+type routeLimit struct {
+	routePattern
+	pattern string
+	limiter *RateLimiter
+}
+
+// rateLimitPolicy picks the limit a request is counted against
+type rateLimitPolicy struct {
+	cfg     config.RateLimitConfig
+	clock   clock.Clock
+	global  *RateLimiter
+	routes  []routeLimit
+	tiers   map[string]int
+	clients *ClientResolver
+
+	mu sync.Mutex
+	// byLimit holds the limiters of API keys, shared by keys with the same
+	// limit since they are counted by key
+	byLimit map[int]*RateLimiter
+}
+
+// newRateLimitPolicy builds the policy of cfg. Invalid route, tier and proxy
+// entries are logged and ignored.
+func newRateLimitPolicy(cfg config.RateLimitConfig, clk clock.Clock) *rateLimitPolicy {
+	p := &rateLimitPolicy{
+		cfg:     cfg,
+		clock:   clk,
+		global:  newRateLimiter(cfg, clk),
+		tiers:   make(map[string]int),
+		byLimit: make(map[int]*RateLimiter),
+		clients: NewClientResolver(cfg.TrustedProxies),
+	}
+	for _, def := range cfg.RouteLimits {
+		pattern, limit, err := splitLimit(def)
+		if err != nil {
+			log.Printf("Ignoring rate limit route: %v", err)
+			continue
+		}
+		route, err := parseRoutePattern(pattern)
+		if err != nil {
+			log.Printf("Ignoring rate limit route %q: %v", def, err)
+			continue
+		}
+		p.routes = append(p.routes, routeLimit{routePattern: route, pattern: pattern, limiter: p.newLimiter(limit)})
+	}
+	// The most specific route wins
+	sort.SliceStable(p.routes, func(i, j int) bool {
+		return p.routes[i].moreSpecific(p.routes[j].routePattern)
+	})
+	for _, def := range cfg.Tiers {
+		name, limit, err := splitLimit(def)
+		if err != nil {
+			log.Printf("Ignoring rate limit tier: %v", err)
+			continue
+		}
+		p.tiers[name] = limit
+	}
+	return p
+}
+
+// splitLimit splits a "name:requests_per_minute" definition at its last colon
+func splitLimit(def string) (string, int, error) {
+	i := strings.LastIndex(def, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("%q must be name:requests_per_minute", def)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(def[i+1:]))
+	if err != nil || limit <= 0 {
+		return "", 0, fmt.Errorf("%q has an invalid requests per minute", def)
+	}
+	return strings.TrimSpace(def[:i]), limit, nil
+}
+
+// newLimiter returns a limiter with the settings of the global one but its
+// own requests per minute
+func (p *rateLimitPolicy) newLimiter(limit int) *RateLimiter {
+	cfg := p.cfg
+	cfg.RequestsPerMinute = limit
+	return newRateLimiter(cfg, p.clock)
+}
+
+// limiterFor returns the limiter of the request and the client it is counted
+// as. The most specific matching route wins, then the limit or tier of the
+// request's API key, then the global limit. Requests with an API key are
+// counted per key, others per client IP.
+func (p *rateLimitPolicy) limiterFor(r *http.Request) (*RateLimiter, string) {
+	client := p.clients.ClientIP(r)
+	key, hasKey := APIKeyFromContext(r.Context())
+	if hasKey {
+		client = "key:" + key.KeyHash
+	}
+	for _, route := range p.routes {
+		if route.matches(r) {
+			return route.limiter, client
+		}
+	}
+	return p.keyLimiter(key), client
+}
+
+// limiterForCall is limiterFor for a call that has no route, counted per API
+// key when ctx carries one and per client otherwise
+func (p *rateLimitPolicy) limiterForCall(ctx context.Context, client string) (*RateLimiter, string) {
+	key, hasKey := APIKeyFromContext(ctx)
+	if hasKey {
+		client = "key:" + key.KeyHash
+	}
+	return p.keyLimiter(key), client
+}
+
+// keyLimiter returns the limiter of the limit or tier of key, or the global
+// limiter when there is no key or it has neither
+func (p *rateLimitPolicy) keyLimiter(key *models.APIKey) *RateLimiter {
+	if key == nil {
+		return p.global
+	}
+	limit := key.RequestsPerMinute
+	if limit <= 0 {
+		limit = p.tiers[key.Tier]
+	}
+	if limit <= 0 {
+		return p.global
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	limiter, ok := p.byLimit[limit]
+	if !ok {
+		limiter = p.newLimiter(limit)
+		p.byLimit[limit] = limiter
+	}
+	return limiter
+}
+
+// snapshot returns the state of the global limit, the route limits and the
+// limits of API keys
+func (p *rateLimitPolicy) snapshot() []models.RateLimitState {
+	states := []models.RateLimitState{p.global.snapshot("global")}
+	for _, route := range p.routes {
+		states = append(states, route.limiter.snapshot(route.pattern))
+	}
+	p.mu.Lock()
+	limits := make([]int, 0, len(p.byLimit))
+	for limit := range p.byLimit {
+		limits = append(limits, limit)
+	}
+	sort.Ints(limits)
+	for _, limit := range limits {
+		states = append(states, p.byLimit[limit].snapshot("api_key"))
+	}
+	p.mu.Unlock()
+	return states
+}
+
+// stop stops the cleanup of every limiter of the policy
+func (p *rateLimitPolicy) stop() {
+	p.global.Stop()
+	for _, route := range p.routes {
+		route.limiter.Stop()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, limiter := range p.byLimit {
+		limiter.Stop()
+	}
+}
@@ -1,14 +1,20 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRateLimit_Disabled(t *testing.T) {
@@ -111,6 +117,42 @@ func TestRateLimit_ExceedsLimit(t *testing.T) {
 	assert.Contains(t, response.Error, "Rate limit exceeded")
 }
 
+func TestRateLimit_ExemptPaths(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled:            true,
+		RequestsPerMinute:  1,
+		BurstSize:          1,
+		WindowSize:         time.Minute,
+		ExemptPathPrefixes: []string{"/api/v1/embed/"},
+	}
+
+	handler := RateLimit(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest("GET", "/api/v1/embed/summary.html", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("X-RateLimit-Limit"))
+	}
+
+	// Other paths are still limited for the same client
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest("GET", "/api/v1/national", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Code, "request %d", i)
+	}
+}
+
 func TestRateLimit_DifferentClients(t *testing.T) {
 	cfg := config.RateLimitConfig{
 		Enabled:           true,
@@ -260,9 +302,137 @@ func TestRateLimit_SlidingWindow(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
-func TestRateLimiter_GetClientIP(t *testing.T) {
-	limiter := NewRateLimiter(config.RateLimitConfig{})
+func TestRateLimit_SoftLimitDelaysInsteadOfRejecting(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 2,
+		WindowSize:        200 * time.Millisecond,
+		SoftLimitRequests: 1,
+		SoftLimitMaxWait:  time.Second,
+	}
+
+	handler := RateLimit(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i := 0; i < 2; i++ {
+		rr := serve()
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get(RateLimitDelayHeader))
+	}
+
+	start := time.Now()
+	rr := serve()
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.NotEmpty(t, rr.Header().Get(RateLimitDelayHeader))
+}
+
+func TestRateLimit_SoftLimitRejectsBeyondBand(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 1,
+		WindowSize:        time.Minute,
+		SoftLimitRequests: 1,
+		SoftLimitMaxWait:  time.Second,
+	}
+
+	handler := RateLimit(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "192.168.1.1:12345"
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// The window does not free a slot within SoftLimitMaxWait, so there is
+	// no point in waiting
+	start := time.Now()
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Empty(t, rr.Header().Get(RateLimitDelayHeader))
+}
+
+func TestRateLimiter_SoftLimitSlots(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{RequestsPerMinute: 1, WindowSize: time.Minute, SoftLimitRequests: 1})
+	defer limiter.Stop()
+
+	assert.False(t, limiter.enqueue("10.0.0.1"), "unknown clients have no record")
+	limiter.isAllowed("10.0.0.1")
+	assert.True(t, limiter.enqueue("10.0.0.1"))
+	assert.False(t, limiter.enqueue("10.0.0.1"))
+	limiter.dequeue("10.0.0.1")
+	assert.True(t, limiter.enqueue("10.0.0.1"))
+}
+
+func TestRateLimits_WindowFollowsClock(t *testing.T) {
+	clk := clock.NewMock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	limits := NewRateLimits(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 2, WindowSize: time.Minute})
+	limits.SetClock(clk)
+	handler := limits.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+	clk.Advance(30 * time.Second)
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	rr := request()
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+	assert.Equal(t, fmt.Sprintf("%d", clk.Now().Add(30*time.Second).Unix()), rr.Header().Get("X-RateLimit-Reset"))
 
+	clk.Advance(30 * time.Second)
+	assert.Equal(t, http.StatusOK, request().Code, "the first request left the window")
+	assert.Equal(t, http.StatusTooManyRequests, request().Code)
+}
+
+func TestRateLimiter_WaitForSlot_Clock(t *testing.T) {
+	clk := clock.NewMock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := newRateLimiter(config.RateLimitConfig{RequestsPerMinute: 1, WindowSize: time.Minute, SoftLimitRequests: 1, SoftLimitMaxWait: 10 * time.Second}, clk)
+	defer limiter.Stop()
+
+	allowed, _, reset := limiter.isAllowed("10.0.0.1")
+	require.True(t, allowed)
+	clk.Advance(55 * time.Second)
+	allowed, _, reset = limiter.isAllowed("10.0.0.1")
+	require.False(t, allowed)
+	require.Equal(t, 5*time.Second, reset)
+
+	done := make(chan time.Duration)
+	go func() {
+		allowed, _, _, waited := limiter.waitForSlot(httptest.NewRequest("GET", "/test", nil), "10.0.0.1", reset)
+		assert.True(t, allowed)
+		done <- waited
+	}()
+	// The wait registers its timer on the mock clock before it can fire
+	for clk.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(5 * time.Second)
+	assert.Equal(t, 5*time.Second, <-done)
+}
+
+func TestClientIP(t *testing.T) {
 	tests := []struct {
 		name         string
 		setupRequest func(*http.Request)
@@ -305,7 +475,7 @@ func TestRateLimiter_GetClientIP(t *testing.T) {
 			req := httptest.NewRequest("GET", "/test", nil)
 			tt.setupRequest(req)
 
-			ip := limiter.getClientIP(req)
+			ip := clientIP(req)
 			assert.Equal(t, tt.expectedIP, ip)
 		})
 	}
@@ -388,3 +558,228 @@ func BenchmarkRateLimit_Reject(b *testing.B) {
 		}
 	})
 }
+
+func newPolicyTestHandler(cfg config.RateLimitConfig) http.Handler {
+	return RateLimit(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// requestCodes sends n requests built by newRequest and returns their status codes
+func requestCodes(handler http.Handler, n int, newRequest func() *http.Request) []int {
+	codes := make([]int, n)
+	for i := range codes {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest())
+		codes[i] = rr.Code
+	}
+	return codes
+}
+
+func TestRateLimit_RouteLimits(t *testing.T) {
+	handler := newPolicyTestHandler(config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 2,
+		WindowSize:        time.Minute,
+		RouteLimits: []string{
+			"/api/v1/health:5",
+			"/api/v1/provinces/*/cases?all=true:1",
+			"/api/v1/provinces:3",
+		},
+	})
+	get := func(target string) func() *http.Request {
+		return func() *http.Request {
+			req := httptest.NewRequest("GET", target, nil)
+			req.RemoteAddr = "192.168.1.1:12345"
+			return req
+		}
+	}
+
+	assert.Equal(t, []int{200, 200, 200, 200, 200, 429}, requestCodes(handler, 6, get("/api/v1/health")))
+	assert.Equal(t, []int{200, 429}, requestCodes(handler, 2, get("/api/v1/provinces/72/cases?all=true")))
+	// The broader route has its own budget
+	assert.Equal(t, []int{200, 200, 200, 429}, requestCodes(handler, 4, get("/api/v1/provinces/72/cases")))
+	assert.Equal(t, []int{200, 200, 429}, requestCodes(handler, 3, get("/api/v1/national")))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, get("/api/v1/health?x=1")())
+	assert.Equal(t, "5", rr.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimit_APIKeyLimits(t *testing.T) {
+	handler := newPolicyTestHandler(config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 1,
+		WindowSize:        time.Minute,
+		Tiers:             []string{"premium:3"},
+	})
+	withKey := func(key models.APIKey, remoteAddr string) func() *http.Request {
+		return func() *http.Request {
+			req := httptest.NewRequest("GET", "/api/v1/national", nil)
+			req.RemoteAddr = remoteAddr
+			return req.WithContext(context.WithValue(req.Context(), apiKeyContextKey{}, &key))
+		}
+	}
+	own := models.APIKey{KeyHash: "own", RequestsPerMinute: 2}
+	tiered := models.APIKey{KeyHash: "tiered", Tier: "premium"}
+	untiered := models.APIKey{KeyHash: "untiered", Tier: "unknown"}
+
+	assert.Equal(t, []int{200, 200, 429}, requestCodes(handler, 3, withKey(own, "10.0.0.1:1")))
+	// Keys are counted per key, whichever client uses them
+	assert.Equal(t, []int{429}, requestCodes(handler, 1, withKey(own, "10.0.0.2:1")))
+	assert.Equal(t, []int{200, 200, 200, 429}, requestCodes(handler, 4, withKey(tiered, "10.0.0.3:1")))
+	assert.Equal(t, []int{200, 429}, requestCodes(handler, 2, withKey(untiered, "10.0.0.4:1")))
+}
+
+func TestRateLimit_CachedRequestCost(t *testing.T) {
+	newHandler := func(cost float64) http.Handler {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status":"success"}`))
+		})
+		cached := ResponseCache(config.ResponseCacheConfig{
+			Policies:     []string{"/api/v1/national/latest:5m"},
+			ServeEnabled: true,
+		})(handler)
+		return RateLimit(config.RateLimitConfig{
+			Enabled:           true,
+			RequestsPerMinute: 3,
+			WindowSize:        time.Minute,
+			CachedRequestCost: cost,
+		})(cached)
+	}
+	get := func(target string) func() *http.Request {
+		return func() *http.Request {
+			req := httptest.NewRequest("GET", target, nil)
+			req.RemoteAddr = "192.168.1.1:12345"
+			return req
+		}
+	}
+
+	// Cache hits are free; only the first, uncached response counts
+	handler := newHandler(0)
+	assert.Equal(t, []int{200, 200, 200, 200, 200, 200}, requestCodes(handler, 6, get("/api/v1/national/latest")))
+	assert.Equal(t, []int{200, 200, 429}, requestCodes(handler, 3, get("/api/v1/national")))
+
+	// Every second hit counts as a whole request
+	handler = newHandler(0.5)
+	assert.Equal(t, []int{200, 200, 200, 200, 200, 429}, requestCodes(handler, 6, get("/api/v1/national/latest")))
+
+	// Hits count in full
+	handler = newHandler(1)
+	assert.Equal(t, []int{200, 200, 200, 429}, requestCodes(handler, 4, get("/api/v1/national/latest")))
+}
+
+func TestRateLimit_CachedRequestRemainingHeader(t *testing.T) {
+	handler := RateLimit(config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 5,
+		WindowSize:        time.Minute,
+	})(ResponseCache(config.ResponseCacheConfig{
+		Policies:     []string{"/api/v1/national/latest:5m"},
+		ServeEnabled: true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	})))
+
+	for i, want := range []string{"4", "4", "4"} {
+		req := httptest.NewRequest("GET", "/api/v1/national/latest", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, want, rr.Header().Get("X-RateLimit-Remaining"), "request %d", i)
+	}
+}
+
+func TestRateLimit_MaxConcurrentRequests(t *testing.T) {
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	handler := RateLimit(config.RateLimitConfig{
+		Enabled:               true,
+		RequestsPerMinute:     100,
+		WindowSize:            time.Minute,
+		MaxConcurrentRequests: 2,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") == "true" {
+			entered <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(target, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve("/api/v1/national?block=true", "192.168.1.1:12345")
+		}()
+		<-entered
+	}
+
+	rr := serve("/api/v1/national", "192.168.1.1:12345")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get(ConcurrencyLimitHeader))
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	// Other clients and streams are not held back
+	assert.Equal(t, http.StatusOK, serve("/api/v1/national", "192.168.1.2:12345").Code)
+	assert.Equal(t, http.StatusOK, serve("/api/v1/stream/cases", "192.168.1.1:12345").Code)
+
+	close(release)
+	wg.Wait()
+	rr = serve("/api/v1/national", "192.168.1.1:12345")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// The rejected request was not counted: 2 blocked, the stream and this one
+	assert.Equal(t, "96", rr.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestRateLimits_SnapshotAndUpdate(t *testing.T) {
+	limits := NewRateLimits(config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerMinute: 5,
+		WindowSize:        time.Minute,
+		RouteLimits:       []string{"/api/v1/export:2"},
+	})
+	handler := limits.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(target, remoteAddr string) int {
+		req := httptest.NewRequest("GET", target, nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	serve("/api/v1/national", "192.168.1.1:12345")
+	serve("/api/v1/national", "192.168.1.2:12345")
+	serve("/api/v1/national", "192.168.1.2:12345")
+	serve("/api/v1/export/xlsx", "192.168.1.1:12345")
+
+	snapshot := limits.Snapshot()
+	assert.True(t, snapshot.Enabled)
+	require.Len(t, snapshot.Limits, 2)
+	assert.Equal(t, models.RateLimitState{
+		Scope: "global", RequestsPerMinute: 5, WindowSeconds: 60,
+		Clients: []models.RateLimitClient{
+			{Client: "192.168.1.2", Requests: 2, Remaining: 3},
+			{Client: "192.168.1.1", Requests: 1, Remaining: 4},
+		},
+	}, snapshot.Limits[0])
+	assert.Equal(t, "/api/v1/export", snapshot.Limits[1].Scope)
+	assert.Equal(t, []models.RateLimitClient{{Client: "192.168.1.1", Requests: 1, Remaining: 1}}, snapshot.Limits[1].Clients)
+
+	// A stricter limit starts counting over
+	limits.Update(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1, WindowSize: time.Minute})
+	assert.Equal(t, http.StatusOK, serve("/api/v1/national", "192.168.1.2:12345"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/national", "192.168.1.2:12345"))
+
+	limits.Update(config.RateLimitConfig{Enabled: false})
+	assert.Equal(t, http.StatusOK, serve("/api/v1/national", "192.168.1.2:12345"))
+	assert.Equal(t, models.RateLimitSnapshot{Limits: []models.RateLimitState{}}, limits.Snapshot())
+}
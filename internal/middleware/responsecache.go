@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/pkg/cache"
+)
+
+// ResponseCacheHeader tells whether a response was served from the response
+// cache (HIT) or generated and stored in it (MISS)
+const ResponseCacheHeader = "X-Response-Cache"
+
+// cachePolicy is the max age of the responses to requests matching a route
+// pattern
+type cachePolicy struct {
+	routePattern
+	maxAge time.Duration
+}
+
+// cachedResponse is a stored successful response
+type cachedResponse struct {
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+}
+
+// cacheHeaderWriter sets the policy's caching headers on 200 responses
+// whose handler did not set Cache-Control itself
+type cacheHeaderWriter struct {
+	http.ResponseWriter
+	maxAge      time.Duration
+	private     bool
+	wroteHeader bool
+	// own is set when the handler chose its own Cache-Control
+	own bool
+}
+
+func (cw *cacheHeaderWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if code == http.StatusOK {
+			if cw.Header().Get("Cache-Control") != "" {
+				cw.own = true
+			} else {
+				setCacheHeaders(cw.Header(), cw.maxAge, cw.private, time.Now().Add(cw.maxAge))
+			}
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cacheHeaderWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *cacheHeaderWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// setCacheHeaders sets Cache-Control and Expires for a response that is
+// fresh for maxAge, until expiresAt. Responses to requests with an API key
+// are private so shared caches do not hand them to clients without one.
+func setCacheHeaders(header http.Header, maxAge time.Duration, private bool, expiresAt time.Time) {
+	if maxAge <= 0 {
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Expires", "0")
+		return
+	}
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	header.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(math.Ceil(maxAge.Seconds()))))
+	header.Set("Expires", expiresAt.UTC().Format(http.TimeFormat))
+}
+
+// ResponseCache sets Cache-Control and Expires on successful GET and HEAD
+// responses by the most specific policy of cfg matching the request, so that
+// browsers and CDNs keep historical data for hours and the latest data for
+// minutes. With cfg.ServeEnabled, GET responses are also kept in memory for
+// their max age, keyed by the full URL, and served to later requests without
+// calling the handler. Invalid policies are logged and ignored.
+func ResponseCache(cfg config.ResponseCacheConfig) func(http.Handler) http.Handler {
+	return NewCachedResponses(cfg).Middleware
+}
+
+// CachedResponses is the response caching of ResponseCache with stored
+// responses that can be cleared when the data changes
+type CachedResponses struct {
+	policies []cachePolicy
+	// responses is nil unless cfg.ServeEnabled
+	responses *cache.Cache
+}
+
+// NewCachedResponses creates the response caching of cfg
+func NewCachedResponses(cfg config.ResponseCacheConfig) *CachedResponses {
+	c := &CachedResponses{}
+	for _, def := range cfg.Policies {
+		policy, err := parseCachePolicy(def)
+		if err != nil {
+			log.Printf("Ignoring response cache policy: %v", err)
+			continue
+		}
+		c.policies = append(c.policies, policy)
+	}
+	sort.SliceStable(c.policies, func(i, j int) bool {
+		return c.policies[i].moreSpecific(c.policies[j].routePattern)
+	})
+	if cfg.ServeEnabled && len(c.policies) > 0 {
+		c.responses = cache.New(time.Minute)
+		c.responses.StartCleanup(time.Minute)
+	}
+	return c
+}
+
+// Clear drops the stored responses, so changed data is served at once
+// instead of once they expire. It implements service.CacheInvalidator.
+func (c *CachedResponses) Clear() {
+	if c.responses != nil {
+		c.responses.Clear()
+	}
+}
+
+// Middleware sets the caching headers and serves stored responses
+func (c *CachedResponses) Middleware(next http.Handler) http.Handler {
+	if len(c.policies) == 0 {
+		return next
+	}
+	responses := c.responses
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method != http.MethodGet && r.Method != http.MethodHead) || isStream(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var policy *cachePolicy
+		for i := range c.policies {
+			if c.policies[i].matches(r) {
+				policy = &c.policies[i]
+				break
+			}
+		}
+		if policy == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		private := r.Header.Get(APIKeyHeader) != ""
+		cw := &cacheHeaderWriter{ResponseWriter: w, maxAge: policy.maxAge, private: private}
+		if responses == nil || r.Method != http.MethodGet || policy.maxAge <= 0 {
+			next.ServeHTTP(cw, r)
+			return
+		}
+
+		// Tenants share paths and Accept selects CSV over JSON, so both are part of the key
+		key := r.Host + "|" + r.Header.Get("X-Tenant") + "|" + r.Header.Get("Accept") + "|" + r.URL.RequestURI()
+		if v, ok := responses.Get(key); ok {
+			stored := v.(cachedResponse)
+			for name, values := range stored.header {
+				w.Header()[name] = values
+			}
+			setCacheHeaders(w.Header(), time.Until(stored.expiresAt), private, stored.expiresAt)
+			w.Header().Set(ResponseCacheHeader, "HIT")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(stored.body)
+			return
+		}
+
+		w.Header().Set(ResponseCacheHeader, "MISS")
+		expiresAt := time.Now().Add(policy.maxAge)
+		sw := &snapshotWriter{ResponseWriter: cw, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		if sw.status != http.StatusOK || sw.overflow || cw.own {
+			return
+		}
+		header := w.Header().Clone()
+		for _, name := range perRequestHeaders {
+			header.Del(name)
+		}
+		responses.Set(key, cachedResponse{header: header, body: bytes.Clone(sw.body.Bytes()), expiresAt: expiresAt}, policy.maxAge)
+	})
+}
+
+// parseCachePolicy parses a "path[?query]:max_age" definition, split at its
+// last colon
+func parseCachePolicy(def string) (cachePolicy, error) {
+	i := strings.LastIndex(def, ":")
+	if i <= 0 {
+		return cachePolicy{}, fmt.Errorf("%q must be path:max_age", def)
+	}
+	maxAge, err := time.ParseDuration(strings.TrimSpace(def[i+1:]))
+	if err != nil || maxAge < 0 {
+		return cachePolicy{}, fmt.Errorf("%q has an invalid max age", def)
+	}
+	pattern, err := parseRoutePattern(strings.TrimSpace(def[:i]))
+	if err != nil {
+		return cachePolicy{}, fmt.Errorf("%q: %w", def, err)
+	}
+	return cachePolicy{routePattern: pattern, maxAge: maxAge}, nil
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCachePolicies = []string{
+	"/api/v1/national:1h", "/api/v1/national/latest:5m", "/api/v1/provinces/*/cases?all=true:2h",
+	"/api/v1/provinces/latest:0", "invalid", "/api/v1/regencies:soon",
+}
+
+func newResponseCacheTestHandler(calls *atomic.Int32, serve bool, status int) http.Handler {
+	cfg := config.ResponseCacheConfig{Policies: testCachePolicies, ServeEnabled: serve}
+	return ResponseCache(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/api/v1/national/own" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+}
+
+func cacheRequest(h http.Handler, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCache_Headers(t *testing.T) {
+	var calls atomic.Int32
+	h := newResponseCacheTestHandler(&calls, false, http.StatusOK)
+
+	tests := []struct {
+		target       string
+		cacheControl string
+	}{
+		{"/api/v1/national?page=2", "public, max-age=3600"},
+		{"/api/v1/national/latest", "public, max-age=300"},
+		{"/api/v1/provinces/72/cases?all=true", "public, max-age=7200"},
+		{"/api/v1/provinces/72/cases", ""},
+		{"/api/v1/provinces/latest", "no-cache"},
+		{"/api/v1/regencies", ""},
+		{"/api/v1/national/own", "no-store"},
+	}
+	for _, tt := range tests {
+		w := cacheRequest(h, tt.target, nil)
+		assert.Equal(t, tt.cacheControl, w.Header().Get("Cache-Control"), tt.target)
+		assert.Empty(t, w.Header().Get(ResponseCacheHeader), tt.target)
+	}
+
+	w := cacheRequest(h, "/api/v1/national", nil)
+	expires, err := http.ParseTime(w.Header().Get("Expires"))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expires, 2*time.Second)
+
+	w = cacheRequest(h, "/api/v1/national", map[string]string{APIKeyHeader: "secret"})
+	assert.Equal(t, "private, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.Equal(t, int32(len(tests)+2), calls.Load())
+}
+
+func TestResponseCache_SkipsErrors(t *testing.T) {
+	var calls atomic.Int32
+	h := newResponseCacheTestHandler(&calls, true, http.StatusInternalServerError)
+
+	first := cacheRequest(h, "/api/v1/national", nil)
+	assert.Empty(t, first.Header().Get("Cache-Control"))
+	second := cacheRequest(h, "/api/v1/national", nil)
+	assert.Equal(t, "MISS", second.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestResponseCache_Serve(t *testing.T) {
+	var calls atomic.Int32
+	h := newResponseCacheTestHandler(&calls, true, http.StatusOK)
+
+	first := cacheRequest(h, "/api/v1/national?sort=date:desc", nil)
+	assert.Equal(t, "MISS", first.Header().Get(ResponseCacheHeader))
+
+	second := cacheRequest(h, "/api/v1/national?sort=date:desc", nil)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", second.Header().Get("Cache-Control"))
+	assert.Equal(t, first.Header().Get("Expires"), second.Header().Get("Expires"))
+	assert.Equal(t, `{"status":"success"}`, second.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+
+	// The full query string, Accept and tenant are part of the key
+	assert.Equal(t, "MISS", cacheRequest(h, "/api/v1/national?sort=date:asc", nil).Header().Get(ResponseCacheHeader))
+	assert.Equal(t, "MISS", cacheRequest(h, "/api/v1/national?sort=date:desc", map[string]string{"Accept": "text/csv"}).Header().Get(ResponseCacheHeader))
+	assert.Equal(t, "MISS", cacheRequest(h, "/api/v1/national?sort=date:desc", map[string]string{"X-Tenant": "sulteng"}).Header().Get(ResponseCacheHeader))
+
+	// Responses with their own Cache-Control and no-cache policies are not kept
+	cacheRequest(h, "/api/v1/national/own", nil)
+	assert.Equal(t, "MISS", cacheRequest(h, "/api/v1/national/own", nil).Header().Get(ResponseCacheHeader))
+	cacheRequest(h, "/api/v1/provinces/latest", nil)
+	assert.Empty(t, cacheRequest(h, "/api/v1/provinces/latest", nil).Header().Get(ResponseCacheHeader))
+	assert.Equal(t, int32(8), calls.Load())
+}
+
+func TestCachedResponses_Clear(t *testing.T) {
+	var calls atomic.Int32
+	responses := NewCachedResponses(config.ResponseCacheConfig{Policies: testCachePolicies, ServeEnabled: true})
+	h := responses.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	cacheRequest(h, "/api/v1/national", nil)
+	assert.Equal(t, "HIT", cacheRequest(h, "/api/v1/national", nil).Header().Get(ResponseCacheHeader))
+	responses.Clear()
+	assert.Equal(t, "MISS", cacheRequest(h, "/api/v1/national", nil).Header().Get(ResponseCacheHeader))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Clearing without ServeEnabled does nothing
+	NewCachedResponses(config.ResponseCacheConfig{Policies: testCachePolicies}).Clear()
+}
+
+func TestResponseCache_ExactPathsAndAnyValue(t *testing.T) {
+	cfg := config.ResponseCacheConfig{Policies: []string{
+		"/api/v1/national:5m", "/api/v1/national$?end_date=*:1h", "/api/v1/provinces/*/cases?end_date=*:2h",
+	}}
+	h := ResponseCache(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		target       string
+		cacheControl string
+	}{
+		{"/api/v1/national", "public, max-age=300"},
+		{"/api/v1/national?end_date=2021-01-31&start_date=2021-01-01", "public, max-age=3600"},
+		{"/api/v1/national?end_date=", "public, max-age=300"},
+		{"/api/v1/national/compare-periods?end_date=2021-01-31", "public, max-age=300"},
+		{"/api/v1/provinces/72/cases?end_date=2021-01-31", "public, max-age=7200"},
+		{"/api/v1/provinces/72/cases", ""},
+	}
+	for _, tt := range tests {
+		w := cacheRequest(h, tt.target, nil)
+		assert.Equal(t, tt.cacheControl, w.Header().Get("Cache-Control"), tt.target)
+	}
+}
+
+func TestResponseCache_NoPolicies(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := ResponseCache(config.ResponseCacheConfig{ServeEnabled: true})(next)
+
+	w := cacheRequest(h, "/api/v1/national", nil)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get(ResponseCacheHeader))
+}
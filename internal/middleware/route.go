@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// routePattern matches requests under a path that carry the given query
+// parameters, e.g. /api/v1/provinces/cases?all=true
+type routePattern struct {
+	segments []string
+	query    url.Values
+	// exact patterns match their path but not the paths below it
+	exact bool
+}
+
+// parseRoutePattern parses a "path[?query]" pattern, "*" matching any one
+// path segment or any non-empty parameter value. A path ending in "$" only
+// matches itself.
+func parseRoutePattern(pattern string) (routePattern, error) {
+	path, rawQuery, _ := strings.Cut(pattern, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return routePattern{}, err
+	}
+	path, exact := strings.CutSuffix(path, "$")
+	return routePattern{segments: strings.Split(strings.Trim(path, "/"), "/"), query: query, exact: exact}, nil
+}
+
+// matches reports whether the request path starts with the pattern's
+// segments, or is them for an exact pattern, and has its query parameters
+func (rp routePattern) matches(r *http.Request) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < len(rp.segments) || (rp.exact && len(segments) != len(rp.segments)) {
+		return false
+	}
+	for i, s := range rp.segments {
+		if s != "*" && s != segments[i] {
+			return false
+		}
+	}
+	query := r.URL.Query()
+	for key, values := range rp.query {
+		if values[0] == "*" {
+			if query.Get(key) == "" {
+				return false
+			}
+		} else if query.Get(key) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// moreSpecific reports whether rp is tried before other: more path segments
+// win, then more parameters, then an exact path
+func (rp routePattern) moreSpecific(other routePattern) bool {
+	if len(rp.segments) != len(other.segments) {
+		return len(rp.segments) > len(other.segments)
+	}
+	if len(rp.query) != len(other.query) {
+		return len(rp.query) > len(other.query)
+	}
+	return rp.exact && !other.exact
+}
+
+// streamPathPrefix holds the long-lived Server-Sent Events endpoints, whose
+// responses are neither cut off by the request timeout nor kept for reuse
+const streamPathPrefix = "/api/v1/stream/"
+
+// webSocketPath is the realtime dashboard WebSocket, exempt like the streams
+const webSocketPath = "/api/v1/ws"
+
+// isStream reports whether r opens a long-lived event stream or WebSocket
+func isStream(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, streamPathPrefix) || r.URL.Path == webSocketPath
+}
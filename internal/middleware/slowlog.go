@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// SlowRequestLog logs every request that takes longer than threshold at WARN
+// level with its route, parameters, status, response size, returned row count
+// and trace ID. Slow SQL statements are logged separately by the database
+// layer (see database.DB.SetSlowQueryThreshold). A threshold of zero disables it.
+func SlowRequestLog(threshold time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if threshold <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapped := &responseWriter{ResponseWriter: w, status: 200}
+
+			next.ServeHTTP(wrapped, r)
+
+			elapsed := time.Since(start)
+			if elapsed < threshold {
+				return
+			}
+
+			route := "unmatched"
+			if current := mux.CurrentRoute(r); current != nil {
+				if tpl, err := current.GetPathTemplate(); err == nil {
+					route = tpl
+				}
+			}
+			traceID := "-"
+			if t, ok := tracing.FromContext(r.Context()); ok {
+				traceID = t.TraceID
+			}
+			rows := w.Header().Get("X-Result-Count")
+			if rows == "" {
+				rows = "-"
+			}
+
+			log.Printf("WARN slow request: %s %s route=%s vars=%v query=%q status=%d bytes=%d rows=%s duration=%v threshold=%v trace=%s",
+				r.Method, r.URL.Path, route, mux.Vars(r), r.URL.RawQuery,
+				wrapped.status, wrapped.size, rows, elapsed.Round(time.Millisecond), threshold, traceID)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestSlowRequestLog_LogsSlowRequest(t *testing.T) {
+	buf := captureLog(t)
+
+	router := mux.NewRouter()
+	router.Use(SlowRequestLog(time.Millisecond))
+	router.HandleFunc("/api/v1/provinces/{provinceId}/cases", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(3 * time.Millisecond)
+		w.Header().Set("X-Result-Count", "42")
+		_, _ = w.Write([]byte("{}"))
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/provinces/72/cases?all=true", nil))
+
+	out := buf.String()
+	assert.Contains(t, out, "WARN slow request")
+	assert.Contains(t, out, "route=/api/v1/provinces/{provinceId}/cases")
+	assert.Contains(t, out, "provinceId:72")
+	assert.Contains(t, out, `query="all=true"`)
+	assert.Contains(t, out, "rows=42")
+}
+
+func TestSlowRequestLog_SkipsFastRequest(t *testing.T) {
+	buf := captureLog(t)
+
+	handler := SlowRequestLog(time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Empty(t, buf.String())
+}
+
+func TestSlowRequestLog_Disabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := SlowRequestLog(0)(next)
+
+	assert.NotNil(t, handler)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+)
+
+// Tenant stores the tenant selected by the X-Tenant header or subdomain in the
+// request context and echoes its slug in the X-Tenant response header. Unknown
+// tenants named in the header are rejected with 404.
+func Tenant(registry *tenant.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t, ok := registry.FromRequest(r)
+			if !ok {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				if err := json.NewEncoder(w).Encode(ErrorResponse{Status: "error", Error: "Unknown tenant"}); err != nil {
+					log.Printf("Error encoding tenant JSON response: %v", err)
+				}
+				return
+			}
+
+			// Responses differ per tenant, so shared caches must key on it
+			w.Header().Add("Vary", tenant.Header)
+			w.Header().Set(tenant.Header, t.Slug)
+			next.ServeHTTP(w, r.WithContext(tenant.NewContext(r.Context(), t)))
+		})
+	}
+}
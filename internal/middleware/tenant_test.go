@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenant(t *testing.T) {
+	registry, err := tenant.NewRegistry([]string{"gorontalo:75:Gorontalo"}, "")
+	require.NoError(t, err)
+
+	var got tenant.Tenant
+	handler := Tenant(registry)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = tenant.FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/regencies", nil)
+	req.Header.Set(tenant.Header, "gorontalo")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "75", got.ProvinceID)
+	assert.Equal(t, "gorontalo", w.Header().Get(tenant.Header))
+	assert.Equal(t, tenant.Header, w.Header().Get("Vary"))
+}
+
+func TestTenant_UnknownTenant(t *testing.T) {
+	registry, err := tenant.NewRegistry(nil, "")
+	require.NoError(t, err)
+
+	called := false
+	handler := Tenant(registry)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/regencies", nil)
+	req.Header.Set(tenant.Header, "papua")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Unknown tenant")
+}
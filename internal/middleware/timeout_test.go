@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeout_SetsDeadline(t *testing.T) {
+	var deadline time.Time
+	var ok bool
+	handler := RequestTimeout(5 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok = r.Context().Deadline()
+	}))
+
+	start := time.Now()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/national", nil))
+
+	assert.True(t, ok)
+	assert.WithinDuration(t, start.Add(5*time.Second), deadline, time.Second)
+}
+
+func TestRequestTimeout_CancelsSlowRequest(t *testing.T) {
+	var ctxErr error
+	handler := RequestTimeout(time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		ctxErr = r.Context().Err()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/national", nil))
+
+	assert.Equal(t, context.DeadlineExceeded, ctxErr)
+}
+
+func TestRequestTimeout_Disabled(t *testing.T) {
+	var ok bool
+	handler := RequestTimeout(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok = r.Context().Deadline()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/national", nil))
+
+	assert.False(t, ok)
+}
+
+func TestRequestTimeout_SkipsStreams(t *testing.T) {
+	var ok bool
+	handler := RequestTimeout(5 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok = r.Context().Deadline()
+	}))
+
+	for _, path := range []string{"/api/v1/stream/cases", "/api/v1/ws"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		assert.False(t, ok, path)
+	}
+}
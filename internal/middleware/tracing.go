@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// Tracing honours traceparent / X-Cloud-Trace-Context headers set by the CDN or
+// proxy, starting a new trace when neither is present. The trace is stored in
+// the request context for logging and outgoing calls, and its ID is echoed in
+// the X-Trace-Id response header. When span recording is enabled the request
+// is recorded as a server span named after its route template.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, ok := tracing.FromRequest(r)
+		if !ok {
+			t = tracing.New()
+		}
+		w.Header().Set(tracing.TraceIDHeader, t.TraceID)
+
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		ctx, span := tracing.StartSpan(tracing.NewContext(r.Context(), t), r.Method+" "+route, tracing.SpanKindServer)
+		if span == nil {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		defer span.End()
+
+		wrapped := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("url.path", r.URL.Path)
+		span.SetAttribute("http.response.status_code", wrapped.status)
+		if wrapped.status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("HTTP %d", wrapped.status))
+		}
+	})
+}
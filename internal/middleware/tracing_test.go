@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracing_HonoursIncomingHeader(t *testing.T) {
+	var got tracing.Trace
+	handler := Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = tracing.FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got.TraceID)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Header().Get("X-Trace-Id"))
+}
+
+func TestTracing_StartsNewTrace(t *testing.T) {
+	var got tracing.Trace
+	handler := Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = tracing.FromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Len(t, got.TraceID, 32)
+	assert.Equal(t, got.TraceID, w.Header().Get("X-Trace-Id"))
+}
+
+type spanCollector struct {
+	spans []tracing.SpanData
+}
+
+func (c *spanCollector) Export(ctx context.Context, spans []tracing.SpanData) error {
+	c.spans = append(c.spans, spans...)
+	return nil
+}
+
+func TestTracing_RecordsServerSpan(t *testing.T) {
+	collector := &spanCollector{}
+	recorder := tracing.NewRecorder(collector, tracing.RecorderOptions{FlushInterval: time.Hour})
+	tracing.SetRecorder(recorder)
+	defer tracing.SetRecorder(nil)
+
+	var inner tracing.Trace
+	router := mux.NewRouter()
+	router.Use(Tracing)
+	router.HandleFunc("/provinces/{provinceId}", func(w http.ResponseWriter, r *http.Request) {
+		inner, _ = tracing.FromContext(r.Context())
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/provinces/72", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NoError(t, recorder.Shutdown(context.Background()))
+	require.Len(t, collector.spans, 1)
+	span := collector.spans[0]
+	assert.Equal(t, "GET /provinces/{provinceId}", span.Name)
+	assert.Equal(t, tracing.SpanKindServer, span.Kind)
+	assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID)
+	assert.Equal(t, inner.SpanID, span.SpanID)
+	assert.Equal(t, 503, span.Attributes["http.response.status_code"])
+	assert.Equal(t, "/provinces/72", span.Attributes["url.path"])
+	assert.Equal(t, "HTTP 503", span.Error)
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Intervals accepted by the ?interval= parameter of the case endpoints
+const (
+	IntervalDaily   = "daily"
+	IntervalWeekly  = "weekly"
+	IntervalMonthly = "monthly"
+)
+
+// CaseIntervals lists the accepted intervals in the order they are documented
+var CaseIntervals = []string{IntervalDaily, IntervalWeekly, IntervalMonthly}
+
+// IsValidCaseInterval reports whether interval is one of CaseIntervals
+func IsValidCaseInterval(interval string) bool {
+	for _, i := range CaseIntervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregatedCase totals the cases of one ISO week or calendar month, for the
+// nation or a single province. Period is the ISO week (2021-W05) or the month
+// (2021-02). StartDate and EndDate are the first and last dates with data, so
+// Days may be fewer than the period's length at the edges of the data.
+type AggregatedCase struct {
+	Period     string
+	ProvinceID string
+	Province   *Province
+	StartDate  time.Time
+	EndDate    time.Time
+	Days       int
+	// Daily counts are summed over the period
+	Positive  int64
+	Recovered int64
+	Deceased  int64
+	// Cumulative counts are the highest of the period, i.e. those at its end
+	CumulativePositive  int64
+	CumulativeRecovered int64
+	CumulativeDeceased  int64
+	// AverageRt is nil when no day of the period has an Rt estimate
+	AverageRt *float64
+}
+
+// AggregatedCaseResponse is the response format of an AggregatedCase
+type AggregatedCaseResponse struct {
+	Period     string          `json:"period"`
+	StartDate  time.Time       `json:"start_date"`
+	EndDate    time.Time       `json:"end_date"`
+	Days       int             `json:"days"`
+	Daily      DailyCases      `json:"daily"`
+	Cumulative CumulativeCases `json:"cumulative"`
+	AverageRt  *float64        `json:"average_rt"`
+	Province   *Province       `json:"province,omitempty"`
+}
+
+// TransformToResponse converts an AggregatedCase to the response format
+func (c AggregatedCase) TransformToResponse() AggregatedCaseResponse {
+	return AggregatedCaseResponse{
+		Period:    c.Period,
+		StartDate: c.StartDate,
+		EndDate:   c.EndDate,
+		Days:      c.Days,
+		Daily: DailyCases{
+			Positive:  c.Positive,
+			Recovered: c.Recovered,
+			Deceased:  c.Deceased,
+			Active:    c.Positive - c.Recovered - c.Deceased,
+		},
+		Cumulative: CumulativeCases{
+			Positive:  c.CumulativePositive,
+			Recovered: c.CumulativeRecovered,
+			Deceased:  c.CumulativeDeceased,
+			Active:    c.CumulativePositive - c.CumulativeRecovered - c.CumulativeDeceased,
+		},
+		AverageRt: c.AverageRt,
+		Province:  c.Province,
+	}
+}
+
+// TransformAggregatedCaseSliceToResponse converts aggregated cases to the response format
+func TransformAggregatedCaseSliceToResponse(cases []AggregatedCase) []AggregatedCaseResponse {
+	responses := make([]AggregatedCaseResponse, len(cases))
+	for i, c := range cases {
+		responses[i] = c.TransformToResponse()
+	}
+	return responses
+}
+
+// AggregatedCaseCSVHeader names the columns of AggregatedCaseResponse.CSVRow
+var AggregatedCaseCSVHeader = []string{
+	"period", "start_date", "end_date", "days", "province_id", "province_name",
+	"positive", "recovered", "deceased", "active",
+	"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "cumulative_active",
+	"average_rt",
+}
+
+// CSVRow flattens the response into the columns of AggregatedCaseCSVHeader.
+// The province columns are empty for national periods.
+func (r AggregatedCaseResponse) CSVRow() []string {
+	var provinceID, provinceName string
+	if r.Province != nil {
+		provinceID, provinceName = r.Province.ID, r.Province.Name
+	}
+	return []string{
+		r.Period, r.StartDate.Format("2006-01-02"), r.EndDate.Format("2006-01-02"), strconv.Itoa(r.Days), provinceID, provinceName,
+		formatCount(r.Daily.Positive), formatCount(r.Daily.Recovered), formatCount(r.Daily.Deceased), formatCount(r.Daily.Active),
+		formatCount(r.Cumulative.Positive), formatCount(r.Cumulative.Recovered), formatCount(r.Cumulative.Deceased), formatCount(r.Cumulative.Active),
+		formatOptionalFloat(r.AverageRt),
+	}
+}
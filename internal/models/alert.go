@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AlertScopeNational scopes a rule to the national series. Any other scope is a province ID.
+const AlertScopeNational = "national"
+
+// Alert rule channels
+const (
+	AlertChannelLog      = "log"
+	AlertChannelWebhook  = "webhook"
+	AlertChannelTelegram = "telegram"
+)
+
+// WebhookEventAlertTriggered is published for rules on the webhook channel
+const WebhookEventAlertTriggered = "alert.triggered"
+
+// AlertMetricColumns maps each metric a rule can watch to its column in
+// national_cases and province_cases
+var AlertMetricColumns = map[string]string{
+	"daily_positive":       "positive",
+	"daily_recovered":      "recovered",
+	"daily_deceased":       "deceased",
+	"cumulative_positive":  "cumulative_positive",
+	"cumulative_recovered": "cumulative_recovered",
+	"cumulative_deceased":  "cumulative_deceased",
+	"rt":                   "rt",
+}
+
+var alertOperators = map[string]func(value, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"==": func(v, t float64) bool { return v == t },
+	"!=": func(v, t float64) bool { return v != t },
+}
+
+var provinceIDPattern = regexp.MustCompile(`^[0-9]{2}$`)
+
+// AlertRule fires a notification on Channel when Metric of the latest day in
+// Scope compares to Threshold with Operator, e.g. daily_positive in 72 > 100.
+// Target is channel specific: the Telegram chat ID, unused for log and webhook.
+type AlertRule struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Metric    string    `json:"metric" db:"metric"`
+	Scope     string    `json:"scope" db:"scope"`
+	Operator  string    `json:"operator" db:"operator"`
+	Threshold float64   `json:"threshold" db:"threshold"`
+	Channel   string    `json:"channel" db:"channel"`
+	Target    string    `json:"target,omitempty" db:"target"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the rule's metric, scope, operator and channel
+func (r AlertRule) Validate() error {
+	var problems []string
+	if strings.TrimSpace(r.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if _, ok := AlertMetricColumns[r.Metric]; !ok {
+		problems = append(problems, fmt.Sprintf("unknown metric %q", r.Metric))
+	}
+	if r.Scope != AlertScopeNational && !provinceIDPattern.MatchString(r.Scope) {
+		problems = append(problems, fmt.Sprintf("scope must be %q or a two digit province ID, got %q", AlertScopeNational, r.Scope))
+	}
+	if _, ok := alertOperators[r.Operator]; !ok {
+		problems = append(problems, fmt.Sprintf("unknown operator %q", r.Operator))
+	}
+	switch r.Channel {
+	case AlertChannelLog, AlertChannelWebhook:
+	case AlertChannelTelegram:
+		if r.Target == "" {
+			problems = append(problems, "telegram channel requires target chat ID")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown channel %q", r.Channel))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Matches reports whether value satisfies the rule's condition
+func (r AlertRule) Matches(value float64) bool {
+	op, ok := alertOperators[r.Operator]
+	return ok && op(value, r.Threshold)
+}
+
+// Describe renders the rule's condition, e.g. "daily_positive in province 72 > 100"
+func (r AlertRule) Describe() string {
+	scope := r.Scope
+	if scope != AlertScopeNational {
+		scope = "province " + scope
+	}
+	return fmt.Sprintf("%s in %s %s %g", r.Metric, scope, r.Operator, r.Threshold)
+}
+
+// MetricObservation is the value of a metric on the latest day of a scope.
+// Value is nil when the column is NULL, e.g. rt before it was estimated.
+type MetricObservation struct {
+	Day   int64
+	Value *float64
+}
+
+// AlertEvaluation records one evaluation of a rule. Value and Day are nil when
+// the scope had no data or the metric was empty.
+type AlertEvaluation struct {
+	ID          int64     `json:"id" db:"id"`
+	RuleID      int64     `json:"rule_id" db:"rule_id"`
+	SyncRunID   *int64    `json:"sync_run_id,omitempty" db:"sync_log_id"`
+	Day         *int64    `json:"day,omitempty" db:"day"`
+	Value       *float64  `json:"value,omitempty" db:"value"`
+	Triggered   bool      `json:"triggered" db:"triggered"`
+	Notified    bool      `json:"notified" db:"notified"`
+	Error       *string   `json:"error,omitempty" db:"error"`
+	EvaluatedAt time.Time `json:"evaluated_at" db:"evaluated_at"`
+}
+
+// AlertNotification is what a triggered rule sends to its channel
+type AlertNotification struct {
+	RuleID    int64   `json:"rule_id"`
+	RuleName  string  `json:"rule_name"`
+	Condition string  `json:"condition"`
+	Day       int64   `json:"day"`
+	Value     float64 `json:"value"`
+}
+
+// Message renders the notification as a single line of text
+func (n AlertNotification) Message() string {
+	return fmt.Sprintf("Alert %q: %s (day %d value %g)", n.RuleName, n.Condition, n.Day, n.Value)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertRule_Validate(t *testing.T) {
+	valid := AlertRule{Name: "Sulteng spike", Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100, Channel: AlertChannelLog}
+	assert.NoError(t, valid.Validate())
+
+	national := valid
+	national.Scope = AlertScopeNational
+	assert.NoError(t, national.Validate())
+
+	invalid := AlertRule{Metric: "hospitalised", Scope: "sulteng", Operator: "=>", Channel: AlertChannelTelegram}
+	err := invalid.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "name is required")
+		assert.Contains(t, err.Error(), `unknown metric "hospitalised"`)
+		assert.Contains(t, err.Error(), `got "sulteng"`)
+		assert.Contains(t, err.Error(), `unknown operator "=>"`)
+		assert.Contains(t, err.Error(), "requires target chat ID")
+	}
+}
+
+func TestAlertRule_Matches(t *testing.T) {
+	rule := AlertRule{Operator: ">", Threshold: 100}
+	assert.True(t, rule.Matches(101))
+	assert.False(t, rule.Matches(100))
+
+	rule.Operator = "<="
+	assert.True(t, rule.Matches(100))
+
+	rule.Operator = "?"
+	assert.False(t, rule.Matches(0))
+}
+
+func TestAlertRule_Describe(t *testing.T) {
+	assert.Equal(t, "daily_positive in province 72 > 100",
+		AlertRule{Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100}.Describe())
+	assert.Equal(t, "rt in national >= 1.1",
+		AlertRule{Metric: "rt", Scope: AlertScopeNational, Operator: ">=", Threshold: 1.1}.Describe())
+}
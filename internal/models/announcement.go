@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Announcement severities
+const (
+	AnnouncementSeverityInfo     = "info"
+	AnnouncementSeverityWarning  = "warning"
+	AnnouncementSeverityCritical = "critical"
+)
+
+// maxAnnouncementLength keeps announcements short enough for a banner
+const maxAnnouncementLength = 500
+
+// Announcement is a message managed through the admin API, such as "data
+// delayed today due to an upstream outage". It is shown from StartsAt until
+// EndsAt, or until it is deleted when EndsAt is nil.
+type Announcement struct {
+	ID        int64      `json:"id" db:"id"`
+	Severity  string     `json:"severity" db:"severity"`
+	Message   string     `json:"message" db:"message"`
+	StartsAt  time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at" db:"ends_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the announcement's severity, message and time window
+func (a Announcement) Validate() error {
+	var problems []string
+	switch a.Severity {
+	case AnnouncementSeverityInfo, AnnouncementSeverityWarning, AnnouncementSeverityCritical:
+	default:
+		problems = append(problems, fmt.Sprintf("severity must be %s, %s or %s, got %q",
+			AnnouncementSeverityInfo, AnnouncementSeverityWarning, AnnouncementSeverityCritical, a.Severity))
+	}
+	if strings.TrimSpace(a.Message) == "" {
+		problems = append(problems, "message is required")
+	} else if len(a.Message) > maxAnnouncementLength {
+		problems = append(problems, fmt.Sprintf("message must be at most %d characters", maxAnnouncementLength))
+	}
+	if a.EndsAt != nil && !a.EndsAt.After(a.StartsAt) {
+		problems = append(problems, "ends_at must be after starts_at")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ActiveAt reports whether the announcement is shown at t
+func (a Announcement) ActiveAt(t time.Time) bool {
+	return !t.Before(a.StartsAt) && (a.EndsAt == nil || t.Before(*a.EndsAt))
+}
+
+// Notice is the public view of an active announcement in meta.notices
+type Notice struct {
+	ID       int64      `json:"id"`
+	Severity string     `json:"severity"`
+	Message  string     `json:"message"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// Notice returns the public view of the announcement
+func (a Announcement) Notice() Notice {
+	return Notice{ID: a.ID, Severity: a.Severity, Message: a.Message, StartsAt: a.StartsAt, EndsAt: a.EndsAt}
+}
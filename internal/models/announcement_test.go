@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnouncement_Validate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	valid := Announcement{Severity: AnnouncementSeverityWarning, Message: "Data delayed today", StartsAt: start, EndsAt: &end}
+	assert.NoError(t, valid.Validate())
+
+	err := Announcement{Severity: "urgent", Message: " ", StartsAt: end, EndsAt: &start}.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `got "urgent"`)
+		assert.Contains(t, err.Error(), "message is required")
+		assert.Contains(t, err.Error(), "ends_at must be after starts_at")
+	}
+
+	err = Announcement{Severity: AnnouncementSeverityInfo, Message: strings.Repeat("x", 501), StartsAt: start}.Validate()
+	assert.ErrorContains(t, err, "at most 500 characters")
+}
+
+func TestAnnouncement_ActiveAt(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	a := Announcement{StartsAt: start, EndsAt: &end}
+
+	assert.False(t, a.ActiveAt(start.Add(-time.Second)))
+	assert.True(t, a.ActiveAt(start))
+	assert.False(t, a.ActiveAt(end))
+
+	a.EndsAt = nil
+	assert.True(t, a.ActiveAt(start.AddDate(1, 0, 0)))
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scopes of API keys. Each scope includes the ones before it: admin keys may
+// also write and read, write keys may also read.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// tierPattern matches rate limit tier names
+var tierPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// APIKeyScopes lists the scopes from the narrowest to the broadest
+var APIKeyScopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// APIKey is a client's key for the X-API-Key header. Only the SHA-256 hash of
+// the key is kept.
+type APIKey struct {
+	ID      int64    `json:"id"`
+	Name    string   `json:"name"`
+	KeyHash string   `json:"-"`
+	Scopes  []string `json:"scopes"`
+	// RequestsPerMinute replaces the global per-client rate limit for the
+	// key's requests when positive
+	RequestsPerMinute int `json:"requests_per_minute"`
+	// Tier names one of the RATE_LIMIT_TIERS limits; RequestsPerMinute wins
+	// when both are set
+	Tier      string    `json:"tier,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HashAPIKey returns the hex SHA-256 hash stored for a key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// HasScope reports whether the key was granted scope or a broader one
+func (k APIKey) HasScope(scope string) bool {
+	required := scopeRank(scope)
+	if required < 0 {
+		return false
+	}
+	for _, s := range k.Scopes {
+		if scopeRank(s) >= required {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeRank(scope string) int {
+	for i, s := range APIKeyScopes {
+		if s == scope {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseAPIKey reads a "name:key:scopes[:requests_per_minute|tier]" definition
+// with scopes joined by "+", e.g. "dashboard:s3cret:read:600" or
+// "partner:s3cret:read:premium"
+func ParseAPIKey(def string) (APIKey, error) {
+	parts := strings.Split(def, ":")
+	if len(parts) != 3 && len(parts) != 4 {
+		return APIKey{}, fmt.Errorf("API key %q must be name:key:scopes[:requests_per_minute|tier]", redactAPIKey(parts))
+	}
+	k := APIKey{Name: strings.TrimSpace(parts[0])}
+	key := strings.TrimSpace(parts[1])
+	if k.Name == "" || key == "" {
+		return APIKey{}, fmt.Errorf("API key %q needs a name and a key", redactAPIKey(parts))
+	}
+	k.KeyHash = HashAPIKey(key)
+	for _, s := range strings.Split(parts[2], "+") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if scopeRank(s) < 0 {
+			return APIKey{}, fmt.Errorf("API key %q has unknown scope %q; use %s", k.Name, s, strings.Join(APIKeyScopes, ", "))
+		}
+		k.Scopes = append(k.Scopes, s)
+	}
+	if len(parts) == 4 {
+		limit := strings.TrimSpace(parts[3])
+		if rpm, err := strconv.Atoi(limit); err == nil {
+			if rpm < 0 {
+				return APIKey{}, fmt.Errorf("API key %q has an invalid requests per minute %q", k.Name, parts[3])
+			}
+			k.RequestsPerMinute = rpm
+		} else if tierPattern.MatchString(limit) {
+			k.Tier = limit
+		} else {
+			return APIKey{}, fmt.Errorf("API key %q has an invalid requests per minute or tier %q", k.Name, parts[3])
+		}
+	}
+	return k, nil
+}
+
+// redactAPIKey formats a definition for error messages without its key
+func redactAPIKey(parts []string) string {
+	if len(parts) < 2 {
+		return strings.Join(parts, ":")
+	}
+	redacted := append([]string{parts[0], "***"}, parts[2:]...)
+	return strings.Join(redacted, ":")
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIKey(t *testing.T) {
+	k, err := ParseAPIKey("dashboard:s3cret:read+write:600")
+
+	require.NoError(t, err)
+	assert.Equal(t, "dashboard", k.Name)
+	assert.Equal(t, HashAPIKey("s3cret"), k.KeyHash)
+	assert.Equal(t, []string{ScopeRead, ScopeWrite}, k.Scopes)
+	assert.Equal(t, 600, k.RequestsPerMinute)
+
+	k, err = ParseAPIKey("app:key:read")
+	require.NoError(t, err)
+	assert.Zero(t, k.RequestsPerMinute)
+
+	k, err = ParseAPIKey("partner:key:read:premium")
+	require.NoError(t, err)
+	assert.Equal(t, "premium", k.Tier)
+	assert.Zero(t, k.RequestsPerMinute)
+}
+
+func TestParseAPIKey_Invalid(t *testing.T) {
+	for _, def := range []string{
+		"dashboard",
+		"dashboard::read",
+		"dashboard:s3cret:superuser",
+		"dashboard:s3cret:read:-1",
+		"dashboard:s3cret:read:Premium Plus",
+		"dashboard:s3cret:read:600:extra",
+	} {
+		_, err := ParseAPIKey(def)
+		if assert.Error(t, err, def) {
+			assert.NotContains(t, err.Error(), "s3cret", "errors must not leak the key")
+		}
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	read := APIKey{Scopes: []string{ScopeRead}}
+	admin := APIKey{Scopes: []string{ScopeAdmin}}
+
+	assert.True(t, read.HasScope(ScopeRead))
+	assert.False(t, read.HasScope(ScopeWrite))
+	assert.True(t, admin.HasScope(ScopeRead))
+	assert.True(t, admin.HasScope(ScopeWrite))
+	assert.False(t, admin.HasScope("owner"))
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// States of the API and its components on the status page
+const (
+	APIStatusOperational = "operational"
+	APIStatusDegraded    = "degraded"
+	APIStatusDown        = "down"
+)
+
+// Incident severities
+const (
+	IncidentSeverityMinor       = "minor"
+	IncidentSeverityMajor       = "major"
+	IncidentSeverityMaintenance = "maintenance"
+)
+
+// ComponentStatus is the result of a live check of one dependency such as the
+// database. Check errors are logged, not published.
+type ComponentStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Incident is a notice about an outage or maintenance, managed through the
+// admin API. It stays on the status page until some time after it is resolved.
+type Incident struct {
+	ID         int64      `json:"id" db:"id"`
+	Title      string     `json:"title" db:"title"`
+	Message    string     `json:"message" db:"message"`
+	Severity   string     `json:"severity" db:"severity"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	ResolvedAt *time.Time `json:"resolved_at" db:"resolved_at"`
+}
+
+// Validate checks the incident's title and severity
+func (i Incident) Validate() error {
+	var problems []string
+	if strings.TrimSpace(i.Title) == "" {
+		problems = append(problems, "title is required")
+	} else if len(i.Title) > 255 {
+		problems = append(problems, "title must be at most 255 characters")
+	}
+	switch i.Severity {
+	case IncidentSeverityMinor, IncidentSeverityMajor, IncidentSeverityMaintenance:
+	default:
+		problems = append(problems, fmt.Sprintf("severity must be %s, %s or %s, got %q",
+			IncidentSeverityMinor, IncidentSeverityMajor, IncidentSeverityMaintenance, i.Severity))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// StatusCheck is one recorded check of a component
+type StatusCheck struct {
+	Component string    `json:"component" db:"component"`
+	Healthy   bool      `json:"healthy" db:"healthy"`
+	CheckedAt time.Time `json:"checked_at" db:"checked_at"`
+}
+
+// ComponentUptime is the share of successful checks of a component in
+// percent. A period without checks is null.
+type ComponentUptime struct {
+	Name    string   `json:"name"`
+	Last24h *float64 `json:"last_24h"`
+	Last7d  *float64 `json:"last_7d"`
+	Last30d *float64 `json:"last_30d"`
+}
+
+// APIStatus is the public status page: live component checks, current
+// incidents and recent uptime
+type APIStatus struct {
+	Status     string            `json:"status"`
+	Components []ComponentStatus `json:"components"`
+	Incidents  []Incident        `json:"incidents"`
+	Uptime     []ComponentUptime `json:"uptime"`
+	CheckedAt  time.Time         `json:"checked_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncident_Validate(t *testing.T) {
+	valid := Incident{Title: "Database maintenance", Severity: IncidentSeverityMaintenance}
+	assert.NoError(t, valid.Validate())
+
+	err := Incident{Title: "  ", Severity: "critical"}.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "title is required")
+		assert.Contains(t, err.Error(), `got "critical"`)
+	}
+
+	err = Incident{Title: strings.Repeat("x", 256), Severity: IncidentSeverityMajor}.Validate()
+	assert.ErrorContains(t, err, "at most 255 characters")
+}
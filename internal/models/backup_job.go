@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Backup job statuses
+const (
+	BackupStatusRunning   = "running"
+	BackupStatusCompleted = "completed"
+	BackupStatusFailed    = "failed"
+)
+
+// BackupJob tracks an asynchronous database backup
+type BackupJob struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	File       string     `json:"file"`
+	Bytes      int64      `json:"bytes"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// AnonymousClient names the requests sent without an API key in bandwidth
+// reports
+const AnonymousClient = "anonymous"
+
+// RouteBandwidth is the response body bytes served for a route
+type RouteBandwidth struct {
+	Route     string  `json:"route" example:"GET /api/v1/provinces/cases?all=true"`
+	Requests  int64   `json:"requests"`
+	Bytes     int64   `json:"bytes"`
+	MeanBytes float64 `json:"mean_bytes"`
+	MaxBytes  int64   `json:"max_bytes"`
+}
+
+// ClientBandwidth is the response body bytes served to an API key, or to the
+// requests without one, with the routes it used most bandwidth on
+type ClientBandwidth struct {
+	// Client is the API key's name, or anonymous
+	Client    string           `json:"client" example:"dashboard"`
+	APIKeyID  int64            `json:"api_key_id,omitempty"`
+	Requests  int64            `json:"requests"`
+	Bytes     int64            `json:"bytes"`
+	MeanBytes float64          `json:"mean_bytes"`
+	TopRoutes []RouteBandwidth `json:"top_routes"`
+}
+
+// BandwidthReport is the bandwidth served since Since, largest first
+type BandwidthReport struct {
+	Since      time.Time         `json:"since"`
+	Requests   int64             `json:"requests"`
+	TotalBytes int64             `json:"total_bytes"`
+	Routes     []RouteBandwidth  `json:"routes"`
+	Clients    []ClientBandwidth `json:"clients"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "strconv"
+
+// NationalCaseCSVHeader names the columns of NationalCaseResponse.CSVRow
+var NationalCaseCSVHeader = []string{
+	"day", "date",
+	"positive", "recovered", "deceased", "active",
+	"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "cumulative_active",
+	"rt", "rt_upper", "rt_lower",
+}
+
+// CSVRow flattens the response into the columns of NationalCaseCSVHeader
+func (r NationalCaseResponse) CSVRow() []string {
+	row := []string{
+		strconv.FormatInt(r.Day, 10), r.Date.Format("2006-01-02"),
+		formatCount(r.Daily.Positive), formatCount(r.Daily.Recovered), formatCount(r.Daily.Deceased), formatCount(r.Daily.Active),
+		formatCount(r.Cumulative.Positive), formatCount(r.Cumulative.Recovered), formatCount(r.Cumulative.Deceased), formatCount(r.Cumulative.Active),
+	}
+	return append(row, reproductionRateColumns(r.Statistics.ReproductionRate)...)
+}
+
+// ProvinceCaseCSVHeader names the columns of ProvinceCaseResponse.CSVRow
+var ProvinceCaseCSVHeader = []string{
+	"day", "date", "province_id", "province_name",
+	"positive", "recovered", "deceased", "active",
+	"odp_active", "odp_finished", "pdp_active", "pdp_finished",
+	"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "cumulative_active",
+	"cumulative_odp_active", "cumulative_odp_finished", "cumulative_odp_total",
+	"cumulative_pdp_active", "cumulative_pdp_finished", "cumulative_pdp_total",
+	"rt", "rt_upper", "rt_lower",
+}
+
+// CSVRow flattens the response into the columns of ProvinceCaseCSVHeader.
+// The province columns are empty when the response omits the province.
+func (r ProvinceCaseResponse) CSVRow() []string {
+	var provinceID, provinceName string
+	if r.Province != nil {
+		provinceID, provinceName = r.Province.ID, r.Province.Name
+	}
+	d, c := r.Daily, r.Cumulative
+	row := []string{
+		strconv.FormatInt(r.Day, 10), r.Date.Format("2006-01-02"), provinceID, provinceName,
+		formatCount(d.Positive), formatCount(d.Recovered), formatCount(d.Deceased), formatCount(d.Active),
+		formatCount(d.ODP.Active), formatCount(d.ODP.Finished), formatCount(d.PDP.Active), formatCount(d.PDP.Finished),
+		formatCount(c.Positive), formatCount(c.Recovered), formatCount(c.Deceased), formatCount(c.Active),
+		formatCount(c.ODP.Active), formatCount(c.ODP.Finished), formatCount(c.ODP.Total),
+		formatCount(c.PDP.Active), formatCount(c.PDP.Finished), formatCount(c.PDP.Total),
+	}
+	return append(row, reproductionRateColumns(r.Statistics.ReproductionRate)...)
+}
+
+func formatCount(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// reproductionRateColumns returns rt, rt_upper and rt_lower, empty when unknown
+func reproductionRateColumns(rate *ReproductionRate) []string {
+	if rate == nil {
+		return []string{"", "", ""}
+	}
+	return []string{formatOptionalFloat(rate.Value), formatOptionalFloat(rate.UpperBound), formatOptionalFloat(rate.LowerBound)}
+}
+
+func formatOptionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
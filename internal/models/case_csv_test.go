@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNationalCaseResponse_CSVRow(t *testing.T) {
+	rt, rtUpper := 1.1, 1.4
+	nc := NationalCase{
+		Day:                5,
+		Date:               time.Date(2020, 3, 6, 0, 0, 0, 0, time.UTC),
+		Positive:           4,
+		CumulativePositive: 10,
+		Rt:                 &rt,
+		RtUpper:            &rtUpper,
+	}
+
+	row := nc.TransformToResponse().CSVRow()
+
+	assert.Len(t, row, len(NationalCaseCSVHeader))
+	assert.Equal(t, []string{"5", "2020-03-06", "4"}, row[:3])
+	assert.Equal(t, "10", row[6])
+	assert.Equal(t, []string{"1.1", "1.4", ""}, row[10:])
+}
+
+func TestProvinceCaseResponse_CSVRow(t *testing.T) {
+	pc := ProvinceCaseWithDate{
+		ProvinceCase: ProvinceCase{
+			Day:                    3,
+			ProvinceID:             "72",
+			Positive:               2,
+			PersonUnderObservation: 7,
+			Province:               &Province{ID: "72", Name: "Sulawesi Tengah"},
+		},
+		Date: time.Date(2020, 3, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	row := pc.TransformToResponse().CSVRow()
+
+	assert.Len(t, row, len(ProvinceCaseCSVHeader))
+	assert.Equal(t, []string{"3", "2020-03-28", "72", "Sulawesi Tengah", "2"}, row[:5])
+	assert.Equal(t, []string{"", "", ""}, row[len(row)-3:])
+}
+
+func TestProvinceCaseResponse_CSVRow_WithoutProvince(t *testing.T) {
+	row := ProvinceCaseResponse{}.CSVRow()
+
+	assert.Len(t, row, len(ProvinceCaseCSVHeader))
+	assert.Equal(t, "", row[2])
+	assert.Equal(t, "", row[3])
+}
+
+func TestAggregatedCaseResponse_CSVRow(t *testing.T) {
+	rt := 1.05
+	c := AggregatedCase{
+		Period:             "2021-W05",
+		Province:           &Province{ID: "72", Name: "Sulawesi Tengah"},
+		StartDate:          time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:            time.Date(2021, 2, 7, 0, 0, 0, 0, time.UTC),
+		Days:               7,
+		Positive:           70,
+		Recovered:          50,
+		Deceased:           2,
+		CumulativePositive: 1000,
+		AverageRt:          &rt,
+	}
+
+	row := c.TransformToResponse().CSVRow()
+
+	assert.Len(t, row, len(AggregatedCaseCSVHeader))
+	assert.Equal(t, []string{"2021-W05", "2021-02-01", "2021-02-07", "7", "72", "Sulawesi Tengah", "70", "50", "2", "18"}, row[:10])
+	assert.Equal(t, "1000", row[10])
+	assert.Equal(t, "1.05", row[14])
+}
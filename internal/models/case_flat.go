@@ -0,0 +1,172 @@
+package models
+
+import "time"
+
+// FlatNationalCase is the ?shape=flat form of NationalCaseResponse, with the
+// nested objects replaced by prefixed keys as the mobile app's models expect
+type FlatNationalCase struct {
+	Day  int64     `json:"day"`
+	Date time.Time `json:"date"`
+	flatCaseCounts
+	flatCaseStatistics
+	Notes []CaseNote `json:"notes,omitempty"`
+	RecordTimestamps
+}
+
+// FlatProvinceCase is the ?shape=flat form of ProvinceCaseResponse. The
+// province keys are empty when the response omits the province.
+type FlatProvinceCase struct {
+	Day          int64     `json:"day"`
+	Date         time.Time `json:"date"`
+	ProvinceID   string    `json:"province_id,omitempty"`
+	ProvinceName string    `json:"province_name,omitempty"`
+	flatCaseCounts
+	DailyODPActive        int64 `json:"daily_odp_active"`
+	DailyODPFinished      int64 `json:"daily_odp_finished"`
+	DailyPDPActive        int64 `json:"daily_pdp_active"`
+	DailyPDPFinished      int64 `json:"daily_pdp_finished"`
+	CumulativeODPActive   int64 `json:"cumulative_odp_active"`
+	CumulativeODPFinished int64 `json:"cumulative_odp_finished"`
+	CumulativeODPTotal    int64 `json:"cumulative_odp_total"`
+	CumulativePDPActive   int64 `json:"cumulative_pdp_active"`
+	CumulativePDPFinished int64 `json:"cumulative_pdp_finished"`
+	CumulativePDPTotal    int64 `json:"cumulative_pdp_total"`
+	flatCaseStatistics
+	Notes []CaseNote `json:"notes,omitempty"`
+	RecordTimestamps
+}
+
+// FlatAggregatedCase is the ?shape=flat form of AggregatedCaseResponse
+type FlatAggregatedCase struct {
+	Period       string    `json:"period"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+	Days         int       `json:"days"`
+	ProvinceID   string    `json:"province_id,omitempty"`
+	ProvinceName string    `json:"province_name,omitempty"`
+	flatCaseCounts
+	AverageRt *float64 `json:"average_rt"`
+}
+
+type flatCaseCounts struct {
+	DailyPositive       int64 `json:"daily_positive"`
+	DailyRecovered      int64 `json:"daily_recovered"`
+	DailyDeceased       int64 `json:"daily_deceased"`
+	DailyActive         int64 `json:"daily_active"`
+	CumulativePositive  int64 `json:"cumulative_positive"`
+	CumulativeRecovered int64 `json:"cumulative_recovered"`
+	CumulativeDeceased  int64 `json:"cumulative_deceased"`
+	CumulativeActive    int64 `json:"cumulative_active"`
+}
+
+func newFlatCaseCounts(daily DailyCases, cumulative CumulativeCases) flatCaseCounts {
+	return flatCaseCounts{
+		DailyPositive:       daily.Positive,
+		DailyRecovered:      daily.Recovered,
+		DailyDeceased:       daily.Deceased,
+		DailyActive:         daily.Active,
+		CumulativePositive:  cumulative.Positive,
+		CumulativeRecovered: cumulative.Recovered,
+		CumulativeDeceased:  cumulative.Deceased,
+		CumulativeActive:    cumulative.Active,
+	}
+}
+
+// flatCaseStatistics holds the statistics of a day. The testing and moving
+// average keys are only present when the nested response has them.
+type flatCaseStatistics struct {
+	PercentageActive       float64  `json:"percentage_active"`
+	PercentageRecovered    float64  `json:"percentage_recovered"`
+	PercentageDeceased     float64  `json:"percentage_deceased"`
+	RtValue                *float64 `json:"rt_value"`
+	RtUpperBound           *float64 `json:"rt_upper_bound"`
+	RtLowerBound           *float64 `json:"rt_lower_bound"`
+	DailyTests             *int64   `json:"daily_tests,omitempty"`
+	CumulativeTests        *int64   `json:"cumulative_tests,omitempty"`
+	PositivityRate         *float64 `json:"positivity_rate,omitempty"`
+	MovingAverageDays      *int     `json:"moving_average_days,omitempty"`
+	MovingAveragePositive  *float64 `json:"moving_average_positive,omitempty"`
+	MovingAverageRecovered *float64 `json:"moving_average_recovered,omitempty"`
+	MovingAverageDeceased  *float64 `json:"moving_average_deceased,omitempty"`
+}
+
+func newFlatCaseStatistics(percentages CasePercentages, rt *ReproductionRate, testing *TestingStatistics, average *MovingAverage) flatCaseStatistics {
+	s := flatCaseStatistics{
+		PercentageActive:    percentages.Active,
+		PercentageRecovered: percentages.Recovered,
+		PercentageDeceased:  percentages.Deceased,
+	}
+	if rt != nil {
+		s.RtValue, s.RtUpperBound, s.RtLowerBound = rt.Value, rt.UpperBound, rt.LowerBound
+	}
+	if testing != nil {
+		daily, cumulative := testing.Daily.Total, testing.Cumulative.Total
+		s.DailyTests, s.CumulativeTests = &daily, &cumulative
+		s.PositivityRate = testing.PositivityRate
+	}
+	if average != nil {
+		days, positive, recovered, deceased := average.Days, average.Positive, average.Recovered, average.Deceased
+		s.MovingAverageDays = &days
+		s.MovingAveragePositive, s.MovingAverageRecovered, s.MovingAverageDeceased = &positive, &recovered, &deceased
+	}
+	return s
+}
+
+// Flat returns the response in the flat shape
+func (r NationalCaseResponse) Flat() FlatNationalCase {
+	st := r.Statistics
+	return FlatNationalCase{
+		Day:                r.Day,
+		Date:               r.Date,
+		flatCaseCounts:     newFlatCaseCounts(r.Daily, r.Cumulative),
+		flatCaseStatistics: newFlatCaseStatistics(st.Percentages, st.ReproductionRate, st.Testing, st.MovingAverage),
+		Notes:              r.Notes,
+		RecordTimestamps:   r.RecordTimestamps,
+	}
+}
+
+// Flat returns the response in the flat shape
+func (r ProvinceCaseResponse) Flat() FlatProvinceCase {
+	d, c, st := r.Daily, r.Cumulative, r.Statistics
+	flat := FlatProvinceCase{
+		Day:  r.Day,
+		Date: r.Date,
+		flatCaseCounts: newFlatCaseCounts(
+			DailyCases{Positive: d.Positive, Recovered: d.Recovered, Deceased: d.Deceased, Active: d.Active},
+			CumulativeCases{Positive: c.Positive, Recovered: c.Recovered, Deceased: c.Deceased, Active: c.Active},
+		),
+		DailyODPActive:        d.ODP.Active,
+		DailyODPFinished:      d.ODP.Finished,
+		DailyPDPActive:        d.PDP.Active,
+		DailyPDPFinished:      d.PDP.Finished,
+		CumulativeODPActive:   c.ODP.Active,
+		CumulativeODPFinished: c.ODP.Finished,
+		CumulativeODPTotal:    c.ODP.Total,
+		CumulativePDPActive:   c.PDP.Active,
+		CumulativePDPFinished: c.PDP.Finished,
+		CumulativePDPTotal:    c.PDP.Total,
+		flatCaseStatistics:    newFlatCaseStatistics(st.Percentages, st.ReproductionRate, st.Testing, st.MovingAverage),
+		Notes:                 r.Notes,
+		RecordTimestamps:      r.RecordTimestamps,
+	}
+	if r.Province != nil {
+		flat.ProvinceID, flat.ProvinceName = r.Province.ID, r.Province.Name
+	}
+	return flat
+}
+
+// Flat returns the response in the flat shape
+func (r AggregatedCaseResponse) Flat() FlatAggregatedCase {
+	flat := FlatAggregatedCase{
+		Period:         r.Period,
+		StartDate:      r.StartDate,
+		EndDate:        r.EndDate,
+		Days:           r.Days,
+		flatCaseCounts: newFlatCaseCounts(r.Daily, r.Cumulative),
+		AverageRt:      r.AverageRt,
+	}
+	if r.Province != nil {
+		flat.ProvinceID, flat.ProvinceName = r.Province.ID, r.Province.Name
+	}
+	return flat
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvinceCaseResponse_Flat(t *testing.T) {
+	rt := 0.9
+	updated := time.Date(2020, 3, 29, 8, 0, 0, 0, time.UTC)
+	pc := ProvinceCaseWithDate{
+		ProvinceCase: ProvinceCase{
+			Day:                              3,
+			Positive:                         2,
+			CumulativePositive:               10,
+			CumulativeRecovered:              4,
+			PersonUnderObservation:           7,
+			FinishedPersonUnderObservation:   3,
+			CumulativePersonUnderObservation: 20,
+			Rt:                               &rt,
+			Province:                         &Province{ID: "72", Name: "Sulawesi Tengah"},
+			MovingAverage:                    &MovingAverage{Days: 7, Positive: 1.5},
+			RecordTimestamps:                 RecordTimestamps{UpdatedAt: &updated},
+		},
+		Date: time.Date(2020, 3, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	flat := pc.TransformToResponse().Flat()
+
+	assert.Equal(t, "72", flat.ProvinceID)
+	assert.Equal(t, int64(2), flat.DailyPositive)
+	assert.Equal(t, int64(6), flat.CumulativeActive)
+	assert.Equal(t, int64(4), flat.DailyODPActive)
+	assert.Equal(t, int64(20), flat.CumulativeODPTotal)
+	assert.Equal(t, &rt, flat.RtValue)
+	assert.InDelta(t, 60.0, flat.PercentageActive, 0.001)
+
+	body, err := json.Marshal(flat)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"moving_average_days":7`)
+	assert.Contains(t, string(body), `"rt_upper_bound":null`)
+	assert.NotContains(t, string(body), "positivity_rate")
+	assert.Contains(t, string(body), `"updated_at":"2020-03-29T08:00:00Z"`)
+	assert.NotContains(t, string(body), "created_at")
+}
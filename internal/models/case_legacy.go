@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// LegacyNationalCase is the ?legacy=true form of NationalCaseResponse: the flat
+// schema with Indonesian keys that the API served before 2.0
+type LegacyNationalCase struct {
+	Hari    int64     `json:"hari"`
+	Tanggal time.Time `json:"tanggal"`
+	legacyCaseCounts
+}
+
+// LegacyProvinceCase is the ?legacy=true form of ProvinceCaseResponse. The
+// province keys are empty when the response omits the province.
+type LegacyProvinceCase struct {
+	Hari       int64     `json:"hari"`
+	Tanggal    time.Time `json:"tanggal"`
+	ProvinsiID string    `json:"provinsi_id,omitempty"`
+	Provinsi   string    `json:"provinsi,omitempty"`
+	legacyCaseCounts
+	ODP                 int64 `json:"odp"`
+	ODPSelesai          int64 `json:"odp_selesai"`
+	PDP                 int64 `json:"pdp"`
+	PDPSelesai          int64 `json:"pdp_selesai"`
+	ODPKumulatif        int64 `json:"odp_kumulatif"`
+	ODPSelesaiKumulatif int64 `json:"odp_selesai_kumulatif"`
+	PDPKumulatif        int64 `json:"pdp_kumulatif"`
+	PDPSelesaiKumulatif int64 `json:"pdp_selesai_kumulatif"`
+}
+
+// legacyCaseCounts are the case counts and Rt estimate of a day. Dirawat
+// (under treatment) is the active count.
+type legacyCaseCounts struct {
+	Kasus              int64    `json:"kasus"`
+	Sembuh             int64    `json:"sembuh"`
+	Meninggal          int64    `json:"meninggal"`
+	Dirawat            int64    `json:"dirawat"`
+	KasusKumulatif     int64    `json:"kasus_kumulatif"`
+	SembuhKumulatif    int64    `json:"sembuh_kumulatif"`
+	MeninggalKumulatif int64    `json:"meninggal_kumulatif"`
+	DirawatKumulatif   int64    `json:"dirawat_kumulatif"`
+	Rt                 *float64 `json:"rt"`
+	RtUpper            *float64 `json:"rt_upper"`
+	RtLower            *float64 `json:"rt_lower"`
+}
+
+func newLegacyCaseCounts(daily DailyCases, cumulative CumulativeCases, rt *ReproductionRate) legacyCaseCounts {
+	counts := legacyCaseCounts{
+		Kasus:              daily.Positive,
+		Sembuh:             daily.Recovered,
+		Meninggal:          daily.Deceased,
+		Dirawat:            daily.Active,
+		KasusKumulatif:     cumulative.Positive,
+		SembuhKumulatif:    cumulative.Recovered,
+		MeninggalKumulatif: cumulative.Deceased,
+		DirawatKumulatif:   cumulative.Active,
+	}
+	if rt != nil {
+		counts.Rt, counts.RtUpper, counts.RtLower = rt.Value, rt.UpperBound, rt.LowerBound
+	}
+	return counts
+}
+
+// Legacy returns the response in the pre-2.0 schema
+func (r NationalCaseResponse) Legacy() LegacyNationalCase {
+	return LegacyNationalCase{
+		Hari:             r.Day,
+		Tanggal:          r.Date,
+		legacyCaseCounts: newLegacyCaseCounts(r.Daily, r.Cumulative, r.Statistics.ReproductionRate),
+	}
+}
+
+// Legacy returns the response in the pre-2.0 schema. The daily ODP and PDP
+// counts are the new people under observation and supervision that day.
+func (r ProvinceCaseResponse) Legacy() LegacyProvinceCase {
+	d, c := r.Daily, r.Cumulative
+	legacy := LegacyProvinceCase{
+		Hari:    r.Day,
+		Tanggal: r.Date,
+		legacyCaseCounts: newLegacyCaseCounts(
+			DailyCases{Positive: d.Positive, Recovered: d.Recovered, Deceased: d.Deceased, Active: d.Active},
+			CumulativeCases{Positive: c.Positive, Recovered: c.Recovered, Deceased: c.Deceased, Active: c.Active},
+			r.Statistics.ReproductionRate,
+		),
+		ODP:                 d.ODP.Active + d.ODP.Finished,
+		ODPSelesai:          d.ODP.Finished,
+		PDP:                 d.PDP.Active + d.PDP.Finished,
+		PDPSelesai:          d.PDP.Finished,
+		ODPKumulatif:        c.ODP.Total,
+		ODPSelesaiKumulatif: c.ODP.Finished,
+		PDPKumulatif:        c.PDP.Total,
+		PDPSelesaiKumulatif: c.PDP.Finished,
+	}
+	if r.Province != nil {
+		legacy.ProvinsiID, legacy.Provinsi = r.Province.ID, r.Province.Name
+	}
+	return legacy
+}
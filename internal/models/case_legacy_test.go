@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNationalCaseResponse_Legacy(t *testing.T) {
+	rt := 1.1
+	nc := NationalCase{
+		Day:                 5,
+		Date:                time.Date(2020, 3, 6, 0, 0, 0, 0, time.UTC),
+		Positive:            4,
+		Recovered:           1,
+		CumulativePositive:  10,
+		CumulativeRecovered: 3,
+		CumulativeDeceased:  1,
+		Rt:                  &rt,
+	}
+
+	body, err := json.Marshal(nc.TransformToResponse().Legacy())
+	require.NoError(t, err)
+
+	var legacy map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &legacy))
+	assert.Equal(t, 5.0, legacy["hari"])
+	assert.Equal(t, 4.0, legacy["kasus"])
+	assert.Equal(t, 1.0, legacy["sembuh"])
+	assert.Equal(t, 0.0, legacy["meninggal"])
+	assert.Equal(t, 6.0, legacy["dirawat_kumulatif"])
+	assert.Equal(t, 1.1, legacy["rt"])
+	assert.Contains(t, legacy, "rt_upper")
+	assert.NotContains(t, legacy, "positive")
+}
+
+func TestProvinceCaseResponse_Legacy(t *testing.T) {
+	pc := ProvinceCaseWithDate{
+		ProvinceCase: ProvinceCase{
+			Day:                                      3,
+			PersonUnderObservation:                   7,
+			FinishedPersonUnderObservation:           2,
+			CumulativePersonUnderObservation:         20,
+			CumulativeFinishedPersonUnderObservation: 12,
+			Province:                                 &Province{ID: "72", Name: "Sulawesi Tengah"},
+		},
+		Date: time.Date(2020, 3, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	legacy := pc.TransformToResponse().Legacy()
+
+	assert.Equal(t, "72", legacy.ProvinsiID)
+	assert.Equal(t, "Sulawesi Tengah", legacy.Provinsi)
+	assert.Equal(t, int64(7), legacy.ODP)
+	assert.Equal(t, int64(2), legacy.ODPSelesai)
+	assert.Equal(t, int64(20), legacy.ODPKumulatif)
+	assert.Equal(t, int64(12), legacy.ODPSelesaiKumulatif)
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxNoteLength keeps each translation of a note to a short remark
+const maxNoteLength = 500
+
+// NoteScopeNational is the CaseNoteFilter.Scope of the national notes
+const NoteScopeNational = "national"
+
+// NoteText is a note in Indonesian and English; either may be empty
+type NoteText struct {
+	ID string `json:"id,omitempty"`
+	EN string `json:"en,omitempty"`
+}
+
+// CaseNote is analyst commentary on the national data, or a province's when
+// ProvinceID is set, of one date, such as "backlog reported this day". Case
+// responses carry the notes of their date with ?include=notes.
+type CaseNote struct {
+	ID         int64     `json:"id" db:"id"`
+	ProvinceID string    `json:"province_id,omitempty" db:"province_id"`
+	Date       time.Time `json:"date" db:"date"`
+	Text       NoteText  `json:"text"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the note's date and text
+func (n CaseNote) Validate() error {
+	var problems []string
+	if n.Date.IsZero() {
+		problems = append(problems, "date is required")
+	}
+	if strings.TrimSpace(n.Text.ID) == "" && strings.TrimSpace(n.Text.EN) == "" {
+		problems = append(problems, "text needs an id or en translation")
+	}
+	if len(n.Text.ID) > maxNoteLength {
+		problems = append(problems, fmt.Sprintf("text.id must be at most %d characters", maxNoteLength))
+	}
+	if len(n.Text.EN) > maxNoteLength {
+		problems = append(problems, fmt.Sprintf("text.en must be at most %d characters", maxNoteLength))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Key identifies the case row the note belongs to
+func (n CaseNote) Key() string {
+	return noteKey(n.ProvinceID, n.Date)
+}
+
+// noteKey is the CaseNote.Key of a national (empty provinceID) or province case
+// row. Dates are compared by calendar day, as stored.
+func noteKey(provinceID string, date time.Time) string {
+	return provinceID + "/" + date.Format("2006-01-02")
+}
+
+// NoteKey is the CaseNote.Key of the row's notes
+func (c NationalCase) NoteKey() string {
+	return noteKey("", c.Date)
+}
+
+// NoteKey is the CaseNote.Key of the row's notes
+func (c ProvinceCaseWithDate) NoteKey() string {
+	return noteKey(c.ProvinceID, c.Date)
+}
+
+// CaseNoteFilter selects notes; zero fields match all
+type CaseNoteFilter struct {
+	// Scope is NoteScopeNational or a province ID
+	Scope     string
+	StartDate time.Time
+	EndDate   time.Time
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// CaseRevision is one write to a published case row, read from the
+// case_revisions view
+type CaseRevision struct {
+	ID int64 `json:"id"`
+	// SyncRunID and Source identify the run that wrote the revision
+	SyncRunID *int64 `json:"sync_run_id"`
+	Source    string `json:"source"`
+	// Action is insert for the first publication of the row, update for a
+	// correction, and soft_delete or restore when an admin hid the row or
+	// brought it back
+	Action    string        `json:"action"`
+	Changes   []FieldChange `json:"changes"`
+	ChangedAt time.Time     `json:"changed_at"`
+	// RolledBackAt is when the run was rolled back, restoring the values
+	// the revision replaced
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty"`
+}
+
+// FieldChange is the value of a column before and after a revision; Before
+// is null for inserted rows
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// CaseRevisionHistory is the revisions of the case of one day, oldest first
+type CaseRevisionHistory struct {
+	Day       int64          `json:"day"`
+	Date      time.Time      `json:"date"`
+	Revisions []CaseRevision `json:"revisions"`
+}
+
+// untrackedColumns are the row snapshot columns that are not case figures
+var untrackedColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true}
+
+// DiffSnapshots returns the columns whose values differ between two row
+// snapshots, by name. Either snapshot may be nil.
+func DiffSnapshots(before, after map[string]interface{}) []FieldChange {
+	columns := make(map[string]bool, len(after))
+	for column := range before {
+		columns[column] = true
+	}
+	for column := range after {
+		columns[column] = true
+	}
+
+	changes := []FieldChange{}
+	for column := range columns {
+		if untrackedColumns[column] || reflect.DeepEqual(before[column], after[column]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: column, Before: before[column], After: after[column]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	before := map[string]interface{}{"id": int64(1), "positive": int64(10), "rt": nil, "recovered": int64(4), "updated_at": "2021-08-01"}
+	after := map[string]interface{}{"id": int64(1), "positive": int64(12), "rt": 1.1, "recovered": int64(4), "updated_at": "2021-08-02"}
+
+	assert.Equal(t, []FieldChange{
+		{Field: "positive", Before: int64(10), After: int64(12)},
+		{Field: "rt", Before: nil, After: 1.1},
+	}, DiffSnapshots(before, after))
+}
+
+func TestDiffSnapshots_Insert(t *testing.T) {
+	changes := DiffSnapshots(nil, map[string]interface{}{"id": int64(1), "positive": int64(10), "created_at": "2021-08-01"})
+
+	assert.Equal(t, []FieldChange{{Field: "positive", Before: nil, After: int64(10)}}, changes)
+}
+
+func TestDiffSnapshots_NoChanges(t *testing.T) {
+	snapshot := map[string]interface{}{"positive": int64(10)}
+
+	changes := DiffSnapshots(snapshot, snapshot)
+
+	assert.NotNil(t, changes)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,47 @@
+package models
+
+// Row change actions
+const (
+	ChangeActionInsert = "insert"
+	ChangeActionUpdate = "update"
+	ChangeActionDelete = "delete"
+	// ChangeActionSoftDelete hides a row from the read queries by setting
+	// its deleted_at, and ChangeActionRestore clears it again
+	ChangeActionSoftDelete = "soft_delete"
+	ChangeActionRestore    = "restore"
+)
+
+// RowChange describes a single row a mutating operation creates, modifies or removes
+type RowChange struct {
+	Table  string                 `json:"table"`
+	Key    string                 `json:"key"`
+	Action string                 `json:"action"`
+	Before map[string]interface{} `json:"before,omitempty"`
+	After  map[string]interface{} `json:"after,omitempty"`
+}
+
+// ChangeSummary is the diff summary returned by mutating admin endpoints.
+// When DryRun is true nothing was committed.
+type ChangeSummary struct {
+	DryRun   bool        `json:"dry_run"`
+	Inserted int         `json:"inserted"`
+	Updated  int         `json:"updated"`
+	Deleted  int         `json:"deleted"`
+	Restored int         `json:"restored,omitempty"`
+	Changes  []RowChange `json:"changes,omitempty"`
+}
+
+// Add records a change and updates the per-action counters
+func (s *ChangeSummary) Add(change RowChange) {
+	switch change.Action {
+	case ChangeActionInsert:
+		s.Inserted++
+	case ChangeActionUpdate:
+		s.Updated++
+	case ChangeActionDelete, ChangeActionSoftDelete:
+		s.Deleted++
+	case ChangeActionRestore:
+		s.Restored++
+	}
+	s.Changes = append(s.Changes, change)
+}
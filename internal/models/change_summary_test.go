@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeSummary_Add(t *testing.T) {
+	var summary ChangeSummary
+	summary.Add(RowChange{Table: "province_cases", Key: "72:100", Action: ChangeActionInsert})
+	summary.Add(RowChange{Table: "province_cases", Key: "72:101", Action: ChangeActionUpdate})
+	summary.Add(RowChange{Table: "province_cases", Key: "72:102", Action: ChangeActionUpdate})
+	summary.Add(RowChange{Table: "province_cases", Key: "72:103", Action: ChangeActionDelete})
+
+	assert.Equal(t, 1, summary.Inserted)
+	assert.Equal(t, 2, summary.Updated)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.Len(t, summary.Changes, 4)
+}
+
+func TestChangeSummary_Add_SoftDelete(t *testing.T) {
+	var summary ChangeSummary
+	summary.Add(RowChange{Table: "national_cases", Key: "day=7", Action: ChangeActionSoftDelete})
+	summary.Add(RowChange{Table: "national_cases", Key: "day=8", Action: ChangeActionRestore})
+
+	assert.Equal(t, 1, summary.Deleted)
+	assert.Equal(t, 1, summary.Restored)
+	assert.Equal(t, 0, summary.Inserted)
+}
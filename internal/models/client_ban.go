@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ClientBan is a temporary ban applied to a client whose 404 and 429
+// responses looked like scraping
+type ClientBan struct {
+	ClientIP string `json:"client_ip"`
+	// Strikes is the number of 404 and 429 responses that triggered the ban
+	Strikes   int       `json:"strikes"`
+	BannedAt  time.Time `json:"banned_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CaseCursor is the position of a case row in keyset pagination. The ID breaks
+// ties between rows of the same date.
+type CaseCursor struct {
+	Date time.Time
+	ID   int64
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c CaseCursor) Encode() string {
+	raw := c.Date.Format("2006-01-02") + "|" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCaseCursor reads a token created by CaseCursor.Encode
+func DecodeCaseCursor(token string) (CaseCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return CaseCursor{}, ErrInvalidCursor
+	}
+	date, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return CaseCursor{}, ErrInvalidCursor
+	}
+	c := CaseCursor{}
+	if c.Date, err = time.Parse("2006-01-02", date); err != nil {
+		return CaseCursor{}, ErrInvalidCursor
+	}
+	if c.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return CaseCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseCursor_RoundTrip(t *testing.T) {
+	cursor := CaseCursor{Date: time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC), ID: 1234}
+
+	token := cursor.Encode()
+	assert.Equal(t, "MjAyMS0wNi0xNXwxMjM0", token)
+
+	decoded, err := DecodeCaseCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestDecodeCaseCursor_Invalid(t *testing.T) {
+	for _, token := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "MjAyMS0xMy0wMXwx", "MjAyMS0wNi0xNXx4"} {
+		_, err := DecodeCaseCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestCursorPaginationMeta(t *testing.T) {
+	next := &CaseCursor{Date: time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC), ID: 1234}
+
+	assert.Equal(t, PaginationMeta{Limit: 50, HasNext: true, NextCursor: next.Encode()}, CursorPaginationMeta(50, false, next))
+	assert.Equal(t, PaginationMeta{Limit: 50, HasPrev: true}, CursorPaginationMeta(50, true, nil))
+}
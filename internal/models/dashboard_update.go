@@ -0,0 +1,9 @@
+package models
+
+// DashboardUpdate is the message the /ws hub sends on connect and whenever new
+// case data arrives: the latest national numbers and those of the hub's
+// province, shaped like /national/latest and /provinces/latest
+type DashboardUpdate struct {
+	National *NationalCaseResponse   `json:"national"`
+	Province *ProvinceWithLatestCase `json:"province"`
+}
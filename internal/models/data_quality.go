@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// Kinds of data quality issues
+const (
+	// QualityIssueNegativeDaily is a daily count below zero
+	QualityIssueNegativeDaily = "negative_daily"
+	// QualityIssueCumulativeRegression is a cumulative count below that of
+	// the previous date
+	QualityIssueCumulativeRegression = "cumulative_regression"
+	// QualityIssueOutlier is a daily count far from those of the days before
+	QualityIssueOutlier = "outlier"
+)
+
+// QualityFlag is a suspected error in one field of a case row
+type QualityFlag struct {
+	Type  string `json:"type"`
+	Field string `json:"field"`
+	Value int64  `json:"value"`
+	// Reference is what Value is compared to: the previous cumulative count
+	// of a regression or the median of the days before an outlier
+	Reference *float64 `json:"reference,omitempty"`
+	Message   string   `json:"message"`
+}
+
+// RecordQuality is the quality block of a case row with anomalies
+type RecordQuality struct {
+	Flagged bool          `json:"flagged"`
+	Issues  []QualityFlag `json:"issues"`
+}
+
+// QualityIssue is a QualityFlag with the national (empty ProvinceID) or
+// province row it was found in
+type QualityIssue struct {
+	ProvinceID string    `json:"province_id,omitempty"`
+	Day        int64     `json:"day"`
+	Date       time.Time `json:"date"`
+	QualityFlag
+}
+
+// QualityIssueFilter selects data quality issues; zero fields match all
+type QualityIssueFilter struct {
+	// ProvinceID selects a province, or the national rows when it is
+	// QualityScopeNational
+	ProvinceID string
+	Type       string
+	StartDate  time.Time
+	EndDate    time.Time
+	// Limit and Offset select a page of the matching issues
+	Limit  int
+	Offset int
+}
+
+// QualityScopeNational is the QualityIssueFilter.ProvinceID of national rows
+const QualityScopeNational = "national"
+
+// Matches reports whether the filter selects issue
+func (f QualityIssueFilter) Matches(issue QualityIssue) bool {
+	switch f.ProvinceID {
+	case "":
+	case QualityScopeNational:
+		if issue.ProvinceID != "" {
+			return false
+		}
+	default:
+		if issue.ProvinceID != f.ProvinceID {
+			return false
+		}
+	}
+	if f.Type != "" && issue.Type != f.Type {
+		return false
+	}
+	if !f.StartDate.IsZero() && issue.Date.Before(f.StartDate) {
+		return false
+	}
+	if !f.EndDate.IsZero() && issue.Date.After(f.EndDate) {
+		return false
+	}
+	return true
+}
+
+// DataQualityReport is a page of the issues found by the latest scan of the
+// case data, most recent first
+type DataQualityReport struct {
+	// CheckedAt is when the case data was last scanned, nil before the first scan
+	CheckedAt  *time.Time     `json:"checked_at"`
+	Issues     []QualityIssue `json:"issues"`
+	Pagination PaginationMeta `json:"pagination"`
+}
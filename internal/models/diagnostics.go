@@ -0,0 +1,37 @@
+package models
+
+// Statuses of startup diagnostic checks. A fatal check stops the API from
+// starting; a warning is reported and tolerated.
+const (
+	DiagnosticOK      = "ok"
+	DiagnosticWarning = "warning"
+	DiagnosticFatal   = "fatal"
+)
+
+// DiagnosticCheck is one condition checked before the API starts serving
+type DiagnosticCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// DiagnosticsReport is the result of the startup diagnostics. OK is false
+// when any check is fatal.
+type DiagnosticsReport struct {
+	OK       bool              `json:"ok"`
+	Fatal    int               `json:"fatal"`
+	Warnings int               `json:"warnings"`
+	Checks   []DiagnosticCheck `json:"checks"`
+}
+
+// Add appends a check and counts it
+func (r *DiagnosticsReport) Add(c DiagnosticCheck) {
+	switch c.Status {
+	case DiagnosticFatal:
+		r.Fatal++
+	case DiagnosticWarning:
+		r.Warnings++
+	}
+	r.OK = r.Fatal == 0
+	r.Checks = append(r.Checks, c)
+}
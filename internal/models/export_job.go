@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Export job statuses
+const (
+	ExportStatusQueued    = "queued"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// Export scopes
+const (
+	ExportScopeNational = "national"
+	ExportScopeProvince = "province"
+)
+
+// Export file formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// ExportJob tracks an asynchronous export of cases to a downloadable file.
+// TotalRows is known once the first page of cases is read, and ETA is the
+// expected finish estimated from the rows processed so far. File is the key
+// of the export file in the export store.
+type ExportJob struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"`
+	Scope         string     `json:"scope"`
+	ProvinceID    string     `json:"province_id,omitempty"`
+	Format        string     `json:"format"`
+	StartDate     string     `json:"start_date,omitempty"`
+	EndDate       string     `json:"end_date,omitempty"`
+	RowsProcessed int        `json:"rows_processed"`
+	TotalRows     int        `json:"total_rows"`
+	ETA           *time.Time `json:"eta,omitempty"`
+	DownloadURL   string     `json:"download_url,omitempty"`
+	File          string     `json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
@@ -0,0 +1,298 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MaxFieldPaths bounds the paths of a ?fields= list
+const MaxFieldPaths = 50
+
+// FieldSet is a sparse fieldset: the JSON fields a response keeps, written
+// as dot paths such as day or daily.positive. A path to an object keeps all
+// of it. The zero FieldSet keeps every field.
+type FieldSet struct {
+	tree fieldTree
+}
+
+// fieldTree holds the selected fields of an object by JSON name; a nil
+// subtree keeps the whole field
+type fieldTree map[string]fieldTree
+
+// jsonField is a field as encoding/json writes it
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	typ       reflect.Type
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// ParseFieldSet parses a comma-separated list of dot paths, each of which
+// must name a JSON field of values of type t. An empty list keeps every field.
+func ParseFieldSet(list string, t reflect.Type) (FieldSet, error) {
+	var set FieldSet
+	var paths []string
+	for _, path := range strings.Split(list, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return set, nil
+	}
+	if len(paths) > MaxFieldPaths {
+		return set, fmt.Errorf("at most %d fields can be selected", MaxFieldPaths)
+	}
+
+	set.tree = fieldTree{}
+	for _, path := range paths {
+		if err := set.tree.add(path, t); err != nil {
+			return FieldSet{}, err
+		}
+	}
+	return set, nil
+}
+
+// add selects the field at path, checking it exists in values of type t
+func (tree fieldTree) add(path string, t reflect.Type) error {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		t = derefType(t)
+		field, ok := findJSONField(t, name)
+		if !ok {
+			if i == 0 {
+				return fmt.Errorf("unknown field %q, use one of %s", path, strings.Join(jsonFieldNames(t), ", "))
+			}
+			return fmt.Errorf("unknown field %q, %s has %s", path, strings.Join(names[:i], "."), strings.Join(jsonFieldNames(t), ", "))
+		}
+		if i == len(names)-1 {
+			tree[name] = nil
+			return nil
+		}
+		sub, selected := tree[name]
+		if selected && sub == nil {
+			// The whole field is selected already
+			return nil
+		}
+		if !isObject(field.typ) {
+			return fmt.Errorf("unknown field %q, %s has no fields", path, strings.Join(names[:i+1], "."))
+		}
+		if sub == nil {
+			sub = fieldTree{}
+			tree[name] = sub
+		}
+		tree, t = sub, field.typ
+	}
+	return nil
+}
+
+// IsZero reports whether the set keeps every field
+func (f FieldSet) IsZero() bool {
+	return f.tree == nil
+}
+
+// String returns the selected dot paths sorted and comma-separated, so equal
+// sets give equal strings. It is empty for the zero FieldSet.
+func (f FieldSet) String() string {
+	var paths []string
+	f.tree.paths("", &paths)
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+// paths appends the dot paths of the selected fields, each after prefix
+func (tree fieldTree) paths(prefix string, paths *[]string) {
+	for name, sub := range tree {
+		if sub == nil {
+			*paths = append(*paths, prefix+name)
+			continue
+		}
+		sub.paths(prefix+name+".", paths)
+	}
+}
+
+// Select returns value cut down to the selected fields, which keep the order
+// of its type, or value itself when every field is kept
+func (f FieldSet) Select(value interface{}) interface{} {
+	if f.tree == nil {
+		return value
+	}
+	return selectFields(reflect.ValueOf(value), f.tree)
+}
+
+// selectedFields is an object of the selected fields, written in order
+type selectedFields []selectedField
+
+type selectedField struct {
+	name  string
+	value interface{}
+}
+
+// MarshalJSON writes the fields as an object in their order
+func (s selectedFields) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, field := range s {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func selectFields(v reflect.Value, tree fieldTree) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return v.Interface()
+	}
+
+	selected := selectedFields{}
+	for _, field := range jsonFields(v.Type()) {
+		sub, ok := tree[field.name]
+		if !ok {
+			continue
+		}
+		fv, ok := fieldByIndex(v, field.index)
+		if !ok || (field.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		if sub == nil {
+			selected = append(selected, selectedField{field.name, fv.Interface()})
+			continue
+		}
+		selected = append(selected, selectedField{field.name, selectFields(fv, sub)})
+	}
+	return selected
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, reporting false for a field
+// of a nil embedded pointer
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// jsonFields lists the fields of struct type t that encoding/json writes, in
+// order, with the fields of embedded structs promoted
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			if embedded := derefType(sf.Type); embedded.Kind() == reflect.Struct {
+				for _, f := range jsonFields(embedded) {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, jsonField{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+			typ:       sf.Type,
+		})
+	}
+	return fields
+}
+
+func findJSONField(t reflect.Type, name string) (jsonField, bool) {
+	if t.Kind() != reflect.Struct {
+		return jsonField{}, false
+	}
+	for _, f := range jsonFields(t) {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return jsonField{}, false
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for _, f := range jsonFields(t) {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// isObject reports whether values of t are written as an object whose fields
+// can be selected; types with their own encoding, such as time.Time, are not
+func isObject(t reflect.Type) bool {
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return false
+	}
+	return derefType(t).Kind() == reflect.Struct
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isEmptyValue is the omitempty test of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
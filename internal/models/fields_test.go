@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var nationalResponseType = reflect.TypeOf(NationalCaseResponse{})
+
+func selectJSON(t *testing.T, list string, value interface{}) string {
+	t.Helper()
+	fields, err := ParseFieldSet(list, reflect.TypeOf(value))
+	require.NoError(t, err)
+	encoded, err := json.Marshal(fields.Select(value))
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestFieldSet_Select(t *testing.T) {
+	rt := 1.1
+	updated := time.Date(2021, 7, 14, 1, 0, 0, 0, time.UTC)
+	c := NationalCase{Day: 7, Date: time.Date(2021, 7, 13, 0, 0, 0, 0, time.UTC), Positive: 70, Recovered: 20, CumulativePositive: 700, Rt: &rt,
+		RecordTimestamps: RecordTimestamps{UpdatedAt: &updated}}
+	response := c.TransformToResponse()
+
+	// Fields keep the order of the type, not of the list
+	assert.Equal(t, `{"day":7,"date":"2021-07-13T00:00:00Z","daily":{"positive":70},"cumulative":{"positive":700}}`,
+		selectJSON(t, "cumulative.positive, daily.positive,date,day", response))
+	assert.Equal(t, `{"daily":{"positive":70,"recovered":20,"deceased":0,"active":50}}`,
+		selectJSON(t, "daily.positive,daily", response))
+	assert.Equal(t, `{"statistics":{"reproduction_rate":{"value":1.1}}}`,
+		selectJSON(t, "statistics.reproduction_rate.value", response))
+	// Promoted fields of embedded structs, and omitempty is kept
+	assert.Equal(t, `{"updated_at":"2021-07-14T01:00:00Z"}`, selectJSON(t, "created_at,updated_at", response))
+	assert.Equal(t, `{"statistics":{}}`, selectJSON(t, "quality,statistics.testing", response))
+	assert.Equal(t, `{"daily_positive":70,"rt_value":1.1}`, selectJSON(t, "rt_value,daily_positive", response.Flat()))
+}
+
+// Fields inside an object the row does not have are left out with it
+func TestFieldSet_SelectNilObject(t *testing.T) {
+	assert.Equal(t, `{"day":1,"statistics":{}}`, selectJSON(t, "day,statistics.testing.positivity_rate", NationalCaseResponse{Day: 1}))
+}
+
+func TestParseFieldSet(t *testing.T) {
+	fields, err := ParseFieldSet(" , ", nationalResponseType)
+	require.NoError(t, err)
+	assert.True(t, fields.IsZero())
+	response := NationalCaseResponse{Day: 3}
+	assert.Equal(t, response, fields.Select(response))
+
+	for list, message := range map[string]string{
+		"positive":            `unknown field "positive", use one of day, date, daily, cumulative, statistics, quality, notes, created_at, updated_at`,
+		"daily.rt":            `unknown field "daily.rt", daily has positive, recovered, deceased, active`,
+		"day.value":           `unknown field "day.value", day has no fields`,
+		"date.year":           `unknown field "date.year", date has no fields`,
+		"notes.text":          `unknown field "notes.text", notes has no fields`,
+		"daily..positive":     `unknown field "daily..positive", daily has positive, recovered, deceased, active`,
+		"statistics.testing.": `unknown field "statistics.testing.", statistics.testing has daily, cumulative, positivity_rate, cumulative_positivity_rate`,
+	} {
+		_, err := ParseFieldSet(list, nationalResponseType)
+		assert.EqualError(t, err, message, list)
+	}
+
+	many := "day"
+	for i := 0; i < MaxFieldPaths; i++ {
+		many += ",date"
+	}
+	_, err = ParseFieldSet(many, nationalResponseType)
+	assert.Error(t, err)
+}
+
+func TestFieldSet_String(t *testing.T) {
+	a, err := ParseFieldSet("statistics.testing.daily,day,daily.positive,daily", nationalResponseType)
+	require.NoError(t, err)
+	b, err := ParseFieldSet("daily,day,statistics.testing.daily", nationalResponseType)
+	require.NoError(t, err)
+
+	assert.Equal(t, "daily,day,statistics.testing.daily", a.String())
+	assert.Equal(t, a.String(), b.String())
+	assert.Equal(t, "", FieldSet{}.String())
+}
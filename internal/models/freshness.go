@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DataFreshness tells consumers how current a list response is, so stale data
+// can be detected programmatically
+type DataFreshness struct {
+	// LastUpdated is the latest updated_at of the returned rows, null when
+	// none of them has one
+	LastUpdated *time.Time `json:"last_updated"`
+	// DataSource is the source recorded for the latest ingestion run
+	DataSource  string    `json:"data_source"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
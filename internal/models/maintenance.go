@@ -0,0 +1,63 @@
+package models
+
+// RateLimitSnapshot is the state of the rate limiter: every limit with the
+// clients it is currently counting
+type RateLimitSnapshot struct {
+	Enabled               bool `json:"enabled"`
+	MaxConcurrentRequests int  `json:"max_concurrent_requests,omitempty"`
+	// InFlight is the number of requests each client has in flight, when
+	// concurrent requests are capped
+	InFlight map[string]int   `json:"in_flight,omitempty"`
+	Limits   []RateLimitState `json:"limits"`
+}
+
+// RateLimitState is one limit of the rate limiter. Scope is global, the
+// route pattern of a route limit or api_key for the limit of API keys.
+type RateLimitState struct {
+	Scope             string            `json:"scope"`
+	RequestsPerMinute int               `json:"requests_per_minute"`
+	WindowSeconds     int               `json:"window_seconds"`
+	Clients           []RateLimitClient `json:"clients"`
+}
+
+// RateLimitClient is the use of a limit by one client, an IP address or
+// "key:" followed by the hash of an API key
+type RateLimitClient struct {
+	Client    string `json:"client"`
+	Requests  int    `json:"requests"`
+	Remaining int    `json:"remaining"`
+	// Queued counts the requests waiting in the soft limit band
+	Queued int `json:"queued,omitempty"`
+}
+
+// ConfigReload is the result of reloading the configuration: the sections
+// that changed, split into those applied at once and those that only take
+// effect after a restart
+type ConfigReload struct {
+	Changed         []string `json:"changed"`
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// ReaggregationResult is the outcome of each step of a re-aggregation
+type ReaggregationResult struct {
+	Steps []ReaggregationStep `json:"steps"`
+}
+
+// ReaggregationStep is the outcome of one step of a re-aggregation; Error is
+// set when it failed
+type ReaggregationStep struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Failed reports whether any step failed
+func (r ReaggregationResult) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Error != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,9 @@ package models
 
 import (
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -19,6 +21,76 @@ type NationalCase struct {
 	Rt                  *float64  `json:"rt" db:"rt"`
 	RtUpper             *float64  `json:"rt_upper" db:"rt_upper"`
 	RtLower             *float64  `json:"rt_lower" db:"rt_lower"`
+	// Tests are the day's test counts, when reported
+	Tests *DailyTests `json:"tests,omitempty"`
+	// MovingAverage is the rolling average ending on the day, when requested
+	MovingAverage *MovingAverage `json:"moving_average,omitempty"`
+	// Quality lists the anomalies found in the row, when there are any
+	Quality *RecordQuality `json:"quality,omitempty"`
+	// Notes are the analyst notes of the date, when requested
+	Notes []CaseNote `json:"notes,omitempty"`
+	RecordTimestamps
+}
+
+// Validate checks a submitted national case row
+func (c NationalCase) Validate() error {
+	var problems []string
+	if c.Day <= 0 {
+		problems = append(problems, "day must be positive")
+	}
+	if c.Date.IsZero() {
+		problems = append(problems, "date is required")
+	}
+	problems = append(problems, validateCounts(c.Positive, c.Recovered, c.Deceased,
+		c.CumulativePositive, c.CumulativeRecovered, c.CumulativeDeceased)...)
+	problems = append(problems, validateRt(c.Rt, c.RtLower, c.RtUpper)...)
+	return joinProblems(problems)
+}
+
+// validateCounts checks that daily and cumulative counts are non-negative and
+// that each cumulative count covers its daily count
+func validateCounts(positive, recovered, deceased, cumPositive, cumRecovered, cumDeceased int64) []string {
+	var problems []string
+	pairs := []struct {
+		name            string
+		daily, cumulate int64
+	}{
+		{"positive", positive, cumPositive},
+		{"recovered", recovered, cumRecovered},
+		{"deceased", deceased, cumDeceased},
+	}
+	for _, p := range pairs {
+		if p.daily < 0 || p.cumulate < 0 {
+			problems = append(problems, fmt.Sprintf("%s counts must not be negative", p.name))
+		} else if p.cumulate < p.daily {
+			problems = append(problems, fmt.Sprintf("cumulative_%s must be at least %s", p.name, p.name))
+		}
+	}
+	return problems
+}
+
+// validateRt checks that Rt is non-negative and within its bounds when given
+func validateRt(rt, lower, upper *float64) []string {
+	var problems []string
+	for _, v := range []*float64{rt, lower, upper} {
+		if v != nil && *v < 0 {
+			return append(problems, "rt values must not be negative")
+		}
+	}
+	if lower != nil && upper != nil && *lower > *upper {
+		problems = append(problems, "rt_lower must not exceed rt_upper")
+	}
+	if rt != nil && ((lower != nil && *rt < *lower) || (upper != nil && *rt > *upper)) {
+		problems = append(problems, "rt must lie between rt_lower and rt_upper")
+	}
+	return problems
+}
+
+func joinProblems(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
 }
 
 type NullFloat64 struct {
@@ -9,6 +9,11 @@ type NationalCaseResponse struct {
 	Daily      DailyCases             `json:"daily"`
 	Cumulative CumulativeCases        `json:"cumulative"`
 	Statistics NationalCaseStatistics `json:"statistics"`
+	// Quality is only present for rows with anomalies
+	Quality *RecordQuality `json:"quality,omitempty"`
+	// Notes are only present with ?include=notes on dates that have notes
+	Notes []CaseNote `json:"notes,omitempty"`
+	RecordTimestamps
 }
 
 // DailyCases represents new cases for a single day
@@ -31,6 +36,10 @@ type CumulativeCases struct {
 type NationalCaseStatistics struct {
 	Percentages      CasePercentages   `json:"percentages"`
 	ReproductionRate *ReproductionRate `json:"reproduction_rate,omitempty"`
+	// Testing is only present for days with reported tests
+	Testing *TestingStatistics `json:"testing,omitempty"`
+	// MovingAverage is only present when smoothing is requested
+	MovingAverage *MovingAverage `json:"moving_average,omitempty"`
 }
 
 // CasePercentages represents percentage distribution of cases
@@ -80,6 +89,14 @@ func (nc *NationalCase) TransformToResponse() NationalCaseResponse {
 		UpperBound: nc.RtUpper,
 		LowerBound: nc.RtLower,
 	}
+	if nc.Tests != nil {
+		testing := nc.Tests.Statistics(nc.Positive, nc.CumulativePositive)
+		response.Statistics.Testing = &testing
+	}
+	response.Statistics.MovingAverage = nc.MovingAverage
+	response.Quality = nc.Quality
+	response.Notes = nc.Notes
+	response.RecordTimestamps = nc.RecordTimestamps
 
 	return response
 }
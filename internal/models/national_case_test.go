@@ -142,3 +142,21 @@ func TestNullFloat64_Value(t *testing.T) {
 		})
 	}
 }
+
+func TestNationalCase_Validate(t *testing.T) {
+	rt, lower, upper := 1.1, 0.9, 1.3
+	valid := NationalCase{Day: 1, Date: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC), Positive: 2, CumulativePositive: 2,
+		Rt: &rt, RtLower: &lower, RtUpper: &upper}
+	assert.NoError(t, valid.Validate())
+
+	tooHigh := 2.0
+	invalid := NationalCase{Positive: 5, CumulativePositive: 3, Deceased: -1, Rt: &tooHigh, RtLower: &lower, RtUpper: &upper}
+	err := invalid.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "day must be positive")
+		assert.Contains(t, err.Error(), "date is required")
+		assert.Contains(t, err.Error(), "cumulative_positive must be at least positive")
+		assert.Contains(t, err.Error(), "deceased counts must not be negative")
+		assert.Contains(t, err.Error(), "rt must lie between rt_lower and rt_upper")
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Reasons a province case row is considered orphaned
+const (
+	OrphanReasonMissingDay      = "missing_national_day"
+	OrphanReasonMissingProvince = "missing_province"
+)
+
+// OrphanProvinceCase is a province_cases row that the read queries silently drop
+// because its day or province_id has no matching parent row
+type OrphanProvinceCase struct {
+	ID         int64    `json:"id" db:"id"`
+	Day        int64    `json:"day" db:"day"`
+	ProvinceID string   `json:"province_id" db:"province_id"`
+	Reasons    []string `json:"reasons"`
+}
+
+// OrphanReport summarises the referential integrity check of province_cases
+type OrphanReport struct {
+	CheckedAt       time.Time            `json:"checked_at"`
+	MissingDay      int                  `json:"missing_day"`
+	MissingProvince int                  `json:"missing_province"`
+	Rows            []OrphanProvinceCase `json:"rows"`
+}
+
+// NewOrphanReport builds a report with per-reason counts for the given rows
+func NewOrphanReport(rows []OrphanProvinceCase) *OrphanReport {
+	report := &OrphanReport{CheckedAt: time.Now().UTC(), Rows: rows}
+	if report.Rows == nil {
+		report.Rows = []OrphanProvinceCase{}
+	}
+	for _, row := range rows {
+		for _, reason := range row.Reasons {
+			switch reason {
+			case OrphanReasonMissingDay:
+				report.MissingDay++
+			case OrphanReasonMissingProvince:
+				report.MissingProvince++
+			}
+		}
+	}
+	return report
+}
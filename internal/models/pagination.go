@@ -9,6 +9,8 @@ type PaginationMeta struct {
 	Page       int  `json:"page"`
 	HasNext    bool `json:"has_next"`
 	HasPrev    bool `json:"has_prev"`
+	// NextCursor is set in cursor mode while there are more rows
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // PaginatedResponse wraps data with pagination metadata
@@ -32,3 +34,13 @@ func CalculatePaginationMeta(limit, offset, total int) PaginationMeta {
 		HasPrev:    offset > 0,
 	}
 }
+
+// CursorPaginationMeta builds the metadata of a cursor mode page. Totals are
+// not counted in cursor mode, so Total, TotalPages and Page stay zero.
+func CursorPaginationMeta(limit int, hasPrev bool, next *CaseCursor) PaginationMeta {
+	meta := PaginationMeta{Limit: limit, HasPrev: hasPrev, HasNext: next != nil}
+	if next != nil {
+		meta.NextCursor = next.Encode()
+	}
+	return meta
+}
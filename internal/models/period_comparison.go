@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Period is an inclusive date window
+type Period struct {
+	Label     string `json:"label"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// ParsePeriod reads a month (2021-06), a single day (2021-06-15) or a date
+// range (2021-06-01..2021-06-15)
+func ParsePeriod(s string) (Period, error) {
+	s = strings.TrimSpace(s)
+	if start, end, ok := strings.Cut(s, ".."); ok {
+		from, err := time.Parse("2006-01-02", start)
+		if err != nil {
+			return Period{}, fmt.Errorf("period %q has an invalid start date", s)
+		}
+		to, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			return Period{}, fmt.Errorf("period %q has an invalid end date", s)
+		}
+		if to.Before(from) {
+			return Period{}, fmt.Errorf("period %q ends before it starts", s)
+		}
+		return Period{Label: s, StartDate: start, EndDate: end}, nil
+	}
+	if month, err := time.Parse("2006-01", s); err == nil {
+		last := month.AddDate(0, 1, -1)
+		return Period{Label: s, StartDate: month.Format("2006-01-02"), EndDate: last.Format("2006-01-02")}, nil
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return Period{Label: s, StartDate: s, EndDate: s}, nil
+	}
+	return Period{}, fmt.Errorf("period %q must be YYYY-MM, YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD", s)
+}
+
+// PeriodTotals sums the new cases of a period. AverageRt is nil when no day of
+// the period has an Rt estimate.
+type PeriodTotals struct {
+	Period
+	Days                 int      `json:"days"`
+	Positive             int64    `json:"positive"`
+	Recovered            int64    `json:"recovered"`
+	Deceased             int64    `json:"deceased"`
+	MinDailyPositive     int64    `json:"min_daily_positive"`
+	MaxDailyPositive     int64    `json:"max_daily_positive"`
+	AverageDailyPositive float64  `json:"average_daily_positive"`
+	AverageRt            *float64 `json:"average_rt"`
+}
+
+// PeriodChange holds the percentage change of each total from period A to
+// period B. A change is nil when the period A value is zero.
+type PeriodChange struct {
+	Positive             *float64 `json:"positive"`
+	Recovered            *float64 `json:"recovered"`
+	Deceased             *float64 `json:"deceased"`
+	AverageDailyPositive *float64 `json:"average_daily_positive"`
+	AverageRt            *float64 `json:"average_rt"`
+}
+
+// PeriodComparison compares the totals of two periods
+type PeriodComparison struct {
+	PeriodA       PeriodTotals `json:"period_a"`
+	PeriodB       PeriodTotals `json:"period_b"`
+	PercentChange PeriodChange `json:"percent_change"`
+}
+
+// SummarizeNationalPeriod totals the national cases of a period
+func SummarizeNationalPeriod(p Period, cases []NationalCase) PeriodTotals {
+	totals := PeriodTotals{Period: p, Days: len(cases)}
+	var rtSum float64
+	var rtDays int
+	for i, c := range cases {
+		if i == 0 || c.Positive < totals.MinDailyPositive {
+			totals.MinDailyPositive = c.Positive
+		}
+		totals.MaxDailyPositive = max(totals.MaxDailyPositive, c.Positive)
+		totals.Positive += c.Positive
+		totals.Recovered += c.Recovered
+		totals.Deceased += c.Deceased
+		if c.Rt != nil {
+			rtSum += *c.Rt
+			rtDays++
+		}
+	}
+	if totals.Days > 0 {
+		totals.AverageDailyPositive = float64(totals.Positive) / float64(totals.Days)
+	}
+	if rtDays > 0 {
+		avg := rtSum / float64(rtDays)
+		totals.AverageRt = &avg
+	}
+	return totals
+}
+
+// ComparePeriods computes the percentage change from a to b
+func ComparePeriods(a, b PeriodTotals) PeriodComparison {
+	cmp := PeriodComparison{PeriodA: a, PeriodB: b}
+	cmp.PercentChange = PeriodChange{
+		Positive:             percentChange(float64(a.Positive), float64(b.Positive)),
+		Recovered:            percentChange(float64(a.Recovered), float64(b.Recovered)),
+		Deceased:             percentChange(float64(a.Deceased), float64(b.Deceased)),
+		AverageDailyPositive: percentChange(a.AverageDailyPositive, b.AverageDailyPositive),
+	}
+	if a.AverageRt != nil && b.AverageRt != nil {
+		cmp.PercentChange.AverageRt = percentChange(*a.AverageRt, *b.AverageRt)
+	}
+	return cmp
+}
+
+func percentChange(from, to float64) *float64 {
+	if from == 0 {
+		return nil
+	}
+	change := (to - from) / from * 100
+	return &change
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Period
+		wantErr bool
+	}{
+		{input: "2021-06", want: Period{Label: "2021-06", StartDate: "2021-06-01", EndDate: "2021-06-30"}},
+		{input: "2020-02", want: Period{Label: "2020-02", StartDate: "2020-02-01", EndDate: "2020-02-29"}},
+		{input: "2021-06-15", want: Period{Label: "2021-06-15", StartDate: "2021-06-15", EndDate: "2021-06-15"}},
+		{input: "2021-06-01..2021-06-14", want: Period{Label: "2021-06-01..2021-06-14", StartDate: "2021-06-01", EndDate: "2021-06-14"}},
+		{input: "2021-06-14..2021-06-01", wantErr: true},
+		{input: "2021-06-01..june", wantErr: true},
+		{input: "june", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePeriod(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	rt := func(v float64) *float64 { return &v }
+	june := SummarizeNationalPeriod(Period{Label: "2021-06"}, []NationalCase{
+		{Positive: 100, Recovered: 50, Deceased: 0, Rt: rt(1.0)},
+		{Positive: 300, Recovered: 50, Deceased: 0, Rt: rt(1.2)},
+	})
+	july := SummarizeNationalPeriod(Period{Label: "2021-07"}, []NationalCase{
+		{Positive: 200, Recovered: 100, Deceased: 4},
+		{Positive: 400, Recovered: 200, Deceased: 6, Rt: rt(1.65)},
+		{Positive: 0, Recovered: 0, Deceased: 0},
+	})
+
+	assert.Equal(t, 2, june.Days)
+	assert.Equal(t, int64(400), june.Positive)
+	assert.InDelta(t, 200.0, june.AverageDailyPositive, 1e-9)
+	assert.Equal(t, int64(100), june.MinDailyPositive)
+	assert.Equal(t, int64(300), june.MaxDailyPositive)
+	assert.InDelta(t, 1.1, *june.AverageRt, 1e-9)
+
+	cmp := ComparePeriods(june, july)
+	assert.InDelta(t, 50.0, *cmp.PercentChange.Positive, 1e-9)
+	assert.InDelta(t, 200.0, *cmp.PercentChange.Recovered, 1e-9)
+	assert.Nil(t, cmp.PercentChange.Deceased, "no deaths in period A")
+	assert.InDelta(t, 0.0, *cmp.PercentChange.AverageDailyPositive, 1e-9)
+	assert.InDelta(t, 50.0, *cmp.PercentChange.AverageRt, 1e-9)
+
+	cmp = ComparePeriods(june, SummarizeNationalPeriod(Period{}, nil))
+	assert.Nil(t, cmp.PercentChange.AverageRt)
+	assert.InDelta(t, -100.0, *cmp.PercentChange.Positive, 1e-9)
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// ProvincePopulation is the population of a province according to a source
+type ProvincePopulation struct {
+	ProvinceID string `json:"province_id" db:"province_id"`
+	Source     string `json:"source" db:"source"`
+	Year       int    `json:"year" db:"year"`
+	Population int64  `json:"population" db:"population"`
+}
+
+// PerCapita relates cumulative cases and vaccine doses to the population.
+// Rates are per 100,000 people and coverages are percentages of the
+// population. A value is nil when its data is missing.
+type PerCapita struct {
+	Population          int64    `json:"population"`
+	PopulationSource    string   `json:"population_source"`
+	PopulationYear      int      `json:"population_year"`
+	IncidencePer100k    *float64 `json:"incidence_per_100k"`
+	DeathsPer100k       *float64 `json:"deaths_per_100k"`
+	FirstDoseCoverage   *float64 `json:"first_dose_coverage"`
+	SecondDoseCoverage  *float64 `json:"second_dose_coverage"`
+	BoosterDoseCoverage *float64 `json:"booster_dose_coverage"`
+}
+
+// ProvincePerCapita is the per capita view of a province's latest case and
+// vaccination days
+type ProvincePerCapita struct {
+	Province
+	CaseDate        *time.Time `json:"case_date"`
+	VaccinationDate *time.Time `json:"vaccination_date"`
+	PerCapita
+}
+
+// NewPerCapita computes the per capita rates of pop from the latest case and
+// vaccination rows, either of which may be nil
+func NewPerCapita(pop ProvincePopulation, latestCase *ProvinceCaseResponse, vaccine *NationalVaccine) PerCapita {
+	pc := PerCapita{Population: pop.Population, PopulationSource: pop.Source, PopulationYear: pop.Year}
+	if pop.Population <= 0 {
+		return pc
+	}
+	if latestCase != nil {
+		pc.IncidencePer100k = per100k(latestCase.Cumulative.Positive, pop.Population)
+		pc.DeathsPer100k = per100k(latestCase.Cumulative.Deceased, pop.Population)
+	}
+	if vaccine != nil {
+		pc.FirstDoseCoverage = coverage(vaccine.CumulativeFirstVaccinationReceived, pop.Population)
+		pc.SecondDoseCoverage = coverage(vaccine.CumulativeSecondVaccinationReceived, pop.Population)
+		pc.BoosterDoseCoverage = coverage(vaccine.CumulativeBoosterVaccinationReceived, pop.Population)
+	}
+	return pc
+}
+
+func per100k(count, population int64) *float64 {
+	rate := float64(count) / float64(population) * 100000
+	return &rate
+}
+
+func coverage(doses, population int64) *float64 {
+	pct := float64(doses) / float64(population) * 100
+	return &pct
+}
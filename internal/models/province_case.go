@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type ProvinceCase struct {
 	ID                                       int64     `json:"id" db:"id"`
@@ -24,6 +27,44 @@ type ProvinceCase struct {
 	RtUpper                                  *float64  `json:"rt_upper" db:"rt_upper"`
 	RtLower                                  *float64  `json:"rt_lower" db:"rt_lower"`
 	Province                                 *Province `json:"province,omitempty"`
+	// Tests are the day's test counts, when reported
+	Tests *DailyTests `json:"tests,omitempty"`
+	// MovingAverage is the rolling average ending on the day, when requested
+	MovingAverage *MovingAverage `json:"moving_average,omitempty"`
+	// Quality lists the anomalies found in the row, when there are any
+	Quality *RecordQuality `json:"quality,omitempty"`
+	// Notes are the analyst notes of the date, when requested
+	Notes []CaseNote `json:"notes,omitempty"`
+	RecordTimestamps
+}
+
+// Validate checks a submitted province case row
+func (c ProvinceCase) Validate() error {
+	var problems []string
+	if c.Day <= 0 {
+		problems = append(problems, "day must be positive")
+	}
+	if !provinceIDPattern.MatchString(c.ProvinceID) {
+		problems = append(problems, fmt.Sprintf("province_id must be a two digit province ID, got %q", c.ProvinceID))
+	}
+	problems = append(problems, validateCounts(c.Positive, c.Recovered, c.Deceased,
+		c.CumulativePositive, c.CumulativeRecovered, c.CumulativeDeceased)...)
+	observation := []struct {
+		name            string
+		daily, cumulate int64
+	}{
+		{"person_under_observation", c.PersonUnderObservation, c.CumulativePersonUnderObservation},
+		{"finished_person_under_observation", c.FinishedPersonUnderObservation, c.CumulativeFinishedPersonUnderObservation},
+		{"person_under_supervision", c.PersonUnderSupervision, c.CumulativePersonUnderSupervision},
+		{"finished_person_under_supervision", c.FinishedPersonUnderSupervision, c.CumulativeFinishedPersonUnderSupervision},
+	}
+	for _, o := range observation {
+		if o.daily < 0 || o.cumulate < 0 {
+			problems = append(problems, fmt.Sprintf("%s counts must not be negative", o.name))
+		}
+	}
+	problems = append(problems, validateRt(c.Rt, c.RtLower, c.RtUpper)...)
+	return joinProblems(problems)
 }
 
 type ProvinceCaseWithDate struct {
@@ -10,6 +10,11 @@ type ProvinceCaseResponse struct {
 	Cumulative ProvinceCumulativeCases `json:"cumulative"`
 	Statistics ProvinceCaseStatistics  `json:"statistics"`
 	Province   *Province               `json:"province,omitempty"`
+	// Quality is only present for rows with anomalies
+	Quality *RecordQuality `json:"quality,omitempty"`
+	// Notes are only present with ?include=notes on dates that have notes
+	Notes []CaseNote `json:"notes,omitempty"`
+	RecordTimestamps
 }
 
 // ProvinceDailyCases represents new cases for a single day in a province
@@ -62,6 +67,10 @@ type SupervisionData struct {
 type ProvinceCaseStatistics struct {
 	Percentages      CasePercentages   `json:"percentages"`
 	ReproductionRate *ReproductionRate `json:"reproduction_rate"`
+	// Testing is only present for days with reported tests
+	Testing *TestingStatistics `json:"testing,omitempty"`
+	// MovingAverage is only present when smoothing is requested
+	MovingAverage *MovingAverage `json:"moving_average,omitempty"`
 }
 
 // TransformToResponse converts a ProvinceCase model to the response format
@@ -135,6 +144,14 @@ func (pc *ProvinceCase) transformToResponseWithOptions(date time.Time, includePr
 		UpperBound: pc.RtUpper,
 		LowerBound: pc.RtLower,
 	}
+	if pc.Tests != nil {
+		testing := pc.Tests.Statistics(pc.Positive, pc.CumulativePositive)
+		response.Statistics.Testing = &testing
+	}
+	response.Statistics.MovingAverage = pc.MovingAverage
+	response.Quality = pc.Quality
+	response.Notes = pc.Notes
+	response.RecordTimestamps = pc.RecordTimestamps
 
 	return response
 }
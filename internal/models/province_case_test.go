@@ -91,3 +91,13 @@ func TestProvinceCaseWithDate_Structure(t *testing.T) {
 	assert.NotNil(t, provinceCaseWithDate.Rt)
 	assert.Equal(t, 1.1, *provinceCaseWithDate.Rt)
 }
+
+func TestProvinceCase_Validate(t *testing.T) {
+	assert.NoError(t, ProvinceCase{Day: 1, ProvinceID: "72", Positive: 1, CumulativePositive: 4}.Validate())
+
+	err := ProvinceCase{Day: 1, ProvinceID: "sulteng", PersonUnderObservation: -2}.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `got "sulteng"`)
+		assert.Contains(t, err.Error(), "person_under_observation counts must not be negative")
+	}
+}
@@ -4,4 +4,24 @@ package models
 type ProvinceWithLatestCase struct {
 	Province
 	LatestCase *ProvinceCaseResponse `json:"latest_case,omitempty"`
+	// DataUnavailable is set when LatestCase is missing, either because the
+	// province has no case data or because it failed to load, so missing
+	// data is not mistaken for zero cases
+	DataUnavailable bool `json:"data_unavailable,omitempty"`
+	// Error explains why the latest case failed to load
+	Error string `json:"error,omitempty"`
+	// PerCapita is set when the population of the province is known
+	PerCapita *PerCapita `json:"per_capita,omitempty"`
+}
+
+// FailedProvinces returns the IDs of the provinces whose latest case failed
+// to load
+func FailedProvinces(provinces []ProvinceWithLatestCase) []string {
+	var failed []string
+	for _, p := range provinces {
+		if p.Error != "" {
+			failed = append(failed, p.ID)
+		}
+	}
+	return failed
 }
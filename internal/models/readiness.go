@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Readiness check statuses. A degraded check is failing but tolerated, so it
+// does not make the API unready.
+const (
+	ReadinessOK       = "ok"
+	ReadinessDegraded = "degraded"
+	ReadinessFailing  = "failing"
+)
+
+// Readiness is the result of the readiness probe
+type Readiness struct {
+	Ready  bool             `json:"ready"`
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+// ReadinessCheck is one condition of readiness
+type ReadinessCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Since is when a degraded or failing check started failing, if known
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// SchemaColumn is a column of a table or view in the current schema
+type SchemaColumn struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+}
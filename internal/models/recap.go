@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Recap scopes
+const (
+	RecapScopeNational = "national"
+	RecapScopeProvince = "province"
+)
+
+// RecapDay is a notable day of a recap
+type RecapDay struct {
+	Day      int64     `json:"day"`
+	Date     time.Time `json:"date"`
+	Positive int64     `json:"positive"`
+}
+
+// RecapMonth holds the new cases of a month, e.g. "2021-03"
+type RecapMonth struct {
+	Month     string `json:"month"`
+	Positive  int64  `json:"positive"`
+	Recovered int64  `json:"recovered"`
+	Deceased  int64  `json:"deceased"`
+}
+
+// RecapWave is a period in which the 7-day average of new positive cases
+// stayed high. End is nil when the wave lasted past the end of the year.
+type RecapWave struct {
+	Start       time.Time  `json:"start"`
+	Peak        time.Time  `json:"peak"`
+	End         *time.Time `json:"end"`
+	PeakAverage float64    `json:"peak_average"`
+}
+
+// RecapVaccination holds the doses given during the year and the cumulative
+// doses and coverage at its last recorded day
+type RecapVaccination struct {
+	FirstDoses             int64   `json:"first_doses"`
+	SecondDoses            int64   `json:"second_doses"`
+	BoosterDoses           int64   `json:"booster_doses"`
+	CumulativeFirstDoses   int64   `json:"cumulative_first_doses"`
+	CumulativeSecondDoses  int64   `json:"cumulative_second_doses"`
+	CumulativeBoosterDoses int64   `json:"cumulative_booster_doses"`
+	Target                 int64   `json:"target"`
+	FirstDoseCoverage      float64 `json:"first_dose_coverage"`
+	SecondDoseCoverage     float64 `json:"second_dose_coverage"`
+	BoosterDoseCoverage    float64 `json:"booster_dose_coverage"`
+}
+
+// Recap summarizes a year of a scope for the annual retrospective
+type Recap struct {
+	Year       int    `json:"year"`
+	Scope      string `json:"scope"`
+	ProvinceID string `json:"province_id,omitempty"`
+	Days       int    `json:"days"`
+	// NewCases are the cases reported during the year, Cumulative the totals
+	// at its last recorded day
+	NewCases    CaseTotals        `json:"new_cases"`
+	Cumulative  CaseTotals        `json:"cumulative"`
+	WorstDay    *RecapDay         `json:"worst_day"`
+	BestMonth   *RecapMonth       `json:"best_month"`
+	AverageRt   *float64          `json:"average_rt"`
+	WaveCount   int               `json:"wave_count"`
+	Waves       []RecapWave       `json:"waves"`
+	Vaccination *RecapVaccination `json:"vaccination"`
+}
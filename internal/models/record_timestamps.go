@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RecordTimestamps are when a case row was stored and last changed. Rows
+// written before the columns were filled have neither.
+type RecordTimestamps struct {
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// ClearTimestamps removes the timestamps, which responses only carry when
+// asked for with ?include=timestamps
+func (t *RecordTimestamps) ClearTimestamps() {
+	t.CreatedAt, t.UpdatedAt = nil, nil
+}
+
+// LastUpdated returns when the row last changed, nil when unknown
+func (t RecordTimestamps) LastUpdated() *time.Time {
+	return t.UpdatedAt
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ReportDay is one day of the trend chart of a report
+type ReportDay struct {
+	Date     time.Time `json:"date"`
+	Positive int64     `json:"positive"`
+	Rt       *float64  `json:"rt"`
+}
+
+// DailyReport is the situational report of a province for one day
+type DailyReport struct {
+	ProvinceID   string            `json:"province_id"`
+	ProvinceName string            `json:"province_name"`
+	Day          int64             `json:"day"`
+	Date         time.Time         `json:"date"`
+	NewCases     CaseTotals        `json:"new_cases"`
+	Cumulative   CaseTotals        `json:"cumulative"`
+	Rt           *ReproductionRate `json:"rt"`
+	// WeekPositive are the new positive cases of the seven days up to Date
+	// and PreviousWeekPositive those of the seven days before.
+	// WeekChangePercent is nil when there is no data from the first day of
+	// the previous week or it had no cases.
+	WeekPositive         int64    `json:"week_positive"`
+	PreviousWeekPositive int64    `json:"previous_week_positive"`
+	WeekChangePercent    *float64 `json:"week_change_percent"`
+	// Trend holds the days of both weeks in chronological order
+	Trend []ReportDay `json:"trend"`
+}
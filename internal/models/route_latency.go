@@ -0,0 +1,12 @@
+package models
+
+// RouteLatency summarises the response time distribution of a single route
+type RouteLatency struct {
+	Route  string  `json:"route"`
+	Count  int64   `json:"count"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+	MeanMs float64 `json:"mean_ms"`
+}
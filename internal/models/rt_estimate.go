@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RtEstimationSource is the sync_log source of the runs writing estimated Rt
+const RtEstimationSource = "rt-estimation"
+
+// CaseSeriesDay is one day of a case series
+type CaseSeriesDay struct {
+	// Day is the day column of the row, the national case ID for provinces
+	Day      int64
+	Date     time.Time
+	Positive int64
+	// HasRt is set when the row already has an Rt value
+	HasRt bool
+}
+
+// CaseSeries is the daily new positive cases of the nation, with an empty
+// ProvinceID, or of a province, ordered by date
+type CaseSeries struct {
+	ProvinceID string
+	Days       []CaseSeriesDay
+}
+
+// RtEstimate is an estimated Rt, with its 95% credible interval, to write to
+// a national (empty ProvinceID) or province case row
+type RtEstimate struct {
+	ProvinceID string
+	Day        int64
+	Rt         float64
+	RtLower    float64
+	RtUpper    float64
+}
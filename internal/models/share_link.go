@@ -0,0 +1,144 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShareMetrics are the metrics a share link can select for display
+var ShareMetrics = map[string]bool{
+	"positive":  true,
+	"recovered": true,
+	"deceased":  true,
+	"active":    true,
+	"odp":       true,
+	"pdp":       true,
+	"rt":        true,
+}
+
+// ShareFilters is the filtered view stored behind a share link. An empty
+// ProvinceID selects the national series; empty dates leave the range open.
+type ShareFilters struct {
+	ProvinceID string   `json:"province_id,omitempty"`
+	StartDate  string   `json:"start_date,omitempty"`
+	EndDate    string   `json:"end_date,omitempty"`
+	Metrics    []string `json:"metrics,omitempty"`
+}
+
+// Normalize trims the filters and sorts and deduplicates the metrics, so equal
+// views have equal filters
+func (f ShareFilters) Normalize() ShareFilters {
+	n := ShareFilters{
+		ProvinceID: strings.TrimSpace(f.ProvinceID),
+		StartDate:  strings.TrimSpace(f.StartDate),
+		EndDate:    strings.TrimSpace(f.EndDate),
+	}
+	seen := make(map[string]bool, len(f.Metrics))
+	for _, m := range f.Metrics {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		n.Metrics = append(n.Metrics, m)
+	}
+	sort.Strings(n.Metrics)
+	return n
+}
+
+// Validate checks the province ID, the date range and the metrics
+func (f ShareFilters) Validate() error {
+	var problems []string
+	if f.ProvinceID != "" && !provinceIDPattern.MatchString(f.ProvinceID) {
+		problems = append(problems, fmt.Sprintf("province_id must be a two digit province ID, got %q", f.ProvinceID))
+	}
+	start, startErr := parseShareDate("start_date", f.StartDate)
+	end, endErr := parseShareDate("end_date", f.EndDate)
+	for _, err := range []error{startErr, endErr} {
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if startErr == nil && endErr == nil && !start.IsZero() && !end.IsZero() && end.Before(start) {
+		problems = append(problems, "end_date must not be before start_date")
+	}
+	for _, m := range f.Metrics {
+		if !ShareMetrics[m] {
+			problems = append(problems, fmt.Sprintf("unknown metric %q", m))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func parseShareDate(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be YYYY-MM-DD, got %q", field, value)
+	}
+	return t, nil
+}
+
+// Hash identifies the normalized filters, so the same view always gets the same link
+func (f ShareFilters) Hash() string {
+	canonical := strings.Join([]string{f.ProvinceID, f.StartDate, f.EndDate, strings.Join(f.Metrics, ",")}, "|")
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// Query encodes the filters as query parameters, with metrics comma separated
+func (f ShareFilters) Query() url.Values {
+	q := url.Values{}
+	if f.ProvinceID != "" {
+		q.Set("province", f.ProvinceID)
+	}
+	if f.StartDate != "" {
+		q.Set("start_date", f.StartDate)
+	}
+	if f.EndDate != "" {
+		q.Set("end_date", f.EndDate)
+	}
+	if len(f.Metrics) > 0 {
+		q.Set("metrics", strings.Join(f.Metrics, ","))
+	}
+	return q
+}
+
+// CasesPath is the API request returning the cases of the view
+func (f ShareFilters) CasesPath() string {
+	path := "/api/v1/national"
+	if f.ProvinceID != "" {
+		path = "/api/v1/provinces/" + f.ProvinceID + "/cases"
+	}
+	q := url.Values{}
+	if f.StartDate != "" && f.EndDate != "" {
+		q.Set("start_date", f.StartDate)
+		q.Set("end_date", f.EndDate)
+	}
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
+}
+
+// ShareLink stores a filtered view under a short code. URL is the public
+// short link and is filled in by the service, not stored.
+type ShareLink struct {
+	ID          int64        `json:"-" db:"id"`
+	Code        string       `json:"code" db:"code"`
+	Filters     ShareFilters `json:"filters" db:"filters"`
+	FiltersHash string       `json:"-" db:"filters_hash"`
+	URL         string       `json:"url"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+}
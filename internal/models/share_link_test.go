@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareFilters_Normalize(t *testing.T) {
+	f := ShareFilters{ProvinceID: " 72 ", Metrics: []string{"rt", "Positive", "rt", " "}}.Normalize()
+
+	assert.Equal(t, "72", f.ProvinceID)
+	assert.Equal(t, []string{"positive", "rt"}, f.Metrics)
+}
+
+func TestShareFilters_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters ShareFilters
+		wantErr string
+	}{
+		{"empty filters", ShareFilters{}, ""},
+		{"full filters", ShareFilters{ProvinceID: "72", StartDate: "2021-06-01", EndDate: "2021-08-31", Metrics: []string{"rt"}}, ""},
+		{"open range", ShareFilters{StartDate: "2021-06-01"}, ""},
+		{"bad province", ShareFilters{ProvinceID: "sulteng"}, "province_id"},
+		{"bad date", ShareFilters{StartDate: "01-06-2021"}, "start_date must be YYYY-MM-DD"},
+		{"reversed range", ShareFilters{StartDate: "2021-08-31", EndDate: "2021-06-01"}, "end_date must not be before start_date"},
+		{"unknown metric", ShareFilters{Metrics: []string{"vaccinated"}}, `unknown metric "vaccinated"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filters.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestShareFilters_Hash(t *testing.T) {
+	a := ShareFilters{ProvinceID: "72", Metrics: []string{"rt", "positive"}}.Normalize()
+	b := ShareFilters{ProvinceID: "72", Metrics: []string{"positive", "rt"}}.Normalize()
+	c := ShareFilters{ProvinceID: "73", Metrics: []string{"positive", "rt"}}.Normalize()
+
+	assert.Equal(t, a.Hash(), b.Hash())
+	assert.NotEqual(t, a.Hash(), c.Hash())
+}
+
+func TestShareFilters_CasesPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/national", ShareFilters{}.CasesPath())
+	assert.Equal(t, "/api/v1/provinces/72/cases?end_date=2021-08-31&start_date=2021-06-01",
+		ShareFilters{ProvinceID: "72", StartDate: "2021-06-01", EndDate: "2021-08-31"}.CasesPath())
+	assert.Equal(t, "/api/v1/provinces/72/cases", ShareFilters{ProvinceID: "72", StartDate: "2021-06-01"}.CasesPath())
+}
+
+func TestShareFilters_Query(t *testing.T) {
+	q := ShareFilters{ProvinceID: "72", EndDate: "2021-08-31", Metrics: []string{"positive", "rt"}}.Query()
+
+	assert.Equal(t, "end_date=2021-08-31&metrics=positive%2Crt&province=72", q.Encode())
+}
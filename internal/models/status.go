@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Province statuses of the traffic-light overview
+const (
+	StatusControlled = "controlled"
+	StatusCaution    = "caution"
+	StatusCritical   = "critical"
+)
+
+// StatusMethodology explains how StatusThresholds.Classify picks a status
+const StatusMethodology = "Each province is classified from the 7 days of data ending at the latest date with data. " +
+	"The rolling Rt is the average of the daily Rt estimates in that window and the weekly incidence is the number of new positive cases in it. " +
+	"A province is critical when the rolling Rt or the weekly incidence reaches its critical threshold, caution when either reaches its caution threshold " +
+	"and controlled otherwise. Without Rt estimates in the window only the incidence is used. " +
+	"Incidence is an absolute count because population figures are not available, so thresholds are best tuned to the provinces served."
+
+// StatusThresholds are the values at which a province becomes caution or
+// critical
+type StatusThresholds struct {
+	RtCaution         float64 `json:"rt_caution"`
+	RtCritical        float64 `json:"rt_critical"`
+	IncidenceCaution  float64 `json:"incidence_caution"`
+	IncidenceCritical float64 `json:"incidence_critical"`
+}
+
+// Classify returns the status of a rolling Rt, nil without estimates, and a
+// weekly incidence
+func (t StatusThresholds) Classify(rollingRt *float64, weeklyIncidence int64) string {
+	incidence := float64(weeklyIncidence)
+	switch {
+	case rollingRt != nil && *rollingRt >= t.RtCritical, incidence >= t.IncidenceCritical:
+		return StatusCritical
+	case rollingRt != nil && *rollingRt >= t.RtCaution, incidence >= t.IncidenceCaution:
+		return StatusCaution
+	default:
+		return StatusControlled
+	}
+}
+
+// ProvinceStatus is the traffic-light status of a province
+type ProvinceStatus struct {
+	Province        Province  `json:"province"`
+	Date            time.Time `json:"date"`
+	Status          string    `json:"status"`
+	RollingRt       *float64  `json:"rolling_rt"`
+	WeeklyIncidence int64     `json:"weekly_incidence"`
+}
+
+// StatusOverview holds the status of every province with the thresholds and
+// methodology used
+type StatusOverview struct {
+	Methodology string           `json:"methodology"`
+	Thresholds  StatusThresholds `json:"thresholds"`
+	WindowStart *time.Time       `json:"window_start"`
+	WindowEnd   *time.Time       `json:"window_end"`
+	Provinces   []ProvinceStatus `json:"provinces"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusThresholds_Classify(t *testing.T) {
+	thresholds := StatusThresholds{RtCaution: 1.0, RtCritical: 1.2, IncidenceCaution: 50, IncidenceCritical: 200}
+	rt := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		rt        *float64
+		incidence int64
+		want      string
+	}{
+		{"low rt and incidence", rt(0.8), 10, StatusControlled},
+		{"rt at caution", rt(1.0), 10, StatusCaution},
+		{"incidence at caution", rt(0.8), 50, StatusCaution},
+		{"rt at critical", rt(1.2), 10, StatusCritical},
+		{"incidence critical with low rt", rt(0.5), 250, StatusCritical},
+		{"no rt uses incidence only", nil, 60, StatusCaution},
+		{"no rt and low incidence", nil, 0, StatusControlled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, thresholds.Classify(tt.rt, tt.incidence))
+		})
+	}
+}
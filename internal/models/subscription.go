@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Email subscription statuses
+const (
+	SubscriptionPending      = "pending"
+	SubscriptionConfirmed    = "confirmed"
+	SubscriptionUnsubscribed = "unsubscribed"
+)
+
+// EmailSubscription is a citizen's subscription to daily Sulawesi Tengah
+// updates. It only receives updates once confirmed; LastSentDay is the day of
+// the latest update emailed.
+type EmailSubscription struct {
+	ID                 int64      `json:"id" db:"id"`
+	Email              string     `json:"email" db:"email"`
+	Status             string     `json:"status" db:"status"`
+	ConfirmToken       string     `json:"-" db:"confirm_token"`
+	UnsubscribeToken   string     `json:"-" db:"unsubscribe_token"`
+	ConfirmationSentAt time.Time  `json:"confirmation_sent_at" db:"confirmation_sent_at"`
+	ConfirmedAt        *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	UnsubscribedAt     *time.Time `json:"unsubscribed_at,omitempty" db:"unsubscribed_at"`
+	LastSentDay        *int64     `json:"last_sent_day,omitempty" db:"last_sent_day"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+}
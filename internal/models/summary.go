@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// CaseTotals are positive, recovered, deceased and active case counts
+type CaseTotals struct {
+	Positive  int64 `json:"positive"`
+	Recovered int64 `json:"recovered"`
+	Deceased  int64 `json:"deceased"`
+	Active    int64 `json:"active"`
+}
+
+// MovingAverage is the average number of new cases per day over the last Days
+// days with data
+type MovingAverage struct {
+	Days      int     `json:"days"`
+	Positive  float64 `json:"positive"`
+	Recovered float64 `json:"recovered"`
+	Deceased  float64 `json:"deceased"`
+}
+
+// NationalSummary holds the latest national totals
+type NationalSummary struct {
+	Day             int64          `json:"day"`
+	Date            time.Time      `json:"date"`
+	Totals          CaseTotals     `json:"totals"`
+	NewCases        CaseTotals     `json:"new_cases"`
+	MovingAverage7d *MovingAverage `json:"moving_average_7d,omitempty"`
+}
+
+// ProvinceSummary holds the latest totals of a province
+type ProvinceSummary struct {
+	Province        Province       `json:"province"`
+	Day             int64          `json:"day"`
+	Date            time.Time      `json:"date"`
+	Totals          CaseTotals     `json:"totals"`
+	NewCases        CaseTotals     `json:"new_cases"`
+	MovingAverage7d *MovingAverage `json:"moving_average_7d,omitempty"`
+}
+
+// Summary aggregates the latest national and province figures. LastUpdated
+// is the most recent date with data.
+type Summary struct {
+	LastUpdated *time.Time        `json:"last_updated"`
+	National    *NationalSummary  `json:"national"`
+	Provinces   []ProvinceSummary `json:"provinces"`
+}
+
+// Add includes a day of new cases in the average
+func (m *MovingAverage) Add(positive, recovered, deceased int64) {
+	n := float64(m.Days)
+	m.Days++
+	m.Positive = (m.Positive*n + float64(positive)) / float64(m.Days)
+	m.Recovered = (m.Recovered*n + float64(recovered)) / float64(m.Days)
+	m.Deceased = (m.Deceased*n + float64(deceased)) / float64(m.Days)
+}
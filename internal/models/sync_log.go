@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sync run statuses
+const (
+	SyncStatusRunning    = "running"
+	SyncStatusCompleted  = "completed"
+	SyncStatusFailed     = "failed"
+	SyncStatusRolledBack = "rolled_back"
+)
+
+// SoftDeleteSource is the sync_log source of the runs soft-deleting or
+// restoring case rows
+const SoftDeleteSource = "soft-delete"
+
+// SyncRun is a single ingestion run recorded in the sync_log table
+type SyncRun struct {
+	ID           int64      `json:"id" db:"id"`
+	Source       string     `json:"source" db:"source"`
+	Status       string     `json:"status" db:"status"`
+	Inserted     int        `json:"inserted" db:"inserted"`
+	Updated      int        `json:"updated" db:"updated"`
+	Conflicts    int        `json:"conflicts" db:"conflicts"`
+	StartedAt    time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty" db:"rolled_back_at"`
+}
+
+// Revision records the before/after state of a row touched by a sync run
+type Revision struct {
+	ID        int64                  `json:"id" db:"id"`
+	SyncRunID int64                  `json:"sync_run_id" db:"sync_log_id"`
+	TableName string                 `json:"table_name" db:"table_name"`
+	RowID     int64                  `json:"row_id" db:"row_id"`
+	Action    string                 `json:"action" db:"action"`
+	Before    map[string]interface{} `json:"before,omitempty" db:"before_data"`
+	After     map[string]interface{} `json:"after,omitempty" db:"after_data"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+// IngestionBatch is a set of upstream rows written by a single sync run
+type IngestionBatch struct {
+	Source   string         `json:"source"`
+	National []NationalCase `json:"national"`
+	Province []ProvinceCase `json:"province"`
+}
+
+// Validate checks every row of the batch and reports the offending rows by index
+func (b IngestionBatch) Validate() error {
+	var problems []string
+	if b.Source == "" {
+		problems = append(problems, "source is required")
+	}
+	for i, c := range b.National {
+		if err := c.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("national[%d]: %v", i, err))
+		}
+	}
+	for i, c := range b.Province {
+		if err := c.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("province[%d]: %v", i, err))
+		}
+	}
+	return joinProblems(problems)
+}
+
+// SyncConflict describes an incoming row that was rejected instead of written
+type SyncConflict struct {
+	Table  string `json:"table"`
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// CaseChange is a national or province case row inserted, updated or restored
+// by a sync run. An update of an existing row is a correction of published
+// data; a restore brings back a soft-deleted row resubmitted by the run.
+// PreviousRt is the row's Rt before an update, or the Rt of the latest earlier
+// day of the same scope for an insert.
+type CaseChange struct {
+	Table      string   `json:"table"`
+	Key        string   `json:"key"`
+	Action     string   `json:"action"`
+	Day        int64    `json:"day"`
+	ProvinceID string   `json:"province_id,omitempty"`
+	Rt         *float64 `json:"rt"`
+	PreviousRt *float64 `json:"previous_rt"`
+}
+
+// IsCorrection reports whether the change updated an already published row
+func (c CaseChange) IsCorrection() bool {
+	return c.Action == ChangeActionUpdate
+}
+
+// RtCrossesOne reports whether Rt moved from below 1 to 1 or above, or back
+func (c CaseChange) RtCrossesOne() bool {
+	if c.Rt == nil || c.PreviousRt == nil {
+		return false
+	}
+	return (*c.PreviousRt < 1) != (*c.Rt < 1)
+}
+
+// SyncSummary reports the outcome of an ingestion run. When DryRun is true
+// nothing was committed and no run was recorded.
+type SyncSummary struct {
+	DryRun    bool           `json:"dry_run,omitempty"`
+	RunID     int64          `json:"run_id"`
+	Inserted  int            `json:"inserted"`
+	Updated   int            `json:"updated"`
+	Restored  int            `json:"restored,omitempty"`
+	Unchanged int            `json:"unchanged"`
+	Conflicts []SyncConflict `json:"conflicts"`
+	Changes   []CaseChange   `json:"changes,omitempty"`
+}
+
+// AddConflict records a rejected row
+func (s *SyncSummary) AddConflict(table, key, reason string) {
+	s.Conflicts = append(s.Conflicts, SyncConflict{Table: table, Key: key, Reason: reason})
+}
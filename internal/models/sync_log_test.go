@@ -0,0 +1,19 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngestionBatch_Validate(t *testing.T) {
+	batch := IngestionBatch{Province: []ProvinceCase{{Day: 1, ProvinceID: "72"}, {Day: 0, ProvinceID: "72"}}}
+
+	err := batch.Validate()
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "source is required")
+		assert.Contains(t, err.Error(), "province[1]: day must be positive")
+		assert.NotContains(t, err.Error(), "province[0]")
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "strings"
+
+// TableIndex is a secondary index on a table, with its columns in key order
+type TableIndex struct {
+	Table   string   `json:"table"`
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	// Unique indexes also enforce that no two rows share the columns' values
+	Unique bool `json:"unique,omitempty"`
+}
+
+// CoveredBy reports whether other serves the same lookups, i.e. it is on the same
+// table and its leading columns match this index's columns in order. A unique
+// index is only covered by a unique index on exactly its columns.
+func (i TableIndex) CoveredBy(other TableIndex) bool {
+	if i.Table != other.Table || len(other.Columns) < len(i.Columns) {
+		return false
+	}
+	if i.Unique && (!other.Unique || len(other.Columns) != len(i.Columns)) {
+		return false
+	}
+	for n, c := range i.Columns {
+		if !strings.EqualFold(c, other.Columns[n]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// TableStats holds the row count and on-disk size of a single database table
+type TableStats struct {
+	Name       string `json:"name"`
+	Rows       int64  `json:"rows"`
+	DataBytes  int64  `json:"data_bytes"`
+	IndexBytes int64  `json:"index_bytes"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+// TableStatsSnapshot is a point-in-time collection of table statistics
+type TableStatsSnapshot struct {
+	CollectedAt      time.Time    `json:"collected_at"`
+	TotalRows        int64        `json:"total_rows"`
+	TotalBytes       int64        `json:"total_bytes"`
+	QuotaBytes       int64        `json:"quota_bytes,omitempty"`
+	QuotaUsedPercent float64      `json:"quota_used_percent,omitempty"`
+	Tables           []TableStats `json:"tables"`
+	Warnings         []string     `json:"warnings,omitempty"`
+}
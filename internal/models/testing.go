@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// DailyTests are the PCR and antigen tests reported for a day. ProvinceID is
+// empty for national totals.
+type DailyTests struct {
+	ID                int64     `json:"id" db:"id"`
+	ProvinceID        string    `json:"province_id,omitempty" db:"province_id"`
+	Date              time.Time `json:"date" db:"date"`
+	PCR               int64     `json:"pcr" db:"pcr"`
+	Antigen           int64     `json:"antigen" db:"antigen"`
+	CumulativePCR     int64     `json:"cumulative_pcr" db:"cumulative_pcr"`
+	CumulativeAntigen int64     `json:"cumulative_antigen" db:"cumulative_antigen"`
+}
+
+// TestCounts are PCR and antigen test counts with their sum
+type TestCounts struct {
+	PCR     int64 `json:"pcr"`
+	Antigen int64 `json:"antigen"`
+	Total   int64 `json:"total"`
+}
+
+// TestingStatistics are the tests of a day and the share of them that came
+// back positive. A positivity rate is nil when no tests were reported.
+type TestingStatistics struct {
+	Daily                    TestCounts `json:"daily"`
+	Cumulative               TestCounts `json:"cumulative"`
+	PositivityRate           *float64   `json:"positivity_rate"`
+	CumulativePositivityRate *float64   `json:"cumulative_positivity_rate"`
+}
+
+// TestingDay is a day of national testing
+type TestingDay struct {
+	Date time.Time `json:"date"`
+	TestingStatistics
+}
+
+// Statistics computes positivity rates, as percentages, from the positive
+// cases of the same day
+func (t DailyTests) Statistics(dailyPositive, cumulativePositive int64) TestingStatistics {
+	stats := TestingStatistics{
+		Daily:      TestCounts{PCR: t.PCR, Antigen: t.Antigen, Total: t.PCR + t.Antigen},
+		Cumulative: TestCounts{PCR: t.CumulativePCR, Antigen: t.CumulativeAntigen, Total: t.CumulativePCR + t.CumulativeAntigen},
+	}
+	stats.PositivityRate = positivityRate(dailyPositive, stats.Daily.Total)
+	stats.CumulativePositivityRate = positivityRate(cumulativePositive, stats.Cumulative.Total)
+	return stats
+}
+
+func positivityRate(positive, tests int64) *float64 {
+	if tests <= 0 {
+		return nil
+	}
+	rate := float64(positive) / float64(tests) * 100
+	return &rate
+}
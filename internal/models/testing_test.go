@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyTests_Statistics(t *testing.T) {
+	tests := DailyTests{PCR: 150, Antigen: 50, CumulativePCR: 1500, CumulativeAntigen: 500}
+
+	stats := tests.Statistics(20, 100)
+
+	assert.Equal(t, TestCounts{PCR: 150, Antigen: 50, Total: 200}, stats.Daily)
+	assert.Equal(t, TestCounts{PCR: 1500, Antigen: 500, Total: 2000}, stats.Cumulative)
+	require.NotNil(t, stats.PositivityRate)
+	assert.InDelta(t, 10.0, *stats.PositivityRate, 0.0001)
+	require.NotNil(t, stats.CumulativePositivityRate)
+	assert.InDelta(t, 5.0, *stats.CumulativePositivityRate, 0.0001)
+}
+
+func TestDailyTests_Statistics_NoTests(t *testing.T) {
+	stats := DailyTests{}.Statistics(20, 100)
+
+	assert.Nil(t, stats.PositivityRate)
+	assert.Nil(t, stats.CumulativePositivityRate)
+}
+
+func TestTransformToResponse_Testing(t *testing.T) {
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	nc := NationalCase{Date: date, Positive: 50, CumulativePositive: 500}
+	assert.Nil(t, nc.TransformToResponse().Statistics.Testing)
+
+	nc.Tests = &DailyTests{Date: date, PCR: 400, Antigen: 100, CumulativePCR: 4000, CumulativeAntigen: 1000}
+	testing := nc.TransformToResponse().Statistics.Testing
+	require.NotNil(t, testing)
+	assert.Equal(t, int64(500), testing.Daily.Total)
+	assert.InDelta(t, 10.0, *testing.PositivityRate, 0.0001)
+
+	pc := ProvinceCase{Positive: 5, CumulativePositive: 50, Tests: &DailyTests{PCR: 100, CumulativePCR: 1000}}
+	testing = pc.TransformToResponse(date).Statistics.Testing
+	require.NotNil(t, testing)
+	assert.InDelta(t, 5.0, *testing.PositivityRate, 0.0001)
+}
@@ -10,10 +10,12 @@ type NationalVaccine struct {
 
 	TotalVaccinationTarget int64 `json:"total_vaccination_target" db:"total_vaccination_target"`
 
-	FirstVaccinationReceived            int64 `json:"first_vaccination_received" db:"first_vaccination_received"`
-	SecondVaccinationReceived           int64 `json:"second_vaccination_received" db:"second_vaccination_received"`
-	CumulativeFirstVaccinationReceived  int64 `json:"cumulative_first_vaccination_received" db:"cumulative_first_vaccination_received"`
-	CumulativeSecondVaccinationReceived int64 `json:"cumulative_second_vaccination_received" db:"cumulative_second_vaccination_received"`
+	FirstVaccinationReceived             int64 `json:"first_vaccination_received" db:"first_vaccination_received"`
+	SecondVaccinationReceived            int64 `json:"second_vaccination_received" db:"second_vaccination_received"`
+	BoosterVaccinationReceived           int64 `json:"booster_vaccination_received" db:"booster_vaccination_received"`
+	CumulativeFirstVaccinationReceived   int64 `json:"cumulative_first_vaccination_received" db:"cumulative_first_vaccination_received"`
+	CumulativeSecondVaccinationReceived  int64 `json:"cumulative_second_vaccination_received" db:"cumulative_second_vaccination_received"`
+	CumulativeBoosterVaccinationReceived int64 `json:"cumulative_booster_vaccination_received" db:"cumulative_booster_vaccination_received"`
 
 	HealthWorkerVaccinationTarget                    int64 `json:"health_worker_vaccination_target" db:"health_worker_vaccination_target"`
 	HealthWorkerFirstVaccinationReceived             int64 `json:"health_worker_first_vaccination_received" db:"health_worker_first_vaccination_received"`
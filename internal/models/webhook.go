@@ -0,0 +1,287 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookEventSyncCompleted       = "sync.completed"
+	WebhookEventNationalCaseCreated = "national_case.created"
+	WebhookEventProvinceCaseCreated = "province_case.created"
+)
+
+// webhookEvents are the events a subscription can register for, besides "*"
+var webhookEvents = map[string]bool{
+	WebhookEventSyncCompleted:       true,
+	WebhookEventAlertTriggered:      true,
+	WebhookEventNationalCaseCreated: true,
+	WebhookEventProvinceCaseCreated: true,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryRetrying is a failed delivery with another attempt scheduled at NextAttemptAt
+	WebhookDeliveryRetrying = "retrying"
+	// WebhookDeliveryFailed is a delivery that will not be retried automatically
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookSubscription is a callback URL notified of data events. The secret
+// signs every delivery and is never serialized.
+type WebhookSubscription struct {
+	ID        int64         `json:"id" db:"id"`
+	URL       string        `json:"url" db:"url"`
+	Secret    string        `json:"-" db:"secret"`
+	Events    []string      `json:"events" db:"events"`
+	Filter    WebhookFilter `json:"filter" db:"filters"`
+	Active    bool          `json:"active" db:"active"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// Wants reports whether the subscription receives event. "*" matches every event.
+func (s WebhookSubscription) Wants(event string) bool {
+	for _, e := range s.Events {
+		if e == "*" || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the callback URL, events and filter of a new subscription
+func (s WebhookSubscription) Validate() error {
+	var problems []string
+	if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("url must be an absolute http or https URL, got %q", s.URL))
+	}
+	if len(s.Events) == 0 {
+		problems = append(problems, "at least one event is required")
+	}
+	for _, e := range s.Events {
+		if e != "*" && !webhookEvents[e] {
+			problems = append(problems, fmt.Sprintf("unknown event %q", e))
+		}
+	}
+	if err := s.Filter.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// WebhookFilter narrows the case changes a subscription is notified of. Every
+// set criterion must hold for a change to match; an empty filter matches all.
+type WebhookFilter struct {
+	// ProvinceIDs only matches province case changes of these provinces
+	ProvinceIDs []string `json:"province_ids,omitempty"`
+	// RtCrossesOne only matches changes where Rt moved across 1
+	RtCrossesOne bool `json:"rt_crosses_one,omitempty"`
+	// CorrectionsOnly only matches updates of already published rows
+	CorrectionsOnly bool `json:"corrections_only,omitempty"`
+}
+
+// Validate checks that the filter's province IDs are two digits
+func (f WebhookFilter) Validate() error {
+	var problems []string
+	for _, id := range f.ProvinceIDs {
+		if !provinceIDPattern.MatchString(id) {
+			problems = append(problems, fmt.Sprintf("filter province IDs must be two digits, got %q", id))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// IsEmpty reports whether the filter has no criteria
+func (f WebhookFilter) IsEmpty() bool {
+	return len(f.ProvinceIDs) == 0 && !f.RtCrossesOne && !f.CorrectionsOnly
+}
+
+// Matches reports whether a single change satisfies every criterion
+func (f WebhookFilter) Matches(c CaseChange) bool {
+	if len(f.ProvinceIDs) > 0 {
+		found := false
+		for _, id := range f.ProvinceIDs {
+			if c.ProvinceID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.RtCrossesOne && !c.RtCrossesOne() {
+		return false
+	}
+	if f.CorrectionsOnly && !c.IsCorrection() {
+		return false
+	}
+	return true
+}
+
+// Apply returns the changes matching the filter
+func (f WebhookFilter) Apply(changes []CaseChange) []CaseChange {
+	var matched []CaseChange
+	for _, c := range changes {
+		if f.Matches(c) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// FilterableEvent is event data carrying case changes that subscription
+// filters can narrow down
+type FilterableEvent interface {
+	CaseChanges() []CaseChange
+	WithCaseChanges(changes []CaseChange) interface{}
+}
+
+// WebhookDelivery records one event sent to one subscription. Payload is only
+// loaded for a single delivery, not in delivery lists.
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id"`
+	DeliveryID     string          `json:"delivery_id" db:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id" db:"subscription_id"`
+	Event          string          `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload,omitempty" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// WebhookSubscriptionStats is a subscription with counts of its deliveries by status
+type WebhookSubscriptionStats struct {
+	WebhookSubscription
+	Delivered      int        `json:"delivered"`
+	Retrying       int        `json:"retrying"`
+	Failed         int        `json:"failed"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+}
+
+// WebhookEnvelope is the JSON body of every delivery
+type WebhookEnvelope struct {
+	ID         string      `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// SyncCompletedData is the data of a sync.completed event. For a filtered
+// subscription Changes only holds the matching changes.
+type SyncCompletedData struct {
+	RunID     int64        `json:"run_id"`
+	Source    string       `json:"source"`
+	Inserted  int          `json:"inserted"`
+	Updated   int          `json:"updated"`
+	Restored  int          `json:"restored,omitempty"`
+	Conflicts int          `json:"conflicts"`
+	Changes   []CaseChange `json:"changes"`
+}
+
+// CaseChanges implements FilterableEvent
+func (d SyncCompletedData) CaseChanges() []CaseChange {
+	return d.Changes
+}
+
+// WithCaseChanges implements FilterableEvent
+func (d SyncCompletedData) WithCaseChanges(changes []CaseChange) interface{} {
+	d.Changes = changes
+	return d
+}
+
+// NationalCaseCreatedData is the data of a national_case.created event, the
+// national case rows added since the previous poll
+type NationalCaseCreatedData struct {
+	Cases []NationalCase `json:"cases"`
+}
+
+// CaseChanges implements FilterableEvent
+func (d NationalCaseCreatedData) CaseChanges() []CaseChange {
+	changes := make([]CaseChange, len(d.Cases))
+	for i, c := range d.Cases {
+		changes[i] = CaseChange{
+			Table:  "national_cases",
+			Key:    fmt.Sprintf("day=%d", c.Day),
+			Action: ChangeActionInsert,
+			Day:    c.Day,
+			Rt:     c.Rt,
+		}
+	}
+	return changes
+}
+
+// WithCaseChanges implements FilterableEvent by keeping the cases of the given changes
+func (d NationalCaseCreatedData) WithCaseChanges(changes []CaseChange) interface{} {
+	keep := make(map[int64]bool, len(changes))
+	for _, c := range changes {
+		keep[c.Day] = true
+	}
+	var cases []NationalCase
+	for _, c := range d.Cases {
+		if keep[c.Day] {
+			cases = append(cases, c)
+		}
+	}
+	d.Cases = cases
+	return d
+}
+
+// ProvinceCaseCreatedData is the data of a province_case.created event, the
+// province case rows added since the previous poll
+type ProvinceCaseCreatedData struct {
+	Cases []ProvinceCaseWithDate `json:"cases"`
+}
+
+// CaseChanges implements FilterableEvent
+func (d ProvinceCaseCreatedData) CaseChanges() []CaseChange {
+	changes := make([]CaseChange, len(d.Cases))
+	for i, c := range d.Cases {
+		changes[i] = CaseChange{
+			Table:      "province_cases",
+			Key:        provinceCaseKey(c.ProvinceID, c.Day),
+			Action:     ChangeActionInsert,
+			Day:        c.Day,
+			ProvinceID: c.ProvinceID,
+			Rt:         c.Rt,
+		}
+	}
+	return changes
+}
+
+// WithCaseChanges implements FilterableEvent by keeping the cases of the given changes
+func (d ProvinceCaseCreatedData) WithCaseChanges(changes []CaseChange) interface{} {
+	keep := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		keep[c.Key] = true
+	}
+	var cases []ProvinceCaseWithDate
+	for _, c := range d.Cases {
+		if keep[provinceCaseKey(c.ProvinceID, c.Day)] {
+			cases = append(cases, c)
+		}
+	}
+	d.Cases = cases
+	return d
+}
+
+func provinceCaseKey(provinceID string, day int64) string {
+	return fmt.Sprintf("province_id=%s,day=%d", provinceID, day)
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rtPtr(v float64) *float64 {
+	return &v
+}
+
+func TestCaseChange_RtCrossesOne(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous *float64
+		rt       *float64
+		want     bool
+	}{
+		{"rises above", rtPtr(0.95), rtPtr(1.1), true},
+		{"reaches exactly one", rtPtr(0.9), rtPtr(1), true},
+		{"falls below", rtPtr(1.0), rtPtr(0.99), true},
+		{"stays above", rtPtr(1.2), rtPtr(1.4), false},
+		{"stays below", rtPtr(0.5), rtPtr(0.8), false},
+		{"no previous", nil, rtPtr(1.2), false},
+		{"no rt", rtPtr(0.8), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CaseChange{PreviousRt: tt.previous, Rt: tt.rt}.RtCrossesOne())
+		})
+	}
+}
+
+func TestWebhookFilter_Apply(t *testing.T) {
+	changes := []CaseChange{
+		{Table: "national_cases", Action: ChangeActionInsert, Day: 10, PreviousRt: rtPtr(0.9), Rt: rtPtr(1.1)},
+		{Table: "province_cases", Action: ChangeActionInsert, Day: 10, ProvinceID: "72", PreviousRt: rtPtr(1.2), Rt: rtPtr(1.3)},
+		{Table: "province_cases", Action: ChangeActionUpdate, Day: 3, ProvinceID: "72", PreviousRt: rtPtr(0.8), Rt: rtPtr(1.05)},
+		{Table: "province_cases", Action: ChangeActionUpdate, Day: 3, ProvinceID: "73"},
+	}
+
+	assert.Len(t, WebhookFilter{}.Apply(changes), 4)
+	assert.True(t, WebhookFilter{}.IsEmpty())
+	assert.Equal(t, changes[1:3], WebhookFilter{ProvinceIDs: []string{"72"}}.Apply(changes))
+	assert.Equal(t, []CaseChange{changes[0], changes[2]}, WebhookFilter{RtCrossesOne: true}.Apply(changes))
+	assert.Equal(t, changes[2:], WebhookFilter{CorrectionsOnly: true}.Apply(changes))
+	assert.Equal(t, []CaseChange{changes[2]}, WebhookFilter{ProvinceIDs: []string{"72"}, RtCrossesOne: true, CorrectionsOnly: true}.Apply(changes))
+	assert.Empty(t, WebhookFilter{ProvinceIDs: []string{"11"}}.Apply(changes))
+}
+
+func TestProvinceCaseCreatedData_Filter(t *testing.T) {
+	data := ProvinceCaseCreatedData{Cases: []ProvinceCaseWithDate{
+		{ProvinceCase: ProvinceCase{ID: 1, Day: 10, ProvinceID: "72", Rt: rtPtr(1.1)}},
+		{ProvinceCase: ProvinceCase{ID: 2, Day: 10, ProvinceID: "73"}},
+	}}
+
+	changes := data.CaseChanges()
+	assert.Equal(t, []CaseChange{
+		{Table: "province_cases", Key: "province_id=72,day=10", Action: ChangeActionInsert, Day: 10, ProvinceID: "72", Rt: rtPtr(1.1)},
+		{Table: "province_cases", Key: "province_id=73,day=10", Action: ChangeActionInsert, Day: 10, ProvinceID: "73"},
+	}, changes)
+
+	filtered := data.WithCaseChanges(WebhookFilter{ProvinceIDs: []string{"73"}}.Apply(changes)).(ProvinceCaseCreatedData)
+	assert.Len(t, filtered.Cases, 1)
+	assert.Equal(t, int64(2), filtered.Cases[0].ID)
+	assert.Len(t, data.Cases, 2)
+}
+
+func TestNationalCaseCreatedData_CaseChanges(t *testing.T) {
+	data := NationalCaseCreatedData{Cases: []NationalCase{{ID: 5, Day: 5}, {ID: 6, Day: 6}}}
+
+	changes := data.CaseChanges()
+	assert.Equal(t, CaseChange{Table: "national_cases", Key: "day=6", Action: ChangeActionInsert, Day: 6}, changes[1])
+	assert.Empty(t, WebhookFilter{ProvinceIDs: []string{"72"}}.Apply(changes))
+
+	filtered := data.WithCaseChanges(changes[:1]).(NationalCaseCreatedData)
+	assert.Equal(t, []NationalCase{{ID: 5, Day: 5}}, filtered.Cases)
+}
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	valid := WebhookSubscription{URL: "https://a.example/hook", Events: []string{WebhookEventProvinceCaseCreated, "*"}}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name string
+		sub  WebhookSubscription
+		want string
+	}{
+		{"relative url", WebhookSubscription{URL: "/hook", Events: []string{"*"}}, "absolute http or https URL"},
+		{"other scheme", WebhookSubscription{URL: "ftp://a.example/hook", Events: []string{"*"}}, "absolute http or https URL"},
+		{"no events", WebhookSubscription{URL: "https://a.example/hook"}, "at least one event"},
+		{"unknown event", WebhookSubscription{URL: "https://a.example/hook", Events: []string{"case.deleted"}}, `unknown event "case.deleted"`},
+		{"bad province", WebhookSubscription{URL: "https://a.example/hook", Events: []string{"*"}, Filter: WebhookFilter{ProvinceIDs: []string{"7"}}}, "two digits"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.sub.Validate(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// AlertRepository stores alert rules and their evaluation history
+type AlertRepository interface {
+	ListRules(ctx context.Context) ([]models.AlertRule, error)
+	ListActiveRules(ctx context.Context) ([]models.AlertRule, error)
+	GetRule(ctx context.Context, id int64) (*models.AlertRule, error)
+	CreateRule(ctx context.Context, rule models.AlertRule) (int64, error)
+	UpdateRule(ctx context.Context, rule models.AlertRule) (bool, error)
+	DeleteRule(ctx context.Context, id int64) (bool, error)
+	LatestMetricValue(ctx context.Context, metric, scope string) (*models.MetricObservation, error)
+	HasNotified(ctx context.Context, ruleID, day int64) (bool, error)
+	RecordEvaluation(ctx context.Context, e models.AlertEvaluation) (int64, error)
+	ListEvaluations(ctx context.Context, ruleID int64, limit int) ([]models.AlertEvaluation, error)
+}
+
+type alertRepository struct {
+	db *database.DB
+}
+
+func NewAlertRepository(db *database.DB) AlertRepository {
+	return &alertRepository{db: db}
+}
+
+// maxEvaluationErrorLength matches alert_evaluations.error
+const maxEvaluationErrorLength = 512
+
+const alertRuleColumns = `id, name, metric, scope, operator, threshold, channel, target, active, created_at, updated_at`
+
+const alertEvaluationColumns = `id, rule_id, sync_log_id, day, value, triggered, notified, error, evaluated_at`
+
+func (r *alertRepository) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	return r.queryRules(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules ORDER BY id`)
+}
+
+func (r *alertRepository) ListActiveRules(ctx context.Context) ([]models.AlertRule, error) {
+	return r.queryRules(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE active = TRUE ORDER BY id`)
+}
+
+// GetRule returns nil when the rule does not exist
+func (r *alertRepository) GetRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	return scanAlertRule(r.db.QueryRowContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE id = ?`, id))
+}
+
+func (r *alertRepository) CreateRule(ctx context.Context, rule models.AlertRule) (int64, error) {
+	id, err := r.db.InsertContext(ctx, `INSERT INTO alert_rules
+		(name, metric, scope, operator, threshold, channel, target, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.Name, rule.Metric, rule.Scope, rule.Operator, rule.Threshold, rule.Channel, rule.Target, rule.Active,
+		rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateRule reports false when the rule does not exist
+func (r *alertRepository) UpdateRule(ctx context.Context, rule models.AlertRule) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE alert_rules
+		SET name = ?, metric = ?, scope = ?, operator = ?, threshold = ?, channel = ?, target = ?, active = ?, updated_at = ?
+		WHERE id = ?`,
+		rule.Name, rule.Metric, rule.Scope, rule.Operator, rule.Threshold, rule.Channel, rule.Target, rule.Active,
+		rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update alert rule %d: %w", rule.ID, err)
+	}
+	return r.exists(ctx, rule.ID, res)
+}
+
+// DeleteRule removes the rule and its evaluation history. It reports false
+// when the rule does not exist.
+func (r *alertRepository) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM alert_evaluations WHERE rule_id = ?`, id); err != nil {
+		return false, fmt.Errorf("failed to delete evaluations of alert rule %d: %w", id, err)
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete alert rule %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read deleted alert rules: %w", err)
+	}
+	return n > 0, nil
+}
+
+// exists reports whether an UPDATE matched the rule. MySQL counts unchanged
+// rows as unaffected, so a zero count is confirmed with a lookup.
+func (r *alertRepository) exists(ctx context.Context, id int64, res sql.Result) (bool, error) {
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return true, nil
+	}
+	rule, err := r.GetRule(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return rule != nil, nil
+}
+
+// LatestMetricValue returns the metric on the latest day stored for the scope,
+// or nil when the scope has no data. The metric must be a key of
+// models.AlertMetricColumns.
+func (r *alertRepository) LatestMetricValue(ctx context.Context, metric, scope string) (*models.MetricObservation, error) {
+	column, ok := models.AlertMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown alert metric %q", metric)
+	}
+
+	var row *sql.Row
+	if scope == models.AlertScopeNational {
+		row = r.db.QueryRowContext(ctx, `SELECT day, `+column+` FROM national_cases WHERE deleted_at IS NULL ORDER BY day DESC LIMIT 1`)
+	} else {
+		row = r.db.QueryRowContext(ctx, `SELECT day, `+column+` FROM province_cases WHERE province_id = ? AND deleted_at IS NULL ORDER BY day DESC LIMIT 1`, scope)
+	}
+
+	var obs models.MetricObservation
+	var value sql.NullFloat64
+	if err := row.Scan(&obs.Day, &value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s for %s: %w", metric, scope, err)
+	}
+	if value.Valid {
+		obs.Value = &value.Float64
+	}
+	return &obs, nil
+}
+
+// HasNotified reports whether the rule already sent a notification for the day
+func (r *alertRepository) HasNotified(ctx context.Context, ruleID, day int64) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM alert_evaluations WHERE rule_id = ? AND day = ? AND notified = TRUE`,
+		ruleID, day).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check notifications of alert rule %d: %w", ruleID, err)
+	}
+	return count > 0, nil
+}
+
+func (r *alertRepository) RecordEvaluation(ctx context.Context, e models.AlertEvaluation) (int64, error) {
+	errMsg := e.Error
+	if errMsg != nil && len(*errMsg) > maxEvaluationErrorLength {
+		truncated := (*errMsg)[:maxEvaluationErrorLength]
+		errMsg = &truncated
+	}
+	var runID, day, value interface{}
+	if e.SyncRunID != nil {
+		runID = *e.SyncRunID
+	}
+	if e.Day != nil {
+		day = *e.Day
+	}
+	if e.Value != nil {
+		value = *e.Value
+	}
+	id, err := r.db.InsertContext(ctx, `INSERT INTO alert_evaluations
+		(rule_id, sync_log_id, day, value, triggered, notified, error, evaluated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.RuleID, runID, day, value, e.Triggered, e.Notified, nullableString(errMsg), e.EvaluatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record evaluation of alert rule %d: %w", e.RuleID, err)
+	}
+	return id, nil
+}
+
+// ListEvaluations returns the most recent evaluations of a rule, newest first
+func (r *alertRepository) ListEvaluations(ctx context.Context, ruleID int64, limit int) ([]models.AlertEvaluation, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+alertEvaluationColumns+` FROM alert_evaluations
+		WHERE rule_id = ? ORDER BY id DESC LIMIT ?`, ruleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert evaluations: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	evaluations := []models.AlertEvaluation{}
+	for rows.Next() {
+		var e models.AlertEvaluation
+		var runID, day sql.NullInt64
+		var value sql.NullFloat64
+		var errMsg sql.NullString
+		if err := rows.Scan(&e.ID, &e.RuleID, &runID, &day, &value, &e.Triggered, &e.Notified, &errMsg, &e.EvaluatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert evaluation: %w", err)
+		}
+		if runID.Valid {
+			e.SyncRunID = &runID.Int64
+		}
+		if day.Valid {
+			e.Day = &day.Int64
+		}
+		if value.Valid {
+			e.Value = &value.Float64
+		}
+		if errMsg.Valid {
+			e.Error = &errMsg.String
+		}
+		evaluations = append(evaluations, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return evaluations, nil
+}
+
+func (r *alertRepository) queryRules(ctx context.Context, query string, args ...interface{}) ([]models.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	rules := []models.AlertRule{}
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return rules, nil
+}
+
+func scanAlertRule(row rowScanner) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	if err := row.Scan(&rule.ID, &rule.Name, &rule.Metric, &rule.Scope, &rule.Operator, &rule.Threshold,
+		&rule.Channel, &rule.Target, &rule.Active, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+	}
+	return &rule, nil
+}
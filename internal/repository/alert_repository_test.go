@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var alertRuleCols = []string{"id", "name", "metric", "scope", "operator", "threshold", "channel", "target", "active", "created_at", "updated_at"}
+
+func TestAlertRepository_ListActiveRules(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, name, metric, .* FROM alert_rules WHERE active = TRUE ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows(alertRuleCols).
+			AddRow(1, "Sulteng spike", "daily_positive", "72", ">", 100.0, "telegram", "-100", true, now, now))
+
+	rules, err := NewAlertRepository(db).ListActiveRules(context.Background())
+
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 1) {
+		assert.Equal(t, "-100", rules[0].Target)
+		assert.Equal(t, 100.0, rules[0].Threshold)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_GetRule_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM alert_rules WHERE id = \?`).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows(alertRuleCols))
+
+	rule, err := NewAlertRepository(db).GetRule(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Nil(t, rule)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_UpdateRule_UnchangedRowStillFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`UPDATE alert_rules`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM alert_rules WHERE id = \?`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(alertRuleCols).AddRow(1, "n", "rt", "national", ">", 1.0, "log", "", true, now, now))
+
+	found, err := NewAlertRepository(db).UpdateRule(context.Background(), models.AlertRule{ID: 1})
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_DeleteRule(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM alert_evaluations WHERE rule_id = \?`).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM alert_rules WHERE id = \?`).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	found, err := NewAlertRepository(db).DeleteRule(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_LatestMetricValue(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewAlertRepository(db)
+
+	mock.ExpectQuery(`SELECT day, positive FROM province_cases WHERE province_id = \? AND deleted_at IS NULL ORDER BY day DESC LIMIT 1`).WithArgs("72").
+		WillReturnRows(sqlmock.NewRows([]string{"day", "positive"}).AddRow(410, 120))
+	mock.ExpectQuery(`SELECT day, rt FROM national_cases WHERE deleted_at IS NULL ORDER BY day DESC LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"day", "rt"}).AddRow(410, nil))
+
+	obs, err := repo.LatestMetricValue(context.Background(), "daily_positive", "72")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(410), obs.Day)
+	assert.Equal(t, 120.0, *obs.Value)
+
+	obs, err = repo.LatestMetricValue(context.Background(), "rt", models.AlertScopeNational)
+	assert.NoError(t, err)
+	assert.Nil(t, obs.Value)
+
+	_, err = repo.LatestMetricValue(context.Background(), "positive; DROP TABLE alert_rules", "72")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_RecordEvaluation(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	runID, day, value := int64(3), int64(410), 120.0
+	mock.ExpectExec(`INSERT INTO alert_evaluations`).
+		WithArgs(int64(1), int64(3), int64(410), 120.0, true, true, nil, now).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	id, err := NewAlertRepository(db).RecordEvaluation(context.Background(), models.AlertEvaluation{
+		RuleID: 1, SyncRunID: &runID, Day: &day, Value: &value, Triggered: true, Notified: true, EvaluatedAt: now,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRepository_ListEvaluations(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM alert_evaluations\s+WHERE rule_id = \? ORDER BY id DESC LIMIT \?`).WithArgs(int64(1), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rule_id", "sync_log_id", "day", "value", "triggered", "notified", "error", "evaluated_at"}).
+			AddRow(2, 1, 3, 410, 120.0, true, false, "telegram returned status 400", now).
+			AddRow(1, 1, nil, nil, nil, false, false, nil, now))
+
+	evaluations, err := NewAlertRepository(db).ListEvaluations(context.Background(), 1, 20)
+
+	assert.NoError(t, err)
+	if assert.Len(t, evaluations, 2) {
+		assert.Equal(t, "telegram returned status 400", *evaluations[0].Error)
+		assert.Nil(t, evaluations[1].Day)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
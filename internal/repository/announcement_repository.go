@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// AnnouncementRepository stores the announcements shown in meta.notices
+type AnnouncementRepository interface {
+	List(ctx context.Context) ([]models.Announcement, error)
+	ListCurrent(ctx context.Context, now time.Time) ([]models.Announcement, error)
+	Get(ctx context.Context, id int64) (*models.Announcement, error)
+	Create(ctx context.Context, announcement models.Announcement) (int64, error)
+	Update(ctx context.Context, announcement models.Announcement) (bool, error)
+	Delete(ctx context.Context, id int64) (bool, error)
+}
+
+type announcementRepository struct {
+	db *database.DB
+}
+
+func NewAnnouncementRepository(db *database.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+const announcementColumns = `id, severity, message, starts_at, ends_at, created_at, updated_at`
+
+// List returns every announcement, newest first
+func (r *announcementRepository) List(ctx context.Context) ([]models.Announcement, error) {
+	return r.query(ctx, `SELECT `+announcementColumns+` FROM announcements ORDER BY starts_at DESC, id DESC`)
+}
+
+// ListCurrent returns the announcements that have not ended by now, including
+// those that start later, in the order they start
+func (r *announcementRepository) ListCurrent(ctx context.Context, now time.Time) ([]models.Announcement, error) {
+	return r.query(ctx, `SELECT `+announcementColumns+` FROM announcements
+		WHERE ends_at IS NULL OR ends_at > ? ORDER BY starts_at, id`, now)
+}
+
+// Get returns nil when the announcement does not exist
+func (r *announcementRepository) Get(ctx context.Context, id int64) (*models.Announcement, error) {
+	return scanAnnouncement(r.db.QueryRowContext(ctx, `SELECT `+announcementColumns+` FROM announcements WHERE id = ?`, id))
+}
+
+func (r *announcementRepository) Create(ctx context.Context, a models.Announcement) (int64, error) {
+	id, err := r.db.InsertContext(ctx, `INSERT INTO announcements
+		(severity, message, starts_at, ends_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		a.Severity, a.Message, a.StartsAt, nullableTime(a.EndsAt), a.CreatedAt, a.UpdatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create announcement: %w", err)
+	}
+	return id, nil
+}
+
+// Update reports false when the announcement does not exist
+func (r *announcementRepository) Update(ctx context.Context, a models.Announcement) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE announcements
+		SET severity = ?, message = ?, starts_at = ?, ends_at = ?, updated_at = ?
+		WHERE id = ?`,
+		a.Severity, a.Message, a.StartsAt, nullableTime(a.EndsAt), a.UpdatedAt, a.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update announcement %d: %w", a.ID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return true, nil
+	}
+	existing, err := r.Get(ctx, a.ID)
+	if err != nil {
+		return false, err
+	}
+	return existing != nil, nil
+}
+
+// Delete reports false when the announcement does not exist
+func (r *announcementRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM announcements WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete announcement %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read deleted announcements: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *announcementRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.Announcement, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query announcements: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	announcements := []models.Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return announcements, nil
+}
+
+func scanAnnouncement(row rowScanner) (*models.Announcement, error) {
+	var a models.Announcement
+	var endsAt sql.NullTime
+	if err := row.Scan(&a.ID, &a.Severity, &a.Message, &a.StartsAt, &endsAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan announcement: %w", err)
+	}
+	if endsAt.Valid {
+		a.EndsAt = &endsAt.Time
+	}
+	return &a, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var announcementCols = []string{"id", "severity", "message", "starts_at", "ends_at", "created_at", "updated_at"}
+
+func TestAnnouncementRepository_ListCurrent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM announcements\s+WHERE ends_at IS NULL OR ends_at > \? ORDER BY starts_at, id`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows(announcementCols).
+			AddRow(1, "warning", "Data delayed today", now, now.Add(time.Hour), now, now).
+			AddRow(2, "info", "New endpoint", now, nil, now, now))
+
+	announcements, err := NewAnnouncementRepository(db).ListCurrent(context.Background(), now)
+
+	assert.NoError(t, err)
+	if assert.Len(t, announcements, 2) {
+		assert.NotNil(t, announcements[0].EndsAt)
+		assert.Nil(t, announcements[1].EndsAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnouncementRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`INSERT INTO announcements`).
+		WithArgs("critical", "Upstream outage", now, nil, now, now).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	id, err := NewAnnouncementRepository(db).Create(context.Background(), models.Announcement{
+		Severity: "critical", Message: "Upstream outage", StartsAt: now, CreatedAt: now, UpdatedAt: now,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnouncementRepository_Update_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE announcements`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM announcements WHERE id = \?`).WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows(announcementCols))
+
+	found, err := NewAnnouncementRepository(db).Update(context.Background(), models.Announcement{ID: 9})
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnouncementRepository_Delete(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM announcements WHERE id = \?`).WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	found, err := NewAnnouncementRepository(db).Delete(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
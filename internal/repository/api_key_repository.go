@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// APIKeyRepository reads the API keys accepted in the X-API-Key header
+type APIKeyRepository interface {
+	ListActive(ctx context.Context) ([]models.APIKey, error)
+}
+
+type apiKeyRepository struct {
+	db *database.DB
+}
+
+func NewAPIKeyRepository(db *database.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// ListActive returns the keys that have not been revoked
+func (r *apiKeyRepository) ListActive(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, key_hash, scopes, requests_per_minute, COALESCE(tier, ''), created_at
+		FROM api_keys WHERE revoked_at IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		var scopes string
+		if err := rows.Scan(&k.ID, &k.Name, &k.KeyHash, &scopes, &k.RequestsPerMinute, &k.Tier, &k.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		for _, s := range strings.Split(scopes, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				k.Scopes = append(k.Scopes, s)
+			}
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository_ListActive(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM api_keys WHERE revoked_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key_hash", "scopes", "requests_per_minute", "tier", "created_at"}).
+			AddRow(1, "dashboard", "abc", "read", 0, "premium", now).
+			AddRow(2, "ops", "def", "read, admin", 600, "", now))
+
+	keys, err := NewAPIKeyRepository(db).ListActive(context.Background())
+
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, []string{models.ScopeRead}, keys[0].Scopes)
+		assert.Equal(t, "premium", keys[0].Tier)
+		assert.Equal(t, []string{models.ScopeRead, models.ScopeAdmin}, keys[1].Scopes)
+		assert.Equal(t, 600, keys[1].RequestsPerMinute)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_ListActive_Error(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM api_keys`).WillReturnError(errors.New("table missing"))
+
+	_, err := NewAPIKeyRepository(db).ListActive(context.Background())
+
+	assert.ErrorContains(t, err, "failed to query API keys")
+}
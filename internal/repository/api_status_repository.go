@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// APIStatusRepository stores the status page incidents and component checks
+type APIStatusRepository interface {
+	ListIncidents(ctx context.Context, resolvedSince time.Time) ([]models.Incident, error)
+	GetIncident(ctx context.Context, id int64) (*models.Incident, error)
+	CreateIncident(ctx context.Context, incident models.Incident) (int64, error)
+	UpdateIncident(ctx context.Context, incident models.Incident) (bool, error)
+	DeleteIncident(ctx context.Context, id int64) (bool, error)
+	RecordChecks(ctx context.Context, checks []models.StatusCheck) error
+	Uptime(ctx context.Context, since time.Time) (map[string]float64, error)
+	DeleteChecksBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type apiStatusRepository struct {
+	db *database.DB
+}
+
+func NewAPIStatusRepository(db *database.DB) APIStatusRepository {
+	return &apiStatusRepository{db: db}
+}
+
+const incidentColumns = `id, title, message, severity, created_at, updated_at, resolved_at`
+
+// ListIncidents returns the unresolved incidents and those resolved at or
+// after resolvedSince, newest first. A zero resolvedSince returns them all.
+func (r *apiStatusRepository) ListIncidents(ctx context.Context, resolvedSince time.Time) ([]models.Incident, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+incidentColumns+` FROM status_incidents
+		WHERE resolved_at IS NULL OR resolved_at >= ? ORDER BY created_at DESC, id DESC`, resolvedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	incidents := []models.Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return incidents, nil
+}
+
+// GetIncident returns nil when the incident does not exist
+func (r *apiStatusRepository) GetIncident(ctx context.Context, id int64) (*models.Incident, error) {
+	return scanIncident(r.db.QueryRowContext(ctx, `SELECT `+incidentColumns+` FROM status_incidents WHERE id = ?`, id))
+}
+
+func (r *apiStatusRepository) CreateIncident(ctx context.Context, incident models.Incident) (int64, error) {
+	id, err := r.db.InsertContext(ctx, `INSERT INTO status_incidents
+		(title, message, severity, created_at, updated_at, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		incident.Title, incident.Message, incident.Severity, incident.CreatedAt, incident.UpdatedAt, nullableTime(incident.ResolvedAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create incident: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateIncident reports false when the incident does not exist
+func (r *apiStatusRepository) UpdateIncident(ctx context.Context, incident models.Incident) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE status_incidents
+		SET title = ?, message = ?, severity = ?, updated_at = ?, resolved_at = ?
+		WHERE id = ?`,
+		incident.Title, incident.Message, incident.Severity, incident.UpdatedAt, nullableTime(incident.ResolvedAt), incident.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update incident %d: %w", incident.ID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return true, nil
+	}
+	existing, err := r.GetIncident(ctx, incident.ID)
+	if err != nil {
+		return false, err
+	}
+	return existing != nil, nil
+}
+
+// DeleteIncident reports false when the incident does not exist
+func (r *apiStatusRepository) DeleteIncident(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM status_incidents WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete incident %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read deleted incidents: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RecordChecks stores the checks with one multi-row insert
+func (r *apiStatusRepository) RecordChecks(ctx context.Context, checks []models.StatusCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(checks))
+	args := make([]interface{}, 0, len(checks)*3)
+	for i, c := range checks {
+		placeholders[i] = "(?, ?, ?)"
+		args = append(args, c.Component, c.Healthy, c.CheckedAt)
+	}
+	_, err := r.db.ExecContext(ctx, `INSERT INTO status_checks (component, healthy, checked_at) VALUES `+
+		strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to record status checks: %w", err)
+	}
+	return nil
+}
+
+// Uptime returns the percentage of healthy checks per component since the
+// given time. Components without checks in the period are left out.
+func (r *apiStatusRepository) Uptime(ctx context.Context, since time.Time) (map[string]float64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT component, AVG(CASE WHEN healthy THEN 1 ELSE 0 END) * 100 FROM status_checks
+		WHERE checked_at >= ? GROUP BY component`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query uptime: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	uptime := make(map[string]float64)
+	for rows.Next() {
+		var component string
+		var percent float64
+		if err := rows.Scan(&component, &percent); err != nil {
+			return nil, fmt.Errorf("failed to scan uptime: %w", err)
+		}
+		uptime[component] = percent
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return uptime, nil
+}
+
+// DeleteChecksBefore removes checks older than before and returns how many
+func (r *apiStatusRepository) DeleteChecksBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM status_checks WHERE checked_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old status checks: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read deleted status checks: %w", err)
+	}
+	return n, nil
+}
+
+func scanIncident(row rowScanner) (*models.Incident, error) {
+	var incident models.Incident
+	var resolvedAt sql.NullTime
+	if err := row.Scan(&incident.ID, &incident.Title, &incident.Message, &incident.Severity,
+		&incident.CreatedAt, &incident.UpdatedAt, &resolvedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan incident: %w", err)
+	}
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+	return &incident, nil
+}
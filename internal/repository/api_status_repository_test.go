@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var incidentCols = []string{"id", "title", "message", "severity", "created_at", "updated_at", "resolved_at"}
+
+func TestAPIStatusRepository_ListIncidents(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	since := now.Add(-7 * 24 * time.Hour)
+	mock.ExpectQuery(`FROM status_incidents\s+WHERE resolved_at IS NULL OR resolved_at >= \? ORDER BY created_at DESC`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows(incidentCols).
+			AddRow(2, "Slow responses", "Investigating", "minor", now, now, nil).
+			AddRow(1, "Maintenance", "", "maintenance", now, now, now))
+
+	incidents, err := NewAPIStatusRepository(db).ListIncidents(context.Background(), since)
+
+	assert.NoError(t, err)
+	if assert.Len(t, incidents, 2) {
+		assert.Nil(t, incidents[0].ResolvedAt)
+		assert.NotNil(t, incidents[1].ResolvedAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIStatusRepository_RecordChecks(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`INSERT INTO status_checks \(component, healthy, checked_at\) VALUES \(\?, \?, \?\), \(\?, \?, \?\)`).
+		WithArgs("database", true, now, "cache", false, now).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+
+	err := NewAPIStatusRepository(db).RecordChecks(context.Background(), []models.StatusCheck{
+		{Component: "database", Healthy: true, CheckedAt: now},
+		{Component: "cache", Healthy: false, CheckedAt: now},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, NewAPIStatusRepository(db).RecordChecks(context.Background(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIStatusRepository_Uptime(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	since := time.Now().Add(-24 * time.Hour)
+	mock.ExpectQuery(`SELECT component, AVG\(CASE WHEN healthy THEN 1 ELSE 0 END\) \* 100 FROM status_checks\s+WHERE checked_at >= \? GROUP BY component`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"component", "uptime"}).AddRow("database", 99.5))
+
+	uptime, err := NewAPIStatusRepository(db).Uptime(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"database": 99.5}, uptime)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIStatusRepository_DeleteIncident_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM status_incidents WHERE id = \?`).WithArgs(int64(5)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	found, err := NewAPIStatusRepository(db).DeleteIncident(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// CaseAggregateQuery selects cases totalled per ISO week or calendar month
+type CaseAggregateQuery struct {
+	// Interval is models.IntervalWeekly or models.IntervalMonthly
+	Interval string
+	// ProvinceID limits province aggregates to one province; all provinces
+	// when empty. National aggregates ignore it.
+	ProvinceID string
+	// StartDate and EndDate bound the dates when both are set
+	StartDate time.Time
+	EndDate   time.Time
+	// Desc orders the periods newest first
+	Desc bool
+}
+
+func (q CaseAggregateQuery) hasDateRange() bool {
+	return !q.StartDate.IsZero() && !q.EndDate.IsZero()
+}
+
+func (q CaseAggregateQuery) order() string {
+	if q.Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// periodExpression returns the SQL labelling the date column with its ISO
+// week (e.g. 2021-W05) or month (2021-02)
+func periodExpression(dialect database.Dialect, interval, dateColumn string) (string, error) {
+	switch interval {
+	case models.IntervalWeekly:
+		return dialect.ISOWeek(dateColumn), nil
+	case models.IntervalMonthly:
+		return dialect.YearMonth(dateColumn), nil
+	default:
+		return "", fmt.Errorf("unsupported aggregation interval %q", interval)
+	}
+}
+
+// scanAggregatedCases reads rows of period, first date, last date, days,
+// summed daily counts, highest cumulative counts and average Rt, each
+// optionally preceded by the province ID and name
+func scanAggregatedCases(rows *sql.Rows, withProvince bool) ([]models.AggregatedCase, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	cases := []models.AggregatedCase{}
+	for rows.Next() {
+		var c models.AggregatedCase
+		var provinceName sql.NullString
+		var averageRt sql.NullFloat64
+		dest := []interface{}{&c.Period, &c.StartDate, &c.EndDate, &c.Days,
+			&c.Positive, &c.Recovered, &c.Deceased,
+			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased, &averageRt}
+		if withProvince {
+			dest = append([]interface{}{&c.ProvinceID, &provinceName}, dest...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregated case: %w", err)
+		}
+		if averageRt.Valid {
+			c.AverageRt = &averageRt.Float64
+		}
+		if provinceName.Valid {
+			c.Province = &models.Province{ID: c.ProvinceID, Name: provinceName.String}
+		}
+		cases = append(cases, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return cases, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// CaseNoteRepository stores the analyst notes attached to case dates
+type CaseNoteRepository interface {
+	List(ctx context.Context, filter models.CaseNoteFilter) ([]models.CaseNote, error)
+	Get(ctx context.Context, id int64) (*models.CaseNote, error)
+	Create(ctx context.Context, note models.CaseNote) (int64, error)
+	Update(ctx context.Context, note models.CaseNote) (bool, error)
+	Delete(ctx context.Context, id int64) (bool, error)
+}
+
+type caseNoteRepository struct {
+	db *database.DB
+}
+
+func NewCaseNoteRepository(db *database.DB) CaseNoteRepository {
+	return &caseNoteRepository{db: db}
+}
+
+const caseNoteColumns = `id, province_id, date, text_id, text_en, created_at, updated_at`
+
+// List returns the matching notes by date, national notes before those of the
+// provinces, and in the order they were written
+func (r *caseNoteRepository) List(ctx context.Context, filter models.CaseNoteFilter) ([]models.CaseNote, error) {
+	q := newSelect(caseNoteColumns, "case_notes")
+	switch filter.Scope {
+	case "":
+	case models.NoteScopeNational:
+		q.where("province_id = ''")
+	default:
+		q.where("province_id = ?", filter.Scope)
+	}
+	if !filter.StartDate.IsZero() {
+		q.where("date >= ?", filter.StartDate.Format("2006-01-02"))
+	}
+	if !filter.EndDate.IsZero() {
+		q.where("date <= ?", filter.EndDate.Format("2006-01-02"))
+	}
+	query, args := q.orderBy("date, province_id, id").build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query case notes: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	notes := []models.CaseNote{}
+	for rows.Next() {
+		n, err := scanCaseNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, *n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return notes, nil
+}
+
+// Get returns nil when the note does not exist
+func (r *caseNoteRepository) Get(ctx context.Context, id int64) (*models.CaseNote, error) {
+	query, args := newSelect(caseNoteColumns, "case_notes").where("id = ?", id).build()
+	return scanCaseNote(r.db.QueryRowContext(ctx, query, args...))
+}
+
+func (r *caseNoteRepository) Create(ctx context.Context, n models.CaseNote) (int64, error) {
+	id, err := r.db.InsertContext(ctx, `INSERT INTO case_notes
+		(province_id, date, text_id, text_en, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		n.ProvinceID, n.Date.Format("2006-01-02"), n.Text.ID, n.Text.EN, n.CreatedAt, n.UpdatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create case note: %w", err)
+	}
+	return id, nil
+}
+
+// Update reports false when the note does not exist
+func (r *caseNoteRepository) Update(ctx context.Context, n models.CaseNote) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE case_notes
+		SET province_id = ?, date = ?, text_id = ?, text_en = ?, updated_at = ?
+		WHERE id = ?`,
+		n.ProvinceID, n.Date.Format("2006-01-02"), n.Text.ID, n.Text.EN, n.UpdatedAt, n.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update case note %d: %w", n.ID, err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected > 0 {
+		return true, nil
+	}
+	existing, err := r.Get(ctx, n.ID)
+	if err != nil {
+		return false, err
+	}
+	return existing != nil, nil
+}
+
+// Delete reports false when the note does not exist
+func (r *caseNoteRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM case_notes WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete case note %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read deleted case notes: %w", err)
+	}
+	return n > 0, nil
+}
+
+func scanCaseNote(row rowScanner) (*models.CaseNote, error) {
+	var n models.CaseNote
+	if err := row.Scan(&n.ID, &n.ProvinceID, &n.Date, &n.Text.ID, &n.Text.EN, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan case note: %w", err)
+	}
+	return &n, nil
+}
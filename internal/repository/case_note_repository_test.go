@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var caseNoteCols = []string{"id", "province_id", "date", "text_id", "text_en", "created_at", "updated_at"}
+
+func TestCaseNoteRepository_List(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM case_notes WHERE province_id = \? AND date >= \? AND date <= \? ORDER BY date, province_id, id$`).
+		WithArgs("72", "2021-07-01", "2021-07-31").
+		WillReturnRows(sqlmock.NewRows(caseNoteCols).
+			AddRow(3, "72", date, "Laporan tertunda", "Backlog reported this day", date, date))
+
+	notes, err := NewCaseNoteRepository(db).List(context.Background(), models.CaseNoteFilter{
+		Scope:     "72",
+		StartDate: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 7, 31, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, notes, 1) {
+		assert.Equal(t, models.NoteText{ID: "Laporan tertunda", EN: "Backlog reported this day"}, notes[0].Text)
+		assert.Equal(t, "72/2021-07-15", notes[0].Key())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCaseNoteRepository_List_National(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM case_notes WHERE province_id = '' ORDER BY`).
+		WillReturnRows(sqlmock.NewRows(caseNoteCols))
+
+	notes, err := NewCaseNoteRepository(db).List(context.Background(), models.CaseNoteFilter{Scope: models.NoteScopeNational})
+
+	assert.NoError(t, err)
+	assert.Empty(t, notes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCaseNoteRepository_Update_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`UPDATE case_notes`).
+		WithArgs("", "2021-07-15", "", "Backlog", now, int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM case_notes WHERE id = \?`).WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows(caseNoteCols))
+
+	found, err := NewCaseNoteRepository(db).Update(context.Background(), models.CaseNote{
+		ID: 9, Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Text: models.NoteText{EN: "Backlog"}, UpdatedAt: now,
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// CaseRevisionRepository reads the revisions of published case rows from the
+// case_revisions view
+type CaseRevisionRepository interface {
+	// ListByRow returns the revisions of a national_cases or province_cases
+	// row, oldest first
+	ListByRow(ctx context.Context, table string, rowID int64) ([]models.CaseRevision, error)
+}
+
+type caseRevisionRepository struct {
+	db *database.DB
+}
+
+func NewCaseRevisionRepository(db *database.DB) CaseRevisionRepository {
+	return &caseRevisionRepository{db: db}
+}
+
+func (r *caseRevisionRepository) ListByRow(ctx context.Context, table string, rowID int64) ([]models.CaseRevision, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, sync_log_id, source, action, before_data, after_data, created_at, rolled_back_at
+		FROM case_revisions
+		WHERE table_name = ? AND row_id = ?
+		ORDER BY id`, table, rowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query case revisions: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	revisions := []models.CaseRevision{}
+	for rows.Next() {
+		var rev models.CaseRevision
+		var runID sql.NullInt64
+		var source sql.NullString
+		var before, after []byte
+		var rolledBackAt sql.NullTime
+		if err := rows.Scan(&rev.ID, &runID, &source, &rev.Action, &before, &after, &rev.ChangedAt, &rolledBackAt); err != nil {
+			return nil, fmt.Errorf("failed to scan case revision: %w", err)
+		}
+		beforeData, err := unmarshalRowSnapshot(before)
+		if err != nil {
+			return nil, err
+		}
+		afterData, err := unmarshalRowSnapshot(after)
+		if err != nil {
+			return nil, err
+		}
+		if runID.Valid {
+			rev.SyncRunID = &runID.Int64
+		}
+		rev.Source = source.String
+		if rolledBackAt.Valid {
+			rev.RolledBackAt = &rolledBackAt.Time
+		}
+		rev.Changes = models.DiffSnapshots(beforeData, afterData)
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return revisions, nil
+}
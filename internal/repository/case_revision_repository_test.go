@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseRevisionRepository_ListByRow(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewCaseRevisionRepository(db)
+
+	inserted := time.Date(2021, 8, 1, 6, 0, 0, 0, time.UTC)
+	corrected := inserted.Add(24 * time.Hour)
+	rolledBack := corrected.Add(time.Hour)
+	mock.ExpectQuery(`SELECT id, sync_log_id, source, action, before_data, after_data, created_at, rolled_back_at\s+FROM case_revisions\s+WHERE table_name = \? AND row_id = \?\s+ORDER BY id`).
+		WithArgs("national_cases", int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sync_log_id", "source", "action", "before_data", "after_data", "created_at", "rolled_back_at"}).
+			AddRow(1, 10, "upstream", "insert", nil, `{"id": 7, "positive": 10}`, inserted, nil).
+			AddRow(2, 11, "rt-estimation", "update", `{"id": 7, "positive": 10, "rt": null}`, `{"id": 7, "positive": 10, "rt": 1.1}`, corrected, rolledBack).
+			AddRow(3, nil, nil, "update", `{"positive": 10}`, `{"positive": 11}`, corrected, nil))
+
+	revisions, err := repo.ListByRow(context.Background(), "national_cases", 7)
+
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, int64(10), *revisions[0].SyncRunID)
+	assert.Equal(t, "upstream", revisions[0].Source)
+	assert.Equal(t, []models.FieldChange{{Field: "positive", After: int64(10)}}, revisions[0].Changes)
+	assert.Nil(t, revisions[0].RolledBackAt)
+	assert.Equal(t, []models.FieldChange{{Field: "rt", After: 1.1}}, revisions[1].Changes)
+	assert.Equal(t, rolledBack, *revisions[1].RolledBackAt)
+	assert.Nil(t, revisions[2].SyncRunID)
+	assert.Empty(t, revisions[2].Source)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"log"
 	"database/sql"
 	"fmt"
@@ -11,9 +12,9 @@ import (
 
 // HospitalRepositoryInterface defines the contract for hospital repository operations
 type HospitalRepositoryInterface interface {
-	GetAll(provinceID int) ([]models.Hospital, error)
-	GetPaginated(provinceID, limit, offset int) ([]models.Hospital, int, error)
-	GetByCode(code string) (*models.Hospital, error)
+	GetAll(ctx context.Context, provinceID int) ([]models.Hospital, error)
+	GetPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.Hospital, int, error)
+	GetByCode(ctx context.Context, code string) (*models.Hospital, error)
 }
 
 // HospitalRepository handles database operations for hospitals
@@ -27,7 +28,7 @@ func NewHospitalRepository(db *database.DB) *HospitalRepository {
 }
 
 // GetAll returns all hospitals for a province (regency_id LIKE provinceID%)
-func (r *HospitalRepository) GetAll(provinceID int) ([]models.Hospital, error) {
+func (r *HospitalRepository) GetAll(ctx context.Context, provinceID int) ([]models.Hospital, error) {
 	query := `SELECT h.id, h.regency_id, h.name, h.hospital_code, h.address, h.latitude, h.longitude,
 		COALESCE((SELECT available FROM hospital_beds WHERE hospital_id = h.id AND hospital_bed_type_id = 1 LIMIT 1), 0) as igd_count
 		FROM hospitals h
@@ -35,7 +36,7 @@ func (r *HospitalRepository) GetAll(provinceID int) ([]models.Hospital, error) {
 		ORDER BY h.name`
 
 	likeParam := fmt.Sprintf("%d%%", provinceID)
-	rows, err := r.db.Query(query, likeParam)
+	rows, err := r.db.QueryContext(ctx, query, likeParam)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hospitals: %w", err)
 	}
@@ -60,13 +61,13 @@ func (r *HospitalRepository) GetAll(provinceID int) ([]models.Hospital, error) {
 
 	// Load contacts and beds for each hospital
 	for i := range hospitals {
-		contacts, err := r.getContacts("App\\Models\\Hospital", hospitals[i].ID)
+		contacts, err := r.getContacts(ctx, "App\\Models\\Hospital", hospitals[i].ID)
 		if err != nil {
 			return nil, err
 		}
 		hospitals[i].Contacts = contacts
 
-		beds, err := r.getBeds(hospitals[i].ID)
+		beds, err := r.getBeds(ctx, hospitals[i].ID)
 		if err != nil {
 			return nil, err
 		}
@@ -77,12 +78,12 @@ func (r *HospitalRepository) GetAll(provinceID int) ([]models.Hospital, error) {
 }
 
 // GetPaginated returns a page of hospitals along with total count
-func (r *HospitalRepository) GetPaginated(provinceID, limit, offset int) ([]models.Hospital, int, error) {
+func (r *HospitalRepository) GetPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.Hospital, int, error) {
 	likeParam := fmt.Sprintf("%d%%", provinceID)
 
 	var total int
 	countQuery := `SELECT COUNT(*) FROM hospitals WHERE regency_id LIKE ?`
-	if err := r.db.QueryRow(countQuery, likeParam).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, countQuery, likeParam).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to count hospitals: %w", err)
 	}
 
@@ -93,7 +94,7 @@ func (r *HospitalRepository) GetPaginated(provinceID, limit, offset int) ([]mode
 		ORDER BY h.name
 		LIMIT ? OFFSET ?`
 
-	rows, err := r.db.Query(query, likeParam, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, likeParam, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query hospitals: %w", err)
 	}
@@ -117,13 +118,13 @@ func (r *HospitalRepository) GetPaginated(provinceID, limit, offset int) ([]mode
 	}
 
 	for i := range hospitals {
-		contacts, err := r.getContacts("App\\Models\\Hospital", hospitals[i].ID)
+		contacts, err := r.getContacts(ctx, "App\\Models\\Hospital", hospitals[i].ID)
 		if err != nil {
 			return nil, 0, err
 		}
 		hospitals[i].Contacts = contacts
 
-		beds, err := r.getBeds(hospitals[i].ID)
+		beds, err := r.getBeds(ctx, hospitals[i].ID)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -134,14 +135,14 @@ func (r *HospitalRepository) GetPaginated(provinceID, limit, offset int) ([]mode
 }
 
 // GetByCode returns a hospital by its code
-func (r *HospitalRepository) GetByCode(code string) (*models.Hospital, error) {
+func (r *HospitalRepository) GetByCode(ctx context.Context, code string) (*models.Hospital, error) {
 	query := `SELECT h.id, h.regency_id, h.name, h.hospital_code, h.address, h.latitude, h.longitude,
 		COALESCE((SELECT available FROM hospital_beds WHERE hospital_id = h.id AND hospital_bed_type_id = 1 LIMIT 1), 0) as igd_count
 		FROM hospitals h
 		WHERE h.hospital_code = ?`
 
 	var h models.Hospital
-	err := r.db.QueryRow(query, code).Scan(&h.ID, &h.RegencyID, &h.Name, &h.HospitalCode, &h.Address,
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&h.ID, &h.RegencyID, &h.Name, &h.HospitalCode, &h.Address,
 		&h.Latitude, &h.Longitude, &h.IGDCount)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -150,13 +151,13 @@ func (r *HospitalRepository) GetByCode(code string) (*models.Hospital, error) {
 		return nil, fmt.Errorf("failed to get hospital: %w", err)
 	}
 
-	contacts, err := r.getContacts("App\\Models\\Hospital", h.ID)
+	contacts, err := r.getContacts(ctx, "App\\Models\\Hospital", h.ID)
 	if err != nil {
 		return nil, err
 	}
 	h.Contacts = contacts
 
-	beds, err := r.getBeds(h.ID)
+	beds, err := r.getBeds(ctx, h.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -165,13 +166,13 @@ func (r *HospitalRepository) GetByCode(code string) (*models.Hospital, error) {
 	return &h, nil
 }
 
-func (r *HospitalRepository) getContacts(contactableType string, contactableID int64) ([]models.Contact, error) {
+func (r *HospitalRepository) getContacts(ctx context.Context, contactableType string, contactableID int64) ([]models.Contact, error) {
 	query := `SELECT c.id, c.contact_type_id, c.contact, ct.name, ct.icon
 		FROM contacts c
 		JOIN contact_types ct ON c.contact_type_id = ct.id
 		WHERE c.contactable_type = ? AND c.contactable_id = ?`
 
-	rows, err := r.db.Query(query, contactableType, contactableID)
+	rows, err := r.db.QueryContext(ctx, query, contactableType, contactableID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query contacts: %w", err)
 	}
@@ -192,13 +193,13 @@ func (r *HospitalRepository) getContacts(contactableType string, contactableID i
 	return contacts, rows.Err()
 }
 
-func (r *HospitalRepository) getBeds(hospitalID int64) ([]models.HospitalBed, error) {
+func (r *HospitalRepository) getBeds(ctx context.Context, hospitalID int64) ([]models.HospitalBed, error) {
 	query := `SELECT hb.id, hb.hospital_id, hb.hospital_bed_type_id, hbt.name, hb.available, hb.total
 		FROM hospital_beds hb
 		JOIN hospital_bed_types hbt ON hb.hospital_bed_type_id = hbt.id
 		WHERE hb.hospital_id = ?`
 
-	rows, err := r.db.Query(query, hospitalID)
+	rows, err := r.db.QueryContext(ctx, query, hospitalID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hospital beds: %w", err)
 	}
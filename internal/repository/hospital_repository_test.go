@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestHospitalRepository_GetAll(t *testing.T) {
 	expectEmptyContacts(mock, 1)
 	expectEmptyBeds(mock, 1)
 
-	hospitals, err := repo.GetAll(72)
+	hospitals, err := repo.GetAll(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, hospitals, 1)
 	assert.Equal(t, "RSUD Palu", hospitals[0].Name)
@@ -66,7 +67,7 @@ func TestHospitalRepository_GetAll_WithContactsAndBeds(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows(bedCols).
 			AddRow(1, 1, 1, "ICU", 5, 10))
 
-	hospitals, err := repo.GetAll(72)
+	hospitals, err := repo.GetAll(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, hospitals, 1)
 	assert.Len(t, hospitals[0].Contacts, 1)
@@ -81,7 +82,7 @@ func TestHospitalRepository_GetAll_QueryError(t *testing.T) {
 		WithArgs("72%").
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetAll(72)
+	_, err := repo.GetAll(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -97,7 +98,7 @@ func TestHospitalRepository_GetAll_ContactsError(t *testing.T) {
 		WithArgs("App\\Models\\Hospital", int64(1)).
 		WillReturnError(errors.New("contact query error"))
 
-	_, err := repo.GetAll(72)
+	_, err := repo.GetAll(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -114,7 +115,7 @@ func TestHospitalRepository_GetAll_BedsError(t *testing.T) {
 		WithArgs(int64(1)).
 		WillReturnError(errors.New("beds query error"))
 
-	_, err := repo.GetAll(72)
+	_, err := repo.GetAll(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -130,7 +131,7 @@ func TestHospitalRepository_GetByCode(t *testing.T) {
 	expectEmptyContacts(mock, 1)
 	expectEmptyBeds(mock, 1)
 
-	hospital, err := repo.GetByCode("7201001")
+	hospital, err := repo.GetByCode(context.Background(), "7201001")
 	assert.NoError(t, err)
 	assert.NotNil(t, hospital)
 	assert.Equal(t, "RSUD Palu", hospital.Name)
@@ -144,7 +145,7 @@ func TestHospitalRepository_GetByCode_NotFound(t *testing.T) {
 		WithArgs("invalid").
 		WillReturnError(sqlmock.ErrCancelled)
 
-	_, err := repo.GetByCode("invalid")
+	_, err := repo.GetByCode(context.Background(), "invalid")
 	assert.Error(t, err)
 }
 
@@ -155,7 +156,7 @@ func TestHospitalRepository_GetByCode_DBError(t *testing.T) {
 		WithArgs("7201001").
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetByCode("7201001")
+	_, err := repo.GetByCode(context.Background(), "7201001")
 	assert.Error(t, err)
 }
 
@@ -175,7 +176,7 @@ func TestHospitalRepository_GetPaginated(t *testing.T) {
 	expectEmptyContacts(mock, 1)
 	expectEmptyBeds(mock, 1)
 
-	hospitals, total, err := repo.GetPaginated(72, 10, 0)
+	hospitals, total, err := repo.GetPaginated(context.Background(), 72, 10, 0)
 	assert.NoError(t, err)
 	assert.Len(t, hospitals, 1)
 	assert.Equal(t, 1, total)
@@ -189,7 +190,7 @@ func TestHospitalRepository_GetPaginated_CountError(t *testing.T) {
 		WithArgs("72%").
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetPaginated(72, 10, 0)
+	_, _, err := repo.GetPaginated(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
 
@@ -204,6 +205,6 @@ func TestHospitalRepository_GetPaginated_QueryError(t *testing.T) {
 		WithArgs("72%", 10, 0).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetPaginated(72, 10, 0)
+	_, _, err := repo.GetPaginated(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// IndexRepository reads the indexes defined in the current schema
+type IndexRepository interface {
+	ListIndexes(ctx context.Context) ([]models.TableIndex, error)
+}
+
+type indexRepository struct {
+	db *database.DB
+}
+
+func NewIndexRepository(db *database.DB) IndexRepository {
+	return &indexRepository{db: db}
+}
+
+// ListIndexes returns every index in the current schema, including primary keys
+func (r *indexRepository) ListIndexes(ctx context.Context) ([]models.TableIndex, error) {
+	query := `SELECT table_name, index_name, column_name, non_unique
+			  FROM information_schema.statistics
+			  WHERE table_schema = DATABASE()
+			  ORDER BY table_name, index_name, seq_in_index`
+	if r.db.Dialect() == database.DialectPostgres {
+		// PostgreSQL has no information_schema.statistics
+		query = `SELECT t.relname, i.relname, a.attname, NOT ix.indisunique
+			  FROM pg_index ix
+			  JOIN pg_class t ON t.oid = ix.indrelid
+			  JOIN pg_class i ON i.oid = ix.indexrelid
+			  JOIN pg_namespace n ON n.oid = t.relnamespace
+			  JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, position) ON true
+			  JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+			  WHERE n.nspname = current_schema()
+			  ORDER BY t.relname, i.relname, k.position`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var indexes []models.TableIndex
+	for rows.Next() {
+		var table, name, column string
+		var nonUnique bool
+		if err := rows.Scan(&table, &name, &column, &nonUnique); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		last := len(indexes) - 1
+		if last >= 0 && indexes[last].Table == table && indexes[last].Name == name {
+			indexes[last].Columns = append(indexes[last].Columns, column)
+			continue
+		}
+		indexes = append(indexes, models.TableIndex{Table: table, Name: name, Columns: []string{column}, Unique: !nonUnique})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexRepository_ListIndexes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIndexRepository(db)
+
+	mock.ExpectQuery(`FROM information_schema.statistics`).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "index_name", "column_name", "non_unique"}).
+			AddRow("national_cases", "PRIMARY", "id", 0).
+			AddRow("province_cases", "idx_province_cases_day", "day", 1).
+			AddRow("province_cases", "uq_province_cases_province_day", "province_id", 0).
+			AddRow("province_cases", "uq_province_cases_province_day", "day", 0))
+
+	indexes, err := repo.ListIndexes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, indexes, 3)
+	assert.False(t, indexes[1].Unique)
+	assert.Equal(t, []string{"province_id", "day"}, indexes[2].Columns)
+	assert.True(t, indexes[2].Unique)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
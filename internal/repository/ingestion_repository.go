@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// IngestionRepository writes upstream case rows, upserting on the natural key
+// (day for national cases, province and day for province cases) so a re-sync
+// never creates duplicate rows.
+type IngestionRepository interface {
+	// Ingest writes the batch as a sync run. With dryRun no run is recorded
+	// and the transaction is rolled back after computing the summary.
+	Ingest(ctx context.Context, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error)
+}
+
+// ErrDuplicateCase matches a DuplicateCaseError
+var ErrDuplicateCase = errors.New("case row already exists")
+
+// DuplicateCaseError is returned when a case row cannot be inserted because
+// the table's unique key already holds its natural key, e.g. for a row a
+// concurrent submission inserted after the key was looked up. Existing is the
+// stored row, when it could be read.
+type DuplicateCaseError struct {
+	Table    string                 `json:"table"`
+	Key      string                 `json:"key"`
+	ID       int64                  `json:"id,omitempty"`
+	Existing map[string]interface{} `json:"existing,omitempty"`
+}
+
+func (e *DuplicateCaseError) Error() string {
+	if e.ID != 0 {
+		return fmt.Sprintf("%s row %s already exists (id %d)", e.Table, e.Key, e.ID)
+	}
+	return fmt.Sprintf("%s row %s already exists", e.Table, e.Key)
+}
+
+func (e *DuplicateCaseError) Is(target error) bool {
+	return target == ErrDuplicateCase
+}
+
+type ingestionRepository struct {
+	db      *database.DB
+	syncLog SyncLogRepository
+}
+
+func NewIngestionRepository(db *database.DB) IngestionRepository {
+	return &ingestionRepository{db: db, syncLog: NewSyncLogRepository(db)}
+}
+
+// storedRow is an existing row matching an incoming row's natural key.
+// deleted marks a row hidden by the soft delete endpoints.
+type storedRow struct {
+	id      int64
+	data    map[string]interface{}
+	deleted bool
+}
+
+// Ingest writes the batch in one transaction recorded as a sync run. Rows whose key
+// repeats within the batch, or which already match several stored rows, are
+// rejected and reported as conflicts rather than written.
+func (r *ingestionRepository) Ingest(ctx context.Context, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error) {
+	if dryRun {
+		return r.ingest(ctx, 0, batch, true)
+	}
+
+	runID, err := r.syncLog.CreateRun(ctx, batch.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := r.ingest(ctx, runID, batch, false)
+	if err != nil {
+		if finishErr := r.syncLog.FinishRun(ctx, runID, models.SyncStatusFailed, 0, 0, 0); finishErr != nil {
+			log.Printf("Error marking sync run %d failed: %v", runID, finishErr)
+		}
+		return nil, err
+	}
+
+	// sync_log has no restored count; restored rows were updated in place
+	if err := r.syncLog.FinishRun(ctx, runID, models.SyncStatusCompleted, summary.Inserted, summary.Updated+summary.Restored, len(summary.Conflicts)); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (r *ingestionRepository) ingest(ctx context.Context, runID int64, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin ingestion transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+				log.Printf("Error rolling back transaction: %v", err)
+			}
+		}
+	}()
+
+	summary := &models.SyncSummary{RunID: runID, DryRun: dryRun, Conflicts: []models.SyncConflict{}}
+
+	// National rows go first: province_cases.day references national_cases.id
+	seen := make(map[string]bool)
+	for _, c := range batch.National {
+		key := fmt.Sprintf("day=%d", c.Day)
+		if seen[key] {
+			summary.AddConflict("national_cases", key, "duplicate key in batch; first occurrence kept")
+			continue
+		}
+		seen[key] = true
+
+		existing, err := findNationalCases(ctx, tx, c.Day)
+		if err != nil {
+			return nil, err
+		}
+		action, before, err := upsertRow(ctx, tx, runID, "national_cases", key, existing, nationalCaseSnapshot(c), dryRun, summary)
+		if err != nil {
+			return nil, duplicateCase(err, "national_cases", key, func() ([]storedRow, error) {
+				return findNationalCases(ctx, tx, c.Day)
+			})
+		}
+		if action != "" {
+			change := models.CaseChange{Table: "national_cases", Key: key, Action: action, Day: c.Day, Rt: c.Rt}
+			if change.PreviousRt, err = previousRt(ctx, tx, action, before, `SELECT rt FROM national_cases WHERE day < ? AND deleted_at IS NULL ORDER BY day DESC LIMIT 1`, c.Day); err != nil {
+				return nil, err
+			}
+			summary.Changes = append(summary.Changes, change)
+		}
+	}
+
+	seen = make(map[string]bool)
+	for _, c := range batch.Province {
+		key := fmt.Sprintf("province_id=%s,day=%d", c.ProvinceID, c.Day)
+		if seen[key] {
+			summary.AddConflict("province_cases", key, "duplicate key in batch; first occurrence kept")
+			continue
+		}
+		seen[key] = true
+
+		existing, err := findProvinceCases(ctx, tx, c.ProvinceID, c.Day)
+		if err != nil {
+			return nil, err
+		}
+		action, before, err := upsertRow(ctx, tx, runID, "province_cases", key, existing, provinceCaseSnapshot(c), dryRun, summary)
+		if err != nil {
+			return nil, duplicateCase(err, "province_cases", key, func() ([]storedRow, error) {
+				return findProvinceCases(ctx, tx, c.ProvinceID, c.Day)
+			})
+		}
+		if action != "" {
+			change := models.CaseChange{Table: "province_cases", Key: key, Action: action, Day: c.Day, ProvinceID: c.ProvinceID, Rt: c.Rt}
+			if change.PreviousRt, err = previousRt(ctx, tx, action, before, `SELECT rt FROM province_cases WHERE province_id = ? AND day < ? AND deleted_at IS NULL ORDER BY day DESC LIMIT 1`, c.ProvinceID, c.Day); err != nil {
+				return nil, err
+			}
+			summary.Changes = append(summary.Changes, change)
+		}
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit ingestion: %w", err)
+	}
+	committed = true
+	return summary, nil
+}
+
+// upsertRow inserts the row when no stored row has its key, updates the single
+// stored row when it differs, and reports a conflict when the key is already
+// duplicated in the table. A soft-deleted stored row is restored and updated
+// in place, recorded as a restore revision followed by an update revision
+// when the values differ, so rolling the run back hides the row again. It
+// returns the action taken, empty when the row was left alone, and the stored
+// values an update replaced. A dry run records no revision.
+func upsertRow(ctx context.Context, exec sqlExecutor, runID int64, table, key string, existing []storedRow, after map[string]interface{}, dryRun bool, summary *models.SyncSummary) (string, map[string]interface{}, error) {
+	columns, values, err := revisionColumns(after)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch len(existing) {
+	case 0:
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		id, err := exec.InsertContext(ctx, `INSERT INTO `+quoteIdentifier(table)+` (`+strings.Join(columns, ", ")+`) VALUES (`+placeholders+`)`, values...)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to insert %s row %s: %w", table, key, err)
+		}
+		if !dryRun {
+			if err := insertRevision(ctx, exec, models.Revision{SyncRunID: runID, TableName: table, RowID: id, Action: models.ChangeActionInsert, After: after}); err != nil {
+				return "", nil, err
+			}
+		}
+		summary.Inserted++
+		return models.ChangeActionInsert, nil, nil
+	case 1:
+		stored := existing[0]
+		changed := !reflect.DeepEqual(stored.data, after)
+		if !changed && !stored.deleted {
+			summary.Unchanged++
+			return "", nil, nil
+		}
+		assignments := make([]string, len(columns))
+		for i, c := range columns {
+			assignments[i] = c + " = ?"
+		}
+		if stored.deleted {
+			assignments = append(assignments, "deleted_at = NULL")
+		}
+		values = append(values, stored.id)
+		if _, err := exec.ExecContext(ctx, `UPDATE `+quoteIdentifier(table)+` SET `+strings.Join(assignments, ", ")+` WHERE id = ?`, values...); err != nil {
+			return "", nil, fmt.Errorf("failed to update %s row %d: %w", table, stored.id, err)
+		}
+		if !dryRun {
+			// A deleted row is one that does not exist, as for the soft delete endpoints
+			if stored.deleted {
+				if err := insertRevision(ctx, exec, models.Revision{SyncRunID: runID, TableName: table, RowID: stored.id, Action: models.ChangeActionRestore, After: stored.data}); err != nil {
+					return "", nil, err
+				}
+			}
+			if changed {
+				if err := insertRevision(ctx, exec, models.Revision{SyncRunID: runID, TableName: table, RowID: stored.id, Action: models.ChangeActionUpdate, Before: stored.data, After: after}); err != nil {
+					return "", nil, err
+				}
+			}
+		}
+		if stored.deleted {
+			// The row reappears like an inserted one, so no values are replaced
+			summary.Restored++
+			return models.ChangeActionRestore, nil, nil
+		}
+		summary.Updated++
+		return models.ChangeActionUpdate, stored.data, nil
+	default:
+		ids := make([]string, len(existing))
+		for i, row := range existing {
+			ids[i] = fmt.Sprint(row.id)
+		}
+		sort.Strings(ids)
+		summary.AddConflict(table, key, fmt.Sprintf("%d rows already stored for this key (ids %s); resolve manually", len(existing), strings.Join(ids, ", ")))
+	}
+	return "", nil, nil
+}
+
+// duplicateCase turns a unique key violation of upsertRow into a
+// DuplicateCaseError with the stored row, which find reads again since it was
+// not there when the key was looked up. Other errors are returned as is.
+func duplicateCase(err error, table, key string, find func() ([]storedRow, error)) error {
+	if !database.IsDuplicateKey(err) {
+		return err
+	}
+	dup := &DuplicateCaseError{Table: table, Key: key}
+	existing, findErr := find()
+	if findErr != nil {
+		log.Printf("Error reading %s row %s after a duplicate key: %v", table, key, findErr)
+	} else if len(existing) > 0 {
+		dup.ID = existing[0].id
+		dup.Existing = existing[0].data
+	}
+	return dup
+}
+
+// previousRt returns the Rt an update replaced, or for an insert the Rt of the
+// latest earlier day selected by query
+func previousRt(ctx context.Context, exec sqlExecutor, action string, before map[string]interface{}, query string, args ...interface{}) (*float64, error) {
+	if action == models.ChangeActionUpdate {
+		if rt, ok := before["rt"].(float64); ok {
+			return &rt, nil
+		}
+		return nil, nil
+	}
+
+	var rt sql.NullFloat64
+	if err := exec.QueryRowContext(ctx, query, args...).Scan(&rt); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to query previous rt: %w", err)
+	}
+	if !rt.Valid {
+		return nil, nil
+	}
+	return &rt.Float64, nil
+}
+
+func findNationalCases(ctx context.Context, exec sqlExecutor, day int64) ([]storedRow, error) {
+	rows, err := exec.QueryContext(ctx, `SELECT id, day, date, positive, recovered, deceased,
+		cumulative_positive, cumulative_recovered, cumulative_deceased,
+		rt, rt_upper, rt_lower, deleted_at
+		FROM national_cases WHERE day = ? FOR UPDATE`, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query national case for day %d: %w", day, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var stored []storedRow
+	for rows.Next() {
+		var c models.NationalCase
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
+			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
+			&c.Rt, &c.RtUpper, &c.RtLower, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan national case: %w", err)
+		}
+		stored = append(stored, storedRow{id: c.ID, data: nationalCaseSnapshot(c), deleted: deletedAt.Valid})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return stored, nil
+}
+
+func findProvinceCases(ctx context.Context, exec sqlExecutor, provinceID string, day int64) ([]storedRow, error) {
+	rows, err := exec.QueryContext(ctx, `SELECT id, day, province_id, positive, recovered, deceased,
+		person_under_observation, finished_person_under_observation,
+		person_under_supervision, finished_person_under_supervision,
+		cumulative_positive, cumulative_recovered, cumulative_deceased,
+		cumulative_person_under_observation, cumulative_finished_person_under_observation,
+		cumulative_person_under_supervision, cumulative_finished_person_under_supervision,
+		rt, rt_upper, rt_lower, deleted_at
+		FROM province_cases WHERE province_id = ? AND day = ? FOR UPDATE`, provinceID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query province case for %s day %d: %w", provinceID, day, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var stored []storedRow
+	for rows.Next() {
+		var c models.ProvinceCase
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.Day, &c.ProvinceID, &c.Positive, &c.Recovered, &c.Deceased,
+			&c.PersonUnderObservation, &c.FinishedPersonUnderObservation,
+			&c.PersonUnderSupervision, &c.FinishedPersonUnderSupervision,
+			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
+			&c.CumulativePersonUnderObservation, &c.CumulativeFinishedPersonUnderObservation,
+			&c.CumulativePersonUnderSupervision, &c.CumulativeFinishedPersonUnderSupervision,
+			&c.Rt, &c.RtUpper, &c.RtLower, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan province case: %w", err)
+		}
+		stored = append(stored, storedRow{id: c.ID, data: provinceCaseSnapshot(c), deleted: deletedAt.Valid})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return stored, nil
+}
+
+// nationalCaseSnapshot returns the writable columns of a national case, excluding id
+func nationalCaseSnapshot(c models.NationalCase) map[string]interface{} {
+	return map[string]interface{}{
+		"day":                  c.Day,
+		"date":                 c.Date.Format("2006-01-02"),
+		"positive":             c.Positive,
+		"recovered":            c.Recovered,
+		"deceased":             c.Deceased,
+		"cumulative_positive":  c.CumulativePositive,
+		"cumulative_recovered": c.CumulativeRecovered,
+		"cumulative_deceased":  c.CumulativeDeceased,
+		"rt":                   floatPtrValue(c.Rt),
+		"rt_upper":             floatPtrValue(c.RtUpper),
+		"rt_lower":             floatPtrValue(c.RtLower),
+	}
+}
+
+// provinceCaseSnapshot returns the writable columns of a province case, excluding id
+func provinceCaseSnapshot(c models.ProvinceCase) map[string]interface{} {
+	return map[string]interface{}{
+		"day":                                 c.Day,
+		"province_id":                         c.ProvinceID,
+		"positive":                            c.Positive,
+		"recovered":                           c.Recovered,
+		"deceased":                            c.Deceased,
+		"person_under_observation":            c.PersonUnderObservation,
+		"finished_person_under_observation":   c.FinishedPersonUnderObservation,
+		"person_under_supervision":            c.PersonUnderSupervision,
+		"finished_person_under_supervision":   c.FinishedPersonUnderSupervision,
+		"cumulative_positive":                 c.CumulativePositive,
+		"cumulative_recovered":                c.CumulativeRecovered,
+		"cumulative_deceased":                 c.CumulativeDeceased,
+		"cumulative_person_under_observation": c.CumulativePersonUnderObservation,
+		"cumulative_finished_person_under_observation": c.CumulativeFinishedPersonUnderObservation,
+		"cumulative_person_under_supervision":          c.CumulativePersonUnderSupervision,
+		"cumulative_finished_person_under_supervision": c.CumulativeFinishedPersonUnderSupervision,
+		"rt":       floatPtrValue(c.Rt),
+		"rt_upper": floatPtrValue(c.RtUpper),
+		"rt_lower": floatPtrValue(c.RtLower),
+	}
+}
+
+func floatPtrValue(f *float64) interface{} {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
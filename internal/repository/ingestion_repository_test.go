@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var nationalIngestCols = []string{"id", "day", "date", "positive", "recovered", "deceased",
+	"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "rt", "rt_upper", "rt_lower", "deleted_at"}
+
+var provinceIngestCols = []string{"id", "day", "province_id", "positive", "recovered", "deceased",
+	"person_under_observation", "finished_person_under_observation",
+	"person_under_supervision", "finished_person_under_supervision",
+	"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+	"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+	"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+	"rt", "rt_upper", "rt_lower", "deleted_at"}
+
+func provinceIngestRow(rows *sqlmock.Rows, id int64, provinceID string, positive int64) *sqlmock.Rows {
+	return rows.AddRow(id, 1, provinceID, positive, 0, 0, 0, 0, 0, 0, positive, 0, 0, 0, 0, 0, 0, nil, nil, nil, nil)
+}
+
+func TestIngestionRepository_Ingest(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIngestionRepository(db)
+	date := time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	batch := models.IngestionBatch{
+		Source: "upstream",
+		National: []models.NationalCase{
+			{Day: 1, Date: date, Positive: 2, CumulativePositive: 2},
+			{Day: 1, Date: date, Positive: 5, CumulativePositive: 5},
+			{Day: 2, Date: date.AddDate(0, 0, 1), Positive: 1, CumulativePositive: 3},
+		},
+		Province: []models.ProvinceCase{
+			{Day: 1, ProvinceID: "72", Positive: 4, CumulativePositive: 4},
+			{Day: 1, ProvinceID: "73", Positive: 1, CumulativePositive: 1},
+		},
+	}
+
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectBegin()
+
+	// day 1 is new
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols))
+	mock.ExpectExec("INSERT INTO `national_cases`").WillReturnResult(sqlmock.NewResult(101, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(9), "national_cases", int64(101), models.ChangeActionInsert, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT rt FROM national_cases WHERE day < \? AND deleted_at IS NULL ORDER BY day DESC LIMIT 1`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"rt"}))
+
+	// day 2 is already stored with identical values
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(102, 2, date.AddDate(0, 0, 1), 1, 0, 0, 3, 0, 0, nil, nil, nil, nil))
+
+	// province 72 changed
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(1)).
+		WillReturnRows(provinceIngestRow(sqlmock.NewRows(provinceIngestCols), 300, "72", 3))
+	mock.ExpectExec("UPDATE `province_cases` SET .* WHERE id = \\?").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(9), "province_cases", int64(300), models.ChangeActionUpdate, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	// province 73 is already duplicated in the table
+	rows := provinceIngestRow(sqlmock.NewRows(provinceIngestCols), 310, "73", 1)
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("73", int64(1)).
+		WillReturnRows(provinceIngestRow(rows, 311, "73", 1))
+
+	mock.ExpectCommit()
+	mock.ExpectExec(`UPDATE sync_log SET status = \?, inserted = \?, updated = \?, conflicts = \?`).
+		WithArgs(models.SyncStatusCompleted, 1, 1, 2, sqlmock.AnyArg(), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	summary, err := repo.Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), summary.RunID)
+	assert.Equal(t, 1, summary.Inserted)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 1, summary.Unchanged)
+	if assert.Len(t, summary.Conflicts, 2) {
+		assert.Equal(t, "day=1", summary.Conflicts[0].Key)
+		assert.Equal(t, "province_id=73,day=1", summary.Conflicts[1].Key)
+		assert.Contains(t, summary.Conflicts[1].Reason, "310, 311")
+	}
+	if assert.Len(t, summary.Changes, 2) {
+		assert.Equal(t, models.CaseChange{Table: "national_cases", Key: "day=1", Action: models.ChangeActionInsert, Day: 1}, summary.Changes[0])
+		assert.True(t, summary.Changes[1].IsCorrection())
+		assert.Equal(t, "72", summary.Changes[1].ProvinceID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestionRepository_Ingest_RecordsPreviousRt(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIngestionRepository(db)
+	rt := 1.2
+
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(5)).
+		WillReturnRows(sqlmock.NewRows(provinceIngestCols))
+	mock.ExpectExec("INSERT INTO `province_cases`").WillReturnResult(sqlmock.NewResult(400, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT rt FROM province_cases WHERE province_id = \? AND day < \?`).WithArgs("72", int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"rt"}).AddRow(0.9))
+	mock.ExpectCommit()
+	mock.ExpectExec(`UPDATE sync_log SET status = \?`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	summary, err := repo.Ingest(context.Background(), models.IngestionBatch{Source: "upstream", Province: []models.ProvinceCase{{Day: 5, ProvinceID: "72", Rt: &rt}}}, false)
+
+	assert.NoError(t, err)
+	if assert.Len(t, summary.Changes, 1) {
+		assert.Equal(t, 0.9, *summary.Changes[0].PreviousRt)
+		assert.True(t, summary.Changes[0].RtCrossesOne())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestionRepository_Ingest_DryRun(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIngestionRepository(db)
+
+	// No run is recorded and no revision written
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(5)).
+		WillReturnRows(sqlmock.NewRows(provinceIngestCols))
+	mock.ExpectExec("INSERT INTO `province_cases`").WillReturnResult(sqlmock.NewResult(400, 1))
+	mock.ExpectQuery(`SELECT rt FROM province_cases WHERE province_id = \? AND day < \?`).WithArgs("72", int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"rt"}))
+	mock.ExpectRollback()
+
+	summary, err := repo.Ingest(context.Background(), models.IngestionBatch{Source: "upstream", Province: []models.ProvinceCase{{Day: 5, ProvinceID: "72"}}}, true)
+
+	assert.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, int64(0), summary.RunID)
+	assert.Equal(t, 1, summary.Inserted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestionRepository_Ingest_RestoresSoftDeletedRow(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIngestionRepository(db)
+	deletedAt := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(1)).
+		WillReturnRows(sqlmock.NewRows(provinceIngestCols).
+			AddRow(300, 1, "72", 3, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, nil, nil, nil, deletedAt))
+	mock.ExpectExec("UPDATE `province_cases` SET .*, deleted_at = NULL WHERE id = \\?").WillReturnResult(sqlmock.NewResult(0, 1))
+	// Rolling the run back reverts the update, then hides the row again
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(9), "province_cases", int64(300), models.ChangeActionRestore, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(9), "province_cases", int64(300), models.ChangeActionUpdate, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectQuery(`SELECT rt FROM province_cases WHERE province_id = \? AND day < \?`).WithArgs("72", int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"rt"}))
+	mock.ExpectCommit()
+	mock.ExpectExec(`UPDATE sync_log SET status = \?`).
+		WithArgs(models.SyncStatusCompleted, 0, 1, 0, sqlmock.AnyArg(), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	summary, err := repo.Ingest(context.Background(), models.IngestionBatch{Source: "upstream", Province: []models.ProvinceCase{
+		{Day: 1, ProvinceID: "72", Positive: 4, CumulativePositive: 4},
+	}}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Restored)
+	assert.Equal(t, 0, summary.Updated)
+	if assert.Len(t, summary.Changes, 1) {
+		assert.Equal(t, models.ChangeActionRestore, summary.Changes[0].Action)
+		assert.False(t, summary.Changes[0].IsCorrection())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestionRepository_Ingest_FailureMarksRunFailed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIngestionRepository(db)
+
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM national_cases WHERE day = \?`).WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectRollback()
+	mock.ExpectExec(`UPDATE sync_log SET status = \?`).
+		WithArgs(models.SyncStatusFailed, 0, 0, 0, sqlmock.AnyArg(), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := repo.Ingest(context.Background(), models.IngestionBatch{Source: "upstream", National: []models.NationalCase{{Day: 1}}}, false)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestionRepository_Ingest_DuplicateKey(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIngestionRepository(db)
+
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(1)).
+		WillReturnRows(sqlmock.NewRows(provinceIngestCols))
+	mock.ExpectExec("INSERT INTO `province_cases`").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '72-1' for key 'uq_province_cases_province_day'"})
+	// A concurrent submission committed the row in the meantime
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(1)).
+		WillReturnRows(provinceIngestRow(sqlmock.NewRows(provinceIngestCols), 300, "72", 7))
+	mock.ExpectRollback()
+	mock.ExpectExec(`UPDATE sync_log SET status = \?`).
+		WithArgs(models.SyncStatusFailed, 0, 0, 0, sqlmock.AnyArg(), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := repo.Ingest(context.Background(), models.IngestionBatch{Source: "upstream", Province: []models.ProvinceCase{{Day: 1, ProvinceID: "72", Positive: 9}}}, false)
+
+	assert.ErrorIs(t, err, ErrDuplicateCase)
+	var dup *DuplicateCaseError
+	if assert.True(t, errors.As(err, &dup)) {
+		assert.Equal(t, "province_id=72,day=1", dup.Key)
+		assert.Equal(t, int64(300), dup.ID)
+		assert.Equal(t, int64(7), dup.Existing["positive"])
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// IntegrityRepository finds province_cases rows without a matching parent row
+// and moves them to province_cases_quarantine
+type IntegrityRepository interface {
+	FindOrphanProvinceCases(ctx context.Context) ([]models.OrphanProvinceCase, error)
+	QuarantineOrphanProvinceCases(ctx context.Context, dryRun bool) (*models.ChangeSummary, error)
+}
+
+type integrityRepository struct {
+	db *database.DB
+}
+
+func NewIntegrityRepository(db *database.DB) IntegrityRepository {
+	return &integrityRepository{db: db}
+}
+
+// orphanProvinceCasesQuery mirrors the joins of the province case read queries
+// (pc.day = nc.id, pc.province_id = p.id) with LEFT JOINs to expose dropped rows
+const orphanProvinceCasesQuery = `SELECT pc.id, pc.day, pc.province_id, nc.id IS NULL, p.id IS NULL
+	FROM province_cases pc
+	LEFT JOIN national_cases nc ON pc.day = nc.id
+	LEFT JOIN provinces p ON pc.province_id = p.id
+	WHERE nc.id IS NULL OR p.id IS NULL
+	ORDER BY pc.id`
+
+func (r *integrityRepository) FindOrphanProvinceCases(ctx context.Context) ([]models.OrphanProvinceCase, error) {
+	return findOrphanProvinceCases(ctx, r.db, "")
+}
+
+// QuarantineOrphanProvinceCases copies every orphaned row into
+// province_cases_quarantine and deletes it from province_cases in one transaction.
+// With dryRun the transaction is rolled back after computing the change summary.
+func (r *integrityRepository) QuarantineOrphanProvinceCases(ctx context.Context, dryRun bool) (*models.ChangeSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin quarantine transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+				log.Printf("Error rolling back transaction: %v", err)
+			}
+		}
+	}()
+
+	orphans, err := findOrphanProvinceCases(ctx, tx, r.db.Dialect().ForUpdateOf("pc"))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ChangeSummary{DryRun: dryRun}
+	now := time.Now().UTC()
+	for _, o := range orphans {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO province_cases_quarantine
+			SELECT pc.*, ?, ? FROM province_cases pc WHERE pc.id = ?`,
+			strings.Join(o.Reasons, ","), now, o.ID); err != nil {
+			return nil, fmt.Errorf("failed to quarantine province case %d: %w", o.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM province_cases WHERE id = ?`, o.ID); err != nil {
+			return nil, fmt.Errorf("failed to remove quarantined province case %d: %w", o.ID, err)
+		}
+		summary.Add(models.RowChange{
+			Table:  "province_cases",
+			Key:    strconv.FormatInt(o.ID, 10),
+			Action: models.ChangeActionDelete,
+			Before: map[string]interface{}{"day": o.Day, "province_id": o.ProvinceID, "reasons": o.Reasons},
+		})
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit quarantine: %w", err)
+	}
+	committed = true
+	return summary, nil
+}
+
+func findOrphanProvinceCases(ctx context.Context, exec sqlExecutor, lock string) ([]models.OrphanProvinceCase, error) {
+	rows, err := exec.QueryContext(ctx, orphanProvinceCasesQuery+lock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphan province cases: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var orphans []models.OrphanProvinceCase
+	for rows.Next() {
+		var o models.OrphanProvinceCase
+		var missingDay, missingProvince bool
+		if err := rows.Scan(&o.ID, &o.Day, &o.ProvinceID, &missingDay, &missingProvince); err != nil {
+			return nil, fmt.Errorf("failed to scan orphan province case: %w", err)
+		}
+		if missingDay {
+			o.Reasons = append(o.Reasons, models.OrphanReasonMissingDay)
+		}
+		if missingProvince {
+			o.Reasons = append(o.Reasons, models.OrphanReasonMissingProvince)
+		}
+		orphans = append(orphans, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return orphans, nil
+}
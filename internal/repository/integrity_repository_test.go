@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var orphanCols = []string{"id", "day", "province_id", "missing_day", "missing_province"}
+
+func TestIntegrityRepository_FindOrphanProvinceCases(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIntegrityRepository(db)
+
+	mock.ExpectQuery(`LEFT JOIN national_cases nc ON pc.day = nc.id`).
+		WillReturnRows(sqlmock.NewRows(orphanCols).
+			AddRow(1, 900, "72", true, false).
+			AddRow(2, 3, "99", false, true).
+			AddRow(3, 901, "98", true, true))
+
+	orphans, err := repo.FindOrphanProvinceCases(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 3)
+	assert.Equal(t, []string{models.OrphanReasonMissingDay}, orphans[0].Reasons)
+	assert.Equal(t, []string{models.OrphanReasonMissingProvince}, orphans[1].Reasons)
+	assert.Equal(t, []string{models.OrphanReasonMissingDay, models.OrphanReasonMissingProvince}, orphans[2].Reasons)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIntegrityRepository_QuarantineOrphanProvinceCases(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIntegrityRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE nc.id IS NULL OR p.id IS NULL\s+ORDER BY pc.id FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows(orphanCols).AddRow(7, 900, "72", true, false))
+	mock.ExpectExec(`INSERT INTO province_cases_quarantine`).
+		WithArgs(models.OrphanReasonMissingDay, sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM province_cases WHERE id = \?`).WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	summary, err := repo.QuarantineOrphanProvinceCases(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.Equal(t, "7", summary.Changes[0].Key)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIntegrityRepository_QuarantineOrphanProvinceCases_DryRun(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewIntegrityRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows(orphanCols).AddRow(7, 900, "72", true, false))
+	mock.ExpectExec(`INSERT INTO province_cases_quarantine`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM province_cases`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	summary, err := repo.QuarantineOrphanProvinceCases(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
@@ -12,16 +14,65 @@ import (
 )
 
 type NationalCaseRepository interface {
-	GetAll() ([]models.NationalCase, error)
-	GetAllSorted(sortParams utils.SortParams) ([]models.NationalCase, error)
-	GetAllPaginated(limit, offset int) ([]models.NationalCase, int, error)
-	GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error)
-	GetByDateRange(startDate, endDate time.Time) ([]models.NationalCase, error)
-	GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.NationalCase, error)
-	GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.NationalCase, int, error)
-	GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error)
-	GetLatest() (*models.NationalCase, error)
-	GetByDay(day int64) (*models.NationalCase, error)
+	// Find returns the cases matching q and their number across all pages
+	Find(ctx context.Context, q NationalCaseQuery) ([]models.NationalCase, int, error)
+	// Each calls fn with the cases matching q one row at a time, as they are
+	// read from the database, and stops at the first error fn returns. The
+	// page and AsOf of q are ignored.
+	Each(ctx context.Context, q NationalCaseQuery, fn func(models.NationalCase) error) error
+	GetLatest(ctx context.Context) (*models.NationalCase, error)
+	GetByDay(ctx context.Context, day int64) (*models.NationalCase, error)
+	// GetByDate returns the case of one date, or nil when there is none
+	GetByDate(ctx context.Context, date time.Time) (*models.NationalCase, error)
+	GetAggregated(ctx context.Context, q CaseAggregateQuery) ([]models.AggregatedCase, error)
+	// GetTotals sums the cases between start and end, or of all dates when
+	// either is zero. The Period of the result is left empty.
+	GetTotals(ctx context.Context, start, end time.Time) (models.PeriodTotals, error)
+}
+
+// NationalCaseQuery selects national cases
+type NationalCaseQuery struct {
+	// StartDate and EndDate bound the dates when both are set
+	StartDate time.Time
+	EndDate   time.Time
+	// AfterID only matches rows with a higher ID, i.e. rows added later
+	AfterID int64
+	Sort    utils.SortParams
+	// Limit and Offset select a page; all matching cases when Limit is 0
+	Limit  int
+	Offset int
+	// AsOf returns the cases as they were at that time, rebuilt from their
+	// revisions; the current cases when zero. Only date order is supported.
+	AsOf time.Time
+	// Columns names the columns to read, e.g. positive or rt; the others are
+	// left zero. The key columns - id, day, date and the timestamps - are
+	// always read, and every column when Columns is empty. Ignored with AsOf.
+	Columns []string
+}
+
+// nationalCaseColumns are the columns of a national case row, named as the
+// table's columns
+var nationalCaseColumns = []column[models.NationalCase]{
+	{name: "id", expr: "id", key: true, dest: func(c *models.NationalCase) interface{} { return &c.ID }},
+	{name: "day", expr: "day", key: true, dest: func(c *models.NationalCase) interface{} { return &c.Day }},
+	{name: "date", expr: "date", key: true, dest: func(c *models.NationalCase) interface{} { return &c.Date }},
+	{name: "positive", expr: "positive", dest: func(c *models.NationalCase) interface{} { return &c.Positive }},
+	{name: "recovered", expr: "recovered", dest: func(c *models.NationalCase) interface{} { return &c.Recovered }},
+	{name: "deceased", expr: "deceased", dest: func(c *models.NationalCase) interface{} { return &c.Deceased }},
+	{name: "cumulative_positive", expr: "cumulative_positive", dest: func(c *models.NationalCase) interface{} { return &c.CumulativePositive }},
+	{name: "cumulative_recovered", expr: "cumulative_recovered", dest: func(c *models.NationalCase) interface{} { return &c.CumulativeRecovered }},
+	{name: "cumulative_deceased", expr: "cumulative_deceased", dest: func(c *models.NationalCase) interface{} { return &c.CumulativeDeceased }},
+	{name: "rt", expr: "rt", dest: func(c *models.NationalCase) interface{} { return &c.Rt }},
+	{name: "rt_upper", expr: "rt_upper", dest: func(c *models.NationalCase) interface{} { return &c.RtUpper }},
+	{name: "rt_lower", expr: "rt_lower", dest: func(c *models.NationalCase) interface{} { return &c.RtLower }},
+	{name: "created_at", expr: "created_at", key: true, dest: func(c *models.NationalCase) interface{} { return &c.CreatedAt }},
+	{name: "updated_at", expr: "updated_at", key: true, dest: func(c *models.NationalCase) interface{} { return &c.UpdatedAt }},
+}
+
+// selectNationalCases starts a query of the given columns of the national
+// cases that are not soft-deleted
+func selectNationalCases(columns []column[models.NationalCase]) *selectBuilder {
+	return newSelect(selectList(columns), "national_cases").where("deleted_at IS NULL")
 }
 
 type nationalCaseRepository struct {
@@ -32,155 +83,102 @@ func NewNationalCaseRepository(db *database.DB) NationalCaseRepository {
 	return &nationalCaseRepository{db: db}
 }
 
-func (r *nationalCaseRepository) GetAll() ([]models.NationalCase, error) {
-	// Default sorting by date ascending
-	return r.GetAllSorted(utils.SortParams{Field: "date", Order: "asc"})
-}
-
-func (r *nationalCaseRepository) GetAllSorted(sortParams utils.SortParams) ([]models.NationalCase, error) {
-	query := `SELECT id, day, date, positive, recovered, deceased, 
-			  cumulative_positive, cumulative_recovered, cumulative_deceased,
-			  rt, rt_upper, rt_lower 
-			  FROM national_cases ORDER BY ` + sortParams.GetSQLOrderClause()
-
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query national cases: %w", err)
+func (r *nationalCaseRepository) Find(ctx context.Context, q NationalCaseQuery) ([]models.NationalCase, int, error) {
+	if !q.AsOf.IsZero() {
+		return r.findAsOf(ctx, q)
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
+	columns := pickColumns(nationalCaseColumns, q.Columns)
+	b := filterNationalCases(q, columns).page(q.Limit, q.Offset)
+
+	total := 0
+	if q.Limit > 0 {
+		countQuery, countArgs := b.buildCount()
+		if err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("failed to count national cases: %w", err)
 		}
-	}()
-
-	var cases []models.NationalCase
-	for rows.Next() {
-		var c models.NationalCase
-		err := rows.Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
-			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-			&c.Rt, &c.RtUpper, &c.RtLower)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan national case: %w", err)
-		}
-		cases = append(cases, c)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+	query, args := b.build()
+	cases, err := r.queryNationalCases(ctx, columns, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	return cases, nil
+	if q.Limit == 0 {
+		total = len(cases)
+	}
+	return cases, total, nil
 }
 
-func (r *nationalCaseRepository) GetByDateRange(startDate, endDate time.Time) ([]models.NationalCase, error) {
-	// Default sorting by date ascending
-	return r.GetByDateRangeSorted(startDate, endDate, utils.SortParams{Field: "date", Order: "asc"})
+func (r *nationalCaseRepository) Each(ctx context.Context, q NationalCaseQuery, fn func(models.NationalCase) error) error {
+	columns := pickColumns(nationalCaseColumns, q.Columns)
+	query, args := filterNationalCases(q, columns).build()
+	return r.eachNationalCase(ctx, columns, query, args, fn)
 }
 
-func (r *nationalCaseRepository) GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.NationalCase, error) {
-	query := `SELECT id, day, date, positive, recovered, deceased, 
-			  cumulative_positive, cumulative_recovered, cumulative_deceased,
-			  rt, rt_upper, rt_lower 
-			  FROM national_cases 
-			  WHERE date BETWEEN ? AND ? 
-			  ORDER BY ` + sortParams.GetSQLOrderClause()
-
-	rows, err := r.db.Query(query, startDate, endDate)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query national cases by date range: %w", err)
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
-
-	var cases []models.NationalCase
-	for rows.Next() {
-		var c models.NationalCase
-		err := rows.Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
-			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-			&c.Rt, &c.RtUpper, &c.RtLower)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan national case: %w", err)
-		}
-		cases = append(cases, c)
+// filterNationalCases selects the columns of the cases matching q in its
+// order, without the page
+func filterNationalCases(q NationalCaseQuery, columns []column[models.NationalCase]) *selectBuilder {
+	b := selectNationalCases(columns).orderBy(nationalOrderClause(q.Sort))
+	if !q.StartDate.IsZero() && !q.EndDate.IsZero() {
+		b.where("date BETWEEN ? AND ?", q.StartDate, q.EndDate)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+	if q.AfterID > 0 {
+		b.where("id > ?", q.AfterID)
 	}
-
-	return cases, nil
+	return b
 }
 
-func (r *nationalCaseRepository) GetLatest() (*models.NationalCase, error) {
-	query := `SELECT id, day, date, positive, recovered, deceased, 
-			  cumulative_positive, cumulative_recovered, cumulative_deceased,
-			  rt, rt_upper, rt_lower 
-			  FROM national_cases 
-			  ORDER BY date DESC LIMIT 1`
-
-	var c models.NationalCase
-	err := r.db.QueryRow(query).Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
-		&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-		&c.Rt, &c.RtUpper, &c.RtLower)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to get latest national case: %w", err)
-	}
-
-	return &c, nil
+func (r *nationalCaseRepository) GetLatest(ctx context.Context) (*models.NationalCase, error) {
+	query, args := selectNationalCases(nationalCaseColumns).
+		orderBy("date DESC, id DESC").
+		page(1, 0).
+		build()
+	return r.queryNationalCase(ctx, query, args...)
 }
 
-func (r *nationalCaseRepository) GetByDay(day int64) (*models.NationalCase, error) {
-	query := `SELECT id, day, date, positive, recovered, deceased,
-			  cumulative_positive, cumulative_recovered, cumulative_deceased,
-			  rt, rt_upper, rt_lower
-			  FROM national_cases
-			  WHERE day = ?`
-
-	var c models.NationalCase
-	err := r.db.QueryRow(query, day).Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
-		&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-		&c.Rt, &c.RtUpper, &c.RtLower)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to get national case by day: %w", err)
-	}
+func (r *nationalCaseRepository) GetByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
+	query, args := selectNationalCases(nationalCaseColumns).
+		where("day = ?", day).
+		build()
+	return r.queryNationalCase(ctx, query, args...)
+}
 
-	return &c, nil
+func (r *nationalCaseRepository) GetByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	query, args := selectNationalCases(nationalCaseColumns).
+		where("date = ?", date).
+		orderBy("id DESC").
+		page(1, 0).
+		build()
+	return r.queryNationalCase(ctx, query, args...)
 }
 
-func (r *nationalCaseRepository) GetAllPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	// Default sorting by date ascending
-	return r.GetAllPaginatedSorted(limit, offset, utils.SortParams{Field: "date", Order: "asc"})
+// queryNationalCase returns the first case of the query, or nil when there is none
+func (r *nationalCaseRepository) queryNationalCase(ctx context.Context, query string, args ...interface{}) (*models.NationalCase, error) {
+	cases, err := r.queryNationalCases(ctx, nationalCaseColumns, query, args...)
+	if err != nil || len(cases) == 0 {
+		return nil, err
+	}
+	return &cases[0], nil
 }
 
-func (r *nationalCaseRepository) GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	// Get total count
-	var total int
-	countQuery := `SELECT COUNT(*) FROM national_cases`
-	err := r.db.QueryRow(countQuery).Scan(&total)
+func (r *nationalCaseRepository) queryNationalCases(ctx context.Context, columns []column[models.NationalCase], query string, args ...interface{}) ([]models.NationalCase, error) {
+	var cases []models.NationalCase
+	err := r.eachNationalCase(ctx, columns, query, args, func(c models.NationalCase) error {
+		cases = append(cases, c)
+		return nil
+	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+		return nil, err
 	}
+	return cases, nil
+}
 
-	// Get paginated data
-	query := `SELECT id, day, date, positive, recovered, deceased,
-			  cumulative_positive, cumulative_recovered, cumulative_deceased,
-			  rt, rt_upper, rt_lower
-			  FROM national_cases
-			  ORDER BY ` + sortParams.GetSQLOrderClause() + `
-			  LIMIT ? OFFSET ?`
-
-	rows, err := r.db.Query(query, limit, offset)
+// eachNationalCase scans the columns of the cases of the query one row at a
+// time
+func (r *nationalCaseRepository) eachNationalCase(ctx context.Context, columns []column[models.NationalCase], query string, args []interface{}, fn func(models.NationalCase) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query national cases paginated: %w", err)
+		return fmt.Errorf("failed to query national cases: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -188,73 +186,95 @@ func (r *nationalCaseRepository) GetAllPaginatedSorted(limit, offset int, sortPa
 		}
 	}()
 
-	var cases []models.NationalCase
 	for rows.Next() {
 		var c models.NationalCase
-		err := rows.Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
-			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-			&c.Rt, &c.RtUpper, &c.RtLower)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan national case: %w", err)
+		if err := rows.Scan(scanDest(columns, &c)...); err != nil {
+			return fmt.Errorf("failed to scan national case: %w", err)
+		}
+		if err := fn(c); err != nil {
+			return err
 		}
-		cases = append(cases, c)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+		return fmt.Errorf("row iteration error: %w", err)
 	}
-
-	return cases, total, nil
+	return nil
 }
 
-func (r *nationalCaseRepository) GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.NationalCase, int, error) {
-	// Default sorting by date ascending
-	return r.GetByDateRangePaginatedSorted(startDate, endDate, limit, offset, utils.SortParams{Field: "date", Order: "asc"})
-}
-
-func (r *nationalCaseRepository) GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	// Get total count for date range
-	var total int
-	countQuery := `SELECT COUNT(*) FROM national_cases WHERE date BETWEEN ? AND ?`
-	err := r.db.QueryRow(countQuery, startDate, endDate).Scan(&total)
+// GetAggregated totals the national cases per ISO week or calendar month:
+// daily counts are summed, cumulative counts take their highest value and Rt
+// is averaged over the days with an estimate
+func (r *nationalCaseRepository) GetAggregated(ctx context.Context, q CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	period, err := periodExpression(r.db.Dialect(), q.Interval, "date")
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get total count for date range: %w", err)
+		return nil, err
+	}
+	where := "WHERE deleted_at IS NULL"
+	var args []interface{}
+	if q.hasDateRange() {
+		where += " AND date BETWEEN ? AND ?"
+		args = append(args, q.StartDate, q.EndDate)
 	}
 
-	// Get paginated data for date range
-	query := `SELECT id, day, date, positive, recovered, deceased,
-			  cumulative_positive, cumulative_recovered, cumulative_deceased,
-			  rt, rt_upper, rt_lower
+	query := `SELECT ` + period + ` AS period, MIN(date), MAX(date), COUNT(*),
+			  SUM(positive), SUM(recovered), SUM(deceased),
+			  MAX(cumulative_positive), MAX(cumulative_recovered), MAX(cumulative_deceased),
+			  AVG(rt)
 			  FROM national_cases
-			  WHERE date BETWEEN ? AND ?
-			  ORDER BY ` + sortParams.GetSQLOrderClause() + `
-			  LIMIT ? OFFSET ?`
+			  ` + where + `
+			  GROUP BY period
+			  ORDER BY MIN(date) ` + q.order()
 
-	rows, err := r.db.Query(query, startDate, endDate, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query national cases by date range paginated: %w", err)
+		return nil, fmt.Errorf("failed to query aggregated national cases: %w", err)
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
+	return scanAggregatedCases(rows, false)
+}
 
-	var cases []models.NationalCase
-	for rows.Next() {
-		var c models.NationalCase
-		err := rows.Scan(&c.ID, &c.Day, &c.Date, &c.Positive, &c.Recovered, &c.Deceased,
-			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-			&c.Rt, &c.RtUpper, &c.RtLower)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan national case: %w", err)
-		}
-		cases = append(cases, c)
+// GetTotals sums, bounds and averages the daily counts in SQL, so the rows of
+// long ranges are never loaded
+func (r *nationalCaseRepository) GetTotals(ctx context.Context, start, end time.Time) (models.PeriodTotals, error) {
+	q := CaseAggregateQuery{StartDate: start, EndDate: end}
+	where := "WHERE deleted_at IS NULL"
+	var args []interface{}
+	if q.hasDateRange() {
+		where += " AND date BETWEEN ? AND ?"
+		args = append(args, start, end)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	query := `SELECT COUNT(*), COALESCE(SUM(positive), 0), COALESCE(SUM(recovered), 0), COALESCE(SUM(deceased), 0),
+			  COALESCE(MIN(positive), 0), COALESCE(MAX(positive), 0), AVG(rt)
+			  FROM national_cases
+			  ` + where
+
+	var totals models.PeriodTotals
+	var averageRt sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&totals.Days,
+		&totals.Positive, &totals.Recovered, &totals.Deceased,
+		&totals.MinDailyPositive, &totals.MaxDailyPositive, &averageRt)
+	if err != nil {
+		return models.PeriodTotals{}, fmt.Errorf("failed to query national case totals: %w", err)
 	}
+	if totals.Days > 0 {
+		totals.AverageDailyPositive = float64(totals.Positive) / float64(totals.Days)
+	}
+	if averageRt.Valid {
+		totals.AverageRt = &averageRt.Float64
+	}
+	return totals, nil
+}
 
-	return cases, total, nil
+// nationalOrderClause is the ORDER BY of sorted national case queries. Ties on
+// the sort field are broken by date and then id in the same direction, so
+// pages of a list sorted by a non-unique field such as positive never overlap
+// or skip rows between requests.
+func nationalOrderClause(sortParams utils.SortParams) string {
+	clause := sortParams.GetSQLOrderClause()
+	order := clause[strings.LastIndex(clause, " ")+1:]
+	if !strings.HasPrefix(clause, "date ") {
+		clause += ", date " + order
+	}
+	return clause + ", id " + order
 }
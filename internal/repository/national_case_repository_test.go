@@ -1,11 +1,13 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/pkg/database"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -19,7 +21,15 @@ func setupMockDB(t *testing.T) (*database.DB, sqlmock.Sqlmock) {
 	return &database.DB{DB: db}, mock
 }
 
-func TestNationalCaseRepository_GetAll(t *testing.T) {
+// setupPostgresMockDB is setupMockDB for the PostgreSQL dialect
+func setupPostgresMockDB(t *testing.T) (*database.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	return database.New(db, database.DialectPostgres), mock
+}
+
+func TestNationalCaseRepository_Find(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -37,24 +47,47 @@ func TestNationalCaseRepository_GetAll(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "day", "date", "positive", "recovered", "deceased",
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"rt", "rt_upper", "rt_lower",
-	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, rt, rtUpper, rtLower)
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, rt, rtUpper, rtLower, now, now)
 
 	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
 		WillReturnRows(rows)
 
-	cases, err := repo.GetAll()
+	cases, total, err := repo.Find(context.Background(), NationalCaseQuery{})
 
 	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
 	assert.Len(t, cases, 1)
 	assert.Equal(t, int64(1), cases[0].ID)
 	assert.Equal(t, int64(100), cases[0].Positive)
 	assert.Equal(t, &rt, cases[0].Rt)
+	assert.Equal(t, &now, cases[0].CreatedAt)
+	assert.Equal(t, &now, cases[0].UpdatedAt)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestNationalCaseRepository_GetByDateRange(t *testing.T) {
+func TestNationalCaseRepository_Find_ContextCancelled(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	mock.ExpectQuery(`SELECT id, day, date`).WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases, _, err := NewNationalCaseRepository(db).Find(ctx, NationalCaseQuery{})
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, cases)
+}
+
+func TestNationalCaseRepository_Find_DateRange(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -71,14 +104,16 @@ func TestNationalCaseRepository_GetByDateRange(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "day", "date", "positive", "recovered", "deceased",
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"rt", "rt_upper", "rt_lower",
-	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, nil, nil, nil)
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, nil, nil, nil, nil, nil)
 
-	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
+	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,.* FROM national_cases WHERE deleted_at IS NULL AND date BETWEEN \? AND \? ORDER BY date ASC, id ASC$`).
 		WithArgs(startDate, endDate).
 		WillReturnRows(rows)
 
-	cases, err := repo.GetByDateRange(startDate, endDate)
+	cases, _, err := repo.Find(context.Background(), NationalCaseQuery{
+		StartDate: startDate, EndDate: endDate, Sort: utils.SortParams{Field: "date", Order: "asc"},
+	})
 
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
@@ -88,6 +123,38 @@ func TestNationalCaseRepository_GetByDateRange(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// Only the key columns and the requested ones are read
+func TestNationalCaseRepository_Find_Columns(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := NewNationalCaseRepository(db)
+	now := time.Now()
+	rt := 1.2
+
+	rows := sqlmock.NewRows([]string{"id", "day", "date", "positive", "rt", "created_at", "updated_at"}).
+		AddRow(1, 1, now, 100, rt, now, now)
+	mock.ExpectQuery(`^SELECT id, day, date, positive, rt, created_at, updated_at FROM national_cases WHERE deleted_at IS NULL ORDER BY date ASC, id ASC$`).
+		WillReturnRows(rows)
+
+	cases, total, err := repo.Find(context.Background(), NationalCaseQuery{
+		Sort:    utils.SortParams{Field: "date", Order: "asc"},
+		Columns: []string{"rt", "positive", "unknown"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, cases, 1)
+	assert.Equal(t, int64(100), cases[0].Positive)
+	assert.Equal(t, &rt, cases[0].Rt)
+	assert.Zero(t, cases[0].CumulativePositive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestNationalCaseRepository_GetLatest(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
@@ -104,13 +171,14 @@ func TestNationalCaseRepository_GetLatest(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "day", "date", "positive", "recovered", "deceased",
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"rt", "rt_upper", "rt_lower",
-	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, rt, nil, nil)
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, rt, nil, nil, nil, nil)
 
-	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
+	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,.* ORDER BY date DESC, id DESC LIMIT \? OFFSET \?`).
+		WithArgs(1, 0).
 		WillReturnRows(rows)
 
-	nationalCase, err := repo.GetLatest()
+	nationalCase, err := repo.GetLatest(context.Background())
 
 	assert.NoError(t, err)
 	assert.NotNil(t, nationalCase)
@@ -132,9 +200,9 @@ func TestNationalCaseRepository_GetLatest_NotFound(t *testing.T) {
 	repo := NewNationalCaseRepository(db)
 
 	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
-		WillReturnError(sql.ErrNoRows)
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-	nationalCase, err := repo.GetLatest()
+	nationalCase, err := repo.GetLatest(context.Background())
 
 	assert.NoError(t, err)
 	assert.Nil(t, nationalCase)
@@ -158,14 +226,14 @@ func TestNationalCaseRepository_GetByDay(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "day", "date", "positive", "recovered", "deceased",
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"rt", "rt_upper", "rt_lower",
-	}).AddRow(1, day, now, 100, 80, 5, 1000, 800, 50, nil, nil, nil)
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(1, day, now, 100, 80, 5, 1000, 800, 50, nil, nil, nil, nil, nil)
 
-	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
+	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,.* WHERE deleted_at IS NULL AND day = \?$`).
 		WithArgs(day).
 		WillReturnRows(rows)
 
-	nationalCase, err := repo.GetByDay(day)
+	nationalCase, err := repo.GetByDay(context.Background(), day)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, nationalCase)
@@ -188,9 +256,48 @@ func TestNationalCaseRepository_GetByDay_NotFound(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
 		WithArgs(day).
-		WillReturnError(sql.ErrNoRows)
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	nationalCase, err := repo.GetByDay(context.Background(), day)
 
-	nationalCase, err := repo.GetByDay(day)
+	assert.NoError(t, err)
+	assert.Nil(t, nationalCase)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalCaseRepository_GetByDate(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := NewNationalCaseRepository(db)
+
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "day", "date", "positive", "recovered", "deceased",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(500, 500, date, 100, 80, 5, 1000, 800, 50, nil, nil, nil, nil, nil)
+
+	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,.* WHERE deleted_at IS NULL AND date = \? ORDER BY id DESC LIMIT \? OFFSET \?$`).
+		WithArgs(date, 1, 0).
+		WillReturnRows(rows)
+
+	nationalCase, err := repo.GetByDate(context.Background(), date)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, nationalCase)
+	assert.Equal(t, date, nationalCase.Date)
+
+	mock.ExpectQuery(`SELECT id, day, date, positive, recovered, deceased,`).
+		WithArgs(date.AddDate(0, 0, 1), 1, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	nationalCase, err = repo.GetByDate(context.Background(), date.AddDate(0, 0, 1))
 
 	assert.NoError(t, err)
 	assert.Nil(t, nationalCase)
@@ -206,69 +313,251 @@ func nationalCaseRows() *sqlmock.Rows {
 	return sqlmock.NewRows([]string{
 		"id", "day", "date", "positive", "recovered", "deceased",
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"rt", "rt_upper", "rt_lower",
-	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, rt, rtUpper, rtLower)
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(1, 1, now, 100, 80, 5, 1000, 800, 50, rt, rtUpper, rtLower, nil, nil)
 }
 
-func TestNationalCaseRepository_GetAllPaginated(t *testing.T) {
+func TestNationalCaseRepository_Find_Paginated(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() { _ = db.Close() }()
 	repo := NewNationalCaseRepository(db)
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-	mock.ExpectQuery(`SELECT id, day`).WithArgs(10, 0).WillReturnRows(nationalCaseRows())
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM national_cases WHERE deleted_at IS NULL$`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT id, day.* ORDER BY positive DESC, date DESC, id DESC LIMIT \? OFFSET \?`).WithArgs(10, 0).WillReturnRows(nationalCaseRows())
 
-	result, total, err := repo.GetAllPaginated(10, 0)
+	result, total, err := repo.Find(context.Background(), NationalCaseQuery{
+		Sort: utils.SortParams{Field: "positive", Order: "desc"}, Limit: 10,
+	})
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
 	assert.Len(t, result, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestNationalCaseRepository_GetAllPaginatedSorted(t *testing.T) {
+func TestNationalCaseRepository_Find_DateRangePaginated(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() { _ = db.Close() }()
 	repo := NewNationalCaseRepository(db)
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-	mock.ExpectQuery(`SELECT id, day`).WithArgs(10, 0).WillReturnRows(nationalCaseRows())
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM national_cases WHERE deleted_at IS NULL AND date BETWEEN \? AND \?`).WithArgs(start, end).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(`SELECT id, day`).WithArgs(start, end, 10, 20).WillReturnRows(nationalCaseRows())
 
-	result, total, err := repo.GetAllPaginatedSorted(10, 0, utils.SortParams{Field: "date", Order: "asc"})
+	result, total, err := repo.Find(context.Background(), NationalCaseQuery{
+		StartDate: start, EndDate: end, Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 10, Offset: 20,
+	})
 	assert.NoError(t, err)
-	assert.Equal(t, 1, total)
+	assert.Equal(t, 25, total)
 	assert.Len(t, result, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestNationalCaseRepository_GetByDateRangePaginated(t *testing.T) {
+func TestNationalCaseRepository_Each(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() { _ = db.Close() }()
 	repo := NewNationalCaseRepository(db)
 
 	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-
-	mock.ExpectQuery(`SELECT COUNT\(\*\)`).WithArgs(start, end).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-	mock.ExpectQuery(`SELECT id, day`).WithArgs(start, end, 10, 0).WillReturnRows(nationalCaseRows())
-
-	result, total, err := repo.GetByDateRangePaginated(start, end, 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, result, 1)
+	rows := nationalCaseRows().
+		AddRow(2, 2, start, 7, 1, 0, 1007, 801, 50, nil, nil, nil, nil, nil).
+		AddRow(3, 3, start, 9, 2, 1, 1016, 803, 51, nil, nil, nil, nil, nil)
+
+	// Neither a count nor a page: every matching row is read
+	mock.ExpectQuery(`SELECT id, day.* AND date BETWEEN \? AND \? ORDER BY date ASC, id ASC$`).WithArgs(start, end).WillReturnRows(rows)
+
+	var days []int64
+	err := repo.Each(context.Background(), NationalCaseQuery{
+		StartDate: start, EndDate: end, Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 10,
+	}, func(c models.NationalCase) error {
+		days = append(days, c.Day)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, days)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestNationalCaseRepository_GetByDateRangePaginatedSorted(t *testing.T) {
+func TestNationalCaseRepository_Each_StopsOnError(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() { _ = db.Close() }()
 	repo := NewNationalCaseRepository(db)
 
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	rows := nationalCaseRows().AddRow(2, 2, time.Now(), 7, 1, 0, 1007, 801, 50, nil, nil, nil, nil, nil)
+	mock.ExpectQuery(`SELECT id, day`).WillReturnRows(rows)
+
+	stop := errors.New("client gone")
+	calls := 0
+	err := repo.Each(context.Background(), NationalCaseQuery{}, func(models.NationalCase) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\)`).WithArgs(start, end).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-	mock.ExpectQuery(`SELECT id, day`).WithArgs(start, end, 10, 0).WillReturnRows(nationalCaseRows())
+func TestNationalCaseRepository_GetAggregated_Weekly(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	start := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"period", "min", "max", "count", "positive", "recovered", "deceased",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "rt"}).
+		AddRow("2021-W05", start, start.AddDate(0, 0, 6), 7, 700, 500, 20, 7000, 5000, 200, 1.1).
+		AddRow("2021-W06", start.AddDate(0, 0, 7), end, 7, 600, 550, 15, 7600, 5550, 215, nil)
+
+	mock.ExpectQuery(`SELECT DATE_FORMAT\(date, '%x-W%v'\) AS period, MIN\(date\).*WHERE deleted_at IS NULL AND date BETWEEN \? AND \?\s+GROUP BY period\s+ORDER BY MIN\(date\) ASC`).
+		WithArgs(start, end).
+		WillReturnRows(rows)
+
+	cases, err := NewNationalCaseRepository(db).GetAggregated(context.Background(), CaseAggregateQuery{
+		Interval: "weekly", StartDate: start, EndDate: end,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+	assert.Equal(t, "2021-W05", cases[0].Period)
+	assert.Equal(t, 7, cases[0].Days)
+	assert.Equal(t, int64(700), cases[0].Positive)
+	assert.Equal(t, int64(7000), cases[0].CumulativePositive)
+	require.NotNil(t, cases[0].AverageRt)
+	assert.Equal(t, 1.1, *cases[0].AverageRt)
+	assert.Nil(t, cases[1].AverageRt)
+	assert.Nil(t, cases[1].Province)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalCaseRepository_GetAggregated_Postgres(t *testing.T) {
+	db, mock := setupPostgresMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	start := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"period", "min", "max", "count", "positive", "recovered", "deceased",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "rt"}).
+		AddRow("2021-02", start, end, 28, 2400, 2100, 60, 9000, 7000, 260, "1.05")
+
+	mock.ExpectQuery(`SELECT to_char\(date, 'YYYY-MM'\) AS period, MIN\(date\).*WHERE deleted_at IS NULL AND date BETWEEN \$1 AND \$2\s+GROUP BY period`).
+		WithArgs(start, end).
+		WillReturnRows(rows)
+
+	cases, err := NewNationalCaseRepository(db).GetAggregated(context.Background(), CaseAggregateQuery{
+		Interval: "monthly", StartDate: start, EndDate: end,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.Equal(t, "2021-02", cases[0].Period)
+	require.NotNil(t, cases[0].AverageRt)
+	assert.Equal(t, 1.05, *cases[0].AverageRt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalCaseRepository_GetAggregated_UnsupportedInterval(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	_, err := NewNationalCaseRepository(db).GetAggregated(context.Background(), CaseAggregateQuery{Interval: "daily"})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalCaseRepository_GetTotals(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT COUNT\(\*\), COALESCE\(SUM\(positive\), 0\).*MIN\(positive\).*MAX\(positive\).*AVG\(rt\)\s+FROM national_cases\s+WHERE deleted_at IS NULL AND date BETWEEN \? AND \?`).
+		WithArgs(start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"days", "positive", "recovered", "deceased", "min", "max", "rt"}).
+			AddRow(30, 3000, 2000, 60, 20, 250, 1.05))
+
+	totals, err := NewNationalCaseRepository(db).GetTotals(context.Background(), start, end)
+
+	require.NoError(t, err)
+	assert.Equal(t, 30, totals.Days)
+	assert.Equal(t, int64(3000), totals.Positive)
+	assert.Equal(t, int64(20), totals.MinDailyPositive)
+	assert.Equal(t, int64(250), totals.MaxDailyPositive)
+	assert.InDelta(t, 100.0, totals.AverageDailyPositive, 1e-9)
+	require.NotNil(t, totals.AverageRt)
+	assert.Equal(t, 1.05, *totals.AverageRt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalCaseRepository_GetTotals_NoRows(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	mock.ExpectQuery(`FROM national_cases\s+WHERE deleted_at IS NULL$`).
+		WillReturnRows(sqlmock.NewRows([]string{"days", "positive", "recovered", "deceased", "min", "max", "rt"}).
+			AddRow(0, 0, 0, 0, 0, 0, nil))
+
+	totals, err := NewNationalCaseRepository(db).GetTotals(context.Background(), time.Time{}, time.Time{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, totals.Days)
+	assert.Zero(t, totals.AverageDailyPositive)
+	assert.Nil(t, totals.AverageRt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalOrderClause_TieBreakers(t *testing.T) {
+	assert.Equal(t, "positive DESC, date DESC, id DESC", nationalOrderClause(utils.SortParams{Field: "positive", Order: "desc"}))
+	assert.Equal(t, "date ASC, id ASC", nationalOrderClause(utils.SortParams{Field: "date", Order: "asc"}))
+	assert.Equal(t, "date ASC, id ASC", nationalOrderClause(utils.SortParams{Field: "unknown", Order: "asc"}))
+	assert.Equal(t, "rt IS NULL, rt ASC, date ASC, id ASC", nationalOrderClause(utils.SortParams{Field: "rt", Order: "asc"}))
+}
+
+func TestNationalCaseRepository_Find_AfterID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "day", "date", "positive", "recovered", "deceased",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}).AddRow(11, 11, time.Now(), 100, 80, 5, 1000, 800, 50, nil, nil, nil, nil, nil)
+
+	mock.ExpectQuery(`FROM national_cases WHERE deleted_at IS NULL AND id > \? ORDER BY date ASC, id ASC$`).
+		WithArgs(int64(10)).
+		WillReturnRows(rows)
+
+	cases, total, err := NewNationalCaseRepository(db).Find(context.Background(), NationalCaseQuery{
+		AfterID: 10, Sort: utils.SortParams{Field: "date", Order: "asc"},
+	})
 
-	result, total, err := repo.GetByDateRangePaginatedSorted(start, end, 10, 0, utils.SortParams{Field: "date", Order: "asc"})
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
-	assert.Len(t, result, 1)
+	assert.Equal(t, int64(11), cases[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
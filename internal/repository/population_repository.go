@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// PopulationRepository reads the province populations of each source
+type PopulationRepository interface {
+	// GetBySource returns the population of every province according to source
+	GetBySource(ctx context.Context, source string) ([]models.ProvincePopulation, error)
+	// GetByProvinceID returns the population of a province according to
+	// source, or nil when the source has none
+	GetByProvinceID(ctx context.Context, provinceID, source string) (*models.ProvincePopulation, error)
+}
+
+type populationRepository struct {
+	db *database.DB
+}
+
+func NewPopulationRepository(db *database.DB) PopulationRepository {
+	return &populationRepository{db: db}
+}
+
+func (r *populationRepository) GetBySource(ctx context.Context, source string) ([]models.ProvincePopulation, error) {
+	query := `SELECT province_id, source, year, population
+			  FROM province_populations
+			  WHERE source = ?
+			  ORDER BY province_id`
+
+	rows, err := r.db.QueryContext(ctx, query, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query province populations: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	populations := []models.ProvincePopulation{}
+	for rows.Next() {
+		var p models.ProvincePopulation
+		if err := rows.Scan(&p.ProvinceID, &p.Source, &p.Year, &p.Population); err != nil {
+			return nil, fmt.Errorf("failed to scan province population: %w", err)
+		}
+		populations = append(populations, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return populations, nil
+}
+
+func (r *populationRepository) GetByProvinceID(ctx context.Context, provinceID, source string) (*models.ProvincePopulation, error) {
+	query := `SELECT province_id, source, year, population
+			  FROM province_populations
+			  WHERE province_id = ? AND source = ?`
+
+	var p models.ProvincePopulation
+	err := r.db.QueryRowContext(ctx, query, provinceID, source).Scan(&p.ProvinceID, &p.Source, &p.Year, &p.Population)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get province population: %w", err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var populationColumns = []string{"province_id", "source", "year", "population"}
+
+func TestPopulationRepository_GetBySource(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM province_populations\s+WHERE source = \?`).
+		WithArgs("bps-2020").
+		WillReturnRows(sqlmock.NewRows(populationColumns).
+			AddRow("71", "bps-2020", 2020, 2621923).
+			AddRow("72", "bps-2020", 2020, 2985734))
+
+	populations, err := NewPopulationRepository(db).GetBySource(context.Background(), "bps-2020")
+
+	require.NoError(t, err)
+	require.Len(t, populations, 2)
+	assert.Equal(t, "72", populations[1].ProvinceID)
+	assert.Equal(t, int64(2985734), populations[1].Population)
+	assert.Equal(t, 2020, populations[1].Year)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPopulationRepository_GetByProvinceID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`WHERE province_id = \? AND source = \?`).
+		WithArgs("72", "bps-2020").
+		WillReturnRows(sqlmock.NewRows(populationColumns).AddRow("72", "bps-2020", 2020, 2985734))
+	mock.ExpectQuery(`WHERE province_id = \? AND source = \?`).
+		WithArgs("99", "bps-2020").
+		WillReturnRows(sqlmock.NewRows(populationColumns))
+
+	repo := NewPopulationRepository(db)
+	population, err := repo.GetByProvinceID(context.Background(), "72", "bps-2020")
+	require.NoError(t, err)
+	require.NotNil(t, population)
+	assert.Equal(t, "bps-2020", population.Source)
+
+	population, err = repo.GetByProvinceID(context.Background(), "99", "bps-2020")
+	require.NoError(t, err)
+	assert.Nil(t, population)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
@@ -11,278 +13,313 @@ import (
 )
 
 type ProvinceCaseRepository interface {
-	GetAll() ([]models.ProvinceCaseWithDate, error)
-	GetAllSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetAllPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetByProvinceID(provinceID string) ([]models.ProvinceCaseWithDate, error)
-	GetByProvinceIDSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetByProvinceIDPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetByProvinceIDPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetByProvinceIDAndDateRange(provinceID string, startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error)
-	GetByProvinceIDAndDateRangeSorted(provinceID string, startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetByProvinceIDAndDateRangePaginated(provinceID string, startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetByProvinceIDAndDateRangePaginatedSorted(provinceID string, startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetByDateRange(startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error)
-	GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetLatestByProvinceID(provinceID string) (*models.ProvinceCaseWithDate, error)
+	// Find returns the cases matching q and their number across all pages
+	Find(ctx context.Context, q ProvinceCaseQuery) ([]models.ProvinceCaseWithDate, int, error)
+	// Each calls fn with the cases matching q one row at a time, as they are
+	// read from the database, and stops at the first error fn returns. The
+	// page and AsOf of q are ignored.
+	Each(ctx context.Context, q ProvinceCaseQuery, fn func(models.ProvinceCaseWithDate) error) error
+	GetLatestByProvinceID(ctx context.Context, provinceID string) (*models.ProvinceCaseWithDate, error)
+	// GetByProvinceIDAndDate returns the case of a province on one date, or
+	// nil when there is none
+	GetByProvinceIDAndDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error)
+	GetPageAfterCursor(ctx context.Context, q ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error)
+	GetAggregated(ctx context.Context, q CaseAggregateQuery) ([]models.AggregatedCase, error)
 }
 
-type provinceCaseRepository struct {
-	db *database.DB
+// ProvinceCaseCursorQuery selects a page of province cases in date order for
+// keyset pagination
+type ProvinceCaseCursorQuery struct {
+	// ProvinceID limits the page to one province; all provinces when empty
+	ProvinceID string
+	// StartDate and EndDate bound the dates when both are set
+	StartDate time.Time
+	EndDate   time.Time
+	// After is the cursor of the last row of the previous page; nil for the first page
+	After *models.CaseCursor
+	Desc  bool
+	Limit int
+	// HasRt skips the rows without an Rt value
+	HasRt bool
+	// Columns names the columns to read as in ProvinceCaseQuery
+	Columns []string
 }
 
-func NewProvinceCaseRepository(db *database.DB) ProvinceCaseRepository {
-	return &provinceCaseRepository{db: db}
+// ProvinceCaseQuery selects province cases
+type ProvinceCaseQuery struct {
+	// ProvinceID limits the cases to one province; all provinces when empty
+	ProvinceID string
+	// StartDate and EndDate bound the dates when both are set
+	StartDate time.Time
+	EndDate   time.Time
+	// HasRt skips the rows without an Rt value
+	HasRt bool
+	// AfterID only matches rows with a higher ID, i.e. rows added later
+	AfterID int64
+	Sort    utils.SortParams
+	// Limit and Offset select a page; all matching cases when Limit is 0
+	Limit  int
+	Offset int
+	// AsOf returns the cases as they were at that time, rebuilt from their
+	// revisions; the current cases when zero. Only date order is supported.
+	AsOf time.Time
+	// Columns names the columns to read, e.g. positive or rt; the others are
+	// left zero. The key columns - id, day, province_id, date, province and
+	// the timestamps - are always read, and every column when Columns is
+	// empty. Ignored with AsOf.
+	Columns []string
 }
 
-func (r *provinceCaseRepository) GetAll() ([]models.ProvinceCaseWithDate, error) {
-	// Default sorting by date ascending
-	return r.GetAllSorted(utils.SortParams{Field: "date", Order: "asc"})
+// provinceCaseRow is the row a province case query scans
+type provinceCaseRow = models.ProvinceCaseWithDate
+
+// provinceCaseColumns are the columns of a province case row from
+// provinceCaseFrom, named as the columns of province_cases. province is the
+// name of the province.
+var provinceCaseColumns = []column[provinceCaseRow]{
+	{name: "id", expr: "pc.id", key: true, dest: func(c *provinceCaseRow) interface{} { return &c.ID }},
+	{name: "day", expr: "pc.day", key: true, dest: func(c *provinceCaseRow) interface{} { return &c.Day }},
+	{name: "province_id", expr: "pc.province_id", key: true, dest: func(c *provinceCaseRow) interface{} { return &c.ProvinceID }},
+	{name: "positive", expr: "pc.positive", dest: func(c *provinceCaseRow) interface{} { return &c.Positive }},
+	{name: "recovered", expr: "pc.recovered", dest: func(c *provinceCaseRow) interface{} { return &c.Recovered }},
+	{name: "deceased", expr: "pc.deceased", dest: func(c *provinceCaseRow) interface{} { return &c.Deceased }},
+	// The ODP/PDP columns are nullable; NULL is read as 0
+	{name: "person_under_observation", expr: "pc.person_under_observation", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.PersonUnderObservation} }},
+	{name: "finished_person_under_observation", expr: "pc.finished_person_under_observation", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.FinishedPersonUnderObservation} }},
+	{name: "person_under_supervision", expr: "pc.person_under_supervision", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.PersonUnderSupervision} }},
+	{name: "finished_person_under_supervision", expr: "pc.finished_person_under_supervision", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.FinishedPersonUnderSupervision} }},
+	{name: "cumulative_positive", expr: "pc.cumulative_positive", dest: func(c *provinceCaseRow) interface{} { return &c.CumulativePositive }},
+	{name: "cumulative_recovered", expr: "pc.cumulative_recovered", dest: func(c *provinceCaseRow) interface{} { return &c.CumulativeRecovered }},
+	{name: "cumulative_deceased", expr: "pc.cumulative_deceased", dest: func(c *provinceCaseRow) interface{} { return &c.CumulativeDeceased }},
+	{name: "cumulative_person_under_observation", expr: "pc.cumulative_person_under_observation", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.CumulativePersonUnderObservation} }},
+	{name: "cumulative_finished_person_under_observation", expr: "pc.cumulative_finished_person_under_observation", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.CumulativeFinishedPersonUnderObservation} }},
+	{name: "cumulative_person_under_supervision", expr: "pc.cumulative_person_under_supervision", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.CumulativePersonUnderSupervision} }},
+	{name: "cumulative_finished_person_under_supervision", expr: "pc.cumulative_finished_person_under_supervision", dest: func(c *provinceCaseRow) interface{} { return nullAsZero{&c.CumulativeFinishedPersonUnderSupervision} }},
+	{name: "rt", expr: "pc.rt", dest: func(c *provinceCaseRow) interface{} { return &c.Rt }},
+	{name: "rt_upper", expr: "pc.rt_upper", dest: func(c *provinceCaseRow) interface{} { return &c.RtUpper }},
+	{name: "rt_lower", expr: "pc.rt_lower", dest: func(c *provinceCaseRow) interface{} { return &c.RtLower }},
+	// Rows with neither a national_cases match nor a stored date keep a zero
+	// date rather than being dropped
+	{name: "date", expr: "COALESCE(nc.date, pc.date)", key: true, dest: func(c *provinceCaseRow) interface{} { return nullAsZeroTime{&c.Date} }},
+	{name: "province", expr: "p.name", key: true, dest: func(c *provinceCaseRow) interface{} { return provinceNameDest{c} }},
+	{name: "created_at", expr: "pc.created_at", key: true, dest: func(c *provinceCaseRow) interface{} { return &c.CreatedAt }},
+	{name: "updated_at", expr: "pc.updated_at", key: true, dest: func(c *provinceCaseRow) interface{} { return &c.UpdatedAt }},
 }
 
-func (r *provinceCaseRepository) GetAllSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  ORDER BY ` + r.buildOrderClause(sortParams)
+const (
+	// pc.date equals COALESCE(nc.date, pc.date) (migration 0024), so filters
+	// and sorts use the column and its indexes
+	provinceCaseFrom = `province_cases pc
+			  LEFT JOIN national_cases nc ON pc.day = nc.id
+			  LEFT JOIN provinces p ON pc.province_id = p.id`
+)
 
-	return r.queryProvinceCases(query)
+// nullAsZero scans a nullable integer column, NULL as 0
+type nullAsZero struct {
+	n *int64
 }
 
-func (r *provinceCaseRepository) GetAllPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	// Default sorting by date ascending
-	return r.GetAllPaginatedSorted(limit, offset, utils.SortParams{Field: "date", Order: "asc"})
+func (z nullAsZero) Scan(value interface{}) error {
+	var v sql.NullInt64
+	if err := v.Scan(value); err != nil {
+		return err
+	}
+	*z.n = v.Int64
+	return nil
 }
 
-func (r *provinceCaseRepository) GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	// First get total count
-	countQuery := `SELECT COUNT(*) FROM province_cases pc
-				   JOIN national_cases nc ON pc.day = nc.id`
+// nullAsZeroTime scans a nullable time column, NULL as the zero time
+type nullAsZeroTime struct {
+	t *time.Time
+}
 
-	var total int
-	err := r.db.QueryRow(countQuery).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count province cases: %w", err)
+func (z nullAsZeroTime) Scan(value interface{}) error {
+	var v sql.NullTime
+	if err := v.Scan(value); err != nil {
+		return err
 	}
+	*z.t = v.Time
+	return nil
+}
 
-	// Get paginated data
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  ORDER BY ` + r.buildOrderClause(sortParams) + `
-			  LIMIT ? OFFSET ?`
+// provinceNameDest scans the name of the province of a case, leaving Province
+// nil when it is NULL. eachProvinceCase sets the province ID.
+type provinceNameDest struct {
+	c *models.ProvinceCaseWithDate
+}
 
-	cases, err := r.queryProvinceCases(query, limit, offset)
-	if err != nil {
-		return nil, 0, err
+func (p provinceNameDest) Scan(value interface{}) error {
+	var name sql.NullString
+	if err := name.Scan(value); err != nil {
+		return err
 	}
-
-	return cases, total, nil
+	if name.Valid {
+		p.c.Province = &models.Province{Name: name.String}
+	}
+	return nil
 }
 
-func (r *provinceCaseRepository) GetByProvinceID(provinceID string) ([]models.ProvinceCaseWithDate, error) {
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE pc.province_id = ?
-			  ORDER BY nc.date DESC`
+// provinceCasesVisible excludes the soft-deleted province cases and the cases
+// of soft-deleted provinces
+const provinceCasesVisible = "pc.deleted_at IS NULL AND p.deleted_at IS NULL"
 
-	return r.queryProvinceCases(query, provinceID)
+// selectProvinceCases starts a query of the given columns of the province
+// cases that are not soft-deleted
+func selectProvinceCases(columns []column[provinceCaseRow]) *selectBuilder {
+	return newSelect(selectList(columns), provinceCaseFrom).where(provinceCasesVisible)
 }
 
-func (r *provinceCaseRepository) GetByProvinceIDPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	// First get total count
-	countQuery := `SELECT COUNT(*) FROM province_cases pc
-				   JOIN national_cases nc ON pc.day = nc.id
-				   WHERE pc.province_id = ?`
+type provinceCaseRepository struct {
+	db *database.DB
+}
 
-	var total int
-	err := r.db.QueryRow(countQuery, provinceID).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count province cases for province %s: %w", provinceID, err)
-	}
+func NewProvinceCaseRepository(db *database.DB) ProvinceCaseRepository {
+	return &provinceCaseRepository{db: db}
+}
 
-	// Get paginated data
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE pc.province_id = ?
-			  ORDER BY nc.date DESC
-			  LIMIT ? OFFSET ?`
+func (r *provinceCaseRepository) Find(ctx context.Context, q ProvinceCaseQuery) ([]models.ProvinceCaseWithDate, int, error) {
+	if !q.AsOf.IsZero() {
+		return r.findAsOf(ctx, q)
+	}
+	columns := pickColumns(provinceCaseColumns, q.Columns)
+	b := r.selectMatching(q, columns).page(q.Limit, q.Offset)
+
+	total := 0
+	if q.Limit > 0 {
+		countQuery, countArgs := b.buildCount()
+		if err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("failed to count province cases: %w", err)
+		}
+	}
 
-	cases, err := r.queryProvinceCases(query, provinceID, limit, offset)
+	query, args := b.build()
+	cases, err := r.queryProvinceCases(ctx, columns, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
-
+	if q.Limit == 0 {
+		total = len(cases)
+	}
 	return cases, total, nil
 }
 
-func (r *provinceCaseRepository) GetByProvinceIDAndDateRange(provinceID string, startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error) {
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE pc.province_id = ? AND nc.date BETWEEN ? AND ?
-			  ORDER BY nc.date DESC`
+func (r *provinceCaseRepository) Each(ctx context.Context, q ProvinceCaseQuery, fn func(models.ProvinceCaseWithDate) error) error {
+	columns := pickColumns(provinceCaseColumns, q.Columns)
+	query, args := r.selectMatching(q, columns).build()
+	return r.eachProvinceCase(ctx, columns, query, args, fn)
+}
 
-	return r.queryProvinceCases(query, provinceID, startDate, endDate)
+// selectMatching selects the columns of the cases matching q in its order,
+// without the page
+func (r *provinceCaseRepository) selectMatching(q ProvinceCaseQuery, columns []column[provinceCaseRow]) *selectBuilder {
+	b := selectProvinceCases(columns).orderBy(r.buildOrderClause(q.Sort))
+	filterProvinceCases(b, q.ProvinceID, q.StartDate, q.EndDate, q.HasRt)
+	if q.AfterID > 0 {
+		b.where("pc.id > ?", q.AfterID)
+	}
+	return b
 }
 
-func (r *provinceCaseRepository) GetByProvinceIDAndDateRangePaginated(provinceID string, startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	// First get total count
-	countQuery := `SELECT COUNT(*) FROM province_cases pc
-				   JOIN national_cases nc ON pc.day = nc.id
-				   WHERE pc.province_id = ? AND nc.date BETWEEN ? AND ?`
+func (r *provinceCaseRepository) GetLatestByProvinceID(ctx context.Context, provinceID string) (*models.ProvinceCaseWithDate, error) {
+	query, args := selectProvinceCases(provinceCaseColumns).
+		where("pc.province_id = ?", provinceID).
+		orderBy("pc.date DESC, pc.id DESC").
+		page(1, 0).
+		build()
 
-	var total int
-	err := r.db.QueryRow(countQuery, provinceID, startDate, endDate).Scan(&total)
+	cases, err := r.queryProvinceCases(ctx, provinceCaseColumns, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count province cases for province %s in date range: %w", provinceID, err)
+		return nil, err
 	}
 
-	// Get paginated data
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE pc.province_id = ? AND nc.date BETWEEN ? AND ?
-			  ORDER BY nc.date DESC
-			  LIMIT ? OFFSET ?`
-
-	cases, err := r.queryProvinceCases(query, provinceID, startDate, endDate, limit, offset)
-	if err != nil {
-		return nil, 0, err
+	if len(cases) == 0 {
+		return nil, nil
 	}
 
-	return cases, total, nil
+	return &cases[0], nil
 }
 
-func (r *provinceCaseRepository) GetByDateRange(startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error) {
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE nc.date BETWEEN ? AND ?
-			  ORDER BY nc.date DESC, p.name`
+func (r *provinceCaseRepository) GetByProvinceIDAndDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	query, args := selectProvinceCases(provinceCaseColumns).
+		where("pc.province_id = ?", provinceID).
+		where("pc.date = ?", date).
+		orderBy("pc.id DESC").
+		page(1, 0).
+		build()
 
-	return r.queryProvinceCases(query, startDate, endDate)
+	cases, err := r.queryProvinceCases(ctx, provinceCaseColumns, query, args...)
+	if err != nil || len(cases) == 0 {
+		return nil, err
+	}
+	return &cases[0], nil
 }
 
-func (r *provinceCaseRepository) GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	// First get total count
-	countQuery := `SELECT COUNT(*) FROM province_cases pc
-				   JOIN national_cases nc ON pc.day = nc.id
-				   WHERE nc.date BETWEEN ? AND ?`
-
-	var total int
-	err := r.db.QueryRow(countQuery, startDate, endDate).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count province cases in date range: %w", err)
+// GetPageAfterCursor returns up to q.Limit cases ordered by date and ID that
+// follow q.After, and the cursor of the last returned row when more follow.
+// Unlike offset pagination the cost does not grow with the page depth: the
+// seek on pc.date and pc.id walks idx_province_cases_province_date, whose
+// entries end with the primary key.
+func (r *provinceCaseRepository) GetPageAfterCursor(ctx context.Context, q ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	order, cmp := "ASC", ">"
+	if q.Desc {
+		order, cmp = "DESC", "<"
+	}
+	// One extra row tells whether another page follows
+	columns := pickColumns(provinceCaseColumns, q.Columns)
+	b := selectProvinceCases(columns).
+		orderBy("pc.date "+order+", pc.id "+order).
+		page(q.Limit+1, 0)
+	filterProvinceCases(b, q.ProvinceID, q.StartDate, q.EndDate, q.HasRt)
+	if q.After != nil {
+		b.where("(pc.date "+cmp+" ? OR (pc.date = ? AND pc.id "+cmp+" ?))",
+			q.After.Date, q.After.Date, q.After.ID)
 	}
 
-	// Get paginated data
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE nc.date BETWEEN ? AND ?
-			  ORDER BY nc.date DESC, p.name
-			  LIMIT ? OFFSET ?`
-
-	cases, err := r.queryProvinceCases(query, startDate, endDate, limit, offset)
+	query, args := b.build()
+	cases, err := r.queryProvinceCases(ctx, columns, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
-
-	return cases, total, nil
+	if len(cases) <= q.Limit {
+		return cases, nil, nil
+	}
+	cases = cases[:q.Limit]
+	last := cases[len(cases)-1]
+	return cases, &models.CaseCursor{Date: last.Date, ID: last.ID}, nil
 }
 
-func (r *provinceCaseRepository) GetLatestByProvinceID(provinceID string) (*models.ProvinceCaseWithDate, error) {
-	query := `SELECT pc.id, pc.day, pc.province_id, pc.positive, pc.recovered, pc.deceased,
-			  pc.person_under_observation, pc.finished_person_under_observation,
-			  pc.person_under_supervision, pc.finished_person_under_supervision,
-			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
-			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
-			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
-			  pc.rt, pc.rt_upper, pc.rt_lower, nc.date, p.name
-			  FROM province_cases pc
-			  JOIN national_cases nc ON pc.day = nc.id
-			  LEFT JOIN provinces p ON pc.province_id = p.id
-			  WHERE pc.province_id = ?
-			  ORDER BY nc.date DESC LIMIT 1`
+// filterProvinceCases adds the conditions shared by the offset and cursor
+// queries; the dates only bound the cases when both are set
+func filterProvinceCases(b *selectBuilder, provinceID string, startDate, endDate time.Time, hasRt bool) {
+	if provinceID != "" {
+		b.where("pc.province_id = ?", provinceID)
+	}
+	if !startDate.IsZero() && !endDate.IsZero() {
+		b.where("pc.date BETWEEN ? AND ?", startDate, endDate)
+	}
+	if hasRt {
+		b.where("pc.rt IS NOT NULL")
+	}
+}
 
-	cases, err := r.queryProvinceCases(query, provinceID)
+func (r *provinceCaseRepository) queryProvinceCases(ctx context.Context, columns []column[provinceCaseRow], query string, args ...interface{}) ([]models.ProvinceCaseWithDate, error) {
+	var cases []models.ProvinceCaseWithDate
+	err := r.eachProvinceCase(ctx, columns, query, args, func(c models.ProvinceCaseWithDate) error {
+		cases = append(cases, c)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	if len(cases) == 0 {
-		return nil, nil
-	}
-
-	return &cases[0], nil
+	return cases, nil
 }
 
-func (r *provinceCaseRepository) queryProvinceCases(query string, args ...interface{}) ([]models.ProvinceCaseWithDate, error) {
-	rows, err := r.db.Query(query, args...)
+// eachProvinceCase scans the columns of the cases of the query one row at a
+// time
+func (r *provinceCaseRepository) eachProvinceCase(ctx context.Context, columns []column[provinceCaseRow], query string, args []interface{}, fn func(models.ProvinceCaseWithDate) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query province cases: %w", err)
+		return fmt.Errorf("failed to query province cases: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -290,80 +327,57 @@ func (r *provinceCaseRepository) queryProvinceCases(query string, args ...interf
 		}
 	}()
 
-	var cases []models.ProvinceCaseWithDate
 	for rows.Next() {
 		var c models.ProvinceCaseWithDate
-		var provinceName sql.NullString
-
-		// Use sql.NullInt64 for nullable ODP/PDP fields to handle NULL values from DB
-		var (
-			personUnderObs                   sql.NullInt64
-			finishedPersonUnderObs           sql.NullInt64
-			personUnderSup                   sql.NullInt64
-			finishedPersonUnderSup           sql.NullInt64
-			cumulativePersonUnderObs         sql.NullInt64
-			cumulativeFinishedPersonUnderObs sql.NullInt64
-			cumulativePersonUnderSup         sql.NullInt64
-			cumulativeFinishedPersonUnderSup sql.NullInt64
-		)
-
-		err := rows.Scan(&c.ID, &c.Day, &c.ProvinceID, &c.Positive, &c.Recovered, &c.Deceased,
-			&personUnderObs, &finishedPersonUnderObs,
-			&personUnderSup, &finishedPersonUnderSup,
-			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
-			&cumulativePersonUnderObs, &cumulativeFinishedPersonUnderObs,
-			&cumulativePersonUnderSup, &cumulativeFinishedPersonUnderSup,
-			&c.Rt, &c.RtUpper, &c.RtLower, &c.Date, &provinceName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan province case: %w", err)
+		if err := rows.Scan(scanDest(columns, &c)...); err != nil {
+			return fmt.Errorf("failed to scan province case: %w", err)
 		}
-
-		// Convert NullInt64 to int64 (NULL → 0)
-		c.PersonUnderObservation = personUnderObs.Int64
-		c.FinishedPersonUnderObservation = finishedPersonUnderObs.Int64
-		c.PersonUnderSupervision = personUnderSup.Int64
-		c.FinishedPersonUnderSupervision = finishedPersonUnderSup.Int64
-		c.CumulativePersonUnderObservation = cumulativePersonUnderObs.Int64
-		c.CumulativeFinishedPersonUnderObservation = cumulativeFinishedPersonUnderObs.Int64
-		c.CumulativePersonUnderSupervision = cumulativePersonUnderSup.Int64
-		c.CumulativeFinishedPersonUnderSupervision = cumulativeFinishedPersonUnderSup.Int64
-
-		if provinceName.Valid {
-			c.Province = &models.Province{
-				ID:   c.ProvinceID,
-				Name: provinceName.String,
-			}
+		if c.Province != nil {
+			c.Province.ID = c.ProvinceID
 		}
 
-		cases = append(cases, c)
+		if err := fn(c); err != nil {
+			return err
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+		return fmt.Errorf("row iteration error: %w", err)
 	}
-
-	return cases, nil
+	return nil
 }
 
 // buildOrderClause builds ORDER BY clause for province case queries
 func (r *provinceCaseRepository) buildOrderClause(sortParams utils.SortParams) string {
 	// Map API field names to database column names for province cases
 	fieldMapping := map[string]string{
-		"date":          "nc.date",
-		"day":           "pc.day",
-		"positive":      "pc.positive",
-		"recovered":     "pc.recovered",
-		"deceased":      "pc.deceased",
-		"active":        "(pc.positive - pc.recovered - pc.deceased)",
-		"province_id":   "pc.province_id",
-		"province_name": "p.name",
-		"created_at":    "pc.created_at",
-		"updated_at":    "pc.updated_at",
+		"date":                    "pc.date",
+		"day":                     "pc.day",
+		"positive":                "pc.positive",
+		"recovered":               "pc.recovered",
+		"deceased":                "pc.deceased",
+		"active":                  "(pc.positive - pc.recovered - pc.deceased)",
+		"cumulative_positive":     "pc.cumulative_positive",
+		"cumulative_recovered":    "pc.cumulative_recovered",
+		"cumulative_deceased":     "pc.cumulative_deceased",
+		"rt":                      "pc.rt",
+		"odp":                     "pc.person_under_observation",
+		"odp_finished":            "pc.finished_person_under_observation",
+		"pdp":                     "pc.person_under_supervision",
+		"pdp_finished":            "pc.finished_person_under_supervision",
+		"cumulative_odp":          "pc.cumulative_person_under_observation",
+		"cumulative_odp_finished": "pc.cumulative_finished_person_under_observation",
+		"cumulative_pdp":          "pc.cumulative_person_under_supervision",
+		"cumulative_pdp_finished": "pc.cumulative_finished_person_under_supervision",
+		"province_id":             "pc.province_id",
+		"province_name":           "p.name",
+		"created_at":              "pc.created_at",
+		"updated_at":              "pc.updated_at",
 	}
 
 	dbField, exists := fieldMapping[sortParams.Field]
 	if !exists {
-		dbField = "nc.date" // fallback to date
+		dbField = "pc.date" // fallback to date
 	}
 
 	order := "ASC"
@@ -371,35 +385,55 @@ func (r *provinceCaseRepository) buildOrderClause(sortParams utils.SortParams) s
 		order = "DESC"
 	}
 
-	// Add secondary sort for consistency
+	// Break ties by province, date and id so pages of a list sorted by a
+	// non-unique field never overlap or skip rows between requests
+	clause := dbField + " " + order
+	if sortParams.Field == "rt" {
+		// Days without an Rt estimate come last in either direction
+		clause = utils.NullsLast(dbField, order)
+	}
 	if sortParams.Field != "province_name" {
-		return dbField + " " + order + ", p.name ASC"
+		clause += ", p.name ASC"
 	}
-
-	return dbField + " " + order
-}
-
-// Stub implementations for other sorted methods - delegate to existing methods for now
-func (r *provinceCaseRepository) GetByProvinceIDSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	return r.GetByProvinceID(provinceID)
-}
-
-func (r *provinceCaseRepository) GetByProvinceIDPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	return r.GetByProvinceIDPaginated(provinceID, limit, offset)
-}
-
-func (r *provinceCaseRepository) GetByProvinceIDAndDateRangeSorted(provinceID string, startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	return r.GetByProvinceIDAndDateRange(provinceID, startDate, endDate)
+	if dbField != "pc.date" {
+		clause += ", pc.date " + order
+	}
+	return clause + ", pc.id " + order
 }
 
-func (r *provinceCaseRepository) GetByProvinceIDAndDateRangePaginatedSorted(provinceID string, startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	return r.GetByProvinceIDAndDateRangePaginated(provinceID, startDate, endDate, limit, offset)
-}
+// GetAggregated totals the province cases per province and ISO week or
+// calendar month, like the national aggregates. Rows without a date are
+// left out.
+func (r *provinceCaseRepository) GetAggregated(ctx context.Context, q CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	const date = "pc.date"
+	period, err := periodExpression(r.db.Dialect(), q.Interval, date)
+	if err != nil {
+		return nil, err
+	}
+	conditions := []string{provinceCasesVisible, date + " IS NOT NULL"}
+	var args []interface{}
+	if q.ProvinceID != "" {
+		conditions = append(conditions, "pc.province_id = ?")
+		args = append(args, q.ProvinceID)
+	}
+	if q.hasDateRange() {
+		conditions = append(conditions, date+" BETWEEN ? AND ?")
+		args = append(args, q.StartDate, q.EndDate)
+	}
 
-func (r *provinceCaseRepository) GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	return r.GetByDateRange(startDate, endDate)
-}
+	query := `SELECT pc.province_id, p.name, ` + period + ` AS period, MIN(` + date + `), MAX(` + date + `), COUNT(*),
+			  SUM(pc.positive), SUM(pc.recovered), SUM(pc.deceased),
+			  MAX(pc.cumulative_positive), MAX(pc.cumulative_recovered), MAX(pc.cumulative_deceased),
+			  AVG(pc.rt)
+			  FROM province_cases pc
+			  LEFT JOIN provinces p ON pc.province_id = p.id
+			  WHERE ` + strings.Join(conditions, " AND ") + `
+			  GROUP BY pc.province_id, p.name, period
+			  ORDER BY MIN(` + date + `) ` + q.order() + `, pc.province_id`
 
-func (r *provinceCaseRepository) GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	return r.GetByDateRangePaginated(startDate, endDate, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aggregated province cases: %w", err)
+	}
+	return scanAggregatedCases(rows, true)
 }
@@ -1,15 +1,18 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestProvinceCaseRepository_GetAll(t *testing.T) {
+func TestProvinceCaseRepository_Find(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -22,22 +25,16 @@ func TestProvinceCaseRepository_GetAll(t *testing.T) {
 	now := time.Now()
 	rt := 1.1
 
-	rows := sqlmock.NewRows([]string{
-		"id", "day", "province_id", "positive", "recovered", "deceased",
-		"person_under_observation", "finished_person_under_observation",
-		"person_under_supervision", "finished_person_under_supervision",
-		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
-		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
-		"rt", "rt_upper", "rt_lower", "date", "name",
-	}).AddRow(1, 1, "11", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, rt, nil, nil, now, "Aceh")
+	rows := sqlmock.NewRows(provinceCaseColumnNames).
+		AddRow(1, 1, "11", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, rt, nil, nil, now, "Aceh", nil, nil)
 
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id.* LEFT JOIN provinces p ON pc\.province_id = p\.id WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL ORDER BY pc\.date ASC, p\.name ASC, pc\.id ASC$`).
 		WillReturnRows(rows)
 
-	cases, err := repo.GetAll()
+	cases, total, err := repo.Find(context.Background(), ProvinceCaseQuery{Sort: utils.SortParams{Field: "date", Order: "asc"}})
 
 	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
 	assert.Len(t, cases, 1)
 	assert.Equal(t, int64(1), cases[0].ID)
 	assert.Equal(t, "11", cases[0].ProvinceID)
@@ -49,44 +46,106 @@ func TestProvinceCaseRepository_GetAll(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceID(t *testing.T) {
+func TestProvinceCaseRepository_Find_ProvinceAndDateRange(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
-			t.Logf("Error closing database: %v", err)
+			t.Logf("Error closing db: %v", err)
 		}
 	}()
-
 	repo := NewProvinceCaseRepository(db)
+	provinceID := "11"
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
 
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \? AND pc\.date BETWEEN \? AND \? ORDER BY pc\.date DESC, p\.name ASC, pc\.id DESC$`).
+		WithArgs(provinceID, start, end).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), provinceID, start))
+
+	cases, _, err := repo.Find(context.Background(), ProvinceCaseQuery{
+		ProvinceID: provinceID,
+		StartDate:  start,
+		EndDate:    end,
+		Sort:       utils.SortParams{Field: "date", Order: "desc"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, cases, 1)
+	assert.Equal(t, provinceID, cases[0].ProvinceID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceCaseRepository_Find_Paginated(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing db: %v", err)
+		}
+	}()
+	repo := NewProvinceCaseRepository(db)
 	provinceID := "11"
 	now := time.Now()
 
-	rows := sqlmock.NewRows([]string{
-		"id", "day", "province_id", "positive", "recovered", "deceased",
-		"person_under_observation", "finished_person_under_observation",
-		"person_under_supervision", "finished_person_under_supervision",
-		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
-		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
-		"rt", "rt_upper", "rt_lower", "date", "name",
-	}).AddRow(1, 1, provinceID, 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, now, "Aceh")
-
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases pc .* WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \?$`).
 		WithArgs(provinceID).
-		WillReturnRows(rows)
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(20))
+	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id.* ORDER BY pc\.positive DESC, p\.name ASC, pc\.date DESC, pc\.id DESC LIMIT \? OFFSET \?`).
+		WithArgs(provinceID, 10, 10).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), provinceID, now))
+
+	cases, total, err := repo.Find(context.Background(), ProvinceCaseQuery{
+		ProvinceID: provinceID,
+		Sort:       utils.SortParams{Field: "positive", Order: "desc"},
+		Limit:      10,
+		Offset:     10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, cases, 1)
+	assert.Equal(t, 20, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceCaseRepository_Find_ByProvinceName(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing db: %v", err)
+		}
+	}()
+	repo := NewProvinceCaseRepository(db)
+	now := time.Now()
 
-	cases, err := repo.GetByProvinceID(provinceID)
+	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "11", now)
+	mock.ExpectQuery(`ORDER BY p\.name DESC`).
+		WillReturnRows(rows)
 
+	cases, _, err := repo.Find(context.Background(), ProvinceCaseQuery{Sort: utils.SortParams{Field: "province_name", Order: "desc"}})
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
-	assert.Equal(t, provinceID, cases[0].ProvinceID)
-	assert.Nil(t, cases[0].Rt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceCaseRepository_Find_UnknownField(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing db: %v", err)
+		}
+	}()
+	repo := NewProvinceCaseRepository(db)
+	now := time.Now()
 
+	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "11", now)
+	mock.ExpectQuery(`SELECT pc\.id`).
+		WillReturnRows(rows)
+
+	cases, _, err := repo.Find(context.Background(), ProvinceCaseQuery{Sort: utils.SortParams{Field: "unknown_field", Order: "asc"}})
+	assert.NoError(t, err)
+	assert.Len(t, cases, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceIDAndDateRange(t *testing.T) {
+// Only the key columns and the requested ones are read
+func TestProvinceCaseRepository_Find_Columns(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -95,35 +154,41 @@ func TestProvinceCaseRepository_GetByProvinceIDAndDateRange(t *testing.T) {
 	}()
 
 	repo := NewProvinceCaseRepository(db)
-
-	provinceID := "11"
-	startDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
 	now := time.Now()
 
-	rows := sqlmock.NewRows([]string{
-		"id", "day", "province_id", "positive", "recovered", "deceased",
-		"person_under_observation", "finished_person_under_observation",
-		"person_under_supervision", "finished_person_under_supervision",
-		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
-		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
-		"rt", "rt_upper", "rt_lower", "date", "name",
-	}).AddRow(1, 1, provinceID, 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, now, "Aceh")
-
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WithArgs(provinceID, startDate, endDate).
+	rows := sqlmock.NewRows([]string{"id", "day", "province_id", "person_under_observation", "date", "name", "created_at", "updated_at"}).
+		AddRow(1, 1, "72", nil, now, "Sulawesi Tengah", nil, nil)
+	mock.ExpectQuery(`^SELECT pc\.id, pc\.day, pc\.province_id, pc\.person_under_observation, COALESCE\(nc\.date, pc\.date\), p\.name, pc\.created_at, pc\.updated_at FROM province_cases pc`).
 		WillReturnRows(rows)
 
-	cases, err := repo.GetByProvinceIDAndDateRange(provinceID, startDate, endDate)
-
-	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.Equal(t, provinceID, cases[0].ProvinceID)
+	cases, _, err := repo.Find(context.Background(), ProvinceCaseQuery{
+		ProvinceID: "72",
+		Columns:    []string{"person_under_observation"},
+	})
 
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.Zero(t, cases[0].PersonUnderObservation, "NULL is read as 0")
+	assert.Equal(t, now, cases[0].Date)
+	require.NotNil(t, cases[0].Province)
+	assert.Equal(t, models.Province{ID: "72", Name: "Sulawesi Tengah"}, *cases[0].Province)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+var provinceCaseColumnNames = []string{
+	"id", "day", "province_id", "positive", "recovered", "deceased",
+	"person_under_observation", "finished_person_under_observation",
+	"person_under_supervision", "finished_person_under_supervision",
+	"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+	"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+	"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+	"rt", "rt_upper", "rt_lower", "date", "name", "created_at", "updated_at",
+}
+
+func addProvinceCaseRow(rows *sqlmock.Rows, provinceID string, now time.Time) *sqlmock.Rows {
+	return rows.AddRow(1, 1, provinceID, 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, now, "Aceh", nil, nil)
+}
+
 func TestProvinceCaseRepository_GetLatestByProvinceID(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
@@ -145,14 +210,14 @@ func TestProvinceCaseRepository_GetLatestByProvinceID(t *testing.T) {
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
 		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
 		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
-		"rt", "rt_upper", "rt_lower", "date", "name",
-	}).AddRow(1, 1, provinceID, 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, rt, nil, nil, now, "Aceh")
+		"rt", "rt_upper", "rt_lower", "date", "name", "created_at", "updated_at",
+	}).AddRow(1, 1, provinceID, 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, rt, nil, nil, now, "Aceh", nil, nil)
 
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WithArgs(provinceID).
+	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id.* ORDER BY pc\.date DESC, pc\.id DESC LIMIT \? OFFSET \?`).
+		WithArgs(provinceID, 1, 0).
 		WillReturnRows(rows)
 
-	provinceCase, err := repo.GetLatestByProvinceID(provinceID)
+	provinceCase, err := repo.GetLatestByProvinceID(context.Background(), provinceID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, provinceCase)
@@ -181,14 +246,14 @@ func TestProvinceCaseRepository_GetLatestByProvinceID_NotFound(t *testing.T) {
 		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
 		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
 		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
-		"rt", "rt_upper", "rt_lower", "date", "name",
+		"rt", "rt_upper", "rt_lower", "date", "name", "created_at", "updated_at",
 	})
 
 	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WithArgs(provinceID).
+		WithArgs(provinceID, 1, 0).
 		WillReturnRows(rows)
 
-	provinceCase, err := repo.GetLatestByProvinceID(provinceID)
+	provinceCase, err := repo.GetLatestByProvinceID(context.Background(), provinceID)
 
 	assert.NoError(t, err)
 	assert.Nil(t, provinceCase)
@@ -196,355 +261,341 @@ func TestProvinceCaseRepository_GetLatestByProvinceID_NotFound(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-var provinceCaseColumns = []string{
-	"id", "day", "province_id", "positive", "recovered", "deceased",
-	"person_under_observation", "finished_person_under_observation",
-	"person_under_supervision", "finished_person_under_supervision",
-	"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
-	"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
-	"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
-	"rt", "rt_upper", "rt_lower", "date", "name",
-}
-
-func addProvinceCaseRow(rows *sqlmock.Rows, provinceID string, now time.Time) *sqlmock.Rows {
-	return rows.AddRow(1, 1, provinceID, 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, now, "Aceh")
-}
-
-func TestProvinceCaseRepository_GetAllPaginated(t *testing.T) {
+func TestProvinceCaseRepository_GetByProvinceIDAndDate(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
-			t.Logf("Error closing db: %v", err)
+			t.Logf("Error closing database: %v", err)
 		}
 	}()
+
 	repo := NewProvinceCaseRepository(db)
-	now := time.Now()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(100))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "day", "province_id", "positive", "recovered", "deceased",
+		"person_under_observation", "finished_person_under_observation",
+		"person_under_supervision", "finished_person_under_supervision",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+		"rt", "rt_upper", "rt_lower", "date", "name", "created_at", "updated_at",
+	}
+	rows := sqlmock.NewRows(columns).
+		AddRow(9, 500, "72", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, date, "Sulawesi Tengah", nil, nil)
+
+	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id.* WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \? AND pc\.date = \? ORDER BY pc\.id DESC LIMIT \? OFFSET \?`).
+		WithArgs("72", date, 1, 0).
 		WillReturnRows(rows)
 
-	cases, total, err := repo.GetAllPaginated(10, 0)
-	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.Equal(t, 100, total)
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
+	provinceCase, err := repo.GetByProvinceIDAndDate(context.Background(), "72", date)
 
-func TestProvinceCaseRepository_GetAllPaginatedSorted(t *testing.T) {
-	db, mock := setupMockDB(t)
-	defer func() {
-		if err := db.Close(); err != nil {
-			t.Logf("Error closing db: %v", err)
-		}
-	}()
-	repo := NewProvinceCaseRepository(db)
-	now := time.Now()
+	assert.NoError(t, err)
+	assert.NotNil(t, provinceCase)
+	assert.Equal(t, int64(500), provinceCase.Day)
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(50))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
 	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WillReturnRows(rows)
+		WithArgs("72", date.AddDate(0, 0, 1), 1, 0).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	provinceCase, err = repo.GetByProvinceIDAndDate(context.Background(), "72", date.AddDate(0, 0, 1))
 
-	sortParams := utils.SortParams{Field: "date", Order: "asc"}
-	cases, total, err := repo.GetAllPaginatedSorted(10, 0, sortParams)
 	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.Equal(t, 50, total)
+	assert.Nil(t, provinceCase)
+
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceIDPaginated(t *testing.T) {
+func TestProvinceCaseRepository_Find_KeepsRowsWithoutNationalCase(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
-			t.Logf("Error closing db: %v", err)
+			t.Logf("Error closing database: %v", err)
 		}
 	}()
+
 	repo := NewProvinceCaseRepository(db)
-	provinceID := "11"
-	now := time.Now()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WithArgs(provinceID).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(20))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), provinceID, now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	stored := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "day", "province_id", "positive", "recovered", "deceased",
+		"person_under_observation", "finished_person_under_observation",
+		"person_under_supervision", "finished_person_under_supervision",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+		"rt", "rt_upper", "rt_lower", "date", "name", "created_at", "updated_at",
+	}).
+		AddRow(1, 900, "72", 5, 0, 0, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, nil, nil, nil, stored, "Sulawesi Tengah", nil, nil).
+		AddRow(2, 901, "72", 1, 0, 0, 0, 0, 0, 0, 6, 0, 0, 0, 0, 0, 0, nil, nil, nil, nil, "Sulawesi Tengah", nil, nil)
+
+	mock.ExpectQuery(`LEFT JOIN national_cases nc ON pc\.day = nc\.id`).
 		WillReturnRows(rows)
 
-	cases, total, err := repo.GetByProvinceIDPaginated(provinceID, 10, 0)
+	cases, _, err := repo.Find(context.Background(), ProvinceCaseQuery{})
+
 	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.Equal(t, 20, total)
+	assert.Len(t, cases, 2)
+	assert.Equal(t, stored, cases[0].Date)
+	assert.True(t, cases[1].Date.IsZero())
+
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceIDAndDateRangePaginated(t *testing.T) {
+func TestProvinceCaseRepository_GetPageAfterCursor(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	provinceID := "11"
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WithArgs(provinceID, start, end).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), provinceID, now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	after := &models.CaseCursor{Date: date, ID: 10}
+	rows := sqlmock.NewRows(provinceCaseColumnNames).
+		AddRow(11, 1, "72", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, date, "Sulawesi Tengah", nil, nil).
+		AddRow(12, 2, "72", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, date.AddDate(0, 0, 1), "Sulawesi Tengah", nil, nil).
+		AddRow(13, 3, "72", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, date.AddDate(0, 0, 2), "Sulawesi Tengah", nil, nil)
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \? AND \(pc\.date > \? OR \(pc\.date = \? AND pc\.id > \?\)\)\s+ORDER BY pc\.date ASC, pc\.id ASC\s+LIMIT \?`).
+		WithArgs("72", date, date, int64(10), 3, 0).
 		WillReturnRows(rows)
 
-	cases, total, err := repo.GetByProvinceIDAndDateRangePaginated(provinceID, start, end, 10, 0)
+	cases, next, err := NewProvinceCaseRepository(db).GetPageAfterCursor(context.Background(),
+		ProvinceCaseCursorQuery{ProvinceID: "72", After: after, Limit: 2})
+
 	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.Equal(t, 5, total)
+	assert.Len(t, cases, 2)
+	assert.Equal(t, &models.CaseCursor{Date: date.AddDate(0, 0, 1), ID: 12}, next)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByDateRange(t *testing.T) {
+func TestProvinceCaseRepository_GetPageAfterCursor_LastPage(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WithArgs(start, end).
-		WillReturnRows(rows)
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.date BETWEEN \? AND \?\s+ORDER BY pc\.date DESC, pc\.id DESC\s+LIMIT \?`).
+		WithArgs(start, end, 51, 0).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "11", start))
+
+	cases, next, err := NewProvinceCaseRepository(db).GetPageAfterCursor(context.Background(),
+		ProvinceCaseCursorQuery{StartDate: start, EndDate: end, Desc: true, Limit: 50})
 
-	cases, err := repo.GetByDateRange(start, end)
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
+	assert.Nil(t, next)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByDateRangePaginated(t *testing.T) {
-	db, mock := setupMockDB(t)
+// The date filters and cursor seeks read pc.date, which only matches the
+// national date while the triggers of migrations/postgres/0024 keep it in sync
+func TestProvinceCaseRepository_Find_ProvinceAndDateRange_Postgres(t *testing.T) {
+	db, mock := setupPostgresMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
 	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WithArgs(start, end).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases pc .* WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \$1 AND pc\.date BETWEEN \$2 AND \$3$`).
+		WithArgs("72", start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(31))
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \$1 AND pc\.date BETWEEN \$2 AND \$3 ORDER BY pc\.date DESC, p\.name ASC, pc\.id DESC LIMIT \$4 OFFSET \$5$`).
+		WithArgs("72", start, end, 10, 0).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "72", end))
+
+	cases, total, err := NewProvinceCaseRepository(db).Find(context.Background(), ProvinceCaseQuery{
+		ProvinceID: "72",
+		StartDate:  start,
+		EndDate:    end,
+		Sort:       utils.SortParams{Field: "date", Order: "desc"},
+		Limit:      10,
+	})
 
-	cases, total, err := repo.GetByDateRangePaginated(start, end, 10, 0)
 	assert.NoError(t, err)
+	assert.Equal(t, 31, total)
 	assert.Len(t, cases, 1)
-	assert.Equal(t, 15, total)
+	assert.Equal(t, end, cases[0].Date)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceIDSorted(t *testing.T) {
-	db, mock := setupMockDB(t)
+func TestProvinceCaseRepository_GetPageAfterCursor_Postgres(t *testing.T) {
+	db, mock := setupPostgresMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	provinceID := "11"
-	now := time.Now()
 
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), provinceID, now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(provinceCaseColumnNames).
+		AddRow(9, 1, "72", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, date.AddDate(0, 0, -1), "Sulawesi Tengah", nil, nil).
+		AddRow(8, 2, "72", 50, 40, 2, 10, 8, 5, 3, 500, 400, 20, 100, 80, 50, 30, nil, nil, nil, date.AddDate(0, 0, -2), "Sulawesi Tengah", nil, nil)
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \$1 AND \(pc\.date < \$2 OR \(pc\.date = \$3 AND pc\.id < \$4\)\)\s+ORDER BY pc\.date DESC, pc\.id DESC\s+LIMIT \$5 OFFSET \$6`).
+		WithArgs("72", date, date, int64(10), 2, 0).
 		WillReturnRows(rows)
 
-	sortParams := utils.SortParams{Field: "date", Order: "desc"}
-	cases, err := repo.GetByProvinceIDSorted(provinceID, sortParams)
-	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
-
-func TestProvinceCaseRepository_GetByProvinceIDPaginatedSorted(t *testing.T) {
-	db, mock := setupMockDB(t)
-	defer func() {
-		if err := db.Close(); err != nil {
-			t.Logf("Error closing db: %v", err)
-		}
-	}()
-	repo := NewProvinceCaseRepository(db)
-	provinceID := "11"
-	now := time.Now()
-
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WithArgs(provinceID).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), provinceID, now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WillReturnRows(rows)
+	cases, next, err := NewProvinceCaseRepository(db).GetPageAfterCursor(context.Background(),
+		ProvinceCaseCursorQuery{ProvinceID: "72", After: &models.CaseCursor{Date: date, ID: 10}, Desc: true, Limit: 1})
 
-	sortParams := utils.SortParams{Field: "positive", Order: "desc"}
-	cases, total, err := repo.GetByProvinceIDPaginatedSorted(provinceID, 10, 0, sortParams)
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
-	assert.Equal(t, 10, total)
+	assert.Equal(t, &models.CaseCursor{Date: date.AddDate(0, 0, -1), ID: 9}, next)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceIDAndDateRangeSorted(t *testing.T) {
+func TestProvinceCaseRepository_Find_HasRt(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	provinceID := "11"
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), provinceID, now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WillReturnRows(rows)
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases pc\s+LEFT JOIN national_cases nc ON pc\.day = nc\.id\s+LEFT JOIN provinces p ON pc\.province_id = p\.id\s+WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \? AND pc\.rt IS NOT NULL`).
+		WithArgs("72").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(120))
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.province_id = \? AND pc\.rt IS NOT NULL\s+ORDER BY pc\.date DESC, p\.name ASC, pc\.id DESC\s+LIMIT \? OFFSET \?`).
+		WithArgs("72", 50, 100).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "72", date))
+
+	cases, total, err := NewProvinceCaseRepository(db).Find(context.Background(), ProvinceCaseQuery{
+		ProvinceID: "72",
+		HasRt:      true,
+		Sort:       utils.SortParams{Field: "date", Order: "desc"},
+		Limit:      50,
+		Offset:     100,
+	})
 
-	sortParams := utils.SortParams{Field: "date", Order: "asc"}
-	cases, err := repo.GetByProvinceIDAndDateRangeSorted(provinceID, start, end, sortParams)
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
+	assert.Equal(t, 120, total)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByProvinceIDAndDateRangePaginatedSorted(t *testing.T) {
+func TestProvinceCaseRepository_Find_All(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	provinceID := "11"
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WithArgs(provinceID, start, end).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(8))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), provinceID, now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WillReturnRows(rows)
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.date BETWEEN \? AND \? AND pc\.rt IS NOT NULL\s+ORDER BY`).
+		WithArgs(start, end).
+		WillReturnRows(addProvinceCaseRow(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "11", start), "72", start))
+
+	cases, total, err := NewProvinceCaseRepository(db).Find(context.Background(), ProvinceCaseQuery{
+		StartDate: start,
+		EndDate:   end,
+		HasRt:     true,
+		Sort:      utils.SortParams{Field: "date", Order: "asc"},
+	})
 
-	sortParams := utils.SortParams{Field: "date", Order: "asc"}
-	cases, total, err := repo.GetByProvinceIDAndDateRangePaginatedSorted(provinceID, start, end, 10, 0, sortParams)
 	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.Equal(t, 8, total)
+	assert.Len(t, cases, 2)
+	assert.Equal(t, 2, total)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByDateRangeSorted(t *testing.T) {
+func TestProvinceCaseRepository_GetPageAfterCursor_HasRt(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
-		WithArgs(start, end).
-		WillReturnRows(rows)
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.rt IS NOT NULL\s+ORDER BY pc\.date ASC, pc\.id ASC\s+LIMIT \?`).
+		WithArgs(11, 0).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "72", date))
+
+	cases, next, err := NewProvinceCaseRepository(db).GetPageAfterCursor(context.Background(),
+		ProvinceCaseCursorQuery{HasRt: true, Limit: 10})
 
-	sortParams := utils.SortParams{Field: "date", Order: "asc"}
-	cases, err := repo.GetByDateRangeSorted(start, end, sortParams)
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
+	assert.Nil(t, next)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetByDateRangePaginatedSorted(t *testing.T) {
+func TestProvinceCaseRepository_GetAggregated_Monthly(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
-			t.Logf("Error closing db: %v", err)
+			t.Logf("Error closing database: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	now := time.Now()
 
-	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_cases`).
-		WithArgs(start, end).
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id, pc\.day, pc\.province_id`).
+	first := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"province_id", "name", "period", "min", "max", "count", "positive", "recovered", "deceased",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased", "rt"}).
+		AddRow("72", "Sulawesi Tengah", "2021-06", first, first.AddDate(0, 0, 29), 30, 900, 800, 12, 15000, 13000, 400, 0.95)
+
+	mock.ExpectQuery(`SELECT pc\.province_id, p\.name, DATE_FORMAT\(pc\.date, '%Y-%m'\) AS period.*WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.date IS NOT NULL AND pc\.province_id = \?\s+GROUP BY pc\.province_id, p\.name, period\s+ORDER BY MIN\(pc\.date\) DESC, pc\.province_id`).
+		WithArgs("72").
 		WillReturnRows(rows)
 
-	sortParams := utils.SortParams{Field: "date", Order: "desc"}
-	cases, total, err := repo.GetByDateRangePaginatedSorted(start, end, 10, 0, sortParams)
+	cases, err := NewProvinceCaseRepository(db).GetAggregated(context.Background(), CaseAggregateQuery{
+		Interval: models.IntervalMonthly, ProvinceID: "72", Desc: true,
+	})
+
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
-	assert.Equal(t, 12, total)
+	assert.Equal(t, "2021-06", cases[0].Period)
+	assert.Equal(t, "72", cases[0].ProvinceID)
+	assert.Equal(t, &models.Province{ID: "72", Name: "Sulawesi Tengah"}, cases[0].Province)
+	assert.Equal(t, 30, cases[0].Days)
+	assert.Equal(t, int64(400), cases[0].CumulativeDeceased)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestProvinceCaseRepository_GetAllSorted_ByProvinceName(t *testing.T) {
-	db, mock := setupMockDB(t)
-	defer func() {
-		if err := db.Close(); err != nil {
-			t.Logf("Error closing db: %v", err)
-		}
-	}()
-	repo := NewProvinceCaseRepository(db)
-	now := time.Now()
-
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id`).
-		WillReturnRows(rows)
-
-	cases, err := repo.GetAllSorted(utils.SortParams{Field: "province_name", Order: "desc"})
-	assert.NoError(t, err)
-	assert.Len(t, cases, 1)
-	assert.NoError(t, mock.ExpectationsWereMet())
+func TestProvinceCaseRepository_BuildOrderClause_TieBreakers(t *testing.T) {
+	r := &provinceCaseRepository{}
+
+	assert.Equal(t, "pc.positive DESC, p.name ASC, pc.date DESC, pc.id DESC",
+		r.buildOrderClause(utils.SortParams{Field: "positive", Order: "desc"}))
+	assert.Equal(t, "pc.date ASC, p.name ASC, pc.id ASC",
+		r.buildOrderClause(utils.SortParams{Field: "date", Order: "asc"}))
+	assert.Equal(t, "p.name ASC, pc.date ASC, pc.id ASC",
+		r.buildOrderClause(utils.SortParams{Field: "province_name", Order: "asc"}))
+	assert.Equal(t, "pc.rt IS NULL, pc.rt DESC, p.name ASC, pc.date DESC, pc.id DESC",
+		r.buildOrderClause(utils.SortParams{Field: "rt", Order: "desc"}))
+	assert.Equal(t, "pc.cumulative_person_under_supervision ASC, p.name ASC, pc.date ASC, pc.id ASC",
+		r.buildOrderClause(utils.SortParams{Field: "cumulative_pdp", Order: "asc"}))
 }
 
-func TestProvinceCaseRepository_GetAllSorted_UnknownField(t *testing.T) {
+func TestProvinceCaseRepository_Find_AfterID(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
 		if err := db.Close(); err != nil {
 			t.Logf("Error closing db: %v", err)
 		}
 	}()
-	repo := NewProvinceCaseRepository(db)
-	now := time.Now()
 
-	rows := addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumns), "11", now)
-	mock.ExpectQuery(`SELECT pc\.id`).
-		WillReturnRows(rows)
+	date := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`LEFT JOIN provinces p ON pc\.province_id = p\.id\s+WHERE pc\.deleted_at IS NULL AND p\.deleted_at IS NULL AND pc\.id > \?\s+ORDER BY`).
+		WithArgs(int64(500)).
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "72", date))
+
+	cases, total, err := NewProvinceCaseRepository(db).Find(context.Background(), ProvinceCaseQuery{
+		AfterID: 500,
+		Sort:    utils.SortParams{Field: "date", Order: "asc"},
+	})
 
-	cases, err := repo.GetAllSorted(utils.SortParams{Field: "unknown_field", Order: "asc"})
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
+	assert.Equal(t, 1, total)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
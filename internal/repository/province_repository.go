@@ -1,16 +1,21 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/pkg/database"
 )
 
 type ProvinceRepository interface {
-	GetAll() ([]models.Province, error)
-	GetByID(id string) (*models.Province, error)
+	GetAll(ctx context.Context) ([]models.Province, error)
+	GetByID(ctx context.Context, id string) (*models.Province, error)
+	GetAllWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error)
+	GetByIDsWithLatestCase(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error)
 }
 
 type provinceRepository struct {
@@ -21,10 +26,10 @@ func NewProvinceRepository(db *database.DB) ProvinceRepository {
 	return &provinceRepository{db: db}
 }
 
-func (r *provinceRepository) GetAll() ([]models.Province, error) {
-	query := `SELECT id, name FROM provinces ORDER BY name`
+func (r *provinceRepository) GetAll(ctx context.Context) ([]models.Province, error) {
+	query := `SELECT id, name FROM provinces WHERE deleted_at IS NULL ORDER BY name`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query provinces: %w", err)
 	}
@@ -51,11 +56,11 @@ func (r *provinceRepository) GetAll() ([]models.Province, error) {
 	return provinces, nil
 }
 
-func (r *provinceRepository) GetByID(id string) (*models.Province, error) {
-	query := `SELECT id, name FROM provinces WHERE id = ?`
+func (r *provinceRepository) GetByID(ctx context.Context, id string) (*models.Province, error) {
+	query := `SELECT id, name FROM provinces WHERE id = ? AND deleted_at IS NULL`
 
 	var p models.Province
-	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.Name)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -65,3 +70,130 @@ func (r *provinceRepository) GetByID(id string) (*models.Province, error) {
 
 	return &p, nil
 }
+
+// GetAllWithLatestCase returns every province with its most recent case in a
+// single query. The latest case is picked per province by a correlated
+// subquery using the same date fallback as the province case queries;
+// provinces without cases have a nil LatestCase.
+func (r *provinceRepository) GetAllWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
+	return r.queryWithLatestCase(ctx, "")
+}
+
+// GetByIDsWithLatestCase is like GetAllWithLatestCase but limited to the
+// provinces with the given IDs. Unknown IDs are ignored.
+func (r *provinceRepository) GetByIDsWithLatestCase(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	if len(ids) == 0 {
+		return []models.ProvinceWithLatestCase{}, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return r.queryWithLatestCase(ctx, "AND p.id IN ("+placeholders+")", args...)
+}
+
+// queryWithLatestCase reads the provinces that are not soft-deleted and match
+// the conditions of where, which starts with AND
+func (r *provinceRepository) queryWithLatestCase(ctx context.Context, where string, args ...interface{}) ([]models.ProvinceWithLatestCase, error) {
+	query := `SELECT p.id, p.name,
+			  pc.id, pc.day, pc.positive, pc.recovered, pc.deceased,
+			  pc.person_under_observation, pc.finished_person_under_observation,
+			  pc.person_under_supervision, pc.finished_person_under_supervision,
+			  pc.cumulative_positive, pc.cumulative_recovered, pc.cumulative_deceased,
+			  pc.cumulative_person_under_observation, pc.cumulative_finished_person_under_observation,
+			  pc.cumulative_person_under_supervision, pc.cumulative_finished_person_under_supervision,
+			  pc.rt, pc.rt_upper, pc.rt_lower, COALESCE(nc.date, pc.date)
+			  FROM provinces p
+			  LEFT JOIN province_cases pc ON pc.id = (
+				  SELECT latest.id FROM province_cases latest
+				  WHERE latest.province_id = p.id AND latest.deleted_at IS NULL
+				  ORDER BY latest.date DESC, latest.id DESC
+				  LIMIT 1)
+			  LEFT JOIN national_cases nc ON pc.day = nc.id
+			  WHERE p.deleted_at IS NULL ` + where + `
+			  ORDER BY p.name`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provinces with latest case: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			fmt.Printf("Error closing rows: %v\n", err)
+		}
+	}()
+
+	var result []models.ProvinceWithLatestCase
+	for rows.Next() {
+		var p models.Province
+		var c models.ProvinceCaseWithDate
+		var caseID sql.NullInt64
+		var date sql.NullTime
+
+		// Every case column is NULL for provinces without cases
+		counts := []*int64{&c.Day, &c.Positive, &c.Recovered, &c.Deceased,
+			&c.PersonUnderObservation, &c.FinishedPersonUnderObservation,
+			&c.PersonUnderSupervision, &c.FinishedPersonUnderSupervision,
+			&c.CumulativePositive, &c.CumulativeRecovered, &c.CumulativeDeceased,
+			&c.CumulativePersonUnderObservation, &c.CumulativeFinishedPersonUnderObservation,
+			&c.CumulativePersonUnderSupervision, &c.CumulativeFinishedPersonUnderSupervision}
+		nullCounts := make([]sql.NullInt64, len(counts))
+
+		dest := []interface{}{&p.ID, &p.Name, &caseID}
+		for i := range nullCounts {
+			dest = append(dest, &nullCounts[i])
+		}
+		dest = append(dest, &c.Rt, &c.RtUpper, &c.RtLower, &date)
+		if err := rows.Scan(dest...); err != nil {
+			// Keep the other provinces when the case of one cannot be read
+			item, scanErr := scanProvinceWithoutCase(rows, len(dest))
+			if scanErr != nil {
+				return nil, fmt.Errorf("failed to scan province with latest case: %w", err)
+			}
+			log.Printf("Error loading the latest case of province %s: %v", item.ID, err)
+			item.DataUnavailable = true
+			item.Error = latestCaseUnavailable
+			result = append(result, item)
+			continue
+		}
+
+		item := models.ProvinceWithLatestCase{Province: p, DataUnavailable: !caseID.Valid}
+		if caseID.Valid {
+			c.ID = caseID.Int64
+			c.ProvinceID = p.ID
+			for i, count := range counts {
+				*count = nullCounts[i].Int64
+			}
+			if date.Valid {
+				c.Date = date.Time
+			}
+			// Without province information to avoid repeating it in every item
+			latest := c.TransformToResponseWithoutProvince()
+			item.LatestCase = &latest
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
+// latestCaseUnavailable is the error of provinces whose latest case could
+// not be read; the cause is logged
+const latestCaseUnavailable = "latest case could not be loaded"
+
+// scanProvinceWithoutCase rescans the current row of a province with latest
+// case query, reading only the province and discarding the case columns
+func scanProvinceWithoutCase(rows *sql.Rows, columns int) (models.ProvinceWithLatestCase, error) {
+	var item models.ProvinceWithLatestCase
+	dest := []interface{}{&item.ID, &item.Name}
+	for len(dest) < columns {
+		dest = append(dest, new(interface{}))
+	}
+	err := rows.Scan(dest...)
+	return item, err
+}
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProvinceRepository_GetAll(t *testing.T) {
@@ -23,10 +26,10 @@ func TestProvinceRepository_GetAll(t *testing.T) {
 		AddRow("72", "Sulawesi Tengah").
 		AddRow("31", "DKI Jakarta")
 
-	mock.ExpectQuery(`SELECT id, name FROM provinces ORDER BY name`).
+	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE deleted_at IS NULL ORDER BY name`).
 		WillReturnRows(rows)
 
-	provinces, err := repo.GetAll()
+	provinces, err := repo.GetAll(context.Background())
 
 	assert.NoError(t, err)
 	assert.Len(t, provinces, 3)
@@ -52,10 +55,10 @@ func TestProvinceRepository_GetAll_Empty(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{"id", "name"})
 
-	mock.ExpectQuery(`SELECT id, name FROM provinces ORDER BY name`).
+	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE deleted_at IS NULL ORDER BY name`).
 		WillReturnRows(rows)
 
-	provinces, err := repo.GetAll()
+	provinces, err := repo.GetAll(context.Background())
 
 	assert.NoError(t, err)
 	assert.Len(t, provinces, 0)
@@ -77,11 +80,11 @@ func TestProvinceRepository_GetByID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "name"}).
 		AddRow(provinceID, "Aceh")
 
-	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE id = \?`).
+	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE id = \? AND deleted_at IS NULL`).
 		WithArgs(provinceID).
 		WillReturnRows(rows)
 
-	province, err := repo.GetByID(provinceID)
+	province, err := repo.GetByID(context.Background(), provinceID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, province)
@@ -103,11 +106,11 @@ func TestProvinceRepository_GetByID_NotFound(t *testing.T) {
 
 	provinceID := "999"
 
-	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE id = \?`).
+	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE id = \? AND deleted_at IS NULL`).
 		WithArgs(provinceID).
 		WillReturnError(sql.ErrNoRows)
 
-	province, err := repo.GetByID(provinceID)
+	province, err := repo.GetByID(context.Background(), provinceID)
 
 	assert.NoError(t, err)
 	assert.Nil(t, province)
@@ -127,11 +130,11 @@ func TestProvinceRepository_GetByID_DatabaseError(t *testing.T) {
 
 	provinceID := "11"
 
-	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE id = \?`).
+	mock.ExpectQuery(`SELECT id, name FROM provinces WHERE id = \? AND deleted_at IS NULL`).
 		WithArgs(provinceID).
 		WillReturnError(sql.ErrConnDone)
 
-	province, err := repo.GetByID(provinceID)
+	province, err := repo.GetByID(context.Background(), provinceID)
 
 	assert.Error(t, err)
 	assert.Nil(t, province)
@@ -139,3 +142,154 @@ func TestProvinceRepository_GetByID_DatabaseError(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestProvinceRepository_GetAllWithLatestCase(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := NewProvinceRepository(db)
+
+	date := time.Date(2021, 8, 17, 0, 0, 0, 0, time.UTC)
+	rt := 0.9
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "case_id", "day", "positive", "recovered", "deceased",
+		"person_under_observation", "finished_person_under_observation",
+		"person_under_supervision", "finished_person_under_supervision",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+		"rt", "rt_upper", "rt_lower", "date",
+	}).
+		AddRow("11", "Aceh", 7, 512, 5, 4, 1, nil, nil, nil, nil, 900, 800, 30, nil, nil, nil, nil, nil, nil, nil, date).
+		AddRow("99", "Papua Selatan", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).
+		AddRow("72", "Sulawesi Tengah", 9, 512, 14, 20, 0, 3, 2, 1, 1, 12345, 11000, 321, 50, 40, 30, 20, rt, 1.1, 0.7, date)
+
+	mock.ExpectQuery(`FROM provinces p\s+LEFT JOIN province_cases pc ON pc\.id = \(`).
+		WillReturnRows(rows)
+
+	provinces, err := repo.GetAllWithLatestCase(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, provinces, 3)
+
+	assert.Equal(t, "Aceh", provinces[0].Name)
+	assert.NotNil(t, provinces[0].LatestCase)
+	assert.Equal(t, int64(512), provinces[0].LatestCase.Day)
+	assert.Equal(t, int64(900), provinces[0].LatestCase.Cumulative.Positive)
+	assert.Nil(t, provinces[0].LatestCase.Province)
+
+	assert.False(t, provinces[0].DataUnavailable)
+	assert.Equal(t, "99", provinces[1].ID)
+	assert.Nil(t, provinces[1].LatestCase)
+	assert.True(t, provinces[1].DataUnavailable)
+	assert.Empty(t, provinces[1].Error)
+
+	assert.Equal(t, date, provinces[2].LatestCase.Date)
+	assert.Equal(t, int64(1), provinces[2].LatestCase.Daily.ODP.Active)
+	assert.Equal(t, &rt, provinces[2].LatestCase.Statistics.ReproductionRate.Value)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceRepository_GetByIDsWithLatestCase(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	date := time.Date(2021, 8, 17, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "case_id", "day", "positive", "recovered", "deceased",
+		"person_under_observation", "finished_person_under_observation",
+		"person_under_supervision", "finished_person_under_supervision",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+		"rt", "rt_upper", "rt_lower", "date",
+	}).
+		AddRow("72", "Sulawesi Tengah", 9, 512, 14, 20, 0, 3, 2, 1, 1, 12345, 11000, 321, 50, 40, 30, 20, nil, nil, nil, date)
+
+	mock.ExpectQuery(`FROM provinces p\s+LEFT JOIN province_cases pc ON pc\.id = \(.*WHERE p\.deleted_at IS NULL AND p\.id IN \(\?, \?\)\s+ORDER BY p\.name`).
+		WithArgs("72", "31").
+		WillReturnRows(rows)
+
+	provinces, err := NewProvinceRepository(db).GetByIDsWithLatestCase(context.Background(), []string{"72", "31"})
+
+	assert.NoError(t, err)
+	assert.Len(t, provinces, 1)
+	assert.Equal(t, int64(12345), provinces[0].LatestCase.Cumulative.Positive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceRepository_GetByIDsWithLatestCase_NoIDs(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	provinces, err := NewProvinceRepository(db).GetByIDsWithLatestCase(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, provinces)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceRepository_GetAllWithLatestCase_UnreadableCase(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	date := time.Date(2021, 8, 17, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "case_id", "day", "positive", "recovered", "deceased",
+		"person_under_observation", "finished_person_under_observation",
+		"person_under_supervision", "finished_person_under_supervision",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"cumulative_person_under_observation", "cumulative_finished_person_under_observation",
+		"cumulative_person_under_supervision", "cumulative_finished_person_under_supervision",
+		"rt", "rt_upper", "rt_lower", "date",
+	}).
+		AddRow("11", "Aceh", 7, 512, "corrupt", 4, 1, nil, nil, nil, nil, 900, 800, 30, nil, nil, nil, nil, nil, nil, nil, date).
+		AddRow("72", "Sulawesi Tengah", 9, 512, 14, 20, 0, 3, 2, 1, 1, 12345, 11000, 321, 50, 40, 30, 20, nil, nil, nil, date)
+	mock.ExpectQuery(`FROM provinces p`).WillReturnRows(rows)
+
+	provinces, err := NewProvinceRepository(db).GetAllWithLatestCase(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, provinces, 2)
+	assert.Equal(t, "11", provinces[0].ID)
+	assert.Equal(t, "Aceh", provinces[0].Name)
+	assert.Nil(t, provinces[0].LatestCase)
+	assert.True(t, provinces[0].DataUnavailable)
+	assert.Equal(t, "latest case could not be loaded", provinces[0].Error)
+	assert.NotNil(t, provinces[1].LatestCase)
+	assert.Empty(t, provinces[1].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceRepository_GetAllWithLatestCase_Error(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	mock.ExpectQuery(`FROM provinces p`).WillReturnError(sql.ErrConnDone)
+
+	_, err := NewProvinceRepository(db).GetAllWithLatestCase(context.Background())
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"log"
 	"fmt"
 
@@ -10,10 +11,10 @@ import (
 
 // ProvinceStatsRepositoryInterface defines the contract for province stats repository operations
 type ProvinceStatsRepositoryInterface interface {
-	GetGenderCases(provinceID int) ([]models.ProvinceGenderCase, error)
-	GetLatestGenderCase(provinceID int) (*models.ProvinceGenderCase, error)
-	GetTests(provinceID int) ([]models.ProvinceTest, error)
-	GetTestTypes() ([]models.TestType, error)
+	GetGenderCases(ctx context.Context, provinceID int) ([]models.ProvinceGenderCase, error)
+	GetLatestGenderCase(ctx context.Context, provinceID int) (*models.ProvinceGenderCase, error)
+	GetTests(ctx context.Context, provinceID int) ([]models.ProvinceTest, error)
+	GetTestTypes(ctx context.Context) ([]models.TestType, error)
 }
 
 // ProvinceStatsRepository handles province gender cases and test data
@@ -26,7 +27,7 @@ func NewProvinceStatsRepository(db *database.DB) *ProvinceStatsRepository {
 }
 
 // GetGenderCases returns all gender/age-based case data for a province
-func (r *ProvinceStatsRepository) GetGenderCases(provinceID int) ([]models.ProvinceGenderCase, error) {
+func (r *ProvinceStatsRepository) GetGenderCases(ctx context.Context, provinceID int) ([]models.ProvinceGenderCase, error) {
 	query := `SELECT id, day, province_id,
 		positive_male, positive_female, pdp_male, pdp_female,
 		positive_male_0_14, positive_male_15_19, positive_male_20_24, positive_male_25_49, positive_male_50_54, positive_male_55,
@@ -35,7 +36,7 @@ func (r *ProvinceStatsRepository) GetGenderCases(provinceID int) ([]models.Provi
 		pdp_female_0_14, pdp_female_15_19, pdp_female_20_24, pdp_female_25_49, pdp_female_50_54, pdp_female_55
 		FROM province_gender_cases WHERE province_id = ? ORDER BY day ASC`
 
-	rows, err := r.db.Query(query, provinceID)
+	rows, err := r.db.QueryContext(ctx, query, provinceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query gender cases: %w", err)
 	}
@@ -63,7 +64,7 @@ func (r *ProvinceStatsRepository) GetGenderCases(provinceID int) ([]models.Provi
 }
 
 // GetLatestGenderCase returns the latest gender case for a province
-func (r *ProvinceStatsRepository) GetLatestGenderCase(provinceID int) (*models.ProvinceGenderCase, error) {
+func (r *ProvinceStatsRepository) GetLatestGenderCase(ctx context.Context, provinceID int) (*models.ProvinceGenderCase, error) {
 	query := `SELECT id, day, province_id,
 		positive_male, positive_female, pdp_male, pdp_female,
 		positive_male_0_14, positive_male_15_19, positive_male_20_24, positive_male_25_49, positive_male_50_54, positive_male_55,
@@ -73,7 +74,7 @@ func (r *ProvinceStatsRepository) GetLatestGenderCase(provinceID int) (*models.P
 		FROM province_gender_cases WHERE province_id = ? ORDER BY day DESC LIMIT 1`
 
 	var c models.ProvinceGenderCase
-	err := r.db.QueryRow(query, provinceID).Scan(&c.ID, &c.Day, &c.ProvinceID,
+	err := r.db.QueryRowContext(ctx, query, provinceID).Scan(&c.ID, &c.Day, &c.ProvinceID,
 		&c.PositiveMale, &c.PositiveFemale, &c.PDPMale, &c.PDPFemale,
 		&c.PositiveMale0_14, &c.PositiveMale15_19, &c.PositiveMale20_24, &c.PositiveMale25_49, &c.PositiveMale50_54, &c.PositiveMale55,
 		&c.PositiveFemale0_14, &c.PositiveFemale15_19, &c.PositiveFemale20_24, &c.PositiveFemale25_49, &c.PositiveFemale50_54, &c.PositiveFemale55,
@@ -87,7 +88,7 @@ func (r *ProvinceStatsRepository) GetLatestGenderCase(provinceID int) (*models.P
 }
 
 // GetTests returns all test data for a province with test type info
-func (r *ProvinceStatsRepository) GetTests(provinceID int) ([]models.ProvinceTest, error) {
+func (r *ProvinceStatsRepository) GetTests(ctx context.Context, provinceID int) ([]models.ProvinceTest, error) {
 	query := `SELECT pt.id, pt.test_type_id, pt.day, pt.province_id, pt.date_from,
 		pt.process, pt.invalid, pt.positive, pt.negative,
 		tt.id, tt.key, tt.name, tt.sample, tt.duration, tt.is_recommended
@@ -96,7 +97,7 @@ func (r *ProvinceStatsRepository) GetTests(provinceID int) ([]models.ProvinceTes
 		WHERE pt.province_id = ?
 		ORDER BY pt.day ASC, pt.test_type_id ASC`
 
-	rows, err := r.db.Query(query, provinceID)
+	rows, err := r.db.QueryContext(ctx, query, provinceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query province tests: %w", err)
 	}
@@ -123,10 +124,10 @@ func (r *ProvinceStatsRepository) GetTests(provinceID int) ([]models.ProvinceTes
 }
 
 // GetTestTypes returns all available test types
-func (r *ProvinceStatsRepository) GetTestTypes() ([]models.TestType, error) {
+func (r *ProvinceStatsRepository) GetTestTypes(ctx context.Context) ([]models.TestType, error) {
 	query := `SELECT id, ` + "`key`" + `, name, sample, duration, is_recommended FROM test_types ORDER BY id`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query test types: %w", err)
 	}
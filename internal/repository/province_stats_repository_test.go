@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"testing"
@@ -43,7 +44,7 @@ func TestProvinceStatsRepository_GetGenderCases(t *testing.T) {
 		WithArgs(72).
 		WillReturnRows(sqlmock.NewRows(genderCols).AddRow(genderRow()...))
 
-	cases, err := repo.GetGenderCases(72)
+	cases, err := repo.GetGenderCases(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, cases, 1)
 	assert.Equal(t, 72, cases[0].ProvinceID)
@@ -57,7 +58,7 @@ func TestProvinceStatsRepository_GetGenderCases_Empty(t *testing.T) {
 		WithArgs(72).
 		WillReturnRows(sqlmock.NewRows(genderCols))
 
-	cases, err := repo.GetGenderCases(72)
+	cases, err := repo.GetGenderCases(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Empty(t, cases)
 }
@@ -69,7 +70,7 @@ func TestProvinceStatsRepository_GetGenderCases_Error(t *testing.T) {
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetGenderCases(72)
+	_, err := repo.GetGenderCases(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -80,7 +81,7 @@ func TestProvinceStatsRepository_GetLatestGenderCase(t *testing.T) {
 		WithArgs(72).
 		WillReturnRows(sqlmock.NewRows(genderCols).AddRow(genderRow()...))
 
-	result, err := repo.GetLatestGenderCase(72)
+	result, err := repo.GetLatestGenderCase(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, 72, result.ProvinceID)
@@ -94,7 +95,7 @@ func TestProvinceStatsRepository_GetLatestGenderCase_Error(t *testing.T) {
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetLatestGenderCase(72)
+	_, err := repo.GetLatestGenderCase(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -106,7 +107,7 @@ func TestProvinceStatsRepository_GetTests(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows(provinceTestCols).
 			AddRow(1, 1, 100, 72, time.Now(), 50, 5, 30, 15, 1, "pcr", "PCR", "Swab", "1-2 hari", true))
 
-	tests, err := repo.GetTests(72)
+	tests, err := repo.GetTests(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, tests, 1)
 	assert.NotNil(t, tests[0].TestType)
@@ -121,7 +122,7 @@ func TestProvinceStatsRepository_GetTests_Error(t *testing.T) {
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetTests(72)
+	_, err := repo.GetTests(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -133,7 +134,7 @@ func TestProvinceStatsRepository_GetTestTypes(t *testing.T) {
 			AddRow(1, "pcr", "PCR", "Swab", "1-2 hari", true).
 			AddRow(2, "antigen", "Antigen", "Swab", "30 menit", false))
 
-	types, err := repo.GetTestTypes()
+	types, err := repo.GetTestTypes(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, types, 2)
 	assert.Equal(t, "pcr", types[0].Key)
@@ -146,6 +147,6 @@ func TestProvinceStatsRepository_GetTestTypes_Error(t *testing.T) {
 	mock.ExpectQuery(`SELECT id`).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetTestTypes()
+	_, err := repo.GetTestTypes(context.Background())
 	assert.Error(t, err)
 }
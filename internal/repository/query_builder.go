@@ -0,0 +1,116 @@
+package repository
+
+import "strings"
+
+// selectBuilder assembles a SELECT from a fixed column list and FROM clause
+// plus the conditions, order and page of one request, so a repository has
+// one query path per table instead of a method per combination of filters
+type selectBuilder struct {
+	columns    string
+	from       string
+	conditions []string
+	args       []interface{}
+	order      string
+	limit      int
+	offset     int
+}
+
+// newSelect starts a query of columns from from, which holds the table and
+// its joins
+func newSelect(columns, from string) *selectBuilder {
+	return &selectBuilder{columns: columns, from: from}
+}
+
+// where adds a condition with its placeholder arguments; conditions are
+// joined with AND
+func (b *selectBuilder) where(condition string, args ...interface{}) *selectBuilder {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// orderBy sets the ORDER BY clause, without the keywords
+func (b *selectBuilder) orderBy(clause string) *selectBuilder {
+	b.order = clause
+	return b
+}
+
+// page limits the rows to limit starting at offset; all rows when limit is 0
+func (b *selectBuilder) page(limit, offset int) *selectBuilder {
+	b.limit, b.offset = limit, offset
+	return b
+}
+
+func (b *selectBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// build returns the query and its arguments
+func (b *selectBuilder) build() (string, []interface{}) {
+	query := "SELECT " + b.columns + " FROM " + b.from + b.whereClause()
+	args := append([]interface{}{}, b.args...)
+	if b.order != "" {
+		query += " ORDER BY " + b.order
+	}
+	if b.limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, b.limit, b.offset)
+	}
+	return query, args
+}
+
+// buildCount returns the query counting the rows matching the conditions
+// across all pages, and its arguments
+func (b *selectBuilder) buildCount() (string, []interface{}) {
+	return "SELECT COUNT(*) FROM " + b.from + b.whereClause(), append([]interface{}{}, b.args...)
+}
+
+// column is a column of rows of type T: its name in a query's Columns, the
+// expression selecting it and where it is scanned. Key columns are always
+// read, as paging, annotations and the data freshness depend on them.
+type column[T any] struct {
+	name string
+	expr string
+	key  bool
+	dest func(row *T) interface{}
+}
+
+// pickColumns returns the key columns of all and those named, in the order of
+// all; every column when names is empty. Unknown names are ignored.
+func pickColumns[T any](all []column[T], names []string) []column[T] {
+	if len(names) == 0 {
+		return all
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var picked []column[T]
+	for _, c := range all {
+		if c.key || wanted[c.name] {
+			picked = append(picked, c)
+		}
+	}
+	return picked
+}
+
+// selectList returns the SELECT list of columns
+func selectList[T any](columns []column[T]) string {
+	exprs := make([]string, len(columns))
+	for i, c := range columns {
+		exprs[i] = c.expr
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// scanDest returns where the columns are scanned into row, in order
+func scanDest[T any](columns []column[T], row *T) []interface{} {
+	dest := make([]interface{}, len(columns))
+	for i, c := range columns {
+		dest[i] = c.dest(row)
+	}
+	return dest
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilder_Build(t *testing.T) {
+	query, args := newSelect("id, day", "national_cases").build()
+	assert.Equal(t, "SELECT id, day FROM national_cases", query)
+	assert.Empty(t, args)
+
+	b := newSelect("id, day", "national_cases").
+		where("date BETWEEN ? AND ?", "2021-01-01", "2021-01-31").
+		where("rt IS NOT NULL").
+		orderBy("date DESC").
+		page(10, 20)
+
+	query, args = b.build()
+	assert.Equal(t, "SELECT id, day FROM national_cases WHERE date BETWEEN ? AND ? AND rt IS NOT NULL ORDER BY date DESC LIMIT ? OFFSET ?", query)
+	assert.Equal(t, []interface{}{"2021-01-01", "2021-01-31", 10, 20}, args)
+
+	query, args = b.buildCount()
+	assert.Equal(t, "SELECT COUNT(*) FROM national_cases WHERE date BETWEEN ? AND ? AND rt IS NOT NULL", query)
+	assert.Equal(t, []interface{}{"2021-01-01", "2021-01-31"}, args)
+}
+
+func TestSelectBuilder_BuildDoesNotShareArgs(t *testing.T) {
+	b := newSelect("id", "national_cases").where("day = ?", 1).page(5, 0)
+
+	_, args := b.build()
+	_, countArgs := b.buildCount()
+	countArgs[0] = 2
+
+	assert.Equal(t, []interface{}{1, 5, 0}, args)
+	_, again := b.build()
+	assert.Equal(t, args, again)
+}
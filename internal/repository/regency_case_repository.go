@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"log"
 	"fmt"
 
@@ -10,8 +11,8 @@ import (
 
 // RegencyCaseRepositoryInterface defines the contract for regency case repository operations
 type RegencyCaseRepositoryInterface interface {
-	GetByRegencyID(regencyID int) ([]models.RegencyCase, error)
-	GetLatestByProvinceID(provinceID int) ([]models.RegencyCase, error)
+	GetByRegencyID(ctx context.Context, regencyID int) ([]models.RegencyCase, error)
+	GetLatestByProvinceID(ctx context.Context, provinceID int) ([]models.RegencyCase, error)
 }
 
 // RegencyCaseRepository handles database operations for regency cases
@@ -25,7 +26,7 @@ func NewRegencyCaseRepository(db *database.DB) *RegencyCaseRepository {
 }
 
 // GetByRegencyID returns all cases for a specific regency
-func (r *RegencyCaseRepository) GetByRegencyID(regencyID int) ([]models.RegencyCase, error) {
+func (r *RegencyCaseRepository) GetByRegencyID(ctx context.Context, regencyID int) ([]models.RegencyCase, error) {
 	query := `SELECT rc.id, rc.day, rc.regency_id, rc.positive, rc.recovered, rc.deceased,
 		rc.person_under_observation, rc.finished_person_under_observation,
 		rc.person_under_supervision, rc.finished_person_under_supervision,
@@ -40,7 +41,7 @@ func (r *RegencyCaseRepository) GetByRegencyID(regencyID int) ([]models.RegencyC
 		WHERE rc.regency_id = ?
 		ORDER BY rc.day ASC`
 
-	rows, err := r.db.Query(query, regencyID)
+	rows, err := r.db.QueryContext(ctx, query, regencyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query regency cases: %w", err)
 	}
@@ -74,7 +75,7 @@ func (r *RegencyCaseRepository) GetByRegencyID(regencyID int) ([]models.RegencyC
 }
 
 // GetLatestByProvinceID returns the latest case for each regency in a province
-func (r *RegencyCaseRepository) GetLatestByProvinceID(provinceID int) ([]models.RegencyCase, error) {
+func (r *RegencyCaseRepository) GetLatestByProvinceID(ctx context.Context, provinceID int) ([]models.RegencyCase, error) {
 	query := `SELECT rc.id, rc.day, rc.regency_id, rc.positive, rc.recovered, rc.deceased,
 		rc.cumulative_positive, rc.cumulative_recovered, rc.cumulative_deceased,
 		rc.person_under_observation, rc.finished_person_under_observation,
@@ -91,7 +92,7 @@ func (r *RegencyCaseRepository) GetLatestByProvinceID(provinceID int) ([]models.
 		ORDER BY reg.name`
 
 	likeParam := fmt.Sprintf("%d%%", provinceID)
-	rows, err := r.db.Query(query, likeParam)
+	rows, err := r.db.QueryContext(ctx, query, likeParam)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query latest regency cases: %w", err)
 	}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"testing"
@@ -68,7 +69,7 @@ func TestRegencyCaseRepository_GetByRegencyID(t *testing.T) {
 		WithArgs(7201).
 		WillReturnRows(sqlmock.NewRows(regencyCaseCols).AddRow(regencyCaseRow(7201)...))
 
-	result, err := repo.GetByRegencyID(7201)
+	result, err := repo.GetByRegencyID(context.Background(), 7201)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Equal(t, 7201, result[0].RegencyID)
@@ -82,7 +83,7 @@ func TestRegencyCaseRepository_GetByRegencyID_Error(t *testing.T) {
 		WithArgs(7201).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetByRegencyID(7201)
+	_, err := repo.GetByRegencyID(context.Background(), 7201)
 	assert.Error(t, err)
 }
 
@@ -93,7 +94,7 @@ func TestRegencyCaseRepository_GetByRegencyID_Empty(t *testing.T) {
 		WithArgs(9999).
 		WillReturnRows(sqlmock.NewRows(regencyCaseCols))
 
-	result, err := repo.GetByRegencyID(9999)
+	result, err := repo.GetByRegencyID(context.Background(), 9999)
 	assert.NoError(t, err)
 	assert.Nil(t, result)
 }
@@ -105,7 +106,7 @@ func TestRegencyCaseRepository_GetLatestByProvinceID(t *testing.T) {
 		WithArgs("72%").
 		WillReturnRows(sqlmock.NewRows(regencyCaseLatestCols).AddRow(regencyCaseLatestRow(7201)...))
 
-	result, err := repo.GetLatestByProvinceID(72)
+	result, err := repo.GetLatestByProvinceID(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -118,6 +119,6 @@ func TestRegencyCaseRepository_GetLatestByProvinceID_Error(t *testing.T) {
 		WithArgs("72%").
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetLatestByProvinceID(72)
+	_, err := repo.GetLatestByProvinceID(context.Background(), 72)
 	assert.Error(t, err)
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"log"
 	"database/sql"
 	"fmt"
@@ -11,9 +12,9 @@ import (
 
 // RegencyRepositoryInterface defines the contract for regency repository operations
 type RegencyRepositoryInterface interface {
-	GetAll(provinceID int) ([]models.Regency, error)
-	GetPaginated(provinceID, limit, offset int) ([]models.Regency, int, error)
-	GetByID(id int) (*models.Regency, error)
+	GetAll(ctx context.Context, provinceID int) ([]models.Regency, error)
+	GetPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.Regency, int, error)
+	GetByID(ctx context.Context, id int) (*models.Regency, error)
 }
 
 // RegencyRepository handles database operations for regencies
@@ -27,10 +28,10 @@ func NewRegencyRepository(db *database.DB) *RegencyRepository {
 }
 
 // GetAll returns all regencies for a province
-func (r *RegencyRepository) GetAll(provinceID int) ([]models.Regency, error) {
+func (r *RegencyRepository) GetAll(ctx context.Context, provinceID int) ([]models.Regency, error) {
 	query := `SELECT id, province_id, name, created_at, updated_at FROM regencies WHERE province_id = ? ORDER BY name`
 
-	rows, err := r.db.Query(query, provinceID)
+	rows, err := r.db.QueryContext(ctx, query, provinceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query regencies: %w", err)
 	}
@@ -52,14 +53,14 @@ func (r *RegencyRepository) GetAll(provinceID int) ([]models.Regency, error) {
 }
 
 // GetPaginated returns a page of regencies with total count
-func (r *RegencyRepository) GetPaginated(provinceID, limit, offset int) ([]models.Regency, int, error) {
+func (r *RegencyRepository) GetPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.Regency, int, error) {
 	var total int
-	if err := r.db.QueryRow(`SELECT COUNT(*) FROM regencies WHERE province_id = ?`, provinceID).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM regencies WHERE province_id = ?`, provinceID).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to count regencies: %w", err)
 	}
 
 	query := `SELECT id, province_id, name, created_at, updated_at FROM regencies WHERE province_id = ? ORDER BY name LIMIT ? OFFSET ?`
-	rows, err := r.db.Query(query, provinceID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, provinceID, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query regencies: %w", err)
 	}
@@ -81,11 +82,11 @@ func (r *RegencyRepository) GetPaginated(provinceID, limit, offset int) ([]model
 }
 
 // GetByID returns a single regency by ID
-func (r *RegencyRepository) GetByID(id int) (*models.Regency, error) {
+func (r *RegencyRepository) GetByID(ctx context.Context, id int) (*models.Regency, error) {
 	query := `SELECT id, province_id, name, created_at, updated_at FROM regencies WHERE id = ?`
 
 	var reg models.Regency
-	err := r.db.QueryRow(query, id).Scan(&reg.ID, &reg.ProvinceID, &reg.Name, &reg.CreatedAt, &reg.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&reg.ID, &reg.ProvinceID, &reg.Name, &reg.CreatedAt, &reg.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"testing"
@@ -29,7 +30,7 @@ func TestRegencyRepository_GetAll(t *testing.T) {
 		WithArgs(72).
 		WillReturnRows(sqlmock.NewRows(regencyCols).AddRow(regencyRow()...))
 
-	result, err := repo.GetAll(72)
+	result, err := repo.GetAll(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Equal(t, "Kabupaten Banggai", result[0].Name)
@@ -43,7 +44,7 @@ func TestRegencyRepository_GetAll_Error(t *testing.T) {
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetAll(72)
+	_, err := repo.GetAll(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -58,7 +59,7 @@ func TestRegencyRepository_GetPaginated(t *testing.T) {
 		WithArgs(72, 10, 0).
 		WillReturnRows(sqlmock.NewRows(regencyCols).AddRow(regencyRow()...))
 
-	result, total, err := repo.GetPaginated(72, 10, 0)
+	result, total, err := repo.GetPaginated(context.Background(), 72, 10, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
 	assert.Len(t, result, 1)
@@ -72,7 +73,7 @@ func TestRegencyRepository_GetPaginated_CountError(t *testing.T) {
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetPaginated(72, 10, 0)
+	_, _, err := repo.GetPaginated(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
 
@@ -87,7 +88,7 @@ func TestRegencyRepository_GetPaginated_QueryError(t *testing.T) {
 		WithArgs(72, 10, 0).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetPaginated(72, 10, 0)
+	_, _, err := repo.GetPaginated(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
 
@@ -98,7 +99,7 @@ func TestRegencyRepository_GetByID(t *testing.T) {
 		WithArgs(7201).
 		WillReturnRows(sqlmock.NewRows(regencyCols).AddRow(regencyRow()...))
 
-	result, err := repo.GetByID(7201)
+	result, err := repo.GetByID(context.Background(), 7201)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "Kabupaten Banggai", result.Name)
@@ -112,7 +113,7 @@ func TestRegencyRepository_GetByID_NotFound(t *testing.T) {
 		WithArgs(9999).
 		WillReturnRows(sqlmock.NewRows(regencyCols))
 
-	result, err := repo.GetByID(9999)
+	result, err := repo.GetByID(context.Background(), 9999)
 	assert.NoError(t, err)
 	assert.Nil(t, result)
 }
@@ -124,6 +125,6 @@ func TestRegencyRepository_GetByID_Error(t *testing.T) {
 		WithArgs(7201).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetByID(7201)
+	_, err := repo.GetByID(context.Background(), 7201)
 	assert.Error(t, err)
 }
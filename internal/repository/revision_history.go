@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// rowStatesAsOf returns the rows of table that changed after asOf, by row ID,
+// with their values as they were at asOf. A nil snapshot is a row that did not
+// exist at asOf. Rows missing from the map have not changed since.
+//
+// A revision is in effect from its creation until its run is rolled back.
+// Rollbacks undo the latest runs first, so a row was at the values written by
+// its latest revision in effect at asOf, or at the values its first revision
+// replaced when none was.
+func rowStatesAsOf(ctx context.Context, exec sqlExecutor, table string, asOf time.Time) (map[int64]map[string]interface{}, error) {
+	rows, err := exec.QueryContext(ctx, `SELECT r.row_id, r.before_data, r.after_data, r.created_at, s.rolled_back_at
+		FROM revisions r LEFT JOIN sync_log s ON r.sync_log_id = s.id
+		WHERE r.table_name = ? AND r.row_id IN (
+			SELECT changed.row_id FROM revisions changed LEFT JOIN sync_log cs ON changed.sync_log_id = cs.id
+			WHERE changed.table_name = ? AND (changed.created_at > ? OR cs.rolled_back_at > ?))
+		ORDER BY r.row_id, r.id`, table, table, asOf, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s revisions: %w", table, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	states := make(map[int64]map[string]interface{})
+	for rows.Next() {
+		var rowID int64
+		var before, after []byte
+		var createdAt time.Time
+		var rolledBackAt sql.NullTime
+		if err := rows.Scan(&rowID, &before, &after, &createdAt, &rolledBackAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+
+		_, seen := states[rowID]
+		if !seen {
+			if states[rowID], err = unmarshalRowSnapshot(before); err != nil {
+				return nil, err
+			}
+		}
+		if createdAt.After(asOf) || (rolledBackAt.Valid && !rolledBackAt.Time.After(asOf)) {
+			continue
+		}
+		if states[rowID], err = unmarshalRowSnapshot(after); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return states, nil
+}
+
+// casesAsOf returns every national case as it was at asOf. Cases soft-deleted
+// after asOf are still read: their deletion is a revision undone by the rebuild.
+func (r *nationalCaseRepository) casesAsOf(ctx context.Context, asOf time.Time) ([]models.NationalCase, error) {
+	query, args := newSelect(selectList(nationalCaseColumns), "national_cases").
+		where("(deleted_at IS NULL OR deleted_at > ?)", asOf).
+		orderBy("id").
+		build()
+	current, err := r.queryNationalCases(ctx, nationalCaseColumns, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	states, err := rowStatesAsOf(ctx, r.db, "national_cases", asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]models.NationalCase, 0, len(current))
+	for _, c := range current {
+		if _, changed := states[c.ID]; !changed {
+			cases = append(cases, c)
+		}
+	}
+	for id, data := range states {
+		if data == nil {
+			continue
+		}
+		c, err := nationalCaseFromSnapshot(id, data)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// findAsOf answers Find with the cases as they were at q.AsOf. The cases are
+// filtered, sorted by date and paged in memory.
+func (r *nationalCaseRepository) findAsOf(ctx context.Context, q NationalCaseQuery) ([]models.NationalCase, int, error) {
+	all, err := r.casesAsOf(ctx, q.AsOf)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cases := all[:0]
+	for _, c := range all {
+		if withinDates(c.Date, q.StartDate, q.EndDate) && c.ID > q.AfterID {
+			cases = append(cases, c)
+		}
+	}
+	desc := q.Sort.Order == "desc"
+	sort.Slice(cases, func(i, j int) bool {
+		if !cases[i].Date.Equal(cases[j].Date) {
+			return cases[i].Date.Before(cases[j].Date) != desc
+		}
+		return (cases[i].ID < cases[j].ID) != desc
+	})
+	start, end := pageBounds(len(cases), q.Limit, q.Offset)
+	return cases[start:end], len(cases), nil
+}
+
+// findAsOf answers Find with the cases as they were at q.AsOf. The cases are
+// filtered, sorted by date and paged in memory.
+func (r *provinceCaseRepository) findAsOf(ctx context.Context, q ProvinceCaseQuery) ([]models.ProvinceCaseWithDate, int, error) {
+	b := newSelect(selectList(provinceCaseColumns), provinceCaseFrom).
+		where("(pc.deleted_at IS NULL OR pc.deleted_at > ?)", q.AsOf).
+		where("p.deleted_at IS NULL").
+		orderBy("pc.id")
+	filterProvinceCases(b, q.ProvinceID, time.Time{}, time.Time{}, false)
+	query, args := b.build()
+	current, err := r.queryProvinceCases(ctx, provinceCaseColumns, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	states, err := rowStatesAsOf(ctx, r.db, "province_cases", q.AsOf)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	all := make([]models.ProvinceCaseWithDate, 0, len(current))
+	byID := make(map[int64]models.ProvinceCaseWithDate, len(current))
+	provinces := make(map[string]*models.Province)
+	for _, c := range current {
+		byID[c.ID] = c
+		if c.Province != nil {
+			provinces[c.ProvinceID] = c.Province
+		}
+		if _, changed := states[c.ID]; !changed {
+			all = append(all, c)
+		}
+	}
+
+	// Rows deleted since keep their date through the national case of their day
+	var nationalDates map[int64]time.Time
+	var deletedProvinces map[string]bool
+	for id, data := range states {
+		if data == nil {
+			continue
+		}
+		pc, err := provinceCaseFromSnapshot(id, data)
+		if err != nil {
+			return nil, 0, err
+		}
+		if q.ProvinceID != "" && pc.ProvinceID != q.ProvinceID {
+			continue
+		}
+		// Provinces have no revisions, so a soft-deleted province hides its
+		// cases at every time
+		if provinces[pc.ProvinceID] == nil {
+			if deletedProvinces == nil {
+				if deletedProvinces, err = r.deletedProvinceIDs(ctx); err != nil {
+					return nil, 0, err
+				}
+			}
+			if deletedProvinces[pc.ProvinceID] {
+				continue
+			}
+		}
+		c := models.ProvinceCaseWithDate{ProvinceCase: pc}
+		if stored, ok := byID[id]; ok {
+			c.Date = stored.Date
+		} else {
+			if nationalDates == nil {
+				if nationalDates, err = r.nationalDatesAsOf(ctx, q.AsOf); err != nil {
+					return nil, 0, err
+				}
+			}
+			c.Date = nationalDates[pc.Day]
+		}
+		c.Province = provinces[pc.ProvinceID]
+		all = append(all, c)
+	}
+
+	cases := all[:0]
+	for _, c := range all {
+		if withinDates(c.Date, q.StartDate, q.EndDate) && (!q.HasRt || c.Rt != nil) && c.ID > q.AfterID {
+			cases = append(cases, c)
+		}
+	}
+	desc := q.Sort.Order == "desc"
+	sort.Slice(cases, func(i, j int) bool {
+		if !cases[i].Date.Equal(cases[j].Date) {
+			return cases[i].Date.Before(cases[j].Date) != desc
+		}
+		if ni, nj := provinceName(cases[i].Province), provinceName(cases[j].Province); ni != nj {
+			return ni < nj
+		}
+		return (cases[i].ID < cases[j].ID) != desc
+	})
+	start, end := pageBounds(len(cases), q.Limit, q.Offset)
+	return cases[start:end], len(cases), nil
+}
+
+// nationalDatesAsOf returns the dates of the national cases at asOf by ID,
+// which province cases reference as their day
+func (r *provinceCaseRepository) nationalDatesAsOf(ctx context.Context, asOf time.Time) (map[int64]time.Time, error) {
+	national, err := (&nationalCaseRepository{db: r.db}).casesAsOf(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	dates := make(map[int64]time.Time, len(national))
+	for _, c := range national {
+		dates[c.ID] = c.Date
+	}
+	return dates, nil
+}
+
+// deletedProvinceIDs returns the IDs of the soft-deleted provinces
+func (r *provinceCaseRepository) deletedProvinceIDs(ctx context.Context) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM provinces WHERE deleted_at IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted provinces: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted province: %w", err)
+		}
+		ids[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return ids, nil
+}
+
+func provinceName(p *models.Province) string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+// withinDates reports whether date lies between start and end, inclusive.
+// Every date does when either bound is unset.
+func withinDates(date, start, end time.Time) bool {
+	if start.IsZero() || end.IsZero() {
+		return true
+	}
+	return !date.Before(start) && !date.After(end)
+}
+
+// pageBounds returns the slice bounds of a page of n rows; all rows when limit is 0
+func pageBounds(n, limit, offset int) (int, int) {
+	start := min(max(offset, 0), n)
+	if limit <= 0 {
+		return start, n
+	}
+	return start, min(start+limit, n)
+}
+
+// nationalCaseFromSnapshot rebuilds a national case from a revision snapshot
+func nationalCaseFromSnapshot(id int64, data map[string]interface{}) (models.NationalCase, error) {
+	s := rowSnapshot(data)
+	date, err := time.Parse("2006-01-02", s.string("date"))
+	if err != nil {
+		return models.NationalCase{}, fmt.Errorf("national_cases row %d revision has invalid date: %w", id, err)
+	}
+	return models.NationalCase{
+		ID:                  id,
+		Day:                 s.int64("day"),
+		Date:                date,
+		Positive:            s.int64("positive"),
+		Recovered:           s.int64("recovered"),
+		Deceased:            s.int64("deceased"),
+		CumulativePositive:  s.int64("cumulative_positive"),
+		CumulativeRecovered: s.int64("cumulative_recovered"),
+		CumulativeDeceased:  s.int64("cumulative_deceased"),
+		Rt:                  s.float64Ptr("rt"),
+		RtUpper:             s.float64Ptr("rt_upper"),
+		RtLower:             s.float64Ptr("rt_lower"),
+	}, nil
+}
+
+// provinceCaseFromSnapshot rebuilds a province case from a revision snapshot
+func provinceCaseFromSnapshot(id int64, data map[string]interface{}) (models.ProvinceCase, error) {
+	s := rowSnapshot(data)
+	provinceID := s.string("province_id")
+	if provinceID == "" {
+		return models.ProvinceCase{}, fmt.Errorf("province_cases row %d revision has no province_id", id)
+	}
+	return models.ProvinceCase{
+		ID:                                       id,
+		Day:                                      s.int64("day"),
+		ProvinceID:                               provinceID,
+		Positive:                                 s.int64("positive"),
+		Recovered:                                s.int64("recovered"),
+		Deceased:                                 s.int64("deceased"),
+		PersonUnderObservation:                   s.int64("person_under_observation"),
+		FinishedPersonUnderObservation:           s.int64("finished_person_under_observation"),
+		PersonUnderSupervision:                   s.int64("person_under_supervision"),
+		FinishedPersonUnderSupervision:           s.int64("finished_person_under_supervision"),
+		CumulativePositive:                       s.int64("cumulative_positive"),
+		CumulativeRecovered:                      s.int64("cumulative_recovered"),
+		CumulativeDeceased:                       s.int64("cumulative_deceased"),
+		CumulativePersonUnderObservation:         s.int64("cumulative_person_under_observation"),
+		CumulativeFinishedPersonUnderObservation: s.int64("cumulative_finished_person_under_observation"),
+		CumulativePersonUnderSupervision:         s.int64("cumulative_person_under_supervision"),
+		CumulativeFinishedPersonUnderSupervision: s.int64("cumulative_finished_person_under_supervision"),
+		Rt:                                       s.float64Ptr("rt"),
+		RtUpper:                                  s.float64Ptr("rt_upper"),
+		RtLower:                                  s.float64Ptr("rt_lower"),
+	}, nil
+}
+
+// rowSnapshot reads the values of a decoded revision snapshot, where whole
+// numbers are int64 and other numbers float64
+type rowSnapshot map[string]interface{}
+
+func (s rowSnapshot) int64(column string) int64 {
+	switch v := s[column].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func (s rowSnapshot) float64Ptr(column string) *float64 {
+	switch v := s[column].(type) {
+	case int64:
+		f := float64(v)
+		return &f
+	case float64:
+		return &v
+	}
+	return nil
+}
+
+func (s rowSnapshot) string(column string) string {
+	v, _ := s[column].(string)
+	return v
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var rowStateColumns = []string{"row_id", "before_data", "after_data", "created_at", "rolled_back_at"}
+
+func TestRowStatesAsOf(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+
+	asOf := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	before, after := asOf.Add(-time.Hour), asOf.Add(time.Hour)
+	mock.ExpectQuery(`SELECT r.row_id, r.before_data, r.after_data, r.created_at, s.rolled_back_at\s+FROM revisions r`).
+		WithArgs("national_cases", "national_cases", asOf, asOf).
+		WillReturnRows(sqlmock.NewRows(rowStateColumns).
+			// Inserted before, corrected after
+			AddRow(1, nil, `{"positive": 10}`, before, nil).
+			AddRow(1, `{"positive": 10}`, `{"positive": 12}`, after, nil).
+			// Inserted after
+			AddRow(2, nil, `{"positive": 20}`, after, nil).
+			// Corrected by a run rolled back before, corrected again after
+			AddRow(3, `{"positive": 30}`, `{"positive": 31}`, before.Add(-time.Hour), before).
+			AddRow(3, `{"positive": 30}`, `{"positive": 32}`, after, nil).
+			// Inserted by a run rolled back after
+			AddRow(4, nil, `{"positive": 40}`, before, after))
+
+	states, err := rowStatesAsOf(context.Background(), db, "national_cases", asOf)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"positive": int64(10)}, states[1])
+	assert.Contains(t, states, int64(2))
+	assert.Nil(t, states[2])
+	assert.Equal(t, map[string]interface{}{"positive": int64(30)}, states[3])
+	assert.Equal(t, map[string]interface{}{"positive": int64(40)}, states[4])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNationalCaseRepository_Find_AsOf(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewNationalCaseRepository(db)
+
+	asOf := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2021, 7, d, 0, 0, 0, 0, time.UTC) }
+	columns := []string{
+		"id", "day", "date", "positive", "recovered", "deceased",
+		"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+		"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+	}
+	mock.ExpectQuery(`SELECT id, day, date.* FROM national_cases WHERE \(deleted_at IS NULL OR deleted_at > \?\) ORDER BY id$`).
+		WithArgs(asOf).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, 1, day(1), 120, 0, 0, 120, 0, 0, nil, nil, nil, nil, nil).
+			AddRow(2, 2, day(2), 50, 0, 0, 170, 0, 0, nil, nil, nil, nil, nil).
+			AddRow(4, 4, day(4), 60, 0, 0, 260, 0, 0, nil, nil, nil, nil, nil))
+	mock.ExpectQuery(`FROM revisions r`).
+		WillReturnRows(sqlmock.NewRows(rowStateColumns).
+			// Row 1 was corrected from 100 to 120 after asOf
+			AddRow(1, nil, `{"day": 1, "date": "2021-07-01", "positive": 100, "cumulative_positive": 100, "rt": 1}`, asOf.Add(-time.Hour), nil).
+			AddRow(1, `{"day": 1, "date": "2021-07-01", "positive": 100, "cumulative_positive": 100, "rt": 1}`, `{"day": 1, "date": "2021-07-01", "positive": 120}`, asOf.Add(time.Hour), nil).
+			// Row 3 was rolled back after asOf
+			AddRow(3, nil, `{"day": 3, "date": "2021-07-03", "positive": 30, "rt_upper": 1.25}`, asOf.Add(-time.Hour), asOf.Add(time.Hour)).
+			// Row 4 was published after asOf
+			AddRow(4, nil, `{"day": 4, "date": "2021-07-04", "positive": 60}`, asOf.Add(time.Hour), nil))
+
+	cases, total, err := repo.Find(context.Background(), NationalCaseQuery{
+		AsOf:  asOf,
+		Sort:  utils.SortParams{Field: "date", Order: "desc"},
+		Limit: 2,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, cases, 2)
+	assert.Equal(t, int64(3), cases[0].ID)
+	assert.Equal(t, day(3), cases[0].Date)
+	assert.Equal(t, 1.25, *cases[0].RtUpper)
+	assert.Equal(t, int64(2), cases[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProvinceCaseRepository_Find_AsOf(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewProvinceCaseRepository(db)
+
+	asOf := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT pc.id.* WHERE \(pc.deleted_at IS NULL OR pc.deleted_at > \?\) AND p.deleted_at IS NULL AND pc.province_id = \? ORDER BY pc.id$`).
+		WithArgs(asOf, "11").
+		WillReturnRows(addProvinceCaseRow(sqlmock.NewRows(provinceCaseColumnNames), "11", now))
+	mock.ExpectQuery(`FROM revisions r`).
+		WithArgs("province_cases", "province_cases", asOf, asOf).
+		WillReturnRows(sqlmock.NewRows(rowStateColumns).
+			// Row 1 was corrected after asOf
+			AddRow(1, `{"day": 1, "province_id": "11", "positive": 45}`, `{"day": 1, "province_id": "11", "positive": 50}`, asOf.Add(time.Hour), nil).
+			// Row 2 of another province was published after asOf
+			AddRow(2, nil, `{"day": 1, "province_id": "31", "positive": 7}`, asOf.Add(time.Hour), nil).
+			// Row 3 was rolled back after asOf
+			AddRow(3, nil, `{"day": 2, "province_id": "11", "positive": 9}`, asOf.Add(-time.Hour), asOf.Add(time.Hour)))
+	// The rolled back row takes the date of its national case
+	mock.ExpectQuery(`SELECT id, day, date.* FROM national_cases WHERE \(deleted_at IS NULL OR deleted_at > \?\) ORDER BY id$`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "day", "date", "positive", "recovered", "deceased",
+			"cumulative_positive", "cumulative_recovered", "cumulative_deceased",
+			"rt", "rt_upper", "rt_lower", "created_at", "updated_at",
+		}).AddRow(2, 2, now.AddDate(0, 0, 1), 1, 0, 0, 1, 0, 0, nil, nil, nil, nil, nil))
+	mock.ExpectQuery(`FROM revisions r`).
+		WithArgs("national_cases", "national_cases", asOf, asOf).
+		WillReturnRows(sqlmock.NewRows(rowStateColumns))
+
+	cases, total, err := repo.Find(context.Background(), ProvinceCaseQuery{ProvinceID: "11", AsOf: asOf})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, cases, 2)
+	assert.Equal(t, int64(45), cases[0].Positive)
+	assert.Equal(t, now, cases[0].Date)
+	assert.Equal(t, int64(3), cases[1].ID)
+	assert.Equal(t, now.AddDate(0, 0, 1), cases[1].Date)
+	assert.Equal(t, "Aceh", cases[1].Province.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
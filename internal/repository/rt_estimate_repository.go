@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// RtEstimateRepository reads the daily case series Rt is estimated from and
+// writes the estimates to the rt, rt_upper and rt_lower columns
+type RtEstimateRepository interface {
+	// GetCaseSeries returns the national series followed by the series of
+	// every province with case rows
+	GetCaseSeries(ctx context.Context) ([]models.CaseSeries, error)
+	// SaveEstimates writes the estimates as a sync run, so it can be rolled
+	// back like an ingestion. Rows that already have an Rt are left alone
+	// unless overwrite is set. With dryRun nothing is committed.
+	SaveEstimates(ctx context.Context, estimates []models.RtEstimate, overwrite, dryRun bool) (*models.ChangeSummary, error)
+}
+
+type rtEstimateRepository struct {
+	db      *database.DB
+	syncLog SyncLogRepository
+}
+
+func NewRtEstimateRepository(db *database.DB) RtEstimateRepository {
+	return &rtEstimateRepository{db: db, syncLog: NewSyncLogRepository(db)}
+}
+
+func (r *rtEstimateRepository) GetCaseSeries(ctx context.Context) ([]models.CaseSeries, error) {
+	national := models.CaseSeries{}
+	err := r.scanSeries(ctx, `SELECT '', day, date, positive, rt IS NOT NULL
+		FROM national_cases
+		WHERE deleted_at IS NULL
+		ORDER BY date, day`, func(provinceID string, day models.CaseSeriesDay) {
+		national.Days = append(national.Days, day)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series := []models.CaseSeries{national}
+	err = r.scanSeries(ctx, `SELECT pc.province_id, pc.day, nc.date, pc.positive, pc.rt IS NOT NULL
+		FROM province_cases pc
+		JOIN national_cases nc ON pc.day = nc.id
+		WHERE pc.deleted_at IS NULL
+		ORDER BY pc.province_id, nc.date, pc.day`, func(provinceID string, day models.CaseSeriesDay) {
+		if last := &series[len(series)-1]; last.ProvinceID == provinceID && len(series) > 1 {
+			last.Days = append(last.Days, day)
+			return
+		}
+		series = append(series, models.CaseSeries{ProvinceID: provinceID, Days: []models.CaseSeriesDay{day}})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// scanSeries passes each province_id, day, date, positive, has rt row of query to add
+func (r *rtEstimateRepository) scanSeries(ctx context.Context, query string, add func(string, models.CaseSeriesDay)) error {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query case series: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var provinceID string
+		var day models.CaseSeriesDay
+		if err := rows.Scan(&provinceID, &day.Day, &day.Date, &day.Positive, &day.HasRt); err != nil {
+			return fmt.Errorf("failed to scan case series day: %w", err)
+		}
+		add(provinceID, day)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
+
+func (r *rtEstimateRepository) SaveEstimates(ctx context.Context, estimates []models.RtEstimate, overwrite, dryRun bool) (*models.ChangeSummary, error) {
+	if dryRun {
+		return r.saveEstimates(ctx, 0, estimates, overwrite, true)
+	}
+
+	runID, err := r.syncLog.CreateRun(ctx, models.RtEstimationSource)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := r.saveEstimates(ctx, runID, estimates, overwrite, false)
+	if err != nil {
+		if finishErr := r.syncLog.FinishRun(ctx, runID, models.SyncStatusFailed, 0, 0, 0); finishErr != nil {
+			log.Printf("Error marking sync run %d failed: %v", runID, finishErr)
+		}
+		return nil, err
+	}
+	if err := r.syncLog.FinishRun(ctx, runID, models.SyncStatusCompleted, 0, summary.Updated, 0); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (r *rtEstimateRepository) saveEstimates(ctx context.Context, runID int64, estimates []models.RtEstimate, overwrite, dryRun bool) (*models.ChangeSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin rt estimate transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+				log.Printf("Error rolling back transaction: %v", err)
+			}
+		}
+	}()
+
+	summary := &models.ChangeSummary{DryRun: dryRun}
+	for _, e := range estimates {
+		table, key := "national_cases", fmt.Sprintf("day=%d", e.Day)
+		var existing []storedRow
+		if e.ProvinceID == "" {
+			existing, err = findNationalCases(ctx, tx, e.Day)
+		} else {
+			table, key = "province_cases", fmt.Sprintf("province_id=%s,day=%d", e.ProvinceID, e.Day)
+			existing, err = findProvinceCases(ctx, tx, e.ProvinceID, e.Day)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Rows with duplicated keys are reported by the integrity checks
+		if len(existing) != 1 {
+			continue
+		}
+		stored := existing[0]
+		if stored.data["rt"] != nil && !overwrite {
+			continue
+		}
+
+		after := make(map[string]interface{}, len(stored.data))
+		for column, value := range stored.data {
+			after[column] = value
+		}
+		after["rt"], after["rt_lower"], after["rt_upper"] = e.Rt, e.RtLower, e.RtUpper
+		if reflect.DeepEqual(stored.data, after) {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE `+quoteIdentifier(table)+` SET rt = ?, rt_lower = ?, rt_upper = ? WHERE id = ?`,
+			e.Rt, e.RtLower, e.RtUpper, stored.id); err != nil {
+			return nil, fmt.Errorf("failed to update rt of %s row %d: %w", table, stored.id, err)
+		}
+		if !dryRun {
+			if err := insertRevision(ctx, tx, models.Revision{SyncRunID: runID, TableName: table, RowID: stored.id,
+				Action: models.ChangeActionUpdate, Before: stored.data, After: after}); err != nil {
+				return nil, err
+			}
+		}
+		summary.Add(models.RowChange{
+			Table:  table,
+			Key:    key,
+			Action: models.ChangeActionUpdate,
+			Before: rtColumns(stored.data),
+			After:  rtColumns(after),
+		})
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rt estimates: %w", err)
+	}
+	committed = true
+	return summary, nil
+}
+
+// rtColumns returns the Rt columns of a row snapshot
+func rtColumns(data map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"rt": data["rt"], "rt_lower": data["rt_lower"], "rt_upper": data["rt_upper"]}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var caseSeriesCols = []string{"province_id", "day", "date", "positive", "has_rt"}
+
+func TestRtEstimateRepository_GetCaseSeries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM national_cases\s+WHERE deleted_at IS NULL\s+ORDER BY date, day`).
+		WillReturnRows(sqlmock.NewRows(caseSeriesCols).
+			AddRow("", 1, date, 10, true).
+			AddRow("", 2, date.AddDate(0, 0, 1), 12, false))
+	mock.ExpectQuery(`FROM province_cases pc\s+JOIN national_cases nc ON pc.day = nc.id\s+WHERE pc.deleted_at IS NULL\s+ORDER BY pc.province_id, nc.date, pc.day`).
+		WillReturnRows(sqlmock.NewRows(caseSeriesCols).
+			AddRow("71", 101, date, 2, false).
+			AddRow("72", 101, date, 5, false).
+			AddRow("72", 102, date.AddDate(0, 0, 1), 7, true))
+
+	series, err := NewRtEstimateRepository(db).GetCaseSeries(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, series, 3)
+	assert.Empty(t, series[0].ProvinceID)
+	assert.Len(t, series[0].Days, 2)
+	assert.True(t, series[0].Days[0].HasRt)
+	assert.Equal(t, "71", series[1].ProvinceID)
+	assert.Len(t, series[1].Days, 1)
+	assert.Equal(t, "72", series[2].ProvinceID)
+	assert.Equal(t, models.CaseSeriesDay{Day: 102, Date: date.AddDate(0, 0, 1), Positive: 7, HasRt: true}, series[2].Days[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRtEstimateRepository_SaveEstimates(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	estimates := []models.RtEstimate{
+		{Day: 8, Rt: 1.12, RtLower: 1.01, RtUpper: 1.24},
+		{Day: 9, Rt: 1.1, RtLower: 1.0, RtUpper: 1.2},
+		{ProvinceID: "72", Day: 8, Rt: 0.95, RtLower: 0.7, RtUpper: 1.22},
+	}
+
+	mock.ExpectExec(`INSERT INTO sync_log`).WithArgs(models.RtEstimationSource, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(108, 8, date, 10, 0, 0, 80, 0, 0, nil, nil, nil, nil))
+	mock.ExpectExec("UPDATE `national_cases` SET rt = \\?, rt_lower = \\?, rt_upper = \\? WHERE id = \\?").
+		WithArgs(1.12, 1.01, 1.24, int64(108)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(9), "national_cases", int64(108), models.ChangeActionUpdate, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// day 9 has an upstream Rt, which is kept without overwrite
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(109, 9, date.AddDate(0, 0, 1), 10, 0, 0, 90, 0, 0, 1.3, 1.5, 1.1, nil))
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(8)).
+		WillReturnRows(provinceIngestRow(sqlmock.NewRows(provinceIngestCols), 300, "72", 3))
+	mock.ExpectExec("UPDATE `province_cases` SET rt = \\?, rt_lower = \\?, rt_upper = \\? WHERE id = \\?").
+		WithArgs(0.95, 0.7, 1.22, int64(300)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(9), "province_cases", int64(300), models.ChangeActionUpdate, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`UPDATE sync_log SET status = \?, inserted = \?, updated = \?, conflicts = \?`).
+		WithArgs(models.SyncStatusCompleted, 0, 2, 0, sqlmock.AnyArg(), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	summary, err := NewRtEstimateRepository(db).SaveEstimates(context.Background(), estimates, false, false)
+
+	require.NoError(t, err)
+	assert.False(t, summary.DryRun)
+	assert.Equal(t, 2, summary.Updated)
+	require.Len(t, summary.Changes, 2)
+	assert.Equal(t, "day=8", summary.Changes[0].Key)
+	assert.Equal(t, map[string]interface{}{"rt": nil, "rt_lower": nil, "rt_upper": nil}, summary.Changes[0].Before)
+	assert.Equal(t, map[string]interface{}{"rt": 1.12, "rt_lower": 1.01, "rt_upper": 1.24}, summary.Changes[0].After)
+	assert.Equal(t, "province_id=72,day=8", summary.Changes[1].Key)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRtEstimateRepository_SaveEstimates_DryRunOverwrite(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(109, 9, date, 10, 0, 0, 90, 0, 0, 1.3, 1.5, 1.1, nil))
+	mock.ExpectExec("UPDATE `national_cases` SET rt = \\?").
+		WithArgs(1.1, 1.0, 1.2, int64(109)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	summary, err := NewRtEstimateRepository(db).SaveEstimates(context.Background(),
+		[]models.RtEstimate{{Day: 9, Rt: 1.1, RtLower: 1.0, RtUpper: 1.2}}, true, true)
+
+	require.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
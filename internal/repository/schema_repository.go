@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// SchemaRepository reads the tables, views and triggers of the current schema
+type SchemaRepository interface {
+	ListColumns(ctx context.Context) ([]models.SchemaColumn, error)
+	ListTriggers(ctx context.Context) ([]string, error)
+}
+
+type schemaRepository struct {
+	db *database.DB
+}
+
+func NewSchemaRepository(db *database.DB) SchemaRepository {
+	return &schemaRepository{db: db}
+}
+
+// ListColumns returns every column of the tables and views in the current schema
+func (r *schemaRepository) ListColumns(ctx context.Context) ([]models.SchemaColumn, error) {
+	query := `SELECT table_name, column_name
+			  FROM information_schema.columns
+			  WHERE table_schema = ` + r.db.Dialect().CurrentSchema() + `
+			  ORDER BY table_name, ordinal_position`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var columns []models.SchemaColumn
+	for rows.Next() {
+		var c models.SchemaColumn
+		if err := rows.Scan(&c.Table, &c.Column); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		columns = append(columns, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return columns, nil
+}
+
+// ListTriggers returns the names of the triggers in the current schema
+func (r *schemaRepository) ListTriggers(ctx context.Context) ([]string, error) {
+	query := `SELECT DISTINCT trigger_name
+			  FROM information_schema.triggers
+			  WHERE trigger_schema = ` + r.db.Dialect().CurrentSchema() + `
+			  ORDER BY trigger_name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var triggers []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger: %w", err)
+		}
+		triggers = append(triggers, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return triggers, nil
+}
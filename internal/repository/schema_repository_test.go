@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRepository_ListColumns(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSchemaRepository(db)
+
+	mock.ExpectQuery(`FROM information_schema.columns`).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "column_name"}).
+			AddRow("api_keys", "id").
+			AddRow("api_keys", "tier").
+			AddRow("case_revisions", "source"))
+
+	columns, err := repo.ListColumns(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SchemaColumn{
+		{Table: "api_keys", Column: "id"},
+		{Table: "api_keys", Column: "tier"},
+		{Table: "case_revisions", Column: "source"},
+	}, columns)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSchemaRepository_ListTriggers(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSchemaRepository(db)
+
+	mock.ExpectQuery(`FROM information_schema.triggers`).
+		WillReturnRows(sqlmock.NewRows([]string{"trigger_name"}).
+			AddRow("national_cases_sync_province_date").
+			AddRow("province_cases_fill_date"))
+
+	triggers, err := repo.ListTriggers(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"national_cases_sync_province_date", "province_cases_fill_date"}, triggers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
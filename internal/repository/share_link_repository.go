@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// ShareLinkRepository stores short links to filtered views
+type ShareLinkRepository interface {
+	GetByCode(ctx context.Context, code string) (*models.ShareLink, error)
+	GetByFiltersHash(ctx context.Context, hash string) (*models.ShareLink, error)
+	Create(ctx context.Context, link models.ShareLink) (int64, error)
+}
+
+type shareLinkRepository struct {
+	db *database.DB
+}
+
+func NewShareLinkRepository(db *database.DB) ShareLinkRepository {
+	return &shareLinkRepository{db: db}
+}
+
+const shareLinkColumns = `id, code, filters, filters_hash, created_at`
+
+// GetByCode returns nil when no link has the code
+func (r *shareLinkRepository) GetByCode(ctx context.Context, code string) (*models.ShareLink, error) {
+	return scanShareLink(r.db.QueryRowContext(ctx, `SELECT `+shareLinkColumns+` FROM share_links WHERE code = ?`, code))
+}
+
+// GetByFiltersHash returns nil when no link stores the filters
+func (r *shareLinkRepository) GetByFiltersHash(ctx context.Context, hash string) (*models.ShareLink, error) {
+	return scanShareLink(r.db.QueryRowContext(ctx, `SELECT `+shareLinkColumns+` FROM share_links WHERE filters_hash = ?`, hash))
+}
+
+func (r *shareLinkRepository) Create(ctx context.Context, link models.ShareLink) (int64, error) {
+	filters, err := json.Marshal(link.Filters)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode share link filters: %w", err)
+	}
+	id, err := r.db.InsertContext(ctx, `INSERT INTO share_links (code, filters, filters_hash, created_at) VALUES (?, ?, ?, ?)`,
+		link.Code, filters, link.FiltersHash, link.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create share link: %w", err)
+	}
+	return id, nil
+}
+
+func scanShareLink(row rowScanner) (*models.ShareLink, error) {
+	var link models.ShareLink
+	var filters []byte
+	if err := row.Scan(&link.ID, &link.Code, &filters, &link.FiltersHash, &link.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan share link: %w", err)
+	}
+	if err := json.Unmarshal(filters, &link.Filters); err != nil {
+		return nil, fmt.Errorf("invalid filters of share link %s: %w", link.Code, err)
+	}
+	return &link, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var shareLinkCols = []string{"id", "code", "filters", "filters_hash", "created_at"}
+
+func TestShareLinkRepository_GetByCode(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM share_links WHERE code = \?`).WithArgs("aB3xY9k").
+		WillReturnRows(sqlmock.NewRows(shareLinkCols).
+			AddRow(1, "aB3xY9k", `{"province_id":"72","metrics":["positive","rt"]}`, "h", now))
+
+	link, err := NewShareLinkRepository(db).GetByCode(context.Background(), "aB3xY9k")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "72", link.Filters.ProvinceID)
+	assert.Equal(t, []string{"positive", "rt"}, link.Filters.Metrics)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareLinkRepository_GetByFiltersHash_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM share_links WHERE filters_hash = \?`).WithArgs("nope").
+		WillReturnRows(sqlmock.NewRows(shareLinkCols))
+
+	link, err := NewShareLinkRepository(db).GetByFiltersHash(context.Background(), "nope")
+
+	assert.NoError(t, err)
+	assert.Nil(t, link)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareLinkRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`INSERT INTO share_links \(code, filters, filters_hash, created_at\)`).
+		WithArgs("aB3xY9k", []byte(`{"province_id":"72","start_date":"2020-04-01"}`), "h", now).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	id, err := NewShareLinkRepository(db).Create(context.Background(), models.ShareLink{
+		Code:        "aB3xY9k",
+		Filters:     models.ShareFilters{ProvinceID: "72", StartDate: "2020-04-01"},
+		FiltersHash: "h",
+		CreatedAt:   now,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// ErrSoftDeleteNoMatch is returned when no row is in the state the soft
+// delete or restore applies to: missing, already deleted, or not deleted
+var ErrSoftDeleteNoMatch = errors.New("no matching record to delete or restore")
+
+// SoftDeleteRepository hides erroneous rows from every read query by setting
+// their deleted_at, and brings them back, so no row referenced by province
+// cases or revisions is ever removed.
+//
+// Case rows change in one transaction recorded as a sync run with a
+// revision per row, which shows the change in the revision history and lets
+// the run be rolled back. Provinces have no revisions. On a dry run the
+// transaction is rolled back and the summary reports what would change.
+type SoftDeleteRepository interface {
+	// SetNationalCaseDeleted soft-deletes or restores the national case of a day
+	SetNationalCaseDeleted(ctx context.Context, day int64, deleted, dryRun bool) (*models.ChangeSummary, error)
+	// SetProvinceCaseDeleted soft-deletes or restores the case of a province on a day
+	SetProvinceCaseDeleted(ctx context.Context, provinceID string, day int64, deleted, dryRun bool) (*models.ChangeSummary, error)
+	// SetProvinceDeleted soft-deletes or restores a province, which hides or
+	// shows its cases with it
+	SetProvinceDeleted(ctx context.Context, id string, deleted, dryRun bool) (*models.ChangeSummary, error)
+}
+
+type softDeleteRepository struct {
+	db *database.DB
+}
+
+func NewSoftDeleteRepository(db *database.DB) SoftDeleteRepository {
+	return &softDeleteRepository{db: db}
+}
+
+func (r *softDeleteRepository) SetNationalCaseDeleted(ctx context.Context, day int64, deleted, dryRun bool) (*models.ChangeSummary, error) {
+	return r.setCasesDeleted(ctx, "national_cases", fmt.Sprintf("day=%d", day), deleted, dryRun, func(exec sqlExecutor) ([]storedRow, error) {
+		return findNationalCases(ctx, exec, day)
+	})
+}
+
+func (r *softDeleteRepository) SetProvinceCaseDeleted(ctx context.Context, provinceID string, day int64, deleted, dryRun bool) (*models.ChangeSummary, error) {
+	return r.setCasesDeleted(ctx, "province_cases", fmt.Sprintf("province_id=%s,day=%d", provinceID, day), deleted, dryRun, func(exec sqlExecutor) ([]storedRow, error) {
+		return findProvinceCases(ctx, exec, provinceID, day)
+	})
+}
+
+func (r *softDeleteRepository) SetProvinceDeleted(ctx context.Context, id string, deleted, dryRun bool) (*models.ChangeSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin soft delete transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+				log.Printf("Error rolling back transaction: %v", err)
+			}
+		}
+	}()
+
+	query, args := softDeleteStatement("provinces", deleted)
+	res, err := tx.ExecContext(ctx, query, append(args, id)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update province %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to read updated provinces: %w", err)
+	} else if n == 0 {
+		return nil, ErrSoftDeleteNoMatch
+	}
+
+	summary := &models.ChangeSummary{DryRun: dryRun}
+	summary.Add(models.RowChange{Table: "provinces", Key: id, Action: softDeleteAction(deleted)})
+	if dryRun {
+		return summary, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit soft delete: %w", err)
+	}
+	committed = true
+	return summary, nil
+}
+
+// setCasesDeleted flips deleted_at of the rows returned by find that are in
+// the other state, and records the change as a sync run. A dry run rolls
+// the transaction back.
+func (r *softDeleteRepository) setCasesDeleted(ctx context.Context, table, key string, deleted, dryRun bool, find func(sqlExecutor) ([]storedRow, error)) (*models.ChangeSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin soft delete transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+				log.Printf("Error rolling back transaction: %v", err)
+			}
+		}
+	}()
+
+	stored, err := find(tx)
+	if err != nil {
+		return nil, err
+	}
+	query, args := softDeleteStatement(table, deleted)
+	var changed []storedRow
+	for _, row := range stored {
+		res, err := tx.ExecContext(ctx, query, append(args, row.id)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update %s row %d: %w", table, row.id, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read updated %s rows: %w", table, err)
+		}
+		if n > 0 {
+			changed = append(changed, row)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, ErrSoftDeleteNoMatch
+	}
+
+	runID, err := createRun(ctx, tx, models.SoftDeleteSource)
+	if err != nil {
+		return nil, err
+	}
+	action := softDeleteAction(deleted)
+	summary := &models.ChangeSummary{DryRun: dryRun}
+	for _, row := range changed {
+		// A deleted row is one that does not exist, as for rowStatesAsOf
+		rev := models.Revision{SyncRunID: runID, TableName: table, RowID: row.id, Action: action, Before: row.data}
+		change := models.RowChange{Table: table, Key: key, Action: action, Before: row.data}
+		if !deleted {
+			rev.Before, rev.After = nil, row.data
+			change.Before, change.After = nil, row.data
+		}
+		if err := insertRevision(ctx, tx, rev); err != nil {
+			return nil, err
+		}
+		summary.Add(change)
+	}
+	if err := finishRun(ctx, tx, runID, models.SyncStatusCompleted, 0, len(changed), 0); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return summary, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit soft delete: %w", err)
+	}
+	committed = true
+	return summary, nil
+}
+
+// softDeleteStatement returns the UPDATE soft-deleting or restoring one row
+// of table by ID, with its arguments before the ID. Rows already in the
+// target state are not matched.
+func softDeleteStatement(table string, deleted bool) (string, []interface{}) {
+	if deleted {
+		return `UPDATE ` + quoteIdentifier(table) + ` SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, []interface{}{time.Now().UTC()}
+	}
+	return `UPDATE ` + quoteIdentifier(table) + ` SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, nil
+}
+
+func softDeleteAction(deleted bool) string {
+	if deleted {
+		return models.ChangeActionSoftDelete
+	}
+	return models.ChangeActionRestore
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDeleteRepository_SetNationalCaseDeleted(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(108, 8, date, 10, 0, 0, 80, 0, 0, nil, nil, nil, nil))
+	mock.ExpectExec("UPDATE `national_cases` SET deleted_at = \\? WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), int64(108)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO sync_log`).WithArgs(models.SoftDeleteSource, models.SyncStatusRunning, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(12), "national_cases", int64(108), models.ChangeActionSoftDelete, sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE sync_log SET status = \?, inserted = \?, updated = \?, conflicts = \?`).
+		WithArgs(models.SyncStatusCompleted, 0, 1, 0, sqlmock.AnyArg(), int64(12)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	summary, err := NewSoftDeleteRepository(db).SetNationalCaseDeleted(context.Background(), 8, true, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Deleted)
+	require.Len(t, summary.Changes, 1)
+	assert.Equal(t, "day=8", summary.Changes[0].Key)
+	assert.Equal(t, int64(80), summary.Changes[0].Before["cumulative_positive"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteRepository_SetProvinceCaseDeleted_Restore(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM province_cases WHERE province_id = \? AND day = \? FOR UPDATE`).WithArgs("72", int64(8)).
+		WillReturnRows(provinceIngestRow(sqlmock.NewRows(provinceIngestCols), 300, "72", 3))
+	mock.ExpectExec("UPDATE `province_cases` SET deleted_at = NULL WHERE id = \\? AND deleted_at IS NOT NULL").
+		WithArgs(int64(300)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(13, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).
+		WithArgs(int64(13), "province_cases", int64(300), models.ChangeActionRestore, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE sync_log SET status`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	summary, err := NewSoftDeleteRepository(db).SetProvinceCaseDeleted(context.Background(), "72", 8, false, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Restored)
+	assert.Equal(t, "province_id=72,day=8", summary.Changes[0].Key)
+	assert.Nil(t, summary.Changes[0].Before)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteRepository_SetNationalCaseDeleted_DryRun(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(108, 8, date, 10, 0, 0, 80, 0, 0, nil, nil, nil, nil))
+	mock.ExpectExec("UPDATE `national_cases` SET deleted_at = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(108)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO sync_log`).WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec(`INSERT INTO revisions`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE sync_log SET status`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	summary, err := NewSoftDeleteRepository(db).SetNationalCaseDeleted(context.Background(), 8, true, true)
+
+	require.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteRepository_SetNationalCaseDeleted_AlreadyDeleted(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM national_cases WHERE day = \? FOR UPDATE`).WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows(nationalIngestCols).AddRow(108, 8, date, 10, 0, 0, 80, 0, 0, nil, nil, nil, nil))
+	mock.ExpectExec("UPDATE `national_cases` SET deleted_at = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(108)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := NewSoftDeleteRepository(db).SetNationalCaseDeleted(context.Background(), 8, true, false)
+
+	assert.ErrorIs(t, err, ErrSoftDeleteNoMatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteRepository_SetProvinceDeleted(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSoftDeleteRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `provinces` SET deleted_at = \\? WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), "72").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `provinces` SET deleted_at = NULL WHERE id = \\? AND deleted_at IS NOT NULL").
+		WithArgs("99").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	summary, err := repo.SetProvinceDeleted(context.Background(), "72", true, false)
+	require.NoError(t, err)
+	assert.Equal(t, []models.RowChange{{Table: "provinces", Key: "72", Action: models.ChangeActionSoftDelete}}, summary.Changes)
+
+	_, err = repo.SetProvinceDeleted(context.Background(), "99", false, false)
+	assert.ErrorIs(t, err, ErrSoftDeleteNoMatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteRepository_SetProvinceDeleted_DryRun(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `provinces` SET deleted_at = \\?").
+		WithArgs(sqlmock.AnyArg(), "72").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	summary, err := NewSoftDeleteRepository(db).SetProvinceDeleted(context.Background(), "72", true, true)
+
+	require.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
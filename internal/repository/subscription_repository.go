@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// SubscriptionRepository stores email subscriptions to daily updates
+type SubscriptionRepository interface {
+	GetByEmail(ctx context.Context, email string) (*models.EmailSubscription, error)
+	GetByConfirmToken(ctx context.Context, token string) (*models.EmailSubscription, error)
+	GetByUnsubscribeToken(ctx context.Context, token string) (*models.EmailSubscription, error)
+	Create(ctx context.Context, s models.EmailSubscription) (int64, error)
+	Update(ctx context.Context, s models.EmailSubscription) error
+	ListDue(ctx context.Context, day int64, limit int) ([]models.EmailSubscription, error)
+	MarkSent(ctx context.Context, id, day int64) error
+}
+
+type subscriptionRepository struct {
+	db *database.DB
+}
+
+func NewSubscriptionRepository(db *database.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+const emailSubscriptionColumns = `id, email, status, confirm_token, unsubscribe_token, confirmation_sent_at,
+	confirmed_at, unsubscribed_at, last_sent_day, created_at`
+
+// GetByEmail returns nil when the address has no subscription
+func (r *subscriptionRepository) GetByEmail(ctx context.Context, email string) (*models.EmailSubscription, error) {
+	return scanEmailSubscription(r.db.QueryRowContext(ctx, `SELECT `+emailSubscriptionColumns+` FROM email_subscriptions WHERE email = ?`, email))
+}
+
+// GetByConfirmToken returns nil when no subscription has the token
+func (r *subscriptionRepository) GetByConfirmToken(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	return scanEmailSubscription(r.db.QueryRowContext(ctx, `SELECT `+emailSubscriptionColumns+` FROM email_subscriptions WHERE confirm_token = ?`, token))
+}
+
+// GetByUnsubscribeToken returns nil when no subscription has the token
+func (r *subscriptionRepository) GetByUnsubscribeToken(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	return scanEmailSubscription(r.db.QueryRowContext(ctx, `SELECT `+emailSubscriptionColumns+` FROM email_subscriptions WHERE unsubscribe_token = ?`, token))
+}
+
+func (r *subscriptionRepository) Create(ctx context.Context, s models.EmailSubscription) (int64, error) {
+	id, err := r.db.InsertContext(ctx, `INSERT INTO email_subscriptions
+		(email, status, confirm_token, unsubscribe_token, confirmation_sent_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		s.Email, s.Status, s.ConfirmToken, s.UnsubscribeToken, s.ConfirmationSentAt, s.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create email subscription: %w", err)
+	}
+	return id, nil
+}
+
+// Update stores the status, tokens and timestamps of a subscription
+func (r *subscriptionRepository) Update(ctx context.Context, s models.EmailSubscription) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE email_subscriptions
+		SET status = ?, confirm_token = ?, unsubscribe_token = ?, confirmation_sent_at = ?, confirmed_at = ?, unsubscribed_at = ?
+		WHERE id = ?`,
+		s.Status, s.ConfirmToken, s.UnsubscribeToken, s.ConfirmationSentAt,
+		nullableTime(s.ConfirmedAt), nullableTime(s.UnsubscribedAt), s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update email subscription %d: %w", s.ID, err)
+	}
+	return nil
+}
+
+// ListDue returns confirmed subscriptions that have not yet been sent the update
+// for day. It reads from the primary so a batch just marked sent is not picked
+// again from a lagging replica.
+func (r *subscriptionRepository) ListDue(ctx context.Context, day int64, limit int) ([]models.EmailSubscription, error) {
+	rows, err := r.db.QueryContext(database.WithPrimary(ctx), `SELECT `+emailSubscriptionColumns+` FROM email_subscriptions
+		WHERE status = ? AND (last_sent_day IS NULL OR last_sent_day < ?) ORDER BY id LIMIT ?`,
+		models.SubscriptionConfirmed, day, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email subscriptions: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var subs []models.EmailSubscription
+	for rows.Next() {
+		s, err := scanEmailSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return subs, nil
+}
+
+// MarkSent records that the update for day was emailed to the subscription
+func (r *subscriptionRepository) MarkSent(ctx context.Context, id, day int64) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE email_subscriptions SET last_sent_day = ? WHERE id = ?`, day, id); err != nil {
+		return fmt.Errorf("failed to mark email subscription %d sent: %w", id, err)
+	}
+	return nil
+}
+
+func scanEmailSubscription(row rowScanner) (*models.EmailSubscription, error) {
+	var s models.EmailSubscription
+	var confirmedAt, unsubscribedAt sql.NullTime
+	var lastSentDay sql.NullInt64
+	if err := row.Scan(&s.ID, &s.Email, &s.Status, &s.ConfirmToken, &s.UnsubscribeToken, &s.ConfirmationSentAt,
+		&confirmedAt, &unsubscribedAt, &lastSentDay, &s.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan email subscription: %w", err)
+	}
+	if confirmedAt.Valid {
+		s.ConfirmedAt = &confirmedAt.Time
+	}
+	if unsubscribedAt.Valid {
+		s.UnsubscribedAt = &unsubscribedAt.Time
+	}
+	if lastSentDay.Valid {
+		s.LastSentDay = &lastSentDay.Int64
+	}
+	return &s, nil
+}
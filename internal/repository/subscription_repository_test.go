@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var emailSubscriptionCols = []string{"id", "email", "status", "confirm_token", "unsubscribe_token", "confirmation_sent_at",
+	"confirmed_at", "unsubscribed_at", "last_sent_day", "created_at"}
+
+func TestSubscriptionRepository_GetByEmail(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM email_subscriptions WHERE email = \?`).WithArgs("warga@example.com").
+		WillReturnRows(sqlmock.NewRows(emailSubscriptionCols).
+			AddRow(1, "warga@example.com", models.SubscriptionConfirmed, "c", "u", now, now, nil, 410, now))
+
+	s, err := NewSubscriptionRepository(db).GetByEmail(context.Background(), "warga@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SubscriptionConfirmed, s.Status)
+	assert.Equal(t, int64(410), *s.LastSentDay)
+	assert.Nil(t, s.UnsubscribedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionRepository_GetByConfirmToken_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM email_subscriptions WHERE confirm_token = \?`).WithArgs("nope").
+		WillReturnRows(sqlmock.NewRows(emailSubscriptionCols))
+
+	s, err := NewSubscriptionRepository(db).GetByConfirmToken(context.Background(), "nope")
+
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionRepository_ListDue(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`WHERE status = \? AND \(last_sent_day IS NULL OR last_sent_day < \?\) ORDER BY id LIMIT \?`).
+		WithArgs(models.SubscriptionConfirmed, int64(411), 100).
+		WillReturnRows(sqlmock.NewRows(emailSubscriptionCols).
+			AddRow(1, "a@example.com", models.SubscriptionConfirmed, "c1", "u1", now, now, nil, nil, now).
+			AddRow(2, "b@example.com", models.SubscriptionConfirmed, "c2", "u2", now, now, nil, 410, now))
+
+	subs, err := NewSubscriptionRepository(db).ListDue(context.Background(), 411, 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, subs, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscriptionRepository_MarkSent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE email_subscriptions SET last_sent_day = \? WHERE id = \?`).WithArgs(int64(411), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, NewSubscriptionRepository(db).MarkSent(context.Background(), 2, 411))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,437 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+var (
+	// ErrSyncRunNotFound is returned when a sync run ID does not exist
+	ErrSyncRunNotFound = errors.New("sync run not found")
+	// ErrSyncRunNotRollbackable is returned when a run is still running or already rolled back
+	ErrSyncRunNotRollbackable = errors.New("sync run cannot be rolled back in its current status")
+	// ErrSyncRunConflict is returned when a later run touched the same rows
+	ErrSyncRunConflict = errors.New("rows of this sync run were modified by a later run; roll that run back first")
+)
+
+// rollbackableTables lists the tables revisions may restore rows in
+var rollbackableTables = map[string]bool{
+	"national_cases": true,
+	"province_cases": true,
+}
+
+var columnNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// SyncLogRepository records ingestion runs with their row revisions and rolls them back
+type SyncLogRepository interface {
+	CreateRun(ctx context.Context, source string) (int64, error)
+	FinishRun(ctx context.Context, id int64, status string, inserted, updated, conflicts int) error
+	GetRun(ctx context.Context, id int64) (*models.SyncRun, error)
+	GetLatestRun(ctx context.Context) (*models.SyncRun, error)
+	ListRuns(ctx context.Context, limit int) ([]models.SyncRun, error)
+	ListRevisions(ctx context.Context, runID int64) ([]models.Revision, error)
+	RecordRevision(ctx context.Context, rev models.Revision) error
+	RollbackRun(ctx context.Context, runID int64, dryRun bool) (*models.ChangeSummary, error)
+}
+
+// sqlExecutor is satisfied by both *database.DB and *database.Tx
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	InsertContext(ctx context.Context, query string, args ...interface{}) (int64, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type syncLogRepository struct {
+	db *database.DB
+}
+
+func NewSyncLogRepository(db *database.DB) SyncLogRepository {
+	return &syncLogRepository{db: db}
+}
+
+const syncRunColumns = `id, source, status, inserted, updated, conflicts, started_at, finished_at, rolled_back_at`
+
+func (r *syncLogRepository) CreateRun(ctx context.Context, source string) (int64, error) {
+	return createRun(ctx, r.db, source)
+}
+
+func (r *syncLogRepository) FinishRun(ctx context.Context, id int64, status string, inserted, updated, conflicts int) error {
+	return finishRun(ctx, r.db, id, status, inserted, updated, conflicts)
+}
+
+func createRun(ctx context.Context, exec sqlExecutor, source string) (int64, error) {
+	id, err := exec.InsertContext(ctx, `INSERT INTO sync_log (source, status, started_at) VALUES (?, ?, ?)`,
+		source, models.SyncStatusRunning, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sync run: %w", err)
+	}
+	return id, nil
+}
+
+func finishRun(ctx context.Context, exec sqlExecutor, id int64, status string, inserted, updated, conflicts int) error {
+	_, err := exec.ExecContext(ctx, `UPDATE sync_log SET status = ?, inserted = ?, updated = ?, conflicts = ?, finished_at = ? WHERE id = ?`,
+		status, inserted, updated, conflicts, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to finish sync run %d: %w", id, err)
+	}
+	return nil
+}
+
+func (r *syncLogRepository) GetRun(ctx context.Context, id int64) (*models.SyncRun, error) {
+	return scanSyncRun(r.db.QueryRowContext(ctx, `SELECT `+syncRunColumns+` FROM sync_log WHERE id = ?`, id))
+}
+
+func (r *syncLogRepository) GetLatestRun(ctx context.Context) (*models.SyncRun, error) {
+	return scanSyncRun(r.db.QueryRowContext(ctx, `SELECT `+syncRunColumns+` FROM sync_log ORDER BY id DESC LIMIT 1`))
+}
+
+func (r *syncLogRepository) ListRuns(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+syncRunColumns+` FROM sync_log ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync runs: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var runs []models.SyncRun
+	for rows.Next() {
+		run, err := scanSyncRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *syncLogRepository) ListRevisions(ctx context.Context, runID int64) ([]models.Revision, error) {
+	return listRevisions(ctx, r.db, runID, "ASC")
+}
+
+func (r *syncLogRepository) RecordRevision(ctx context.Context, rev models.Revision) error {
+	return insertRevision(ctx, r.db, rev)
+}
+
+// RollbackRun reverts every revision of a run in reverse order inside one transaction.
+// With dryRun the transaction is rolled back after computing the change summary.
+func (r *syncLogRepository) RollbackRun(ctx context.Context, runID int64, dryRun bool) (*models.ChangeSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin rollback transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+				log.Printf("Error rolling back transaction: %v", err)
+			}
+		}
+	}()
+
+	// Locking the run serializes rollbacks of it
+	run, err := scanSyncRun(tx.QueryRowContext(ctx, `SELECT `+syncRunColumns+` FROM sync_log WHERE id = ? FOR UPDATE`, runID))
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrSyncRunNotFound
+	}
+	if run.Status == models.SyncStatusRunning || run.Status == models.SyncStatusRolledBack {
+		return nil, ErrSyncRunNotRollbackable
+	}
+
+	// Ingestion locks a row before writing it and its revision, so with the
+	// rows of the run locked no later run can touch them until this commits.
+	// The conflict check reads after the locks are held and so sees every run
+	// that wrote them first.
+	if err := lockRevisionRows(ctx, tx, runID); err != nil {
+		return nil, err
+	}
+	var conflicts int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM revisions later
+		JOIN sync_log s ON later.sync_log_id = s.id
+		JOIN revisions own ON own.table_name = later.table_name AND own.row_id = later.row_id
+		WHERE own.sync_log_id = ? AND later.sync_log_id > ? AND s.status <> ?`,
+		runID, runID, models.SyncStatusRolledBack).Scan(&conflicts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rollback conflicts: %w", err)
+	}
+	if conflicts > 0 {
+		return nil, ErrSyncRunConflict
+	}
+
+	revisions, err := listRevisions(ctx, tx, runID, "DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ChangeSummary{DryRun: dryRun}
+	for _, rev := range revisions {
+		change, err := revertRevision(ctx, tx, rev)
+		if err != nil {
+			return nil, err
+		}
+		summary.Add(change)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE sync_log SET status = ?, rolled_back_at = ? WHERE id = ?`,
+		models.SyncStatusRolledBack, time.Now().UTC(), runID); err != nil {
+		return nil, fmt.Errorf("failed to mark sync run %d rolled back: %w", runID, err)
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rollback of sync run %d: %w", runID, err)
+	}
+	committed = true
+	return summary, nil
+}
+
+// lockRevisionRows locks the rows that the revisions of a run wrote, in a
+// fixed table order so concurrent rollbacks cannot deadlock
+func lockRevisionRows(ctx context.Context, exec sqlExecutor, runID int64) error {
+	tables := make([]string, 0, len(rollbackableTables))
+	for table := range rollbackableTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		rows, err := exec.QueryContext(ctx, `SELECT id FROM `+quoteIdentifier(table)+`
+			WHERE id IN (SELECT row_id FROM revisions WHERE sync_log_id = ? AND table_name = ?)
+			ORDER BY id FOR UPDATE`, runID, table)
+		if err != nil {
+			return fmt.Errorf("failed to lock %s rows: %w", table, err)
+		}
+		for rows.Next() {
+		}
+		err = rows.Err()
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock %s rows: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// revertRevision undoes a single revision and describes the resulting change
+func revertRevision(ctx context.Context, exec sqlExecutor, rev models.Revision) (models.RowChange, error) {
+	if !rollbackableTables[rev.TableName] {
+		return models.RowChange{}, fmt.Errorf("revision %d targets unsupported table %q", rev.ID, rev.TableName)
+	}
+	table := quoteIdentifier(rev.TableName)
+	change := models.RowChange{Table: rev.TableName, Key: strconv.FormatInt(rev.RowID, 10)}
+
+	switch rev.Action {
+	case models.ChangeActionInsert:
+		if _, err := exec.ExecContext(ctx, `DELETE FROM `+table+` WHERE id = ?`, rev.RowID); err != nil {
+			return change, fmt.Errorf("failed to delete %s row %d: %w", rev.TableName, rev.RowID, err)
+		}
+		change.Action = models.ChangeActionDelete
+		change.Before = rev.After
+	case models.ChangeActionUpdate:
+		columns, values, err := revisionColumns(rev.Before)
+		if err != nil {
+			return change, err
+		}
+		assignments := make([]string, len(columns))
+		for i, c := range columns {
+			assignments[i] = c + " = ?"
+		}
+		values = append(values, rev.RowID)
+		if _, err := exec.ExecContext(ctx, `UPDATE `+table+` SET `+strings.Join(assignments, ", ")+` WHERE id = ?`, values...); err != nil {
+			return change, fmt.Errorf("failed to restore %s row %d: %w", rev.TableName, rev.RowID, err)
+		}
+		change.Action = models.ChangeActionUpdate
+		change.Before = rev.After
+		change.After = rev.Before
+	case models.ChangeActionDelete:
+		columns, values, err := revisionColumns(rev.Before)
+		if err != nil {
+			return change, err
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		if _, err := exec.ExecContext(ctx, `INSERT INTO `+table+` (`+strings.Join(columns, ", ")+`) VALUES (`+placeholders+`)`, values...); err != nil {
+			return change, fmt.Errorf("failed to re-insert %s row %d: %w", rev.TableName, rev.RowID, err)
+		}
+		change.Action = models.ChangeActionInsert
+		change.After = rev.Before
+	case models.ChangeActionSoftDelete:
+		if _, err := exec.ExecContext(ctx, `UPDATE `+table+` SET deleted_at = NULL WHERE id = ?`, rev.RowID); err != nil {
+			return change, fmt.Errorf("failed to restore %s row %d: %w", rev.TableName, rev.RowID, err)
+		}
+		change.Action = models.ChangeActionRestore
+		change.After = rev.Before
+	case models.ChangeActionRestore:
+		if _, err := exec.ExecContext(ctx, `UPDATE `+table+` SET deleted_at = ? WHERE id = ?`, time.Now().UTC(), rev.RowID); err != nil {
+			return change, fmt.Errorf("failed to soft-delete %s row %d: %w", rev.TableName, rev.RowID, err)
+		}
+		change.Action = models.ChangeActionSoftDelete
+		change.Before = rev.After
+	default:
+		return change, fmt.Errorf("revision %d has unknown action %q", rev.ID, rev.Action)
+	}
+	return change, nil
+}
+
+// revisionColumns returns the sorted, validated column names of a row snapshot with their values
+func revisionColumns(data map[string]interface{}) ([]string, []interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("revision has no row snapshot to restore")
+	}
+	columns := make([]string, 0, len(data))
+	for c := range data {
+		if !columnNamePattern.MatchString(c) {
+			return nil, nil, fmt.Errorf("revision contains invalid column name %q", c)
+		}
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = data[c]
+	}
+	return columns, values, nil
+}
+
+// insertRevision records a revision of a row written by an ingestion run
+func insertRevision(ctx context.Context, exec sqlExecutor, rev models.Revision) error {
+	before, err := marshalRowSnapshot(rev.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalRowSnapshot(rev.After)
+	if err != nil {
+		return err
+	}
+	_, err = exec.ExecContext(ctx, `INSERT INTO revisions (sync_log_id, table_name, row_id, action, before_data, after_data, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rev.SyncRunID, rev.TableName, rev.RowID, rev.Action, before, after, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record revision for %s row %d: %w", rev.TableName, rev.RowID, err)
+	}
+	return nil
+}
+
+func listRevisions(ctx context.Context, exec sqlExecutor, runID int64, order string) ([]models.Revision, error) {
+	rows, err := exec.QueryContext(ctx, `SELECT id, sync_log_id, table_name, row_id, action, before_data, after_data, created_at
+		FROM revisions WHERE sync_log_id = ? ORDER BY id `+order, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var revisions []models.Revision
+	for rows.Next() {
+		var rev models.Revision
+		var before, after []byte
+		if err := rows.Scan(&rev.ID, &rev.SyncRunID, &rev.TableName, &rev.RowID, &rev.Action, &before, &after, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		if rev.Before, err = unmarshalRowSnapshot(before); err != nil {
+			return nil, err
+		}
+		if rev.After, err = unmarshalRowSnapshot(after); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return revisions, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSyncRun(row rowScanner) (*models.SyncRun, error) {
+	var run models.SyncRun
+	var finishedAt, rolledBackAt sql.NullTime
+	err := row.Scan(&run.ID, &run.Source, &run.Status, &run.Inserted, &run.Updated, &run.Conflicts,
+		&run.StartedAt, &finishedAt, &rolledBackAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan sync run: %w", err)
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	if rolledBackAt.Valid {
+		run.RolledBackAt = &rolledBackAt.Time
+	}
+	return &run, nil
+}
+
+func marshalRowSnapshot(data map[string]interface{}) (interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode row snapshot: %w", err)
+	}
+	return string(b), nil
+}
+
+// unmarshalRowSnapshot decodes a JSON row snapshot, keeping integers as int64
+// so restored values round-trip exactly.
+func unmarshalRowSnapshot(raw []byte) (map[string]interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var data map[string]interface{}
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode row snapshot: %w", err)
+	}
+	for k, v := range data {
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				data[k] = i
+			} else if f, err := n.Float64(); err == nil {
+				data[k] = f
+			} else {
+				data[k] = n.String()
+			}
+		}
+	}
+	return data, nil
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
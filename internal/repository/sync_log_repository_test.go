@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var syncRunCols = []string{"id", "source", "status", "inserted", "updated", "conflicts", "started_at", "finished_at", "rolled_back_at"}
+var revisionCols = []string{"id", "sync_log_id", "table_name", "row_id", "action", "before_data", "after_data", "created_at"}
+
+func TestSyncLogRepository_CreateRun(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+
+	mock.ExpectExec(`INSERT INTO sync_log`).
+		WithArgs("upstream", models.SyncStatusRunning, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	id, err := repo.CreateRun(context.Background(), "upstream")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncLogRepository_GetRun_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+
+	mock.ExpectQuery(`SELECT id, source, status`).WithArgs(int64(99)).
+		WillReturnRows(sqlmock.NewRows(syncRunCols))
+
+	run, err := repo.GetRun(context.Background(), 99)
+
+	assert.NoError(t, err)
+	assert.Nil(t, run)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectRevisionRowLocks expects the rows of a run to be locked before its
+// conflict check
+func expectRevisionRowLocks(mock sqlmock.Sqlmock, runID int64) {
+	for _, table := range []string{"national_cases", "province_cases"} {
+		mock.ExpectQuery("SELECT id FROM `"+table+"`\\s+WHERE id IN \\(SELECT row_id FROM revisions WHERE sync_log_id = \\? AND table_name = \\?\\)\\s+ORDER BY id FOR UPDATE").
+			WithArgs(runID, table).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+}
+
+func TestSyncLogRepository_RollbackRun(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, source, status, (.+) FROM sync_log WHERE id = \? FOR UPDATE`).WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(syncRunCols).AddRow(5, "upstream", models.SyncStatusCompleted, 1, 1, 0, now, now, nil))
+	expectRevisionRowLocks(mock, 5)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM revisions later`).
+		WithArgs(int64(5), int64(5), models.SyncStatusRolledBack).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT id, sync_log_id, table_name`).WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(revisionCols).
+			AddRow(2, 5, "province_cases", 300, "update", []byte(`{"positive":10,"rt":1.05}`), []byte(`{"positive":99,"rt":1.05}`), now).
+			AddRow(1, 5, "province_cases", 301, "insert", nil, []byte(`{"positive":4}`), now))
+	mock.ExpectExec("UPDATE `province_cases` SET positive = \\?, rt = \\? WHERE id = \\?").
+		WithArgs(int64(10), 1.05, int64(300)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `province_cases` WHERE id = \\?").
+		WithArgs(int64(301)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE sync_log SET status = \?, rolled_back_at = \?`).
+		WithArgs(models.SyncStatusRolledBack, sqlmock.AnyArg(), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	summary, err := repo.RollbackRun(context.Background(), 5, false)
+
+	assert.NoError(t, err)
+	assert.False(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncLogRepository_RollbackRun_DryRunDoesNotCommit(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, source, status`).WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(syncRunCols).AddRow(5, "upstream", models.SyncStatusCompleted, 1, 0, 0, now, now, nil))
+	expectRevisionRowLocks(mock, 5)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM revisions later`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT id, sync_log_id, table_name`).WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(revisionCols).
+			AddRow(1, 5, "national_cases", 10, "insert", nil, []byte(`{"positive":4}`), now))
+	mock.ExpectExec("DELETE FROM `national_cases`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE sync_log SET status`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	summary, err := repo.RollbackRun(context.Background(), 5, true)
+
+	assert.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncLogRepository_RollbackRun_SoftDelete(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, source, status`).WithArgs(int64(6)).
+		WillReturnRows(sqlmock.NewRows(syncRunCols).AddRow(6, models.SoftDeleteSource, models.SyncStatusCompleted, 0, 1, 0, now, now, nil))
+	expectRevisionRowLocks(mock, 6)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM revisions later`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT id, sync_log_id, table_name`).WithArgs(int64(6)).
+		WillReturnRows(sqlmock.NewRows(revisionCols).
+			AddRow(3, 6, "national_cases", 108, models.ChangeActionSoftDelete, []byte(`{"positive":10}`), nil, now))
+	mock.ExpectExec("UPDATE `national_cases` SET deleted_at = NULL WHERE id = \\?").
+		WithArgs(int64(108)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE sync_log SET status`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	summary, err := repo.RollbackRun(context.Background(), 6, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Restored)
+	assert.Equal(t, models.ChangeActionRestore, summary.Changes[0].Action)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncLogRepository_RollbackRun_Conflict(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, source, status`).WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(syncRunCols).AddRow(5, "upstream", models.SyncStatusCompleted, 1, 0, 0, now, now, nil))
+	expectRevisionRowLocks(mock, 5)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM revisions later`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectRollback()
+
+	_, err := repo.RollbackRun(context.Background(), 5, false)
+
+	assert.ErrorIs(t, err, ErrSyncRunConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncLogRepository_RollbackRun_AlreadyRolledBack(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewSyncLogRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, source, status`).WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(syncRunCols).AddRow(5, "upstream", models.SyncStatusRolledBack, 1, 0, 0, now, now, now))
+	mock.ExpectRollback()
+
+	_, err := repo.RollbackRun(context.Background(), 5, false)
+
+	assert.ErrorIs(t, err, ErrSyncRunNotRollbackable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevisionColumns_RejectsInvalidColumn(t *testing.T) {
+	_, _, err := revisionColumns(map[string]interface{}{"positive; DROP TABLE x": 1})
+	assert.Error(t, err)
+}
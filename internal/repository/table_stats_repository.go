@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// TableStatsRepository reads per-table row counts and sizes from the database catalog
+type TableStatsRepository interface {
+	GetTableStats(ctx context.Context) ([]models.TableStats, error)
+}
+
+type tableStatsRepository struct {
+	db *database.DB
+}
+
+func NewTableStatsRepository(db *database.DB) TableStatsRepository {
+	return &tableStatsRepository{db: db}
+}
+
+// GetTableStats returns row counts and sizes for every table in the current schema.
+// Row counts come from information_schema and are estimates for InnoDB tables,
+// like those of PostgreSQL's planner statistics.
+func (r *tableStatsRepository) GetTableStats(ctx context.Context) ([]models.TableStats, error) {
+	query := `SELECT table_name, COALESCE(table_rows, 0), COALESCE(data_length, 0), COALESCE(index_length, 0)
+			  FROM information_schema.tables
+			  WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'
+			  ORDER BY table_name`
+	if r.db.Dialect() == database.DialectPostgres {
+		query = `SELECT c.relname, GREATEST(c.reltuples, 0)::bigint, pg_table_size(c.oid), pg_indexes_size(c.oid)
+			  FROM pg_class c
+			  JOIN pg_namespace n ON n.oid = c.relnamespace
+			  WHERE n.nspname = current_schema() AND c.relkind = 'r'
+			  ORDER BY c.relname`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table stats: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var stats []models.TableStats
+	for rows.Next() {
+		var s models.TableStats
+		if err := rows.Scan(&s.Name, &s.Rows, &s.DataBytes, &s.IndexBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan table stats: %w", err)
+		}
+		s.TotalBytes = s.DataBytes + s.IndexBytes
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return stats, nil
+}
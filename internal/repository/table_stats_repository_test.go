@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableStatsRepository_GetTableStats(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := NewTableStatsRepository(db)
+
+	rows := sqlmock.NewRows([]string{"table_name", "table_rows", "data_length", "index_length"}).
+		AddRow("national_cases", 1000, 65536, 16384).
+		AddRow("province_cases", 34000, 4194304, 1048576)
+
+	mock.ExpectQuery(`SELECT table_name, COALESCE\(table_rows, 0\)`).
+		WillReturnRows(rows)
+
+	stats, err := repo.GetTableStats(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "national_cases", stats[0].Name)
+	assert.Equal(t, int64(1000), stats[0].Rows)
+	assert.Equal(t, int64(81920), stats[0].TotalBytes)
+	assert.Equal(t, int64(5242880), stats[1].TotalBytes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTableStatsRepository_GetTableStats_QueryError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := NewTableStatsRepository(db)
+
+	mock.ExpectQuery(`SELECT table_name`).WillReturnError(errors.New("access denied"))
+
+	stats, err := repo.GetTableStats(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.Contains(t, err.Error(), "failed to query table stats")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"log"
 	"fmt"
 
@@ -10,8 +11,8 @@ import (
 
 // TaskForceRepositoryInterface defines the contract for task force repository operations
 type TaskForceRepositoryInterface interface {
-	GetAllByProvinceID(provinceID int) ([]models.TaskForceByRegency, error)
-	GetPaginatedByProvinceID(provinceID, limit, offset int) ([]models.TaskForceByRegency, int, error)
+	GetAllByProvinceID(ctx context.Context, provinceID int) ([]models.TaskForceByRegency, error)
+	GetPaginatedByProvinceID(ctx context.Context, provinceID, limit, offset int) ([]models.TaskForceByRegency, int, error)
 }
 
 // TaskForceRepository handles database operations for task forces
@@ -25,10 +26,10 @@ func NewTaskForceRepository(db *database.DB) *TaskForceRepository {
 }
 
 // GetAllByProvinceID returns all task forces grouped by regency for a province
-func (r *TaskForceRepository) GetAllByProvinceID(provinceID int) ([]models.TaskForceByRegency, error) {
+func (r *TaskForceRepository) GetAllByProvinceID(ctx context.Context, provinceID int) ([]models.TaskForceByRegency, error) {
 	// Get regencies first
 	regQuery := `SELECT id, name FROM regencies WHERE province_id = ? ORDER BY name`
-	regRows, err := r.db.Query(regQuery, provinceID)
+	regRows, err := r.db.QueryContext(ctx, regQuery, provinceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query regencies: %w", err)
 	}
@@ -53,7 +54,7 @@ func (r *TaskForceRepository) GetAllByProvinceID(provinceID int) ([]models.TaskF
 	// Get task forces with contacts for each regency
 	for i := range result {
 		tfQuery := `SELECT tf.id, tf.regency_id, tf.name FROM task_forces tf WHERE tf.regency_id = ?`
-		tfRows, err := r.db.Query(tfQuery, result[i].RegencyID)
+		tfRows, err := r.db.QueryContext(ctx, tfQuery, result[i].RegencyID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to query task forces: %w", err)
 		}
@@ -73,7 +74,7 @@ func (r *TaskForceRepository) GetAllByProvinceID(provinceID int) ([]models.TaskF
 				FROM contacts c
 				JOIN contact_types ct ON c.contact_type_id = ct.id
 				WHERE c.contactable_type = 'App\\Models\\TaskForce' AND c.contactable_id = ?`
-			cRows, err := r.db.Query(cQuery, tf.ID)
+			cRows, err := r.db.QueryContext(ctx, cQuery, tf.ID)
 			if err != nil {
 				if err := tfRows.Close(); err != nil {
 					log.Printf("Error closing rows: %v", err)
@@ -113,14 +114,14 @@ func (r *TaskForceRepository) GetAllByProvinceID(provinceID int) ([]models.TaskF
 }
 
 // GetPaginatedByProvinceID returns a page of task forces grouped by regency, with total regency count
-func (r *TaskForceRepository) GetPaginatedByProvinceID(provinceID, limit, offset int) ([]models.TaskForceByRegency, int, error) {
+func (r *TaskForceRepository) GetPaginatedByProvinceID(ctx context.Context, provinceID, limit, offset int) ([]models.TaskForceByRegency, int, error) {
 	var total int
-	if err := r.db.QueryRow(`SELECT COUNT(*) FROM regencies WHERE province_id = ?`, provinceID).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM regencies WHERE province_id = ?`, provinceID).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to count regencies: %w", err)
 	}
 
 	regQuery := `SELECT id, name FROM regencies WHERE province_id = ? ORDER BY name LIMIT ? OFFSET ?`
-	regRows, err := r.db.Query(regQuery, provinceID, limit, offset)
+	regRows, err := r.db.QueryContext(ctx, regQuery, provinceID, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query regencies: %w", err)
 	}
@@ -144,7 +145,7 @@ func (r *TaskForceRepository) GetPaginatedByProvinceID(provinceID, limit, offset
 
 	for i := range result {
 		tfQuery := `SELECT tf.id, tf.regency_id, tf.name FROM task_forces tf WHERE tf.regency_id = ?`
-		tfRows, err := r.db.Query(tfQuery, result[i].RegencyID)
+		tfRows, err := r.db.QueryContext(ctx, tfQuery, result[i].RegencyID)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to query task forces: %w", err)
 		}
@@ -163,7 +164,7 @@ func (r *TaskForceRepository) GetPaginatedByProvinceID(provinceID, limit, offset
 				FROM contacts c
 				JOIN contact_types ct ON c.contact_type_id = ct.id
 				WHERE c.contactable_type = 'App\\Models\\TaskForce' AND c.contactable_id = ?`
-			cRows, err := r.db.Query(cQuery, tf.ID)
+			cRows, err := r.db.QueryContext(ctx, cQuery, tf.ID)
 			if err != nil {
 				if cerr := tfRows.Close(); cerr != nil {
 					log.Printf("Error closing rows: %v", cerr)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -31,7 +32,7 @@ func TestTaskForceRepository_GetAllByProvinceID(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows(contactCols).
 			AddRow(1, 1, "0812-0000-0000", "WhatsApp", "whatsapp"))
 
-	result, err := repo.GetAllByProvinceID(72)
+	result, err := repo.GetAllByProvinceID(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Equal(t, "Banggai", result[0].RegencyName)
@@ -47,7 +48,7 @@ func TestTaskForceRepository_GetAllByProvinceID_NoRegencies(t *testing.T) {
 		WithArgs(72).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
 
-	result, err := repo.GetAllByProvinceID(72)
+	result, err := repo.GetAllByProvinceID(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Empty(t, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -60,7 +61,7 @@ func TestTaskForceRepository_GetAllByProvinceID_RegencyQueryError(t *testing.T)
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, err := repo.GetAllByProvinceID(72)
+	_, err := repo.GetAllByProvinceID(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -76,7 +77,7 @@ func TestTaskForceRepository_GetAllByProvinceID_TaskForceQueryError(t *testing.T
 		WithArgs(7201).
 		WillReturnError(errors.New("task force error"))
 
-	_, err := repo.GetAllByProvinceID(72)
+	_, err := repo.GetAllByProvinceID(context.Background(), 72)
 	assert.Error(t, err)
 }
 
@@ -92,7 +93,7 @@ func TestTaskForceRepository_GetAllByProvinceID_EmptyTaskForces(t *testing.T) {
 		WithArgs(7201).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "regency_id", "name"}))
 
-	result, err := repo.GetAllByProvinceID(72)
+	result, err := repo.GetAllByProvinceID(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Empty(t, result[0].TaskForces)
@@ -120,7 +121,7 @@ func TestTaskForceRepository_GetPaginatedByProvinceID(t *testing.T) {
 		WithArgs(int64(1)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "contact_type_id", "contact", "name", "icon"}))
 
-	result, total, err := repo.GetPaginatedByProvinceID(72, 10, 0)
+	result, total, err := repo.GetPaginatedByProvinceID(context.Background(), 72, 10, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
 	assert.Len(t, result, 1)
@@ -134,7 +135,7 @@ func TestTaskForceRepository_GetPaginatedByProvinceID_CountError(t *testing.T) {
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetPaginatedByProvinceID(72, 10, 0)
+	_, _, err := repo.GetPaginatedByProvinceID(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
 
@@ -149,6 +150,6 @@ func TestTaskForceRepository_GetPaginatedByProvinceID_QueryError(t *testing.T) {
 		WithArgs(72, 10, 0).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetPaginatedByProvinceID(72, 10, 0)
+	_, _, err := repo.GetPaginatedByProvinceID(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// TestRepository reads the daily PCR and antigen test counts
+type TestRepository interface {
+	// GetNationalByDateRange returns national test counts in date order
+	GetNationalByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.DailyTests, error)
+	// GetProvinceByDateRange returns a province's test counts in date order,
+	// or those of every province when provinceID is empty
+	GetProvinceByDateRange(ctx context.Context, provinceID string, startDate, endDate time.Time) ([]models.DailyTests, error)
+}
+
+type testRepository struct {
+	db *database.DB
+}
+
+func NewTestRepository(db *database.DB) TestRepository {
+	return &testRepository{db: db}
+}
+
+func (r *testRepository) GetNationalByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.DailyTests, error) {
+	query := `SELECT id, province_id, date, pcr, antigen, cumulative_pcr, cumulative_antigen
+			  FROM tests
+			  WHERE province_id = '' AND date BETWEEN ? AND ?
+			  ORDER BY date ASC`
+	tests, err := r.query(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query national tests: %w", err)
+	}
+	return tests, nil
+}
+
+func (r *testRepository) GetProvinceByDateRange(ctx context.Context, provinceID string, startDate, endDate time.Time) ([]models.DailyTests, error) {
+	query := `SELECT id, province_id, date, pcr, antigen, cumulative_pcr, cumulative_antigen
+			  FROM tests
+			  WHERE province_id = ? AND date BETWEEN ? AND ?
+			  ORDER BY date ASC`
+	args := []interface{}{provinceID, startDate, endDate}
+	if provinceID == "" {
+		query = `SELECT id, province_id, date, pcr, antigen, cumulative_pcr, cumulative_antigen
+			  FROM tests
+			  WHERE province_id <> '' AND date BETWEEN ? AND ?
+			  ORDER BY province_id ASC, date ASC`
+		args = args[1:]
+	}
+	tests, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query province tests: %w", err)
+	}
+	return tests, nil
+}
+
+func (r *testRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.DailyTests, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var tests []models.DailyTests
+	for rows.Next() {
+		var t models.DailyTests
+		if err := rows.Scan(&t.ID, &t.ProvinceID, &t.Date, &t.PCR, &t.Antigen, &t.CumulativePCR, &t.CumulativeAntigen); err != nil {
+			return nil, fmt.Errorf("failed to scan tests: %w", err)
+		}
+		tests = append(tests, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return tests, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testColumns = []string{"id", "province_id", "date", "pcr", "antigen", "cumulative_pcr", "cumulative_antigen"}
+
+func TestTestRepository_GetNationalByDateRange(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	start := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM tests\s+WHERE province_id = '' AND date BETWEEN \? AND \?`).
+		WithArgs(start, end).
+		WillReturnRows(sqlmock.NewRows(testColumns).
+			AddRow(1, "", start, 400, 100, 4000, 1000).
+			AddRow(2, "", end, 300, 200, 4300, 1200))
+
+	tests, err := NewTestRepository(db).GetNationalByDateRange(context.Background(), start, end)
+
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+	assert.Equal(t, int64(400), tests[0].PCR)
+	assert.Equal(t, int64(1200), tests[1].CumulativeAntigen)
+	assert.Empty(t, tests[0].ProvinceID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTestRepository_GetProvinceByDateRange(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	start := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`WHERE province_id = \? AND date BETWEEN`).
+		WithArgs("72", start, start).
+		WillReturnRows(sqlmock.NewRows(testColumns).AddRow(1, "72", start, 40, 10, 400, 100))
+	mock.ExpectQuery(`WHERE province_id <> '' AND date BETWEEN`).
+		WithArgs(start, start).
+		WillReturnRows(sqlmock.NewRows(testColumns).AddRow(1, "72", start, 40, 10, 400, 100).AddRow(2, "73", start, 20, 5, 200, 50))
+
+	repo := NewTestRepository(db)
+	tests, err := repo.GetProvinceByDateRange(context.Background(), "72", start, start)
+	require.NoError(t, err)
+	assert.Len(t, tests, 1)
+	assert.Equal(t, "72", tests[0].ProvinceID)
+
+	tests, err = repo.GetProvinceByDateRange(context.Background(), "", start, start)
+	require.NoError(t, err)
+	assert.Len(t, tests, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTestRepository_QueryError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM tests`).WillReturnError(errors.New("table missing"))
+
+	_, err := NewTestRepository(db).GetNationalByDateRange(context.Background(), time.Now(), time.Now())
+	assert.ErrorContains(t, err, "failed to query national tests")
+}
@@ -1,38 +1,64 @@
 package repository
 
 import (
-	"log"
+	"context"
+	"database/sql"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/pkg/database"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
 )
 
 // VaccinationRepositoryInterface defines the contract for vaccination repository operations
 type VaccinationRepositoryInterface interface {
-	GetNationalVaccinations() ([]models.NationalVaccine, error)
-	GetNationalVaccinationsPaginated(limit, offset int) ([]models.NationalVaccine, int, error)
-	GetProvinceVaccinations(provinceID int) ([]models.ProvinceVaccine, error)
-	GetProvinceVaccinationsPaginated(provinceID, limit, offset int) ([]models.ProvinceVaccine, int, error)
-	GetVaccineLocations(provinceID int) ([]models.VaccineLocation, error)
-	GetVaccineLocationsPaginated(provinceID, limit, offset int) ([]models.VaccineLocation, int, error)
+	GetNationalVaccinations(ctx context.Context) ([]models.NationalVaccine, error)
+	GetNationalVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.NationalVaccine, int, error)
+	QueryNationalVaccinations(ctx context.Context, q VaccinationQuery) ([]models.NationalVaccine, int, error)
+	GetProvinceVaccinations(ctx context.Context, provinceID int) ([]models.ProvinceVaccine, error)
+	GetProvinceVaccinationsPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.ProvinceVaccine, int, error)
+	QueryProvinceVaccinations(ctx context.Context, provinceID int, q VaccinationQuery) ([]models.ProvinceVaccine, int, error)
+	// GetLatestProvinceVaccinations returns the most recent vaccination row of every province
+	GetLatestProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error)
+	GetVaccineLocations(ctx context.Context, provinceID int) ([]models.VaccineLocation, error)
+	GetVaccineLocationsPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.VaccineLocation, int, error)
 }
 
-// VaccinationRepository handles database operations for vaccination data
-type VaccinationRepository struct {
-	db *database.DB
+// VaccinationQuery filters, sorts and pages vaccination rows. Zero dates leave
+// the range open and a zero Limit returns every row.
+type VaccinationQuery struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Sort      utils.SortParams
+	Limit     int
+	Offset    int
 }
 
-// NewVaccinationRepository creates a new VaccinationRepository
-func NewVaccinationRepository(db *database.DB) *VaccinationRepository {
-	return &VaccinationRepository{db: db}
+// vaccinationSortColumns maps the API sort fields of vaccination rows to columns
+var vaccinationSortColumns = map[string]string{
+	"date":                    "date",
+	"day":                     "day",
+	"first_dose":              "first_vaccination_received",
+	"second_dose":             "second_vaccination_received",
+	"booster_dose":            "booster_vaccination_received",
+	"cumulative_first_dose":   "cumulative_first_vaccination_received",
+	"cumulative_second_dose":  "cumulative_second_vaccination_received",
+	"cumulative_booster_dose": "cumulative_booster_vaccination_received",
 }
 
-// GetNationalVaccinations returns all national vaccination data
-func (r *VaccinationRepository) GetNationalVaccinations() ([]models.NationalVaccine, error) {
-	query := `SELECT id, day, date, total_vaccination_target,
-		first_vaccination_received, second_vaccination_received,
-		cumulative_first_vaccination_received, cumulative_second_vaccination_received,
+// IsValidVaccinationSortField reports whether vaccination rows can be sorted by field
+func IsValidVaccinationSortField(field string) bool {
+	_, ok := vaccinationSortColumns[field]
+	return ok
+}
+
+// vaccineColumns are the dose columns shared by national_vaccines and province_vaccines
+const vaccineColumns = `total_vaccination_target,
+		first_vaccination_received, second_vaccination_received, booster_vaccination_received,
+		cumulative_first_vaccination_received, cumulative_second_vaccination_received, cumulative_booster_vaccination_received,
 		health_worker_vaccination_target, health_worker_first_vaccination_received, health_worker_second_vaccination_received,
 		cumulative_health_worker_first_vaccination_received, cumulative_health_worker_second_vaccination_received,
 		elderly_vaccination_target, elderly_first_vaccination_received, elderly_second_vaccination_received,
@@ -42,103 +68,67 @@ func (r *VaccinationRepository) GetNationalVaccinations() ([]models.NationalVacc
 		public_vaccination_target, public_first_vaccination_received, public_second_vaccination_received,
 		cumulative_public_first_vaccination_received, cumulative_public_second_vaccination_received,
 		teenager_vaccination_target, teenager_first_vaccination_received, teenager_second_vaccination_received,
-		cumulative_teenager_first_vaccination_received, cumulative_teenager_second_vaccination_received
-		FROM national_vaccines ORDER BY day ASC`
+		cumulative_teenager_first_vaccination_received, cumulative_teenager_second_vaccination_received`
 
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query national vaccinations: %w", err)
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
+const (
+	nationalVaccineSelect = `SELECT id, day, date, ` + vaccineColumns + ` FROM national_vaccines`
+	provinceVaccineSelect = `SELECT id, day, province_id, date, ` + vaccineColumns + ` FROM province_vaccines`
+)
 
-	var vaccines []models.NationalVaccine
-	for rows.Next() {
-		var v models.NationalVaccine
-		if err := rows.Scan(&v.ID, &v.Day, &v.Date, &v.TotalVaccinationTarget,
-			&v.FirstVaccinationReceived, &v.SecondVaccinationReceived,
-			&v.CumulativeFirstVaccinationReceived, &v.CumulativeSecondVaccinationReceived,
-			&v.HealthWorkerVaccinationTarget, &v.HealthWorkerFirstVaccinationReceived, &v.HealthWorkerSecondVaccinationReceived,
-			&v.CumulativeHealthWorkerFirstVaccinationReceived, &v.CumulativeHealthWorkerSecondVaccinationReceived,
-			&v.ElderlyVaccinationTarget, &v.ElderlyFirstVaccinationReceived, &v.ElderlySecondVaccinationReceived,
-			&v.CumulativeElderlyFirstVaccinationReceived, &v.CumulativeElderlySecondVaccinationReceived,
-			&v.PublicOfficerVaccinationTarget, &v.PublicOfficerFirstVaccinationReceived, &v.PublicOfficerSecondVaccinationReceived,
-			&v.CumulativePublicOfficerFirstVaccinationReceived, &v.CumulativePublicOfficerSecondVaccinationReceived,
-			&v.PublicVaccinationTarget, &v.PublicFirstVaccinationReceived, &v.PublicSecondVaccinationReceived,
-			&v.CumulativePublicFirstVaccinationReceived, &v.CumulativePublicSecondVaccinationReceived,
-			&v.TeenagerVaccinationTarget, &v.TeenagerFirstVaccinationReceived, &v.TeenagerSecondVaccinationReceived,
-			&v.CumulativeTeenagerFirstVaccinationReceived, &v.CumulativeTeenagerSecondVaccinationReceived,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan national vaccine: %w", err)
-		}
-		vaccines = append(vaccines, v)
+// vaccineDoseFields returns scan destinations matching vaccineColumns
+func vaccineDoseFields(v *models.NationalVaccine) []interface{} {
+	return []interface{}{&v.TotalVaccinationTarget,
+		&v.FirstVaccinationReceived, &v.SecondVaccinationReceived, &v.BoosterVaccinationReceived,
+		&v.CumulativeFirstVaccinationReceived, &v.CumulativeSecondVaccinationReceived, &v.CumulativeBoosterVaccinationReceived,
+		&v.HealthWorkerVaccinationTarget, &v.HealthWorkerFirstVaccinationReceived, &v.HealthWorkerSecondVaccinationReceived,
+		&v.CumulativeHealthWorkerFirstVaccinationReceived, &v.CumulativeHealthWorkerSecondVaccinationReceived,
+		&v.ElderlyVaccinationTarget, &v.ElderlyFirstVaccinationReceived, &v.ElderlySecondVaccinationReceived,
+		&v.CumulativeElderlyFirstVaccinationReceived, &v.CumulativeElderlySecondVaccinationReceived,
+		&v.PublicOfficerVaccinationTarget, &v.PublicOfficerFirstVaccinationReceived, &v.PublicOfficerSecondVaccinationReceived,
+		&v.CumulativePublicOfficerFirstVaccinationReceived, &v.CumulativePublicOfficerSecondVaccinationReceived,
+		&v.PublicVaccinationTarget, &v.PublicFirstVaccinationReceived, &v.PublicSecondVaccinationReceived,
+		&v.CumulativePublicFirstVaccinationReceived, &v.CumulativePublicSecondVaccinationReceived,
+		&v.TeenagerVaccinationTarget, &v.TeenagerFirstVaccinationReceived, &v.TeenagerSecondVaccinationReceived,
+		&v.CumulativeTeenagerFirstVaccinationReceived, &v.CumulativeTeenagerSecondVaccinationReceived,
 	}
-	return vaccines, rows.Err()
 }
 
-// GetProvinceVaccinations returns vaccination data for a province (default: SulTeng = 72)
-func (r *VaccinationRepository) GetProvinceVaccinations(provinceID int) ([]models.ProvinceVaccine, error) {
-	query := `SELECT id, day, province_id, date, total_vaccination_target,
-		first_vaccination_received, second_vaccination_received,
-		cumulative_first_vaccination_received, cumulative_second_vaccination_received,
-		health_worker_vaccination_target, health_worker_first_vaccination_received, health_worker_second_vaccination_received,
-		cumulative_health_worker_first_vaccination_received, cumulative_health_worker_second_vaccination_received,
-		elderly_vaccination_target, elderly_first_vaccination_received, elderly_second_vaccination_received,
-		cumulative_elderly_first_vaccination_received, cumulative_elderly_second_vaccination_received,
-		public_officer_vaccination_target, public_officer_first_vaccination_received, public_officer_second_vaccination_received,
-		cumulative_public_officer_first_vaccination_received, cumulative_public_officer_second_vaccination_received,
-		public_vaccination_target, public_first_vaccination_received, public_second_vaccination_received,
-		cumulative_public_first_vaccination_received, cumulative_public_second_vaccination_received,
-		teenager_vaccination_target, teenager_first_vaccination_received, teenager_second_vaccination_received,
-		cumulative_teenager_first_vaccination_received, cumulative_teenager_second_vaccination_received
-		FROM province_vaccines WHERE province_id = ? ORDER BY day ASC`
+// VaccinationRepository handles database operations for vaccination data
+type VaccinationRepository struct {
+	db *database.DB
+}
 
-	rows, err := r.db.Query(query, provinceID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query province vaccinations: %w", err)
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
+// NewVaccinationRepository creates a new VaccinationRepository
+func NewVaccinationRepository(db *database.DB) *VaccinationRepository {
+	return &VaccinationRepository{db: db}
+}
 
-	var vaccines []models.ProvinceVaccine
-	for rows.Next() {
-		var v models.ProvinceVaccine
-		if err := rows.Scan(&v.ID, &v.Day, &v.ProvinceID, &v.Date, &v.TotalVaccinationTarget,
-			&v.FirstVaccinationReceived, &v.SecondVaccinationReceived,
-			&v.CumulativeFirstVaccinationReceived, &v.CumulativeSecondVaccinationReceived,
-			&v.HealthWorkerVaccinationTarget, &v.HealthWorkerFirstVaccinationReceived, &v.HealthWorkerSecondVaccinationReceived,
-			&v.CumulativeHealthWorkerFirstVaccinationReceived, &v.CumulativeHealthWorkerSecondVaccinationReceived,
-			&v.ElderlyVaccinationTarget, &v.ElderlyFirstVaccinationReceived, &v.ElderlySecondVaccinationReceived,
-			&v.CumulativeElderlyFirstVaccinationReceived, &v.CumulativeElderlySecondVaccinationReceived,
-			&v.PublicOfficerVaccinationTarget, &v.PublicOfficerFirstVaccinationReceived, &v.PublicOfficerSecondVaccinationReceived,
-			&v.CumulativePublicOfficerFirstVaccinationReceived, &v.CumulativePublicOfficerSecondVaccinationReceived,
-			&v.PublicVaccinationTarget, &v.PublicFirstVaccinationReceived, &v.PublicSecondVaccinationReceived,
-			&v.CumulativePublicFirstVaccinationReceived, &v.CumulativePublicSecondVaccinationReceived,
-			&v.TeenagerVaccinationTarget, &v.TeenagerFirstVaccinationReceived, &v.TeenagerSecondVaccinationReceived,
-			&v.CumulativeTeenagerFirstVaccinationReceived, &v.CumulativeTeenagerSecondVaccinationReceived,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan province vaccine: %w", err)
-		}
-		vaccines = append(vaccines, v)
-	}
-	return vaccines, rows.Err()
+// GetNationalVaccinations returns all national vaccination data
+func (r *VaccinationRepository) GetNationalVaccinations(ctx context.Context) ([]models.NationalVaccine, error) {
+	return r.queryNational(ctx, nationalVaccineSelect+` ORDER BY day ASC`)
+}
+
+// GetProvinceVaccinations returns vaccination data for a province (default: SulTeng = 72)
+func (r *VaccinationRepository) GetProvinceVaccinations(ctx context.Context, provinceID int) ([]models.ProvinceVaccine, error) {
+	return r.queryProvince(ctx, provinceVaccineSelect+` WHERE province_id = ? ORDER BY day ASC`, provinceID)
+}
+
+// GetLatestProvinceVaccinations returns the most recent vaccination row of every province
+func (r *VaccinationRepository) GetLatestProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error) {
+	return r.queryProvince(ctx, provinceVaccineSelect+` WHERE (province_id, date) IN
+		(SELECT province_id, MAX(date) FROM province_vaccines GROUP BY province_id)
+		ORDER BY province_id ASC`)
 }
 
 // GetVaccineLocations returns vaccination centers for SulTeng regencies
-func (r *VaccinationRepository) GetVaccineLocations(provinceID int) ([]models.VaccineLocation, error) {
+func (r *VaccinationRepository) GetVaccineLocations(ctx context.Context, provinceID int) ([]models.VaccineLocation, error) {
 	query := `SELECT id, regency_id, name, address, operational_time,
 		is_first_vaccination, is_second_vaccination,
 		daily_vaccination_quota, vaccination_stock_remaining, notes
 		FROM vaccine_locations WHERE regency_id LIKE ? ORDER BY name`
 
 	likeParam := fmt.Sprintf("%d%%", provinceID)
-	rows, err := r.db.Query(query, likeParam)
+	rows, err := r.db.QueryContext(ctx, query, likeParam)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vaccine locations: %w", err)
 	}
@@ -162,123 +152,75 @@ func (r *VaccinationRepository) GetVaccineLocations(provinceID int) ([]models.Va
 }
 
 // GetNationalVaccinationsPaginated returns a page of national vaccination data with total count
-func (r *VaccinationRepository) GetNationalVaccinationsPaginated(limit, offset int) ([]models.NationalVaccine, int, error) {
+func (r *VaccinationRepository) GetNationalVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.NationalVaccine, int, error) {
 	var total int
-	if err := r.db.QueryRow(`SELECT COUNT(*) FROM national_vaccines`).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM national_vaccines`).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to count national vaccinations: %w", err)
 	}
 
-	query := `SELECT id, day, date, total_vaccination_target,
-		first_vaccination_received, second_vaccination_received,
-		cumulative_first_vaccination_received, cumulative_second_vaccination_received,
-		health_worker_vaccination_target, health_worker_first_vaccination_received, health_worker_second_vaccination_received,
-		cumulative_health_worker_first_vaccination_received, cumulative_health_worker_second_vaccination_received,
-		elderly_vaccination_target, elderly_first_vaccination_received, elderly_second_vaccination_received,
-		cumulative_elderly_first_vaccination_received, cumulative_elderly_second_vaccination_received,
-		public_officer_vaccination_target, public_officer_first_vaccination_received, public_officer_second_vaccination_received,
-		cumulative_public_officer_first_vaccination_received, cumulative_public_officer_second_vaccination_received,
-		public_vaccination_target, public_first_vaccination_received, public_second_vaccination_received,
-		cumulative_public_first_vaccination_received, cumulative_public_second_vaccination_received,
-		teenager_vaccination_target, teenager_first_vaccination_received, teenager_second_vaccination_received,
-		cumulative_teenager_first_vaccination_received, cumulative_teenager_second_vaccination_received
-		FROM national_vaccines ORDER BY day ASC LIMIT ? OFFSET ?`
-
-	rows, err := r.db.Query(query, limit, offset)
+	vaccines, err := r.queryNational(ctx, nationalVaccineSelect+` ORDER BY day ASC LIMIT ? OFFSET ?`, limit, offset)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query national vaccinations: %w", err)
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
-
-	var vaccines []models.NationalVaccine
-	for rows.Next() {
-		var v models.NationalVaccine
-		if err := rows.Scan(&v.ID, &v.Day, &v.Date, &v.TotalVaccinationTarget,
-			&v.FirstVaccinationReceived, &v.SecondVaccinationReceived,
-			&v.CumulativeFirstVaccinationReceived, &v.CumulativeSecondVaccinationReceived,
-			&v.HealthWorkerVaccinationTarget, &v.HealthWorkerFirstVaccinationReceived, &v.HealthWorkerSecondVaccinationReceived,
-			&v.CumulativeHealthWorkerFirstVaccinationReceived, &v.CumulativeHealthWorkerSecondVaccinationReceived,
-			&v.ElderlyVaccinationTarget, &v.ElderlyFirstVaccinationReceived, &v.ElderlySecondVaccinationReceived,
-			&v.CumulativeElderlyFirstVaccinationReceived, &v.CumulativeElderlySecondVaccinationReceived,
-			&v.PublicOfficerVaccinationTarget, &v.PublicOfficerFirstVaccinationReceived, &v.PublicOfficerSecondVaccinationReceived,
-			&v.CumulativePublicOfficerFirstVaccinationReceived, &v.CumulativePublicOfficerSecondVaccinationReceived,
-			&v.PublicVaccinationTarget, &v.PublicFirstVaccinationReceived, &v.PublicSecondVaccinationReceived,
-			&v.CumulativePublicFirstVaccinationReceived, &v.CumulativePublicSecondVaccinationReceived,
-			&v.TeenagerVaccinationTarget, &v.TeenagerFirstVaccinationReceived, &v.TeenagerSecondVaccinationReceived,
-			&v.CumulativeTeenagerFirstVaccinationReceived, &v.CumulativeTeenagerSecondVaccinationReceived,
-		); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan national vaccine: %w", err)
-		}
-		vaccines = append(vaccines, v)
+		return nil, 0, err
 	}
-	return vaccines, total, rows.Err()
+	return vaccines, total, nil
 }
 
 // GetProvinceVaccinationsPaginated returns a page of province vaccination data with total count
-func (r *VaccinationRepository) GetProvinceVaccinationsPaginated(provinceID, limit, offset int) ([]models.ProvinceVaccine, int, error) {
+func (r *VaccinationRepository) GetProvinceVaccinationsPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.ProvinceVaccine, int, error) {
 	var total int
-	if err := r.db.QueryRow(`SELECT COUNT(*) FROM province_vaccines WHERE province_id = ?`, provinceID).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM province_vaccines WHERE province_id = ?`, provinceID).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to count province vaccinations: %w", err)
 	}
 
-	query := `SELECT id, day, province_id, date, total_vaccination_target,
-		first_vaccination_received, second_vaccination_received,
-		cumulative_first_vaccination_received, cumulative_second_vaccination_received,
-		health_worker_vaccination_target, health_worker_first_vaccination_received, health_worker_second_vaccination_received,
-		cumulative_health_worker_first_vaccination_received, cumulative_health_worker_second_vaccination_received,
-		elderly_vaccination_target, elderly_first_vaccination_received, elderly_second_vaccination_received,
-		cumulative_elderly_first_vaccination_received, cumulative_elderly_second_vaccination_received,
-		public_officer_vaccination_target, public_officer_first_vaccination_received, public_officer_second_vaccination_received,
-		cumulative_public_officer_first_vaccination_received, cumulative_public_officer_second_vaccination_received,
-		public_vaccination_target, public_first_vaccination_received, public_second_vaccination_received,
-		cumulative_public_first_vaccination_received, cumulative_public_second_vaccination_received,
-		teenager_vaccination_target, teenager_first_vaccination_received, teenager_second_vaccination_received,
-		cumulative_teenager_first_vaccination_received, cumulative_teenager_second_vaccination_received
-		FROM province_vaccines WHERE province_id = ? ORDER BY day ASC LIMIT ? OFFSET ?`
+	vaccines, err := r.queryProvince(ctx, provinceVaccineSelect+` WHERE province_id = ? ORDER BY day ASC LIMIT ? OFFSET ?`, provinceID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return vaccines, total, nil
+}
 
-	rows, err := r.db.Query(query, provinceID, limit, offset)
+// QueryNationalVaccinations returns the national vaccination rows matching q
+// and the number of rows matching before paging
+func (r *VaccinationRepository) QueryNationalVaccinations(ctx context.Context, q VaccinationQuery) ([]models.NationalVaccine, int, error) {
+	where, args := q.where(nil, nil)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM national_vaccines`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count national vaccinations: %w", err)
+	}
+
+	query, args := q.paged(nationalVaccineSelect+where, args)
+	vaccines, err := r.queryNational(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query province vaccinations: %w", err)
+		return nil, 0, err
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
+	return vaccines, total, nil
+}
 
-	var vaccines []models.ProvinceVaccine
-	for rows.Next() {
-		var v models.ProvinceVaccine
-		if err := rows.Scan(&v.ID, &v.Day, &v.ProvinceID, &v.Date, &v.TotalVaccinationTarget,
-			&v.FirstVaccinationReceived, &v.SecondVaccinationReceived,
-			&v.CumulativeFirstVaccinationReceived, &v.CumulativeSecondVaccinationReceived,
-			&v.HealthWorkerVaccinationTarget, &v.HealthWorkerFirstVaccinationReceived, &v.HealthWorkerSecondVaccinationReceived,
-			&v.CumulativeHealthWorkerFirstVaccinationReceived, &v.CumulativeHealthWorkerSecondVaccinationReceived,
-			&v.ElderlyVaccinationTarget, &v.ElderlyFirstVaccinationReceived, &v.ElderlySecondVaccinationReceived,
-			&v.CumulativeElderlyFirstVaccinationReceived, &v.CumulativeElderlySecondVaccinationReceived,
-			&v.PublicOfficerVaccinationTarget, &v.PublicOfficerFirstVaccinationReceived, &v.PublicOfficerSecondVaccinationReceived,
-			&v.CumulativePublicOfficerFirstVaccinationReceived, &v.CumulativePublicOfficerSecondVaccinationReceived,
-			&v.PublicVaccinationTarget, &v.PublicFirstVaccinationReceived, &v.PublicSecondVaccinationReceived,
-			&v.CumulativePublicFirstVaccinationReceived, &v.CumulativePublicSecondVaccinationReceived,
-			&v.TeenagerVaccinationTarget, &v.TeenagerFirstVaccinationReceived, &v.TeenagerSecondVaccinationReceived,
-			&v.CumulativeTeenagerFirstVaccinationReceived, &v.CumulativeTeenagerSecondVaccinationReceived,
-		); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan province vaccine: %w", err)
-		}
-		vaccines = append(vaccines, v)
+// QueryProvinceVaccinations returns the vaccination rows of a province
+// matching q and the number of rows matching before paging
+func (r *VaccinationRepository) QueryProvinceVaccinations(ctx context.Context, provinceID int, q VaccinationQuery) ([]models.ProvinceVaccine, int, error) {
+	where, args := q.where([]string{"province_id = ?"}, []interface{}{provinceID})
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM province_vaccines`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count province vaccinations: %w", err)
+	}
+
+	query, args := q.paged(provinceVaccineSelect+where, args)
+	vaccines, err := r.queryProvince(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
-	return vaccines, total, rows.Err()
+	return vaccines, total, nil
 }
 
 // GetVaccineLocationsPaginated returns a page of vaccine locations with total count
-func (r *VaccinationRepository) GetVaccineLocationsPaginated(provinceID, limit, offset int) ([]models.VaccineLocation, int, error) {
+func (r *VaccinationRepository) GetVaccineLocationsPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.VaccineLocation, int, error) {
 	likeParam := fmt.Sprintf("%d%%", provinceID)
 
 	var total int
-	if err := r.db.QueryRow(`SELECT COUNT(*) FROM vaccine_locations WHERE regency_id LIKE ?`, likeParam).Scan(&total); err != nil {
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vaccine_locations WHERE regency_id LIKE ?`, likeParam).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to count vaccine locations: %w", err)
 	}
 
@@ -287,7 +229,7 @@ func (r *VaccinationRepository) GetVaccineLocationsPaginated(provinceID, limit,
 		daily_vaccination_quota, vaccination_stock_remaining, notes
 		FROM vaccine_locations WHERE regency_id LIKE ? ORDER BY name LIMIT ? OFFSET ?`
 
-	rows, err := r.db.Query(query, likeParam, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, likeParam, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query vaccine locations: %w", err)
 	}
@@ -309,3 +251,82 @@ func (r *VaccinationRepository) GetVaccineLocationsPaginated(provinceID, limit,
 	}
 	return locations, total, rows.Err()
 }
+
+func (r *VaccinationRepository) queryNational(ctx context.Context, query string, args ...interface{}) ([]models.NationalVaccine, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query national vaccinations: %w", err)
+	}
+	defer closeVaccineRows(rows)
+
+	var vaccines []models.NationalVaccine
+	for rows.Next() {
+		var v models.NationalVaccine
+		dest := append([]interface{}{&v.ID, &v.Day, &v.Date}, vaccineDoseFields(&v)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan national vaccine: %w", err)
+		}
+		vaccines = append(vaccines, v)
+	}
+	return vaccines, rows.Err()
+}
+
+func (r *VaccinationRepository) queryProvince(ctx context.Context, query string, args ...interface{}) ([]models.ProvinceVaccine, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query province vaccinations: %w", err)
+	}
+	defer closeVaccineRows(rows)
+
+	var vaccines []models.ProvinceVaccine
+	for rows.Next() {
+		var v models.ProvinceVaccine
+		dest := append([]interface{}{&v.ID, &v.Day, &v.ProvinceID, &v.Date}, vaccineDoseFields(&v.NationalVaccine)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan province vaccine: %w", err)
+		}
+		vaccines = append(vaccines, v)
+	}
+	return vaccines, rows.Err()
+}
+
+func closeVaccineRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Printf("Error closing rows: %v", err)
+	}
+}
+
+// where adds the date range of q to the conditions
+func (q VaccinationQuery) where(conditions []string, args []interface{}) (string, []interface{}) {
+	if !q.StartDate.IsZero() {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, q.StartDate)
+	}
+	if !q.EndDate.IsZero() {
+		conditions = append(conditions, "date <= ?")
+		args = append(args, q.EndDate)
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// paged adds the order and page of q to a query
+func (q VaccinationQuery) paged(query string, args []interface{}) (string, []interface{}) {
+	column, ok := vaccinationSortColumns[q.Sort.Field]
+	if !ok {
+		column = "date"
+	}
+	order := "ASC"
+	if q.Sort.Order == "desc" {
+		order = "DESC"
+	}
+	// day breaks ties so pages stay stable
+	query += " ORDER BY " + column + " " + order + ", day " + order
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	}
+	return query, args
+}
@@ -1,19 +1,22 @@
 package repository
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 var nationalVaccineColumns = []string{
 	"id", "day", "date", "total_vaccination_target",
-	"first_vaccination_received", "second_vaccination_received",
-	"cumulative_first_vaccination_received", "cumulative_second_vaccination_received",
+	"first_vaccination_received", "second_vaccination_received", "booster_vaccination_received",
+	"cumulative_first_vaccination_received", "cumulative_second_vaccination_received", "cumulative_booster_vaccination_received",
 	"health_worker_vaccination_target", "health_worker_first_vaccination_received", "health_worker_second_vaccination_received",
 	"cumulative_health_worker_first_vaccination_received", "cumulative_health_worker_second_vaccination_received",
 	"elderly_vaccination_target", "elderly_first_vaccination_received", "elderly_second_vaccination_received",
@@ -28,7 +31,7 @@ var nationalVaccineColumns = []string{
 
 func addNationalVaccineRow(rows *sqlmock.Rows, now time.Time) *sqlmock.Rows {
 	vals := []driver.Value{1, 1, now}
-	for i := 0; i < 30; i++ {
+	for i := 0; i < 32; i++ {
 		vals = append(vals, int64(100))
 	}
 	return rows.AddRow(vals...)
@@ -48,7 +51,7 @@ func TestVaccinationRepository_GetNationalVaccinations(t *testing.T) {
 	mock.ExpectQuery(`SELECT id, day, date`).
 		WillReturnRows(rows)
 
-	vaccines, err := repo.GetNationalVaccinations()
+	vaccines, err := repo.GetNationalVaccinations(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, vaccines, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -65,8 +68,8 @@ func TestVaccinationRepository_GetProvinceVaccinations(t *testing.T) {
 	now := time.Now()
 
 	provinceCols := []string{"id", "day", "province_id", "date", "total_vaccination_target",
-		"first_vaccination_received", "second_vaccination_received",
-		"cumulative_first_vaccination_received", "cumulative_second_vaccination_received",
+		"first_vaccination_received", "second_vaccination_received", "booster_vaccination_received",
+		"cumulative_first_vaccination_received", "cumulative_second_vaccination_received", "cumulative_booster_vaccination_received",
 		"health_worker_vaccination_target", "health_worker_first_vaccination_received", "health_worker_second_vaccination_received",
 		"cumulative_health_worker_first_vaccination_received", "cumulative_health_worker_second_vaccination_received",
 		"elderly_vaccination_target", "elderly_first_vaccination_received", "elderly_second_vaccination_received",
@@ -80,7 +83,7 @@ func TestVaccinationRepository_GetProvinceVaccinations(t *testing.T) {
 	}
 
 	vals := []driver.Value{1, 1, 72, now}
-	for i := 0; i < 30; i++ {
+	for i := 0; i < 32; i++ {
 		vals = append(vals, int64(50))
 	}
 	rows := sqlmock.NewRows(provinceCols).AddRow(vals...)
@@ -88,13 +91,35 @@ func TestVaccinationRepository_GetProvinceVaccinations(t *testing.T) {
 		WithArgs(72).
 		WillReturnRows(rows)
 
-	vaccines, err := repo.GetProvinceVaccinations(72)
+	vaccines, err := repo.GetProvinceVaccinations(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, vaccines, 1)
 	assert.Equal(t, 72, vaccines[0].ProvinceID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestVaccinationRepository_GetLatestProvinceVaccinations(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewVaccinationRepository(db)
+
+	cols := []string{"id", "day", "province_id", "date"}
+	vals := []driver.Value{9, 400, 72, time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)}
+	for i := 0; i < 32; i++ {
+		cols = append(cols, fmt.Sprintf("dose_%d", i))
+		vals = append(vals, int64(1000))
+	}
+	mock.ExpectQuery(`FROM province_vaccines WHERE \(province_id, date\) IN\s+\(SELECT province_id, MAX\(date\) FROM province_vaccines GROUP BY province_id\)`).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(vals...))
+
+	vaccines, err := repo.GetLatestProvinceVaccinations(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, vaccines, 1)
+	assert.Equal(t, 72, vaccines[0].ProvinceID)
+	assert.Equal(t, int64(1000), vaccines[0].CumulativeFirstVaccinationReceived)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestVaccinationRepository_GetVaccineLocations(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() {
@@ -114,14 +139,13 @@ func TestVaccinationRepository_GetVaccineLocations(t *testing.T) {
 		WithArgs("72%").
 		WillReturnRows(rows)
 
-	locs, err := repo.GetVaccineLocations(72)
+	locs, err := repo.GetVaccineLocations(context.Background(), 72)
 	assert.NoError(t, err)
 	assert.Len(t, locs, 1)
 	assert.Equal(t, "Puskesmas A", locs[0].Name)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-
 func TestVaccinationRepository_GetNationalVaccinationsPaginated(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer func() { _ = db.Close() }()
@@ -135,7 +159,7 @@ func TestVaccinationRepository_GetNationalVaccinationsPaginated(t *testing.T) {
 		WithArgs(10, 0).
 		WillReturnRows(addNationalVaccineRow(sqlmock.NewRows(nationalVaccineColumns), now))
 
-	result, total, err := repo.GetNationalVaccinationsPaginated(10, 0)
+	result, total, err := repo.GetNationalVaccinationsPaginated(context.Background(), 10, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 2, total)
 	assert.Len(t, result, 1)
@@ -150,7 +174,7 @@ func TestVaccinationRepository_GetNationalVaccinationsPaginated_CountError(t *te
 	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM national_vaccines`).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetNationalVaccinationsPaginated(10, 0)
+	_, _, err := repo.GetNationalVaccinationsPaginated(context.Background(), 10, 0)
 	assert.Error(t, err)
 }
 
@@ -161,8 +185,8 @@ func TestVaccinationRepository_GetProvinceVaccinationsPaginated(t *testing.T) {
 	now := time.Now()
 
 	provinceCols := []string{"id", "day", "province_id", "date", "total_vaccination_target",
-		"first_vaccination_received", "second_vaccination_received",
-		"cumulative_first_vaccination_received", "cumulative_second_vaccination_received",
+		"first_vaccination_received", "second_vaccination_received", "booster_vaccination_received",
+		"cumulative_first_vaccination_received", "cumulative_second_vaccination_received", "cumulative_booster_vaccination_received",
 		"health_worker_vaccination_target", "health_worker_first_vaccination_received", "health_worker_second_vaccination_received",
 		"cumulative_health_worker_first_vaccination_received", "cumulative_health_worker_second_vaccination_received",
 		"elderly_vaccination_target", "elderly_first_vaccination_received", "elderly_second_vaccination_received",
@@ -175,7 +199,7 @@ func TestVaccinationRepository_GetProvinceVaccinationsPaginated(t *testing.T) {
 		"cumulative_teenager_first_vaccination_received", "cumulative_teenager_second_vaccination_received",
 	}
 	vals := []driver.Value{1, 1, 72, now}
-	for i := 0; i < 30; i++ {
+	for i := 0; i < 32; i++ {
 		vals = append(vals, int64(50))
 	}
 
@@ -187,7 +211,7 @@ func TestVaccinationRepository_GetProvinceVaccinationsPaginated(t *testing.T) {
 		WithArgs(72, 10, 0).
 		WillReturnRows(sqlmock.NewRows(provinceCols).AddRow(vals...))
 
-	result, total, err := repo.GetProvinceVaccinationsPaginated(72, 10, 0)
+	result, total, err := repo.GetProvinceVaccinationsPaginated(context.Background(), 72, 10, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
 	assert.Len(t, result, 1)
@@ -203,7 +227,7 @@ func TestVaccinationRepository_GetProvinceVaccinationsPaginated_CountError(t *te
 		WithArgs(72).
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetProvinceVaccinationsPaginated(72, 10, 0)
+	_, _, err := repo.GetProvinceVaccinationsPaginated(context.Background(), 72, 10, 0)
 	assert.Error(t, err)
 }
 
@@ -224,7 +248,7 @@ func TestVaccinationRepository_GetVaccineLocationsPaginated(t *testing.T) {
 		WithArgs("72%", 10, 0).
 		WillReturnRows(sqlmock.NewRows(locCols).AddRow(1, 7201, "Puskesmas A", "Jl. Raya", "08:00-16:00", true, true, 100, 50, ""))
 
-	result, total, err := repo.GetVaccineLocationsPaginated(72, 10, 0)
+	result, total, err := repo.GetVaccineLocationsPaginated(context.Background(), 72, 10, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, total)
 	assert.Len(t, result, 1)
@@ -240,6 +264,64 @@ func TestVaccinationRepository_GetVaccineLocationsPaginated_CountError(t *testin
 		WithArgs("72%").
 		WillReturnError(errors.New("db error"))
 
-	_, _, err := repo.GetVaccineLocationsPaginated(72, 10, 0)
+	_, _, err := repo.GetVaccineLocationsPaginated(context.Background(), 72, 10, 0)
+	assert.Error(t, err)
+}
+
+func TestVaccinationRepository_QueryNationalVaccinations(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewVaccinationRepository(db)
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM national_vaccines WHERE date >= \? AND date <= \?`).
+		WithArgs(start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(30))
+	mock.ExpectQuery(`FROM national_vaccines WHERE date >= \? AND date <= \? ORDER BY booster_vaccination_received DESC, day DESC LIMIT \? OFFSET \?`).
+		WithArgs(start, end, 10, 20).
+		WillReturnRows(addNationalVaccineRow(sqlmock.NewRows(nationalVaccineColumns), start))
+
+	result, total, err := repo.QueryNationalVaccinations(context.Background(), VaccinationQuery{
+		StartDate: start, EndDate: end,
+		Sort:  utils.SortParams{Field: "booster_dose", Order: "desc"},
+		Limit: 10, Offset: 20,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 30, total)
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(100), result[0].CumulativeBoosterVaccinationReceived)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVaccinationRepository_QueryProvinceVaccinations_All(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewVaccinationRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_vaccines WHERE province_id = \?`).
+		WithArgs(72).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`FROM province_vaccines WHERE province_id = \? ORDER BY date ASC, day ASC$`).
+		WithArgs(72).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, total, err := repo.QueryProvinceVaccinations(context.Background(), 72, VaccinationQuery{Sort: utils.SortParams{Field: "unknown"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVaccinationRepository_QueryProvinceVaccinations_CountError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewVaccinationRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM province_vaccines`).
+		WithArgs(72).
+		WillReturnError(errors.New("db error"))
+
+	_, _, err := repo.QueryProvinceVaccinations(context.Background(), 72, VaccinationQuery{})
 	assert.Error(t, err)
 }
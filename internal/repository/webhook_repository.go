@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+)
+
+// WebhookRepository stores webhook subscriptions and their delivery log
+type WebhookRepository interface {
+	ListActiveSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	ListSubscriptionStats(ctx context.Context) ([]models.WebhookSubscriptionStats, error)
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (int64, error)
+	CreateDelivery(ctx context.Context, d models.WebhookDelivery) (int64, error)
+	GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, d models.WebhookDelivery) error
+	GetCursor(ctx context.Context, name string) (int64, bool, error)
+	SetCursor(ctx context.Context, name string, lastID int64) error
+}
+
+type webhookRepository struct {
+	db *database.DB
+}
+
+func NewWebhookRepository(db *database.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// maxDeliveryErrorLength matches webhook_deliveries.last_error
+const maxDeliveryErrorLength = 512
+
+const webhookSubscriptionColumns = `id, url, secret, events, filters, active, created_at`
+
+// webhookDeliveryColumns excludes the payload, which only single-delivery reads load
+const webhookDeliveryColumns = `id, delivery_id, subscription_id, event, status, attempts,
+	next_attempt_at, response_status, last_error, created_at, delivered_at`
+
+func (r *webhookRepository) ListActiveSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions WHERE active = TRUE ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var subs []models.WebhookSubscription
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return subs, nil
+}
+
+func (r *webhookRepository) ListSubscriptionStats(ctx context.Context) ([]models.WebhookSubscriptionStats, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT s.id, s.url, s.secret, s.events, s.filters, s.active, s.created_at,
+		COALESCE(SUM(d.status = ?), 0), COALESCE(SUM(d.status = ?), 0), COALESCE(SUM(d.status = ?), 0),
+		MAX(d.created_at)
+		FROM webhook_subscriptions s
+		LEFT JOIN webhook_deliveries d ON d.subscription_id = s.id
+		GROUP BY s.id, s.url, s.secret, s.events, s.filters, s.active, s.created_at
+		ORDER BY s.id`,
+		models.WebhookDeliveryDelivered, models.WebhookDeliveryRetrying, models.WebhookDeliveryFailed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscription stats: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var stats []models.WebhookSubscriptionStats
+	for rows.Next() {
+		var s models.WebhookSubscriptionStats
+		var events string
+		var filters []byte
+		var lastDeliveryAt sql.NullTime
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, &events, &filters, &s.Active, &s.CreatedAt,
+			&s.Delivered, &s.Retrying, &s.Failed, &lastDeliveryAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription stats: %w", err)
+		}
+		s.Events = splitEvents(events)
+		if s.Filter, err = parseWebhookFilter(s.ID, filters); err != nil {
+			return nil, err
+		}
+		if lastDeliveryAt.Valid {
+			s.LastDeliveryAt = &lastDeliveryAt.Time
+		}
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return stats, nil
+}
+
+// GetSubscription returns nil when the subscription does not exist. It reads
+// from the primary, so a subscription is found right after it was created and
+// deliveries stop as soon as it is deactivated.
+func (r *webhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return scanWebhookSubscription(r.db.QueryRowContext(database.WithPrimary(ctx), `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = ?`, id))
+}
+
+// CreateSubscription stores a subscription. A filter without criteria is stored as NULL.
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (int64, error) {
+	var filters interface{}
+	if !sub.Filter.IsEmpty() {
+		data, err := json.Marshal(sub.Filter)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode webhook filter: %w", err)
+		}
+		filters = string(data)
+	}
+	id, err := r.db.InsertContext(ctx, `INSERT INTO webhook_subscriptions
+		(url, secret, events, filters, active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		sub.URL, sub.Secret, strings.Join(sub.Events, ","), filters, sub.Active, sub.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return id, nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, d models.WebhookDelivery) (int64, error) {
+	id, err := r.db.InsertContext(ctx, `INSERT INTO webhook_deliveries
+		(delivery_id, subscription_id, event, payload, status, attempts, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		d.DeliveryID, d.SubscriptionID, d.Event, string(d.Payload), d.Status, d.Attempts, d.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return id, nil
+}
+
+// GetDelivery returns the delivery including its payload, or nil when it does not exist
+func (r *webhookRepository) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+webhookDeliveryColumns+`, payload FROM webhook_deliveries WHERE id = ?`, id)
+	return scanWebhookDelivery(row, true)
+}
+
+// ListDeliveries returns the most recent deliveries of a subscription, newest first
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	return r.queryDeliveries(ctx, false, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE subscription_id = ? ORDER BY id DESC LIMIT ?`, subscriptionID, limit)
+}
+
+// ListDueDeliveries returns retrying deliveries whose next attempt is due, with
+// payloads. It reads from the primary so a delivery just attempted is not sent
+// again from a lagging replica.
+func (r *webhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	return r.queryDeliveries(database.WithPrimary(ctx), true, `SELECT `+webhookDeliveryColumns+`, payload FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`,
+		models.WebhookDeliveryRetrying, now, limit)
+}
+
+// RecordAttempt stores the status, attempt count, schedule and outcome of a delivery.
+// delivered_at is set when the status is delivered.
+func (r *webhookRepository) RecordAttempt(ctx context.Context, d models.WebhookDelivery) error {
+	var deliveredAt interface{}
+	if d.Status == models.WebhookDeliveryDelivered {
+		deliveredAt = time.Now().UTC()
+	}
+	lastError := d.LastError
+	if lastError != nil && len(*lastError) > maxDeliveryErrorLength {
+		truncated := (*lastError)[:maxDeliveryErrorLength]
+		lastError = &truncated
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, response_status = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?`,
+		d.Status, d.Attempts, nullableTime(d.NextAttemptAt), nullableInt(d.ResponseStatus), nullableString(lastError), deliveredAt, d.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery %d: %w", d.ID, err)
+	}
+	return nil
+}
+
+// GetCursor returns the last row ID stored under name, read from the primary
+// so it is never behind the last SetCursor. It reports false when the cursor
+// was never set.
+func (r *webhookRepository) GetCursor(ctx context.Context, name string) (int64, bool, error) {
+	var lastID int64
+	err := r.db.QueryRowContext(database.WithPrimary(ctx), `SELECT last_id FROM webhook_cursors WHERE name = ?`, name).Scan(&lastID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get webhook cursor %s: %w", name, err)
+	}
+	return lastID, true, nil
+}
+
+// SetCursor creates or moves the cursor stored under name
+func (r *webhookRepository) SetCursor(ctx context.Context, name string, lastID int64) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO webhook_cursors (name, last_id, updated_at)
+		VALUES (?, ?, ?)
+		`+r.db.Dialect().Upsert([]string{"name"}, "last_id", "updated_at"),
+		name, lastID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set webhook cursor %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) queryDeliveries(ctx context.Context, withPayload bool, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows, withPayload)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return deliveries, nil
+}
+
+func scanWebhookSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	var events string
+	var filters []byte
+	if err := row.Scan(&s.ID, &s.URL, &s.Secret, &events, &filters, &s.Active, &s.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+	}
+	s.Events = splitEvents(events)
+	filter, err := parseWebhookFilter(s.ID, filters)
+	if err != nil {
+		return nil, err
+	}
+	s.Filter = filter
+	return &s, nil
+}
+
+// parseWebhookFilter decodes the filters column; NULL is an empty filter
+func parseWebhookFilter(subscriptionID int64, data []byte) (models.WebhookFilter, error) {
+	var filter models.WebhookFilter
+	if len(data) == 0 {
+		return filter, nil
+	}
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return filter, fmt.Errorf("invalid filters of webhook subscription %d: %w", subscriptionID, err)
+	}
+	return filter, nil
+}
+
+func scanWebhookDelivery(row rowScanner, withPayload bool) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var nextAttemptAt, deliveredAt sql.NullTime
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	dest := []interface{}{&d.ID, &d.DeliveryID, &d.SubscriptionID, &d.Event, &d.Status, &d.Attempts,
+		&nextAttemptAt, &responseStatus, &lastError, &d.CreatedAt, &deliveredAt}
+	var payload []byte
+	if withPayload {
+		dest = append(dest, &payload)
+	}
+	if err := row.Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+	if nextAttemptAt.Valid {
+		d.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		d.ResponseStatus = &status
+	}
+	if lastError.Valid {
+		d.LastError = &lastError.String
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	if withPayload {
+		d.Payload = payload
+	}
+	return &d, nil
+}
+
+// splitEvents parses the comma separated events column
+func splitEvents(events string) []string {
+	var out []string
+	for _, e := range strings.Split(events, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func nullableInt(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullableString(v *string) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullableTime(v *time.Time) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRepository_ListActiveSubscriptions(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, url, secret, events, filters, active, created_at\s+FROM webhook_subscriptions WHERE active = TRUE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "events", "filters", "active", "created_at"}).
+			AddRow(1, "https://a.example/hook", "s1", "*", nil, true, now).
+			AddRow(2, "https://b.example/hook", "s2", "sync.completed, other.event", []byte(`{"province_ids":["72"],"rt_crosses_one":true}`), true, now))
+
+	subs, err := NewWebhookRepository(db).ListActiveSubscriptions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, subs, 2)
+	assert.Equal(t, []string{"sync.completed", "other.event"}, subs[1].Events)
+	assert.True(t, subs[1].Wants(models.WebhookEventSyncCompleted))
+	assert.True(t, subs[0].Filter.IsEmpty())
+	assert.Equal(t, models.WebhookFilter{ProvinceIDs: []string{"72"}, RtCrossesOne: true}, subs[1].Filter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_CreateDelivery(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`INSERT INTO webhook_deliveries`).
+		WithArgs("abc", int64(1), "sync.completed", `{"id":"abc"}`, models.WebhookDeliveryPending, 0, now).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	id, err := NewWebhookRepository(db).CreateDelivery(context.Background(), models.WebhookDelivery{
+		DeliveryID: "abc", SubscriptionID: 1, Event: "sync.completed", Payload: []byte(`{"id":"abc"}`),
+		Status: models.WebhookDeliveryPending, CreatedAt: now,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_RecordAttempt(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	status := 503
+	lastError := "subscriber responded with status 503"
+	next := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE webhook_deliveries`).
+		WithArgs(models.WebhookDeliveryRetrying, 2, next, 503, lastError, nil, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := NewWebhookRepository(db).RecordAttempt(context.Background(), models.WebhookDelivery{
+		ID: 5, Status: models.WebhookDeliveryRetrying, Attempts: 2, NextAttemptAt: &next,
+		ResponseStatus: &status, LastError: &lastError,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var webhookDeliveryCols = []string{"id", "delivery_id", "subscription_id", "event", "status", "attempts",
+	"next_attempt_at", "response_status", "last_error", "created_at", "delivered_at"}
+
+func TestWebhookRepository_ListDeliveries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, delivery_id, .* FROM webhook_deliveries\s+WHERE subscription_id = \? ORDER BY id DESC LIMIT \?`).
+		WithArgs(int64(1), 50).
+		WillReturnRows(sqlmock.NewRows(webhookDeliveryCols).
+			AddRow(2, "d2", 1, "sync.completed", models.WebhookDeliveryRetrying, 1, now, 502, "subscriber responded with status 502", now, nil).
+			AddRow(1, "d1", 1, "sync.completed", models.WebhookDeliveryDelivered, 1, nil, 204, nil, now, now))
+
+	deliveries, err := NewWebhookRepository(db).ListDeliveries(context.Background(), 1, 50)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, 502, *deliveries[0].ResponseStatus)
+	assert.NotNil(t, deliveries[0].NextAttemptAt)
+	assert.Nil(t, deliveries[0].Payload)
+	assert.Nil(t, deliveries[1].LastError)
+	assert.NotNil(t, deliveries[1].DeliveredAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetDelivery(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, delivery_id, .*, payload FROM webhook_deliveries WHERE id = \?`).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(append(webhookDeliveryCols, "payload")).
+			AddRow(3, "d3", 1, "sync.completed", models.WebhookDeliveryFailed, 6, nil, nil, "timeout", now, nil, []byte(`{"id":"d3"}`)))
+
+	d, err := NewWebhookRepository(db).GetDelivery(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"d3"}`, string(d.Payload))
+	assert.Nil(t, d.ResponseStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetDelivery_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM webhook_deliveries WHERE id = \?`).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(append(webhookDeliveryCols, "payload")))
+
+	d, err := NewWebhookRepository(db).GetDelivery(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+}
+
+func TestWebhookRepository_ListSubscriptionStats(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM webhook_subscriptions s\s+LEFT JOIN webhook_deliveries d`).
+		WithArgs(models.WebhookDeliveryDelivered, models.WebhookDeliveryRetrying, models.WebhookDeliveryFailed).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "events", "filters", "active", "created_at", "delivered", "retrying", "failed", "last"}).
+			AddRow(1, "https://a.example/hook", "s1", "*", nil, true, now, 10, 1, 2, now).
+			AddRow(2, "https://b.example/hook", "s2", "*", []byte(`{"corrections_only":true}`), false, now, 0, 0, 0, nil))
+
+	stats, err := NewWebhookRepository(db).ListSubscriptionStats(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, 10, stats[0].Delivered)
+	assert.Equal(t, 2, stats[0].Failed)
+	assert.Nil(t, stats[1].LastDeliveryAt)
+	assert.True(t, stats[1].Filter.CorrectionsOnly)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetSubscription_InvalidFilter(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM webhook_subscriptions WHERE id = \?`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "events", "filters", "active", "created_at"}).
+			AddRow(1, "https://a.example/hook", "s1", "*", []byte(`{"province_ids":72}`), true, time.Now()))
+
+	_, err := NewWebhookRepository(db).GetSubscription(context.Background(), 1)
+
+	assert.ErrorContains(t, err, "invalid filters of webhook subscription 1")
+}
+
+func TestWebhookRepository_CreateSubscription(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(`INSERT INTO webhook_subscriptions`).
+		WithArgs("https://a.example/hook", "s1", "national_case.created,province_case.created", `{"province_ids":["72"]}`, true, now).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec(`INSERT INTO webhook_subscriptions`).
+		WithArgs("https://b.example/hook", "s2", "*", nil, true, now).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	repo := NewWebhookRepository(db)
+	id, err := repo.CreateSubscription(context.Background(), models.WebhookSubscription{
+		URL: "https://a.example/hook", Secret: "s1", Active: true, CreatedAt: now,
+		Events: []string{models.WebhookEventNationalCaseCreated, models.WebhookEventProvinceCaseCreated},
+		Filter: models.WebhookFilter{ProvinceIDs: []string{"72"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), id)
+
+	id, err = repo.CreateSubscription(context.Background(), models.WebhookSubscription{
+		URL: "https://b.example/hook", Secret: "s2", Events: []string{"*"}, Active: true, CreatedAt: now,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_Cursor(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT last_id FROM webhook_cursors WHERE name = \?`).WithArgs("national_cases").
+		WillReturnRows(sqlmock.NewRows([]string{"last_id"}))
+	mock.ExpectQuery(`SELECT last_id FROM webhook_cursors WHERE name = \?`).WithArgs("province_cases").
+		WillReturnRows(sqlmock.NewRows([]string{"last_id"}).AddRow(42))
+	mock.ExpectExec(`INSERT INTO webhook_cursors .* ON DUPLICATE KEY UPDATE`).
+		WithArgs("province_cases", int64(50), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewWebhookRepository(db)
+	_, ok, err := repo.GetCursor(context.Background(), "national_cases")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	lastID, ok, err := repo.GetCursor(context.Background(), "province_cases")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), lastID)
+
+	assert.NoError(t, repo.SetCursor(context.Background(), "province_cases", 50))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_SetCursor_Postgres(t *testing.T) {
+	db, mock := setupPostgresMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO webhook_cursors \(name, last_id, updated_at\)\s+VALUES \(\$1, \$2, \$3\)\s+ON CONFLICT \(name\) DO UPDATE SET last_id = EXCLUDED.last_id, updated_at = EXCLUDED.updated_at`).
+		WithArgs("province_cases", int64(50), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, NewWebhookRepository(db).SetCursor(context.Background(), "province_cases", 50))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
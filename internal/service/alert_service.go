@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// ErrInvalidAlertRule wraps validation failures of created or updated rules
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// AlertEvaluator checks the alert rules against freshly ingested data
+type AlertEvaluator interface {
+	Evaluate(ctx context.Context, runID int64) ([]models.AlertEvaluation, error)
+}
+
+// AlertMessenger sends a text message to a chat, e.g. a Telegram bot
+type AlertMessenger interface {
+	SendMessage(ctx context.Context, chatID, text string) error
+}
+
+// alertNotifyTimeout bounds sending one notification
+const alertNotifyTimeout = 30 * time.Second
+
+// AlertService manages threshold alert rules and evaluates them after each
+// ingestion run. A rule notifies its channel at most once per day of data.
+type AlertService struct {
+	repo      repository.AlertRepository
+	publisher WebhookPublisher
+	messenger AlertMessenger
+	clock     clock.Clock
+}
+
+// NewAlertService creates an AlertService. The publisher and messenger may be
+// nil; rules on the webhook or telegram channel then record an error instead of
+// notifying.
+func NewAlertService(repo repository.AlertRepository, publisher WebhookPublisher, messenger AlertMessenger) *AlertService {
+	return &AlertService{repo: repo, publisher: publisher, messenger: messenger, clock: clock.System}
+}
+
+// SetClock sets the clock of the rule and evaluation timestamps
+func (s *AlertService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// ListRules returns every rule, active or not
+func (s *AlertService) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	rules, err := s.repo.ListRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule returns the rule, or nil when it does not exist
+func (s *AlertService) GetRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	rule, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// CreateRule validates and stores a new rule
+func (s *AlertService) CreateRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlertRule, err)
+	}
+	rule.CreatedAt = s.clock.Now().UTC()
+	rule.UpdatedAt = rule.CreatedAt
+	id, err := s.repo.CreateRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	return &rule, nil
+}
+
+// UpdateRule validates and replaces the rule with the given ID. It returns nil
+// when the rule does not exist.
+func (s *AlertService) UpdateRule(ctx context.Context, id int64, rule models.AlertRule) (*models.AlertRule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlertRule, err)
+	}
+	existing, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+	rule.ID = id
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = s.clock.Now().UTC()
+	found, err := s.repo.UpdateRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &rule, nil
+}
+
+// DeleteRule removes the rule and its history. It reports false when the rule
+// does not exist.
+func (s *AlertService) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	return s.repo.DeleteRule(ctx, id)
+}
+
+// ListEvaluations returns the latest evaluations of a rule, or nil when the
+// rule does not exist
+func (s *AlertService) ListEvaluations(ctx context.Context, ruleID int64, limit int) ([]models.AlertEvaluation, error) {
+	rule, err := s.repo.GetRule(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	if rule == nil {
+		return nil, nil
+	}
+	evaluations, err := s.repo.ListEvaluations(ctx, ruleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert evaluations: %w", err)
+	}
+	return evaluations, nil
+}
+
+// Evaluate checks every active rule against the latest day of its scope,
+// notifies triggered rules that have not yet notified for that day and records
+// each evaluation. A rule that fails to evaluate is logged and recorded but does
+// not stop the others.
+func (s *AlertService) Evaluate(ctx context.Context, runID int64) ([]models.AlertEvaluation, error) {
+	rules, err := s.repo.ListActiveRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	evaluations := make([]models.AlertEvaluation, 0, len(rules))
+	for _, rule := range rules {
+		e := s.evaluateRule(ctx, rule, runID)
+		if e.Error != nil {
+			log.Printf("Alert rule %d (%s) evaluation failed: %s", rule.ID, rule.Describe(), *e.Error)
+		}
+		id, err := s.repo.RecordEvaluation(ctx, e)
+		if err != nil {
+			return evaluations, err
+		}
+		e.ID = id
+		evaluations = append(evaluations, e)
+	}
+	return evaluations, nil
+}
+
+func (s *AlertService) evaluateRule(ctx context.Context, rule models.AlertRule, runID int64) models.AlertEvaluation {
+	e := models.AlertEvaluation{RuleID: rule.ID, EvaluatedAt: s.clock.Now().UTC()}
+	if runID != 0 {
+		e.SyncRunID = &runID
+	}
+	fail := func(err error) models.AlertEvaluation {
+		msg := err.Error()
+		e.Error = &msg
+		return e
+	}
+
+	obs, err := s.repo.LatestMetricValue(ctx, rule.Metric, rule.Scope)
+	if err != nil {
+		return fail(err)
+	}
+	if obs == nil {
+		return e
+	}
+	e.Day = &obs.Day
+	e.Value = obs.Value
+	if obs.Value == nil || !rule.Matches(*obs.Value) {
+		return e
+	}
+	e.Triggered = true
+
+	notified, err := s.repo.HasNotified(ctx, rule.ID, obs.Day)
+	if err != nil {
+		return fail(err)
+	}
+	if notified {
+		return e
+	}
+
+	n := models.AlertNotification{
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Condition: rule.Describe(),
+		Day:       obs.Day,
+		Value:     *obs.Value,
+	}
+	if err := s.notify(ctx, rule, n); err != nil {
+		return fail(err)
+	}
+	e.Notified = true
+	return e
+}
+
+func (s *AlertService) notify(ctx context.Context, rule models.AlertRule, n models.AlertNotification) error {
+	switch rule.Channel {
+	case models.AlertChannelLog:
+		log.Print(n.Message())
+		return nil
+	case models.AlertChannelWebhook:
+		if s.publisher == nil {
+			return fmt.Errorf("webhook channel is not configured")
+		}
+		return s.publisher.Publish(ctx, models.WebhookEventAlertTriggered, n)
+	case models.AlertChannelTelegram:
+		if s.messenger == nil {
+			return fmt.Errorf("telegram channel is not configured")
+		}
+		ctx, cancel := context.WithTimeout(ctx, alertNotifyTimeout)
+		defer cancel()
+		return s.messenger.SendMessage(ctx, rule.Target, n.Message())
+	}
+	return fmt.Errorf("unknown channel %q", rule.Channel)
+}
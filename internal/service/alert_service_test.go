@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertRepository) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	args := m.Called()
+	return args.Get(0).([]models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRepository) ListActiveRules(ctx context.Context) ([]models.AlertRule, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRepository) GetRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRepository) CreateRule(ctx context.Context, rule models.AlertRule) (int64, error) {
+	args := m.Called(rule)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAlertRepository) UpdateRule(ctx context.Context, rule models.AlertRule) (bool, error) {
+	args := m.Called(rule)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAlertRepository) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAlertRepository) LatestMetricValue(ctx context.Context, metric, scope string) (*models.MetricObservation, error) {
+	args := m.Called(metric, scope)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MetricObservation), args.Error(1)
+}
+
+func (m *MockAlertRepository) HasNotified(ctx context.Context, ruleID, day int64) (bool, error) {
+	args := m.Called(ruleID, day)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAlertRepository) RecordEvaluation(ctx context.Context, e models.AlertEvaluation) (int64, error) {
+	args := m.Called(e)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAlertRepository) ListEvaluations(ctx context.Context, ruleID int64, limit int) ([]models.AlertEvaluation, error) {
+	args := m.Called(ruleID, limit)
+	return args.Get(0).([]models.AlertEvaluation), args.Error(1)
+}
+
+type recordingMessenger struct {
+	chats    []string
+	messages []string
+	err      error
+}
+
+func (m *recordingMessenger) SendMessage(ctx context.Context, chatID, text string) error {
+	m.chats = append(m.chats, chatID)
+	m.messages = append(m.messages, text)
+	return m.err
+}
+
+var alertTestNow = time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestAlertService(repo *MockAlertRepository, publisher WebhookPublisher, messenger AlertMessenger) *AlertService {
+	s := NewAlertService(repo, publisher, messenger)
+	s.SetClock(clock.NewMock(alertTestNow))
+	return s
+}
+
+func observation(day int64, value float64) *models.MetricObservation {
+	return &models.MetricObservation{Day: day, Value: &value}
+}
+
+func TestAlertService_Evaluate_NotifiesTelegram(t *testing.T) {
+	rule := models.AlertRule{ID: 1, Name: "Sulteng spike", Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100,
+		Channel: models.AlertChannelTelegram, Target: "-100"}
+	repo := new(MockAlertRepository)
+	repo.On("ListActiveRules").Return([]models.AlertRule{rule}, nil)
+	repo.On("LatestMetricValue", "daily_positive", "72").Return(observation(410, 120), nil)
+	repo.On("HasNotified", int64(1), int64(410)).Return(false, nil)
+	repo.On("RecordEvaluation", mock.MatchedBy(func(e models.AlertEvaluation) bool {
+		return e.RuleID == 1 && e.Triggered && e.Notified && *e.SyncRunID == 7 && *e.Day == 410 && e.Error == nil
+	})).Return(int64(11), nil)
+	messenger := new(recordingMessenger)
+
+	evaluations, err := newTestAlertService(repo, nil, messenger).Evaluate(context.Background(), 7)
+
+	assert.NoError(t, err)
+	if assert.Len(t, evaluations, 1) {
+		assert.Equal(t, int64(11), evaluations[0].ID)
+	}
+	assert.Equal(t, []string{"-100"}, messenger.chats)
+	assert.Contains(t, messenger.messages[0], "daily_positive in province 72 > 100")
+	repo.AssertExpectations(t)
+}
+
+func TestAlertService_Evaluate_NotifiesOncePerDay(t *testing.T) {
+	rule := models.AlertRule{ID: 1, Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100, Channel: models.AlertChannelWebhook}
+	repo := new(MockAlertRepository)
+	repo.On("ListActiveRules").Return([]models.AlertRule{rule}, nil)
+	repo.On("LatestMetricValue", "daily_positive", "72").Return(observation(410, 120), nil)
+	repo.On("HasNotified", int64(1), int64(410)).Return(true, nil)
+	repo.On("RecordEvaluation", mock.MatchedBy(func(e models.AlertEvaluation) bool {
+		return e.Triggered && !e.Notified
+	})).Return(int64(12), nil)
+	publisher := new(recordingPublisher)
+
+	_, err := newTestAlertService(repo, publisher, nil).Evaluate(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Empty(t, publisher.events)
+	repo.AssertExpectations(t)
+}
+
+func TestAlertService_Evaluate_NotTriggered(t *testing.T) {
+	rule := models.AlertRule{ID: 2, Metric: "rt", Scope: models.AlertScopeNational, Operator: ">", Threshold: 1, Channel: models.AlertChannelWebhook}
+	repo := new(MockAlertRepository)
+	repo.On("ListActiveRules").Return([]models.AlertRule{rule}, nil)
+	repo.On("LatestMetricValue", "rt", models.AlertScopeNational).Return(observation(410, 0.8), nil)
+	repo.On("RecordEvaluation", mock.MatchedBy(func(e models.AlertEvaluation) bool {
+		return !e.Triggered && *e.Value == 0.8
+	})).Return(int64(13), nil)
+	publisher := new(recordingPublisher)
+
+	_, err := newTestAlertService(repo, publisher, nil).Evaluate(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Empty(t, publisher.events)
+	repo.AssertNotCalled(t, "HasNotified", mock.Anything, mock.Anything)
+}
+
+func TestAlertService_Evaluate_PublishesWebhook(t *testing.T) {
+	rule := models.AlertRule{ID: 3, Name: "National Rt", Metric: "rt", Scope: models.AlertScopeNational, Operator: ">=", Threshold: 1,
+		Channel: models.AlertChannelWebhook}
+	repo := new(MockAlertRepository)
+	repo.On("ListActiveRules").Return([]models.AlertRule{rule}, nil)
+	repo.On("LatestMetricValue", "rt", models.AlertScopeNational).Return(observation(410, 1.2), nil)
+	repo.On("HasNotified", int64(3), int64(410)).Return(false, nil)
+	repo.On("RecordEvaluation", mock.Anything).Return(int64(14), nil)
+	publisher := new(recordingPublisher)
+
+	_, err := newTestAlertService(repo, publisher, nil).Evaluate(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.WebhookEventAlertTriggered}, publisher.events)
+	assert.Equal(t, models.AlertNotification{RuleID: 3, RuleName: "National Rt", Condition: "rt in national >= 1", Day: 410, Value: 1.2},
+		publisher.data[0])
+}
+
+func TestAlertService_Evaluate_RecordsNotifyFailure(t *testing.T) {
+	rules := []models.AlertRule{
+		{ID: 1, Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100, Channel: models.AlertChannelTelegram, Target: "-100"},
+		{ID: 2, Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100, Channel: models.AlertChannelLog},
+	}
+	repo := new(MockAlertRepository)
+	repo.On("ListActiveRules").Return(rules, nil)
+	repo.On("LatestMetricValue", "daily_positive", "72").Return(observation(410, 120), nil)
+	repo.On("HasNotified", mock.Anything, int64(410)).Return(false, nil)
+	repo.On("RecordEvaluation", mock.MatchedBy(func(e models.AlertEvaluation) bool {
+		return e.RuleID == 1 && !e.Notified && *e.Error == "telegram channel is not configured"
+	})).Return(int64(15), nil)
+	repo.On("RecordEvaluation", mock.MatchedBy(func(e models.AlertEvaluation) bool {
+		return e.RuleID == 2 && e.Notified
+	})).Return(int64(16), nil)
+
+	evaluations, err := newTestAlertService(repo, nil, nil).Evaluate(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Len(t, evaluations, 2)
+	repo.AssertExpectations(t)
+}
+
+func TestAlertService_CreateRule(t *testing.T) {
+	rule := models.AlertRule{Name: "Sulteng spike", Metric: "daily_positive", Scope: "72", Operator: ">", Threshold: 100,
+		Channel: models.AlertChannelLog, Active: true}
+	stored := rule
+	stored.CreatedAt, stored.UpdatedAt = alertTestNow, alertTestNow
+	repo := new(MockAlertRepository)
+	repo.On("CreateRule", stored).Return(int64(4), nil)
+
+	created, err := newTestAlertService(repo, nil, nil).CreateRule(context.Background(), rule)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), created.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestAlertService_CreateRule_Invalid(t *testing.T) {
+	repo := new(MockAlertRepository)
+
+	_, err := newTestAlertService(repo, nil, nil).CreateRule(context.Background(), models.AlertRule{Name: "x", Metric: "beds"})
+
+	assert.True(t, errors.Is(err, ErrInvalidAlertRule))
+	repo.AssertNotCalled(t, "CreateRule", mock.Anything)
+}
+
+func TestAlertService_UpdateRule_NotFound(t *testing.T) {
+	repo := new(MockAlertRepository)
+	repo.On("GetRule", int64(9)).Return(nil, nil)
+
+	rule, err := newTestAlertService(repo, nil, nil).UpdateRule(context.Background(), 9, models.AlertRule{Name: "x", Metric: "rt",
+		Scope: models.AlertScopeNational, Operator: ">", Channel: models.AlertChannelLog})
+
+	assert.NoError(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestAlertService_ListEvaluations_UnknownRule(t *testing.T) {
+	repo := new(MockAlertRepository)
+	repo.On("GetRule", int64(9)).Return(nil, nil)
+
+	evaluations, err := newTestAlertService(repo, nil, nil).ListEvaluations(context.Background(), 9, 50)
+
+	assert.NoError(t, err)
+	assert.Nil(t, evaluations)
+	repo.AssertNotCalled(t, "ListEvaluations", mock.Anything, mock.Anything)
+}
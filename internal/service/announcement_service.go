@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// ErrInvalidAnnouncement wraps validation failures of created or updated announcements
+var ErrInvalidAnnouncement = errors.New("invalid announcement")
+
+// AnnouncementService manages announcements and serves the active ones as
+// notices. Notices are added to every response, so they come from an
+// in-memory copy of the announcements that have not ended, reloaded
+// periodically and after every change.
+type AnnouncementService struct {
+	repo  repository.AnnouncementRepository
+	clock clock.Clock
+
+	mu       sync.RWMutex
+	current  []models.Announcement
+	stopChan chan struct{}
+}
+
+// NewAnnouncementService creates an AnnouncementService. Call Refresh or
+// Start to load the current announcements.
+func NewAnnouncementService(repo repository.AnnouncementRepository) *AnnouncementService {
+	return &AnnouncementService{
+		repo:     repo,
+		clock:    clock.System,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock that decides which announcements are active and
+// when they are refreshed
+func (s *AnnouncementService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Refresh reloads the announcements that have not ended. On failure the
+// previously loaded ones are kept.
+func (s *AnnouncementService) Refresh(ctx context.Context) error {
+	current, err := s.repo.ListCurrent(ctx, s.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to load announcements: %w", err)
+	}
+	s.mu.Lock()
+	s.current = current
+	s.mu.Unlock()
+	return nil
+}
+
+// Start refreshes immediately and then at the given interval in a background
+// goroutine, so scheduled announcements created elsewhere show up in time.
+func (s *AnnouncementService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("Announcement refresh failed: %v", err)
+		}
+
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if err := s.Refresh(ctx); err != nil {
+					log.Printf("Announcement refresh failed: %v", err)
+				}
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background refreshes.
+func (s *AnnouncementService) Stop() {
+	close(s.stopChan)
+}
+
+// ActiveNotices returns the announcements shown right now, in the order they
+// started. It never touches the database.
+func (s *AnnouncementService) ActiveNotices() []models.Notice {
+	now := s.clock.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	notices := []models.Notice{}
+	for _, a := range s.current {
+		if a.ActiveAt(now) {
+			notices = append(notices, a.Notice())
+		}
+	}
+	return notices
+}
+
+// ListAnnouncements returns every announcement, newest first
+func (s *AnnouncementService) ListAnnouncements(ctx context.Context) ([]models.Announcement, error) {
+	announcements, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// CreateAnnouncement validates and stores a new announcement. A zero StartsAt
+// shows it right away.
+func (s *AnnouncementService) CreateAnnouncement(ctx context.Context, a models.Announcement) (*models.Announcement, error) {
+	now := s.clock.Now().UTC()
+	if a.StartsAt.IsZero() {
+		a.StartsAt = now
+	}
+	if err := a.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAnnouncement, err)
+	}
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	id, err := s.repo.Create(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	a.ID = id
+	s.refreshAfterChange(ctx)
+	return &a, nil
+}
+
+// UpdateAnnouncement replaces the announcement's severity, message and time
+// window. A zero StartsAt keeps the current one. It returns nil when the
+// announcement does not exist.
+func (s *AnnouncementService) UpdateAnnouncement(ctx context.Context, id int64, a models.Announcement) (*models.Announcement, error) {
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get announcement: %w", err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+	if a.StartsAt.IsZero() {
+		a.StartsAt = existing.StartsAt
+	}
+	if err := a.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAnnouncement, err)
+	}
+
+	a.ID = id
+	a.CreatedAt = existing.CreatedAt
+	a.UpdatedAt = s.clock.Now().UTC()
+	found, err := s.repo.Update(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	s.refreshAfterChange(ctx)
+	return &a, nil
+}
+
+// DeleteAnnouncement removes the announcement, reporting false when it does not exist
+func (s *AnnouncementService) DeleteAnnouncement(ctx context.Context, id int64) (bool, error) {
+	found, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if found {
+		s.refreshAfterChange(ctx)
+	}
+	return found, nil
+}
+
+// refreshAfterChange shows a change right away; if the reload fails the
+// periodic refresh picks it up later
+func (s *AnnouncementService) refreshAfterChange(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Announcement refresh failed: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAnnouncementRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnnouncementRepository) List(ctx context.Context) ([]models.Announcement, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) ListCurrent(ctx context.Context, now time.Time) ([]models.Announcement, error) {
+	args := m.Called(now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Get(ctx context.Context, id int64) (*models.Announcement, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Create(ctx context.Context, announcement models.Announcement) (int64, error) {
+	args := m.Called(announcement)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Update(ctx context.Context, announcement models.Announcement) (bool, error) {
+	args := m.Called(announcement)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+var announcementTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestAnnouncementService(repo *MockAnnouncementRepository) *AnnouncementService {
+	svc := NewAnnouncementService(repo)
+	svc.SetClock(clock.NewMock(announcementTestNow))
+	return svc
+}
+
+func TestAnnouncementService_ActiveNotices(t *testing.T) {
+	repo := new(MockAnnouncementRepository)
+	svc := newTestAnnouncementService(repo)
+	later := announcementTestNow.Add(time.Hour)
+	repo.On("ListCurrent", announcementTestNow).Return([]models.Announcement{
+		{ID: 1, Severity: models.AnnouncementSeverityWarning, Message: "Data delayed today", StartsAt: announcementTestNow.Add(-time.Hour)},
+		{ID: 2, Severity: models.AnnouncementSeverityInfo, Message: "Maintenance tonight", StartsAt: later},
+	}, nil).Once()
+
+	assert.Empty(t, svc.ActiveNotices(), "nothing is shown before the first refresh")
+	require.NoError(t, svc.Refresh(context.Background()))
+
+	notices := svc.ActiveNotices()
+	require.Len(t, notices, 1)
+	assert.Equal(t, "Data delayed today", notices[0].Message)
+
+	svc.SetClock(clock.NewMock(later))
+	assert.Len(t, svc.ActiveNotices(), 2, "scheduled announcements show up without a refresh")
+}
+
+func TestAnnouncementService_Refresh_KeepsPreviousOnError(t *testing.T) {
+	repo := new(MockAnnouncementRepository)
+	svc := newTestAnnouncementService(repo)
+	repo.On("ListCurrent", announcementTestNow).Return([]models.Announcement{
+		{ID: 1, Severity: models.AnnouncementSeverityCritical, Message: "Upstream outage", StartsAt: announcementTestNow},
+	}, nil).Once()
+	repo.On("ListCurrent", announcementTestNow).Return(nil, errors.New("connection refused")).Once()
+
+	require.NoError(t, svc.Refresh(context.Background()))
+	assert.Error(t, svc.Refresh(context.Background()))
+
+	assert.Len(t, svc.ActiveNotices(), 1)
+}
+
+func TestAnnouncementService_CreateAnnouncement(t *testing.T) {
+	repo := new(MockAnnouncementRepository)
+	svc := newTestAnnouncementService(repo)
+	repo.On("Create", mock.MatchedBy(func(a models.Announcement) bool {
+		return a.StartsAt.Equal(announcementTestNow) && a.CreatedAt.Equal(announcementTestNow)
+	})).Return(int64(5), nil)
+	repo.On("ListCurrent", announcementTestNow).Return([]models.Announcement{
+		{ID: 5, Severity: models.AnnouncementSeverityWarning, Message: "Data delayed today", StartsAt: announcementTestNow},
+	}, nil)
+
+	created, err := svc.CreateAnnouncement(context.Background(), models.Announcement{
+		Severity: models.AnnouncementSeverityWarning, Message: "Data delayed today",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), created.ID)
+	assert.Len(t, svc.ActiveNotices(), 1, "a new announcement shows up right away")
+}
+
+func TestAnnouncementService_CreateAnnouncement_Invalid(t *testing.T) {
+	svc := newTestAnnouncementService(new(MockAnnouncementRepository))
+
+	_, err := svc.CreateAnnouncement(context.Background(), models.Announcement{Severity: "urgent", Message: "Outage"})
+
+	assert.ErrorIs(t, err, ErrInvalidAnnouncement)
+}
+
+func TestAnnouncementService_UpdateAnnouncement_KeepsStart(t *testing.T) {
+	repo := new(MockAnnouncementRepository)
+	svc := newTestAnnouncementService(repo)
+	start := announcementTestNow.Add(-2 * time.Hour)
+	end := announcementTestNow.Add(time.Hour)
+	repo.On("Get", int64(3)).Return(&models.Announcement{ID: 3, StartsAt: start, CreatedAt: start}, nil)
+	repo.On("Update", mock.Anything).Return(true, nil)
+	repo.On("ListCurrent", announcementTestNow).Return([]models.Announcement{}, nil)
+
+	updated, err := svc.UpdateAnnouncement(context.Background(), 3, models.Announcement{
+		Severity: models.AnnouncementSeverityInfo, Message: "Data is back", EndsAt: &end,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, start, updated.StartsAt)
+	assert.Equal(t, start, updated.CreatedAt)
+	assert.Equal(t, announcementTestNow, updated.UpdatedAt)
+}
+
+func TestAnnouncementService_UpdateAnnouncement_NotFound(t *testing.T) {
+	repo := new(MockAnnouncementRepository)
+	svc := newTestAnnouncementService(repo)
+	repo.On("Get", int64(9)).Return(nil, nil)
+
+	updated, err := svc.UpdateAnnouncement(context.Background(), 9, models.Announcement{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, updated)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// APIKeyService authenticates X-API-Key headers against the keys configured in
+// the environment and the ones stored in the database. Every request is
+// checked, so the keys are kept in memory and reloaded periodically.
+type APIKeyService struct {
+	repo    repository.APIKeyRepository
+	envKeys []models.APIKey
+
+	mu       sync.RWMutex
+	byHash   map[string]models.APIKey
+	clock    clock.Clock
+	stopChan chan struct{}
+}
+
+// NewAPIKeyService creates an APIKeyService that accepts envKeys right away.
+// repo may be nil to use only envKeys; otherwise call Refresh or Start to load
+// the stored keys.
+func NewAPIKeyService(repo repository.APIKeyRepository, envKeys []models.APIKey) *APIKeyService {
+	s := &APIKeyService{
+		repo:     repo,
+		envKeys:  envKeys,
+		clock:    clock.System,
+		stopChan: make(chan struct{}),
+	}
+	s.byHash = s.index(nil)
+	return s
+}
+
+// SetClock sets the clock of the refresh schedule
+func (s *APIKeyService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Refresh reloads the stored keys that have not been revoked. On failure the
+// previously loaded ones are kept.
+func (s *APIKeyService) Refresh(ctx context.Context) error {
+	if s.repo == nil {
+		return nil
+	}
+	stored, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load API keys: %w", err)
+	}
+	byHash := s.index(stored)
+	s.mu.Lock()
+	s.byHash = byHash
+	s.mu.Unlock()
+	return nil
+}
+
+// index maps key hashes to keys. Keys from the environment win over stored
+// keys with the same hash.
+func (s *APIKeyService) index(stored []models.APIKey) map[string]models.APIKey {
+	byHash := make(map[string]models.APIKey, len(stored)+len(s.envKeys))
+	for _, k := range stored {
+		byHash[k.KeyHash] = k
+	}
+	for _, k := range s.envKeys {
+		byHash[k.KeyHash] = k
+	}
+	return byHash
+}
+
+// Start refreshes immediately and then at the given interval in a background
+// goroutine, so keys created or revoked in the database take effect.
+func (s *APIKeyService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("API key refresh failed: %v", err)
+		}
+
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if err := s.Refresh(ctx); err != nil {
+					log.Printf("API key refresh failed: %v", err)
+				}
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background refreshes.
+func (s *APIKeyService) Stop() {
+	close(s.stopChan)
+}
+
+// Authenticate returns the key matching the X-API-Key header value. It never
+// touches the database.
+func (s *APIKeyService) Authenticate(key string) (*models.APIKey, bool) {
+	if key == "" {
+		return nil, false
+	}
+	s.mu.RLock()
+	k, ok := s.byHash[models.HashAPIKey(key)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return &k, true
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) ListActive(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("ListActive").Return([]models.APIKey{
+		{Name: "stored", KeyHash: models.HashAPIKey("db-key"), Scopes: []string{models.ScopeRead}},
+		{Name: "shadowed", KeyHash: models.HashAPIKey("env-key"), Scopes: []string{models.ScopeRead}},
+	}, nil)
+	s := NewAPIKeyService(repo, []models.APIKey{
+		{Name: "env", KeyHash: models.HashAPIKey("env-key"), Scopes: []string{models.ScopeAdmin}},
+	})
+
+	k, ok := s.Authenticate("env-key")
+	require.True(t, ok, "environment keys work before the first refresh")
+	assert.Equal(t, "env", k.Name)
+	_, ok = s.Authenticate("db-key")
+	assert.False(t, ok)
+
+	require.NoError(t, s.Refresh(context.Background()))
+
+	k, ok = s.Authenticate("db-key")
+	require.True(t, ok)
+	assert.Equal(t, "stored", k.Name)
+	k, ok = s.Authenticate("env-key")
+	require.True(t, ok)
+	assert.Equal(t, "env", k.Name, "environment keys win over stored keys")
+	_, ok = s.Authenticate("")
+	assert.False(t, ok)
+	_, ok = s.Authenticate("unknown")
+	assert.False(t, ok)
+}
+
+func TestAPIKeyService_RefreshKeepsKeysOnError(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("ListActive").Return([]models.APIKey{
+		{Name: "stored", KeyHash: models.HashAPIKey("db-key"), Scopes: []string{models.ScopeRead}},
+	}, nil).Once()
+	repo.On("ListActive").Return(nil, errors.New("connection refused")).Once()
+	s := NewAPIKeyService(repo, nil)
+
+	require.NoError(t, s.Refresh(context.Background()))
+	assert.Error(t, s.Refresh(context.Background()))
+
+	_, ok := s.Authenticate("db-key")
+	assert.True(t, ok)
+}
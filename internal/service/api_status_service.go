@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// ErrInvalidIncident wraps validation failures of created or updated incidents
+var ErrInvalidIncident = errors.New("invalid incident")
+
+// componentCheckTimeout bounds a single component check
+const componentCheckTimeout = 5 * time.Second
+
+// resolvedIncidentWindow is how long a resolved incident stays on the status page
+const resolvedIncidentWindow = 7 * 24 * time.Hour
+
+// maxPendingChecks caps the checks kept in memory while they cannot be
+// stored, e.g. during a database outage; a day of one-minute checks of a few
+// components fits
+const maxPendingChecks = 10000
+
+// ComponentCheck probes one dependency of the API, e.g. the database
+type ComponentCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// APIStatusService builds the public status page from live component checks,
+// incidents managed by admins and the uptime recorded by periodic checks
+type APIStatusService struct {
+	repo      repository.APIStatusRepository
+	checks    []ComponentCheck
+	retention time.Duration
+	clock     clock.Clock
+
+	mu sync.Mutex
+	// pending are recorded checks not stored yet. Failed checks of the
+	// database itself cannot be stored until it is back, so they wait here
+	// instead of being lost and inflating the uptime.
+	pending  []models.StatusCheck
+	stopChan chan struct{}
+}
+
+// NewAPIStatusService creates an APIStatusService. Checks older than
+// retention are deleted as new ones are recorded.
+func NewAPIStatusService(repo repository.APIStatusRepository, checks []ComponentCheck, retention time.Duration) *APIStatusService {
+	return &APIStatusService{
+		repo:      repo,
+		checks:    checks,
+		retention: retention,
+		clock:     clock.System,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock of the checks, the uptime windows and the probe schedule
+func (s *APIStatusService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// runChecks checks every component concurrently
+func (s *APIStatusService) runChecks(ctx context.Context) []models.StatusCheck {
+	results := make([]models.StatusCheck, len(s.checks))
+	var wg sync.WaitGroup
+	for i, c := range s.checks {
+		wg.Add(1)
+		go func(i int, c ComponentCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+			defer cancel()
+			err := c.Check(checkCtx)
+			if err != nil {
+				log.Printf("Status check of %s failed: %v", c.Name, err)
+			}
+			results[i] = models.StatusCheck{Component: c.Name, Healthy: err == nil, CheckedAt: s.clock.Now().UTC()}
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+// Probe checks every component and records the results for the uptime
+// percentages
+func (s *APIStatusService) Probe(ctx context.Context) error {
+	results := s.runChecks(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, results...)
+	if len(s.pending) > maxPendingChecks {
+		s.pending = s.pending[len(s.pending)-maxPendingChecks:]
+	}
+	if err := s.repo.RecordChecks(ctx, s.pending); err != nil {
+		return err
+	}
+	s.pending = nil
+
+	if s.retention > 0 {
+		if _, err := s.repo.DeleteChecksBefore(ctx, s.clock.Now().UTC().Add(-s.retention)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start probes immediately and then at the given interval in a background goroutine.
+func (s *APIStatusService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		if err := s.Probe(ctx); err != nil {
+			log.Printf("Status probe failed: %v", err)
+		}
+
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if err := s.Probe(ctx); err != nil {
+					log.Printf("Status probe failed: %v", err)
+				}
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background probes.
+func (s *APIStatusService) Stop() {
+	close(s.stopChan)
+}
+
+// GetStatus checks the components live and adds the current incidents and
+// uptime. The page must render while the database is down, so failures to
+// load incidents or uptime are logged and leave those parts empty.
+func (s *APIStatusService) GetStatus(ctx context.Context) (*models.APIStatus, error) {
+	now := s.clock.Now().UTC()
+	status := &models.APIStatus{
+		Status:     models.APIStatusOperational,
+		Components: make([]models.ComponentStatus, 0, len(s.checks)),
+		Incidents:  []models.Incident{},
+		Uptime:     make([]models.ComponentUptime, 0, len(s.checks)),
+		CheckedAt:  now,
+	}
+
+	down := 0
+	for _, result := range s.runChecks(ctx) {
+		component := models.ComponentStatus{Name: result.Component, Status: models.APIStatusOperational}
+		if !result.Healthy {
+			component.Status = models.APIStatusDown
+			down++
+		}
+		status.Components = append(status.Components, component)
+	}
+
+	incidents, err := s.repo.ListIncidents(ctx, now.Add(-resolvedIncidentWindow))
+	if err != nil {
+		log.Printf("Failed to load incidents for the status page: %v", err)
+	} else {
+		status.Incidents = incidents
+	}
+
+	switch {
+	case len(s.checks) > 0 && down == len(s.checks):
+		status.Status = models.APIStatusDown
+	case down > 0 || hasOpenIncident(status.Incidents):
+		status.Status = models.APIStatusDegraded
+	}
+
+	periods := []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+	uptimes := make([]map[string]float64, len(periods))
+	for i, period := range periods {
+		if uptimes[i], err = s.repo.Uptime(ctx, now.Add(-period)); err != nil {
+			log.Printf("Failed to load uptime for the status page: %v", err)
+			return status, nil
+		}
+	}
+	for _, c := range s.checks {
+		status.Uptime = append(status.Uptime, models.ComponentUptime{
+			Name:    c.Name,
+			Last24h: uptimePercent(uptimes[0], c.Name),
+			Last7d:  uptimePercent(uptimes[1], c.Name),
+			Last30d: uptimePercent(uptimes[2], c.Name),
+		})
+	}
+	return status, nil
+}
+
+// hasOpenIncident reports whether an unresolved incident other than planned
+// maintenance is listed
+func hasOpenIncident(incidents []models.Incident) bool {
+	for _, incident := range incidents {
+		if incident.ResolvedAt == nil && incident.Severity != models.IncidentSeverityMaintenance {
+			return true
+		}
+	}
+	return false
+}
+
+func uptimePercent(uptime map[string]float64, component string) *float64 {
+	if percent, ok := uptime[component]; ok {
+		return &percent
+	}
+	return nil
+}
+
+// ListIncidents returns every incident, newest first
+func (s *APIStatusService) ListIncidents(ctx context.Context) ([]models.Incident, error) {
+	incidents, err := s.repo.ListIncidents(ctx, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// CreateIncident validates and stores a new incident
+func (s *APIStatusService) CreateIncident(ctx context.Context, incident models.Incident) (*models.Incident, error) {
+	if err := incident.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIncident, err)
+	}
+	incident.CreatedAt = s.clock.Now().UTC()
+	incident.UpdatedAt = incident.CreatedAt
+	id, err := s.repo.CreateIncident(ctx, incident)
+	if err != nil {
+		return nil, err
+	}
+	incident.ID = id
+	return &incident, nil
+}
+
+// UpdateIncident replaces the incident's title, message and severity and
+// resolves or reopens it. It returns nil when the incident does not exist.
+func (s *APIStatusService) UpdateIncident(ctx context.Context, id int64, incident models.Incident, resolved bool) (*models.Incident, error) {
+	if err := incident.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIncident, err)
+	}
+	existing, err := s.repo.GetIncident(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	incident.ID = id
+	incident.CreatedAt = existing.CreatedAt
+	incident.UpdatedAt = s.clock.Now().UTC()
+	switch {
+	case !resolved:
+		incident.ResolvedAt = nil
+	case existing.ResolvedAt != nil:
+		incident.ResolvedAt = existing.ResolvedAt
+	default:
+		incident.ResolvedAt = &incident.UpdatedAt
+	}
+	found, err := s.repo.UpdateIncident(ctx, incident)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &incident, nil
+}
+
+// DeleteIncident removes the incident, reporting false when it does not exist
+func (s *APIStatusService) DeleteIncident(ctx context.Context, id int64) (bool, error) {
+	return s.repo.DeleteIncident(ctx, id)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIStatusRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIStatusRepository) ListIncidents(ctx context.Context, resolvedSince time.Time) ([]models.Incident, error) {
+	args := m.Called(resolvedSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Incident), args.Error(1)
+}
+
+func (m *MockAPIStatusRepository) GetIncident(ctx context.Context, id int64) (*models.Incident, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Incident), args.Error(1)
+}
+
+func (m *MockAPIStatusRepository) CreateIncident(ctx context.Context, incident models.Incident) (int64, error) {
+	args := m.Called(incident)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAPIStatusRepository) UpdateIncident(ctx context.Context, incident models.Incident) (bool, error) {
+	args := m.Called(incident)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIStatusRepository) DeleteIncident(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIStatusRepository) RecordChecks(ctx context.Context, checks []models.StatusCheck) error {
+	args := m.Called(checks)
+	return args.Error(0)
+}
+
+func (m *MockAPIStatusRepository) Uptime(ctx context.Context, since time.Time) (map[string]float64, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]float64), args.Error(1)
+}
+
+func (m *MockAPIStatusRepository) DeleteChecksBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var statusTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestAPIStatusService(repo *MockAPIStatusRepository, dbErr error) *APIStatusService {
+	svc := NewAPIStatusService(repo, []ComponentCheck{
+		{Name: "database", Check: func(context.Context) error { return dbErr }},
+		{Name: "cache", Check: func(context.Context) error { return nil }},
+	}, 30*24*time.Hour)
+	svc.SetClock(clock.NewMock(statusTestNow))
+	return svc
+}
+
+func TestAPIStatusService_GetStatus(t *testing.T) {
+	repo := new(MockAPIStatusRepository)
+	svc := newTestAPIStatusService(repo, nil)
+
+	repo.On("ListIncidents", statusTestNow.Add(-resolvedIncidentWindow)).Return([]models.Incident{
+		{ID: 1, Title: "Planned upgrade", Severity: models.IncidentSeverityMaintenance},
+	}, nil)
+	repo.On("Uptime", statusTestNow.Add(-24*time.Hour)).Return(map[string]float64{"database": 100, "cache": 99}, nil)
+	repo.On("Uptime", statusTestNow.Add(-7*24*time.Hour)).Return(map[string]float64{"database": 99.5}, nil)
+	repo.On("Uptime", statusTestNow.Add(-30*24*time.Hour)).Return(map[string]float64{"database": 98}, nil)
+
+	status, err := svc.GetStatus(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.APIStatusOperational, status.Status, "maintenance does not degrade the status")
+	assert.Equal(t, []models.ComponentStatus{{Name: "database", Status: "operational"}, {Name: "cache", Status: "operational"}}, status.Components)
+	assert.Len(t, status.Incidents, 1)
+	require.Len(t, status.Uptime, 2)
+	assert.Equal(t, 99.5, *status.Uptime[0].Last7d)
+	assert.Equal(t, 99.0, *status.Uptime[1].Last24h)
+	assert.Nil(t, status.Uptime[1].Last7d)
+	repo.AssertExpectations(t)
+}
+
+func TestAPIStatusService_GetStatus_DatabaseDown(t *testing.T) {
+	repo := new(MockAPIStatusRepository)
+	svc := newTestAPIStatusService(repo, errors.New("connection refused"))
+
+	repo.On("ListIncidents", mock.Anything).Return(nil, errors.New("connection refused"))
+	repo.On("Uptime", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	status, err := svc.GetStatus(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.APIStatusDegraded, status.Status)
+	assert.Equal(t, models.APIStatusDown, status.Components[0].Status)
+	assert.Empty(t, status.Incidents)
+	assert.Empty(t, status.Uptime)
+}
+
+func TestAPIStatusService_GetStatus_OpenIncidentDegrades(t *testing.T) {
+	repo := new(MockAPIStatusRepository)
+	svc := newTestAPIStatusService(repo, nil)
+
+	repo.On("ListIncidents", mock.Anything).Return([]models.Incident{{ID: 1, Title: "Stale data", Severity: models.IncidentSeverityMinor}}, nil)
+	repo.On("Uptime", mock.Anything).Return(map[string]float64{}, nil)
+
+	status, err := svc.GetStatus(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.APIStatusDegraded, status.Status)
+}
+
+func TestAPIStatusService_Probe_KeepsChecksUntilStored(t *testing.T) {
+	repo := new(MockAPIStatusRepository)
+	svc := newTestAPIStatusService(repo, errors.New("connection refused"))
+
+	repo.On("RecordChecks", mock.MatchedBy(func(c []models.StatusCheck) bool { return len(c) == 2 })).Return(errors.New("connection refused")).Once()
+	assert.Error(t, svc.Probe(context.Background()))
+
+	repo.On("RecordChecks", mock.MatchedBy(func(c []models.StatusCheck) bool {
+		return len(c) == 4 && c[0].Component == "database" && !c[0].Healthy
+	})).Return(nil).Once()
+	repo.On("DeleteChecksBefore", statusTestNow.Add(-30*24*time.Hour)).Return(int64(0), nil)
+	assert.NoError(t, svc.Probe(context.Background()))
+
+	assert.Empty(t, svc.pending)
+	repo.AssertExpectations(t)
+}
+
+func TestAPIStatusService_UpdateIncident_Resolves(t *testing.T) {
+	repo := new(MockAPIStatusRepository)
+	svc := newTestAPIStatusService(repo, nil)
+	created := statusTestNow.Add(-time.Hour)
+
+	repo.On("GetIncident", int64(3)).Return(&models.Incident{ID: 3, CreatedAt: created}, nil)
+	repo.On("UpdateIncident", mock.Anything).Return(true, nil)
+
+	incident, err := svc.UpdateIncident(context.Background(), 3, models.Incident{Title: "Outage", Severity: models.IncidentSeverityMajor}, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, created, incident.CreatedAt)
+	require.NotNil(t, incident.ResolvedAt)
+	assert.Equal(t, statusTestNow, *incident.ResolvedAt)
+}
+
+func TestAPIStatusService_CreateIncident_Invalid(t *testing.T) {
+	svc := newTestAPIStatusService(new(MockAPIStatusRepository), nil)
+
+	_, err := svc.CreateIncident(context.Background(), models.Incident{Title: "Outage", Severity: "critical"})
+
+	assert.ErrorIs(t, err, ErrInvalidIncident)
+}
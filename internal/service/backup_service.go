@@ -0,0 +1,147 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+)
+
+// Dumper writes a replayable SQL script of the database
+type Dumper interface {
+	Dump(ctx context.Context, w io.Writer) error
+}
+
+// BackupService runs database backups in the background and tracks their progress
+type BackupService struct {
+	dumper Dumper
+	store  storage.BlobStore
+
+	mu   sync.RWMutex
+	jobs map[string]*models.BackupJob
+}
+
+// NewBackupService creates a BackupService that writes gzipped dumps into store.
+func NewBackupService(dumper Dumper, store storage.BlobStore) *BackupService {
+	return &BackupService{
+		dumper: dumper,
+		store:  store,
+		jobs:   make(map[string]*models.BackupJob),
+	}
+}
+
+// StartBackup starts an asynchronous backup and returns a snapshot of the new job.
+func (s *BackupService) StartBackup() models.BackupJob {
+	now := time.Now().UTC()
+	id := now.Format("20060102T150405.000000000")
+	key := "backup-" + now.Format("20060102-150405") + ".sql.gz"
+	job := &models.BackupJob{
+		ID:        id,
+		Status:    models.BackupStatusRunning,
+		File:      s.store.Location(key),
+		StartedAt: now,
+	}
+
+	s.mu.Lock()
+	s.jobs[id] = job
+	snapshot := *job
+	s.mu.Unlock()
+
+	go s.run(job, key)
+
+	return snapshot
+}
+
+func (s *BackupService) run(job *models.BackupJob, key string) {
+	ctx := context.Background()
+	size, err := storage.Write(ctx, s.store, key, func(w io.Writer) error {
+		return WriteBackup(ctx, s.dumper, w)
+	})
+
+	finished := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.FinishedAt = &finished
+	job.Bytes = size
+	if err != nil {
+		job.Status = models.BackupStatusFailed
+		job.Error = err.Error()
+		log.Printf("Backup %s failed: %v", job.ID, err)
+		return
+	}
+	job.Status = models.BackupStatusCompleted
+	log.Printf("Backup %s written to %s (%d bytes)", job.ID, job.File, size)
+}
+
+// GetJob returns a snapshot of the job with the given ID, or nil if unknown.
+func (s *BackupService) GetJob(id string) *models.BackupJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil
+	}
+	snapshot := *job
+	return &snapshot
+}
+
+// ListJobs returns snapshots of all known jobs, newest first.
+func (s *BackupService) ListJobs() []models.BackupJob {
+	s.mu.RLock()
+	jobs := make([]models.BackupJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs
+}
+
+// WriteBackup dumps the database gzip-compressed into w
+func WriteBackup(ctx context.Context, dumper Dumper, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	if err := dumper.Dump(ctx, gz); err != nil {
+		_ = gz.Close()
+		return fmt.Errorf("failed to dump database: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish backup compression: %w", err)
+	}
+	return nil
+}
+
+// WriteBackupFile dumps the database into a gzip-compressed file at path and
+// returns the size of the written file.
+func WriteBackupFile(ctx context.Context, dumper Dumper, path string) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o640)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backup file: %w", err)
+	}
+
+	if err := WriteBackup(ctx, dumper, f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return 0, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("failed to stat backup file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close backup file: %w", err)
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDumper struct {
+	err error
+}
+
+func (d fakeDumper) Dump(ctx context.Context, w io.Writer) error {
+	if d.err != nil {
+		return d.err
+	}
+	_, err := io.WriteString(w, "SET FOREIGN_KEY_CHECKS=0;\n")
+	return err
+}
+
+func waitForBackup(t *testing.T, svc *BackupService, id string) *models.BackupJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job := svc.GetJob(id)
+		require.NotNil(t, job)
+		if job.Status != models.BackupStatusRunning {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("backup %s did not finish", id)
+	return nil
+}
+
+func TestBackupService_StartBackup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	svc := NewBackupService(fakeDumper{}, storage.NewLocal(dir))
+
+	job := svc.StartBackup()
+	assert.Equal(t, models.BackupStatusRunning, job.Status)
+
+	finished := waitForBackup(t, svc, job.ID)
+	assert.Equal(t, models.BackupStatusCompleted, finished.Status)
+	assert.NotNil(t, finished.FinishedAt)
+	assert.Greater(t, finished.Bytes, int64(0))
+
+	_, err := os.Stat(finished.File)
+	assert.NoError(t, err)
+	assert.Len(t, svc.ListJobs(), 1)
+}
+
+func TestBackupService_StartBackup_DumpError(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewBackupService(fakeDumper{err: errors.New("lock wait timeout")}, storage.NewLocal(dir))
+
+	job := svc.StartBackup()
+	finished := waitForBackup(t, svc, job.ID)
+
+	assert.Equal(t, models.BackupStatusFailed, finished.Status)
+	assert.Contains(t, finished.Error, "lock wait timeout")
+	_, err := os.Stat(finished.File)
+	assert.True(t, os.IsNotExist(err))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a failed backup leaves no partial file")
+}
+
+func TestBackupService_GetJob_Unknown(t *testing.T) {
+	svc := NewBackupService(fakeDumper{}, storage.NewLocal(t.TempDir()))
+	assert.Nil(t, svc.GetJob("missing"))
+}
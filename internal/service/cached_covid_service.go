@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/banua-coder/pico-api-go/pkg/cache"
-	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
 )
 
 const (
@@ -15,20 +19,89 @@ const (
 	ttlDefault    = time.Hour
 )
 
+// CacheTTLs sets how long cached results live: Latest for the most recent
+// day of data, Historical for fixed date ranges and Default for everything
+// else. Zero fields use the built-in TTLs.
+type CacheTTLs struct {
+	Latest     time.Duration
+	Historical time.Duration
+	Default    time.Duration
+}
+
+// withDefaults fills zero TTLs with the built-in ones
+func (t CacheTTLs) withDefaults() CacheTTLs {
+	if t.Latest <= 0 {
+		t.Latest = ttlLatest
+	}
+	if t.Historical <= 0 {
+		t.Historical = ttlHistorical
+	}
+	if t.Default <= 0 {
+		t.Default = ttlDefault
+	}
+	return t
+}
+
 // CacheInvalidator is the interface for cache invalidation.
 type CacheInvalidator interface {
 	Clear()
 }
 
+// CacheInvalidators clears every cache it holds, e.g. the query cache and the
+// HTTP response cache, as one CacheInvalidator
+type CacheInvalidators []CacheInvalidator
+
+func (cs CacheInvalidators) Clear() {
+	for _, c := range cs {
+		c.Clear()
+	}
+}
+
+// CacheWarmer is implemented by cached services that can pre-render their
+// busiest responses, so they are served from memory right after the cache
+// is cleared.
+type CacheWarmer interface {
+	Warm(ctx context.Context) error
+}
+
+// WarmCache is a CacheWarmer that reports whether its busiest responses are
+// cached
+type WarmCache interface {
+	CacheWarmer
+	IsWarm() bool
+}
+
+// CacheSizer is implemented by caches that can report how many entries they hold.
+type CacheSizer interface {
+	Len() int
+}
+
 // cachedCovidService wraps a CovidService with in-memory caching.
 type cachedCovidService struct {
 	svc   CovidService
 	cache *cache.Cache
+	ttl   CacheTTLs
+	clock clock.Clock
 }
 
 // NewCachedCovidService returns a CovidService backed by an in-memory cache.
 func NewCachedCovidService(svc CovidService, c *cache.Cache) CovidService {
-	return &cachedCovidService{svc: svc, cache: c}
+	return NewCachedCovidServiceWithTTLs(svc, c, CacheTTLs{})
+}
+
+// NewCachedCovidServiceWithTTLs returns a CovidService backed by an in-memory
+// cache with the given TTLs.
+func NewCachedCovidServiceWithTTLs(svc CovidService, c *cache.Cache, ttls CacheTTLs) CovidService {
+	return &cachedCovidService{svc: svc, cache: c, ttl: ttls.withDefaults(), clock: clock.System}
+}
+
+// SetClock sets the clock of the date range presets, here and in the wrapped
+// service
+func (s *cachedCovidService) SetClock(c clock.Clock) {
+	s.clock = c
+	if setter, ok := s.svc.(ClockSetter); ok {
+		setter.SetClock(c)
+	}
 }
 
 // -- helper ----------------------------------------------------------
@@ -45,54 +118,54 @@ func (s *cachedCovidService) getOrSet(key string, ttl time.Duration, fn func() (
 	return v, nil
 }
 
-// -- national cases --------------------------------------------------
+// provincesOverviewKey caches GetProvincesWithLatestCase, the payload
+// requested most right after the daily update
+const provincesOverviewKey = "province:all:with_latest"
 
-func (s *cachedCovidService) GetNationalCases() ([]models.NationalCase, error) {
-	v, err := s.getOrSet("national:all", ttlDefault, func() (interface{}, error) {
-		return s.svc.GetNationalCases()
-	})
+// Warm loads the provinces overview and pins it in the cache, so it does not
+// expire until the cache is next cleared. Call it after clearing the cache
+// on new data.
+func (s *cachedCovidService) Warm(ctx context.Context) error {
+	provinces, err := s.svc.GetProvincesWithLatestCase(ctx)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to pre-render provinces overview: %w", err)
 	}
-	return v.([]models.NationalCase), nil
+	if failed := models.FailedProvinces(provinces); len(failed) > 0 {
+		return fmt.Errorf("failed to pre-render provinces overview: latest case of provinces %s failed to load", strings.Join(failed, ", "))
+	}
+	s.cache.Pin(provincesOverviewKey, provinces)
+	return nil
 }
 
-func (s *cachedCovidService) GetNationalCasesSorted(sortParams utils.SortParams) ([]models.NationalCase, error) {
-	key := fmt.Sprintf("national:all:sort:%s:%s", sortParams.Field, sortParams.Order)
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		return s.svc.GetNationalCasesSorted(sortParams)
-	})
-	if err != nil {
-		return nil, err
-	}
-	return v.([]models.NationalCase), nil
+// IsWarm reports whether the provinces overview is in the cache
+func (s *cachedCovidService) IsWarm() bool {
+	_, ok := s.cache.Get(provincesOverviewKey)
+	return ok
 }
 
-func (s *cachedCovidService) GetNationalCasesPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	key := fmt.Sprintf("national:all:page:%d:%d", limit, offset)
-	type result struct {
-		cases []models.NationalCase
-		total int
+// caseListKey identifies a case list by every option. Fixed date ranges are
+// historical and cached longer.
+func (s *cachedCovidService) caseListKey(prefix string, opts QueryOptions) (string, time.Duration) {
+	key := fmt.Sprintf("%s:%s:date:%s:rt:%t:page:%d:%d:sort:%s:%s:asof:%d:cols:%s", prefix, opts.ProvinceID,
+		opts.Range, opts.HasRt, opts.Page.Limit, opts.Page.Offset, opts.Sort.Field, opts.Sort.Order, opts.AsOf.Unix(),
+		strings.Join(opts.Columns, ","))
+	if opts.Range.IsSet() {
+		return key, s.ttl.Historical
 	}
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		cases, total, err := s.svc.GetNationalCasesPaginated(limit, offset)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
+	return key, s.ttl.Default
 }
 
-func (s *cachedCovidService) GetNationalCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	key := fmt.Sprintf("national:all:page:%d:%d:sort:%s:%s", limit, offset, sortParams.Field, sortParams.Order)
+// -- national cases --------------------------------------------------
+
+func (s *cachedCovidService) ListNationalCases(ctx context.Context, opts QueryOptions) ([]models.NationalCase, int, error) {
+	opts.ProvinceID, opts.HasRt = "", false
+	key, ttl := s.caseListKey("national:list", opts)
 	type result struct {
 		cases []models.NationalCase
 		total int
 	}
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		cases, total, err := s.svc.GetNationalCasesPaginatedSorted(limit, offset, sortParams)
+	v, err := s.getOrSet(key, ttl, func() (interface{}, error) {
+		cases, total, err := s.svc.ListNationalCases(ctx, opts)
 		return result{cases, total}, err
 	})
 	if err != nil {
@@ -102,65 +175,30 @@ func (s *cachedCovidService) GetNationalCasesPaginatedSorted(limit, offset int,
 	return r.cases, r.total, nil
 }
 
-func (s *cachedCovidService) GetNationalCasesByDateRange(startDate, endDate string) ([]models.NationalCase, error) {
-	key := fmt.Sprintf("national:date:%s:%s", startDate, endDate)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetNationalCasesByDateRange(startDate, endDate)
-	})
-	if err != nil {
-		return nil, err
-	}
-	return v.([]models.NationalCase), nil
+// EachNationalCase is not cached: it streams lists too long to keep
+func (s *cachedCovidService) EachNationalCase(ctx context.Context, opts QueryOptions, fn func([]models.NationalCase) error) error {
+	return s.svc.EachNationalCase(ctx, opts, fn)
 }
 
-func (s *cachedCovidService) GetNationalCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.NationalCase, error) {
-	key := fmt.Sprintf("national:date:%s:%s:sort:%s:%s", startDate, endDate, sortParams.Field, sortParams.Order)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetNationalCasesByDateRangeSorted(startDate, endDate, sortParams)
+func (s *cachedCovidService) GetLatestNationalCase(ctx context.Context) (*models.NationalCase, error) {
+	v, err := s.getOrSet("national:latest", s.ttl.Latest, func() (interface{}, error) {
+		return s.svc.GetLatestNationalCase(ctx)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return v.([]models.NationalCase), nil
-}
-
-func (s *cachedCovidService) GetNationalCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.NationalCase, int, error) {
-	key := fmt.Sprintf("national:date:%s:%s:page:%d:%d", startDate, endDate, limit, offset)
-	type result struct {
-		cases []models.NationalCase
-		total int
-	}
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		cases, total, err := s.svc.GetNationalCasesByDateRangePaginated(startDate, endDate, limit, offset)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
+	return v.(*models.NationalCase), nil
 }
 
-func (s *cachedCovidService) GetNationalCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	key := fmt.Sprintf("national:date:%s:%s:page:%d:%d:sort:%s:%s", startDate, endDate, limit, offset, sortParams.Field, sortParams.Order)
-	type result struct {
-		cases []models.NationalCase
-		total int
-	}
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		cases, total, err := s.svc.GetNationalCasesByDateRangePaginatedSorted(startDate, endDate, limit, offset, sortParams)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
+// ResolveDateRange anchors presets on the cached latest national case
+func (s *cachedCovidService) ResolveDateRange(ctx context.Context, preset string) (string, string, error) {
+	return resolveDateRange(ctx, preset, s.clock, s.GetLatestNationalCase)
 }
 
-func (s *cachedCovidService) GetLatestNationalCase() (*models.NationalCase, error) {
-	v, err := s.getOrSet("national:latest", ttlLatest, func() (interface{}, error) {
-		return s.svc.GetLatestNationalCase()
+func (s *cachedCovidService) GetNationalCaseByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
+	key := fmt.Sprintf("national:day:%d", day)
+	v, err := s.getOrSet(key, s.ttl.Historical, func() (interface{}, error) {
+		return s.svc.GetNationalCaseByDay(ctx, day)
 	})
 	if err != nil {
 		return nil, err
@@ -168,10 +206,10 @@ func (s *cachedCovidService) GetLatestNationalCase() (*models.NationalCase, erro
 	return v.(*models.NationalCase), nil
 }
 
-func (s *cachedCovidService) GetNationalCaseByDay(day int64) (*models.NationalCase, error) {
-	key := fmt.Sprintf("national:day:%d", day)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetNationalCaseByDay(day)
+func (s *cachedCovidService) GetNationalCaseByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	key := fmt.Sprintf("national:date:%s", date.Format("2006-01-02"))
+	v, err := s.getOrSet(key, s.ttl.Historical, func() (interface{}, error) {
+		return s.svc.GetNationalCaseByDate(ctx, date)
 	})
 	if err != nil {
 		return nil, err
@@ -181,9 +219,9 @@ func (s *cachedCovidService) GetNationalCaseByDay(day int64) (*models.NationalCa
 
 // -- provinces -------------------------------------------------------
 
-func (s *cachedCovidService) GetProvinces() ([]models.Province, error) {
-	v, err := s.getOrSet("province:all", ttlDefault, func() (interface{}, error) {
-		return s.svc.GetProvinces()
+func (s *cachedCovidService) GetProvinces(ctx context.Context) ([]models.Province, error) {
+	v, err := s.getOrSet("province:all", s.ttl.Default, func() (interface{}, error) {
+		return s.svc.GetProvinces(ctx)
 	})
 	if err != nil {
 		return nil, err
@@ -191,10 +229,10 @@ func (s *cachedCovidService) GetProvinces() ([]models.Province, error) {
 	return v.([]models.Province), nil
 }
 
-func (s *cachedCovidService) GetProvinceByID(id string) (*models.Province, error) {
+func (s *cachedCovidService) GetProvinceByID(ctx context.Context, id string) (*models.Province, error) {
 	key := fmt.Sprintf("province:%s", id)
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		return s.svc.GetProvinceByID(id)
+	v, err := s.getOrSet(key, s.ttl.Default, func() (interface{}, error) {
+		return s.svc.GetProvinceByID(ctx, id)
 	})
 	if err != nil {
 		return nil, err
@@ -202,63 +240,49 @@ func (s *cachedCovidService) GetProvinceByID(id string) (*models.Province, error
 	return v.(*models.Province), nil
 }
 
-func (s *cachedCovidService) GetProvincesWithLatestCase() ([]models.ProvinceWithLatestCase, error) {
-	v, err := s.getOrSet("province:all:with_latest", ttlLatest, func() (interface{}, error) {
-		return s.svc.GetProvincesWithLatestCase()
+func (s *cachedCovidService) GetProvincesWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
+	return s.getOrSetProvinces(provincesOverviewKey, func() ([]models.ProvinceWithLatestCase, error) {
+		return s.svc.GetProvincesWithLatestCase(ctx)
 	})
-	if err != nil {
-		return nil, err
-	}
-	return v.([]models.ProvinceWithLatestCase), nil
 }
 
-func (s *cachedCovidService) GetProvinceCases(provinceID string) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:%s:cases:all", provinceID)
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		return s.svc.GetProvinceCases(provinceID)
+func (s *cachedCovidService) GetProvincesWithLatestCaseByIDs(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	// The order of the IDs does not change the result
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	key := fmt.Sprintf("province:ids:%s:with_latest", strings.Join(sorted, ","))
+	return s.getOrSetProvinces(key, func() ([]models.ProvinceWithLatestCase, error) {
+		return s.svc.GetProvincesWithLatestCaseByIDs(ctx, ids)
 	})
-	if err != nil {
-		return nil, err
-	}
-	return v.([]models.ProvinceCaseWithDate), nil
 }
 
-func (s *cachedCovidService) GetProvinceCasesSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:%s:cases:all:sort:%s:%s", provinceID, sortParams.Field, sortParams.Order)
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		return s.svc.GetProvinceCasesSorted(provinceID, sortParams)
-	})
+// getOrSetProvinces is getOrSet for provinces with their latest case. Lists
+// where the latest case of a province failed to load are not cached, so the
+// next request retries them.
+func (s *cachedCovidService) getOrSetProvinces(key string, fn func() ([]models.ProvinceWithLatestCase, error)) ([]models.ProvinceWithLatestCase, error) {
+	if v, ok := s.cache.Get(key); ok {
+		return v.([]models.ProvinceWithLatestCase), nil
+	}
+	provinces, err := fn()
 	if err != nil {
 		return nil, err
 	}
-	return v.([]models.ProvinceCaseWithDate), nil
-}
-
-func (s *cachedCovidService) GetProvinceCasesPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:%s:cases:page:%d:%d", provinceID, limit, offset)
-	type result struct {
-		cases []models.ProvinceCaseWithDate
-		total int
-	}
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		cases, total, err := s.svc.GetProvinceCasesPaginated(provinceID, limit, offset)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
+	if len(models.FailedProvinces(provinces)) == 0 {
+		s.cache.Set(key, provinces, s.ttl.Latest)
 	}
-	r := v.(result)
-	return r.cases, r.total, nil
+	return provinces, nil
 }
 
-func (s *cachedCovidService) GetProvinceCasesPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:%s:cases:page:%d:%d:sort:%s:%s", provinceID, limit, offset, sortParams.Field, sortParams.Order)
+// -- province cases --------------------------------------------------
+
+func (s *cachedCovidService) ListProvinceCases(ctx context.Context, opts QueryOptions) ([]models.ProvinceCaseWithDate, int, error) {
+	key, ttl := s.caseListKey("province:cases:list", opts)
 	type result struct {
 		cases []models.ProvinceCaseWithDate
 		total int
 	}
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		cases, total, err := s.svc.GetProvinceCasesPaginatedSorted(provinceID, limit, offset, sortParams)
+	v, err := s.getOrSet(key, ttl, func() (interface{}, error) {
+		cases, total, err := s.svc.ListProvinceCases(ctx, opts)
 		return result{cases, total}, err
 	})
 	if err != nil {
@@ -268,171 +292,87 @@ func (s *cachedCovidService) GetProvinceCasesPaginatedSorted(provinceID string,
 	return r.cases, r.total, nil
 }
 
-func (s *cachedCovidService) GetProvinceCasesByDateRange(provinceID, startDate, endDate string) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:%s:cases:date:%s:%s", provinceID, startDate, endDate)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetProvinceCasesByDateRange(provinceID, startDate, endDate)
-	})
-	if err != nil {
-		return nil, err
-	}
-	return v.([]models.ProvinceCaseWithDate), nil
+// EachProvinceCase is not cached, like EachNationalCase
+func (s *cachedCovidService) EachProvinceCase(ctx context.Context, opts QueryOptions, fn func([]models.ProvinceCaseWithDate) error) error {
+	return s.svc.EachProvinceCase(ctx, opts, fn)
 }
 
-func (s *cachedCovidService) GetProvinceCasesByDateRangeSorted(provinceID, startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:%s:cases:date:%s:%s:sort:%s:%s", provinceID, startDate, endDate, sortParams.Field, sortParams.Order)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetProvinceCasesByDateRangeSorted(provinceID, startDate, endDate, sortParams)
+func (s *cachedCovidService) GetProvinceCaseByDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	key := fmt.Sprintf("province:cases:date:%s:%s", provinceID, date.Format("2006-01-02"))
+	v, err := s.getOrSet(key, s.ttl.Historical, func() (interface{}, error) {
+		return s.svc.GetProvinceCaseByDate(ctx, provinceID, date)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return v.([]models.ProvinceCaseWithDate), nil
+	return v.(*models.ProvinceCaseWithDate), nil
 }
 
-func (s *cachedCovidService) GetProvinceCasesByDateRangePaginated(provinceID, startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:%s:cases:date:%s:%s:page:%d:%d", provinceID, startDate, endDate, limit, offset)
-	type result struct {
-		cases []models.ProvinceCaseWithDate
-		total int
-	}
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		cases, total, err := s.svc.GetProvinceCasesByDateRangePaginated(provinceID, startDate, endDate, limit, offset)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
+func (s *cachedCovidService) GetProvinceCasesAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	after := ""
+	if q.After != nil {
+		after = q.After.Encode()
 	}
-	r := v.(result)
-	return r.cases, r.total, nil
-}
-
-func (s *cachedCovidService) GetProvinceCasesByDateRangePaginatedSorted(provinceID, startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:%s:cases:date:%s:%s:page:%d:%d:sort:%s:%s", provinceID, startDate, endDate, limit, offset, sortParams.Field, sortParams.Order)
+	key := fmt.Sprintf("province:cases:cursor:%s:%s:%s:%s:%t:%d:rt:%t:cols:%s", q.ProvinceID,
+		q.StartDate.Format("2006-01-02"), q.EndDate.Format("2006-01-02"), after, q.Desc, q.Limit, q.HasRt,
+		strings.Join(q.Columns, ","))
 	type result struct {
 		cases []models.ProvinceCaseWithDate
-		total int
+		next  *models.CaseCursor
 	}
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		cases, total, err := s.svc.GetProvinceCasesByDateRangePaginatedSorted(provinceID, startDate, endDate, limit, offset, sortParams)
-		return result{cases, total}, err
+	v, err := s.getOrSet(key, s.ttl.Default, func() (interface{}, error) {
+		cases, next, err := s.svc.GetProvinceCasesAfterCursor(ctx, q)
+		return result{cases, next}, err
 	})
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 	r := v.(result)
-	return r.cases, r.total, nil
+	return r.cases, r.next, nil
 }
 
-// -- all province cases ----------------------------------------------
-
-func (s *cachedCovidService) GetAllProvinceCases() ([]models.ProvinceCaseWithDate, error) {
-	v, err := s.getOrSet("province:cases:all", ttlDefault, func() (interface{}, error) {
-		return s.svc.GetAllProvinceCases()
+func (s *cachedCovidService) GetNationalCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	key := fmt.Sprintf("national:agg:%s:%s:%s:%t", q.Interval,
+		q.StartDate.Format("2006-01-02"), q.EndDate.Format("2006-01-02"), q.Desc)
+	v, err := s.getOrSet(key, s.ttl.Default, func() (interface{}, error) {
+		return s.svc.GetNationalCasesAggregated(ctx, q)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return v.([]models.ProvinceCaseWithDate), nil
+	return v.([]models.AggregatedCase), nil
 }
 
-func (s *cachedCovidService) GetAllProvinceCasesSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:cases:all:sort:%s:%s", sortParams.Field, sortParams.Order)
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		return s.svc.GetAllProvinceCasesSorted(sortParams)
+func (s *cachedCovidService) GetNationalTotals(ctx context.Context, dates DateRange) (models.PeriodTotals, error) {
+	key := fmt.Sprintf("national:totals:%s:%s", dates.Start.Format("2006-01-02"), dates.End.Format("2006-01-02"))
+	v, err := s.getOrSet(key, s.ttl.Default, func() (interface{}, error) {
+		return s.svc.GetNationalTotals(ctx, dates)
 	})
 	if err != nil {
-		return nil, err
+		return models.PeriodTotals{}, err
 	}
-	return v.([]models.ProvinceCaseWithDate), nil
+	return v.(models.PeriodTotals), nil
 }
 
-func (s *cachedCovidService) GetAllProvinceCasesPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:cases:all:page:%d:%d", limit, offset)
-	type result struct {
-		cases []models.ProvinceCaseWithDate
-		total int
-	}
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		cases, total, err := s.svc.GetAllProvinceCasesPaginated(limit, offset)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
-}
-
-func (s *cachedCovidService) GetAllProvinceCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:cases:all:page:%d:%d:sort:%s:%s", limit, offset, sortParams.Field, sortParams.Order)
-	type result struct {
-		cases []models.ProvinceCaseWithDate
-		total int
-	}
-	v, err := s.getOrSet(key, ttlDefault, func() (interface{}, error) {
-		cases, total, err := s.svc.GetAllProvinceCasesPaginatedSorted(limit, offset, sortParams)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
-}
-
-func (s *cachedCovidService) GetAllProvinceCasesByDateRange(startDate, endDate string) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:cases:date:%s:%s", startDate, endDate)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetAllProvinceCasesByDateRange(startDate, endDate)
+func (s *cachedCovidService) GetProvinceCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	key := fmt.Sprintf("province:cases:agg:%s:%s:%s:%s:%t", q.ProvinceID, q.Interval,
+		q.StartDate.Format("2006-01-02"), q.EndDate.Format("2006-01-02"), q.Desc)
+	v, err := s.getOrSet(key, s.ttl.Default, func() (interface{}, error) {
+		return s.svc.GetProvinceCasesAggregated(ctx, q)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return v.([]models.ProvinceCaseWithDate), nil
+	return v.([]models.AggregatedCase), nil
 }
 
-func (s *cachedCovidService) GetAllProvinceCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	key := fmt.Sprintf("province:cases:date:%s:%s:sort:%s:%s", startDate, endDate, sortParams.Field, sortParams.Order)
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		return s.svc.GetAllProvinceCasesByDateRangeSorted(startDate, endDate, sortParams)
-	})
-	if err != nil {
-		return nil, err
-	}
-	return v.([]models.ProvinceCaseWithDate), nil
-}
+// Moving averages are added to cases the caller already holds, so they are
+// computed by the wrapped service rather than cached.
 
-func (s *cachedCovidService) GetAllProvinceCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:cases:date:%s:%s:page:%d:%d", startDate, endDate, limit, offset)
-	type result struct {
-		cases []models.ProvinceCaseWithDate
-		total int
-	}
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		cases, total, err := s.svc.GetAllProvinceCasesByDateRangePaginated(startDate, endDate, limit, offset)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
+func (s *cachedCovidService) AddNationalMovingAverages(ctx context.Context, cases []models.NationalCase, days int) ([]models.NationalCase, error) {
+	return s.svc.AddNationalMovingAverages(ctx, cases, days)
 }
 
-func (s *cachedCovidService) GetAllProvinceCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	key := fmt.Sprintf("province:cases:date:%s:%s:page:%d:%d:sort:%s:%s", startDate, endDate, limit, offset, sortParams.Field, sortParams.Order)
-	type result struct {
-		cases []models.ProvinceCaseWithDate
-		total int
-	}
-	v, err := s.getOrSet(key, ttlHistorical, func() (interface{}, error) {
-		cases, total, err := s.svc.GetAllProvinceCasesByDateRangePaginatedSorted(startDate, endDate, limit, offset, sortParams)
-		return result{cases, total}, err
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	r := v.(result)
-	return r.cases, r.total, nil
+func (s *cachedCovidService) AddProvinceMovingAverages(ctx context.Context, cases []models.ProvinceCaseWithDate, days int) ([]models.ProvinceCaseWithDate, error) {
+	return s.svc.AddProvinceMovingAverages(ctx, cases, days)
 }
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/banua-coder/pico-api-go/pkg/cache"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -17,39 +19,37 @@ type MockCovidService struct {
 	mock.Mock
 }
 
-func (m *MockCovidService) GetNationalCases() ([]models.NationalCase, error) {
-	args := m.Called()
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-func (m *MockCovidService) GetNationalCasesSorted(s utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(s)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-func (m *MockCovidService) GetNationalCasesPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetNationalCasesPaginatedSorted(limit, offset int, s utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset, s)
+func (m *MockCovidService) ListNationalCases(ctx context.Context, opts QueryOptions) ([]models.NationalCase, int, error) {
+	args := m.Called(opts)
 	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
 }
-func (m *MockCovidService) GetNationalCasesByDateRange(start, end string) ([]models.NationalCase, error) {
-	args := m.Called(start, end)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-func (m *MockCovidService) GetNationalCasesByDateRangeSorted(start, end string, s utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(start, end, s)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+
+func (m *MockCovidService) ListProvinceCases(ctx context.Context, opts QueryOptions) ([]models.ProvinceCaseWithDate, int, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
 }
-func (m *MockCovidService) GetNationalCasesByDateRangePaginated(start, end string, limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(start, end, limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+
+func (m *MockCovidService) EachNationalCase(ctx context.Context, opts QueryOptions, fn func([]models.NationalCase) error) error {
+	args := m.Called(opts)
+	for _, chunk := range args.Get(0).([][]models.NationalCase) {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
-func (m *MockCovidService) GetNationalCasesByDateRangePaginatedSorted(start, end string, limit, offset int, s utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(start, end, limit, offset, s)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+
+func (m *MockCovidService) EachProvinceCase(ctx context.Context, opts QueryOptions, fn func([]models.ProvinceCaseWithDate) error) error {
+	args := m.Called(opts)
+	for _, chunk := range args.Get(0).([][]models.ProvinceCaseWithDate) {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
-func (m *MockCovidService) GetLatestNationalCase() (*models.NationalCase, error) {
+
+func (m *MockCovidService) GetLatestNationalCase(ctx context.Context) (*models.NationalCase, error) {
 	args := m.Called()
 	res := args.Get(0)
 	if res == nil {
@@ -57,7 +57,42 @@ func (m *MockCovidService) GetLatestNationalCase() (*models.NationalCase, error)
 	}
 	return res.(*models.NationalCase), args.Error(1)
 }
-func (m *MockCovidService) GetNationalCaseByDay(day int64) (*models.NationalCase, error) {
+func (m *MockCovidService) ResolveDateRange(ctx context.Context, preset string) (string, string, error) {
+	args := m.Called(preset)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockCovidService) GetNationalCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
+}
+
+func (m *MockCovidService) GetNationalTotals(ctx context.Context, dates DateRange) (models.PeriodTotals, error) {
+	args := m.Called(dates)
+	return args.Get(0).(models.PeriodTotals), args.Error(1)
+}
+
+func (m *MockCovidService) GetProvinceCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
+}
+
+func (m *MockCovidService) AddNationalMovingAverages(ctx context.Context, cases []models.NationalCase, days int) ([]models.NationalCase, error) {
+	args := m.Called(cases, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NationalCase), args.Error(1)
+}
+
+func (m *MockCovidService) AddProvinceMovingAverages(ctx context.Context, cases []models.ProvinceCaseWithDate, days int) ([]models.ProvinceCaseWithDate, error) {
+	args := m.Called(cases, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+}
+func (m *MockCovidService) GetNationalCaseByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
 	args := m.Called(day)
 	res := args.Get(0)
 	if res == nil {
@@ -65,11 +100,20 @@ func (m *MockCovidService) GetNationalCaseByDay(day int64) (*models.NationalCase
 	}
 	return res.(*models.NationalCase), args.Error(1)
 }
-func (m *MockCovidService) GetProvinces() ([]models.Province, error) {
+
+func (m *MockCovidService) GetNationalCaseByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	args := m.Called(date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.NationalCase), args.Error(1)
+}
+func (m *MockCovidService) GetProvinces(ctx context.Context) ([]models.Province, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Province), args.Error(1)
 }
-func (m *MockCovidService) GetProvinceByID(id string) (*models.Province, error) {
+func (m *MockCovidService) GetProvinceByID(ctx context.Context, id string) (*models.Province, error) {
 	args := m.Called(id)
 	res := args.Get(0)
 	if res == nil {
@@ -77,317 +121,100 @@ func (m *MockCovidService) GetProvinceByID(id string) (*models.Province, error)
 	}
 	return res.(*models.Province), args.Error(1)
 }
-func (m *MockCovidService) GetProvincesWithLatestCase() ([]models.ProvinceWithLatestCase, error) {
+func (m *MockCovidService) GetProvincesWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
 	args := m.Called()
 	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
 }
-func (m *MockCovidService) GetProvinceCases(pid string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(pid)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetProvinceCasesSorted(pid string, s utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(pid, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetProvinceCasesPaginated(pid string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(pid, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetProvinceCasesPaginatedSorted(pid string, limit, offset int, s utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(pid, limit, offset, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetProvinceCasesByDateRange(pid, start, end string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(pid, start, end)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetProvinceCasesByDateRangeSorted(pid, start, end string, s utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(pid, start, end, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetProvinceCasesByDateRangePaginated(pid, start, end string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(pid, start, end, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetProvinceCasesByDateRangePaginatedSorted(pid, start, end string, limit, offset int, s utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(pid, start, end, limit, offset, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetAllProvinceCases() ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called()
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetAllProvinceCasesSorted(s utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetAllProvinceCasesPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetAllProvinceCasesPaginatedSorted(limit, offset int, s utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-func (m *MockCovidService) GetAllProvinceCasesByDateRange(start, end string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(start, end)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockCovidService) GetProvinceCasesAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	args := m.Called(q)
+	next, _ := args.Get(1).(*models.CaseCursor)
+	return args.Get(0).([]models.ProvinceCaseWithDate), next, args.Error(2)
 }
-func (m *MockCovidService) GetAllProvinceCasesByDateRangeSorted(start, end string, s utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(start, end, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-func (m *MockCovidService) GetAllProvinceCasesByDateRangePaginated(start, end string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(start, end, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+
+func (m *MockCovidService) GetProvinceCaseByDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	args := m.Called(provinceID, date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.ProvinceCaseWithDate), args.Error(1)
 }
-func (m *MockCovidService) GetAllProvinceCasesByDateRangePaginatedSorted(start, end string, limit, offset int, s utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(start, end, limit, offset, s)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+
+func (m *MockCovidService) GetProvincesWithLatestCaseByIDs(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
 }
 
 func newTestCache() *cache.Cache {
 	return cache.New(time.Hour)
 }
 
-func TestCachedCovidService_GetNationalCases(t *testing.T) {
+func TestCachedCovidService_ListNationalCases(t *testing.T) {
 	t.Run("cache miss - calls underlying service", func(t *testing.T) {
 		mockSvc := new(MockCovidService)
-		c := newTestCache()
-		svc := NewCachedCovidService(mockSvc, c)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
 
+		opts := QueryOptions{Sort: utils.SortParams{Field: "date", Order: "asc"}, Page: Page{Limit: 10}}
 		expected := []models.NationalCase{{}}
-		mockSvc.On("GetNationalCases").Return(expected, nil).Once()
+		mockSvc.On("ListNationalCases", opts).Return(expected, 1, nil).Once()
 
-		result, err := svc.GetNationalCases()
+		cases, total, err := svc.ListNationalCases(context.Background(), opts)
 		assert.NoError(t, err)
-		assert.Equal(t, expected, result)
+		assert.Equal(t, expected, cases)
+		assert.Equal(t, 1, total)
 		mockSvc.AssertExpectations(t)
 	})
 
 	t.Run("cache hit - does not call underlying service again", func(t *testing.T) {
 		mockSvc := new(MockCovidService)
-		c := newTestCache()
-		svc := NewCachedCovidService(mockSvc, c)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
 
-		expected := []models.NationalCase{{}}
-		mockSvc.On("GetNationalCases").Return(expected, nil).Once()
+		opts := QueryOptions{Range: testDateRange("2021-01-01", "2021-01-31")}
+		mockSvc.On("ListNationalCases", opts).Return([]models.NationalCase{{}}, 1, nil).Once()
 
-		_, _ = svc.GetNationalCases() // prime cache
-		result, err := svc.GetNationalCases() // should hit cache
+		_, _, _ = svc.ListNationalCases(context.Background(), opts) // prime cache
+		_, total, err := svc.ListNationalCases(context.Background(), opts)
 		assert.NoError(t, err)
-		assert.Equal(t, expected, result)
-		mockSvc.AssertNumberOfCalls(t, "GetNationalCases", 1)
+		assert.Equal(t, 1, total)
+		mockSvc.AssertNumberOfCalls(t, "ListNationalCases", 1)
 	})
 
-	t.Run("error - returns error from underlying service", func(t *testing.T) {
+	t.Run("options are part of the cache key", func(t *testing.T) {
 		mockSvc := new(MockCovidService)
-		c := newTestCache()
-		svc := NewCachedCovidService(mockSvc, c)
-
-		mockSvc.On("GetNationalCases").Return([]models.NationalCase{}, errors.New("db error"))
-
-		_, err := svc.GetNationalCases()
-		assert.Error(t, err)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
+
+		first := QueryOptions{Page: Page{Limit: 10}}
+		second := QueryOptions{Page: Page{Limit: 10, Offset: 10}}
+		sorted := QueryOptions{Sort: utils.SortParams{Field: "positive", Order: "desc"}, Page: Page{Limit: 10}}
+		for _, opts := range []QueryOptions{first, second, sorted} {
+			mockSvc.On("ListNationalCases", opts).Return([]models.NationalCase{}, 30, nil).Once()
+			_, _, _ = svc.ListNationalCases(context.Background(), opts)
+			_, _, _ = svc.ListNationalCases(context.Background(), opts)
+		}
+		mockSvc.AssertNumberOfCalls(t, "ListNationalCases", 3)
 	})
-}
-
-func TestCachedCovidService_GetNationalCasesSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesSorted", sp).Return(expected, nil).Once()
-
-	result, err := svc.GetNationalCasesSorted(sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	// cache hit
-	result2, err := svc.GetNationalCasesSorted(sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result2)
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesSorted", 1)
-}
-
-func TestCachedCovidService_GetNationalCasesSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "desc"}
-	mockSvc.On("GetNationalCasesSorted", sp).Return([]models.NationalCase{}, errors.New("err"))
-	_, err := svc.GetNationalCasesSorted(sp)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetNationalCasesPaginated(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesPaginated", 10, 0).Return(expected, 1, nil).Once()
 
-	cases, total, err := svc.GetNationalCasesPaginated(10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	// cache hit
-	_, _, _ = svc.GetNationalCasesPaginated(10, 0)
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesPaginated", 1)
-}
-
-func TestCachedCovidService_GetNationalCasesPaginated_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetNationalCasesPaginated", 10, 0).Return([]models.NationalCase{}, 0, errors.New("err"))
-	_, _, err := svc.GetNationalCasesPaginated(10, 0)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetNationalCasesPaginatedSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesPaginatedSorted", 10, 0, sp).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetNationalCasesPaginatedSorted(10, 0, sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetNationalCasesPaginatedSorted(10, 0, sp)
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesPaginatedSorted", 1)
-}
-
-func TestCachedCovidService_GetNationalCasesPaginatedSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetNationalCasesPaginatedSorted", 10, 0, sp).Return([]models.NationalCase{}, 0, errors.New("err"))
-	_, _, err := svc.GetNationalCasesPaginatedSorted(10, 0, sp)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRange(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesByDateRange", "2021-01-01", "2021-12-31").Return(expected, nil).Once()
-
-	result, err := svc.GetNationalCasesByDateRange("2021-01-01", "2021-12-31")
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetNationalCasesByDateRange("2021-01-01", "2021-12-31")
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesByDateRange", 1)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRange_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetNationalCasesByDateRange", "2021-01-01", "2021-12-31").Return([]models.NationalCase{}, errors.New("err"))
-	_, err := svc.GetNationalCasesByDateRange("2021-01-01", "2021-12-31")
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRangeSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesByDateRangeSorted", "2021-01-01", "2021-12-31", sp).Return(expected, nil).Once()
-
-	result, err := svc.GetNationalCasesByDateRangeSorted("2021-01-01", "2021-12-31", sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetNationalCasesByDateRangeSorted("2021-01-01", "2021-12-31", sp)
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesByDateRangeSorted", 1)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRangeSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetNationalCasesByDateRangeSorted", "2021-01-01", "2021-12-31", sp).Return([]models.NationalCase{}, errors.New("err"))
-	_, err := svc.GetNationalCasesByDateRangeSorted("2021-01-01", "2021-12-31", sp)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRangePaginated(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesByDateRangePaginated", "2021-01-01", "2021-12-31", 10, 0).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetNationalCasesByDateRangePaginated("2021-01-01", "2021-12-31", 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetNationalCasesByDateRangePaginated("2021-01-01", "2021-12-31", 10, 0)
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesByDateRangePaginated", 1)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRangePaginated_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetNationalCasesByDateRangePaginated", "2021-01-01", "2021-12-31", 10, 0).Return([]models.NationalCase{}, 0, errors.New("err"))
-	_, _, err := svc.GetNationalCasesByDateRangePaginated("2021-01-01", "2021-12-31", 10, 0)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetNationalCasesByDateRangePaginatedSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+	t.Run("province options are ignored", func(t *testing.T) {
+		mockSvc := new(MockCovidService)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
 
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.NationalCase{{}}
-	mockSvc.On("GetNationalCasesByDateRangePaginatedSorted", "2021-01-01", "2021-12-31", 10, 0, sp).Return(expected, 1, nil).Once()
+		mockSvc.On("ListNationalCases", QueryOptions{}).Return([]models.NationalCase{}, 0, nil).Once()
 
-	cases, total, err := svc.GetNationalCasesByDateRangePaginatedSorted("2021-01-01", "2021-12-31", 10, 0, sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
+		_, _, err := svc.ListNationalCases(context.Background(), QueryOptions{ProvinceID: "72", HasRt: true})
+		assert.NoError(t, err)
+		_, _, _ = svc.ListNationalCases(context.Background(), QueryOptions{})
+		mockSvc.AssertExpectations(t)
+	})
 
-	_, _, _ = svc.GetNationalCasesByDateRangePaginatedSorted("2021-01-01", "2021-12-31", 10, 0, sp)
-	mockSvc.AssertNumberOfCalls(t, "GetNationalCasesByDateRangePaginatedSorted", 1)
-}
+	t.Run("error - returns error from underlying service", func(t *testing.T) {
+		mockSvc := new(MockCovidService)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
 
-func TestCachedCovidService_GetNationalCasesByDateRangePaginatedSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+		mockSvc.On("ListNationalCases", QueryOptions{}).Return([]models.NationalCase{}, 0, errors.New("db error"))
 
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetNationalCasesByDateRangePaginatedSorted", "2021-01-01", "2021-12-31", 10, 0, sp).Return([]models.NationalCase{}, 0, errors.New("err"))
-	_, _, err := svc.GetNationalCasesByDateRangePaginatedSorted("2021-01-01", "2021-12-31", 10, 0, sp)
-	assert.Error(t, err)
+		_, _, err := svc.ListNationalCases(context.Background(), QueryOptions{})
+		assert.Error(t, err)
+	})
 }
 
 func TestCachedCovidService_GetLatestNationalCase(t *testing.T) {
@@ -399,11 +226,11 @@ func TestCachedCovidService_GetLatestNationalCase(t *testing.T) {
 		expected := &models.NationalCase{}
 		mockSvc.On("GetLatestNationalCase").Return(expected, nil).Once()
 
-		result, err := svc.GetLatestNationalCase()
+		result, err := svc.GetLatestNationalCase(context.Background())
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 
-		_, _ = svc.GetLatestNationalCase()
+		_, _ = svc.GetLatestNationalCase(context.Background())
 		mockSvc.AssertNumberOfCalls(t, "GetLatestNationalCase", 1)
 	})
 
@@ -413,7 +240,7 @@ func TestCachedCovidService_GetLatestNationalCase(t *testing.T) {
 		svc := NewCachedCovidService(mockSvc, c)
 
 		mockSvc.On("GetLatestNationalCase").Return(nil, errors.New("err"))
-		_, err := svc.GetLatestNationalCase()
+		_, err := svc.GetLatestNationalCase(context.Background())
 		assert.Error(t, err)
 	})
 }
@@ -427,11 +254,11 @@ func TestCachedCovidService_GetNationalCaseByDay(t *testing.T) {
 		expected := &models.NationalCase{}
 		mockSvc.On("GetNationalCaseByDay", int64(1)).Return(expected, nil).Once()
 
-		result, err := svc.GetNationalCaseByDay(1)
+		result, err := svc.GetNationalCaseByDay(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 
-		_, _ = svc.GetNationalCaseByDay(1)
+		_, _ = svc.GetNationalCaseByDay(context.Background(), 1)
 		mockSvc.AssertNumberOfCalls(t, "GetNationalCaseByDay", 1)
 	})
 
@@ -441,11 +268,38 @@ func TestCachedCovidService_GetNationalCaseByDay(t *testing.T) {
 		svc := NewCachedCovidService(mockSvc, c)
 
 		mockSvc.On("GetNationalCaseByDay", int64(1)).Return(nil, errors.New("err"))
-		_, err := svc.GetNationalCaseByDay(1)
+		_, err := svc.GetNationalCaseByDay(context.Background(), 1)
 		assert.Error(t, err)
 	})
 }
 
+func TestCachedCovidService_GetCaseByDate(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	svc := NewCachedCovidService(mockSvc, newTestCache())
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	national := &models.NationalCase{Date: date}
+	province := &models.ProvinceCaseWithDate{Date: date}
+	mockSvc.On("GetNationalCaseByDate", date).Return(national, nil).Once()
+	mockSvc.On("GetProvinceCaseByDate", "72", date).Return(province, nil).Once()
+	mockSvc.On("GetProvinceCaseByDate", "31", date).Return(nil, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		nationalResult, err := svc.GetNationalCaseByDate(context.Background(), date)
+		assert.NoError(t, err)
+		assert.Equal(t, national, nationalResult)
+
+		provinceResult, err := svc.GetProvinceCaseByDate(context.Background(), "72", date)
+		assert.NoError(t, err)
+		assert.Equal(t, province, provinceResult)
+
+		missing, err := svc.GetProvinceCaseByDate(context.Background(), "31", date)
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	}
+	mockSvc.AssertExpectations(t)
+}
+
 func TestCachedCovidService_GetProvinces(t *testing.T) {
 	mockSvc := new(MockCovidService)
 	c := newTestCache()
@@ -454,11 +308,11 @@ func TestCachedCovidService_GetProvinces(t *testing.T) {
 	expected := []models.Province{{}}
 	mockSvc.On("GetProvinces").Return(expected, nil).Once()
 
-	result, err := svc.GetProvinces()
+	result, err := svc.GetProvinces(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 
-	_, _ = svc.GetProvinces()
+	_, _ = svc.GetProvinces(context.Background())
 	mockSvc.AssertNumberOfCalls(t, "GetProvinces", 1)
 }
 
@@ -468,7 +322,7 @@ func TestCachedCovidService_GetProvinces_Error(t *testing.T) {
 	svc := NewCachedCovidService(mockSvc, c)
 
 	mockSvc.On("GetProvinces").Return([]models.Province{}, errors.New("err"))
-	_, err := svc.GetProvinces()
+	_, err := svc.GetProvinces(context.Background())
 	assert.Error(t, err)
 }
 
@@ -481,11 +335,11 @@ func TestCachedCovidService_GetProvinceByID(t *testing.T) {
 		expected := &models.Province{}
 		mockSvc.On("GetProvinceByID", "1").Return(expected, nil).Once()
 
-		result, err := svc.GetProvinceByID("1")
+		result, err := svc.GetProvinceByID(context.Background(), "1")
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 
-		_, _ = svc.GetProvinceByID("1")
+		_, _ = svc.GetProvinceByID(context.Background(), "1")
 		mockSvc.AssertNumberOfCalls(t, "GetProvinceByID", 1)
 	})
 
@@ -495,7 +349,7 @@ func TestCachedCovidService_GetProvinceByID(t *testing.T) {
 		svc := NewCachedCovidService(mockSvc, c)
 
 		mockSvc.On("GetProvinceByID", "1").Return(nil, errors.New("err"))
-		_, err := svc.GetProvinceByID("1")
+		_, err := svc.GetProvinceByID(context.Background(), "1")
 		assert.Error(t, err)
 	})
 }
@@ -508,460 +362,187 @@ func TestCachedCovidService_GetProvincesWithLatestCase(t *testing.T) {
 	expected := []models.ProvinceWithLatestCase{{}}
 	mockSvc.On("GetProvincesWithLatestCase").Return(expected, nil).Once()
 
-	result, err := svc.GetProvincesWithLatestCase()
+	result, err := svc.GetProvincesWithLatestCase(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 
-	_, _ = svc.GetProvincesWithLatestCase()
+	_, _ = svc.GetProvincesWithLatestCase(context.Background())
 	mockSvc.AssertNumberOfCalls(t, "GetProvincesWithLatestCase", 1)
 }
 
-func TestCachedCovidService_GetProvincesWithLatestCase_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase{}, errors.New("err"))
-	_, err := svc.GetProvincesWithLatestCase()
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCases(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCases", "p1").Return(expected, nil).Once()
-
-	result, err := svc.GetProvinceCases("p1")
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetProvinceCases("p1")
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCases", 1)
-}
-
-func TestCachedCovidService_GetProvinceCases_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetProvinceCases", "p1").Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetProvinceCases("p1")
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCasesSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesSorted", "p1", sp).Return(expected, nil).Once()
-
-	result, err := svc.GetProvinceCasesSorted("p1", sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetProvinceCasesSorted("p1", sp)
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesSorted", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetProvinceCasesSorted", "p1", sp).Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetProvinceCasesSorted("p1", sp)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCasesPaginated(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesPaginated", "p1", 10, 0).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetProvinceCasesPaginated("p1", 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetProvinceCasesPaginated("p1", 10, 0)
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesPaginated", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesPaginated_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetProvinceCasesPaginated", "p1", 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetProvinceCasesPaginated("p1", 10, 0)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCasesPaginatedSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesPaginatedSorted", "p1", 10, 0, sp).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetProvinceCasesPaginatedSorted("p1", 10, 0, sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetProvinceCasesPaginatedSorted("p1", 10, 0, sp)
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesPaginatedSorted", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesPaginatedSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetProvinceCasesPaginatedSorted", "p1", 10, 0, sp).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetProvinceCasesPaginatedSorted("p1", 10, 0, sp)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRange(t *testing.T) {
+func TestCachedCovidService_GetProvincesWithLatestCase_PartialFailure(t *testing.T) {
 	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+	svc := NewCachedCovidService(mockSvc, newTestCache())
 
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesByDateRange", "p1", "2021-01-01", "2021-12-31").Return(expected, nil).Once()
+	partial := []models.ProvinceWithLatestCase{{Province: models.Province{ID: "72"}, DataUnavailable: true, Error: "latest case could not be loaded"}}
+	mockSvc.On("GetProvincesWithLatestCase").Return(partial, nil)
 
-	result, err := svc.GetProvinceCasesByDateRange("p1", "2021-01-01", "2021-12-31")
+	result, err := svc.GetProvincesWithLatestCase(context.Background())
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetProvinceCasesByDateRange("p1", "2021-01-01", "2021-12-31")
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesByDateRange", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRange_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+	assert.Equal(t, partial, result)
 
-	mockSvc.On("GetProvinceCasesByDateRange", "p1", "2021-01-01", "2021-12-31").Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetProvinceCasesByDateRange("p1", "2021-01-01", "2021-12-31")
-	assert.Error(t, err)
+	// Not cached, so the next request retries the failed province
+	_, _ = svc.GetProvincesWithLatestCase(context.Background())
+	mockSvc.AssertNumberOfCalls(t, "GetProvincesWithLatestCase", 2)
+	assert.ErrorContains(t, svc.(CacheWarmer).Warm(context.Background()), "latest case of provinces 72 failed to load")
 }
 
-func TestCachedCovidService_GetProvinceCasesByDateRangeSorted(t *testing.T) {
+func TestCachedCovidService_Warm(t *testing.T) {
 	mockSvc := new(MockCovidService)
-	c := newTestCache()
+	c := cache.New(time.Millisecond)
 	svc := NewCachedCovidService(mockSvc, c)
 
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesByDateRangeSorted", "p1", "2021-01-01", "2021-12-31", sp).Return(expected, nil).Once()
+	expected := []models.ProvinceWithLatestCase{{Province: models.Province{ID: "72"}}}
+	mockSvc.On("GetProvincesWithLatestCase").Return(expected, nil).Once()
 
-	result, err := svc.GetProvinceCasesByDateRangeSorted("p1", "2021-01-01", "2021-12-31", sp)
+	assert.NoError(t, svc.(CacheWarmer).Warm(context.Background()))
+	time.Sleep(5 * time.Millisecond) // past the TTL, the pinned overview stays
+	result, err := svc.GetProvincesWithLatestCase(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetProvinceCasesByDateRangeSorted("p1", "2021-01-01", "2021-12-31", sp)
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesByDateRangeSorted", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRangeSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetProvinceCasesByDateRangeSorted", "p1", "2021-01-01", "2021-12-31", sp).Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetProvinceCasesByDateRangeSorted("p1", "2021-01-01", "2021-12-31", sp)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRangePaginated(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesByDateRangePaginated", "p1", "2021-01-01", "2021-12-31", 10, 0).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetProvinceCasesByDateRangePaginated("p1", "2021-01-01", "2021-12-31", 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetProvinceCasesByDateRangePaginated("p1", "2021-01-01", "2021-12-31", 10, 0)
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesByDateRangePaginated", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRangePaginated_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetProvinceCasesByDateRangePaginated", "p1", "2021-01-01", "2021-12-31", 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetProvinceCasesByDateRangePaginated("p1", "2021-01-01", "2021-12-31", 10, 0)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRangePaginatedSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetProvinceCasesByDateRangePaginatedSorted", "p1", "2021-01-01", "2021-12-31", 10, 0, sp).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetProvinceCasesByDateRangePaginatedSorted("p1", "2021-01-01", "2021-12-31", 10, 0, sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetProvinceCasesByDateRangePaginatedSorted("p1", "2021-01-01", "2021-12-31", 10, 0, sp)
-	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesByDateRangePaginatedSorted", 1)
-}
-
-func TestCachedCovidService_GetProvinceCasesByDateRangePaginatedSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetProvinceCasesByDateRangePaginatedSorted", "p1", "2021-01-01", "2021-12-31", 10, 0, sp).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetProvinceCasesByDateRangePaginatedSorted("p1", "2021-01-01", "2021-12-31", 10, 0, sp)
-	assert.Error(t, err)
+	mockSvc.AssertNumberOfCalls(t, "GetProvincesWithLatestCase", 1)
 }
 
-func TestCachedCovidService_GetAllProvinceCases(t *testing.T) {
+func TestCachedCovidService_Warm_Error(t *testing.T) {
 	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCases").Return(expected, nil).Once()
-
-	result, err := svc.GetAllProvinceCases()
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
+	svc := NewCachedCovidService(mockSvc, newTestCache())
 
-	_, _ = svc.GetAllProvinceCases()
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCases", 1)
-}
+	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase{}, errors.New("db error"))
 
-func TestCachedCovidService_GetAllProvinceCases_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetAllProvinceCases").Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetAllProvinceCases()
-	assert.Error(t, err)
+	err := svc.(CacheWarmer).Warm(context.Background())
+	assert.ErrorContains(t, err, "failed to pre-render provinces overview")
 }
 
-func TestCachedCovidService_GetAllProvinceCasesSorted(t *testing.T) {
+func TestCachedCovidService_GetProvincesWithLatestCaseByIDs(t *testing.T) {
 	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+	svc := NewCachedCovidService(mockSvc, newTestCache())
 
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesSorted", sp).Return(expected, nil).Once()
+	expected := []models.ProvinceWithLatestCase{{Province: models.Province{ID: "31"}}, {Province: models.Province{ID: "72"}}}
+	mockSvc.On("GetProvincesWithLatestCaseByIDs", []string{"72", "31"}).Return(expected, nil).Once()
 
-	result, err := svc.GetAllProvinceCasesSorted(sp)
+	result, err := svc.GetProvincesWithLatestCaseByIDs(context.Background(), []string{"72", "31"})
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 
-	_, _ = svc.GetAllProvinceCasesSorted(sp)
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesSorted", 1)
-}
-
-func TestCachedCovidService_GetAllProvinceCasesSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetAllProvinceCasesSorted", sp).Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetAllProvinceCasesSorted(sp)
-	assert.Error(t, err)
+	// The same IDs in another order are served from the cache
+	_, _ = svc.GetProvincesWithLatestCaseByIDs(context.Background(), []string{"31", "72"})
+	mockSvc.AssertNumberOfCalls(t, "GetProvincesWithLatestCaseByIDs", 1)
 }
 
-func TestCachedCovidService_GetAllProvinceCasesPaginated(t *testing.T) {
+func TestCachedCovidService_GetProvinceCasesAfterCursor(t *testing.T) {
 	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+	svc := NewCachedCovidService(mockSvc, newTestCache())
 
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesPaginated", 10, 0).Return(expected, 1, nil).Once()
+	first := repository.ProvinceCaseCursorQuery{ProvinceID: "72", Limit: 2}
+	next := &models.CaseCursor{Date: time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC), ID: 7}
+	second := repository.ProvinceCaseCursorQuery{ProvinceID: "72", After: next, Limit: 2}
+	mockSvc.On("GetProvinceCasesAfterCursor", first).Return([]models.ProvinceCaseWithDate{{}}, next, nil).Once()
+	mockSvc.On("GetProvinceCasesAfterCursor", second).Return([]models.ProvinceCaseWithDate{}, nil, nil).Once()
 
-	cases, total, err := svc.GetAllProvinceCasesPaginated(10, 0)
+	_, cursor, err := svc.GetProvinceCasesAfterCursor(context.Background(), first)
 	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetAllProvinceCasesPaginated(10, 0)
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesPaginated", 1)
-}
-
-func TestCachedCovidService_GetAllProvinceCasesPaginated_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	mockSvc.On("GetAllProvinceCasesPaginated", 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetAllProvinceCasesPaginated(10, 0)
-	assert.Error(t, err)
-}
-
-func TestCachedCovidService_GetAllProvinceCasesPaginatedSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesPaginatedSorted", 10, 0, sp).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetAllProvinceCasesPaginatedSorted(10, 0, sp)
+	assert.Equal(t, next, cursor)
+	_, _, _ = svc.GetProvinceCasesAfterCursor(context.Background(), first)
+	_, cursor, err = svc.GetProvinceCasesAfterCursor(context.Background(), second)
 	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
-
-	_, _, _ = svc.GetAllProvinceCasesPaginatedSorted(10, 0, sp)
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesPaginatedSorted", 1)
-}
+	assert.Nil(t, cursor)
 
-func TestCachedCovidService_GetAllProvinceCasesPaginatedSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetAllProvinceCasesPaginatedSorted", 10, 0, sp).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetAllProvinceCasesPaginatedSorted(10, 0, sp)
-	assert.Error(t, err)
+	// Pages after different cursors are cached separately
+	mockSvc.AssertNumberOfCalls(t, "GetProvinceCasesAfterCursor", 2)
 }
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRange(t *testing.T) {
+func TestCachedCovidService_CustomTTLs(t *testing.T) {
 	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+	svc := NewCachedCovidServiceWithTTLs(mockSvc, newTestCache(), CacheTTLs{Latest: 20 * time.Millisecond})
 
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesByDateRange", "2021-01-01", "2021-12-31").Return(expected, nil).Once()
+	expected := []models.ProvinceWithLatestCase{{}}
+	mockSvc.On("GetProvincesWithLatestCase").Return(expected, nil)
+	mockSvc.On("GetProvinces").Return([]models.Province{}, nil)
 
-	result, err := svc.GetAllProvinceCasesByDateRange("2021-01-01", "2021-12-31")
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
+	_, _ = svc.GetProvincesWithLatestCase(context.Background())
+	_, _ = svc.GetProvinces(context.Background())
+	_, _ = svc.GetProvincesWithLatestCase(context.Background())
+	mockSvc.AssertNumberOfCalls(t, "GetProvincesWithLatestCase", 1)
 
-	_, _ = svc.GetAllProvinceCasesByDateRange("2021-01-01", "2021-12-31")
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesByDateRange", 1)
+	// The latest TTL expires while Default keeps its built-in hour
+	time.Sleep(30 * time.Millisecond)
+	_, _ = svc.GetProvincesWithLatestCase(context.Background())
+	_, _ = svc.GetProvinces(context.Background())
+	mockSvc.AssertNumberOfCalls(t, "GetProvincesWithLatestCase", 2)
+	mockSvc.AssertNumberOfCalls(t, "GetProvinces", 1)
 }
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRange_Error(t *testing.T) {
+func TestCachedCovidService_GetProvincesWithLatestCase_Error(t *testing.T) {
 	mockSvc := new(MockCovidService)
 	c := newTestCache()
 	svc := NewCachedCovidService(mockSvc, c)
 
-	mockSvc.On("GetAllProvinceCasesByDateRange", "2021-01-01", "2021-12-31").Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetAllProvinceCasesByDateRange("2021-01-01", "2021-12-31")
+	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase{}, errors.New("err"))
+	_, err := svc.GetProvincesWithLatestCase(context.Background())
 	assert.Error(t, err)
 }
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRangeSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesByDateRangeSorted", "2021-01-01", "2021-12-31", sp).Return(expected, nil).Once()
-
-	result, err := svc.GetAllProvinceCasesByDateRangeSorted("2021-01-01", "2021-12-31", sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-
-	_, _ = svc.GetAllProvinceCasesByDateRangeSorted("2021-01-01", "2021-12-31", sp)
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesByDateRangeSorted", 1)
-}
-
-func TestCachedCovidService_GetAllProvinceCasesByDateRangeSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetAllProvinceCasesByDateRangeSorted", "2021-01-01", "2021-12-31", sp).Return([]models.ProvinceCaseWithDate{}, errors.New("err"))
-	_, err := svc.GetAllProvinceCasesByDateRangeSorted("2021-01-01", "2021-12-31", sp)
-	assert.Error(t, err)
-}
+func TestCachedCovidService_ListProvinceCases(t *testing.T) {
+	t.Run("cache hit - does not call underlying service again", func(t *testing.T) {
+		mockSvc := new(MockCovidService)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRangePaginated(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+		opts := QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "desc"}, Page: Page{Limit: 50}}
+		expected := []models.ProvinceCaseWithDate{{}}
+		mockSvc.On("ListProvinceCases", opts).Return(expected, 120, nil).Once()
 
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesByDateRangePaginated", "2021-01-01", "2021-12-31", 10, 0).Return(expected, 1, nil).Once()
+		cases, total, err := svc.ListProvinceCases(context.Background(), opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cases)
+		assert.Equal(t, 120, total)
+		_, _, _ = svc.ListProvinceCases(context.Background(), opts)
+		mockSvc.AssertNumberOfCalls(t, "ListProvinceCases", 1)
+	})
 
-	cases, total, err := svc.GetAllProvinceCasesByDateRangePaginated("2021-01-01", "2021-12-31", 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
+	t.Run("province and Rt filter are part of the cache key", func(t *testing.T) {
+		mockSvc := new(MockCovidService)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
+
+		withRt := QueryOptions{ProvinceID: "72", HasRt: true, Page: Page{Limit: 50}}
+		unfiltered := QueryOptions{ProvinceID: "72", Page: Page{Limit: 50}}
+		otherProvince := QueryOptions{ProvinceID: "73", Page: Page{Limit: 50}}
+		allProvinces := QueryOptions{Page: Page{Limit: 50}}
+		mockSvc.On("ListProvinceCases", withRt).Return([]models.ProvinceCaseWithDate{{}}, 1, nil).Once()
+		mockSvc.On("ListProvinceCases", unfiltered).Return([]models.ProvinceCaseWithDate{{}, {}}, 2, nil).Once()
+		mockSvc.On("ListProvinceCases", otherProvince).Return([]models.ProvinceCaseWithDate{}, 0, nil).Once()
+		mockSvc.On("ListProvinceCases", allProvinces).Return([]models.ProvinceCaseWithDate{}, 0, nil).Once()
+
+		_, total, err := svc.ListProvinceCases(context.Background(), withRt)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		_, _, _ = svc.ListProvinceCases(context.Background(), withRt)
+		_, total, err = svc.ListProvinceCases(context.Background(), unfiltered)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		_, _, _ = svc.ListProvinceCases(context.Background(), otherProvince)
+		_, _, _ = svc.ListProvinceCases(context.Background(), allProvinces)
+		mockSvc.AssertNumberOfCalls(t, "ListProvinceCases", 4)
+	})
 
-	_, _, _ = svc.GetAllProvinceCasesByDateRangePaginated("2021-01-01", "2021-12-31", 10, 0)
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesByDateRangePaginated", 1)
-}
+	t.Run("error - returns error from underlying service", func(t *testing.T) {
+		mockSvc := new(MockCovidService)
+		svc := NewCachedCovidService(mockSvc, newTestCache())
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRangePaginated_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
+		opts := QueryOptions{ProvinceID: "72", Range: testDateRange("2021-01-01", "2021-01-31")}
+		mockSvc.On("ListProvinceCases", opts).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
 
-	mockSvc.On("GetAllProvinceCasesByDateRangePaginated", "2021-01-01", "2021-12-31", 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetAllProvinceCasesByDateRangePaginated("2021-01-01", "2021-12-31", 10, 0)
-	assert.Error(t, err)
+		_, _, err := svc.ListProvinceCases(context.Background(), opts)
+		assert.Error(t, err)
+	})
 }
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRangePaginatedSorted(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{}}
-	mockSvc.On("GetAllProvinceCasesByDateRangePaginatedSorted", "2021-01-01", "2021-12-31", 10, 0, sp).Return(expected, 1, nil).Once()
-
-	cases, total, err := svc.GetAllProvinceCasesByDateRangePaginatedSorted("2021-01-01", "2021-12-31", 10, 0, sp)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, cases)
-	assert.Equal(t, 1, total)
+func TestCacheInvalidators_ClearsEveryCache(t *testing.T) {
+	first, second := cache.New(time.Minute), cache.New(time.Minute)
+	first.Set("a", 1)
+	second.Set("b", 2)
 
-	_, _, _ = svc.GetAllProvinceCasesByDateRangePaginatedSorted("2021-01-01", "2021-12-31", 10, 0, sp)
-	mockSvc.AssertNumberOfCalls(t, "GetAllProvinceCasesByDateRangePaginatedSorted", 1)
-}
+	CacheInvalidators{first, second}.Clear()
 
-func TestCachedCovidService_GetAllProvinceCasesByDateRangePaginatedSorted_Error(t *testing.T) {
-	mockSvc := new(MockCovidService)
-	c := newTestCache()
-	svc := NewCachedCovidService(mockSvc, c)
-
-	sp := utils.SortParams{Field: "date", Order: "asc"}
-	mockSvc.On("GetAllProvinceCasesByDateRangePaginatedSorted", "2021-01-01", "2021-12-31", 10, 0, sp).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("err"))
-	_, _, err := svc.GetAllProvinceCasesByDateRangePaginatedSorted("2021-01-01", "2021-12-31", 10, 0, sp)
-	assert.Error(t, err)
+	assert.Equal(t, 0, first.Len())
+	assert.Equal(t, 0, second.Len())
 }
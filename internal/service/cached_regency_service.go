@@ -1,38 +1,48 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/pkg/cache"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 )
 
 // cachedRegencyService wraps a RegencyServiceInterface with in-memory caching.
+// Lists scoped to the tenant's province are keyed by that province.
 type cachedRegencyService struct {
 	svc   RegencyServiceInterface
 	cache *cache.Cache
+	ttl   CacheTTLs
 }
 
 // NewCachedRegencyService returns a RegencyServiceInterface backed by an in-memory cache.
 func NewCachedRegencyService(svc RegencyServiceInterface, c *cache.Cache) RegencyServiceInterface {
-	return &cachedRegencyService{svc: svc, cache: c}
+	return NewCachedRegencyServiceWithTTLs(svc, c, CacheTTLs{})
 }
 
-func (s *cachedRegencyService) GetRegencies() ([]models.Regency, error) {
-	const key = "regency:all"
+// NewCachedRegencyServiceWithTTLs returns a RegencyServiceInterface backed by
+// an in-memory cache with the given TTLs.
+func NewCachedRegencyServiceWithTTLs(svc RegencyServiceInterface, c *cache.Cache, ttls CacheTTLs) RegencyServiceInterface {
+	return &cachedRegencyService{svc: svc, cache: c, ttl: ttls.withDefaults()}
+}
+
+func (s *cachedRegencyService) GetRegencies(ctx context.Context) ([]models.Regency, error) {
+	key := "regency:all:" + tenant.FromContext(ctx).ProvinceID
 	if v, ok := s.cache.Get(key); ok {
 		return v.([]models.Regency), nil
 	}
-	result, err := s.svc.GetRegencies()
+	result, err := s.svc.GetRegencies(ctx)
 	if err != nil {
 		return nil, err
 	}
-	s.cache.Set(key, result, ttlDefault)
+	s.cache.Set(key, result, s.ttl.Default)
 	return result, nil
 }
 
-func (s *cachedRegencyService) GetRegenciesPaginated(limit, offset int) ([]models.Regency, int, error) {
-	key := fmt.Sprintf("regency:all:page:%d:%d", limit, offset)
+func (s *cachedRegencyService) GetRegenciesPaginated(ctx context.Context, limit, offset int) ([]models.Regency, int, error) {
+	key := fmt.Sprintf("regency:all:%s:page:%d:%d", tenant.FromContext(ctx).ProvinceID, limit, offset)
 	type res struct {
 		items []models.Regency
 		total int
@@ -41,49 +51,62 @@ func (s *cachedRegencyService) GetRegenciesPaginated(limit, offset int) ([]model
 		r := v.(res)
 		return r.items, r.total, nil
 	}
-	items, total, err := s.svc.GetRegenciesPaginated(limit, offset)
+	items, total, err := s.svc.GetRegenciesPaginated(ctx, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
-	s.cache.Set(key, res{items, total}, ttlDefault)
+	s.cache.Set(key, res{items, total}, s.ttl.Default)
 	return items, total, nil
 }
 
-func (s *cachedRegencyService) GetRegencyByID(id int) (*models.Regency, error) {
+func (s *cachedRegencyService) GetRegenciesByProvince(ctx context.Context, provinceID int) ([]models.Regency, error) {
+	key := fmt.Sprintf("regency:province:%d", provinceID)
+	if v, ok := s.cache.Get(key); ok {
+		return v.([]models.Regency), nil
+	}
+	result, err := s.svc.GetRegenciesByProvince(ctx, provinceID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, result, s.ttl.Default)
+	return result, nil
+}
+
+func (s *cachedRegencyService) GetRegencyByID(ctx context.Context, id int) (*models.Regency, error) {
 	key := fmt.Sprintf("regency:%d", id)
 	if v, ok := s.cache.Get(key); ok {
 		return v.(*models.Regency), nil
 	}
-	result, err := s.svc.GetRegencyByID(id)
+	result, err := s.svc.GetRegencyByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	s.cache.Set(key, result, ttlDefault)
+	s.cache.Set(key, result, s.ttl.Default)
 	return result, nil
 }
 
-func (s *cachedRegencyService) GetRegencyCases(regencyID int) ([]models.RegencyCase, error) {
+func (s *cachedRegencyService) GetRegencyCases(ctx context.Context, regencyID int) ([]models.RegencyCase, error) {
 	key := fmt.Sprintf("regency:%d:cases:all", regencyID)
 	if v, ok := s.cache.Get(key); ok {
 		return v.([]models.RegencyCase), nil
 	}
-	result, err := s.svc.GetRegencyCases(regencyID)
+	result, err := s.svc.GetRegencyCases(ctx, regencyID)
 	if err != nil {
 		return nil, err
 	}
-	s.cache.Set(key, result, ttlDefault)
+	s.cache.Set(key, result, s.ttl.Default)
 	return result, nil
 }
 
-func (s *cachedRegencyService) GetLatestRegencyCases() ([]models.RegencyCase, error) {
-	const key = "regency:cases:latest"
+func (s *cachedRegencyService) GetLatestRegencyCases(ctx context.Context) ([]models.RegencyCase, error) {
+	key := "regency:cases:latest:" + tenant.FromContext(ctx).ProvinceID
 	if v, ok := s.cache.Get(key); ok {
 		return v.([]models.RegencyCase), nil
 	}
-	result, err := s.svc.GetLatestRegencyCases()
+	result, err := s.svc.GetLatestRegencyCases(ctx)
 	if err != nil {
 		return nil, err
 	}
-	s.cache.Set(key, result, ttlLatest)
+	s.cache.Set(key, result, s.ttl.Latest)
 	return result, nil
 }
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/pkg/cache"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,17 +18,22 @@ type MockRegencyService struct {
 	mock.Mock
 }
 
-func (m *MockRegencyService) GetRegencies() ([]models.Regency, error) {
+func (m *MockRegencyService) GetRegencies(ctx context.Context) ([]models.Regency, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Regency), args.Error(1)
 }
 
-func (m *MockRegencyService) GetRegenciesPaginated(limit, offset int) ([]models.Regency, int, error) {
+func (m *MockRegencyService) GetRegenciesPaginated(ctx context.Context, limit, offset int) ([]models.Regency, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.Regency), args.Int(1), args.Error(2)
 }
 
-func (m *MockRegencyService) GetRegencyByID(id int) (*models.Regency, error) {
+func (m *MockRegencyService) GetRegenciesByProvince(ctx context.Context, provinceID int) ([]models.Regency, error) {
+	args := m.Called(provinceID)
+	return args.Get(0).([]models.Regency), args.Error(1)
+}
+
+func (m *MockRegencyService) GetRegencyByID(ctx context.Context, id int) (*models.Regency, error) {
 	args := m.Called(id)
 	res := args.Get(0)
 	if res == nil {
@@ -35,12 +42,12 @@ func (m *MockRegencyService) GetRegencyByID(id int) (*models.Regency, error) {
 	return res.(*models.Regency), args.Error(1)
 }
 
-func (m *MockRegencyService) GetRegencyCases(regencyID int) ([]models.RegencyCase, error) {
+func (m *MockRegencyService) GetRegencyCases(ctx context.Context, regencyID int) ([]models.RegencyCase, error) {
 	args := m.Called(regencyID)
 	return args.Get(0).([]models.RegencyCase), args.Error(1)
 }
 
-func (m *MockRegencyService) GetLatestRegencyCases() ([]models.RegencyCase, error) {
+func (m *MockRegencyService) GetLatestRegencyCases(ctx context.Context) ([]models.RegencyCase, error) {
 	args := m.Called()
 	return args.Get(0).([]models.RegencyCase), args.Error(1)
 }
@@ -54,7 +61,7 @@ func TestCachedRegencyService_GetRegencies(t *testing.T) {
 		expected := []models.Regency{{}}
 		mockSvc.On("GetRegencies").Return(expected, nil).Once()
 
-		result, err := svc.GetRegencies()
+		result, err := svc.GetRegencies(context.Background())
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 		mockSvc.AssertExpectations(t)
@@ -68,8 +75,8 @@ func TestCachedRegencyService_GetRegencies(t *testing.T) {
 		expected := []models.Regency{{}}
 		mockSvc.On("GetRegencies").Return(expected, nil).Once()
 
-		_, _ = svc.GetRegencies()
-		result, err := svc.GetRegencies()
+		_, _ = svc.GetRegencies(context.Background())
+		result, err := svc.GetRegencies(context.Background())
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 		mockSvc.AssertNumberOfCalls(t, "GetRegencies", 1)
@@ -81,9 +88,23 @@ func TestCachedRegencyService_GetRegencies(t *testing.T) {
 		svc := NewCachedRegencyService(mockSvc, c)
 
 		mockSvc.On("GetRegencies").Return([]models.Regency{}, errors.New("db error"))
-		_, err := svc.GetRegencies()
+		_, err := svc.GetRegencies(context.Background())
 		assert.Error(t, err)
 	})
+
+	t.Run("tenants are cached separately", func(t *testing.T) {
+		mockSvc := new(MockRegencyService)
+		c := cache.New(time.Hour)
+		svc := NewCachedRegencyService(mockSvc, c)
+
+		mockSvc.On("GetRegencies").Return([]models.Regency{{}}, nil).Twice()
+		gorontalo := tenant.NewContext(context.Background(), tenant.Tenant{Slug: "gorontalo", ProvinceID: "75", Name: "Gorontalo"})
+
+		_, _ = svc.GetRegencies(context.Background())
+		_, _ = svc.GetRegencies(gorontalo)
+		_, _ = svc.GetRegencies(gorontalo)
+		mockSvc.AssertNumberOfCalls(t, "GetRegencies", 2)
+	})
 }
 
 func TestCachedRegencyService_GetRegenciesPaginated(t *testing.T) {
@@ -95,7 +116,7 @@ func TestCachedRegencyService_GetRegenciesPaginated(t *testing.T) {
 		expected := []models.Regency{{}}
 		mockSvc.On("GetRegenciesPaginated", 10, 0).Return(expected, 1, nil).Once()
 
-		items, total, err := svc.GetRegenciesPaginated(10, 0)
+		items, total, err := svc.GetRegenciesPaginated(context.Background(), 10, 0)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, items)
 		assert.Equal(t, 1, total)
@@ -109,8 +130,8 @@ func TestCachedRegencyService_GetRegenciesPaginated(t *testing.T) {
 		expected := []models.Regency{{}}
 		mockSvc.On("GetRegenciesPaginated", 10, 0).Return(expected, 1, nil).Once()
 
-		_, _, _ = svc.GetRegenciesPaginated(10, 0)
-		items, total, err := svc.GetRegenciesPaginated(10, 0)
+		_, _, _ = svc.GetRegenciesPaginated(context.Background(), 10, 0)
+		items, total, err := svc.GetRegenciesPaginated(context.Background(), 10, 0)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, items)
 		assert.Equal(t, 1, total)
@@ -123,7 +144,7 @@ func TestCachedRegencyService_GetRegenciesPaginated(t *testing.T) {
 		svc := NewCachedRegencyService(mockSvc, c)
 
 		mockSvc.On("GetRegenciesPaginated", 10, 0).Return([]models.Regency{}, 0, errors.New("err"))
-		_, _, err := svc.GetRegenciesPaginated(10, 0)
+		_, _, err := svc.GetRegenciesPaginated(context.Background(), 10, 0)
 		assert.Error(t, err)
 	})
 }
@@ -137,7 +158,7 @@ func TestCachedRegencyService_GetRegencyByID(t *testing.T) {
 		expected := &models.Regency{}
 		mockSvc.On("GetRegencyByID", 1).Return(expected, nil).Once()
 
-		result, err := svc.GetRegencyByID(1)
+		result, err := svc.GetRegencyByID(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 	})
@@ -150,8 +171,8 @@ func TestCachedRegencyService_GetRegencyByID(t *testing.T) {
 		expected := &models.Regency{}
 		mockSvc.On("GetRegencyByID", 1).Return(expected, nil).Once()
 
-		_, _ = svc.GetRegencyByID(1)
-		result, err := svc.GetRegencyByID(1)
+		_, _ = svc.GetRegencyByID(context.Background(), 1)
+		result, err := svc.GetRegencyByID(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 		mockSvc.AssertNumberOfCalls(t, "GetRegencyByID", 1)
@@ -163,7 +184,7 @@ func TestCachedRegencyService_GetRegencyByID(t *testing.T) {
 		svc := NewCachedRegencyService(mockSvc, c)
 
 		mockSvc.On("GetRegencyByID", 1).Return(nil, errors.New("not found"))
-		_, err := svc.GetRegencyByID(1)
+		_, err := svc.GetRegencyByID(context.Background(), 1)
 		assert.Error(t, err)
 	})
 }
@@ -177,7 +198,7 @@ func TestCachedRegencyService_GetRegencyCases(t *testing.T) {
 		expected := []models.RegencyCase{{}}
 		mockSvc.On("GetRegencyCases", 1).Return(expected, nil).Once()
 
-		result, err := svc.GetRegencyCases(1)
+		result, err := svc.GetRegencyCases(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 	})
@@ -190,8 +211,8 @@ func TestCachedRegencyService_GetRegencyCases(t *testing.T) {
 		expected := []models.RegencyCase{{}}
 		mockSvc.On("GetRegencyCases", 1).Return(expected, nil).Once()
 
-		_, _ = svc.GetRegencyCases(1)
-		result, err := svc.GetRegencyCases(1)
+		_, _ = svc.GetRegencyCases(context.Background(), 1)
+		result, err := svc.GetRegencyCases(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 		mockSvc.AssertNumberOfCalls(t, "GetRegencyCases", 1)
@@ -203,7 +224,7 @@ func TestCachedRegencyService_GetRegencyCases(t *testing.T) {
 		svc := NewCachedRegencyService(mockSvc, c)
 
 		mockSvc.On("GetRegencyCases", 1).Return([]models.RegencyCase{}, errors.New("err"))
-		_, err := svc.GetRegencyCases(1)
+		_, err := svc.GetRegencyCases(context.Background(), 1)
 		assert.Error(t, err)
 	})
 }
@@ -217,7 +238,7 @@ func TestCachedRegencyService_GetLatestRegencyCases(t *testing.T) {
 		expected := []models.RegencyCase{{}}
 		mockSvc.On("GetLatestRegencyCases").Return(expected, nil).Once()
 
-		result, err := svc.GetLatestRegencyCases()
+		result, err := svc.GetLatestRegencyCases(context.Background())
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 	})
@@ -230,8 +251,8 @@ func TestCachedRegencyService_GetLatestRegencyCases(t *testing.T) {
 		expected := []models.RegencyCase{{}}
 		mockSvc.On("GetLatestRegencyCases").Return(expected, nil).Once()
 
-		_, _ = svc.GetLatestRegencyCases()
-		result, err := svc.GetLatestRegencyCases()
+		_, _ = svc.GetLatestRegencyCases(context.Background())
+		result, err := svc.GetLatestRegencyCases(context.Background())
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
 		mockSvc.AssertNumberOfCalls(t, "GetLatestRegencyCases", 1)
@@ -243,7 +264,22 @@ func TestCachedRegencyService_GetLatestRegencyCases(t *testing.T) {
 		svc := NewCachedRegencyService(mockSvc, c)
 
 		mockSvc.On("GetLatestRegencyCases").Return([]models.RegencyCase{}, errors.New("err"))
-		_, err := svc.GetLatestRegencyCases()
+		_, err := svc.GetLatestRegencyCases(context.Background())
 		assert.Error(t, err)
 	})
 }
+
+func TestCachedRegencyService_GetRegenciesByProvince(t *testing.T) {
+	mockSvc := new(MockRegencyService)
+	svc := NewCachedRegencyService(mockSvc, cache.New(time.Hour))
+
+	expected := []models.Regency{{ID: 7271, ProvinceID: 72}}
+	mockSvc.On("GetRegenciesByProvince", 72).Return(expected, nil).Once()
+
+	_, _ = svc.GetRegenciesByProvince(context.Background(), 72)
+	result, err := svc.GetRegenciesByProvince(context.Background(), 72)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockSvc.AssertNumberOfCalls(t, "GetRegenciesByProvince", 1)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// ErrInvalidCaseNote wraps validation failures of created or updated notes
+var ErrInvalidCaseNote = errors.New("invalid case note")
+
+// CaseNoteService manages the analyst notes on case dates and adds them to
+// case rows
+type CaseNoteService struct {
+	repo  repository.CaseNoteRepository
+	clock clock.Clock
+}
+
+func NewCaseNoteService(repo repository.CaseNoteRepository) *CaseNoteService {
+	return &CaseNoteService{repo: repo, clock: clock.System}
+}
+
+// SetClock sets the clock of the notes' created_at and updated_at
+func (s *CaseNoteService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// ListNotes returns the matching notes by date
+func (s *CaseNoteService) ListNotes(ctx context.Context, filter models.CaseNoteFilter) ([]models.CaseNote, error) {
+	notes, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list case notes: %w", err)
+	}
+	return notes, nil
+}
+
+// CreateNote validates and stores a new note
+func (s *CaseNoteService) CreateNote(ctx context.Context, n models.CaseNote) (*models.CaseNote, error) {
+	if err := n.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCaseNote, err)
+	}
+	now := s.clock.Now().UTC()
+	n.CreatedAt, n.UpdatedAt = now, now
+	id, err := s.repo.Create(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	n.ID = id
+	return &n, nil
+}
+
+// UpdateNote replaces the note's scope, date and text. It returns nil when the
+// note does not exist.
+func (s *CaseNoteService) UpdateNote(ctx context.Context, id int64, n models.CaseNote) (*models.CaseNote, error) {
+	if err := n.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCaseNote, err)
+	}
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get case note: %w", err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	n.ID = id
+	n.CreatedAt = existing.CreatedAt
+	n.UpdatedAt = s.clock.Now().UTC()
+	found, err := s.repo.Update(ctx, n)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// DeleteNote removes the note, reporting false when it does not exist
+func (s *CaseNoteService) DeleteNote(ctx context.Context, id int64) (bool, error) {
+	return s.repo.Delete(ctx, id)
+}
+
+// AnnotateNationalCases returns the cases with the notes of their dates. The
+// cases are copied when any has notes, as they may be shared with the cache.
+func (s *CaseNoteService) AnnotateNationalCases(ctx context.Context, cases []models.NationalCase) ([]models.NationalCase, error) {
+	if len(cases) == 0 {
+		return cases, nil
+	}
+	filter := models.CaseNoteFilter{Scope: models.NoteScopeNational}
+	for i, c := range cases {
+		if i == 0 || c.Date.Before(filter.StartDate) {
+			filter.StartDate = c.Date
+		}
+		if i == 0 || c.Date.After(filter.EndDate) {
+			filter.EndDate = c.Date
+		}
+	}
+	byKey, err := s.notesByKey(ctx, filter)
+	if err != nil || len(byKey) == 0 {
+		return cases, err
+	}
+
+	annotated := append([]models.NationalCase(nil), cases...)
+	for i := range annotated {
+		annotated[i].Notes = byKey[annotated[i].NoteKey()]
+	}
+	return annotated, nil
+}
+
+// AnnotateProvinceCases is AnnotateNationalCases for province cases, which
+// may be of several provinces
+func (s *CaseNoteService) AnnotateProvinceCases(ctx context.Context, cases []models.ProvinceCaseWithDate) ([]models.ProvinceCaseWithDate, error) {
+	if len(cases) == 0 {
+		return cases, nil
+	}
+	filter := models.CaseNoteFilter{Scope: cases[0].ProvinceID}
+	for i, c := range cases {
+		if c.ProvinceID != filter.Scope {
+			filter.Scope = ""
+		}
+		if i == 0 || c.Date.Before(filter.StartDate) {
+			filter.StartDate = c.Date
+		}
+		if i == 0 || c.Date.After(filter.EndDate) {
+			filter.EndDate = c.Date
+		}
+	}
+	byKey, err := s.notesByKey(ctx, filter)
+	if err != nil || len(byKey) == 0 {
+		return cases, err
+	}
+
+	annotated := append([]models.ProvinceCaseWithDate(nil), cases...)
+	for i := range annotated {
+		annotated[i].Notes = byKey[annotated[i].NoteKey()]
+	}
+	return annotated, nil
+}
+
+func (s *CaseNoteService) notesByKey(ctx context.Context, filter models.CaseNoteFilter) (map[string][]models.CaseNote, error) {
+	notes, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load case notes: %w", err)
+	}
+	byKey := make(map[string][]models.CaseNote)
+	for _, n := range notes {
+		byKey[n.Key()] = append(byKey[n.Key()], n)
+	}
+	return byKey, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCaseNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockCaseNoteRepository) List(ctx context.Context, filter models.CaseNoteFilter) ([]models.CaseNote, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CaseNote), args.Error(1)
+}
+
+func (m *MockCaseNoteRepository) Get(ctx context.Context, id int64) (*models.CaseNote, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CaseNote), args.Error(1)
+}
+
+func (m *MockCaseNoteRepository) Create(ctx context.Context, note models.CaseNote) (int64, error) {
+	args := m.Called(note)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCaseNoteRepository) Update(ctx context.Context, note models.CaseNote) (bool, error) {
+	args := m.Called(note)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCaseNoteRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+var caseNoteTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestCaseNoteService(repo *MockCaseNoteRepository) *CaseNoteService {
+	svc := NewCaseNoteService(repo)
+	svc.SetClock(clock.NewMock(caseNoteTestNow))
+	return svc
+}
+
+func TestCaseNoteService_CreateNote(t *testing.T) {
+	repo := new(MockCaseNoteRepository)
+	svc := newTestCaseNoteService(repo)
+	repo.On("Create", mock.MatchedBy(func(n models.CaseNote) bool {
+		return n.CreatedAt.Equal(caseNoteTestNow) && n.UpdatedAt.Equal(caseNoteTestNow)
+	})).Return(int64(4), nil)
+
+	created, err := svc.CreateNote(context.Background(), models.CaseNote{
+		Date: time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+		Text: models.NoteText{EN: "Backlog reported this day"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), created.ID)
+}
+
+func TestCaseNoteService_CreateNote_Invalid(t *testing.T) {
+	svc := newTestCaseNoteService(new(MockCaseNoteRepository))
+
+	_, err := svc.CreateNote(context.Background(), models.CaseNote{Date: caseNoteTestNow})
+
+	assert.ErrorIs(t, err, ErrInvalidCaseNote)
+}
+
+func TestCaseNoteService_UpdateNote_NotFound(t *testing.T) {
+	repo := new(MockCaseNoteRepository)
+	svc := newTestCaseNoteService(repo)
+	repo.On("Get", int64(9)).Return(nil, nil)
+
+	updated, err := svc.UpdateNote(context.Background(), 9, models.CaseNote{
+		Date: caseNoteTestNow, Text: models.NoteText{ID: "Laporan tertunda"},
+	})
+
+	assert.NoError(t, err)
+	assert.Nil(t, updated)
+}
+
+func TestCaseNoteService_AnnotateNationalCases(t *testing.T) {
+	repo := new(MockCaseNoteRepository)
+	svc := newTestCaseNoteService(repo)
+	day1 := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	note := models.CaseNote{ID: 1, Date: day2, Text: models.NoteText{EN: "Backlog reported this day"}}
+	repo.On("List", models.CaseNoteFilter{Scope: models.NoteScopeNational, StartDate: day1, EndDate: day2}).
+		Return([]models.CaseNote{note}, nil)
+	cases := []models.NationalCase{{Day: 1, Date: day1}, {Day: 2, Date: day2}}
+
+	annotated, err := svc.AnnotateNationalCases(context.Background(), cases)
+
+	require.NoError(t, err)
+	assert.Empty(t, annotated[0].Notes)
+	assert.Equal(t, []models.CaseNote{note}, annotated[1].Notes)
+	assert.Empty(t, cases[1].Notes, "the given cases are left alone")
+}
+
+func TestCaseNoteService_AnnotateProvinceCases_SeveralProvinces(t *testing.T) {
+	repo := new(MockCaseNoteRepository)
+	svc := newTestCaseNoteService(repo)
+	day := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	note := models.CaseNote{ID: 2, ProvinceID: "72", Date: day, Text: models.NoteText{ID: "Laporan tertunda"}}
+	repo.On("List", models.CaseNoteFilter{StartDate: day, EndDate: day}).Return([]models.CaseNote{
+		{ID: 1, Date: day, Text: models.NoteText{EN: "National backlog"}},
+		note,
+	}, nil)
+	cases := []models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "71"}, Date: day},
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "72"}, Date: day},
+	}
+
+	annotated, err := svc.AnnotateProvinceCases(context.Background(), cases)
+
+	require.NoError(t, err)
+	assert.Empty(t, annotated[0].Notes)
+	assert.Equal(t, []models.CaseNote{note}, annotated[1].Notes)
+}
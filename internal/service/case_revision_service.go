@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+)
+
+// ErrCaseNotFound is returned for a day without a case row
+var ErrCaseNotFound = errors.New("case not found")
+
+// CaseRevisionService lists the corrections of published case figures
+type CaseRevisionService struct {
+	nationalRepo repository.NationalCaseRepository
+	revisions    repository.CaseRevisionRepository
+}
+
+// NewCaseRevisionService creates a CaseRevisionService
+func NewCaseRevisionService(nationalRepo repository.NationalCaseRepository, revisions repository.CaseRevisionRepository) *CaseRevisionService {
+	return &CaseRevisionService{nationalRepo: nationalRepo, revisions: revisions}
+}
+
+// GetNationalRevisions returns the revisions of the national case of a day,
+// or ErrCaseNotFound
+func (s *CaseRevisionService) GetNationalRevisions(ctx context.Context, day int64) (*models.CaseRevisionHistory, error) {
+	nationalCase, err := s.nationalRepo.GetByDay(ctx, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get national case: %w", err)
+	}
+	if nationalCase == nil {
+		return nil, ErrCaseNotFound
+	}
+	revisions, err := s.revisions.ListByRow(ctx, "national_cases", nationalCase.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get national case revisions: %w", err)
+	}
+	return &models.CaseRevisionHistory{Day: nationalCase.Day, Date: nationalCase.Date, Revisions: revisions}, nil
+}
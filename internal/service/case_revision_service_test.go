@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCaseRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCaseRevisionRepository) ListByRow(ctx context.Context, table string, rowID int64) ([]models.CaseRevision, error) {
+	args := m.Called(table, rowID)
+	return args.Get(0).([]models.CaseRevision), args.Error(1)
+}
+
+func TestCaseRevisionService_GetNationalRevisions(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	revisionRepo := new(MockCaseRevisionRepository)
+	date := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	nationalRepo.On("GetByDay", int64(500)).Return(&models.NationalCase{ID: 42, Day: 500, Date: date}, nil)
+	revisions := []models.CaseRevision{{ID: 1, Action: "insert"}, {ID: 2, Action: "update"}}
+	revisionRepo.On("ListByRow", "national_cases", int64(42)).Return(revisions, nil)
+
+	history, err := NewCaseRevisionService(nationalRepo, revisionRepo).GetNationalRevisions(context.Background(), 500)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.CaseRevisionHistory{Day: 500, Date: date, Revisions: revisions}, history)
+	revisionRepo.AssertExpectations(t)
+}
+
+func TestCaseRevisionService_GetNationalRevisions_NotFound(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	nationalRepo.On("GetByDay", int64(9999)).Return(nil, nil)
+
+	_, err := NewCaseRevisionService(nationalRepo, new(MockCaseRevisionRepository)).GetNationalRevisions(context.Background(), 9999)
+
+	assert.ErrorIs(t, err, ErrCaseNotFound)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// caseStreamEvents are the published events passed on to stream subscribers
+var caseStreamEvents = map[string]bool{
+	models.WebhookEventSyncCompleted:       true,
+	models.WebhookEventNationalCaseCreated: true,
+	models.WebhookEventProvinceCaseCreated: true,
+}
+
+// caseStreamBuffer is how many events a subscriber may fall behind before it
+// is dropped
+const caseStreamBuffer = 16
+
+// caseStreamSubscriber is one open stream
+type caseStreamSubscriber struct {
+	filter models.WebhookFilter
+	events chan models.WebhookEnvelope
+}
+
+// CaseStream passes new case data to the open /stream/cases connections. It
+// is a WebhookPublisher, so it receives the same sync.completed,
+// national_case.created and province_case.created events as webhook
+// subscribers. Events are only held in memory and are not replayed.
+type CaseStream struct {
+	mu          sync.Mutex
+	subscribers map[*caseStreamSubscriber]struct{}
+	lastID      int64
+	closed      bool
+	clock       clock.Clock
+}
+
+// NewCaseStream creates a CaseStream
+func NewCaseStream() *CaseStream {
+	return &CaseStream{subscribers: make(map[*caseStreamSubscriber]struct{}), clock: clock.System}
+}
+
+// SetClock sets the clock that stamps published events
+func (s *CaseStream) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Subscribe returns a channel of the events matching filter and a function
+// that ends the subscription. The channel is closed when the subscription
+// ends, including when the subscriber falls more than caseStreamBuffer
+// events behind and when the stream is closed.
+func (s *CaseStream) Subscribe(filter models.WebhookFilter) (<-chan models.WebhookEnvelope, func()) {
+	sub := &caseStreamSubscriber{filter: filter, events: make(chan models.WebhookEnvelope, caseStreamBuffer)}
+	s.mu.Lock()
+	if s.closed {
+		close(sub.events)
+	} else {
+		s.subscribers[sub] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	return sub.events, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.remove(sub)
+	}
+}
+
+// Publish sends event to every subscriber whose filter it matches. It never
+// blocks on a slow subscriber.
+func (s *CaseStream) Publish(ctx context.Context, event string, data interface{}) error {
+	if !caseStreamEvents[event] {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	envelope := models.WebhookEnvelope{
+		ID:         strconv.FormatInt(s.lastID, 10),
+		Event:      event,
+		OccurredAt: s.clock.Now().UTC(),
+	}
+	for sub := range s.subscribers {
+		subData, ok := filterEventData(sub.filter, data)
+		if !ok {
+			continue
+		}
+		envelope.Data = subData
+		select {
+		case sub.events <- envelope:
+		default:
+			s.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Close ends every subscription, and those made afterwards, so the open
+// streams finish during a shutdown
+func (s *CaseStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subscribers {
+		s.remove(sub)
+	}
+}
+
+// remove ends a subscription once; the caller holds s.mu
+func (s *CaseStream) remove(sub *caseStreamSubscriber) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
+
+// Publishers sends each event to every publisher in turn, so one source of
+// events can feed both webhooks and the case stream
+type Publishers []WebhookPublisher
+
+// Publish calls every publisher, even after one fails, and returns their
+// joined errors
+func (ps Publishers) Publish(ctx context.Context, event string, data interface{}) error {
+	var errs []error
+	for _, p := range ps {
+		if err := p.Publish(ctx, event, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
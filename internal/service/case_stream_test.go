@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseStream_Publish(t *testing.T) {
+	stream := NewCaseStream()
+	stream.SetClock(clock.NewMock(time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC)))
+	all, cancelAll := stream.Subscribe(models.WebhookFilter{})
+	defer cancelAll()
+	central, cancelCentral := stream.Subscribe(models.WebhookFilter{ProvinceIDs: []string{"72"}})
+	defer cancelCentral()
+
+	data := models.ProvinceCaseCreatedData{Cases: []models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{ID: 1, Day: 500, ProvinceID: "72"}},
+		{ProvinceCase: models.ProvinceCase{ID: 2, Day: 500, ProvinceID: "73"}},
+	}}
+	require.NoError(t, stream.Publish(context.Background(), models.WebhookEventProvinceCaseCreated, data))
+	// Alerts are not case data and are not streamed
+	require.NoError(t, stream.Publish(context.Background(), models.WebhookEventAlertTriggered, struct{}{}))
+
+	event := <-all
+	assert.Equal(t, "1", event.ID)
+	assert.Equal(t, models.WebhookEventProvinceCaseCreated, event.Event)
+	assert.Equal(t, time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC), event.OccurredAt)
+	assert.Len(t, event.Data.(models.ProvinceCaseCreatedData).Cases, 2)
+
+	event = <-central
+	require.Len(t, event.Data.(models.ProvinceCaseCreatedData).Cases, 1)
+	assert.Equal(t, "72", event.Data.(models.ProvinceCaseCreatedData).Cases[0].ProvinceID)
+
+	assert.Empty(t, all)
+	assert.Empty(t, central)
+}
+
+func TestCaseStream_DropsSlowSubscriber(t *testing.T) {
+	stream := NewCaseStream()
+	events, cancel := stream.Subscribe(models.WebhookFilter{})
+
+	data := models.NationalCaseCreatedData{Cases: []models.NationalCase{{ID: 1, Day: 1}}}
+	for i := 0; i <= caseStreamBuffer; i++ {
+		require.NoError(t, stream.Publish(context.Background(), models.WebhookEventNationalCaseCreated, data))
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, caseStreamBuffer, received)
+	assert.Empty(t, stream.subscribers)
+	// Ending an already dropped subscription is harmless
+	cancel()
+}
+
+func TestCaseStream_Cancel(t *testing.T) {
+	stream := NewCaseStream()
+	events, cancel := stream.Subscribe(models.WebhookFilter{})
+	cancel()
+	cancel()
+
+	_, open := <-events
+	assert.False(t, open)
+	assert.NoError(t, stream.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 1}))
+}
+
+func TestPublishers_Publish(t *testing.T) {
+	failing := new(MockWebhookPublisher)
+	failing.On("Publish", models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 1}).Return(errors.New("db down"))
+	stream := NewCaseStream()
+	events, cancel := stream.Subscribe(models.WebhookFilter{})
+	defer cancel()
+
+	err := Publishers{failing, stream}.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 1})
+
+	assert.ErrorContains(t, err, "db down")
+	// The stream still got the event after the first publisher failed
+	assert.Equal(t, models.WebhookEventSyncCompleted, (<-events).Event)
+	failing.AssertExpectations(t)
+}
+
+func TestCaseStream_Close(t *testing.T) {
+	stream := NewCaseStream()
+	events, cancel := stream.Subscribe(models.WebhookFilter{})
+	defer cancel()
+
+	stream.Close()
+
+	_, open := <-events
+	assert.False(t, open)
+	later, cancelLater := stream.Subscribe(models.WebhookFilter{})
+	defer cancelLater()
+	_, open = <-later
+	assert.False(t, open)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// Cursor names under which CaseWebhookPoller stores the last announced row ID
+const (
+	nationalCaseCursor = "national_cases"
+	provinceCaseCursor = "province_cases"
+)
+
+// caseWebhookBatch limits the cases sent in one event, so a large backfill is
+// split over several deliveries
+const caseWebhookBatch = 100
+
+// CaseWebhookCursors stores the last case row ID the poller announced per table
+type CaseWebhookCursors interface {
+	GetCursor(ctx context.Context, name string) (int64, bool, error)
+	SetCursor(ctx context.Context, name string, lastID int64) error
+}
+
+// CaseWebhookPoller publishes national_case.created and province_case.created
+// events for case rows added since the previous poll, whether they came in
+// through ingestion or were written to the database directly. Delivery,
+// signing and retries are left to the publisher.
+type CaseWebhookPoller struct {
+	nationalRepo repository.NationalCaseRepository
+	provinceRepo repository.ProvinceCaseRepository
+	cursors      CaseWebhookCursors
+	publisher    WebhookPublisher
+
+	clock    clock.Clock
+	stopChan chan struct{}
+}
+
+// NewCaseWebhookPoller creates a CaseWebhookPoller
+func NewCaseWebhookPoller(nationalRepo repository.NationalCaseRepository, provinceRepo repository.ProvinceCaseRepository, cursors CaseWebhookCursors, publisher WebhookPublisher) *CaseWebhookPoller {
+	return &CaseWebhookPoller{
+		nationalRepo: nationalRepo,
+		provinceRepo: provinceRepo,
+		cursors:      cursors,
+		publisher:    publisher,
+		clock:        clock.System,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock of the poll schedule
+func (p *CaseWebhookPoller) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// Poll publishes the case rows added since the previous poll and moves the
+// cursors past them. The first poll only records the current highest IDs, so
+// existing data is never announced. A cursor only moves once its events were
+// handed to the publisher.
+func (p *CaseWebhookPoller) Poll(ctx context.Context) error {
+	if err := p.pollNational(ctx); err != nil {
+		return err
+	}
+	return p.pollProvince(ctx)
+}
+
+func (p *CaseWebhookPoller) pollNational(ctx context.Context) error {
+	lastID, ok, err := p.cursors.GetCursor(ctx, nationalCaseCursor)
+	if err != nil {
+		return err
+	}
+	cases, _, err := p.nationalRepo.Find(ctx, repository.NationalCaseQuery{AfterID: lastID, Sort: byDateAsc})
+	if err != nil {
+		return fmt.Errorf("failed to get new national cases: %w", err)
+	}
+	if len(cases) == 0 {
+		if !ok {
+			return p.cursors.SetCursor(ctx, nationalCaseCursor, 0)
+		}
+		return nil
+	}
+
+	maxID := lastID
+	for _, c := range cases {
+		maxID = max(maxID, c.ID)
+	}
+	if ok {
+		for start := 0; start < len(cases); start += caseWebhookBatch {
+			batch := cases[start:min(start+caseWebhookBatch, len(cases))]
+			data := models.NationalCaseCreatedData{Cases: batch}
+			if err := p.publisher.Publish(ctx, models.WebhookEventNationalCaseCreated, data); err != nil {
+				return err
+			}
+		}
+	}
+	return p.cursors.SetCursor(ctx, nationalCaseCursor, maxID)
+}
+
+func (p *CaseWebhookPoller) pollProvince(ctx context.Context) error {
+	lastID, ok, err := p.cursors.GetCursor(ctx, provinceCaseCursor)
+	if err != nil {
+		return err
+	}
+	cases, _, err := p.provinceRepo.Find(ctx, repository.ProvinceCaseQuery{AfterID: lastID, Sort: byDateAsc})
+	if err != nil {
+		return fmt.Errorf("failed to get new province cases: %w", err)
+	}
+	if len(cases) == 0 {
+		if !ok {
+			return p.cursors.SetCursor(ctx, provinceCaseCursor, 0)
+		}
+		return nil
+	}
+
+	maxID := lastID
+	for _, c := range cases {
+		maxID = max(maxID, c.ID)
+	}
+	if ok {
+		for start := 0; start < len(cases); start += caseWebhookBatch {
+			batch := cases[start:min(start+caseWebhookBatch, len(cases))]
+			data := models.ProvinceCaseCreatedData{Cases: batch}
+			if err := p.publisher.Publish(ctx, models.WebhookEventProvinceCaseCreated, data); err != nil {
+				return err
+			}
+		}
+	}
+	return p.cursors.SetCursor(ctx, provinceCaseCursor, maxID)
+}
+
+// Start polls at the given interval in a background goroutine.
+func (p *CaseWebhookPoller) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		ticker := p.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if err := p.Poll(ctx); err != nil {
+					log.Printf("Case webhook poll failed: %v", err)
+				}
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background polling started by Start.
+func (p *CaseWebhookPoller) Stop() {
+	close(p.stopChan)
+}
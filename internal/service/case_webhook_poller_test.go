@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookPublisher struct {
+	mock.Mock
+}
+
+func (m *MockWebhookPublisher) Publish(ctx context.Context, event string, data interface{}) error {
+	return m.Called(event, data).Error(0)
+}
+
+func provinceCaseRows(ids ...int64) []models.ProvinceCaseWithDate {
+	cases := make([]models.ProvinceCaseWithDate, len(ids))
+	for i, id := range ids {
+		cases[i] = models.ProvinceCaseWithDate{ProvinceCase: models.ProvinceCase{ID: id, Day: id, ProvinceID: "72"}}
+	}
+	return cases
+}
+
+func TestCaseWebhookPoller_Poll(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	provinceRepo := new(MockProvinceCaseRepository)
+	cursors := new(MockWebhookRepository)
+	publisher := new(MockWebhookPublisher)
+
+	cursors.On("GetCursor", "national_cases").Return(int64(10), true, nil)
+	nationalRepo.On("Find", repository.NationalCaseQuery{AfterID: 10, Sort: byDateAsc}).
+		Return([]models.NationalCase{{ID: 12, Day: 12}, {ID: 11, Day: 11}}, 2, nil)
+	publisher.On("Publish", models.WebhookEventNationalCaseCreated,
+		models.NationalCaseCreatedData{Cases: []models.NationalCase{{ID: 12, Day: 12}, {ID: 11, Day: 11}}}).Return(nil)
+	cursors.On("SetCursor", "national_cases", int64(12)).Return(nil)
+
+	cursors.On("GetCursor", "province_cases").Return(int64(500), true, nil)
+	provinceRepo.On("Find", repository.ProvinceCaseQuery{AfterID: 500, Sort: byDateAsc}).Return(provinceCaseRows(), 0, nil)
+
+	err := NewCaseWebhookPoller(nationalRepo, provinceRepo, cursors, publisher).Poll(context.Background())
+
+	assert.NoError(t, err)
+	cursors.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+	cursors.AssertNotCalled(t, "SetCursor", "province_cases", mock.Anything)
+}
+
+func TestCaseWebhookPoller_Poll_FirstRunOnlyRecordsCursor(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	provinceRepo := new(MockProvinceCaseRepository)
+	cursors := new(MockWebhookRepository)
+	publisher := new(MockWebhookPublisher)
+
+	cursors.On("GetCursor", "national_cases").Return(int64(0), false, nil)
+	nationalRepo.On("Find", repository.NationalCaseQuery{Sort: byDateAsc}).Return([]models.NationalCase{}, 0, nil)
+	cursors.On("SetCursor", "national_cases", int64(0)).Return(nil)
+	cursors.On("GetCursor", "province_cases").Return(int64(0), false, nil)
+	provinceRepo.On("Find", repository.ProvinceCaseQuery{Sort: byDateAsc}).Return(provinceCaseRows(3, 9, 4), 3, nil)
+	cursors.On("SetCursor", "province_cases", int64(9)).Return(nil)
+
+	err := NewCaseWebhookPoller(nationalRepo, provinceRepo, cursors, publisher).Poll(context.Background())
+
+	assert.NoError(t, err)
+	cursors.AssertExpectations(t)
+	publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestCaseWebhookPoller_Poll_Batches(t *testing.T) {
+	ids := make([]int64, caseWebhookBatch+1)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	nationalRepo := new(MockNationalCaseRepository)
+	provinceRepo := new(MockProvinceCaseRepository)
+	cursors := new(MockWebhookRepository)
+	publisher := new(MockWebhookPublisher)
+
+	cursors.On("GetCursor", "national_cases").Return(int64(0), true, nil)
+	nationalRepo.On("Find", mock.Anything).Return([]models.NationalCase{}, 0, nil)
+	cursors.On("GetCursor", "province_cases").Return(int64(0), true, nil)
+	provinceRepo.On("Find", mock.Anything).Return(provinceCaseRows(ids...), len(ids), nil)
+	publisher.On("Publish", models.WebhookEventProvinceCaseCreated, models.ProvinceCaseCreatedData{Cases: provinceCaseRows(ids[:caseWebhookBatch]...)}).Return(nil).Once()
+	publisher.On("Publish", models.WebhookEventProvinceCaseCreated, models.ProvinceCaseCreatedData{Cases: provinceCaseRows(ids[caseWebhookBatch:]...)}).Return(nil).Once()
+	cursors.On("SetCursor", "province_cases", int64(caseWebhookBatch+1)).Return(nil)
+
+	err := NewCaseWebhookPoller(nationalRepo, provinceRepo, cursors, publisher).Poll(context.Background())
+
+	assert.NoError(t, err)
+	publisher.AssertExpectations(t)
+	cursors.AssertExpectations(t)
+}
+
+func TestCaseWebhookPoller_Poll_PublishErrorKeepsCursor(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	provinceRepo := new(MockProvinceCaseRepository)
+	cursors := new(MockWebhookRepository)
+	publisher := new(MockWebhookPublisher)
+
+	cursors.On("GetCursor", "national_cases").Return(int64(10), true, nil)
+	nationalRepo.On("Find", mock.Anything).Return([]models.NationalCase{{ID: 11, Day: 11}}, 1, nil)
+	publisher.On("Publish", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	err := NewCaseWebhookPoller(nationalRepo, provinceRepo, cursors, publisher).Poll(context.Background())
+
+	assert.EqualError(t, err, "db down")
+	cursors.AssertNotCalled(t, "SetCursor", mock.Anything, mock.Anything)
+	provinceRepo.AssertNotCalled(t, "Find", mock.Anything)
+}
@@ -1,434 +1,552 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 )
 
 type CovidService interface {
-	GetNationalCases() ([]models.NationalCase, error)
-	GetNationalCasesSorted(sortParams utils.SortParams) ([]models.NationalCase, error)
-	GetNationalCasesPaginated(limit, offset int) ([]models.NationalCase, int, error)
-	GetNationalCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error)
-	GetNationalCasesByDateRange(startDate, endDate string) ([]models.NationalCase, error)
-	GetNationalCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.NationalCase, error)
-	GetNationalCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.NationalCase, int, error)
-	GetNationalCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error)
-	GetLatestNationalCase() (*models.NationalCase, error)
-	GetNationalCaseByDay(day int64) (*models.NationalCase, error)
-	GetProvinces() ([]models.Province, error)
-	GetProvinceByID(id string) (*models.Province, error)
-	GetProvincesWithLatestCase() ([]models.ProvinceWithLatestCase, error)
-	GetProvinceCases(provinceID string) ([]models.ProvinceCaseWithDate, error)
-	GetProvinceCasesSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetProvinceCasesPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetProvinceCasesPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetProvinceCasesByDateRange(provinceID, startDate, endDate string) ([]models.ProvinceCaseWithDate, error)
-	GetProvinceCasesByDateRangeSorted(provinceID, startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetProvinceCasesByDateRangePaginated(provinceID, startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetProvinceCasesByDateRangePaginatedSorted(provinceID, startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetAllProvinceCases() ([]models.ProvinceCaseWithDate, error)
-	GetAllProvinceCasesSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetAllProvinceCasesPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetAllProvinceCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-	GetAllProvinceCasesByDateRange(startDate, endDate string) ([]models.ProvinceCaseWithDate, error)
-	GetAllProvinceCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error)
-	GetAllProvinceCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error)
-	GetAllProvinceCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error)
-}
+	// ListNationalCases returns the national cases selected by opts and their
+	// total count. Without a sort they are ordered by date, oldest first.
+	ListNationalCases(ctx context.Context, opts QueryOptions) ([]models.NationalCase, int, error)
+	// EachNationalCase calls fn with the cases ListNationalCases would return
+	// for opts without its page, in consecutive chunks read while the query
+	// runs, so no more than a chunk is held in memory. AsOf is not supported.
+	EachNationalCase(ctx context.Context, opts QueryOptions, fn func([]models.NationalCase) error) error
+	GetLatestNationalCase(ctx context.Context) (*models.NationalCase, error)
+	GetNationalCaseByDay(ctx context.Context, day int64) (*models.NationalCase, error)
+	// GetNationalCaseByDate returns the national case of one date, or nil when
+	// there is none
+	GetNationalCaseByDate(ctx context.Context, date time.Time) (*models.NationalCase, error)
+	GetProvinces(ctx context.Context) ([]models.Province, error)
+	GetProvinceByID(ctx context.Context, id string) (*models.Province, error)
+	GetProvincesWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error)
+	GetProvincesWithLatestCaseByIDs(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error)
+	// ListProvinceCases returns the province cases selected by opts and their
+	// total count. Without a sort the cases of one province are newest first
+	// and those of all provinces oldest first.
+	ListProvinceCases(ctx context.Context, opts QueryOptions) ([]models.ProvinceCaseWithDate, int, error)
+	// EachProvinceCase is EachNationalCase for province cases
+	EachProvinceCase(ctx context.Context, opts QueryOptions, fn func([]models.ProvinceCaseWithDate) error) error
+	GetProvinceCasesAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error)
+	// GetProvinceCaseByDate returns the case of a province on one date, or nil
+	// when there is none
+	GetProvinceCaseByDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error)
+	// GetNationalCasesAggregated totals the national cases per ISO week or calendar month
+	GetNationalCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error)
+	// GetProvinceCasesAggregated totals the province cases per province and ISO week or calendar month
+	GetProvinceCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error)
+	// GetNationalTotals sums the national cases within dates, or of all dates
+	// when the range is not set
+	GetNationalTotals(ctx context.Context, dates DateRange) (models.PeriodTotals, error)
+	// AddNationalMovingAverages returns a copy of cases with the moving average
+	// of daily counts over the given number of days ending on each date
+	AddNationalMovingAverages(ctx context.Context, cases []models.NationalCase, days int) ([]models.NationalCase, error)
+	// AddProvinceMovingAverages returns a copy of cases with the moving average
+	// of daily counts over the given number of days ending on each date, per
+	// province
+	AddProvinceMovingAverages(ctx context.Context, cases []models.ProvinceCaseWithDate, days int) ([]models.ProvinceCaseWithDate, error)
+	// ResolveDateRange turns a ?range= preset such as last30d into start and end dates
+	ResolveDateRange(ctx context.Context, preset string) (startDate, endDate string, err error)
+}
+
+// Page selects Limit cases starting at Offset; every case when Limit is 0
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// QueryOptions selects a list of national or province cases. Each field is
+// optional, so a new filter is one more field rather than another method.
+type QueryOptions struct {
+	// ProvinceID limits province cases to one province; all provinces when
+	// empty. National lists ignore it.
+	ProvinceID string
+	Range      DateRange
+	// HasRt skips the province cases without an Rt value. National lists ignore it.
+	HasRt bool
+	// Sort orders the cases; the list's default order when Field is empty
+	Sort utils.SortParams
+	Page Page
+	// AsOf returns the cases as they were published at that time rather than
+	// with later corrections; the current cases when zero. Only date order is
+	// supported.
+	AsOf time.Time
+	// Columns names the case columns the caller uses, which are then the only
+	// ones read besides the key columns; every column when empty
+	Columns []string
+}
+
+// sortOr returns the requested order, or def when the client did not sort
+func (o QueryOptions) sortOr(def utils.SortParams) utils.SortParams {
+	if o.Sort.Field == "" {
+		return def
+	}
+	return o.Sort
+}
+
+// streamChunkSize is the number of cases EachNationalCase and
+// EachProvinceCase hand over at a time
+const streamChunkSize = 500
+
+// Orders of the case lists that the client does not sort
+var (
+	byDateAsc  = utils.SortParams{Field: "date", Order: "asc"}
+	byDateDesc = utils.SortParams{Field: "date", Order: "desc"}
+)
 
 type covidService struct {
 	nationalCaseRepo repository.NationalCaseRepository
 	provinceRepo     repository.ProvinceRepository
 	provinceCaseRepo repository.ProvinceCaseRepository
+	// testRepo is optional; without it cases carry no test counts
+	testRepo repository.TestRepository
+	clock    clock.Clock
 }
 
 func NewCovidService(
 	nationalCaseRepo repository.NationalCaseRepository,
 	provinceRepo repository.ProvinceRepository,
 	provinceCaseRepo repository.ProvinceCaseRepository,
+) CovidService {
+	return NewCovidServiceWithTests(nationalCaseRepo, provinceRepo, provinceCaseRepo, nil)
+}
+
+// NewCovidServiceWithTests returns a CovidService that adds the day's test
+// counts to the cases it returns
+func NewCovidServiceWithTests(
+	nationalCaseRepo repository.NationalCaseRepository,
+	provinceRepo repository.ProvinceRepository,
+	provinceCaseRepo repository.ProvinceCaseRepository,
+	testRepo repository.TestRepository,
 ) CovidService {
 	return &covidService{
 		nationalCaseRepo: nationalCaseRepo,
 		provinceRepo:     provinceRepo,
 		provinceCaseRepo: provinceCaseRepo,
+		testRepo:         testRepo,
+		clock:            clock.System,
 	}
 }
 
-func (s *covidService) GetNationalCases() ([]models.NationalCase, error) {
-	cases, err := s.nationalCaseRepo.GetAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get national cases: %w", err)
-	}
-	return cases, nil
+// SetClock sets the clock that ends date range presets when there is no case
+func (s *covidService) SetClock(c clock.Clock) {
+	s.clock = c
 }
 
-func (s *covidService) GetNationalCasesSorted(sortParams utils.SortParams) ([]models.NationalCase, error) {
-	cases, err := s.nationalCaseRepo.GetAllSorted(sortParams)
+func (s *covidService) ListNationalCases(ctx context.Context, opts QueryOptions) ([]models.NationalCase, int, error) {
+	cases, total, err := s.nationalCaseRepo.Find(ctx, nationalCaseQuery(opts))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sorted national cases: %w", err)
+		return nil, 0, fmt.Errorf("failed to list national cases: %w", err)
 	}
-	return cases, nil
+	s.attachNationalTests(ctx, cases)
+	return cases, total, nil
 }
 
-func (s *covidService) GetNationalCasesByDateRange(startDate, endDate string) ([]models.NationalCase, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
-	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
-	}
-
-	cases, err := s.nationalCaseRepo.GetByDateRange(start, end)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get national cases by date range: %w", err)
+func (s *covidService) EachNationalCase(ctx context.Context, opts QueryOptions, fn func([]models.NationalCase) error) error {
+	opts.Page = Page{}
+	q := nationalCaseQuery(opts)
+	return eachChunk(func(yield func(models.NationalCase) error) error {
+		if err := s.nationalCaseRepo.Each(ctx, q, yield); err != nil {
+			return fmt.Errorf("failed to stream national cases: %w", err)
+		}
+		return nil
+	}, func(chunk []models.NationalCase) error {
+		s.attachNationalTests(ctx, chunk)
+		return fn(chunk)
+	})
+}
+
+func nationalCaseQuery(opts QueryOptions) repository.NationalCaseQuery {
+	q := repository.NationalCaseQuery{
+		Sort:    opts.sortOr(byDateAsc),
+		Limit:   opts.Page.Limit,
+		Offset:  opts.Page.Offset,
+		AsOf:    opts.AsOf,
+		Columns: opts.Columns,
+	}
+	if opts.Range.IsSet() {
+		q.StartDate, q.EndDate = opts.Range.Start, opts.Range.End
+	}
+	return q
+}
+
+// eachChunk gathers the rows each yields into chunks of streamChunkSize and
+// passes every chunk to fn, the last one possibly shorter. Each chunk is a
+// new slice, as fn may keep it.
+func eachChunk[T any](each func(yield func(T) error) error, fn func([]T) error) error {
+	chunk := make([]T, 0, streamChunkSize)
+	err := each(func(row T) error {
+		chunk = append(chunk, row)
+		if len(chunk) < streamChunkSize {
+			return nil
+		}
+		full := chunk
+		chunk = make([]T, 0, streamChunkSize)
+		return fn(full)
+	})
+	if err != nil || len(chunk) == 0 {
+		return err
 	}
-	return cases, nil
+	return fn(chunk)
 }
 
-func (s *covidService) GetNationalCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.NationalCase, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
-	}
-
-	end, err := time.Parse("2006-01-02", endDate)
+func (s *covidService) GetLatestNationalCase(ctx context.Context) (*models.NationalCase, error) {
+	nationalCase, err := s.nationalCaseRepo.GetLatest(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
+		return nil, fmt.Errorf("failed to get latest national case: %w", err)
 	}
+	s.attachNationalCaseTests(ctx, nationalCase)
+	return nationalCase, nil
+}
 
-	cases, err := s.nationalCaseRepo.GetByDateRangeSorted(start, end, sortParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get sorted national cases by date range: %w", err)
-	}
-	return cases, nil
+func (s *covidService) ResolveDateRange(ctx context.Context, preset string) (string, string, error) {
+	return resolveDateRange(ctx, preset, s.clock, s.nationalCaseRepo.GetLatest)
 }
 
-func (s *covidService) GetLatestNationalCase() (*models.NationalCase, error) {
-	nationalCase, err := s.nationalCaseRepo.GetLatest()
+func (s *covidService) GetNationalCaseByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
+	nationalCase, err := s.nationalCaseRepo.GetByDay(ctx, day)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest national case: %w", err)
+		return nil, fmt.Errorf("failed to get national case by day: %w", err)
 	}
+	s.attachNationalCaseTests(ctx, nationalCase)
 	return nationalCase, nil
 }
 
-func (s *covidService) GetNationalCaseByDay(day int64) (*models.NationalCase, error) {
-	nationalCase, err := s.nationalCaseRepo.GetByDay(day)
+func (s *covidService) GetNationalCaseByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	nationalCase, err := s.nationalCaseRepo.GetByDate(ctx, date)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get national case by day: %w", err)
+		return nil, fmt.Errorf("failed to get national case by date: %w", err)
 	}
+	s.attachNationalCaseTests(ctx, nationalCase)
 	return nationalCase, nil
 }
 
-func (s *covidService) GetProvinceByID(id string) (*models.Province, error) {
-	province, err := s.provinceRepo.GetByID(id)
+func (s *covidService) GetProvinceByID(ctx context.Context, id string) (*models.Province, error) {
+	province, err := s.provinceRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get province: %w", err)
 	}
 	return province, nil
 }
 
-func (s *covidService) GetNationalCasesPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	cases, total, err := s.nationalCaseRepo.GetAllPaginated(limit, offset)
+func (s *covidService) GetProvinces(ctx context.Context) ([]models.Province, error) {
+	provinces, err := s.provinceRepo.GetAll(ctx)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get paginated national cases: %w", err)
+		return nil, fmt.Errorf("failed to get provinces: %w", err)
 	}
-	return cases, total, nil
+	return provinces, nil
 }
 
-func (s *covidService) GetNationalCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	cases, total, err := s.nationalCaseRepo.GetAllPaginatedSorted(limit, offset, sortParams)
+func (s *covidService) GetProvincesWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
+	provinces, err := s.provinceRepo.GetAllWithLatestCase(ctx)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get paginated sorted national cases: %w", err)
+		return nil, fmt.Errorf("failed to get provinces with latest case: %w", err)
 	}
-	return cases, total, nil
+	s.attachLatestCaseTests(ctx, provinces)
+	return provinces, nil
 }
 
-func (s *covidService) GetNationalCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.NationalCase, int, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid start date format: %w", err)
-	}
-
-	end, err := time.Parse("2006-01-02", endDate)
+func (s *covidService) GetProvincesWithLatestCaseByIDs(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	provinces, err := s.provinceRepo.GetByIDsWithLatestCase(ctx, ids)
 	if err != nil {
-		return nil, 0, fmt.Errorf("invalid end date format: %w", err)
+		return nil, fmt.Errorf("failed to get provinces with latest case by IDs: %w", err)
 	}
+	s.attachLatestCaseTests(ctx, provinces)
+	return provinces, nil
+}
 
-	cases, total, err := s.nationalCaseRepo.GetByDateRangePaginated(start, end, limit, offset)
+func (s *covidService) ListProvinceCases(ctx context.Context, opts QueryOptions) ([]models.ProvinceCaseWithDate, int, error) {
+	cases, total, err := s.provinceCaseRepo.Find(ctx, provinceCaseQuery(opts))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get paginated national cases by date range: %w", err)
+		return nil, 0, fmt.Errorf("failed to list province cases: %w", err)
 	}
+	s.attachProvinceTests(ctx, cases)
 	return cases, total, nil
 }
 
-func (s *covidService) GetNationalCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid start date format: %w", err)
-	}
+func (s *covidService) EachProvinceCase(ctx context.Context, opts QueryOptions, fn func([]models.ProvinceCaseWithDate) error) error {
+	opts.Page = Page{}
+	q := provinceCaseQuery(opts)
+	return eachChunk(func(yield func(models.ProvinceCaseWithDate) error) error {
+		if err := s.provinceCaseRepo.Each(ctx, q, yield); err != nil {
+			return fmt.Errorf("failed to stream province cases: %w", err)
+		}
+		return nil
+	}, func(chunk []models.ProvinceCaseWithDate) error {
+		s.attachProvinceTests(ctx, chunk)
+		return fn(chunk)
+	})
+}
 
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid end date format: %w", err)
+func provinceCaseQuery(opts QueryOptions) repository.ProvinceCaseQuery {
+	defaultSort := byDateAsc
+	if opts.ProvinceID != "" {
+		defaultSort = byDateDesc
 	}
-
-	cases, total, err := s.nationalCaseRepo.GetByDateRangePaginatedSorted(start, end, limit, offset, sortParams)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get paginated sorted national cases by date range: %w", err)
+	q := repository.ProvinceCaseQuery{
+		ProvinceID: opts.ProvinceID,
+		HasRt:      opts.HasRt,
+		Sort:       opts.sortOr(defaultSort),
+		Limit:      opts.Page.Limit,
+		Offset:     opts.Page.Offset,
+		AsOf:       opts.AsOf,
+		Columns:    opts.Columns,
 	}
-	return cases, total, nil
+	if opts.Range.IsSet() {
+		q.StartDate, q.EndDate = opts.Range.Start, opts.Range.End
+	}
+	return q
 }
 
-func (s *covidService) GetProvinces() ([]models.Province, error) {
-	provinces, err := s.provinceRepo.GetAll()
+func (s *covidService) GetProvinceCasesAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	cases, next, err := s.provinceCaseRepo.GetPageAfterCursor(ctx, q)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get provinces: %w", err)
+		return nil, nil, fmt.Errorf("failed to get province cases after cursor: %w", err)
 	}
-	return provinces, nil
+	s.attachProvinceTests(ctx, cases)
+	return cases, next, nil
 }
 
-func (s *covidService) GetProvincesWithLatestCase() ([]models.ProvinceWithLatestCase, error) {
-	provinces, err := s.provinceRepo.GetAll()
+func (s *covidService) GetProvinceCaseByDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	provinceCase, err := s.provinceCaseRepo.GetByProvinceIDAndDate(ctx, provinceID, date)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get provinces: %w", err)
+		return nil, fmt.Errorf("failed to get province case by date: %w", err)
 	}
-
-	result := make([]models.ProvinceWithLatestCase, len(provinces))
-
-	for i, province := range provinces {
-		result[i] = models.ProvinceWithLatestCase{
-			Province: province,
-		}
-
-		// Get latest case for this province
-		latestCase, err := s.provinceCaseRepo.GetLatestByProvinceID(province.ID)
-		if err != nil {
-			// If error or no data, continue without latest case
-			continue
-		}
-
-		if latestCase != nil {
-			// Transform to response format without province information to avoid redundancy
-			caseResponse := latestCase.TransformToResponseWithoutProvince()
-			result[i].LatestCase = &caseResponse
-		}
+	if provinceCase == nil {
+		return nil, nil
 	}
-
-	return result, nil
+	cases := []models.ProvinceCaseWithDate{*provinceCase}
+	s.attachProvinceTests(ctx, cases)
+	return &cases[0], nil
 }
 
-func (s *covidService) GetProvinceCases(provinceID string) ([]models.ProvinceCaseWithDate, error) {
-	cases, err := s.provinceCaseRepo.GetByProvinceID(provinceID)
+func (s *covidService) GetNationalCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	cases, err := s.nationalCaseRepo.GetAggregated(ctx, q)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get province cases: %w", err)
+		return nil, fmt.Errorf("failed to get aggregated national cases: %w", err)
 	}
 	return cases, nil
 }
 
-func (s *covidService) GetProvinceCasesByDateRange(provinceID, startDate, endDate string) ([]models.ProvinceCaseWithDate, error) {
-	start, err := time.Parse("2006-01-02", startDate)
+func (s *covidService) GetNationalTotals(ctx context.Context, dates DateRange) (models.PeriodTotals, error) {
+	totals, err := s.nationalCaseRepo.GetTotals(ctx, dates.Start, dates.End)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
+		return models.PeriodTotals{}, fmt.Errorf("failed to get national case totals: %w", err)
 	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
-	}
-
-	cases, err := s.provinceCaseRepo.GetByProvinceIDAndDateRange(provinceID, start, end)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get province cases by date range: %w", err)
-	}
-	return cases, nil
+	return totals, nil
 }
 
-func (s *covidService) GetAllProvinceCases() ([]models.ProvinceCaseWithDate, error) {
-	cases, err := s.provinceCaseRepo.GetAll()
+func (s *covidService) GetProvinceCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	cases, err := s.provinceCaseRepo.GetAggregated(ctx, q)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all province cases: %w", err)
+		return nil, fmt.Errorf("failed to get aggregated province cases: %w", err)
 	}
 	return cases, nil
 }
 
-func (s *covidService) GetAllProvinceCasesSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	cases, err := s.provinceCaseRepo.GetAllSorted(sortParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get sorted province cases: %w", err)
-	}
-	return cases, nil
+// -- moving averages -------------------------------------------------
+//
+// Averages are computed from the days with data in the window, which is
+// loaded separately so that cases at the edge of a page or date range still
+// average over their preceding days. The cases are copied since they may be
+// shared through the cache.
+
+// dailyCounts are the new cases of a day
+type dailyCounts struct {
+	positive, recovered, deceased int64
 }
 
-func (s *covidService) GetAllProvinceCasesByDateRange(startDate, endDate string) ([]models.ProvinceCaseWithDate, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
+func (s *covidService) AddNationalMovingAverages(ctx context.Context, cases []models.NationalCase, days int) ([]models.NationalCase, error) {
+	if len(cases) == 0 {
+		return cases, nil
 	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
+	start, end := cases[0].Date, cases[0].Date
+	for _, c := range cases {
+		start, end = minDate(start, c.Date), maxDate(end, c.Date)
 	}
-
-	cases, err := s.provinceCaseRepo.GetByDateRange(start, end)
+	window, _, err := s.nationalCaseRepo.Find(ctx, repository.NationalCaseQuery{StartDate: start.AddDate(0, 0, -(days - 1)), EndDate: end})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all province cases by date range: %w", err)
+		return nil, fmt.Errorf("failed to get national cases for moving averages: %w", err)
 	}
-	return cases, nil
-}
-
-func (s *covidService) GetProvinceCasesPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	cases, total, err := s.provinceCaseRepo.GetByProvinceIDPaginated(provinceID, limit, offset)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get province cases paginated: %w", err)
+	byKey := make(map[string]dailyCounts, len(window))
+	for _, c := range window {
+		byKey[testKey("", c.Date)] = dailyCounts{c.Positive, c.Recovered, c.Deceased}
 	}
-	return cases, total, nil
+	smoothed := make([]models.NationalCase, len(cases))
+	for i, c := range cases {
+		c.MovingAverage = movingAverage(byKey, "", c.Date, days)
+		smoothed[i] = c
+	}
+	return smoothed, nil
 }
 
-func (s *covidService) GetProvinceCasesByDateRangePaginated(provinceID, startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid start date format: %w", err)
+func (s *covidService) AddProvinceMovingAverages(ctx context.Context, cases []models.ProvinceCaseWithDate, days int) ([]models.ProvinceCaseWithDate, error) {
+	if len(cases) == 0 {
+		return cases, nil
 	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid end date format: %w", err)
+	provinceID := cases[0].ProvinceID
+	start, end := cases[0].Date, cases[0].Date
+	for _, c := range cases {
+		start, end = minDate(start, c.Date), maxDate(end, c.Date)
+		if c.ProvinceID != provinceID {
+			provinceID = ""
+		}
 	}
-
-	cases, total, err := s.provinceCaseRepo.GetByProvinceIDAndDateRangePaginated(provinceID, start, end, limit, offset)
+	start = start.AddDate(0, 0, -(days - 1))
+	window, _, err := s.provinceCaseRepo.Find(ctx, repository.ProvinceCaseQuery{ProvinceID: provinceID, StartDate: start, EndDate: end})
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get province cases by date range paginated: %w", err)
+		return nil, fmt.Errorf("failed to get province cases for moving averages: %w", err)
 	}
-	return cases, total, nil
+	byKey := make(map[string]dailyCounts, len(window))
+	for _, c := range window {
+		byKey[testKey(c.ProvinceID, c.Date)] = dailyCounts{c.Positive, c.Recovered, c.Deceased}
+	}
+	smoothed := make([]models.ProvinceCaseWithDate, len(cases))
+	for i, c := range cases {
+		c.MovingAverage = movingAverage(byKey, c.ProvinceID, c.Date, days)
+		smoothed[i] = c
+	}
+	return smoothed, nil
 }
 
-func (s *covidService) GetAllProvinceCasesPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	cases, total, err := s.provinceCaseRepo.GetAllPaginated(limit, offset)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get all province cases paginated: %w", err)
+// movingAverage averages the counts of the days with data among the given
+// number of days ending on date
+func movingAverage(byKey map[string]dailyCounts, provinceID string, date time.Time, days int) *models.MovingAverage {
+	average := &models.MovingAverage{}
+	for d := 0; d < days; d++ {
+		if c, ok := byKey[testKey(provinceID, date.AddDate(0, 0, -d))]; ok {
+			average.Add(c.positive, c.recovered, c.deceased)
+		}
 	}
-	return cases, total, nil
+	return average
 }
 
-func (s *covidService) GetAllProvinceCasesByDateRangePaginated(startDate, endDate string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid start date format: %w", err)
-	}
+// -- test counts -----------------------------------------------------
+//
+// Test counts are supplementary: failing to load them is logged and the cases
+// are returned without them.
 
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid end date format: %w", err)
+func (s *covidService) attachNationalTests(ctx context.Context, cases []models.NationalCase) {
+	if s.testRepo == nil || len(cases) == 0 {
+		return
 	}
-
-	cases, total, err := s.provinceCaseRepo.GetByDateRangePaginated(start, end, limit, offset)
+	start, end := cases[0].Date, cases[0].Date
+	for _, c := range cases {
+		start, end = minDate(start, c.Date), maxDate(end, c.Date)
+	}
+	tests, err := s.testRepo.GetNationalByDateRange(ctx, start, end)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get all province cases by date range paginated: %w", err)
+		log.Printf("Error loading national tests: %v", err)
+		return
+	}
+	byDate := testsByKey(tests)
+	for i := range cases {
+		if t, ok := byDate[testKey("", cases[i].Date)]; ok {
+			cases[i].Tests = &t
+		}
 	}
-	return cases, total, nil
 }
 
-func (s *covidService) GetAllProvinceCasesPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	cases, total, err := s.provinceCaseRepo.GetAllPaginatedSorted(limit, offset, sortParams)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get sorted province cases paginated: %w", err)
+func (s *covidService) attachNationalCaseTests(ctx context.Context, c *models.NationalCase) {
+	if c == nil {
+		return
 	}
-	return cases, total, nil
+	cases := []models.NationalCase{*c}
+	s.attachNationalTests(ctx, cases)
+	c.Tests = cases[0].Tests
 }
 
-func (s *covidService) GetAllProvinceCasesByDateRangeSorted(startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
+func (s *covidService) attachProvinceTests(ctx context.Context, cases []models.ProvinceCaseWithDate) {
+	if s.testRepo == nil || len(cases) == 0 {
+		return
 	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
+	provinceID := cases[0].ProvinceID
+	start, end := cases[0].Date, cases[0].Date
+	for _, c := range cases {
+		start, end = minDate(start, c.Date), maxDate(end, c.Date)
+		if c.ProvinceID != provinceID {
+			provinceID = ""
+		}
 	}
-
-	cases, err := s.provinceCaseRepo.GetByDateRangeSorted(start, end, sortParams)
+	tests, err := s.testRepo.GetProvinceByDateRange(ctx, provinceID, start, end)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sorted province cases by date range: %w", err)
+		log.Printf("Error loading province tests: %v", err)
+		return
+	}
+	byKey := testsByKey(tests)
+	for i := range cases {
+		if t, ok := byKey[testKey(cases[i].ProvinceID, cases[i].Date)]; ok {
+			cases[i].Tests = &t
+		}
 	}
-	return cases, nil
 }
 
-func (s *covidService) GetAllProvinceCasesByDateRangePaginatedSorted(startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid start date format: %w", err)
+// attachLatestCaseTests adds testing statistics to already transformed latest
+// cases
+func (s *covidService) attachLatestCaseTests(ctx context.Context, provinces []models.ProvinceWithLatestCase) {
+	if s.testRepo == nil {
+		return
 	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid end date format: %w", err)
+	var start, end time.Time
+	for _, p := range provinces {
+		if p.LatestCase == nil {
+			continue
+		}
+		if start.IsZero() {
+			start, end = p.LatestCase.Date, p.LatestCase.Date
+		}
+		start, end = minDate(start, p.LatestCase.Date), maxDate(end, p.LatestCase.Date)
 	}
-
-	cases, total, err := s.provinceCaseRepo.GetByDateRangePaginatedSorted(start, end, limit, offset, sortParams)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get sorted province cases by date range paginated: %w", err)
+	if start.IsZero() {
+		return
 	}
-	return cases, total, nil
-}
-
-func (s *covidService) GetProvinceCasesSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	cases, err := s.provinceCaseRepo.GetByProvinceIDSorted(provinceID, sortParams)
+	tests, err := s.testRepo.GetProvinceByDateRange(ctx, "", start, end)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sorted province cases: %w", err)
+		log.Printf("Error loading province tests: %v", err)
+		return
 	}
-	return cases, nil
-}
-
-func (s *covidService) GetProvinceCasesPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	cases, total, err := s.provinceCaseRepo.GetByProvinceIDPaginatedSorted(provinceID, limit, offset, sortParams)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get sorted province cases paginated: %w", err)
+	byKey := testsByKey(tests)
+	for _, p := range provinces {
+		if p.LatestCase == nil {
+			continue
+		}
+		if t, ok := byKey[testKey(p.ID, p.LatestCase.Date)]; ok {
+			testing := t.Statistics(p.LatestCase.Daily.Positive, p.LatestCase.Cumulative.Positive)
+			p.LatestCase.Statistics.Testing = &testing
+		}
 	}
-	return cases, total, nil
 }
 
-func (s *covidService) GetProvinceCasesByDateRangeSorted(provinceID, startDate, endDate string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
-	}
-
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
-	}
-
-	cases, err := s.provinceCaseRepo.GetByProvinceIDAndDateRangeSorted(provinceID, start, end, sortParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get sorted province cases by date range: %w", err)
-	}
-	return cases, nil
+func testKey(provinceID string, date time.Time) string {
+	return provinceID + "|" + date.Format("2006-01-02")
 }
 
-func (s *covidService) GetProvinceCasesByDateRangePaginatedSorted(provinceID, startDate, endDate string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid start date format: %w", err)
+func testsByKey(tests []models.DailyTests) map[string]models.DailyTests {
+	byKey := make(map[string]models.DailyTests, len(tests))
+	for _, t := range tests {
+		byKey[testKey(t.ProvinceID, t.Date)] = t
 	}
+	return byKey
+}
 
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid end date format: %w", err)
+func minDate(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
 	}
+	return a
+}
 
-	cases, total, err := s.provinceCaseRepo.GetByProvinceIDAndDateRangePaginatedSorted(provinceID, start, end, limit, offset, sortParams)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get sorted province cases by date range paginated: %w", err)
+func maxDate(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
 	}
-	return cases, total, nil
+	return a
 }
@@ -1,36 +1,45 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockNationalCaseRepository struct {
 	mock.Mock
 }
 
-func (m *MockNationalCaseRepository) GetAll() ([]models.NationalCase, error) {
-	args := m.Called()
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+func (m *MockNationalCaseRepository) Find(ctx context.Context, q repository.NationalCaseQuery) ([]models.NationalCase, int, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
 }
 
-func (m *MockNationalCaseRepository) GetByDateRange(startDate, endDate time.Time) ([]models.NationalCase, error) {
-	args := m.Called(startDate, endDate)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+func (m *MockNationalCaseRepository) Each(ctx context.Context, q repository.NationalCaseQuery, fn func(models.NationalCase) error) error {
+	args := m.Called(q)
+	for _, c := range args.Get(0).([]models.NationalCase) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
-func (m *MockNationalCaseRepository) GetLatest() (*models.NationalCase, error) {
+func (m *MockNationalCaseRepository) GetLatest(ctx context.Context) (*models.NationalCase, error) {
 	args := m.Called()
 	return args.Get(0).(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepository) GetByDay(day int64) (*models.NationalCase, error) {
+func (m *MockNationalCaseRepository) GetByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
 	args := m.Called(day)
 	result := args.Get(0)
 	if result == nil {
@@ -39,46 +48,45 @@ func (m *MockNationalCaseRepository) GetByDay(day int64) (*models.NationalCase,
 	return result.(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepository) GetAllSorted(sortParams utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(sortParams)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-
-func (m *MockNationalCaseRepository) GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(startDate, endDate, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-
-func (m *MockNationalCaseRepository) GetAllPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
-}
-
-func (m *MockNationalCaseRepository) GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func (m *MockNationalCaseRepository) GetByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	args := m.Called(date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepository) GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(startDate, endDate, limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func (m *MockNationalCaseRepository) GetAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepository) GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func (m *MockNationalCaseRepository) GetTotals(ctx context.Context, start, end time.Time) (models.PeriodTotals, error) {
+	args := m.Called(start, end)
+	return args.Get(0).(models.PeriodTotals), args.Error(1)
 }
 
 type MockProvinceRepository struct {
 	mock.Mock
 }
 
-func (m *MockProvinceRepository) GetAll() ([]models.Province, error) {
+func (m *MockProvinceRepository) GetAll(ctx context.Context) ([]models.Province, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Province), args.Error(1)
 }
 
-func (m *MockProvinceRepository) GetByID(id string) (*models.Province, error) {
+func (m *MockProvinceRepository) GetAllWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
+}
+
+func (m *MockProvinceRepository) GetByIDsWithLatestCase(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
+}
+
+func (m *MockProvinceRepository) GetByID(ctx context.Context, id string) (*models.Province, error) {
 	args := m.Called(id)
 	result := args.Get(0)
 	if result == nil {
@@ -91,27 +99,33 @@ type MockProvinceCaseRepository struct {
 	mock.Mock
 }
 
-func (m *MockProvinceCaseRepository) GetAll() ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called()
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepository) Find(ctx context.Context, q repository.ProvinceCaseQuery) ([]models.ProvinceCaseWithDate, int, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
 }
 
-func (m *MockProvinceCaseRepository) GetByProvinceID(provinceID string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepository) Each(ctx context.Context, q repository.ProvinceCaseQuery, fn func(models.ProvinceCaseWithDate) error) error {
+	args := m.Called(q)
+	for _, c := range args.Get(0).([]models.ProvinceCaseWithDate) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
-func (m *MockProvinceCaseRepository) GetByProvinceIDAndDateRange(provinceID string, startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, startDate, endDate)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepository) GetPageAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	args := m.Called(q)
+	next, _ := args.Get(1).(*models.CaseCursor)
+	return args.Get(0).([]models.ProvinceCaseWithDate), next, args.Error(2)
 }
 
-func (m *MockProvinceCaseRepository) GetByDateRange(startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(startDate, endDate)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepository) GetAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
 }
 
-func (m *MockProvinceCaseRepository) GetLatestByProvinceID(provinceID string) (*models.ProvinceCaseWithDate, error) {
+func (m *MockProvinceCaseRepository) GetLatestByProvinceID(ctx context.Context, provinceID string) (*models.ProvinceCaseWithDate, error) {
 	args := m.Called(provinceID)
 	result := args.Get(0)
 	if result == nil {
@@ -120,67 +134,36 @@ func (m *MockProvinceCaseRepository) GetLatestByProvinceID(provinceID string) (*
 	return result.(*models.ProvinceCaseWithDate), args.Error(1)
 }
 
-// Paginated methods
-func (m *MockProvinceCaseRepository) GetAllPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepository) GetByProvinceIDPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepository) GetByProvinceIDAndDateRangePaginated(provinceID string, startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, startDate, endDate, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepository) GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(startDate, endDate, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-// Sorted methods
-func (m *MockProvinceCaseRepository) GetAllSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-func (m *MockProvinceCaseRepository) GetByProvinceIDSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-func (m *MockProvinceCaseRepository) GetByProvinceIDAndDateRangeSorted(provinceID string, startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, startDate, endDate, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-func (m *MockProvinceCaseRepository) GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(startDate, endDate, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepository) GetByProvinceIDAndDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	args := m.Called(provinceID, date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.ProvinceCaseWithDate), args.Error(1)
 }
 
-// Paginated sorted methods
-func (m *MockProvinceCaseRepository) GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+type MockTestRepository struct {
+	mock.Mock
 }
 
-func (m *MockProvinceCaseRepository) GetByProvinceIDPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func (m *MockTestRepository) GetNationalByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.DailyTests, error) {
+	args := m.Called(startDate, endDate)
+	return args.Get(0).([]models.DailyTests), args.Error(1)
 }
 
-func (m *MockProvinceCaseRepository) GetByProvinceIDAndDateRangePaginatedSorted(provinceID string, startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func (m *MockTestRepository) GetProvinceByDateRange(ctx context.Context, provinceID string, startDate, endDate time.Time) ([]models.DailyTests, error) {
+	args := m.Called(provinceID, startDate, endDate)
+	return args.Get(0).([]models.DailyTests), args.Error(1)
 }
 
-func (m *MockProvinceCaseRepository) GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+// testDateRange parses a date range for expectations
+func testDateRange(start, end string) DateRange {
+	dates, err := ParseDateRange(start, end)
+	if err != nil {
+		panic(err)
+	}
+	return dates
 }
 
 func setupMockService() (*MockNationalCaseRepository, *MockProvinceRepository, *MockProvinceCaseRepository, CovidService) {
@@ -193,72 +176,170 @@ func setupMockService() (*MockNationalCaseRepository, *MockProvinceRepository, *
 	return mockNationalRepo, mockProvinceRepo, mockProvinceCaseRepo, service
 }
 
-func TestCovidService_GetNationalCases(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-
-	expectedCases := []models.NationalCase{
-		{ID: 1, Positive: 100, Recovered: 80, Deceased: 5},
-		{ID: 2, Positive: 150, Recovered: 120, Deceased: 8},
+func TestCovidService_ListNationalCases(t *testing.T) {
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	positiveDesc := utils.SortParams{Field: "positive", Order: "desc"}
+
+	tests := []struct {
+		name  string
+		opts  QueryOptions
+		query repository.NationalCaseQuery
+	}{
+		{"defaults to date ascending", QueryOptions{}, repository.NationalCaseQuery{Sort: byDateAsc}},
+		{"date range", QueryOptions{Range: testDateRange("2020-03-01", "2020-03-31")},
+			repository.NationalCaseQuery{StartDate: start, EndDate: end, Sort: byDateAsc}},
+		{"sorted page", QueryOptions{Sort: positiveDesc, Page: Page{Limit: 10, Offset: 20}},
+			repository.NationalCaseQuery{Sort: positiveDesc, Limit: 10, Offset: 20}},
+		{"province options are ignored", QueryOptions{ProvinceID: "72", HasRt: true}, repository.NationalCaseQuery{Sort: byDateAsc}},
+		{"as of", QueryOptions{AsOf: start}, repository.NationalCaseQuery{Sort: byDateAsc, AsOf: start}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockNationalRepo, _, _, service := setupMockService()
+			expected := []models.NationalCase{{ID: 1, Positive: 100}}
+			mockNationalRepo.On("Find", tt.query).Return(expected, 42, nil)
+
+			cases, total, err := service.ListNationalCases(context.Background(), tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, cases)
+			assert.Equal(t, 42, total)
+			mockNationalRepo.AssertExpectations(t)
+		})
 	}
+}
 
-	mockNationalRepo.On("GetAll").Return(expectedCases, nil)
+func TestParseDateRange(t *testing.T) {
+	dates, err := ParseDateRange("2020-03-01", "2020-03-31")
+	require.NoError(t, err)
+	assert.Equal(t, DateRange{
+		Start: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+	}, dates)
+	assert.True(t, dates.IsSet())
+	assert.Equal(t, "2020-03-01:2020-03-31", dates.String())
 
-	cases, err := service.GetNationalCases()
+	dates, err = ParseDateRange("2020-03-01", "")
+	require.NoError(t, err)
+	assert.False(t, dates.IsSet())
+	assert.Empty(t, dates.String())
 
-	assert.NoError(t, err)
-	assert.Equal(t, expectedCases, cases)
-	mockNationalRepo.AssertExpectations(t)
+	_, err = ParseDateRange("invalid-date", "2020-03-31")
+	assert.ErrorIs(t, err, ErrInvalidDateFormat)
+	assert.ErrorContains(t, err, "start date")
+
+	_, err = ParseDateRange("2020-03-01", "2020-31-03")
+	assert.ErrorIs(t, err, ErrInvalidDateFormat)
+	assert.ErrorContains(t, err, "end date")
 }
 
-func TestCovidService_GetNationalCases_Error(t *testing.T) {
+func TestCovidService_ListNationalCases_Error(t *testing.T) {
 	mockNationalRepo, _, _, service := setupMockService()
+	mockNationalRepo.On("Find", repository.NationalCaseQuery{Sort: byDateAsc}).Return([]models.NationalCase{}, 0, errors.New("database error"))
 
-	mockNationalRepo.On("GetAll").Return([]models.NationalCase{}, errors.New("database error"))
-
-	cases, err := service.GetNationalCases()
+	cases, total, err := service.ListNationalCases(context.Background(), QueryOptions{})
 
-	assert.Error(t, err)
+	assert.ErrorContains(t, err, "failed to list national cases")
 	assert.Nil(t, cases)
-	assert.Contains(t, err.Error(), "failed to get national cases")
-	mockNationalRepo.AssertExpectations(t)
+	assert.Zero(t, total)
 }
 
-func TestCovidService_GetNationalCasesByDateRange(t *testing.T) {
+func TestCovidService_EachNationalCase(t *testing.T) {
 	mockNationalRepo, _, _, service := setupMockService()
-
-	startDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expectedCases := []models.NationalCase{
-		{ID: 1, Positive: 100, Date: startDate},
+	rows := make([]models.NationalCase, streamChunkSize*2+1)
+	for i := range rows {
+		rows[i].Day = int64(i + 1)
 	}
+	// The page is ignored: every matching case is streamed
+	mockNationalRepo.On("Each", repository.NationalCaseQuery{Sort: byDateAsc}).Return(rows, nil)
+
+	var sizes []int
+	var streamed []models.NationalCase
+	err := service.EachNationalCase(context.Background(), QueryOptions{Page: Page{Limit: 10}}, func(chunk []models.NationalCase) error {
+		sizes = append(sizes, len(chunk))
+		streamed = append(streamed, chunk...)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{streamChunkSize, streamChunkSize, 1}, sizes)
+	assert.Equal(t, rows, streamed)
+	mockNationalRepo.AssertExpectations(t)
+}
 
-	mockNationalRepo.On("GetByDateRange", startDate, endDate).Return(expectedCases, nil)
+func TestCovidService_EachNationalCase_Error(t *testing.T) {
+	mockNationalRepo, _, _, service := setupMockService()
+	mockNationalRepo.On("Each", repository.NationalCaseQuery{Sort: byDateAsc}).Return([]models.NationalCase{{ID: 1}}, errors.New("database error"))
 
-	cases, err := service.GetNationalCasesByDateRange("2020-03-01", "2020-03-31")
+	called := false
+	err := service.EachNationalCase(context.Background(), QueryOptions{}, func([]models.NationalCase) error {
+		called = true
+		return nil
+	})
 
-	assert.NoError(t, err)
-	assert.Equal(t, expectedCases, cases)
-	mockNationalRepo.AssertExpectations(t)
+	assert.ErrorContains(t, err, "failed to stream national cases")
+	// The chunk read before the error is not passed on
+	assert.False(t, called)
 }
 
-func TestCovidService_GetNationalCasesByDateRange_InvalidStartDate(t *testing.T) {
-	_, _, _, service := setupMockService()
+func TestCovidService_EachProvinceCase(t *testing.T) {
+	_, _, mockProvinceCaseRepo, service := setupMockService()
+	rows := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "72"}}}
+	mockProvinceCaseRepo.On("Each", repository.ProvinceCaseQuery{ProvinceID: "72", Sort: byDateDesc}).Return(rows, nil)
 
-	cases, err := service.GetNationalCasesByDateRange("invalid-date", "2020-03-31")
+	var streamed []models.ProvinceCaseWithDate
+	err := service.EachProvinceCase(context.Background(), QueryOptions{ProvinceID: "72"}, func(chunk []models.ProvinceCaseWithDate) error {
+		streamed = append(streamed, chunk...)
+		return nil
+	})
 
-	assert.Error(t, err)
-	assert.Nil(t, cases)
-	assert.Contains(t, err.Error(), "invalid start date format")
+	require.NoError(t, err)
+	assert.Equal(t, rows, streamed)
+	mockProvinceCaseRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetNationalCasesByDateRange_InvalidEndDate(t *testing.T) {
-	_, _, _, service := setupMockService()
+func TestCovidService_ListProvinceCases(t *testing.T) {
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
+	rtDesc := utils.SortParams{Field: "rt", Order: "desc"}
+
+	tests := []struct {
+		name  string
+		opts  QueryOptions
+		query repository.ProvinceCaseQuery
+	}{
+		{"one province defaults to newest first", QueryOptions{ProvinceID: "11"},
+			repository.ProvinceCaseQuery{ProvinceID: "11", Sort: byDateDesc}},
+		{"all provinces default to date ascending", QueryOptions{}, repository.ProvinceCaseQuery{Sort: byDateAsc}},
+		{"date range page", QueryOptions{ProvinceID: "11", Range: testDateRange("2020-03-01", "2020-03-31"), Page: Page{Limit: 10}},
+			repository.ProvinceCaseQuery{ProvinceID: "11", StartDate: start, EndDate: end, Sort: byDateDesc, Limit: 10}},
+		{"rows with Rt", QueryOptions{HasRt: true, Sort: rtDesc, Page: Page{Limit: 50, Offset: 50}},
+			repository.ProvinceCaseQuery{HasRt: true, Sort: rtDesc, Limit: 50, Offset: 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, mockProvinceCaseRepo, service := setupMockService()
+			expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 7, ProvinceID: "11"}}}
+			mockProvinceCaseRepo.On("Find", tt.query).Return(expected, 120, nil)
+
+			cases, total, err := service.ListProvinceCases(context.Background(), tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, cases)
+			assert.Equal(t, 120, total)
+			mockProvinceCaseRepo.AssertExpectations(t)
+		})
+	}
+}
 
-	cases, err := service.GetNationalCasesByDateRange("2020-03-01", "invalid-date")
+func TestCovidService_ListProvinceCases_Error(t *testing.T) {
+	_, _, mockProvinceCaseRepo, service := setupMockService()
 
-	assert.Error(t, err)
-	assert.Nil(t, cases)
-	assert.Contains(t, err.Error(), "invalid end date format")
+	mockProvinceCaseRepo.On("Find", repository.ProvinceCaseQuery{HasRt: true, Sort: byDateAsc}).
+		Return([]models.ProvinceCaseWithDate(nil), 0, errors.New("db error"))
+	_, _, err := service.ListProvinceCases(context.Background(), QueryOptions{HasRt: true})
+	assert.ErrorContains(t, err, "failed to list province cases")
 }
 
 func TestCovidService_GetLatestNationalCase(t *testing.T) {
@@ -267,607 +348,337 @@ func TestCovidService_GetLatestNationalCase(t *testing.T) {
 	expectedCase := &models.NationalCase{ID: 1, Positive: 100}
 	mockNationalRepo.On("GetLatest").Return(expectedCase, nil)
 
-	nationalCase, err := service.GetLatestNationalCase()
+	nationalCase, err := service.GetLatestNationalCase(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCase, nationalCase)
 	mockNationalRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetProvinces(t *testing.T) {
-	_, mockProvinceRepo, _, service := setupMockService()
-
-	expectedProvinces := []models.Province{
-		{ID: "11", Name: "Aceh"},
-		{ID: "31", Name: "DKI Jakarta"},
+func TestCovidService_ResolveDateRange(t *testing.T) {
+	latest := &models.NationalCase{Date: time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		preset    string
+		wantStart string
+		wantEnd   string
+	}{
+		{"last7d", "2023-06-24", "2023-06-30"},
+		{"last14d", "2023-06-17", "2023-06-30"},
+		{"last30d", "2023-06-01", "2023-06-30"},
+		{"last90d", "2023-04-02", "2023-06-30"},
+		{"ytd", "2023-01-01", "2023-06-30"},
 	}
 
-	mockProvinceRepo.On("GetAll").Return(expectedProvinces, nil)
-
-	provinces, err := service.GetProvinces()
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			mockNationalRepo, _, _, service := setupMockService()
+			mockNationalRepo.On("GetLatest").Return(latest, nil)
 
-	assert.NoError(t, err)
-	assert.Equal(t, expectedProvinces, provinces)
-	mockProvinceRepo.AssertExpectations(t)
-}
+			start, end, err := service.ResolveDateRange(context.Background(), tt.preset)
 
-func TestCovidService_GetProvinceCases(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-
-	provinceID := "11"
-	expectedCases := []models.ProvinceCaseWithDate{
-		{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: provinceID, Positive: 50}},
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
 	}
-
-	mockProvinceCaseRepo.On("GetByProvinceID", provinceID).Return(expectedCases, nil)
-
-	cases, err := service.GetProvinceCases(provinceID)
-
-	assert.NoError(t, err)
-	assert.Equal(t, expectedCases, cases)
-	mockProvinceCaseRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetProvinceCasesByDateRange(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-
-	provinceID := "11"
-	startDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expectedCases := []models.ProvinceCaseWithDate{
-		{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: provinceID, Positive: 50}},
-	}
-
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRange", provinceID, startDate, endDate).Return(expectedCases, nil)
-
-	cases, err := service.GetProvinceCasesByDateRange(provinceID, "2020-03-01", "2020-03-31")
+func TestCovidService_ResolveDateRange_AllAndInvalid(t *testing.T) {
+	mockNationalRepo, _, _, service := setupMockService()
 
+	start, end, err := service.ResolveDateRange(context.Background(), "all")
 	assert.NoError(t, err)
-	assert.Equal(t, expectedCases, cases)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
-
-func TestCovidService_GetAllProvinceCases(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
+	assert.Empty(t, start)
+	assert.Empty(t, end)
 
-	expectedCases := []models.ProvinceCaseWithDate{
-		{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11", Positive: 50}},
-		{ProvinceCase: models.ProvinceCase{ID: 2, ProvinceID: "31", Positive: 100}},
-	}
+	_, _, err = service.ResolveDateRange(context.Background(), "last5d")
+	assert.ErrorIs(t, err, ErrInvalidDateRangePreset)
+	mockNationalRepo.AssertNotCalled(t, "GetLatest")
+}
 
-	mockProvinceCaseRepo.On("GetAll").Return(expectedCases, nil)
+func TestCovidService_ResolveDateRange_WithoutData(t *testing.T) {
+	mockNationalRepo, _, _, service := setupMockService()
+	mockNationalRepo.On("GetLatest").Return((*models.NationalCase)(nil), nil)
+	service.(ClockSetter).SetClock(clock.NewMock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
 
-	cases, err := service.GetAllProvinceCases()
+	start, end, err := service.ResolveDateRange(context.Background(), "last7d")
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedCases, cases)
-	mockProvinceCaseRepo.AssertExpectations(t)
+	assert.Equal(t, "2026-02-23", start)
+	assert.Equal(t, "2026-03-01", end)
 }
 
-func TestCovidService_GetAllProvinceCasesByDateRange(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
+func TestCovidService_GetProvinces(t *testing.T) {
+	_, mockProvinceRepo, _, service := setupMockService()
 
-	startDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expectedCases := []models.ProvinceCaseWithDate{
-		{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11", Positive: 50}},
+	expectedProvinces := []models.Province{
+		{ID: "11", Name: "Aceh"},
+		{ID: "31", Name: "DKI Jakarta"},
 	}
 
-	mockProvinceCaseRepo.On("GetByDateRange", startDate, endDate).Return(expectedCases, nil)
+	mockProvinceRepo.On("GetAll").Return(expectedProvinces, nil)
 
-	cases, err := service.GetAllProvinceCasesByDateRange("2020-03-01", "2020-03-31")
+	provinces, err := service.GetProvinces(context.Background())
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedCases, cases)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
-
-func TestCovidService_GetNationalCasesSorted(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	expected := []models.NationalCase{{ID: 1, Positive: 100}}
-	mockNationalRepo.On("GetAllSorted", sort).Return(expected, nil)
-	result, err := service.GetNationalCasesSorted(sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockNationalRepo.AssertExpectations(t)
-}
-
-func TestCovidService_GetNationalCasesByDateRangeSorted(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.NationalCase{{ID: 1, Positive: 100}}
-	mockNationalRepo.On("GetByDateRangeSorted", start, end, sort).Return(expected, nil)
-	result, err := service.GetNationalCasesByDateRangeSorted("2020-03-01", "2020-03-31", sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockNationalRepo.AssertExpectations(t)
+	assert.Equal(t, expectedProvinces, provinces)
+	mockProvinceRepo.AssertExpectations(t)
 }
 
 func TestCovidService_GetNationalCaseByDay(t *testing.T) {
 	mockNationalRepo, _, _, service := setupMockService()
 	expected := &models.NationalCase{ID: 1, Positive: 100}
 	mockNationalRepo.On("GetByDay", int64(1)).Return(expected, nil)
-	result, err := service.GetNationalCaseByDay(1)
+	result, err := service.GetNationalCaseByDay(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 	mockNationalRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetProvinceByID(t *testing.T) {
-	_, mockProvinceRepo, _, service := setupMockService()
-	expected := &models.Province{ID: "11", Name: "Aceh"}
-	mockProvinceRepo.On("GetByID", "11").Return(expected, nil)
-	result, err := service.GetProvinceByID("11")
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockProvinceRepo.AssertExpectations(t)
-}
-
-func TestCovidService_GetNationalCasesPaginated(t *testing.T) {
+func TestCovidService_GetNationalCaseByDate(t *testing.T) {
 	mockNationalRepo, _, _, service := setupMockService()
-	expected := []models.NationalCase{{ID: 1, Positive: 100}}
-	mockNationalRepo.On("GetAllPaginated", 10, 0).Return(expected, 1, nil)
-	result, total, err := service.GetNationalCasesPaginated(10, 0)
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	expected := &models.NationalCase{ID: 1, Date: date, Positive: 100}
+	mockNationalRepo.On("GetByDate", date).Return(expected, nil)
+	result, err := service.GetNationalCaseByDate(context.Background(), date)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
 	mockNationalRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetNationalCasesPaginatedSorted(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	expected := []models.NationalCase{{ID: 1, Positive: 100}}
-	mockNationalRepo.On("GetAllPaginatedSorted", 10, 0, sort).Return(expected, 1, nil)
-	result, total, err := service.GetNationalCasesPaginatedSorted(10, 0, sort)
+func TestCovidService_GetProvinceCaseByDate(t *testing.T) {
+	_, _, mockProvinceCaseRepo, service := setupMockService()
+	date := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	expected := &models.ProvinceCaseWithDate{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "72"}, Date: date}
+	mockProvinceCaseRepo.On("GetByProvinceIDAndDate", "72", date).Return(expected, nil)
+	mockProvinceCaseRepo.On("GetByProvinceIDAndDate", "72", date.AddDate(0, 0, 1)).Return(nil, nil)
+
+	result, err := service.GetProvinceCaseByDate(context.Background(), "72", date)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockNationalRepo.AssertExpectations(t)
-}
 
-func TestCovidService_GetNationalCasesByDateRangePaginated(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.NationalCase{{ID: 1, Positive: 100}}
-	mockNationalRepo.On("GetByDateRangePaginated", start, end, 10, 0).Return(expected, 1, nil)
-	result, total, err := service.GetNationalCasesByDateRangePaginated("2020-03-01", "2020-03-31", 10, 0)
+	result, err = service.GetProvinceCaseByDate(context.Background(), "72", date.AddDate(0, 0, 1))
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockNationalRepo.AssertExpectations(t)
+	assert.Nil(t, result)
+	mockProvinceCaseRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetNationalCasesByDateRangePaginatedSorted(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.NationalCase{{ID: 1, Positive: 100}}
-	mockNationalRepo.On("GetByDateRangePaginatedSorted", start, end, 10, 0, sort).Return(expected, 1, nil)
-	result, total, err := service.GetNationalCasesByDateRangePaginatedSorted("2020-03-01", "2020-03-31", 10, 0, sort)
+func TestCovidService_GetProvinceByID(t *testing.T) {
+	_, mockProvinceRepo, _, service := setupMockService()
+	expected := &models.Province{ID: "11", Name: "Aceh"}
+	mockProvinceRepo.On("GetByID", "11").Return(expected, nil)
+	result, err := service.GetProvinceByID(context.Background(), "11")
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockNationalRepo.AssertExpectations(t)
+	mockProvinceRepo.AssertExpectations(t)
 }
 
 func TestCovidService_GetProvincesWithLatestCase(t *testing.T) {
 	_, mockProvinceRepo, mockProvinceCaseRepo, service := setupMockService()
-	provinces := []models.Province{{ID: "11", Name: "Aceh"}}
-	latestCase := &models.ProvinceCaseWithDate{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11", Positive: 50}}
-	mockProvinceRepo.On("GetAll").Return(provinces, nil)
-	mockProvinceCaseRepo.On("GetLatestByProvinceID", "11").Return(latestCase, nil)
-	result, err := service.GetProvincesWithLatestCase()
+	latestCase := (&models.ProvinceCaseWithDate{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11", Positive: 50}}).TransformToResponseWithoutProvince()
+	provinces := []models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "11", Name: "Aceh"}, LatestCase: &latestCase},
+		{Province: models.Province{ID: "99", Name: "Papua Selatan"}},
+	}
+	mockProvinceRepo.On("GetAllWithLatestCase").Return(provinces, nil)
+	result, err := service.GetProvincesWithLatestCase(context.Background())
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
+	assert.Equal(t, provinces, result)
 	mockProvinceRepo.AssertExpectations(t)
-	mockProvinceCaseRepo.AssertExpectations(t)
+	// One query for all provinces instead of one per province
+	mockProvinceCaseRepo.AssertNotCalled(t, "GetLatestByProvinceID", mock.Anything)
 }
 
-func TestCovidService_GetAllProvinceCasesSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetAllSorted", sort).Return(expected, nil)
-	result, err := service.GetAllProvinceCasesSorted(sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+// Error tests for better coverage
 
-func TestCovidService_GetProvinceCasesPaginated(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11"}}}
-	mockProvinceCaseRepo.On("GetByProvinceIDPaginated", "11", 10, 0).Return(expected, 1, nil)
-	result, total, err := service.GetProvinceCasesPaginated("11", 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
+func TestCovidService_GetNationalCaseByDay_Error(t *testing.T) {
+	mockNationalRepo, _, _, service := setupMockService()
+	mockNationalRepo.On("GetByDay", int64(1)).Return((*models.NationalCase)(nil), errors.New("db error"))
+	_, err := service.GetNationalCaseByDay(context.Background(), 1)
+	assert.Error(t, err)
 }
 
-func TestCovidService_GetProvinceCasesByDateRangePaginated(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRangePaginated", "11", start, end, 10, 0).Return(expected, 1, nil)
-	result, total, err := service.GetProvinceCasesByDateRangePaginated("11", "2020-03-01", "2020-03-31", 10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
+func TestCovidService_GetProvinceByID_Error(t *testing.T) {
+	_, mockProvinceRepo, _, service := setupMockService()
+	mockProvinceRepo.On("GetByID", "11").Return((*models.Province)(nil), errors.New("db error"))
+	_, err := service.GetProvinceByID(context.Background(), "11")
+	assert.Error(t, err)
 }
 
-func TestCovidService_GetAllProvinceCasesPaginated(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetAllPaginated", 10, 0).Return(expected, 1, nil)
-	result, total, err := service.GetAllProvinceCasesPaginated(10, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+func TestCovidService_GetProvincesWithLatestCaseByIDs(t *testing.T) {
+	_, mockProvinceRepo, _, service := setupMockService()
+	provinces := []models.ProvinceWithLatestCase{{Province: models.Province{ID: "72", Name: "Sulawesi Tengah"}}}
+	mockProvinceRepo.On("GetByIDsWithLatestCase", []string{"72", "31"}).Return(provinces, nil)
+	mockProvinceRepo.On("GetByIDsWithLatestCase", []string{"11"}).Return([]models.ProvinceWithLatestCase(nil), errors.New("db error"))
 
-func TestCovidService_GetAllProvinceCasesByDateRangePaginated(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetByDateRangePaginated", start, end, 10, 0).Return(expected, 1, nil)
-	result, total, err := service.GetAllProvinceCasesByDateRangePaginated("2020-03-01", "2020-03-31", 10, 0)
+	result, err := service.GetProvincesWithLatestCaseByIDs(context.Background(), []string{"72", "31"})
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+	assert.Equal(t, provinces, result)
 
-func TestCovidService_GetAllProvinceCasesPaginatedSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetAllPaginatedSorted", 10, 0, sort).Return(expected, 1, nil)
-	result, total, err := service.GetAllProvinceCasesPaginatedSorted(10, 0, sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
+	_, err = service.GetProvincesWithLatestCaseByIDs(context.Background(), []string{"11"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get provinces with latest case by IDs")
 }
 
-func TestCovidService_GetAllProvinceCasesByDateRangeSorted(t *testing.T) {
+func TestCovidService_GetProvinceCasesAfterCursor(t *testing.T) {
 	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetByDateRangeSorted", start, end, sort).Return(expected, nil)
-	result, err := service.GetAllProvinceCasesByDateRangeSorted("2020-03-01", "2020-03-31", sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+	q := repository.ProvinceCaseCursorQuery{ProvinceID: "72", Limit: 50}
+	next := &models.CaseCursor{Date: time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC), ID: 7}
+	cases := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 7, ProvinceID: "72"}}}
+	mockProvinceCaseRepo.On("GetPageAfterCursor", q).Return(cases, next, nil)
 
-func TestCovidService_GetAllProvinceCasesByDateRangePaginatedSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetByDateRangePaginatedSorted", start, end, 10, 0, sort).Return(expected, 1, nil)
-	result, total, err := service.GetAllProvinceCasesByDateRangePaginatedSorted("2020-03-01", "2020-03-31", 10, 0, sort)
+	result, cursor, err := service.GetProvinceCasesAfterCursor(context.Background(), q)
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+	assert.Equal(t, cases, result)
+	assert.Equal(t, next, cursor)
 
-func TestCovidService_GetProvinceCasesSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11"}}}
-	mockProvinceCaseRepo.On("GetByProvinceIDSorted", "11", sort).Return(expected, nil)
-	result, err := service.GetProvinceCasesSorted("11", sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockProvinceCaseRepo.AssertExpectations(t)
+	failing := repository.ProvinceCaseCursorQuery{Limit: 10}
+	mockProvinceCaseRepo.On("GetPageAfterCursor", failing).Return([]models.ProvinceCaseWithDate(nil), nil, errors.New("db error"))
+	_, _, err = service.GetProvinceCasesAfterCursor(context.Background(), failing)
+	assert.Error(t, err)
 }
 
-func TestCovidService_GetProvinceCasesPaginatedSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1, ProvinceID: "11"}}}
-	mockProvinceCaseRepo.On("GetByProvinceIDPaginatedSorted", "11", 10, 0, sort).Return(expected, 1, nil)
-	result, total, err := service.GetProvinceCasesPaginatedSorted("11", 10, 0, sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+func TestCovidService_GetCasesAggregated(t *testing.T) {
+	mockNationalRepo, _, mockProvinceCaseRepo, service := setupMockService()
+	q := repository.CaseAggregateQuery{Interval: models.IntervalWeekly}
+	weeks := []models.AggregatedCase{{Period: "2021-W05", Days: 7, Positive: 700}}
+	mockNationalRepo.On("GetAggregated", q).Return(weeks, nil)
+	provinceQuery := repository.CaseAggregateQuery{Interval: models.IntervalMonthly, ProvinceID: "72"}
+	mockProvinceCaseRepo.On("GetAggregated", provinceQuery).Return([]models.AggregatedCase(nil), errors.New("db error"))
 
-func TestCovidService_GetProvinceCasesByDateRangeSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRangeSorted", "11", start, end, sort).Return(expected, nil)
-	result, err := service.GetProvinceCasesByDateRangeSorted("11", "2020-03-01", "2020-03-31", sort)
+	result, err := service.GetNationalCasesAggregated(context.Background(), q)
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockProvinceCaseRepo.AssertExpectations(t)
-}
+	assert.Equal(t, weeks, result)
 
-func TestCovidService_GetProvinceCasesByDateRangePaginatedSorted(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "day", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	expected := []models.ProvinceCaseWithDate{{ProvinceCase: models.ProvinceCase{ID: 1}}}
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRangePaginatedSorted", "11", start, end, 10, 0, sort).Return(expected, 1, nil)
-	result, total, err := service.GetProvinceCasesByDateRangePaginatedSorted("11", "2020-03-01", "2020-03-31", 10, 0, sort)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	assert.Equal(t, 1, total)
-	mockProvinceCaseRepo.AssertExpectations(t)
+	_, err = service.GetProvinceCasesAggregated(context.Background(), provinceQuery)
+	assert.ErrorContains(t, err, "failed to get aggregated province cases")
 }
 
-// Error tests for better coverage
-
-func TestCovidService_GetNationalCasesSorted_Error(t *testing.T) {
+func TestCovidService_GetNationalTotals(t *testing.T) {
 	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	mockNationalRepo.On("GetAllSorted", sort).Return([]models.NationalCase{}, errors.New("db error"))
-	_, err := service.GetNationalCasesSorted(sort)
-	assert.Error(t, err)
-}
+	dates := testDateRange("2021-06-01", "2021-06-30")
+	mockNationalRepo.On("GetTotals", dates.Start, dates.End).Return(models.PeriodTotals{Days: 30, Positive: 3000}, nil).Once()
+	mockNationalRepo.On("GetTotals", dates.Start, dates.End).Return(models.PeriodTotals{}, errors.New("db error")).Once()
 
-func TestCovidService_GetNationalCasesByDateRangeSorted_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	_, err := service.GetNationalCasesByDateRangeSorted("invalid", "2020-03-31", sort)
-	assert.Error(t, err)
-	_, err = service.GetNationalCasesByDateRangeSorted("2020-03-01", "invalid", sort)
-	assert.Error(t, err)
-}
+	totals, err := service.GetNationalTotals(context.Background(), dates)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3000), totals.Positive)
 
-func TestCovidService_GetNationalCasesByDateRangeSorted_Error(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockNationalRepo.On("GetByDateRangeSorted", start, end, sort).Return([]models.NationalCase{}, errors.New("db error"))
-	_, err := service.GetNationalCasesByDateRangeSorted("2020-03-01", "2020-03-31", sort)
-	assert.Error(t, err)
+	_, err = service.GetNationalTotals(context.Background(), dates)
+	assert.ErrorContains(t, err, "failed to get national case totals")
 }
 
-func TestCovidService_GetNationalCaseByDay_Error(t *testing.T) {
+func TestCovidService_AddNationalMovingAverages(t *testing.T) {
 	mockNationalRepo, _, _, service := setupMockService()
-	mockNationalRepo.On("GetByDay", int64(1)).Return((*models.NationalCase)(nil), errors.New("db error"))
-	_, err := service.GetNationalCaseByDay(1)
-	assert.Error(t, err)
-}
+	day := func(d int) time.Time { return time.Date(2021, 2, d, 0, 0, 0, 0, time.UTC) }
+	// Feb 2 has no data and is left out of the average
+	mockNationalRepo.On("Find", repository.NationalCaseQuery{StartDate: day(1), EndDate: day(4)}).Return([]models.NationalCase{
+		{Date: day(1), Positive: 10, Recovered: 4},
+		{Date: day(3), Positive: 20, Recovered: 8, Deceased: 3},
+		{Date: day(4), Positive: 60, Recovered: 6},
+	}, 0, nil)
+	cases := []models.NationalCase{{Date: day(4)}, {Date: day(3)}}
 
-func TestCovidService_GetProvinceByID_Error(t *testing.T) {
-	_, mockProvinceRepo, _, service := setupMockService()
-	mockProvinceRepo.On("GetByID", "11").Return((*models.Province)(nil), errors.New("db error"))
-	_, err := service.GetProvinceByID("11")
-	assert.Error(t, err)
-}
+	smoothed, err := service.AddNationalMovingAverages(context.Background(), cases, 3)
 
-func TestCovidService_GetNationalCasesPaginated_Error(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	mockNationalRepo.On("GetAllPaginated", 10, 0).Return([]models.NationalCase{}, 0, errors.New("db error"))
-	_, _, err := service.GetNationalCasesPaginated(10, 0)
-	assert.Error(t, err)
+	require.NoError(t, err)
+	assert.Equal(t, &models.MovingAverage{Days: 2, Positive: 40, Recovered: 7, Deceased: 1.5}, smoothed[0].MovingAverage)
+	assert.Equal(t, &models.MovingAverage{Days: 2, Positive: 15, Recovered: 6, Deceased: 1.5}, smoothed[1].MovingAverage)
+	assert.Nil(t, cases[0].MovingAverage, "the given cases are not modified")
 }
 
-func TestCovidService_GetNationalCasesPaginatedSorted_Error(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	mockNationalRepo.On("GetAllPaginatedSorted", 10, 0, sort).Return([]models.NationalCase{}, 0, errors.New("db error"))
-	_, _, err := service.GetNationalCasesPaginatedSorted(10, 0, sort)
-	assert.Error(t, err)
-}
+func TestCovidService_AddProvinceMovingAverages(t *testing.T) {
+	_, _, mockProvinceCaseRepo, service := setupMockService()
+	day := func(d int) time.Time { return time.Date(2021, 2, d, 0, 0, 0, 0, time.UTC) }
+	mockProvinceCaseRepo.On("Find", repository.ProvinceCaseQuery{StartDate: day(1), EndDate: day(2)}).Return([]models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "72", Positive: 4}, Date: day(1)},
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "31", Positive: 100}, Date: day(1)},
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "72", Positive: 8}, Date: day(2)},
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "31", Positive: 50}, Date: day(2)},
+	}, 0, nil)
+	cases := []models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "72"}, Date: day(2)},
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "31"}, Date: day(2)},
+	}
 
-func TestCovidService_GetNationalCasesByDateRangePaginated_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	_, _, err := service.GetNationalCasesByDateRangePaginated("invalid", "2020-03-31", 10, 0)
-	assert.Error(t, err)
-	_, _, err = service.GetNationalCasesByDateRangePaginated("2020-03-01", "invalid", 10, 0)
-	assert.Error(t, err)
-}
+	smoothed, err := service.AddProvinceMovingAverages(context.Background(), cases, 2)
 
-func TestCovidService_GetNationalCasesByDateRangePaginated_Error(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockNationalRepo.On("GetByDateRangePaginated", start, end, 10, 0).Return([]models.NationalCase{}, 0, errors.New("db error"))
-	_, _, err := service.GetNationalCasesByDateRangePaginated("2020-03-01", "2020-03-31", 10, 0)
-	assert.Error(t, err)
-}
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, smoothed[0].MovingAverage.Positive)
+	assert.Equal(t, 75.0, smoothed[1].MovingAverage.Positive)
 
-func TestCovidService_GetNationalCasesByDateRangePaginatedSorted_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	_, _, err := service.GetNationalCasesByDateRangePaginatedSorted("invalid", "2020-03-31", 10, 0, sort)
-	assert.Error(t, err)
-}
-
-func TestCovidService_GetNationalCasesByDateRangePaginatedSorted_Error(t *testing.T) {
-	mockNationalRepo, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockNationalRepo.On("GetByDateRangePaginatedSorted", start, end, 10, 0, sort).Return([]models.NationalCase{}, 0, errors.New("db error"))
-	_, _, err := service.GetNationalCasesByDateRangePaginatedSorted("2020-03-01", "2020-03-31", 10, 0, sort)
-	assert.Error(t, err)
+	mockProvinceCaseRepo.On("Find", repository.ProvinceCaseQuery{ProvinceID: "72", StartDate: day(1), EndDate: day(2)}).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
+	_, err = service.AddProvinceMovingAverages(context.Background(), cases[:1], 2)
+	assert.ErrorContains(t, err, "failed to get province cases for moving averages")
 }
 
 func TestCovidService_GetProvincesWithLatestCase_Error(t *testing.T) {
 	_, mockProvinceRepo, _, service := setupMockService()
-	mockProvinceRepo.On("GetAll").Return([]models.Province{}, errors.New("db error"))
-	_, err := service.GetProvincesWithLatestCase()
-	assert.Error(t, err)
-}
-
-func TestCovidService_GetProvincesWithLatestCase_CaseError(t *testing.T) {
-	_, mockProvinceRepo, mockProvinceCaseRepo, service := setupMockService()
-	provinces := []models.Province{{ID: "11", Name: "Aceh"}}
-	mockProvinceRepo.On("GetAll").Return(provinces, nil)
-	mockProvinceCaseRepo.On("GetLatestByProvinceID", "11").Return((*models.ProvinceCaseWithDate)(nil), errors.New("db error"))
-	// Error from GetLatestByProvinceID is ignored (continue), result is still returned
-	result, err := service.GetProvincesWithLatestCase()
-	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Nil(t, result[0].LatestCase)
-}
-
-func TestCovidService_GetAllProvinceCasesSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	mockProvinceCaseRepo.On("GetAllSorted", sort).Return([]models.ProvinceCaseWithDate{}, errors.New("db error"))
-	_, err := service.GetAllProvinceCasesSorted(sort)
-	assert.Error(t, err)
-}
-
-func TestCovidService_GetProvinceCasesPaginated_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	mockProvinceCaseRepo.On("GetByProvinceIDPaginated", "11", 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetProvinceCasesPaginated("11", 10, 0)
-	assert.Error(t, err)
-}
-
-func TestCovidService_GetProvinceCasesByDateRangePaginated_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	_, _, err := service.GetProvinceCasesByDateRangePaginated("11", "invalid", "2020-03-31", 10, 0)
-	assert.Error(t, err)
-}
-
-func TestCovidService_GetProvinceCasesByDateRangePaginated_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRangePaginated", "11", start, end, 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetProvinceCasesByDateRangePaginated("11", "2020-03-01", "2020-03-31", 10, 0)
+	mockProvinceRepo.On("GetAllWithLatestCase").Return([]models.ProvinceWithLatestCase{}, errors.New("db error"))
+	_, err := service.GetProvincesWithLatestCase(context.Background())
 	assert.Error(t, err)
 }
 
-func TestCovidService_GetAllProvinceCasesPaginated_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	mockProvinceCaseRepo.On("GetAllPaginated", 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetAllProvinceCasesPaginated(10, 0)
-	assert.Error(t, err)
-}
+func TestCovidService_AttachesNationalTests(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	testRepo := new(MockTestRepository)
+	svc := NewCovidServiceWithTests(nationalRepo, new(MockProvinceRepository), new(MockProvinceCaseRepository), testRepo)
 
-func TestCovidService_GetAllProvinceCasesByDateRangePaginated_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	_, _, err := service.GetAllProvinceCasesByDateRangePaginated("invalid", "2020-03-31", 10, 0)
-	assert.Error(t, err)
-}
+	day1 := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	nationalRepo.On("Find", repository.NationalCaseQuery{Sort: byDateAsc}).Return([]models.NationalCase{{Day: 2, Date: day2}, {Day: 1, Date: day1}}, 0, nil)
+	testRepo.On("GetNationalByDateRange", day1, day2).Return([]models.DailyTests{{Date: day1, PCR: 100}}, nil)
 
-func TestCovidService_GetAllProvinceCasesByDateRangePaginated_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetByDateRangePaginated", start, end, 10, 0).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetAllProvinceCasesByDateRangePaginated("2020-03-01", "2020-03-31", 10, 0)
-	assert.Error(t, err)
-}
+	cases, _, err := svc.ListNationalCases(context.Background(), QueryOptions{})
 
-func TestCovidService_GetAllProvinceCasesPaginatedSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	mockProvinceCaseRepo.On("GetAllPaginatedSorted", 10, 0, sort).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetAllProvinceCasesPaginatedSorted(10, 0, sort)
-	assert.Error(t, err)
+	require.NoError(t, err)
+	assert.Nil(t, cases[0].Tests)
+	require.NotNil(t, cases[1].Tests)
+	assert.Equal(t, int64(100), cases[1].Tests.PCR)
+	testRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetAllProvinceCasesByDateRangeSorted_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	_, err := service.GetAllProvinceCasesByDateRangeSorted("invalid", "2020-03-31", sort)
-	assert.Error(t, err)
-}
+func TestCovidService_AttachesProvinceTests(t *testing.T) {
+	provinceRepo := new(MockProvinceRepository)
+	provinceCaseRepo := new(MockProvinceCaseRepository)
+	testRepo := new(MockTestRepository)
+	svc := NewCovidServiceWithTests(new(MockNationalCaseRepository), provinceRepo, provinceCaseRepo, testRepo)
 
-func TestCovidService_GetAllProvinceCasesByDateRangeSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetByDateRangeSorted", start, end, sort).Return([]models.ProvinceCaseWithDate{}, errors.New("db error"))
-	_, err := service.GetAllProvinceCasesByDateRangeSorted("2020-03-01", "2020-03-31", sort)
-	assert.Error(t, err)
-}
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	provinceCaseRepo.On("Find", repository.ProvinceCaseQuery{ProvinceID: "72", Sort: byDateDesc}).Return([]models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{ProvinceID: "72"}, Date: date},
+	}, 0, nil)
+	testRepo.On("GetProvinceByDateRange", "72", date, date).Return([]models.DailyTests{{ProvinceID: "72", Date: date, Antigen: 30}}, nil)
 
-func TestCovidService_GetAllProvinceCasesByDateRangePaginatedSorted_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	_, _, err := service.GetAllProvinceCasesByDateRangePaginatedSorted("invalid", "2020-03-31", 10, 0, sort)
-	assert.Error(t, err)
-}
+	cases, _, err := svc.ListProvinceCases(context.Background(), QueryOptions{ProvinceID: "72"})
+	require.NoError(t, err)
+	require.NotNil(t, cases[0].Tests)
+	assert.Equal(t, int64(30), cases[0].Tests.Antigen)
 
-func TestCovidService_GetAllProvinceCasesByDateRangePaginatedSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetByDateRangePaginatedSorted", start, end, 10, 0, sort).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetAllProvinceCasesByDateRangePaginatedSorted("2020-03-01", "2020-03-31", 10, 0, sort)
-	assert.Error(t, err)
-}
+	provinceRepo.On("GetAllWithLatestCase").Return([]models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "72"}, LatestCase: &models.ProvinceCaseResponse{Date: date, Daily: models.ProvinceDailyCases{Positive: 3}}},
+		{Province: models.Province{ID: "73"}},
+	}, nil)
+	testRepo.On("GetProvinceByDateRange", "", date, date).Return([]models.DailyTests{{ProvinceID: "72", Date: date, PCR: 30}}, nil)
 
-func TestCovidService_GetProvinceCasesSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	mockProvinceCaseRepo.On("GetByProvinceIDSorted", "11", sort).Return([]models.ProvinceCaseWithDate{}, errors.New("db error"))
-	_, err := service.GetProvinceCasesSorted("11", sort)
-	assert.Error(t, err)
+	provinces, err := svc.GetProvincesWithLatestCase(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, provinces[0].LatestCase.Statistics.Testing)
+	assert.InDelta(t, 10.0, *provinces[0].LatestCase.Statistics.Testing.PositivityRate, 0.0001)
+	testRepo.AssertExpectations(t)
 }
 
-func TestCovidService_GetProvinceCasesPaginatedSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	mockProvinceCaseRepo.On("GetByProvinceIDPaginatedSorted", "11", 10, 0, sort).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetProvinceCasesPaginatedSorted("11", 10, 0, sort)
-	assert.Error(t, err)
-}
-
-func TestCovidService_GetProvinceCasesByDateRangeSorted_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	_, err := service.GetProvinceCasesByDateRangeSorted("11", "invalid", "2020-03-31", sort)
-	assert.Error(t, err)
-}
+func TestCovidService_TestsErrorIsNotFatal(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	testRepo := new(MockTestRepository)
+	svc := NewCovidServiceWithTests(nationalRepo, new(MockProvinceRepository), new(MockProvinceCaseRepository), testRepo)
 
-func TestCovidService_GetProvinceCasesByDateRangeSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRangeSorted", "11", start, end, sort).Return([]models.ProvinceCaseWithDate{}, errors.New("db error"))
-	_, err := service.GetProvinceCasesByDateRangeSorted("11", "2020-03-01", "2020-03-31", sort)
-	assert.Error(t, err)
-}
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	nationalRepo.On("GetLatest").Return(&models.NationalCase{Day: 1, Date: date}, nil)
+	testRepo.On("GetNationalByDateRange", date, date).Return([]models.DailyTests(nil), errors.New("table missing"))
 
-func TestCovidService_GetProvinceCasesByDateRangePaginatedSorted_InvalidDate(t *testing.T) {
-	_, _, _, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	_, _, err := service.GetProvinceCasesByDateRangePaginatedSorted("11", "invalid", "2020-03-31", 10, 0, sort)
-	assert.Error(t, err)
-}
+	latest, err := svc.GetLatestNationalCase(context.Background())
 
-func TestCovidService_GetProvinceCasesByDateRangePaginatedSorted_Error(t *testing.T) {
-	_, _, mockProvinceCaseRepo, service := setupMockService()
-	sort := utils.SortParams{Field: "date", Order: "asc"}
-	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetByProvinceIDAndDateRangePaginatedSorted", "11", start, end, 10, 0, sort).Return([]models.ProvinceCaseWithDate{}, 0, errors.New("db error"))
-	_, _, err := service.GetProvinceCasesByDateRangePaginatedSorted("11", "2020-03-01", "2020-03-31", 10, 0, sort)
-	assert.Error(t, err)
+	require.NoError(t, err)
+	assert.Nil(t, latest.Tests)
 }
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+var (
+	// ErrDashboardHubFull is returned by Subscribe at the connection limit
+	ErrDashboardHubFull = errors.New("dashboard hub is at its connection limit")
+	// ErrDashboardClientLimit is returned by Subscribe when the client has
+	// as many connections as it is allowed
+	ErrDashboardClientLimit = errors.New("too many dashboard connections from this client")
+	// ErrDashboardHubClosed is returned by Subscribe after Close
+	ErrDashboardHubClosed = errors.New("dashboard hub is closed")
+)
+
+// dashboardProvinceID is the province whose numbers are sent next to the
+// national ones, Sulawesi Tengah
+const dashboardProvinceID = "72"
+
+// dashboardHubBuffer is how many updates a subscriber may fall behind before
+// it is dropped
+const dashboardHubBuffer = 4
+
+// DashboardHubLimits caps the open dashboard connections; zero disables a
+// limit
+type DashboardHubLimits struct {
+	MaxConnections          int
+	MaxConnectionsPerClient int
+}
+
+// dashboardSubscriber is one open dashboard connection
+type dashboardSubscriber struct {
+	client  string
+	updates chan models.DashboardUpdate
+}
+
+// DashboardHub broadcasts the latest national and Sulawesi Tengah numbers to the
+// open /ws connections. It is a WebhookPublisher, so it sends a fresh update
+// whenever sync.completed, national_case.created or province_case.created is
+// published.
+type DashboardHub struct {
+	covid       CovidService
+	limits      DashboardHubLimits
+	mu          sync.Mutex
+	subscribers map[*dashboardSubscriber]struct{}
+	perClient   map[string]int
+	closed      bool
+}
+
+// NewDashboardHub creates a DashboardHub
+func NewDashboardHub(covid CovidService, limits DashboardHubLimits) *DashboardHub {
+	return &DashboardHub{
+		covid:       covid,
+		limits:      limits,
+		subscribers: make(map[*dashboardSubscriber]struct{}),
+		perClient:   make(map[string]int),
+	}
+}
+
+// Latest loads the current dashboard numbers
+func (h *DashboardHub) Latest(ctx context.Context) (models.DashboardUpdate, error) {
+	var update models.DashboardUpdate
+	national, err := h.covid.GetLatestNationalCase(ctx)
+	if err != nil {
+		return update, fmt.Errorf("failed to load latest national case: %w", err)
+	}
+	if national != nil {
+		response := national.TransformToResponse()
+		update.National = &response
+	}
+	provinces, err := h.covid.GetProvincesWithLatestCaseByIDs(ctx, []string{dashboardProvinceID})
+	if err != nil {
+		return update, fmt.Errorf("failed to load latest province case: %w", err)
+	}
+	if len(provinces) > 0 {
+		update.Province = &provinces[0]
+	}
+	return update, nil
+}
+
+// Subscribe returns a channel of the updates for a connection of client and a
+// function that ends the subscription. The channel is closed when the
+// subscription ends, including when the subscriber falls more than
+// dashboardHubBuffer updates behind and when the hub is closed.
+func (h *DashboardHub) Subscribe(client string) (<-chan models.DashboardUpdate, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case h.closed:
+		return nil, nil, ErrDashboardHubClosed
+	case h.limits.MaxConnections > 0 && len(h.subscribers) >= h.limits.MaxConnections:
+		return nil, nil, ErrDashboardHubFull
+	case h.limits.MaxConnectionsPerClient > 0 && h.perClient[client] >= h.limits.MaxConnectionsPerClient:
+		return nil, nil, ErrDashboardClientLimit
+	}
+
+	sub := &dashboardSubscriber{client: client, updates: make(chan models.DashboardUpdate, dashboardHubBuffer)}
+	h.subscribers[sub] = struct{}{}
+	h.perClient[client]++
+	return sub.updates, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(sub)
+	}, nil
+}
+
+// Publish sends every subscriber the latest numbers after new case data
+// arrives. It never blocks on a slow subscriber.
+func (h *DashboardHub) Publish(ctx context.Context, event string, data interface{}) error {
+	if !caseStreamEvents[event] || h.connections() == 0 {
+		return nil
+	}
+	update, err := h.Latest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to broadcast dashboard update: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		select {
+		case sub.updates <- update:
+		default:
+			h.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Close ends every subscription and refuses new ones, letting the open
+// connections finish during a shutdown
+func (h *DashboardHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subscribers {
+		h.remove(sub)
+	}
+}
+
+// connections returns the number of open subscriptions
+func (h *DashboardHub) connections() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// remove ends a subscription once; the caller holds h.mu
+func (h *DashboardHub) remove(sub *dashboardSubscriber) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	if h.perClient[sub.client]--; h.perClient[sub.client] == 0 {
+		delete(h.perClient, sub.client)
+	}
+	close(sub.updates)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardHub_Publish(t *testing.T) {
+	covid := new(MockCovidService)
+	covid.On("GetLatestNationalCase").Return(&models.NationalCase{ID: 1, Day: 500, Positive: 120}, nil)
+	covid.On("GetProvincesWithLatestCaseByIDs", []string{"72"}).
+		Return([]models.ProvinceWithLatestCase{{Province: models.Province{ID: "72", Name: "Sulawesi Tengah"}}}, nil)
+	hub := NewDashboardHub(covid, DashboardHubLimits{})
+
+	// Without connections nothing is loaded
+	require.NoError(t, hub.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 1}))
+	covid.AssertNotCalled(t, "GetLatestNationalCase")
+
+	updates, cancel, err := hub.Subscribe("10.0.0.1")
+	require.NoError(t, err)
+	defer cancel()
+	require.NoError(t, hub.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 2}))
+	// Alerts are not case data and send no update
+	require.NoError(t, hub.Publish(context.Background(), models.WebhookEventAlertTriggered, struct{}{}))
+
+	update := <-updates
+	require.NotNil(t, update.National)
+	assert.Equal(t, int64(500), update.National.Day)
+	assert.Equal(t, int64(120), update.National.Daily.Positive)
+	require.NotNil(t, update.Province)
+	assert.Equal(t, "Sulawesi Tengah", update.Province.Name)
+	assert.Empty(t, updates)
+}
+
+func TestDashboardHub_PublishError(t *testing.T) {
+	covid := new(MockCovidService)
+	covid.On("GetLatestNationalCase").Return(nil, errors.New("db down"))
+	hub := NewDashboardHub(covid, DashboardHubLimits{})
+	_, cancel, err := hub.Subscribe("10.0.0.1")
+	require.NoError(t, err)
+	defer cancel()
+
+	err = hub.Publish(context.Background(), models.WebhookEventNationalCaseCreated, models.NationalCaseCreatedData{})
+
+	assert.ErrorContains(t, err, "db down")
+}
+
+func TestDashboardHub_Limits(t *testing.T) {
+	hub := NewDashboardHub(new(MockCovidService), DashboardHubLimits{MaxConnections: 3, MaxConnectionsPerClient: 2})
+
+	_, cancelA1, err := hub.Subscribe("a")
+	require.NoError(t, err)
+	_, cancelA2, err := hub.Subscribe("a")
+	require.NoError(t, err)
+	_, _, err = hub.Subscribe("a")
+	assert.ErrorIs(t, err, ErrDashboardClientLimit)
+
+	_, cancelB, err := hub.Subscribe("b")
+	require.NoError(t, err)
+	defer cancelB()
+	_, _, err = hub.Subscribe("c")
+	assert.ErrorIs(t, err, ErrDashboardHubFull)
+
+	// Ending a connection frees its slots, once
+	cancelA1()
+	cancelA1()
+	_, cancelA3, err := hub.Subscribe("a")
+	require.NoError(t, err)
+	defer cancelA3()
+	cancelA2()
+	_, cancelC, err := hub.Subscribe("c")
+	require.NoError(t, err)
+	defer cancelC()
+}
+
+func TestDashboardHub_DropsSlowSubscriber(t *testing.T) {
+	covid := new(MockCovidService)
+	covid.On("GetLatestNationalCase").Return(nil, nil)
+	covid.On("GetProvincesWithLatestCaseByIDs", []string{"72"}).Return([]models.ProvinceWithLatestCase{}, nil)
+	hub := NewDashboardHub(covid, DashboardHubLimits{MaxConnectionsPerClient: 1})
+	updates, cancel, err := hub.Subscribe("a")
+	require.NoError(t, err)
+	defer cancel()
+
+	for i := 0; i <= dashboardHubBuffer; i++ {
+		require.NoError(t, hub.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{}))
+	}
+
+	received := 0
+	for range updates {
+		received++
+	}
+	assert.Equal(t, dashboardHubBuffer, received)
+	// The dropped connection no longer counts against the client
+	_, cancelAgain, err := hub.Subscribe("a")
+	require.NoError(t, err)
+	cancelAgain()
+}
+
+func TestDashboardHub_Close(t *testing.T) {
+	hub := NewDashboardHub(new(MockCovidService), DashboardHubLimits{})
+	updates, cancel, err := hub.Subscribe("a")
+	require.NoError(t, err)
+
+	hub.Close()
+	cancel()
+
+	_, open := <-updates
+	assert.False(t, open)
+	_, _, err = hub.Subscribe("a")
+	assert.ErrorIs(t, err, ErrDashboardHubClosed)
+}
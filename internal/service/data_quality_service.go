@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// OutlierRule decides when a daily count is an extreme outlier: when it lies
+// more than Threshold robust standard deviations (1.4826 times the median
+// absolute deviation, at least 1) from the median of the WindowDays before
+type OutlierRule struct {
+	WindowDays int
+	Threshold  float64
+	// MinDeviation ignores counts closer than this to the median, which are
+	// common among small counts with a near zero deviation
+	MinDeviation int64
+}
+
+// DataQualityService flags anomalies in the case data: negative daily
+// counts, cumulative counts below those of the previous date and extreme
+// daily outliers. The data is scanned at an interval and, as a
+// WebhookPublisher receiving sync.completed, after each ingestion.
+type DataQualityService struct {
+	nationalRepo repository.NationalCaseRepository
+	provinceRepo repository.ProvinceCaseRepository
+	outliers     OutlierRule
+	clock        clock.Clock
+
+	mu sync.RWMutex
+	// issues are ordered most recent first
+	issues    []models.QualityIssue
+	byRow     map[qualityRowKey][]models.QualityFlag
+	checkedAt *time.Time
+	stopChan  chan struct{}
+}
+
+// qualityRowKey identifies a national (empty provinceID) or province row
+type qualityRowKey struct {
+	provinceID string
+	day        int64
+}
+
+// qualityFields are the counts checked, by their daily names
+var qualityFields = []string{"positive", "recovered", "deceased"}
+
+// qualityPoint is the checked counts of one row, in qualityFields order
+type qualityPoint struct {
+	key        qualityRowKey
+	date       time.Time
+	daily      [3]int64
+	cumulative [3]int64
+}
+
+// NewDataQualityService creates a DataQualityService. No issues are reported
+// until the first Scan.
+func NewDataQualityService(nationalRepo repository.NationalCaseRepository, provinceRepo repository.ProvinceCaseRepository, outliers OutlierRule) *DataQualityService {
+	return &DataQualityService{
+		nationalRepo: nationalRepo,
+		provinceRepo: provinceRepo,
+		outliers:     outliers,
+		clock:        clock.System,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock of the scan timestamps and the scan schedule
+func (s *DataQualityService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Scan checks all national and province rows and replaces the issues found
+// by the previous scan
+func (s *DataQualityService) Scan(ctx context.Context) error {
+	issues, err := s.findIssues(ctx)
+	if err != nil {
+		return err
+	}
+	byRow := make(map[qualityRowKey][]models.QualityFlag)
+	for _, issue := range issues {
+		key := qualityRowKey{provinceID: issue.ProvinceID, day: issue.Day}
+		byRow[key] = append(byRow[key], issue.QualityFlag)
+	}
+
+	checkedAt := s.clock.Now().UTC()
+	s.mu.Lock()
+	s.issues, s.byRow, s.checkedAt = issues, byRow, &checkedAt
+	s.mu.Unlock()
+	return nil
+}
+
+// Preview scans the data like Scan and describes how the issues would
+// change, keeping the current ones
+func (s *DataQualityService) Preview(ctx context.Context) ([]models.RowChange, error) {
+	issues, err := s.findIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	current := s.issues
+	s.mu.RUnlock()
+
+	before := make(map[string]models.QualityIssue, len(current))
+	for _, issue := range current {
+		before[qualityIssueKey(issue)] = issue
+	}
+	var changes []models.RowChange
+	for _, issue := range issues {
+		key := qualityIssueKey(issue)
+		old, ok := before[key]
+		delete(before, key)
+		switch {
+		case !ok:
+			changes = append(changes, models.RowChange{Table: "data_quality_issues", Key: key, Action: models.ChangeActionInsert, After: qualityIssueFields(issue)})
+		case old.Value != issue.Value || old.Message != issue.Message:
+			changes = append(changes, models.RowChange{Table: "data_quality_issues", Key: key, Action: models.ChangeActionUpdate,
+				Before: qualityIssueFields(old), After: qualityIssueFields(issue)})
+		}
+	}
+	// Resolved issues, in their current order
+	for _, issue := range current {
+		if _, ok := before[qualityIssueKey(issue)]; ok {
+			changes = append(changes, models.RowChange{Table: "data_quality_issues", Key: qualityIssueKey(issue), Action: models.ChangeActionDelete, Before: qualityIssueFields(issue)})
+		}
+	}
+	return changes, nil
+}
+
+// qualityIssueKey identifies an issue by its row, type and field, e.g.
+// 72/310/negative_daily/positive or national/310/outlier/deceased
+func qualityIssueKey(issue models.QualityIssue) string {
+	scope := issue.ProvinceID
+	if scope == "" {
+		scope = models.QualityScopeNational
+	}
+	return fmt.Sprintf("%s/%d/%s/%s", scope, issue.Day, issue.Type, issue.Field)
+}
+
+func qualityIssueFields(issue models.QualityIssue) map[string]interface{} {
+	return map[string]interface{}{"value": issue.Value, "message": issue.Message}
+}
+
+// findIssues checks all national and province rows, most recent issues first
+func (s *DataQualityService) findIssues(ctx context.Context) ([]models.QualityIssue, error) {
+	byDate := utils.SortParams{Field: "date", Order: "asc"}
+	national, _, err := s.nationalRepo.Find(ctx, repository.NationalCaseQuery{Sort: byDate})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan national cases: %w", err)
+	}
+	provinces, _, err := s.provinceRepo.Find(ctx, repository.ProvinceCaseQuery{Sort: byDate})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan province cases: %w", err)
+	}
+
+	series := [][]qualityPoint{make([]qualityPoint, 0, len(national))}
+	for _, c := range national {
+		series[0] = append(series[0], qualityPoint{
+			key:        qualityRowKey{day: c.Day},
+			date:       c.Date,
+			daily:      [3]int64{c.Positive, c.Recovered, c.Deceased},
+			cumulative: [3]int64{c.CumulativePositive, c.CumulativeRecovered, c.CumulativeDeceased},
+		})
+	}
+	seriesIndex := make(map[string]int)
+	for _, c := range provinces {
+		i, ok := seriesIndex[c.ProvinceID]
+		if !ok {
+			i = len(series)
+			seriesIndex[c.ProvinceID] = i
+			series = append(series, nil)
+		}
+		series[i] = append(series[i], qualityPoint{
+			key:        qualityRowKey{provinceID: c.ProvinceID, day: c.Day},
+			date:       c.Date,
+			daily:      [3]int64{c.Positive, c.Recovered, c.Deceased},
+			cumulative: [3]int64{c.CumulativePositive, c.CumulativeRecovered, c.CumulativeDeceased},
+		})
+	}
+
+	var issues []models.QualityIssue
+	for _, points := range series {
+		issues = append(issues, s.detect(points)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if !issues[i].Date.Equal(issues[j].Date) {
+			return issues[i].Date.After(issues[j].Date)
+		}
+		return issues[i].ProvinceID < issues[j].ProvinceID
+	})
+	return issues, nil
+}
+
+// detect returns the issues of one date ordered series
+func (s *DataQualityService) detect(points []qualityPoint) []models.QualityIssue {
+	var issues []models.QualityIssue
+	windowStart := 0
+	for i, p := range points {
+		for windowStart < i && daysBetween(points[windowStart].date, p.date) > s.outliers.WindowDays {
+			windowStart++
+		}
+		add := func(flag models.QualityFlag) {
+			issues = append(issues, models.QualityIssue{ProvinceID: p.key.provinceID, Day: p.key.day, Date: p.date, QualityFlag: flag})
+		}
+
+		for f, field := range qualityFields {
+			value := p.daily[f]
+			if value < 0 {
+				add(models.QualityFlag{
+					Type:    models.QualityIssueNegativeDaily,
+					Field:   field,
+					Value:   value,
+					Message: fmt.Sprintf("%s is negative", field),
+				})
+			} else if median, ok := s.outlierMedian(points[windowStart:i], f, value); ok {
+				add(models.QualityFlag{
+					Type:      models.QualityIssueOutlier,
+					Field:     field,
+					Value:     value,
+					Reference: &median,
+					Message:   fmt.Sprintf("%s is far from the median of %g over the previous %d days", field, median, s.outliers.WindowDays),
+				})
+			}
+
+			if i > 0 && p.cumulative[f] < points[i-1].cumulative[f] {
+				previous := float64(points[i-1].cumulative[f])
+				add(models.QualityFlag{
+					Type:      models.QualityIssueCumulativeRegression,
+					Field:     "cumulative_" + field,
+					Value:     p.cumulative[f],
+					Reference: &previous,
+					Message:   fmt.Sprintf("cumulative_%s fell from %d on the previous date", field, points[i-1].cumulative[f]),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// outlierMedian returns the median of field over window and whether value is
+// an outlier against it. Windows with less than half their days of data, or
+// rules without a window, find no outliers.
+func (s *DataQualityService) outlierMedian(window []qualityPoint, field int, value int64) (float64, bool) {
+	values := make([]float64, 0, len(window))
+	for _, p := range window {
+		if p.daily[field] >= 0 {
+			values = append(values, float64(p.daily[field]))
+		}
+	}
+	if s.outliers.WindowDays <= 0 || len(values) == 0 || 2*len(values) < s.outliers.WindowDays {
+		return 0, false
+	}
+
+	m := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+	scale := math.Max(1, 1.4826*median(deviations))
+	deviation := math.Abs(float64(value) - m)
+	return m, deviation >= float64(s.outliers.MinDeviation) && deviation/scale > s.outliers.Threshold
+}
+
+// median returns the median of values, reordering them
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
+
+// Issues returns a page of the issues matching filter, most recent first.
+// A zero Limit returns all of them.
+func (s *DataQualityService) Issues(filter models.QualityIssueFilter) models.DataQualityReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matching := []models.QualityIssue{}
+	for _, issue := range s.issues {
+		if filter.Matches(issue) {
+			matching = append(matching, issue)
+		}
+	}
+	total := len(matching)
+	limit, offset := filter.Limit, filter.Offset
+	if limit <= 0 {
+		limit, offset = max(total, 1), 0
+	}
+	page := matching[min(offset, total):min(offset+limit, total)]
+	return models.DataQualityReport{
+		CheckedAt:  s.checkedAt,
+		Issues:     page,
+		Pagination: models.CalculatePaginationMeta(limit, offset, total),
+	}
+}
+
+// AnnotateNationalCases returns the cases with the quality block of each
+// flagged row. Cases are copied, not changed, as they may be cached.
+func (s *DataQualityService) AnnotateNationalCases(cases []models.NationalCase) []models.NationalCase {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var annotated []models.NationalCase
+	for i, c := range cases {
+		flags := s.byRow[qualityRowKey{day: c.Day}]
+		if flags == nil {
+			continue
+		}
+		if annotated == nil {
+			annotated = append([]models.NationalCase(nil), cases...)
+		}
+		annotated[i].Quality = &models.RecordQuality{Flagged: true, Issues: flags}
+	}
+	if annotated == nil {
+		return cases
+	}
+	return annotated
+}
+
+// AnnotateProvinceCases is AnnotateNationalCases for province cases
+func (s *DataQualityService) AnnotateProvinceCases(cases []models.ProvinceCaseWithDate) []models.ProvinceCaseWithDate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var annotated []models.ProvinceCaseWithDate
+	for i, c := range cases {
+		flags := s.byRow[qualityRowKey{provinceID: c.ProvinceID, day: c.Day}]
+		if flags == nil {
+			continue
+		}
+		if annotated == nil {
+			annotated = append([]models.ProvinceCaseWithDate(nil), cases...)
+		}
+		annotated[i].Quality = &models.RecordQuality{Flagged: true, Issues: flags}
+	}
+	if annotated == nil {
+		return cases
+	}
+	return annotated
+}
+
+// Publish rescans the data in the background after each completed ingestion
+func (s *DataQualityService) Publish(ctx context.Context, event string, data interface{}) error {
+	if event == models.WebhookEventSyncCompleted {
+		go s.scan(context.Background())
+	}
+	return nil
+}
+
+// Start scans the data immediately and then at the given interval in a
+// background goroutine. A zero interval scans only once.
+func (s *DataQualityService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		s.scan(ctx)
+		if interval <= 0 {
+			return
+		}
+
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				s.scan(ctx)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (s *DataQualityService) scan(ctx context.Context) {
+	if err := s.Scan(ctx); err != nil {
+		log.Printf("Data quality scan failed: %v", err)
+		return
+	}
+	s.mu.RLock()
+	found := len(s.issues)
+	s.mu.RUnlock()
+	if found > 0 {
+		log.Printf("Data quality scan flagged %d issues", found)
+	}
+}
+
+// Stop halts the background scans started by Start.
+func (s *DataQualityService) Stop() {
+	close(s.stopChan)
+}
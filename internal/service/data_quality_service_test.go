@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var qualityTestRule = OutlierRule{WindowDays: 14, Threshold: 10, MinDeviation: 50}
+
+// qualityNationalSeries is 30 days of about 100 new cases a day, with a
+// spike of 5000 on day 20 and negative recoveries on day 25 that lower the
+// cumulative count
+func qualityNationalSeries() []models.NationalCase {
+	start := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	var cases []models.NationalCase
+	var cumPositive, cumRecovered int64
+	for i := 0; i < 30; i++ {
+		c := models.NationalCase{Day: int64(i + 1), Date: start.AddDate(0, 0, i), Positive: 100 + int64(i%5), Recovered: 50}
+		switch i + 1 {
+		case 20:
+			c.Positive = 5000
+		case 25:
+			c.Recovered = -30
+		}
+		cumPositive += c.Positive
+		cumRecovered += c.Recovered
+		c.CumulativePositive, c.CumulativeRecovered = cumPositive, cumRecovered
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+func newQualityTestService(t *testing.T, national []models.NationalCase, provinces []models.ProvinceCaseWithDate) *DataQualityService {
+	nationalRepo := new(MockNationalCaseRepository)
+	nationalRepo.On("Find", mock.Anything).Return(national, len(national), nil)
+	provinceRepo := new(MockProvinceCaseRepository)
+	provinceRepo.On("Find", mock.Anything).Return(provinces, len(provinces), nil)
+	svc := NewDataQualityService(nationalRepo, provinceRepo, qualityTestRule)
+	svc.SetClock(clock.NewMock(time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)))
+	require.NoError(t, svc.Scan(context.Background()))
+	return svc
+}
+
+func TestDataQualityService_Scan(t *testing.T) {
+	svc := newQualityTestService(t, qualityNationalSeries(), nil)
+
+	report := svc.Issues(models.QualityIssueFilter{})
+
+	require.NotNil(t, report.CheckedAt)
+	require.Len(t, report.Issues, 3)
+	// Most recent first
+	negative, regression, outlier := report.Issues[0], report.Issues[1], report.Issues[2]
+	assert.Equal(t, int64(25), negative.Day)
+	assert.Equal(t, models.QualityIssueNegativeDaily, negative.Type)
+	assert.Equal(t, "recovered", negative.Field)
+	assert.Equal(t, int64(-30), negative.Value)
+
+	assert.Equal(t, int64(25), regression.Day)
+	assert.Equal(t, models.QualityIssueCumulativeRegression, regression.Type)
+	assert.Equal(t, "cumulative_recovered", regression.Field)
+	assert.Equal(t, float64(24*50), *regression.Reference)
+
+	assert.Equal(t, int64(20), outlier.Day)
+	assert.Equal(t, models.QualityIssueOutlier, outlier.Type)
+	assert.Equal(t, "positive", outlier.Field)
+	assert.Equal(t, float64(102), *outlier.Reference)
+	assert.Equal(t, 3, report.Pagination.Total)
+}
+
+func TestDataQualityService_Preview(t *testing.T) {
+	svc := newQualityTestService(t, qualityNationalSeries(), nil)
+	// The negative recoveries were corrected on day 25 and reported on day 10
+	cases := qualityNationalSeries()
+	var cumRecovered int64
+	for i := range cases {
+		switch cases[i].Day {
+		case 10:
+			cases[i].Recovered = -30
+		case 25:
+			cases[i].Recovered = 50
+		}
+		cumRecovered += cases[i].Recovered
+		cases[i].CumulativeRecovered = cumRecovered
+	}
+	nationalRepo := new(MockNationalCaseRepository)
+	nationalRepo.On("Find", mock.Anything).Return(cases, len(cases), nil)
+	svc.nationalRepo = nationalRepo
+
+	changes, err := svc.Preview(context.Background())
+
+	require.NoError(t, err)
+	var keys []string
+	for _, change := range changes {
+		keys = append(keys, change.Action+" "+change.Key)
+	}
+	assert.Equal(t, []string{
+		"insert national/10/negative_daily/recovered",
+		"insert national/10/cumulative_regression/cumulative_recovered",
+		"delete national/25/negative_daily/recovered",
+		"delete national/25/cumulative_regression/cumulative_recovered",
+	}, keys)
+	// The current issues are kept
+	assert.Equal(t, int64(25), svc.Issues(models.QualityIssueFilter{}).Issues[0].Day)
+}
+
+func TestDataQualityService_OutlierNeedsData(t *testing.T) {
+	// A spike on the third day has too few days before it to compare to
+	cases := qualityNationalSeries()[:3]
+	cases[2].Positive = 5000
+	cases[2].CumulativePositive = cases[1].CumulativePositive + 5000
+	svc := newQualityTestService(t, cases, nil)
+
+	assert.Empty(t, svc.Issues(models.QualityIssueFilter{}).Issues)
+}
+
+func TestDataQualityService_ProvinceSeriesAndFilter(t *testing.T) {
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	provinces := []models.ProvinceCaseWithDate{
+		{ProvinceCase: models.ProvinceCase{Day: 1, ProvinceID: "72", Deceased: 2, CumulativeDeceased: 10}, Date: date},
+		{ProvinceCase: models.ProvinceCase{Day: 1, ProvinceID: "31", CumulativeDeceased: 5}, Date: date},
+		// Each province is compared to its own previous date only
+		{ProvinceCase: models.ProvinceCase{Day: 2, ProvinceID: "72", CumulativeDeceased: 8}, Date: date.AddDate(0, 0, 1)},
+		{ProvinceCase: models.ProvinceCase{Day: 2, ProvinceID: "31", CumulativeDeceased: 6}, Date: date.AddDate(0, 0, 1)},
+	}
+	svc := newQualityTestService(t, qualityNationalSeries(), provinces)
+
+	report := svc.Issues(models.QualityIssueFilter{ProvinceID: "72"})
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "72", report.Issues[0].ProvinceID)
+	assert.Equal(t, "cumulative_deceased", report.Issues[0].Field)
+
+	assert.Len(t, svc.Issues(models.QualityIssueFilter{ProvinceID: models.QualityScopeNational}).Issues, 3)
+	assert.Len(t, svc.Issues(models.QualityIssueFilter{Type: models.QualityIssueOutlier}).Issues, 1)
+	assert.Len(t, svc.Issues(models.QualityIssueFilter{StartDate: date, EndDate: date.AddDate(0, 0, 1)}).Issues, 1)
+
+	page := svc.Issues(models.QualityIssueFilter{Limit: 2, Offset: 2})
+	require.Len(t, page.Issues, 2)
+	assert.Equal(t, 4, page.Pagination.Total)
+	assert.False(t, page.Pagination.HasNext)
+	assert.Empty(t, svc.Issues(models.QualityIssueFilter{Limit: 2, Offset: 10}).Issues)
+}
+
+func TestDataQualityService_Annotate(t *testing.T) {
+	national := qualityNationalSeries()
+	svc := newQualityTestService(t, national, nil)
+
+	annotated := svc.AnnotateNationalCases(national)
+
+	require.NotNil(t, annotated[24].Quality)
+	assert.True(t, annotated[24].Quality.Flagged)
+	assert.Len(t, annotated[24].Quality.Issues, 2)
+	require.NotNil(t, annotated[19].Quality)
+	assert.Nil(t, annotated[0].Quality)
+	// The given cases are left unchanged
+	assert.Nil(t, national[24].Quality)
+
+	clean := national[:5]
+	assert.Equal(t, clean, svc.AnnotateNationalCases(clean))
+}
+
+func TestDataQualityService_ScanError(t *testing.T) {
+	nationalRepo := new(MockNationalCaseRepository)
+	nationalRepo.On("Find", mock.Anything).Return([]models.NationalCase(nil), 0, errors.New("db down"))
+	svc := NewDataQualityService(nationalRepo, new(MockProvinceCaseRepository), qualityTestRule)
+
+	assert.Error(t, svc.Scan(context.Background()))
+	report := svc.Issues(models.QualityIssueFilter{})
+	assert.Nil(t, report.CheckedAt)
+	assert.Empty(t, report.Issues)
+}
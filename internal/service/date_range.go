@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// ErrInvalidDateRangePreset is returned for a ?range= value that is not one of
+// DateRangePresets
+var ErrInvalidDateRangePreset = errors.New("invalid date range preset")
+
+// ErrInvalidDateFormat is returned by ParseDateRange for a date that is not YYYY-MM-DD
+var ErrInvalidDateFormat = errors.New("invalid date format")
+
+// DateRange bounds a list by dates. It only applies when both are set.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseDateRange parses YYYY-MM-DD start and end dates, either of which may
+// be empty. Handlers parse their parameters once and pass the range down.
+func ParseDateRange(start, end string) (DateRange, error) {
+	var r DateRange
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Time
+	}{{"start", start, &r.Start}, {"end", end, &r.End}} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("%w: %s date %q", ErrInvalidDateFormat, d.name, d.value)
+		}
+		*d.dest = parsed
+	}
+	return r, nil
+}
+
+// IsSet reports whether both dates are set
+func (r DateRange) IsSet() bool {
+	return !r.Start.IsZero() && !r.End.IsZero()
+}
+
+// String formats a set range as "YYYY-MM-DD:YYYY-MM-DD", or "" otherwise
+func (r DateRange) String() string {
+	if !r.IsSet() {
+		return ""
+	}
+	return r.Start.Format("2006-01-02") + ":" + r.End.Format("2006-01-02")
+}
+
+// DateRangePresets are the accepted ?range= values
+var DateRangePresets = []string{"last7d", "last14d", "last30d", "last90d", "ytd", "all"}
+
+// presetDays is the number of days, the end date included, of each lastNd preset
+var presetDays = map[string]int{"last7d": 7, "last14d": 14, "last30d": 30, "last90d": 90}
+
+// resolveDateRange turns preset into start and end dates (YYYY-MM-DD). The
+// range ends on the date of the latest national case rather than today, since
+// the data stops being updated at some point; without any case it ends today
+// by clk. "all" resolves to empty dates, which select every record.
+func resolveDateRange(ctx context.Context, preset string, clk clock.Clock, latest func(context.Context) (*models.NationalCase, error)) (string, string, error) {
+	days, isLastN := presetDays[preset]
+	switch {
+	case preset == "all":
+		return "", "", nil
+	case !isLastN && preset != "ytd":
+		return "", "", fmt.Errorf("%w %q", ErrInvalidDateRangePreset, preset)
+	}
+
+	end := clk.Now()
+	latestCase, err := latest(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve date range: %w", err)
+	}
+	if latestCase != nil {
+		end = latestCase.Date
+	}
+
+	start := time.Date(end.Year(), time.January, 1, 0, 0, 0, 0, end.Location())
+	if isLastN {
+		start = end.AddDate(0, 0, -(days - 1))
+	}
+	return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+)
+
+// ErrExportQueueFull is returned when an export is requested while the queue
+// is at capacity
+var ErrExportQueueFull = errors.New("export queue is full")
+
+// exportPageSize is the number of cases read per query, and so the step in
+// which an export's progress advances
+const exportPageSize = 1000
+
+// ExportRequest selects the cases of an export job and its file format
+type ExportRequest struct {
+	Scope string
+	// ProvinceID limits a province export to one province; all provinces when empty
+	ProvinceID string
+	Format     string
+	Range      DateRange
+}
+
+// ExportRenderer writes exported cases in the format of req. National exports
+// pass only national cases and province exports only province cases.
+type ExportRenderer func(w io.Writer, req ExportRequest, national []models.NationalCase, province []models.ProvinceCaseWithDate) error
+
+// ExportService runs case exports from a bounded queue in the background,
+// tracking the rows each has processed, and keeps the files for download
+// until they expire
+type ExportService struct {
+	covid     CovidService
+	render    ExportRenderer
+	store     storage.BlobStore
+	retention time.Duration
+	clock     clock.Clock
+
+	queue    chan *models.ExportJob
+	mu       sync.RWMutex
+	jobs     map[string]*models.ExportJob
+	requests map[string]ExportRequest
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewExportService creates an ExportService that writes export files into
+// store and queues up to queueSize exports. Finished jobs and their files are
+// removed after retention. Call Start to process the queue.
+func NewExportService(covid CovidService, render ExportRenderer, store storage.BlobStore, queueSize int, retention time.Duration) *ExportService {
+	return &ExportService{
+		covid:     covid,
+		render:    render,
+		store:     store,
+		retention: retention,
+		clock:     clock.System,
+		queue:     make(chan *models.ExportJob, queueSize),
+		jobs:      make(map[string]*models.ExportJob),
+		requests:  make(map[string]ExportRequest),
+		done:      make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock of the job timestamps and their retention
+func (s *ExportService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Start processes the queued exports one at a time in the background.
+func (s *ExportService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go func() {
+		defer close(s.done)
+		for {
+			select {
+			case job := <-s.queue:
+				s.run(ctx, job)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the running export and waits for the queue started by Start
+// to halt.
+func (s *ExportService) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+// StartExport queues an export and returns a snapshot of the new job, or
+// ErrExportQueueFull when the queue is at capacity.
+func (s *ExportService) StartExport(req ExportRequest) (models.ExportJob, error) {
+	s.removeExpired()
+
+	now := s.clock.Now().UTC()
+	id := newExportID()
+	job := &models.ExportJob{
+		ID:         id,
+		Status:     models.ExportStatusQueued,
+		Scope:      req.Scope,
+		ProvinceID: req.ProvinceID,
+		Format:     req.Format,
+		File:       "export-" + id + "." + req.Format,
+		CreatedAt:  now,
+	}
+	if req.Range.IsSet() {
+		job.StartDate = req.Range.Start.Format("2006-01-02")
+		job.EndDate = req.Range.End.Format("2006-01-02")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case s.queue <- job:
+	default:
+		return models.ExportJob{}, ErrExportQueueFull
+	}
+	s.jobs[id] = job
+	s.requests[id] = req
+	return *job, nil
+}
+
+// GetJob returns a snapshot of the job with the given ID, or nil if unknown.
+func (s *ExportService) GetJob(id string) *models.ExportJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil
+	}
+	snapshot := *job
+	return &snapshot
+}
+
+func (s *ExportService) run(ctx context.Context, job *models.ExportJob) {
+	s.mu.Lock()
+	req := s.requests[job.ID]
+	started := s.clock.Now().UTC()
+	job.Status = models.ExportStatusRunning
+	job.StartedAt = &started
+	s.mu.Unlock()
+
+	err := s.export(ctx, job, req)
+
+	finished := s.clock.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.FinishedAt = &finished
+	job.ETA = nil
+	if err != nil {
+		job.Status = models.ExportStatusFailed
+		job.Error = err.Error()
+		log.Printf("Export %s failed: %v", job.ID, err)
+		return
+	}
+	job.Status = models.ExportStatusCompleted
+	job.DownloadURL = "/api/v1/exports/" + job.ID + "/download"
+}
+
+// export reads the cases page by page, reporting the progress on the job,
+// and writes them to the job's file
+func (s *ExportService) export(ctx context.Context, job *models.ExportJob, req ExportRequest) error {
+	var national []models.NationalCase
+	var province []models.ProvinceCaseWithDate
+	for offset := 0; ; offset += exportPageSize {
+		opts := QueryOptions{ProvinceID: req.ProvinceID, Range: req.Range, Page: Page{Limit: exportPageSize, Offset: offset}}
+		var n, total int
+		var err error
+		if req.Scope == models.ExportScopeNational {
+			var cases []models.NationalCase
+			cases, total, err = s.covid.ListNationalCases(ctx, opts)
+			national, n = append(national, cases...), len(cases)
+		} else {
+			var cases []models.ProvinceCaseWithDate
+			cases, total, err = s.covid.ListProvinceCases(ctx, opts)
+			province, n = append(province, cases...), len(cases)
+		}
+		if err != nil {
+			return err
+		}
+		s.progress(job, offset+n, total)
+		if n == 0 || offset+n >= total {
+			break
+		}
+	}
+
+	write := func(w io.Writer) error {
+		return s.render(w, req, national, province)
+	}
+	// Workbooks are zip files already, so only CSV gains from gzip and brotli
+	// variants
+	var err error
+	if req.Format == models.ExportFormatCSV {
+		_, err = storage.WriteEncoded(ctx, s.store, job.File, write)
+	} else {
+		_, err = storage.Write(ctx, s.store, job.File, write)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// OpenExport opens the file of the completed export with the given ID, or
+// returns storage.ErrNotFound. The pre-compressed variant that best matches
+// acceptEncoding is opened when there is one, and its content encoding
+// returned. Close the blob after reading it.
+func (s *ExportService) OpenExport(ctx context.Context, id, acceptEncoding string) (*storage.Blob, string, error) {
+	job := s.GetJob(id)
+	if job == nil || job.Status != models.ExportStatusCompleted {
+		return nil, "", storage.ErrNotFound
+	}
+	return storage.OpenEncoded(ctx, s.store, job.File, acceptEncoding)
+}
+
+// progress records the rows processed and estimates the finish from the
+// pace so far
+func (s *ExportService) progress(job *models.ExportJob, processed, total int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.RowsProcessed, job.TotalRows = processed, total
+	job.ETA = nil
+	if processed > 0 && processed < total {
+		now := s.clock.Now().UTC()
+		elapsed := now.Sub(*job.StartedAt)
+		eta := now.Add(elapsed * time.Duration(total-processed) / time.Duration(processed))
+		job.ETA = &eta
+	}
+}
+
+// removeExpired forgets the jobs that finished longer than the retention ago
+// and removes their files
+func (s *ExportService) removeExpired() {
+	cutoff := s.clock.Now().Add(-s.retention)
+	var files []string
+	s.mu.Lock()
+	for id, job := range s.jobs {
+		if job.FinishedAt == nil || job.FinishedAt.After(cutoff) {
+			continue
+		}
+		files = append(files, job.File)
+		delete(s.jobs, id)
+		delete(s.requests, id)
+	}
+	s.mu.Unlock()
+
+	// The store may be remote, so files are removed outside the lock
+	for _, file := range files {
+		if err := storage.DeleteEncoded(context.Background(), s.store, file); err != nil {
+			log.Printf("Error removing export file %s: %v", s.store.Location(file), err)
+		}
+	}
+}
+
+// newExportID returns 16 random bytes hex encoded, so job IDs and their
+// download URLs cannot be guessed
+func newExportID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
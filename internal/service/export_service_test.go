@@ -0,0 +1,154 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRenderer writes the number of exported cases
+func countingRenderer(w io.Writer, req ExportRequest, national []models.NationalCase, province []models.ProvinceCaseWithDate) error {
+	_, err := fmt.Fprintf(w, "%s %d %d", req.Scope, len(national), len(province))
+	return err
+}
+
+func waitForExport(t *testing.T, svc *ExportService, id string) *models.ExportJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job := svc.GetJob(id)
+		require.NotNil(t, job)
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("export %s did not finish", id)
+	return nil
+}
+
+func TestExportService_StartExport(t *testing.T) {
+	covid := new(MockCovidService)
+	firstPage := make([]models.NationalCase, exportPageSize)
+	covid.On("ListNationalCases", QueryOptions{Page: Page{Limit: exportPageSize}}).Return(firstPage, exportPageSize+1, nil)
+	covid.On("ListNationalCases", QueryOptions{Page: Page{Limit: exportPageSize, Offset: exportPageSize}}).Return([]models.NationalCase{{ID: 1}}, exportPageSize+1, nil)
+
+	store := storage.NewLocal(filepath.Join(t.TempDir(), "exports"))
+	svc := NewExportService(covid, countingRenderer, store, 5, time.Hour)
+	svc.Start()
+	defer svc.Stop()
+
+	job, err := svc.StartExport(ExportRequest{Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, models.ExportStatusQueued, job.Status)
+	assert.Len(t, job.ID, 32)
+
+	finished := waitForExport(t, svc, job.ID)
+	assert.Equal(t, models.ExportStatusCompleted, finished.Status)
+	assert.Equal(t, exportPageSize+1, finished.RowsProcessed)
+	assert.Equal(t, exportPageSize+1, finished.TotalRows)
+	assert.Nil(t, finished.ETA)
+	assert.Equal(t, "/api/v1/exports/"+job.ID+"/download", finished.DownloadURL)
+
+	blob, encoding, err := svc.OpenExport(context.Background(), job.ID, "")
+	require.NoError(t, err)
+	assert.Empty(t, encoding)
+	content, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	assert.Equal(t, "national 1001 0", string(content))
+
+	blob, encoding, err = svc.OpenExport(context.Background(), job.ID, "gzip, deflate")
+	require.NoError(t, err)
+	assert.Equal(t, storage.EncodingGzip, encoding)
+	gz, err := gzip.NewReader(blob)
+	require.NoError(t, err)
+	content, err = io.ReadAll(gz)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	assert.Equal(t, "national 1001 0", string(content))
+	covid.AssertExpectations(t)
+}
+
+func TestExportService_StartExport_Failed(t *testing.T) {
+	covid := new(MockCovidService)
+	opts := QueryOptions{ProvinceID: "72", Range: testDateRange("2021-01-01", "2021-01-31"), Page: Page{Limit: exportPageSize}}
+	covid.On("ListProvinceCases", opts).Return([]models.ProvinceCaseWithDate(nil), 0, errors.New("db down"))
+
+	dir := t.TempDir()
+	svc := NewExportService(covid, countingRenderer, storage.NewLocal(dir), 5, time.Hour)
+	svc.Start()
+	defer svc.Stop()
+
+	job, err := svc.StartExport(ExportRequest{Scope: models.ExportScopeProvince, ProvinceID: "72", Format: models.ExportFormatXLSX, Range: opts.Range})
+	require.NoError(t, err)
+	assert.Equal(t, "2021-01-01", job.StartDate)
+
+	finished := waitForExport(t, svc, job.ID)
+	assert.Equal(t, models.ExportStatusFailed, finished.Status)
+	assert.Contains(t, finished.Error, "db down")
+	assert.Empty(t, finished.DownloadURL)
+	_, err = os.Stat(filepath.Join(dir, finished.File))
+	assert.True(t, os.IsNotExist(err))
+	_, _, err = svc.OpenExport(context.Background(), job.ID, "")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestExportService_StartExport_QueueFull(t *testing.T) {
+	// Without Start nothing drains the queue
+	svc := NewExportService(new(MockCovidService), countingRenderer, storage.NewLocal(t.TempDir()), 1, time.Hour)
+
+	_, err := svc.StartExport(ExportRequest{Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	require.NoError(t, err)
+	_, err = svc.StartExport(ExportRequest{Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	assert.ErrorIs(t, err, ErrExportQueueFull)
+}
+
+func TestExportService_Progress(t *testing.T) {
+	svc := NewExportService(new(MockCovidService), countingRenderer, storage.NewLocal(t.TempDir()), 1, time.Hour)
+	now := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	svc.SetClock(clock.NewMock(now))
+	started := now.Add(-10 * time.Second)
+	job := &models.ExportJob{StartedAt: &started}
+
+	svc.progress(job, 1000, 3000)
+
+	assert.Equal(t, 1000, job.RowsProcessed)
+	assert.Equal(t, 3000, job.TotalRows)
+	require.NotNil(t, job.ETA)
+	assert.Equal(t, now.Add(20*time.Second), *job.ETA)
+}
+
+func TestExportService_RemovesExpiredExports(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewExportService(new(MockCovidService), countingRenderer, storage.NewLocal(dir), 5, time.Hour)
+	now := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	svc.SetClock(clock.NewMock(now))
+
+	file := filepath.Join(dir, "export-old.csv")
+	require.NoError(t, os.WriteFile(file, []byte("old"), 0o600))
+	require.NoError(t, os.WriteFile(file+".gz", []byte("old"), 0o600))
+	finished := now.Add(-2 * time.Hour)
+	svc.jobs["old"] = &models.ExportJob{ID: "old", Status: models.ExportStatusCompleted, File: "export-old.csv", FinishedAt: &finished}
+
+	_, err := svc.StartExport(ExportRequest{Scope: models.ExportScopeNational, Format: models.ExportFormatCSV})
+	require.NoError(t, err)
+
+	assert.Nil(t, svc.GetJob("old"))
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(file + ".gz")
+	assert.True(t, os.IsNotExist(err))
+}
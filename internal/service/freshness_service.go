@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// FreshnessService describes how current the rows of a list response are.
+// The data source is that of the latest ingestion run; it is loaded once and
+// kept current as a WebhookPublisher receiving sync.completed.
+type FreshnessService struct {
+	syncRepo repository.SyncLogRepository
+	mu       sync.RWMutex
+	source   string
+	clock    clock.Clock
+}
+
+// NewFreshnessService creates a FreshnessService
+func NewFreshnessService(syncRepo repository.SyncLogRepository) *FreshnessService {
+	return &FreshnessService{syncRepo: syncRepo, clock: clock.System}
+}
+
+// SetClock sets the clock of the generated_at timestamps
+func (s *FreshnessService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// freshnessRunLookback is how many sync runs Load looks through for an
+// ingestion run when the latest one estimated Rt
+const freshnessRunLookback = 20
+
+// Load reads the source of the latest ingestion run. Rt estimation runs only
+// add to ingested rows, so they are not a data source.
+func (s *FreshnessService) Load(ctx context.Context) error {
+	source, err := s.latestSource(ctx)
+	if err != nil {
+		return err
+	}
+	if source != "" {
+		s.setSource(source)
+	}
+	return nil
+}
+
+// Preview describes how Load would change the data source
+func (s *FreshnessService) Preview(ctx context.Context) ([]models.RowChange, error) {
+	source, err := s.latestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	current := s.source
+	s.mu.RUnlock()
+	if source == "" || source == current {
+		return nil, nil
+	}
+	return []models.RowChange{{
+		Table:  "freshness",
+		Key:    "data_source",
+		Action: models.ChangeActionUpdate,
+		Before: map[string]interface{}{"data_source": current},
+		After:  map[string]interface{}{"data_source": source},
+	}}, nil
+}
+
+// latestSource returns the source of the latest ingestion run, or "" before
+// the first
+func (s *FreshnessService) latestSource(ctx context.Context) (string, error) {
+	run, err := s.syncRepo.GetLatestRun(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load data source: %w", err)
+	}
+	if run != nil && run.Source == models.RtEstimationSource {
+		runs, err := s.syncRepo.ListRuns(ctx, freshnessRunLookback)
+		if err != nil {
+			return "", fmt.Errorf("failed to load data source: %w", err)
+		}
+		run = nil
+		for i := range runs {
+			if runs[i].Source != models.RtEstimationSource {
+				run = &runs[i]
+				break
+			}
+		}
+	}
+	if run == nil {
+		return "", nil
+	}
+	return run.Source, nil
+}
+
+// Publish records the source of each completed ingestion run
+func (s *FreshnessService) Publish(ctx context.Context, event string, data interface{}) error {
+	if completed, ok := data.(models.SyncCompletedData); ok && event == models.WebhookEventSyncCompleted {
+		s.setSource(completed.Source)
+	}
+	return nil
+}
+
+// Describe returns the freshness of rows, a slice whose elements may have a
+// LastUpdated method like the case rows
+func (s *FreshnessService) Describe(rows interface{}) models.DataFreshness {
+	s.mu.RLock()
+	freshness := models.DataFreshness{DataSource: s.source, GeneratedAt: s.clock.Now().UTC()}
+	s.mu.RUnlock()
+
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return freshness
+	}
+	for i := 0; i < v.Len(); i++ {
+		row, ok := v.Index(i).Interface().(interface{ LastUpdated() *time.Time })
+		if !ok {
+			continue
+		}
+		if updated := row.LastUpdated(); updated != nil && (freshness.LastUpdated == nil || updated.After(*freshness.LastUpdated)) {
+			freshness.LastUpdated = updated
+		}
+	}
+	return freshness
+}
+
+func (s *FreshnessService) setSource(source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source = source
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreshnessService_Describe(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetLatestRun").Return(&models.SyncRun{ID: 3, Source: "kemkes"}, nil)
+	svc := NewFreshnessService(repo)
+	svc.SetClock(clock.NewMock(time.Date(2021, 7, 15, 8, 0, 0, 0, time.UTC)))
+	require.NoError(t, svc.Load(context.Background()))
+
+	older := time.Date(2021, 7, 13, 1, 0, 0, 0, time.UTC)
+	newer := time.Date(2021, 7, 14, 1, 0, 0, 0, time.UTC)
+	rows := []models.NationalCase{
+		{ID: 1, RecordTimestamps: models.RecordTimestamps{UpdatedAt: &newer}},
+		{ID: 2},
+		{ID: 3, RecordTimestamps: models.RecordTimestamps{UpdatedAt: &older}},
+	}
+
+	assert.Equal(t, models.DataFreshness{
+		LastUpdated: &newer,
+		DataSource:  "kemkes",
+		GeneratedAt: time.Date(2021, 7, 15, 8, 0, 0, 0, time.UTC),
+	}, svc.Describe(rows))
+
+	// Rows without timestamps leave LastUpdated unknown
+	assert.Nil(t, svc.Describe([]models.Hospital{{}}).LastUpdated)
+	assert.Nil(t, svc.Describe([]models.NationalCase{}).LastUpdated)
+}
+
+func TestFreshnessService_Publish(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetLatestRun").Return(nil, nil)
+	svc := NewFreshnessService(repo)
+	require.NoError(t, svc.Load(context.Background()))
+	assert.Empty(t, svc.Describe(nil).DataSource)
+
+	require.NoError(t, svc.Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 4, Source: "dinkes"}))
+	require.NoError(t, svc.Publish(context.Background(), models.WebhookEventNationalCaseCreated, models.NationalCaseCreatedData{}))
+
+	assert.Equal(t, "dinkes", svc.Describe(nil).DataSource)
+}
+
+func TestFreshnessService_LoadError(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetLatestRun").Return(nil, errors.New("db down"))
+
+	err := NewFreshnessService(repo).Load(context.Background())
+
+	assert.ErrorContains(t, err, "failed to load data source")
+}
+
+func TestFreshnessService_LoadSkipsRtEstimationRuns(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetLatestRun").Return(&models.SyncRun{ID: 5, Source: models.RtEstimationSource}, nil)
+	repo.On("ListRuns", freshnessRunLookback).Return([]models.SyncRun{
+		{ID: 5, Source: models.RtEstimationSource},
+		{ID: 4, Source: "kemkes"},
+	}, nil)
+	svc := NewFreshnessService(repo)
+
+	require.NoError(t, svc.Load(context.Background()))
+
+	assert.Equal(t, "kemkes", svc.Describe(nil).DataSource)
+}
+
+func TestFreshnessService_Preview(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetLatestRun").Return(&models.SyncRun{ID: 3, Source: "kemkes"}, nil).Once()
+	repo.On("GetLatestRun").Return(&models.SyncRun{ID: 4, Source: "dinkes"}, nil)
+	svc := NewFreshnessService(repo)
+	require.NoError(t, svc.Load(context.Background()))
+
+	changes, err := svc.Preview(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.RowChange{{
+		Table:  "freshness",
+		Key:    "data_source",
+		Action: models.ChangeActionUpdate,
+		Before: map[string]interface{}{"data_source": "kemkes"},
+		After:  map[string]interface{}{"data_source": "dinkes"},
+	}}, changes)
+	assert.Equal(t, "kemkes", svc.Describe(nil).DataSource)
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 )
 
 // HospitalService handles business logic for hospitals
@@ -15,17 +18,17 @@ func NewHospitalService(hospitalRepo repository.HospitalRepositoryInterface) *Ho
 	return &HospitalService{hospitalRepo: hospitalRepo}
 }
 
-// GetHospitals returns all hospitals in SulTeng (province_id=72)
-func (s *HospitalService) GetHospitals() ([]models.Hospital, error) {
-	return s.hospitalRepo.GetAll(72)
+// GetHospitals returns all hospitals in the request's tenant province
+func (s *HospitalService) GetHospitals(ctx context.Context) ([]models.Hospital, error) {
+	return s.hospitalRepo.GetAll(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
 // GetHospitalsPaginated returns a page of hospitals with total count
-func (s *HospitalService) GetHospitalsPaginated(limit, offset int) ([]models.Hospital, int, error) {
-	return s.hospitalRepo.GetPaginated(72, limit, offset)
+func (s *HospitalService) GetHospitalsPaginated(ctx context.Context, limit, offset int) ([]models.Hospital, int, error) {
+	return s.hospitalRepo.GetPaginated(ctx, tenant.FromContext(ctx).ProvinceNumber(), limit, offset)
 }
 
 // GetHospitalByCode returns a single hospital by code
-func (s *HospitalService) GetHospitalByCode(code string) (*models.Hospital, error) {
-	return s.hospitalRepo.GetByCode(code)
+func (s *HospitalService) GetHospitalByCode(ctx context.Context, code string) (*models.Hospital, error) {
+	return s.hospitalRepo.GetByCode(ctx, code)
 }
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,17 +16,17 @@ type MockHospitalRepository struct {
 	mock.Mock
 }
 
-func (m *MockHospitalRepository) GetAll(provinceID int) ([]models.Hospital, error) {
+func (m *MockHospitalRepository) GetAll(ctx context.Context, provinceID int) ([]models.Hospital, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.Hospital), args.Error(1)
 }
 
-func (m *MockHospitalRepository) GetPaginated(provinceID, limit, offset int) ([]models.Hospital, int, error) {
+func (m *MockHospitalRepository) GetPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.Hospital, int, error) {
 	args := m.Called(provinceID, limit, offset)
 	return args.Get(0).([]models.Hospital), args.Int(1), args.Error(2)
 }
 
-func (m *MockHospitalRepository) GetByCode(code string) (*models.Hospital, error) {
+func (m *MockHospitalRepository) GetByCode(ctx context.Context, code string) (*models.Hospital, error) {
 	args := m.Called(code)
 	result := args.Get(0)
 	if result == nil {
@@ -49,19 +51,31 @@ func TestHospitalService_GetHospitals(t *testing.T) {
 	}
 	mockRepo.On("GetAll", 72).Return(expected, nil)
 
-	result, err := svc.GetHospitals()
+	result, err := svc.GetHospitals(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestHospitalService_GetHospitals_Tenant(t *testing.T) {
+	mockRepo, svc := setupHospitalService()
+	mockRepo.On("GetAll", 75).Return([]models.Hospital{{ID: 3, RegencyID: 7571, Name: "RSUD Aloei Saboe"}}, nil)
+
+	ctx := tenant.NewContext(context.Background(), tenant.Tenant{Slug: "gorontalo", ProvinceID: "75", Name: "Gorontalo"})
+	result, err := svc.GetHospitals(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestHospitalService_GetHospitals_Error(t *testing.T) {
 	mockRepo, svc := setupHospitalService()
 
 	mockRepo.On("GetAll", 72).Return([]models.Hospital{}, errors.New("db error"))
 
-	result, err := svc.GetHospitals()
+	result, err := svc.GetHospitals(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -75,7 +89,7 @@ func TestHospitalService_GetHospitalByCode(t *testing.T) {
 	expected := &models.Hospital{ID: 1, RegencyID: 7201, Name: "RSUD Banggai", HospitalCode: &code}
 	mockRepo.On("GetByCode", code).Return(expected, nil)
 
-	result, err := svc.GetHospitalByCode(code)
+	result, err := svc.GetHospitalByCode(context.Background(), code)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -87,7 +101,7 @@ func TestHospitalService_GetHospitalByCode_NotFound(t *testing.T) {
 
 	mockRepo.On("GetByCode", "NOTFOUND").Return(nil, nil)
 
-	result, err := svc.GetHospitalByCode("NOTFOUND")
+	result, err := svc.GetHospitalByCode(context.Background(), "NOTFOUND")
 
 	assert.NoError(t, err)
 	assert.Nil(t, result)
@@ -99,7 +113,7 @@ func TestHospitalService_GetHospitalByCode_Error(t *testing.T) {
 
 	mockRepo.On("GetByCode", "7201001").Return(nil, errors.New("db error"))
 
-	result, err := svc.GetHospitalByCode("7201001")
+	result, err := svc.GetHospitalByCode(context.Background(), "7201001")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -115,7 +129,7 @@ func TestHospitalService_GetHospitalsPaginated(t *testing.T) {
 	}
 	mockRepo.On("GetPaginated", 72, 10, 0).Return(expected, 1, nil)
 
-	result, total, err := svc.GetHospitalsPaginated(10, 0)
+	result, total, err := svc.GetHospitalsPaginated(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -128,7 +142,7 @@ func TestHospitalService_GetHospitalsPaginated_Error(t *testing.T) {
 
 	mockRepo.On("GetPaginated", 72, 10, 0).Return([]models.Hospital{}, 0, errors.New("db error"))
 
-	result, total, err := svc.GetHospitalsPaginated(10, 0)
+	result, total, err := svc.GetHospitalsPaginated(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+)
+
+// ExpectedIndexes are the indexes the repository queries rely on. They are created
+// by migrations/0005_add_query_indexes.sql; the unique province case key replaced
+// its plain index in 0022_add_province_cases_unique_day.sql.
+var ExpectedIndexes = []models.TableIndex{
+	{Table: "national_cases", Name: "idx_national_cases_day", Columns: []string{"day"}},
+	{Table: "national_cases", Name: "idx_national_cases_date", Columns: []string{"date"}},
+	{Table: "province_cases", Name: "uq_province_cases_province_day", Columns: []string{"province_id", "day"}, Unique: true},
+	{Table: "province_cases", Name: "idx_province_cases_day", Columns: []string{"day"}},
+	{Table: "province_cases", Name: "idx_province_cases_province_date", Columns: []string{"province_id", "date"}},
+	{Table: "regency_cases", Name: "idx_regency_cases_regency_day", Columns: []string{"regency_id", "day"}},
+}
+
+// IndexAdvisor compares the schema's indexes with ExpectedIndexes
+type IndexAdvisor struct {
+	repo     repository.IndexRepository
+	expected []models.TableIndex
+}
+
+func NewIndexAdvisor(repo repository.IndexRepository) *IndexAdvisor {
+	return &IndexAdvisor{repo: repo, expected: ExpectedIndexes}
+}
+
+// MissingIndexes returns the expected indexes that no existing index covers.
+// An existing index with the same leading columns counts, whatever its name.
+func (a *IndexAdvisor) MissingIndexes(ctx context.Context) ([]models.TableIndex, error) {
+	existing, err := a.repo.ListIndexes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check indexes: %w", err)
+	}
+
+	var missing []models.TableIndex
+	for _, want := range a.expected {
+		covered := false
+		for _, have := range existing {
+			if want.CoveredBy(have) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, want)
+		}
+	}
+	return missing, nil
+}
+
+// WarnMissing logs a warning with the CREATE INDEX statement for every missing index.
+// It is meant for startup and never fails the caller.
+func (a *IndexAdvisor) WarnMissing(ctx context.Context) {
+	missing, err := a.MissingIndexes(ctx)
+	if err != nil {
+		log.Printf("Index check skipped: %v", err)
+		return
+	}
+	for _, idx := range missing {
+		create := "CREATE INDEX"
+		if idx.Unique {
+			create = "CREATE UNIQUE INDEX"
+		}
+		log.Printf("WARNING: missing index on %s(%s); create it with: %s %s ON %s (%s);",
+			idx.Table, strings.Join(idx.Columns, ", "), create, idx.Name, idx.Table, strings.Join(idx.Columns, ", "))
+	}
+}
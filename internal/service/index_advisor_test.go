@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIndexRepository struct {
+	mock.Mock
+}
+
+func (m *MockIndexRepository) ListIndexes(ctx context.Context) ([]models.TableIndex, error) {
+	args := m.Called()
+	return args.Get(0).([]models.TableIndex), args.Error(1)
+}
+
+func TestIndexAdvisor_MissingIndexes(t *testing.T) {
+	repo := new(MockIndexRepository)
+	repo.On("ListIndexes").Return([]models.TableIndex{
+		{Table: "national_cases", Name: "PRIMARY", Columns: []string{"id"}},
+		// differently named but with matching leading columns
+		{Table: "province_cases", Name: "legacy_key", Columns: []string{"province_id", "day", "positive"}},
+	}, nil)
+
+	advisor := NewIndexAdvisor(repo)
+	advisor.expected = []models.TableIndex{
+		{Table: "national_cases", Name: "idx_national_cases_date", Columns: []string{"date"}},
+		{Table: "province_cases", Name: "idx_province_cases_province_day", Columns: []string{"province_id", "day"}},
+		{Table: "province_cases", Name: "idx_province_cases_day", Columns: []string{"day"}},
+	}
+
+	missing, err := advisor.MissingIndexes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, missing, 2)
+	assert.Equal(t, "idx_national_cases_date", missing[0].Name)
+	assert.Equal(t, "idx_province_cases_day", missing[1].Name)
+}
+
+func TestIndexAdvisor_MissingIndexes_Unique(t *testing.T) {
+	repo := new(MockIndexRepository)
+	repo.On("ListIndexes").Return([]models.TableIndex{
+		// serves the lookups but does not enforce the key
+		{Table: "province_cases", Name: "idx_province_cases_province_day", Columns: []string{"province_id", "day"}},
+		{Table: "tests", Name: "uq_tests_province_date", Columns: []string{"province_id", "date"}, Unique: true},
+	}, nil)
+
+	advisor := NewIndexAdvisor(repo)
+	advisor.expected = []models.TableIndex{
+		{Table: "province_cases", Name: "uq_province_cases_province_day", Columns: []string{"province_id", "day"}, Unique: true},
+		{Table: "tests", Name: "uq_tests_province_date", Columns: []string{"province_id", "date"}, Unique: true},
+		{Table: "tests", Name: "idx_tests_province", Columns: []string{"province_id"}},
+	}
+
+	missing, err := advisor.MissingIndexes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "uq_province_cases_province_day", missing[0].Name)
+}
+
+func TestIndexAdvisor_MissingIndexes_Error(t *testing.T) {
+	repo := new(MockIndexRepository)
+	repo.On("ListIndexes").Return([]models.TableIndex(nil), errors.New("access denied"))
+
+	_, err := NewIndexAdvisor(repo).MissingIndexes(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+)
+
+// ErrInvalidIngestionBatch wraps validation failures of submitted case data
+var ErrInvalidIngestionBatch = errors.New("invalid ingestion batch")
+
+// ErrDuplicateCase and DuplicateCaseError are re-exported so handlers can map
+// a unique key violation to 409 with the stored row
+var ErrDuplicateCase = repository.ErrDuplicateCase
+
+type DuplicateCaseError = repository.DuplicateCaseError
+
+// ingestionFollowUpQueueSize bounds the committed runs waiting for their
+// cache warm-up, webhooks and alert evaluation
+const ingestionFollowUpQueueSize = 64
+
+// IngestionService writes upstream case data as a tracked sync run
+type IngestionService struct {
+	repo        repository.IngestionRepository
+	invalidator CacheInvalidator
+	warmer      CacheWarmer
+	publisher   WebhookPublisher
+	alerts      AlertEvaluator
+
+	mu        sync.Mutex
+	running   bool
+	followUps chan syncFollowUp
+	done      chan struct{}
+}
+
+// syncFollowUp is the work left after a run that changed rows was committed
+type syncFollowUp struct {
+	source  string
+	summary models.SyncSummary
+}
+
+// NewIngestionService creates an IngestionService. The invalidator, warmer,
+// publisher and alerts may be nil; when set the cache is cleared and
+// pre-rendered, a sync.completed webhook is sent and the alert rules are
+// evaluated after a run that changed any rows. The cache is cleared before
+// Ingest returns; the rest runs in the background once Start is called, and in
+// the caller until then.
+func NewIngestionService(repo repository.IngestionRepository, invalidator CacheInvalidator, warmer CacheWarmer, publisher WebhookPublisher, alerts AlertEvaluator) *IngestionService {
+	return &IngestionService{
+		repo:        repo,
+		invalidator: invalidator,
+		warmer:      warmer,
+		publisher:   publisher,
+		alerts:      alerts,
+		followUps:   make(chan syncFollowUp, ingestionFollowUpQueueSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start runs the follow-up work of committed runs one at a time in the
+// background, in the order the runs were committed.
+func (s *IngestionService) Start() {
+	s.mu.Lock()
+	s.running = true
+	s.mu.Unlock()
+	go func() {
+		defer close(s.done)
+		for f := range s.followUps {
+			s.followUp(context.Background(), f)
+		}
+	}()
+}
+
+// Stop waits for the follow-up work queued before it to finish. Runs
+// committed afterwards do their follow-up work in the caller.
+func (s *IngestionService) Stop() {
+	s.mu.Lock()
+	s.running = false
+	close(s.followUps)
+	s.mu.Unlock()
+	<-s.done
+}
+
+// Ingest validates and upserts the batch. Duplicate keys are reported in the
+// summary's conflicts instead of failing the whole run. With dryRun the rows
+// are written in a transaction that is rolled back, and nothing is cleared,
+// announced or evaluated.
+func (s *IngestionService) Ingest(ctx context.Context, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error) {
+	if err := batch.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIngestionBatch, err)
+	}
+	summary, err := s.repo.Ingest(ctx, batch, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest batch from %s: %w", batch.Source, err)
+	}
+	if dryRun || summary.Inserted+summary.Updated+summary.Restored == 0 {
+		return summary, nil
+	}
+	if s.invalidator != nil {
+		s.invalidator.Clear()
+	}
+
+	f := syncFollowUp{source: batch.Source, summary: *summary}
+	s.mu.Lock()
+	queued := false
+	if s.running {
+		select {
+		case s.followUps <- f:
+			queued = true
+		default:
+			log.Printf("Sync follow-up queue full; running the follow-up of sync run %d in the request", summary.RunID)
+		}
+	}
+	s.mu.Unlock()
+	if !queued {
+		// The rows are committed, so the follow-up work must not be cut short
+		// when the submitting client disconnects
+		s.followUp(context.WithoutCancel(ctx), f)
+	}
+	return summary, nil
+}
+
+// followUp warms the cache, sends the sync.completed webhook and evaluates
+// the alert rules of a committed run
+func (s *IngestionService) followUp(ctx context.Context, f syncFollowUp) {
+	summary := f.summary
+	// Pre-render before announcing the update, so the clients it brings in
+	// are served from memory
+	if s.warmer != nil {
+		if err := s.warmer.Warm(ctx); err != nil {
+			log.Printf("Error warming cache after sync run %d: %v", summary.RunID, err)
+		}
+	}
+	if s.publisher != nil {
+		data := models.SyncCompletedData{
+			RunID:     summary.RunID,
+			Source:    f.source,
+			Inserted:  summary.Inserted,
+			Updated:   summary.Updated,
+			Restored:  summary.Restored,
+			Conflicts: len(summary.Conflicts),
+			Changes:   summary.Changes,
+		}
+		if err := s.publisher.Publish(ctx, models.WebhookEventSyncCompleted, data); err != nil {
+			log.Printf("Error publishing %s webhook for sync run %d: %v", models.WebhookEventSyncCompleted, summary.RunID, err)
+		}
+	}
+	if s.alerts != nil {
+		if _, err := s.alerts.Evaluate(ctx, summary.RunID); err != nil {
+			log.Printf("Error evaluating alert rules for sync run %d: %v", summary.RunID, err)
+		}
+	}
+}
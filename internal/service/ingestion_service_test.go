@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIngestionRepository struct {
+	mock.Mock
+}
+
+func (m *MockIngestionRepository) Ingest(ctx context.Context, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error) {
+	args := m.Called(batch, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SyncSummary), args.Error(1)
+}
+
+func TestIngestionService_Ingest_ClearsCacheOnChanges(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 1, Inserted: 2}, nil)
+	invalidator := new(countingInvalidator)
+
+	summary, err := NewIngestionService(repo, invalidator, nil, nil, nil).Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Inserted)
+	assert.Equal(t, 1, invalidator.clears)
+}
+
+type countingWarmer struct {
+	warms int
+	err   error
+}
+
+func (w *countingWarmer) Warm(ctx context.Context) error {
+	w.warms++
+	return w.err
+}
+
+func TestIngestionService_Ingest_WarmsCacheOnChanges(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 1, Updated: 1}, nil)
+	invalidator := new(countingInvalidator)
+	warmer := &countingWarmer{err: errors.New("db down")}
+
+	_, err := NewIngestionService(repo, invalidator, warmer, nil, nil).Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err, "warming failures are only logged")
+	assert.Equal(t, 1, invalidator.clears)
+	assert.Equal(t, 1, warmer.warms)
+}
+
+func TestIngestionService_Ingest_OnlyConflictsKeepsCache(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	summary := &models.SyncSummary{RunID: 1, Unchanged: 3}
+	summary.AddConflict("national_cases", "day=4", "duplicate key in batch; first occurrence kept")
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(summary, nil)
+	invalidator := new(countingInvalidator)
+
+	result, err := NewIngestionService(repo, invalidator, nil, nil, nil).Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Conflicts, 1)
+	assert.Equal(t, 0, invalidator.clears)
+}
+
+func TestIngestionService_Ingest_RequiresSource(t *testing.T) {
+	repo := new(MockIngestionRepository)
+
+	_, err := NewIngestionService(repo, nil, nil, nil, nil).Ingest(context.Background(), models.IngestionBatch{}, false)
+
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "Ingest", mock.Anything, mock.Anything)
+}
+
+func TestIngestionService_Ingest_Error(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(nil, errors.New("db down"))
+
+	_, err := NewIngestionService(repo, nil, nil, nil, nil).Ingest(context.Background(), batch, false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "db down")
+}
+
+type recordingPublisher struct {
+	events []string
+	data   []interface{}
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event string, data interface{}) error {
+	p.events = append(p.events, event)
+	p.data = append(p.data, data)
+	return nil
+}
+
+func TestIngestionService_Ingest_PublishesSyncCompleted(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 7, Updated: 1}, nil)
+	publisher := new(recordingPublisher)
+
+	_, err := NewIngestionService(repo, nil, nil, publisher, nil).Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.WebhookEventSyncCompleted}, publisher.events)
+	assert.Equal(t, models.SyncCompletedData{RunID: 7, Source: "upstream", Updated: 1}, publisher.data[0])
+}
+
+func TestIngestionService_Ingest_NoChangesPublishesNothing(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 7, Unchanged: 4}, nil)
+	publisher := new(recordingPublisher)
+
+	_, err := NewIngestionService(repo, nil, nil, publisher, nil).Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, publisher.events)
+}
+
+type recordingEvaluator struct{ runs []int64 }
+
+func (e *recordingEvaluator) Evaluate(ctx context.Context, runID int64) ([]models.AlertEvaluation, error) {
+	e.runs = append(e.runs, runID)
+	return nil, nil
+}
+
+func TestIngestionService_Ingest_EvaluatesAlerts(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 7, Inserted: 1}, nil)
+	alerts := new(recordingEvaluator)
+
+	_, err := NewIngestionService(repo, nil, nil, nil, alerts).Ingest(context.Background(), batch, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7}, alerts.runs)
+}
+
+func TestIngestionService_Ingest_RejectsInvalidRows(t *testing.T) {
+	repo := new(MockIngestionRepository)
+
+	_, err := NewIngestionService(repo, nil, nil, nil, nil).Ingest(context.Background(), models.IngestionBatch{
+		Source:   "api",
+		Province: []models.ProvinceCase{{Day: 1, ProvinceID: "72", Positive: 5, CumulativePositive: 2}},
+	}, false)
+
+	assert.ErrorIs(t, err, ErrInvalidIngestionBatch)
+	repo.AssertNotCalled(t, "Ingest", mock.Anything, mock.Anything)
+}
+
+func TestIngestionService_Ingest_DryRunSkipsFollowUp(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, true).Return(&models.SyncSummary{DryRun: true, Inserted: 1}, nil)
+	invalidator := new(countingInvalidator)
+	publisher := new(recordingPublisher)
+
+	summary, err := NewIngestionService(repo, invalidator, nil, publisher, nil).Ingest(context.Background(), batch, true)
+
+	assert.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 0, invalidator.clears)
+	assert.Empty(t, publisher.events)
+}
+
+// blockingPublisher holds every Publish until release is closed
+type blockingPublisher struct {
+	release chan struct{}
+	mu      sync.Mutex
+	runs    []int64
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, event string, data interface{}) error {
+	<-p.release
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runs = append(p.runs, data.(models.SyncCompletedData).RunID)
+	return nil
+}
+
+func TestIngestionService_Ingest_FollowUpInBackground(t *testing.T) {
+	batch := models.IngestionBatch{Source: "upstream"}
+	repo := new(MockIngestionRepository)
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 7, Inserted: 1}, nil).Once()
+	repo.On("Ingest", batch, false).Return(&models.SyncSummary{RunID: 8, Updated: 1}, nil).Once()
+	invalidator := new(countingInvalidator)
+	publisher := &blockingPublisher{release: make(chan struct{})}
+	svc := NewIngestionService(repo, invalidator, nil, publisher, nil)
+	svc.Start()
+
+	// Both return while the webhooks of the first run are still being sent
+	for range 2 {
+		_, err := svc.Ingest(context.Background(), batch, false)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, invalidator.clears)
+
+	close(publisher.release)
+	svc.Stop()
+	assert.Equal(t, []int64{7, 8}, publisher.runs, "Stop waits for the queued follow-ups, in order")
+}
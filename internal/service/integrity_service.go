@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+)
+
+// IntegrityService reports and quarantines province case rows with dangling references
+type IntegrityService struct {
+	repo        repository.IntegrityRepository
+	invalidator CacheInvalidator
+}
+
+// NewIntegrityService creates an IntegrityService. The invalidator may be nil.
+func NewIntegrityService(repo repository.IntegrityRepository, invalidator CacheInvalidator) *IntegrityService {
+	return &IntegrityService{repo: repo, invalidator: invalidator}
+}
+
+func (s *IntegrityService) GetOrphanReport(ctx context.Context) (*models.OrphanReport, error) {
+	orphans, err := s.repo.FindOrphanProvinceCases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check province case integrity: %w", err)
+	}
+	return models.NewOrphanReport(orphans), nil
+}
+
+// QuarantineOrphans moves orphaned province case rows out of province_cases.
+func (s *IntegrityService) QuarantineOrphans(ctx context.Context, dryRun bool) (*models.ChangeSummary, error) {
+	summary, err := s.repo.QuarantineOrphanProvinceCases(ctx, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quarantine orphan province cases: %w", err)
+	}
+	if !dryRun && summary.Deleted > 0 && s.invalidator != nil {
+		s.invalidator.Clear()
+	}
+	return summary, nil
+}
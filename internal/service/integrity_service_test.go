@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIntegrityRepository struct {
+	mock.Mock
+}
+
+func (m *MockIntegrityRepository) FindOrphanProvinceCases(ctx context.Context) ([]models.OrphanProvinceCase, error) {
+	args := m.Called()
+	return args.Get(0).([]models.OrphanProvinceCase), args.Error(1)
+}
+
+func (m *MockIntegrityRepository) QuarantineOrphanProvinceCases(ctx context.Context, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeSummary), args.Error(1)
+}
+
+func TestIntegrityService_GetOrphanReport(t *testing.T) {
+	repo := new(MockIntegrityRepository)
+	repo.On("FindOrphanProvinceCases").Return([]models.OrphanProvinceCase{
+		{ID: 1, Reasons: []string{models.OrphanReasonMissingDay}},
+		{ID: 2, Reasons: []string{models.OrphanReasonMissingDay, models.OrphanReasonMissingProvince}},
+	}, nil)
+
+	report, err := NewIntegrityService(repo, nil).GetOrphanReport(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.MissingDay)
+	assert.Equal(t, 1, report.MissingProvince)
+	assert.Len(t, report.Rows, 2)
+}
+
+func TestIntegrityService_QuarantineOrphans_ClearsCache(t *testing.T) {
+	repo := new(MockIntegrityRepository)
+	repo.On("QuarantineOrphanProvinceCases", false).Return(&models.ChangeSummary{Deleted: 2}, nil)
+	invalidator := new(countingInvalidator)
+
+	_, err := NewIntegrityService(repo, invalidator).QuarantineOrphans(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, invalidator.clears)
+}
+
+func TestIntegrityService_QuarantineOrphans_DryRunKeepsCache(t *testing.T) {
+	repo := new(MockIntegrityRepository)
+	repo.On("QuarantineOrphanProvinceCases", true).Return(&models.ChangeSummary{DryRun: true, Deleted: 2}, nil)
+	invalidator := new(countingInvalidator)
+
+	_, err := NewIntegrityService(repo, invalidator).QuarantineOrphans(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, invalidator.clears)
+}
@@ -1,43 +1,282 @@
 package service
 
-import "github.com/banua-coder/pico-api-go/internal/models"
+import (
+	"context"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/storage"
+)
+
+// ClockSetter is implemented by the services, schedulers and middleware
+// whose behavior depends on the current time
+type ClockSetter interface {
+	SetClock(c clock.Clock)
+}
 
 // RegencyServiceInterface defines the contract for regency operations
 type RegencyServiceInterface interface {
-	GetRegencies() ([]models.Regency, error)
-	GetRegenciesPaginated(limit, offset int) ([]models.Regency, int, error)
-	GetRegencyByID(id int) (*models.Regency, error)
-	GetRegencyCases(regencyID int) ([]models.RegencyCase, error)
-	GetLatestRegencyCases() ([]models.RegencyCase, error)
+	GetRegencies(ctx context.Context) ([]models.Regency, error)
+	GetRegenciesPaginated(ctx context.Context, limit, offset int) ([]models.Regency, int, error)
+	GetRegenciesByProvince(ctx context.Context, provinceID int) ([]models.Regency, error)
+	GetRegencyByID(ctx context.Context, id int) (*models.Regency, error)
+	GetRegencyCases(ctx context.Context, regencyID int) ([]models.RegencyCase, error)
+	GetLatestRegencyCases(ctx context.Context) ([]models.RegencyCase, error)
 }
 
 // HospitalServiceInterface defines the contract for hospital operations
 type HospitalServiceInterface interface {
-	GetHospitals() ([]models.Hospital, error)
-	GetHospitalsPaginated(limit, offset int) ([]models.Hospital, int, error)
-	GetHospitalByCode(code string) (*models.Hospital, error)
+	GetHospitals(ctx context.Context) ([]models.Hospital, error)
+	GetHospitalsPaginated(ctx context.Context, limit, offset int) ([]models.Hospital, int, error)
+	GetHospitalByCode(ctx context.Context, code string) (*models.Hospital, error)
 }
 
 // TaskForceServiceInterface defines the contract for task force operations
 type TaskForceServiceInterface interface {
-	GetTaskForces() ([]models.TaskForceByRegency, error)
-	GetTaskForcesPaginated(limit, offset int) ([]models.TaskForceByRegency, int, error)
+	GetTaskForces(ctx context.Context) ([]models.TaskForceByRegency, error)
+	GetTaskForcesPaginated(ctx context.Context, limit, offset int) ([]models.TaskForceByRegency, int, error)
 }
 
 // VaccinationServiceInterface defines the contract for vaccination operations
 type VaccinationServiceInterface interface {
-	GetNationalVaccinations() ([]models.NationalVaccine, error)
-	GetNationalVaccinationsPaginated(limit, offset int) ([]models.NationalVaccine, int, error)
-	GetProvinceVaccinations() ([]models.ProvinceVaccine, error)
-	GetProvinceVaccinationsPaginated(limit, offset int) ([]models.ProvinceVaccine, int, error)
-	GetVaccineLocations() ([]models.VaccineLocation, error)
-	GetVaccineLocationsPaginated(limit, offset int) ([]models.VaccineLocation, int, error)
+	GetNationalVaccinations(ctx context.Context) ([]models.NationalVaccine, error)
+	GetNationalVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.NationalVaccine, int, error)
+	QueryNationalVaccinations(ctx context.Context, q repository.VaccinationQuery) ([]models.NationalVaccine, int, error)
+	GetProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error)
+	GetProvinceVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.ProvinceVaccine, int, error)
+	QueryProvinceVaccinations(ctx context.Context, provinceID int, q repository.VaccinationQuery) ([]models.ProvinceVaccine, int, error)
+	GetLatestProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error)
+	GetVaccineLocations(ctx context.Context) ([]models.VaccineLocation, error)
+	GetVaccineLocationsPaginated(ctx context.Context, limit, offset int) ([]models.VaccineLocation, int, error)
 }
 
 // ProvinceStatsServiceInterface defines the contract for province stats operations
 type ProvinceStatsServiceInterface interface {
-	GetGenderCases() ([]models.ProvinceGenderCase, error)
-	GetLatestGenderCase() (*models.ProvinceGenderCase, error)
-	GetTests() ([]models.ProvinceTest, error)
-	GetTestTypes() ([]models.TestType, error)
+	GetGenderCases(ctx context.Context) ([]models.ProvinceGenderCase, error)
+	GetLatestGenderCase(ctx context.Context) (*models.ProvinceGenderCase, error)
+	GetTests(ctx context.Context) ([]models.ProvinceTest, error)
+	GetTestTypes(ctx context.Context) ([]models.TestType, error)
+}
+
+// TableStatsProvider exposes the latest table row count and size snapshot
+type TableStatsProvider interface {
+	Snapshot() *models.TableStatsSnapshot
+}
+
+// ReadinessChecker decides whether the API can serve traffic
+type ReadinessChecker interface {
+	Ready(ctx context.Context) models.Readiness
+}
+
+// BackupServiceInterface defines the contract for asynchronous database backups
+type BackupServiceInterface interface {
+	StartBackup() models.BackupJob
+	GetJob(id string) *models.BackupJob
+	ListJobs() []models.BackupJob
+}
+
+// ExportServiceInterface defines the contract for queued case exports
+type ExportServiceInterface interface {
+	StartExport(req ExportRequest) (models.ExportJob, error)
+	GetJob(id string) *models.ExportJob
+	OpenExport(ctx context.Context, id, acceptEncoding string) (*storage.Blob, string, error)
+}
+
+// SyncServiceInterface defines the contract for ingestion run history and rollback
+type SyncServiceInterface interface {
+	ListRuns(ctx context.Context, limit int) ([]models.SyncRun, error)
+	GetRun(ctx context.Context, id int64) (*SyncRunDetail, error)
+	RollbackRun(ctx context.Context, id int64, dryRun bool) (*models.ChangeSummary, error)
+	RollbackLatestRun(ctx context.Context, dryRun bool) (*models.ChangeSummary, error)
+}
+
+// IntegrityServiceInterface defines the contract for referential integrity checks
+type IntegrityServiceInterface interface {
+	GetOrphanReport(ctx context.Context) (*models.OrphanReport, error)
+	QuarantineOrphans(ctx context.Context, dryRun bool) (*models.ChangeSummary, error)
+}
+
+// SoftDeleteServiceInterface defines the contract for hiding and restoring
+// erroneous cases and provinces
+type SoftDeleteServiceInterface interface {
+	DeleteNationalCase(ctx context.Context, day int64, dryRun bool) (*models.ChangeSummary, error)
+	RestoreNationalCase(ctx context.Context, day int64, dryRun bool) (*models.ChangeSummary, error)
+	DeleteProvinceCase(ctx context.Context, provinceID string, day int64, dryRun bool) (*models.ChangeSummary, error)
+	RestoreProvinceCase(ctx context.Context, provinceID string, day int64, dryRun bool) (*models.ChangeSummary, error)
+	DeleteProvince(ctx context.Context, id string, dryRun bool) (*models.ChangeSummary, error)
+	RestoreProvince(ctx context.Context, id string, dryRun bool) (*models.ChangeSummary, error)
+}
+
+// RtEstimationServiceInterface defines the contract for estimating Rt from daily cases
+type RtEstimationServiceInterface interface {
+	Estimate(ctx context.Context, overwrite, dryRun bool) (*models.ChangeSummary, error)
+}
+
+// CaseRevisionServiceInterface defines the contract for the corrections of published cases
+type CaseRevisionServiceInterface interface {
+	GetNationalRevisions(ctx context.Context, day int64) (*models.CaseRevisionHistory, error)
+}
+
+// ReaggregationServiceInterface defines the contract for recomputing the data
+// derived from the case tables
+type ReaggregationServiceInterface interface {
+	Reaggregate(ctx context.Context) models.ReaggregationResult
+	Preview(ctx context.Context) (*models.ChangeSummary, error)
+}
+
+// ConfigReloader rereads the configuration and applies what can change
+// while serving
+type ConfigReloader interface {
+	Reload() (*models.ConfigReload, error)
+}
+
+// RateLimitReporter exposes the state of the rate limiter
+type RateLimitReporter interface {
+	Snapshot() models.RateLimitSnapshot
+}
+
+// DataQualityReporter exposes the anomalies flagged in the case data
+type DataQualityReporter interface {
+	Issues(filter models.QualityIssueFilter) models.DataQualityReport
+	AnnotateNationalCases(cases []models.NationalCase) []models.NationalCase
+	AnnotateProvinceCases(cases []models.ProvinceCaseWithDate) []models.ProvinceCaseWithDate
+}
+
+// LatencyReporter exposes per-route response time percentiles
+type LatencyReporter interface {
+	LatencySummary() []models.RouteLatency
+}
+
+// BandwidthReporter exposes the response bytes served per route and client
+type BandwidthReporter interface {
+	BandwidthSummary() models.BandwidthReport
+}
+
+// AbuseGuard exposes the clients banned by abuse detection
+type AbuseGuard interface {
+	ActiveBans() []models.ClientBan
+	Unban(clientIP string) bool
+}
+
+// WebhookServiceInterface defines the contract for webhook registration and the delivery dashboard
+type WebhookServiceInterface interface {
+	CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscriptionStats, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+}
+
+// CaseStreamInterface defines the contract for subscribing to new case data
+type CaseStreamInterface interface {
+	Subscribe(filter models.WebhookFilter) (<-chan models.WebhookEnvelope, func())
+}
+
+// DashboardHubInterface defines the contract for the realtime dashboard feed
+type DashboardHubInterface interface {
+	Latest(ctx context.Context) (models.DashboardUpdate, error)
+	Subscribe(client string) (<-chan models.DashboardUpdate, func(), error)
+}
+
+// AlertServiceInterface defines the contract for alert rule management
+type AlertServiceInterface interface {
+	ListRules(ctx context.Context) ([]models.AlertRule, error)
+	GetRule(ctx context.Context, id int64) (*models.AlertRule, error)
+	CreateRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error)
+	UpdateRule(ctx context.Context, id int64, rule models.AlertRule) (*models.AlertRule, error)
+	DeleteRule(ctx context.Context, id int64) (bool, error)
+	ListEvaluations(ctx context.Context, ruleID int64, limit int) ([]models.AlertEvaluation, error)
+}
+
+// APIStatusServiceInterface defines the contract for the public status page
+// and its incident notices
+type APIStatusServiceInterface interface {
+	GetStatus(ctx context.Context) (*models.APIStatus, error)
+	ListIncidents(ctx context.Context) ([]models.Incident, error)
+	CreateIncident(ctx context.Context, incident models.Incident) (*models.Incident, error)
+	UpdateIncident(ctx context.Context, id int64, incident models.Incident, resolved bool) (*models.Incident, error)
+	DeleteIncident(ctx context.Context, id int64) (bool, error)
+}
+
+// FreshnessReporter supplies the freshness metadata of list responses
+type FreshnessReporter interface {
+	Describe(rows interface{}) models.DataFreshness
+}
+
+// NoticeBoard supplies the notices added to every API response
+type NoticeBoard interface {
+	ActiveNotices() []models.Notice
+}
+
+// AnnouncementServiceInterface defines the contract for announcement
+// management and the notices they produce
+type AnnouncementServiceInterface interface {
+	NoticeBoard
+	ListAnnouncements(ctx context.Context) ([]models.Announcement, error)
+	CreateAnnouncement(ctx context.Context, announcement models.Announcement) (*models.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, id int64, announcement models.Announcement) (*models.Announcement, error)
+	DeleteAnnouncement(ctx context.Context, id int64) (bool, error)
+}
+
+// CaseNoteServiceInterface defines the contract for managing the analyst
+// notes on case dates and adding them to case rows
+type CaseNoteServiceInterface interface {
+	ListNotes(ctx context.Context, filter models.CaseNoteFilter) ([]models.CaseNote, error)
+	CreateNote(ctx context.Context, note models.CaseNote) (*models.CaseNote, error)
+	UpdateNote(ctx context.Context, id int64, note models.CaseNote) (*models.CaseNote, error)
+	DeleteNote(ctx context.Context, id int64) (bool, error)
+	AnnotateNationalCases(ctx context.Context, cases []models.NationalCase) ([]models.NationalCase, error)
+	AnnotateProvinceCases(ctx context.Context, cases []models.ProvinceCaseWithDate) ([]models.ProvinceCaseWithDate, error)
+}
+
+// IngestionServiceInterface defines the contract for submitting case data
+type IngestionServiceInterface interface {
+	Ingest(ctx context.Context, batch models.IngestionBatch, dryRun bool) (*models.SyncSummary, error)
+}
+
+// SubscriptionServiceInterface defines the contract for public email subscriptions
+type SubscriptionServiceInterface interface {
+	Subscribe(ctx context.Context, email string) error
+	Confirm(ctx context.Context, token string) (*models.EmailSubscription, error)
+	Unsubscribe(ctx context.Context, token string) (*models.EmailSubscription, error)
+}
+
+// ShareServiceInterface defines the contract for short links to filtered views
+type ShareServiceInterface interface {
+	CreateLink(ctx context.Context, filters models.ShareFilters) (*models.ShareLink, error)
+	GetLink(ctx context.Context, code string) (*models.ShareLink, error)
+	DashboardURL(link *models.ShareLink) string
+}
+
+// SummaryServiceInterface defines the contract for the aggregated summary
+type SummaryServiceInterface interface {
+	GetSummary(ctx context.Context) (*models.Summary, error)
+}
+
+// RecapServiceInterface defines the contract for yearly recaps
+type RecapServiceInterface interface {
+	GetRecap(ctx context.Context, year int, provinceID string) (*models.Recap, error)
+}
+
+// ReportServiceInterface defines the contract for situational reports
+type ReportServiceInterface interface {
+	GetDailyReport(ctx context.Context, provinceID string, date time.Time) (*models.DailyReport, error)
+}
+
+// StatusServiceInterface defines the contract for the province status overview
+type StatusServiceInterface interface {
+	GetStatuses(ctx context.Context) (*models.StatusOverview, error)
+}
+
+// TestingServiceInterface defines the contract for national test counts
+type TestingServiceInterface interface {
+	GetNationalTests(ctx context.Context, dates DateRange) ([]models.TestingDay, error)
+}
+
+// PopulationServiceInterface defines the contract for per capita statistics
+type PopulationServiceInterface interface {
+	GetProvincePerCapita(ctx context.Context, provinceID string) (*models.ProvincePerCapita, error)
+	AddPerCapita(ctx context.Context, provinces []models.ProvinceWithLatestCase) ([]models.ProvinceWithLatestCase, error)
 }
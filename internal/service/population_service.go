@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// ErrPopulationNotFound is returned for a province without a population in
+// the configured source
+var ErrPopulationNotFound = errors.New("population not found")
+
+// PopulationService relates case and vaccination counts to the province
+// populations of one source
+type PopulationService struct {
+	populations repository.PopulationRepository
+	covid       CovidService
+	vaccination VaccinationServiceInterface
+	source      string
+}
+
+// NewPopulationService creates a PopulationService reading the populations of
+// source. vaccination may be nil, in which case there are no dose coverages.
+func NewPopulationService(populations repository.PopulationRepository, covid CovidService, vaccination VaccinationServiceInterface, source string) *PopulationService {
+	return &PopulationService{populations: populations, covid: covid, vaccination: vaccination, source: source}
+}
+
+// GetProvincePerCapita returns the per capita statistics of a province's
+// latest case and vaccination days, or ErrPopulationNotFound
+func (s *PopulationService) GetProvincePerCapita(ctx context.Context, provinceID string) (*models.ProvincePerCapita, error) {
+	population, err := s.populations.GetByProvinceID(ctx, provinceID, s.source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get population: %w", err)
+	}
+	if population == nil {
+		return nil, ErrPopulationNotFound
+	}
+
+	result := &models.ProvincePerCapita{Province: models.Province{ID: provinceID}}
+	provinces, err := s.covid.GetProvincesWithLatestCaseByIDs(ctx, []string{provinceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest province case: %w", err)
+	}
+	var latestCase *models.ProvinceCaseResponse
+	if len(provinces) > 0 {
+		result.Province = provinces[0].Province
+		if latestCase = provinces[0].LatestCase; latestCase != nil {
+			result.CaseDate = &latestCase.Date
+		}
+	}
+
+	var vaccine *models.NationalVaccine
+	if s.vaccination != nil {
+		id, err := strconv.Atoi(provinceID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid province id %q: %w", provinceID, err)
+		}
+		rows, _, err := s.vaccination.QueryProvinceVaccinations(ctx, id, repository.VaccinationQuery{
+			Sort:  utils.SortParams{Field: "date", Order: "desc"},
+			Limit: 1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest province vaccination: %w", err)
+		}
+		if len(rows) > 0 {
+			vaccine = &rows[0].NationalVaccine
+			result.VaccinationDate = &vaccine.Date
+		}
+	}
+
+	result.PerCapita = models.NewPerCapita(*population, latestCase, vaccine)
+	return result, nil
+}
+
+// AddPerCapita returns a copy of provinces with the per capita statistics of
+// those with a population in the configured source
+func (s *PopulationService) AddPerCapita(ctx context.Context, provinces []models.ProvinceWithLatestCase) ([]models.ProvinceWithLatestCase, error) {
+	populations, err := s.populations.GetBySource(ctx, s.source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get populations: %w", err)
+	}
+	byProvince := make(map[string]models.ProvincePopulation, len(populations))
+	for _, p := range populations {
+		byProvince[p.ProvinceID] = p
+	}
+
+	vaccines := make(map[string]*models.NationalVaccine)
+	if s.vaccination != nil && len(byProvince) > 0 {
+		latest, err := s.vaccination.GetLatestProvinceVaccinations(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest province vaccinations: %w", err)
+		}
+		for i := range latest {
+			vaccines[strconv.Itoa(latest[i].ProvinceID)] = &latest[i].NationalVaccine
+		}
+	}
+
+	// Copy so the cached province list is not modified
+	result := make([]models.ProvinceWithLatestCase, len(provinces))
+	for i, p := range provinces {
+		if population, ok := byProvince[p.ID]; ok {
+			perCapita := models.NewPerCapita(population, p.LatestCase, vaccines[p.ID])
+			p.PerCapita = &perCapita
+		}
+		result[i] = p
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPopulationRepository struct {
+	mock.Mock
+}
+
+func (m *MockPopulationRepository) GetBySource(ctx context.Context, source string) ([]models.ProvincePopulation, error) {
+	args := m.Called(source)
+	return args.Get(0).([]models.ProvincePopulation), args.Error(1)
+}
+
+func (m *MockPopulationRepository) GetByProvinceID(ctx context.Context, provinceID, source string) (*models.ProvincePopulation, error) {
+	args := m.Called(provinceID, source)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.ProvincePopulation), args.Error(1)
+}
+
+var sultengPopulation = models.ProvincePopulation{ProvinceID: "72", Source: "bps-2020", Year: 2020, Population: 3000000}
+
+func TestPopulationService_GetProvincePerCapita(t *testing.T) {
+	caseDate := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	populations := new(MockPopulationRepository)
+	populations.On("GetByProvinceID", "72", "bps-2020").Return(&sultengPopulation, nil)
+	covid := new(MockCovidService)
+	covid.On("GetProvincesWithLatestCaseByIDs", []string{"72"}).Return([]models.ProvinceWithLatestCase{{
+		Province:   models.Province{ID: "72", Name: "Sulawesi Tengah"},
+		LatestCase: &models.ProvinceCaseResponse{Date: caseDate, Cumulative: models.ProvinceCumulativeCases{Positive: 60000, Deceased: 1500}},
+	}}, nil)
+	vaccinationRepo := new(MockVaccinationRepository)
+	vaccinationRepo.On("QueryProvinceVaccinations", 72, repository.VaccinationQuery{Sort: utils.SortParams{Field: "date", Order: "desc"}, Limit: 1}).
+		Return([]models.ProvinceVaccine{{ProvinceID: 72, NationalVaccine: models.NationalVaccine{
+			Date:                                 caseDate,
+			CumulativeFirstVaccinationReceived:   2400000,
+			CumulativeSecondVaccinationReceived:  1800000,
+			CumulativeBoosterVaccinationReceived: 600000,
+		}}}, 1, nil)
+
+	svc := NewPopulationService(populations, covid, NewVaccinationService(vaccinationRepo), "bps-2020")
+	result, err := svc.GetProvincePerCapita(context.Background(), "72")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Sulawesi Tengah", result.Name)
+	assert.Equal(t, int64(3000000), result.Population)
+	assert.Equal(t, "bps-2020", result.PopulationSource)
+	assert.Equal(t, caseDate, *result.CaseDate)
+	assert.InDelta(t, 2000.0, *result.IncidencePer100k, 1e-9)
+	assert.InDelta(t, 50.0, *result.DeathsPer100k, 1e-9)
+	assert.InDelta(t, 80.0, *result.FirstDoseCoverage, 1e-9)
+	assert.InDelta(t, 60.0, *result.SecondDoseCoverage, 1e-9)
+	assert.InDelta(t, 20.0, *result.BoosterDoseCoverage, 1e-9)
+}
+
+func TestPopulationService_GetProvincePerCapita_NoPopulation(t *testing.T) {
+	populations := new(MockPopulationRepository)
+	populations.On("GetByProvinceID", "99", "bps-2020").Return(nil, nil)
+	populations.On("GetByProvinceID", "72", "bps-2020").Return(nil, errors.New("db down"))
+	svc := NewPopulationService(populations, new(MockCovidService), nil, "bps-2020")
+
+	_, err := svc.GetProvincePerCapita(context.Background(), "99")
+	assert.ErrorIs(t, err, ErrPopulationNotFound)
+
+	_, err = svc.GetProvincePerCapita(context.Background(), "72")
+	assert.ErrorContains(t, err, "failed to get population")
+}
+
+func TestPopulationService_GetProvincePerCapita_WithoutVaccinations(t *testing.T) {
+	populations := new(MockPopulationRepository)
+	populations.On("GetByProvinceID", "72", "bps-2020").Return(&sultengPopulation, nil)
+	covid := new(MockCovidService)
+	covid.On("GetProvincesWithLatestCaseByIDs", []string{"72"}).Return([]models.ProvinceWithLatestCase{}, nil)
+
+	result, err := NewPopulationService(populations, covid, nil, "bps-2020").GetProvincePerCapita(context.Background(), "72")
+
+	require.NoError(t, err)
+	assert.Equal(t, "72", result.ID)
+	assert.Nil(t, result.CaseDate)
+	assert.Nil(t, result.IncidencePer100k)
+	assert.Nil(t, result.FirstDoseCoverage)
+}
+
+func TestPopulationService_AddPerCapita(t *testing.T) {
+	populations := new(MockPopulationRepository)
+	populations.On("GetBySource", "bps-2020").Return([]models.ProvincePopulation{sultengPopulation}, nil)
+	vaccinationRepo := new(MockVaccinationRepository)
+	vaccinationRepo.On("GetLatestProvinceVaccinations").Return([]models.ProvinceVaccine{
+		{ProvinceID: 72, NationalVaccine: models.NationalVaccine{CumulativeFirstVaccinationReceived: 1500000}},
+	}, nil)
+	provinces := []models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "71"}},
+		{Province: models.Province{ID: "72"}, LatestCase: &models.ProvinceCaseResponse{Cumulative: models.ProvinceCumulativeCases{Positive: 30000}}},
+	}
+
+	svc := NewPopulationService(populations, new(MockCovidService), NewVaccinationService(vaccinationRepo), "bps-2020")
+	result, err := svc.AddPerCapita(context.Background(), provinces)
+
+	require.NoError(t, err)
+	assert.Nil(t, result[0].PerCapita, "no population for province 71")
+	require.NotNil(t, result[1].PerCapita)
+	assert.InDelta(t, 1000.0, *result[1].PerCapita.IncidencePer100k, 1e-9)
+	assert.InDelta(t, 50.0, *result[1].PerCapita.FirstDoseCoverage, 1e-9)
+	assert.Nil(t, provinces[1].PerCapita, "the input is not modified")
+}
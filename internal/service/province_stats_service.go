@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 )
 
 type ProvinceStatsService struct {
@@ -13,18 +16,18 @@ func NewProvinceStatsService(repo repository.ProvinceStatsRepositoryInterface) *
 	return &ProvinceStatsService{repo: repo}
 }
 
-func (s *ProvinceStatsService) GetGenderCases() ([]models.ProvinceGenderCase, error) {
-	return s.repo.GetGenderCases(72)
+func (s *ProvinceStatsService) GetGenderCases(ctx context.Context) ([]models.ProvinceGenderCase, error) {
+	return s.repo.GetGenderCases(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
-func (s *ProvinceStatsService) GetLatestGenderCase() (*models.ProvinceGenderCase, error) {
-	return s.repo.GetLatestGenderCase(72)
+func (s *ProvinceStatsService) GetLatestGenderCase(ctx context.Context) (*models.ProvinceGenderCase, error) {
+	return s.repo.GetLatestGenderCase(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
-func (s *ProvinceStatsService) GetTests() ([]models.ProvinceTest, error) {
-	return s.repo.GetTests(72)
+func (s *ProvinceStatsService) GetTests(ctx context.Context) ([]models.ProvinceTest, error) {
+	return s.repo.GetTests(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
-func (s *ProvinceStatsService) GetTestTypes() ([]models.TestType, error) {
-	return s.repo.GetTestTypes()
+func (s *ProvinceStatsService) GetTestTypes(ctx context.Context) ([]models.TestType, error) {
+	return s.repo.GetTestTypes(ctx)
 }
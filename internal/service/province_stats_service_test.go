@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -14,12 +15,12 @@ type MockProvinceStatsRepository struct {
 	mock.Mock
 }
 
-func (m *MockProvinceStatsRepository) GetGenderCases(provinceID int) ([]models.ProvinceGenderCase, error) {
+func (m *MockProvinceStatsRepository) GetGenderCases(ctx context.Context, provinceID int) ([]models.ProvinceGenderCase, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.ProvinceGenderCase), args.Error(1)
 }
 
-func (m *MockProvinceStatsRepository) GetLatestGenderCase(provinceID int) (*models.ProvinceGenderCase, error) {
+func (m *MockProvinceStatsRepository) GetLatestGenderCase(ctx context.Context, provinceID int) (*models.ProvinceGenderCase, error) {
 	args := m.Called(provinceID)
 	result := args.Get(0)
 	if result == nil {
@@ -28,12 +29,12 @@ func (m *MockProvinceStatsRepository) GetLatestGenderCase(provinceID int) (*mode
 	return result.(*models.ProvinceGenderCase), args.Error(1)
 }
 
-func (m *MockProvinceStatsRepository) GetTests(provinceID int) ([]models.ProvinceTest, error) {
+func (m *MockProvinceStatsRepository) GetTests(ctx context.Context, provinceID int) ([]models.ProvinceTest, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.ProvinceTest), args.Error(1)
 }
 
-func (m *MockProvinceStatsRepository) GetTestTypes() ([]models.TestType, error) {
+func (m *MockProvinceStatsRepository) GetTestTypes(ctx context.Context) ([]models.TestType, error) {
 	args := m.Called()
 	return args.Get(0).([]models.TestType), args.Error(1)
 }
@@ -53,7 +54,7 @@ func TestProvinceStatsService_GetGenderCases(t *testing.T) {
 	}
 	mockRepo.On("GetGenderCases", 72).Return(expected, nil)
 
-	result, err := svc.GetGenderCases()
+	result, err := svc.GetGenderCases(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -65,7 +66,7 @@ func TestProvinceStatsService_GetGenderCases_Error(t *testing.T) {
 
 	mockRepo.On("GetGenderCases", 72).Return([]models.ProvinceGenderCase{}, errors.New("db error"))
 
-	result, err := svc.GetGenderCases()
+	result, err := svc.GetGenderCases(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -78,7 +79,7 @@ func TestProvinceStatsService_GetLatestGenderCase(t *testing.T) {
 	expected := &models.ProvinceGenderCase{ID: 2, Day: 2, ProvinceID: 72, PositiveMale: 60, PositiveFemale: 55}
 	mockRepo.On("GetLatestGenderCase", 72).Return(expected, nil)
 
-	result, err := svc.GetLatestGenderCase()
+	result, err := svc.GetLatestGenderCase(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -90,7 +91,7 @@ func TestProvinceStatsService_GetLatestGenderCase_NotFound(t *testing.T) {
 
 	mockRepo.On("GetLatestGenderCase", 72).Return(nil, nil)
 
-	result, err := svc.GetLatestGenderCase()
+	result, err := svc.GetLatestGenderCase(context.Background())
 
 	assert.NoError(t, err)
 	assert.Nil(t, result)
@@ -102,7 +103,7 @@ func TestProvinceStatsService_GetLatestGenderCase_Error(t *testing.T) {
 
 	mockRepo.On("GetLatestGenderCase", 72).Return(nil, errors.New("db error"))
 
-	result, err := svc.GetLatestGenderCase()
+	result, err := svc.GetLatestGenderCase(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -118,7 +119,7 @@ func TestProvinceStatsService_GetTests(t *testing.T) {
 	}
 	mockRepo.On("GetTests", 72).Return(expected, nil)
 
-	result, err := svc.GetTests()
+	result, err := svc.GetTests(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -130,7 +131,7 @@ func TestProvinceStatsService_GetTests_Error(t *testing.T) {
 
 	mockRepo.On("GetTests", 72).Return([]models.ProvinceTest{}, errors.New("db error"))
 
-	result, err := svc.GetTests()
+	result, err := svc.GetTests(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -146,7 +147,7 @@ func TestProvinceStatsService_GetTestTypes(t *testing.T) {
 	}
 	mockRepo.On("GetTestTypes").Return(expected, nil)
 
-	result, err := svc.GetTestTypes()
+	result, err := svc.GetTestTypes(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -158,7 +159,7 @@ func TestProvinceStatsService_GetTestTypes_Error(t *testing.T) {
 
 	mockRepo.On("GetTestTypes").Return([]models.TestType{}, errors.New("db error"))
 
-	result, err := svc.GetTestTypes()
+	result, err := svc.GetTestTypes(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
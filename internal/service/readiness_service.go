@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// cacheWarmTimeout bounds a cache warm started by the readiness probe
+const cacheWarmTimeout = 30 * time.Second
+
+// MigrationMarker is a column, or for migrations that only add triggers a
+// trigger, that only exists once a migration was applied
+type MigrationMarker struct {
+	Migration string
+	Column    models.SchemaColumn
+	Trigger   string
+}
+
+// ExpectedMigrations are the markers of the migrations in migrations/. Keep
+// it in step when adding one.
+var ExpectedMigrations = []MigrationMarker{
+	{"0001_create_sync_log_and_revisions", models.SchemaColumn{Table: "revisions", Column: "id"}, ""},
+	{"0002_add_sync_log_conflicts", models.SchemaColumn{Table: "sync_log", Column: "conflicts"}, ""},
+	{"0003_create_province_cases_quarantine", models.SchemaColumn{Table: "province_cases_quarantine", Column: "quarantine_reason"}, ""},
+	{"0004_add_province_cases_date", models.SchemaColumn{Table: "province_cases", Column: "date"}, ""},
+	{"0006_create_webhooks", models.SchemaColumn{Table: "webhook_deliveries", Column: "id"}, ""},
+	{"0007_add_webhook_delivery_retries", models.SchemaColumn{Table: "webhook_deliveries", Column: "next_attempt_at"}, ""},
+	{"0008_add_webhook_subscription_filters", models.SchemaColumn{Table: "webhook_subscriptions", Column: "filters"}, ""},
+	{"0009_create_alert_rules", models.SchemaColumn{Table: "alert_evaluations", Column: "id"}, ""},
+	{"0010_create_email_subscriptions", models.SchemaColumn{Table: "email_subscriptions", Column: "id"}, ""},
+	{"0011_create_share_links", models.SchemaColumn{Table: "share_links", Column: "id"}, ""},
+	{"0012_add_vaccine_booster_doses", models.SchemaColumn{Table: "province_vaccines", Column: "booster_vaccination_received"}, ""},
+	{"0013_create_tests", models.SchemaColumn{Table: "tests", Column: "id"}, ""},
+	{"0014_create_status_page", models.SchemaColumn{Table: "status_checks", Column: "id"}, ""},
+	{"0015_create_announcements", models.SchemaColumn{Table: "announcements", Column: "id"}, ""},
+	{"0016_create_api_keys", models.SchemaColumn{Table: "api_keys", Column: "id"}, ""},
+	{"0017_add_api_key_tiers", models.SchemaColumn{Table: "api_keys", Column: "tier"}, ""},
+	{"0018_create_webhook_cursors", models.SchemaColumn{Table: "webhook_cursors", Column: "name"}, ""},
+	{"0019_create_province_populations", models.SchemaColumn{Table: "province_populations", Column: "province_id"}, ""},
+	{"0020_create_case_revisions", models.SchemaColumn{Table: "case_revisions", Column: "source"}, ""},
+	{"0021_add_soft_delete", models.SchemaColumn{Table: "province_cases_quarantine", Column: "deleted_at"}, ""},
+	{"0023_create_case_notes", models.SchemaColumn{Table: "case_notes", Column: "id"}, ""},
+	// Province date filters, sorts and cursors rely on its triggers keeping
+	// province_cases.date in sync
+	{"0024_sync_province_cases_date", models.SchemaColumn{}, "national_cases_sync_province_date"},
+}
+
+// ReadinessPolicy decides which failing checks make the API unready rather
+// than degraded
+type ReadinessPolicy struct {
+	// DatabaseGracePeriod is how long failing database pings are tolerated
+	// before the API is unready, so a blip does not take every instance out
+	// of the load balancer at once
+	DatabaseGracePeriod time.Duration
+	// RequireCacheWarm keeps the API unready until the cache was warmed once
+	// after startup. A cache that goes cold later is only degraded.
+	RequireCacheWarm bool
+	// RequireMigrations keeps the API unready while migrations are missing
+	RequireMigrations bool
+}
+
+// ReadinessService checks whether the API can serve traffic: the database
+// answers, the cache was warmed and the migrations were applied
+type ReadinessService struct {
+	pingDB func(ctx context.Context) error
+	schema repository.SchemaRepository
+	// cache is nil when the query cache is disabled
+	cache  WarmCache
+	policy ReadinessPolicy
+	clock  clock.Clock
+
+	mu             sync.Mutex
+	dbFailingSince time.Time
+	warmedOnce     bool
+	warming        bool
+	// migrated is set once every migration was found, since they are not
+	// undone while serving
+	migrated bool
+}
+
+// NewReadinessService creates a ReadinessService. cache may be nil.
+func NewReadinessService(pingDB func(ctx context.Context) error, schema repository.SchemaRepository, cache WarmCache, policy ReadinessPolicy) *ReadinessService {
+	return &ReadinessService{pingDB: pingDB, schema: schema, cache: cache, policy: policy, clock: clock.System}
+}
+
+// SetClock sets the clock the database grace period is measured on
+func (s *ReadinessService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Ready runs every check. The API is ready when none of them is failing.
+func (s *ReadinessService) Ready(ctx context.Context) models.Readiness {
+	checks := []models.ReadinessCheck{s.checkDatabase(ctx)}
+	if s.cache != nil {
+		checks = append(checks, s.checkCache())
+	}
+	checks = append(checks, s.checkMigrations(ctx))
+
+	readiness := models.Readiness{Ready: true, Checks: checks}
+	for _, c := range checks {
+		if c.Status == models.ReadinessFailing {
+			readiness.Ready = false
+		}
+	}
+	return readiness
+}
+
+// checkDatabase pings the database. Failures are degraded until they have
+// lasted for the grace period.
+func (s *ReadinessService) checkDatabase(ctx context.Context) models.ReadinessCheck {
+	check := models.ReadinessCheck{Name: "database", Status: models.ReadinessOK}
+	pingCtx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+	defer cancel()
+	err := s.pingDB(pingCtx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.dbFailingSince = time.Time{}
+		return check
+	}
+	now := s.clock.Now()
+	if s.dbFailingSince.IsZero() {
+		s.dbFailingSince = now
+	}
+	since := s.dbFailingSince.UTC()
+	check.Error, check.Since = err.Error(), &since
+	check.Status = models.ReadinessDegraded
+	if now.Sub(s.dbFailingSince) >= s.policy.DatabaseGracePeriod {
+		check.Status = models.ReadinessFailing
+	}
+	return check
+}
+
+// checkCache reports whether the cache is warm and starts warming it when it
+// is not
+func (s *ReadinessService) checkCache() models.ReadinessCheck {
+	check := models.ReadinessCheck{Name: "cache", Status: models.ReadinessOK}
+	warm := s.cache.IsWarm()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if warm {
+		s.warmedOnce = true
+		return check
+	}
+	check.Error = "cache is not warm yet"
+	check.Status = models.ReadinessDegraded
+	if s.policy.RequireCacheWarm && !s.warmedOnce {
+		check.Status = models.ReadinessFailing
+	}
+	if !s.warming {
+		s.warming = true
+		go s.warm()
+	}
+	return check
+}
+
+func (s *ReadinessService) warm() {
+	ctx, cancel := context.WithTimeout(context.Background(), cacheWarmTimeout)
+	defer cancel()
+	err := s.cache.Warm(ctx)
+	if err != nil {
+		log.Printf("Cache warm for readiness failed: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.warming = false
+	if err == nil {
+		s.warmedOnce = true
+	}
+}
+
+// checkMigrations looks for the marker of every expected migration
+func (s *ReadinessService) checkMigrations(ctx context.Context) models.ReadinessCheck {
+	check := models.ReadinessCheck{Name: "migrations", Status: models.ReadinessOK}
+	s.mu.Lock()
+	migrated := s.migrated
+	s.mu.Unlock()
+	if migrated {
+		return check
+	}
+
+	missing, err := missingMigrations(ctx, s.schema)
+	switch {
+	case err != nil:
+		check.Error = err.Error()
+	case len(missing) > 0:
+		check.Error = "migrations not applied: " + strings.Join(missing, ", ")
+	default:
+		s.mu.Lock()
+		s.migrated = true
+		s.mu.Unlock()
+		return check
+	}
+	check.Status = models.ReadinessDegraded
+	if s.policy.RequireMigrations {
+		check.Status = models.ReadinessFailing
+	}
+	return check
+}
+
+// missingMigrations returns the names of the expected migrations whose marker
+// column or trigger does not exist
+func missingMigrations(ctx context.Context, schema repository.SchemaRepository) ([]string, error) {
+	columns, err := schema.ListColumns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migrations: %w", err)
+	}
+	existing := make(map[models.SchemaColumn]bool, len(columns))
+	for _, c := range columns {
+		existing[models.SchemaColumn{Table: strings.ToLower(c.Table), Column: strings.ToLower(c.Column)}] = true
+	}
+	triggers, err := schema.ListTriggers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migrations: %w", err)
+	}
+	existingTriggers := make(map[string]bool, len(triggers))
+	for _, t := range triggers {
+		existingTriggers[strings.ToLower(t)] = true
+	}
+
+	var missing []string
+	for _, m := range ExpectedMigrations {
+		applied := existing[m.Column]
+		if m.Trigger != "" {
+			applied = existingTriggers[m.Trigger]
+		}
+		if !applied {
+			missing = append(missing, m.Migration)
+		}
+	}
+	return missing, nil
+}
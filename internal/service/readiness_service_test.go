@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSchemaRepository struct {
+	mock.Mock
+}
+
+func (m *MockSchemaRepository) ListColumns(ctx context.Context) ([]models.SchemaColumn, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SchemaColumn), args.Error(1)
+}
+
+func (m *MockSchemaRepository) ListTriggers(ctx context.Context) ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// fakeWarmCache is warm once Warm ran, until it is cleared
+type fakeWarmCache struct {
+	mu    sync.Mutex
+	warm  bool
+	warms int
+}
+
+func (c *fakeWarmCache) Warm(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warm = true
+	c.warms++
+	return nil
+}
+
+func (c *fakeWarmCache) IsWarm() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.warm
+}
+
+func (c *fakeWarmCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warm = false
+}
+
+// appliedMigrations returns the marker columns of the expected migrations
+func appliedMigrations() []models.SchemaColumn {
+	columns := make([]models.SchemaColumn, 0, len(ExpectedMigrations))
+	for _, m := range ExpectedMigrations {
+		if m.Trigger == "" {
+			columns = append(columns, m.Column)
+		}
+	}
+	return columns
+}
+
+// appliedTriggers returns the marker triggers of the expected migrations
+func appliedTriggers() []string {
+	var triggers []string
+	for _, m := range ExpectedMigrations {
+		if m.Trigger != "" {
+			triggers = append(triggers, m.Trigger)
+		}
+	}
+	return triggers
+}
+
+func readinessStatuses(r models.Readiness) map[string]string {
+	statuses := make(map[string]string, len(r.Checks))
+	for _, c := range r.Checks {
+		statuses[c.Name] = c.Status
+	}
+	return statuses
+}
+
+func TestReadinessService_Ready(t *testing.T) {
+	schema := new(MockSchemaRepository)
+	schema.On("ListColumns").Return(appliedMigrations(), nil).Once()
+	schema.On("ListTriggers").Return(appliedTriggers(), nil).Once()
+	svc := NewReadinessService(func(context.Context) error { return nil }, schema, &fakeWarmCache{warm: true},
+		ReadinessPolicy{RequireCacheWarm: true, RequireMigrations: true})
+
+	readiness := svc.Ready(context.Background())
+
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, map[string]string{"database": "ok", "cache": "ok", "migrations": "ok"}, readinessStatuses(readiness))
+	// Applied migrations are not looked up again
+	assert.True(t, svc.Ready(context.Background()).Ready)
+	schema.AssertExpectations(t)
+}
+
+func TestReadinessService_DatabaseGracePeriod(t *testing.T) {
+	schema := new(MockSchemaRepository)
+	schema.On("ListColumns").Return(appliedMigrations(), nil).Once()
+	schema.On("ListTriggers").Return(appliedTriggers(), nil).Once()
+	var pingErr error
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewMock(start)
+	svc := NewReadinessService(func(context.Context) error { return pingErr }, schema, nil,
+		ReadinessPolicy{DatabaseGracePeriod: 30 * time.Second, RequireMigrations: true})
+	svc.SetClock(clk)
+	require.True(t, svc.Ready(context.Background()).Ready)
+
+	pingErr = errors.New("connection refused")
+	readiness := svc.Ready(context.Background())
+	assert.True(t, readiness.Ready, "a blip is tolerated")
+	assert.Equal(t, models.ReadinessDegraded, readiness.Checks[0].Status)
+	assert.Equal(t, "connection refused", readiness.Checks[0].Error)
+	assert.Equal(t, start, *readiness.Checks[0].Since)
+
+	clk.Advance(30 * time.Second)
+	readiness = svc.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, models.ReadinessFailing, readiness.Checks[0].Status)
+
+	pingErr = nil
+	readiness = svc.Ready(context.Background())
+	assert.True(t, readiness.Ready)
+	assert.Nil(t, readiness.Checks[0].Since)
+}
+
+func TestReadinessService_CacheWarm(t *testing.T) {
+	schema := new(MockSchemaRepository)
+	schema.On("ListColumns").Return(appliedMigrations(), nil)
+	schema.On("ListTriggers").Return(appliedTriggers(), nil)
+	cache := &fakeWarmCache{}
+	svc := NewReadinessService(func(context.Context) error { return nil }, schema, cache,
+		ReadinessPolicy{RequireCacheWarm: true})
+
+	readiness := svc.Ready(context.Background())
+	assert.False(t, readiness.Ready, "not ready before the first warm")
+	assert.Equal(t, models.ReadinessFailing, readinessStatuses(readiness)["cache"])
+
+	require.Eventually(t, func() bool { return svc.Ready(context.Background()).Ready }, time.Second, time.Millisecond)
+
+	cache.Clear()
+	readiness = svc.Ready(context.Background())
+	assert.True(t, readiness.Ready, "a cache cleared after startup is only degraded")
+	assert.Equal(t, models.ReadinessDegraded, readinessStatuses(readiness)["cache"])
+	require.Eventually(t, cache.IsWarm, time.Second, time.Millisecond)
+}
+
+func TestReadinessService_MissingMigrations(t *testing.T) {
+	schema := new(MockSchemaRepository)
+	applied := appliedMigrations()
+	// The last column marker and the trigger marker are missing
+	schema.On("ListColumns").Return(applied[:len(applied)-1], nil)
+	schema.On("ListTriggers").Return([]string{"province_cases_fill_date"}, nil)
+	policy := ReadinessPolicy{RequireMigrations: true}
+	svc := NewReadinessService(func(context.Context) error { return nil }, schema, nil, policy)
+
+	readiness := svc.Ready(context.Background())
+
+	assert.False(t, readiness.Ready)
+	missing := ExpectedMigrations[len(ExpectedMigrations)-2:]
+	assert.Equal(t, "migrations not applied: "+missing[0].Migration+", "+missing[1].Migration, readiness.Checks[1].Error)
+
+	policy.RequireMigrations = false
+	svc = NewReadinessService(func(context.Context) error { return nil }, schema, nil, policy)
+	readiness = svc.Ready(context.Background())
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, models.ReadinessDegraded, readiness.Checks[1].Status)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// AggregationTask recomputes one kind of data derived from the case tables.
+// Preview computes what Run would change without applying it; it is nil for
+// tasks that change no data, such as refreshing the cache.
+type AggregationTask struct {
+	Name    string
+	Run     func(ctx context.Context) error
+	Preview func(ctx context.Context) ([]models.RowChange, error)
+}
+
+// ReaggregationService recomputes the data derived from the case tables on
+// demand, for when the tables were changed outside the API
+type ReaggregationService struct {
+	tasks []AggregationTask
+	clock clock.Clock
+}
+
+// NewReaggregationService creates a ReaggregationService running tasks in
+// order
+func NewReaggregationService(tasks ...AggregationTask) *ReaggregationService {
+	return &ReaggregationService{tasks: tasks, clock: clock.System}
+}
+
+// SetClock sets the clock that times the steps
+func (s *ReaggregationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Reaggregate runs every task, also after one fails, and reports how each
+// went
+func (s *ReaggregationService) Reaggregate(ctx context.Context) models.ReaggregationResult {
+	result := models.ReaggregationResult{Steps: make([]models.ReaggregationStep, 0, len(s.tasks))}
+	for _, task := range s.tasks {
+		start := s.clock.Now()
+		step := models.ReaggregationStep{Name: task.Name}
+		if err := task.Run(ctx); err != nil {
+			log.Printf("Re-aggregation step %s failed: %v", task.Name, err)
+			step.Error = err.Error()
+		}
+		step.DurationMs = s.clock.Now().Sub(start).Milliseconds()
+		result.Steps = append(result.Steps, step)
+	}
+	return result
+}
+
+// Preview computes what a re-aggregation would change, stopping at the first
+// task that fails, and changes nothing itself
+func (s *ReaggregationService) Preview(ctx context.Context) (*models.ChangeSummary, error) {
+	summary := &models.ChangeSummary{DryRun: true}
+	for _, task := range s.tasks {
+		if task.Preview == nil {
+			continue
+		}
+		changes, err := task.Preview(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("re-aggregation step %s: %w", task.Name, err)
+		}
+		for _, change := range changes {
+			summary.Add(change)
+		}
+	}
+	return summary, nil
+}
+
+// CacheRefreshTask clears the query cache and pre-renders its busiest
+// responses again; warmer may be nil
+func CacheRefreshTask(invalidator CacheInvalidator, warmer CacheWarmer) AggregationTask {
+	return AggregationTask{Name: "cache", Run: func(ctx context.Context) error {
+		invalidator.Clear()
+		if warmer == nil {
+			return nil
+		}
+		return warmer.Warm(ctx)
+	}}
+}
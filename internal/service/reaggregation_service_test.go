@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubWarmer struct {
+	err   error
+	warms int
+}
+
+func (w *stubWarmer) Warm(ctx context.Context) error {
+	w.warms++
+	return w.err
+}
+
+func TestReaggregationService_Reaggregate(t *testing.T) {
+	var ran []string
+	task := func(name string, err error) AggregationTask {
+		return AggregationTask{Name: name, Run: func(ctx context.Context) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+	svc := NewReaggregationService(task("freshness", nil), task("data_quality", errors.New("db down")), task("stats", nil))
+
+	result := svc.Reaggregate(context.Background())
+
+	// A failed step does not stop the ones after it
+	assert.Equal(t, []string{"freshness", "data_quality", "stats"}, ran)
+	assert.True(t, result.Failed())
+	assert.Len(t, result.Steps, 3)
+	assert.Empty(t, result.Steps[0].Error)
+	assert.Equal(t, "db down", result.Steps[1].Error)
+}
+
+func TestReaggregationService_Preview(t *testing.T) {
+	ran := false
+	svc := NewReaggregationService(
+		AggregationTask{Name: "freshness", Run: func(ctx context.Context) error {
+			ran = true
+			return nil
+		}, Preview: func(ctx context.Context) ([]models.RowChange, error) {
+			return []models.RowChange{{Table: "freshness", Key: "data_source", Action: models.ChangeActionUpdate}}, nil
+		}},
+		AggregationTask{Name: "data_quality", Preview: func(ctx context.Context) ([]models.RowChange, error) {
+			return []models.RowChange{
+				{Table: "data_quality_issues", Key: "national/10/negative_daily/recovered", Action: models.ChangeActionInsert},
+				{Table: "data_quality_issues", Key: "national/25/negative_daily/recovered", Action: models.ChangeActionDelete},
+			}, nil
+		}},
+		// Steps that change no data are not previewed
+		CacheRefreshTask(&countingInvalidator{}, nil),
+	)
+
+	summary, err := svc.Preview(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, ran)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Inserted)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 1, summary.Deleted)
+
+	svc = NewReaggregationService(AggregationTask{Name: "data_quality", Preview: func(ctx context.Context) ([]models.RowChange, error) {
+		return nil, errors.New("db down")
+	}})
+	_, err = svc.Preview(context.Background())
+	assert.EqualError(t, err, "re-aggregation step data_quality: db down")
+}
+
+func TestCacheRefreshTask(t *testing.T) {
+	invalidator := &countingInvalidator{}
+	warmer := &stubWarmer{}
+
+	assert.NoError(t, CacheRefreshTask(invalidator, warmer).Run(context.Background()))
+	assert.Equal(t, 1, invalidator.clears)
+	assert.Equal(t, 1, warmer.warms)
+
+	warmer.err = errors.New("overview failed")
+	assert.EqualError(t, CacheRefreshTask(invalidator, warmer).Run(context.Background()), "overview failed")
+	// Without a warmer the cache is only cleared
+	assert.NoError(t, CacheRefreshTask(invalidator, nil).Run(context.Background()))
+	assert.Equal(t, 3, invalidator.clears)
+}
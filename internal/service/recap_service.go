@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+// ErrRecapNoData is returned when a scope has no case data in the year
+var ErrRecapNoData = errors.New("no case data for this year")
+
+const (
+	// waveThreshold is the share of the year's highest 7-day average of new
+	// positive cases the average has to reach for a wave to start
+	waveThreshold = 0.25
+	// waveEndRatio is the share of the threshold the average has to fall
+	// below for a wave to end, so noise around the threshold is not counted
+	// as several waves
+	waveEndRatio = 0.5
+)
+
+// recapDay is the part of a national or province case row a recap reads
+type recapDay struct {
+	day                                    int64
+	date                                   time.Time
+	positive, recovered, deceased          int64
+	cumPositive, cumRecovered, cumDeceased int64
+	rt                                     *float64
+}
+
+// RecapService builds the yearly recap of the annual retrospective page. It
+// reads through the covid service and therefore shares its cache.
+type RecapService struct {
+	covid       CovidService
+	vaccination VaccinationServiceInterface
+}
+
+// NewRecapService creates a RecapService. vaccination may be nil, in which
+// case recaps have no vaccination progress.
+func NewRecapService(covid CovidService, vaccination VaccinationServiceInterface) *RecapService {
+	return &RecapService{covid: covid, vaccination: vaccination}
+}
+
+// GetRecap summarizes a year nationally, or of a province when provinceID is
+// not empty
+func (s *RecapService) GetRecap(ctx context.Context, year int, provinceID string) (*models.Recap, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	opts := QueryOptions{
+		ProvinceID: provinceID,
+		Range:      DateRange{Start: start, End: end},
+		Sort:       byDateAsc,
+	}
+
+	recap := &models.Recap{Year: year, Scope: models.RecapScopeNational, ProvinceID: provinceID, Waves: []models.RecapWave{}}
+	var days []recapDay
+	if provinceID == "" {
+		cases, _, err := s.covid.ListNationalCases(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get national cases for recap: %w", err)
+		}
+		for _, c := range cases {
+			days = append(days, recapDay{c.Day, c.Date, c.Positive, c.Recovered, c.Deceased,
+				c.CumulativePositive, c.CumulativeRecovered, c.CumulativeDeceased, c.Rt})
+		}
+	} else {
+		recap.Scope = models.RecapScopeProvince
+		cases, _, err := s.covid.ListProvinceCases(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get province cases for recap: %w", err)
+		}
+		for _, c := range cases {
+			days = append(days, recapDay{c.Day, c.Date, c.Positive, c.Recovered, c.Deceased,
+				c.CumulativePositive, c.CumulativeRecovered, c.CumulativeDeceased, c.Rt})
+		}
+	}
+	if len(days) == 0 {
+		return nil, ErrRecapNoData
+	}
+
+	summarizeRecapCases(recap, days)
+	recap.Waves = detectWaves(days)
+	recap.WaveCount = len(recap.Waves)
+
+	vaccination, err := s.vaccinationRecap(ctx, provinceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	recap.Vaccination = vaccination
+	return recap, nil
+}
+
+// summarizeRecapCases fills the totals, worst day, best month and average Rt
+// from days in chronological order
+func summarizeRecapCases(recap *models.Recap, days []recapDay) {
+	recap.Days = len(days)
+	var rtSum float64
+	var rtDays int
+	var months []models.RecapMonth
+	for _, d := range days {
+		recap.NewCases.Positive += d.positive
+		recap.NewCases.Recovered += d.recovered
+		recap.NewCases.Deceased += d.deceased
+		if recap.WorstDay == nil || d.positive > recap.WorstDay.Positive {
+			recap.WorstDay = &models.RecapDay{Day: d.day, Date: d.date, Positive: d.positive}
+		}
+		if d.rt != nil {
+			rtSum += *d.rt
+			rtDays++
+		}
+		month := d.date.Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, models.RecapMonth{Month: month})
+		}
+		m := &months[len(months)-1]
+		m.Positive += d.positive
+		m.Recovered += d.recovered
+		m.Deceased += d.deceased
+	}
+	recap.NewCases.Active = recap.NewCases.Positive - recap.NewCases.Recovered - recap.NewCases.Deceased
+
+	last := days[len(days)-1]
+	recap.Cumulative = models.CaseTotals{
+		Positive:  last.cumPositive,
+		Recovered: last.cumRecovered,
+		Deceased:  last.cumDeceased,
+		Active:    last.cumPositive - last.cumRecovered - last.cumDeceased,
+	}
+	if rtDays > 0 {
+		avg := rtSum / float64(rtDays)
+		recap.AverageRt = &avg
+	}
+	for i := range months {
+		if recap.BestMonth == nil || months[i].Positive < recap.BestMonth.Positive {
+			recap.BestMonth = &months[i]
+		}
+	}
+}
+
+// detectWaves finds the periods in which the 7-day average of new positive
+// cases rose to waveThreshold of its highest value in days
+func detectWaves(days []recapDay) []models.RecapWave {
+	averages := make([]float64, len(days))
+	var sum int64
+	var peak float64
+	for i, d := range days {
+		sum += d.positive
+		if i >= movingAverageDays {
+			sum -= days[i-movingAverageDays].positive
+		}
+		averages[i] = float64(sum) / math.Min(float64(i+1), movingAverageDays)
+		peak = math.Max(peak, averages[i])
+	}
+
+	waves := []models.RecapWave{}
+	if peak == 0 {
+		return waves
+	}
+	threshold := peak * waveThreshold
+	var current *models.RecapWave
+	for i, avg := range averages {
+		switch {
+		case current == nil && avg >= threshold:
+			current = &models.RecapWave{Start: days[i].date, Peak: days[i].date, PeakAverage: avg}
+		case current != nil && avg < threshold*waveEndRatio:
+			end := days[i].date
+			current.End = &end
+			waves = append(waves, *current)
+			current = nil
+		case current != nil && avg > current.PeakAverage:
+			current.Peak = days[i].date
+			current.PeakAverage = avg
+		}
+	}
+	if current != nil {
+		waves = append(waves, *current)
+	}
+	return waves
+}
+
+// vaccinationRecap totals the doses given in the window and reads the
+// cumulative doses of its last recorded day. It returns nil when there is no
+// vaccination data.
+func (s *RecapService) vaccinationRecap(ctx context.Context, provinceID string, start, end time.Time) (*models.RecapVaccination, error) {
+	if s.vaccination == nil {
+		return nil, nil
+	}
+	q := repository.VaccinationQuery{StartDate: start, EndDate: end, Sort: utils.SortParams{Field: "date", Order: "asc"}}
+	var rows []models.NationalVaccine
+	if provinceID == "" {
+		national, _, err := s.vaccination.QueryNationalVaccinations(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get national vaccinations for recap: %w", err)
+		}
+		rows = national
+	} else {
+		id, err := strconv.Atoi(provinceID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid province id %q: %w", provinceID, err)
+		}
+		province, _, err := s.vaccination.QueryProvinceVaccinations(ctx, id, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get province vaccinations for recap: %w", err)
+		}
+		for _, p := range province {
+			rows = append(rows, p.NationalVaccine)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	v := &models.RecapVaccination{}
+	for _, r := range rows {
+		v.FirstDoses += r.FirstVaccinationReceived
+		v.SecondDoses += r.SecondVaccinationReceived
+		v.BoosterDoses += r.BoosterVaccinationReceived
+	}
+	last := rows[len(rows)-1]
+	v.CumulativeFirstDoses = last.CumulativeFirstVaccinationReceived
+	v.CumulativeSecondDoses = last.CumulativeSecondVaccinationReceived
+	v.CumulativeBoosterDoses = last.CumulativeBoosterVaccinationReceived
+	v.Target = last.TotalVaccinationTarget
+	v.FirstDoseCoverage = recapCoverage(v.CumulativeFirstDoses, v.Target)
+	v.SecondDoseCoverage = recapCoverage(v.CumulativeSecondDoses, v.Target)
+	v.BoosterDoseCoverage = recapCoverage(v.CumulativeBoosterDoses, v.Target)
+	return v, nil
+}
+
+// recapCoverage returns cumulative as a percentage of target rounded to two
+// decimals, like the coverage of the vaccination endpoints
+func recapCoverage(cumulative, target int64) float64 {
+	if target == 0 {
+		return 0
+	}
+	return math.Round(float64(cumulative)/float64(target)*10000) / 100
+}
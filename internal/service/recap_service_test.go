@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recapNationalCases returns 45 days from 2021-01-01 with two surges of new
+// positive cases: days 10-19 and days 35-44
+func recapNationalCases() []models.NationalCase {
+	var cases []models.NationalCase
+	var cumulative int64
+	for i := 0; i < 45; i++ {
+		var positive int64
+		switch {
+		case i < 10:
+			positive = 10
+		case i < 20, i >= 35:
+			positive = 200
+		}
+		cumulative += positive
+		c := models.NationalCase{
+			Day: int64(i + 1), Date: time.Date(2021, 1, 1+i, 0, 0, 0, 0, time.UTC),
+			Positive: positive, Recovered: 1, CumulativePositive: cumulative, CumulativeRecovered: int64(i + 1),
+		}
+		if i < 2 {
+			rt := 1.0 + float64(i)
+			c.Rt = &rt
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+func TestRecapService_GetRecap_National(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListNationalCases", QueryOptions{Range: testDateRange("2021-01-01", "2021-12-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return(recapNationalCases(), 0, nil)
+	mockRepo := new(MockVaccinationRepository)
+	mockRepo.On("QueryNationalVaccinations", repository.VaccinationQuery{
+		StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+		Sort:      utils.SortParams{Field: "date", Order: "asc"},
+	}).Return([]models.NationalVaccine{
+		{FirstVaccinationReceived: 100, TotalVaccinationTarget: 1000},
+		{FirstVaccinationReceived: 50, SecondVaccinationReceived: 20, TotalVaccinationTarget: 1000,
+			CumulativeFirstVaccinationReceived: 400, CumulativeSecondVaccinationReceived: 120},
+	}, 2, nil)
+
+	recap, err := NewRecapService(mockSvc, NewVaccinationService(mockRepo)).GetRecap(context.Background(), 2021, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.RecapScopeNational, recap.Scope)
+	assert.Equal(t, 45, recap.Days)
+	assert.Equal(t, models.CaseTotals{Positive: 4100, Recovered: 45, Active: 4055}, recap.NewCases)
+	assert.Equal(t, int64(4100), recap.Cumulative.Positive)
+	require.NotNil(t, recap.WorstDay)
+	assert.Equal(t, int64(11), recap.WorstDay.Day, "the first of equally bad days wins")
+	require.NotNil(t, recap.BestMonth)
+	assert.Equal(t, "2021-02", recap.BestMonth.Month)
+	assert.Equal(t, int64(2000), recap.BestMonth.Positive)
+	require.NotNil(t, recap.AverageRt)
+	assert.InDelta(t, 1.5, *recap.AverageRt, 0.0001)
+
+	require.Equal(t, 2, recap.WaveCount)
+	assert.Equal(t, time.Date(2021, 1, 12, 0, 0, 0, 0, time.UTC), recap.Waves[0].Start)
+	require.NotNil(t, recap.Waves[0].End)
+	assert.Equal(t, time.Date(2021, 1, 27, 0, 0, 0, 0, time.UTC), *recap.Waves[0].End)
+	assert.InDelta(t, 200, recap.Waves[0].PeakAverage, 0.0001)
+	assert.Nil(t, recap.Waves[1].End, "the second wave lasts past the data")
+
+	require.NotNil(t, recap.Vaccination)
+	assert.Equal(t, int64(150), recap.Vaccination.FirstDoses)
+	assert.Equal(t, int64(400), recap.Vaccination.CumulativeFirstDoses)
+	assert.Equal(t, 40.0, recap.Vaccination.FirstDoseCoverage)
+	assert.Equal(t, 12.0, recap.Vaccination.SecondDoseCoverage)
+}
+
+func TestRecapService_GetRecap_Province(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "72", Range: testDateRange("2022-01-01", "2022-12-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{Day: 700, ProvinceID: "72", Positive: 5, CumulativePositive: 500}, Date: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}, 0, nil)
+	mockRepo := new(MockVaccinationRepository)
+	mockRepo.On("QueryProvinceVaccinations", 72, repository.VaccinationQuery{
+		StartDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+		Sort:      utils.SortParams{Field: "date", Order: "asc"},
+	}).Return([]models.ProvinceVaccine{}, 0, nil)
+
+	recap, err := NewRecapService(mockSvc, NewVaccinationService(mockRepo)).GetRecap(context.Background(), 2022, "72")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.RecapScopeProvince, recap.Scope)
+	assert.Equal(t, "72", recap.ProvinceID)
+	assert.Equal(t, int64(500), recap.Cumulative.Positive)
+	assert.Nil(t, recap.AverageRt)
+	assert.Equal(t, 1, recap.WaveCount)
+	assert.Nil(t, recap.Vaccination)
+}
+
+func TestRecapService_GetRecap_NoData(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListNationalCases", QueryOptions{Range: testDateRange("2020-01-01", "2020-12-31"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.NationalCase{}, 0, nil)
+
+	_, err := NewRecapService(mockSvc, nil).GetRecap(context.Background(), 2020, "")
+	assert.True(t, errors.Is(err, ErrRecapNoData))
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 )
 
 // RegencyService handles business logic for regencies
@@ -19,27 +22,32 @@ func NewRegencyService(regencyRepo repository.RegencyRepositoryInterface, regenc
 	}
 }
 
-// GetRegencies returns all regencies for SulTeng (province_id=72)
-func (s *RegencyService) GetRegencies() ([]models.Regency, error) {
-	return s.regencyRepo.GetAll(72)
+// GetRegencies returns all regencies for the request's tenant province
+func (s *RegencyService) GetRegencies(ctx context.Context) ([]models.Regency, error) {
+	return s.regencyRepo.GetAll(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
 // GetRegenciesPaginated returns a page of regencies with total count
-func (s *RegencyService) GetRegenciesPaginated(limit, offset int) ([]models.Regency, int, error) {
-	return s.regencyRepo.GetPaginated(72, limit, offset)
+func (s *RegencyService) GetRegenciesPaginated(ctx context.Context, limit, offset int) ([]models.Regency, int, error) {
+	return s.regencyRepo.GetPaginated(ctx, tenant.FromContext(ctx).ProvinceNumber(), limit, offset)
+}
+
+// GetRegenciesByProvince returns all regencies (districts) of any province
+func (s *RegencyService) GetRegenciesByProvince(ctx context.Context, provinceID int) ([]models.Regency, error) {
+	return s.regencyRepo.GetAll(ctx, provinceID)
 }
 
 // GetRegencyByID returns a single regency
-func (s *RegencyService) GetRegencyByID(id int) (*models.Regency, error) {
-	return s.regencyRepo.GetByID(id)
+func (s *RegencyService) GetRegencyByID(ctx context.Context, id int) (*models.Regency, error) {
+	return s.regencyRepo.GetByID(ctx, id)
 }
 
 // GetRegencyCases returns all cases for a regency
-func (s *RegencyService) GetRegencyCases(regencyID int) ([]models.RegencyCase, error) {
-	return s.regencyCaseRepo.GetByRegencyID(regencyID)
+func (s *RegencyService) GetRegencyCases(ctx context.Context, regencyID int) ([]models.RegencyCase, error) {
+	return s.regencyCaseRepo.GetByRegencyID(ctx, regencyID)
 }
 
 // GetLatestRegencyCases returns latest case for each regency
-func (s *RegencyService) GetLatestRegencyCases() ([]models.RegencyCase, error) {
-	return s.regencyCaseRepo.GetLatestByProvinceID(72)
+func (s *RegencyService) GetLatestRegencyCases(ctx context.Context) ([]models.RegencyCase, error) {
+	return s.regencyCaseRepo.GetLatestByProvinceID(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
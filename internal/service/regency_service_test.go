@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -14,17 +15,17 @@ type MockRegencyRepository struct {
 	mock.Mock
 }
 
-func (m *MockRegencyRepository) GetAll(provinceID int) ([]models.Regency, error) {
+func (m *MockRegencyRepository) GetAll(ctx context.Context, provinceID int) ([]models.Regency, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.Regency), args.Error(1)
 }
 
-func (m *MockRegencyRepository) GetPaginated(provinceID, limit, offset int) ([]models.Regency, int, error) {
+func (m *MockRegencyRepository) GetPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.Regency, int, error) {
 	args := m.Called(provinceID, limit, offset)
 	return args.Get(0).([]models.Regency), args.Int(1), args.Error(2)
 }
 
-func (m *MockRegencyRepository) GetByID(id int) (*models.Regency, error) {
+func (m *MockRegencyRepository) GetByID(ctx context.Context, id int) (*models.Regency, error) {
 	args := m.Called(id)
 	result := args.Get(0)
 	if result == nil {
@@ -38,12 +39,12 @@ type MockRegencyCaseRepository struct {
 	mock.Mock
 }
 
-func (m *MockRegencyCaseRepository) GetByRegencyID(regencyID int) ([]models.RegencyCase, error) {
+func (m *MockRegencyCaseRepository) GetByRegencyID(ctx context.Context, regencyID int) ([]models.RegencyCase, error) {
 	args := m.Called(regencyID)
 	return args.Get(0).([]models.RegencyCase), args.Error(1)
 }
 
-func (m *MockRegencyCaseRepository) GetLatestByProvinceID(provinceID int) ([]models.RegencyCase, error) {
+func (m *MockRegencyCaseRepository) GetLatestByProvinceID(ctx context.Context, provinceID int) ([]models.RegencyCase, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.RegencyCase), args.Error(1)
 }
@@ -64,7 +65,7 @@ func TestRegencyService_GetRegencies(t *testing.T) {
 	}
 	mockRepo.On("GetAll", 72).Return(expected, nil)
 
-	result, err := svc.GetRegencies()
+	result, err := svc.GetRegencies(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -76,7 +77,7 @@ func TestRegencyService_GetRegencies_Error(t *testing.T) {
 
 	mockRepo.On("GetAll", 72).Return([]models.Regency{}, errors.New("db error"))
 
-	result, err := svc.GetRegencies()
+	result, err := svc.GetRegencies(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -89,7 +90,7 @@ func TestRegencyService_GetRegencyByID(t *testing.T) {
 	expected := &models.Regency{ID: 7201, ProvinceID: 72, Name: "Kabupaten Banggai"}
 	mockRepo.On("GetByID", 7201).Return(expected, nil)
 
-	result, err := svc.GetRegencyByID(7201)
+	result, err := svc.GetRegencyByID(context.Background(), 7201)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -101,7 +102,7 @@ func TestRegencyService_GetRegencyByID_NotFound(t *testing.T) {
 
 	mockRepo.On("GetByID", 9999).Return(nil, nil)
 
-	result, err := svc.GetRegencyByID(9999)
+	result, err := svc.GetRegencyByID(context.Background(), 9999)
 
 	assert.NoError(t, err)
 	assert.Nil(t, result)
@@ -113,7 +114,7 @@ func TestRegencyService_GetRegencyByID_Error(t *testing.T) {
 
 	mockRepo.On("GetByID", 7201).Return(nil, errors.New("db error"))
 
-	result, err := svc.GetRegencyByID(7201)
+	result, err := svc.GetRegencyByID(context.Background(), 7201)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -128,7 +129,7 @@ func TestRegencyService_GetRegencyCases(t *testing.T) {
 	}
 	mockCaseRepo.On("GetByRegencyID", 7201).Return(expected, nil)
 
-	result, err := svc.GetRegencyCases(7201)
+	result, err := svc.GetRegencyCases(context.Background(), 7201)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -140,7 +141,7 @@ func TestRegencyService_GetRegencyCases_Error(t *testing.T) {
 
 	mockCaseRepo.On("GetByRegencyID", 7201).Return([]models.RegencyCase{}, errors.New("db error"))
 
-	result, err := svc.GetRegencyCases(7201)
+	result, err := svc.GetRegencyCases(context.Background(), 7201)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -156,7 +157,7 @@ func TestRegencyService_GetLatestRegencyCases(t *testing.T) {
 	}
 	mockCaseRepo.On("GetLatestByProvinceID", 72).Return(expected, nil)
 
-	result, err := svc.GetLatestRegencyCases()
+	result, err := svc.GetLatestRegencyCases(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -168,7 +169,7 @@ func TestRegencyService_GetLatestRegencyCases_Error(t *testing.T) {
 
 	mockCaseRepo.On("GetLatestByProvinceID", 72).Return([]models.RegencyCase{}, errors.New("db error"))
 
-	result, err := svc.GetLatestRegencyCases()
+	result, err := svc.GetLatestRegencyCases(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -183,7 +184,7 @@ func TestRegencyService_GetRegenciesPaginated(t *testing.T) {
 	}
 	mockRepo.On("GetPaginated", 72, 10, 0).Return(expected, 1, nil)
 
-	result, total, err := svc.GetRegenciesPaginated(10, 0)
+	result, total, err := svc.GetRegenciesPaginated(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -196,10 +197,23 @@ func TestRegencyService_GetRegenciesPaginated_Error(t *testing.T) {
 
 	mockRepo.On("GetPaginated", 72, 10, 0).Return([]models.Regency{}, 0, errors.New("db error"))
 
-	result, total, err := svc.GetRegenciesPaginated(10, 0)
+	result, total, err := svc.GetRegenciesPaginated(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
 	assert.Equal(t, 0, total)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestRegencyService_GetRegenciesByProvince(t *testing.T) {
+	mockRepo, _, svc := setupRegencyService()
+
+	expected := []models.Regency{{ID: 7371, ProvinceID: 73, Name: "Kota Makassar"}}
+	mockRepo.On("GetAll", 73).Return(expected, nil)
+
+	result, err := svc.GetRegenciesByProvince(context.Background(), 73)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockRepo.AssertExpectations(t)
+}
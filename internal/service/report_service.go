@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// ErrReportNoData is returned when a province has no case data for the
+// report date
+var ErrReportNoData = errors.New("no case data for this date")
+
+// reportWeekDays is the length of the weeks a report compares
+const reportWeekDays = 7
+
+// ReportService builds the daily situational reports sent to health offices.
+// It reads through the covid service and therefore shares its cache.
+type ReportService struct {
+	covid CovidService
+}
+
+// NewReportService creates a ReportService
+func NewReportService(covid CovidService) *ReportService {
+	return &ReportService{covid: covid}
+}
+
+// GetDailyReport returns the report of a province for date, or for its
+// latest day with data when date is zero
+func (s *ReportService) GetDailyReport(ctx context.Context, provinceID string, date time.Time) (*models.DailyReport, error) {
+	end := date
+	if date.IsZero() {
+		latest, _, err := s.covid.ListProvinceCases(ctx, QueryOptions{ProvinceID: provinceID, Sort: byDateDesc, Page: Page{Limit: 1}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest province case for report: %w", err)
+		}
+		if len(latest) == 0 {
+			return nil, ErrReportNoData
+		}
+		end = latest[0].Date
+	}
+
+	start := end.AddDate(0, 0, -(2*reportWeekDays - 1))
+	cases, _, err := s.covid.ListProvinceCases(ctx, QueryOptions{
+		ProvinceID: provinceID,
+		Range:      DateRange{Start: start, End: end},
+		Sort:       byDateAsc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get province cases for report: %w", err)
+	}
+	if len(cases) == 0 || !sameDay(cases[len(cases)-1].Date, end) {
+		return nil, ErrReportNoData
+	}
+	return newDailyReport(provinceID, cases, end), nil
+}
+
+// newDailyReport builds the report of the last of cases, which are in
+// chronological order and end on end
+func newDailyReport(provinceID string, cases []models.ProvinceCaseWithDate, end time.Time) *models.DailyReport {
+	last := cases[len(cases)-1]
+	report := &models.DailyReport{
+		ProvinceID: provinceID,
+		Day:        last.Day,
+		Date:       last.Date,
+		NewCases: models.CaseTotals{
+			Positive:  last.Positive,
+			Recovered: last.Recovered,
+			Deceased:  last.Deceased,
+			Active:    last.Positive - last.Recovered - last.Deceased,
+		},
+		Cumulative: models.CaseTotals{
+			Positive:  last.CumulativePositive,
+			Recovered: last.CumulativeRecovered,
+			Deceased:  last.CumulativeDeceased,
+			Active:    last.CumulativePositive - last.CumulativeRecovered - last.CumulativeDeceased,
+		},
+		Trend: make([]models.ReportDay, 0, len(cases)),
+	}
+	if last.Province != nil {
+		report.ProvinceName = last.Province.Name
+	}
+	if last.Rt != nil {
+		report.Rt = &models.ReproductionRate{Value: last.Rt, UpperBound: last.RtUpper, LowerBound: last.RtLower}
+	}
+
+	weekStart := end.AddDate(0, 0, -(reportWeekDays - 1))
+	previousWeekStart := weekStart.AddDate(0, 0, -reportWeekDays)
+	previousWeekComplete := false
+	for _, c := range cases {
+		report.Trend = append(report.Trend, models.ReportDay{Date: c.Date, Positive: c.Positive, Rt: c.Rt})
+		if c.Date.Before(weekStart) {
+			report.PreviousWeekPositive += c.Positive
+			previousWeekComplete = previousWeekComplete || sameDay(c.Date, previousWeekStart)
+		} else {
+			report.WeekPositive += c.Positive
+		}
+	}
+	if previousWeekComplete && report.PreviousWeekPositive > 0 {
+		change := float64(report.WeekPositive-report.PreviousWeekPositive) / float64(report.PreviousWeekPositive) * 100
+		report.WeekChangePercent = &change
+	}
+	return report
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportCases returns days cases of Sulawesi Tengah ending on 2021-07-14,
+// with 10 new positive cases a day before the last week and 15 in it
+func reportCases(days int) []models.ProvinceCaseWithDate {
+	end := time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)
+	var cases []models.ProvinceCaseWithDate
+	for i := days - 1; i >= 0; i-- {
+		positive := int64(10)
+		if i < 7 {
+			positive = 15
+		}
+		cases = append(cases, models.ProvinceCaseWithDate{
+			ProvinceCase: models.ProvinceCase{
+				Day: int64(500 - i), ProvinceID: "72", Positive: positive, Recovered: 4, Deceased: 1,
+				CumulativePositive: 1000, CumulativeRecovered: 800, CumulativeDeceased: 30,
+				Province: &models.Province{ID: "72", Name: "Sulawesi Tengah"},
+			},
+			Date: end.AddDate(0, 0, -i),
+		})
+	}
+	return cases
+}
+
+func TestReportService_GetDailyReport(t *testing.T) {
+	asc := utils.SortParams{Field: "date", Order: "asc"}
+	cases := reportCases(14)
+	rt := 1.08
+	cases[13].Rt = &rt
+
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "72", Range: testDateRange("2021-07-01", "2021-07-14"), Sort: asc}).Return(cases, len(cases), nil)
+
+	report, err := NewReportService(mockSvc).GetDailyReport(context.Background(), "72", time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "Sulawesi Tengah", report.ProvinceName)
+	assert.Equal(t, int64(500), report.Day)
+	assert.Equal(t, models.CaseTotals{Positive: 15, Recovered: 4, Deceased: 1, Active: 10}, report.NewCases)
+	assert.Equal(t, int64(170), report.Cumulative.Active)
+	assert.Equal(t, &rt, report.Rt.Value)
+	assert.Equal(t, int64(105), report.WeekPositive)
+	assert.Equal(t, int64(70), report.PreviousWeekPositive)
+	require.NotNil(t, report.WeekChangePercent)
+	assert.InDelta(t, 50.0, *report.WeekChangePercent, 0.001)
+	assert.Len(t, report.Trend, 14)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestReportService_GetDailyReport_Latest(t *testing.T) {
+	cases := reportCases(10)
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "desc"}, Page: Page{Limit: 1}}).Return(cases[9:], 1, nil)
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "72", Range: testDateRange("2021-07-01", "2021-07-14"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return(cases, len(cases), nil)
+
+	report, err := NewReportService(mockSvc).GetDailyReport(context.Background(), "72", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC), report.Date)
+	assert.Nil(t, report.Rt)
+	// The previous week lacks its first days
+	assert.Equal(t, int64(30), report.PreviousWeekPositive)
+	assert.Nil(t, report.WeekChangePercent)
+}
+
+func TestReportService_GetDailyReport_NoData(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	// The range has data, but not for the report date
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "72", Range: testDateRange("2021-07-02", "2021-07-15"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).Return(reportCases(14), 0, nil)
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "99", Sort: utils.SortParams{Field: "date", Order: "desc"}, Page: Page{Limit: 1}}).Return([]models.ProvinceCaseWithDate{}, 0, nil)
+	mockSvc.On("ListProvinceCases", QueryOptions{ProvinceID: "73", Range: testDateRange("2021-07-01", "2021-07-14"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.ProvinceCaseWithDate(nil), 0, errors.New("db down"))
+
+	svc := NewReportService(mockSvc)
+	_, err := svc.GetDailyReport(context.Background(), "72", time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrReportNoData)
+	_, err = svc.GetDailyReport(context.Background(), "99", time.Time{})
+	assert.ErrorIs(t, err, ErrReportNoData)
+	_, err = svc.GetDailyReport(context.Background(), "73", time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrReportNoData)
+}
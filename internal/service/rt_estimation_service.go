@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/epi"
+)
+
+// RtEstimationService estimates Rt from the daily positive cases for the
+// national and province rows whose upstream data has none
+type RtEstimationService struct {
+	repo        repository.RtEstimateRepository
+	cfg         epi.Config
+	invalidator CacheInvalidator
+	clock       clock.Clock
+	stopChan    chan struct{}
+}
+
+// NewRtEstimationService creates an RtEstimationService, failing on an
+// unusable serial interval or window. The invalidator may be nil.
+func NewRtEstimationService(repo repository.RtEstimateRepository, cfg epi.Config, invalidator CacheInvalidator) (*RtEstimationService, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rt estimation config: %w", err)
+	}
+	return &RtEstimationService{repo: repo, cfg: cfg, invalidator: invalidator, clock: clock.System, stopChan: make(chan struct{})}, nil
+}
+
+// SetClock sets the clock of the estimation schedule
+func (s *RtEstimationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Estimate estimates Rt for every case series and writes the estimates of
+// rows without an Rt, or of all rows with overwrite. With dryRun the changes
+// are reported but not committed.
+func (s *RtEstimationService) Estimate(ctx context.Context, overwrite, dryRun bool) (*models.ChangeSummary, error) {
+	series, err := s.repo.GetCaseSeries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get case series: %w", err)
+	}
+
+	var estimates []models.RtEstimate
+	for _, cs := range series {
+		seriesEstimates, err := s.estimateSeries(cs, overwrite)
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, seriesEstimates...)
+	}
+	if len(estimates) == 0 {
+		return &models.ChangeSummary{DryRun: dryRun}, nil
+	}
+
+	summary, err := s.repo.SaveEstimates(ctx, estimates, overwrite, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save rt estimates: %w", err)
+	}
+	if !dryRun && summary.Updated > 0 && s.invalidator != nil {
+		s.invalidator.Clear()
+	}
+	return summary, nil
+}
+
+// estimateSeries returns the estimates of the days of cs to write. Days
+// missing from the series count as days without new cases, and negative
+// counts, corrections of earlier days, as zero.
+func (s *RtEstimationService) estimateSeries(cs models.CaseSeries, overwrite bool) ([]models.RtEstimate, error) {
+	if len(cs.Days) == 0 {
+		return nil, nil
+	}
+	start := cs.Days[0].Date
+	last := cs.Days[len(cs.Days)-1].Date
+	incidence := make([]float64, daysBetween(start, last)+1)
+	for _, d := range cs.Days {
+		incidence[daysBetween(start, d.Date)] += math.Max(0, float64(d.Positive))
+	}
+
+	estimated, err := epi.EstimateRt(incidence, s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	var estimates []models.RtEstimate
+	for _, d := range cs.Days {
+		e := estimated[daysBetween(start, d.Date)]
+		if e == nil || (d.HasRt && !overwrite) {
+			continue
+		}
+		estimates = append(estimates, models.RtEstimate{
+			ProvinceID: cs.ProvinceID,
+			Day:        d.Day,
+			Rt:         roundRt(e.Mean),
+			RtLower:    roundRt(e.Lower),
+			RtUpper:    roundRt(e.Upper),
+		})
+	}
+	return estimates, nil
+}
+
+// daysBetween counts the calendar days from start to end
+func daysBetween(start, end time.Time) int {
+	return int(math.Round(end.Sub(start).Hours() / 24))
+}
+
+// roundRt rounds to the two decimals Rt is published with
+func roundRt(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Start estimates missing Rt values immediately and then at the given
+// interval in a background goroutine.
+func (s *RtEstimationService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		s.estimateMissing(ctx)
+
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				s.estimateMissing(ctx)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (s *RtEstimationService) estimateMissing(ctx context.Context) {
+	summary, err := s.Estimate(ctx, false, false)
+	if err != nil {
+		log.Printf("Rt estimation failed: %v", err)
+		return
+	}
+	if summary.Updated > 0 {
+		log.Printf("Estimated Rt for %d case rows", summary.Updated)
+	}
+}
+
+// Stop halts the background estimation started by Start.
+func (s *RtEstimationService) Stop() {
+	close(s.stopChan)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/epi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRtEstimateRepository struct {
+	mock.Mock
+}
+
+func (m *MockRtEstimateRepository) GetCaseSeries(ctx context.Context) ([]models.CaseSeries, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CaseSeries), args.Error(1)
+}
+
+func (m *MockRtEstimateRepository) SaveEstimates(ctx context.Context, estimates []models.RtEstimate, overwrite, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(estimates, overwrite, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeSummary), args.Error(1)
+}
+
+var rtTestConfig = epi.Config{SerialIntervalMean: 4.7, SerialIntervalSD: 2.9, WindowDays: 7}
+
+// constantSeries is 30 days of 100 new cases, with the 15th day missing and
+// the 20th day having an Rt already
+func constantSeries(provinceID string) models.CaseSeries {
+	start := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	cs := models.CaseSeries{ProvinceID: provinceID}
+	for i := 0; i < 30; i++ {
+		if i == 14 {
+			continue
+		}
+		cs.Days = append(cs.Days, models.CaseSeriesDay{Day: int64(i + 1), Date: start.AddDate(0, 0, i), Positive: 100, HasRt: i == 19})
+	}
+	return cs
+}
+
+func TestRtEstimationService_Estimate(t *testing.T) {
+	repo := new(MockRtEstimateRepository)
+	repo.On("GetCaseSeries").Return([]models.CaseSeries{constantSeries(""), constantSeries("72")}, nil)
+	var saved []models.RtEstimate
+	repo.On("SaveEstimates", mock.Anything, false, false).Run(func(args mock.Arguments) {
+		saved = args.Get(0).([]models.RtEstimate)
+	}).Return(&models.ChangeSummary{Updated: 42}, nil)
+	invalidator := new(countingInvalidator)
+	svc, err := NewRtEstimationService(repo, rtTestConfig, invalidator)
+	require.NoError(t, err)
+
+	summary, err := svc.Estimate(context.Background(), false, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, summary.Updated)
+	assert.Equal(t, 1, invalidator.clears)
+	// Days 8 to 30 have a full window, minus the missing day and the one with an Rt
+	require.Len(t, saved, 2*21)
+	assert.Equal(t, int64(8), saved[0].Day)
+	for _, e := range saved {
+		assert.NotEqual(t, int64(20), e.Day)
+		assert.LessOrEqual(t, e.RtLower, e.Rt)
+		assert.GreaterOrEqual(t, e.RtUpper, e.Rt)
+		// Rt is rounded to two decimals
+		assert.Equal(t, roundRt(e.Rt), e.Rt)
+	}
+	assert.Equal(t, "72", saved[len(saved)-1].ProvinceID)
+	// The missing day counts as a day without cases, which lowers the estimate of the day after it
+	assert.Equal(t, int64(16), saved[7].Day)
+	assert.Less(t, saved[7].Rt, 1.0)
+	assert.InDelta(t, 1, saved[len(saved)-1].Rt, 0.1)
+}
+
+func TestRtEstimationService_Estimate_Overwrite(t *testing.T) {
+	repo := new(MockRtEstimateRepository)
+	repo.On("GetCaseSeries").Return([]models.CaseSeries{constantSeries("")}, nil)
+	var saved []models.RtEstimate
+	repo.On("SaveEstimates", mock.Anything, true, true).Run(func(args mock.Arguments) {
+		saved = args.Get(0).([]models.RtEstimate)
+	}).Return(&models.ChangeSummary{DryRun: true, Updated: 22}, nil)
+	invalidator := new(countingInvalidator)
+	svc, err := NewRtEstimationService(repo, rtTestConfig, invalidator)
+	require.NoError(t, err)
+
+	_, err = svc.Estimate(context.Background(), true, true)
+
+	require.NoError(t, err)
+	assert.Len(t, saved, 22)
+	assert.Zero(t, invalidator.clears, "dry runs change nothing")
+}
+
+func TestRtEstimationService_Estimate_NothingToEstimate(t *testing.T) {
+	repo := new(MockRtEstimateRepository)
+	repo.On("GetCaseSeries").Return([]models.CaseSeries{{}}, nil)
+	svc, err := NewRtEstimationService(repo, rtTestConfig, nil)
+	require.NoError(t, err)
+
+	summary, err := svc.Estimate(context.Background(), false, false)
+
+	require.NoError(t, err)
+	assert.Zero(t, summary.Updated)
+	repo.AssertNotCalled(t, "SaveEstimates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRtEstimationService_Errors(t *testing.T) {
+	_, err := NewRtEstimationService(new(MockRtEstimateRepository), epi.Config{}, nil)
+	assert.ErrorContains(t, err, "invalid rt estimation config")
+
+	repo := new(MockRtEstimateRepository)
+	repo.On("GetCaseSeries").Return(nil, errors.New("db down"))
+	svc, err := NewRtEstimationService(repo, rtTestConfig, nil)
+	require.NoError(t, err)
+	_, err = svc.Estimate(context.Background(), false, false)
+	assert.ErrorContains(t, err, "failed to get case series")
+}
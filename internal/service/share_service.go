@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// ErrInvalidShareFilters wraps validation failures of the filters of a new share link
+var ErrInvalidShareFilters = errors.New("invalid share filters")
+
+const shareCodeAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// shareCodeLength is the length of generated codes; 57^7 codes keep random
+// collisions rare at the expected number of links
+const shareCodeLength = 7
+
+// shareCodeAttempts is how often a new code is drawn when it is already taken
+const shareCodeAttempts = 5
+
+var shareCodePattern = regexp.MustCompile(`^[a-zA-Z0-9]{1,16}$`)
+
+// ShareService stores filtered dashboard views under short codes. The same
+// filters always map to the same link.
+type ShareService struct {
+	repo         repository.ShareLinkRepository
+	baseURL      string
+	dashboardURL string
+	clock        clock.Clock
+}
+
+// NewShareService creates a ShareService. baseURL is the public address of the
+// API used in short links; dashboardURL is where expanded links redirect to
+// and may be empty when links should only be expanded as JSON.
+func NewShareService(repo repository.ShareLinkRepository, baseURL, dashboardURL string) *ShareService {
+	return &ShareService{
+		repo:         repo,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		dashboardURL: dashboardURL,
+		clock:        clock.System,
+	}
+}
+
+// SetClock sets the clock that stamps new links
+func (s *ShareService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// CreateLink returns the link of the filters, creating it when the filters
+// have not been shared before
+func (s *ShareService) CreateLink(ctx context.Context, filters models.ShareFilters) (*models.ShareLink, error) {
+	filters = filters.Normalize()
+	if err := filters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidShareFilters, err)
+	}
+	hash := filters.Hash()
+
+	existing, err := s.repo.GetByFiltersHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up share link: %w", err)
+	}
+	if existing != nil {
+		return s.withURL(existing), nil
+	}
+
+	code, err := s.unusedCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	link := models.ShareLink{
+		Code:        code,
+		Filters:     filters,
+		FiltersHash: hash,
+		CreatedAt:   s.clock.Now().UTC(),
+	}
+	if link.ID, err = s.repo.Create(ctx, link); err != nil {
+		// A concurrent request may have shared the same filters first
+		if existing, lookupErr := s.repo.GetByFiltersHash(ctx, hash); lookupErr == nil && existing != nil {
+			return s.withURL(existing), nil
+		}
+		return nil, err
+	}
+	return s.withURL(&link), nil
+}
+
+// GetLink returns the link with the code, or nil when it does not exist
+func (s *ShareService) GetLink(ctx context.Context, code string) (*models.ShareLink, error) {
+	if !shareCodePattern.MatchString(code) {
+		return nil, nil
+	}
+	link, err := s.repo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	if link == nil {
+		return nil, nil
+	}
+	return s.withURL(link), nil
+}
+
+// DashboardURL is the dashboard address showing the link's view, or empty when
+// no dashboard is configured
+func (s *ShareService) DashboardURL(link *models.ShareLink) string {
+	if s.dashboardURL == "" {
+		return ""
+	}
+	q := link.Filters.Query()
+	if len(q) == 0 {
+		return s.dashboardURL
+	}
+	sep := "?"
+	if strings.Contains(s.dashboardURL, "?") {
+		sep = "&"
+	}
+	return s.dashboardURL + sep + q.Encode()
+}
+
+func (s *ShareService) withURL(link *models.ShareLink) *models.ShareLink {
+	link.URL = s.baseURL + "/s/" + link.Code
+	return link
+}
+
+func (s *ShareService) unusedCode(ctx context.Context) (string, error) {
+	for i := 0; i < shareCodeAttempts; i++ {
+		code := newShareCode()
+		existing, err := s.repo.GetByCode(ctx, code)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up share link: %w", err)
+		}
+		if existing == nil {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("no unused share code after %d attempts", shareCodeAttempts)
+}
+
+func newShareCode() string {
+	max := big.NewInt(int64(len(shareCodeAlphabet)))
+	b := make([]byte, shareCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(fmt.Sprintf("crypto/rand failed: %v", err))
+		}
+		b[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(b)
+}
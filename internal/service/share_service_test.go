@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareLinkRepository) GetByCode(ctx context.Context, code string) (*models.ShareLink, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) GetByFiltersHash(ctx context.Context, hash string) (*models.ShareLink, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link models.ShareLink) (int64, error) {
+	args := m.Called(link)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestShareService_CreateLink_New(t *testing.T) {
+	repo := new(MockShareLinkRepository)
+	svc := NewShareService(repo, "https://api.example.com/", "")
+	now := time.Date(2021, 9, 1, 8, 0, 0, 0, time.UTC)
+	svc.SetClock(clock.NewMock(now))
+
+	filters := models.ShareFilters{ProvinceID: "72", Metrics: []string{"rt", "positive"}}.Normalize()
+	repo.On("GetByFiltersHash", filters.Hash()).Return(nil, nil)
+	repo.On("GetByCode", mock.AnythingOfType("string")).Return(nil, nil).Once()
+	repo.On("Create", mock.MatchedBy(func(l models.ShareLink) bool {
+		return len(l.Code) == shareCodeLength && l.FiltersHash == filters.Hash() && l.CreatedAt.Equal(now)
+	})).Return(int64(3), nil)
+
+	link, err := svc.CreateLink(context.Background(), models.ShareFilters{ProvinceID: "72", Metrics: []string{"rt", "positive"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), link.ID)
+	assert.Equal(t, []string{"positive", "rt"}, link.Filters.Metrics)
+	assert.Equal(t, "https://api.example.com/s/"+link.Code, link.URL)
+	repo.AssertExpectations(t)
+}
+
+func TestShareService_CreateLink_ReusesExisting(t *testing.T) {
+	repo := new(MockShareLinkRepository)
+	svc := NewShareService(repo, "https://api.example.com", "")
+
+	filters := models.ShareFilters{ProvinceID: "72"}
+	repo.On("GetByFiltersHash", filters.Hash()).Return(&models.ShareLink{ID: 1, Code: "abc1234", Filters: filters}, nil)
+
+	link, err := svc.CreateLink(context.Background(), filters)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc1234", link.Code)
+	assert.Equal(t, "https://api.example.com/s/abc1234", link.URL)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestShareService_CreateLink_RetriesTakenCode(t *testing.T) {
+	repo := new(MockShareLinkRepository)
+	svc := NewShareService(repo, "", "")
+
+	repo.On("GetByFiltersHash", mock.Anything).Return(nil, nil)
+	repo.On("GetByCode", mock.Anything).Return(&models.ShareLink{ID: 9}, nil).Once()
+	repo.On("GetByCode", mock.Anything).Return(nil, nil).Once()
+	repo.On("Create", mock.Anything).Return(int64(10), nil)
+
+	link, err := svc.CreateLink(context.Background(), models.ShareFilters{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), link.ID)
+	repo.AssertNumberOfCalls(t, "GetByCode", 2)
+}
+
+func TestShareService_CreateLink_ConcurrentInsert(t *testing.T) {
+	repo := new(MockShareLinkRepository)
+	svc := NewShareService(repo, "", "")
+
+	repo.On("GetByFiltersHash", mock.Anything).Return(nil, nil).Once()
+	repo.On("GetByCode", mock.Anything).Return(nil, nil)
+	repo.On("Create", mock.Anything).Return(int64(0), errors.New("duplicate entry"))
+	repo.On("GetByFiltersHash", mock.Anything).Return(&models.ShareLink{ID: 4, Code: "xyz7890"}, nil).Once()
+
+	link, err := svc.CreateLink(context.Background(), models.ShareFilters{ProvinceID: "72"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "xyz7890", link.Code)
+}
+
+func TestShareService_CreateLink_Invalid(t *testing.T) {
+	svc := NewShareService(new(MockShareLinkRepository), "", "")
+
+	_, err := svc.CreateLink(context.Background(), models.ShareFilters{Metrics: []string{"vaccinated"}})
+
+	assert.ErrorIs(t, err, ErrInvalidShareFilters)
+}
+
+func TestShareService_GetLink(t *testing.T) {
+	repo := new(MockShareLinkRepository)
+	svc := NewShareService(repo, "https://api.example.com", "")
+
+	repo.On("GetByCode", "abc1234").Return(&models.ShareLink{Code: "abc1234"}, nil)
+	repo.On("GetByCode", "missing").Return(nil, nil)
+
+	link, err := svc.GetLink(context.Background(), "abc1234")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/s/abc1234", link.URL)
+
+	link, err = svc.GetLink(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, link)
+
+	// Codes that cannot exist are not looked up
+	link, err = svc.GetLink(context.Background(), "../admin")
+	assert.NoError(t, err)
+	assert.Nil(t, link)
+	repo.AssertNumberOfCalls(t, "GetByCode", 2)
+}
+
+func TestShareService_DashboardURL(t *testing.T) {
+	link := &models.ShareLink{Filters: models.ShareFilters{ProvinceID: "72", Metrics: []string{"rt"}}}
+
+	assert.Equal(t, "", NewShareService(nil, "", "").DashboardURL(link))
+	assert.Equal(t, "https://dashboard.example.com/?metrics=rt&province=72",
+		NewShareService(nil, "", "https://dashboard.example.com/").DashboardURL(link))
+	assert.Equal(t, "https://example.com/?page=covid&metrics=rt&province=72",
+		NewShareService(nil, "", "https://example.com/?page=covid").DashboardURL(link))
+	assert.Equal(t, "https://dashboard.example.com/",
+		NewShareService(nil, "", "https://dashboard.example.com/").DashboardURL(&models.ShareLink{}))
+}
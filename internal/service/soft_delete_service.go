@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+)
+
+// ErrSoftDeleteNoMatch is re-exported so handlers can map it to 404
+var ErrSoftDeleteNoMatch = repository.ErrSoftDeleteNoMatch
+
+// SoftDeleteService hides erroneous cases and provinces from the API and
+// restores them
+type SoftDeleteService struct {
+	repo        repository.SoftDeleteRepository
+	invalidator CacheInvalidator
+}
+
+// NewSoftDeleteService creates a SoftDeleteService. The invalidator may be
+// nil; when set the cache is cleared after every change.
+func NewSoftDeleteService(repo repository.SoftDeleteRepository, invalidator CacheInvalidator) *SoftDeleteService {
+	return &SoftDeleteService{repo: repo, invalidator: invalidator}
+}
+
+func (s *SoftDeleteService) DeleteNationalCase(ctx context.Context, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return s.apply(s.repo.SetNationalCaseDeleted(ctx, day, true, dryRun))
+}
+
+func (s *SoftDeleteService) RestoreNationalCase(ctx context.Context, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return s.apply(s.repo.SetNationalCaseDeleted(ctx, day, false, dryRun))
+}
+
+func (s *SoftDeleteService) DeleteProvinceCase(ctx context.Context, provinceID string, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return s.apply(s.repo.SetProvinceCaseDeleted(ctx, provinceID, day, true, dryRun))
+}
+
+func (s *SoftDeleteService) RestoreProvinceCase(ctx context.Context, provinceID string, day int64, dryRun bool) (*models.ChangeSummary, error) {
+	return s.apply(s.repo.SetProvinceCaseDeleted(ctx, provinceID, day, false, dryRun))
+}
+
+func (s *SoftDeleteService) DeleteProvince(ctx context.Context, id string, dryRun bool) (*models.ChangeSummary, error) {
+	return s.apply(s.repo.SetProvinceDeleted(ctx, id, true, dryRun))
+}
+
+func (s *SoftDeleteService) RestoreProvince(ctx context.Context, id string, dryRun bool) (*models.ChangeSummary, error) {
+	return s.apply(s.repo.SetProvinceDeleted(ctx, id, false, dryRun))
+}
+
+// apply clears the cache after a committed change so hidden rows stop being
+// served
+func (s *SoftDeleteService) apply(summary *models.ChangeSummary, err error) (*models.ChangeSummary, error) {
+	if err != nil {
+		return nil, fmt.Errorf("failed to soft-delete or restore: %w", err)
+	}
+	if s.invalidator != nil && !summary.DryRun {
+		s.invalidator.Clear()
+	}
+	return summary, nil
+}
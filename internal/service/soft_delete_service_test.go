@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSoftDeleteRepository struct {
+	mock.Mock
+}
+
+func (m *MockSoftDeleteRepository) SetNationalCaseDeleted(ctx context.Context, day int64, deleted, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(day, deleted, dryRun))
+}
+
+func (m *MockSoftDeleteRepository) SetProvinceCaseDeleted(ctx context.Context, provinceID string, day int64, deleted, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(provinceID, day, deleted, dryRun))
+}
+
+func (m *MockSoftDeleteRepository) SetProvinceDeleted(ctx context.Context, id string, deleted, dryRun bool) (*models.ChangeSummary, error) {
+	return m.summary(m.Called(id, deleted, dryRun))
+}
+
+func (m *MockSoftDeleteRepository) summary(args mock.Arguments) (*models.ChangeSummary, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeSummary), args.Error(1)
+}
+
+func TestSoftDeleteService_DeleteNationalCase_ClearsCache(t *testing.T) {
+	repo := new(MockSoftDeleteRepository)
+	repo.On("SetNationalCaseDeleted", int64(8), true, false).Return(&models.ChangeSummary{Deleted: 1}, nil)
+	invalidator := new(countingInvalidator)
+
+	summary, err := NewSoftDeleteService(repo, invalidator).DeleteNationalCase(context.Background(), 8, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Deleted)
+	assert.Equal(t, 1, invalidator.clears)
+	repo.AssertExpectations(t)
+}
+
+func TestSoftDeleteService_DryRun_KeepsCache(t *testing.T) {
+	repo := new(MockSoftDeleteRepository)
+	repo.On("SetProvinceCaseDeleted", "72", int64(8), true, true).Return(&models.ChangeSummary{DryRun: true, Deleted: 1}, nil)
+	invalidator := new(countingInvalidator)
+
+	summary, err := NewSoftDeleteService(repo, invalidator).DeleteProvinceCase(context.Background(), "72", 8, true)
+
+	assert.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 0, invalidator.clears)
+	repo.AssertExpectations(t)
+}
+
+func TestSoftDeleteService_RestoreProvince_NoMatch(t *testing.T) {
+	repo := new(MockSoftDeleteRepository)
+	repo.On("SetProvinceDeleted", "72", false, false).Return(nil, ErrSoftDeleteNoMatch)
+	invalidator := new(countingInvalidator)
+
+	_, err := NewSoftDeleteService(repo, invalidator).RestoreProvince(context.Background(), "72", false)
+
+	assert.ErrorIs(t, err, ErrSoftDeleteNoMatch)
+	assert.Equal(t, 0, invalidator.clears)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// earliestSaneTime is before any case data; a clock showing an earlier time
+// was never set
+var earliestSaneTime = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// diagnosticsTimeout bounds the database checks of the startup diagnostics
+const diagnosticsTimeout = 10 * time.Second
+
+// WritableDir is a directory the API writes files to
+type WritableDir struct {
+	// Name is the setting naming the directory, e.g. EXPORT_DIR
+	Name string
+	Path string
+	// Required makes an unwritable directory fatal rather than a warning
+	Required bool
+}
+
+// StartupEnvironment is what the startup diagnostics check
+type StartupEnvironment struct {
+	// ConfigChecks are the problems found by config.Validate
+	ConfigChecks []models.DiagnosticCheck
+	// ConnectErr is why the database could not be opened. Schema and
+	// ServerTime are only used without it.
+	ConnectErr error
+	Schema     repository.SchemaRepository
+	ServerTime func(ctx context.Context) (time.Time, error)
+	// RequireMigrations makes missing migrations fatal, as the readiness
+	// probe would keep the API out of the load balancer anyway
+	RequireMigrations bool
+	Dirs              []WritableDir
+	// MaxClockSkew is the largest tolerated difference between Clock and
+	// the database server's clock
+	MaxClockSkew time.Duration
+	Clock        clock.Clock
+}
+
+// DiagnoseStartup checks the configuration, the database connection and
+// migrations, the directories written to and the clock, so the API can refuse
+// to start rather than fail at request time
+func DiagnoseStartup(ctx context.Context, env StartupEnvironment) models.DiagnosticsReport {
+	report := models.DiagnosticsReport{OK: true, Checks: []models.DiagnosticCheck{}}
+	if env.Clock == nil {
+		env.Clock = clock.System
+	}
+
+	if len(env.ConfigChecks) == 0 {
+		report.Add(models.DiagnosticCheck{Name: "config", Status: models.DiagnosticOK})
+	}
+	for _, c := range env.ConfigChecks {
+		report.Add(c)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsTimeout)
+	defer cancel()
+	connected := env.ConnectErr == nil && env.Schema != nil
+	switch {
+	case env.ConnectErr != nil:
+		report.Add(models.DiagnosticCheck{Name: "database", Status: models.DiagnosticFatal, Message: env.ConnectErr.Error()})
+	case env.Schema == nil:
+		report.Add(models.DiagnosticCheck{Name: "database", Status: models.DiagnosticFatal, Message: "not connected"})
+	default:
+		report.Add(models.DiagnosticCheck{Name: "database", Status: models.DiagnosticOK})
+		report.Add(checkStartupMigrations(ctx, env))
+	}
+
+	for _, dir := range env.Dirs {
+		report.Add(checkWritableDir(dir))
+	}
+	report.Add(checkClock(ctx, env, connected))
+	return report
+}
+
+func checkStartupMigrations(ctx context.Context, env StartupEnvironment) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "migrations", Status: models.DiagnosticOK}
+	missing, err := missingMigrations(ctx, env.Schema)
+	switch {
+	case err != nil:
+		check.Message = err.Error()
+	case len(missing) > 0:
+		check.Message = "migrations not applied: " + strings.Join(missing, ", ")
+	default:
+		return check
+	}
+	check.Status = models.DiagnosticWarning
+	if env.RequireMigrations {
+		check.Status = models.DiagnosticFatal
+	}
+	return check
+}
+
+// checkWritableDir creates the directory if needed and writes a file to it
+func checkWritableDir(dir WritableDir) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: dir.Name, Status: models.DiagnosticOK}
+	err := os.MkdirAll(dir.Path, 0o750)
+	if err == nil {
+		var f *os.File
+		if f, err = os.CreateTemp(dir.Path, ".write-check-*"); err == nil {
+			err = f.Close()
+			if removeErr := os.Remove(f.Name()); err == nil {
+				err = removeErr
+			}
+		}
+	}
+	if err != nil {
+		check.Status = models.DiagnosticWarning
+		if dir.Required {
+			check.Status = models.DiagnosticFatal
+		}
+		check.Message = fmt.Sprintf("%s is not writable: %v", dir.Path, err)
+	}
+	return check
+}
+
+// checkClock rejects a clock that was never set and warns when it drifted
+// from the database server's, which stamps rows with its own time
+func checkClock(ctx context.Context, env StartupEnvironment, connected bool) models.DiagnosticCheck {
+	check := models.DiagnosticCheck{Name: "clock", Status: models.DiagnosticOK}
+	now := env.Clock.Now()
+	if now.Before(earliestSaneTime) {
+		check.Status = models.DiagnosticFatal
+		check.Message = fmt.Sprintf("system clock shows %s, before any case data", now.UTC().Format(time.RFC3339))
+		return check
+	}
+	if !connected || env.ServerTime == nil {
+		return check
+	}
+
+	serverTime, err := env.ServerTime(ctx)
+	if err != nil {
+		check.Status = models.DiagnosticWarning
+		check.Message = err.Error()
+		return check
+	}
+	skew := now.Sub(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if env.MaxClockSkew > 0 && skew > env.MaxClockSkew {
+		check.Status = models.DiagnosticWarning
+		check.Message = fmt.Sprintf("system clock is %s off the database server's", skew.Round(time.Second))
+	}
+	return check
+}
+
+// FormatDiagnostics renders a report as one block for the log, the failing
+// checks first
+func FormatDiagnostics(report models.DiagnosticsReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Startup diagnostics: %d fatal, %d warnings", report.Fatal, report.Warnings)
+	for _, status := range []string{models.DiagnosticFatal, models.DiagnosticWarning, models.DiagnosticOK} {
+		for _, c := range report.Checks {
+			if c.Status != status {
+				continue
+			}
+			fmt.Fprintf(&b, "\n  %-8s %s", c.Status, c.Name)
+			if c.Message != "" {
+				b.WriteString(": " + c.Message)
+			}
+		}
+	}
+	return b.String()
+}
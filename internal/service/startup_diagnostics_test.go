@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diagnosticStatuses(r models.DiagnosticsReport) map[string]string {
+	statuses := make(map[string]string, len(r.Checks))
+	for _, c := range r.Checks {
+		statuses[c.Name] = c.Status
+	}
+	return statuses
+}
+
+func TestDiagnoseStartup_OK(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	schema := new(MockSchemaRepository)
+	schema.On("ListColumns").Return(appliedMigrations(), nil)
+	schema.On("ListTriggers").Return(appliedTriggers(), nil)
+	dir := filepath.Join(t.TempDir(), "exports")
+
+	report := DiagnoseStartup(context.Background(), StartupEnvironment{
+		Schema:            schema,
+		ServerTime:        func(context.Context) (time.Time, error) { return now.Add(-10 * time.Second), nil },
+		RequireMigrations: true,
+		Dirs:              []WritableDir{{Name: "EXPORT_DIR", Path: dir, Required: true}},
+		MaxClockSkew:      time.Minute,
+		Clock:             clock.NewMock(now),
+	})
+
+	assert.True(t, report.OK)
+	assert.Zero(t, report.Warnings)
+	assert.Equal(t, map[string]string{
+		"config":     models.DiagnosticOK,
+		"database":   models.DiagnosticOK,
+		"migrations": models.DiagnosticOK,
+		"EXPORT_DIR": models.DiagnosticOK,
+		"clock":      models.DiagnosticOK,
+	}, diagnosticStatuses(report))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err, "the directory is created")
+	assert.Empty(t, entries, "the check file is removed")
+}
+
+func TestDiagnoseStartup_Problems(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	schema := new(MockSchemaRepository)
+	applied := appliedMigrations()
+	schema.On("ListColumns").Return(applied[:len(applied)-1], nil)
+	schema.On("ListTriggers").Return(appliedTriggers(), nil)
+	notADir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notADir, nil, 0o600))
+
+	env := StartupEnvironment{
+		ConfigChecks: []models.DiagnosticCheck{{Name: "DB_USERNAME", Status: models.DiagnosticWarning, Message: "no database user set"}},
+		Schema:       schema,
+		ServerTime:   func(context.Context) (time.Time, error) { return now.Add(-5 * time.Minute), nil },
+		Dirs: []WritableDir{
+			{Name: "EXPORT_DIR", Path: notADir, Required: true},
+			{Name: "BACKUP_DIR", Path: notADir},
+		},
+		MaxClockSkew: time.Minute,
+		Clock:        clock.NewMock(now),
+	}
+	report := DiagnoseStartup(context.Background(), env)
+
+	assert.False(t, report.OK)
+	assert.Equal(t, 1, report.Fatal)
+	assert.Equal(t, 4, report.Warnings)
+	assert.Equal(t, map[string]string{
+		"DB_USERNAME": models.DiagnosticWarning,
+		"database":    models.DiagnosticOK,
+		"migrations":  models.DiagnosticWarning,
+		"EXPORT_DIR":  models.DiagnosticFatal,
+		"BACKUP_DIR":  models.DiagnosticWarning,
+		"clock":       models.DiagnosticWarning,
+	}, diagnosticStatuses(report))
+
+	env.RequireMigrations = true
+	env.Dirs = nil
+	assert.Equal(t, models.DiagnosticFatal, diagnosticStatuses(DiagnoseStartup(context.Background(), env))["migrations"])
+}
+
+func TestDiagnoseStartup_NotConnected(t *testing.T) {
+	report := DiagnoseStartup(context.Background(), StartupEnvironment{
+		ConnectErr: errors.New("failed to ping database after 3 attempts: connection refused"),
+		Clock:      clock.NewMock(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+
+	assert.False(t, report.OK)
+	assert.Equal(t, map[string]string{
+		"config":   models.DiagnosticOK,
+		"database": models.DiagnosticFatal,
+		"clock":    models.DiagnosticFatal,
+	}, diagnosticStatuses(report))
+}
+
+func TestFormatDiagnostics(t *testing.T) {
+	var report models.DiagnosticsReport
+	report.Add(models.DiagnosticCheck{Name: "config", Status: models.DiagnosticOK})
+	report.Add(models.DiagnosticCheck{Name: "database", Status: models.DiagnosticFatal, Message: "connection refused"})
+
+	assert.Equal(t, "Startup diagnostics: 1 fatal, 0 warnings\n  fatal    database: connection refused\n  ok       config", FormatDiagnostics(report))
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// statusWindowDays is the window of the rolling Rt and weekly incidence
+const statusWindowDays = 7
+
+// StatusService classifies provinces for the traffic-light overview. It reads
+// through the covid service and therefore shares its cache.
+type StatusService struct {
+	covid      CovidService
+	thresholds models.StatusThresholds
+}
+
+// NewStatusService creates a StatusService classifying with thresholds
+func NewStatusService(covid CovidService, thresholds models.StatusThresholds) *StatusService {
+	return &StatusService{covid: covid, thresholds: thresholds}
+}
+
+// GetStatuses returns the status of every province with data, computed over
+// the 7 days ending at the latest date with data of any province
+func (s *StatusService) GetStatuses(ctx context.Context) (*models.StatusOverview, error) {
+	overview := &models.StatusOverview{
+		Methodology: models.StatusMethodology,
+		Thresholds:  s.thresholds,
+		Provinces:   []models.ProvinceStatus{},
+	}
+
+	provinces, err := s.covid.GetProvincesWithLatestCase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provinces with latest case: %w", err)
+	}
+	byID := make(map[string]int, len(provinces))
+	var end time.Time
+	for _, p := range provinces {
+		if p.LatestCase == nil {
+			continue
+		}
+		byID[p.ID] = len(overview.Provinces)
+		overview.Provinces = append(overview.Provinces, models.ProvinceStatus{Province: p.Province, Date: p.LatestCase.Date})
+		if p.LatestCase.Date.After(end) {
+			end = p.LatestCase.Date
+		}
+	}
+	if len(overview.Provinces) == 0 {
+		return overview, nil
+	}
+
+	start := end.AddDate(0, 0, -(statusWindowDays - 1))
+	overview.WindowStart, overview.WindowEnd = &start, &end
+	window, _, err := s.covid.ListProvinceCases(ctx, QueryOptions{
+		Range: DateRange{Start: start, End: end},
+		Sort:  byDateAsc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get province cases for status: %w", err)
+	}
+
+	rtSums := make([]float64, len(overview.Provinces))
+	rtDays := make([]int, len(overview.Provinces))
+	for _, c := range window {
+		i, ok := byID[c.ProvinceID]
+		if !ok {
+			continue
+		}
+		overview.Provinces[i].WeeklyIncidence += c.Positive
+		if c.Rt != nil {
+			rtSums[i] += *c.Rt
+			rtDays[i]++
+		}
+	}
+	for i := range overview.Provinces {
+		p := &overview.Provinces[i]
+		if rtDays[i] > 0 {
+			rt := rtSums[i] / float64(rtDays[i])
+			p.RollingRt = &rt
+		}
+		p.Status = s.thresholds.Classify(p.RollingRt, p.WeeklyIncidence)
+	}
+	return overview, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var statusTestThresholds = models.StatusThresholds{RtCaution: 1.0, RtCritical: 1.2, IncidenceCaution: 50, IncidenceCritical: 200}
+
+func TestStatusService_GetStatuses(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2021, 8, day, 0, 0, 0, 0, time.UTC) }
+	rt := func(v float64) *float64 { return &v }
+	mockSvc := new(MockCovidService)
+	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "72", Name: "Sulawesi Tengah"}, LatestCase: &models.ProvinceCaseResponse{Date: date(10)}},
+		{Province: models.Province{ID: "31", Name: "DKI Jakarta"}, LatestCase: &models.ProvinceCaseResponse{Date: date(10)}},
+		{Province: models.Province{ID: "11", Name: "Aceh"}, LatestCase: &models.ProvinceCaseResponse{Date: date(9)}},
+		{Province: models.Province{ID: "99", Name: "No data"}},
+	}, nil)
+	mockSvc.On("ListProvinceCases", QueryOptions{Range: testDateRange("2021-08-04", "2021-08-10"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "72", Positive: 10, Rt: rt(0.8)}, Date: date(9)},
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "72", Positive: 10, Rt: rt(1.0)}, Date: date(10)},
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "31", Positive: 150, Rt: rt(0.9)}, Date: date(9)},
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "31", Positive: 100}, Date: date(10)},
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "11", Positive: 5}, Date: date(9)},
+		}, 0, nil)
+
+	overview, err := NewStatusService(mockSvc, statusTestThresholds).GetStatuses(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusMethodology, overview.Methodology)
+	assert.Equal(t, statusTestThresholds, overview.Thresholds)
+	assert.Equal(t, date(4), *overview.WindowStart)
+	assert.Equal(t, date(10), *overview.WindowEnd)
+	require.Len(t, overview.Provinces, 3, "provinces without data are left out")
+
+	sulteng := overview.Provinces[0]
+	assert.Equal(t, int64(20), sulteng.WeeklyIncidence)
+	assert.InDelta(t, 0.9, *sulteng.RollingRt, 1e-9)
+	assert.Equal(t, models.StatusControlled, sulteng.Status)
+
+	jakarta := overview.Provinces[1]
+	assert.Equal(t, int64(250), jakarta.WeeklyIncidence)
+	assert.InDelta(t, 0.9, *jakarta.RollingRt, 1e-9, "days without Rt are not averaged")
+	assert.Equal(t, models.StatusCritical, jakarta.Status)
+
+	aceh := overview.Provinces[2]
+	assert.Nil(t, aceh.RollingRt)
+	assert.Equal(t, models.StatusControlled, aceh.Status)
+}
+
+func TestStatusService_GetStatuses_Error(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase(nil), errors.New("db down"))
+
+	_, err := NewStatusService(mockSvc, statusTestThresholds).GetStatuses(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/mailer"
+)
+
+// ErrInvalidEmail is returned when a subscription request has no usable address
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// Mailer sends a single email
+type Mailer interface {
+	Send(msg mailer.Message) error
+}
+
+// subscriptionProvinceID is the province covered by the daily update emails
+const subscriptionProvinceID = "72"
+
+// confirmationCooldown is the minimum time between confirmation emails to the
+// same address, so the public endpoint cannot be used to flood an inbox
+const confirmationCooldown = 10 * time.Minute
+
+// subscriptionSendBatch is the number of subscribers loaded per query while
+// sending updates
+const subscriptionSendBatch = 100
+
+// SubscriptionService manages double opt-in email subscriptions and emails a
+// daily Sulawesi Tengah update to confirmed subscribers when a new day of data
+// is available
+type SubscriptionService struct {
+	repo          repository.SubscriptionRepository
+	provinceCases repository.ProvinceCaseRepository
+	mailer        Mailer
+	baseURL       string
+	clock         clock.Clock
+
+	stopChan chan struct{}
+}
+
+// NewSubscriptionService creates a SubscriptionService. baseURL is the public
+// address of the API used in confirmation and unsubscribe links.
+func NewSubscriptionService(repo repository.SubscriptionRepository, provinceCases repository.ProvinceCaseRepository, mailer Mailer, baseURL string) *SubscriptionService {
+	return &SubscriptionService{
+		repo:          repo,
+		provinceCases: provinceCases,
+		mailer:        mailer,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		clock:         clock.System,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock of the subscription timestamps and the update
+// schedule
+func (s *SubscriptionService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Subscribe starts a subscription by emailing a confirmation link. The outcome
+// is the same whether the address is new, pending or already confirmed, so the
+// endpoint does not reveal who is subscribed.
+func (s *SubscriptionService) Subscribe(ctx context.Context, email string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to look up subscription: %w", err)
+	}
+	now := s.clock.Now().UTC()
+
+	if existing == nil {
+		sub := models.EmailSubscription{
+			Email:              email,
+			Status:             models.SubscriptionPending,
+			ConfirmToken:       newSubscriptionToken(),
+			UnsubscribeToken:   newSubscriptionToken(),
+			ConfirmationSentAt: now,
+			CreatedAt:          now,
+		}
+		if sub.ID, err = s.repo.Create(ctx, sub); err != nil {
+			return err
+		}
+		return s.sendConfirmation(sub)
+	}
+
+	switch {
+	case existing.Status == models.SubscriptionConfirmed:
+		return nil
+	case existing.Status == models.SubscriptionPending && now.Sub(existing.ConfirmationSentAt) < confirmationCooldown:
+		return nil
+	}
+	existing.Status = models.SubscriptionPending
+	existing.ConfirmToken = newSubscriptionToken()
+	existing.ConfirmationSentAt = now
+	existing.UnsubscribedAt = nil
+	if err := s.repo.Update(ctx, *existing); err != nil {
+		return err
+	}
+	return s.sendConfirmation(*existing)
+}
+
+// Confirm activates the subscription with the confirmation token. It returns
+// nil when the token is unknown; confirming twice is not an error.
+func (s *SubscriptionService) Confirm(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	if token == "" {
+		return nil, nil
+	}
+	sub, err := s.repo.GetByConfirmToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up subscription: %w", err)
+	}
+	if sub == nil || sub.Status == models.SubscriptionConfirmed {
+		return sub, nil
+	}
+	if sub.Status != models.SubscriptionPending {
+		return nil, nil
+	}
+	now := s.clock.Now().UTC()
+	sub.Status = models.SubscriptionConfirmed
+	sub.ConfirmedAt = &now
+	if err := s.repo.Update(ctx, *sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Unsubscribe ends the subscription with the unsubscribe token. It returns nil
+// when the token is unknown; unsubscribing twice is not an error.
+func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	if token == "" {
+		return nil, nil
+	}
+	sub, err := s.repo.GetByUnsubscribeToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up subscription: %w", err)
+	}
+	if sub == nil || sub.Status == models.SubscriptionUnsubscribed {
+		return sub, nil
+	}
+	now := s.clock.Now().UTC()
+	sub.Status = models.SubscriptionUnsubscribed
+	sub.UnsubscribedAt = &now
+	if err := s.repo.Update(ctx, *sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// SendDailyUpdates emails the latest day of Sulawesi Tengah data to every
+// confirmed subscriber that has not received it yet and returns how many were
+// sent. A failed email is logged and retried on the next call.
+func (s *SubscriptionService) SendDailyUpdates(ctx context.Context) (int, error) {
+	latest, err := s.provinceCases.GetLatestByProvinceID(ctx, subscriptionProvinceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest province case: %w", err)
+	}
+	if latest == nil {
+		return 0, nil
+	}
+
+	sent := 0
+	for {
+		due, err := s.repo.ListDue(ctx, latest.Day, subscriptionSendBatch)
+		if err != nil {
+			return sent, fmt.Errorf("failed to list due subscriptions: %w", err)
+		}
+		progressed := false
+		for _, sub := range due {
+			if err := s.mailer.Send(s.dailyUpdateMessage(sub, *latest)); err != nil {
+				log.Printf("Daily update to subscription %d failed: %v", sub.ID, err)
+				continue
+			}
+			if err := s.repo.MarkSent(ctx, sub.ID, latest.Day); err != nil {
+				return sent, err
+			}
+			sent++
+			progressed = true
+		}
+		// Stop after a short page, or when every email in a full page failed
+		// and the same subscribers would be returned again
+		if len(due) < subscriptionSendBatch || !progressed {
+			return sent, nil
+		}
+	}
+}
+
+// Start sends due updates at the given interval in a background goroutine.
+func (s *SubscriptionService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if n, err := s.SendDailyUpdates(ctx); err != nil {
+					log.Printf("Daily update sending failed after %d emails: %v", n, err)
+				}
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background sending started by Start.
+func (s *SubscriptionService) Stop() {
+	close(s.stopChan)
+}
+
+func (s *SubscriptionService) sendConfirmation(sub models.EmailSubscription) error {
+	link := s.link("confirm", sub.ConfirmToken)
+	return s.mailer.Send(mailer.Message{
+		To:      sub.Email,
+		Subject: "Konfirmasi langganan update COVID-19 Sulawesi Tengah",
+		Body: "Terima kasih telah berlangganan update harian COVID-19 Sulawesi Tengah.\n\n" +
+			"Buka tautan berikut untuk mengonfirmasi langganan Anda:\n" + link + "\n\n" +
+			"Abaikan email ini jika Anda tidak meminta langganan ini.\n",
+	})
+}
+
+func (s *SubscriptionService) dailyUpdateMessage(sub models.EmailSubscription, c models.ProvinceCaseWithDate) mailer.Message {
+	unsubscribe := s.link("unsubscribe", sub.UnsubscribeToken)
+	date := c.Date.Format("2006-01-02")
+	var b strings.Builder
+	fmt.Fprintf(&b, "Update COVID-19 Sulawesi Tengah, %s (hari ke-%d)\n\n", date, c.Day)
+	fmt.Fprintf(&b, "Kasus baru:     %d\n", c.Positive)
+	fmt.Fprintf(&b, "Sembuh:         %d\n", c.Recovered)
+	fmt.Fprintf(&b, "Meninggal:      %d\n\n", c.Deceased)
+	fmt.Fprintf(&b, "Total positif:  %d\n", c.CumulativePositive)
+	fmt.Fprintf(&b, "Total sembuh:   %d\n", c.CumulativeRecovered)
+	fmt.Fprintf(&b, "Total meninggal: %d\n", c.CumulativeDeceased)
+	if c.Rt != nil {
+		fmt.Fprintf(&b, "Rt:             %.2f\n", *c.Rt)
+	}
+	fmt.Fprintf(&b, "\nBerhenti berlangganan: %s\n", unsubscribe)
+	return mailer.Message{
+		To:      sub.Email,
+		Subject: "Update COVID-19 Sulawesi Tengah " + date,
+		Body:    b.String(),
+		Headers: map[string]string{"List-Unsubscribe": "<" + unsubscribe + ">"},
+	}
+}
+
+func (s *SubscriptionService) link(action, token string) string {
+	return fmt.Sprintf("%s/api/v1/subscriptions/%s?token=%s", s.baseURL, action, url.QueryEscape(token))
+}
+
+// normalizeEmail lowercases and validates a bare address such as warga@example.com
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || len(email) > 254 {
+		return "", ErrInvalidEmail
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@"):], ".") {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+// newSubscriptionToken returns 32 random bytes hex encoded
+func newSubscriptionToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
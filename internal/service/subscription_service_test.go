@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionRepository) GetByEmail(ctx context.Context, email string) (*models.EmailSubscription, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailSubscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetByConfirmToken(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailSubscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetByUnsubscribeToken(ctx context.Context, token string) (*models.EmailSubscription, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailSubscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Create(ctx context.Context, s models.EmailSubscription) (int64, error) {
+	args := m.Called(s)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Update(ctx context.Context, s models.EmailSubscription) error {
+	return m.Called(s).Error(0)
+}
+
+func (m *MockSubscriptionRepository) ListDue(ctx context.Context, day int64, limit int) ([]models.EmailSubscription, error) {
+	args := m.Called(day, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EmailSubscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) MarkSent(ctx context.Context, id, day int64) error {
+	return m.Called(id, day).Error(0)
+}
+
+type recordingMailer struct {
+	sent []mailer.Message
+	fail map[string]bool
+}
+
+func (m *recordingMailer) Send(msg mailer.Message) error {
+	if m.fail[msg.To] {
+		return errors.New("mailbox unavailable")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var subscriptionTestNow = time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC)
+
+func newTestSubscriptionService(repo *MockSubscriptionRepository, cases *MockProvinceCaseRepository, m Mailer) *SubscriptionService {
+	s := NewSubscriptionService(repo, cases, m, "https://pico.example.com/")
+	s.SetClock(clock.NewMock(subscriptionTestNow))
+	return s
+}
+
+func TestSubscriptionService_Subscribe_New(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByEmail", "warga@example.com").Return(nil, nil)
+	repo.On("Create", mock.MatchedBy(func(s models.EmailSubscription) bool {
+		return s.Email == "warga@example.com" && s.Status == models.SubscriptionPending &&
+			len(s.ConfirmToken) == 64 && len(s.UnsubscribeToken) == 64 && s.ConfirmToken != s.UnsubscribeToken
+	})).Return(int64(1), nil)
+	m := new(recordingMailer)
+
+	err := newTestSubscriptionService(repo, nil, m).Subscribe(context.Background(), "  Warga@Example.com ")
+
+	assert.NoError(t, err)
+	if assert.Len(t, m.sent, 1) {
+		assert.Equal(t, "warga@example.com", m.sent[0].To)
+		assert.Contains(t, m.sent[0].Body, "https://pico.example.com/api/v1/subscriptions/confirm?token=")
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Subscribe_InvalidEmail(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	s := newTestSubscriptionService(repo, nil, new(recordingMailer))
+
+	for _, email := range []string{"", "not-an-email", "Warga <warga@example.com>", "warga@localhost", "a@b.c\r\nBcc: x@y.z"} {
+		assert.ErrorIs(t, s.Subscribe(context.Background(), email), ErrInvalidEmail, email)
+	}
+	repo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+}
+
+func TestSubscriptionService_Subscribe_AlreadyConfirmedSendsNothing(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByEmail", "warga@example.com").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionConfirmed}, nil)
+	m := new(recordingMailer)
+
+	assert.NoError(t, newTestSubscriptionService(repo, nil, m).Subscribe(context.Background(), "warga@example.com"))
+	assert.Empty(t, m.sent)
+}
+
+func TestSubscriptionService_Subscribe_PendingWithinCooldown(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByEmail", "warga@example.com").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionPending,
+		ConfirmationSentAt: subscriptionTestNow.Add(-time.Minute)}, nil)
+	m := new(recordingMailer)
+
+	assert.NoError(t, newTestSubscriptionService(repo, nil, m).Subscribe(context.Background(), "warga@example.com"))
+	assert.Empty(t, m.sent)
+	repo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestSubscriptionService_Subscribe_ResubscribeAfterUnsubscribe(t *testing.T) {
+	unsubscribedAt := subscriptionTestNow.Add(-48 * time.Hour)
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByEmail", "warga@example.com").Return(&models.EmailSubscription{ID: 1, Email: "warga@example.com",
+		Status: models.SubscriptionUnsubscribed, ConfirmToken: "old", UnsubscribedAt: &unsubscribedAt}, nil)
+	repo.On("Update", mock.MatchedBy(func(s models.EmailSubscription) bool {
+		return s.Status == models.SubscriptionPending && s.ConfirmToken != "old" && s.UnsubscribedAt == nil &&
+			s.ConfirmationSentAt.Equal(subscriptionTestNow)
+	})).Return(nil)
+	m := new(recordingMailer)
+
+	assert.NoError(t, newTestSubscriptionService(repo, nil, m).Subscribe(context.Background(), "warga@example.com"))
+	assert.Len(t, m.sent, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Confirm(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByConfirmToken", "tok").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionPending}, nil)
+	repo.On("Update", mock.MatchedBy(func(s models.EmailSubscription) bool {
+		return s.Status == models.SubscriptionConfirmed && s.ConfirmedAt.Equal(subscriptionTestNow)
+	})).Return(nil)
+	repo.On("GetByConfirmToken", "unknown").Return(nil, nil)
+	s := newTestSubscriptionService(repo, nil, nil)
+
+	sub, err := s.Confirm(context.Background(), "tok")
+	assert.NoError(t, err)
+	assert.Equal(t, models.SubscriptionConfirmed, sub.Status)
+
+	sub, err = s.Confirm(context.Background(), "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, sub)
+	repo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Confirm_UnsubscribedTokenIsStale(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByConfirmToken", "tok").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionUnsubscribed}, nil)
+
+	sub, err := newTestSubscriptionService(repo, nil, nil).Confirm(context.Background(), "tok")
+
+	assert.NoError(t, err)
+	assert.Nil(t, sub)
+	repo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestSubscriptionService_Unsubscribe(t *testing.T) {
+	repo := new(MockSubscriptionRepository)
+	repo.On("GetByUnsubscribeToken", "tok").Return(&models.EmailSubscription{ID: 1, Status: models.SubscriptionConfirmed}, nil)
+	repo.On("Update", mock.MatchedBy(func(s models.EmailSubscription) bool {
+		return s.Status == models.SubscriptionUnsubscribed && s.UnsubscribedAt != nil
+	})).Return(nil)
+
+	sub, err := newTestSubscriptionService(repo, nil, nil).Unsubscribe(context.Background(), "tok")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SubscriptionUnsubscribed, sub.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_SendDailyUpdates(t *testing.T) {
+	rt := 0.87
+	cases := new(MockProvinceCaseRepository)
+	cases.On("GetLatestByProvinceID", "72").Return(&models.ProvinceCaseWithDate{
+		ProvinceCase: models.ProvinceCase{Day: 411, ProvinceID: "72", Positive: 12, CumulativePositive: 10242, Rt: &rt},
+		Date:         time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC),
+	}, nil)
+	repo := new(MockSubscriptionRepository)
+	repo.On("ListDue", int64(411), subscriptionSendBatch).Return([]models.EmailSubscription{
+		{ID: 1, Email: "a@example.com", UnsubscribeToken: "u1"},
+		{ID: 2, Email: "b@example.com", UnsubscribeToken: "u2"},
+	}, nil)
+	repo.On("MarkSent", int64(1), int64(411)).Return(nil)
+	m := &recordingMailer{fail: map[string]bool{"b@example.com": true}}
+
+	sent, err := newTestSubscriptionService(repo, cases, m).SendDailyUpdates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	if assert.Len(t, m.sent, 1) {
+		assert.Equal(t, "Update COVID-19 Sulawesi Tengah 2021-06-30", m.sent[0].Subject)
+		assert.Contains(t, m.sent[0].Body, "Kasus baru:     12")
+		assert.Contains(t, m.sent[0].Body, "Rt:             0.87")
+		assert.Equal(t, "<https://pico.example.com/api/v1/subscriptions/unsubscribe?token=u1>", m.sent[0].Headers["List-Unsubscribe"])
+	}
+	repo.AssertNotCalled(t, "MarkSent", int64(2), mock.Anything)
+}
+
+func TestSubscriptionService_SendDailyUpdates_NoData(t *testing.T) {
+	cases := new(MockProvinceCaseRepository)
+	cases.On("GetLatestByProvinceID", "72").Return(nil, nil)
+	repo := new(MockSubscriptionRepository)
+
+	sent, err := newTestSubscriptionService(repo, cases, new(recordingMailer)).SendDailyUpdates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+	repo.AssertNotCalled(t, "ListDue", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+)
+
+// movingAverageDays is the window of the moving averages in the summary
+const movingAverageDays = 7
+
+// SummaryService aggregates the latest national and province figures so
+// dashboards do not need to fetch full histories. It reads through the covid
+// service and therefore shares its cache.
+type SummaryService struct {
+	covid CovidService
+}
+
+// NewSummaryService creates a SummaryService
+func NewSummaryService(covid CovidService) *SummaryService {
+	return &SummaryService{covid: covid}
+}
+
+// GetSummary returns the latest totals and 7-day moving averages of new cases
+// nationally and per province
+func (s *SummaryService) GetSummary(ctx context.Context) (*models.Summary, error) {
+	summary := &models.Summary{Provinces: []models.ProvinceSummary{}}
+
+	national, err := s.nationalSummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if national != nil {
+		summary.National = national
+		summary.LastUpdated = latestDate(summary.LastUpdated, national.Date)
+	}
+
+	provinces, err := s.covid.GetProvincesWithLatestCase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provinces with latest case: %w", err)
+	}
+	byID := make(map[string]int, len(provinces))
+	for _, p := range provinces {
+		if p.LatestCase == nil {
+			continue
+		}
+		c := p.LatestCase
+		byID[p.ID] = len(summary.Provinces)
+		summary.Provinces = append(summary.Provinces, models.ProvinceSummary{
+			Province: p.Province,
+			Day:      c.Day,
+			Date:     c.Date,
+			Totals: models.CaseTotals{
+				Positive:  c.Cumulative.Positive,
+				Recovered: c.Cumulative.Recovered,
+				Deceased:  c.Cumulative.Deceased,
+				Active:    c.Cumulative.Active,
+			},
+			NewCases: models.CaseTotals{
+				Positive:  c.Daily.Positive,
+				Recovered: c.Daily.Recovered,
+				Deceased:  c.Daily.Deceased,
+				Active:    c.Daily.Active,
+			},
+		})
+		summary.LastUpdated = latestDate(summary.LastUpdated, c.Date)
+	}
+	if len(summary.Provinces) == 0 {
+		return summary, nil
+	}
+
+	// One query for the window of every province instead of one per province
+	var end time.Time
+	for _, p := range summary.Provinces {
+		if p.Date.After(end) {
+			end = p.Date
+		}
+	}
+	start := end.AddDate(0, 0, -(movingAverageDays - 1))
+	window, _, err := s.covid.ListProvinceCases(ctx, QueryOptions{
+		Range: DateRange{Start: start, End: end},
+		Sort:  byDateAsc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get province cases for moving averages: %w", err)
+	}
+	for _, c := range window {
+		i, ok := byID[c.ProvinceID]
+		if !ok {
+			continue
+		}
+		p := &summary.Provinces[i]
+		if p.MovingAverage7d == nil {
+			p.MovingAverage7d = &models.MovingAverage{}
+		}
+		p.MovingAverage7d.Add(c.Positive, c.Recovered, c.Deceased)
+	}
+	return summary, nil
+}
+
+func (s *SummaryService) nationalSummary(ctx context.Context) (*models.NationalSummary, error) {
+	recent, _, err := s.covid.ListNationalCases(ctx, QueryOptions{Sort: byDateDesc, Page: Page{Limit: movingAverageDays}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent national cases: %w", err)
+	}
+	if len(recent) == 0 {
+		return nil, nil
+	}
+
+	latest := recent[0]
+	national := &models.NationalSummary{
+		Day:  latest.Day,
+		Date: latest.Date,
+		Totals: models.CaseTotals{
+			Positive:  latest.CumulativePositive,
+			Recovered: latest.CumulativeRecovered,
+			Deceased:  latest.CumulativeDeceased,
+			Active:    latest.CumulativePositive - latest.CumulativeRecovered - latest.CumulativeDeceased,
+		},
+		NewCases: models.CaseTotals{
+			Positive:  latest.Positive,
+			Recovered: latest.Recovered,
+			Deceased:  latest.Deceased,
+			Active:    latest.Positive - latest.Recovered - latest.Deceased,
+		},
+		MovingAverage7d: &models.MovingAverage{},
+	}
+	// Rows are only averaged when they fall into the window ending at the
+	// latest date, so gaps in the data do not stretch it
+	start := latest.Date.AddDate(0, 0, -(movingAverageDays - 1))
+	for _, c := range recent {
+		if !c.Date.Before(start) {
+			national.MovingAverage7d.Add(c.Positive, c.Recovered, c.Deceased)
+		}
+	}
+	return national, nil
+}
+
+func latestDate(current *time.Time, date time.Time) *time.Time {
+	if current == nil || date.After(*current) {
+		return &date
+	}
+	return current
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summaryDate(day int) time.Time {
+	return time.Date(2021, 8, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSummaryService_GetSummary(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	national := []models.NationalCase{
+		{Day: 10, Date: summaryDate(10), Positive: 70, Recovered: 20, Deceased: 7,
+			CumulativePositive: 1000, CumulativeRecovered: 800, CumulativeDeceased: 50},
+		{Day: 9, Date: summaryDate(9), Positive: 50, Recovered: 30, Deceased: 3},
+		// Outside the 7-day window because of a gap in the data
+		{Day: 2, Date: summaryDate(2), Positive: 1000},
+	}
+	mockSvc.On("ListNationalCases", QueryOptions{Sort: utils.SortParams{Field: "date", Order: "desc"}, Page: Page{Limit: 7}}).Return(national, 10, nil)
+	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase{
+		{
+			Province: models.Province{ID: "72", Name: "Sulawesi Tengah"},
+			LatestCase: &models.ProvinceCaseResponse{
+				Day: 11, Date: summaryDate(11),
+				Daily:      models.ProvinceDailyCases{Positive: 4},
+				Cumulative: models.ProvinceCumulativeCases{Positive: 300, Recovered: 250, Deceased: 10, Active: 40},
+			},
+		},
+		{Province: models.Province{ID: "99", Name: "No data"}},
+	}, nil)
+	mockSvc.On("ListProvinceCases", QueryOptions{Range: testDateRange("2021-08-05", "2021-08-11"), Sort: utils.SortParams{Field: "date", Order: "asc"}}).
+		Return([]models.ProvinceCaseWithDate{
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "72", Positive: 2, Recovered: 4}, Date: summaryDate(10)},
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "72", Positive: 4, Recovered: 2}, Date: summaryDate(11)},
+			{ProvinceCase: models.ProvinceCase{ProvinceID: "31", Positive: 100}, Date: summaryDate(11)},
+		}, 0, nil)
+
+	summary, err := NewSummaryService(mockSvc).GetSummary(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, summaryDate(11), *summary.LastUpdated)
+	require.NotNil(t, summary.National)
+	assert.Equal(t, models.CaseTotals{Positive: 1000, Recovered: 800, Deceased: 50, Active: 150}, summary.National.Totals)
+	assert.Equal(t, int64(70), summary.National.NewCases.Positive)
+	assert.Equal(t, &models.MovingAverage{Days: 2, Positive: 60, Recovered: 25, Deceased: 5}, summary.National.MovingAverage7d)
+
+	require.Len(t, summary.Provinces, 1, "provinces without data are left out")
+	province := summary.Provinces[0]
+	assert.Equal(t, "72", province.Province.ID)
+	assert.Equal(t, int64(40), province.Totals.Active)
+	assert.Equal(t, &models.MovingAverage{Days: 2, Positive: 3, Recovered: 3}, province.MovingAverage7d)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSummaryService_GetSummary_NoData(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListNationalCases", QueryOptions{Sort: utils.SortParams{Field: "date", Order: "desc"}, Page: Page{Limit: 7}}).Return([]models.NationalCase{}, 0, nil)
+	mockSvc.On("GetProvincesWithLatestCase").Return([]models.ProvinceWithLatestCase{}, nil)
+
+	summary, err := NewSummaryService(mockSvc).GetSummary(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, summary.LastUpdated)
+	assert.Nil(t, summary.National)
+	assert.Empty(t, summary.Provinces)
+}
+
+func TestSummaryService_GetSummary_Error(t *testing.T) {
+	mockSvc := new(MockCovidService)
+	mockSvc.On("ListNationalCases", QueryOptions{Sort: utils.SortParams{Field: "date", Order: "desc"}, Page: Page{Limit: 7}}).
+		Return([]models.NationalCase(nil), 0, errors.New("db down"))
+
+	_, err := NewSummaryService(mockSvc).GetSummary(context.Background())
+	assert.ErrorContains(t, err, "db down")
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+)
+
+// Sync rollback errors, re-exported so handlers can map them to HTTP statuses
+var (
+	ErrSyncRunNotFound        = repository.ErrSyncRunNotFound
+	ErrSyncRunNotRollbackable = repository.ErrSyncRunNotRollbackable
+	ErrSyncRunConflict        = repository.ErrSyncRunConflict
+)
+
+// SyncRunDetail is a sync run together with the row revisions it produced
+type SyncRunDetail struct {
+	models.SyncRun
+	Revisions []models.Revision `json:"revisions"`
+}
+
+// SyncService exposes ingestion run history and rollback
+type SyncService struct {
+	repo        repository.SyncLogRepository
+	invalidator CacheInvalidator
+}
+
+// NewSyncService creates a SyncService. The invalidator may be nil; when set the
+// cache is cleared after a committed rollback so stale data is not served.
+func NewSyncService(repo repository.SyncLogRepository, invalidator CacheInvalidator) *SyncService {
+	return &SyncService{repo: repo, invalidator: invalidator}
+}
+
+func (s *SyncService) ListRuns(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	runs, err := s.repo.ListRuns(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (s *SyncService) GetRun(ctx context.Context, id int64) (*SyncRunDetail, error) {
+	run, err := s.repo.GetRun(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync run: %w", err)
+	}
+	if run == nil {
+		return nil, nil
+	}
+	revisions, err := s.repo.ListRevisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync run revisions: %w", err)
+	}
+	return &SyncRunDetail{SyncRun: *run, Revisions: revisions}, nil
+}
+
+// RollbackRun atomically reverts all rows written by the given run.
+func (s *SyncService) RollbackRun(ctx context.Context, id int64, dryRun bool) (*models.ChangeSummary, error) {
+	summary, err := s.repo.RollbackRun(ctx, id, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back sync run %d: %w", id, err)
+	}
+	if !dryRun && s.invalidator != nil {
+		s.invalidator.Clear()
+	}
+	return summary, nil
+}
+
+// RollbackLatestRun reverts the most recent sync run.
+func (s *SyncService) RollbackLatestRun(ctx context.Context, dryRun bool) (*models.ChangeSummary, error) {
+	run, err := s.repo.GetLatestRun(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest sync run: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("failed to roll back latest sync run: %w", ErrSyncRunNotFound)
+	}
+	return s.RollbackRun(ctx, run.ID, dryRun)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSyncLogRepository mocks repository.SyncLogRepository
+type MockSyncLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockSyncLogRepository) CreateRun(ctx context.Context, source string) (int64, error) {
+	args := m.Called(source)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSyncLogRepository) FinishRun(ctx context.Context, id int64, status string, inserted, updated, conflicts int) error {
+	return m.Called(id, status, inserted, updated, conflicts).Error(0)
+}
+
+func (m *MockSyncLogRepository) GetRun(ctx context.Context, id int64) (*models.SyncRun, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SyncRun), args.Error(1)
+}
+
+func (m *MockSyncLogRepository) GetLatestRun(ctx context.Context) (*models.SyncRun, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SyncRun), args.Error(1)
+}
+
+func (m *MockSyncLogRepository) ListRuns(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]models.SyncRun), args.Error(1)
+}
+
+func (m *MockSyncLogRepository) ListRevisions(ctx context.Context, runID int64) ([]models.Revision, error) {
+	args := m.Called(runID)
+	return args.Get(0).([]models.Revision), args.Error(1)
+}
+
+func (m *MockSyncLogRepository) RecordRevision(ctx context.Context, rev models.Revision) error {
+	return m.Called(rev).Error(0)
+}
+
+func (m *MockSyncLogRepository) RollbackRun(ctx context.Context, runID int64, dryRun bool) (*models.ChangeSummary, error) {
+	args := m.Called(runID, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeSummary), args.Error(1)
+}
+
+func TestSyncService_RollbackRun_ClearsCache(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("RollbackRun", int64(3), false).Return(&models.ChangeSummary{Deleted: 2}, nil)
+	invalidator := new(countingInvalidator)
+
+	svc := NewSyncService(repo, invalidator)
+	summary, err := svc.RollbackRun(context.Background(), 3, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Deleted)
+	assert.Equal(t, 1, invalidator.clears)
+	repo.AssertExpectations(t)
+}
+
+func TestSyncService_RollbackRun_DryRunKeepsCache(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("RollbackRun", int64(3), true).Return(&models.ChangeSummary{DryRun: true}, nil)
+	invalidator := new(countingInvalidator)
+
+	svc := NewSyncService(repo, invalidator)
+	_, err := svc.RollbackRun(context.Background(), 3, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, invalidator.clears)
+}
+
+func TestSyncService_RollbackLatestRun_NoRuns(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetLatestRun").Return(nil, nil)
+
+	svc := NewSyncService(repo, nil)
+	_, err := svc.RollbackLatestRun(context.Background(), false)
+
+	assert.True(t, errors.Is(err, ErrSyncRunNotFound))
+}
+
+func TestSyncService_GetRun(t *testing.T) {
+	repo := new(MockSyncLogRepository)
+	repo.On("GetRun", int64(4)).Return(&models.SyncRun{ID: 4}, nil)
+	repo.On("ListRevisions", int64(4)).Return([]models.Revision{{ID: 1}}, nil)
+
+	svc := NewSyncService(repo, nil)
+	detail, err := svc.GetRun(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), detail.ID)
+	assert.Len(t, detail.Revisions, 1)
+}
+
+type countingInvalidator struct {
+	clears int
+}
+
+func (c *countingInvalidator) Clear() {
+	c.clears++
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// quotaWarnPercent is the share of the disk quota above which a warning is raised.
+const quotaWarnPercent = 80.0
+
+// TableStatsMonitor periodically collects per-table row counts and sizes and
+// flags unexpected growth between collections.
+type TableStatsMonitor struct {
+	repo              repository.TableStatsRepository
+	growthWarnPercent float64
+	quotaBytes        int64
+
+	mu       sync.RWMutex
+	snapshot *models.TableStatsSnapshot
+	clock    clock.Clock
+	stopChan chan struct{}
+}
+
+// NewTableStatsMonitor creates a monitor. growthWarnPercent is the row or size growth
+// between two collections that triggers a warning; quotaBytes of 0 disables quota checks.
+func NewTableStatsMonitor(repo repository.TableStatsRepository, growthWarnPercent float64, quotaBytes int64) *TableStatsMonitor {
+	return &TableStatsMonitor{
+		repo:              repo,
+		growthWarnPercent: growthWarnPercent,
+		quotaBytes:        quotaBytes,
+		clock:             clock.System,
+		stopChan:          make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock of the snapshots and the collection schedule
+func (m *TableStatsMonitor) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// Collect reads the current table statistics and stores them as the latest snapshot.
+func (m *TableStatsMonitor) Collect(ctx context.Context) error {
+	tables, err := m.repo.GetTableStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect table stats: %w", err)
+	}
+
+	snapshot := &models.TableStatsSnapshot{
+		CollectedAt: m.clock.Now().UTC(),
+		Tables:      tables,
+		QuotaBytes:  m.quotaBytes,
+	}
+	for _, t := range tables {
+		snapshot.TotalRows += t.Rows
+		snapshot.TotalBytes += t.TotalBytes
+	}
+
+	m.mu.Lock()
+	previous := m.snapshot
+	m.mu.Unlock()
+
+	snapshot.Warnings = m.detectGrowth(previous, snapshot)
+
+	if m.quotaBytes > 0 {
+		snapshot.QuotaUsedPercent = float64(snapshot.TotalBytes) / float64(m.quotaBytes) * 100
+		if snapshot.QuotaUsedPercent >= quotaWarnPercent {
+			snapshot.Warnings = append(snapshot.Warnings,
+				fmt.Sprintf("database uses %.1f%% of the %d byte disk quota", snapshot.QuotaUsedPercent, m.quotaBytes))
+		}
+	}
+
+	for _, w := range snapshot.Warnings {
+		log.Printf("Table stats warning: %s", w)
+	}
+
+	m.mu.Lock()
+	m.snapshot = snapshot
+	m.mu.Unlock()
+
+	return nil
+}
+
+// detectGrowth compares two snapshots and describes tables that grew faster than the threshold.
+func (m *TableStatsMonitor) detectGrowth(previous, current *models.TableStatsSnapshot) []string {
+	if previous == nil || m.growthWarnPercent <= 0 {
+		return nil
+	}
+
+	before := make(map[string]models.TableStats, len(previous.Tables))
+	for _, t := range previous.Tables {
+		before[t.Name] = t
+	}
+
+	var warnings []string
+	for _, t := range current.Tables {
+		prev, ok := before[t.Name]
+		if !ok {
+			continue
+		}
+		if pct, grew := growthPercent(prev.Rows, t.Rows); grew && pct >= m.growthWarnPercent {
+			warnings = append(warnings, fmt.Sprintf("table %s rows grew %.1f%% (%d -> %d)", t.Name, pct, prev.Rows, t.Rows))
+		}
+		if pct, grew := growthPercent(prev.TotalBytes, t.TotalBytes); grew && pct >= m.growthWarnPercent {
+			warnings = append(warnings, fmt.Sprintf("table %s size grew %.1f%% (%d -> %d bytes)", t.Name, pct, prev.TotalBytes, t.TotalBytes))
+		}
+	}
+	return warnings
+}
+
+func growthPercent(before, after int64) (float64, bool) {
+	if before <= 0 || after <= before {
+		return 0, false
+	}
+	return float64(after-before) / float64(before) * 100, true
+}
+
+// Snapshot returns the most recent collection, or nil if none has completed yet.
+func (m *TableStatsMonitor) Snapshot() *models.TableStatsSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshot
+}
+
+// Start collects immediately and then at the given interval in a background goroutine.
+func (m *TableStatsMonitor) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		if err := m.Collect(ctx); err != nil {
+			log.Printf("Table stats collection failed: %v", err)
+		}
+
+		ticker := m.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if err := m.Collect(ctx); err != nil {
+					log.Printf("Table stats collection failed: %v", err)
+				}
+			case <-m.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background collection started by Start.
+func (m *TableStatsMonitor) Stop() {
+	close(m.stopChan)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTableStatsRepository mocks repository.TableStatsRepository
+type MockTableStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockTableStatsRepository) GetTableStats(ctx context.Context) ([]models.TableStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TableStats), args.Error(1)
+}
+
+func TestTableStatsMonitor_Collect(t *testing.T) {
+	repo := new(MockTableStatsRepository)
+	repo.On("GetTableStats").Return([]models.TableStats{
+		{Name: "national_cases", Rows: 100, TotalBytes: 1000},
+		{Name: "province_cases", Rows: 3400, TotalBytes: 9000},
+	}, nil)
+
+	monitor := NewTableStatsMonitor(repo, 50, 0)
+	assert.Nil(t, monitor.Snapshot())
+
+	err := monitor.Collect(context.Background())
+
+	assert.NoError(t, err)
+	snapshot := monitor.Snapshot()
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, int64(3500), snapshot.TotalRows)
+	assert.Equal(t, int64(10000), snapshot.TotalBytes)
+	assert.Len(t, snapshot.Tables, 2)
+	assert.Empty(t, snapshot.Warnings)
+	repo.AssertExpectations(t)
+}
+
+func TestTableStatsMonitor_Collect_WarnsOnGrowth(t *testing.T) {
+	repo := new(MockTableStatsRepository)
+	repo.On("GetTableStats").Return([]models.TableStats{
+		{Name: "audit_logs", Rows: 100, TotalBytes: 1000},
+		{Name: "provinces", Rows: 34, TotalBytes: 100},
+	}, nil).Once()
+	repo.On("GetTableStats").Return([]models.TableStats{
+		{Name: "audit_logs", Rows: 300, TotalBytes: 1200},
+		{Name: "provinces", Rows: 34, TotalBytes: 100},
+	}, nil).Once()
+
+	monitor := NewTableStatsMonitor(repo, 50, 0)
+
+	assert.NoError(t, monitor.Collect(context.Background()))
+	assert.NoError(t, monitor.Collect(context.Background()))
+
+	warnings := monitor.Snapshot().Warnings
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "audit_logs rows grew 200.0%")
+	repo.AssertExpectations(t)
+}
+
+func TestTableStatsMonitor_Collect_WarnsOnQuota(t *testing.T) {
+	repo := new(MockTableStatsRepository)
+	repo.On("GetTableStats").Return([]models.TableStats{
+		{Name: "province_cases", Rows: 10, TotalBytes: 900},
+	}, nil)
+
+	monitor := NewTableStatsMonitor(repo, 50, 1000)
+
+	assert.NoError(t, monitor.Collect(context.Background()))
+
+	snapshot := monitor.Snapshot()
+	assert.InDelta(t, 90.0, snapshot.QuotaUsedPercent, 0.001)
+	assert.Len(t, snapshot.Warnings, 1)
+	assert.Contains(t, snapshot.Warnings[0], "disk quota")
+}
+
+func TestTableStatsMonitor_Collect_Error(t *testing.T) {
+	repo := new(MockTableStatsRepository)
+	repo.On("GetTableStats").Return(nil, errors.New("db down"))
+
+	monitor := NewTableStatsMonitor(repo, 50, 0)
+
+	err := monitor.Collect(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to collect table stats")
+	assert.Nil(t, monitor.Snapshot())
+}
+
+func TestTableStatsMonitor_Start(t *testing.T) {
+	repo := new(MockTableStatsRepository)
+	repo.On("GetTableStats").Return([]models.TableStats{{Name: "national_cases", Rows: 100, TotalBytes: 1000}}, nil)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewMock(start)
+	monitor := NewTableStatsMonitor(repo, 50, 0)
+	monitor.SetClock(clk)
+
+	monitor.Start(time.Hour)
+	defer monitor.Stop()
+	collectedAt := func() time.Time {
+		if snapshot := monitor.Snapshot(); snapshot != nil {
+			return snapshot.CollectedAt
+		}
+		return time.Time{}
+	}
+	require.Eventually(t, func() bool { return clk.Waiting() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, start, collectedAt(), "collects right away")
+
+	clk.Advance(time.Hour)
+	assert.Eventually(t, func() bool { return collectedAt().Equal(start.Add(time.Hour)) }, time.Second, time.Millisecond)
+	repo.AssertNumberOfCalls(t, "GetTableStats", 2)
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 )
 
 // TaskForceService handles business logic for task forces
@@ -15,12 +18,12 @@ func NewTaskForceService(taskForceRepo repository.TaskForceRepositoryInterface)
 	return &TaskForceService{taskForceRepo: taskForceRepo}
 }
 
-// GetTaskForces returns all task forces grouped by regency in SulTeng (province_id=72)
-func (s *TaskForceService) GetTaskForces() ([]models.TaskForceByRegency, error) {
-	return s.taskForceRepo.GetAllByProvinceID(72)
+// GetTaskForces returns all task forces grouped by regency in the request's tenant province
+func (s *TaskForceService) GetTaskForces(ctx context.Context) ([]models.TaskForceByRegency, error) {
+	return s.taskForceRepo.GetAllByProvinceID(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
 // GetTaskForcesPaginated returns a page of task forces grouped by regency with total count
-func (s *TaskForceService) GetTaskForcesPaginated(limit, offset int) ([]models.TaskForceByRegency, int, error) {
-	return s.taskForceRepo.GetPaginatedByProvinceID(72, limit, offset)
+func (s *TaskForceService) GetTaskForcesPaginated(ctx context.Context, limit, offset int) ([]models.TaskForceByRegency, int, error) {
+	return s.taskForceRepo.GetPaginatedByProvinceID(ctx, tenant.FromContext(ctx).ProvinceNumber(), limit, offset)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -14,12 +15,12 @@ type MockTaskForceRepository struct {
 	mock.Mock
 }
 
-func (m *MockTaskForceRepository) GetPaginatedByProvinceID(provinceID, limit, offset int) ([]models.TaskForceByRegency, int, error) {
+func (m *MockTaskForceRepository) GetPaginatedByProvinceID(ctx context.Context, provinceID, limit, offset int) ([]models.TaskForceByRegency, int, error) {
 	args := m.Called(provinceID, limit, offset)
 	return args.Get(0).([]models.TaskForceByRegency), args.Int(1), args.Error(2)
 }
 
-func (m *MockTaskForceRepository) GetAllByProvinceID(provinceID int) ([]models.TaskForceByRegency, error) {
+func (m *MockTaskForceRepository) GetAllByProvinceID(ctx context.Context, provinceID int) ([]models.TaskForceByRegency, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.TaskForceByRegency), args.Error(1)
 }
@@ -49,7 +50,7 @@ func TestTaskForceService_GetTaskForces(t *testing.T) {
 	}
 	mockRepo.On("GetAllByProvinceID", 72).Return(expected, nil)
 
-	result, err := svc.GetTaskForces()
+	result, err := svc.GetTaskForces(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -61,7 +62,7 @@ func TestTaskForceService_GetTaskForces_Empty(t *testing.T) {
 
 	mockRepo.On("GetAllByProvinceID", 72).Return([]models.TaskForceByRegency{}, nil)
 
-	result, err := svc.GetTaskForces()
+	result, err := svc.GetTaskForces(context.Background())
 
 	assert.NoError(t, err)
 	assert.Empty(t, result)
@@ -73,7 +74,7 @@ func TestTaskForceService_GetTaskForces_Error(t *testing.T) {
 
 	mockRepo.On("GetAllByProvinceID", 72).Return([]models.TaskForceByRegency{}, errors.New("db error"))
 
-	result, err := svc.GetTaskForces()
+	result, err := svc.GetTaskForces(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -90,7 +91,7 @@ func TestTaskForceService_GetTaskForcesPaginated(t *testing.T) {
 	}
 	mockRepo.On("GetPaginatedByProvinceID", 72, 10, 0).Return(expected, 1, nil)
 
-	result, total, err := svc.GetTaskForcesPaginated(10, 0)
+	result, total, err := svc.GetTaskForcesPaginated(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -103,7 +104,7 @@ func TestTaskForceService_GetTaskForcesPaginated_Error(t *testing.T) {
 
 	mockRepo.On("GetPaginatedByProvinceID", 72, 10, 0).Return([]models.TaskForceByRegency{}, 0, errors.New("db error"))
 
-	result, total, err := svc.GetTaskForcesPaginated(10, 0)
+	result, total, err := svc.GetTaskForcesPaginated(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+)
+
+// firstTestDate bounds requests without a date range
+var firstTestDate = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// TestingService serves the national test counts with positivity rates
+type TestingService struct {
+	tests repository.TestRepository
+	covid CovidService
+	clock clock.Clock
+}
+
+// NewTestingService creates a TestingService. Positive cases are read through
+// the covid service and therefore share its cache.
+func NewTestingService(tests repository.TestRepository, covid CovidService) *TestingService {
+	return &TestingService{tests: tests, covid: covid, clock: clock.System}
+}
+
+// SetClock sets the clock that ends requests without a date range
+func (s *TestingService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// GetNationalTests returns the national test counts within dates, or of
+// every day when it is not set. Days without a national case row have no
+// positivity rates.
+func (s *TestingService) GetNationalTests(ctx context.Context, dates DateRange) ([]models.TestingDay, error) {
+	start, end := firstTestDate, s.clock.Now().UTC()
+	if dates.IsSet() {
+		start, end = dates.Start, dates.End
+	}
+
+	tests, err := s.tests.GetNationalByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get national tests: %w", err)
+	}
+	days := make([]models.TestingDay, 0, len(tests))
+	if len(tests) == 0 {
+		return days, nil
+	}
+
+	cases, _, err := s.covid.ListNationalCases(ctx, QueryOptions{
+		Range: DateRange{Start: tests[0].Date, End: tests[len(tests)-1].Date},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get national cases: %w", err)
+	}
+	byDate := make(map[string]models.NationalCase, len(cases))
+	for _, c := range cases {
+		byDate[c.Date.Format("2006-01-02")] = c
+	}
+
+	for _, t := range tests {
+		day := models.TestingDay{Date: t.Date}
+		if c, ok := byDate[t.Date.Format("2006-01-02")]; ok {
+			day.TestingStatistics = t.Statistics(c.Positive, c.CumulativePositive)
+		} else {
+			day.TestingStatistics = t.Statistics(0, 0)
+			day.PositivityRate, day.CumulativePositivityRate = nil, nil
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestingService_GetNationalTests(t *testing.T) {
+	testRepo := new(MockTestRepository)
+	covid := new(MockCovidService)
+	svc := NewTestingService(testRepo, covid)
+
+	day1 := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	testRepo.On("GetNationalByDateRange", day1, day2).Return([]models.DailyTests{
+		{Date: day1, PCR: 800, Antigen: 200, CumulativePCR: 8000, CumulativeAntigen: 2000},
+		{Date: day2, PCR: 500},
+	}, nil)
+	covid.On("ListNationalCases", QueryOptions{Range: testDateRange("2021-07-01", "2021-07-02")}).Return([]models.NationalCase{
+		{Date: day1, Positive: 100, CumulativePositive: 500},
+	}, 0, nil)
+
+	days, err := svc.GetNationalTests(context.Background(), testDateRange("2021-07-01", "2021-07-02"))
+
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.Equal(t, int64(1000), days[0].Daily.Total)
+	assert.InDelta(t, 10.0, *days[0].PositivityRate, 0.0001)
+	assert.InDelta(t, 5.0, *days[0].CumulativePositivityRate, 0.0001)
+	assert.Equal(t, int64(500), days[1].Daily.Total)
+	assert.Nil(t, days[1].PositivityRate)
+}
+
+func TestTestingService_GetNationalTests_Empty(t *testing.T) {
+	testRepo := new(MockTestRepository)
+	svc := NewTestingService(testRepo, new(MockCovidService))
+	today := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.SetClock(clock.NewMock(today))
+	testRepo.On("GetNationalByDateRange", firstTestDate, today).Return([]models.DailyTests{}, nil)
+
+	days, err := svc.GetNationalTests(context.Background(), DateRange{})
+
+	require.NoError(t, err)
+	assert.NotNil(t, days)
+	assert.Empty(t, days)
+}
+
+func TestTestingService_GetNationalTests_Errors(t *testing.T) {
+	testRepo := new(MockTestRepository)
+	svc := NewTestingService(testRepo, new(MockCovidService))
+
+	testRepo.On("GetNationalByDateRange", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]models.DailyTests(nil), errors.New("table missing"))
+	_, err := svc.GetNationalTests(context.Background(), testDateRange("2021-07-01", "2021-07-02"))
+	assert.ErrorContains(t, err, "failed to get national tests")
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+)
+
+// tracedCovidService records a span for every call to the wrapped CovidService.
+// Wrapped around the cached service, cache hits show up as spans without SQL
+// children.
+type tracedCovidService struct {
+	svc CovidService
+}
+
+// NewTracedCovidService returns a CovidService recording a span per call
+func NewTracedCovidService(svc CovidService) CovidService {
+	return &tracedCovidService{svc: svc}
+}
+
+// SetClock passes the clock on to the wrapped service
+func (s *tracedCovidService) SetClock(c clock.Clock) {
+	if setter, ok := s.svc.(ClockSetter); ok {
+		setter.SetClock(c)
+	}
+}
+
+func endSpan(span *tracing.Span, err error) {
+	span.RecordError(err)
+	span.End()
+}
+
+func (s *tracedCovidService) ListNationalCases(ctx context.Context, opts QueryOptions) (result []models.NationalCase, total int, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.ListNationalCases", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.ListNationalCases(ctx, opts)
+}
+
+func (s *tracedCovidService) EachNationalCase(ctx context.Context, opts QueryOptions, fn func([]models.NationalCase) error) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.EachNationalCase", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.EachNationalCase(ctx, opts, fn)
+}
+
+func (s *tracedCovidService) GetLatestNationalCase(ctx context.Context) (result *models.NationalCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetLatestNationalCase", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetLatestNationalCase(ctx)
+}
+
+func (s *tracedCovidService) ResolveDateRange(ctx context.Context, preset string) (startDate, endDate string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.ResolveDateRange", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.ResolveDateRange(ctx, preset)
+}
+
+func (s *tracedCovidService) GetNationalCaseByDay(ctx context.Context, day int64) (result *models.NationalCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetNationalCaseByDay", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetNationalCaseByDay(ctx, day)
+}
+
+func (s *tracedCovidService) GetNationalCaseByDate(ctx context.Context, date time.Time) (result *models.NationalCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetNationalCaseByDate", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetNationalCaseByDate(ctx, date)
+}
+
+func (s *tracedCovidService) GetProvinces(ctx context.Context) (result []models.Province, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvinces", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvinces(ctx)
+}
+
+func (s *tracedCovidService) GetProvinceByID(ctx context.Context, id string) (result *models.Province, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvinceByID", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvinceByID(ctx, id)
+}
+
+func (s *tracedCovidService) GetProvincesWithLatestCase(ctx context.Context) (result []models.ProvinceWithLatestCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvincesWithLatestCase", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvincesWithLatestCase(ctx)
+}
+
+func (s *tracedCovidService) GetProvincesWithLatestCaseByIDs(ctx context.Context, ids []string) (result []models.ProvinceWithLatestCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvincesWithLatestCaseByIDs", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvincesWithLatestCaseByIDs(ctx, ids)
+}
+
+func (s *tracedCovidService) ListProvinceCases(ctx context.Context, opts QueryOptions) (result []models.ProvinceCaseWithDate, total int, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.ListProvinceCases", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.ListProvinceCases(ctx, opts)
+}
+
+func (s *tracedCovidService) EachProvinceCase(ctx context.Context, opts QueryOptions, fn func([]models.ProvinceCaseWithDate) error) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.EachProvinceCase", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.EachProvinceCase(ctx, opts, fn)
+}
+
+func (s *tracedCovidService) GetProvinceCaseByDate(ctx context.Context, provinceID string, date time.Time) (result *models.ProvinceCaseWithDate, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvinceCaseByDate", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvinceCaseByDate(ctx, provinceID, date)
+}
+
+func (s *tracedCovidService) GetProvinceCasesAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) (result []models.ProvinceCaseWithDate, next *models.CaseCursor, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvinceCasesAfterCursor", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvinceCasesAfterCursor(ctx, q)
+}
+
+func (s *tracedCovidService) GetNationalCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) (result []models.AggregatedCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetNationalCasesAggregated", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetNationalCasesAggregated(ctx, q)
+}
+
+func (s *tracedCovidService) GetNationalTotals(ctx context.Context, dates DateRange) (result models.PeriodTotals, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetNationalTotals", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetNationalTotals(ctx, dates)
+}
+
+func (s *tracedCovidService) GetProvinceCasesAggregated(ctx context.Context, q repository.CaseAggregateQuery) (result []models.AggregatedCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.GetProvinceCasesAggregated", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.GetProvinceCasesAggregated(ctx, q)
+}
+
+func (s *tracedCovidService) AddNationalMovingAverages(ctx context.Context, cases []models.NationalCase, days int) (result []models.NationalCase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.AddNationalMovingAverages", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.AddNationalMovingAverages(ctx, cases, days)
+}
+
+func (s *tracedCovidService) AddProvinceMovingAverages(ctx context.Context, cases []models.ProvinceCaseWithDate, days int) (result []models.ProvinceCaseWithDate, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CovidService.AddProvinceMovingAverages", tracing.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+	return s.svc.AddProvinceMovingAverages(ctx, cases, days)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanCollector struct {
+	spans []tracing.SpanData
+}
+
+func (c *spanCollector) Export(ctx context.Context, spans []tracing.SpanData) error {
+	c.spans = append(c.spans, spans...)
+	return nil
+}
+
+func TestTracedCovidService_RecordsSpans(t *testing.T) {
+	collector := &spanCollector{}
+	recorder := tracing.NewRecorder(collector, tracing.RecorderOptions{FlushInterval: time.Hour})
+	tracing.SetRecorder(recorder)
+	defer tracing.SetRecorder(nil)
+
+	inner := new(MockCovidService)
+	inner.On("GetLatestNationalCase").Return(&models.NationalCase{Day: 10}, nil)
+	inner.On("GetProvinceByID", "99").Return(nil, errors.New("not found"))
+	svc := NewTracedCovidService(inner)
+
+	parent := tracing.New()
+	ctx := tracing.NewContext(context.Background(), parent)
+	latest, err := svc.GetLatestNationalCase(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), latest.Day)
+	_, err = svc.GetProvinceByID(ctx, "99")
+	assert.Error(t, err)
+
+	require.NoError(t, recorder.Shutdown(context.Background()))
+	require.Len(t, collector.spans, 2)
+	assert.Equal(t, "CovidService.GetLatestNationalCase", collector.spans[0].Name)
+	assert.Equal(t, parent.SpanID, collector.spans[0].ParentSpanID)
+	assert.Empty(t, collector.spans[0].Error)
+	assert.Equal(t, "CovidService.GetProvinceByID", collector.spans[1].Name)
+	assert.Equal(t, "not found", collector.spans[1].Error)
+	inner.AssertExpectations(t)
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+
 	"github.com/banua-coder/pico-api-go/internal/models"
 	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/tenant"
 )
 
 type VaccinationService struct {
@@ -13,26 +16,38 @@ func NewVaccinationService(vaccinationRepo repository.VaccinationRepositoryInter
 	return &VaccinationService{vaccinationRepo: vaccinationRepo}
 }
 
-func (s *VaccinationService) GetNationalVaccinations() ([]models.NationalVaccine, error) {
-	return s.vaccinationRepo.GetNationalVaccinations()
+func (s *VaccinationService) GetNationalVaccinations(ctx context.Context) ([]models.NationalVaccine, error) {
+	return s.vaccinationRepo.GetNationalVaccinations(ctx)
+}
+
+func (s *VaccinationService) GetNationalVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.NationalVaccine, int, error) {
+	return s.vaccinationRepo.GetNationalVaccinationsPaginated(ctx, limit, offset)
+}
+
+func (s *VaccinationService) QueryNationalVaccinations(ctx context.Context, q repository.VaccinationQuery) ([]models.NationalVaccine, int, error) {
+	return s.vaccinationRepo.QueryNationalVaccinations(ctx, q)
+}
+
+func (s *VaccinationService) GetProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error) {
+	return s.vaccinationRepo.GetProvinceVaccinations(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
-func (s *VaccinationService) GetNationalVaccinationsPaginated(limit, offset int) ([]models.NationalVaccine, int, error) {
-	return s.vaccinationRepo.GetNationalVaccinationsPaginated(limit, offset)
+func (s *VaccinationService) GetProvinceVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.ProvinceVaccine, int, error) {
+	return s.vaccinationRepo.GetProvinceVaccinationsPaginated(ctx, tenant.FromContext(ctx).ProvinceNumber(), limit, offset)
 }
 
-func (s *VaccinationService) GetProvinceVaccinations() ([]models.ProvinceVaccine, error) {
-	return s.vaccinationRepo.GetProvinceVaccinations(72)
+func (s *VaccinationService) QueryProvinceVaccinations(ctx context.Context, provinceID int, q repository.VaccinationQuery) ([]models.ProvinceVaccine, int, error) {
+	return s.vaccinationRepo.QueryProvinceVaccinations(ctx, provinceID, q)
 }
 
-func (s *VaccinationService) GetProvinceVaccinationsPaginated(limit, offset int) ([]models.ProvinceVaccine, int, error) {
-	return s.vaccinationRepo.GetProvinceVaccinationsPaginated(72, limit, offset)
+func (s *VaccinationService) GetLatestProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error) {
+	return s.vaccinationRepo.GetLatestProvinceVaccinations(ctx)
 }
 
-func (s *VaccinationService) GetVaccineLocations() ([]models.VaccineLocation, error) {
-	return s.vaccinationRepo.GetVaccineLocations(72)
+func (s *VaccinationService) GetVaccineLocations(ctx context.Context) ([]models.VaccineLocation, error) {
+	return s.vaccinationRepo.GetVaccineLocations(ctx, tenant.FromContext(ctx).ProvinceNumber())
 }
 
-func (s *VaccinationService) GetVaccineLocationsPaginated(limit, offset int) ([]models.VaccineLocation, int, error) {
-	return s.vaccinationRepo.GetVaccineLocationsPaginated(72, limit, offset)
+func (s *VaccinationService) GetVaccineLocationsPaginated(ctx context.Context, limit, offset int) ([]models.VaccineLocation, int, error) {
+	return s.vaccinationRepo.GetVaccineLocationsPaginated(ctx, tenant.FromContext(ctx).ProvinceNumber(), limit, offset)
 }
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,36 +17,51 @@ type MockVaccinationRepository struct {
 	mock.Mock
 }
 
-func (m *MockVaccinationRepository) GetNationalVaccinations() ([]models.NationalVaccine, error) {
+func (m *MockVaccinationRepository) GetNationalVaccinations(ctx context.Context) ([]models.NationalVaccine, error) {
 	args := m.Called()
 	return args.Get(0).([]models.NationalVaccine), args.Error(1)
 }
 
-func (m *MockVaccinationRepository) GetNationalVaccinationsPaginated(limit, offset int) ([]models.NationalVaccine, int, error) {
+func (m *MockVaccinationRepository) GetNationalVaccinationsPaginated(ctx context.Context, limit, offset int) ([]models.NationalVaccine, int, error) {
 	args := m.Called(limit, offset)
 	return args.Get(0).([]models.NationalVaccine), args.Int(1), args.Error(2)
 }
 
-func (m *MockVaccinationRepository) GetProvinceVaccinationsPaginated(provinceID, limit, offset int) ([]models.ProvinceVaccine, int, error) {
+func (m *MockVaccinationRepository) GetProvinceVaccinationsPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.ProvinceVaccine, int, error) {
 	args := m.Called(provinceID, limit, offset)
 	return args.Get(0).([]models.ProvinceVaccine), args.Int(1), args.Error(2)
 }
 
-func (m *MockVaccinationRepository) GetVaccineLocationsPaginated(provinceID, limit, offset int) ([]models.VaccineLocation, int, error) {
+func (m *MockVaccinationRepository) GetVaccineLocationsPaginated(ctx context.Context, provinceID, limit, offset int) ([]models.VaccineLocation, int, error) {
 	args := m.Called(provinceID, limit, offset)
 	return args.Get(0).([]models.VaccineLocation), args.Int(1), args.Error(2)
 }
 
-func (m *MockVaccinationRepository) GetProvinceVaccinations(provinceID int) ([]models.ProvinceVaccine, error) {
+func (m *MockVaccinationRepository) GetProvinceVaccinations(ctx context.Context, provinceID int) ([]models.ProvinceVaccine, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.ProvinceVaccine), args.Error(1)
 }
 
-func (m *MockVaccinationRepository) GetVaccineLocations(provinceID int) ([]models.VaccineLocation, error) {
+func (m *MockVaccinationRepository) GetVaccineLocations(ctx context.Context, provinceID int) ([]models.VaccineLocation, error) {
 	args := m.Called(provinceID)
 	return args.Get(0).([]models.VaccineLocation), args.Error(1)
 }
 
+func (m *MockVaccinationRepository) QueryNationalVaccinations(ctx context.Context, q repository.VaccinationQuery) ([]models.NationalVaccine, int, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.NationalVaccine), args.Int(1), args.Error(2)
+}
+
+func (m *MockVaccinationRepository) GetLatestProvinceVaccinations(ctx context.Context) ([]models.ProvinceVaccine, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ProvinceVaccine), args.Error(1)
+}
+
+func (m *MockVaccinationRepository) QueryProvinceVaccinations(ctx context.Context, provinceID int, q repository.VaccinationQuery) ([]models.ProvinceVaccine, int, error) {
+	args := m.Called(provinceID, q)
+	return args.Get(0).([]models.ProvinceVaccine), args.Int(1), args.Error(2)
+}
+
 func setupVaccinationService() (*MockVaccinationRepository, *VaccinationService) {
 	mockRepo := new(MockVaccinationRepository)
 	svc := NewVaccinationService(mockRepo)
@@ -60,7 +77,7 @@ func TestVaccinationService_GetNationalVaccinations(t *testing.T) {
 	}
 	mockRepo.On("GetNationalVaccinations").Return(expected, nil)
 
-	result, err := svc.GetNationalVaccinations()
+	result, err := svc.GetNationalVaccinations(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -72,7 +89,7 @@ func TestVaccinationService_GetNationalVaccinations_Error(t *testing.T) {
 
 	mockRepo.On("GetNationalVaccinations").Return([]models.NationalVaccine{}, errors.New("db error"))
 
-	result, err := svc.GetNationalVaccinations()
+	result, err := svc.GetNationalVaccinations(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -87,7 +104,7 @@ func TestVaccinationService_GetProvinceVaccinations(t *testing.T) {
 	}
 	mockRepo.On("GetProvinceVaccinations", 72).Return(expected, nil)
 
-	result, err := svc.GetProvinceVaccinations()
+	result, err := svc.GetProvinceVaccinations(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -99,7 +116,7 @@ func TestVaccinationService_GetProvinceVaccinations_Error(t *testing.T) {
 
 	mockRepo.On("GetProvinceVaccinations", 72).Return([]models.ProvinceVaccine{}, errors.New("db error"))
 
-	result, err := svc.GetProvinceVaccinations()
+	result, err := svc.GetProvinceVaccinations(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -115,7 +132,7 @@ func TestVaccinationService_GetVaccineLocations(t *testing.T) {
 	}
 	mockRepo.On("GetVaccineLocations", 72).Return(expected, nil)
 
-	result, err := svc.GetVaccineLocations()
+	result, err := svc.GetVaccineLocations(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -127,7 +144,7 @@ func TestVaccinationService_GetVaccineLocations_Error(t *testing.T) {
 
 	mockRepo.On("GetVaccineLocations", 72).Return([]models.VaccineLocation{}, errors.New("db error"))
 
-	result, err := svc.GetVaccineLocations()
+	result, err := svc.GetVaccineLocations(context.Background())
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -142,7 +159,7 @@ func TestVaccinationService_GetNationalVaccinationsPaginated(t *testing.T) {
 	}
 	mockRepo.On("GetNationalVaccinationsPaginated", 10, 0).Return(expected, 1, nil)
 
-	result, total, err := svc.GetNationalVaccinationsPaginated(10, 0)
+	result, total, err := svc.GetNationalVaccinationsPaginated(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -155,7 +172,7 @@ func TestVaccinationService_GetNationalVaccinationsPaginated_Error(t *testing.T)
 
 	mockRepo.On("GetNationalVaccinationsPaginated", 10, 0).Return([]models.NationalVaccine{}, 0, errors.New("db error"))
 
-	result, total, err := svc.GetNationalVaccinationsPaginated(10, 0)
+	result, total, err := svc.GetNationalVaccinationsPaginated(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -171,7 +188,7 @@ func TestVaccinationService_GetProvinceVaccinationsPaginated(t *testing.T) {
 	}
 	mockRepo.On("GetProvinceVaccinationsPaginated", 72, 10, 0).Return(expected, 1, nil)
 
-	result, total, err := svc.GetProvinceVaccinationsPaginated(10, 0)
+	result, total, err := svc.GetProvinceVaccinationsPaginated(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -184,7 +201,7 @@ func TestVaccinationService_GetProvinceVaccinationsPaginated_Error(t *testing.T)
 
 	mockRepo.On("GetProvinceVaccinationsPaginated", 72, 10, 0).Return([]models.ProvinceVaccine{}, 0, errors.New("db error"))
 
-	result, total, err := svc.GetProvinceVaccinationsPaginated(10, 0)
+	result, total, err := svc.GetProvinceVaccinationsPaginated(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
@@ -200,7 +217,7 @@ func TestVaccinationService_GetVaccineLocationsPaginated(t *testing.T) {
 	}
 	mockRepo.On("GetVaccineLocationsPaginated", 72, 10, 0).Return(expected, 1, nil)
 
-	result, total, err := svc.GetVaccineLocationsPaginated(10, 0)
+	result, total, err := svc.GetVaccineLocationsPaginated(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
@@ -213,10 +230,28 @@ func TestVaccinationService_GetVaccineLocationsPaginated_Error(t *testing.T) {
 
 	mockRepo.On("GetVaccineLocationsPaginated", 72, 10, 0).Return([]models.VaccineLocation{}, 0, errors.New("db error"))
 
-	result, total, err := svc.GetVaccineLocationsPaginated(10, 0)
+	result, total, err := svc.GetVaccineLocationsPaginated(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Empty(t, result)
 	assert.Equal(t, 0, total)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestVaccinationService_QueryVaccinations(t *testing.T) {
+	mockRepo, svc := setupVaccinationService()
+	q := repository.VaccinationQuery{Limit: 50}
+
+	mockRepo.On("QueryNationalVaccinations", q).Return([]models.NationalVaccine{{ID: 1}}, 1, nil)
+	mockRepo.On("QueryProvinceVaccinations", 31, q).Return([]models.ProvinceVaccine{{ProvinceID: 31}}, 1, nil)
+
+	national, total, err := svc.QueryNationalVaccinations(context.Background(), q)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, national, 1)
+
+	province, _, err := svc.QueryProvinceVaccinations(context.Background(), 31, q)
+	assert.NoError(t, err)
+	assert.Equal(t, 31, province[0].ProvinceID)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/webhook"
+)
+
+// ErrInvalidWebhookSubscription is returned for registrations with an invalid URL, events or filter
+var ErrInvalidWebhookSubscription = errors.New("invalid webhook subscription")
+
+// WebhookPublisher notifies webhook subscribers of an event
+type WebhookPublisher interface {
+	Publish(ctx context.Context, event string, data interface{}) error
+}
+
+// WebhookSender delivers a signed message to a subscriber URL
+type WebhookSender interface {
+	Send(ctx context.Context, url, secret string, msg webhook.Message) (webhook.Result, error)
+}
+
+// webhookSendTimeout bounds a delivery attempt to one subscriber, including the
+// sender's own quick retries
+const webhookSendTimeout = 30 * time.Second
+
+// webhookRetrySchedule is the wait before each automatic retry of a failed
+// delivery. A delivery failing after the last entry is marked failed.
+var webhookRetrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// webhookRetryBatch limits the deliveries retried per tick
+const webhookRetryBatch = 50
+
+// WebhookService signs and sends events to every active subscription that wants
+// them, logs each delivery in webhook_deliveries, retries failed deliveries on a
+// schedule and exposes the delivery log to admins
+type WebhookService struct {
+	repo   repository.WebhookRepository
+	sender WebhookSender
+	clock  clock.Clock
+
+	stopChan chan struct{}
+}
+
+// NewWebhookService creates a WebhookService
+func NewWebhookService(repo repository.WebhookRepository, sender WebhookSender) *WebhookService {
+	return &WebhookService{repo: repo, sender: sender, clock: clock.System, stopChan: make(chan struct{})}
+}
+
+// SetClock sets the clock of the delivery timestamps and the retry schedule
+func (s *WebhookService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Publish delivers event to the subscribers synchronously. A failed delivery is
+// recorded, scheduled for retry and logged, but does not stop delivery to the
+// other subscribers.
+func (s *WebhookService) Publish(ctx context.Context, event string, data interface{}) error {
+	subs, err := s.repo.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	for _, sub := range subs {
+		if !sub.Wants(event) {
+			continue
+		}
+		subData, ok := filterEventData(sub.Filter, data)
+		if !ok {
+			continue
+		}
+		if err := s.publishTo(ctx, sub, event, subData); err != nil {
+			log.Printf("Webhook delivery of %s to subscription %d failed: %v", event, sub.ID, err)
+		}
+	}
+	return nil
+}
+
+// filterEventData narrows the event's case changes to those matching the filter.
+// It reports false when nothing matches; events without case changes only go to
+// subscriptions without a filter.
+func filterEventData(filter models.WebhookFilter, data interface{}) (interface{}, bool) {
+	if filter.IsEmpty() {
+		return data, true
+	}
+	event, ok := data.(models.FilterableEvent)
+	if !ok {
+		return nil, false
+	}
+	matched := filter.Apply(event.CaseChanges())
+	if len(matched) == 0 {
+		return nil, false
+	}
+	return event.WithCaseChanges(matched), true
+}
+
+func (s *WebhookService) publishTo(ctx context.Context, sub models.WebhookSubscription, event string, data interface{}) error {
+	deliveryID := webhook.NewDeliveryID()
+	body, err := json.Marshal(models.WebhookEnvelope{
+		ID:         deliveryID,
+		Event:      event,
+		OccurredAt: s.clock.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delivery := models.WebhookDelivery{
+		DeliveryID:     deliveryID,
+		SubscriptionID: sub.ID,
+		Event:          event,
+		Payload:        body,
+		Status:         models.WebhookDeliveryPending,
+		CreatedAt:      s.clock.Now().UTC(),
+	}
+	if delivery.ID, err = s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	if err := s.attempt(ctx, sub, &delivery); err != nil {
+		return err
+	}
+	if delivery.LastError != nil {
+		return fmt.Errorf("%s", *delivery.LastError)
+	}
+	return nil
+}
+
+// attempt sends the delivery once and records the outcome in d. Failures are
+// scheduled for retry until webhookRetrySchedule is exhausted. The returned
+// error is only set when the outcome could not be stored.
+func (s *WebhookService) attempt(ctx context.Context, sub models.WebhookSubscription, d *models.WebhookDelivery) error {
+	sendCtx, cancel := context.WithTimeout(ctx, webhookSendTimeout)
+	defer cancel()
+	result, sendErr := s.sender.Send(sendCtx, sub.URL, sub.Secret, webhook.Message{DeliveryID: d.DeliveryID, Event: d.Event, Body: d.Payload})
+
+	d.Attempts++
+	d.ResponseStatus = nil
+	if result.StatusCode != 0 {
+		status := result.StatusCode
+		d.ResponseStatus = &status
+	}
+	d.NextAttemptAt = nil
+	d.LastError = nil
+
+	if sendErr == nil {
+		d.Status = models.WebhookDeliveryDelivered
+	} else {
+		msg := sendErr.Error()
+		d.LastError = &msg
+		d.Status = models.WebhookDeliveryFailed
+		if retry := d.Attempts - 1; retry < len(webhookRetrySchedule) {
+			next := s.clock.Now().UTC().Add(webhookRetrySchedule[retry])
+			d.Status = models.WebhookDeliveryRetrying
+			d.NextAttemptAt = &next
+		}
+	}
+
+	return s.repo.RecordAttempt(ctx, *d)
+}
+
+// RetryDue re-sends every delivery whose scheduled retry is due and returns how
+// many were attempted. Deliveries of deleted or deactivated subscriptions are
+// marked failed instead.
+func (s *WebhookService) RetryDue(ctx context.Context) (int, error) {
+	due, err := s.repo.ListDueDeliveries(ctx, s.clock.Now().UTC(), webhookRetryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due webhook deliveries: %w", err)
+	}
+
+	for i := range due {
+		d := &due[i]
+		sub, err := s.repo.GetSubscription(ctx, d.SubscriptionID)
+		if err != nil {
+			return i, err
+		}
+		if sub == nil || !sub.Active {
+			msg := "subscription no longer active"
+			d.Status = models.WebhookDeliveryFailed
+			d.NextAttemptAt = nil
+			d.LastError = &msg
+			if err := s.repo.RecordAttempt(ctx, *d); err != nil {
+				return i, err
+			}
+			continue
+		}
+		if err := s.attempt(ctx, *sub, d); err != nil {
+			return i, err
+		}
+		if d.LastError != nil {
+			log.Printf("Webhook retry of delivery %s (attempt %d) failed: %s", d.DeliveryID, d.Attempts, *d.LastError)
+		}
+	}
+	return len(due), nil
+}
+
+// Start retries due deliveries at the given interval in a background goroutine.
+func (s *WebhookService) Start(interval time.Duration) {
+	go func() {
+		ctx := context.Background()
+		ticker := s.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if _, err := s.RetryDue(ctx); err != nil {
+					log.Printf("Webhook retry failed: %v", err)
+				}
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background retries started by Start.
+func (s *WebhookService) Stop() {
+	close(s.stopChan)
+}
+
+// CreateSubscription validates and stores an active subscription with a new
+// random signing secret. The returned subscription is the only place the
+// secret is exposed.
+func (s *WebhookService) CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookSubscription, err)
+	}
+	sub.Secret = newSubscriptionToken()
+	sub.Active = true
+	sub.CreatedAt = s.clock.Now().UTC()
+	id, err := s.repo.CreateSubscription(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	sub.ID = id
+	return &sub, nil
+}
+
+// ListSubscriptions returns every subscription with its delivery counts
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscriptionStats, error) {
+	stats, err := s.repo.ListSubscriptionStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return stats, nil
+}
+
+// ListDeliveries returns the latest deliveries of a subscription, or nil when
+// the subscription does not exist
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	sub, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if sub == nil {
+		return nil, nil
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns a delivery with its payload, or nil when it does not exist
+func (s *WebhookService) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	d, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+// Redeliver immediately re-sends a delivery with its original delivery ID and
+// payload, whatever its status, and returns it with the new outcome. It returns
+// nil when the delivery does not exist. A failed attempt is reported in the
+// delivery rather than as an error.
+func (s *WebhookService) Redeliver(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	d, err := s.GetDelivery(ctx, id)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	sub, err := s.repo.GetSubscription(ctx, d.SubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("subscription %d of delivery %d no longer exists", d.SubscriptionID, id)
+	}
+
+	if err := s.attempt(ctx, *sub, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/pkg/clock"
+	"github.com/banua-coder/pico-api-go/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) ListActiveSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListSubscriptionStats(ctx context.Context) ([]models.WebhookSubscriptionStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscriptionStats), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (int64, error) {
+	args := m.Called(sub)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, d models.WebhookDelivery) (int64, error) {
+	args := m.Called(d)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(subscriptionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, d models.WebhookDelivery) error {
+	return m.Called(d).Error(0)
+}
+
+func (m *MockWebhookRepository) GetCursor(ctx context.Context, name string) (int64, bool, error) {
+	args := m.Called(name)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
+}
+
+func (m *MockWebhookRepository) SetCursor(ctx context.Context, name string, lastID int64) error {
+	return m.Called(name, lastID).Error(0)
+}
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, url, secret string, msg webhook.Message) (webhook.Result, error) {
+	args := m.Called(url, secret, msg)
+	return args.Get(0).(webhook.Result), args.Error(1)
+}
+
+func newTestWebhookService(repo *MockWebhookRepository, sender *MockWebhookSender, now time.Time) *WebhookService {
+	s := NewWebhookService(repo, sender)
+	s.SetClock(clock.NewMock(now))
+	return s
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	repo.On("ListActiveSubscriptions").Return([]models.WebhookSubscription{
+		{ID: 1, URL: "https://a.example/hook", Secret: "s1", Events: []string{"*"}},
+		{ID: 2, URL: "https://b.example/hook", Secret: "s2", Events: []string{"other.event"}},
+	}, nil)
+	repo.On("CreateDelivery", mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.SubscriptionID == 1 && d.Status == models.WebhookDeliveryPending && len(d.DeliveryID) == 32
+	})).Return(int64(10), nil)
+	sender.On("Send", "https://a.example/hook", "s1", mock.MatchedBy(func(msg webhook.Message) bool {
+		var env models.WebhookEnvelope
+		return json.Unmarshal(msg.Body, &env) == nil && env.ID == msg.DeliveryID && env.Event == models.WebhookEventSyncCompleted
+	})).Return(webhook.Result{StatusCode: 204}, nil)
+	repo.On("RecordAttempt", mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.ID == 10 && d.Status == models.WebhookDeliveryDelivered && d.Attempts == 1 &&
+			*d.ResponseStatus == 204 && d.NextAttemptAt == nil && d.LastError == nil
+	})).Return(nil)
+
+	err := newTestWebhookService(repo, sender, time.Now()).Publish(context.Background(), models.WebhookEventSyncCompleted, models.SyncCompletedData{RunID: 3})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	sender.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestWebhookService_Publish_SchedulesRetry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	repo.On("ListActiveSubscriptions").Return([]models.WebhookSubscription{
+		{ID: 1, URL: "https://a.example/hook", Secret: "s1", Events: []string{"*"}},
+	}, nil)
+	repo.On("CreateDelivery", mock.Anything).Return(int64(11), nil)
+	sender.On("Send", mock.Anything, mock.Anything, mock.Anything).
+		Return(webhook.Result{StatusCode: 500}, errors.New("subscriber responded with status 500"))
+	repo.On("RecordAttempt", mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.Status == models.WebhookDeliveryRetrying && *d.ResponseStatus == 500 &&
+			d.NextAttemptAt.Equal(now.Add(time.Minute)) && *d.LastError == "subscriber responded with status 500"
+	})).Return(nil)
+
+	err := newTestWebhookService(repo, sender, now).Publish(context.Background(), models.WebhookEventSyncCompleted, nil)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_Publish_ListError(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	repo.On("ListActiveSubscriptions").Return(nil, errors.New("db down"))
+
+	err := NewWebhookService(repo, new(MockWebhookSender)).Publish(context.Background(), models.WebhookEventSyncCompleted, nil)
+
+	assert.Error(t, err)
+}
+
+func TestWebhookService_RetryDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	repo.On("ListDueDeliveries", now, webhookRetryBatch).Return([]models.WebhookDelivery{
+		{ID: 1, DeliveryID: "d1", SubscriptionID: 1, Status: models.WebhookDeliveryRetrying, Attempts: len(webhookRetrySchedule)},
+		{ID: 2, DeliveryID: "d2", SubscriptionID: 2, Status: models.WebhookDeliveryRetrying, Attempts: 1},
+	}, nil)
+	repo.On("GetSubscription", int64(1)).Return(&models.WebhookSubscription{ID: 1, URL: "https://a.example/hook", Active: true}, nil)
+	repo.On("GetSubscription", int64(2)).Return(&models.WebhookSubscription{ID: 2, Active: false}, nil)
+	sender.On("Send", "https://a.example/hook", mock.Anything, mock.Anything).Return(webhook.Result{}, errors.New("connection refused"))
+	// The last scheduled retry failed: give up
+	repo.On("RecordAttempt", mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.ID == 1 && d.Status == models.WebhookDeliveryFailed && d.NextAttemptAt == nil && d.ResponseStatus == nil
+	})).Return(nil)
+	repo.On("RecordAttempt", mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.ID == 2 && d.Status == models.WebhookDeliveryFailed && d.Attempts == 1
+	})).Return(nil)
+
+	n, err := newTestWebhookService(repo, sender, now).RetryDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	repo.AssertExpectations(t)
+	sender.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestWebhookService_ListDeliveries_UnknownSubscription(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	repo.On("GetSubscription", int64(9)).Return(nil, nil)
+
+	deliveries, err := NewWebhookService(repo, new(MockWebhookSender)).ListDeliveries(context.Background(), 9, 50)
+
+	assert.NoError(t, err)
+	assert.Nil(t, deliveries)
+	repo.AssertNotCalled(t, "ListDeliveries", mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	repo.On("GetDelivery", int64(5)).Return(&models.WebhookDelivery{
+		ID: 5, DeliveryID: "d5", SubscriptionID: 1, Event: "sync.completed", Payload: []byte(`{}`),
+		Status: models.WebhookDeliveryFailed, Attempts: 6,
+	}, nil)
+	repo.On("GetSubscription", int64(1)).Return(&models.WebhookSubscription{ID: 1, URL: "https://a.example/hook", Secret: "s1"}, nil)
+	sender.On("Send", "https://a.example/hook", "s1", webhook.Message{DeliveryID: "d5", Event: "sync.completed", Body: []byte(`{}`)}).
+		Return(webhook.Result{StatusCode: 200}, nil)
+	repo.On("RecordAttempt", mock.Anything).Return(nil)
+
+	d, err := NewWebhookService(repo, sender).Redeliver(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryDelivered, d.Status)
+	assert.Equal(t, 7, d.Attempts)
+}
+
+func TestWebhookService_Redeliver_NotFound(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	repo.On("GetDelivery", int64(5)).Return(nil, nil)
+
+	d, err := NewWebhookService(repo, new(MockWebhookSender)).Redeliver(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+}
+
+func TestWebhookService_Publish_AppliesFilters(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	repo.On("ListActiveSubscriptions").Return([]models.WebhookSubscription{
+		{ID: 1, URL: "https://all.example/hook", Events: []string{"*"}},
+		{ID: 2, URL: "https://sulteng.example/hook", Events: []string{"*"}, Filter: models.WebhookFilter{ProvinceIDs: []string{"72"}}},
+		{ID: 3, URL: "https://corrections.example/hook", Events: []string{"*"}, Filter: models.WebhookFilter{CorrectionsOnly: true}},
+	}, nil)
+	repo.On("CreateDelivery", mock.Anything).Return(int64(1), nil)
+	repo.On("RecordAttempt", mock.Anything).Return(nil)
+
+	bodies := map[string]models.SyncCompletedData{}
+	sender.On("Send", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		var env struct {
+			Data models.SyncCompletedData `json:"data"`
+		}
+		_ = json.Unmarshal(args.Get(2).(webhook.Message).Body, &env)
+		bodies[args.String(0)] = env.Data
+	}).Return(webhook.Result{StatusCode: 200}, nil)
+
+	data := models.SyncCompletedData{RunID: 1, Inserted: 2, Changes: []models.CaseChange{
+		{Table: "national_cases", Key: "day=10", Action: models.ChangeActionInsert, Day: 10},
+		{Table: "province_cases", Key: "province_id=72,day=10", Action: models.ChangeActionInsert, Day: 10, ProvinceID: "72"},
+	}}
+	err := NewWebhookService(repo, sender).Publish(context.Background(), models.WebhookEventSyncCompleted, data)
+
+	assert.NoError(t, err)
+	assert.Len(t, bodies, 2, "the corrections-only subscription has nothing to receive")
+	assert.Len(t, bodies["https://all.example/hook"].Changes, 2)
+	if assert.Len(t, bodies["https://sulteng.example/hook"].Changes, 1) {
+		assert.Equal(t, "72", bodies["https://sulteng.example/hook"].Changes[0].ProvinceID)
+	}
+}
+
+func TestWebhookService_Publish_FilteredSubscriptionSkipsUnfilterableEvents(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	repo.On("ListActiveSubscriptions").Return([]models.WebhookSubscription{
+		{ID: 2, URL: "https://sulteng.example/hook", Events: []string{"*"}, Filter: models.WebhookFilter{ProvinceIDs: []string{"72"}}},
+	}, nil)
+
+	err := NewWebhookService(repo, sender).Publish(context.Background(), "other.event", map[string]string{"a": "b"})
+
+	assert.NoError(t, err)
+	sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockWebhookRepository)
+	repo.On("CreateSubscription", mock.MatchedBy(func(sub models.WebhookSubscription) bool {
+		return sub.URL == "https://a.example/hook" && len(sub.Secret) == 64 && sub.Active && sub.CreatedAt.Equal(now)
+	})).Return(int64(7), nil)
+
+	sub, err := newTestWebhookService(repo, new(MockWebhookSender), now).CreateSubscription(context.Background(), models.WebhookSubscription{
+		URL: "https://a.example/hook", Events: []string{models.WebhookEventProvinceCaseCreated},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), sub.ID)
+	assert.Len(t, sub.Secret, 64)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateSubscription_Invalid(t *testing.T) {
+	repo := new(MockWebhookRepository)
+
+	_, err := newTestWebhookService(repo, new(MockWebhookSender), time.Now()).CreateSubscription(context.Background(), models.WebhookSubscription{
+		URL: "not a url", Events: []string{"*"},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidWebhookSubscription)
+	repo.AssertNotCalled(t, "CreateSubscription", mock.Anything)
+}
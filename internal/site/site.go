@@ -0,0 +1,34 @@
+// Package site embeds the static landing page served at the root URL of
+// production builds, so the binary stays self-contained.
+package site
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// siteCacheControl lets browsers keep the landing page and its assets for an
+// hour; they only change with a new release
+const siteCacheControl = "public, max-age=3600"
+
+// FS returns the embedded site rooted at its index.html
+func FS() fs.FS {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Handler serves the embedded site
+func Handler() http.Handler {
+	files := http.FileServer(http.FS(FS()))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", siteCacheControl)
+		files.ServeHTTP(w, r)
+	})
+}
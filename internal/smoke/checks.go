@@ -0,0 +1,882 @@
+package smoke
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nationalCaseKeys and provinceCaseKeys are the fields every case row has
+var (
+	nationalCaseKeys = []string{"day", "date", "daily.positive", "daily.recovered", "daily.deceased", "daily.active",
+		"cumulative.positive", "cumulative.recovered", "cumulative.deceased", "cumulative.active", "statistics"}
+	provinceCaseKeys = append([]string{"daily.odp", "daily.pdp"}, nationalCaseKeys...)
+)
+
+// paginationKeys are the fields of the pagination of a list
+var paginationKeys = []string{"limit", "offset", "total", "total_pages", "page", "has_next", "has_prev"}
+
+// optionalEndpoints are the JSON endpoints a deployment enables by its
+// configuration; each is checked for the response envelope and skipped when
+// it answers 404. {day}, {year} and {provinceId} take the values found by
+// earlier checks.
+var optionalEndpoints = []string{
+	"/api/v1/national/vaccinations",
+	"/api/v1/national/tests",
+	"/api/v1/national/{day}/revisions",
+	"/api/v1/provinces/{provinceId}/per-capita",
+	"/api/v1/provinces/{provinceId}/districts",
+	"/api/v1/provinces/{provinceId}/vaccinations",
+	"/api/v1/data-quality/issues",
+	"/api/v1/summary",
+	"/api/v1/recap/{year}",
+	"/api/v1/status",
+	"/api/v1/system-status",
+	"/api/v1/regencies",
+	"/api/v1/hospitals",
+	"/api/v1/task-forces",
+	"/api/v1/vaccination/national",
+	"/api/v1/vaccination/province",
+	"/api/v1/vaccination/locations",
+	"/api/v1/stats/gender",
+	"/api/v1/stats/gender/latest",
+	"/api/v1/stats/tests",
+	"/api/v1/stats/test-types",
+}
+
+// checks returns the checks of a run in order
+func checks() []check {
+	list := []check{
+		{"liveness", checkLiveness},
+		{"readiness", checkReadiness},
+		{"health", checkHealth},
+		{"api index", checkIndex},
+		{"api descriptor", checkDescriptor},
+		{"changelog", envelopeCheck("/api/v1/changes")},
+		{"tenant", envelopeCheck("/api/v1/tenant")},
+		{"latest national case", checkLatestNational},
+		{"cors headers", checkCORS},
+		{"rate limit headers", checkRateLimit},
+		{"cache headers", checkCacheHeaders},
+		{"response cache", checkResponseCache},
+		{"national case by day", checkNationalByDay},
+		{"national pagination", checkNationalPagination},
+		{"national csv", checkNationalCSV},
+		{"field selection", checkFieldSelection},
+		{"invalid parameter", checkInvalidParameter},
+		{"period comparison", checkComparePeriods},
+		{"provinces", checkProvinces},
+		{"province", checkProvince},
+		{"province cases", checkProvinceCases},
+		{"all province cases", checkAllProvinceCases},
+		{"latest province cases", checkLatestProvinceCases},
+		{"metrics", checkMetrics},
+		{"rankings", checkRankings},
+		{"province metrics", checkProvinceMetrics},
+		{"province calendar", checkProvinceCalendar},
+		{"xlsx export", checkExportXLSX},
+		{"embed card", conditionalCheck("/api/v1/embed/summary.html", "text/html", nil)},
+		{"og image", conditionalCheck("/api/v1/og/daily.png", "image/png", []byte("\x89PNG\r\n\x1a\n"))},
+		{"daily report", checkDailyReport},
+		{"robots.txt", checkRobots},
+		{"unknown route", checkUnknownRoute},
+	}
+	for _, path := range optionalEndpoints {
+		list = append(list, check{strings.TrimPrefix(path, "/api/v1"), optionalCheck(path)})
+	}
+	return list
+}
+
+func checkLiveness(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/healthz")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	var probe struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil || probe.Status != "alive" {
+		return resp.fail("status %q, want alive", probe.Status)
+	}
+	return nil
+}
+
+func checkReadiness(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/readyz")
+	if err != nil {
+		return err
+	}
+	if err := resp.optional(); err != nil {
+		return err
+	}
+	if resp.status == http.StatusServiceUnavailable {
+		var body struct {
+			Data struct {
+				Checks []struct {
+					Name   string `json:"name"`
+					Status string `json:"status"`
+				} `json:"checks"`
+			} `json:"data"`
+		}
+		var failing []string
+		if json.Unmarshal(resp.body, &body) == nil {
+			for _, c := range body.Data.Checks {
+				if c.Status == "failing" {
+					failing = append(failing, c.Name)
+				}
+			}
+		}
+		return resp.fail("not ready, failing: %s", strings.Join(failing, ", "))
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "ready", "checks")
+}
+
+func checkHealth(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/health")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Data struct {
+			Status   string `json:"status"`
+			Database struct {
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			} `json:"database"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(resp.body, &body); err != nil {
+		return resp.fail("status %d, invalid JSON: %v", resp.status, err)
+	}
+	if body.Data.Status != "healthy" {
+		return resp.fail("%s, database %s %s", body.Data.Status, body.Data.Database.Status, body.Data.Database.Error)
+	}
+	_, err = resp.envelope()
+	return err
+}
+
+func checkIndex(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "api", "endpoints")
+}
+
+func checkDescriptor(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/.well-known/api-descriptor")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "name", "version", "formats", "limits.default_page_size", "rate_limit", "metrics")
+}
+
+func checkLatestNational(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/national/latest")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	if err := resp.requireKeys(data, nationalCaseKeys...); err != nil {
+		return err
+	}
+	var latest struct {
+		Day  int64     `json:"day"`
+		Date time.Time `json:"date"`
+	}
+	if err := json.Unmarshal(data, &latest); err != nil {
+		return resp.fail("invalid case: %v", err)
+	}
+	if latest.Day <= 0 || latest.Date.IsZero() {
+		return resp.fail("no day or date")
+	}
+	s.latestDay, s.latestDate = latest.Day, latest.Date
+	return nil
+}
+
+func checkCORS(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/national/latest", "Origin", "https://example.com")
+	if err != nil {
+		return err
+	}
+	if got := resp.header.Get("Access-Control-Allow-Origin"); got != "*" {
+		return resp.fail("Access-Control-Allow-Origin %q, want *", got)
+	}
+
+	preflight, err := s.do(ctx, http.MethodOptions, "/api/v1/national", "Origin", "https://example.com", "Access-Control-Request-Method", "GET")
+	if err != nil {
+		return err
+	}
+	if err := preflight.expect(http.StatusOK); err != nil {
+		return err
+	}
+	methods, err := preflight.requireHeader("Access-Control-Allow-Methods")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(methods, "GET") {
+		return preflight.fail("Access-Control-Allow-Methods %q does not allow GET", methods)
+	}
+	return nil
+}
+
+func checkRateLimit(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/national/latest")
+	if err != nil {
+		return err
+	}
+	if resp.header.Get("X-RateLimit-Limit") == "" {
+		return skip("no X-RateLimit-Limit header, rate limiting is disabled")
+	}
+	values := map[string]int{}
+	for _, name := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"} {
+		value, err := resp.requireHeader(name)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return resp.fail("%s %q is not a count", name, value)
+		}
+		values[name] = n
+	}
+	if values["X-RateLimit-Limit"] == 0 || values["X-RateLimit-Remaining"] > values["X-RateLimit-Limit"] {
+		return resp.fail("X-RateLimit-Remaining %d out of X-RateLimit-Limit %d", values["X-RateLimit-Remaining"], values["X-RateLimit-Limit"])
+	}
+	return nil
+}
+
+// checkCacheHeaders checks that the latest data may be cached for minutes
+// and the history for longer
+func checkCacheHeaders(ctx context.Context, s *session) error {
+	latest, err := maxAge(ctx, s, "/api/v1/national/latest")
+	if err != nil {
+		return err
+	}
+	history, err := maxAge(ctx, s, "/api/v1/national?limit=1")
+	if err != nil {
+		return err
+	}
+	if history < latest {
+		return fmt.Errorf("history max-age %ds is shorter than latest max-age %ds", history, latest)
+	}
+	return nil
+}
+
+// maxAge requests path and returns the max-age of its Cache-Control, and its
+// Expires must be a date
+func maxAge(ctx context.Context, s *session, path string) (int, error) {
+	resp, err := s.get(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	if err := resp.expect(http.StatusOK); err != nil {
+		return 0, err
+	}
+	cacheControl, err := resp.requireHeader("Cache-Control")
+	if err != nil {
+		return 0, err
+	}
+	for _, directive := range strings.Split(cacheControl, ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !ok {
+			continue
+		}
+		age, err := strconv.Atoi(value)
+		if err != nil || age <= 0 {
+			return 0, resp.fail("Cache-Control %q has an invalid max-age", cacheControl)
+		}
+		if _, err := http.ParseTime(resp.header.Get("Expires")); err != nil {
+			return 0, resp.fail("Expires %q is not a date", resp.header.Get("Expires"))
+		}
+		return age, nil
+	}
+	return 0, resp.fail("Cache-Control %q has no max-age", cacheControl)
+}
+
+// checkResponseCache checks that a repeated request is served from the
+// response cache with the same body
+func checkResponseCache(ctx context.Context, s *session) error {
+	const path = "/api/v1/national/latest"
+	first, err := s.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if first.header.Get("X-Response-Cache") == "" {
+		return skip("no X-Response-Cache header, serving from the response cache is disabled")
+	}
+	second, err := s.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if got := second.header.Get("X-Response-Cache"); got != "HIT" {
+		return second.fail("X-Response-Cache %q on a repeated request, want HIT", got)
+	}
+	if !bytes.Equal(first.body, second.body) {
+		return second.fail("cached body differs from the first response")
+	}
+	return nil
+}
+
+func checkNationalByDay(ctx context.Context, s *session) error {
+	if s.latestDay == 0 {
+		return skip("latest national case unknown")
+	}
+	resp, err := s.get(ctx, fmt.Sprintf("/api/v1/national/%d", s.latestDay))
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	var c struct {
+		Day int64 `json:"day"`
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.Day != s.latestDay {
+		return resp.fail("day %d, want %d", c.Day, s.latestDay)
+	}
+	return resp.requireKeys(data, nationalCaseKeys...)
+}
+
+// page is a page of a paginated list
+type page struct {
+	Data       []json.RawMessage `json:"data"`
+	Pagination struct {
+		Limit   int  `json:"limit"`
+		Offset  int  `json:"offset"`
+		Total   int  `json:"total"`
+		HasNext bool `json:"has_next"`
+		HasPrev bool `json:"has_prev"`
+	} `json:"pagination"`
+}
+
+// getPage requests a page of a case list and checks its rows and pagination
+func getPage(ctx context.Context, s *session, path string, limit, offset int, keys []string) (*page, error) {
+	resp, err := s.get(ctx, fmt.Sprintf("%s?limit=%d&offset=%d", path, limit, offset))
+	if err != nil {
+		return nil, err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return nil, err
+	}
+	if err := requireKeys(data, "data", "pagination"); err != nil {
+		return nil, resp.fail("%v", err)
+	}
+	var wrapper struct {
+		Data       json.RawMessage `json:"data"`
+		Pagination json.RawMessage `json:"pagination"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, resp.fail("invalid page: %v", err)
+	}
+	if err := requireKeys(wrapper.Pagination, paginationKeys...); err != nil {
+		return nil, resp.fail("pagination: %v", err)
+	}
+	if _, err := requireRows(wrapper.Data, keys...); err != nil {
+		return nil, resp.fail("%v", err)
+	}
+	var p page
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, resp.fail("invalid page: %v", err)
+	}
+
+	pg := p.Pagination
+	switch {
+	case pg.Limit != limit || pg.Offset != offset:
+		return nil, resp.fail("pagination limit %d offset %d, want %d and %d", pg.Limit, pg.Offset, limit, offset)
+	case len(p.Data) > limit:
+		return nil, resp.fail("%d rows, more than the limit %d", len(p.Data), limit)
+	case offset < pg.Total && len(p.Data) != min(limit, pg.Total-offset):
+		return nil, resp.fail("%d rows of %d from offset %d", len(p.Data), pg.Total, offset)
+	case pg.HasNext != (offset+len(p.Data) < pg.Total) || pg.HasPrev != (offset > 0):
+		return nil, resp.fail("has_next %t has_prev %t inconsistent with %d rows of %d", pg.HasNext, pg.HasPrev, len(p.Data), pg.Total)
+	}
+	return &p, nil
+}
+
+// checkPages checks two consecutive pages of a case list, returning its total
+func checkPages(ctx context.Context, s *session, path string, keys []string) (int, error) {
+	first, err := getPage(ctx, s, path, 2, 0, keys)
+	if err != nil {
+		return 0, err
+	}
+	total := first.Pagination.Total
+	if total < 3 {
+		return total, nil
+	}
+	second, err := getPage(ctx, s, path, 2, 2, keys)
+	if err != nil {
+		return 0, err
+	}
+	if second.Pagination.Total != total {
+		return 0, fmt.Errorf("%s: total changed from %d to %d between pages", path, total, second.Pagination.Total)
+	}
+	seen := map[string]bool{}
+	for _, row := range first.Data {
+		seen[rowKey(row)] = true
+	}
+	for _, row := range second.Data {
+		if seen[rowKey(row)] {
+			return 0, fmt.Errorf("%s: row %s is on two pages", path, rowKey(row))
+		}
+	}
+	return total, nil
+}
+
+// rowKey identifies a case row by its day and province
+func rowKey(row json.RawMessage) string {
+	var c struct {
+		Day      int64 `json:"day"`
+		Province *struct {
+			ID string `json:"id"`
+		} `json:"province"`
+	}
+	_ = json.Unmarshal(row, &c)
+	if c.Province != nil {
+		return fmt.Sprintf("%s/%d", c.Province.ID, c.Day)
+	}
+	return strconv.FormatInt(c.Day, 10)
+}
+
+func checkNationalPagination(ctx context.Context, s *session) error {
+	total, err := checkPages(ctx, s, "/api/v1/national", nationalCaseKeys)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return errors.New("no national cases")
+	}
+	s.nationalTotal = total
+	return nil
+}
+
+func checkNationalCSV(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/national?limit=2", "Accept", "text/csv")
+	if err != nil {
+		return err
+	}
+	if err := resp.expect(http.StatusOK); err != nil {
+		return err
+	}
+	if err := resp.contentType("text/csv"); err != nil {
+		return err
+	}
+	if got := resp.header.Get("X-Pagination-Limit"); got != "2" {
+		return resp.fail("X-Pagination-Limit %q, want 2", got)
+	}
+	if s.nationalTotal > 0 {
+		if got := resp.header.Get("X-Total-Count"); got != strconv.Itoa(s.nationalTotal) {
+			return resp.fail("X-Total-Count %q, want the JSON total %d", got, s.nationalTotal)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(string(resp.body)), "\n")
+	if !strings.HasPrefix(lines[0], "day,date,") {
+		return resp.fail("header row %q, want day,date,...", lines[0])
+	}
+	if rows := len(lines) - 1; rows != min(2, s.nationalTotal) && s.nationalTotal > 0 {
+		return resp.fail("%d rows, want %d", rows, min(2, s.nationalTotal))
+	}
+	return nil
+}
+
+func checkFieldSelection(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/national/latest?fields=day,daily.positive")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	keys, err := keysOf(data)
+	if err != nil {
+		return resp.fail("%v", err)
+	}
+	if strings.Join(keys, ",") != "day,daily" {
+		return resp.fail("fields %v, want [day daily]", keys)
+	}
+	var row struct {
+		Daily json.RawMessage `json:"daily"`
+	}
+	_ = json.Unmarshal(data, &row)
+	if keys, err = keysOf(row.Daily); err != nil || strings.Join(keys, ",") != "positive" {
+		return resp.fail("daily fields %v, want [positive]", keys)
+	}
+	return nil
+}
+
+// checkInvalidParameter checks that a bad query parameter is rejected with
+// the code and field of the validation error
+func checkInvalidParameter(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/national?start_date=not-a-date")
+	if err != nil {
+		return err
+	}
+	if err := resp.expect(http.StatusBadRequest); err != nil {
+		return err
+	}
+	var body struct {
+		Status string `json:"status"`
+		Code   string `json:"code"`
+		Field  string `json:"field"`
+	}
+	if err := json.Unmarshal(resp.body, &body); err != nil {
+		return resp.fail("invalid JSON: %v", err)
+	}
+	if body.Status != "error" || body.Code != "INVALID_DATE_FORMAT" || body.Field != "start_date" {
+		return resp.fail("status %q code %q field %q, want error INVALID_DATE_FORMAT start_date", body.Status, body.Code, body.Field)
+	}
+	return nil
+}
+
+func checkComparePeriods(ctx context.Context, s *session) error {
+	if s.latestDate.IsZero() {
+		return skip("latest national case unknown")
+	}
+	b := s.latestDate.Format("2006-01")
+	a := s.latestDate.AddDate(0, 0, 1-s.latestDate.Day()).AddDate(0, -1, 0).Format("2006-01")
+	resp, err := s.get(ctx, "/api/v1/national/compare-periods?period_a="+a+"&period_b="+b)
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "period_a", "period_b", "percent_change")
+}
+
+func checkProvinces(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/provinces")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	rows, err := requireRows(data, "id", "name")
+	if err != nil {
+		return resp.fail("%v", err)
+	}
+	if len(rows) == 0 {
+		return resp.fail("no provinces")
+	}
+	var province struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rows[0], &province); err != nil || province.ID == "" {
+		return resp.fail("province without an id")
+	}
+	s.provinceID = province.ID
+	return nil
+}
+
+func checkProvince(ctx context.Context, s *session) error {
+	if s.provinceID == "" {
+		return skip("no province known")
+	}
+	resp, err := s.get(ctx, "/api/v1/provinces/"+url.PathEscape(s.provinceID))
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "id", "name")
+}
+
+func checkProvinceCases(ctx context.Context, s *session) error {
+	if s.provinceID == "" {
+		return skip("no province known")
+	}
+	_, err := checkPages(ctx, s, "/api/v1/provinces/"+url.PathEscape(s.provinceID)+"/cases", provinceCaseKeys)
+	return err
+}
+
+func checkAllProvinceCases(ctx context.Context, s *session) error {
+	_, err := getPage(ctx, s, "/api/v1/provinces/cases", 2, 0, provinceCaseKeys)
+	return err
+}
+
+func checkLatestProvinceCases(ctx context.Context, s *session) error {
+	if s.provinceID == "" {
+		return skip("no province known")
+	}
+	resp, err := s.get(ctx, "/api/v1/provinces/latest?ids="+url.QueryEscape(s.provinceID))
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	rows, err := requireRows(data, "id", "name")
+	if err != nil {
+		return resp.fail("%v", err)
+	}
+	if len(rows) != 1 {
+		return resp.fail("%d provinces, want 1", len(rows))
+	}
+	var province struct {
+		LatestCase json.RawMessage `json:"latest_case"`
+		Error      string          `json:"error"`
+	}
+	_ = json.Unmarshal(rows[0], &province)
+	if province.Error != "" {
+		return resp.fail("latest case failed to load: %s", province.Error)
+	}
+	if province.LatestCase == nil {
+		return nil
+	}
+	if err := requireKeys(province.LatestCase, provinceCaseKeys...); err != nil {
+		return resp.fail("latest_case: %v", err)
+	}
+	return nil
+}
+
+func checkMetrics(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/metrics")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	rows, err := requireRows(data, "name", "description", "dependencies")
+	if err != nil {
+		return resp.fail("%v", err)
+	}
+	if len(rows) == 0 {
+		return resp.fail("no metrics")
+	}
+	var metric struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(rows[0], &metric)
+	s.metric = metric.Name
+	return nil
+}
+
+func checkRankings(ctx context.Context, s *session) error {
+	if s.metric == "" {
+		return skip("no metric known")
+	}
+	resp, err := s.get(ctx, "/api/v1/rankings?metric="+url.QueryEscape(s.metric))
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	if _, err := requireRows(data, "rank", "province", "value", "day", "date"); err != nil {
+		return resp.fail("%v", err)
+	}
+	return nil
+}
+
+func checkProvinceMetrics(ctx context.Context, s *session) error {
+	if s.provinceID == "" {
+		return skip("no province known")
+	}
+	resp, err := s.get(ctx, "/api/v1/provinces/"+url.PathEscape(s.provinceID)+"/metrics")
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "province_id", "days", "metrics")
+}
+
+func checkProvinceCalendar(ctx context.Context, s *session) error {
+	if s.provinceID == "" || s.latestDate.IsZero() {
+		return skip("no province or latest date known")
+	}
+	resp, err := s.get(ctx, fmt.Sprintf("/api/v1/provinces/%s/calendar?year=%d", url.PathEscape(s.provinceID), s.latestDate.Year()))
+	if err != nil {
+		return err
+	}
+	data, err := resp.envelope()
+	if err != nil {
+		return err
+	}
+	return resp.requireKeys(data, "province_id", "year", "metric", "days")
+}
+
+func checkExportXLSX(ctx context.Context, s *session) error {
+	if s.latestDate.IsZero() {
+		return skip("latest national case unknown")
+	}
+	end := s.latestDate.Format("2006-01-02")
+	start := s.latestDate.AddDate(0, 0, -6).Format("2006-01-02")
+	resp, err := s.get(ctx, "/api/v1/export/xlsx?scope=national&start_date="+start+"&end_date="+end)
+	if err != nil {
+		return err
+	}
+	if err := resp.expect(http.StatusOK); err != nil {
+		return err
+	}
+	if err := resp.contentType("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"); err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(resp.body, []byte("PK")) {
+		return resp.fail("body is not a zip archive")
+	}
+	return nil
+}
+
+// conditionalCheck checks a cacheable file: its media type, its signature
+// when given, and that a request with its ETag answers 304
+func conditionalCheck(path, mediaType string, signature []byte) func(ctx context.Context, s *session) error {
+	return func(ctx context.Context, s *session) error {
+		resp, err := s.get(ctx, path)
+		if err != nil {
+			return err
+		}
+		if err := resp.expect(http.StatusOK); err != nil {
+			return err
+		}
+		if err := resp.contentType(mediaType); err != nil {
+			return err
+		}
+		if signature != nil && !bytes.HasPrefix(resp.body, signature) {
+			return resp.fail("body is not %s", mediaType)
+		}
+		if _, err := resp.requireHeader("Cache-Control"); err != nil {
+			return err
+		}
+		etag, err := resp.requireHeader("ETag")
+		if err != nil {
+			return err
+		}
+		revalidated, err := s.get(ctx, path, "If-None-Match", etag)
+		if err != nil {
+			return err
+		}
+		return revalidated.expect(http.StatusNotModified)
+	}
+}
+
+func checkDailyReport(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/reports/daily")
+	if err != nil {
+		return err
+	}
+	if err := resp.optional(); err != nil {
+		return err
+	}
+	if err := resp.expect(http.StatusOK); err != nil {
+		return err
+	}
+	if err := resp.contentType("application/pdf"); err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(resp.body, []byte("%PDF")) {
+		return resp.fail("body is not a PDF")
+	}
+	return nil
+}
+
+func checkRobots(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/robots.txt")
+	if err != nil {
+		return err
+	}
+	if err := resp.expect(http.StatusOK); err != nil {
+		return err
+	}
+	if err := resp.contentType("text/plain"); err != nil {
+		return err
+	}
+	if !bytes.Contains(resp.body, []byte("User-agent: *")) {
+		return resp.fail("no User-agent: * group")
+	}
+	return nil
+}
+
+func checkUnknownRoute(ctx context.Context, s *session) error {
+	resp, err := s.get(ctx, "/api/v1/smoke-test-unknown-route")
+	if err != nil {
+		return err
+	}
+	return resp.expect(http.StatusNotFound)
+}
+
+// envelopeCheck checks that path answers with the success envelope
+func envelopeCheck(path string) func(ctx context.Context, s *session) error {
+	return func(ctx context.Context, s *session) error {
+		resp, err := s.get(ctx, path)
+		if err != nil {
+			return err
+		}
+		_, err = resp.envelope()
+		return err
+	}
+}
+
+// optionalCheck checks an endpoint of optionalEndpoints
+func optionalCheck(pattern string) func(ctx context.Context, s *session) error {
+	return func(ctx context.Context, s *session) error {
+		values := map[string]string{}
+		if s.latestDay != 0 {
+			values["{day}"] = strconv.FormatInt(s.latestDay, 10)
+			values["{year}"] = strconv.Itoa(s.latestDate.Year())
+		}
+		if s.provinceID != "" {
+			values["{provinceId}"] = url.PathEscape(s.provinceID)
+		}
+		path := pattern
+		for _, placeholder := range []string{"{day}", "{year}", "{provinceId}"} {
+			if !strings.Contains(path, placeholder) {
+				continue
+			}
+			value, ok := values[placeholder]
+			if !ok {
+				return skip("%s unknown", strings.Trim(placeholder, "{}"))
+			}
+			path = strings.ReplaceAll(path, placeholder, value)
+		}
+		resp, err := s.get(ctx, path)
+		if err != nil {
+			return err
+		}
+		if err := resp.optional(); err != nil {
+			return err
+		}
+		_, err = resp.envelope()
+		return err
+	}
+}
@@ -0,0 +1,355 @@
+// Package smoke runs the end-to-end smoke test of a running deployment. It
+// requests every public endpoint and checks the status, JSON schema, headers,
+// pagination and caching of the responses, going further than the health
+// check after a deploy.
+package smoke
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Check statuses
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// maxBodyBytes bounds the response bodies read, which are held in memory
+const maxBodyBytes = 64 << 20
+
+// maxRateLimitWait is the longest Retry-After waited for before a rate
+// limited request is retried
+const maxRateLimitWait = time.Minute
+
+// Options configures a smoke test run
+type Options struct {
+	// BaseURL is the deployment to test, e.g. https://pico-api.banuacoder.com
+	BaseURL string
+	// APIKey is sent as X-API-Key when set, e.g. for a higher rate limit
+	APIKey string
+	// Timeout bounds each request
+	Timeout time.Duration
+	// Client sends the requests; nil uses an http.Client with Timeout
+	Client *http.Client
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Report is the outcome of a smoke test run. OK is false when a check failed.
+type Report struct {
+	BaseURL string   `json:"base_url"`
+	OK      bool     `json:"ok"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+	Results []Result `json:"results"`
+}
+
+// skipError marks a check that did not run, e.g. because the deployment does
+// not enable its endpoint
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+func skip(format string, args ...interface{}) error {
+	return skipError{reason: fmt.Sprintf(format, args...)}
+}
+
+// check is one step of the smoke test
+type check struct {
+	name string
+	run  func(ctx context.Context, s *session) error
+}
+
+// Run runs the checks against the deployment at opts.BaseURL in turn. Later
+// checks use the day and province found by earlier ones and are skipped when
+// those failed.
+func Run(ctx context.Context, opts Options) Report {
+	s := newSession(opts)
+	report := Report{BaseURL: s.baseURL, Results: []Result{}}
+	for _, c := range checks() {
+		start := time.Now()
+		err := c.run(ctx, s)
+		result := Result{Name: c.name, Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+		var skipped skipError
+		switch {
+		case errors.As(err, &skipped):
+			result.Status = StatusSkipped
+			report.Skipped++
+		case err != nil:
+			result.Status = StatusFailed
+			report.Failed++
+		default:
+			report.Passed++
+		}
+		if err != nil {
+			result.Message = err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.OK = report.Failed == 0
+	return report
+}
+
+// Format renders a report as one block for the terminal, the failed checks
+// first
+func Format(report Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Smoke test of %s: %d passed, %d failed, %d skipped", report.BaseURL, report.Passed, report.Failed, report.Skipped)
+	for _, status := range []string{StatusFailed, StatusSkipped, StatusOK} {
+		for _, r := range report.Results {
+			if r.Status != status {
+				continue
+			}
+			fmt.Fprintf(&b, "\n  %-8s %s (%dms)", r.Status, r.Name, r.DurationMs)
+			if r.Message != "" {
+				b.WriteString(": " + r.Message)
+			}
+		}
+	}
+	return b.String()
+}
+
+// session sends the requests of a run and keeps what the checks found
+type session struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	// latestDay and latestDate are those of the latest national case
+	latestDay  int64
+	latestDate time.Time
+	// nationalTotal is the number of national cases
+	nationalTotal int
+	provinceID    string
+	metric        string
+}
+
+func newSession(opts Options) *session {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	return &session{
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		apiKey:  opts.APIKey,
+		client:  client,
+		sleep:   sleepContext,
+	}
+}
+
+// response is a response read in full
+type response struct {
+	method string
+	path   string
+	status int
+	header http.Header
+	body   []byte
+}
+
+// get requests path, setting the header name and value pairs
+func (s *session) get(ctx context.Context, path string, header ...string) (*response, error) {
+	return s.do(ctx, http.MethodGet, path, header...)
+}
+
+// do sends a request, waiting out and retrying rate limited ones
+func (s *session) do(ctx context.Context, method, path string, header ...string) (*response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "pico-api-smoke")
+		if s.apiKey != "" {
+			req.Header.Set("X-API-Key", s.apiKey)
+		}
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", method, path, err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: failed to read response: %w", method, path, err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < 3 {
+			wait, convErr := strconv.Atoi(resp.Header.Get("Retry-After"))
+			if convErr == nil && time.Duration(wait)*time.Second <= maxRateLimitWait {
+				if err := s.sleep(ctx, time.Duration(wait)*time.Second+time.Second/2); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		return &response{method: method, path: path, status: resp.StatusCode, header: resp.Header, body: body}, nil
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fail describes a problem with the response
+func (r *response) fail(format string, args ...interface{}) error {
+	return fmt.Errorf("%s %s: %s", r.method, r.path, fmt.Sprintf(format, args...))
+}
+
+// expect checks the status, describing an error response by its message
+func (r *response) expect(status int) error {
+	if r.status == status {
+		return nil
+	}
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	detail := ""
+	if json.Unmarshal(r.body, &body) == nil && (body.Error != "" || body.Message != "") {
+		detail = ": " + strings.TrimSpace(body.Error+" "+body.Message)
+	}
+	return r.fail("status %d, want %d%s", r.status, status, detail)
+}
+
+// optional skips a check whose endpoint the deployment does not serve
+func (r *response) optional() error {
+	if r.status == http.StatusNotFound {
+		return skip("%s %s: 404, not enabled on this deployment or no data", r.method, r.path)
+	}
+	return nil
+}
+
+// contentType checks the media type of the response
+func (r *response) contentType(mediaType string) error {
+	if got := r.header.Get("Content-Type"); !strings.HasPrefix(got, mediaType) {
+		return r.fail("Content-Type %q, want %s", got, mediaType)
+	}
+	return nil
+}
+
+// envelope checks a successful JSON response and returns its data
+func (r *response) envelope() (json.RawMessage, error) {
+	if err := r.expect(http.StatusOK); err != nil {
+		return nil, err
+	}
+	if err := r.contentType("application/json"); err != nil {
+		return nil, err
+	}
+	var body struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(r.body, &body); err != nil {
+		return nil, r.fail("invalid JSON: %v", err)
+	}
+	if body.Status != "success" {
+		return nil, r.fail("status %q, want success", body.Status)
+	}
+	if len(body.Data) == 0 || string(body.Data) == "null" {
+		return nil, r.fail("no data")
+	}
+	return body.Data, nil
+}
+
+// requireHeader checks that the response has the header and returns it
+func (r *response) requireHeader(name string) (string, error) {
+	value := r.header.Get(name)
+	if value == "" {
+		return "", r.fail("no %s header", name)
+	}
+	return value, nil
+}
+
+// requireKeys checks that the JSON object has the dot paths, e.g. daily.positive
+func requireKeys(raw json.RawMessage, paths ...string) error {
+	for _, path := range paths {
+		value := raw
+		for _, name := range strings.Split(path, ".") {
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(value, &object); err != nil {
+				return fmt.Errorf("%s: not an object", path)
+			}
+			var ok bool
+			if value, ok = object[name]; !ok {
+				return fmt.Errorf("missing %s", path)
+			}
+		}
+	}
+	return nil
+}
+
+// requireKeys checks that the JSON object of the response has the dot paths
+func (r *response) requireKeys(raw json.RawMessage, paths ...string) error {
+	if err := requireKeys(raw, paths...); err != nil {
+		return r.fail("%v", err)
+	}
+	return nil
+}
+
+// requireRows checks that every row of the JSON array has the dot paths and
+// returns the rows
+func requireRows(raw json.RawMessage, paths ...string) ([]json.RawMessage, error) {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, errors.New("data is not a list")
+	}
+	for i, row := range rows {
+		if err := requireKeys(row, paths...); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return rows, nil
+}
+
+// keysOf returns the names of a JSON object in order
+func keysOf(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("not an object")
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, tok.(string))
+		var skipped json.RawMessage
+		if err := dec.Decode(&skipped); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
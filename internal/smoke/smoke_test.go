@@ -0,0 +1,308 @@
+package smoke
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeployment serves the core endpoints of the API over five days of
+// national cases; the optional endpoints answer 404
+type fakeDeployment struct {
+	mu     sync.Mutex
+	seen   map[string]bool
+	mux    *http.ServeMux
+	cached bool
+	// broken is a path that answers 500
+	broken string
+}
+
+func newFakeDeployment() *fakeDeployment {
+	d := &fakeDeployment{seen: map[string]bool{}, mux: http.NewServeMux(), cached: true}
+	success := func(data interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": data})
+		}
+	}
+	m := d.mux
+	m.HandleFunc("GET /healthz", success(map[string]string{"status": "alive"}))
+	m.HandleFunc("GET /api/v1/health", success(map[string]interface{}{"status": "healthy", "database": map[string]string{"status": "healthy"}}))
+	m.HandleFunc("GET /api/v1", success(map[string]interface{}{"api": map[string]string{"version": "2.9.0"}, "endpoints": map[string]string{}}))
+	m.HandleFunc("GET /api/v1/.well-known/api-descriptor", success(map[string]interface{}{
+		"name": "pico-api", "version": "2.9.0", "formats": []string{"json", "csv"},
+		"limits": map[string]int{"default_page_size": 50}, "rate_limit": map[string]int{}, "metrics": []string{},
+	}))
+	m.HandleFunc("GET /api/v1/changes", success(map[string]interface{}{"changes": []string{}}))
+	m.HandleFunc("GET /api/v1/tenant", success(map[string]string{"id": "default"}))
+	m.HandleFunc("GET /api/v1/national/latest", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fields") == "day,daily.positive" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"status":"success","data":{"day":5,"daily":{"positive":5}}}`)
+			return
+		}
+		success(caseRow(5, false))(w, r)
+	})
+	m.HandleFunc("GET /api/v1/national/{day}", func(w http.ResponseWriter, r *http.Request) {
+		day, _ := strconv.Atoi(r.PathValue("day"))
+		success(caseRow(day, false))(w, r)
+	})
+	m.HandleFunc("GET /api/v1/national", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start_date") == "not-a-date" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"status": "error", "error": "Invalid start_date", "code": "INVALID_DATE_FORMAT", "field": "start_date"})
+			return
+		}
+		writePage(w, r, false)
+	})
+	m.HandleFunc("GET /api/v1/national/compare-periods", success(map[string]interface{}{"period_a": map[string]int{}, "period_b": map[string]int{}, "percent_change": map[string]int{}}))
+	m.HandleFunc("GET /api/v1/provinces", success([]map[string]string{{"id": "72", "name": "Sulawesi Tengah"}}))
+	m.HandleFunc("GET /api/v1/provinces/{code}", success(map[string]string{"id": "72", "name": "Sulawesi Tengah"}))
+	m.HandleFunc("GET /api/v1/provinces/{id}/cases", func(w http.ResponseWriter, r *http.Request) { writePage(w, r, true) })
+	m.HandleFunc("GET /api/v1/provinces/cases", func(w http.ResponseWriter, r *http.Request) { writePage(w, r, true) })
+	m.HandleFunc("GET /api/v1/provinces/latest", success([]map[string]interface{}{{"id": "72", "name": "Sulawesi Tengah", "latest_case": caseRow(5, true)}}))
+	m.HandleFunc("GET /api/v1/metrics", success([]map[string]interface{}{{"name": "rt", "description": "Reproduction rate", "dependencies": []string{}}}))
+	m.HandleFunc("GET /api/v1/rankings", success([]map[string]interface{}{{"rank": 1, "province": map[string]string{"id": "72"}, "value": 1.1, "day": 5, "date": "2020-03-06T00:00:00Z"}}))
+	m.HandleFunc("GET /api/v1/provinces/{id}/metrics", success(map[string]interface{}{"province_id": "72", "days": 5, "metrics": map[string]int{}}))
+	m.HandleFunc("GET /api/v1/provinces/{id}/calendar", success(map[string]interface{}{"province_id": "72", "year": 2020, "metric": "positive", "days": []int{}}))
+	m.HandleFunc("GET /api/v1/export/xlsx", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		_, _ = io.WriteString(w, "PK\x03\x04")
+	})
+	m.HandleFunc("GET /api/v1/embed/summary.html", conditional("text/html; charset=utf-8", "<div></div>"))
+	m.HandleFunc("GET /api/v1/og/daily.png", conditional("image/png", "\x89PNG\r\n\x1a\nIHDR"))
+	m.HandleFunc("GET /robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "User-agent: *\nAllow: /\n")
+	})
+	return d
+}
+
+// ServeHTTP adds the CORS, rate limit and cache headers of the middleware
+func (d *fakeDeployment) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("X-RateLimit-Limit", "100")
+	w.Header().Set("X-RateLimit-Remaining", "99")
+	w.Header().Set("X-RateLimit-Reset", "1700000000")
+	if strings.HasPrefix(r.URL.Path, "/api/v1/national") {
+		maxAge := 3600
+		if r.URL.Path == "/api/v1/national/latest" {
+			maxAge = 300
+		}
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+		w.Header().Set("Expires", time.Now().Add(time.Duration(maxAge)*time.Second).UTC().Format(http.TimeFormat))
+		if d.cached {
+			key := r.Header.Get("Accept") + r.URL.RequestURI()
+			d.mu.Lock()
+			if d.seen[key] {
+				w.Header().Set("X-Response-Cache", "HIT")
+			} else {
+				w.Header().Set("X-Response-Cache", "MISS")
+			}
+			d.seen[key] = true
+			d.mu.Unlock()
+		}
+	}
+	if r.URL.Path == d.broken {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"status": "error", "error": "Failed to compare periods"})
+		return
+	}
+	d.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func caseRow(day int, province bool) map[string]interface{} {
+	counts := map[string]int{"positive": day, "recovered": 0, "deceased": 0, "active": day}
+	daily := map[string]int{"positive": day, "recovered": 0, "deceased": 0, "active": day}
+	if province {
+		daily["odp"], daily["pdp"] = 0, 0
+	}
+	return map[string]interface{}{
+		"day":        day,
+		"date":       time.Date(2020, 3, 1+day, 0, 0, 0, 0, time.UTC),
+		"daily":      daily,
+		"cumulative": counts,
+		"statistics": map[string]interface{}{},
+	}
+}
+
+// writePage writes a page of the five days, as JSON or CSV by Accept
+func writePage(w http.ResponseWriter, r *http.Request, province bool) {
+	const total = 5
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	rows := []map[string]interface{}{}
+	for day := offset + 1; day <= min(offset+limit, total); day++ {
+		rows = append(rows, caseRow(day, province))
+	}
+	if r.Header.Get("Accept") == "text/csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		w.Header().Set("X-Pagination-Limit", strconv.Itoa(limit))
+		_, _ = io.WriteString(w, "day,date,positive\n")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%d,2020-03-0%d,%d\n", row["day"], row["day"].(int)+1, row["day"])
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": map[string]interface{}{
+		"data": rows,
+		"pagination": map[string]interface{}{
+			"limit": limit, "offset": offset, "total": total, "total_pages": (total + limit - 1) / limit,
+			"page": offset/limit + 1, "has_next": offset+limit < total, "has_prev": offset > 0,
+		},
+	}})
+}
+
+func conditional(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func resultsByName(report Report) map[string]Result {
+	results := map[string]Result{}
+	for _, r := range report.Results {
+		results[r.Name] = r
+	}
+	return results
+}
+
+func TestRun_HealthyDeployment(t *testing.T) {
+	server := httptest.NewServer(newFakeDeployment())
+	defer server.Close()
+
+	report := Run(context.Background(), Options{BaseURL: server.URL + "/", Timeout: 5 * time.Second})
+
+	for _, r := range report.Results {
+		assert.NotEqual(t, StatusFailed, r.Status, "%s: %s", r.Name, r.Message)
+	}
+	assert.True(t, report.OK)
+	assert.Equal(t, server.URL, report.BaseURL)
+	assert.Equal(t, len(report.Results), report.Passed+report.Skipped)
+
+	results := resultsByName(report)
+	assert.Equal(t, StatusOK, results["response cache"].Status)
+	assert.Equal(t, StatusOK, results["national pagination"].Status)
+	assert.Equal(t, StatusSkipped, results["readiness"].Status)
+	assert.Equal(t, StatusSkipped, results["/summary"].Status)
+	assert.Contains(t, results["/national/{day}/revisions"].Message, "/api/v1/national/5/revisions: 404")
+}
+
+func TestRun_ReportsFailures(t *testing.T) {
+	deployment := newFakeDeployment()
+	deployment.cached = false
+	deployment.broken = "/api/v1/national/compare-periods"
+	server := httptest.NewServer(deployment)
+	defer server.Close()
+
+	report := Run(context.Background(), Options{BaseURL: server.URL})
+
+	assert.False(t, report.OK)
+	assert.Equal(t, 1, report.Failed)
+	results := resultsByName(report)
+	assert.Equal(t, StatusSkipped, results["response cache"].Status)
+	assert.Equal(t, StatusFailed, results["period comparison"].Status)
+	assert.Equal(t, "GET /api/v1/national/compare-periods?period_a=2020-02&period_b=2020-03: status 500, want 200: Failed to compare periods",
+		results["period comparison"].Message)
+}
+
+func TestRun_UnreachableDeploymentSkipsDependentChecks(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	report := Run(context.Background(), Options{BaseURL: server.URL, Timeout: time.Second})
+
+	results := resultsByName(report)
+	assert.False(t, report.OK)
+	assert.Equal(t, StatusFailed, results["liveness"].Status)
+	assert.Equal(t, StatusSkipped, results["national case by day"].Status)
+	assert.Equal(t, "latest national case unknown", results["national case by day"].Message)
+}
+
+func TestGetPage_InconsistentPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": map[string]interface{}{
+			"data": []interface{}{caseRow(1, false)},
+			"pagination": map[string]interface{}{
+				"limit": 2, "offset": 0, "total": 5, "total_pages": 3, "page": 1, "has_next": true, "has_prev": false,
+			},
+		}})
+	}))
+	defer server.Close()
+
+	_, err := getPage(context.Background(), newSession(Options{BaseURL: server.URL}), "/api/v1/national", 2, 0, nationalCaseKeys)
+	assert.EqualError(t, err, "GET /api/v1/national?limit=2&offset=0: 1 rows of 5 from offset 0")
+}
+
+func TestSession_RetriesRateLimitedRequests(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	s := newSession(Options{BaseURL: server.URL, APIKey: "key"})
+	var waited []time.Duration
+	s.sleep = func(ctx context.Context, d time.Duration) error {
+		waited = append(waited, d)
+		return nil
+	}
+	resp, err := s.get(context.Background(), "/healthz")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.status)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []time.Duration{7500 * time.Millisecond}, waited)
+}
+
+func TestRequireKeys(t *testing.T) {
+	row := json.RawMessage(`{"day":1,"daily":{"positive":2},"statistics":null}`)
+	assert.NoError(t, requireKeys(row, "day", "daily.positive", "statistics"))
+	assert.EqualError(t, requireKeys(row, "daily.recovered"), "missing daily.recovered")
+	assert.EqualError(t, requireKeys(row, "day.value"), "day.value: not an object")
+}
+
+func TestFormat(t *testing.T) {
+	report := Report{BaseURL: "https://pico-api.banuacoder.com", Passed: 1, Failed: 1, Skipped: 1, Results: []Result{
+		{Name: "liveness", Status: StatusOK, DurationMs: 12},
+		{Name: "/summary", Status: StatusSkipped, DurationMs: 8, Message: "GET /api/v1/summary: 404, not enabled on this deployment or no data"},
+		{Name: "health", Status: StatusFailed, DurationMs: 30, Message: "GET /api/v1/health: degraded, database unhealthy"},
+	}}
+
+	assert.Equal(t, "Smoke test of https://pico-api.banuacoder.com: 1 passed, 1 failed, 1 skipped\n"+
+		"  failed   health (30ms): GET /api/v1/health: degraded, database unhealthy\n"+
+		"  skipped  /summary (8ms): GET /api/v1/summary: 404, not enabled on this deployment or no data\n"+
+		"  ok       liveness (12ms)", Format(report))
+}
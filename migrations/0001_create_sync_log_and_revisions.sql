@@ -0,0 +1,28 @@
+-- Tracks every ingestion (sync) run and the row-level revisions it produced so a
+-- run can be rolled back when upstream publishes corrupted data.
+
+CREATE TABLE IF NOT EXISTS sync_log (
+    id             BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    source         VARCHAR(64)     NOT NULL,
+    status         VARCHAR(16)     NOT NULL,
+    inserted       INT             NOT NULL DEFAULT 0,
+    updated        INT             NOT NULL DEFAULT 0,
+    started_at     DATETIME        NOT NULL,
+    finished_at    DATETIME        NULL,
+    rolled_back_at DATETIME        NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS revisions (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    sync_log_id BIGINT UNSIGNED NULL,
+    table_name  VARCHAR(64)     NOT NULL,
+    row_id      BIGINT          NOT NULL,
+    action      VARCHAR(16)     NOT NULL,
+    before_data JSON            NULL,
+    after_data  JSON            NULL,
+    created_at  DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_revisions_sync_log (sync_log_id),
+    KEY idx_revisions_row (table_name, row_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
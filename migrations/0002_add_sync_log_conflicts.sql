@@ -0,0 +1,3 @@
+-- Records how many incoming rows an ingestion run rejected as duplicates.
+
+ALTER TABLE sync_log ADD COLUMN conflicts INT NOT NULL DEFAULT 0 AFTER updated;
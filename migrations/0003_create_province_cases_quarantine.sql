@@ -0,0 +1,8 @@
+-- Holds province_cases rows moved aside by the orphan quarantine because their
+-- day has no national_cases record or their province_id has no province.
+
+CREATE TABLE IF NOT EXISTS province_cases_quarantine LIKE province_cases;
+
+ALTER TABLE province_cases_quarantine
+    ADD COLUMN quarantine_reason VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN quarantined_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP;
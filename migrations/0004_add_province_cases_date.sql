@@ -0,0 +1,19 @@
+-- Stores the date on province_cases so rows whose day has no national_cases
+-- record still carry a date. Read queries LEFT JOIN national_cases and fall
+-- back to this column: COALESCE(nc.date, pc.date).
+
+ALTER TABLE province_cases ADD COLUMN date DATE NULL AFTER day;
+
+-- The quarantine table mirrors province_cases column for column (see 0003)
+ALTER TABLE province_cases_quarantine ADD COLUMN date DATE NULL AFTER day;
+
+UPDATE province_cases pc
+JOIN national_cases nc ON pc.day = nc.id
+SET pc.date = nc.date
+WHERE pc.date IS NULL;
+
+CREATE INDEX idx_province_cases_date ON province_cases (date);
+
+-- Keep the column filled for rows written without an explicit date
+CREATE TRIGGER province_cases_fill_date BEFORE INSERT ON province_cases
+FOR EACH ROW SET NEW.date = COALESCE(NEW.date, (SELECT nc.date FROM national_cases nc WHERE nc.id = NEW.day));
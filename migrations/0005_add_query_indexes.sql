@@ -0,0 +1,15 @@
+-- Indexes for the API's query patterns. Keep in sync with service.ExpectedIndexes,
+-- which the server checks at startup.
+
+-- Day lookups (GetByDay, ingestion upserts) and date range / date sorted pages
+CREATE INDEX idx_national_cases_day ON national_cases (day);
+CREATE INDEX idx_national_cases_date ON national_cases (date);
+
+-- Province case lookups by natural key, joins to national_cases and
+-- per-province pages sorted by date
+CREATE INDEX idx_province_cases_province_day ON province_cases (province_id, day);
+CREATE INDEX idx_province_cases_day ON province_cases (day);
+CREATE INDEX idx_province_cases_province_date ON province_cases (province_id, date);
+
+-- Latest regency case per regency
+CREATE INDEX idx_regency_cases_regency_day ON regency_cases (regency_id, day);
@@ -0,0 +1,28 @@
+-- Webhook subscribers and the log of every signed delivery sent to them.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    url        VARCHAR(2048)   NOT NULL,
+    secret     VARCHAR(128)    NOT NULL,
+    events     VARCHAR(255)    NOT NULL DEFAULT '*',
+    active     TINYINT(1)      NOT NULL DEFAULT 1,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    delivery_id     CHAR(32)        NOT NULL,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    event           VARCHAR(64)     NOT NULL,
+    payload         JSON            NOT NULL,
+    status          VARCHAR(16)     NOT NULL,
+    attempts        INT             NOT NULL DEFAULT 0,
+    response_status INT             NULL,
+    last_error      VARCHAR(512)    NULL,
+    created_at      DATETIME        NOT NULL,
+    delivered_at    DATETIME        NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_webhook_deliveries_delivery_id (delivery_id),
+    KEY idx_webhook_deliveries_subscription (subscription_id, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+-- Schedules automatic retries of failed webhook deliveries.
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN next_attempt_at DATETIME NULL AFTER attempts,
+    ADD KEY idx_webhook_deliveries_due (status, next_attempt_at);
@@ -0,0 +1,5 @@
+-- Subscriber-defined filters narrowing which case changes trigger a delivery.
+-- NULL means every change of a subscribed event is delivered.
+
+ALTER TABLE webhook_subscriptions
+    ADD COLUMN filters JSON NULL AFTER events;
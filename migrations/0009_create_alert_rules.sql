@@ -0,0 +1,32 @@
+-- Threshold alert rules evaluated after each ingestion run, and the history of
+-- their evaluations.
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(128)    NOT NULL,
+    metric     VARCHAR(32)     NOT NULL,
+    scope      VARCHAR(16)     NOT NULL,
+    operator   VARCHAR(2)      NOT NULL,
+    threshold  DOUBLE          NOT NULL,
+    channel    VARCHAR(16)     NOT NULL,
+    target     VARCHAR(255)    NOT NULL DEFAULT '',
+    active     TINYINT(1)      NOT NULL DEFAULT 1,
+    created_at DATETIME        NOT NULL,
+    updated_at DATETIME        NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS alert_evaluations (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    rule_id      BIGINT UNSIGNED NOT NULL,
+    sync_log_id  BIGINT UNSIGNED NULL,
+    day          BIGINT          NULL,
+    value        DOUBLE          NULL,
+    triggered    TINYINT(1)      NOT NULL,
+    notified     TINYINT(1)      NOT NULL DEFAULT 0,
+    error        VARCHAR(512)    NULL,
+    evaluated_at DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_alert_evaluations_rule (rule_id, id),
+    KEY idx_alert_evaluations_rule_day (rule_id, day, notified)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
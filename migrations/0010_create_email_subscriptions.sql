@@ -0,0 +1,20 @@
+-- Email subscriptions to daily Sulawesi Tengah updates. Subscriptions stay
+-- pending until the confirmation link is followed (double opt-in).
+
+CREATE TABLE IF NOT EXISTS email_subscriptions (
+    id                   BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    email                VARCHAR(254)    NOT NULL,
+    status               VARCHAR(16)     NOT NULL,
+    confirm_token        CHAR(64)        NOT NULL,
+    unsubscribe_token    CHAR(64)        NOT NULL,
+    confirmation_sent_at DATETIME        NOT NULL,
+    confirmed_at         DATETIME        NULL,
+    unsubscribed_at      DATETIME        NULL,
+    last_sent_day        BIGINT          NULL,
+    created_at           DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_email_subscriptions_email (email),
+    UNIQUE KEY uq_email_subscriptions_confirm_token (confirm_token),
+    UNIQUE KEY uq_email_subscriptions_unsubscribe_token (unsubscribe_token),
+    KEY idx_email_subscriptions_due (status, last_sent_day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
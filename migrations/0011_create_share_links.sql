@@ -0,0 +1,13 @@
+-- Short links for shareable filtered views. Filters are stored as JSON and
+-- identical filter sets share one link through filters_hash.
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    code         VARCHAR(16)     NOT NULL,
+    filters      TEXT            NOT NULL,
+    filters_hash CHAR(64)        NOT NULL,
+    created_at   DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_share_links_code (code),
+    UNIQUE KEY uq_share_links_filters_hash (filters_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,23 @@
+-- Incident notices shown on the public API status page, and the periodic
+-- component checks its uptime percentages are computed from.
+
+CREATE TABLE IF NOT EXISTS status_incidents (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    title       VARCHAR(255)    NOT NULL,
+    message     TEXT            NOT NULL,
+    severity    VARCHAR(16)     NOT NULL,
+    created_at  DATETIME        NOT NULL,
+    updated_at  DATETIME        NOT NULL,
+    resolved_at DATETIME        NULL,
+    PRIMARY KEY (id),
+    KEY idx_status_incidents_resolved_at (resolved_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS status_checks (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    component  VARCHAR(32)     NOT NULL,
+    healthy    TINYINT(1)      NOT NULL,
+    checked_at DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_status_checks_checked_at (checked_at, component)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
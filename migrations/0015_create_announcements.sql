@@ -0,0 +1,14 @@
+-- Announcements managed by admins and shown in meta.notices of every API
+-- response while they are active, e.g. to explain delayed data.
+
+CREATE TABLE IF NOT EXISTS announcements (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    severity   VARCHAR(16)     NOT NULL,
+    message    VARCHAR(500)    NOT NULL,
+    starts_at  DATETIME        NOT NULL,
+    ends_at    DATETIME        NULL,
+    created_at DATETIME        NOT NULL,
+    updated_at DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_announcements_ends_at (ends_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,17 @@
+-- API keys accepted in the X-API-Key header when AUTH_ENABLED is set. Keys
+-- are stored as SHA-256 hashes; scopes are a comma separated list of read,
+-- write and admin. A positive requests_per_minute replaces the global rate
+-- limit for the key's requests.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id                  BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name                VARCHAR(64)     NOT NULL,
+    key_hash            CHAR(64)        NOT NULL,
+    scopes              VARCHAR(64)     NOT NULL,
+    requests_per_minute INT UNSIGNED    NOT NULL DEFAULT 0,
+    created_at          DATETIME        NOT NULL,
+    revoked_at          DATETIME        NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_api_keys_key_hash (key_hash),
+    UNIQUE KEY uq_api_keys_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,9 @@
+-- Highest case row ID already announced by the new row webhook poller, one row
+-- per watched table, so a restart does not announce or skip rows.
+
+CREATE TABLE IF NOT EXISTS webhook_cursors (
+    name       VARCHAR(64)     NOT NULL,
+    last_id    BIGINT UNSIGNED NOT NULL,
+    updated_at DATETIME        NOT NULL,
+    PRIMARY KEY (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,19 @@
+-- The revisions of national and province case rows, with the source of the
+-- run that wrote them. Every write to the case tables already records a row
+-- revision for rollbacks, so corrections of published figures are read from
+-- there rather than recorded twice.
+
+CREATE OR REPLACE VIEW case_revisions AS
+SELECT r.id,
+       r.sync_log_id,
+       s.source,
+       r.table_name,
+       r.row_id,
+       r.action,
+       r.before_data,
+       r.after_data,
+       r.created_at,
+       s.rolled_back_at
+FROM revisions r
+LEFT JOIN sync_log s ON r.sync_log_id = s.id
+WHERE r.table_name IN ('national_cases', 'province_cases');
@@ -0,0 +1,15 @@
+-- Erroneous provinces and case rows are hidden by setting deleted_at rather
+-- than deleted: province_cases reference national_cases and provinces, and
+-- revisions reference the case rows. Every read query skips rows with a
+-- deleted_at; the admin soft delete endpoints set and clear it.
+
+ALTER TABLE national_cases ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE province_cases ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE provinces ADD COLUMN deleted_at DATETIME NULL;
+
+-- The quarantine copies province_cases rows with SELECT pc.*, so its columns
+-- must follow those of province_cases before the quarantine ones (see 0003)
+ALTER TABLE province_cases_quarantine
+    ADD COLUMN deleted_at DATETIME NULL,
+    MODIFY COLUMN quarantine_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER deleted_at,
+    MODIFY COLUMN quarantined_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER quarantine_reason;
@@ -0,0 +1,15 @@
+-- Analyst notes on the national data (empty province_id) or a province's data
+-- of one date, e.g. "backlog reported this day", in Indonesian and English.
+-- Case responses carry them with ?include=notes.
+
+CREATE TABLE IF NOT EXISTS case_notes (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    province_id VARCHAR(2)      NOT NULL DEFAULT '',
+    date        DATE            NOT NULL,
+    text_id     VARCHAR(500)    NOT NULL DEFAULT '',
+    text_en     VARCHAR(500)    NOT NULL DEFAULT '',
+    created_at  DATETIME        NOT NULL,
+    updated_at  DATETIME        NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_case_notes_province_date (province_id, date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,25 @@
+-- Keeps province_cases.date equal to the date read queries select,
+-- COALESCE(nc.date, pc.date), so filters and sorts can use the column and its
+-- indexes (0004, 0005) directly. The insert trigger of 0004 only filled a
+-- missing date and nothing followed later changes of the day or of the
+-- national date.
+
+-- Resync the rows that drifted since 0004
+UPDATE province_cases pc
+JOIN national_cases nc ON pc.day = nc.id
+SET pc.date = nc.date
+WHERE nc.date IS NOT NULL AND NOT (pc.date <=> nc.date);
+
+-- The national date wins over a submitted one, as in the read queries
+DROP TRIGGER IF EXISTS province_cases_fill_date;
+CREATE TRIGGER province_cases_fill_date BEFORE INSERT ON province_cases
+FOR EACH ROW SET NEW.date = COALESCE((SELECT nc.date FROM national_cases nc WHERE nc.id = NEW.day), NEW.date);
+
+-- Follow a changed day, or a row updated with another date
+CREATE TRIGGER province_cases_sync_date BEFORE UPDATE ON province_cases
+FOR EACH ROW SET NEW.date = COALESCE((SELECT nc.date FROM national_cases nc WHERE nc.id = NEW.day), NEW.date);
+
+-- Follow a corrected national date
+CREATE TRIGGER national_cases_sync_province_date AFTER UPDATE ON national_cases
+FOR EACH ROW UPDATE province_cases SET date = NEW.date
+WHERE day = NEW.id AND NEW.date IS NOT NULL AND NOT (date <=> NEW.date);
@@ -0,0 +1,29 @@
+-- Tracks every ingestion (sync) run and the row-level revisions it produced so a
+-- run can be rolled back when upstream publishes corrupted data.
+
+CREATE TABLE IF NOT EXISTS sync_log (
+    id             BIGINT       GENERATED BY DEFAULT AS IDENTITY,
+    source         VARCHAR(64)  NOT NULL,
+    status         VARCHAR(16)  NOT NULL,
+    inserted       INT          NOT NULL DEFAULT 0,
+    updated        INT          NOT NULL DEFAULT 0,
+    started_at     TIMESTAMP    NOT NULL,
+    finished_at    TIMESTAMP    NULL,
+    rolled_back_at TIMESTAMP    NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS revisions (
+    id          BIGINT       GENERATED BY DEFAULT AS IDENTITY,
+    sync_log_id BIGINT       NULL,
+    table_name  VARCHAR(64)  NOT NULL,
+    row_id      BIGINT       NOT NULL,
+    action      VARCHAR(16)  NOT NULL,
+    before_data JSON         NULL,
+    after_data  JSON         NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_revisions_sync_log ON revisions (sync_log_id);
+CREATE INDEX IF NOT EXISTS idx_revisions_row ON revisions (table_name, row_id);
@@ -0,0 +1,38 @@
+-- Stores the date on province_cases so rows whose day has no national_cases
+-- record still carry a date. Read queries LEFT JOIN national_cases and fall
+-- back to this column: COALESCE(nc.date, pc.date).
+
+ALTER TABLE province_cases ADD COLUMN date DATE NULL;
+
+-- The quarantine table mirrors province_cases column for column (see 0003).
+-- PostgreSQL only appends columns, so the quarantine ones are re-added after
+-- the new column.
+ALTER TABLE province_cases_quarantine RENAME COLUMN quarantine_reason TO old_quarantine_reason;
+ALTER TABLE province_cases_quarantine RENAME COLUMN quarantined_at TO old_quarantined_at;
+ALTER TABLE province_cases_quarantine
+    ADD COLUMN date              DATE         NULL,
+    ADD COLUMN quarantine_reason VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN quarantined_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP;
+UPDATE province_cases_quarantine
+SET quarantine_reason = old_quarantine_reason, quarantined_at = old_quarantined_at;
+ALTER TABLE province_cases_quarantine
+    DROP COLUMN old_quarantine_reason,
+    DROP COLUMN old_quarantined_at;
+
+UPDATE province_cases pc
+SET date = nc.date
+FROM national_cases nc
+WHERE pc.day = nc.id AND pc.date IS NULL;
+
+CREATE INDEX idx_province_cases_date ON province_cases (date);
+
+-- Keep the column filled for rows written without an explicit date
+CREATE OR REPLACE FUNCTION province_cases_fill_date() RETURNS trigger AS $$
+BEGIN
+    NEW.date := COALESCE(NEW.date, (SELECT nc.date FROM national_cases nc WHERE nc.id = NEW.day));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER province_cases_fill_date BEFORE INSERT ON province_cases
+FOR EACH ROW EXECUTE FUNCTION province_cases_fill_date();
@@ -0,0 +1,29 @@
+-- Webhook subscribers and the log of every signed delivery sent to them.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         BIGINT         GENERATED BY DEFAULT AS IDENTITY,
+    url        VARCHAR(2048)  NOT NULL,
+    secret     VARCHAR(128)   NOT NULL,
+    events     VARCHAR(255)   NOT NULL DEFAULT '*',
+    active     BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGINT        GENERATED BY DEFAULT AS IDENTITY,
+    delivery_id     CHAR(32)      NOT NULL,
+    subscription_id BIGINT        NOT NULL,
+    event           VARCHAR(64)   NOT NULL,
+    payload         JSON          NOT NULL,
+    status          VARCHAR(16)   NOT NULL,
+    attempts        INT           NOT NULL DEFAULT 0,
+    response_status INT           NULL,
+    last_error      VARCHAR(512)  NULL,
+    created_at      TIMESTAMP     NOT NULL,
+    delivered_at    TIMESTAMP     NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_webhook_deliveries_delivery_id UNIQUE (delivery_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id);
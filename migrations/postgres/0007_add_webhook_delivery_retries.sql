@@ -0,0 +1,5 @@
+-- Schedules automatic retries of failed webhook deliveries.
+
+ALTER TABLE webhook_deliveries ADD COLUMN next_attempt_at TIMESTAMP NULL;
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,33 @@
+-- Threshold alert rules evaluated after each ingestion run, and the history of
+-- their evaluations.
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id         BIGINT            GENERATED BY DEFAULT AS IDENTITY,
+    name       VARCHAR(128)      NOT NULL,
+    metric     VARCHAR(32)       NOT NULL,
+    scope      VARCHAR(16)       NOT NULL,
+    operator   VARCHAR(2)        NOT NULL,
+    threshold  DOUBLE PRECISION  NOT NULL,
+    channel    VARCHAR(16)       NOT NULL,
+    target     VARCHAR(255)      NOT NULL DEFAULT '',
+    active     BOOLEAN           NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP         NOT NULL,
+    updated_at TIMESTAMP         NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS alert_evaluations (
+    id           BIGINT            GENERATED BY DEFAULT AS IDENTITY,
+    rule_id      BIGINT            NOT NULL,
+    sync_log_id  BIGINT            NULL,
+    day          BIGINT            NULL,
+    value        DOUBLE PRECISION  NULL,
+    triggered    BOOLEAN           NOT NULL,
+    notified     BOOLEAN           NOT NULL DEFAULT FALSE,
+    error        VARCHAR(512)      NULL,
+    evaluated_at TIMESTAMP         NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_evaluations_rule ON alert_evaluations (rule_id, id);
+CREATE INDEX IF NOT EXISTS idx_alert_evaluations_rule_day ON alert_evaluations (rule_id, day, notified);
@@ -0,0 +1,21 @@
+-- Email subscriptions to daily Sulawesi Tengah updates. Subscriptions stay
+-- pending until the confirmation link is followed (double opt-in).
+
+CREATE TABLE IF NOT EXISTS email_subscriptions (
+    id                   BIGINT        GENERATED BY DEFAULT AS IDENTITY,
+    email                VARCHAR(254)  NOT NULL,
+    status               VARCHAR(16)   NOT NULL,
+    confirm_token        CHAR(64)      NOT NULL,
+    unsubscribe_token    CHAR(64)      NOT NULL,
+    confirmation_sent_at TIMESTAMP     NOT NULL,
+    confirmed_at         TIMESTAMP     NULL,
+    unsubscribed_at      TIMESTAMP     NULL,
+    last_sent_day        BIGINT        NULL,
+    created_at           TIMESTAMP     NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_email_subscriptions_email UNIQUE (email),
+    CONSTRAINT uq_email_subscriptions_confirm_token UNIQUE (confirm_token),
+    CONSTRAINT uq_email_subscriptions_unsubscribe_token UNIQUE (unsubscribe_token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_subscriptions_due ON email_subscriptions (status, last_sent_day);
@@ -0,0 +1,13 @@
+-- Short links for shareable filtered views. Filters are stored as JSON and
+-- identical filter sets share one link through filters_hash.
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id           BIGINT       GENERATED BY DEFAULT AS IDENTITY,
+    code         VARCHAR(16)  NOT NULL,
+    filters      TEXT         NOT NULL,
+    filters_hash CHAR(64)     NOT NULL,
+    created_at   TIMESTAMP    NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_share_links_code UNIQUE (code),
+    CONSTRAINT uq_share_links_filters_hash UNIQUE (filters_hash)
+);
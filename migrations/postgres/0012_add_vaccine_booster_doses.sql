@@ -0,0 +1,12 @@
+-- Booster (third) doses. Rows recorded before boosters were given keep 0.
+
+ALTER TABLE national_vaccines
+    ADD COLUMN booster_vaccination_received BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN cumulative_booster_vaccination_received BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE province_vaccines
+    ADD COLUMN booster_vaccination_received BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN cumulative_booster_vaccination_received BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_national_vaccines_date ON national_vaccines (date);
+CREATE INDEX idx_province_vaccines_province_date ON province_vaccines (province_id, date);
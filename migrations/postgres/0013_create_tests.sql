@@ -0,0 +1,14 @@
+-- Daily PCR and antigen test counts. National totals use an empty province_id
+-- so one unique key covers both levels.
+
+CREATE TABLE IF NOT EXISTS tests (
+    id                 BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    province_id        VARCHAR(2)  NOT NULL DEFAULT '',
+    date               DATE        NOT NULL,
+    pcr                BIGINT      NOT NULL DEFAULT 0,
+    antigen            BIGINT      NOT NULL DEFAULT 0,
+    cumulative_pcr     BIGINT      NOT NULL DEFAULT 0,
+    cumulative_antigen BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_tests_province_date UNIQUE (province_id, date)
+);
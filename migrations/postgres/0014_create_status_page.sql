@@ -0,0 +1,25 @@
+-- Incident notices shown on the public API status page, and the periodic
+-- component checks its uptime percentages are computed from.
+
+CREATE TABLE IF NOT EXISTS status_incidents (
+    id          BIGINT        GENERATED BY DEFAULT AS IDENTITY,
+    title       VARCHAR(255)  NOT NULL,
+    message     TEXT          NOT NULL,
+    severity    VARCHAR(16)   NOT NULL,
+    created_at  TIMESTAMP     NOT NULL,
+    updated_at  TIMESTAMP     NOT NULL,
+    resolved_at TIMESTAMP     NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_incidents_resolved_at ON status_incidents (resolved_at);
+
+CREATE TABLE IF NOT EXISTS status_checks (
+    id         BIGINT       GENERATED BY DEFAULT AS IDENTITY,
+    component  VARCHAR(32)  NOT NULL,
+    healthy    BOOLEAN      NOT NULL,
+    checked_at TIMESTAMP    NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_checks_checked_at ON status_checks (checked_at, component);
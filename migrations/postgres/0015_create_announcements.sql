@@ -0,0 +1,15 @@
+-- Announcements managed by admins and shown in meta.notices of every API
+-- response while they are active, e.g. to explain delayed data.
+
+CREATE TABLE IF NOT EXISTS announcements (
+    id         BIGINT        GENERATED BY DEFAULT AS IDENTITY,
+    severity   VARCHAR(16)   NOT NULL,
+    message    VARCHAR(500)  NOT NULL,
+    starts_at  TIMESTAMP     NOT NULL,
+    ends_at    TIMESTAMP     NULL,
+    created_at TIMESTAMP     NOT NULL,
+    updated_at TIMESTAMP     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_ends_at ON announcements (ends_at);
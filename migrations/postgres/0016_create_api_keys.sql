@@ -0,0 +1,17 @@
+-- API keys accepted in the X-API-Key header when AUTH_ENABLED is set. Keys
+-- are stored as SHA-256 hashes; scopes are a comma separated list of read,
+-- write and admin. A positive requests_per_minute replaces the global rate
+-- limit for the key's requests.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id                  BIGINT       GENERATED BY DEFAULT AS IDENTITY,
+    name                VARCHAR(64)  NOT NULL,
+    key_hash            CHAR(64)     NOT NULL,
+    scopes              VARCHAR(64)  NOT NULL,
+    requests_per_minute INT          NOT NULL DEFAULT 0 CHECK (requests_per_minute >= 0),
+    created_at          TIMESTAMP    NOT NULL,
+    revoked_at          TIMESTAMP    NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT uq_api_keys_name UNIQUE (name)
+);
@@ -0,0 +1,5 @@
+-- Rate limit tier of an API key, one of the RATE_LIMIT_TIERS names. A positive
+-- requests_per_minute still wins over the tier.
+
+ALTER TABLE api_keys
+    ADD COLUMN tier VARCHAR(32) NULL;
@@ -0,0 +1,9 @@
+-- Highest case row ID already announced by the new row webhook poller, one row
+-- per watched table, so a restart does not announce or skip rows.
+
+CREATE TABLE IF NOT EXISTS webhook_cursors (
+    name       VARCHAR(64)  NOT NULL,
+    last_id    BIGINT       NOT NULL,
+    updated_at TIMESTAMP    NOT NULL,
+    PRIMARY KEY (name)
+);
@@ -0,0 +1,11 @@
+-- Province populations by source, e.g. the 2020 census (bps-2020) or a civil
+-- registry count (dukcapil-2021). The API reads the source named by
+-- POPULATION_SOURCE, so figures from several sources can be kept side by side.
+
+CREATE TABLE IF NOT EXISTS province_populations (
+    province_id VARCHAR(2)  NOT NULL,
+    source      VARCHAR(32) NOT NULL,
+    year        SMALLINT    NOT NULL,
+    population  BIGINT      NOT NULL,
+    PRIMARY KEY (province_id, source)
+);
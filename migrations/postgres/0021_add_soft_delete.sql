@@ -0,0 +1,23 @@
+-- Erroneous provinces and case rows are hidden by setting deleted_at rather
+-- than deleted: province_cases reference national_cases and provinces, and
+-- revisions reference the case rows. Every read query skips rows with a
+-- deleted_at; the admin soft delete endpoints set and clear it.
+
+ALTER TABLE national_cases ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE province_cases ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE provinces ADD COLUMN deleted_at TIMESTAMP NULL;
+
+-- The quarantine copies province_cases rows with SELECT pc.*, so its columns
+-- must follow those of province_cases before the quarantine ones (see 0003).
+-- As in 0004 the quarantine columns are re-added after the new one.
+ALTER TABLE province_cases_quarantine RENAME COLUMN quarantine_reason TO old_quarantine_reason;
+ALTER TABLE province_cases_quarantine RENAME COLUMN quarantined_at TO old_quarantined_at;
+ALTER TABLE province_cases_quarantine
+    ADD COLUMN deleted_at        TIMESTAMP    NULL,
+    ADD COLUMN quarantine_reason VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN quarantined_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP;
+UPDATE province_cases_quarantine
+SET quarantine_reason = old_quarantine_reason, quarantined_at = old_quarantined_at;
+ALTER TABLE province_cases_quarantine
+    DROP COLUMN old_quarantine_reason,
+    DROP COLUMN old_quarantined_at;
@@ -0,0 +1,16 @@
+-- One province_cases row per province and day. Ingestion already upserts on
+-- the key, but two submissions racing for a new day, or a hand edit, could
+-- still add a second row. The unique key replaces the plain index of 0005.
+--
+-- Soft-deleted rows keep their key, so resubmitting a hidden day restores and
+-- updates the row in place. The statement fails while duplicates exist; list them with
+--
+--   SELECT province_id, day, COUNT(*) FROM province_cases
+--   GROUP BY province_id, day HAVING COUNT(*) > 1;
+--
+-- and delete the extra rows before applying it.
+
+ALTER TABLE province_cases
+    ADD CONSTRAINT uq_province_cases_province_day UNIQUE (province_id, day);
+
+DROP INDEX idx_province_cases_province_day;
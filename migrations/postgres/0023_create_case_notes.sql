@@ -0,0 +1,16 @@
+-- Analyst notes on the national data (empty province_id) or a province's data
+-- of one date, e.g. "backlog reported this day", in Indonesian and English.
+-- Case responses carry them with ?include=notes.
+
+CREATE TABLE IF NOT EXISTS case_notes (
+    id          BIGINT        GENERATED BY DEFAULT AS IDENTITY,
+    province_id VARCHAR(2)    NOT NULL DEFAULT '',
+    date        DATE          NOT NULL,
+    text_id     VARCHAR(500)  NOT NULL DEFAULT '',
+    text_en     VARCHAR(500)  NOT NULL DEFAULT '',
+    created_at  TIMESTAMP     NOT NULL,
+    updated_at  TIMESTAMP     NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_notes_province_date ON case_notes (province_id, date);
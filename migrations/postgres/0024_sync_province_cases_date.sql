@@ -0,0 +1,54 @@
+-- Keeps province_cases.date equal to the date read queries select,
+-- COALESCE(nc.date, pc.date), so filters and sorts can use the column and its
+-- indexes (0004, 0005) directly. The insert trigger of 0004 only filled a
+-- missing date and nothing followed later changes of the day or of the
+-- national date.
+--
+-- Every trigger is dropped and created again, so the script can also be
+-- applied on its own to a database converted from MySQL, where the triggers
+-- were not carried over.
+
+-- Resync the rows that drifted since 0004
+UPDATE province_cases pc
+SET date = nc.date
+FROM national_cases nc
+WHERE pc.day = nc.id AND nc.date IS NOT NULL AND pc.date IS DISTINCT FROM nc.date;
+
+-- The national date wins over a submitted one, as in the read queries
+CREATE OR REPLACE FUNCTION province_cases_fill_date() RETURNS trigger AS $$
+BEGIN
+    NEW.date := COALESCE((SELECT nc.date FROM national_cases nc WHERE nc.id = NEW.day), NEW.date);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS province_cases_fill_date ON province_cases;
+CREATE TRIGGER province_cases_fill_date BEFORE INSERT ON province_cases
+FOR EACH ROW EXECUTE FUNCTION province_cases_fill_date();
+
+-- Follow a changed day, or a row updated with another date
+CREATE OR REPLACE FUNCTION province_cases_sync_date() RETURNS trigger AS $$
+BEGIN
+    NEW.date := COALESCE((SELECT nc.date FROM national_cases nc WHERE nc.id = NEW.day), NEW.date);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS province_cases_sync_date ON province_cases;
+CREATE TRIGGER province_cases_sync_date BEFORE UPDATE ON province_cases
+FOR EACH ROW EXECUTE FUNCTION province_cases_sync_date();
+
+-- Follow a corrected national date
+CREATE OR REPLACE FUNCTION national_cases_sync_province_date() RETURNS trigger AS $$
+BEGIN
+    IF NEW.date IS NOT NULL THEN
+        UPDATE province_cases SET date = NEW.date
+        WHERE day = NEW.id AND date IS DISTINCT FROM NEW.date;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS national_cases_sync_province_date ON national_cases;
+CREATE TRIGGER national_cases_sync_province_date AFTER UPDATE ON national_cases
+FOR EACH ROW EXECUTE FUNCTION national_cases_sync_province_date();
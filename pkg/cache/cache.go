@@ -7,10 +7,16 @@ import (
 )
 
 type entry struct {
-	value     interface{}
+	value interface{}
+	// expiresAt is zero for pinned entries
 	expiresAt time.Time
 }
 
+// expired reports whether the entry has expired at now. Pinned entries never expire.
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
 // Cache is a thread-safe in-memory cache with TTL support.
 type Cache struct {
 	mu         sync.RWMutex
@@ -37,12 +43,20 @@ func (c *Cache) Set(key string, value interface{}, ttl ...time.Duration) {
 	c.mu.Unlock()
 }
 
+// Pin stores a value that never expires. It stays until it is replaced,
+// deleted or the cache is cleared.
+func (c *Cache) Pin(key string, value interface{}) {
+	c.mu.Lock()
+	c.items[key] = entry{value: value}
+	c.mu.Unlock()
+}
+
 // Get retrieves a value from the cache. Returns (value, true) on hit, (nil, false) on miss or expiry.
 func (c *Cache) Get(key string) (interface{}, bool) {
 	c.mu.RLock()
 	e, ok := c.items[key]
 	c.mu.RUnlock()
-	if !ok || time.Now().After(e.expiresAt) {
+	if !ok || e.expired(time.Now()) {
 		return nil, false
 	}
 	return e.value, true
@@ -66,6 +80,20 @@ func (c *Cache) DeletePrefix(prefix string) {
 	c.mu.Unlock()
 }
 
+// Len returns the number of unexpired entries in the cache.
+func (c *Cache) Len() int {
+	now := time.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	n := 0
+	for _, e := range c.items {
+		if !e.expired(now) {
+			n++
+		}
+	}
+	return n
+}
+
 // Clear removes all entries from the cache.
 func (c *Cache) Clear() {
 	c.mu.Lock()
@@ -82,7 +110,7 @@ func (c *Cache) StartCleanup(interval time.Duration) {
 			now := time.Now()
 			c.mu.Lock()
 			for k, e := range c.items {
-				if now.After(e.expiresAt) {
+				if e.expired(now) {
 					delete(c.items, k)
 				}
 			}
@@ -82,6 +82,21 @@ func TestStartCleanup(t *testing.T) {
 	assert.False(t, exists, "cleanup goroutine should have evicted expired entry")
 }
 
+func TestPin(t *testing.T) {
+	c := New(time.Millisecond * 50)
+	c.Pin("k", "v")
+	c.StartCleanup(time.Millisecond * 30)
+	time.Sleep(time.Millisecond * 100)
+	v, ok := c.Get("k")
+	assert.True(t, ok, "pinned entries should not expire")
+	assert.Equal(t, "v", v)
+	assert.Equal(t, 1, c.Len())
+
+	c.Clear()
+	_, ok = c.Get("k")
+	assert.False(t, ok)
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	c := New(time.Minute)
 	var wg sync.WaitGroup
@@ -96,3 +111,13 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCache_Len(t *testing.T) {
+	c := New(time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("expired", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	assert.Equal(t, 2, c.Len())
+}
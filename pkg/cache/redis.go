@@ -174,10 +174,20 @@ func (c *RedisAwareCache) Clear() {
 	c.redis.Clear()
 }
 
+// Len returns the number of unexpired entries in the in-memory layer.
+func (c *RedisAwareCache) Len() int {
+	return c.mem.Len()
+}
+
 func (c *RedisAwareCache) StartCleanup(interval time.Duration) {
 	c.mem.StartCleanup(interval)
 }
 
+// Ping verifies connectivity to the Redis layer.
+func (c *RedisAwareCache) Ping(ctx context.Context) error {
+	return c.redis.Ping(ctx)
+}
+
 // Unwrap returns the underlying *Cache for compatibility with functions that require *Cache directly.
 func (c *RedisAwareCache) Unwrap() *Cache {
 	return c.mem
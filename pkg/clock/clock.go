@@ -0,0 +1,56 @@
+// Package clock abstracts the current time, tickers and timers so time
+// dependent code can run on the system clock, on a clock shifted to another
+// date or on a Mock advanced by hand.
+package clock
+
+import "time"
+
+// Clock tells the time and schedules ticks
+type Clock interface {
+	Now() time.Time
+	// NewTicker sends the time every d, dropping ticks for slow receivers
+	// like time.NewTicker
+	NewTicker(d time.Duration) Ticker
+	// After sends the time once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker is a time.Ticker of a Clock
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// System is the real clock
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type systemTicker struct {
+	ticker *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.ticker.C }
+
+func (t systemTicker) Stop() { t.ticker.Stop() }
+
+// Offset returns a clock that starts at start and runs at the speed of the
+// system clock, e.g. to present historic data as current
+func Offset(start time.Time) Clock {
+	return offsetClock{offset: time.Until(start)}
+}
+
+type offsetClock struct {
+	systemClock
+	offset time.Duration
+}
+
+func (c offsetClock) Now() time.Time { return time.Now().Add(c.offset) }
@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mockStart = time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC)
+
+func TestMock_Now(t *testing.T) {
+	m := NewMock(mockStart)
+	assert.Equal(t, mockStart, m.Now())
+
+	m.Advance(time.Hour)
+	assert.Equal(t, mockStart.Add(time.Hour), m.Now())
+
+	m.Set(mockStart)
+	assert.Equal(t, mockStart, m.Now())
+}
+
+func TestMock_NewTicker(t *testing.T) {
+	m := NewMock(mockStart)
+	ticker := m.NewTicker(time.Minute)
+
+	m.Advance(30 * time.Second)
+	assert.Empty(t, ticker.C())
+
+	m.Advance(30 * time.Second)
+	assert.Equal(t, mockStart.Add(time.Minute), <-ticker.C())
+
+	// Ticks not received are dropped
+	m.Advance(3 * time.Minute)
+	assert.Equal(t, mockStart.Add(2*time.Minute), <-ticker.C())
+	assert.Empty(t, ticker.C())
+
+	ticker.Stop()
+	m.Advance(time.Hour)
+	assert.Empty(t, ticker.C())
+}
+
+func TestMock_After(t *testing.T) {
+	m := NewMock(mockStart)
+	c := m.After(time.Second)
+	assert.Equal(t, 1, m.Waiting())
+
+	m.Advance(999 * time.Millisecond)
+	assert.Empty(t, c)
+	m.Advance(time.Millisecond)
+	assert.Equal(t, mockStart.Add(time.Second), <-c)
+	assert.Zero(t, m.Waiting())
+
+	m.Advance(time.Hour)
+	assert.Empty(t, c)
+
+	// Zero durations fire at once
+	assert.Equal(t, m.Now(), <-m.After(0))
+}
+
+func TestMock_Concurrent(t *testing.T) {
+	m := NewMock(mockStart)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		c := m.After(time.Duration(i+1) * time.Second)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-c
+			_ = m.Now()
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		go m.NewTicker(time.Second).Stop()
+		m.Advance(time.Second)
+	}
+	wg.Wait()
+}
+
+func TestOffset(t *testing.T) {
+	c := Offset(mockStart)
+
+	assert.WithinDuration(t, mockStart, c.Now(), time.Second)
+	assert.True(t, c.Now().After(mockStart) || c.Now().Equal(mockStart))
+}
@@ -0,0 +1,122 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Mock is a Clock that only moves when told to. Tickers and timers fire as
+// Set or Advance pass their times. It is safe for concurrent use.
+type Mock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*mockWaiter
+}
+
+// mockWaiter is a pending ticker or timer; interval is zero for timers
+type mockWaiter struct {
+	next     time.Time
+	interval time.Duration
+	c        chan time.Time
+	stopped  bool
+}
+
+// NewMock creates a Mock showing now
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now}
+}
+
+// Now returns the time last set
+func (m *Mock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Advance moves the clock forward by d
+func (m *Mock) Advance(d time.Duration) {
+	m.mu.Lock()
+	m.set(m.now.Add(d))
+	m.mu.Unlock()
+}
+
+// Set moves the clock to t. Moving it back fires nothing.
+func (m *Mock) Set(t time.Time) {
+	m.mu.Lock()
+	m.set(t)
+	m.mu.Unlock()
+}
+
+func (m *Mock) set(t time.Time) {
+	m.now = t
+	pending := m.waiters[:0]
+	for _, w := range m.waiters {
+		for !w.stopped && !w.next.After(t) {
+			// Like time.Ticker, a tick is dropped when the last one was
+			// not received yet
+			select {
+			case w.c <- w.next:
+			default:
+			}
+			if w.interval <= 0 {
+				w.stopped = true
+				break
+			}
+			w.next = w.next.Add(w.interval)
+		}
+		if !w.stopped {
+			pending = append(pending, w)
+		}
+	}
+	m.waiters = pending
+}
+
+// Waiting returns the number of tickers and timers still pending, so tests
+// can wait for code under test to start waiting before advancing the clock
+func (m *Mock) Waiting() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, w := range m.waiters {
+		if !w.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+// NewTicker returns a ticker firing every d of mock time
+func (m *Mock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return &mockTicker{mock: m, waiter: m.wait(d, d)}
+}
+
+// After returns a channel receiving the mock time once d has passed
+func (m *Mock) After(d time.Duration) <-chan time.Time {
+	return m.wait(d, 0).c
+}
+
+func (m *Mock) wait(d, interval time.Duration) *mockWaiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := &mockWaiter{next: m.now.Add(d), interval: interval, c: make(chan time.Time, 1)}
+	m.waiters = append(m.waiters, w)
+	// Timers of zero or negative durations fire at once
+	m.set(m.now)
+	return w
+}
+
+type mockTicker struct {
+	mock   *Mock
+	waiter *mockWaiter
+}
+
+func (t *mockTicker) C() <-chan time.Time { return t.waiter.c }
+
+func (t *mockTicker) Stop() {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	t.waiter.stopped = true
+}
@@ -0,0 +1,306 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dumpBatchSize is the number of rows written per INSERT statement in a dump.
+const dumpBatchSize = 100
+
+// statementTerminator ends every statement written by Dump. Restore splits on
+// the semicolons outside quotes and comments, so values may contain it.
+const statementTerminator = ";\n"
+
+// ErrBackupUnsupported is returned by Dump and Restore on engines other than
+// MySQL, whose own tools (pg_dump) should be used instead
+var ErrBackupUnsupported = errors.New("backups are only supported on MySQL")
+
+// Dump writes a SQL script recreating every table in the current schema to w.
+// The rows are read on one connection inside a consistent snapshot, so the
+// dump is a single point in time even while the API writes. The output can be
+// replayed with Restore.
+func (db *DB) Dump(ctx context.Context, w io.Writer) error {
+	if db.Dialect() != DialectMySQL {
+		return ErrBackupUnsupported
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for dump: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing dump connection: %v", err)
+		}
+	}()
+
+	// The snapshot needs REPEATABLE READ, whatever the server default is
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return fmt.Errorf("failed to set dump isolation level: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY"); err != nil {
+		return fmt.Errorf("failed to start dump snapshot: %w", err)
+	}
+	defer func() {
+		// The connection goes back to the pool, so end the snapshot even when
+		// the context was cancelled
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK"); err != nil {
+			log.Printf("Error ending dump snapshot: %v", err)
+		}
+	}()
+
+	tables, err := listTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "-- pico-api-go backup generated at %s\n", time.Now().UTC().Format(time.RFC3339))
+	bw.WriteString("SET FOREIGN_KEY_CHECKS=0" + statementTerminator)
+
+	for _, table := range tables {
+		if err := dumpTable(ctx, conn, bw, table); err != nil {
+			return err
+		}
+	}
+
+	bw.WriteString("SET FOREIGN_KEY_CHECKS=1" + statementTerminator)
+	return bw.Flush()
+}
+
+// Restore replays a SQL script produced by Dump. All statements run on a single
+// connection so session settings such as FOREIGN_KEY_CHECKS apply throughout.
+func (db *DB) Restore(ctx context.Context, r io.Reader) (int, error) {
+	if db.Dialect() != DialectMySQL {
+		return 0, ErrBackupUnsupported
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection for restore: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing restore connection: %v", err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	scanner.Split(splitStatements)
+
+	executed := 0
+	for scanner.Scan() {
+		stmt := strings.TrimSpace(stripComments(scanner.Text()))
+		if stmt == "" {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return executed, fmt.Errorf("failed to execute statement %d: %w", executed+1, err)
+		}
+		executed++
+	}
+	if err := scanner.Err(); err != nil {
+		return executed, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	return executed, nil
+}
+
+func listTables(ctx context.Context, conn *sql.Conn) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+func dumpTable(ctx context.Context, conn *sql.Conn, w *bufio.Writer, table string) error {
+	var name, createStmt string
+	if err := conn.QueryRowContext(ctx, "SHOW CREATE TABLE "+quoteIdentifier(table)).Scan(&name, &createStmt); err != nil {
+		return fmt.Errorf("failed to read definition of table %s: %w", table, err)
+	}
+
+	fmt.Fprintf(w, "\n-- Table %s\n", table)
+	w.WriteString("DROP TABLE IF EXISTS " + quoteIdentifier(table) + statementTerminator)
+	w.WriteString(createStmt + statementTerminator)
+
+	rows, err := conn.QueryContext(ctx, "SELECT * FROM "+quoteIdentifier(table))
+	if err != nil {
+		return fmt.Errorf("failed to read rows of table %s: %w", table, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to read columns of table %s: %w", table, err)
+	}
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdentifier(c)
+	}
+	insertPrefix := "INSERT INTO " + quoteIdentifier(table) + " (" + strings.Join(quoted, ", ") + ") VALUES\n"
+
+	values := make([]interface{}, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	batch := 0
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return fmt.Errorf("failed to scan row of table %s: %w", table, err)
+		}
+		if batch == 0 {
+			w.WriteString(insertPrefix)
+		} else {
+			w.WriteString(",\n")
+		}
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = sqlLiteral(v)
+		}
+		w.WriteString("(" + strings.Join(literals, ", ") + ")")
+		batch++
+		if batch == dumpBatchSize {
+			w.WriteString(statementTerminator)
+			batch = 0
+		}
+	}
+	if batch > 0 {
+		w.WriteString(statementTerminator)
+	}
+	return rows.Err()
+}
+
+// sqlLiteral renders a scanned column value as a MySQL literal.
+func sqlLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case bool:
+		if val {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return "'" + val.Format("2006-01-02 15:04:05") + "'"
+	case []byte:
+		return "'" + escapeString(string(val)) + "'"
+	case string:
+		return "'" + escapeString(val) + "'"
+	default:
+		return "'" + escapeString(fmt.Sprint(val)) + "'"
+	}
+}
+
+// escapeString escapes a string for use inside single quotes. Newlines are
+// escaped too, which keeps every row of a dump on one line.
+func escapeString(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+		"\x00", `\0`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\x1a", `\Z`,
+	)
+	return replacer.Replace(s)
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// splitStatements is a bufio.SplitFunc that yields one statement per token,
+// splitting on the semicolons outside quoted strings, quoted identifiers and
+// comments.
+func splitStatements(data []byte, atEOF bool) (int, []byte, error) {
+	if i := statementEnd(data); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), bytes.TrimSpace(data), nil
+	}
+	return 0, nil, nil
+}
+
+// statementEnd returns the index of the first semicolon of data that ends a
+// statement, or -1 when there is none yet
+func statementEnd(data []byte) int {
+	var quote byte // the open ', " or `, 0 outside quotes
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case quote != 0:
+			switch {
+			case c == '\\' && quote != '`':
+				i++ // the escaped character never closes the quote
+			case c == quote:
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '#' || (c == '-' && bytes.HasPrefix(data[i:], []byte("-- "))):
+			end := bytes.IndexByte(data[i:], '\n')
+			if end < 0 {
+				return -1
+			}
+			i += end
+		case c == '/' && bytes.HasPrefix(data[i:], []byte("/*")):
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return -1
+			}
+			i += end + 3
+		case c == ';':
+			return i
+		}
+	}
+	return -1
+}
+
+// stripComments removes full-line "--" comments from a statement.
+func stripComments(stmt string) string {
+	lines := strings.Split(stmt, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_Dump(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := &DB{DB: sqlDB}
+	defer db.Close()
+
+	mock.ExpectExec(`SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT table_name FROM information_schema.tables`).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("provinces"))
+	mock.ExpectQuery("SHOW CREATE TABLE `provinces`").
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+			AddRow("provinces", "CREATE TABLE `provinces` (\n  `id` varchar(2) NOT NULL\n)"))
+	mock.ExpectQuery("SELECT \\* FROM `provinces`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "updated_at"}).
+			AddRow("72", []byte("Sulawesi Tengah"), time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)).
+			AddRow("11", []byte("O'Aceh\nline"), nil))
+	mock.ExpectExec(`ROLLBACK`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	var buf bytes.Buffer
+	require.NoError(t, db.Dump(context.Background(), &buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "SET FOREIGN_KEY_CHECKS=0;\n")
+	assert.Contains(t, out, "DROP TABLE IF EXISTS `provinces`;\n")
+	assert.Contains(t, out, "INSERT INTO `provinces` (`id`, `name`, `updated_at`) VALUES\n")
+	assert.Contains(t, out, "('72', 'Sulawesi Tengah', '2020-03-01 00:00:00'),\n")
+	assert.Contains(t, out, `('11', 'O\'Aceh\nline', NULL);`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_Restore(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := &DB{DB: sqlDB}
+	defer db.Close()
+
+	script := strings.Join([]string{
+		"-- pico-api-go backup",
+		"SET FOREIGN_KEY_CHECKS=0;",
+		"",
+		"-- Table provinces",
+		"DROP TABLE IF EXISTS `provinces`;",
+		"INSERT INTO `provinces` (`id`) VALUES\n('72');",
+		"SET FOREIGN_KEY_CHECKS=1;",
+	}, "\n")
+
+	mock.ExpectExec(`SET FOREIGN_KEY_CHECKS=0`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DROP TABLE IF EXISTS `provinces`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `provinces`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SET FOREIGN_KEY_CHECKS=1`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	executed, err := db.Restore(context.Background(), strings.NewReader(script))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, executed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_Restore_SemicolonsInValues(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db := &DB{DB: sqlDB}
+	defer db.Close()
+
+	insert := "INSERT INTO `announcements` (`id`, `message`) VALUES\n(1, 'closed;\nreopened'),\n(2, 'it''s; \\'done\\';')"
+	script := "-- Table announcements; with a semicolon\n" +
+		"CREATE TABLE `a;b` (`id` int COMMENT 'x;\ny')" + statementTerminator +
+		insert + statementTerminator +
+		"/* trailing; comment */ SELECT 1"
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE `a;b` (`id` int COMMENT 'x;\ny')")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(insert)).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT 1")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	executed, err := db.Restore(context.Background(), strings.NewReader(script))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, executed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatementEnd(t *testing.T) {
+	assert.Equal(t, 8, statementEnd([]byte("SELECT 1; SELECT 2")))
+	assert.Equal(t, 13, statementEnd([]byte("SELECT '\\';x';")))
+	assert.Equal(t, 19, statementEnd([]byte("SELECT `a;b` -- c;\n;")))
+	assert.Equal(t, -1, statementEnd([]byte("SELECT 'open;")))
+	assert.Equal(t, -1, statementEnd([]byte("SELECT 1 /* open;")))
+}
+
+func TestSQLLiteral(t *testing.T) {
+	assert.Equal(t, "NULL", sqlLiteral(nil))
+	assert.Equal(t, "42", sqlLiteral(int64(42)))
+	assert.Equal(t, "1.25", sqlLiteral(1.25))
+	assert.Equal(t, `'a\\b'`, sqlLiteral([]byte(`a\b`)))
+}
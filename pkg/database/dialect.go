@@ -0,0 +1,152 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the SQL flavour of a database engine. Repositories write their
+// statements for MySQL, with ? placeholders and backtick-quoted identifiers;
+// DB rewrites them for the engine it is connected to, and the methods below
+// cover the few clauses that have no common spelling.
+type Dialect string
+
+const (
+	DialectMySQL    Dialect = "mysql"
+	DialectPostgres Dialect = "postgres"
+)
+
+// ParseDialect returns the dialect of a DB_DRIVER value
+func ParseDialect(driver string) (Dialect, error) {
+	switch Dialect(strings.ToLower(strings.TrimSpace(driver))) {
+	case "", DialectMySQL:
+		return DialectMySQL, nil
+	case DialectPostgres, "postgresql", "pgx":
+		return DialectPostgres, nil
+	default:
+		return "", fmt.Errorf("unsupported database driver %q, use %s or %s", driver, DialectMySQL, DialectPostgres)
+	}
+}
+
+// system is the dialect's name in tracing attributes
+func (d Dialect) system() string {
+	if d == DialectPostgres {
+		return "postgresql"
+	}
+	return "mysql"
+}
+
+// Rebind rewrites a MySQL statement for the dialect. For PostgreSQL the ?
+// placeholders become $1, $2, ... and backtick-quoted identifiers are double
+// quoted; string literals are left alone. MySQL statements are returned as is.
+func (d Dialect) Rebind(query string) string {
+	if d != DialectPostgres || !strings.ContainsAny(query, "?`") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; c {
+		case '\'':
+			end := closingQuote(query, i)
+			b.WriteString(query[i:end])
+			i = end - 1
+		case '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			name := query[i+1 : i+1+end]
+			b.WriteString(`"` + strings.ReplaceAll(name, `"`, `""`) + `"`)
+			i += end + 1
+		case '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// closingQuote returns the index after the string literal starting at
+// query[start], honouring doubled and backslash-escaped quotes
+func closingQuote(query string, start int) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case '\'':
+			if i+1 < len(query) && query[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// CurrentSchema is the SQL function returning the schema whose tables the
+// information_schema queries list
+func (d Dialect) CurrentSchema() string {
+	if d == DialectPostgres {
+		return "current_schema()"
+	}
+	return "DATABASE()"
+}
+
+// Upsert returns the clause ending an INSERT that updates columns of the row
+// already stored under the unique key instead of failing
+func (d Dialect) Upsert(key []string, columns ...string) string {
+	assignments := make([]string, len(columns))
+	if d == DialectPostgres {
+		for i, c := range columns {
+			assignments[i] = c + " = EXCLUDED." + c
+		}
+		return "ON CONFLICT (" + strings.Join(key, ", ") + ") DO UPDATE SET " + strings.Join(assignments, ", ")
+	}
+	for i, c := range columns {
+		assignments[i] = c + " = VALUES(" + c + ")"
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
+
+// ISOWeek labels a date expression with its ISO week, e.g. 2021-W05
+func (d Dialect) ISOWeek(expr string) string {
+	if d == DialectPostgres {
+		return "to_char(" + expr + `, 'IYYY-"W"IW')`
+	}
+	return "DATE_FORMAT(" + expr + ", '%x-W%v')"
+}
+
+// YearMonth labels a date expression with its month, e.g. 2021-02
+func (d Dialect) YearMonth(expr string) string {
+	if d == DialectPostgres {
+		return "to_char(" + expr + ", 'YYYY-MM')"
+	}
+	return "DATE_FORMAT(" + expr + ", '%Y-%m')"
+}
+
+// ForUpdateOf returns the locking clause of a SELECT that locks the rows of
+// table, the alias of the outer side of its joins. PostgreSQL cannot lock the
+// nullable side of an outer join; MySQL locks every row read either way.
+func (d Dialect) ForUpdateOf(table string) string {
+	if d == DialectPostgres {
+		return " FOR UPDATE OF " + table
+	}
+	return " FOR UPDATE"
+}
+
+// UnixTimestamp is the SQL expression of the server's current time in whole
+// seconds since the Unix epoch, free of session time zones
+func (d Dialect) UnixTimestamp() string {
+	if d == DialectPostgres {
+		return "CAST(EXTRACT(EPOCH FROM now()) AS BIGINT)"
+	}
+	return "UNIX_TIMESTAMP()"
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDialect(t *testing.T) {
+	for driver, want := range map[string]Dialect{"": DialectMySQL, "mysql": DialectMySQL, "postgres": DialectPostgres, "PostgreSQL": DialectPostgres} {
+		got, err := ParseDialect(driver)
+		require.NoError(t, err, driver)
+		assert.Equal(t, want, got, driver)
+	}
+
+	_, err := ParseDialect("sqlite")
+	assert.Error(t, err)
+}
+
+func TestDialect_Rebind(t *testing.T) {
+	query := "SELECT `day`, name FROM `national_cases` WHERE day > ? AND name <> 'who?' AND note = 'it''s ?' LIMIT ?"
+
+	assert.Equal(t, query, DialectMySQL.Rebind(query))
+	assert.Equal(t,
+		`SELECT "day", name FROM "national_cases" WHERE day > $1 AND name <> 'who?' AND note = 'it''s ?' LIMIT $2`,
+		DialectPostgres.Rebind(query))
+}
+
+func TestDialect_Upsert(t *testing.T) {
+	assert.Equal(t, "ON DUPLICATE KEY UPDATE last_id = VALUES(last_id)", DialectMySQL.Upsert([]string{"name"}, "last_id"))
+	assert.Equal(t, "ON CONFLICT (name) DO UPDATE SET last_id = EXCLUDED.last_id", DialectPostgres.Upsert([]string{"name"}, "last_id"))
+}
+
+func TestDB_Postgres_RewritesStatements(t *testing.T) {
+	sqlDB, mock := newMockDB(t)
+	db := New(sqlDB, DialectPostgres)
+
+	mock.ExpectQuery(`SELECT day FROM national_cases WHERE day = \$1`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow(7))
+	mock.ExpectQuery(`INSERT INTO share_links \(code\) VALUES \(\$1\) RETURNING id`).WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "province_cases" SET rt = \$1 WHERE id = \$2`).WithArgs(1.1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var day int
+	require.NoError(t, db.QueryRowContext(context.Background(), "SELECT day FROM national_cases WHERE day = ?", 7).Scan(&day))
+	id, err := db.InsertContext(context.Background(), "INSERT INTO share_links (code) VALUES (?)", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), id)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(context.Background(), "UPDATE `province_cases` SET rt = ? WHERE id = ?", 1.1, 3)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_MySQL_InsertContext(t *testing.T) {
+	sqlDB, mock := newMockDB(t)
+	db := &DB{DB: sqlDB}
+	mock.ExpectExec(`INSERT INTO share_links \(code\) VALUES \(\?\)`).WithArgs("abc").
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	id, err := db.InsertContext(context.Background(), "INSERT INTO share_links (code) VALUES (?)", "abc")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
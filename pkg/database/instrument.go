@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+)
+
+// maxLoggedQueryLength truncates the SQL text written to the slow query log
+const maxLoggedQueryLength = 500
+
+// SetSlowQueryThreshold logs every Query, QueryRow and Exec call, with or
+// without a context, slower than d at WARN level. Zero disables the slow
+// query log.
+func (db *DB) SetSlowQueryThreshold(d time.Duration) {
+	db.slowQueryThreshold = d
+}
+
+// Query times sql.DB.Query without a context
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext times sql.DB.QueryContext, running read-only queries on a read
+// replica when there is one. Statements of every method are rewritten for the
+// database's dialect first. The duration covers execution up to the first
+// result, not iteration over the returned rows.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query = db.Dialect().Rebind(query)
+	ctx, span := db.startQuerySpan(ctx, query)
+	start := time.Now()
+	rows, err := db.queryRead(ctx, query, args)
+	db.observeQuery(query, len(args), time.Since(start), -1, err)
+	endQuerySpan(span, err)
+	return rows, err
+}
+
+// QueryRow times sql.DB.QueryRow without a context
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext times sql.DB.QueryRowContext, running read-only queries on
+// a read replica when there is one
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	query = db.Dialect().Rebind(query)
+	ctx, span := db.startQuerySpan(ctx, query)
+	start := time.Now()
+	row := db.queryRowRead(ctx, query, args)
+	db.observeQuery(query, len(args), time.Since(start), -1, row.Err())
+	endQuerySpan(span, row.Err())
+	return row
+}
+
+// Exec times sql.DB.Exec without a context
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext times sql.DB.ExecContext and logs the affected row count of
+// slow statements
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query = db.Dialect().Rebind(query)
+	ctx, span := db.startQuerySpan(ctx, query)
+	start := time.Now()
+	res, err := db.DB.ExecContext(ctx, query, args...)
+	elapsed := time.Since(start)
+	endQuerySpan(span, err)
+
+	affected := int64(-1)
+	if err == nil && db.slowQueryThreshold > 0 && elapsed >= db.slowQueryThreshold {
+		if n, rowsErr := res.RowsAffected(); rowsErr == nil {
+			affected = n
+		}
+	}
+	db.observeQuery(query, len(args), elapsed, affected, err)
+	return res, err
+}
+
+func (db *DB) observeQuery(query string, argCount int, elapsed time.Duration, rowsAffected int64, err error) {
+	if db.slowQueryThreshold <= 0 || elapsed < db.slowQueryThreshold {
+		return
+	}
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	rows := ""
+	if rowsAffected >= 0 {
+		rows = " rows_affected=" + strconv.FormatInt(rowsAffected, 10)
+	}
+	log.Printf("WARN slow query: duration=%v threshold=%v args=%d status=%s%s sql=%q",
+		elapsed.Round(time.Microsecond), db.slowQueryThreshold, argCount, status, rows, compactSQL(query))
+}
+
+// startQuerySpan records a statement as a client span named after its SQL
+// verb. Statements outside a traced request, such as background jobs calling
+// Query without a context, are not recorded to avoid single-span traces.
+func (db *DB) startQuerySpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	if _, ok := tracing.FromContext(ctx); !ok {
+		return ctx, nil
+	}
+	compact := compactSQL(query)
+	name := "SQL"
+	if verb, _, _ := strings.Cut(compact, " "); verb != "" {
+		name = strings.ToUpper(verb)
+	}
+	ctx, span := tracing.StartSpan(ctx, name, tracing.SpanKindClient)
+	span.SetAttribute("db.system", db.Dialect().system())
+	span.SetAttribute("db.query.text", compact)
+	return ctx, span
+}
+
+func endQuerySpan(span *tracing.Span, err error) {
+	if err != sql.ErrNoRows {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// compactSQL collapses whitespace so multi-line queries fit on one log line
+func compactSQL(query string) string {
+	compact := strings.Join(strings.Fields(query), " ")
+	if len(compact) > maxLoggedQueryLength {
+		compact = compact[:maxLoggedQueryLength] + "..."
+	}
+	return compact
+}
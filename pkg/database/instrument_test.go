@@ -0,0 +1,124 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_SlowQueryLog(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	db := &DB{DB: sqlDB}
+	db.SetSlowQueryThreshold(time.Millisecond)
+
+	mock.ExpectExec("UPDATE national_cases").WillDelayFor(5 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	_, err = db.Exec("UPDATE national_cases\n\t\tSET rt = ? WHERE day = ?", 1.1, 4)
+	require.NoError(t, err)
+	rows, err := db.Query("SELECT 1")
+	require.NoError(t, err)
+	rows.Close()
+
+	out := buf.String()
+	assert.Contains(t, out, "WARN slow query")
+	assert.Contains(t, out, "rows_affected=3")
+	assert.Contains(t, out, `sql="UPDATE national_cases SET rt = ? WHERE day = ?"`)
+	assert.NotContains(t, out, "SELECT 1")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_SlowQueryLogDisabled(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	db := &DB{DB: sqlDB}
+	mock.ExpectExec("DELETE").WillDelayFor(2 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = db.Exec("DELETE FROM revisions")
+	require.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestDB_QueryContextCancelled(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := &DB{DB: sqlDB}
+	mock.ExpectQuery("SELECT").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = db.QueryContext(ctx, "SELECT 1")
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+type spanCollector struct {
+	spans []tracing.SpanData
+}
+
+func (c *spanCollector) Export(ctx context.Context, spans []tracing.SpanData) error {
+	c.spans = append(c.spans, spans...)
+	return nil
+}
+
+func TestDB_QuerySpans(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	collector := &spanCollector{}
+	recorder := tracing.NewRecorder(collector, tracing.RecorderOptions{FlushInterval: time.Hour})
+	tracing.SetRecorder(recorder)
+	defer tracing.SetRecorder(nil)
+
+	db := &DB{DB: sqlDB}
+	mock.ExpectQuery("(?i)select name").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Sulawesi Tengah"))
+	mock.ExpectExec("DELETE FROM").WillReturnError(errors.New("locked"))
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	ctx := tracing.NewContext(context.Background(), tracing.New())
+	var name string
+	require.NoError(t, db.QueryRowContext(ctx, "select name\n\t FROM provinces WHERE id = ?", "72").Scan(&name))
+	_, err = db.ExecContext(ctx, "DELETE FROM share_links")
+	require.Error(t, err)
+	// Untraced queries are not recorded
+	rows, err := db.Query("SELECT 1")
+	require.NoError(t, err)
+	rows.Close()
+
+	require.NoError(t, recorder.Shutdown(context.Background()))
+	require.Len(t, collector.spans, 2)
+	assert.Equal(t, "SELECT", collector.spans[0].Name)
+	assert.Equal(t, tracing.SpanKindClient, collector.spans[0].Kind)
+	assert.Equal(t, "select name FROM provinces WHERE id = ?", collector.spans[0].Attributes["db.query.text"])
+	assert.Empty(t, collector.spans[0].Error)
+	assert.Equal(t, "DELETE", collector.spans[1].Name)
+	assert.Equal(t, "locked", collector.spans[1].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,17 +3,32 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/banua-coder/pico-api-go/internal/config"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
+// erDupEntry is MySQL's error number for a unique key violation
+const erDupEntry = 1062
+
 type DB struct {
 	*sql.DB
+
+	// dialect is the engine's SQL flavour; the zero value is MySQL
+	dialect Dialect
+
+	// slowQueryThreshold enables the slow query log when positive
+	slowQueryThreshold time.Duration
+	// replicas serve the read-only queries when configured; nil sends
+	// everything to the primary
+	replicas *replicaSet
 }
 
 type ConnectionConfig struct {
@@ -25,8 +40,42 @@ type ConnectionConfig struct {
 	RetryDelay      time.Duration
 }
 
+// New wraps an open connection pool of the dialect's engine
+func New(db *sql.DB, dialect Dialect) *DB {
+	return &DB{DB: db, dialect: dialect}
+}
+
+// Dialect returns the SQL flavour of the connected engine
+func (db *DB) Dialect() Dialect {
+	if db.dialect == "" {
+		return DialectMySQL
+	}
+	return db.dialect
+}
+
+// NewConnection connects to the engine selected by cfg.Driver
+func NewConnection(cfg *config.DatabaseConfig) (*DB, error) {
+	dialect, err := ParseDialect(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	if dialect == DialectPostgres {
+		return NewPostgresConnection(cfg)
+	}
+	return NewMySQLConnection(cfg)
+}
+
 func NewMySQLConnection(cfg *config.DatabaseConfig) (*DB, error) {
-	connCfg := ConnectionConfig{
+	return NewMySQLConnectionWithConfig(cfg, connectionConfig(cfg))
+}
+
+func NewMySQLConnectionWithConfig(cfg *config.DatabaseConfig, connCfg ConnectionConfig) (*DB, error) {
+	return open(DialectMySQL, cfg, connCfg)
+}
+
+// connectionConfig returns the pool settings of cfg
+func connectionConfig(cfg *config.DatabaseConfig) ConnectionConfig {
+	return ConnectionConfig{
 		MaxOpenConns:    cfg.MaxOpenConns,
 		MaxIdleConns:    cfg.MaxIdleConns,
 		ConnMaxLifetime: cfg.ConnMaxLifetime,
@@ -34,19 +83,12 @@ func NewMySQLConnection(cfg *config.DatabaseConfig) (*DB, error) {
 		RetryAttempts:   3,
 		RetryDelay:      1 * time.Second,
 	}
-
-	return NewMySQLConnectionWithConfig(cfg, connCfg)
 }
 
-func NewMySQLConnectionWithConfig(cfg *config.DatabaseConfig, connCfg ConnectionConfig) (*DB, error) {
-	// Enhanced DSN with better timeout and connection parameters for shared hosting
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=10s&readTimeout=10s&writeTimeout=10s&maxAllowedPacket=0&tls=false&allowOldPasswords=1&clientFoundRows=false&columnsWithAlias=false&interpolateParams=true",
-		cfg.Username,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.DBName,
-	)
+// open connects to the primary of the dialect's engine, retrying with
+// exponential backoff, and to its read replicas
+func open(dialect Dialect, cfg *config.DatabaseConfig, connCfg ConnectionConfig) (*DB, error) {
+	dsn := dialect.dsn(cfg, net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)))
 
 	var db *sql.DB
 	var err error
@@ -55,7 +97,7 @@ func NewMySQLConnectionWithConfig(cfg *config.DatabaseConfig, connCfg Connection
 	for attempt := 1; attempt <= connCfg.RetryAttempts; attempt++ {
 		log.Printf("Attempting to connect to database (attempt %d/%d)", attempt, connCfg.RetryAttempts)
 
-		db, err = sql.Open("mysql", dsn)
+		db, err = sql.Open(string(dialect), dsn)
 		if err != nil {
 			if attempt == connCfg.RetryAttempts {
 				return nil, fmt.Errorf("failed to open database connection after %d attempts: %w", connCfg.RetryAttempts, err)
@@ -67,11 +109,7 @@ func NewMySQLConnectionWithConfig(cfg *config.DatabaseConfig, connCfg Connection
 			continue
 		}
 
-		// Configure connection pool
-		db.SetMaxOpenConns(connCfg.MaxOpenConns)
-		db.SetMaxIdleConns(connCfg.MaxIdleConns)
-		db.SetConnMaxLifetime(connCfg.ConnMaxLifetime)
-		db.SetConnMaxIdleTime(connCfg.ConnMaxIdleTime)
+		configurePool(db, connCfg)
 
 		// Test the connection
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -95,7 +133,72 @@ func NewMySQLConnectionWithConfig(cfg *config.DatabaseConfig, connCfg Connection
 		break
 	}
 
-	return &DB{db}, nil
+	primary := New(db, dialect)
+	if len(cfg.Replicas) > 0 {
+		primary.replicas = openReplicas(dialect, cfg, connCfg)
+	}
+	return primary, nil
+}
+
+// dsn returns the DSN of the configured database on addr
+func (d Dialect) dsn(cfg *config.DatabaseConfig, addr string) string {
+	if d == DialectPostgres {
+		return postgresDSN(cfg, addr)
+	}
+	return mysqlDSN(cfg, addr)
+}
+
+// mysqlDSN returns the DSN of the configured MySQL database on addr
+func mysqlDSN(cfg *config.DatabaseConfig, addr string) string {
+	// Enhanced DSN with better timeout and connection parameters for shared hosting
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=10s&readTimeout=10s&writeTimeout=10s&maxAllowedPacket=0&tls=false&allowOldPasswords=1&clientFoundRows=false&columnsWithAlias=false&interpolateParams=true",
+		cfg.Username,
+		cfg.Password,
+		addr,
+		cfg.DBName,
+	)
+}
+
+func configurePool(db *sql.DB, connCfg ConnectionConfig) {
+	db.SetMaxOpenConns(connCfg.MaxOpenConns)
+	db.SetMaxIdleConns(connCfg.MaxIdleConns)
+	db.SetConnMaxLifetime(connCfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(connCfg.ConnMaxIdleTime)
+}
+
+// openReplicas opens a pool per read replica with the primary's credentials
+// and pool settings. Unlike the primary, an unreachable replica does not fail
+// startup: it is skipped until its retry interval passes.
+func openReplicas(dialect Dialect, cfg *config.DatabaseConfig, connCfg ConnectionConfig) *replicaSet {
+	set := newReplicaSet(nil, cfg.ReplicaRetryInterval)
+	for _, addr := range cfg.Replicas {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, strconv.Itoa(cfg.Port))
+		}
+		db, err := sql.Open(string(dialect), dialect.dsn(cfg, addr))
+		if err != nil {
+			log.Printf("Skipping read replica %s: %v", addr, err)
+			continue
+		}
+		configurePool(db, connCfg)
+		r := &replica{name: addr, db: db}
+		set.replicas = append(set.replicas, r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = db.PingContext(ctx)
+		cancel()
+		if err != nil {
+			set.markDown(r, err)
+			continue
+		}
+		log.Printf("Read replica %s connected", addr)
+	}
+	return set
+}
+
+// Close closes the primary and the read replicas
+func (db *DB) Close() error {
+	return errors.Join(db.DB.Close(), db.replicas.close())
 }
 
 func DefaultConnectionConfig() ConnectionConfig {
@@ -127,7 +230,25 @@ func (db *DB) HealthCheck() error {
 	return nil
 }
 
+// ServerTime returns the database server's clock
+func (db *DB) ServerTime(ctx context.Context) (time.Time, error) {
+	var unix int64
+	if err := db.QueryRowContext(ctx, "SELECT "+db.Dialect().UnixTimestamp()).Scan(&unix); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read database time: %w", err)
+	}
+	return time.Unix(unix, 0).UTC(), nil
+}
+
 // GetConnectionStats returns database connection statistics
 func (db *DB) GetConnectionStats() sql.DBStats {
 	return db.Stats()
 }
+
+// IsDuplicateKey reports whether err is a unique key violation
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == erDupEntry
+	}
+	return isPostgresUniqueViolation(err)
+}
@@ -1,11 +1,18 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultConnectionConfig(t *testing.T) {
@@ -56,3 +63,52 @@ func TestNewMySQLConnection_ConnectionFailed(t *testing.T) {
 	_, err := NewMySQLConnection(cfg)
 	assert.Error(t, err)
 }
+
+func TestPostgresDSN(t *testing.T) {
+	cfg := &config.DatabaseConfig{Username: "pico", Password: "p@ss word", DBName: "pico_db", SSLMode: "disable"}
+
+	assert.Equal(t, "postgres://pico:p%40ss%20word@db:5432/pico_db?connect_timeout=10&sslmode=disable&timezone=UTC",
+		postgresDSN(cfg, "db:5432"))
+}
+
+func TestNewConnection_UnknownDriver(t *testing.T) {
+	_, err := NewConnection(&config.DatabaseConfig{Driver: "sqlite"})
+
+	assert.ErrorContains(t, err, "unsupported database driver")
+}
+
+func TestIsDuplicateKey(t *testing.T) {
+	dup := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '72-8' for key 'uq_province_cases_province_day'"}
+
+	assert.True(t, IsDuplicateKey(dup))
+	assert.True(t, IsDuplicateKey(fmt.Errorf("failed to insert: %w", dup)))
+	assert.False(t, IsDuplicateKey(&mysql.MySQLError{Number: 1452}))
+	assert.True(t, IsDuplicateKey(&pq.Error{Code: "23505"}))
+	assert.False(t, IsDuplicateKey(&pq.Error{Code: "23503"}))
+	assert.False(t, IsDuplicateKey(errors.New("duplicate")))
+	assert.False(t, IsDuplicateKey(nil))
+}
+
+func TestDB_ServerTime(t *testing.T) {
+	for _, tt := range []struct {
+		dialect Dialect
+		query   string
+	}{
+		{DialectMySQL, `SELECT UNIX_TIMESTAMP\(\)`},
+		{DialectPostgres, `SELECT CAST\(EXTRACT\(EPOCH FROM now\(\)\) AS BIGINT\)`},
+	} {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db := New(sqlDB, tt.dialect)
+			defer db.Close()
+
+			mock.ExpectQuery(tt.query).WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow(int64(1760601600)))
+
+			now, err := db.ServerTime(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, time.Date(2025, 10, 16, 8, 0, 0, 0, time.UTC), now)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/lib/pq"
+)
+
+// pgUniqueViolation is PostgreSQL's SQLSTATE for a unique key violation
+const pgUniqueViolation = "23505"
+
+// NewPostgresConnection connects to the configured PostgreSQL database
+func NewPostgresConnection(cfg *config.DatabaseConfig) (*DB, error) {
+	return open(DialectPostgres, cfg, connectionConfig(cfg))
+}
+
+// postgresDSN returns the URL of the configured PostgreSQL database on addr.
+// Sessions run in UTC, the zone of the times the repositories store.
+func postgresDSN(cfg *config.DatabaseConfig, addr string) string {
+	query := url.Values{}
+	if cfg.SSLMode != "" {
+		query.Set("sslmode", cfg.SSLMode)
+	}
+	query.Set("connect_timeout", "10")
+	query.Set("timezone", "UTC")
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     addr,
+		Path:     "/" + cfg.DBName,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+func isPostgresUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// defaultReplicaRetryInterval is how long a failed replica is skipped when the
+// configuration does not say
+const defaultReplicaRetryInterval = 30 * time.Second
+
+// replica is a read replica's connection pool
+type replica struct {
+	name string
+	db   *sql.DB
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+func (r *replica) available(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !now.Before(r.downUntil)
+}
+
+// replicaSet spreads reads round-robin over the replicas. A replica whose
+// connection fails is skipped for retryInterval; while every replica is
+// skipped, reads go to the primary.
+type replicaSet struct {
+	replicas      []*replica
+	next          atomic.Uint64
+	retryInterval time.Duration
+}
+
+func newReplicaSet(replicas []*replica, retryInterval time.Duration) *replicaSet {
+	if retryInterval <= 0 {
+		retryInterval = defaultReplicaRetryInterval
+	}
+	return &replicaSet{replicas: replicas, retryInterval: retryInterval}
+}
+
+// pick returns the next available replica, nil when there is none
+func (s *replicaSet) pick() *replica {
+	if s == nil || len(s.replicas) == 0 {
+		return nil
+	}
+	now := time.Now()
+	start := s.next.Add(1) - 1
+	for i := range s.replicas {
+		r := s.replicas[(start+uint64(i))%uint64(len(s.replicas))]
+		if r.available(now) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *replicaSet) markDown(r *replica, err error) {
+	r.mu.Lock()
+	r.downUntil = time.Now().Add(s.retryInterval)
+	r.mu.Unlock()
+	log.Printf("Read replica %s failed, sending its reads to the primary for %v: %v", r.name, s.retryInterval, err)
+}
+
+func (s *replicaSet) close() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	for _, r := range s.replicas {
+		if err := r.db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// primaryKey is the context key set by WithPrimary
+type primaryKey struct{}
+
+// WithPrimary returns a context whose queries run on the primary even when
+// they could go to a replica. Replicas lag behind the primary, so reads that
+// must see the latest writes use it: cursors, work queues, and rows read back
+// right after they were written.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// replicaRead reports whether a statement may run on a replica
+func replicaRead(ctx context.Context, query string) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return !primary && readOnly(query)
+}
+
+// queryRead runs a read-only query on a replica, falling back to the primary
+// when the replica cannot be reached. Other statements, and every statement
+// of a WithPrimary context, run on the primary.
+func (db *DB) queryRead(ctx context.Context, query string, args []interface{}) (*sql.Rows, error) {
+	if replicaRead(ctx, query) {
+		if r := db.replicas.pick(); r != nil {
+			rows, err := r.db.QueryContext(ctx, query, args...)
+			if !isConnectionError(err) {
+				return rows, err
+			}
+			db.replicas.markDown(r, err)
+		}
+	}
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+// queryRowRead is queryRead for a single row
+func (db *DB) queryRowRead(ctx context.Context, query string, args []interface{}) *sql.Row {
+	if replicaRead(ctx, query) {
+		if r := db.replicas.pick(); r != nil {
+			row := r.db.QueryRowContext(ctx, query, args...)
+			if !isConnectionError(row.Err()) {
+				return row
+			}
+			db.replicas.markDown(r, row.Err())
+		}
+	}
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// readOnly reports whether a statement may run on a replica: a SELECT that
+// takes no locks. Locking reads belong to a transaction on the primary anyway,
+// and GET_LOCK must run where the lock holders are.
+func readOnly(query string) bool {
+	upper := strings.ToUpper(strings.Join(strings.Fields(query), " "))
+	if !strings.HasPrefix(upper, "SELECT ") {
+		return false
+	}
+	for _, lock := range []string{" FOR UPDATE", " FOR SHARE", " LOCK IN SHARE MODE", "GET_LOCK(", "RELEASE_LOCK("} {
+		if strings.Contains(upper, lock) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConnectionError reports whether err means the server could not be
+// reached, as opposed to the statement failing on it
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.As(err, &netErr)
+}
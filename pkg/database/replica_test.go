@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+func TestDB_ReadsGoRoundRobinToReplicas(t *testing.T) {
+	primaryDB, primary := newMockDB(t)
+	replicaDB1, replica1 := newMockDB(t)
+	replicaDB2, replica2 := newMockDB(t)
+	db := &DB{DB: primaryDB, replicas: newReplicaSet([]*replica{
+		{name: "replica-1", db: replicaDB1},
+		{name: "replica-2", db: replicaDB2},
+	}, time.Minute)}
+
+	replica1.ExpectQuery("SELECT day FROM national_cases").WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow(1))
+	replica2.ExpectQuery("SELECT day FROM national_cases").WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow(1))
+	replica1.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(3))
+	primary.ExpectExec("UPDATE national_cases").WillReturnResult(sqlmock.NewResult(0, 1))
+	primary.ExpectQuery("SELECT GET_LOCK").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+
+	for i := 0; i < 2; i++ {
+		rows, err := db.QueryContext(context.Background(), "SELECT day FROM national_cases")
+		require.NoError(t, err)
+		rows.Close()
+	}
+	var n int
+	require.NoError(t, db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM national_cases").Scan(&n))
+	_, err := db.ExecContext(context.Background(), "UPDATE national_cases SET rt = ?", 1.1)
+	require.NoError(t, err)
+	var locked int
+	require.NoError(t, db.QueryRow("SELECT GET_LOCK('scheduler', 0)").Scan(&locked))
+
+	for _, m := range []sqlmock.Sqlmock{primary, replica1, replica2} {
+		assert.NoError(t, m.ExpectationsWereMet())
+	}
+}
+
+func TestDB_ReadFailsOverToPrimary(t *testing.T) {
+	primaryDB, primary := newMockDB(t)
+	replicaDB, replicaMock := newMockDB(t)
+	db := &DB{DB: primaryDB, replicas: newReplicaSet([]*replica{{name: "replica-1", db: replicaDB}}, time.Minute)}
+
+	replicaMock.ExpectQuery("SELECT day").WillReturnError(&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")})
+	primary.ExpectQuery("SELECT day").WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow(1))
+	// The replica is skipped until its retry interval passes
+	primary.ExpectQuery("SELECT day").WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow(1))
+
+	for i := 0; i < 2; i++ {
+		var day int
+		require.NoError(t, db.QueryRowContext(context.Background(), "SELECT day FROM national_cases").Scan(&day))
+	}
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestDB_WithPrimaryReadsFromPrimary(t *testing.T) {
+	primaryDB, primary := newMockDB(t)
+	replicaDB, replicaMock := newMockDB(t)
+	db := &DB{DB: primaryDB, replicas: newReplicaSet([]*replica{{name: "replica-1", db: replicaDB}}, time.Minute)}
+
+	primary.ExpectQuery("SELECT last_id FROM webhook_cursors").WillReturnRows(sqlmock.NewRows([]string{"last_id"}).AddRow(41))
+	primary.ExpectQuery("SELECT id FROM webhook_deliveries").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	replicaMock.ExpectQuery("SELECT day FROM national_cases").WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow(1))
+
+	ctx := WithPrimary(context.Background())
+	var lastID int64
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT last_id FROM webhook_cursors WHERE name = ?", "national").Scan(&lastID))
+	rows, err := db.QueryContext(ctx, "SELECT id FROM webhook_deliveries")
+	require.NoError(t, err)
+	rows.Close()
+	// Other contexts still read from the replica
+	rows, err = db.QueryContext(context.Background(), "SELECT day FROM national_cases")
+	require.NoError(t, err)
+	rows.Close()
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestDB_ReplicaQueryErrorIsReturned(t *testing.T) {
+	primaryDB, primary := newMockDB(t)
+	replicaDB, replicaMock := newMockDB(t)
+	db := &DB{DB: primaryDB, replicas: newReplicaSet([]*replica{{name: "replica-1", db: replicaDB}}, time.Minute)}
+
+	replicaMock.ExpectQuery("SELECT nope").WillReturnError(sql.ErrNoRows)
+
+	_, err := db.QueryContext(context.Background(), "SELECT nope FROM national_cases")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestReadOnly(t *testing.T) {
+	assert.True(t, readOnly("SELECT * FROM national_cases"))
+	assert.True(t, readOnly("\n\t\tselect id\n\t\tFROM provinces"))
+	assert.False(t, readOnly("SELECT id FROM national_cases WHERE day = ? FOR UPDATE"))
+	assert.False(t, readOnly("SELECT RELEASE_LOCK('scheduler')"))
+	assert.False(t, readOnly("INSERT INTO sync_log (source) VALUES (?)"))
+	assert.False(t, readOnly("SHOW TABLES"))
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tx is a transaction on the primary whose statements are rewritten for the
+// database's dialect like those of DB
+type Tx struct {
+	*sql.Tx
+	dialect Dialect
+}
+
+// BeginTx starts a transaction on the primary
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, dialect: db.Dialect()}, nil
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, tx.dialect.Rebind(query), args...)
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, tx.dialect.Rebind(query), args...)
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, tx.dialect.Rebind(query), args...)
+}
+
+// InsertContext runs an INSERT of one row and returns its id, see
+// DB.InsertContext
+func (tx *Tx) InsertContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return insertReturningID(ctx, tx, tx.dialect, query, args)
+}
+
+// InsertContext runs an INSERT of one row and returns its generated id
+// column. PostgreSQL has no LastInsertId, so the statement asks for the id
+// with RETURNING there.
+func (db *DB) InsertContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return insertReturningID(ctx, db, db.Dialect(), query, args)
+}
+
+type execQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func insertReturningID(ctx context.Context, exec execQueryer, dialect Dialect, query string, args []interface{}) (int64, error) {
+	var id int64
+	if dialect == DialectPostgres {
+		if err := exec.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	if id, err = res.LastInsertId(); err != nil {
+		return 0, fmt.Errorf("failed to read inserted id: %w", err)
+	}
+	return id, nil
+}
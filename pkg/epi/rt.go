@@ -0,0 +1,189 @@
+// Package epi estimates the time-varying reproduction number Rt from daily
+// case counts with the method of Cori et al. (2013), as implemented by the
+// EpiEstim R package.
+package epi
+
+import (
+	"errors"
+	"math"
+)
+
+// Prior of Rt: a gamma distribution with mean 5 and standard deviation 5, the
+// EpiEstim default, which the case counts of any real epidemic outweigh
+const (
+	priorShape = 1.0
+	priorScale = 5.0
+)
+
+// maxSerialIntervalDays truncates the serial interval distribution
+const maxSerialIntervalDays = 60
+
+// Config configures the estimation
+type Config struct {
+	// SerialIntervalMean and SerialIntervalSD describe the gamma distributed
+	// serial interval, in days
+	SerialIntervalMean float64
+	SerialIntervalSD   float64
+	// WindowDays is the number of days each estimate is smoothed over
+	WindowDays int
+}
+
+// Validate checks that the serial interval and window are usable
+func (c Config) Validate() error {
+	if c.SerialIntervalMean <= 1 || c.SerialIntervalSD <= 0 {
+		return errors.New("serial interval mean must be above 1 day and its standard deviation positive")
+	}
+	if c.WindowDays < 1 {
+		return errors.New("window must be at least 1 day")
+	}
+	return nil
+}
+
+// Estimate is the posterior mean of Rt and its 95% credible interval
+type Estimate struct {
+	Mean  float64
+	Lower float64
+	Upper float64
+}
+
+// EstimateRt estimates Rt for every day of incidence, the new cases of
+// consecutive days. An estimate covers the window ending on its day, so the
+// first WindowDays days, and days whose window follows no earlier cases, have
+// none (nil).
+func EstimateRt(incidence []float64, cfg Config) ([]*Estimate, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	weights := SerialIntervalWeights(cfg.SerialIntervalMean, cfg.SerialIntervalSD)
+
+	// infectivity[t] is the total infectiousness of the cases before day t
+	infectivity := make([]float64, len(incidence))
+	for t := range incidence {
+		for k := 1; k < len(weights) && k <= t; k++ {
+			infectivity[t] += incidence[t-k] * weights[k]
+		}
+	}
+
+	estimates := make([]*Estimate, len(incidence))
+	for t := cfg.WindowDays; t < len(incidence); t++ {
+		var cases, infectiousness float64
+		for s := t - cfg.WindowDays + 1; s <= t; s++ {
+			cases += incidence[s]
+			infectiousness += infectivity[s]
+		}
+		if infectiousness <= 0 {
+			continue
+		}
+		shape := priorShape + cases
+		scale := 1 / (1/priorScale + infectiousness)
+		estimates[t] = &Estimate{
+			Mean:  shape * scale,
+			Lower: gammaQuantile(0.025, shape, scale),
+			Upper: gammaQuantile(0.975, shape, scale),
+		}
+	}
+	return estimates, nil
+}
+
+// SerialIntervalWeights discretizes a gamma distributed serial interval with
+// the given mean and standard deviation. Weight k is the probability of an
+// interval of k days; weight 0 is always zero so a case does not infect on
+// its own day, and the mass below 1.5 days goes to day 1.
+func SerialIntervalWeights(mean, sd float64) []float64 {
+	shape := (mean / sd) * (mean / sd)
+	scale := sd * sd / mean
+
+	weights := []float64{0}
+	var total, previous float64
+	for k := 1; k <= maxSerialIntervalDays; k++ {
+		cdf := gammaCDF(float64(k)+0.5, shape, scale)
+		weights = append(weights, cdf-previous)
+		total += cdf - previous
+		previous = cdf
+		if 1-cdf < 1e-6 {
+			break
+		}
+	}
+	for k := range weights {
+		weights[k] /= total
+	}
+	return weights
+}
+
+// gammaCDF is the distribution function of a gamma distribution
+func gammaCDF(x, shape, scale float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return regularizedGammaP(shape, x/scale)
+}
+
+// gammaQuantile finds the p quantile of a gamma distribution by bisection
+func gammaQuantile(p, shape, scale float64) float64 {
+	lo, hi := 0.0, shape*scale+10*math.Sqrt(shape)*scale
+	for gammaCDF(hi, shape, scale) < p {
+		hi *= 2
+	}
+	for i := 0; i < 100 && hi-lo > 1e-9*hi; i++ {
+		mid := (lo + hi) / 2
+		if gammaCDF(mid, shape, scale) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regularizedGammaP is the regularized lower incomplete gamma function P(a, x),
+// from its series below a+1 and its continued fraction above
+func regularizedGammaP(a, x float64) float64 {
+	const (
+		eps     = 1e-14
+		tiny    = 1e-300
+		maxIter = 10000
+	)
+	if x <= 0 {
+		return 0
+	}
+	lgamma, _ := math.Lgamma(a)
+	prefactor := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		term := 1 / a
+		sum := term
+		for n := 1; n < maxIter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return math.Min(1, sum*prefactor)
+	}
+
+	// Modified Lentz's method for the continued fraction of Q(a, x)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < maxIter; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return math.Max(0, 1-prefactor*h)
+}
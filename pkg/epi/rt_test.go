@@ -0,0 +1,96 @@
+package epi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = Config{SerialIntervalMean: 4.7, SerialIntervalSD: 2.9, WindowDays: 7}
+
+func TestRegularizedGammaP(t *testing.T) {
+	// P(1, x) is the exponential distribution function
+	for _, x := range []float64{0.1, 1, 2.5, 10} {
+		assert.InDelta(t, 1-math.Exp(-x), regularizedGammaP(1, x), 1e-12, "x=%g", x)
+	}
+	assert.Zero(t, regularizedGammaP(2, 0))
+	// Median of a gamma distribution with shape 1 and scale 1 is ln 2
+	assert.InDelta(t, math.Ln2, gammaQuantile(0.5, 1, 1), 1e-7)
+	// The central limit: a large shape is close to normal
+	assert.InDelta(t, 1000+1.959964*math.Sqrt(1000), gammaQuantile(0.975, 1000, 1), 1)
+}
+
+func TestSerialIntervalWeights(t *testing.T) {
+	weights := SerialIntervalWeights(4.7, 2.9)
+
+	assert.Zero(t, weights[0])
+	var total, mean float64
+	for k, w := range weights {
+		assert.GreaterOrEqual(t, w, 0.0)
+		total += w
+		mean += float64(k) * w
+	}
+	assert.InDelta(t, 1, total, 1e-9)
+	assert.InDelta(t, 4.7, mean, 0.1)
+}
+
+func TestEstimateRt_ConstantIncidence(t *testing.T) {
+	incidence := make([]float64, 60)
+	for i := range incidence {
+		incidence[i] = 100
+	}
+
+	estimates, err := EstimateRt(incidence, testConfig)
+
+	require.NoError(t, err)
+	for i := 0; i < testConfig.WindowDays; i++ {
+		assert.Nil(t, estimates[i], "day %d", i)
+	}
+	last := estimates[len(estimates)-1]
+	require.NotNil(t, last)
+	assert.InDelta(t, 1, last.Mean, 0.01)
+	assert.Less(t, last.Lower, last.Mean)
+	assert.Greater(t, last.Upper, last.Mean)
+}
+
+func TestEstimateRt_GrowthAndDecline(t *testing.T) {
+	growing := make([]float64, 40)
+	declining := make([]float64, 40)
+	for i := range growing {
+		growing[i] = 10 * math.Exp(0.1*float64(i))
+		declining[i] = 1000 * math.Exp(-0.1*float64(i))
+	}
+
+	up, err := EstimateRt(growing, testConfig)
+	require.NoError(t, err)
+	down, err := EstimateRt(declining, testConfig)
+	require.NoError(t, err)
+
+	assert.Greater(t, up[39].Lower, 1.0)
+	assert.Less(t, down[39].Upper, 1.0)
+}
+
+func TestEstimateRt_NoEarlierCases(t *testing.T) {
+	incidence := make([]float64, 20)
+	incidence[15] = 5
+
+	estimates, err := EstimateRt(incidence, testConfig)
+
+	require.NoError(t, err)
+	for i := 0; i <= 15; i++ {
+		assert.Nil(t, estimates[i], "day %d", i)
+	}
+	assert.NotNil(t, estimates[16])
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, testConfig.Validate())
+	assert.Error(t, Config{SerialIntervalMean: 1, SerialIntervalSD: 1, WindowDays: 7}.Validate())
+	assert.Error(t, Config{SerialIntervalMean: 4.7, SerialIntervalSD: 0, WindowDays: 7}.Validate())
+	assert.Error(t, Config{SerialIntervalMean: 4.7, SerialIntervalSD: 2.9}.Validate())
+
+	_, err := EstimateRt([]float64{1, 2, 3}, Config{})
+	assert.Error(t, err)
+}
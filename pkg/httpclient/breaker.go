@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half_open"
+)
+
+// breaker opens after threshold consecutive failures, rejects calls for
+// openFor, then lets a single trial call through (half-open). The trial's
+// outcome closes or re-opens the circuit.
+type breaker struct {
+	threshold int
+	openFor   time.Duration
+	now       func() time.Time
+
+	mutex     sync.Mutex
+	failures  int
+	openedAt  time.Time
+	isOpen    bool
+	trialSent bool
+}
+
+func newBreaker(threshold int, openFor time.Duration) *breaker {
+	return &breaker{threshold: threshold, openFor: openFor, now: time.Now}
+}
+
+func (b *breaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.isOpen {
+		return true
+	}
+	if b.now().Sub(b.openedAt) < b.openFor || b.trialSent {
+		return false
+	}
+	b.trialSent = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.failures = 0
+	b.isOpen = false
+	b.trialSent = false
+}
+
+func (b *breaker) failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.failures++
+	if b.trialSent || b.failures >= b.threshold {
+		b.isOpen = true
+		b.openedAt = b.now()
+		b.trialSent = false
+	}
+}
+
+func (b *breaker) state() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch {
+	case !b.isOpen:
+		return circuitClosed
+	case b.now().Sub(b.openedAt) >= b.openFor:
+		return circuitHalfOpen
+	default:
+		return circuitOpen
+	}
+}
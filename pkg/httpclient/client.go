@@ -0,0 +1,303 @@
+// Package httpclient provides the shared outbound HTTP client used by the
+// ingestion, webhook and notification subsystems. It adds per-attempt timeouts,
+// retries with jittered exponential backoff, a circuit breaker, trace header
+// propagation and request metrics on top of net/http.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+)
+
+// ErrCircuitOpen is returned without contacting the remote host while the
+// client's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Options configures a Client. Zero values fall back to DefaultOptions.
+type Options struct {
+	// Timeout bounds each attempt, including reading the response headers
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseBackoff and MaxBackoff bound the jittered exponential backoff between attempts
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// FailureThreshold consecutive failures open the circuit for OpenDuration
+	FailureThreshold int
+	OpenDuration     time.Duration
+	// RetryNonIdempotent also retries POST and PATCH requests. Only enable it
+	// when the receiver deduplicates, e.g. by an idempotency key.
+	RetryNonIdempotent bool
+}
+
+// DefaultOptions returns conservative settings for calls to third-party services
+func DefaultOptions() Options {
+	return Options{
+		Timeout:          10 * time.Second,
+		MaxRetries:       3,
+		BaseBackoff:      200 * time.Millisecond,
+		MaxBackoff:       5 * time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// Stats are the counters of a Client since startup
+type Stats struct {
+	Name         string  `json:"name"`
+	Requests     int64   `json:"requests"`
+	Attempts     int64   `json:"attempts"`
+	Retries      int64   `json:"retries"`
+	Failures     int64   `json:"failures"`
+	Rejected     int64   `json:"rejected"`
+	CircuitState string  `json:"circuit_state"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	LastError    string  `json:"last_error,omitempty"`
+}
+
+// Client is a resilient HTTP client. Create one per remote service with New so
+// each service gets its own circuit breaker and metrics.
+type Client struct {
+	name    string
+	opts    Options
+	http    *http.Client
+	breaker *breaker
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	mutex          sync.Mutex
+	stats          Stats
+	totalLatencyMs float64
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      = make(map[string]*Client)
+)
+
+// New creates a client named after the remote service it talks to and registers
+// it for AllStats.
+func New(name string, opts Options) *Client {
+	defaults := DefaultOptions()
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaults.BaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaults.MaxBackoff
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaults.FailureThreshold
+	}
+	if opts.OpenDuration <= 0 {
+		opts.OpenDuration = defaults.OpenDuration
+	}
+
+	c := &Client{
+		name:    name,
+		opts:    opts,
+		http:    &http.Client{Timeout: opts.Timeout},
+		breaker: newBreaker(opts.FailureThreshold, opts.OpenDuration),
+		sleep:   sleepContext,
+		stats:   Stats{Name: name},
+	}
+
+	registryMutex.Lock()
+	registry[name] = c
+	registryMutex.Unlock()
+	return c
+}
+
+// AllStats returns the stats of every client created with New, sorted by name
+func AllStats() []Stats {
+	registryMutex.Lock()
+	clients := make([]*Client, 0, len(registry))
+	for _, c := range registry {
+		clients = append(clients, c)
+	}
+	registryMutex.Unlock()
+
+	stats := make([]Stats, len(clients))
+	for i, c := range clients {
+		stats[i] = c.Stats()
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// Stats returns a snapshot of the client's counters
+func (c *Client) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s := c.stats
+	s.CircuitState = c.breaker.state()
+	if s.Attempts > 0 {
+		s.AvgLatencyMs = c.totalLatencyMs / float64(s.Attempts)
+	}
+	return s
+}
+
+// Do sends req, retrying network errors, 429 and 5xx responses. Requests with a
+// body must set GetBody (http.NewRequest does for common body types) to be
+// retried. Trace headers from the request context are added to every attempt.
+// The returned response, when err is nil, must be closed by the caller.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	c.count(func(s *Stats) { s.Requests++ })
+
+	retryable := c.opts.RetryNonIdempotent || isIdempotent(req.Method)
+	if req.Body != nil && req.GetBody == nil {
+		retryable = false
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.allow() {
+			c.count(func(s *Stats) { s.Rejected++ })
+			if lastErr != nil {
+				return nil, fmt.Errorf("%s: %w (last error: %v)", c.name, ErrCircuitOpen, lastErr)
+			}
+			return nil, fmt.Errorf("%s: %w", c.name, ErrCircuitOpen)
+		}
+
+		attemptReq, err := c.prepare(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := c.http.Do(attemptReq)
+		elapsed := time.Since(start)
+		c.count(func(s *Stats) {
+			s.Attempts++
+			c.totalLatencyMs += float64(elapsed) / float64(time.Millisecond)
+		})
+
+		failed, retryAfter := classify(resp, err)
+		if !failed {
+			c.breaker.success()
+			return resp, nil
+		}
+
+		c.breaker.failure()
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		c.count(func(s *Stats) {
+			s.Failures++
+			s.LastError = lastErr.Error()
+		})
+
+		if !retryable || attempt >= c.opts.MaxRetries || req.Context().Err() != nil {
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", c.name, err)
+			}
+			// Hand the final error response to the caller to inspect
+			return resp, nil
+		}
+		if resp != nil {
+			drainAndClose(resp)
+		}
+
+		c.count(func(s *Stats) { s.Retries++ })
+		wait := c.backoff(attempt)
+		if retryAfter > wait && retryAfter <= c.opts.MaxBackoff {
+			wait = retryAfter
+		}
+		if err := c.sleep(req.Context(), wait); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+	}
+}
+
+// Get is a convenience wrapper around Do for GET requests
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+func (c *Client) prepare(req *http.Request, attempt int) (*http.Request, error) {
+	attemptReq := req.Clone(req.Context())
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to replay request body: %w", c.name, err)
+		}
+		attemptReq.Body = body
+	}
+	tracing.Inject(attemptReq)
+	return attemptReq, nil
+}
+
+// backoff returns the full-jitter exponential delay before retry number attempt+1
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := float64(c.opts.BaseBackoff) * math.Pow(2, float64(attempt))
+	if ceiling > float64(c.opts.MaxBackoff) {
+		ceiling = float64(c.opts.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+func (c *Client) count(update func(s *Stats)) {
+	c.mutex.Lock()
+	update(&c.stats)
+	c.mutex.Unlock()
+}
+
+// classify reports whether an attempt failed and the server's Retry-After, if any
+func classify(resp *http.Response, err error) (bool, time.Duration) {
+	if err != nil {
+		return true, 0
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		var retryAfter time.Duration
+		if secs, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return true, retryAfter
+	}
+	return false, 0
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func drainAndClose(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
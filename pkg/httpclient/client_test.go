@@ -0,0 +1,181 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(name string, opts Options) *Client {
+	c := New(name, opts)
+	c.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return c
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient("retry-test", Options{MaxRetries: 3})
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls)
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Requests)
+	assert.Equal(t, int64(3), stats.Attempts)
+	assert.Equal(t, int64(2), stats.Retries)
+	assert.Equal(t, circuitClosed, stats.CircuitState)
+}
+
+func TestClient_ReturnsFinalErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := newTestClient("final-error-test", Options{MaxRetries: 1, FailureThreshold: 10})
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int64(2), c.Stats().Attempts)
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := newTestClient("client-error-test", Options{MaxRetries: 3})
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestClient_DoesNotRetryPostByDefault(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newTestClient("post-test", Options{MaxRetries: 3})
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"a":1}`))
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestClient_ReplaysBodyOnRetry(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient("replay-test", Options{MaxRetries: 2, RetryNonIdempotent: true})
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("payload"))
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+}
+
+func TestClient_CircuitBreakerOpens(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newTestClient("breaker-test", Options{MaxRetries: 5, FailureThreshold: 2, OpenDuration: time.Hour})
+	_, err := c.Get(context.Background(), srv.URL)
+
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), calls)
+
+	_, err = c.Get(context.Background(), srv.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), calls)
+	assert.Equal(t, circuitOpen, c.Stats().CircuitState)
+	assert.Equal(t, int64(2), c.Stats().Rejected)
+}
+
+func TestClient_PropagatesTrace(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(tracing.TraceparentHeader)
+	}))
+	defer srv.Close()
+
+	trace := tracing.New()
+	ctx := tracing.NewContext(context.Background(), trace)
+	resp, err := newTestClient("trace-test", Options{}).Get(ctx, srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Contains(t, got, trace.TraceID)
+}
+
+func TestBreaker_HalfOpenTrial(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.failure()
+	assert.False(t, b.allow())
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, circuitHalfOpen, b.state())
+	assert.True(t, b.allow())
+	assert.False(t, b.allow(), "only one trial call while half-open")
+
+	b.success()
+	assert.Equal(t, circuitClosed, b.state())
+	assert.True(t, b.allow())
+}
+
+func TestAllStats(t *testing.T) {
+	newTestClient("zz-stats-test", Options{})
+
+	names := make([]string, 0)
+	for _, s := range AllStats() {
+		names = append(names, s.Name)
+	}
+	assert.Contains(t, names, "zz-stats-test")
+}
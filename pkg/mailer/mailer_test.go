@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com", 587, "user", "pass", "Pico <noreply@example.com>")
+	m.now = func() time.Time { return time.Date(2021, 7, 1, 8, 0, 0, 0, time.UTC) }
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	m.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		assert.NotNil(t, a)
+		return nil
+	}
+
+	err := m.Send(Message{To: "warga@example.com", Subject: "Update harian", Body: "line one\nline two",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/u>"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, "Pico <noreply@example.com>", gotFrom)
+	assert.Equal(t, []string{"warga@example.com"}, gotTo)
+	msg := string(gotMsg)
+	assert.Contains(t, msg, "To: warga@example.com\r\n")
+	assert.Contains(t, msg, "Subject: Update harian\r\n")
+	assert.Contains(t, msg, "List-Unsubscribe: <https://example.com/u>\r\n")
+	assert.True(t, strings.HasSuffix(msg, "\r\n\r\nline one\r\nline two"))
+}
+
+func TestSMTPMailer_Send_RejectsHeaderInjection(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com", 25, "", "", "noreply@example.com")
+	m.sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+		t.Fatal("should not send")
+		return nil
+	}
+
+	assert.Error(t, m.Send(Message{To: "a@example.com\r\nBcc: b@example.com"}))
+}
+
+func TestSMTPMailer_Send_WrapsError(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com", 25, "", "", "noreply@example.com")
+	m.sendMail = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+
+	err := m.Send(Message{To: "a@example.com"})
+
+	assert.ErrorContains(t, err, "connection refused")
+}
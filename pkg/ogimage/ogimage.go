@@ -0,0 +1,162 @@
+// Package ogimage renders Open Graph preview images for social shares using
+// the Go fonts, so no external rendering service is needed.
+package ogimage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Width and Height are the size recommended for og:image previews
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	margin     = 60
+	gap        = 30
+	tileHeight = 170
+	accentSize = 8
+)
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	header     = color.RGBA{0x10, 0x2a, 0x43, 0xff}
+	textColor  = color.RGBA{0x1f, 0x29, 0x33, 0xff}
+	muted      = color.RGBA{0x62, 0x7d, 0x98, 0xff}
+	tileFill   = color.RGBA{0xf0, 0xf4, 0xf8, 0xff}
+)
+
+// Stat is one headline number on the card
+type Stat struct {
+	Label string
+	Value string
+	// Delta is the change shown under the value, empty to omit it
+	Delta string
+	Color color.Color
+}
+
+// Card is the content of a preview image. Up to four stats are drawn in a
+// two by two grid.
+type Card struct {
+	Title    string
+	Subtitle string
+	Stats    []Stat
+	Footer   string
+}
+
+type faces struct {
+	title, subtitle, label, value, delta, footer font.Face
+}
+
+var (
+	loadOnce    sync.Once
+	loadedFaces faces
+	loadErr     error
+)
+
+func loadFaces() (faces, error) {
+	loadOnce.Do(func() {
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			loadErr = fmt.Errorf("failed to parse regular font: %w", err)
+			return
+		}
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			loadErr = fmt.Errorf("failed to parse bold font: %w", err)
+			return
+		}
+		face := func(f *opentype.Font, size float64) font.Face {
+			if loadErr != nil {
+				return nil
+			}
+			var fc font.Face
+			fc, loadErr = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+			return fc
+		}
+		loadedFaces = faces{
+			title:    face(bold, 54),
+			subtitle: face(regular, 30),
+			label:    face(regular, 26),
+			value:    face(bold, 58),
+			delta:    face(regular, 24),
+			footer:   face(regular, 22),
+		}
+	})
+	return loadedFaces, loadErr
+}
+
+// Render draws the card and writes it to w as a PNG
+func Render(w io.Writer, c Card) error {
+	img, err := Draw(c)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	return nil
+}
+
+// Draw draws the card onto a new Width x Height image
+func Draw(c Card) (*image.RGBA, error) {
+	f, err := loadFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fill(img, img.Bounds(), background)
+	fill(img, image.Rect(0, 0, Width, 190), header)
+
+	drawText(img, f.title, color.White, margin, 100, c.Title)
+	drawText(img, f.subtitle, color.RGBA{0xbc, 0xcc, 0xdc, 0xff}, margin, 150, c.Subtitle)
+
+	tileWidth := (Width - 2*margin - gap) / 2
+	for i, s := range c.Stats {
+		if i == 4 {
+			break
+		}
+		x := margin + (i%2)*(tileWidth+gap)
+		y := 220 + (i/2)*(tileHeight+gap/2)
+		fill(img, image.Rect(x, y, x+tileWidth, y+tileHeight), tileFill)
+		accent := s.Color
+		if accent == nil {
+			accent = textColor
+		}
+		fill(img, image.Rect(x, y, x+accentSize, y+tileHeight), accent)
+
+		drawText(img, f.label, muted, x+32, y+44, s.Label)
+		drawText(img, f.value, accent, x+32, y+112, s.Value)
+		if s.Delta != "" {
+			drawText(img, f.delta, muted, x+32, y+150, s.Delta)
+		}
+	}
+
+	if c.Footer != "" {
+		drawText(img, f.footer, muted, margin, Height-22, c.Footer)
+	}
+	return img, nil
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, s string) {
+	d := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
@@ -0,0 +1,49 @@
+package ogimage
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	red := color.RGBA{0xc6, 0x28, 0x28, 0xff}
+	card := Card{
+		Title:    "COVID-19 Sulawesi Tengah",
+		Subtitle: "Hari ke-512 · 17 Agustus 2021",
+		Stats: []Stat{
+			{Label: "Positif", Value: "12.345", Delta: "+14 hari ini", Color: red},
+			{Label: "Sembuh", Value: "11.000"},
+			{Label: "Meninggal", Value: "321"},
+			{Label: "Dirawat", Value: "1.024"},
+			{Label: "Ignored", Value: "0"},
+		},
+		Footer: "Sumber: PICO API",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, card))
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Width, img.Bounds().Dx())
+	assert.Equal(t, Height, img.Bounds().Dy())
+
+	// Header band, page background and the first tile's accent bar
+	assert.Equal(t, color.RGBAModel.Convert(header), color.RGBAModel.Convert(img.At(5, 5)))
+	assert.Equal(t, color.RGBAModel.Convert(background), color.RGBAModel.Convert(img.At(Width-5, Height-5)))
+	assert.Equal(t, color.RGBAModel.Convert(red), color.RGBAModel.Convert(img.At(margin+2, 230)))
+}
+
+func TestDraw_DrawsText(t *testing.T) {
+	blank, err := Draw(Card{})
+	require.NoError(t, err)
+	withTitle, err := Draw(Card{Title: "COVID-19"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, blank.Pix, withTitle.Pix)
+}
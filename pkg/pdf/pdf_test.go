@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := New("Laporan (Harian)")
+	page := doc.AddPage()
+	page.Text(40, 60, Bold, 18, Black, "Hari ke-450 · Sulawesi (Tengah)")
+	page.Line(40, 70, 555.28, 70, 0.5, Color{0xcc, 0xcc, 0xcc})
+	page.Rect(40, 100, 20, 50, Color{0xff, 0, 0})
+	doc.AddPage().Text(40, 60, Regular, 10, Black, "Halaman 2")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	out := buf.Bytes()
+	assert.Equal(t, int64(len(out)), n)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, buf.String(), `/Title (Laporan \(Harian\))`)
+	assert.Contains(t, buf.String(), "/Kids [6 0 R 8 0 R] /Count 2")
+
+	// Every cross-reference entry points at its object
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, xref)
+	start, err := strconv.Atoi(string(xref[1]))
+	require.NoError(t, err)
+	entries := strings.Split(string(out[start:]), "\n")
+	require.Equal(t, "0 10", entries[1])
+	for i := 1; i < 10; i++ {
+		offset, err := strconv.Atoi(entries[2+i][:10])
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i))), "object %d", i)
+	}
+
+	content := pageContent(t, out, 7)
+	assert.Contains(t, content, `0 0 0 rg BT /F2 18 Tf 40 781.89 Td (Hari ke-450 \267 Sulawesi \(Tengah\)) Tj ET`)
+	assert.Contains(t, content, "0.8 0.8 0.8 RG 0.5 w 40 771.89 m 555.28 771.89 l S")
+	assert.Contains(t, content, "1 0 0 rg 40 691.89 20 50 re f")
+	assert.Contains(t, pageContent(t, out, 9), "/F1 10 Tf")
+}
+
+// pageContent inflates the content stream of object id
+func pageContent(t *testing.T, out []byte, id int) string {
+	t.Helper()
+	header := fmt.Sprintf("%d 0 obj\n", id)
+	start := bytes.Index(out, []byte(header))
+	require.GreaterOrEqual(t, start, 0)
+	body := out[start:]
+	body = body[bytes.Index(body, []byte("stream\n"))+len("stream\n"):]
+	zr, err := zlib.NewReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestEncodeText(t *testing.T) {
+	assert.Equal(t, `a\\b \(c\)`, encodeText(`a\b (c)`))
+	assert.Equal(t, `5 \226 7 \351`, encodeText("5 – 7 é"))
+	assert.Equal(t, "??", encodeText("日本"))
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content encodings of the pre-compressed variants of a blob
+const (
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+// brotliLevel trades compression for speed; level 11 takes several times as
+// long on multi-MB files for a few percent less
+const brotliLevel = 9
+
+// encodings lists the variants in order of preference when a client accepts
+// several equally
+var encodings = []string{EncodingBrotli, EncodingGzip}
+
+// EncodedKey returns the key the variant of key in the given content
+// encoding is stored under
+func EncodedKey(key, encoding string) string {
+	switch encoding {
+	case EncodingBrotli:
+		return key + ".br"
+	case EncodingGzip:
+		return key + ".gz"
+	default:
+		return key
+	}
+}
+
+// WriteEncoded stores what write writes under key, plus its gzip and brotli
+// variants, so downloads are not compressed per request. write is called once
+// per variant. It returns the bytes stored under key.
+func WriteEncoded(ctx context.Context, store BlobStore, key string, write func(w io.Writer) error) (int64, error) {
+	n, err := Write(ctx, store, key, write)
+	if err != nil {
+		return n, err
+	}
+	for _, encoding := range encodings {
+		_, err := Write(ctx, store, EncodedKey(key, encoding), func(w io.Writer) error {
+			cw := newCompressor(w, encoding)
+			if err := write(cw); err != nil {
+				_ = cw.Close()
+				return err
+			}
+			return cw.Close()
+		})
+		if err != nil {
+			return n, errors.Join(err, DeleteEncoded(ctx, store, key))
+		}
+	}
+	return n, nil
+}
+
+func newCompressor(w io.Writer, encoding string) io.WriteCloser {
+	if encoding == EncodingBrotli {
+		return brotli.NewWriterLevel(w, brotliLevel)
+	}
+	gz, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+	return gz
+}
+
+// DeleteEncoded removes the blob stored under key and its variants
+func DeleteEncoded(ctx context.Context, store BlobStore, key string) error {
+	var errs []error
+	for _, k := range []string{key, EncodedKey(key, EncodingBrotli), EncodedKey(key, EncodingGzip)} {
+		if err := store.Delete(ctx, k); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OpenEncoded opens the variant of key that best matches the Accept-Encoding
+// header and returns it with its content encoding, empty for the blob as
+// stored. Blobs written without variants are returned as stored.
+func OpenEncoded(ctx context.Context, store BlobStore, key, acceptEncoding string) (*Blob, string, error) {
+	for _, encoding := range negotiate(acceptEncoding) {
+		blob, err := store.Open(ctx, EncodedKey(key, encoding))
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return blob, encoding, nil
+	}
+	blob, err := store.Open(ctx, key)
+	return blob, "", err
+}
+
+// negotiate returns the variant encodings the Accept-Encoding header allows,
+// the highest quality first and ties in order of preference
+func negotiate(acceptEncoding string) []string {
+	quality := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		switch coding {
+		case "x-gzip":
+			coding = EncodingGzip
+		case "*":
+			wildcard = q
+			continue
+		}
+		quality[coding] = q
+	}
+
+	var accepted []string
+	var weights []float64
+	for _, encoding := range encodings {
+		q, ok := quality[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q <= 0 {
+			continue
+		}
+		// Insert after the variants of at least the same quality
+		i := len(accepted)
+		for i > 0 && weights[i-1] < q {
+			i--
+		}
+		accepted = append(accepted[:i], append([]string{encoding}, accepted[i:]...)...)
+		weights = append(weights[:i], append([]float64{q}, weights[i:]...)...)
+	}
+	return accepted
+}
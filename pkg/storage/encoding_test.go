@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEncoded_OpenEncoded(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(t.TempDir())
+	csv := strings.Repeat("day,date,positive\n", 100)
+
+	n, err := WriteEncoded(ctx, store, "export.csv", func(w io.Writer) error {
+		_, err := io.WriteString(w, csv)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(csv)), n)
+
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+		decode         func(io.Reader) (io.Reader, error)
+	}{
+		{"", "", nil},
+		{"identity", "", nil},
+		{"gzip, deflate", EncodingGzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"gzip, deflate, br", EncodingBrotli, func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+		{"br;q=0.5, gzip", EncodingGzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"x-gzip", EncodingGzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"*", EncodingBrotli, func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+		{"*, br;q=0", EncodingGzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"br;q=0, gzip;q=0", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			blob, encoding, err := OpenEncoded(ctx, store, "export.csv", tt.acceptEncoding)
+			require.NoError(t, err)
+			defer func() { _ = blob.Close() }()
+			assert.Equal(t, tt.encoding, encoding)
+
+			var r io.Reader = blob
+			if tt.decode != nil {
+				r, err = tt.decode(blob)
+				require.NoError(t, err)
+			}
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, csv, string(content))
+		})
+	}
+
+	require.NoError(t, DeleteEncoded(ctx, store, "export.csv"))
+	for _, key := range []string{"export.csv", "export.csv.gz", "export.csv.br"} {
+		_, err := store.Open(ctx, key)
+		assert.ErrorIs(t, err, ErrNotFound, key)
+	}
+}
+
+// Blobs stored without variants are served as stored
+func TestOpenEncoded_NoVariants(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(filepath.Join(t.TempDir(), "exports"))
+	_, err := store.Put(ctx, "export.xlsx", strings.NewReader("PK"))
+	require.NoError(t, err)
+
+	blob, encoding, err := OpenEncoded(ctx, store, "export.xlsx", "br, gzip")
+	require.NoError(t, err)
+	assert.Empty(t, encoding)
+	content, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	assert.Equal(t, "PK", string(content))
+
+	_, _, err = OpenEncoded(ctx, store, "missing.csv", "br")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// A failed write leaves neither the blob nor its variants
+func TestWriteEncoded_Failed(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(t.TempDir())
+	calls := 0
+	_, err := WriteEncoded(ctx, store, "export.csv", func(w io.Writer) error {
+		calls++
+		if calls == 2 {
+			return errors.New("render failed")
+		}
+		_, err := io.WriteString(w, "day,date\n")
+		return err
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "render failed")
+	for _, key := range []string{"export.csv", "export.csv.gz", "export.csv.br"} {
+		_, err := store.Open(ctx, key)
+		assert.ErrorIs(t, err, ErrNotFound, key)
+	}
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal_PutOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "exports")
+	store := NewLocal(dir)
+
+	n, err := store.Put(ctx, "2021/export.csv", strings.NewReader("day,date\n"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), n)
+	assert.Equal(t, filepath.Join(dir, "2021", "export.csv"), store.Location("2021/export.csv"))
+
+	blob, err := store.Open(ctx, "2021/export.csv")
+	require.NoError(t, err)
+	content, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	assert.Equal(t, "day,date\n", string(content))
+	assert.Equal(t, int64(9), blob.Size)
+	_, seekable := blob.ReadCloser.(io.Seeker)
+	assert.True(t, seekable)
+
+	require.NoError(t, store.Delete(ctx, "2021/export.csv"))
+	require.NoError(t, store.Delete(ctx, "2021/export.csv"), "deleting a missing blob is no error")
+	_, err = store.Open(ctx, "2021/export.csv")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocal_PutFailedLeavesNoBlob(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocal(dir)
+
+	_, err := Write(context.Background(), store, "backup.sql.gz", func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return errors.New("lock wait timeout")
+	})
+	assert.EqualError(t, err, "lock wait timeout")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocal_RejectsKeysOutsideDir(t *testing.T) {
+	store := NewLocal(t.TempDir())
+	for _, key := range []string{"", "../secret", "/etc/passwd"} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("x"))
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/httpclient"
+)
+
+// gcsEndpoint is the XML API of Google Cloud Storage, which accepts S3
+// requests signed with an HMAC key
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// transferTimeout bounds an upload or download, including its body
+const transferTimeout = 10 * time.Minute
+
+// emptyPayloadHash is the SHA-256 of an empty body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Options configures an S3 store
+type S3Options struct {
+	// Endpoint is the URL of an S3 compatible service such as MinIO, which
+	// is addressed path-style; empty uses AWS S3 in Region
+	Endpoint string
+	// Region defaults to us-east-1
+	Region string
+	Bucket string
+	// Prefix is prepended to every key
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3 stores blobs as objects of an S3 bucket, or of any service speaking its
+// API. Requests are signed with AWS Signature Version 4.
+type S3 struct {
+	opts   S3Options
+	scheme string
+	bucket *url.URL
+	client *httpclient.Client
+	now    func() time.Time
+}
+
+// NewS3 creates a store in an S3 bucket
+func NewS3(opts S3Options) (*S3, error) {
+	return newS3("s3", opts)
+}
+
+// NewGCS creates a store in a Google Cloud Storage bucket through its XML
+// API, authenticated with the HMAC key of a service account
+func NewGCS(opts S3Options) (*S3, error) {
+	if opts.Endpoint == "" {
+		opts.Endpoint = gcsEndpoint
+	}
+	if opts.Region == "" {
+		opts.Region = "auto"
+	}
+	return newS3("gs", opts)
+}
+
+func newS3(scheme string, opts S3Options) (*S3, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("a storage bucket is required")
+	}
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+		return nil, errors.New("storage credentials are required")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	opts.Prefix = joinKey(opts.Prefix)
+
+	var bucket *url.URL
+	if opts.Endpoint == "" {
+		bucket = &url.URL{Scheme: "https", Host: opts.Bucket + ".s3." + opts.Region + ".amazonaws.com"}
+	} else {
+		endpoint, err := url.Parse(opts.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid storage endpoint %q", opts.Endpoint)
+		}
+		bucket = &url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host, Path: strings.TrimSuffix(endpoint.Path, "/") + "/" + opts.Bucket}
+	}
+
+	client := httpclient.DefaultOptions()
+	client.Timeout = transferTimeout
+	return &S3{
+		opts:   opts,
+		scheme: scheme,
+		bucket: bucket,
+		client: httpclient.New(scheme+"://"+joinKey(opts.Bucket, opts.Prefix), client),
+		now:    time.Now,
+	}, nil
+}
+
+// Put spools r to a temporary file, as S3 needs the length and checksum of
+// an object before its upload, and uploads it
+func (s *S3) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	f, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create spool file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, hash), r)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := s.request(ctx, http.MethodPut, key, io.NewSectionReader(f, 0, n))
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = n
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(f, 0, n)), nil
+	}
+	resp, err := s.do(req, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return 0, err
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError(req, resp)
+	}
+	return n, nil
+}
+
+// Open downloads the object of key, which is read from the response body
+func (s *S3) Open(ctx context.Context, key string) (*Blob, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		drain(resp)
+		return nil, ErrNotFound
+	default:
+		defer drain(resp)
+		return nil, statusError(req, resp)
+	}
+	blob := &Blob{ReadCloser: resp.Body, Size: resp.ContentLength}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		blob.ModTime = modified
+	}
+	return blob, nil
+}
+
+// Delete deletes the object of key
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return statusError(req, resp)
+}
+
+// Location returns the URI of the object of key, e.g. s3://bucket/prefix/key
+func (s *S3) Location(key string) string {
+	return s.scheme + "://" + joinKey(s.opts.Bucket, s.opts.Prefix, key)
+}
+
+func (s *S3) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if key == "" {
+		return nil, errors.New("empty blob key")
+	}
+	object := *s.bucket
+	name := joinKey(s.opts.Prefix, key)
+	object.Path = s.bucket.Path + "/" + name
+	object.RawPath = uriEncode(s.bucket.Path, false) + "/" + uriEncode(name, false)
+	return http.NewRequestWithContext(ctx, method, object.String(), body)
+}
+
+func (s *S3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("storage %s %s failed: %w", req.Method, req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 authorization of req, covering its host
+// and every header set so far
+func (s *S3) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	stamp := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	names := []string{"host"}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.opts.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretAccessKey), day)
+	for _, part := range []string{s.opts.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and encodes the query parameters to be signed
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes all but the unreserved characters of RFC 3986,
+// and slashes unless encodeSlash is set, as Signature Version 4 expects
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// statusError describes a failed response by the error code in its body
+func statusError(req *http.Request, resp *http.Response) error {
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	detail := resp.Status
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 4<<10)).Decode(&body); err == nil && body.Code != "" {
+		detail += ": " + body.Code
+		if body.Message != "" {
+			detail += " " + body.Message
+		}
+	}
+	return fmt.Errorf("storage %s %s failed: %s", req.Method, req.URL.Path, detail)
+}
+
+// drain reads the rest of a response body, so the connection can be reused,
+// and closes it
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+)
+
+// Storage drivers
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+	DriverGCS   = "gcs"
+)
+
+// ErrNotFound is returned when opening a key that is not stored
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps files such as exports and backups under slash-separated
+// keys, on the local disk or in a bucket
+type BlobStore interface {
+	// Put stores everything read from r under key, replacing what was
+	// stored there, and returns the bytes stored. A failed Put leaves no blob.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open returns the blob stored under key, or ErrNotFound
+	Open(ctx context.Context, key string) (*Blob, error)
+	// Delete removes the blob stored under key; a missing key is no error
+	Delete(ctx context.Context, key string) error
+	// Location describes where key is stored, for logs and job listings
+	Location(key string) string
+}
+
+// Blob is an open stored blob. Close it after reading. The blobs of the
+// local store are also io.Seekers, so they can serve range requests.
+type Blob struct {
+	io.ReadCloser
+	Size    int64
+	ModTime time.Time
+}
+
+// New opens the store selected by cfg.Driver. The local store keeps its blobs
+// in dir; the bucket stores keep them under cfg.Prefix and then prefix, so
+// several subsystems can share a bucket.
+func New(cfg *config.StorageConfig, dir, prefix string) (BlobStore, error) {
+	opts := S3Options{
+		Endpoint:        cfg.Endpoint,
+		Region:          cfg.Region,
+		Bucket:          cfg.Bucket,
+		Prefix:          joinKey(cfg.Prefix, prefix),
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.Driver)) {
+	case "", DriverLocal:
+		return NewLocal(dir), nil
+	case DriverS3:
+		return NewS3(opts)
+	case DriverGCS:
+		return NewGCS(opts)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q, use local, s3 or gcs", cfg.Driver)
+	}
+}
+
+// Write stores what write writes under key without buffering it first
+func Write(ctx context.Context, store BlobStore, key string, write func(w io.Writer) error) (int64, error) {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = pw.CloseWithError(write(pw))
+	}()
+	n, err := store.Put(ctx, key, pr)
+	// Unblocks write when Put stopped reading early
+	_ = pr.CloseWithError(err)
+	<-done
+	return n, err
+}
+
+// joinKey joins key parts with slashes, skipping empty ones
+func joinKey(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.Trim(p, "/"); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, "/")
+}
@@ -0,0 +1,61 @@
+// Package telegram sends plain text messages through the Telegram Bot API.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/banua-coder/pico-api-go/pkg/httpclient"
+)
+
+// DefaultBaseURL is the Telegram Bot API endpoint
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Client sends messages as a single bot
+type Client struct {
+	BaseURL string
+	token   string
+	http    *httpclient.Client
+}
+
+// NewClient creates a Client for the bot token using the shared outbound client "telegram"
+func NewClient(token string) *Client {
+	return &Client{BaseURL: DefaultBaseURL, token: token, http: httpclient.New("telegram", httpclient.DefaultOptions())}
+}
+
+// SendMessage posts text to the chat. Non-2xx responses are returned as errors
+// carrying Telegram's description.
+func (c *Client) SendMessage(ctx context.Context, chatID, text string) error {
+	body, err := json.Marshal(map[string]string{"chat_id": chatID, "text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram message: %w", err)
+	}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", c.BaseURL, c.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var reply struct {
+			Description string `json:"description"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &reply) == nil && reply.Description != "" {
+			return fmt.Errorf("telegram returned status %d: %s", resp.StatusCode, reply.Description)
+		}
+		return fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SendMessage(t *testing.T) {
+	var got map[string]string
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("123:abc")
+	c.BaseURL = server.URL
+
+	require.NoError(t, c.SendMessage(context.Background(), "-100", "hello"))
+	assert.Equal(t, "/bot123:abc/sendMessage", path)
+	assert.Equal(t, map[string]string{"chat_id": "-100", "text": "hello"}, got)
+}
+
+func TestClient_SendMessage_ReturnsDescription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("123:abc")
+	c.BaseURL = server.URL
+
+	err := c.SendMessage(context.Background(), "-100", "hello")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chat not found")
+}
@@ -0,0 +1,136 @@
+// Package tenant lets one deployment serve several province teams. Each tenant
+// is focused on one province; the shared tables are already keyed by province,
+// so the province ID acts as the tenant column.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Header selects the tenant of a request explicitly
+const Header = "X-Tenant"
+
+// Default is the original Sulawesi Tengah dataset, used when nothing selects
+// a tenant, e.g. by background jobs and the CLI
+var Default = Tenant{Slug: "sulteng", ProvinceID: "72", Name: "Sulawesi Tengah"}
+
+var (
+	slugPattern     = regexp.MustCompile(`^[a-z][a-z0-9-]{1,30}$`)
+	provincePattern = regexp.MustCompile(`^[0-9]{2}$`)
+)
+
+// Tenant is a province team's focused dataset
+type Tenant struct {
+	Slug       string `json:"slug"`
+	ProvinceID string `json:"province_id"`
+	Name       string `json:"name"`
+}
+
+// ProvinceNumber returns the province ID as used by the regency, hospital and
+// vaccination tables
+func (t Tenant) ProvinceNumber() int {
+	n, _ := strconv.Atoi(t.ProvinceID)
+	return n
+}
+
+// Parse reads a "slug:province_id:Name" definition, e.g. "gorontalo:75:Gorontalo"
+func Parse(def string) (Tenant, error) {
+	parts := strings.SplitN(def, ":", 3)
+	if len(parts) != 3 {
+		return Tenant{}, fmt.Errorf("tenant %q must be slug:province_id:name", def)
+	}
+	t := Tenant{
+		Slug:       strings.ToLower(strings.TrimSpace(parts[0])),
+		ProvinceID: strings.TrimSpace(parts[1]),
+		Name:       strings.TrimSpace(parts[2]),
+	}
+	if !slugPattern.MatchString(t.Slug) {
+		return Tenant{}, fmt.Errorf("tenant %q has an invalid slug", def)
+	}
+	if !provincePattern.MatchString(t.ProvinceID) {
+		return Tenant{}, fmt.Errorf("tenant %q has an invalid province ID", def)
+	}
+	if t.Name == "" {
+		return Tenant{}, fmt.Errorf("tenant %q has no name", def)
+	}
+	return t, nil
+}
+
+// Registry holds the tenants a deployment serves
+type Registry struct {
+	tenants map[string]Tenant
+	def     Tenant
+}
+
+// NewRegistry builds a registry from "slug:province_id:name" definitions. The
+// Default tenant is always included; defaultSlug picks the tenant of requests
+// that do not select one and may be empty for Default.
+func NewRegistry(defs []string, defaultSlug string) (*Registry, error) {
+	r := &Registry{tenants: map[string]Tenant{Default.Slug: Default}, def: Default}
+	for _, def := range defs {
+		t, err := Parse(def)
+		if err != nil {
+			return nil, err
+		}
+		r.tenants[t.Slug] = t
+	}
+	if defaultSlug != "" {
+		t, ok := r.tenants[strings.ToLower(defaultSlug)]
+		if !ok {
+			return nil, fmt.Errorf("default tenant %q is not defined", defaultSlug)
+		}
+		r.def = t
+	}
+	return r, nil
+}
+
+// Default returns the tenant of requests that do not select one
+func (r *Registry) Default() Tenant {
+	return r.def
+}
+
+// Lookup returns the tenant with the slug
+func (r *Registry) Lookup(slug string) (Tenant, bool) {
+	t, ok := r.tenants[strings.ToLower(strings.TrimSpace(slug))]
+	return t, ok
+}
+
+// FromRequest selects the tenant of a request by the X-Tenant header, then by
+// the first label of the host (gorontalo.api.example.com), then the default.
+// ok is false when the header names an unknown tenant.
+func (r *Registry) FromRequest(req *http.Request) (Tenant, bool) {
+	if slug := req.Header.Get(Header); slug != "" {
+		return r.Lookup(slug)
+	}
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if label, _, found := strings.Cut(host, "."); found {
+		if t, ok := r.Lookup(label); ok {
+			return t, true
+		}
+	}
+	return r.def, true
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying t
+func NewContext(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant stored in ctx, or Default
+func FromContext(ctx context.Context) Tenant {
+	if t, ok := ctx.Value(contextKey{}).(Tenant); ok {
+		return t
+	}
+	return Default
+}
@@ -0,0 +1,86 @@
+package tenant
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse(" Gorontalo : 75 : Gorontalo ")
+	require.NoError(t, err)
+	assert.Equal(t, Tenant{Slug: "gorontalo", ProvinceID: "75", Name: "Gorontalo"}, got)
+	assert.Equal(t, 75, got.ProvinceNumber())
+
+	for _, def := range []string{"gorontalo:75", "g:75:Gorontalo", "gorontalo:7a:Gorontalo", "gorontalo:75:", "bad slug:75:X"} {
+		_, err := Parse(def)
+		assert.Error(t, err, def)
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	r, err := NewRegistry([]string{"gorontalo:75:Gorontalo"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, Default, r.Default())
+	got, ok := r.Lookup("GORONTALO")
+	assert.True(t, ok)
+	assert.Equal(t, "75", got.ProvinceID)
+
+	r, err = NewRegistry([]string{"gorontalo:75:Gorontalo"}, "gorontalo")
+	require.NoError(t, err)
+	assert.Equal(t, "gorontalo", r.Default().Slug)
+	_, ok = r.Lookup("sulteng")
+	assert.True(t, ok, "the built-in tenant stays available")
+
+	_, err = NewRegistry(nil, "gorontalo")
+	assert.Error(t, err)
+	_, err = NewRegistry([]string{"broken"}, "")
+	assert.Error(t, err)
+}
+
+func TestRegistry_FromRequest(t *testing.T) {
+	r, err := NewRegistry([]string{"gorontalo:75:Gorontalo"}, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		host     string
+		header   string
+		wantSlug string
+		wantOK   bool
+	}{
+		{name: "default", host: "api.example.com", wantSlug: "sulteng", wantOK: true},
+		{name: "header", host: "api.example.com", header: "gorontalo", wantSlug: "gorontalo", wantOK: true},
+		{name: "subdomain", host: "gorontalo.api.example.com", wantSlug: "gorontalo", wantOK: true},
+		{name: "subdomain with port", host: "gorontalo.localhost:8080", wantSlug: "gorontalo", wantOK: true},
+		{name: "header wins over subdomain", host: "gorontalo.api.example.com", header: "sulteng", wantSlug: "sulteng", wantOK: true},
+		{name: "unknown subdomain", host: "www.example.com", wantSlug: "sulteng", wantOK: true},
+		{name: "unknown header", host: "api.example.com", header: "papua", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/regencies", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+
+			got, ok := r.FromRequest(req)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantSlug, got.Slug)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+
+	gorontalo := Tenant{Slug: "gorontalo", ProvinceID: "75", Name: "Gorontalo"}
+	assert.Equal(t, gorontalo, FromContext(NewContext(context.Background(), gorontalo)))
+}
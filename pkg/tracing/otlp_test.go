@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var got map[string]interface{}
+	var path, apiKey, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		apiKey = r.Header.Get("x-api-key")
+		contentType = r.Header.Get("Content-Type")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	start := time.Unix(1700000000, 0)
+	exporter := NewOTLPExporter(srv.URL+"/", "pico-api-go", []string{"x-api-key = secret"})
+	err := exporter.Export(context.Background(), []SpanData{{
+		Name:         "SELECT",
+		Kind:         SpanKindClient,
+		TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:       "00f067aa0ba902b7",
+		ParentSpanID: "1111111111111111",
+		Start:        start,
+		End:          start.Add(time.Millisecond),
+		Attributes:   map[string]interface{}{"db.system": "mysql", "rows": 3, "cached": false},
+		Error:        "boom",
+	}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/traces", path)
+	assert.Equal(t, "secret", apiKey)
+	assert.Equal(t, "application/json", contentType)
+
+	resource := got["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	serviceName := resource["resource"].(map[string]interface{})["attributes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "service.name", serviceName["key"])
+	assert.Equal(t, "pico-api-go", serviceName["value"].(map[string]interface{})["stringValue"])
+
+	span := resource["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span["traceId"])
+	assert.Equal(t, "1111111111111111", span["parentSpanId"])
+	assert.Equal(t, float64(SpanKindClient), span["kind"])
+	assert.Equal(t, "1700000000000000000", span["startTimeUnixNano"])
+	assert.Equal(t, "1700000000001000000", span["endTimeUnixNano"])
+	assert.Equal(t, map[string]interface{}{"code": float64(2), "message": "boom"}, span["status"])
+
+	attrs := span["attributes"].([]interface{})
+	require.Len(t, attrs, 3)
+	assert.Equal(t, map[string]interface{}{"key": "cached", "value": map[string]interface{}{"boolValue": false}}, attrs[0])
+	assert.Equal(t, map[string]interface{}{"key": "db.system", "value": map[string]interface{}{"stringValue": "mysql"}}, attrs[1])
+	assert.Equal(t, map[string]interface{}{"key": "rows", "value": map[string]interface{}{"intValue": "3"}}, attrs[2])
+}
+
+func TestOTLPExporter_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := NewOTLPExporter(srv.URL, "pico-api-go", nil).Export(context.Background(), []SpanData{{Name: "x"}})
+	assert.ErrorContains(t, err, "status 401")
+}
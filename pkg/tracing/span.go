@@ -0,0 +1,228 @@
+package tracing
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind tells the backend which side of a call a span describes. The values
+// match the OTLP enum.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// SpanData is a finished span as handed to an Exporter
+type SpanData struct {
+	Name         string
+	Kind         SpanKind
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]interface{}
+	// Error is the message of the error recorded on the span, if any
+	Error string
+}
+
+// Exporter sends finished spans to a tracing backend
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Span is an operation in progress. A nil *Span is valid and records nothing,
+// which is what StartSpan returns while no Recorder is installed.
+type Span struct {
+	data     SpanData
+	recorder *Recorder
+	ended    atomic.Bool
+}
+
+// SetAttribute attaches a string, bool, integer or float value to the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span as failed. Nil errors are ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.data.Error = err.Error()
+}
+
+// End finishes the span and queues it for export. Only the first call counts.
+func (s *Span) End() {
+	if s == nil || !s.ended.CompareAndSwap(false, true) {
+		return
+	}
+	s.data.End = time.Now()
+	s.recorder.record(s.data)
+}
+
+// StartSpan starts a child of the span in ctx, or a new trace when ctx has
+// none, and returns a context carrying it. Without an installed Recorder, or
+// when the trace is not sampled, it returns ctx unchanged and a nil span.
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	r := defaultRecorder.Load()
+	if r == nil {
+		return ctx, nil
+	}
+	parent, ok := FromContext(ctx)
+	if !ok {
+		parent = New()
+		parent.SpanID = ""
+	}
+	if !parent.Sampled {
+		return ctx, nil
+	}
+
+	child := parent.Child()
+	span := &Span{
+		recorder: r,
+		data: SpanData{
+			Name:         name,
+			Kind:         kind,
+			TraceID:      child.TraceID,
+			SpanID:       child.SpanID,
+			ParentSpanID: parent.SpanID,
+			Start:        time.Now(),
+			Attributes:   make(map[string]interface{}),
+		},
+	}
+	return NewContext(ctx, child), span
+}
+
+// RecorderOptions tune how a Recorder batches spans
+type RecorderOptions struct {
+	// BatchSize is the number of spans sent per export
+	BatchSize int
+	// FlushInterval is the longest a finished span waits before export
+	FlushInterval time.Duration
+	// QueueSize caps the spans waiting for export; further spans are dropped
+	QueueSize int
+}
+
+// Recorder batches finished spans and exports them in the background
+type Recorder struct {
+	exporter Exporter
+	opts     RecorderOptions
+	queue    chan SpanData
+	done     chan struct{}
+	wg       sync.WaitGroup
+	dropped  atomic.Int64
+}
+
+var defaultRecorder atomic.Pointer[Recorder]
+
+// NewRecorder starts a Recorder exporting to exporter. Zero options fall back
+// to a batch of 256 spans, a 5 second flush interval and a queue of 2048.
+func NewRecorder(exporter Exporter, opts RecorderOptions) *Recorder {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 256
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 2048
+	}
+	r := &Recorder{
+		exporter: exporter,
+		opts:     opts,
+		queue:    make(chan SpanData, opts.QueueSize),
+		done:     make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r
+}
+
+// SetRecorder installs r as the destination of spans started by StartSpan.
+// Nil disables span recording.
+func SetRecorder(r *Recorder) {
+	defaultRecorder.Store(r)
+}
+
+// Dropped returns the number of spans discarded because the queue was full
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Shutdown exports the queued spans and stops the Recorder
+func (r *Recorder) Shutdown(ctx context.Context) error {
+	close(r.done)
+	finished := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) record(span SpanData) {
+	select {
+	case r.queue <- span:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+func (r *Recorder) run() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, r.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), r.opts.FlushInterval)
+		if err := r.exporter.Export(ctx, batch); err != nil {
+			log.Printf("Failed to export %d spans: %v", len(batch), err)
+		}
+		cancel()
+		batch = make([]SpanData, 0, r.opts.BatchSize)
+	}
+
+	for {
+		select {
+		case span := <-r.queue:
+			batch = append(batch, span)
+			if len(batch) >= r.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-r.done:
+			for {
+				select {
+				case span := <-r.queue:
+					batch = append(batch, span)
+					if len(batch) >= r.opts.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collectingExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *collectingExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func installRecorder(t *testing.T) (*Recorder, *collectingExporter) {
+	exporter := &collectingExporter{}
+	r := NewRecorder(exporter, RecorderOptions{FlushInterval: time.Hour})
+	SetRecorder(r)
+	t.Cleanup(func() { SetRecorder(nil) })
+	return r, exporter
+}
+
+func TestStartSpan_WithoutRecorder(t *testing.T) {
+	ctx := context.Background()
+	got, span := StartSpan(ctx, "noop", SpanKindInternal)
+
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, got)
+	// A nil span is safe to use
+	span.SetAttribute("key", "value")
+	span.RecordError(errors.New("boom"))
+	span.End()
+}
+
+func TestStartSpan_ChildOfContextTrace(t *testing.T) {
+	r, exporter := installRecorder(t)
+	parent := Trace{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}
+
+	ctx, span := StartSpan(NewContext(context.Background(), parent), "GET /provinces", SpanKindServer)
+	require.NotNil(t, span)
+	_, child := StartSpan(ctx, "SELECT", SpanKindClient)
+	child.SetAttribute("db.system", "mysql")
+	child.RecordError(errors.New("boom"))
+	child.End()
+	span.End()
+	span.End()
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.Len(t, exporter.spans, 2)
+	db, server := exporter.spans[0], exporter.spans[1]
+	assert.Equal(t, parent.TraceID, server.TraceID)
+	assert.Equal(t, parent.SpanID, server.ParentSpanID)
+	assert.Equal(t, server.SpanID, db.ParentSpanID)
+	assert.Equal(t, parent.TraceID, db.TraceID)
+	assert.Equal(t, "mysql", db.Attributes["db.system"])
+	assert.Equal(t, "boom", db.Error)
+	assert.Empty(t, server.Error)
+	assert.False(t, server.End.Before(server.Start))
+}
+
+func TestStartSpan_NewTrace(t *testing.T) {
+	r, exporter := installRecorder(t)
+
+	ctx, span := StartSpan(context.Background(), "job", SpanKindInternal)
+	span.End()
+
+	trace, ok := FromContext(ctx)
+	assert.True(t, ok)
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.Len(t, exporter.spans, 1)
+	assert.Equal(t, trace.TraceID, exporter.spans[0].TraceID)
+	assert.Empty(t, exporter.spans[0].ParentSpanID)
+}
+
+func TestStartSpan_NotSampled(t *testing.T) {
+	installRecorder(t)
+	parent := Trace{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+
+	_, span := StartSpan(NewContext(context.Background(), parent), "GET /", SpanKindServer)
+	assert.Nil(t, span)
+}
+
+func TestRecorder_ExportsFullBatches(t *testing.T) {
+	exporter := &collectingExporter{}
+	r := NewRecorder(exporter, RecorderOptions{BatchSize: 2, FlushInterval: time.Hour})
+	r.record(SpanData{Name: "a"})
+	r.record(SpanData{Name: "b"})
+
+	assert.Eventually(t, func() bool {
+		exporter.mu.Lock()
+		defer exporter.mu.Unlock()
+		return len(exporter.spans) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestRecorder_DropsWhenQueueFull(t *testing.T) {
+	r := &Recorder{queue: make(chan SpanData, 1)}
+	r.record(SpanData{Name: "a"})
+	r.record(SpanData{Name: "b"})
+
+	assert.Equal(t, int64(1), r.Dropped())
+}
@@ -0,0 +1,136 @@
+// Package tracing carries distributed trace context from incoming requests to
+// logs and outgoing HTTP calls. It understands the W3C traceparent header and
+// Google Cloud's X-Cloud-Trace-Context header. When a Recorder is installed,
+// spans started with StartSpan are batched and exported, e.g. over OTLP.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Header names
+const (
+	TraceparentHeader = "traceparent"
+	CloudTraceHeader  = "X-Cloud-Trace-Context"
+	TraceIDHeader     = "X-Trace-Id"
+)
+
+var (
+	traceparentPattern = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+	cloudTracePattern  = regexp.MustCompile(`^([0-9a-fA-F]{32})(?:/([0-9]+))?(?:;o=([01]))?$`)
+)
+
+// Trace identifies the trace a request belongs to and the span that sent it
+type Trace struct {
+	TraceID string // 32 lowercase hex characters
+	SpanID  string // 16 lowercase hex characters
+	Sampled bool
+}
+
+type contextKey struct{}
+
+// FromRequest extracts the trace context of an incoming request, preferring
+// traceparent over X-Cloud-Trace-Context. ok is false when neither header is
+// present and valid.
+func FromRequest(r *http.Request) (Trace, bool) {
+	if t, ok := parseTraceparent(r.Header.Get(TraceparentHeader)); ok {
+		return t, true
+	}
+	return parseCloudTrace(r.Header.Get(CloudTraceHeader))
+}
+
+// New starts a new sampled trace
+func New() Trace {
+	return Trace{TraceID: randomHex(16), SpanID: randomHex(8), Sampled: true}
+}
+
+// Child returns a new span in the same trace, to be sent on an outgoing call
+func (t Trace) Child() Trace {
+	return Trace{TraceID: t.TraceID, SpanID: randomHex(8), Sampled: t.Sampled}
+}
+
+// Traceparent formats the trace as a W3C traceparent header value
+func (t Trace) Traceparent() string {
+	flags := "00"
+	if t.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", t.TraceID, t.SpanID, flags)
+}
+
+// CloudTraceContext formats the trace as an X-Cloud-Trace-Context header value
+func (t Trace) CloudTraceContext() string {
+	span, _ := strconv.ParseUint(t.SpanID, 16, 64)
+	sampled := 0
+	if t.Sampled {
+		sampled = 1
+	}
+	return fmt.Sprintf("%s/%d;o=%d", t.TraceID, span, sampled)
+}
+
+// NewContext returns a copy of ctx carrying t
+func NewContext(ctx context.Context, t Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the trace stored in ctx, if any
+func FromContext(ctx context.Context) (Trace, bool) {
+	t, ok := ctx.Value(contextKey{}).(Trace)
+	return t, ok
+}
+
+// Inject sets trace headers on an outgoing request from the trace in its
+// context, as a child span. Requests without a trace are left unchanged.
+func Inject(req *http.Request) {
+	t, ok := FromContext(req.Context())
+	if !ok {
+		return
+	}
+	child := t.Child()
+	req.Header.Set(TraceparentHeader, child.Traceparent())
+	req.Header.Set(CloudTraceHeader, child.CloudTraceContext())
+}
+
+func parseTraceparent(v string) (Trace, bool) {
+	m := traceparentPattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil || m[1] == "ff" || isZero(m[2]) || isZero(m[3]) {
+		return Trace{}, false
+	}
+	flags, _ := strconv.ParseUint(m[4], 16, 8)
+	return Trace{TraceID: m[2], SpanID: m[3], Sampled: flags&1 == 1}, true
+}
+
+func parseCloudTrace(v string) (Trace, bool) {
+	m := cloudTracePattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil || isZero(m[1]) {
+		return Trace{}, false
+	}
+	t := Trace{TraceID: strings.ToLower(m[1]), Sampled: m[3] == "1"}
+	span, err := strconv.ParseUint(m[2], 10, 64)
+	if err != nil || span == 0 {
+		t.SpanID = randomHex(8)
+	} else {
+		t.SpanID = fmt.Sprintf("%016x", span)
+	}
+	return t, true
+}
+
+func isZero(hexID string) bool {
+	return strings.Trim(hexID, "0") == ""
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms; keep IDs non-zero regardless
+		b[n-1] = 1
+	}
+	return hex.EncodeToString(b)
+}
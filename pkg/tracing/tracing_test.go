@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRequest_Traceparent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	trace, ok := FromRequest(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", trace.SpanID)
+	assert.True(t, trace.Sampled)
+}
+
+func TestFromRequest_CloudTraceContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(CloudTraceHeader, "105445AA7843BC8BF206B12000100000/1;o=1")
+
+	trace, ok := FromRequest(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "105445aa7843bc8bf206b12000100000", trace.TraceID)
+	assert.Equal(t, "0000000000000001", trace.SpanID)
+	assert.True(t, trace.Sampled)
+}
+
+func TestFromRequest_PrefersTraceparent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	req.Header.Set(CloudTraceHeader, "105445aa7843bc8bf206b12000100000/1;o=1")
+
+	trace, ok := FromRequest(req)
+
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.TraceID)
+	assert.False(t, trace.Sampled)
+}
+
+func TestFromRequest_Invalid(t *testing.T) {
+	for _, header := range []string{
+		"",
+		"garbage",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(TraceparentHeader, header)
+
+		_, ok := FromRequest(req)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestInject_SetsChildSpan(t *testing.T) {
+	parent := Trace{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	req = req.WithContext(NewContext(context.Background(), parent))
+
+	Inject(req)
+
+	child, ok := parseTraceparent(req.Header.Get(TraceparentHeader))
+	assert.True(t, ok)
+	assert.Equal(t, parent.TraceID, child.TraceID)
+	assert.NotEqual(t, parent.SpanID, child.SpanID)
+	assert.Contains(t, req.Header.Get(CloudTraceHeader), parent.TraceID+"/")
+}
+
+func TestInject_WithoutTrace(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+
+	Inject(req)
+
+	assert.Empty(t, req.Header.Get(TraceparentHeader))
+}
+
+func TestCloudTraceContext_RoundTrip(t *testing.T) {
+	trace := New()
+	parsed, ok := parseCloudTrace(trace.CloudTraceContext())
+
+	assert.True(t, ok)
+	assert.Equal(t, trace, parsed)
+}
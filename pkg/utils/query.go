@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -55,6 +56,12 @@ type SortParams struct {
 // Format: ?sort=field:order or ?sort=field (defaults to asc)
 // Example: ?sort=date:desc or ?sort=date
 func ParseSortParam(r *http.Request, defaultField string) SortParams {
+	return ParseSortParamFor(r, defaultField, IsValidSortField)
+}
+
+// ParseSortParamFor is ParseSortParam for resources with their own sortable
+// fields; fields isValid rejects fall back to defaultField
+func ParseSortParamFor(r *http.Request, defaultField string, isValid func(string) bool) SortParams {
 	sortParam := r.URL.Query().Get("sort")
 
 	// Default sorting by date ascending
@@ -77,7 +84,7 @@ func ParseSortParam(r *http.Request, defaultField string) SortParams {
 	}
 
 	// Validate field name (prevent SQL injection)
-	if !IsValidSortField(field) {
+	if !isValid(field) {
 		field = defaultField
 	}
 
@@ -87,38 +94,84 @@ func ParseSortParam(r *http.Request, defaultField string) SortParams {
 	}
 }
 
-// IsValidSortField validates if the field name is allowed for sorting
+// ParseSort parses a "field" or "field:order" sort value as ?sort= takes it,
+// ascending when the order is left out. Unlike ParseSortParam it rejects an
+// invalid field or order instead of replacing it; it returns the zero
+// SortParams, the list's default order, for an empty value.
+func ParseSort(value string, isValid func(string) bool) (SortParams, error) {
+	if value == "" {
+		return SortParams{}, nil
+	}
+	field, order, hasOrder := strings.Cut(value, ":")
+	field = strings.TrimSpace(field)
+	order = strings.ToLower(strings.TrimSpace(order))
+	if !hasOrder {
+		order = "asc"
+	}
+	if !isValid(field) {
+		return SortParams{}, fmt.Errorf("invalid sort field %q", field)
+	}
+	if order != "asc" && order != "desc" {
+		return SortParams{}, fmt.Errorf("invalid sort order %q, use asc or desc", order)
+	}
+	return SortParams{Field: field, Order: order}, nil
+}
+
+// IsValidSortField validates if the field name is allowed for sorting. The
+// ODP and PDP fields only apply to province cases.
 func IsValidSortField(field string) bool {
 	allowedFields := map[string]bool{
-		"date":          true,
-		"day":           true,
-		"positive":      true,
-		"recovered":     true,
-		"deceased":      true,
-		"active":        true,
-		"province_id":   true,
-		"province_name": true,
-		"created_at":    true,
-		"updated_at":    true,
+		"date":                    true,
+		"day":                     true,
+		"positive":                true,
+		"recovered":               true,
+		"deceased":                true,
+		"active":                  true,
+		"cumulative_positive":     true,
+		"cumulative_recovered":    true,
+		"cumulative_deceased":     true,
+		"rt":                      true,
+		"odp":                     true,
+		"odp_finished":            true,
+		"pdp":                     true,
+		"pdp_finished":            true,
+		"cumulative_odp":          true,
+		"cumulative_odp_finished": true,
+		"cumulative_pdp":          true,
+		"cumulative_pdp_finished": true,
+		"province_id":             true,
+		"province_name":           true,
+		"created_at":              true,
+		"updated_at":              true,
 	}
 
 	return allowedFields[field]
 }
 
+// NullsLast orders column in the given direction with NULL values after all
+// others, which MySQL would otherwise put first in ascending order
+func NullsLast(column, order string) string {
+	return column + " IS NULL, " + column + " " + order
+}
+
 // GetSQLOrderClause generates SQL ORDER BY clause from sort parameters
 func (s SortParams) GetSQLOrderClause() string {
 	// Map API field names to database column names
 	fieldMapping := map[string]string{
-		"date":          "date",
-		"day":           "day",
-		"positive":      "positive",
-		"recovered":     "recovered",
-		"deceased":      "deceased",
-		"active":        "active",
-		"province_id":   "province_id",
-		"province_name": "province_name",
-		"created_at":    "created_at",
-		"updated_at":    "updated_at",
+		"date":                 "date",
+		"day":                  "day",
+		"positive":             "positive",
+		"recovered":            "recovered",
+		"deceased":             "deceased",
+		"active":               "active",
+		"cumulative_positive":  "cumulative_positive",
+		"cumulative_recovered": "cumulative_recovered",
+		"cumulative_deceased":  "cumulative_deceased",
+		"rt":                   "rt",
+		"province_id":          "province_id",
+		"province_name":        "province_name",
+		"created_at":           "created_at",
+		"updated_at":           "updated_at",
 	}
 
 	dbField, exists := fieldMapping[s.Field]
@@ -131,16 +184,26 @@ func (s SortParams) GetSQLOrderClause() string {
 		order = "ASC" // default to ASC
 	}
 
+	// Days without an Rt estimate come last in either direction
+	if dbField == "rt" {
+		return NullsLast(dbField, order)
+	}
 	return dbField + " " + order
 }
 
+// Page sizes of paginated lists
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 1000
+)
+
 // ValidatePaginationParams validates and adjusts pagination parameters
 func ValidatePaginationParams(limit, offset int) (int, int) {
 	// Validate limit
 	if limit <= 0 {
-		limit = 50 // Default limit
-	} else if limit > 1000 {
-		limit = 1000 // Max limit
+		limit = DefaultPageLimit
+	} else if limit > MaxPageLimit {
+		limit = MaxPageLimit
 	}
 
 	// Validate offset
@@ -260,6 +260,9 @@ func TestIsValidSortField(t *testing.T) {
 	assert.True(t, IsValidSortField("day"))
 	assert.True(t, IsValidSortField("positive"))
 	assert.True(t, IsValidSortField("province_id"))
+	assert.True(t, IsValidSortField("rt"))
+	assert.True(t, IsValidSortField("cumulative_positive"))
+	assert.True(t, IsValidSortField("cumulative_pdp_finished"))
 	assert.False(t, IsValidSortField("unknown"))
 	assert.False(t, IsValidSortField(""))
 }
@@ -273,4 +276,35 @@ func TestGetSQLOrderClause(t *testing.T) {
 
 	s3 := SortParams{Field: "unknown_field", Order: "asc"}
 	assert.Equal(t, "date ASC", s3.GetSQLOrderClause()) // fallback to date
+
+	s4 := SortParams{Field: "rt", Order: "desc"}
+	assert.Equal(t, "rt IS NULL, rt DESC", s4.GetSQLOrderClause())
+
+	s5 := SortParams{Field: "odp", Order: "asc"}
+	assert.Equal(t, "date ASC", s5.GetSQLOrderClause()) // no ODP in national cases
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    SortParams
+		wantErr bool
+	}{
+		{"", SortParams{}, false},
+		{"positive", SortParams{Field: "positive", Order: "asc"}, false},
+		{" date : DESC ", SortParams{Field: "date", Order: "desc"}, false},
+		{"name", SortParams{}, true},
+		{"date:up", SortParams{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSort(tt.value, IsValidSortField)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
 }
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/httpclient"
+)
+
+// Message is a single signed delivery to one subscriber
+type Message struct {
+	DeliveryID string
+	Event      string
+	Body       []byte
+}
+
+// Result describes the outcome of a delivery attempt
+type Result struct {
+	StatusCode int
+	Duration   time.Duration
+}
+
+// Sender posts signed messages to subscriber URLs
+type Sender struct {
+	client *httpclient.Client
+	now    func() time.Time
+}
+
+// NewSender creates a Sender on top of the shared outbound client. Webhook
+// receivers deduplicate by delivery ID, so the client may retry POSTs.
+func NewSender(client *httpclient.Client) *Sender {
+	return &Sender{client: client, now: time.Now}
+}
+
+// NewDefaultSender creates a Sender with its own "webhooks" outbound client
+func NewDefaultSender() *Sender {
+	opts := httpclient.DefaultOptions()
+	opts.RetryNonIdempotent = true
+	return NewSender(httpclient.New("webhooks", opts))
+}
+
+// Send delivers msg to url signed with secret. Any non-2xx response is an error;
+// the status code is still reported in the result.
+func (s *Sender) Send(ctx context.Context, url, secret string, msg Message) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(msg.Body))
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pico-api-webhooks/1")
+	req.Header.Set(EventHeader, msg.Event)
+	SignRequest(req, secret, msg.DeliveryID, msg.Body, s.now())
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	result := Result{Duration: time.Since(start)}
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+	}()
+
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	return result, nil
+}
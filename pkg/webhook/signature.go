@@ -0,0 +1,193 @@
+// Package webhook signs outgoing webhook deliveries and verifies them on the
+// receiving side. Subscribers written in Go can use Verifier directly; the
+// scheme is documented in WEBHOOKS.md for other languages.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	SignatureHeader = "X-Pico-Signature"
+	TimestampHeader = "X-Pico-Timestamp"
+	DeliveryHeader  = "X-Pico-Delivery"
+	EventHeader     = "X-Pico-Event"
+)
+
+// signatureVersion prefixes the hex digest so the scheme can change without
+// breaking existing verifiers
+const signatureVersion = "v1"
+
+// DefaultTolerance is how far a delivery's timestamp may be from the
+// receiver's clock before it is rejected
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrMissingHeaders is returned when a signature, timestamp or delivery header is absent
+	ErrMissingHeaders = errors.New("webhook signature headers missing")
+	// ErrInvalidSignature is returned when no signature matches the payload
+	ErrInvalidSignature = errors.New("webhook signature does not match payload")
+	// ErrTimestampOutOfRange is returned for deliveries signed too long ago or in the future
+	ErrTimestampOutOfRange = errors.New("webhook timestamp outside tolerance")
+	// ErrReplayed is returned when a delivery ID has already been accepted
+	ErrReplayed = errors.New("webhook delivery already received")
+)
+
+// NewDeliveryID returns a random 32 character hex delivery ID
+func NewDeliveryID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("webhook: failed to read random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// Sign returns the signature header value for a delivery. The HMAC-SHA256 covers
+// "<timestamp>.<deliveryID>.<body>" so neither the timestamp nor the delivery ID
+// can be swapped without invalidating the signature.
+func Sign(secret string, timestamp int64, deliveryID string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(deliveryID))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest sets the signature, timestamp and delivery headers on req
+func SignRequest(req *http.Request, secret, deliveryID string, body []byte, now time.Time) {
+	timestamp := now.Unix()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, deliveryID, body))
+}
+
+// ReplayCache remembers accepted delivery IDs until they expire
+type ReplayCache interface {
+	// Seen records deliveryID and reports whether it was already recorded
+	Seen(deliveryID string, expires time.Time) bool
+}
+
+// Verifier checks deliveries on the subscriber side
+type Verifier struct {
+	// Secrets accepted for the subscription. Pass the old and new secret
+	// while rotating.
+	Secrets []string
+	// Tolerance defaults to DefaultTolerance
+	Tolerance time.Duration
+	// Replays rejects repeated delivery IDs when set
+	Replays ReplayCache
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// NewVerifier creates a Verifier with the default tolerance and an in-memory replay cache
+func NewVerifier(secrets ...string) *Verifier {
+	return &Verifier{Secrets: secrets, Tolerance: DefaultTolerance, Replays: NewMemoryReplayCache()}
+}
+
+// Verify checks the headers of a delivery against its raw body
+func (v *Verifier) Verify(header http.Header, body []byte) error {
+	signatures := header.Get(SignatureHeader)
+	timestampValue := header.Get(TimestampHeader)
+	deliveryID := header.Get(DeliveryHeader)
+	if signatures == "" || timestampValue == "" || deliveryID == "" {
+		return ErrMissingHeaders
+	}
+
+	timestamp, err := strconv.ParseInt(timestampValue, 10, 64)
+	if err != nil {
+		return ErrTimestampOutOfRange
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-tolerance)) || signedAt.After(now.Add(tolerance)) {
+		return ErrTimestampOutOfRange
+	}
+
+	if !v.matches(signatures, timestamp, deliveryID, body) {
+		return ErrInvalidSignature
+	}
+
+	// Only record the ID once the signature is known to be genuine, and keep it
+	// for as long as the timestamp would still be accepted
+	if v.Replays != nil && v.Replays.Seen(deliveryID, signedAt.Add(tolerance)) {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// VerifyRequest reads and verifies the body of an incoming delivery, returning the body
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook body: %w", err)
+	}
+	if err := v.Verify(r.Header, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// matches compares every comma separated signature in the header against every secret
+func (v *Verifier) matches(signatures string, timestamp int64, deliveryID string, body []byte) bool {
+	for _, secret := range v.Secrets {
+		expected := []byte(Sign(secret, timestamp, deliveryID, body))
+		for _, sig := range strings.Split(signatures, ",") {
+			if hmac.Equal([]byte(strings.TrimSpace(sig)), expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MemoryReplayCache is a ReplayCache for a single subscriber process
+type MemoryReplayCache struct {
+	mutex sync.Mutex
+	seen  map[string]time.Time
+	now   func() time.Time
+}
+
+// NewMemoryReplayCache creates an empty MemoryReplayCache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{seen: make(map[string]time.Time), now: time.Now}
+}
+
+// Seen implements ReplayCache. Expired IDs are pruned on each call.
+func (c *MemoryReplayCache) Seen(deliveryID string, expires time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for id, exp := range c.seen {
+		if now.After(exp) {
+			delete(c.seen, id)
+		}
+	}
+	if _, ok := c.seen[deliveryID]; ok {
+		return true
+	}
+	c.seen[deliveryID] = expires
+	return false
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedHeader(secret, deliveryID string, body []byte, at time.Time) http.Header {
+	h := http.Header{}
+	h.Set(TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+	h.Set(DeliveryHeader, deliveryID)
+	h.Set(SignatureHeader, Sign(secret, at.Unix(), deliveryID, body))
+	return h
+}
+
+func TestSign_IsDeterministic(t *testing.T) {
+	a := Sign("secret", 1700000000, "d1", []byte(`{}`))
+	assert.Equal(t, a, Sign("secret", 1700000000, "d1", []byte(`{}`)))
+	assert.Regexp(t, `^v1=[0-9a-f]{64}$`, a)
+	assert.NotEqual(t, a, Sign("secret", 1700000001, "d1", []byte(`{}`)))
+	assert.NotEqual(t, a, Sign("secret", 1700000000, "d2", []byte(`{}`)))
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"sync.completed"}`)
+	v := NewVerifier("secret")
+	v.Now = func() time.Time { return now }
+	v.Replays.(*MemoryReplayCache).now = v.Now
+
+	assert.NoError(t, v.Verify(signedHeader("secret", "d1", body, now), body))
+	assert.ErrorIs(t, v.Verify(signedHeader("secret", "d1", body, now), body), ErrReplayed)
+	assert.ErrorIs(t, v.Verify(signedHeader("other", "d2", body, now), body), ErrInvalidSignature)
+	assert.ErrorIs(t, v.Verify(signedHeader("secret", "d3", body, now), []byte(`{}`)), ErrInvalidSignature)
+	assert.ErrorIs(t, v.Verify(signedHeader("secret", "d4", body, now.Add(-10*time.Minute)), body), ErrTimestampOutOfRange)
+	assert.ErrorIs(t, v.Verify(http.Header{}, body), ErrMissingHeaders)
+}
+
+func TestVerifier_AcceptsRotatedSecret(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{}`)
+	v := NewVerifier("new-secret", "old-secret")
+
+	assert.NoError(t, v.Verify(signedHeader("old-secret", "d1", body, now), body))
+}
+
+func TestVerifier_RejectedSignatureDoesNotConsumeDeliveryID(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{}`)
+	v := NewVerifier("secret")
+
+	assert.ErrorIs(t, v.Verify(signedHeader("forged", "d1", body, now), body), ErrInvalidSignature)
+	assert.NoError(t, v.Verify(signedHeader("secret", "d1", body, now), body))
+}
+
+func TestMemoryReplayCache_Expires(t *testing.T) {
+	now := time.Now()
+	c := NewMemoryReplayCache()
+	c.now = func() time.Time { return now }
+
+	assert.False(t, c.Seen("d1", now.Add(time.Minute)))
+	assert.True(t, c.Seen("d1", now.Add(time.Minute)))
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, c.Seen("d1", now.Add(time.Minute)))
+}
+
+func TestSender_SendSignsDelivery(t *testing.T) {
+	v := NewVerifier("secret")
+	var event string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event = r.Header.Get(EventHeader)
+		if _, err := v.VerifyRequest(r); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewSender(httpclient.New("webhook-test", httpclient.Options{MaxRetries: 0}))
+	result, err := s.Send(context.Background(), srv.URL, "secret", Message{DeliveryID: NewDeliveryID(), Event: "sync.completed", Body: []byte(`{}`)})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, result.StatusCode)
+	assert.Equal(t, "sync.completed", event)
+}
+
+func TestSender_SendReportsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	s := NewSender(httpclient.New("webhook-test-gone", httpclient.Options{MaxRetries: 0}))
+	result, err := s.Send(context.Background(), srv.URL, "secret", Message{DeliveryID: "d1", Body: []byte(`{}`)})
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusGone, result.StatusCode)
+}
@@ -0,0 +1,221 @@
+// Package xlsx writes Office Open XML spreadsheets with plain string, number
+// and date cells, so workbooks can be streamed without a spreadsheet library.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of an .xlsx workbook
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// maxSheetName is the longest sheet name Excel accepts
+const maxSheetName = 31
+
+// Cell values are strings, ints, int64s, float64s, time.Time dates or nil
+// for an empty cell. A nil *float64 is empty as well.
+type Cell = interface{}
+
+// excelEpoch is day zero of the 1900 date system, which counts the
+// nonexistent 29 February 1900 and so starts on 30 December 1899
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// Style indexes into the cellXfs of styles.xml
+const (
+	styleDefault = 0
+	styleDate    = 1
+	styleHeader  = 2
+)
+
+// Writer streams a workbook into a zip archive one sheet at a time. Close
+// must be called to write the workbook parts after the last sheet.
+type Writer struct {
+	zw     *zip.Writer
+	sheets []string
+}
+
+// NewWriter returns a Writer writing the workbook to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// WriteSheet adds a sheet with header as its bold first row followed by rows
+func (w *Writer) WriteSheet(name string, header []string, rows [][]Cell) error {
+	name = sheetName(name)
+	for _, existing := range w.sheets {
+		if strings.EqualFold(existing, name) {
+			return fmt.Errorf("duplicate sheet name %q", name)
+		}
+	}
+	w.sheets = append(w.sheets, name)
+
+	part, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return fmt.Errorf("failed to create sheet %q: %w", name, err)
+	}
+	bw := bufio.NewWriter(part)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(header) > 0 {
+		// Keep the header row in view while scrolling
+		bw.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	bw.WriteString(`<sheetData>`)
+	rowNum := 1
+	if len(header) > 0 {
+		cells := make([]Cell, len(header))
+		for i, h := range header {
+			cells[i] = h
+		}
+		if err := writeRow(bw, rowNum, cells, styleHeader); err != nil {
+			return err
+		}
+		rowNum++
+	}
+	for _, row := range rows {
+		if err := writeRow(bw, rowNum, row, styleDefault); err != nil {
+			return err
+		}
+		rowNum++
+	}
+	bw.WriteString(`</sheetData></worksheet>`)
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write sheet %q: %w", name, err)
+	}
+	return nil
+}
+
+// Close writes the workbook, relationship, content type and style parts and
+// finishes the archive
+func (w *Writer) Close() error {
+	var workbook, rels, types strings.Builder
+	workbook.WriteString(xml.Header)
+	workbook.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	types.WriteString(xml.Header)
+	types.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i, name := range w.sheets {
+		id := i + 1
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(name), id, id)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, id, id)
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, id)
+	}
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`, len(w.sheets)+1)
+	types.WriteString(`</Types>`)
+
+	parts := []struct{ name, content string }{
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", styles},
+		{"_rels/.rels", rootRels},
+		{"[Content_Types].xml", types.String()},
+	}
+	for _, p := range parts {
+		f, err := w.zw.Create(p.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", p.name, err)
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p.name, err)
+		}
+	}
+	return w.zw.Close()
+}
+
+func writeRow(bw *bufio.Writer, rowNum int, cells []Cell, style int) error {
+	fmt.Fprintf(bw, `<row r="%d">`, rowNum)
+	for i, value := range cells {
+		ref := ColumnName(i) + strconv.Itoa(rowNum)
+		switch v := value.(type) {
+		case nil:
+			continue
+		case *float64:
+			if v == nil {
+				continue
+			}
+			fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(*v, 'f', -1, 64))
+		case string:
+			fmt.Fprintf(bw, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, style, escape(v))
+		case int:
+			fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%d</v></c>`, ref, style, v)
+		case int64:
+			fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%d</v></c>`, ref, style, v)
+		case float64:
+			fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			serial := v.Sub(excelEpoch).Hours() / 24
+			fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDate, strconv.FormatFloat(serial, 'f', -1, 64))
+		default:
+			return fmt.Errorf("unsupported cell type %T in %s", value, ref)
+		}
+	}
+	_, err := bw.WriteString(`</row>`)
+	return err
+}
+
+// ColumnName returns the letters of the zero-based column index, e.g. 0 is A
+// and 27 is AB
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sheetName removes the characters Excel rejects in sheet names and
+// shortens the name to 31 characters
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet"
+	}
+	if runes := []rune(name); len(runes) > maxSheetName {
+		name = string(runes[:maxSheetName])
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles defines the default, date (yyyy-mm-dd) and bold header cell formats
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,90 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[f.Name] = string(content)
+
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				require.ErrorIs(t, err, io.EOF, f.Name)
+				break
+			}
+		}
+	}
+	return parts
+}
+
+func TestWriter(t *testing.T) {
+	rt := 1.05
+	var unknown *float64
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.WriteSheet("Daily", []string{"date", "positive", "rt", "province"}, [][]Cell{
+		{time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC), int64(120), &rt, "Sulawesi <Tengah> & co"},
+		{time.Date(2021, 6, 16, 0, 0, 0, 0, time.UTC), 80, unknown, nil},
+	}))
+	require.NoError(t, w.WriteSheet("Cumulative: all/total", nil, [][]Cell{{12.5}}))
+	require.NoError(t, w.Close())
+
+	parts := readParts(t, buf.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" s="2" t="inlineStr"><is><t>date</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2" s="1"><v>44362</v></c>`)
+	assert.Contains(t, sheet, `<c r="B2" s="0"><v>120</v></c>`)
+	assert.Contains(t, sheet, `<c r="C2" s="0"><v>1.05</v></c>`)
+	assert.Contains(t, sheet, `<t>Sulawesi &lt;Tengah&gt; &amp; co</t>`)
+	assert.Contains(t, sheet, `<row r="3"><c r="A3" s="1"><v>44363</v></c><c r="B3" s="0"><v>80</v></c></row>`)
+	assert.Contains(t, sheet, `state="frozen"`)
+
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], `<row r="1"><c r="A1" s="0"><v>12.5</v></c></row>`)
+	assert.NotContains(t, parts["xl/worksheets/sheet2.xml"], `frozen`)
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Daily" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Cumulative alltotal" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, parts["xl/_rels/workbook.xml.rels"], `Target="styles.xml"`)
+	assert.Contains(t, parts["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+}
+
+func TestWriter_Errors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	require.NoError(t, w.WriteSheet("Daily", nil, nil))
+	assert.Error(t, w.WriteSheet("daily", nil, nil))
+	assert.Error(t, w.WriteSheet("Other", nil, [][]Cell{{struct{}{}}}))
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, want, ColumnName(index))
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	assert.Equal(t, "Sheet", sheetName("[]"))
+	assert.Equal(t, "Provinsi Sulawesi Tengah Harian", sheetName("Provinsi Sulawesi Tengah Harian 2021"))
+}
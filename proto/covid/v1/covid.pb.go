@@ -0,0 +1,1309 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: covid/v1/covid.proto
+
+package covidv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CaseQuery selects cases as the query parameters of the case list endpoints.
+// Every field is optional; an invalid one fails with INVALID_ARGUMENT.
+type CaseQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date and end_date bound the cases as YYYY-MM-DD; they only apply
+	// when both are set
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// range is a date range preset such as last30d, which cannot be combined
+	// with start_date or end_date
+	Range string `protobuf:"bytes,3,opt,name=range,proto3" json:"range,omitempty"`
+	// sort is "field" or "field:order" as in ?sort=, e.g. "positive:desc"
+	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaseQuery) Reset() {
+	*x = CaseQuery{}
+	mi := &file_covid_v1_covid_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaseQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseQuery) ProtoMessage() {}
+
+func (x *CaseQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseQuery.ProtoReflect.Descriptor instead.
+func (*CaseQuery) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CaseQuery) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *CaseQuery) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *CaseQuery) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+func (x *CaseQuery) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ListNationalCasesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query *CaseQuery             `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// limit defaults to 50 and is capped as on /national; offset skips cases
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNationalCasesRequest) Reset() {
+	*x = ListNationalCasesRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNationalCasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNationalCasesRequest) ProtoMessage() {}
+
+func (x *ListNationalCasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNationalCasesRequest.ProtoReflect.Descriptor instead.
+func (*ListNationalCasesRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListNationalCasesRequest) GetQuery() *CaseQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *ListNationalCasesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListNationalCasesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListNationalCasesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Cases []*NationalCase        `protobuf:"bytes,1,rep,name=cases,proto3" json:"cases,omitempty"`
+	// total counts the cases of every page
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNationalCasesResponse) Reset() {
+	*x = ListNationalCasesResponse{}
+	mi := &file_covid_v1_covid_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNationalCasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNationalCasesResponse) ProtoMessage() {}
+
+func (x *ListNationalCasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNationalCasesResponse.ProtoReflect.Descriptor instead.
+func (*ListNationalCasesResponse) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListNationalCasesResponse) GetCases() []*NationalCase {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+func (x *ListNationalCasesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type StreamNationalCasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         *CaseQuery             `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNationalCasesRequest) Reset() {
+	*x = StreamNationalCasesRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNationalCasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNationalCasesRequest) ProtoMessage() {}
+
+func (x *StreamNationalCasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNationalCasesRequest.ProtoReflect.Descriptor instead.
+func (*StreamNationalCasesRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamNationalCasesRequest) GetQuery() *CaseQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type GetLatestNationalCaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestNationalCaseRequest) Reset() {
+	*x = GetLatestNationalCaseRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestNationalCaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestNationalCaseRequest) ProtoMessage() {}
+
+func (x *GetLatestNationalCaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestNationalCaseRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestNationalCaseRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{4}
+}
+
+type GetNationalCaseByDayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           int64                  `protobuf:"varint,1,opt,name=day,proto3" json:"day,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNationalCaseByDayRequest) Reset() {
+	*x = GetNationalCaseByDayRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNationalCaseByDayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNationalCaseByDayRequest) ProtoMessage() {}
+
+func (x *GetNationalCaseByDayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNationalCaseByDayRequest.ProtoReflect.Descriptor instead.
+func (*GetNationalCaseByDayRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetNationalCaseByDayRequest) GetDay() int64 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+type ListProvincesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProvincesRequest) Reset() {
+	*x = ListProvincesRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProvincesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvincesRequest) ProtoMessage() {}
+
+func (x *ListProvincesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvincesRequest.ProtoReflect.Descriptor instead.
+func (*ListProvincesRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{6}
+}
+
+type ListProvincesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provinces     []*Province            `protobuf:"bytes,1,rep,name=provinces,proto3" json:"provinces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProvincesResponse) Reset() {
+	*x = ListProvincesResponse{}
+	mi := &file_covid_v1_covid_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProvincesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvincesResponse) ProtoMessage() {}
+
+func (x *ListProvincesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvincesResponse.ProtoReflect.Descriptor instead.
+func (*ListProvincesResponse) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListProvincesResponse) GetProvinces() []*Province {
+	if x != nil {
+		return x.Provinces
+	}
+	return nil
+}
+
+type GetProvinceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProvinceRequest) Reset() {
+	*x = GetProvinceRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProvinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvinceRequest) ProtoMessage() {}
+
+func (x *GetProvinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvinceRequest.ProtoReflect.Descriptor instead.
+func (*GetProvinceRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetProvinceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListProvinceCasesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// province_id limits the cases to one province; all provinces when empty
+	ProvinceId    string     `protobuf:"bytes,1,opt,name=province_id,json=provinceId,proto3" json:"province_id,omitempty"`
+	Query         *CaseQuery `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32      `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32      `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProvinceCasesRequest) Reset() {
+	*x = ListProvinceCasesRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProvinceCasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvinceCasesRequest) ProtoMessage() {}
+
+func (x *ListProvinceCasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvinceCasesRequest.ProtoReflect.Descriptor instead.
+func (*ListProvinceCasesRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListProvinceCasesRequest) GetProvinceId() string {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return ""
+}
+
+func (x *ListProvinceCasesRequest) GetQuery() *CaseQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *ListProvinceCasesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListProvinceCasesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListProvinceCasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cases         []*ProvinceCase        `protobuf:"bytes,1,rep,name=cases,proto3" json:"cases,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProvinceCasesResponse) Reset() {
+	*x = ListProvinceCasesResponse{}
+	mi := &file_covid_v1_covid_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProvinceCasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvinceCasesResponse) ProtoMessage() {}
+
+func (x *ListProvinceCasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvinceCasesResponse.ProtoReflect.Descriptor instead.
+func (*ListProvinceCasesResponse) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListProvinceCasesResponse) GetCases() []*ProvinceCase {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+func (x *ListProvinceCasesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type StreamProvinceCasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProvinceId    string                 `protobuf:"bytes,1,opt,name=province_id,json=provinceId,proto3" json:"province_id,omitempty"`
+	Query         *CaseQuery             `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamProvinceCasesRequest) Reset() {
+	*x = StreamProvinceCasesRequest{}
+	mi := &file_covid_v1_covid_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamProvinceCasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamProvinceCasesRequest) ProtoMessage() {}
+
+func (x *StreamProvinceCasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamProvinceCasesRequest.ProtoReflect.Descriptor instead.
+func (*StreamProvinceCasesRequest) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StreamProvinceCasesRequest) GetProvinceId() string {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return ""
+}
+
+func (x *StreamProvinceCasesRequest) GetQuery() *CaseQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type Province struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Province) Reset() {
+	*x = Province{}
+	mi := &file_covid_v1_covid_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Province) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Province) ProtoMessage() {}
+
+func (x *Province) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Province.ProtoReflect.Descriptor instead.
+func (*Province) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Province) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Province) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// DailyTests are the PCR and antigen tests reported for a day
+type DailyTests struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Pcr               int64                  `protobuf:"varint,1,opt,name=pcr,proto3" json:"pcr,omitempty"`
+	Antigen           int64                  `protobuf:"varint,2,opt,name=antigen,proto3" json:"antigen,omitempty"`
+	CumulativePcr     int64                  `protobuf:"varint,3,opt,name=cumulative_pcr,json=cumulativePcr,proto3" json:"cumulative_pcr,omitempty"`
+	CumulativeAntigen int64                  `protobuf:"varint,4,opt,name=cumulative_antigen,json=cumulativeAntigen,proto3" json:"cumulative_antigen,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DailyTests) Reset() {
+	*x = DailyTests{}
+	mi := &file_covid_v1_covid_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyTests) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyTests) ProtoMessage() {}
+
+func (x *DailyTests) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyTests.ProtoReflect.Descriptor instead.
+func (*DailyTests) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DailyTests) GetPcr() int64 {
+	if x != nil {
+		return x.Pcr
+	}
+	return 0
+}
+
+func (x *DailyTests) GetAntigen() int64 {
+	if x != nil {
+		return x.Antigen
+	}
+	return 0
+}
+
+func (x *DailyTests) GetCumulativePcr() int64 {
+	if x != nil {
+		return x.CumulativePcr
+	}
+	return 0
+}
+
+func (x *DailyTests) GetCumulativeAntigen() int64 {
+	if x != nil {
+		return x.CumulativeAntigen
+	}
+	return 0
+}
+
+type NationalCase struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Day                 int64                  `protobuf:"varint,2,opt,name=day,proto3" json:"day,omitempty"`
+	Date                *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	Positive            int64                  `protobuf:"varint,4,opt,name=positive,proto3" json:"positive,omitempty"`
+	Recovered           int64                  `protobuf:"varint,5,opt,name=recovered,proto3" json:"recovered,omitempty"`
+	Deceased            int64                  `protobuf:"varint,6,opt,name=deceased,proto3" json:"deceased,omitempty"`
+	CumulativePositive  int64                  `protobuf:"varint,7,opt,name=cumulative_positive,json=cumulativePositive,proto3" json:"cumulative_positive,omitempty"`
+	CumulativeRecovered int64                  `protobuf:"varint,8,opt,name=cumulative_recovered,json=cumulativeRecovered,proto3" json:"cumulative_recovered,omitempty"`
+	CumulativeDeceased  int64                  `protobuf:"varint,9,opt,name=cumulative_deceased,json=cumulativeDeceased,proto3" json:"cumulative_deceased,omitempty"`
+	// The reproduction rate and its bounds are unset when not estimated
+	Rt      *float64 `protobuf:"fixed64,10,opt,name=rt,proto3,oneof" json:"rt,omitempty"`
+	RtUpper *float64 `protobuf:"fixed64,11,opt,name=rt_upper,json=rtUpper,proto3,oneof" json:"rt_upper,omitempty"`
+	RtLower *float64 `protobuf:"fixed64,12,opt,name=rt_lower,json=rtLower,proto3,oneof" json:"rt_lower,omitempty"`
+	// tests is unset when the day's tests were not reported
+	Tests         *DailyTests `protobuf:"bytes,13,opt,name=tests,proto3" json:"tests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NationalCase) Reset() {
+	*x = NationalCase{}
+	mi := &file_covid_v1_covid_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NationalCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NationalCase) ProtoMessage() {}
+
+func (x *NationalCase) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NationalCase.ProtoReflect.Descriptor instead.
+func (*NationalCase) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *NationalCase) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NationalCase) GetDay() int64 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *NationalCase) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *NationalCase) GetPositive() int64 {
+	if x != nil {
+		return x.Positive
+	}
+	return 0
+}
+
+func (x *NationalCase) GetRecovered() int64 {
+	if x != nil {
+		return x.Recovered
+	}
+	return 0
+}
+
+func (x *NationalCase) GetDeceased() int64 {
+	if x != nil {
+		return x.Deceased
+	}
+	return 0
+}
+
+func (x *NationalCase) GetCumulativePositive() int64 {
+	if x != nil {
+		return x.CumulativePositive
+	}
+	return 0
+}
+
+func (x *NationalCase) GetCumulativeRecovered() int64 {
+	if x != nil {
+		return x.CumulativeRecovered
+	}
+	return 0
+}
+
+func (x *NationalCase) GetCumulativeDeceased() int64 {
+	if x != nil {
+		return x.CumulativeDeceased
+	}
+	return 0
+}
+
+func (x *NationalCase) GetRt() float64 {
+	if x != nil && x.Rt != nil {
+		return *x.Rt
+	}
+	return 0
+}
+
+func (x *NationalCase) GetRtUpper() float64 {
+	if x != nil && x.RtUpper != nil {
+		return *x.RtUpper
+	}
+	return 0
+}
+
+func (x *NationalCase) GetRtLower() float64 {
+	if x != nil && x.RtLower != nil {
+		return *x.RtLower
+	}
+	return 0
+}
+
+func (x *NationalCase) GetTests() *DailyTests {
+	if x != nil {
+		return x.Tests
+	}
+	return nil
+}
+
+// ProvinceCase is a day of a province, with the ODP (person under
+// observation) and PDP (person under supervision) counts of 2020
+type ProvinceCase struct {
+	state                                    protoimpl.MessageState `protogen:"open.v1"`
+	Id                                       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Day                                      int64                  `protobuf:"varint,2,opt,name=day,proto3" json:"day,omitempty"`
+	Date                                     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	Province                                 *Province              `protobuf:"bytes,4,opt,name=province,proto3" json:"province,omitempty"`
+	Positive                                 int64                  `protobuf:"varint,5,opt,name=positive,proto3" json:"positive,omitempty"`
+	Recovered                                int64                  `protobuf:"varint,6,opt,name=recovered,proto3" json:"recovered,omitempty"`
+	Deceased                                 int64                  `protobuf:"varint,7,opt,name=deceased,proto3" json:"deceased,omitempty"`
+	PersonUnderObservation                   int64                  `protobuf:"varint,8,opt,name=person_under_observation,json=personUnderObservation,proto3" json:"person_under_observation,omitempty"`
+	FinishedPersonUnderObservation           int64                  `protobuf:"varint,9,opt,name=finished_person_under_observation,json=finishedPersonUnderObservation,proto3" json:"finished_person_under_observation,omitempty"`
+	PersonUnderSupervision                   int64                  `protobuf:"varint,10,opt,name=person_under_supervision,json=personUnderSupervision,proto3" json:"person_under_supervision,omitempty"`
+	FinishedPersonUnderSupervision           int64                  `protobuf:"varint,11,opt,name=finished_person_under_supervision,json=finishedPersonUnderSupervision,proto3" json:"finished_person_under_supervision,omitempty"`
+	CumulativePositive                       int64                  `protobuf:"varint,12,opt,name=cumulative_positive,json=cumulativePositive,proto3" json:"cumulative_positive,omitempty"`
+	CumulativeRecovered                      int64                  `protobuf:"varint,13,opt,name=cumulative_recovered,json=cumulativeRecovered,proto3" json:"cumulative_recovered,omitempty"`
+	CumulativeDeceased                       int64                  `protobuf:"varint,14,opt,name=cumulative_deceased,json=cumulativeDeceased,proto3" json:"cumulative_deceased,omitempty"`
+	CumulativePersonUnderObservation         int64                  `protobuf:"varint,15,opt,name=cumulative_person_under_observation,json=cumulativePersonUnderObservation,proto3" json:"cumulative_person_under_observation,omitempty"`
+	CumulativeFinishedPersonUnderObservation int64                  `protobuf:"varint,16,opt,name=cumulative_finished_person_under_observation,json=cumulativeFinishedPersonUnderObservation,proto3" json:"cumulative_finished_person_under_observation,omitempty"`
+	CumulativePersonUnderSupervision         int64                  `protobuf:"varint,17,opt,name=cumulative_person_under_supervision,json=cumulativePersonUnderSupervision,proto3" json:"cumulative_person_under_supervision,omitempty"`
+	CumulativeFinishedPersonUnderSupervision int64                  `protobuf:"varint,18,opt,name=cumulative_finished_person_under_supervision,json=cumulativeFinishedPersonUnderSupervision,proto3" json:"cumulative_finished_person_under_supervision,omitempty"`
+	Rt                                       *float64               `protobuf:"fixed64,19,opt,name=rt,proto3,oneof" json:"rt,omitempty"`
+	RtUpper                                  *float64               `protobuf:"fixed64,20,opt,name=rt_upper,json=rtUpper,proto3,oneof" json:"rt_upper,omitempty"`
+	RtLower                                  *float64               `protobuf:"fixed64,21,opt,name=rt_lower,json=rtLower,proto3,oneof" json:"rt_lower,omitempty"`
+	Tests                                    *DailyTests            `protobuf:"bytes,22,opt,name=tests,proto3" json:"tests,omitempty"`
+	unknownFields                            protoimpl.UnknownFields
+	sizeCache                                protoimpl.SizeCache
+}
+
+func (x *ProvinceCase) Reset() {
+	*x = ProvinceCase{}
+	mi := &file_covid_v1_covid_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProvinceCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProvinceCase) ProtoMessage() {}
+
+func (x *ProvinceCase) ProtoReflect() protoreflect.Message {
+	mi := &file_covid_v1_covid_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProvinceCase.ProtoReflect.Descriptor instead.
+func (*ProvinceCase) Descriptor() ([]byte, []int) {
+	return file_covid_v1_covid_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ProvinceCase) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetDay() int64 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ProvinceCase) GetProvince() *Province {
+	if x != nil {
+		return x.Province
+	}
+	return nil
+}
+
+func (x *ProvinceCase) GetPositive() int64 {
+	if x != nil {
+		return x.Positive
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetRecovered() int64 {
+	if x != nil {
+		return x.Recovered
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetDeceased() int64 {
+	if x != nil {
+		return x.Deceased
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetPersonUnderObservation() int64 {
+	if x != nil {
+		return x.PersonUnderObservation
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetFinishedPersonUnderObservation() int64 {
+	if x != nil {
+		return x.FinishedPersonUnderObservation
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetPersonUnderSupervision() int64 {
+	if x != nil {
+		return x.PersonUnderSupervision
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetFinishedPersonUnderSupervision() int64 {
+	if x != nil {
+		return x.FinishedPersonUnderSupervision
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativePositive() int64 {
+	if x != nil {
+		return x.CumulativePositive
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativeRecovered() int64 {
+	if x != nil {
+		return x.CumulativeRecovered
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativeDeceased() int64 {
+	if x != nil {
+		return x.CumulativeDeceased
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativePersonUnderObservation() int64 {
+	if x != nil {
+		return x.CumulativePersonUnderObservation
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativeFinishedPersonUnderObservation() int64 {
+	if x != nil {
+		return x.CumulativeFinishedPersonUnderObservation
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativePersonUnderSupervision() int64 {
+	if x != nil {
+		return x.CumulativePersonUnderSupervision
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetCumulativeFinishedPersonUnderSupervision() int64 {
+	if x != nil {
+		return x.CumulativeFinishedPersonUnderSupervision
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetRt() float64 {
+	if x != nil && x.Rt != nil {
+		return *x.Rt
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetRtUpper() float64 {
+	if x != nil && x.RtUpper != nil {
+		return *x.RtUpper
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetRtLower() float64 {
+	if x != nil && x.RtLower != nil {
+		return *x.RtLower
+	}
+	return 0
+}
+
+func (x *ProvinceCase) GetTests() *DailyTests {
+	if x != nil {
+		return x.Tests
+	}
+	return nil
+}
+
+var File_covid_v1_covid_proto protoreflect.FileDescriptor
+
+const file_covid_v1_covid_proto_rawDesc = "" +
+	"\n" +
+	"\x14covid/v1/covid.proto\x12\bcovid.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"o\n" +
+	"\tCaseQuery\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x14\n" +
+	"\x05range\x18\x03 \x01(\tR\x05range\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\"s\n" +
+	"\x18ListNationalCasesRequest\x12)\n" +
+	"\x05query\x18\x01 \x01(\v2\x13.covid.v1.CaseQueryR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"_\n" +
+	"\x19ListNationalCasesResponse\x12,\n" +
+	"\x05cases\x18\x01 \x03(\v2\x16.covid.v1.NationalCaseR\x05cases\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"G\n" +
+	"\x1aStreamNationalCasesRequest\x12)\n" +
+	"\x05query\x18\x01 \x01(\v2\x13.covid.v1.CaseQueryR\x05query\"\x1e\n" +
+	"\x1cGetLatestNationalCaseRequest\"/\n" +
+	"\x1bGetNationalCaseByDayRequest\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\x03R\x03day\"\x16\n" +
+	"\x14ListProvincesRequest\"I\n" +
+	"\x15ListProvincesResponse\x120\n" +
+	"\tprovinces\x18\x01 \x03(\v2\x12.covid.v1.ProvinceR\tprovinces\"$\n" +
+	"\x12GetProvinceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x94\x01\n" +
+	"\x18ListProvinceCasesRequest\x12\x1f\n" +
+	"\vprovince_id\x18\x01 \x01(\tR\n" +
+	"provinceId\x12)\n" +
+	"\x05query\x18\x02 \x01(\v2\x13.covid.v1.CaseQueryR\x05query\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"_\n" +
+	"\x19ListProvinceCasesResponse\x12,\n" +
+	"\x05cases\x18\x01 \x03(\v2\x16.covid.v1.ProvinceCaseR\x05cases\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"h\n" +
+	"\x1aStreamProvinceCasesRequest\x12\x1f\n" +
+	"\vprovince_id\x18\x01 \x01(\tR\n" +
+	"provinceId\x12)\n" +
+	"\x05query\x18\x02 \x01(\v2\x13.covid.v1.CaseQueryR\x05query\".\n" +
+	"\bProvince\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x8e\x01\n" +
+	"\n" +
+	"DailyTests\x12\x10\n" +
+	"\x03pcr\x18\x01 \x01(\x03R\x03pcr\x12\x18\n" +
+	"\aantigen\x18\x02 \x01(\x03R\aantigen\x12%\n" +
+	"\x0ecumulative_pcr\x18\x03 \x01(\x03R\rcumulativePcr\x12-\n" +
+	"\x12cumulative_antigen\x18\x04 \x01(\x03R\x11cumulativeAntigen\"\xed\x03\n" +
+	"\fNationalCase\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03day\x18\x02 \x01(\x03R\x03day\x12.\n" +
+	"\x04date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x1a\n" +
+	"\bpositive\x18\x04 \x01(\x03R\bpositive\x12\x1c\n" +
+	"\trecovered\x18\x05 \x01(\x03R\trecovered\x12\x1a\n" +
+	"\bdeceased\x18\x06 \x01(\x03R\bdeceased\x12/\n" +
+	"\x13cumulative_positive\x18\a \x01(\x03R\x12cumulativePositive\x121\n" +
+	"\x14cumulative_recovered\x18\b \x01(\x03R\x13cumulativeRecovered\x12/\n" +
+	"\x13cumulative_deceased\x18\t \x01(\x03R\x12cumulativeDeceased\x12\x13\n" +
+	"\x02rt\x18\n" +
+	" \x01(\x01H\x00R\x02rt\x88\x01\x01\x12\x1e\n" +
+	"\brt_upper\x18\v \x01(\x01H\x01R\artUpper\x88\x01\x01\x12\x1e\n" +
+	"\brt_lower\x18\f \x01(\x01H\x02R\artLower\x88\x01\x01\x12*\n" +
+	"\x05tests\x18\r \x01(\v2\x14.covid.v1.DailyTestsR\x05testsB\x05\n" +
+	"\x03_rtB\v\n" +
+	"\t_rt_upperB\v\n" +
+	"\t_rt_lower\"\x85\t\n" +
+	"\fProvinceCase\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03day\x18\x02 \x01(\x03R\x03day\x12.\n" +
+	"\x04date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12.\n" +
+	"\bprovince\x18\x04 \x01(\v2\x12.covid.v1.ProvinceR\bprovince\x12\x1a\n" +
+	"\bpositive\x18\x05 \x01(\x03R\bpositive\x12\x1c\n" +
+	"\trecovered\x18\x06 \x01(\x03R\trecovered\x12\x1a\n" +
+	"\bdeceased\x18\a \x01(\x03R\bdeceased\x128\n" +
+	"\x18person_under_observation\x18\b \x01(\x03R\x16personUnderObservation\x12I\n" +
+	"!finished_person_under_observation\x18\t \x01(\x03R\x1efinishedPersonUnderObservation\x128\n" +
+	"\x18person_under_supervision\x18\n" +
+	" \x01(\x03R\x16personUnderSupervision\x12I\n" +
+	"!finished_person_under_supervision\x18\v \x01(\x03R\x1efinishedPersonUnderSupervision\x12/\n" +
+	"\x13cumulative_positive\x18\f \x01(\x03R\x12cumulativePositive\x121\n" +
+	"\x14cumulative_recovered\x18\r \x01(\x03R\x13cumulativeRecovered\x12/\n" +
+	"\x13cumulative_deceased\x18\x0e \x01(\x03R\x12cumulativeDeceased\x12M\n" +
+	"#cumulative_person_under_observation\x18\x0f \x01(\x03R cumulativePersonUnderObservation\x12^\n" +
+	",cumulative_finished_person_under_observation\x18\x10 \x01(\x03R(cumulativeFinishedPersonUnderObservation\x12M\n" +
+	"#cumulative_person_under_supervision\x18\x11 \x01(\x03R cumulativePersonUnderSupervision\x12^\n" +
+	",cumulative_finished_person_under_supervision\x18\x12 \x01(\x03R(cumulativeFinishedPersonUnderSupervision\x12\x13\n" +
+	"\x02rt\x18\x13 \x01(\x01H\x00R\x02rt\x88\x01\x01\x12\x1e\n" +
+	"\brt_upper\x18\x14 \x01(\x01H\x01R\artUpper\x88\x01\x01\x12\x1e\n" +
+	"\brt_lower\x18\x15 \x01(\x01H\x02R\artLower\x88\x01\x01\x12*\n" +
+	"\x05tests\x18\x16 \x01(\v2\x14.covid.v1.DailyTestsR\x05testsB\x05\n" +
+	"\x03_rtB\v\n" +
+	"\t_rt_upperB\v\n" +
+	"\t_rt_lower2\xbb\x05\n" +
+	"\fCovidService\x12\\\n" +
+	"\x11ListNationalCases\x12\".covid.v1.ListNationalCasesRequest\x1a#.covid.v1.ListNationalCasesResponse\x12U\n" +
+	"\x13StreamNationalCases\x12$.covid.v1.StreamNationalCasesRequest\x1a\x16.covid.v1.NationalCase0\x01\x12W\n" +
+	"\x15GetLatestNationalCase\x12&.covid.v1.GetLatestNationalCaseRequest\x1a\x16.covid.v1.NationalCase\x12U\n" +
+	"\x14GetNationalCaseByDay\x12%.covid.v1.GetNationalCaseByDayRequest\x1a\x16.covid.v1.NationalCase\x12P\n" +
+	"\rListProvinces\x12\x1e.covid.v1.ListProvincesRequest\x1a\x1f.covid.v1.ListProvincesResponse\x12?\n" +
+	"\vGetProvince\x12\x1c.covid.v1.GetProvinceRequest\x1a\x12.covid.v1.Province\x12\\\n" +
+	"\x11ListProvinceCases\x12\".covid.v1.ListProvinceCasesRequest\x1a#.covid.v1.ListProvinceCasesResponse\x12U\n" +
+	"\x13StreamProvinceCases\x12$.covid.v1.StreamProvinceCasesRequest\x1a\x16.covid.v1.ProvinceCase0\x01B;Z9github.com/banua-coder/pico-api-go/proto/covid/v1;covidv1b\x06proto3"
+
+var (
+	file_covid_v1_covid_proto_rawDescOnce sync.Once
+	file_covid_v1_covid_proto_rawDescData []byte
+)
+
+func file_covid_v1_covid_proto_rawDescGZIP() []byte {
+	file_covid_v1_covid_proto_rawDescOnce.Do(func() {
+		file_covid_v1_covid_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_covid_v1_covid_proto_rawDesc), len(file_covid_v1_covid_proto_rawDesc)))
+	})
+	return file_covid_v1_covid_proto_rawDescData
+}
+
+var file_covid_v1_covid_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_covid_v1_covid_proto_goTypes = []any{
+	(*CaseQuery)(nil),                    // 0: covid.v1.CaseQuery
+	(*ListNationalCasesRequest)(nil),     // 1: covid.v1.ListNationalCasesRequest
+	(*ListNationalCasesResponse)(nil),    // 2: covid.v1.ListNationalCasesResponse
+	(*StreamNationalCasesRequest)(nil),   // 3: covid.v1.StreamNationalCasesRequest
+	(*GetLatestNationalCaseRequest)(nil), // 4: covid.v1.GetLatestNationalCaseRequest
+	(*GetNationalCaseByDayRequest)(nil),  // 5: covid.v1.GetNationalCaseByDayRequest
+	(*ListProvincesRequest)(nil),         // 6: covid.v1.ListProvincesRequest
+	(*ListProvincesResponse)(nil),        // 7: covid.v1.ListProvincesResponse
+	(*GetProvinceRequest)(nil),           // 8: covid.v1.GetProvinceRequest
+	(*ListProvinceCasesRequest)(nil),     // 9: covid.v1.ListProvinceCasesRequest
+	(*ListProvinceCasesResponse)(nil),    // 10: covid.v1.ListProvinceCasesResponse
+	(*StreamProvinceCasesRequest)(nil),   // 11: covid.v1.StreamProvinceCasesRequest
+	(*Province)(nil),                     // 12: covid.v1.Province
+	(*DailyTests)(nil),                   // 13: covid.v1.DailyTests
+	(*NationalCase)(nil),                 // 14: covid.v1.NationalCase
+	(*ProvinceCase)(nil),                 // 15: covid.v1.ProvinceCase
+	(*timestamppb.Timestamp)(nil),        // 16: google.protobuf.Timestamp
+}
+var file_covid_v1_covid_proto_depIdxs = []int32{
+	0,  // 0: covid.v1.ListNationalCasesRequest.query:type_name -> covid.v1.CaseQuery
+	14, // 1: covid.v1.ListNationalCasesResponse.cases:type_name -> covid.v1.NationalCase
+	0,  // 2: covid.v1.StreamNationalCasesRequest.query:type_name -> covid.v1.CaseQuery
+	12, // 3: covid.v1.ListProvincesResponse.provinces:type_name -> covid.v1.Province
+	0,  // 4: covid.v1.ListProvinceCasesRequest.query:type_name -> covid.v1.CaseQuery
+	15, // 5: covid.v1.ListProvinceCasesResponse.cases:type_name -> covid.v1.ProvinceCase
+	0,  // 6: covid.v1.StreamProvinceCasesRequest.query:type_name -> covid.v1.CaseQuery
+	16, // 7: covid.v1.NationalCase.date:type_name -> google.protobuf.Timestamp
+	13, // 8: covid.v1.NationalCase.tests:type_name -> covid.v1.DailyTests
+	16, // 9: covid.v1.ProvinceCase.date:type_name -> google.protobuf.Timestamp
+	12, // 10: covid.v1.ProvinceCase.province:type_name -> covid.v1.Province
+	13, // 11: covid.v1.ProvinceCase.tests:type_name -> covid.v1.DailyTests
+	1,  // 12: covid.v1.CovidService.ListNationalCases:input_type -> covid.v1.ListNationalCasesRequest
+	3,  // 13: covid.v1.CovidService.StreamNationalCases:input_type -> covid.v1.StreamNationalCasesRequest
+	4,  // 14: covid.v1.CovidService.GetLatestNationalCase:input_type -> covid.v1.GetLatestNationalCaseRequest
+	5,  // 15: covid.v1.CovidService.GetNationalCaseByDay:input_type -> covid.v1.GetNationalCaseByDayRequest
+	6,  // 16: covid.v1.CovidService.ListProvinces:input_type -> covid.v1.ListProvincesRequest
+	8,  // 17: covid.v1.CovidService.GetProvince:input_type -> covid.v1.GetProvinceRequest
+	9,  // 18: covid.v1.CovidService.ListProvinceCases:input_type -> covid.v1.ListProvinceCasesRequest
+	11, // 19: covid.v1.CovidService.StreamProvinceCases:input_type -> covid.v1.StreamProvinceCasesRequest
+	2,  // 20: covid.v1.CovidService.ListNationalCases:output_type -> covid.v1.ListNationalCasesResponse
+	14, // 21: covid.v1.CovidService.StreamNationalCases:output_type -> covid.v1.NationalCase
+	14, // 22: covid.v1.CovidService.GetLatestNationalCase:output_type -> covid.v1.NationalCase
+	14, // 23: covid.v1.CovidService.GetNationalCaseByDay:output_type -> covid.v1.NationalCase
+	7,  // 24: covid.v1.CovidService.ListProvinces:output_type -> covid.v1.ListProvincesResponse
+	12, // 25: covid.v1.CovidService.GetProvince:output_type -> covid.v1.Province
+	10, // 26: covid.v1.CovidService.ListProvinceCases:output_type -> covid.v1.ListProvinceCasesResponse
+	15, // 27: covid.v1.CovidService.StreamProvinceCases:output_type -> covid.v1.ProvinceCase
+	20, // [20:28] is the sub-list for method output_type
+	12, // [12:20] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_covid_v1_covid_proto_init() }
+func file_covid_v1_covid_proto_init() {
+	if File_covid_v1_covid_proto != nil {
+		return
+	}
+	file_covid_v1_covid_proto_msgTypes[14].OneofWrappers = []any{}
+	file_covid_v1_covid_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_covid_v1_covid_proto_rawDesc), len(file_covid_v1_covid_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_covid_v1_covid_proto_goTypes,
+		DependencyIndexes: file_covid_v1_covid_proto_depIdxs,
+		MessageInfos:      file_covid_v1_covid_proto_msgTypes,
+	}.Build()
+	File_covid_v1_covid_proto = out.File
+	file_covid_v1_covid_proto_goTypes = nil
+	file_covid_v1_covid_proto_depIdxs = nil
+}
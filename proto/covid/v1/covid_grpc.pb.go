@@ -0,0 +1,418 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: covid/v1/covid.proto
+
+package covidv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CovidService_ListNationalCases_FullMethodName     = "/covid.v1.CovidService/ListNationalCases"
+	CovidService_StreamNationalCases_FullMethodName   = "/covid.v1.CovidService/StreamNationalCases"
+	CovidService_GetLatestNationalCase_FullMethodName = "/covid.v1.CovidService/GetLatestNationalCase"
+	CovidService_GetNationalCaseByDay_FullMethodName  = "/covid.v1.CovidService/GetNationalCaseByDay"
+	CovidService_ListProvinces_FullMethodName         = "/covid.v1.CovidService/ListProvinces"
+	CovidService_GetProvince_FullMethodName           = "/covid.v1.CovidService/GetProvince"
+	CovidService_ListProvinceCases_FullMethodName     = "/covid.v1.CovidService/ListProvinceCases"
+	CovidService_StreamProvinceCases_FullMethodName   = "/covid.v1.CovidService/StreamProvinceCases"
+)
+
+// CovidServiceClient is the client API for CovidService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CovidService serves the national and province cases of the /national and
+// /provinces endpoints to internal services. Cases are the stored rows with
+// the day's test counts; derived figures such as active cases are left to the
+// client.
+type CovidServiceClient interface {
+	// ListNationalCases returns a page of national cases, oldest first unless sorted
+	ListNationalCases(ctx context.Context, in *ListNationalCasesRequest, opts ...grpc.CallOption) (*ListNationalCasesResponse, error)
+	// StreamNationalCases sends every national case the query selects
+	StreamNationalCases(ctx context.Context, in *StreamNationalCasesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NationalCase], error)
+	GetLatestNationalCase(ctx context.Context, in *GetLatestNationalCaseRequest, opts ...grpc.CallOption) (*NationalCase, error)
+	// GetNationalCaseByDay fails with NOT_FOUND when the day has no case
+	GetNationalCaseByDay(ctx context.Context, in *GetNationalCaseByDayRequest, opts ...grpc.CallOption) (*NationalCase, error)
+	ListProvinces(ctx context.Context, in *ListProvincesRequest, opts ...grpc.CallOption) (*ListProvincesResponse, error)
+	// GetProvince fails with NOT_FOUND for an unknown province ID
+	GetProvince(ctx context.Context, in *GetProvinceRequest, opts ...grpc.CallOption) (*Province, error)
+	// ListProvinceCases returns a page of province cases; those of one province
+	// are newest first and those of all provinces oldest first unless sorted
+	ListProvinceCases(ctx context.Context, in *ListProvinceCasesRequest, opts ...grpc.CallOption) (*ListProvinceCasesResponse, error)
+	// StreamProvinceCases sends every province case the query selects
+	StreamProvinceCases(ctx context.Context, in *StreamProvinceCasesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProvinceCase], error)
+}
+
+type covidServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCovidServiceClient(cc grpc.ClientConnInterface) CovidServiceClient {
+	return &covidServiceClient{cc}
+}
+
+func (c *covidServiceClient) ListNationalCases(ctx context.Context, in *ListNationalCasesRequest, opts ...grpc.CallOption) (*ListNationalCasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNationalCasesResponse)
+	err := c.cc.Invoke(ctx, CovidService_ListNationalCases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *covidServiceClient) StreamNationalCases(ctx context.Context, in *StreamNationalCasesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NationalCase], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CovidService_ServiceDesc.Streams[0], CovidService_StreamNationalCases_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamNationalCasesRequest, NationalCase]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CovidService_StreamNationalCasesClient = grpc.ServerStreamingClient[NationalCase]
+
+func (c *covidServiceClient) GetLatestNationalCase(ctx context.Context, in *GetLatestNationalCaseRequest, opts ...grpc.CallOption) (*NationalCase, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NationalCase)
+	err := c.cc.Invoke(ctx, CovidService_GetLatestNationalCase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *covidServiceClient) GetNationalCaseByDay(ctx context.Context, in *GetNationalCaseByDayRequest, opts ...grpc.CallOption) (*NationalCase, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NationalCase)
+	err := c.cc.Invoke(ctx, CovidService_GetNationalCaseByDay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *covidServiceClient) ListProvinces(ctx context.Context, in *ListProvincesRequest, opts ...grpc.CallOption) (*ListProvincesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProvincesResponse)
+	err := c.cc.Invoke(ctx, CovidService_ListProvinces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *covidServiceClient) GetProvince(ctx context.Context, in *GetProvinceRequest, opts ...grpc.CallOption) (*Province, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Province)
+	err := c.cc.Invoke(ctx, CovidService_GetProvince_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *covidServiceClient) ListProvinceCases(ctx context.Context, in *ListProvinceCasesRequest, opts ...grpc.CallOption) (*ListProvinceCasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProvinceCasesResponse)
+	err := c.cc.Invoke(ctx, CovidService_ListProvinceCases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *covidServiceClient) StreamProvinceCases(ctx context.Context, in *StreamProvinceCasesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProvinceCase], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CovidService_ServiceDesc.Streams[1], CovidService_StreamProvinceCases_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamProvinceCasesRequest, ProvinceCase]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CovidService_StreamProvinceCasesClient = grpc.ServerStreamingClient[ProvinceCase]
+
+// CovidServiceServer is the server API for CovidService service.
+// All implementations must embed UnimplementedCovidServiceServer
+// for forward compatibility.
+//
+// CovidService serves the national and province cases of the /national and
+// /provinces endpoints to internal services. Cases are the stored rows with
+// the day's test counts; derived figures such as active cases are left to the
+// client.
+type CovidServiceServer interface {
+	// ListNationalCases returns a page of national cases, oldest first unless sorted
+	ListNationalCases(context.Context, *ListNationalCasesRequest) (*ListNationalCasesResponse, error)
+	// StreamNationalCases sends every national case the query selects
+	StreamNationalCases(*StreamNationalCasesRequest, grpc.ServerStreamingServer[NationalCase]) error
+	GetLatestNationalCase(context.Context, *GetLatestNationalCaseRequest) (*NationalCase, error)
+	// GetNationalCaseByDay fails with NOT_FOUND when the day has no case
+	GetNationalCaseByDay(context.Context, *GetNationalCaseByDayRequest) (*NationalCase, error)
+	ListProvinces(context.Context, *ListProvincesRequest) (*ListProvincesResponse, error)
+	// GetProvince fails with NOT_FOUND for an unknown province ID
+	GetProvince(context.Context, *GetProvinceRequest) (*Province, error)
+	// ListProvinceCases returns a page of province cases; those of one province
+	// are newest first and those of all provinces oldest first unless sorted
+	ListProvinceCases(context.Context, *ListProvinceCasesRequest) (*ListProvinceCasesResponse, error)
+	// StreamProvinceCases sends every province case the query selects
+	StreamProvinceCases(*StreamProvinceCasesRequest, grpc.ServerStreamingServer[ProvinceCase]) error
+	mustEmbedUnimplementedCovidServiceServer()
+}
+
+// UnimplementedCovidServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCovidServiceServer struct{}
+
+func (UnimplementedCovidServiceServer) ListNationalCases(context.Context, *ListNationalCasesRequest) (*ListNationalCasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNationalCases not implemented")
+}
+func (UnimplementedCovidServiceServer) StreamNationalCases(*StreamNationalCasesRequest, grpc.ServerStreamingServer[NationalCase]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamNationalCases not implemented")
+}
+func (UnimplementedCovidServiceServer) GetLatestNationalCase(context.Context, *GetLatestNationalCaseRequest) (*NationalCase, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestNationalCase not implemented")
+}
+func (UnimplementedCovidServiceServer) GetNationalCaseByDay(context.Context, *GetNationalCaseByDayRequest) (*NationalCase, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNationalCaseByDay not implemented")
+}
+func (UnimplementedCovidServiceServer) ListProvinces(context.Context, *ListProvincesRequest) (*ListProvincesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProvinces not implemented")
+}
+func (UnimplementedCovidServiceServer) GetProvince(context.Context, *GetProvinceRequest) (*Province, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProvince not implemented")
+}
+func (UnimplementedCovidServiceServer) ListProvinceCases(context.Context, *ListProvinceCasesRequest) (*ListProvinceCasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProvinceCases not implemented")
+}
+func (UnimplementedCovidServiceServer) StreamProvinceCases(*StreamProvinceCasesRequest, grpc.ServerStreamingServer[ProvinceCase]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamProvinceCases not implemented")
+}
+func (UnimplementedCovidServiceServer) mustEmbedUnimplementedCovidServiceServer() {}
+func (UnimplementedCovidServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeCovidServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CovidServiceServer will
+// result in compilation errors.
+type UnsafeCovidServiceServer interface {
+	mustEmbedUnimplementedCovidServiceServer()
+}
+
+func RegisterCovidServiceServer(s grpc.ServiceRegistrar, srv CovidServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCovidServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CovidService_ServiceDesc, srv)
+}
+
+func _CovidService_ListNationalCases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNationalCasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CovidServiceServer).ListNationalCases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CovidService_ListNationalCases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CovidServiceServer).ListNationalCases(ctx, req.(*ListNationalCasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CovidService_StreamNationalCases_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamNationalCasesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CovidServiceServer).StreamNationalCases(m, &grpc.GenericServerStream[StreamNationalCasesRequest, NationalCase]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CovidService_StreamNationalCasesServer = grpc.ServerStreamingServer[NationalCase]
+
+func _CovidService_GetLatestNationalCase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestNationalCaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CovidServiceServer).GetLatestNationalCase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CovidService_GetLatestNationalCase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CovidServiceServer).GetLatestNationalCase(ctx, req.(*GetLatestNationalCaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CovidService_GetNationalCaseByDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNationalCaseByDayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CovidServiceServer).GetNationalCaseByDay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CovidService_GetNationalCaseByDay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CovidServiceServer).GetNationalCaseByDay(ctx, req.(*GetNationalCaseByDayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CovidService_ListProvinces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProvincesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CovidServiceServer).ListProvinces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CovidService_ListProvinces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CovidServiceServer).ListProvinces(ctx, req.(*ListProvincesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CovidService_GetProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProvinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CovidServiceServer).GetProvince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CovidService_GetProvince_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CovidServiceServer).GetProvince(ctx, req.(*GetProvinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CovidService_ListProvinceCases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProvinceCasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CovidServiceServer).ListProvinceCases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CovidService_ListProvinceCases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CovidServiceServer).ListProvinceCases(ctx, req.(*ListProvinceCasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CovidService_StreamProvinceCases_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamProvinceCasesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CovidServiceServer).StreamProvinceCases(m, &grpc.GenericServerStream[StreamProvinceCasesRequest, ProvinceCase]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CovidService_StreamProvinceCasesServer = grpc.ServerStreamingServer[ProvinceCase]
+
+// CovidService_ServiceDesc is the grpc.ServiceDesc for CovidService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CovidService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "covid.v1.CovidService",
+	HandlerType: (*CovidServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNationalCases",
+			Handler:    _CovidService_ListNationalCases_Handler,
+		},
+		{
+			MethodName: "GetLatestNationalCase",
+			Handler:    _CovidService_GetLatestNationalCase_Handler,
+		},
+		{
+			MethodName: "GetNationalCaseByDay",
+			Handler:    _CovidService_GetNationalCaseByDay_Handler,
+		},
+		{
+			MethodName: "ListProvinces",
+			Handler:    _CovidService_ListProvinces_Handler,
+		},
+		{
+			MethodName: "GetProvince",
+			Handler:    _CovidService_GetProvince_Handler,
+		},
+		{
+			MethodName: "ListProvinceCases",
+			Handler:    _CovidService_ListProvinceCases_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamNationalCases",
+			Handler:       _CovidService_StreamNationalCases_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamProvinceCases",
+			Handler:       _CovidService_StreamProvinceCases_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "covid/v1/covid.proto",
+}
@@ -0,0 +1,423 @@
+// Package benchmark runs the main repository queries against a seeded MySQL
+// schema to catch query plan regressions, such as a new sort that falls back to
+// a filesort, before release.
+//
+// The benchmarks need a disposable database and are skipped unless
+// BENCH_DB_NAME is set. Connection settings come from the usual DB_* variables.
+// The schema is dropped, recreated and seeded with BENCH_PROVINCE_ROWS
+// province case rows (default 1,000,000) whenever it holds fewer rows.
+//
+//	BENCH_DB_NAME=pico_bench make bench-queries
+//
+// The ProvinceMetrics and NationalTotals benchmarks compare loading a full
+// history into memory before aggregating it with streaming it page by page
+// through a metrics.Accumulator and with totalling it in SQL.
+//
+// Besides ns/op every benchmark reports sorted-rows/op, the growth of MySQL's
+// Sort_rows session counter per call. A non-zero value means the query plan
+// sorts rows instead of reading them in index order.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/banua-coder/pico-api-go/internal/config"
+	"github.com/banua-coder/pico-api-go/internal/metrics"
+	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
+	"github.com/banua-coder/pico-api-go/pkg/database"
+	"github.com/banua-coder/pico-api-go/pkg/utils"
+)
+
+const (
+	defaultProvinceRows = 1000000
+	provinceCount       = 34
+	seedBatchSize       = 1000
+)
+
+var (
+	setupOnce sync.Once
+	benchDB   *database.DB
+	setupErr  error
+	days      int64
+)
+
+func openBenchDB(b *testing.B) *database.DB {
+	b.Helper()
+	name := os.Getenv("BENCH_DB_NAME")
+	if name == "" {
+		b.Skip("BENCH_DB_NAME not set; skipping query benchmarks")
+	}
+
+	setupOnce.Do(func() {
+		cfg := config.Load()
+		if name == cfg.Database.DBName {
+			setupErr = fmt.Errorf("BENCH_DB_NAME must differ from DB_NAME: the benchmark schema is dropped and reseeded")
+			return
+		}
+		cfg.Database.DBName = name
+
+		benchDB, setupErr = database.NewConnection(&cfg.Database)
+		if setupErr != nil {
+			return
+		}
+		// One connection so the Sort_rows session counter belongs to the benchmarked queries
+		benchDB.SetMaxOpenConns(1)
+		rows := int64(defaultProvinceRows)
+		if v := os.Getenv("BENCH_PROVINCE_ROWS"); v != "" {
+			if rows, setupErr = strconv.ParseInt(v, 10, 64); setupErr != nil {
+				return
+			}
+		}
+		setupErr = seed(benchDB, rows)
+	})
+	if setupErr != nil {
+		b.Fatalf("benchmark setup failed: %v", setupErr)
+	}
+	return benchDB
+}
+
+// seed recreates the schema and fills it unless it already holds enough rows
+func seed(db *database.DB, provinceRows int64) error {
+	days = (provinceRows + provinceCount - 1) / provinceCount
+
+	var existing int64
+	if err := db.QueryRow(`SELECT COUNT(*) FROM province_cases`).Scan(&existing); err == nil && existing >= provinceRows {
+		return nil
+	}
+
+	for _, stmt := range baseSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to create schema: %w", err)
+		}
+	}
+	if err := applyMigrations(db); err != nil {
+		return err
+	}
+
+	provinces := make([]string, provinceCount)
+	for i := range provinces {
+		provinces[i] = fmt.Sprintf("('%d', 'Province %d')", 11+i, 11+i)
+	}
+	if _, err := db.Exec(`INSERT INTO provinces (id, name) VALUES ` + strings.Join(provinces, ", ")); err != nil {
+		return fmt.Errorf("failed to seed provinces: %w", err)
+	}
+
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	err := insertBatches(db, `INSERT INTO national_cases (id, day, date, positive, recovered, deceased,
+		cumulative_positive, cumulative_recovered, cumulative_deceased, rt, rt_upper, rt_lower) VALUES `,
+		days, func(i int64) string {
+			day := i + 1
+			return fmt.Sprintf("(%d, %d, '%s', %d, %d, %d, %d, %d, %d, 1.01, 1.2, 0.9)",
+				day, day, start.AddDate(0, 0, int(i)).Format("2006-01-02"),
+				day%500, day%400, day%20, day*500, day*400, day*20)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to seed national cases: %w", err)
+	}
+
+	err = insertBatches(db, `INSERT INTO province_cases (day, province_id, positive, recovered, deceased,
+		person_under_observation, finished_person_under_observation,
+		person_under_supervision, finished_person_under_supervision,
+		cumulative_positive, cumulative_recovered, cumulative_deceased,
+		cumulative_person_under_observation, cumulative_finished_person_under_observation,
+		cumulative_person_under_supervision, cumulative_finished_person_under_supervision,
+		rt, rt_upper, rt_lower) VALUES `,
+		provinceRows, func(i int64) string {
+			day := i/provinceCount + 1
+			return fmt.Sprintf("(%d, '%d', %d, %d, %d, 0, 0, 0, 0, %d, %d, %d, 0, 0, 0, 0, 1.01, 1.2, 0.9)",
+				day, 11+i%provinceCount, i%50, i%40, i%3, day*50, day*40, day*3)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to seed province cases: %w", err)
+	}
+
+	_, err = db.Exec(`ANALYZE TABLE national_cases, province_cases, provinces`)
+	return err
+}
+
+func insertBatches(db *database.DB, prefix string, total int64, row func(i int64) string) error {
+	values := make([]string, 0, seedBatchSize)
+	for i := int64(0); i < total; i++ {
+		values = append(values, row(i))
+		if len(values) == seedBatchSize || i == total-1 {
+			if _, err := db.Exec(prefix + strings.Join(values, ", ")); err != nil {
+				return err
+			}
+			values = values[:0]
+		}
+	}
+	return nil
+}
+
+// applyMigrations replays migrations/*.sql in order so the benchmarked schema
+// carries the same columns, triggers and indexes as production
+func applyMigrations(db *database.DB) error {
+	files, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		_, err = db.Restore(context.Background(), f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to apply %s: %w", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// baseSchema holds the pre-migration tables the repositories read from
+var baseSchema = []string{
+	`SET FOREIGN_KEY_CHECKS=0`,
+	`DROP TRIGGER IF EXISTS province_cases_fill_date`,
+	`DROP TABLE IF EXISTS revisions, sync_log, province_cases_quarantine, province_cases, national_cases, provinces, regency_cases`,
+	`SET FOREIGN_KEY_CHECKS=1`,
+	`CREATE TABLE provinces (
+		id VARCHAR(8) NOT NULL PRIMARY KEY,
+		name VARCHAR(128) NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE national_cases (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		day BIGINT NOT NULL,
+		date DATE NOT NULL,
+		positive BIGINT NOT NULL DEFAULT 0,
+		recovered BIGINT NOT NULL DEFAULT 0,
+		deceased BIGINT NOT NULL DEFAULT 0,
+		cumulative_positive BIGINT NOT NULL DEFAULT 0,
+		cumulative_recovered BIGINT NOT NULL DEFAULT 0,
+		cumulative_deceased BIGINT NOT NULL DEFAULT 0,
+		rt DOUBLE NULL,
+		rt_upper DOUBLE NULL,
+		rt_lower DOUBLE NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE province_cases (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		day BIGINT NOT NULL,
+		province_id VARCHAR(8) NOT NULL,
+		positive BIGINT NOT NULL DEFAULT 0,
+		recovered BIGINT NOT NULL DEFAULT 0,
+		deceased BIGINT NOT NULL DEFAULT 0,
+		person_under_observation BIGINT NULL,
+		finished_person_under_observation BIGINT NULL,
+		person_under_supervision BIGINT NULL,
+		finished_person_under_supervision BIGINT NULL,
+		cumulative_positive BIGINT NOT NULL DEFAULT 0,
+		cumulative_recovered BIGINT NOT NULL DEFAULT 0,
+		cumulative_deceased BIGINT NOT NULL DEFAULT 0,
+		cumulative_person_under_observation BIGINT NULL,
+		cumulative_finished_person_under_observation BIGINT NULL,
+		cumulative_person_under_supervision BIGINT NULL,
+		cumulative_finished_person_under_supervision BIGINT NULL,
+		rt DOUBLE NULL,
+		rt_upper DOUBLE NULL,
+		rt_lower DOUBLE NULL,
+		created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE regency_cases (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		day BIGINT NOT NULL,
+		regency_id VARCHAR(8) NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+}
+
+func sortRows(b *testing.B, db *database.DB) int64 {
+	b.Helper()
+	var name, value string
+	if err := db.QueryRow(`SHOW SESSION STATUS LIKE 'Sort_rows'`).Scan(&name, &value); err != nil {
+		b.Fatalf("failed to read Sort_rows: %v", err)
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		b.Fatalf("unexpected Sort_rows value %q: %v", value, err)
+	}
+	return n
+}
+
+// benchmarkQuery times fn and reports how many rows MySQL sorted per call
+func benchmarkQuery(b *testing.B, fn func(db *database.DB) error) {
+	db := openBenchDB(b)
+	if err := fn(db); err != nil {
+		b.Fatalf("query failed: %v", err)
+	}
+
+	before := sortRows(b, db)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := fn(db); err != nil {
+			b.Fatalf("query failed: %v", err)
+		}
+	}
+	b.StopTimer()
+	// SHOW SESSION STATUS itself does not sort, so the delta is the queries' own
+	b.ReportMetric(float64(sortRows(b, db)-before)/float64(b.N), "sorted-rows/op")
+}
+
+func dateOfDay(day int64) time.Time {
+	return time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(day-1))
+}
+
+func BenchmarkNationalCases_GetByDay(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, err := repository.NewNationalCaseRepository(db).GetByDay(context.Background(), days/2)
+		return err
+	})
+}
+
+func BenchmarkNationalCases_GetLatest(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, err := repository.NewNationalCaseRepository(db).GetLatest(context.Background())
+		return err
+	})
+}
+
+func BenchmarkNationalCases_PaginatedSortedByDateDesc(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, _, err := repository.NewNationalCaseRepository(db).Find(context.Background(), repository.NationalCaseQuery{
+			Sort: utils.SortParams{Field: "date", Order: "desc"}, Limit: 50, Offset: 100,
+		})
+		return err
+	})
+}
+
+func BenchmarkNationalCases_DateRange(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, _, err := repository.NewNationalCaseRepository(db).Find(context.Background(), repository.NationalCaseQuery{
+			StartDate: dateOfDay(days / 2), EndDate: dateOfDay(days/2 + 30),
+		})
+		return err
+	})
+}
+
+func BenchmarkProvinceCases_PaginatedSortedByDate(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, _, err := repository.NewProvinceCaseRepository(db).Find(context.Background(), repository.ProvinceCaseQuery{
+			Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 50, Offset: 1000,
+		})
+		return err
+	})
+}
+
+func BenchmarkProvinceCases_ByProvincePaginated(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, _, err := repository.NewProvinceCaseRepository(db).Find(context.Background(), repository.ProvinceCaseQuery{
+			ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "desc"}, Limit: 50,
+		})
+		return err
+	})
+}
+
+func BenchmarkProvinceCases_ByProvinceAndDateRange(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, _, err := repository.NewProvinceCaseRepository(db).Find(context.Background(), repository.ProvinceCaseQuery{
+			ProvinceID: "72", StartDate: dateOfDay(days / 2), EndDate: dateOfDay(days/2 + 30),
+		})
+		return err
+	})
+}
+
+func BenchmarkProvinceCases_DateRangePaginated(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, _, err := repository.NewProvinceCaseRepository(db).Find(context.Background(), repository.ProvinceCaseQuery{
+			StartDate: dateOfDay(days / 2), EndDate: dateOfDay(days/2 + 7), Limit: 50,
+		})
+		return err
+	})
+}
+
+func BenchmarkProvinceCases_LatestByProvince(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, err := repository.NewProvinceCaseRepository(db).GetLatestByProvinceID(context.Background(), "72")
+		return err
+	})
+}
+
+func BenchmarkProvinces_WithLatestCase(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, err := repository.NewProvinceRepository(db).GetAllWithLatestCase(context.Background())
+		return err
+	})
+}
+
+// aggregatePageSize matches the page size the province metrics endpoint reads
+const aggregatePageSize = 1000
+
+// BenchmarkProvinceMetrics_LoadAll aggregates a province's full history after
+// reading every row into memory
+func BenchmarkProvinceMetrics_LoadAll(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		cases, _, err := repository.NewProvinceCaseRepository(db).Find(context.Background(), repository.ProvinceCaseQuery{
+			ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"},
+		})
+		if err != nil {
+			return err
+		}
+		series := make([]metrics.Values, len(cases))
+		for i := range cases {
+			series[i] = metrics.FromProvinceCase(cases[i].TransformToResponseWithoutProvince())
+		}
+		metrics.Default.Aggregate(series)
+		return nil
+	})
+}
+
+// BenchmarkProvinceMetrics_Streaming aggregates a province's full history page
+// by page, holding at most one page in memory
+func BenchmarkProvinceMetrics_Streaming(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		repo := repository.NewProvinceCaseRepository(db)
+		acc := metrics.Default.NewAccumulator()
+		for offset := 0; ; offset += aggregatePageSize {
+			cases, total, err := repo.Find(context.Background(), repository.ProvinceCaseQuery{
+				ProvinceID: "72", Sort: utils.SortParams{Field: "date", Order: "asc"},
+				Limit: aggregatePageSize, Offset: offset,
+			})
+			if err != nil {
+				return err
+			}
+			for i := range cases {
+				acc.Add(metrics.FromProvinceCase(cases[i].TransformToResponseWithoutProvince()))
+			}
+			if len(cases) < aggregatePageSize || offset+len(cases) >= total {
+				break
+			}
+		}
+		acc.Summaries()
+		return nil
+	})
+}
+
+// BenchmarkNationalTotals_LoadAll totals the full national history in Go
+func BenchmarkNationalTotals_LoadAll(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		cases, _, err := repository.NewNationalCaseRepository(db).Find(context.Background(), repository.NationalCaseQuery{})
+		if err != nil {
+			return err
+		}
+		models.SummarizeNationalPeriod(models.Period{}, cases)
+		return nil
+	})
+}
+
+// BenchmarkNationalTotals_SQL totals the full national history in MySQL
+func BenchmarkNationalTotals_SQL(b *testing.B) {
+	benchmarkQuery(b, func(db *database.DB) error {
+		_, err := repository.NewNationalCaseRepository(db).GetTotals(context.Background(), dateOfDay(1), dateOfDay(days))
+		return err
+	})
+}
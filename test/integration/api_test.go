@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"github.com/banua-coder/pico-api-go/internal/handler"
 	"github.com/banua-coder/pico-api-go/internal/middleware"
 	"github.com/banua-coder/pico-api-go/internal/models"
+	"github.com/banua-coder/pico-api-go/internal/repository"
 	"github.com/banua-coder/pico-api-go/internal/service"
 	"github.com/banua-coder/pico-api-go/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -20,22 +22,27 @@ type MockNationalCaseRepo struct {
 	mock.Mock
 }
 
-func (m *MockNationalCaseRepo) GetAll() ([]models.NationalCase, error) {
-	args := m.Called()
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+func (m *MockNationalCaseRepo) Find(ctx context.Context, q repository.NationalCaseQuery) ([]models.NationalCase, int, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
 }
 
-func (m *MockNationalCaseRepo) GetByDateRange(startDate, endDate time.Time) ([]models.NationalCase, error) {
-	args := m.Called(startDate, endDate)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
+func (m *MockNationalCaseRepo) Each(ctx context.Context, q repository.NationalCaseQuery, fn func(models.NationalCase) error) error {
+	args := m.Called(q)
+	for _, c := range args.Get(0).([]models.NationalCase) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
-func (m *MockNationalCaseRepo) GetLatest() (*models.NationalCase, error) {
+func (m *MockNationalCaseRepo) GetLatest(ctx context.Context) (*models.NationalCase, error) {
 	args := m.Called()
 	return args.Get(0).(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepo) GetByDay(day int64) (*models.NationalCase, error) {
+func (m *MockNationalCaseRepo) GetByDay(ctx context.Context, day int64) (*models.NationalCase, error) {
 	args := m.Called(day)
 	result := args.Get(0)
 	if result == nil {
@@ -44,46 +51,45 @@ func (m *MockNationalCaseRepo) GetByDay(day int64) (*models.NationalCase, error)
 	return result.(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepo) GetAllSorted(sortParams utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(sortParams)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-
-func (m *MockNationalCaseRepo) GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.NationalCase, error) {
-	args := m.Called(startDate, endDate, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Error(1)
-}
-
-func (m *MockNationalCaseRepo) GetAllPaginated(limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
-}
-
-func (m *MockNationalCaseRepo) GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(limit, offset, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func (m *MockNationalCaseRepo) GetByDate(ctx context.Context, date time.Time) (*models.NationalCase, error) {
+	args := m.Called(date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.NationalCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepo) GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.NationalCase, int, error) {
-	args := m.Called(startDate, endDate, limit, offset)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func (m *MockNationalCaseRepo) GetAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
 }
 
-func (m *MockNationalCaseRepo) GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.NationalCase, int, error) {
-	args := m.Called(startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.NationalCase), args.Int(1), args.Error(2)
+func (m *MockNationalCaseRepo) GetTotals(ctx context.Context, start, end time.Time) (models.PeriodTotals, error) {
+	args := m.Called(start, end)
+	return args.Get(0).(models.PeriodTotals), args.Error(1)
 }
 
 type MockProvinceRepo struct {
 	mock.Mock
 }
 
-func (m *MockProvinceRepo) GetAll() ([]models.Province, error) {
+func (m *MockProvinceRepo) GetAll(ctx context.Context) ([]models.Province, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Province), args.Error(1)
 }
 
-func (m *MockProvinceRepo) GetByID(id string) (*models.Province, error) {
+func (m *MockProvinceRepo) GetAllWithLatestCase(ctx context.Context) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
+}
+
+func (m *MockProvinceRepo) GetByIDsWithLatestCase(ctx context.Context, ids []string) ([]models.ProvinceWithLatestCase, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.ProvinceWithLatestCase), args.Error(1)
+}
+
+func (m *MockProvinceRepo) GetByID(ctx context.Context, id string) (*models.Province, error) {
 	args := m.Called(id)
 	result := args.Get(0)
 	if result == nil {
@@ -96,27 +102,33 @@ type MockProvinceCaseRepo struct {
 	mock.Mock
 }
 
-func (m *MockProvinceCaseRepo) GetAll() ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called()
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepo) Find(ctx context.Context, q repository.ProvinceCaseQuery) ([]models.ProvinceCaseWithDate, int, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
 }
 
-func (m *MockProvinceCaseRepo) GetByProvinceID(provinceID string) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepo) Each(ctx context.Context, q repository.ProvinceCaseQuery, fn func(models.ProvinceCaseWithDate) error) error {
+	args := m.Called(q)
+	for _, c := range args.Get(0).([]models.ProvinceCaseWithDate) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
-func (m *MockProvinceCaseRepo) GetByProvinceIDAndDateRange(provinceID string, startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, startDate, endDate)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepo) GetPageAfterCursor(ctx context.Context, q repository.ProvinceCaseCursorQuery) ([]models.ProvinceCaseWithDate, *models.CaseCursor, error) {
+	args := m.Called(q)
+	next, _ := args.Get(1).(*models.CaseCursor)
+	return args.Get(0).([]models.ProvinceCaseWithDate), next, args.Error(2)
 }
 
-func (m *MockProvinceCaseRepo) GetByDateRange(startDate, endDate time.Time) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(startDate, endDate)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
+func (m *MockProvinceCaseRepo) GetAggregated(ctx context.Context, q repository.CaseAggregateQuery) ([]models.AggregatedCase, error) {
+	args := m.Called(q)
+	return args.Get(0).([]models.AggregatedCase), args.Error(1)
 }
 
-func (m *MockProvinceCaseRepo) GetLatestByProvinceID(provinceID string) (*models.ProvinceCaseWithDate, error) {
+func (m *MockProvinceCaseRepo) GetLatestByProvinceID(ctx context.Context, provinceID string) (*models.ProvinceCaseWithDate, error) {
 	args := m.Called(provinceID)
 	result := args.Get(0)
 	if result == nil {
@@ -125,67 +137,13 @@ func (m *MockProvinceCaseRepo) GetLatestByProvinceID(provinceID string) (*models
 	return result.(*models.ProvinceCaseWithDate), args.Error(1)
 }
 
-// Paginated methods
-func (m *MockProvinceCaseRepo) GetAllPaginated(limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepo) GetByProvinceIDPaginated(provinceID string, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepo) GetByProvinceIDAndDateRangePaginated(provinceID string, startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, startDate, endDate, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepo) GetByDateRangePaginated(startDate, endDate time.Time, limit, offset int) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(startDate, endDate, limit, offset)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-// Sorted methods
-func (m *MockProvinceCaseRepo) GetAllSorted(sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-func (m *MockProvinceCaseRepo) GetByProvinceIDSorted(provinceID string, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-func (m *MockProvinceCaseRepo) GetByProvinceIDAndDateRangeSorted(provinceID string, startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(provinceID, startDate, endDate, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-func (m *MockProvinceCaseRepo) GetByDateRangeSorted(startDate, endDate time.Time, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, error) {
-	args := m.Called(startDate, endDate, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Error(1)
-}
-
-// Paginated sorted methods
-func (m *MockProvinceCaseRepo) GetAllPaginatedSorted(limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepo) GetByProvinceIDPaginatedSorted(provinceID string, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepo) GetByProvinceIDAndDateRangePaginatedSorted(provinceID string, startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(provinceID, startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
-}
-
-func (m *MockProvinceCaseRepo) GetByDateRangePaginatedSorted(startDate, endDate time.Time, limit, offset int, sortParams utils.SortParams) ([]models.ProvinceCaseWithDate, int, error) {
-	args := m.Called(startDate, endDate, limit, offset, sortParams)
-	return args.Get(0).([]models.ProvinceCaseWithDate), args.Int(1), args.Error(2)
+func (m *MockProvinceCaseRepo) GetByProvinceIDAndDate(ctx context.Context, provinceID string, date time.Time) (*models.ProvinceCaseWithDate, error) {
+	args := m.Called(provinceID, date)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*models.ProvinceCaseWithDate), args.Error(1)
 }
 
 func setupTestServer() (*httptest.Server, *MockNationalCaseRepo, *MockProvinceRepo, *MockProvinceCaseRepo) {
@@ -250,7 +208,7 @@ func TestAPI_GetNationalCases(t *testing.T) {
 		},
 	}
 
-	mockNationalRepo.On("GetAllPaginatedSorted", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, len(expectedCases), nil)
+	mockNationalRepo.On("Find", repository.NationalCaseQuery{Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 50}).Return(expectedCases, len(expectedCases), nil)
 
 	resp, err := http.Get(server.URL + "/api/v1/national")
 	assert.NoError(t, err)
@@ -281,7 +239,7 @@ func TestAPI_GetNationalCasesWithDateRange(t *testing.T) {
 		{ID: 1, Date: startDate, Positive: 100},
 	}
 
-	mockNationalRepo.On("GetByDateRangePaginatedSorted", startDate, endDate, 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, len(expectedCases), nil)
+	mockNationalRepo.On("Find", repository.NationalCaseQuery{StartDate: startDate, EndDate: endDate, Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 50}).Return(expectedCases, len(expectedCases), nil)
 
 	resp, err := http.Get(server.URL + "/api/v1/national?start_date=2020-03-01&end_date=2020-03-31")
 	assert.NoError(t, err)
@@ -335,27 +293,23 @@ func TestAPI_GetProvinces(t *testing.T) {
 	server, _, mockProvinceRepo, mockProvinceCaseRepo := setupTestServer()
 	defer server.Close()
 
-	expectedProvinces := []models.Province{
-		{ID: "11", Name: "Aceh"},
-		{ID: "31", Name: "DKI Jakarta"},
-	}
-
-	// Mock the calls needed for GetProvincesWithLatestCase (default behavior)
-	mockProvinceRepo.On("GetAll").Return(expectedProvinces, nil)
-
-	// Mock the latest case data for each province
+	// The latest case of every province comes from a single repository call
 	testTime := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
-	mockProvinceCaseRepo.On("GetLatestByProvinceID", "11").Return(&models.ProvinceCaseWithDate{
+	acehCase := (&models.ProvinceCaseWithDate{
 		ProvinceCase: models.ProvinceCase{
 			ID: 1, ProvinceID: "11", Positive: 10, Day: 100,
 		},
 		Date: testTime,
-	}, nil)
-	mockProvinceCaseRepo.On("GetLatestByProvinceID", "31").Return(&models.ProvinceCaseWithDate{
+	}).TransformToResponseWithoutProvince()
+	jakartaCase := (&models.ProvinceCaseWithDate{
 		ProvinceCase: models.ProvinceCase{
 			ID: 2, ProvinceID: "31", Positive: 25, Day: 100,
 		},
 		Date: testTime,
+	}).TransformToResponseWithoutProvince()
+	mockProvinceRepo.On("GetAllWithLatestCase").Return([]models.ProvinceWithLatestCase{
+		{Province: models.Province{ID: "11", Name: "Aceh"}, LatestCase: &acehCase},
+		{Province: models.Province{ID: "31", Name: "DKI Jakarta"}, LatestCase: &jakartaCase},
 	}, nil)
 
 	resp, err := http.Get(server.URL + "/api/v1/provinces")
@@ -392,7 +346,7 @@ func TestAPI_GetProvinceCases(t *testing.T) {
 		},
 	}
 
-	mockProvinceCaseRepo.On("GetAllPaginatedSorted", 50, 0, utils.SortParams{Field: "date", Order: "asc"}).Return(expectedCases, len(expectedCases), nil)
+	mockProvinceCaseRepo.On("Find", repository.ProvinceCaseQuery{Sort: utils.SortParams{Field: "date", Order: "asc"}, Limit: 50}).Return(expectedCases, len(expectedCases), nil)
 
 	resp, err := http.Get(server.URL + "/api/v1/provinces/cases")
 	assert.NoError(t, err)